@@ -3,22 +3,37 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/qhato/ecommerce/config"
 
+	// Admin
+	adminApp "github.com/qhato/ecommerce/internal/admin/application"
+	adminDomain "github.com/qhato/ecommerce/internal/admin/domain"
+	adminPersistence "github.com/qhato/ecommerce/internal/admin/infrastructure/persistence"
+	adminHttp "github.com/qhato/ecommerce/internal/admin/ports/http"
+
 	// Catalog
 	catalogApp "github.com/qhato/ecommerce/internal/catalog/application"
 	catalogCommands "github.com/qhato/ecommerce/internal/catalog/application/commands"
 	catalogQueries "github.com/qhato/ecommerce/internal/catalog/application/queries"
+	catalogDomain "github.com/qhato/ecommerce/internal/catalog/domain"
 	catalogPersistence "github.com/qhato/ecommerce/internal/catalog/infrastructure/persistence"
 	catalogHttp "github.com/qhato/ecommerce/internal/catalog/ports/http"
 
+	// Search
+	searchApp "github.com/qhato/ecommerce/internal/search/application"
+	searchPersistence "github.com/qhato/ecommerce/internal/search/infrastructure/persistence"
+	searchHttp "github.com/qhato/ecommerce/internal/search/ports/http"
+
 	// Customer
+	customerApp "github.com/qhato/ecommerce/internal/customer/application"
 	customerCommands "github.com/qhato/ecommerce/internal/customer/application/commands"
 	customerQueries "github.com/qhato/ecommerce/internal/customer/application/queries"
 	customerPersistence "github.com/qhato/ecommerce/internal/customer/infrastructure/persistence"
@@ -28,38 +43,89 @@ import (
 	orderApp "github.com/qhato/ecommerce/internal/order/application"
 	orderCommands "github.com/qhato/ecommerce/internal/order/application/commands"
 	orderQueries "github.com/qhato/ecommerce/internal/order/application/queries"
+	orderDomain "github.com/qhato/ecommerce/internal/order/domain"
 	orderPersistence "github.com/qhato/ecommerce/internal/order/infrastructure/persistence"
 	orderHttp "github.com/qhato/ecommerce/internal/order/ports/http"
 
 	// Offer
 	offerApp "github.com/qhato/ecommerce/internal/offer/application"
+	offerDomain "github.com/qhato/ecommerce/internal/offer/domain"
 	offerPersistence "github.com/qhato/ecommerce/internal/offer/infrastructure/persistence"
+	offerHttp "github.com/qhato/ecommerce/internal/offer/ports/http"
 
 	// Inventory
 	inventoryApp "github.com/qhato/ecommerce/internal/inventory/application"
+	inventoryDomain "github.com/qhato/ecommerce/internal/inventory/domain"
 	inventoryPersistence "github.com/qhato/ecommerce/internal/inventory/infrastructure/persistence"
+	inventoryRedis "github.com/qhato/ecommerce/internal/inventory/infrastructure/redis"
+	inventoryHttp "github.com/qhato/ecommerce/internal/inventory/ports/http"
 
 	// Tax
 	taxApp "github.com/qhato/ecommerce/internal/tax/application"
+	taxDomain "github.com/qhato/ecommerce/internal/tax/domain"
 	taxPersistence "github.com/qhato/ecommerce/internal/tax/infrastructure/persistence"
+	taxHttp "github.com/qhato/ecommerce/internal/tax/ports/http"
+
+	// Analytics
+	analyticsApp "github.com/qhato/ecommerce/internal/analytics/application"
+	analyticsPersistence "github.com/qhato/ecommerce/internal/analytics/infrastructure/persistence"
+	analyticsHttp "github.com/qhato/ecommerce/internal/analytics/ports/http"
 
 	// Payment
+	paymentApp "github.com/qhato/ecommerce/internal/payment/application"
 	paymentCommands "github.com/qhato/ecommerce/internal/payment/application/commands"
+	paymentDomain "github.com/qhato/ecommerce/internal/payment/domain"
 	paymentQueries "github.com/qhato/ecommerce/internal/payment/application/queries"
 	paymentPersistence "github.com/qhato/ecommerce/internal/payment/infrastructure/persistence"
 	paymentHttp "github.com/qhato/ecommerce/internal/payment/ports/http"
 
 	// Fulfillment
+	fulfillmentApp "github.com/qhato/ecommerce/internal/fulfillment/application"
 	fulfillmentCommands "github.com/qhato/ecommerce/internal/fulfillment/application/commands"
 	fulfillmentPersistence "github.com/qhato/ecommerce/internal/fulfillment/infrastructure/persistence"
 	fulfillmentHttp "github.com/qhato/ecommerce/internal/fulfillment/ports/http"
 
+	// Site
+	siteApp "github.com/qhato/ecommerce/internal/site/application"
+	sitePersistence "github.com/qhato/ecommerce/internal/site/infrastructure/persistence"
+	siteHttp "github.com/qhato/ecommerce/internal/site/ports/http"
+
+	// Warranty
+	warrantyApp "github.com/qhato/ecommerce/internal/warranty/application"
+	warrantyPersistence "github.com/qhato/ecommerce/internal/warranty/infrastructure/persistence"
+	warrantyHttp "github.com/qhato/ecommerce/internal/warranty/ports/http"
+
+	// Business calendar
+	calendarApp "github.com/qhato/ecommerce/internal/calendar/application"
+	calendarPersistence "github.com/qhato/ecommerce/internal/calendar/infrastructure/persistence"
+	calendarHttp "github.com/qhato/ecommerce/internal/calendar/ports/http"
+
+	// Inbound webhooks
+	webhookApp "github.com/qhato/ecommerce/internal/webhook/application"
+	webhookPersistence "github.com/qhato/ecommerce/internal/webhook/infrastructure/persistence"
+	webhookHttp "github.com/qhato/ecommerce/internal/webhook/ports/http"
+
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/auth"
 	"github.com/qhato/ecommerce/pkg/cache"
 	"github.com/qhato/ecommerce/pkg/database"
 	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/featureflag"
+	"github.com/qhato/ecommerce/pkg/i18n"
+	"github.com/qhato/ecommerce/pkg/export"
+	"github.com/qhato/ecommerce/pkg/httpclient"
 	"github.com/qhato/ecommerce/pkg/logger"
 	"github.com/qhato/ecommerce/pkg/middleware"
+	"github.com/qhato/ecommerce/pkg/money"
+	"github.com/qhato/ecommerce/pkg/notification"
+	"github.com/qhato/ecommerce/pkg/objectstore"
+	"github.com/qhato/ecommerce/pkg/pdf"
+	"github.com/qhato/ecommerce/pkg/ratelimit"
+	"github.com/qhato/ecommerce/pkg/requestlog"
+	"github.com/qhato/ecommerce/pkg/settings"
 	"github.com/qhato/ecommerce/pkg/validator"
+	"github.com/qhato/ecommerce/pkg/webhook"
+	"github.com/qhato/ecommerce/schemas"
 )
 
 func main() {
@@ -69,6 +135,10 @@ func main() {
 		fmt.Printf("Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+
+	// Currency rounding and display rules used by DTOs and templates
+	money.Configure(cfg.Money.CurrencyRules(), cfg.Money.LocaleFormats(), cfg.Storefront.DefaultLocale)
+
 	// Initialize logger
 	err = logger.Initialize(cfg.App.Environment, cfg.App.LogLevel)
 	if err != nil {
@@ -90,6 +160,10 @@ func main() {
 		MaxIdleConns: cfg.Database.MaxIdleConns,
 		MaxLifetime: cfg.Database.MaxLifetime,
 		MaxIdleTime: cfg.Database.MaxIdleTime,
+		QueryTimeout: cfg.Database.QueryTimeout,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+		ExplainQueries: cfg.Database.ExplainQueries && cfg.IsDevelopment(),
+		ExplainTables: cfg.Database.ExplainTables,
 	})
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to database")
@@ -98,32 +172,222 @@ func main() {
 	log.Info("Connected to database")
 
 	// Initialize cache
+	// Redis sits behind a circuit breaker; while it is down the cache falls back to memory
+	resilienceCtx, stopResilience := context.WithCancel(context.Background())
+	defer stopResilience()
 	var cacheStore cache.Cache
+	var flashTokens inventoryDomain.FlashTokenStore // Flash-sale counters shared with the storefront
+	var rateLimitCounters ratelimit.Inspector         // Storefront rate limit counters, shared through Redis only
 	if cfg.Redis.Host != "" { // Check Redis host for cache type
-		cacheStore, err = cache.NewRedisCache(cache.RedisConfig{ // Convert config.RedisConfig to cache.RedisConfig
+		redisCache := cache.NewLazyRedisCache(cache.RedisConfig{ // Convert config.RedisConfig to cache.RedisConfig
 			Host: cfg.Redis.Host,
 			Port: cfg.Redis.Port,
 			Password: cfg.Redis.Password,
 			Database: cfg.Redis.Database,
 			PoolSize: cfg.Redis.PoolSize,
-			Prefix: "admin_api", // Assuming a prefix for admin cache
+			Prefix: cfg.CacheKeyPrefix(), // Keeps environments sharing a Redis server apart
 		})
-		if err != nil {
-			log.WithError(err).Fatal("Failed to connect to Redis")
+		resilientCache := cache.NewResilientCache(redisCache, cache.ResilienceConfig{
+			Name:             "redis",
+			FailureThreshold: cfg.Redis.BreakerThreshold,
+			Cooldown:         cfg.Redis.BreakerCooldown,
+			ProbeInterval:    cfg.Redis.ProbeInterval,
+			FallbackTTL:      cfg.Redis.TTL,
+		}, log)
+		resilientCache.StartRecoveryProbe(resilienceCtx)
+		cacheStore = resilientCache
+		flashTokens = inventoryRedis.NewFlashTokenStore(redisCache.GetClient(), "ecommerce")
+		rateLimitCounters = ratelimit.NewRedisLimiter(redisCache.GetClient(), "storefront")
+		if err := redisCache.Health(context.Background()); err != nil {
+			log.WithError(err).Warn("Redis unavailable, serving from in-memory fallback until it recovers")
+		} else {
+			log.Info("Connected to Redis cache")
 		}
-		log.Info("Connected to Redis cache")
 	} else {
 		cacheStore = cache.NewMemoryCache(cfg.Redis.TTL, cfg.Redis.TTL/2) // Provide arguments
 		log.Info("Using in-memory cache")
 	}
 
-	// Initialize event bus
-	eventBus := event.NewMemoryBus() // No arguments
-	log.Info("Event bus initialized")
+	// Entries both binaries read live in the shared namespace so either one's
+	// invalidations reach the other; the rest stay in this binary's namespace
+	cacheFlusher := cache.NewFlusher(cacheStore, cache.NamespaceShared, cache.NamespaceAdmin, cache.NamespaceStorefront)
+	adminCache := cache.WithNamespace(cacheStore, cache.NamespaceAdmin)
+	cacheStore = cache.WithNamespace(cacheStore, cache.NamespaceShared)
+
+	// Initialize event bus. With the redis driver, events are shared
+	// between instances through Redis Streams and the instances of this service
+	// consume them as one consumer group.
+	var eventBus event.Bus = event.NewMemoryBus()
+	if cfg.EventBus.Driver == "redis" {
+		group := cfg.EventBus.ConsumerGroup
+		if group == "" {
+			group = "admin_api"
+		}
+		eventBus = event.NewRedisStreamBus(event.RedisStreamConfig{
+			Host:            cfg.Redis.Host,
+			Port:            cfg.Redis.Port,
+			Password:        cfg.Redis.Password,
+			Database:        cfg.Redis.Database,
+			PoolSize:        cfg.Redis.PoolSize,
+			StreamPrefix:    cfg.EventBus.StreamPrefix,
+			Group:           group,
+			MaxLen:          cfg.EventBus.MaxLen,
+			BlockTimeout:    cfg.EventBus.BlockTimeout,
+			ClaimIdle:       cfg.EventBus.ClaimIdle,
+			MetricsInterval: cfg.EventBus.MetricsInterval,
+		}, log)
+	}
+	// Published events are checked against the schemas in schemas/events, which
+	// consumers in other contexts rely on
+	if schemaMode := event.SchemaMode(cfg.EventBus.SchemaMode); schemaMode != event.SchemaModeOff {
+		eventSchemas, err := event.LoadSchemas(schemas.Events)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load event schemas")
+		}
+		problems := eventSchemas.Check()
+		for _, problem := range problems {
+			log.WithField("problem", problem).Warn("Event schema check failed")
+		}
+		if len(problems) > 0 && schemaMode == event.SchemaModeEnforce {
+			log.Fatal("Event schemas do not match the event types or their consumers; run make check-events")
+		}
+		eventBus = event.WithSchemas(eventBus, eventSchemas, schemaMode, log)
+	}
+	defer eventBus.Close()
+	log.WithField("driver", cfg.EventBus.Driver).Info("Event bus initialized")
+
+	// Hot checkout queries (active offers, offer codes, tax rates) are cached and
+	// invalidated when their data changes
+	queryCache := cache.NewQueryCache(cacheStore, cache.QueryCacheConfig{
+		TTL:          cfg.QueryCache.TTL,
+		RefreshAhead: cfg.QueryCache.RefreshAhead,
+	}, log)
+	if err := queryCache.InvalidateOn(eventBus, offerApp.OfferCacheNamespace, offerDomain.EventOfferChanged); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe offer cache invalidation")
+	}
+	if err := queryCache.InvalidateOn(eventBus, taxApp.TaxCacheNamespace, taxDomain.EventTaxDetailChanged); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe tax cache invalidation")
+	}
 
 	// Initialize validator
 	val := validator.New()
 
+	// ========== ADMIN SESSIONS ==========
+
+	// Admin audit log; sign-ins and session revocations are recorded as security events
+	auditLogRepo := adminPersistence.NewPostgresAuditLogRepository(db)
+	auditService := audit.NewAuditService(auditLogRepo)
+	auditLogService := adminApp.NewAuditLogService(auditLogRepo, cfg.Audit.Retention, cfg.Audit.ArchiveBatchSize, log)
+	auditCtx, stopAudit := context.WithCancel(context.Background())
+	defer stopAudit()
+	auditLogService.StartScheduledArchival(auditCtx, cfg.Audit.ArchiveInterval)
+
+	// Admin API request log for support forensics, written in the background with secrets and personal data redacted
+	requestLogRepo := adminPersistence.NewPostgresRequestLogRepository(db)
+	requestLogService := adminApp.NewRequestLogService(requestLogRepo, cfg.RequestLog.Retention, cfg.RequestLog.PurgeBatchSize, log)
+	requestLogRecorder := requestlog.NewRecorder(requestLogRepo, cfg.RequestLog.BufferSize, log)
+	if cfg.RequestLog.Enabled {
+		requestLogRecorder.Start(auditCtx)
+		requestLogService.StartScheduledPurge(auditCtx, cfg.RequestLog.PurgeInterval)
+	}
+
+	// Admin logins create server-side sessions that can be listed and revoked
+	jwtService := auth.NewJWTService(cfg.Auth.JWTSecret, cfg.Auth.JWTExpiration)
+	adminUserRepo := adminPersistence.NewPostgresAdminUserRepository(db)
+	adminSessionService := adminApp.NewAdminSessionService(
+		adminUserRepo,
+		adminPersistence.NewPostgresAdminSessionRepository(db),
+		jwtService,
+		auth.NewPasswordService(cfg.Auth.BcryptCost),
+		auth.NewTokenBlacklist(adminCache),
+		auditService,
+		cfg.Auth.AdminSessionIdle,
+		log,
+	)
+	adminAuth := middleware.SessionJWTAuth(jwtService, adminSessionService)
+	// External sales channels call the integration API with their own API keys
+	channelAuth := middleware.ChannelAuth(auth.NewChannelKeys(cfg.Integrations.ChannelKeys()))
+	adminAuditLogHandler := adminHttp.NewAdminAuditLogHandler(auditLogService, adminAuth, log)
+	adminRequestLogHandler := adminHttp.NewAdminRequestLogHandler(requestLogService, adminAuth, log)
+
+	// Admin roles; predefined templates can be seeded as roles and cloned
+	adminRoleRepo := adminPersistence.NewPostgresAdminRoleRepository(db)
+	adminRoleService := adminApp.NewAdminRoleService(adminRoleRepo, adminUserRepo, auditService, log)
+	adminRoleHandler := adminHttp.NewAdminRoleHandler(adminRoleService, adminAuth, log)
+	// Admins see and revoke their own sessions; those of others need the session manager permission
+	adminSessionHandler := adminHttp.NewAdminSessionHandler(
+		adminSessionService,
+		adminAuth,
+		middleware.RequirePermission(adminRoleService, adminDomain.PermissionManageSessions),
+		log,
+	)
+
+	// Saved filters and columns of the order, product and customer lists, shared with roles
+	savedViewService := adminApp.NewSavedViewService(adminPersistence.NewPostgresSavedViewRepository(db), adminRoleRepo, auditService, log)
+	adminSavedViewHandler := adminHttp.NewAdminSavedViewHandler(savedViewService, adminAuth, log)
+
+	// Task inbox of the workflows needing an admin decision; workflows subscribe to admin.task.completed
+	adminTaskService := adminApp.NewAdminTaskService(adminPersistence.NewPostgresAdminTaskRepository(db), adminRoleRepo, adminUserRepo, eventBus, auditService, log)
+	adminTaskHandler := adminHttp.NewAdminTaskHandler(adminTaskService, adminAuth, log)
+
+	// Storefront maintenance mode and kill switches; storefront instances pick up switches at their next refresh
+	featureFlags := featureflag.New(adminPersistence.NewPostgresFeatureFlagRepository(db), log)
+	if err := featureFlags.Refresh(context.Background()); err != nil {
+		log.WithError(err).Warn("Failed to load feature flags")
+	}
+	featureFlagService := adminApp.NewFeatureFlagService(featureFlags, auditService, log)
+	adminFeatureFlagHandler := adminHttp.NewAdminFeatureFlagHandler(featureFlagService, adminAuth, log)
+
+	// Site settings such as the store name and order number prefix, read by services in place of constants
+	siteSettings, err := settings.New(adminPersistence.NewPostgresSiteSettingRepository(db), cfg.SiteSettingDefaults(), log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize site settings")
+	}
+	if err := siteSettings.Refresh(context.Background()); err != nil {
+		log.WithError(err).Warn("Failed to load site settings")
+	}
+	settingsCtx, stopSettings := context.WithCancel(context.Background())
+	defer stopSettings()
+	siteSettings.StartScheduledRefresh(settingsCtx, cfg.Features.SettingsRefreshInterval)
+	siteSettingService := adminApp.NewSiteSettingService(siteSettings, auditService, log)
+	adminSiteSettingHandler := adminHttp.NewAdminSiteSettingHandler(siteSettingService, adminAuth, log)
+
+	// Storefront API quotas; raises are picked up by storefront instances at their next refresh
+	rateLimitQuotas := ratelimit.NewQuotas(adminPersistence.NewPostgresRateLimitOverrideRepository(db), log)
+	rateLimitService := adminApp.NewRateLimitService(rateLimitQuotas, cfg.RateLimit.Policy(), rateLimitCounters, auditService, log)
+	adminRateLimitHandler := adminHttp.NewAdminRateLimitHandler(rateLimitService, adminAuth, log)
+
+	// ========== EXPORTS ==========
+
+	// Admins are emailed when a background export is ready, if an email provider is configured
+	notifications := notification.NewNotificationService()
+	if cfg.Notification.EmailAPIURL != "" {
+		emailClient := httpclient.New(cfg.HTTPClient.Client("email", cfg.Notification.EmailAPIURL), log)
+		emailSender := notification.NewHTTPEmailSender(emailClient, cfg.Notification.EmailPath, cfg.Notification.EmailFrom)
+		emailSender.SetSettings(siteSettings)
+		notifications.RegisterSender(emailSender)
+	}
+	// Customers' storefront inboxes are the in-app channel, next to email
+	notifications.RegisterSender(customerApp.NewInboxSender(
+		customerApp.NewCustomerNotificationService(customerPersistence.NewPostgresCustomerNotificationRepository(db), log),
+	))
+
+	// Large customer and order exports run as background jobs
+	exportJobs, err := export.NewJobManager(export.JobConfig{
+		Dir:           cfg.Export.Dir,
+		Retention:     cfg.Export.Retention,
+		MaxConcurrent: cfg.Export.MaxConcurrent,
+	}, notifications, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize export jobs")
+	}
+	exportCtx, stopExports := context.WithCancel(context.Background())
+	defer stopExports()
+	exportJobs.StartCleanup(exportCtx, time.Hour)
+	adminExportHandler := adminHttp.NewAdminExportHandler(exportJobs, adminAuth, log)
+	adminDiagnosticsHandler := adminHttp.NewAdminDiagnosticsHandler(db.IndexAdvisor(), adminAuth, log)
+	adminCacheHandler := adminHttp.NewAdminCacheHandler(cacheFlusher, adminAuth, log)
+
 	// ========== CATALOG BOUNDED CONTEXT ========== 
 
 	// Catalog repositories
@@ -138,6 +402,7 @@ func main() {
 	skuProductOptionValueXrefRepo := catalogPersistence.NewPostgresSkuProductOptionValueXrefRepository(db)
 	productOptionRepo := catalogPersistence.NewPostgresProductOptionRepository(db)
 	productOptionValueRepo := catalogPersistence.NewPostgresProductOptionValueRepository(db)
+	productDraftRepo := catalogPersistence.NewPostgresProductDraftRepository(db)
 
 	// Catalog application services
 	productService := catalogApp.NewProductService(productRepo, productAttributeRepo, productOptionXrefRepo, categoryProductXrefRepo)
@@ -146,9 +411,9 @@ func main() {
 	_ = catalogApp.NewProductOptionService(productOptionRepo, productOptionValueRepo) // Assigned to _
 
 	// Catalog command handlers
-	productCommandHandler := catalogCommands.NewProductCommandHandler(productRepo, productAttributeRepo, eventBus, val, log)
+	productCommandHandler := catalogCommands.NewProductCommandHandler(productRepo, productAttributeRepo, categoryProductXrefRepo, productDraftRepo, eventBus, val, log)
 	categoryCommandHandler := catalogCommands.NewCategoryCommandHandler(categoryRepo, categoryAttributeRepo, eventBus, val, log)
-	skuCommandHandler := catalogCommands.NewSKUCommandHandler(skuRepo, skuAttributeRepo, eventBus, val, log)
+	skuCommandHandler := catalogCommands.NewSKUCommandHandler(skuRepo, skuAttributeRepo, skuProductOptionValueXrefRepo, eventBus, val, log)
 
 	// Catalog query handlers
 	productQueryHandler := catalogQueries.NewProductQueryHandler(productRepo, cacheStore, log)
@@ -160,19 +425,216 @@ func main() {
 	adminCategoryHandler := catalogHttp.NewAdminCategoryHandler(categoryCommandHandler, categoryQueryHandler, log)
 	adminSKUHandler := catalogHttp.NewAdminSKUHandler(skuCommandHandler, skuQueryHandler, log)
 
+	// Edits of published products wait in drafts until a catalog reviewer approves them
+	productVersionQueryHandler := catalogQueries.NewProductVersionQueryHandler(productRepo, productDraftRepo, log)
+	catalogReviewer := middleware.RequirePermission(adminRoleService, adminDomain.PermissionApproveCatalog)
+	adminProductApprovalHandler := catalogHttp.NewAdminProductApprovalHandler(productCommandHandler, productVersionQueryHandler, adminAuth, catalogReviewer, log)
+
+	// URL keys are slugs, generated from names when left empty and unique wherever they are served
+	urlKeyService := catalogApp.NewURLKeyService(catalogPersistence.NewPostgresURLKeyRepository(db), productRepo, categoryRepo)
+	productCommandHandler.SetURLKeys(urlKeyService)
+	categoryCommandHandler.SetURLKeys(urlKeyService)
+	adminURLKeyHandler := catalogHttp.NewAdminURLKeyHandler(urlKeyService, adminAuth, log)
+
+	// Shipping restrictions by destination and age
+	shippingRestrictionService := catalogApp.NewShippingRestrictionService(catalogPersistence.NewPostgresShippingRestrictionRepository(db))
+	adminShippingRestrictionHandler := catalogHttp.NewAdminShippingRestrictionHandler(shippingRestrictionService, log)
+
+	// Catalog snapshots promote content between environments after reviewing their diff
+	catalogSnapshotService := catalogApp.NewCatalogSnapshotService(productRepo, categoryRepo, skuRepo, eventBus, cfg.App.Environment, log)
+	adminCatalogSnapshotHandler := catalogHttp.NewAdminCatalogSnapshotHandler(catalogSnapshotService, adminAuth, log)
+
+	// Category merchandising rules; previews list products the way the storefront does
+	categoryMerchandisingService := catalogApp.NewCategoryMerchandisingService(catalogPersistence.NewPostgresCategoryMerchandisingRepository(db), categoryRepo)
+	merchandisingPreviewQueryHandler := catalogQueries.NewProductQueryHandler(productRepo, cacheStore, log)
+	merchandisingPreviewQueryHandler.SetOutOfStockPolicy(catalogDomain.OutOfStockPolicy(cfg.Catalog.OutOfStockPolicy))
+	adminCategoryMerchandisingHandler := catalogHttp.NewAdminCategoryMerchandisingHandler(categoryMerchandisingService, merchandisingPreviewQueryHandler, adminAuth, log)
+
+	// Manual product badges; computed badges follow the catalog badge rules
+	productBadgeService := catalogApp.NewProductBadgeService(catalogPersistence.NewPostgresProductBadgeRepository(db), productRepo, catalogDomain.BadgeRules{
+		NewDays:            cfg.Catalog.BadgeNewDays,
+		BestsellerDays:     cfg.Catalog.BestsellerDays,
+		BestsellerMinUnits: cfg.Catalog.BestsellerMinUnits,
+	})
+	productQueryHandler.SetBadgeProvider(productBadgeService)
+	merchandisingPreviewQueryHandler.SetBadgeProvider(productBadgeService)
+	adminProductBadgeHandler := catalogHttp.NewAdminProductBadgeHandler(productBadgeService, adminAuth, log)
+
+	// Product completeness scores, shown on admin listings and gating first publication
+	qualityRules := catalogDomain.QualityRules{Rules: catalogDomain.DefaultQualityRules(), ImagePrefix: cfg.Catalog.QualityImagePrefix}
+	if len(cfg.Catalog.QualityRules) > 0 {
+		qualityRules.Rules = nil
+		for _, rule := range cfg.Catalog.QualityRules {
+			qualityRules.Rules = append(qualityRules.Rules, catalogDomain.QualityRule{
+				Check:      catalogDomain.QualityCheck(rule.Check),
+				Weight:     rule.Weight,
+				Blocking:   rule.Blocking,
+				MinLength:  rule.MinLength,
+				MinCount:   rule.MinCount,
+				Attributes: rule.Attributes,
+			})
+		}
+	}
+	if err := qualityRules.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid catalog quality rules")
+	}
+	dataQualityService := catalogApp.NewDataQualityService(catalogPersistence.NewPostgresProductQualityRepository(db), qualityRules)
+	productQueryHandler.SetQualityScorer(dataQualityService)
+	if cfg.Catalog.QualityBlockPublish {
+		productCommandHandler.SetPublishGate(dataQualityService)
+	}
+	adminDataQualityHandler := catalogHttp.NewAdminDataQualityHandler(dataQualityService, adminAuth, log)
+
+	// Catalog edits drop their cached entries; warmed ones and the navigation tree are loaded again right away
+	categoryQueryHandler.SetNavigationDepth(cfg.Catalog.NavigationDepth)
+	catalogCacheWarmer := catalogQueries.NewCacheWarmer(productQueryHandler, categoryQueryHandler, catalogPersistence.NewPostgresCatalogPopularityRepository(db), cacheStore, catalogQueries.CacheWarmConfig{
+		ProductIDs:     cfg.Catalog.CacheWarmProducts,
+		CategoryIDs:    cfg.Catalog.CacheWarmCategories,
+		TopProducts:    cfg.Catalog.CacheWarmTopProducts,
+		TopCategories:  cfg.Catalog.CacheWarmTopCategories,
+		PopularityDays: cfg.Catalog.CacheWarmPopularityDays,
+		Navigation:     true,
+	}, log)
+	if err := catalogCacheWarmer.Subscribe(eventBus); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe catalog cache warmer")
+	}
+	if cfg.Catalog.CacheWarmOnStartup {
+		go catalogCacheWarmer.WarmAll(context.Background())
+	}
+
+	// Rebuild the product availability read model used by storefront listings
+	productAvailabilityService := catalogApp.NewProductAvailabilityService(catalogPersistence.NewPostgresProductAvailabilityRepository(db), log)
+	catalogCtx, stopCatalog := context.WithCancel(context.Background())
+	defer stopCatalog()
+	productAvailabilityService.StartScheduledRefresh(catalogCtx, cfg.Catalog.AvailabilityRefreshInterval)
+
+	// Tell headless frontends and CDNs which storefront URLs to purge after catalog edits
+	var publishClient *httpclient.Client
+	if len(cfg.Catalog.PublishWebhookURLs) > 0 {
+		publishClient = httpclient.New(cfg.HTTPClient.Client("content-webhook", ""), log)
+	}
+	contentPublishService := catalogApp.NewContentPublishService(productRepo, categoryRepo, skuRepo, categoryProductXrefRepo, eventBus, publishClient, catalogApp.ContentPublishConfig{
+		BaseURL:       cfg.Catalog.PublishBaseURL,
+		WebhookURLs:   cfg.Catalog.PublishWebhookURLs,
+		WebhookSecret: cfg.Catalog.PublishWebhookSecret,
+		Events:        cfg.Catalog.PublishEvents,
+		ContentPath:   cfg.Catalog.PublishContentPath,
+		GlobalPaths:   cfg.Catalog.PublishGlobalPaths,
+		BatchWindow:   cfg.Catalog.PublishBatchWindow,
+	}, log)
+	if err := contentPublishService.Subscribe(eventBus); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe content publish service")
+	}
+	contentPublishService.Start(catalogCtx)
+	adminContentPublishHandler := catalogHttp.NewAdminContentPublishHandler(contentPublishService, adminAuth, log)
+
+	// Price-drop alerts, fired by manual, snapshot and scheduled price changes
+	priceWatchService := catalogApp.NewPriceWatchService(
+		skuRepo,
+		catalogPersistence.NewPostgresPriceWatchRepository(db),
+		catalogPersistence.NewPostgresScheduledPriceChangeRepository(db),
+		eventBus,
+		notifications,
+		catalogApp.PriceWatchConfig{Attribution: cfg.Catalog.PriceWatchAttribution},
+		log,
+	)
+	if err := priceWatchService.Subscribe(eventBus); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe price watch service")
+	}
+	priceWatchService.StartScheduler(catalogCtx, cfg.Catalog.PriceWatchInterval)
+	adminPriceWatchHandler := catalogHttp.NewAdminPriceWatchHandler(priceWatchService, adminAuth, log)
+
+	// ========== SEARCH BOUNDED CONTEXT ==========
+
+	// Search configuration (synonyms, stopwords, boosts, pinned products)
+	searchConfigService := searchApp.NewSearchConfigService(searchPersistence.NewPostgresSearchConfigRepository(db), cacheStore, eventBus, log)
+	productQueryHandler.SetSearchAnalyzer(searchConfigService)
+
+	// Search HTTP handlers
+	adminSearchConfigHandler := searchHttp.NewAdminSearchConfigHandler(searchConfigService, log)
+
 	// ========== CUSTOMER BOUNDED CONTEXT ========== 
 
 	// Customer repositories
 	customerRepo := customerPersistence.NewPostgresCustomerRepository(db)
+	customerNoteRepo := customerPersistence.NewPostgresCustomerNoteRepository(db)
 
 	// Customer command handlers
-	customerCommandHandler := customerCommands.NewCustomerCommandHandler(customerRepo, eventBus, val, log)
+	customerCommandHandler := customerCommands.NewCustomerCommandHandler(customerRepo, customerNoteRepo, eventBus, val, log)
 
 	// Customer query handlers
-	customerQueryHandler := customerQueries.NewCustomerQueryHandler(customerRepo, cacheStore, log)
+	customerQueryHandler := customerQueries.NewCustomerQueryHandler(customerRepo, customerNoteRepo, cacheStore, log)
+	customerQueryHandler.SetCacheTTLs(cfg.Storefront.CustomerCacheTTL, cfg.Storefront.CustomerMissTTL)
+	if err := customerQueryHandler.Subscribe(eventBus); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe customer cache invalidation")
+	}
 
 	// Customer HTTP handlers
 	adminCustomerHandler := customerHttp.NewAdminCustomerHandler(customerCommandHandler, customerQueryHandler, val, log)
+	adminCustomerExportHandler := customerHttp.NewAdminCustomerExportHandler(customerPersistence.NewPostgresCustomerExportRepository(db), exportJobs, adminAuth, log)
+
+	// Tags and typed attributes kept on customers by CRM integrations
+	customerAttributeService := customerApp.NewCustomerAttributeService(customerRepo, customerPersistence.NewPostgresCustomerAttributeRepository(db), eventBus, log)
+	adminCustomerTagHandler := customerHttp.NewAdminCustomerTagHandler(customerAttributeService, adminAuth, log)
+
+	// Duplicate accounts queued for review and merged into the account kept
+	customerMergeService := customerApp.NewCustomerMergeService(
+		customerRepo,
+		customerPersistence.NewPostgresCustomerDuplicateRepository(db),
+		auditService,
+		eventBus,
+		log,
+	)
+	duplicateScanCtx, stopDuplicateScan := context.WithCancel(context.Background())
+	defer stopDuplicateScan()
+	customerMergeService.StartScheduledScan(duplicateScanCtx, cfg.Customer.DuplicateScanInterval)
+	adminCustomerMergeHandler := customerHttp.NewAdminCustomerMergeHandler(customerMergeService, adminAuth, log)
+
+	// Customers imported from other platforms by a resumable background job
+	customerImportService, err := customerApp.NewCustomerImportService(
+		customerPersistence.NewPostgresCustomerImportRepository(db),
+		customerRepo,
+		customerPersistence.NewPostgresAddressRepository(db),
+		eventBus,
+		customerApp.CustomerImportConfig{
+			Dir:       cfg.Customer.ImportDir,
+			BatchSize: cfg.Customer.ImportBatchSize,
+			Lease:     cfg.Customer.ImportLease,
+		},
+		log,
+	)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create customer import service")
+	}
+	customerImportCtx, stopCustomerImports := context.WithCancel(context.Background())
+	defer stopCustomerImports()
+	customerImportService.StartWorker(customerImportCtx, cfg.Customer.ImportPollInterval)
+	adminCustomerImportHandler := customerHttp.NewAdminCustomerImportHandler(customerImportService, exportJobs, cfg.Customer.ImportMaxMiB<<20, adminAuth, log)
+
+	// ========== BUSINESS CALENDAR ==========
+
+	// Working days and holiday sets of sites and warehouses, shared by ship-by
+	// deadlines, delivery estimates and offers scheduled in business days
+	calendarLocation, err := time.LoadLocation(cfg.Calendar.TimeZone)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid calendar time zone")
+	}
+	calendarWorkdays, err := cfg.Calendar.Weekdays()
+	if err != nil {
+		log.WithError(err).Fatal("Invalid calendar workdays")
+	}
+	calendarService := calendarApp.NewCalendarService(
+		calendarPersistence.NewPostgresCalendarRepository(db),
+		calendarApp.CalendarConfig{
+			Location:    calendarLocation,
+			Workdays:    calendarWorkdays,
+			CutoffHour:  cfg.Calendar.CutoffHour,
+			CacheTTL:    cfg.Calendar.CacheTTL,
+			ImportYears: cfg.Calendar.ImportYears,
+		},
+		log,
+	)
+	adminCalendarHandler := calendarHttp.NewAdminCalendarHandler(calendarService, adminAuth, cfg.Calendar.ImportMaxKiB*1024, log)
 
 	// ========== OFFER BOUNDED CONTEXT ========== 
 
@@ -184,6 +646,7 @@ func main() {
 	offerPriceDataRepo := offerPersistence.NewPostgresOfferPriceDataRepository(db)
 	qualCritOfferXrefRepo := offerPersistence.NewPostgresQualCritOfferXrefRepository(db)
 	tarCritOfferXrefRepo := offerPersistence.NewPostgresTarCritOfferXrefRepository(db)
+	customerTargetingRepo := offerPersistence.NewPostgresCustomerTargetingRepository(db)
 
 	// Offer application services
 	offerService := offerApp.NewOfferService(
@@ -194,7 +657,33 @@ func main() {
 		offerPriceDataRepo,
 		qualCritOfferXrefRepo,
 		tarCritOfferXrefRepo,
+		customerTargetingRepo,
+		queryCache,
+		calendarService,
+		eventBus,
+		log,
+	)
+	offerReportService := offerApp.NewOfferReportService(offerPersistence.NewPostgresOfferReportRepository(db))
+	offerCodeBatchRepo := offerPersistence.NewPostgresOfferCodeBatchRepository(db)
+	offerCodeBatchService := offerApp.NewOfferCodeBatchService(offerRepo, offerCodeBatchRepo, eventBus, log)
+
+	// Offer HTTP handlers
+	adminOfferReportHandler := offerHttp.NewAdminOfferReportHandler(offerReportService, log)
+	adminOfferConditionHandler := offerHttp.NewAdminOfferConditionHandler(offerService, adminAuth, log)
+	adminOfferScheduleHandler := offerHttp.NewAdminOfferScheduleHandler(offerService, adminAuth, log)
+	adminOfferCodeBatchHandler := offerHttp.NewAdminOfferCodeBatchHandler(offerCodeBatchService, offerCodeBatchRepo, exportJobs, adminAuth, log)
+
+	// Catalog sale events ("daily deals") reprice SKUs and run their offers over the event window
+	saleEventService := catalogApp.NewSaleEventService(
+		catalogPersistence.NewPostgresSaleEventRepository(db),
+		skuRepo,
+		eventBus,
+		offerService,
+		catalogApp.SaleEventConfig{Upcoming: cfg.Catalog.SaleEventUpcoming},
+		log,
 	)
+	saleEventService.StartScheduler(catalogCtx, cfg.Catalog.SaleEventInterval)
+	adminSaleEventHandler := catalogHttp.NewAdminSaleEventHandler(saleEventService, adminAuth, log)
 
 	// ========== INVENTORY BOUNDED CONTEXT ========== 
 
@@ -202,7 +691,99 @@ func main() {
 	inventoryLevelRepo := inventoryPersistence.NewPostgresInventoryRepository(db)
 
 	// Inventory application services
-	inventoryService := inventoryApp.NewInventoryService(inventoryLevelRepo) // NewInventoryService takes a repo
+	inventoryService := inventoryApp.NewInventoryService(inventoryLevelRepo, eventBus)
+
+	// Available-to-promise: on hand, less reserved and allocated stock, plus expected inbound receipts
+	atpService := inventoryApp.NewATPService(
+		inventoryLevelRepo,
+		inventoryPersistence.NewPostgresInboundReceiptRepository(db),
+		cfg.Inventory.InboundHorizon,
+		eventBus,
+	)
+
+	// SKUs are made unavailable when their stock runs out and available again once restocked
+	skuAvailabilitySyncService := catalogApp.NewSKUAvailabilitySyncService(
+		skuRepo,
+		catalogPersistence.NewPostgresSKUStockoutRepository(db),
+		atpService,
+		eventBus,
+		catalogApp.SKUAvailabilitySyncConfig{
+			Hysteresis: catalogDomain.StockHysteresis{
+				OutOfStockAt:  cfg.Inventory.OutOfStockAt,
+				BackInStockAt: cfg.Inventory.BackInStockAt,
+			},
+		},
+		log,
+	)
+	if cfg.Inventory.AvailabilitySync {
+		if err := skuAvailabilitySyncService.Subscribe(eventBus); err != nil {
+			log.WithError(err).Fatal("Failed to subscribe SKU availability sync")
+		}
+	}
+	adminSKUAvailabilitySyncHandler := catalogHttp.NewAdminSKUAvailabilitySyncHandler(skuAvailabilitySyncService, adminAuth, log)
+
+	// Bulk CSV imports of quantities on hand, recorded in the adjustment ledger
+	inventoryImportService := inventoryApp.NewInventoryImportService(
+		inventoryLevelRepo,
+		inventoryPersistence.NewPostgresInventoryAdjustmentRepository(db),
+		eventBus,
+		log,
+	)
+
+	// Marketplaces reserve stock within their allocation through the integration API
+	salesChannels := make([]*inventoryApp.SalesChannel, 0, len(cfg.Inventory.Channels))
+	for code, channel := range cfg.Inventory.Channels {
+		salesChannels = append(salesChannels, &inventoryApp.SalesChannel{
+			Code: code,
+			Allocation: inventoryDomain.ChannelAllocation{
+				Percent:     channel.AllocationPercent,
+				MaxQuantity: channel.MaxQuantity,
+			},
+			SKUCaps:        channel.SKUCaps,
+			ReservationTTL: channel.ReservationTTL,
+			MaxTTL:         channel.MaxReservationTTL,
+		})
+	}
+	channelReservationService := inventoryApp.NewChannelReservationService(
+		inventoryPersistence.NewPostgresChannelReservationRepository(db),
+		salesChannels,
+		eventBus,
+		log,
+	)
+	reservationCtx, stopReservationSweep := context.WithCancel(context.Background())
+	defer stopReservationSweep()
+	channelReservationService.StartExpirySweep(reservationCtx, cfg.Inventory.ReservationSweepInterval)
+
+	// Flash-sale allocation: reservations of SKUs in a flash sale take Redis tokens, reconciled
+	// with the inventory levels by this service only
+	flashAllocationService := inventoryApp.NewFlashAllocationService(
+		inventoryPersistence.NewPostgresFlashAllocationRepository(db),
+		flashTokens,
+		eventBus,
+		log,
+	)
+	flashReconcileCtx, stopFlashReconcile := context.WithCancel(context.Background())
+	defer stopFlashReconcile()
+	flashAllocationService.StartReconciler(flashReconcileCtx, cfg.Inventory.FlashReconcileInterval)
+
+	// Bin locations of warehouses, used to sort pick lists along the pick path
+	binLocationService := inventoryApp.NewBinLocationService(inventoryPersistence.NewPostgresBinLocationRepository(db), inventoryLevelRepo, log)
+
+	// Customer returns received into inventory: restocked, damaged or held in quarantine
+	returnReceivingService := inventoryApp.NewReturnReceivingService(
+		inventoryPersistence.NewPostgresReturnReceiptRepository(db),
+		inventoryLevelRepo,
+		eventBus,
+		log,
+	)
+
+	// Inventory HTTP handlers
+	adminInventoryHandler := inventoryHttp.NewAdminInventoryHandler(atpService, adminAuth, log)
+	adminFlashAllocationHandler := inventoryHttp.NewAdminFlashAllocationHandler(flashAllocationService, adminAuth, log)
+	adminBinLocationHandler := inventoryHttp.NewAdminBinLocationHandler(binLocationService, adminAuth, log)
+	adminReturnHandler := inventoryHttp.NewAdminReturnHandler(returnReceivingService, adminAuth, log)
+	adminInventoryImportHandler := inventoryHttp.NewAdminInventoryImportHandler(inventoryImportService, inventoryPersistence.NewPostgresInventoryExportRepository(db), exportJobs, adminAuth, log)
+	integrationChannelReservationHandler := inventoryHttp.NewIntegrationChannelReservationHandler(channelReservationService, channelAuth, log)
 
 	// ========== TAX BOUNDED CONTEXT ========== 
 
@@ -210,7 +791,20 @@ func main() {
 	taxDetailRepo := taxPersistence.NewPostgresTaxDetailRepository(db)
 
 	// Tax application services
-	taxService := taxApp.NewTaxService(taxDetailRepo) // Pass taxDetailRepository
+	// An external tax engine, when configured, calculates tax instead of the configured tax details
+	var taxProvider taxApp.TaxProvider
+	if cfg.Tax.ProviderURL != "" {
+		taxProvider = taxApp.NewHTTPTaxProvider(httpclient.New(cfg.HTTPClient.Client("tax", cfg.Tax.ProviderURL), log), cfg.Tax.ProviderPath)
+	}
+	taxService := taxApp.NewTaxService(taxDetailRepo, queryCache, taxProvider, eventBus, log)
+
+	// Duties and import fees of cross-border orders; an external landed cost service,
+	// when configured, estimates them instead of the duty tables
+	var landedCostProvider taxApp.LandedCostProvider
+	if cfg.Tax.LandedCostURL != "" {
+		landedCostProvider = taxApp.NewHTTPLandedCostProvider(httpclient.New(cfg.HTTPClient.Client("landed-cost", cfg.Tax.LandedCostURL), log), cfg.Tax.LandedCostPath)
+	}
+	landedCostService := taxApp.NewLandedCostService(taxPersistence.NewPostgresLandedCostRepository(db), landedCostProvider, log)
 
 	// ========== ORDER BOUNDED CONTEXT ========== 
 
@@ -221,6 +815,37 @@ func main() {
 	orderItemAdjustmentRepo := orderPersistence.NewPostgresOrderItemAdjustmentRepository(db)
 	orderItemAttributeRepo := orderPersistence.NewPostgresOrderItemAttributeRepository(db)
 	fulfillmentGroupRepo := orderPersistence.NewPostgresFulfillmentGroupRepository(db)
+	orderTaxDetailRepo := orderPersistence.NewPostgresOrderTaxDetailRepository(db)
+	orderNoteRepo := orderPersistence.NewPostgresOrderNoteRepository(db)
+
+	// Inventory released by cancelled orders is queued and retried until it succeeds
+	deallocationService := orderApp.NewInventoryDeallocationService(
+		orderPersistence.NewPostgresInventoryDeallocationRepository(db),
+		orderRepo,
+		inventoryService,
+		orderApp.InventoryDeallocationConfig{
+			MaxAttempts: cfg.Order.DeallocationMaxAttempts,
+			RetryDelay:  cfg.Order.DeallocationRetryDelay,
+		},
+		log,
+	)
+	deallocationCtx, stopDeallocations := context.WithCancel(context.Background())
+	defer stopDeallocations()
+	deallocationService.StartScheduledProcessing(deallocationCtx, cfg.Order.DeallocationInterval)
+
+	// Cart policy evaluated when items are added and when the order is submitted
+	cartPolicy := &orderDomain.CartPolicy{
+		MaxQuantityPerSKU: cfg.Order.MaxQuantityPerSKU,
+		MaxDistinctLines:  cfg.Order.MaxDistinctLines,
+	}
+	for _, restriction := range cfg.Order.CategoryRestrictions {
+		cartPolicy.CategoryRestrictions = append(cartPolicy.CategoryRestrictions, orderDomain.CategoryRestriction{
+			CategoryID:       restriction.CategoryID,
+			AllowedSegments:  restriction.AllowedSegments,
+			BlockedCountries: restriction.BlockedCountries,
+		})
+	}
+	cartValidator := orderApp.NewCartValidator(cartPolicy, orderPersistence.NewPostgresCartPolicyContextRepository(db))
 
 	// Order application service
 	orderService := orderApp.NewOrderService(
@@ -232,9 +857,116 @@ func main() {
 		fulfillmentGroupRepo,
 		offerService,
 		inventoryService,
+		atpService,
 		productService,
 		skuService,
 		taxService,
+		orderDomain.TaxMode(cfg.Tax.Mode),
+		orderTaxDetailRepo,
+		cartValidator,
+		deallocationService,
+		flashAllocationService,
+		siteSettings,
+	)
+
+	// Order notes and timeline
+	orderNoteService := orderApp.NewOrderNoteService(orderRepo, orderNoteRepo)
+
+	// Gift wraps and personal messages on order items
+	giftOptionService := orderApp.NewGiftOptionService(
+		orderRepo,
+		orderItemRepo,
+		orderPersistence.NewPostgresPersonalMessageRepository(db),
+		orderPersistence.NewPostgresGiftWrapOptionRepository(db),
+		orderService,
+		skuService,
+	)
+
+	// Duties and import fees shown on international checkouts, charged with DDP orders
+	orderLandedCostRepo := orderPersistence.NewPostgresOrderLandedCostRepository(db)
+	orderLandedCostService := orderApp.NewLandedCostService(
+		orderRepo,
+		orderItemRepo,
+		orderLandedCostRepo,
+		orderPersistence.NewPostgresCartPolicyContextRepository(db),
+		landedCostService,
+		cfg.Tax.ShipFromCountry,
+	)
+
+	// Message catalogs translating documents and emails into the customer's locale;
+	// keys a catalog leaves out fall back to the default locale and the built-in English
+	messages := i18n.NewCatalog(cfg.Storefront.DefaultLocale)
+	messages.Add(orderApp.MessagesLocale, orderApp.Messages)
+	if cfg.Storefront.MessageDir != "" {
+		if err := messages.LoadDir(cfg.Storefront.MessageDir); err != nil {
+			log.WithError(err).Fatal("Failed to load message catalogs")
+		}
+	}
+
+	// PDF invoices, quotes, packing slips and commercial invoices, rendered by a bounded pool of workers
+	documentEngine := pdf.NewEngine()
+	documentEngine.SetMessages(messages)
+	for name, text := range orderApp.DocumentTemplates {
+		if err := documentEngine.AddTemplate(name, text); err != nil {
+			log.WithError(err).Fatal("Failed to parse document templates")
+		}
+	}
+	if cfg.Documents.TemplateDir != "" {
+		if err := documentEngine.LoadTemplates(cfg.Documents.TemplateDir); err != nil {
+			log.WithError(err).Fatal("Failed to load document templates")
+		}
+	}
+	documentFonts := map[pdf.FontStyle]string{
+		pdf.Regular:    cfg.Documents.FontRegular,
+		pdf.Bold:       cfg.Documents.FontBold,
+		pdf.Italic:     cfg.Documents.FontItalic,
+		pdf.BoldItalic: cfg.Documents.FontBoldItalic,
+	}
+	for style, path := range documentFonts {
+		if path == "" {
+			continue
+		}
+		if err := documentEngine.RegisterFontFile(pdf.DefaultFamily, style, path); err != nil {
+			log.WithError(err).Fatal("Failed to load document font")
+		}
+	}
+	if cfg.Documents.LogoPath != "" {
+		logoData, err := os.ReadFile(cfg.Documents.LogoPath)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to read document logo")
+		}
+		logo, err := pdf.NewImage(logoData)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load document logo")
+		}
+		documentEngine.RegisterImage("logo", logo)
+	}
+	documentQueue := pdf.NewQueue(documentEngine, pdf.QueueConfig{
+		Workers: cfg.Documents.Workers,
+		Size:    cfg.Documents.QueueSize,
+		Timeout: cfg.Documents.RenderTimeout,
+	}, log)
+	defer documentQueue.Close()
+	// Serial numbers shipped with high-value items are printed on invoices
+	shipmentRepo := fulfillmentPersistence.NewPostgresShipmentRepository(db)
+	shipmentSerialRepo := fulfillmentPersistence.NewPostgresShipmentSerialRepository(db)
+	serialNumberService := fulfillmentApp.NewSerialNumberService(shipmentSerialRepo, shipmentRepo, orderItemRepo)
+	orderDocumentService := orderApp.NewOrderDocumentService(
+		orderRepo,
+		orderItemRepo,
+		orderAdjustmentRepo,
+		orderTaxDetailRepo,
+		giftOptionService,
+		serialNumberService,
+		landedCostService,
+		orderLandedCostRepo,
+		documentQueue,
+		orderApp.DocumentSeller{
+			Name:         cfg.Documents.SellerName,
+			AddressLines: cfg.Documents.SellerAddress,
+			TaxID:        cfg.Documents.SellerTaxID,
+		},
+		cfg.Order.QuoteValidity,
 	)
 
 	// Order command handlers
@@ -244,7 +976,218 @@ func main() {
 	orderQueryHandler := orderQueries.NewOrderQueryHandler(orderService, cacheStore, log) // Pass orderService
 
 	// Order HTTP handlers
-	adminOrderHandler := orderHttp.NewAdminOrderHandler(orderCommandHandler, orderQueryHandler, val, log)
+	adminOrderHandler := orderHttp.NewAdminOrderHandler(orderCommandHandler, orderQueryHandler, orderNoteService, val, log)
+	adminOrderExportHandler := orderHttp.NewAdminOrderExportHandler(orderPersistence.NewPostgresOrderExportRepository(db), exportJobs, adminAuth, log)
+	adminDeallocationHandler := orderHttp.NewAdminDeallocationHandler(deallocationService, adminAuth, log)
+	adminGiftOptionHandler := orderHttp.NewAdminGiftOptionHandler(giftOptionService, adminAuth, log)
+	adminLandedCostHandler := orderHttp.NewAdminLandedCostHandler(orderLandedCostService, adminAuth, log)
+	adminCustomsHandler := taxHttp.NewAdminCustomsHandler(landedCostService, adminAuth, log)
+	adminOrderDocumentHandler := orderHttp.NewAdminOrderDocumentHandler(orderDocumentService, adminAuth, log)
+
+	// Files attached to orders, kept in object storage when configured
+	var attachmentStore objectstore.Store
+	if cfg.Attachments.StorageURL != "" {
+		attachmentClientCfg := cfg.HTTPClient.Client("attachments", "")
+		attachmentClientCfg.Timeout = cfg.Attachments.Timeout
+		attachmentClientCfg.RedactHeaders = []string{"X-Amz-Security-Token"}
+		attachmentStore, err = objectstore.New(cfg.Attachments.Store(), httpclient.New(attachmentClientCfg, log))
+		if err != nil {
+			log.WithError(err).Fatal("Failed to initialize order attachment storage")
+		}
+	}
+	attachmentMaxBytes := int64(cfg.Attachments.MaxSizeKiB) << 10
+	orderAttachmentService := orderApp.NewOrderAttachmentService(
+		orderRepo,
+		orderPersistence.NewPostgresOrderAttachmentRepository(db),
+		attachmentStore,
+		orderApp.OrderAttachmentConfig{
+			MaxSizeBytes:    attachmentMaxBytes,
+			ContentTypes:    cfg.Attachments.ContentTypes,
+			CustomerUploads: cfg.Attachments.CustomerUploads,
+		},
+		log,
+	)
+	adminOrderAttachmentHandler := orderHttp.NewAdminOrderAttachmentHandler(
+		orderAttachmentService,
+		attachmentMaxBytes,
+		adminAuth,
+		middleware.RequirePermission(adminRoleService, adminDomain.PermissionReadOrder),
+		middleware.RequirePermission(adminRoleService, adminDomain.PermissionAllOrder),
+		log,
+	)
+
+	// Payment links for unpaid orders; the storefront collects the payments
+	assistedOrderRepo := orderPersistence.NewPostgresAssistedOrderRepository(db)
+	paymentLinkService := orderApp.NewPaymentLinkService(
+		orderService,
+		orderPersistence.NewPostgresPaymentLinkRepository(db),
+		assistedOrderRepo,
+		nil,
+		notifications,
+		orderApp.PaymentLinkConfig{URL: cfg.Order.PayLinkURL, TTL: cfg.Order.PayLinkTTL, Messages: messages},
+		log,
+	)
+	adminPaymentLinkHandler := orderHttp.NewAdminPaymentLinkHandler(paymentLinkService, adminAuth, val, log)
+
+	// Cash on delivery, bank transfer and pay-in-store orders wait for staff to record the payment
+	offlinePaymentService := orderApp.NewOfflinePaymentService(
+		orderService,
+		orderPersistence.NewPostgresOfflinePaymentMethodRepository(db),
+		orderPersistence.NewPostgresOfflinePaymentRepository(db),
+		assistedOrderRepo,
+		orderPersistence.NewPostgresShippingDestinationRepository(db),
+		log,
+	)
+	adminOfflinePaymentHandler := orderHttp.NewAdminOfflinePaymentHandler(offlinePaymentService, adminAuth, val, log)
+
+	// Orders held for review; staff are emailed about holds open longer than the SLA
+	orderHoldService := orderApp.NewOrderHoldService(
+		orderRepo,
+		orderPersistence.NewPostgresOrderHoldRepository(db),
+		orderService,
+		notifications,
+		orderApp.OrderHoldConfig{SLA: cfg.Order.HoldSLA, AlertEmail: cfg.Order.HoldAlertEmail},
+		log,
+	)
+	holdCtx, stopHoldMonitor := context.WithCancel(context.Background())
+	defer stopHoldMonitor()
+	orderHoldService.StartSLAMonitor(holdCtx, cfg.Order.HoldSLAInterval)
+	adminOrderHoldHandler := orderHttp.NewAdminOrderHoldHandler(orderHoldService, adminAuth, log)
+
+	// Staff edit orders until they ship; only added lines are priced again
+	orderEditService := orderApp.NewOrderEditService(
+		orderRepo,
+		orderItemRepo,
+		orderPersistence.NewPostgresOrderEditRepository(db),
+		shipmentRepo,
+		orderService,
+		orderApp.OrderEditConfig{Window: cfg.Order.EditWindow, TTL: cfg.Order.EditTTL},
+		log,
+	)
+	adminOrderEditHandler := orderHttp.NewAdminOrderEditHandler(orderEditService, adminAuth, log)
+
+	// Completed orders past the retention window of their status move to the archive tables
+	orderArchiveService := orderApp.NewOrderArchiveService(
+		orderRepo,
+		orderPersistence.NewPostgresOrderArchiveRepository(db),
+		cfg.Order.ArchiveBatchSize,
+		log,
+	)
+	archiveCtx, stopOrderArchival := context.WithCancel(context.Background())
+	defer stopOrderArchival()
+	orderArchiveService.StartScheduledArchival(archiveCtx, cfg.Order.ArchiveInterval)
+	adminOrderArchiveHandler := orderHttp.NewAdminOrderArchiveHandler(orderArchiveService, adminAuth, log)
+
+	// Order tags and tag rules behind the ops work queues
+	orderTagService := orderApp.NewOrderTagService(
+		orderRepo,
+		orderPersistence.NewPostgresOrderTagRepository(db),
+		log,
+	)
+	tagRuleCtx, stopTagRules := context.WithCancel(context.Background())
+	defer stopTagRules()
+	orderTagService.StartRuleTagger(tagRuleCtx, cfg.Order.TagRuleInterval)
+	adminOrderTagHandler := orderHttp.NewAdminOrderTagHandler(orderTagService, adminAuth, log)
+
+	// Orders placed by agents for phone and mail customers
+	assistedOrderService := orderApp.NewAssistedOrderService(
+		orderService,
+		assistedOrderRepo,
+		orderPersistence.NewPostgresAssistedOrderCustomerRepository(db),
+		paymentApp.NewPaymentService(),
+		paymentLinkService,
+		log,
+	)
+	adminAssistedOrderHandler := orderHttp.NewAdminAssistedOrderHandler(assistedOrderService, cfg.Order.AssistedOverrideRole, adminAuth, val, log)
+
+	// Orders placed on external sales channels (marketplaces), sent in each channel's order format
+	orderChannels := make([]*orderApp.OrderChannel, 0, len(cfg.Integrations.Channels))
+	var acknowledgementClient *httpclient.Client
+	for code, channel := range cfg.Integrations.Channels {
+		orderChannels = append(orderChannels, &orderApp.OrderChannel{
+			Code:               code,
+			Format:             channel.OrderFormat,
+			AcknowledgementURL: channel.AcknowledgementURL,
+		})
+		if channel.AcknowledgementURL != "" && acknowledgementClient == nil {
+			acknowledgementClient = httpclient.New(cfg.HTTPClient.Client("channel-acknowledgement", ""), log)
+		}
+	}
+	channelOrderIngestionService := orderApp.NewChannelOrderIngestionService(
+		orderService,
+		orderPersistence.NewPostgresChannelOrderRepository(db),
+		orderPersistence.NewPostgresChannelSKUMappingRepository(db),
+		orderPersistence.NewPostgresAssistedOrderCustomerRepository(db),
+		channelReservationService,
+		acknowledgementClient,
+		orderChannels,
+		log,
+	)
+	adminChannelOrderHandler := orderHttp.NewAdminChannelOrderHandler(channelOrderIngestionService, adminAuth, val, log)
+	integrationChannelOrderHandler := orderHttp.NewIntegrationChannelOrderHandler(channelOrderIngestionService, channelAuth, log)
+
+	// ========== ANALYTICS BOUNDED CONTEXT ========== 
+
+	// Analytics application services
+	analyticsService := analyticsApp.NewAnalyticsService(analyticsPersistence.NewPostgresAnalyticsRepository(db), log)
+
+	// Refresh summary tables in the background (nightly-style batch job); the
+	// refresh and warehouse export queries scan whole tables, so they run
+	// without the query timeout
+	analyticsCtx, stopAnalytics := context.WithCancel(database.WithQueryTimeout(context.Background(), 0))
+	defer stopAnalytics()
+	analyticsService.StartScheduledRefresh(analyticsCtx, 6*time.Hour)
+
+	// Analytics HTTP handlers
+	adminAnalyticsHandler := analyticsHttp.NewAdminAnalyticsHandler(analyticsService, log)
+	channelReportService := analyticsApp.NewChannelReportService(analyticsPersistence.NewPostgresChannelReportRepository(db), log)
+	adminChannelReportHandler := analyticsHttp.NewAdminChannelReportHandler(channelReportService, log)
+	adminTaxReportHandler := analyticsHttp.NewAdminTaxReportHandler(
+		analyticsApp.NewTaxReportService(analyticsPersistence.NewPostgresTaxReportRepository(db), log),
+		log,
+	)
+
+	// Orders, inventory and offer redemptions are exported to the data warehouse, if storage is configured
+	var adminWarehouseExportHandler *analyticsHttp.AdminWarehouseExportHandler
+	if cfg.Warehouse.StorageURL != "" {
+		warehouseClientCfg := cfg.HTTPClient.Client("warehouse", "")
+		warehouseClientCfg.Timeout = cfg.Warehouse.UploadTimeout
+		warehouseClientCfg.RedactHeaders = []string{"X-Amz-Security-Token"}
+		warehouseStore, err := objectstore.New(objectstore.Config{
+			URL:             cfg.Warehouse.StorageURL,
+			Region:          cfg.Warehouse.Region,
+			Endpoint:        cfg.Warehouse.Endpoint,
+			PathStyle:       cfg.Warehouse.PathStyle,
+			AccessKeyID:     cfg.Warehouse.AccessKeyID,
+			SecretAccessKey: cfg.Warehouse.SecretAccessKey,
+			SessionToken:    cfg.Warehouse.SessionToken,
+		}, httpclient.New(warehouseClientCfg, log))
+		if err != nil {
+			log.WithError(err).Fatal("Failed to initialize warehouse export storage")
+		}
+		warehouseExportService := analyticsApp.NewWarehouseExportService(
+			analyticsPersistence.NewPostgresWarehouseExportRepository(db),
+			warehouseStore,
+			cfg.Warehouse.BatchSize,
+			log,
+		)
+		warehouseExportService.StartScheduledExport(analyticsCtx, cfg.Warehouse.Interval)
+		adminWarehouseExportHandler = analyticsHttp.NewAdminWarehouseExportHandler(warehouseExportService, log)
+	}
+
+	// Sales summary, low stock and offer performance reports emailed to subscribers as CSV or PDF by export jobs
+	reportSubscriptionService := adminApp.NewReportSubscriptionService(
+		adminPersistence.NewPostgresReportSubscriptionRepository(db),
+		[]export.Report{
+			analyticsApp.NewSalesSummaryReport(channelReportService),
+			inventoryApp.NewLowStockReport(inventoryPersistence.NewPostgresInventoryExportRepository(db)),
+			offerApp.NewOfferPerformanceReport(offerReportService),
+		},
+		exportJobs,
+		log,
+	)
+	reportSubscriptionService.StartScheduledDelivery(exportCtx, cfg.Export.ReportInterval)
+	adminReportSubscriptionHandler := adminHttp.NewAdminReportSubscriptionHandler(reportSubscriptionService, adminAuth, log)
 
 	// ========== PAYMENT BOUNDED CONTEXT ========== 
 
@@ -257,28 +1200,160 @@ func main() {
 	// Payment query handlers
 	paymentQueryHandler := paymentQueries.NewPaymentQueryHandler(paymentRepo, cacheStore, log)
 
+	// Payment gateway for the configured provider; provider calls go through the resilient HTTP client
+	paymentGateways := paymentDomain.NewPaymentGatewayService()
+	gatewayConfig := &paymentDomain.GatewayConfig{
+		GatewayName: cfg.Payment.Provider,
+		Enabled:     true,
+		APIKey:      cfg.Payment.PublicKey,
+		APISecret:   cfg.Payment.SecretKey,
+	}
+	var cardGateway paymentDomain.PaymentGateway
+	switch cfg.Payment.Provider {
+	case "stripe":
+		stripeClient := httpclient.New(cfg.HTTPClient.Client("stripe", paymentDomain.StripeBaseURL), log)
+		cardGateway = paymentDomain.NewStripeGateway(gatewayConfig, stripeClient)
+		paymentGateways.RegisterGateway(cardGateway)
+	case "paypal":
+		paypalBaseURL := paymentDomain.PayPalSandboxBaseURL
+		if cfg.IsProduction() {
+			paypalBaseURL = paymentDomain.PayPalProductionBaseURL
+		}
+		paypalClient := httpclient.New(cfg.HTTPClient.Client("paypal", paypalBaseURL), log)
+		cardGateway = paymentDomain.NewPayPalGateway(gatewayConfig, paypalClient)
+		paymentGateways.RegisterGateway(cardGateway)
+	}
+	_ = paymentGateways // Assigned to _ until payment commands process through the gateway
+
+	// Captures, voids and refunds of the tenders orders were paid with on the storefront.
+	// No gift card issuer is integrated yet, so gift card tenders are not accepted.
+	splitPaymentService := orderApp.NewSplitPaymentService(
+		orderService,
+		orderPersistence.NewPostgresOrderTenderRepository(db),
+		orderApp.TenderGateways{Card: cardGateway},
+		log,
+	)
+	adminSplitPaymentHandler := orderHttp.NewAdminSplitPaymentHandler(splitPaymentService, adminAuth, val, log)
+
 	// Payment HTTP handlers
 	adminPaymentHandler := paymentHttp.NewAdminPaymentHandler(paymentCommandHandler, paymentQueryHandler, val, log)
 
 	// ========== FULFILLMENT BOUNDED CONTEXT ========== 
 
-	// Fulfillment repositories
-	shipmentRepo := fulfillmentPersistence.NewPostgresShipmentRepository(db)
-
 	// Fulfillment command handlers
 	shipmentCommandHandler := fulfillmentCommands.NewShipmentCommandHandler(shipmentRepo, eventBus, log)
 
+	// Customers find shipped and delivered orders in their storefront inbox
+	orderUpdateNotifier := orderApp.NewOrderUpdateNotifier(orderRepo, notifications, log)
+	if err := orderUpdateNotifier.Subscribe(eventBus); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe order update notifier")
+	}
+
 	// Fulfillment HTTP handlers
-	adminShipmentHandler := fulfillmentHttp.NewAdminShipmentHandler(shipmentCommandHandler, shipmentRepo, val, log)
+	adminShipmentHandler := fulfillmentHttp.NewAdminShipmentHandler(shipmentCommandHandler, shipmentRepo, serialNumberService, orderDocumentService, val, log, cfg.Tax.ShipFromCountry)
+
+	// Fulfillment groups are checked against their ship-by deadline in business days;
+	// operations are emailed about the late and at-risk ones
+	fulfillmentSLAService := fulfillmentApp.NewFulfillmentSLAService(
+		fulfillmentPersistence.NewPostgresFulfillmentSLARepository(db),
+		calendarService,
+		notifications,
+		fulfillmentApp.FulfillmentSLAConfig{
+			DefaultShipWithinDays: cfg.Fulfillment.SLAShipWithinDays,
+			AtRisk:                cfg.Fulfillment.SLAAtRisk,
+			AlertEmail:            cfg.Fulfillment.SLAAlertEmail,
+		},
+		log,
+	)
+	slaCtx, stopSLAMonitor := context.WithCancel(context.Background())
+	defer stopSLAMonitor()
+	fulfillmentSLAService.StartMonitor(slaCtx, cfg.Fulfillment.SLACheckInterval)
+	adminFulfillmentSLAHandler := fulfillmentHttp.NewAdminFulfillmentSLAHandler(fulfillmentSLAService, adminAuth, log)
+
+	// Fulfillment options offered at checkout, priced by weight or subtotal bands
+	fulfillmentOptionService := fulfillmentApp.NewFulfillmentOptionService(fulfillmentPersistence.NewPostgresFulfillmentOptionRepository(db), log)
+	adminFulfillmentOptionHandler := fulfillmentHttp.NewAdminFulfillmentOptionHandler(fulfillmentOptionService, adminAuth, log)
+
+	// ========== WARRANTY ==========
+
+	// Warranty terms per product and the registrations support looks up
+	warrantyService := warrantyApp.NewWarrantyService(
+		warrantyPersistence.NewPostgresWarrantyTermsRepository(db),
+		warrantyPersistence.NewPostgresProductRegistrationRepository(db),
+		orderRepo,
+		orderItemRepo,
+		serialNumberService,
+	)
+	adminWarrantyHandler := warrantyHttp.NewAdminWarrantyHandler(warrantyService, adminAuth, log)
+
+	// ========== SITE DOMAINS ==========
+
+	// Custom domains of the configured sites, served by the storefront once verified by DNS
+	siteIDs := []string{cfg.Storefront.DefaultSite}
+	for id := range cfg.Storefront.Sites {
+		siteIDs = append(siteIDs, id)
+	}
+	siteDomainService := siteApp.NewSiteDomainService(sitePersistence.NewPostgresSiteDomainRepository(db), sitePersistence.NewPostgresCertificateCache(db), net.DefaultResolver, siteIDs, log)
+	adminSiteDomainHandler := siteHttp.NewAdminSiteDomainHandler(siteDomainService, adminAuth, log)
+
+	// ========== INBOUND WEBHOOKS ==========
+
+	// Payment, carrier and other providers post webhooks signed with their own scheme;
+	// they are stored once verified and processed in the background by the handlers
+	// registered for their provider and event
+	webhookVerifiers := make(map[string]webhook.Verifier, len(cfg.Webhooks.Providers))
+	for name, provider := range cfg.Webhooks.Providers {
+		verifier, err := webhook.NewVerifier(provider.Scheme, provider.Secret, provider.Tolerance)
+		if err != nil {
+			log.WithError(err).WithField("provider", name).Fatal("Invalid webhook provider")
+		}
+		webhookVerifiers[name] = verifier
+	}
+	inboundWebhookService := webhookApp.NewInboundWebhookService(
+		webhookPersistence.NewPostgresInboundWebhookRepository(db),
+		webhookVerifiers,
+		webhookApp.InboundWebhookConfig{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			RetryDelay:  cfg.Webhooks.RetryDelay,
+		},
+		log,
+	)
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	defer stopWebhooks()
+	inboundWebhookService.StartWorker(webhookCtx, cfg.Webhooks.PollInterval)
+	webhookHandler := webhookHttp.NewWebhookHandler(inboundWebhookService, log)
+	adminWebhookHandler := webhookHttp.NewAdminWebhookHandler(inboundWebhookService, adminAuth, log)
 
 	// ========== ROUTER SETUP ========== 
 
 	// Setup router
+	// Convert config.RouteTimeoutConfig to middleware.RouteTimeout
+	requestTimeouts := middleware.TimeoutConfig{Default: cfg.Server.RequestTimeout}
+	for _, route := range cfg.Server.RouteTimeouts {
+		requestTimeouts.Routes = append(requestTimeouts.Routes, middleware.RouteTimeout{
+			Method:     route.Method,
+			PathPrefix: route.PathPrefix,
+			Timeout:    route.Timeout,
+		})
+	}
+
 	r := chi.NewRouter()
 
 	// Apply global middleware
 	r.Use(middleware.RequestLogger())
+	if cfg.RequestLog.Enabled {
+		// Outside Recovery so requests that panic are logged with their 500
+		r.Use(middleware.RequestLog(requestLogRecorder, middleware.RequestLogConfig{
+			ReadSampleRate:  cfg.RequestLog.ReadSampleRate,
+			WriteSampleRate: cfg.RequestLog.WriteSampleRate,
+			CaptureBodies:   cfg.RequestLog.CaptureBodies,
+			MaxBodyBytes:    cfg.RequestLog.MaxBodyBytes,
+			ExcludePaths:    cfg.RequestLog.ExcludePaths,
+			RedactFields:    cfg.RequestLog.RedactFields,
+		}))
+	}
 	r.Use(middleware.Recovery()) // Pass log to Recoverer
+	r.Use(middleware.Timeout(requestTimeouts))
 	r.Use(middleware.CORS(middleware.CORSConfig{ // Convert config.CORSConfig to middleware.CORSConfig
 		AllowedOrigins:   cfg.CORS.AllowedOrigins,
 		AllowedMethods:   cfg.CORS.AllowedMethods,
@@ -287,6 +1362,18 @@ func main() {
 		AllowCredentials: cfg.CORS.AllowCredentials,
 		MaxAge:           cfg.CORS.MaxAge,
 	}))
+	// Schedules entered without an offset are read in the time zone of the X-Site-ID site
+	timeZones, err := cfg.Storefront.TimeZones()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load site time zones")
+	}
+	r.Use(middleware.SiteTimeZone(timeZones, cfg.Storefront.DefaultSite))
+	// List requests carrying ?savedViewId= get the filters of the saved view
+	r.Use(middleware.SavedViews(savedViewService, map[string]string{
+		"/orders":         string(adminDomain.SavedViewListOrders),
+		"/admin/products": string(adminDomain.SavedViewListProducts),
+		"/customers":      string(adminDomain.SavedViewListCustomers),
+	}, adminAuth))
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -295,26 +1382,113 @@ func main() {
 	})
 
 	// Register routes (protected with auth middleware for production)
-	// For now, routes are open. In production, add: r.Use(middleware.Auth(jwtSecret))
+	// For now, routes are open. In production, add: r.Use(adminAuth)
+
+	// Admin login and session routes (session routes are always protected)
+	adminSessionHandler.RegisterRoutes(r)
+	adminAuditLogHandler.RegisterRoutes(r)
+	adminRequestLogHandler.RegisterRoutes(r)
+	adminRoleHandler.RegisterRoutes(r)
+	adminFeatureFlagHandler.RegisterRoutes(r)
+	adminSiteSettingHandler.RegisterRoutes(r)
+	adminRateLimitHandler.RegisterRoutes(r)
+	adminSavedViewHandler.RegisterRoutes(r)
+	adminReportSubscriptionHandler.RegisterRoutes(r)
+	adminTaskHandler.RegisterRoutes(r)
+	adminExportHandler.RegisterRoutes(r)
+	adminDiagnosticsHandler.RegisterRoutes(r)
+	adminCacheHandler.RegisterRoutes(r)
 
 	// Catalog routes
 	adminProductHandler.RegisterRoutes(r)
+	adminProductApprovalHandler.RegisterRoutes(r)
 	adminCategoryHandler.RegisterRoutes(r)
+	adminURLKeyHandler.RegisterRoutes(r)
 	adminSKUHandler.RegisterRoutes(r)
+	adminShippingRestrictionHandler.RegisterRoutes(r)
+	adminCatalogSnapshotHandler.RegisterRoutes(r)
+	adminCategoryMerchandisingHandler.RegisterRoutes(r)
+	adminProductBadgeHandler.RegisterRoutes(r)
+	adminDataQualityHandler.RegisterRoutes(r)
+	adminContentPublishHandler.RegisterRoutes(r)
+	adminPriceWatchHandler.RegisterRoutes(r)
+	adminSKUAvailabilitySyncHandler.RegisterRoutes(r)
+	adminSaleEventHandler.RegisterRoutes(r)
+
+	// Search routes
+	adminSearchConfigHandler.RegisterRoutes(r)
 
 	// Customer routes
 	adminCustomerHandler.RegisterRoutes(r)
+	adminCustomerExportHandler.RegisterRoutes(r)
+	adminCustomerTagHandler.RegisterRoutes(r)
+	adminCustomerMergeHandler.RegisterRoutes(r)
+	adminCustomerImportHandler.RegisterRoutes(r)
+
+	// Offer routes
+	adminOfferReportHandler.RegisterRoutes(r)
+	adminOfferConditionHandler.RegisterRoutes(r)
+	adminOfferScheduleHandler.RegisterRoutes(r)
+	adminOfferCodeBatchHandler.RegisterRoutes(r)
 
 	// Order routes
 	adminOrderHandler.RegisterRoutes(r)
+	adminOrderExportHandler.RegisterRoutes(r)
+	adminDeallocationHandler.RegisterRoutes(r)
+	adminGiftOptionHandler.RegisterRoutes(r)
+	adminLandedCostHandler.RegisterRoutes(r)
+	adminCustomsHandler.RegisterRoutes(r)
+	adminOrderDocumentHandler.RegisterRoutes(r)
+	adminOrderAttachmentHandler.RegisterRoutes(r)
+	adminAssistedOrderHandler.RegisterRoutes(r)
+	adminPaymentLinkHandler.RegisterRoutes(r)
+	adminOfflinePaymentHandler.RegisterRoutes(r)
+	adminSplitPaymentHandler.RegisterRoutes(r)
+	adminOrderHoldHandler.RegisterRoutes(r)
+	adminOrderEditHandler.RegisterRoutes(r)
+	adminOrderArchiveHandler.RegisterRoutes(r)
+	adminOrderTagHandler.RegisterRoutes(r)
+	adminChannelOrderHandler.RegisterRoutes(r)
+	integrationChannelOrderHandler.RegisterRoutes(r)
+
+	// Inventory routes
+	adminInventoryHandler.RegisterRoutes(r)
+	adminInventoryImportHandler.RegisterRoutes(r)
+	adminFlashAllocationHandler.RegisterRoutes(r)
+	adminBinLocationHandler.RegisterRoutes(r)
+	adminReturnHandler.RegisterRoutes(r)
+	integrationChannelReservationHandler.RegisterRoutes(r)
+
+	// Analytics routes
+	adminAnalyticsHandler.RegisterRoutes(r)
+	adminChannelReportHandler.RegisterRoutes(r)
+	adminTaxReportHandler.RegisterRoutes(r)
+	if adminWarehouseExportHandler != nil {
+		adminWarehouseExportHandler.RegisterRoutes(r)
+	}
 
 	// Payment routes
 	adminPaymentHandler.RegisterRoutes(r)
 
 	// Fulfillment routes
 	adminShipmentHandler.RegisterRoutes(r)
+	adminFulfillmentSLAHandler.RegisterRoutes(r)
+	adminFulfillmentOptionHandler.RegisterRoutes(r)
+
+	// Warranty routes
+	adminWarrantyHandler.RegisterRoutes(r)
+
+	// Site routes
+	adminSiteDomainHandler.RegisterRoutes(r)
+
+	// Business calendar routes
+	adminCalendarHandler.RegisterRoutes(r)
+
+	// Inbound webhook routes
+	webhookHandler.RegisterRoutes(r)
+	adminWebhookHandler.RegisterRoutes(r)
 
-	log.WithField("contexts", "catalog, customer, order, payment, fulfillment").Info("All bounded contexts initialized")
+	log.WithField("contexts", "catalog, search, customer, offer, order, analytics, payment, fulfillment, warranty, site, calendar, webhook").Info("All bounded contexts initialized")
 
 	// Start HTTP server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port) // Use host from config
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/qhato/ecommerce/config"
+
+	// Anonymization
+	anonymizeApp "github.com/qhato/ecommerce/internal/anonymize/application"
+	anonymizePersistence "github.com/qhato/ecommerce/internal/anonymize/infrastructure/persistence"
+
+	"github.com/qhato/ecommerce/pkg/auth"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// anonymize rewrites customer PII, order emails and payment references in
+// place in a database restored from a production snapshot. Replacements are
+// derived from a secret key, so a value gets the same replacement in every
+// table, and in every refresh anonymized with the same key.
+func main() {
+	configPath := flag.String("config", "config.yaml", "path to the configuration file")
+	confirm := flag.String("confirm", "", "name of the configured database, confirming it may be rewritten")
+	tables := flag.String("tables", "", "comma separated tables to anonymize (default: all)")
+	batchSize := flag.Int("batch-size", 1000, "number of rows rewritten per transaction")
+	restart := flag.Bool("restart", false, "anonymize the tables again instead of resuming; only after restoring a new snapshot")
+	password := flag.String("password", "", "password given to every account (default: none, so no account can sign in)")
+	flag.Parse()
+
+	// The key is read from the environment so it stays out of shell history
+	key := os.Getenv("ECOMMERCE_ANONYMIZE_KEY")
+
+	// Load configuration
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.IsProduction() {
+		fmt.Println("Refusing to anonymize a production environment")
+		os.Exit(1)
+	}
+	if *confirm == "" || *confirm != cfg.Database.Database {
+		fmt.Printf("Pass -confirm=%s to rewrite the personal data in database %q on %s\n", cfg.Database.Database, cfg.Database.Database, cfg.Database.Host)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	if err := logger.Initialize(cfg.App.Environment, cfg.App.LogLevel); err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	log := logger.Get()
+
+	var passwordHash string
+	if *password != "" {
+		passwordHash, err = auth.NewPasswordService(bcrypt.DefaultCost).HashPassword(*password)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to hash password")
+		}
+	}
+	faker, err := anonymizeApp.NewFaker([]byte(key), passwordHash)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid ECOMMERCE_ANONYMIZE_KEY")
+	}
+
+	// Stop cleanly between batches on interrupt; the run can be resumed later
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.New(ctx, toDatabaseConfig(cfg.Database))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to connect to database")
+	}
+	defer db.Close()
+
+	service := anonymizeApp.NewAnonymizeService(anonymizePersistence.NewPostgresAnonymizeRepository(db), faker, log)
+
+	cmd := &anonymizeApp.RunAnonymizationCommand{
+		BatchSize: *batchSize,
+		Restart:   *restart,
+	}
+	for _, table := range strings.Split(*tables, ",") {
+		if table = strings.TrimSpace(table); table != "" {
+			cmd.Tables = append(cmd.Tables, table)
+		}
+	}
+
+	reports, err := service.Run(ctx, cmd)
+	printJSON(reports)
+	if err != nil {
+		log.WithError(err).Error("Anonymization failed")
+		os.Exit(1)
+	}
+	for _, report := range reports {
+		if report.Status == anonymizeApp.TableInterrupted {
+			os.Exit(2)
+		}
+	}
+}
+
+func toDatabaseConfig(cfg config.DatabaseConfig) database.Config {
+	return database.Config{
+		Host:           cfg.Host,
+		Port:           cfg.Port,
+		User:           cfg.User,
+		Password:       cfg.Password,
+		Database:       cfg.Database,
+		SSLMode:        cfg.SSLMode,
+		MaxConnections: cfg.MaxConnections,
+		MaxIdleConns:   cfg.MaxIdleConns,
+		MaxLifetime:    cfg.MaxLifetime,
+		MaxIdleTime:    cfg.MaxIdleTime,
+
+		SlowQueryThreshold: cfg.SlowQueryThreshold,
+	}
+}
+
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
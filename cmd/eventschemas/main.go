@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/schemas"
+
+	// Producers register their event types, consumers the fields they read
+	_ "github.com/qhato/ecommerce/internal/admin/domain"
+	_ "github.com/qhato/ecommerce/internal/catalog/application"
+	_ "github.com/qhato/ecommerce/internal/catalog/domain"
+	_ "github.com/qhato/ecommerce/internal/customer/domain"
+	_ "github.com/qhato/ecommerce/internal/fulfillment/domain"
+	_ "github.com/qhato/ecommerce/internal/inventory/domain"
+	_ "github.com/qhato/ecommerce/internal/offer/domain"
+	_ "github.com/qhato/ecommerce/internal/order/application"
+	_ "github.com/qhato/ecommerce/internal/search/domain"
+	_ "github.com/qhato/ecommerce/internal/tax/domain"
+)
+
+// eventschemas is the compatibility suite of the event schemas kept under
+// schemas/events. It fails when a schema version breaks the previous one, when
+// an event type no longer matches its latest schema, or when a consumer reads a
+// field the schema does not have. With -generate it writes a new version for
+// each event type whose Go type changed, refusing changes that would break
+// consumers: fields may be added, not removed, retyped or made optional.
+func main() {
+	generate := flag.Bool("generate", false, "write a new schema version for each event type that changed")
+	dir := flag.String("dir", "schemas/events", "directory of the schema files, used by -generate")
+	flag.Parse()
+
+	registry, err := event.LoadSchemas(schemas.Events)
+	if err != nil {
+		fmt.Printf("Failed to load event schemas: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *generate {
+		written, err := generateSchemas(registry, *dir)
+		for _, name := range written {
+			fmt.Printf("wrote %s\n", filepath.Join(*dir, name))
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(written) > 0 {
+			// The embedded schemas are those of the build; run again to check the new files
+			return
+		}
+	}
+
+	problems := registry.Check()
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("%d event schema problems\n", len(problems))
+		os.Exit(1)
+	}
+	fmt.Printf("%d event schemas and %d consumer contracts are compatible\n", len(registry.Types()), len(event.RegisteredContracts()))
+}
+
+// generateSchemas writes the next version of the schema of each registered
+// event type whose Go type no longer matches its latest schema
+func generateSchemas(registry *event.SchemaRegistry, dir string) ([]string, error) {
+	registered := event.RegisteredTypes()
+	eventTypes := make([]string, 0, len(registered))
+	for eventType := range registered {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+
+	var written []string
+	for _, eventType := range eventTypes {
+		next := event.SchemaOf(eventType, registered[eventType])
+		next.Version = 1
+		if latest, ok := registry.Latest(eventType); ok {
+			if equalFields(latest.Fields, next.Fields) {
+				continue
+			}
+			if breaks := event.CheckCompatible(latest, next); len(breaks) > 0 {
+				return written, fmt.Errorf("%s: the change %v breaks consumers of version %d; publish a new event type instead", eventType, breaks, latest.Version)
+			}
+			next.Version = latest.Version + 1
+		}
+
+		data, err := json.MarshalIndent(next, "", "  ")
+		if err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, next.Filename()), append(data, '\n'), 0o644); err != nil {
+			return written, fmt.Errorf("failed to write schema of %s: %w", eventType, err)
+		}
+		written = append(written, next.Filename())
+	}
+	return written, nil
+}
+
+func equalFields(a, b map[string]event.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, field := range a {
+		if b[name] != field {
+			return false
+		}
+	}
+	return true
+}
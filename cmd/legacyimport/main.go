@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/qhato/ecommerce/config"
+
+	// Catalog
+	catalogPersistence "github.com/qhato/ecommerce/internal/catalog/infrastructure/persistence"
+
+	// Customer
+	customerPersistence "github.com/qhato/ecommerce/internal/customer/infrastructure/persistence"
+
+	// Offer
+	offerPersistence "github.com/qhato/ecommerce/internal/offer/infrastructure/persistence"
+
+	// Order
+	orderPersistence "github.com/qhato/ecommerce/internal/order/infrastructure/persistence"
+
+	// Legacy import
+	legacyApp "github.com/qhato/ecommerce/internal/legacyimport/application"
+	legacyDomain "github.com/qhato/ecommerce/internal/legacyimport/domain"
+	legacyPersistence "github.com/qhato/ecommerce/internal/legacyimport/infrastructure/persistence"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "path to the configuration file")
+	entities := flag.String("entities", "", "comma separated entities to import (default: all, in dependency order)")
+	batchSize := flag.Int("batch-size", 500, "number of legacy rows per batch")
+	maxBatches := flag.Int("max-batches", 0, "stop after this many batches per entity (0 = no limit)")
+	restart := flag.Bool("restart", false, "start a new run instead of resuming the latest unfinished one")
+	verify := flag.Bool("verify", false, "only verify imported data against the legacy database")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.LegacyDatabase.Host == "" {
+		fmt.Println("Legacy database host is not configured (ECOMMERCE_LEGACYDATABASE_HOST)")
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	if err := logger.Initialize(cfg.App.Environment, cfg.App.LogLevel); err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	log := logger.Get()
+
+	entityTypes, err := parseEntities(*entities)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid entities")
+	}
+
+	// Stop cleanly between batches on interrupt; the run can be resumed later
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Initialize target database
+	db, err := database.New(ctx, toDatabaseConfig(cfg.Database))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to connect to database")
+	}
+	defer db.Close()
+
+	// Initialize legacy Broadleaf database
+	legacyDB, err := database.New(ctx, toDatabaseConfig(cfg.LegacyDatabase))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to connect to legacy database")
+	}
+	defer legacyDB.Close()
+	log.Info("Connected to target and legacy databases")
+
+	productRepo := catalogPersistence.NewPostgresProductRepository(db)
+	importers := []legacyApp.EntityImporter{
+		legacyApp.NewCategoryImporter(catalogPersistence.NewPostgresCategoryRepository(db)),
+		legacyApp.NewProductImporter(productRepo),
+		legacyApp.NewSKUImporter(catalogPersistence.NewPostgresSKURepository(db), productRepo),
+		legacyApp.NewCategoryProductXrefImporter(catalogPersistence.NewPostgresCategoryProductXrefRepository(db)),
+		legacyApp.NewCustomerImporter(customerPersistence.NewPostgresCustomerRepository(db)),
+		legacyApp.NewOfferImporter(offerPersistence.NewPostgresOfferRepository(db)),
+		legacyApp.NewOrderImporter(orderPersistence.NewPostgresOrderRepository(db)),
+		legacyApp.NewOrderItemImporter(orderPersistence.NewPostgresOrderItemRepository(db)),
+	}
+
+	importService := legacyApp.NewImportService(
+		legacyPersistence.NewPostgresImportRunRepository(db),
+		legacyPersistence.NewPostgresIDMappingRepository(db),
+		legacyPersistence.NewBroadleafSource(legacyDB),
+		importers,
+		db,
+		log,
+	)
+
+	exitCode := 0
+	for _, entityType := range entityTypes {
+		if *verify {
+			report, err := importService.Verify(ctx, entityType)
+			if err != nil {
+				log.WithError(err).WithField("entity", entityType).Error("Verification failed")
+				exitCode = 1
+				continue
+			}
+			printJSON(report)
+			if !report.Verified {
+				exitCode = 2
+			}
+			continue
+		}
+
+		run, err := importService.Run(ctx, &legacyApp.RunImportCommand{
+			EntityType: entityType,
+			BatchSize:  *batchSize,
+			MaxBatches: *maxBatches,
+			Restart:    *restart,
+		})
+		if err != nil {
+			log.WithError(err).WithField("entity", entityType).Error("Import failed")
+			os.Exit(1)
+		}
+		printJSON(run)
+
+		// Later entities depend on earlier ones, so stop if this one did not finish
+		if run.Status != string(legacyDomain.ImportRunStatusCompleted) {
+			log.WithField("entity", entityType).Warn("Import paused; rerun to resume")
+			break
+		}
+	}
+
+	os.Exit(exitCode)
+}
+
+func parseEntities(value string) ([]legacyDomain.EntityType, error) {
+	if value == "" {
+		return legacyDomain.DefaultEntityOrder, nil
+	}
+
+	known := make(map[legacyDomain.EntityType]bool, len(legacyDomain.DefaultEntityOrder))
+	for _, entityType := range legacyDomain.DefaultEntityOrder {
+		known[entityType] = true
+	}
+
+	entityTypes := make([]legacyDomain.EntityType, 0)
+	for _, name := range strings.Split(value, ",") {
+		entityType := legacyDomain.EntityType(strings.TrimSpace(name))
+		if !known[entityType] {
+			return nil, fmt.Errorf("unknown entity: %s", entityType)
+		}
+		entityTypes = append(entityTypes, entityType)
+	}
+	return entityTypes, nil
+}
+
+func toDatabaseConfig(cfg config.DatabaseConfig) database.Config {
+	return database.Config{
+		Host:           cfg.Host,
+		Port:           cfg.Port,
+		User:           cfg.User,
+		Password:       cfg.Password,
+		Database:       cfg.Database,
+		SSLMode:        cfg.SSLMode,
+		MaxConnections: cfg.MaxConnections,
+		MaxIdleConns:   cfg.MaxIdleConns,
+		MaxLifetime:    cfg.MaxLifetime,
+		MaxIdleTime:    cfg.MaxIdleTime,
+
+		SlowQueryThreshold: cfg.SlowQueryThreshold,
+	}
+}
+
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,15 +12,23 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/qhato/ecommerce/config"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
 
 	// Catalog
 	catalogApp "github.com/qhato/ecommerce/internal/catalog/application"
 	//catalogCommands "github.com/qhato/ecommerce/internal/catalog/application/commands"
 	catalogQueries "github.com/qhato/ecommerce/internal/catalog/application/queries"
+	catalogDomain "github.com/qhato/ecommerce/internal/catalog/domain"
 	catalogPersistence "github.com/qhato/ecommerce/internal/catalog/infrastructure/persistence"
 	catalogHttp "github.com/qhato/ecommerce/internal/catalog/ports/http"
 
+	// Search
+	searchApp "github.com/qhato/ecommerce/internal/search/application"
+	searchPersistence "github.com/qhato/ecommerce/internal/search/infrastructure/persistence"
+
 	// Customer
+	customerApp "github.com/qhato/ecommerce/internal/customer/application"
 	customerCommands "github.com/qhato/ecommerce/internal/customer/application/commands"
 	customerQueries "github.com/qhato/ecommerce/internal/customer/application/queries"
 	customerPersistence "github.com/qhato/ecommerce/internal/customer/infrastructure/persistence"
@@ -28,39 +37,76 @@ import (
 	// Order
 	orderApp "github.com/qhato/ecommerce/internal/order/application"
 	//orderCommands "github.com/qhato/ecommerce/internal/order/application/commands"
+	orderDomain "github.com/qhato/ecommerce/internal/order/domain"
 	orderQueries "github.com/qhato/ecommerce/internal/order/application/queries"
 	orderPersistence "github.com/qhato/ecommerce/internal/order/infrastructure/persistence"
 	orderHttp "github.com/qhato/ecommerce/internal/order/ports/http"
 
 	// Offer
 	offerApp "github.com/qhato/ecommerce/internal/offer/application"
+	offerDomain "github.com/qhato/ecommerce/internal/offer/domain"
 	offerPersistence "github.com/qhato/ecommerce/internal/offer/infrastructure/persistence"
+	offerHttp "github.com/qhato/ecommerce/internal/offer/ports/http"
 
 	// Inventory
 	inventoryApp "github.com/qhato/ecommerce/internal/inventory/application"
+	inventoryDomain "github.com/qhato/ecommerce/internal/inventory/domain"
 	inventoryPersistence "github.com/qhato/ecommerce/internal/inventory/infrastructure/persistence"
+	inventoryRedis "github.com/qhato/ecommerce/internal/inventory/infrastructure/redis"
+	inventoryHttp "github.com/qhato/ecommerce/internal/inventory/ports/http"
 
 	// Tax
 	taxApp "github.com/qhato/ecommerce/internal/tax/application"
+	taxDomain "github.com/qhato/ecommerce/internal/tax/domain"
 	taxPersistence "github.com/qhato/ecommerce/internal/tax/infrastructure/persistence"
 
 	// Payment
+	paymentDomain "github.com/qhato/ecommerce/internal/payment/domain"
 	//paymentCommands "github.com/qhato/ecommerce/internal/payment/application/commands"
 	//paymentQueries "github.com/qhato/ecommerce/internal/payment/application/queries"
 	//paymentPersistence "github.com/qhato/ecommerce/internal/payment/infrastructure/persistence"
 	//paymentHttp "github.com/qhato/ecommerce/internal/payment/ports/http"
 
 	// Fulfillment
+	fulfillmentApp "github.com/qhato/ecommerce/internal/fulfillment/application"
 	//fulfillmentCommands "github.com/qhato/ecommerce/internal/fulfillment/application/commands"
 	fulfillmentPersistence "github.com/qhato/ecommerce/internal/fulfillment/infrastructure/persistence"
 	fulfillmentHttp "github.com/qhato/ecommerce/internal/fulfillment/ports/http"
 
+	// Site
+	siteApp "github.com/qhato/ecommerce/internal/site/application"
+	sitePersistence "github.com/qhato/ecommerce/internal/site/infrastructure/persistence"
+
+	// Warranty
+	warrantyApp "github.com/qhato/ecommerce/internal/warranty/application"
+	warrantyPersistence "github.com/qhato/ecommerce/internal/warranty/infrastructure/persistence"
+	warrantyHttp "github.com/qhato/ecommerce/internal/warranty/ports/http"
+
+	// Composed pages
+	bffApp "github.com/qhato/ecommerce/internal/bff/application"
+	bffHttp "github.com/qhato/ecommerce/internal/bff/ports/http"
+
+	// Admin
+	adminPersistence "github.com/qhato/ecommerce/internal/admin/infrastructure/persistence"
+
+	"github.com/qhato/ecommerce/pkg/auth"
 	"github.com/qhato/ecommerce/pkg/cache"
 	"github.com/qhato/ecommerce/pkg/database"
 	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/featureflag"
+	"github.com/qhato/ecommerce/pkg/geoip"
+	"github.com/qhato/ecommerce/pkg/httpclient"
+	"github.com/qhato/ecommerce/pkg/loadshed"
 	"github.com/qhato/ecommerce/pkg/logger"
 	"github.com/qhato/ecommerce/pkg/middleware"
+	"github.com/qhato/ecommerce/pkg/money"
+	"github.com/qhato/ecommerce/pkg/objectstore"
+	"github.com/qhato/ecommerce/pkg/ratelimit"
+	"github.com/qhato/ecommerce/pkg/waitingroom"
+	"github.com/qhato/ecommerce/pkg/server"
+	"github.com/qhato/ecommerce/pkg/settings"
 	"github.com/qhato/ecommerce/pkg/validator"
+	"github.com/qhato/ecommerce/schemas"
 )
 
 func main() {
@@ -71,6 +117,9 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Currency rounding and display rules used by DTOs and templates
+	money.Configure(cfg.Money.CurrencyRules(), cfg.Money.LocaleFormats(), cfg.Storefront.DefaultLocale)
+
 	// Initialize logger
 	err = logger.Initialize(cfg.App.Environment, cfg.App.LogLevel)
 	if err != nil {
@@ -92,6 +141,10 @@ func main() {
 		MaxIdleConns: cfg.Database.MaxIdleConns,
 		MaxLifetime: cfg.Database.MaxLifetime,
 		MaxIdleTime: cfg.Database.MaxIdleTime,
+		QueryTimeout: cfg.Database.QueryTimeout,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+		ExplainQueries: cfg.Database.ExplainQueries && cfg.IsDevelopment(),
+		ExplainTables: cfg.Database.ExplainTables,
 	})
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to database")
@@ -100,28 +153,103 @@ func main() {
 	log.Info("Connected to database")
 
 	// Initialize cache (important for storefront performance)
+	// Redis sits behind a circuit breaker; while it is down the cache falls back to memory
+	resilienceCtx, stopResilience := context.WithCancel(context.Background())
+	defer stopResilience()
 	var cacheStore cache.Cache
+	var flashTokens inventoryDomain.FlashTokenStore // Flash-sale counters shared with the admin
+	var rateLimiter ratelimit.Limiter = ratelimit.NewMemoryLimiter()
+	var waitingRoomStore waitingroom.Store = waitingroom.NewMemoryStore()
 	if cfg.Redis.Host != "" { // Check Redis host for cache type
-		cacheStore, err = cache.NewRedisCache(cache.RedisConfig{ // Convert config.RedisConfig to cache.RedisConfig
+		redisCache := cache.NewLazyRedisCache(cache.RedisConfig{ // Convert config.RedisConfig to cache.RedisConfig
 			Host: cfg.Redis.Host,
 			Port: cfg.Redis.Port,
 			Password: cfg.Redis.Password,
 			Database: cfg.Redis.Database,
 			PoolSize: cfg.Redis.PoolSize,
-			Prefix: "admin_api", // Assuming a prefix for admin cache
+			Prefix: cfg.CacheKeyPrefix(), // Keeps environments sharing a Redis server apart
 		})
-		if err != nil {
-			log.WithError(err).Fatal("Failed to connect to Redis")
+		resilientCache := cache.NewResilientCache(redisCache, cache.ResilienceConfig{
+			Name:             "redis",
+			FailureThreshold: cfg.Redis.BreakerThreshold,
+			Cooldown:         cfg.Redis.BreakerCooldown,
+			ProbeInterval:    cfg.Redis.ProbeInterval,
+			FallbackTTL:      cfg.Redis.TTL,
+		}, log)
+		resilientCache.StartRecoveryProbe(resilienceCtx)
+		cacheStore = resilientCache
+		flashTokens = inventoryRedis.NewFlashTokenStore(redisCache.GetClient(), "ecommerce")
+		rateLimiter = ratelimit.NewResilientLimiter(ratelimit.NewRedisLimiter(redisCache.GetClient(), "storefront"), cfg.Redis.BreakerThreshold, cfg.Redis.BreakerCooldown, log)
+		waitingRoomStore = waitingroom.NewRedisStore(redisCache.GetClient(), "storefront")
+		if err := redisCache.Health(context.Background()); err != nil {
+			log.WithError(err).Warn("Redis unavailable, serving from in-memory fallback until it recovers")
+		} else {
+			log.Info("Connected to Redis cache")
 		}
-		log.Info("Connected to Redis cache")
 	} else {
 		cacheStore = cache.NewMemoryCache(cfg.Redis.TTL, cfg.Redis.TTL/2) // Provide arguments
 		log.Info("Using in-memory cache")
 	}
 
-	// Initialize event bus (for customer registration, etc.)
-	eventBus := event.NewMemoryBus()
-	log.Info("Event bus initialized")
+	// Entries both binaries read live in the shared namespace so either one's
+	// invalidations reach the other; the rest stay in this binary's namespace
+	storefrontCache := cache.WithNamespace(cacheStore, cache.NamespaceStorefront)
+	cacheStore = cache.WithNamespace(cacheStore, cache.NamespaceShared)
+
+	// Initialize event bus (for customer registration, etc.). With the redis driver, events are shared
+	// between instances through Redis Streams and the instances of this service
+	// consume them as one consumer group.
+	var eventBus event.Bus = event.NewMemoryBus()
+	if cfg.EventBus.Driver == "redis" {
+		group := cfg.EventBus.ConsumerGroup
+		if group == "" {
+			group = "storefront"
+		}
+		eventBus = event.NewRedisStreamBus(event.RedisStreamConfig{
+			Host:            cfg.Redis.Host,
+			Port:            cfg.Redis.Port,
+			Password:        cfg.Redis.Password,
+			Database:        cfg.Redis.Database,
+			PoolSize:        cfg.Redis.PoolSize,
+			StreamPrefix:    cfg.EventBus.StreamPrefix,
+			Group:           group,
+			MaxLen:          cfg.EventBus.MaxLen,
+			BlockTimeout:    cfg.EventBus.BlockTimeout,
+			ClaimIdle:       cfg.EventBus.ClaimIdle,
+			MetricsInterval: cfg.EventBus.MetricsInterval,
+		}, log)
+	}
+	// Published events are checked against the schemas in schemas/events, which
+	// consumers in other contexts rely on
+	if schemaMode := event.SchemaMode(cfg.EventBus.SchemaMode); schemaMode != event.SchemaModeOff {
+		eventSchemas, err := event.LoadSchemas(schemas.Events)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load event schemas")
+		}
+		problems := eventSchemas.Check()
+		for _, problem := range problems {
+			log.WithField("problem", problem).Warn("Event schema check failed")
+		}
+		if len(problems) > 0 && schemaMode == event.SchemaModeEnforce {
+			log.Fatal("Event schemas do not match the event types or their consumers; run make check-events")
+		}
+		eventBus = event.WithSchemas(eventBus, eventSchemas, schemaMode, log)
+	}
+	defer eventBus.Close()
+	log.WithField("driver", cfg.EventBus.Driver).Info("Event bus initialized")
+
+	// Hot checkout queries (active offers, offer codes, tax rates) are cached and
+	// invalidated when their data changes
+	queryCache := cache.NewQueryCache(cacheStore, cache.QueryCacheConfig{
+		TTL:          cfg.QueryCache.TTL,
+		RefreshAhead: cfg.QueryCache.RefreshAhead,
+	}, log)
+	if err := queryCache.InvalidateOn(eventBus, offerApp.OfferCacheNamespace, offerDomain.EventOfferChanged); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe offer cache invalidation")
+	}
+	if err := queryCache.InvalidateOn(eventBus, taxApp.TaxCacheNamespace, taxDomain.EventTaxDetailChanged); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe tax cache invalidation")
+	}
 
 	// Initialize validator
 	val := validator.New()
@@ -152,22 +280,127 @@ func main() {
 	categoryQueryHandler := catalogQueries.NewCategoryQueryHandler(categoryRepo, cacheStore, log)
 	skuQueryHandler := catalogQueries.NewSKUQueryHandler(skuRepo, cacheStore, log)
 
+	// Apply admin-managed synonyms, stopwords, boosts and pinned products to searches
+	searchConfigService := searchApp.NewSearchConfigService(searchPersistence.NewPostgresSearchConfigRepository(db), cacheStore, eventBus, log)
+	productQueryHandler.SetSearchAnalyzer(searchConfigService)
+
+	// Listing policy for out-of-stock products (availability read model is refreshed by the admin service)
+	productQueryHandler.SetOutOfStockPolicy(catalogDomain.OutOfStockPolicy(cfg.Catalog.OutOfStockPolicy))
+
+	// Admin-managed pins and sort rules order category listings
+	categoryMerchandisingService := catalogApp.NewCategoryMerchandisingService(catalogPersistence.NewPostgresCategoryMerchandisingRepository(db), categoryRepo)
+	productQueryHandler.SetMerchandiser(categoryMerchandisingService)
+
+	// New, Sale, Bestseller and campaign badges on listings and product pages
+	productBadgeService := catalogApp.NewProductBadgeService(catalogPersistence.NewPostgresProductBadgeRepository(db), productRepo, catalogDomain.BadgeRules{
+		NewDays:            cfg.Catalog.BadgeNewDays,
+		BestsellerDays:     cfg.Catalog.BestsellerDays,
+		BestsellerMinUnits: cfg.Catalog.BestsellerMinUnits,
+	})
+	productQueryHandler.SetBadgeProvider(productBadgeService)
+
+	// Preload the navigation tree, configured entries and best sellers so the first visitors hit a warm cache
+	categoryQueryHandler.SetNavigationDepth(cfg.Catalog.NavigationDepth)
+	catalogPopularityRepo := catalogPersistence.NewPostgresCatalogPopularityRepository(db)
+	catalogCacheWarmer := catalogQueries.NewCacheWarmer(productQueryHandler, categoryQueryHandler, catalogPopularityRepo, cacheStore, catalogQueries.CacheWarmConfig{
+		ProductIDs:     cfg.Catalog.CacheWarmProducts,
+		CategoryIDs:    cfg.Catalog.CacheWarmCategories,
+		TopProducts:    cfg.Catalog.CacheWarmTopProducts,
+		TopCategories:  cfg.Catalog.CacheWarmTopCategories,
+		PopularityDays: cfg.Catalog.CacheWarmPopularityDays,
+		Navigation:     true,
+	}, log)
+	if cfg.Catalog.CacheWarmOnStartup {
+		go catalogCacheWarmer.WarmAll(context.Background())
+	}
+
+	// Shipping restrictions are exposed so the storefront can explain why items cannot ship
+	shippingRestrictionService := catalogApp.NewShippingRestrictionService(catalogPersistence.NewPostgresShippingRestrictionRepository(db))
+
+	// Price-drop alerts are set here and sent by the admin server, which applies price changes
+	priceWatchService := catalogApp.NewPriceWatchService(
+		skuRepo,
+		catalogPersistence.NewPostgresPriceWatchRepository(db),
+		catalogPersistence.NewPostgresScheduledPriceChangeRepository(db),
+		eventBus,
+		nil,
+		catalogApp.PriceWatchConfig{Attribution: cfg.Catalog.PriceWatchAttribution},
+		log,
+	)
+
+	// Sale events are listed with countdowns and shown on products and SKUs; the admin server starts and ends them
+	saleEventService := catalogApp.NewSaleEventService(
+		catalogPersistence.NewPostgresSaleEventRepository(db),
+		skuRepo,
+		eventBus,
+		nil,
+		catalogApp.SaleEventConfig{Upcoming: cfg.Catalog.SaleEventUpcoming},
+		log,
+	)
+	productQueryHandler.SetDealProvider(saleEventService)
+	skuQueryHandler.SetDealProvider(saleEventService)
+
 	// Catalog HTTP handlers
-	storefrontCatalogHandler := catalogHttp.NewStorefrontCatalogHandler(productQueryHandler, categoryQueryHandler, skuQueryHandler, log)
+	storefrontCatalogHandler := catalogHttp.NewStorefrontCatalogHandler(productQueryHandler, categoryQueryHandler, skuQueryHandler, shippingRestrictionService, log)
+	storefrontCatalogHandler.SetSaleEvents(saleEventService)
+	storefrontCatalogHandler.SetURLKeys(catalogApp.NewURLKeyService(catalogPersistence.NewPostgresURLKeyRepository(db), productRepo, categoryRepo))
+	storefrontPriceWatchHandler := catalogHttp.NewStorefrontPriceWatchHandler(priceWatchService, log)
 
 	// ========== CUSTOMER BOUNDED CONTEXT ==========
 
 	// Customer repositories
 	customerRepo := customerPersistence.NewPostgresCustomerRepository(db)
+	customerNoteRepo := customerPersistence.NewPostgresCustomerNoteRepository(db)
 
 	// Customer command handlers (for registration)
-	customerCommandHandler := customerCommands.NewCustomerCommandHandler(customerRepo, eventBus, val, log)
+	customerCommandHandler := customerCommands.NewCustomerCommandHandler(customerRepo, customerNoteRepo, eventBus, val, log)
 
 	// Customer query handlers
-	customerQueryHandler := customerQueries.NewCustomerQueryHandler(customerRepo, cacheStore, log)
+	customerQueryHandler := customerQueries.NewCustomerQueryHandler(customerRepo, customerNoteRepo, cacheStore, log)
+	customerQueryHandler.SetCacheTTLs(cfg.Storefront.CustomerCacheTTL, cfg.Storefront.CustomerMissTTL)
+	if err := customerQueryHandler.Subscribe(eventBus); err != nil {
+		log.WithError(err).Fatal("Failed to subscribe customer cache invalidation")
+	}
+
+	// Locale and currency each visitor selected; read by the storefront context middleware
+	visitorPreferenceService := customerApp.NewVisitorPreferenceService(
+		customerPersistence.NewPostgresVisitorPreferenceRepository(db),
+		cfg.Storefront.Localizations(),
+		storefrontCache,
+		cfg.Storefront.PreferenceCacheTTL,
+		log,
+	)
+
+	// Addresses are normalized internally and, when configured, verified by an external provider
+	var addressProvider customerApp.AddressProvider
+	if cfg.Address.ProviderURL != "" {
+		addressProvider = customerApp.NewHTTPAddressProvider(httpclient.New(cfg.HTTPClient.Client("address", cfg.Address.ProviderURL), log), cfg.Address.ProviderPath)
+	}
+	addressService := customerApp.NewAddressService(customerPersistence.NewPostgresAddressRepository(db), addressProvider, log)
+
+	// Storefront inbox; messages are delivered by the admin server's notification pipeline
+	customerNotificationService := customerApp.NewCustomerNotificationService(customerPersistence.NewPostgresCustomerNotificationRepository(db), log)
 
 	// Customer HTTP handlers
-	storefrontCustomerHandler := customerHttp.NewStorefrontCustomerHandler(customerCommandHandler, customerQueryHandler, val, log)
+	jwtService := auth.NewJWTService(cfg.Auth.JWTSecret, cfg.Auth.JWTExpiration)
+	storefrontCustomerHandler := customerHttp.NewStorefrontCustomerHandler(customerCommandHandler, customerQueryHandler, jwtService, val, log)
+	storefrontPreferenceHandler := customerHttp.NewStorefrontPreferenceHandler(visitorPreferenceService, log)
+	storefrontAddressHandler := customerHttp.NewStorefrontAddressHandler(addressService, log)
+	storefrontNotificationHandler := customerHttp.NewStorefrontNotificationHandler(customerNotificationService, log)
+
+	// ========== SITE SETTINGS ==========
+
+	// Store name, emails, order number prefix and promotion thresholds are changed on the admin
+	siteSettings, err := settings.New(adminPersistence.NewPostgresSiteSettingRepository(db), cfg.SiteSettingDefaults(), log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create site settings")
+	}
+	if err := siteSettings.Refresh(context.Background()); err != nil {
+		log.WithError(err).Warn("Failed to load site settings")
+	}
+	settingsCtx, stopSettings := context.WithCancel(context.Background())
+	defer stopSettings()
+	siteSettings.StartScheduledRefresh(settingsCtx, cfg.Features.SettingsRefreshInterval)
 
 	// ========== OFFER BOUNDED CONTEXT ========== 
 
@@ -179,6 +412,7 @@ func main() {
 	offerPriceDataRepo := offerPersistence.NewPostgresOfferPriceDataRepository(db)
 	qualCritOfferXrefRepo := offerPersistence.NewPostgresQualCritOfferXrefRepository(db)
 	tarCritOfferXrefRepo := offerPersistence.NewPostgresTarCritOfferXrefRepository(db)
+	customerTargetingRepo := offerPersistence.NewPostgresCustomerTargetingRepository(db)
 
 	// Offer application services
 	offerService := offerApp.NewOfferService(
@@ -189,7 +423,20 @@ func main() {
 		offerPriceDataRepo,
 		qualCritOfferXrefRepo,
 		tarCritOfferXrefRepo,
+		customerTargetingRepo,
+		queryCache,
+		nil, // Offers are scheduled from the admin
+		eventBus,
+		log,
+	)
+
+	// Spend-based offers a cart is close to qualifying for, for upsell messaging
+	promotionMessageService := offerApp.NewPromotionMessageService(
+		offerService,
+		siteSettings,
+		log,
 	)
+	storefrontPromotionHandler := offerHttp.NewStorefrontPromotionHandler(promotionMessageService, log)
 
 	// ========== INVENTORY BOUNDED CONTEXT ========== 
 
@@ -197,7 +444,29 @@ func main() {
 	inventoryLevelRepo := inventoryPersistence.NewPostgresInventoryRepository(db)
 
 	// Inventory application services
-	inventoryService := inventoryApp.NewInventoryService(inventoryLevelRepo) // NewInventoryService takes a repo
+	// Stock changes are published for the SKU availability sync the admin runs
+	inventoryService := inventoryApp.NewInventoryService(inventoryLevelRepo, eventBus)
+
+	// Available-to-promise: on hand, less reserved and allocated stock, plus expected inbound receipts
+	atpService := inventoryApp.NewATPService(
+		inventoryLevelRepo,
+		inventoryPersistence.NewPostgresInboundReceiptRepository(db),
+		cfg.Inventory.InboundHorizon,
+		eventBus,
+	)
+	storefrontCatalogHandler.SetAvailableToPromise(atpService)
+
+	// Flash-sale allocation: reservations of SKUs in a flash sale take Redis tokens; the admin reconciles them
+	flashAllocationService := inventoryApp.NewFlashAllocationService(
+		inventoryPersistence.NewPostgresFlashAllocationRepository(db),
+		flashTokens,
+		eventBus,
+		log,
+	)
+	storefrontCatalogHandler.SetAddToCartPath(cfg.Storefront.AddToCartPath)
+
+	// Inventory HTTP handlers
+	storefrontInventoryHandler := inventoryHttp.NewStorefrontInventoryHandler(atpService, log)
 
 	// ========== TAX BOUNDED CONTEXT ========== 
 
@@ -205,7 +474,20 @@ func main() {
 	taxDetailRepo := taxPersistence.NewPostgresTaxDetailRepository(db)
 
 	// Tax application services
-	taxService := taxApp.NewTaxService(taxDetailRepo) // Pass taxDetailRepository
+	// An external tax engine, when configured, calculates tax instead of the configured tax details
+	var taxProvider taxApp.TaxProvider
+	if cfg.Tax.ProviderURL != "" {
+		taxProvider = taxApp.NewHTTPTaxProvider(httpclient.New(cfg.HTTPClient.Client("tax", cfg.Tax.ProviderURL), log), cfg.Tax.ProviderPath)
+	}
+	taxService := taxApp.NewTaxService(taxDetailRepo, queryCache, taxProvider, eventBus, log)
+
+	// Duties and import fees of cross-border orders; an external landed cost service,
+	// when configured, estimates them instead of the duty tables
+	var landedCostProvider taxApp.LandedCostProvider
+	if cfg.Tax.LandedCostURL != "" {
+		landedCostProvider = taxApp.NewHTTPLandedCostProvider(httpclient.New(cfg.HTTPClient.Client("landed-cost", cfg.Tax.LandedCostURL), log), cfg.Tax.LandedCostPath)
+	}
+	landedCostService := taxApp.NewLandedCostService(taxPersistence.NewPostgresLandedCostRepository(db), landedCostProvider, log)
 
 	// ========== ORDER BOUNDED CONTEXT ========== 
 
@@ -216,6 +498,21 @@ func main() {
 	orderItemAdjustmentRepo := orderPersistence.NewPostgresOrderItemAdjustmentRepository(db)
 	orderItemAttributeRepo := orderPersistence.NewPostgresOrderItemAttributeRepository(db)
 	fulfillmentGroupRepo := orderPersistence.NewPostgresFulfillmentGroupRepository(db)
+	orderTaxDetailRepo := orderPersistence.NewPostgresOrderTaxDetailRepository(db)
+	orderNoteRepo := orderPersistence.NewPostgresOrderNoteRepository(db)
+
+	// Inventory released by cancelled orders is queued and retried until it succeeds
+	deallocationService := orderApp.NewInventoryDeallocationService(
+		orderPersistence.NewPostgresInventoryDeallocationRepository(db),
+		orderRepo,
+		inventoryService,
+		orderApp.InventoryDeallocationConfig{
+			MaxAttempts: cfg.Order.DeallocationMaxAttempts,
+			RetryDelay:  cfg.Order.DeallocationRetryDelay,
+		},
+		log,
+	)
+	// Retries are processed by the admin API
 
 	// Order application service
 	orderService := orderApp.NewOrderService(
@@ -227,16 +524,148 @@ func main() {
 		fulfillmentGroupRepo,
 		offerService,
 		inventoryService,
+		atpService,
 		productService,
 		skuService,
 		taxService,
+		orderDomain.TaxMode(cfg.Tax.Mode),
+		orderTaxDetailRepo,
+		nil, // The storefront only adds gift wrap items, cart policy is enforced where orders change
+		deallocationService,
+		flashAllocationService,
+		siteSettings,
+	)
+
+	// Order notes and timeline
+	orderNoteService := orderApp.NewOrderNoteService(orderRepo, orderNoteRepo)
+
+	// Gift wraps and personal messages on cart items
+	giftOptionService := orderApp.NewGiftOptionService(
+		orderRepo,
+		orderItemRepo,
+		orderPersistence.NewPostgresPersonalMessageRepository(db),
+		orderPersistence.NewPostgresGiftWrapOptionRepository(db),
+		orderService,
+		skuService,
+	)
+
+	// Duties and import fees shown on international checkouts, charged with DDP orders
+	orderLandedCostService := orderApp.NewLandedCostService(
+		orderRepo,
+		orderItemRepo,
+		orderPersistence.NewPostgresOrderLandedCostRepository(db),
+		orderPersistence.NewPostgresCartPolicyContextRepository(db),
+		landedCostService,
+		cfg.Tax.ShipFromCountry,
 	)
 
 	// Order query handlers
 	orderQueryHandler := orderQueries.NewOrderQueryHandler(orderService, cacheStore, log)
 
 	// Order HTTP handlers
-	storefrontOrderHandler := orderHttp.NewStorefrontOrderHandler(orderQueryHandler, log)
+	storefrontOrderHandler := orderHttp.NewStorefrontOrderHandler(orderQueryHandler, orderNoteService, promotionMessageService, log)
+	storefrontGiftOptionHandler := orderHttp.NewStorefrontGiftOptionHandler(giftOptionService, log)
+	storefrontLandedCostHandler := orderHttp.NewStorefrontLandedCostHandler(orderLandedCostService, log)
+	storefrontCartHandler := orderHttp.NewStorefrontCartHandler(orderService, log)
+
+	// Payment gateway confirming payment link payments; provider calls go through the resilient HTTP client
+	var paymentGateway paymentDomain.PaymentGateway
+	gatewayConfig := &paymentDomain.GatewayConfig{
+		GatewayName: cfg.Payment.Provider,
+		Enabled:     true,
+		APIKey:      cfg.Payment.PublicKey,
+		APISecret:   cfg.Payment.SecretKey,
+	}
+	switch cfg.Payment.Provider {
+	case "stripe":
+		stripeClient := httpclient.New(cfg.HTTPClient.Client("stripe", paymentDomain.StripeBaseURL), log)
+		paymentGateway = paymentDomain.NewStripeGateway(gatewayConfig, stripeClient)
+	case "paypal":
+		paypalBaseURL := paymentDomain.PayPalSandboxBaseURL
+		if cfg.IsProduction() {
+			paypalBaseURL = paymentDomain.PayPalProductionBaseURL
+		}
+		paypalClient := httpclient.New(cfg.HTTPClient.Client("paypal", paypalBaseURL), log)
+		paymentGateway = paymentDomain.NewPayPalGateway(gatewayConfig, paypalClient)
+	}
+
+	// Payment links are issued and emailed by the admin API and paid here
+	paymentLinkService := orderApp.NewPaymentLinkService(
+		orderService,
+		orderPersistence.NewPostgresPaymentLinkRepository(db),
+		orderPersistence.NewPostgresAssistedOrderRepository(db),
+		paymentGateway,
+		nil,
+		orderApp.PaymentLinkConfig{URL: cfg.Order.PayLinkURL, TTL: cfg.Order.PayLinkTTL, PublicKey: cfg.Payment.PublicKey},
+		log,
+	)
+	storefrontPayLinkHandler := orderHttp.NewStorefrontPayLinkHandler(paymentLinkService, log)
+
+	// Orders placed with an offline payment method are submitted once the admin API records the payment
+	offlinePaymentService := orderApp.NewOfflinePaymentService(
+		orderService,
+		orderPersistence.NewPostgresOfflinePaymentMethodRepository(db),
+		orderPersistence.NewPostgresOfflinePaymentRepository(db),
+		orderPersistence.NewPostgresAssistedOrderRepository(db),
+		orderPersistence.NewPostgresShippingDestinationRepository(db),
+		log,
+	)
+	storefrontOfflinePaymentHandler := orderHttp.NewStorefrontOfflinePaymentHandler(offlinePaymentService, log)
+
+	// Orders paid with several tenders, e.g. two cards; the admin API captures and refunds them.
+	// No gift card issuer is integrated yet, so gift card tenders are not accepted.
+	splitPaymentService := orderApp.NewSplitPaymentService(
+		orderService,
+		orderPersistence.NewPostgresOrderTenderRepository(db),
+		orderApp.TenderGateways{Card: paymentGateway},
+		log,
+	)
+	storefrontSplitPaymentHandler := orderHttp.NewStorefrontSplitPaymentHandler(splitPaymentService, log)
+
+	// Customers see the customer-visible files attached to their orders and may attach their own
+	var attachmentStore objectstore.Store
+	if cfg.Attachments.StorageURL != "" {
+		attachmentClientCfg := cfg.HTTPClient.Client("attachments", "")
+		attachmentClientCfg.Timeout = cfg.Attachments.Timeout
+		attachmentClientCfg.RedactHeaders = []string{"X-Amz-Security-Token"}
+		attachmentStore, err = objectstore.New(cfg.Attachments.Store(), httpclient.New(attachmentClientCfg, log))
+		if err != nil {
+			log.WithError(err).Fatal("Failed to initialize order attachment storage")
+		}
+	}
+	attachmentMaxBytes := int64(cfg.Attachments.MaxSizeKiB) << 10
+	orderAttachmentService := orderApp.NewOrderAttachmentService(
+		orderRepo,
+		orderPersistence.NewPostgresOrderAttachmentRepository(db),
+		attachmentStore,
+		orderApp.OrderAttachmentConfig{
+			MaxSizeBytes:    attachmentMaxBytes,
+			ContentTypes:    cfg.Attachments.ContentTypes,
+			CustomerUploads: cfg.Attachments.CustomerUploads,
+		},
+		log,
+	)
+	storefrontOrderAttachmentHandler := orderHttp.NewStorefrontOrderAttachmentHandler(orderAttachmentService, attachmentMaxBytes, log)
+
+	// ========== COMPOSED PAGES ==========
+
+	// Home, product and cart pages in one call each, fanning out to the contexts above
+	storefrontBFFService := bffApp.NewStorefrontBFFService(bffApp.BFFSources{
+		Products:   productQueryHandler,
+		Categories: categoryQueryHandler,
+		SKUs:       skuQueryHandler,
+		Popularity: catalogPopularityRepo,
+		ATP:        atpService,
+		Offers:     offerService,
+		Promotions: promotionMessageService,
+		Orders:     orderService,
+	}, bffApp.BFFConfig{
+		SectionTimeout: cfg.Storefront.BFFSectionTimeout,
+		BestSellers:    cfg.Storefront.BFFBestSellers,
+		BestSellerDays: cfg.Catalog.BestsellerDays,
+		Promotions:     cfg.Storefront.BFFPromotions,
+	}, log)
+	storefrontBFFHandler := bffHttp.NewStorefrontBFFHandler(storefrontBFFService, log)
 
 	// ========== FULFILLMENT BOUNDED CONTEXT ==========
 
@@ -246,14 +675,103 @@ func main() {
 	// Fulfillment HTTP handlers
 	storefrontShipmentHandler := fulfillmentHttp.NewStorefrontShipmentHandler(shipmentRepo, log)
 
+	// Customers edit their orders for a while after placing them, until they ship
+	orderEditService := orderApp.NewOrderEditService(
+		orderRepo,
+		orderItemRepo,
+		orderPersistence.NewPostgresOrderEditRepository(db),
+		shipmentRepo,
+		orderService,
+		orderApp.OrderEditConfig{Window: cfg.Order.EditWindow, TTL: cfg.Order.EditTTL},
+		log,
+	)
+	storefrontOrderEditHandler := orderHttp.NewStorefrontOrderEditHandler(orderEditService, log)
+
+	// ========== WARRANTY ==========
+
+	// Customers register purchased items from their order history; serialized items by a shipped serial
+	serialNumberService := fulfillmentApp.NewSerialNumberService(fulfillmentPersistence.NewPostgresShipmentSerialRepository(db), shipmentRepo, orderItemRepo)
+	warrantyService := warrantyApp.NewWarrantyService(
+		warrantyPersistence.NewPostgresWarrantyTermsRepository(db),
+		warrantyPersistence.NewPostgresProductRegistrationRepository(db),
+		orderRepo,
+		orderItemRepo,
+		serialNumberService,
+	)
+	storefrontWarrantyHandler := warrantyHttp.NewStorefrontWarrantyHandler(warrantyService, log)
+
+	// ========== SITE DOMAINS ==========
+
+	// Verified custom domains serve their site and, with autocert, get certificates on their first handshake
+	certificateCache := sitePersistence.NewPostgresCertificateCache(db)
+	siteDomainService := siteApp.NewSiteDomainService(sitePersistence.NewPostgresSiteDomainRepository(db), certificateCache, net.DefaultResolver, nil, log)
+	if err := siteDomainService.Refresh(context.Background()); err != nil {
+		log.WithError(err).Warn("Failed to load site domains")
+	}
+	domainCtx, stopDomains := context.WithCancel(context.Background())
+	defer stopDomains()
+	siteDomainService.StartScheduledRefresh(domainCtx, cfg.Storefront.DomainRefreshInterval)
+
+	// ========== FEATURE FLAGS ==========
+
+	// Maintenance mode and kill switches are switched on the admin and picked up at the next refresh
+	featureFlags := featureflag.New(adminPersistence.NewPostgresFeatureFlagRepository(db), log)
+	if err := featureFlags.Refresh(context.Background()); err != nil {
+		log.WithError(err).Warn("Failed to load feature flags")
+	}
+	flagCtx, stopFlags := context.WithCancel(context.Background())
+	defer stopFlags()
+	featureFlags.StartScheduledRefresh(flagCtx, cfg.Features.RefreshInterval)
+
+	// Temporary raises of customer and partner quotas set by the admin
+	rateLimitQuotas := ratelimit.NewQuotas(adminPersistence.NewPostgresRateLimitOverrideRepository(db), log)
+	if err := rateLimitQuotas.Refresh(context.Background()); err != nil {
+		log.WithError(err).Warn("Failed to load rate limit overrides")
+	}
+	rateLimitQuotas.StartScheduledRefresh(flagCtx, cfg.RateLimit.RefreshInterval)
+	maintenanceConfig := middleware.MaintenanceConfig{
+		Flags:       featureFlags,
+		Settings:    siteSettings,
+		StoreName:   cfg.App.Name,
+		RetryAfter:  cfg.Features.MaintenanceRetryAfter,
+		BypassPaths: []string{"/health", "/ready"},
+		BypassToken: cfg.Features.MaintenanceBypassToken,
+	}
+	if cfg.Features.MaintenancePage != "" {
+		page, err := os.ReadFile(cfg.Features.MaintenancePage)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to read maintenance page")
+		}
+		maintenanceConfig.Page = page
+	}
+	killSwitchRoutes := make([]middleware.KillSwitchRoute, 0, len(cfg.Features.KillSwitchRoutes))
+	for _, route := range cfg.Features.KillSwitchRoutes {
+		killSwitchRoutes = append(killSwitchRoutes, middleware.KillSwitchRoute{
+			Flag:       route.Flag,
+			Method:     route.Method,
+			PathPrefix: route.PathPrefix,
+		})
+	}
+
 	// ========== ROUTER SETUP ==========
 
+	// Convert config.RouteTimeoutConfig to middleware.RouteTimeout
+	requestTimeouts := middleware.TimeoutConfig{Default: cfg.Server.RequestTimeout}
+	for _, route := range cfg.Server.RouteTimeouts {
+		requestTimeouts.Routes = append(requestTimeouts.Routes, middleware.RouteTimeout{
+			Method:     route.Method,
+			PathPrefix: route.PathPrefix,
+			Timeout:    route.Timeout,
+		})
+	}
+
 	// Setup router
 	r := chi.NewRouter()
 
 	// Apply global middleware
 	r.Use(middleware.RequestLogger())
 	r.Use(middleware.Recovery())
+	r.Use(middleware.Timeout(requestTimeouts))
 	r.Use(middleware.CORS(middleware.CORSConfig{ // Convert config.CORSConfig to middleware.CORSConfig
 		AllowedOrigins:   cfg.CORS.AllowedOrigins,
 		AllowedMethods:   cfg.CORS.AllowedMethods,
@@ -262,6 +780,108 @@ func main() {
 		AllowCredentials: cfg.CORS.AllowCredentials,
 		MaxAge:           cfg.CORS.MaxAge,
 	}))
+	r.Use(middleware.Maintenance(maintenanceConfig))
+	r.Use(middleware.KillSwitches(featureFlags, killSwitchRoutes))
+
+	// Resolve site, locale, currency and customer once per request
+	r.Use(middleware.OptionalJWTAuth(jwtService))
+	// Crawlers and anonymous browsing are shed first as the server or database
+	// nears saturation, before the storefront context is resolved
+	loadShedConfig := loadshed.Config{
+		Enabled:         cfg.LoadShed.Enabled,
+		MaxInFlight:     cfg.LoadShed.MaxInFlight,
+		LowThreshold:    cfg.LoadShed.LowThreshold,
+		NormalThreshold: cfg.LoadShed.NormalThreshold,
+		RetryAfter:      cfg.LoadShed.RetryAfter,
+		LatencyTargets:  make(map[string]time.Duration, len(cfg.LoadShed.Groups)),
+	}
+	if cfg.LoadShed.DBPoolUsage {
+		loadShedConfig.PoolUsage = db.PoolUsage
+	}
+	loadShedGroups := make([]middleware.LoadSheddingGroup, 0, len(cfg.LoadShed.Groups))
+	for _, group := range cfg.LoadShed.Groups {
+		priority, _ := loadshed.ParsePriority(group.Priority) // Checked by config validation
+		loadShedConfig.LatencyTargets[group.Name] = group.LatencyTarget
+		routes := make([]middleware.LoadSheddingRoute, 0, len(group.Routes))
+		for _, route := range group.Routes {
+			routes = append(routes, middleware.LoadSheddingRoute{Method: route.Method, PathPrefix: route.PathPrefix})
+		}
+		loadShedGroups = append(loadShedGroups, middleware.LoadSheddingGroup{
+			Name:         group.Name,
+			Priority:     priority,
+			AnonymousLow: group.AnonymousLow,
+			Routes:       routes,
+		})
+	}
+	shedder := loadshed.New(loadShedConfig)
+	r.Use(middleware.LoadShedding(shedder, middleware.LoadSheddingConfig{
+		Groups:        loadShedGroups,
+		CrawlerAgents: cfg.LoadShed.CrawlerAgents,
+		BypassPaths:   []string{"/health", "/ready"},
+	}))
+	storefrontContextConfig := middleware.StorefrontContextConfig{
+		DefaultSite:       cfg.Storefront.DefaultSite,
+		Localizations:     cfg.Storefront.Localizations(),
+		SessionCookieName: cfg.Auth.SessionCookieName,
+		Preferences:       visitorPreferenceService,
+		Hosts:             siteDomainService,
+	}
+	// Anonymous visitors are located by IP to default their locale, currency and tax estimates
+	if cfg.Storefront.GeoIPDatabase != "" {
+		geoReader, err := geoip.Open(cfg.Storefront.GeoIPDatabase)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to open GeoIP database")
+		}
+		log.WithField("database_type", geoReader.DatabaseType()).Info("GeoIP enabled")
+		storefrontContextConfig.GeoIP = geoReader
+	}
+	r.Use(middleware.StorefrontContext(storefrontContextConfig))
+	// Schedules entered without an offset are read in the time zone of the site
+	timeZones, err := cfg.Storefront.TimeZones()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load site time zones")
+	}
+	r.Use(middleware.SiteTimeZone(timeZones, cfg.Storefront.DefaultSite))
+	if cfg.RateLimit.Enabled {
+		rateLimitRoutes := make(map[string][]middleware.RateLimitRoute, len(cfg.RateLimit.Buckets))
+		for _, bucket := range cfg.RateLimit.Buckets {
+			for _, route := range bucket.Routes {
+				rateLimitRoutes[bucket.Name] = append(rateLimitRoutes[bucket.Name], middleware.RateLimitRoute{
+					Method: route.Method,
+					Path:   route.Path,
+				})
+			}
+		}
+		r.Use(middleware.RateLimit(rateLimiter, middleware.RateLimitConfig{
+			Policy: cfg.RateLimit.Policy(),
+			Routes: rateLimitRoutes,
+			Keys:   auth.NewChannelKeys(cfg.RateLimit.PartnerKeys()),
+			Quotas: rateLimitQuotas,
+		}))
+	}
+	// During high-demand drops checkout is queued past its concurrency limit, protecting payments and inventory
+	var waitingRoom *waitingroom.Room
+	if cfg.WaitingRoom.Enabled {
+		secret := cfg.WaitingRoom.Secret
+		if secret == "" {
+			secret = cfg.Auth.JWTSecret
+		}
+		waitingRoom = waitingroom.New(waitingRoomStore, waitingroom.Config{
+			MaxActive:      cfg.WaitingRoom.MaxActive,
+			AdmitPerMinute: cfg.WaitingRoom.AdmitPerMinute,
+			SessionTTL:     cfg.WaitingRoom.SessionTTL,
+			TicketTTL:      cfg.WaitingRoom.TicketTTL,
+			Secret:         []byte(secret),
+		})
+		waitingRoomRoutes := make([]middleware.WaitingRoomRoute, 0, len(cfg.WaitingRoom.Routes))
+		for _, route := range cfg.WaitingRoom.Routes {
+			waitingRoomRoutes = append(waitingRoomRoutes, middleware.WaitingRoomRoute{
+				Method: route.Method,
+				Path:   route.Path,
+			})
+		}
+		r.Use(middleware.WaitingRoom(waitingRoom, waitingRoomRoutes))
+	}
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -269,6 +889,57 @@ func main() {
 		w.Write([]byte(`{"status":"healthy"}`))
 	})
 
+	// Readiness fails while draining so load balancers stop routing new requests
+	srv := &http.Server{
+		Handler:      r,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Database.MaxIdleTime, // Use a relevant idle timeout from config
+	}
+	drainer := server.NewDrainer(srv, server.DrainConfig{
+		Delay:    cfg.Server.DrainDelay,
+		Timeout:  cfg.Server.ShutdownTimeout,
+		InFlight: shedder.InFlight,
+	}, log)
+	r.Get("/ready", drainer.ReadinessHandler)
+
+	// HTTPS serves the platform certificate and, with autocert, the certificates of verified site domains chosen by SNI
+	if cfg.Server.TLS.Enabled {
+		tlsOptions := server.TLSOptions{CertFile: cfg.Server.TLS.CertFile, KeyFile: cfg.Server.TLS.KeyFile}
+		if cfg.Server.TLS.AutoCert {
+			tlsOptions.Manager = &autocert.Manager{
+				Prompt:     autocert.AcceptTOS,
+				Cache:      certificateCache,
+				HostPolicy: siteDomainService.HostPolicy,
+				Email:      cfg.Server.TLS.ACMEEmail,
+			}
+			if cfg.Server.TLS.ACMEDirectoryURL != "" {
+				tlsOptions.Manager.Client = &acme.Client{DirectoryURL: cfg.Server.TLS.ACMEDirectoryURL}
+			}
+		}
+		tlsConfig, err := server.NewTLSConfig(tlsOptions)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to configure TLS")
+		}
+		srv.TLSConfig = tlsConfig
+
+		// HTTP-01 challenges, with plain HTTP requests redirected to HTTPS
+		if tlsOptions.Manager != nil && cfg.Server.TLS.HTTPChallengePort > 0 {
+			challengeSrv := &http.Server{
+				Addr:         fmt.Sprintf(":%d", cfg.Server.TLS.HTTPChallengePort),
+				Handler:      tlsOptions.Manager.HTTPHandler(nil),
+				ReadTimeout:  cfg.Server.ReadTimeout,
+				WriteTimeout: cfg.Server.WriteTimeout,
+			}
+			defer challengeSrv.Close()
+			go func() {
+				if err := challengeSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.WithError(err).Error("ACME HTTP challenge server failed")
+				}
+			}()
+		}
+	}
+
 	// API info
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -282,26 +953,51 @@ func main() {
 
 	// Register storefront routes (public, some may require auth in production)
 	storefrontCatalogHandler.RegisterRoutes(r)
+	storefrontPriceWatchHandler.RegisterRoutes(r)
+	storefrontPromotionHandler.RegisterRoutes(r)
 	storefrontCustomerHandler.RegisterRoutes(r)
+	storefrontPreferenceHandler.RegisterRoutes(r)
+	storefrontAddressHandler.RegisterRoutes(r)
+	storefrontNotificationHandler.RegisterRoutes(r)
 	storefrontOrderHandler.RegisterRoutes(r)
+	storefrontGiftOptionHandler.RegisterRoutes(r)
+	storefrontLandedCostHandler.RegisterRoutes(r)
+	storefrontOrderEditHandler.RegisterRoutes(r)
+	storefrontCartHandler.RegisterRoutes(r)
+	storefrontPayLinkHandler.RegisterRoutes(r)
+	storefrontOfflinePaymentHandler.RegisterRoutes(r)
+	storefrontSplitPaymentHandler.RegisterRoutes(r)
+	storefrontOrderAttachmentHandler.RegisterRoutes(r)
+	storefrontInventoryHandler.RegisterRoutes(r)
 	storefrontShipmentHandler.RegisterRoutes(r)
+	storefrontWarrantyHandler.RegisterRoutes(r)
+	storefrontBFFHandler.RegisterRoutes(r)
+	if waitingRoom != nil {
+		waitingRoom.RegisterRoutes(r)
+	}
 
-	log.WithField("contexts", "catalog, customer, order, fulfillment").Info("All storefront contexts initialized")
+	log.WithField("contexts", "catalog, customer, order, fulfillment, warranty").Info("All storefront contexts initialized")
 
-	// Start HTTP server
+	// Start HTTP server on an inherited listener (systemd or handoff fd) or a new socket
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
-	srv := &http.Server{
-		Addr:         addr,
-		Handler:      r,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
-		IdleTimeout:  cfg.Database.MaxIdleTime, // Use a relevant idle timeout from config
+	listener, source, err := server.Listen(context.Background(), server.ListenerConfig{
+		Address:     addr,
+		ReusePort:   cfg.Server.ReusePort,
+		InheritFD:   cfg.Server.InheritFD,
+		SystemdName: cfg.Server.SystemdSocket,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("Failed to open server listener")
 	}
 
 	// Start server in a goroutine
 	go func() {
-		log.WithField("address", addr).Info("Storefront API server listening")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.WithFields(logger.Fields{"address": listener.Addr().String(), "listener": source, "tls": srv.TLSConfig != nil}).Info("Storefront API server listening")
+		serve := srv.Serve
+		if srv.TLSConfig != nil {
+			serve = func(listener net.Listener) error { return srv.ServeTLS(listener, "", "") }
+		}
+		if err := serve(listener); err != nil && err != http.ErrServerClosed {
 			log.WithError(err).Fatal("Server failed to start")
 		}
 	}()
@@ -313,11 +1009,8 @@ func main() {
 
 	log.Info("Shutting down Storefront API server...")
 
-	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := srv.Shutdown(ctx); err != nil {
+	// Drain in-flight requests (e.g. checkouts) before exiting
+	if err := drainer.Drain(); err != nil {
 		log.WithError(err).Error("Server forced to shutdown")
 	}
 
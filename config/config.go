@@ -2,21 +2,78 @@ package config
 
 import (
 	"fmt"
+	"net/http"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/spf13/viper"
+
+	"github.com/qhato/ecommerce/pkg/cache"
+	"github.com/qhato/ecommerce/pkg/calendar"
+	"github.com/qhato/ecommerce/pkg/featureflag"
+	"github.com/qhato/ecommerce/pkg/httpclient"
+	"github.com/qhato/ecommerce/pkg/loadshed"
+	"github.com/qhato/ecommerce/pkg/money"
+	"github.com/qhato/ecommerce/pkg/objectstore"
+	"github.com/qhato/ecommerce/pkg/ratelimit"
+	"github.com/qhato/ecommerce/pkg/requestctx"
+	"github.com/qhato/ecommerce/pkg/schedule"
+	"github.com/qhato/ecommerce/pkg/settings"
 )
 
 // Config holds all application configuration
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Auth     AuthConfig
-	Payment  PaymentConfig
-	Server   ServerConfig
-	CORS     CORSConfig
+	App         AppConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	Auth        AuthConfig
+	Payment     PaymentConfig
+	Server      ServerConfig
+	CORS        CORSConfig
+	Catalog     CatalogConfig
+	Storefront  StorefrontConfig
+	Order       OrderConfig
+	Tax         TaxConfig
+	Address     AddressConfig
+	Inventory   InventoryConfig
+	Money       MoneyConfig
+	HTTPClient  HTTPClientConfig
+	RateLimit   RateLimitConfig
+	WaitingRoom WaitingRoomConfig
+	LoadShed    LoadShedConfig
+	QueryCache  QueryCacheConfig
+	EventBus    EventBusConfig
+	Audit       AuditConfig
+	RequestLog  RequestLogConfig
+	Export      ExportConfig
+	Warehouse   WarehouseConfig
+	Attachments AttachmentConfig
+	Documents   DocumentConfig
+	Features    FeaturesConfig
+
+	// Fulfillment tracks the ship-by SLA of fulfillment groups
+	Fulfillment FulfillmentConfig
+
+	// Calendar is the default working week of business calendars
+	Calendar CalendarConfig
+
+	// Customer schedules the scan for duplicate accounts
+	Customer CustomerConfig
+
+	// Integrations authenticates external sales channels (marketplaces)
+	Integrations IntegrationsConfig
+
+	// Webhooks verifies and processes the webhooks of payment, carrier and other providers
+	Webhooks WebhooksConfig
+
+	// Notification configures outgoing email; unset sends nothing
+	Notification NotificationConfig
+
+	// LegacyDatabase points at an existing Broadleaf database used by the legacy import tool
+	LegacyDatabase DatabaseConfig
 }
 
 // AppConfig holds application-level configuration
@@ -33,15 +90,38 @@ type ServerConfig struct {
 	Port            int
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
-	ShutdownTimeout time.Duration
+	ShutdownTimeout time.Duration // Wait for in-flight requests to complete once the listener closes
 	TLS             TLSConfig
+
+	// Zero-downtime deploys
+	ReusePort     bool          // Bind with SO_REUSEPORT so the next release can bind the same port
+	InheritFD     int           // Listener file descriptor handed off by a parent process; 0 binds a new socket
+	SystemdSocket string        // LISTEN_FDNAMES entry to use under systemd socket activation; empty takes the first
+	DrainDelay    time.Duration // Readiness fails for this long before the listener closes
+
+	// Request budgets
+	RequestTimeout time.Duration        // Deadline of requests without a route timeout; 0 disables it
+	RouteTimeouts  []RouteTimeoutConfig // Per-route overrides, the longest matching prefix wins
+}
+
+// RouteTimeoutConfig overrides the request timeout of the routes under a path prefix
+type RouteTimeoutConfig struct {
+	Method     string // Empty matches any method
+	PathPrefix string
+	Timeout    time.Duration // 0 disables the timeout, and that of its queries, e.g. for streamed exports
 }
 
 // TLSConfig holds TLS/HTTPS configuration
 type TLSConfig struct {
 	Enabled  bool
-	CertFile string
+	CertFile string // Certificate of the platform's own hostnames; optional with AutoCert
 	KeyFile  string
+
+	// Certificates of verified custom site domains, issued by an ACME CA on their first handshake
+	AutoCert          bool
+	ACMEEmail         string // Contact for expiry and account notices from the CA
+	ACMEDirectoryURL  string // Empty uses Let's Encrypt production
+	HTTPChallengePort int    // Answers HTTP-01 challenges and redirects to HTTPS; 0 relies on TLS-ALPN-01 alone
 }
 
 // DatabaseConfig holds database connection configuration
@@ -56,6 +136,11 @@ type DatabaseConfig struct {
 	MaxIdleConns   int
 	MaxLifetime    time.Duration
 	MaxIdleTime    time.Duration
+
+	QueryTimeout       time.Duration // Deadline of each query within its request; routes with their own timeout lift it to theirs; 0 disables it
+	SlowQueryThreshold time.Duration // Queries running at least this long are logged; 0 disables it
+	ExplainQueries     bool          // EXPLAIN repository queries and collect index suggestions; development only
+	ExplainTables      []string      // Tables whose sequential scans are reported
 }
 
 // RedisConfig holds Redis configuration
@@ -66,6 +151,272 @@ type RedisConfig struct {
 	Database int
 	PoolSize int
 	TTL      time.Duration
+	// KeyPrefix is prepended to every cache key so deployments sharing a Redis
+	// server stay apart; empty uses <app name>:<environment>
+	KeyPrefix string
+
+	// Circuit breaker around Redis; while open, cache and rate limiting fall back to memory
+	BreakerThreshold int           // Consecutive failures that trip the breaker
+	BreakerCooldown  time.Duration // Time before a request may retry Redis
+	ProbeInterval    time.Duration // Health check interval while the breaker is open
+}
+
+// EventBusConfig selects the event bus. The memory bus delivers events within
+// the process; the Redis Streams bus shares them between instances through the
+// Redis server, with each consumer group receiving every event once.
+type EventBusConfig struct {
+	Driver          string        // memory or redis
+	StreamPrefix    string        // Prefix of the stream of each event type
+	ConsumerGroup   string        // Instances of a service share a group; empty uses the app name
+	MaxLen          int64         // Approximate entries kept per stream; 0 keeps every entry
+	BlockTimeout    time.Duration // How long a read waits for new entries
+	ClaimIdle       time.Duration // Pending entries idle this long are claimed from crashed consumers
+	MetricsInterval time.Duration // How often stream lag is sampled for /debug/vars
+	SchemaMode      string        // Published events checked against schemas/events: off, warn or enforce
+}
+
+// QueryCacheConfig holds the caching of hot checkout queries (active offers, offer codes, tax rates)
+type QueryCacheConfig struct {
+	TTL          time.Duration // Maximum age of a cached result
+	RefreshAhead time.Duration // Window before expiry in which results are refreshed in the background
+}
+
+// AuditConfig holds admin audit log retention
+type AuditConfig struct {
+	Retention        time.Duration // Entries older than this are archived to cold storage; 0 keeps them
+	ArchiveInterval  time.Duration // How often expired entries are archived
+	ArchiveBatchSize int           // Entries moved per archival statement
+}
+
+// RequestLogConfig holds the admin API request log kept for support forensics
+type RequestLogConfig struct {
+	Enabled         bool
+	ReadSampleRate  float64       // Share of successful GET requests logged, 0 to 1; failed requests are always logged
+	WriteSampleRate float64       // Share of other successful requests logged, 0 to 1
+	CaptureBodies   bool          // Keep redacted request and response bodies
+	MaxBodyBytes    int           // Bodies are cut at this size
+	ExcludePaths    []string      // Path prefixes never logged
+	RedactFields    []string      // Field names redacted on top of the built-in secrets and personal data
+	Retention       time.Duration // Entries older than this are deleted; 0 keeps them
+	PurgeInterval   time.Duration // How often expired entries are deleted
+	PurgeBatchSize  int           // Entries deleted per statement
+	BufferSize      int           // Entries waiting to be written; more are dropped
+}
+
+// ExportConfig holds background admin export settings
+type ExportConfig struct {
+	Dir            string        // Directory export files are written to; defaults to the OS temp dir
+	Retention      time.Duration // How long finished exports can be downloaded
+	MaxConcurrent  int           // Background exports running at the same time
+	ReportInterval time.Duration // How often subscribed reports due for delivery are emailed; 0 disables deliveries
+}
+
+// WarehouseConfig holds the scheduled export of order, inventory and offer data to the data warehouse
+type WarehouseConfig struct {
+	StorageURL      string        // s3://bucket/prefix, gs://bucket/prefix or file:///path; empty disables exports
+	Region          string        // Defaults to us-east-1 for S3 and auto for GCS
+	Endpoint        string        // Custom endpoint of an S3-compatible service
+	PathStyle       bool          // Address buckets in the path instead of the host name
+	AccessKeyID     string        // HMAC key for GCS
+	SecretAccessKey string        // HMAC secret for GCS
+	SessionToken    string        // Temporary credentials only
+	Interval        time.Duration // How often exports run; 0 only exports on demand
+	BatchSize       int           // Rows read per query; each batch is written as one file per partition
+	UploadTimeout   time.Duration // Per upload attempt
+}
+
+// AttachmentConfig holds the storage of files attached to orders: purchase orders,
+// customs documents and photos of damaged items
+type AttachmentConfig struct {
+	StorageURL      string        // s3://bucket/prefix, gs://bucket/prefix or file:///path; empty disables attachments
+	Region          string        // Defaults to us-east-1 for S3 and auto for GCS
+	Endpoint        string        // Custom endpoint of an S3-compatible service
+	PathStyle       bool          // Address buckets in the path instead of the host name
+	AccessKeyID     string        // HMAC key for GCS
+	SecretAccessKey string        // HMAC secret for GCS
+	SessionToken    string        // Temporary credentials only
+	Timeout         time.Duration // Per upload or download attempt
+	MaxSizeKiB      int           // Largest file accepted
+	ContentTypes    []string      // Content types accepted, e.g. application/pdf or image/*
+	CustomerUploads int           // Files a customer may attach to one order; 0 only lets staff attach files
+}
+
+// Store returns the object store settings of the attachments
+func (c AttachmentConfig) Store() objectstore.Config {
+	return objectstore.Config{
+		URL:             c.StorageURL,
+		Region:          c.Region,
+		Endpoint:        c.Endpoint,
+		PathStyle:       c.PathStyle,
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+	}
+}
+
+// FulfillmentConfig holds the ship-by SLA of fulfillment groups. Deadlines
+// are counted in business days of the default business calendar.
+type FulfillmentConfig struct {
+	SLAShipWithinDays int           // Business days to ship for methods without a policy
+	SLAAtRisk         time.Duration // How close to the deadline an unshipped group is at risk
+	SLACheckInterval  time.Duration // How often groups are checked against their deadline; 0 disables the monitor
+	SLAAlertEmail     string        // Where late and at-risk groups are reported; empty only logs them
+}
+
+// CalendarConfig holds the working week of the default business calendar,
+// used where no calendar stored by admins sets one
+type CalendarConfig struct {
+	TimeZone     string        // IANA zone; empty is UTC
+	Workdays     []string      // Days worked on, e.g. MON..FRI
+	CutoffHour   int           // Work started at or after this hour counts from the next business day; 0 has no cutoff
+	CacheTTL     time.Duration // How long resolved calendars are reused before edits made elsewhere are seen
+	ImportYears  int           // Years ahead yearly recurring iCal events are expanded
+	ImportMaxKiB int64         // Largest iCal feed accepted
+}
+
+// Weekdays parses the days of the default working week
+func (c CalendarConfig) Weekdays() ([]time.Weekday, error) {
+	return calendar.ParseWeekdays(c.Workdays)
+}
+
+// CustomerConfig holds customer account maintenance settings
+type CustomerConfig struct {
+	DuplicateScanInterval time.Duration // How often accounts are scanned for duplicates to review; 0 disables the job
+	ImportDir             string        // Directory uploaded import files are kept in until their import completes; empty uses the system temp directory
+	ImportMaxMiB          int64         // Largest customer import file accepted
+	ImportBatchSize       int           // Records committed at a time; an interrupted import resumes after the last batch
+	ImportPollInterval    time.Duration // How often the import worker looks for queued imports; 0 disables it
+	ImportLease           time.Duration // How long an import stays with a worker without progress before another takes it over
+}
+
+// DocumentConfig holds PDF rendering of invoices, quotes and packing slips
+type DocumentConfig struct {
+	TemplateDir    string // Directory of *.html templates replacing the built-in ones by name; empty uses the built-in ones
+	FontRegular    string // TrueType files replacing Helvetica, e.g. for a brand font; empty keeps Helvetica
+	FontBold       string
+	FontItalic     string
+	FontBoldItalic string
+	LogoPath       string        // PNG or JPEG shown as the "logo" image; empty shows none
+	Workers        int           // Documents rendered at the same time
+	QueueSize      int           // Requests waiting for a worker before new ones are refused
+	RenderTimeout  time.Duration // Longest a request waits for its document, queued and rendering
+	SellerName     string        // Shown on invoices and quotes
+	SellerAddress  []string      // One entry per line
+	SellerTaxID    string
+}
+
+// NotificationConfig holds the transactional email provider
+type NotificationConfig struct {
+	EmailAPIURL string // Base URL of the email provider's API; empty disables email
+	EmailPath   string // Path emails are posted to
+	EmailFrom   string
+}
+
+// RateLimitConfig holds storefront request rate limiting configuration
+type RateLimitConfig struct {
+	Enabled          bool
+	Requests         int           // Requests allowed per client IP per window
+	CustomerRequests int           // Requests allowed per authenticated customer; 0 uses Requests
+	Window           time.Duration // Length of a fixed window
+
+	Buckets         []RateLimitBucketConfig           // Expensive routes, such as search and checkout, counted apart
+	Partners        map[string]RateLimitPartnerConfig // Partner integrations identified by X-API-Key, by partner
+	RefreshInterval time.Duration                     // How often storefronts reload the limit overrides set by the admin
+}
+
+// RateLimitBucketConfig counts the storefront routes matching its patterns against limits of their own
+type RateLimitBucketConfig struct {
+	Name             string
+	Requests         int // Per client IP per window
+	CustomerRequests int // Per customer and partner; 0 uses Requests
+	Window           time.Duration
+	Routes           []RoutePatternConfig
+}
+
+// RateLimitPartnerConfig holds the API key and quotas of a partner integration
+type RateLimitPartnerConfig struct {
+	APIKey   string
+	Requests int            // Requests per window outside buckets; 0 uses the customer limit
+	Buckets  map[string]int // Per bucket name; buckets missing use their customer limit
+}
+
+// PartnerKeys returns the partner API keys by partner
+func (c RateLimitConfig) PartnerKeys() map[string]string {
+	keys := make(map[string]string, len(c.Partners))
+	for partner, p := range c.Partners {
+		keys[partner] = p.APIKey
+	}
+	return keys
+}
+
+// Policy returns the limits of each kind of client, outside buckets and in each bucket
+func (c RateLimitConfig) Policy() ratelimit.Policy {
+	policy := ratelimit.Policy{
+		Requests:         c.Requests,
+		CustomerRequests: c.CustomerRequests,
+		Window:           c.Window,
+		Partners:         make(map[string]ratelimit.PartnerQuota, len(c.Partners)),
+	}
+	for _, bucket := range c.Buckets {
+		policy.Buckets = append(policy.Buckets, ratelimit.Bucket{
+			Name:             bucket.Name,
+			Requests:         bucket.Requests,
+			CustomerRequests: bucket.CustomerRequests,
+			Window:           bucket.Window,
+		})
+	}
+	for partner, p := range c.Partners {
+		policy.Partners[partner] = ratelimit.PartnerQuota{Requests: p.Requests, Buckets: p.Buckets}
+	}
+	return policy
+}
+
+// WaitingRoomConfig holds the virtual waiting room queuing storefront checkout
+// during high-demand drops. Shared through Redis when it is configured.
+type WaitingRoomConfig struct {
+	Enabled        bool
+	MaxActive      int                  // Checkout sessions active at once before visitors are queued
+	AdmitPerMinute int                  // Queued visitors admitted per minute
+	SessionTTL     time.Duration        // How long an admitted visitor may check out
+	TicketTTL      time.Duration        // How long a queue ticket stays valid
+	Secret         string               // Signs queue tickets; empty uses the JWT secret
+	Routes         []RoutePatternConfig // Checkout routes behind the waiting room
+}
+
+// RoutePatternConfig matches storefront routes by method and path pattern
+type RoutePatternConfig struct {
+	Method string // Empty matches any method
+	Path   string // path.Match pattern, e.g. "/orders/*/payment"
+}
+
+// RoutePrefixConfig matches storefront routes by method and path prefix
+type RoutePrefixConfig struct {
+	Method     string // Empty matches any method
+	PathPrefix string
+}
+
+// LoadShedConfig rejects low-priority storefront traffic with 503 and
+// Retry-After as the server or its database nears saturation, keeping capacity
+// for checkout and payments. Requests in flight and latency are measured per
+// route group and published at /debug/vars even while shedding is disabled.
+type LoadShedConfig struct {
+	Enabled         bool
+	MaxInFlight     int                   // Requests in flight at full saturation; 0 ignores them
+	DBPoolUsage     bool                  // The share of database connections in use counts as saturation
+	LowThreshold    float64               // Saturation (0-1) from which low-priority requests are rejected
+	NormalThreshold float64               // Saturation (0-1) from which normal requests are rejected
+	RetryAfter      time.Duration         // Suggested to rejected clients
+	CrawlerAgents   []string              // User-Agent substrings of crawlers, which are low priority
+	Groups          []LoadShedGroupConfig // Route groups; requests of no group are normal priority
+}
+
+// LoadShedGroupConfig is a storefront route group measured and shed together
+type LoadShedGroupConfig struct {
+	Name          string
+	Priority      string              // critical, normal or low
+	AnonymousLow  bool                // Anonymous GET requests are low priority, e.g. browsing
+	LatencyTarget time.Duration       // Average latency at full saturation; 0 ignores the latency of the group
+	Routes        []RoutePrefixConfig // The longest matching prefix wins
 }
 
 // AuthConfig holds authentication configuration
@@ -77,6 +428,7 @@ type AuthConfig struct {
 	SessionCookieName   string
 	SessionCookieSecure bool
 	SessionCookieDomain string
+	AdminSessionIdle    time.Duration // Admin sessions without activity for this long are rejected; 0 disables
 }
 
 // PaymentConfig holds payment gateway configuration
@@ -87,6 +439,32 @@ type PaymentConfig struct {
 	WebhookKey string
 }
 
+// HTTPClientConfig holds the defaults for outbound calls to external providers
+type HTTPClientConfig struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	LogBodies        bool // Log redacted request and response bodies at debug level
+}
+
+// Client returns the client settings for a provider
+func (c HTTPClientConfig) Client(name, baseURL string) httpclient.Config {
+	return httpclient.Config{
+		Name:             name,
+		BaseURL:          baseURL,
+		Timeout:          c.Timeout,
+		MaxRetries:       c.MaxRetries,
+		RetryBaseDelay:   c.RetryBaseDelay,
+		RetryMaxDelay:    c.RetryMaxDelay,
+		BreakerThreshold: c.BreakerThreshold,
+		BreakerCooldown:  c.BreakerCooldown,
+		LogBodies:        c.LogBodies,
+	}
+}
+
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
 	AllowedOrigins   []string
@@ -97,6 +475,332 @@ type CORSConfig struct {
 	MaxAge           int
 }
 
+// CatalogConfig holds storefront catalog listing configuration
+type CatalogConfig struct {
+	OutOfStockPolicy            string        // badge, bottom, hide
+	AvailabilityRefreshInterval time.Duration // How often the product availability read model is rebuilt
+	BadgeNewDays                int           // Days after activation a product shows the New badge; 0 disables it
+	BestsellerDays              int           // Order history considered for the Bestseller badge
+	BestsellerMinUnits          int           // Units ordered within BestsellerDays to show the Bestseller badge; 0 disables it
+	NavigationDepth             int           // Category levels in the storefront navigation tree
+
+	// Catalog caches preloaded at startup and re-warmed after edits invalidate them
+	CacheWarmOnStartup      bool
+	CacheWarmProducts       []int64 // Product IDs always warmed
+	CacheWarmCategories     []int64 // Category IDs always warmed
+	CacheWarmTopProducts    int     // Best-selling products warmed as well; 0 disables
+	CacheWarmTopCategories  int     // Best-selling categories warmed as well; 0 disables
+	CacheWarmPopularityDays int     // Order history ranking the best sellers
+
+	// Content published notifications telling headless frontends and CDNs which storefront URLs to purge
+	PublishWebhookURLs   []string      // Endpoints receiving each notification as a JSON POST
+	PublishWebhookSecret string        // Signs the webhook timestamp and body with HMAC-SHA256; empty sends it unsigned
+	PublishEvents        bool          // Publish catalog.content.published on the event bus as well
+	PublishBaseURL       string        // Storefront origin prefixed to catalog URLs, e.g. https://shop.example.com
+	PublishContentPath   string        // Storefront path of CMS content; {slug} is replaced
+	PublishGlobalPaths   []string      // Pages showing the navigation tree, purged on category changes
+	PublishBatchWindow   time.Duration // Changes within the window are sent in one notification
+
+	// Price-drop alerts customers set on SKUs
+	PriceWatchInterval    time.Duration // How often scheduled price changes are applied and conversions attributed; 0 disables
+	PriceWatchAttribution time.Duration // Orders with the SKU placed this long after an alert count as converted
+
+	// Scheduled sale events ("daily deals")
+	SaleEventInterval time.Duration // How often due sale events are started and ended; 0 disables
+	SaleEventUpcoming time.Duration // Scheduled events starting within it are listed on the storefront
+
+	// Product completeness scores shown to admins
+	QualityRules        []QualityRuleConfig // Empty uses the default rules
+	QualityImagePrefix  string              // Product and SKU attributes named with it hold image URLs
+	QualityBlockPublish bool                // Draft products failing a blocking rule cannot be published
+}
+
+// QualityRuleConfig weighs a completeness check in product quality scores
+type QualityRuleConfig struct {
+	Check      string   // IMAGES, DESCRIPTION, LONG_DESCRIPTION, ATTRIBUTES, META_TITLE, META_DESCRIPTION, CATEGORY, PRICE, INVENTORY
+	Weight     int      // Share of the score the check is worth
+	Blocking   bool     // Products failing it cannot be published
+	MinLength  int      // Text checks: characters required
+	MinCount   int      // Images, attributes and inventory: values or units required
+	Attributes []string // Attributes check: names that must be set
+}
+
+// OrderConfig holds cart and order validation policies
+type OrderConfig struct {
+	MaxQuantityPerSKU    int // 0 disables the limit
+	MaxDistinctLines     int // 0 disables the limit
+	CategoryRestrictions []CategoryRestrictionConfig
+
+	// Inventory released by cancelled orders is retried until it succeeds
+	DeallocationMaxAttempts int           // Attempts before a release is dead-lettered for reconciliation
+	DeallocationRetryDelay  time.Duration // Delay before the first retry; doubles with each attempt
+	DeallocationInterval    time.Duration // How often due releases are retried
+
+	// Carts show the automatic offers they are close to qualifying for
+	PromotionMessageMaxGap float64 // Largest spend gap advertised; 0 advertises any gap
+	PromotionMessageLimit  int     // Offers advertised per cart; 0 advertises all of them
+
+	// Orders placed by agents for phone and mail customers
+	AssistedOverrideRole string        // Admin role allowed to override prices and bypass the cart policy
+	PayLinkURL           string        // Storefront page paying an order; "{token}" is replaced with the link token
+	PayLinkTTL           time.Duration // How long a payment link can be used unless set when it is issued
+
+	// Orders held for fraud, payment or address review
+	HoldSLA         time.Duration // How long a hold may stay open before staff are told; 0 disables the alerts
+	HoldSLAInterval time.Duration // How often holds are checked against the SLA
+	HoldAlertEmail  string        // Where overdue holds are reported; empty only logs them
+
+	// Quotes are PDFs of carts for customers buying on account
+	QuoteValidity time.Duration // How long the prices of a quote are honored
+
+	// Completed orders are moved to archive tables once past the retention
+	// window the admin sets for their status
+	ArchiveInterval  time.Duration // How often orders past retention are archived; 0 disables the job
+	ArchiveBatchSize int           // Orders moved per archival statement
+	TagRuleInterval  time.Duration // How often tag rules are applied to newly submitted orders; 0 disables the job
+
+	// Submitted orders can be edited until they ship; the edit is previewed and
+	// confirmed before the order changes
+	EditWindow time.Duration // How long after submission customers can edit their orders; 0 leaves it to staff
+	EditTTL    time.Duration // How long an edit stays open before it expires
+}
+
+// TaxConfig holds order tax calculation settings
+type TaxConfig struct {
+	// immediate calculates tax on every cart change; deferred estimates it from the
+	// cached rates and calculates it once when the order is submitted
+	Mode         string
+	ProviderURL  string // Base URL of an external tax engine; empty calculates from the configured tax details
+	ProviderPath string // Path orders are posted to for calculation
+
+	// Duties and import fees of cross-border orders are estimated from the duty
+	// tables unless an external landed cost service is configured
+	ShipFromCountry string // ISO alpha-2 country orders ship from; orders shipped within it owe no duties
+	LandedCostURL   string // Base URL of an external landed cost service; empty estimates from the duty tables
+	LandedCostPath  string // Path orders are posted to for duty estimation
+}
+
+// AddressConfig holds address validation configuration
+type AddressConfig struct {
+	ProviderURL  string // Base URL of an address verification service; empty only normalizes addresses
+	ProviderPath string // Path addresses are posted to for verification
+}
+
+// InventoryConfig holds available-to-promise settings
+type InventoryConfig struct {
+	InboundHorizon time.Duration // Inbound receipts expected within this window are promised; 0 promises all of them
+
+	// External sales channels (marketplaces) reserving stock through the integration API
+	Channels                 map[string]SalesChannelConfig // Channel code -> allocation; channels are defined in Integrations
+	ReservationSweepInterval time.Duration                 // How often expired channel reservations are released
+
+	// Flash-sale allocation reserves SKUs from Redis counters; needs Redis
+	FlashReconcileInterval time.Duration // How often flash reservations are applied to the inventory levels; 0 only reconciles on demand
+
+	// SKUs are made unavailable when their available-to-promise runs out and
+	// available again once restocked, apart enough not to flap
+	AvailabilitySync bool // Off, the availability flag of SKUs is only set by admins
+	OutOfStockAt     int  // Available-to-promise at or below which a SKU is made unavailable
+	BackInStockAt    int  // Available-to-promise from which a SKU the sync made unavailable is made available again
+}
+
+// SalesChannelConfig holds the allocation caps of an external sales channel
+type SalesChannelConfig struct {
+	AllocationPercent int            // Share of each SKU's sellable stock the channel may hold reserved, 1-100
+	MaxQuantity       int            // Units of a SKU the channel may hold reserved; 0 leaves only the share
+	SKUCaps           map[string]int // SKU ID -> units, overriding MaxQuantity; 0 keeps the SKU off the channel
+	ReservationTTL    time.Duration  // Expiry of reservations that do not ask for one
+	MaxReservationTTL time.Duration  // Longest expiry a reservation may ask for; 0 has no limit
+}
+
+// CategoryRestrictionConfig restricts purchases from a category by customer segment or destination
+type CategoryRestrictionConfig struct {
+	CategoryID       int64
+	AllowedSegments  []string // Customer role names allowed to purchase; empty allows everyone
+	BlockedCountries []string // ISO alpha-2 destination countries
+}
+
+// StorefrontConfig holds storefront request context defaults
+type StorefrontConfig struct {
+	DefaultSite         string
+	TimeZone            string // IANA zone schedules entered without an offset are read in, e.g. "America/New_York"
+	DefaultLocale       string
+	DefaultCurrency     string
+	SupportedLocales    []string
+	SupportedCurrencies []string
+	LocaleCurrencies    map[string]string     // Locale -> default currency
+	CountryCurrencies   map[string]string     // Country -> default currency for visitors located by GeoIP
+	GeoIPDatabase       string                // Path to a MaxMind GeoIP2/GeoLite2 Country or City database; empty disables GeoIP
+	AddToCartPath       string                // Cart endpoint linked as add_to_cart in catalog hypermedia; empty omits the link
+	MessageDir          string                // Directory of per-locale message catalogs, e.g. es-ES.toml or fr.json, translating documents and emails; empty uses the built-in English messages
+	Sites               map[string]SiteConfig // Site ID -> locales and currencies; sites not listed use the defaults above
+	PreferenceCacheTTL  time.Duration         // How long a visitor's stored locale and currency are cached
+	CustomerCacheTTL    time.Duration         // How long customer profiles are cached
+	CustomerMissTTL     time.Duration         // How long a lookup of a nonexistent customer ID is cached
+
+	// Custom domains serving sites are registered and verified on the admin
+	DomainRefreshInterval time.Duration // How often the storefront reloads the verified domains it serves
+
+	// Composed pages (/bff) fetch their sections in parallel and leave out the ones that fail
+	BFFSectionTimeout time.Duration // Sections slower than this are left out; 0 waits for every section
+	BFFBestSellers    int           // Best sellers on the home page; ranked over Catalog.BestsellerDays
+	BFFPromotions     int           // Promotions advertised per page; 0 advertises all of them
+}
+
+// FeaturesConfig holds the runtime flags switched on the admin: storefront
+// maintenance mode and the kill switches of its subsystems. Site settings
+// changed on the admin are reloaded alongside.
+type FeaturesConfig struct {
+	RefreshInterval         time.Duration           // How often servers reload the flags
+	SettingsRefreshInterval time.Duration           // How often servers reload the site settings
+	MaintenancePage         string                  // Path to the HTML page of maintenance mode; empty uses a built-in page
+	MaintenanceRetryAfter   time.Duration           // Retry-After of maintenance responses; 0 omits it
+	MaintenanceBypassToken  string                  // X-Maintenance-Bypass value served during maintenance; empty disables it
+	KillSwitchRoutes        []KillSwitchRouteConfig // Storefront routes turned off with their flag
+}
+
+// KillSwitchRouteConfig turns off the storefront routes under a path prefix while a flag is off
+type KillSwitchRouteConfig struct {
+	Flag       string // checkout, search or reviews
+	Method     string // Empty matches any method
+	PathPrefix string
+}
+
+// IntegrationsConfig holds the external sales channels calling the integration API
+type IntegrationsConfig struct {
+	Channels map[string]ChannelConfig // Channel code, e.g. "ebay" -> credentials and order ingestion
+}
+
+// ChannelConfig holds the credentials and order ingestion settings of an external sales channel
+type ChannelConfig struct {
+	APIKey             string // Sent by the channel's integration as a bearer token
+	OrderFormat        string // Payload format of ingested orders: generic (the default) or ebay
+	AcknowledgementURL string // Receives the result of each ingested order; empty only answers the request
+}
+
+// ChannelKeys returns the API key of each channel
+func (c IntegrationsConfig) ChannelKeys() map[string]string {
+	keys := make(map[string]string, len(c.Channels))
+	for code, channel := range c.Channels {
+		keys[code] = channel.APIKey
+	}
+	return keys
+}
+
+// WebhooksConfig holds the providers allowed to send webhooks and their processing retries
+type WebhooksConfig struct {
+	Providers    map[string]WebhookProviderConfig // Provider name in /webhooks/{provider}, e.g. "stripe" -> signature
+	PollInterval time.Duration                    // How often stored webhooks are processed
+	MaxAttempts  int                              // Attempts before a webhook is dead-lettered
+	RetryDelay   time.Duration                    // Delay before the first retry; doubles with each attempt
+}
+
+// WebhookProviderConfig holds how the webhooks of a provider are signed
+type WebhookProviderConfig struct {
+	Scheme    string        // hmac (X-Webhook-Signature, the default) or stripe
+	Secret    string        // Signing secret shared with the provider
+	Tolerance time.Duration // Max age of a signed timestamp before it is rejected as a replay; 0 uses 5 minutes
+}
+
+// SiteConfig holds the locales, currencies and time zone of a site
+type SiteConfig struct {
+	TimeZone            string // Empty uses the storefront time zone
+	DefaultLocale       string
+	DefaultCurrency     string
+	SupportedLocales    []string
+	SupportedCurrencies []string
+	LocaleCurrencies    map[string]string // Locale -> default currency
+	CountryCurrencies   map[string]string // Country -> default currency for visitors located by GeoIP
+}
+
+// MoneyConfig holds currency rounding and display rules; entries override the
+// built-in rules of pkg/money
+type MoneyConfig struct {
+	Currencies map[string]CurrencyRuleConfig // ISO 4217 code -> rule
+	Locales    map[string]LocaleFormatConfig // Locale -> display format
+}
+
+// CurrencyRuleConfig holds the rounding rule and symbol of a currency
+type CurrencyRuleConfig struct {
+	Symbol            string
+	DecimalPlaces     int32
+	RoundingIncrement float64 // e.g. 0.05; 0 rounds to DecimalPlaces
+	RoundingMode      string  // half_up, half_even, up, down
+}
+
+// LocaleFormatConfig holds how amounts are displayed in a locale
+type LocaleFormatConfig struct {
+	DecimalSeparator string
+	GroupSeparator   string
+	SymbolPosition   string // before, after
+	SymbolSpacing    bool
+}
+
+// CurrencyRules converts the configured currencies to money rules
+func (c MoneyConfig) CurrencyRules() []money.CurrencyRule {
+	rules := make([]money.CurrencyRule, 0, len(c.Currencies))
+	for code, currency := range c.Currencies {
+		rules = append(rules, money.CurrencyRule{
+			Code:              strings.ToUpper(code),
+			Symbol:            currency.Symbol,
+			DecimalPlaces:     currency.DecimalPlaces,
+			RoundingIncrement: decimal.NewFromFloat(currency.RoundingIncrement),
+			RoundingMode:      money.RoundingMode(currency.RoundingMode),
+		})
+	}
+	return rules
+}
+
+// LocaleFormats converts the configured locales to money display formats
+func (c MoneyConfig) LocaleFormats() map[string]money.LocaleFormat {
+	formats := make(map[string]money.LocaleFormat, len(c.Locales))
+	for locale, format := range c.Locales {
+		formats[locale] = money.LocaleFormat{
+			DecimalSeparator: format.DecimalSeparator,
+			GroupSeparator:   format.GroupSeparator,
+			SymbolPosition:   money.SymbolPosition(format.SymbolPosition),
+			SymbolSpacing:    format.SymbolSpacing,
+		}
+	}
+	return formats
+}
+
+// Localizations converts the default and per-site locales and currencies to
+// storefront localizations
+func (c StorefrontConfig) Localizations() requestctx.Localizations {
+	localizations := requestctx.Localizations{
+		Default: requestctx.Localization{
+			DefaultLocale:     c.DefaultLocale,
+			DefaultCurrency:   c.DefaultCurrency,
+			Locales:           c.SupportedLocales,
+			Currencies:        c.SupportedCurrencies,
+			LocaleCurrencies:  c.LocaleCurrencies,
+			CountryCurrencies: c.CountryCurrencies,
+		},
+		Sites: make(map[string]requestctx.Localization, len(c.Sites)),
+	}
+	for id, site := range c.Sites {
+		localizations.Sites[id] = requestctx.Localization{
+			DefaultLocale:     site.DefaultLocale,
+			DefaultCurrency:   site.DefaultCurrency,
+			Locales:           site.SupportedLocales,
+			Currencies:        site.SupportedCurrencies,
+			LocaleCurrencies:  site.LocaleCurrencies,
+			CountryCurrencies: site.CountryCurrencies,
+		}
+	}
+	return localizations
+}
+
+// TimeZones loads the default and per-site time zones schedules are read in
+func (c StorefrontConfig) TimeZones() (schedule.Zones, error) {
+	sites := make(map[string]string, len(c.Sites))
+	for id, site := range c.Sites {
+		sites[id] = site.TimeZone
+	}
+	return schedule.LoadZones(c.TimeZone, sites)
+}
+
 // Load loads configuration from file and environment variables
 func Load(configPath string) (*Config, error) {
 	v := viper.New()
@@ -155,7 +859,22 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.readtimeout", "15s")
 	v.SetDefault("server.writetimeout", "15s")
 	v.SetDefault("server.shutdowntimeout", "30s")
+	v.SetDefault("server.reuseport", false)
+	v.SetDefault("server.inheritfd", 0)
+	v.SetDefault("server.systemdsocket", "")
+	v.SetDefault("server.draindelay", "0s")
+	v.SetDefault("server.requesttimeout", "30s")
+	v.SetDefault("server.routetimeouts", []RouteTimeoutConfig{
+		{Method: http.MethodGet, PathPrefix: "/catalog/", Timeout: 5 * time.Second},
+		{PathPrefix: "/admin/catalog/snapshot", Timeout: 2 * time.Minute},
+		{PathPrefix: "/admin/catalog/snapshot/import", Timeout: 10 * time.Minute},
+		{PathPrefix: "/admin/exports/", Timeout: 0},
+	})
 	v.SetDefault("server.tls.enabled", false)
+	v.SetDefault("server.tls.autocert", false)
+	v.SetDefault("server.tls.acmeemail", "")
+	v.SetDefault("server.tls.acmedirectoryurl", "")
+	v.SetDefault("server.tls.httpchallengeport", 0)
 
 	// Database defaults
 	v.SetDefault("database.host", "localhost")
@@ -168,6 +887,22 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.maxidleconns", 5)
 	v.SetDefault("database.maxlifetime", "5m")
 	v.SetDefault("database.maxidletime", "10m")
+	v.SetDefault("database.querytimeout", "15s")
+	v.SetDefault("database.slowquerythreshold", "200ms")
+	v.SetDefault("database.explainqueries", true)
+	v.SetDefault("database.explaintables", []string{"blc_order", "blc_order_item", "blc_sku", "blc_product"})
+
+	// Legacy Broadleaf database defaults (used only by cmd/legacyimport)
+	v.SetDefault("legacydatabase.host", "")
+	v.SetDefault("legacydatabase.port", 5432)
+	v.SetDefault("legacydatabase.user", "broadleaf")
+	v.SetDefault("legacydatabase.password", "")
+	v.SetDefault("legacydatabase.database", "broadleaf")
+	v.SetDefault("legacydatabase.sslmode", "disable")
+	v.SetDefault("legacydatabase.maxconnections", 5)
+	v.SetDefault("legacydatabase.maxidleconns", 1)
+	v.SetDefault("legacydatabase.maxlifetime", "5m")
+	v.SetDefault("legacydatabase.maxidletime", "10m")
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
@@ -176,6 +911,166 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("redis.database", 0)
 	v.SetDefault("redis.poolsize", 10)
 	v.SetDefault("redis.ttl", "1h")
+	v.SetDefault("redis.keyprefix", "")
+	v.SetDefault("redis.breakerthreshold", 3)
+	v.SetDefault("redis.breakercooldown", "10s")
+	v.SetDefault("redis.probeinterval", "5s")
+
+	// Query cache defaults
+	v.SetDefault("querycache.ttl", "5m")
+	v.SetDefault("querycache.refreshahead", "1m")
+
+	// Event bus defaults
+	v.SetDefault("eventbus.driver", "memory")
+	v.SetDefault("eventbus.streamprefix", "events")
+	v.SetDefault("eventbus.consumergroup", "")
+	v.SetDefault("eventbus.maxlen", 100000)
+	v.SetDefault("eventbus.blocktimeout", "5s")
+	v.SetDefault("eventbus.claimidle", "1m")
+	v.SetDefault("eventbus.metricsinterval", "30s")
+	v.SetDefault("eventbus.schemamode", "warn")
+
+	// Audit log defaults
+	v.SetDefault("audit.retention", "2160h") // 90 days
+	v.SetDefault("audit.archiveinterval", "1h")
+	v.SetDefault("audit.archivebatchsize", 1000)
+
+	// Admin request log defaults
+	v.SetDefault("requestlog.enabled", true)
+	v.SetDefault("requestlog.readsamplerate", 0.1)
+	v.SetDefault("requestlog.writesamplerate", 1.0)
+	v.SetDefault("requestlog.capturebodies", true)
+	v.SetDefault("requestlog.maxbodybytes", 16384)
+	v.SetDefault("requestlog.excludepaths", []string{"/health", "/admin/request-logs"})
+	v.SetDefault("requestlog.redactfields", []string{})
+	v.SetDefault("requestlog.retention", "720h") // 30 days
+	v.SetDefault("requestlog.purgeinterval", "1h")
+	v.SetDefault("requestlog.purgebatchsize", 1000)
+	v.SetDefault("requestlog.buffersize", 1000)
+
+	// Export defaults
+	v.SetDefault("export.dir", "")
+	v.SetDefault("export.retention", "24h")
+	v.SetDefault("export.maxconcurrent", 2)
+	v.SetDefault("export.reportinterval", "5m")
+
+	// Warehouse export defaults
+	v.SetDefault("warehouse.storageurl", "")
+	v.SetDefault("warehouse.region", "")
+	v.SetDefault("warehouse.endpoint", "")
+	v.SetDefault("warehouse.pathstyle", false)
+	v.SetDefault("warehouse.accesskeyid", "")
+	v.SetDefault("warehouse.secretaccesskey", "")
+	v.SetDefault("warehouse.sessiontoken", "")
+	v.SetDefault("warehouse.interval", "1h")
+	v.SetDefault("warehouse.batchsize", 50000)
+	v.SetDefault("warehouse.uploadtimeout", "2m")
+
+	// Order attachment defaults
+	v.SetDefault("attachments.storageurl", "")
+	v.SetDefault("attachments.region", "")
+	v.SetDefault("attachments.endpoint", "")
+	v.SetDefault("attachments.pathstyle", false)
+	v.SetDefault("attachments.accesskeyid", "")
+	v.SetDefault("attachments.secretaccesskey", "")
+	v.SetDefault("attachments.sessiontoken", "")
+	v.SetDefault("attachments.timeout", "30s")
+	v.SetDefault("attachments.maxsizekib", 10240)
+	v.SetDefault("attachments.contenttypes", []string{"application/pdf", "image/*", "text/plain", "text/csv"})
+	v.SetDefault("attachments.customeruploads", 10)
+
+	// Fulfillment SLA defaults
+	v.SetDefault("fulfillment.slashipwithindays", 2)
+	v.SetDefault("fulfillment.slaatrisk", "8h")
+	v.SetDefault("fulfillment.slacheckinterval", "15m")
+	v.SetDefault("fulfillment.slaalertemail", "")
+
+	// Business calendar defaults
+	v.SetDefault("calendar.timezone", "")
+	v.SetDefault("calendar.workdays", []string{"MON", "TUE", "WED", "THU", "FRI"})
+	v.SetDefault("calendar.cutoffhour", 14)
+	v.SetDefault("calendar.cachettl", "5m")
+	v.SetDefault("calendar.importyears", 5)
+	v.SetDefault("calendar.importmaxkib", 1024)
+
+	// Document rendering defaults
+	v.SetDefault("documents.templatedir", "")
+	v.SetDefault("documents.fontregular", "")
+	v.SetDefault("documents.fontbold", "")
+	v.SetDefault("documents.fontitalic", "")
+	v.SetDefault("documents.fontbolditalic", "")
+	v.SetDefault("documents.logopath", "")
+	v.SetDefault("documents.workers", 2)
+	v.SetDefault("documents.queuesize", 32)
+	v.SetDefault("documents.rendertimeout", "30s")
+	v.SetDefault("documents.sellername", "")
+	v.SetDefault("documents.selleraddress", []string{})
+	v.SetDefault("documents.sellertaxid", "")
+
+	// Tax defaults
+	v.SetDefault("tax.mode", "immediate")
+	v.SetDefault("tax.providerurl", "")
+	v.SetDefault("tax.providerpath", "/v1/tax/calculate")
+	v.SetDefault("tax.shipfromcountry", "US")
+	v.SetDefault("tax.landedcosturl", "")
+	v.SetDefault("tax.landedcostpath", "/v1/landed-cost/estimate")
+
+	// Address validation defaults
+	v.SetDefault("address.providerurl", "")
+	v.SetDefault("address.providerpath", "/v1/addresses/verify")
+
+	// Notification defaults
+	v.SetDefault("notification.emailapiurl", "")
+	v.SetDefault("notification.emailpath", "/v1/send")
+	v.SetDefault("notification.emailfrom", "no-reply@localhost")
+
+	// Rate limit defaults
+	v.SetDefault("ratelimit.enabled", true)
+	v.SetDefault("ratelimit.requests", 300)
+	v.SetDefault("ratelimit.customerrequests", 600)
+	v.SetDefault("ratelimit.window", "1m")
+	v.SetDefault("ratelimit.refreshinterval", "30s")
+	v.SetDefault("ratelimit.buckets", []RateLimitBucketConfig{
+		{Name: "search", Requests: 60, CustomerRequests: 120, Window: time.Minute, Routes: []RoutePatternConfig{
+			{Method: http.MethodGet, Path: "/catalog/products/search"},
+		}},
+		{Name: "checkout", Requests: 20, CustomerRequests: 30, Window: time.Minute, Routes: []RoutePatternConfig{
+			{Method: http.MethodPost, Path: "/orders/*/payment"},
+			{Method: http.MethodPost, Path: "/orders/*/offline-payment"},
+			{Method: http.MethodPost, Path: "/orders/pay-links/*"},
+		}},
+	})
+
+	// Waiting room defaults
+	v.SetDefault("waitingroom.enabled", false)
+	v.SetDefault("waitingroom.maxactive", 500)
+	v.SetDefault("waitingroom.admitperminute", 120)
+	v.SetDefault("waitingroom.sessionttl", "15m")
+	v.SetDefault("waitingroom.ticketttl", "2h")
+	v.SetDefault("waitingroom.secret", "")
+	v.SetDefault("waitingroom.routes", []RoutePatternConfig{
+		{Method: http.MethodPost, Path: "/orders/*/payment"},
+		{Method: http.MethodPost, Path: "/orders/*/offline-payment"},
+		{Method: http.MethodPost, Path: "/orders/pay-links/*"},
+	})
+
+	// Load shedding defaults
+	v.SetDefault("loadshed.enabled", false)
+	v.SetDefault("loadshed.maxinflight", 1000)
+	v.SetDefault("loadshed.dbpoolusage", true)
+	v.SetDefault("loadshed.lowthreshold", 0.8)
+	v.SetDefault("loadshed.normalthreshold", 0.95)
+	v.SetDefault("loadshed.retryafter", "5s")
+	v.SetDefault("loadshed.crawleragents", []string{"bot", "crawler", "spider", "slurp", "facebookexternalhit"})
+	v.SetDefault("loadshed.groups", []LoadShedGroupConfig{
+		{Name: "checkout", Priority: "critical", LatencyTarget: 2 * time.Second, Routes: []RoutePrefixConfig{
+			{PathPrefix: "/orders"},
+		}},
+		{Name: "browse", Priority: "normal", AnonymousLow: true, LatencyTarget: time.Second, Routes: []RoutePrefixConfig{
+			{PathPrefix: "/catalog"},
+			{PathPrefix: "/bff"},
+		}},
+	})
 
 	// Auth defaults
 	v.SetDefault("auth.jwtsecret", "change-me-in-production")
@@ -185,6 +1080,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("auth.sessioncookiename", "session")
 	v.SetDefault("auth.sessioncookiesecure", false)
 	v.SetDefault("auth.sessioncookiedomain", "")
+	v.SetDefault("auth.adminsessionidle", "30m")
 
 	// Payment defaults
 	v.SetDefault("payment.provider", "stripe")
@@ -192,13 +1088,131 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("payment.secretkey", "")
 	v.SetDefault("payment.webhookkey", "")
 
+	// Outbound HTTP client defaults
+	v.SetDefault("httpclient.timeout", "10s")
+	v.SetDefault("httpclient.maxretries", 2)
+	v.SetDefault("httpclient.retrybasedelay", "200ms")
+	v.SetDefault("httpclient.retrymaxdelay", "2s")
+	v.SetDefault("httpclient.breakerthreshold", 5)
+	v.SetDefault("httpclient.breakercooldown", "30s")
+	v.SetDefault("httpclient.logbodies", false)
+
 	// CORS defaults
 	v.SetDefault("cors.allowedorigins", []string{"*"})
 	v.SetDefault("cors.allowedmethods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
-	v.SetDefault("cors.allowedheaders", []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"})
-	v.SetDefault("cors.exposedheaders", []string{})
+	v.SetDefault("cors.allowedheaders", []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Waiting-Room-Token"})
+	v.SetDefault("cors.exposedheaders", []string{"X-Waiting-Room-Token", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Bucket"})
 	v.SetDefault("cors.allowcredentials", true)
 	v.SetDefault("cors.maxage", 300)
+
+	// Catalog defaults
+	v.SetDefault("catalog.outofstockpolicy", "badge")
+	v.SetDefault("catalog.availabilityrefreshinterval", "5m")
+	v.SetDefault("catalog.badgenewdays", 30)
+	v.SetDefault("catalog.bestsellerdays", 30)
+	v.SetDefault("catalog.bestsellerminunits", 10)
+	v.SetDefault("catalog.navigationdepth", 3)
+	v.SetDefault("catalog.cachewarmonstartup", true)
+	v.SetDefault("catalog.cachewarmproducts", []int64{})
+	v.SetDefault("catalog.cachewarmcategories", []int64{})
+	v.SetDefault("catalog.cachewarmtopproducts", 50)
+	v.SetDefault("catalog.cachewarmtopcategories", 20)
+	v.SetDefault("catalog.cachewarmpopularitydays", 30)
+	v.SetDefault("catalog.publishwebhookurls", []string{})
+	v.SetDefault("catalog.publishwebhooksecret", "")
+	v.SetDefault("catalog.publishevents", false)
+	v.SetDefault("catalog.publishbaseurl", "")
+	v.SetDefault("catalog.publishcontentpath", "/pages/{slug}")
+	v.SetDefault("catalog.publishglobalpaths", []string{"/"})
+	v.SetDefault("catalog.publishbatchwindow", "2s")
+	v.SetDefault("catalog.pricewatchinterval", "1m")
+	v.SetDefault("catalog.pricewatchattribution", "168h")
+	v.SetDefault("catalog.saleeventinterval", "30s")
+	v.SetDefault("catalog.saleeventupcoming", "24h")
+	v.SetDefault("catalog.qualityimageprefix", "image")
+	v.SetDefault("catalog.qualityblockpublish", true)
+
+	// Order defaults
+	v.SetDefault("order.maxquantitypersku", 0)
+	v.SetDefault("order.maxdistinctlines", 0)
+	v.SetDefault("order.deallocationmaxattempts", 10)
+	v.SetDefault("order.deallocationretrydelay", "30s")
+	v.SetDefault("order.deallocationinterval", "1m")
+	v.SetDefault("order.promotionmessagemaxgap", 0)
+	v.SetDefault("order.promotionmessagelimit", 3)
+	v.SetDefault("order.assistedoverriderole", "admin")
+	v.SetDefault("order.paylinkurl", "http://localhost:3000/pay/{token}")
+	v.SetDefault("order.paylinkttl", "72h")
+	v.SetDefault("order.holdsla", "24h")
+	v.SetDefault("order.holdslainterval", "15m")
+	v.SetDefault("order.holdalertemail", "")
+	v.SetDefault("order.quotevalidity", "720h")
+	v.SetDefault("order.archiveinterval", "6h")
+	v.SetDefault("order.archivebatchsize", 500)
+	v.SetDefault("order.tagruleinterval", "1m")
+	v.SetDefault("order.editwindow", "1h")
+	v.SetDefault("order.editttl", "30m")
+	v.SetDefault("customer.duplicatescaninterval", "24h")
+	v.SetDefault("customer.importdir", "")
+	v.SetDefault("customer.importmaxmib", 256)
+	v.SetDefault("customer.importbatchsize", 100)
+	v.SetDefault("customer.importpollinterval", "10s")
+	v.SetDefault("customer.importlease", "2m")
+	v.SetDefault("inventory.inboundhorizon", "720h")
+	v.SetDefault("inventory.channels", map[string]interface{}{})
+	v.SetDefault("inventory.reservationsweepinterval", "1m")
+	v.SetDefault("inventory.flashreconcileinterval", "5s")
+	v.SetDefault("inventory.availabilitysync", true)
+	v.SetDefault("inventory.outofstockat", 0)
+	v.SetDefault("inventory.backinstockat", 1)
+	v.SetDefault("integrations.channels", map[string]interface{}{})
+	v.SetDefault("webhooks.providers", map[string]interface{}{})
+	v.SetDefault("webhooks.pollinterval", "5s")
+	v.SetDefault("webhooks.maxattempts", 10)
+	v.SetDefault("webhooks.retrydelay", "30s")
+
+	// Storefront defaults
+	v.SetDefault("storefront.defaultsite", "default")
+	v.SetDefault("storefront.timezone", "UTC")
+	v.SetDefault("storefront.defaultlocale", "en-US")
+	v.SetDefault("storefront.defaultcurrency", "USD")
+	v.SetDefault("storefront.supportedlocales", []string{"en-US", "es-ES"})
+	v.SetDefault("storefront.supportedcurrencies", []string{"USD", "EUR"})
+	v.SetDefault("storefront.localecurrencies", map[string]string{"en-US": "USD", "es-ES": "EUR"})
+	v.SetDefault("storefront.countrycurrencies", map[string]string{})
+	v.SetDefault("storefront.geoipdatabase", "")
+	v.SetDefault("storefront.addtocartpath", "")
+	v.SetDefault("storefront.messagedir", "")
+	v.SetDefault("storefront.sites", map[string]interface{}{})
+	v.SetDefault("storefront.preferencecachettl", "5m")
+	v.SetDefault("storefront.customercachettl", "5m")
+	v.SetDefault("storefront.customermissttl", "30s")
+	v.SetDefault("storefront.domainrefreshinterval", "1m")
+	v.SetDefault("storefront.bffsectiontimeout", "800ms")
+	v.SetDefault("storefront.bffbestsellers", 8)
+	v.SetDefault("storefront.bffpromotions", 5)
+
+	// Runtime flag defaults
+	v.SetDefault("features.refreshinterval", "15s")
+	v.SetDefault("features.settingsrefreshinterval", "30s")
+	v.SetDefault("features.maintenancepage", "")
+	v.SetDefault("features.maintenanceretryafter", "5m")
+	v.SetDefault("features.maintenancebypasstoken", "")
+	v.SetDefault("features.killswitchroutes", []KillSwitchRouteConfig{
+		{Flag: featureflag.Checkout, Method: http.MethodPost, PathPrefix: "/orders/pay-links/"},
+		{Flag: featureflag.Search, Method: http.MethodGet, PathPrefix: "/catalog/products/search"},
+	})
+}
+
+// SiteSettingDefaults returns the configured values site settings default to
+// until they are changed on the admin
+func (c *Config) SiteSettingDefaults() map[string]string {
+	return map[string]string{
+		string(settings.StoreName):              c.App.Name,
+		string(settings.EmailFrom):              c.Notification.EmailFrom,
+		string(settings.PromotionMessageMaxGap): strconv.FormatFloat(c.Order.PromotionMessageMaxGap, 'f', -1, 64),
+		string(settings.PromotionMessageLimit):  strconv.Itoa(c.Order.PromotionMessageLimit),
+	}
 }
 
 // Validate validates the configuration
@@ -209,6 +1223,36 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid environment: %s (must be development, staging, or production)", c.App.Environment)
 	}
 
+	// Validate server draining and request budgets
+	if c.Server.ShutdownTimeout < 0 || c.Server.DrainDelay < 0 {
+		return fmt.Errorf("server shutdown timeout and drain delay cannot be negative")
+	}
+	if c.Server.RequestTimeout < 0 {
+		return fmt.Errorf("server request timeout cannot be negative")
+	}
+	if c.Database.QueryTimeout < 0 {
+		return fmt.Errorf("database query timeout cannot be negative")
+	}
+	for _, route := range c.Server.RouteTimeouts {
+		if route.PathPrefix == "" || route.Timeout < 0 {
+			return fmt.Errorf("invalid route timeout for %q: a path prefix and a non-negative timeout are required", route.PathPrefix)
+		}
+	}
+	if c.Server.InheritFD < 0 || (c.Server.InheritFD > 0 && c.Server.InheritFD < 3) {
+		return fmt.Errorf("invalid server inherited file descriptor: %d (must be 3 or above)", c.Server.InheritFD)
+	}
+	if c.Server.TLS.Enabled {
+		if c.Server.TLS.CertFile == "" && !c.Server.TLS.AutoCert {
+			return fmt.Errorf("TLS requires a certificate file or autocert")
+		}
+		if (c.Server.TLS.CertFile == "") != (c.Server.TLS.KeyFile == "") {
+			return fmt.Errorf("TLS certificate and key files must be set together")
+		}
+	}
+	if c.Server.TLS.HTTPChallengePort < 0 || c.Server.TLS.HTTPChallengePort > 65535 {
+		return fmt.Errorf("invalid TLS HTTP challenge port: %d", c.Server.TLS.HTTPChallengePort)
+	}
+
 	// Validate database
 	if c.Database.Host == "" {
 		return fmt.Errorf("database host is required")
@@ -216,6 +1260,400 @@ func (c *Config) Validate() error {
 	if c.Database.Database == "" {
 		return fmt.Errorf("database name is required")
 	}
+	if c.Database.SlowQueryThreshold < 0 {
+		return fmt.Errorf("database slow query threshold cannot be negative")
+	}
+
+	// Validate catalog listing policy
+	validPolicies := map[string]bool{"badge": true, "bottom": true, "hide": true}
+	if !validPolicies[c.Catalog.OutOfStockPolicy] {
+		return fmt.Errorf("invalid catalog out-of-stock policy: %s (must be badge, bottom, or hide)", c.Catalog.OutOfStockPolicy)
+	}
+	if c.Catalog.BadgeNewDays < 0 || c.Catalog.BestsellerDays < 0 || c.Catalog.BestsellerMinUnits < 0 {
+		return fmt.Errorf("catalog badge rules cannot be negative")
+	}
+	if c.Catalog.NavigationDepth < 1 {
+		return fmt.Errorf("catalog navigation depth must be at least 1")
+	}
+	if c.Catalog.CacheWarmTopProducts < 0 || c.Catalog.CacheWarmTopCategories < 0 || c.Catalog.CacheWarmPopularityDays < 0 {
+		return fmt.Errorf("catalog cache warming limits cannot be negative")
+	}
+	if c.Catalog.PublishBatchWindow <= 0 {
+		return fmt.Errorf("catalog publish batch window must be greater than 0")
+	}
+	if !strings.Contains(c.Catalog.PublishContentPath, "{slug}") {
+		return fmt.Errorf("catalog publish content path must contain {slug}")
+	}
+	if c.Catalog.PriceWatchInterval < 0 || c.Catalog.PriceWatchAttribution < 0 {
+		return fmt.Errorf("catalog price watch durations cannot be negative")
+	}
+	if c.Catalog.SaleEventInterval < 0 || c.Catalog.SaleEventUpcoming < 0 {
+		return fmt.Errorf("catalog sale event durations cannot be negative")
+	}
+
+	// Validate cart policy
+	if c.Order.MaxQuantityPerSKU < 0 || c.Order.MaxDistinctLines < 0 {
+		return fmt.Errorf("order line item limits cannot be negative")
+	}
+	for _, restriction := range c.Order.CategoryRestrictions {
+		if restriction.CategoryID == 0 {
+			return fmt.Errorf("order category restriction requires a category ID")
+		}
+	}
+
+	// Validate inventory deallocation retries
+	if c.Order.DeallocationMaxAttempts < 0 || c.Order.DeallocationRetryDelay < 0 {
+		return fmt.Errorf("order deallocation attempts and retry delay cannot be negative")
+	}
+
+	// Validate webhook providers
+	if c.Webhooks.MaxAttempts < 0 || c.Webhooks.RetryDelay < 0 || c.Webhooks.PollInterval < 0 {
+		return fmt.Errorf("webhook poll interval, attempts and retry delay cannot be negative")
+	}
+	for name, provider := range c.Webhooks.Providers {
+		if provider.Secret == "" {
+			return fmt.Errorf("webhook provider %s requires a secret", name)
+		}
+		if scheme := strings.ToLower(provider.Scheme); scheme != "" && scheme != "hmac" && scheme != "stripe" {
+			return fmt.Errorf("invalid signature scheme of webhook provider %s: %q (must be hmac or stripe)", name, provider.Scheme)
+		}
+		if provider.Tolerance < 0 {
+			return fmt.Errorf("signature tolerance of webhook provider %s cannot be negative", name)
+		}
+	}
+
+	// Validate order archival
+	if c.Order.ArchiveInterval < 0 || c.Order.ArchiveBatchSize < 0 {
+		return fmt.Errorf("order archive interval and batch size cannot be negative")
+	}
+
+	// Validate order edits
+	if c.Order.EditWindow < 0 || c.Order.EditTTL <= 0 {
+		return fmt.Errorf("order edit window cannot be negative and edit TTL must be positive")
+	}
+
+	// Validate order tag rules
+	if c.Order.TagRuleInterval < 0 {
+		return fmt.Errorf("order tag rule interval cannot be negative")
+	}
+
+	// Validate duplicate customer detection
+	if c.Customer.DuplicateScanInterval < 0 {
+		return fmt.Errorf("customer duplicate scan interval cannot be negative")
+	}
+	if c.Customer.ImportMaxMiB < 1 || c.Customer.ImportBatchSize < 1 {
+		return fmt.Errorf("customer import max size and batch size must be positive")
+	}
+	if c.Customer.ImportPollInterval < 0 {
+		return fmt.Errorf("customer import poll interval cannot be negative")
+	}
+	if c.Customer.ImportLease < 10*time.Second {
+		return fmt.Errorf("customer import lease must be at least 10s")
+	}
+
+	// Validate promotion messaging
+	if c.Order.PromotionMessageMaxGap < 0 || c.Order.PromotionMessageLimit < 0 {
+		return fmt.Errorf("order promotion message gap and limit cannot be negative")
+	}
+
+	// Validate payment links
+	if c.Order.PayLinkTTL <= 0 {
+		return fmt.Errorf("order pay link TTL must be positive")
+	}
+	if !strings.Contains(c.Order.PayLinkURL, "{token}") {
+		return fmt.Errorf("order pay link URL must contain {token}")
+	}
+
+	// Validate tax calculation
+	if c.Tax.Mode != "immediate" && c.Tax.Mode != "deferred" {
+		return fmt.Errorf("invalid tax mode: %s (must be immediate or deferred)", c.Tax.Mode)
+	}
+	if len(c.Tax.ShipFromCountry) != 2 {
+		return fmt.Errorf("invalid tax ship-from country: %q (must be an ISO alpha-2 code)", c.Tax.ShipFromCountry)
+	}
+
+	// Validate available-to-promise
+	if c.Inventory.InboundHorizon < 0 {
+		return fmt.Errorf("inventory inbound horizon cannot be negative")
+	}
+
+	if c.Inventory.FlashReconcileInterval < 0 {
+		return fmt.Errorf("inventory flash reconcile interval cannot be negative")
+	}
+
+	if c.Inventory.BackInStockAt <= c.Inventory.OutOfStockAt {
+		return fmt.Errorf("inventory back in stock threshold (%d) must be above the out of stock threshold (%d)", c.Inventory.BackInStockAt, c.Inventory.OutOfStockAt)
+	}
+
+	// Validate sales channels
+	if c.Inventory.ReservationSweepInterval <= 0 {
+		return fmt.Errorf("inventory reservation sweep interval must be positive")
+	}
+	channelKeys := make(map[string]string, len(c.Integrations.Channels))
+	for code, channel := range c.Integrations.Channels {
+		if channel.APIKey == "" {
+			return fmt.Errorf("sales channel %s requires an API key", code)
+		}
+		if other, ok := channelKeys[channel.APIKey]; ok {
+			return fmt.Errorf("sales channels %s and %s share an API key", other, code)
+		}
+		channelKeys[channel.APIKey] = code
+		if channel.OrderFormat != "" && channel.OrderFormat != "generic" && channel.OrderFormat != "ebay" {
+			return fmt.Errorf("invalid order format of sales channel %s: %q (must be generic or ebay)", code, channel.OrderFormat)
+		}
+	}
+	for code, channel := range c.Inventory.Channels {
+		if _, ok := c.Integrations.Channels[code]; !ok {
+			return fmt.Errorf("inventory sales channel %s is not defined in integrations", code)
+		}
+		if channel.AllocationPercent < 1 || channel.AllocationPercent > 100 {
+			return fmt.Errorf("invalid allocation percent of sales channel %s: %d (must be 1-100)", code, channel.AllocationPercent)
+		}
+		if channel.MaxQuantity < 0 {
+			return fmt.Errorf("max quantity of sales channel %s cannot be negative", code)
+		}
+		for skuID, limit := range channel.SKUCaps {
+			if limit < 0 {
+				return fmt.Errorf("cap of SKU %s on sales channel %s cannot be negative", skuID, code)
+			}
+		}
+		if channel.ReservationTTL <= 0 || channel.MaxReservationTTL < 0 ||
+			(channel.MaxReservationTTL > 0 && channel.ReservationTTL > channel.MaxReservationTTL) {
+			return fmt.Errorf("sales channel %s requires a positive reservation TTL within its max reservation TTL", code)
+		}
+	}
+
+	// Validate rate limits
+	if c.RateLimit.Enabled && (c.RateLimit.Requests <= 0 || c.RateLimit.Window <= 0) {
+		return fmt.Errorf("rate limit requests and window must be positive when enabled")
+	}
+	if c.RateLimit.CustomerRequests < 0 {
+		return fmt.Errorf("rate limit customer requests must not be negative")
+	}
+	buckets := map[string]bool{ratelimit.DefaultBucket: true}
+	for _, bucket := range c.RateLimit.Buckets {
+		if bucket.Name == "" || buckets[bucket.Name] {
+			return fmt.Errorf("rate limit bucket names must be unique and not empty or default")
+		}
+		buckets[bucket.Name] = true
+		if bucket.Requests <= 0 || bucket.CustomerRequests < 0 || bucket.Window <= 0 || len(bucket.Routes) == 0 {
+			return fmt.Errorf("rate limit bucket %s requires positive requests and window and at least one route", bucket.Name)
+		}
+	}
+	partnerKeys := make(map[string]string, len(c.RateLimit.Partners))
+	for partner, p := range c.RateLimit.Partners {
+		if p.APIKey == "" {
+			return fmt.Errorf("rate limit partner %s requires an API key", partner)
+		}
+		if other, ok := partnerKeys[p.APIKey]; ok {
+			return fmt.Errorf("rate limit partners %s and %s share an API key", other, partner)
+		}
+		partnerKeys[p.APIKey] = partner
+		if p.Requests < 0 {
+			return fmt.Errorf("rate limit partner %s requests must not be negative", partner)
+		}
+		for name, requests := range p.Buckets {
+			if name == ratelimit.DefaultBucket || !buckets[name] || requests < 0 {
+				return fmt.Errorf("rate limit partner %s has an unknown bucket %s or a negative limit", partner, name)
+			}
+		}
+	}
+
+	// Validate waiting room
+	if c.WaitingRoom.Enabled {
+		if c.WaitingRoom.MaxActive <= 0 || c.WaitingRoom.AdmitPerMinute <= 0 {
+			return fmt.Errorf("waiting room max active and admit per minute must be positive when enabled")
+		}
+		if c.WaitingRoom.SessionTTL <= 0 || c.WaitingRoom.TicketTTL <= 0 {
+			return fmt.Errorf("waiting room session and ticket TTLs must be positive when enabled")
+		}
+		for _, route := range c.WaitingRoom.Routes {
+			if _, err := path.Match(route.Path, "/"); route.Path == "" || err != nil {
+				return fmt.Errorf("invalid waiting room route path: %q", route.Path)
+			}
+		}
+	}
+
+	// Validate load shedding
+	if c.LoadShed.MaxInFlight < 0 || c.LoadShed.RetryAfter < 0 {
+		return fmt.Errorf("load shedding max in flight and retry after must not be negative")
+	}
+	if c.LoadShed.LowThreshold < 0 || c.LoadShed.NormalThreshold < 0 ||
+		(c.LoadShed.NormalThreshold > 0 && c.LoadShed.LowThreshold > c.LoadShed.NormalThreshold) {
+		return fmt.Errorf("load shedding thresholds must not be negative, and the low threshold must not exceed the normal one")
+	}
+	loadShedGroups := make(map[string]bool, len(c.LoadShed.Groups))
+	for _, group := range c.LoadShed.Groups {
+		if group.Name == "" || loadShedGroups[group.Name] {
+			return fmt.Errorf("load shedding groups need a unique name: %q", group.Name)
+		}
+		loadShedGroups[group.Name] = true
+		if _, err := loadshed.ParsePriority(group.Priority); err != nil {
+			return fmt.Errorf("load shedding group %s: %w", group.Name, err)
+		}
+		if group.LatencyTarget < 0 {
+			return fmt.Errorf("load shedding group %s latency target must not be negative", group.Name)
+		}
+		for _, route := range group.Routes {
+			if !strings.HasPrefix(route.PathPrefix, "/") {
+				return fmt.Errorf("load shedding group %s has an invalid path prefix: %q", group.Name, route.PathPrefix)
+			}
+		}
+	}
+
+	// Validate cache key prefix; it becomes part of the patterns namespaces are flushed by
+	if strings.ContainsAny(c.Redis.KeyPrefix, "*?[]\\ ") {
+		return fmt.Errorf("redis key prefix cannot contain spaces or glob characters")
+	}
+
+	// Validate query cache
+	if c.QueryCache.RefreshAhead < 0 || (c.QueryCache.TTL > 0 && c.QueryCache.RefreshAhead >= c.QueryCache.TTL) {
+		return fmt.Errorf("query cache refresh-ahead must be shorter than its TTL")
+	}
+
+	// Validate event bus
+	switch c.EventBus.Driver {
+	case "memory":
+	case "redis":
+		if c.Redis.Host == "" {
+			return fmt.Errorf("the redis event bus requires a Redis host")
+		}
+		if c.EventBus.StreamPrefix == "" || c.EventBus.BlockTimeout <= 0 || c.EventBus.ClaimIdle <= 0 {
+			return fmt.Errorf("the redis event bus requires a stream prefix and positive block timeout and claim idle time")
+		}
+	default:
+		return fmt.Errorf("invalid event bus driver: %s (must be memory or redis)", c.EventBus.Driver)
+	}
+	if c.EventBus.MaxLen < 0 || c.EventBus.MetricsInterval < 0 {
+		return fmt.Errorf("event bus max length and metrics interval cannot be negative")
+	}
+	switch c.EventBus.SchemaMode {
+	case "off", "warn", "enforce":
+	default:
+		return fmt.Errorf("invalid event schema mode: %s (must be off, warn or enforce)", c.EventBus.SchemaMode)
+	}
+
+	// Validate audit retention
+	if c.Audit.Retention < 0 || c.Audit.ArchiveBatchSize < 0 {
+		return fmt.Errorf("audit retention and archive batch size cannot be negative")
+	}
+
+	// Validate request log
+	if c.RequestLog.ReadSampleRate < 0 || c.RequestLog.ReadSampleRate > 1 ||
+		c.RequestLog.WriteSampleRate < 0 || c.RequestLog.WriteSampleRate > 1 {
+		return fmt.Errorf("request log sample rates must be between 0 and 1")
+	}
+	if c.RequestLog.MaxBodyBytes < 0 || c.RequestLog.Retention < 0 || c.RequestLog.PurgeBatchSize < 0 || c.RequestLog.BufferSize < 0 {
+		return fmt.Errorf("request log body size, retention, purge batch size and buffer size cannot be negative")
+	}
+
+	// Validate exports
+	if c.Export.Retention < 0 || c.Export.MaxConcurrent < 0 || c.Export.ReportInterval < 0 {
+		return fmt.Errorf("export retention, max concurrent exports and report interval cannot be negative")
+	}
+	if c.Warehouse.Interval < 0 || c.Warehouse.BatchSize < 0 || c.Warehouse.UploadTimeout < 0 {
+		return fmt.Errorf("warehouse export interval, batch size and upload timeout cannot be negative")
+	}
+	if c.Attachments.Timeout < 0 || c.Attachments.CustomerUploads < 0 {
+		return fmt.Errorf("attachment timeout and customer uploads cannot be negative")
+	}
+	if c.Attachments.StorageURL != "" && c.Attachments.MaxSizeKiB <= 0 {
+		return fmt.Errorf("attachment max size must be positive")
+	}
+	if _, err := time.LoadLocation(c.Calendar.TimeZone); err != nil {
+		return fmt.Errorf("invalid calendar time zone %q: %w", c.Calendar.TimeZone, err)
+	}
+	if workdays, err := c.Calendar.Weekdays(); err != nil {
+		return fmt.Errorf("invalid calendar workdays: %w", err)
+	} else if len(workdays) == 0 {
+		return fmt.Errorf("the default calendar requires at least one workday")
+	}
+	if c.Calendar.CutoffHour < 0 || c.Calendar.CutoffHour > 23 {
+		return fmt.Errorf("calendar cutoff hour must be between 0 and 23")
+	}
+	if c.Calendar.CacheTTL < 0 || c.Calendar.ImportYears < 1 || c.Calendar.ImportMaxKiB < 1 {
+		return fmt.Errorf("calendar cache TTL cannot be negative, and iCal import years and size must be at least 1")
+	}
+	if c.Fulfillment.SLAShipWithinDays < 0 || c.Fulfillment.SLAAtRisk < 0 || c.Fulfillment.SLACheckInterval < 0 {
+		return fmt.Errorf("fulfillment SLA days, at-risk window and check interval cannot be negative")
+	}
+	if c.Documents.Workers < 1 || c.Documents.QueueSize < 0 || c.Documents.RenderTimeout < 0 {
+		return fmt.Errorf("document rendering needs at least one worker and a queue size and timeout that are not negative")
+	}
+
+	// Validate runtime flags
+	if c.Features.RefreshInterval <= 0 || c.Features.MaintenanceRetryAfter < 0 {
+		return fmt.Errorf("feature flag refresh interval must be positive and maintenance retry-after cannot be negative")
+	}
+	if c.Features.SettingsRefreshInterval <= 0 {
+		return fmt.Errorf("site settings refresh interval must be positive")
+	}
+	for key, value := range c.SiteSettingDefaults() {
+		if _, err := settings.Normalize(key, value); err != nil {
+			return fmt.Errorf("invalid default of site setting: %w", err)
+		}
+	}
+	for _, route := range c.Features.KillSwitchRoutes {
+		if route.PathPrefix == "" {
+			return fmt.Errorf("kill switch route of %s requires a path prefix", route.Flag)
+		}
+		if !featureflag.IsKnown(route.Flag) || route.Flag == featureflag.Maintenance {
+			return fmt.Errorf("invalid kill switch flag: %q", route.Flag)
+		}
+	}
+	if c.Order.QuoteValidity <= 0 {
+		return fmt.Errorf("order quote validity must be positive")
+	}
+
+	// Validate storefront localization
+	sites := map[string]SiteConfig{"": {
+		DefaultLocale:       c.Storefront.DefaultLocale,
+		DefaultCurrency:     c.Storefront.DefaultCurrency,
+		SupportedLocales:    c.Storefront.SupportedLocales,
+		SupportedCurrencies: c.Storefront.SupportedCurrencies,
+	}}
+	for id, site := range c.Storefront.Sites {
+		sites[id] = site
+	}
+	for id, site := range sites {
+		if !containsFold(site.SupportedLocales, site.DefaultLocale) {
+			return fmt.Errorf("storefront site %q default locale %s is not a supported locale", id, site.DefaultLocale)
+		}
+		if !containsFold(site.SupportedCurrencies, site.DefaultCurrency) {
+			return fmt.Errorf("storefront site %q default currency %s is not a supported currency", id, site.DefaultCurrency)
+		}
+	}
+	if _, err := c.Storefront.TimeZones(); err != nil {
+		return fmt.Errorf("storefront %w", err)
+	}
+	if c.Storefront.PreferenceCacheTTL < 0 {
+		return fmt.Errorf("storefront preference cache TTL cannot be negative")
+	}
+	if c.Storefront.CustomerCacheTTL < 0 || c.Storefront.CustomerMissTTL < 0 {
+		return fmt.Errorf("storefront customer cache TTLs cannot be negative")
+	}
+	if c.Storefront.DomainRefreshInterval <= 0 {
+		return fmt.Errorf("storefront domain refresh interval must be greater than 0")
+	}
+	if c.Storefront.BFFSectionTimeout < 0 || c.Storefront.BFFBestSellers < 0 || c.Storefront.BFFPromotions < 0 {
+		return fmt.Errorf("storefront composed page timeout and sizes cannot be negative")
+	}
+
+	// Validate money rules
+	validModes := map[string]bool{"": true, "half_up": true, "half_even": true, "up": true, "down": true}
+	for code, currency := range c.Money.Currencies {
+		if !validModes[currency.RoundingMode] {
+			return fmt.Errorf("invalid rounding mode for %s: %s (must be half_up, half_even, up, or down)", code, currency.RoundingMode)
+		}
+		if currency.DecimalPlaces < 0 || currency.RoundingIncrement < 0 {
+			return fmt.Errorf("decimal places and rounding increment for %s cannot be negative", code)
+		}
+	}
+	for locale, format := range c.Money.Locales {
+		if format.SymbolPosition != "before" && format.SymbolPosition != "after" {
+			return fmt.Errorf("invalid symbol position for %s: %s (must be before or after)", locale, format.SymbolPosition)
+		}
+	}
 
 	// Validate auth in production
 	if c.App.Environment == "production" {
@@ -253,6 +1691,14 @@ func (c *Config) DatabaseDSN() string {
 	)
 }
 
+// CacheKeyPrefix returns the prefix of the cache keys of this deployment
+func (c *Config) CacheKeyPrefix() string {
+	if c.Redis.KeyPrefix != "" {
+		return c.Redis.KeyPrefix
+	}
+	return cache.KeyPrefix(c.App.Name, c.App.Environment)
+}
+
 // RedisAddr returns the Redis address
 func (c *Config) RedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Redis.Host, c.Redis.Port)
@@ -262,3 +1708,13 @@ func (c *Config) RedisAddr() string {
 func (c *Config) ServerAddr() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
 }
+
+// containsFold checks if values contain s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
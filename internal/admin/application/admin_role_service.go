@@ -0,0 +1,345 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// adminRoleEntityType is the audit log entity type of admin roles
+const adminRoleEntityType = "admin_role"
+
+// AdminRoleService defines the application service for admin roles and permission bundles.
+type AdminRoleService interface {
+	// ListTemplates lists the predefined role templates.
+	ListTemplates(ctx context.Context) []*RoleTemplateDTO
+
+	// SeedTemplates creates a role for each template, or for the given template keys. Roles
+	// that already exist are given the template permissions they are missing.
+	SeedTemplates(ctx context.Context, keys []string, actorID string) ([]*AdminRoleDTO, error)
+
+	// ListRoles lists every admin role with its permissions.
+	ListRoles(ctx context.Context) ([]*AdminRoleDTO, error)
+
+	// GetRole retrieves an admin role with its permissions.
+	GetRole(ctx context.Context, roleID int64) (*AdminRoleDTO, error)
+
+	// CloneRole creates a new role with the permissions of an existing one.
+	CloneRole(ctx context.Context, roleID int64, req *CloneRoleRequest, actorID string) (*AdminRoleDTO, error)
+
+	// GetUserPermissions resolves the effective permissions of an admin user across its
+	// roles, optionally compared with another role.
+	GetUserPermissions(ctx context.Context, adminUserID int64, compareRoleID *int64) (*UserPermissionsDTO, error)
+
+	// HasPermission checks whether an admin user holds a permission through a role or a direct
+	// grant. The user is given by the ID authenticated requests carry.
+	HasPermission(ctx context.Context, userID string, permission string) (bool, error)
+}
+
+type adminRoleService struct {
+	roleRepo     domain.AdminRoleRepository
+	userRepo     domain.AdminUserRepository
+	auditService *audit.AuditService
+	log          *logger.Logger
+}
+
+// NewAdminRoleService creates a new instance of AdminRoleService.
+func NewAdminRoleService(
+	roleRepo domain.AdminRoleRepository,
+	userRepo domain.AdminUserRepository,
+	auditService *audit.AuditService,
+	log *logger.Logger,
+) AdminRoleService {
+	return &adminRoleService{
+		roleRepo:     roleRepo,
+		userRepo:     userRepo,
+		auditService: auditService,
+		log:          log,
+	}
+}
+
+func (s *adminRoleService) ListTemplates(ctx context.Context) []*RoleTemplateDTO {
+	templates := domain.RoleTemplates()
+	dtos := make([]*RoleTemplateDTO, len(templates))
+	for i, template := range templates {
+		dtos[i] = ToRoleTemplateDTO(template)
+	}
+	return dtos
+}
+
+func (s *adminRoleService) SeedTemplates(ctx context.Context, keys []string, actorID string) ([]*AdminRoleDTO, error) {
+	templates := domain.RoleTemplates()
+	if len(keys) > 0 {
+		templates = templates[:0:0]
+		for _, key := range keys {
+			template, ok := domain.FindRoleTemplate(key)
+			if !ok {
+				return nil, errors.ValidationError("unknown role template: " + key)
+			}
+			templates = append(templates, template)
+		}
+	}
+
+	seeded := make([]*AdminRoleDTO, 0, len(templates))
+	for _, template := range templates {
+		role, err := s.seedTemplate(ctx, template, actorID)
+		if err != nil {
+			return nil, err
+		}
+		seeded = append(seeded, ToAdminRoleDTO(role))
+	}
+	return seeded, nil
+}
+
+// seedTemplate creates the template's role, or tops up the permissions of an existing role of the same name
+func (s *adminRoleService) seedTemplate(ctx context.Context, template domain.RoleTemplate, actorID string) (*domain.AdminRole, error) {
+	existing, err := s.roleRepo.FindByName(ctx, template.RoleName)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if existing == nil {
+		role, err := template.NewRole()
+		if err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+		if err := s.roleRepo.Create(ctx, role); err != nil {
+			return nil, err
+		}
+		s.recordChange(ctx, audit.AuditActionCreate, role.ID, actorID, map[string]interface{}{
+			"template":    template.Key,
+			"permissions": role.PermissionNames(),
+		})
+		return s.roleRepo.FindByID(ctx, role.ID)
+	}
+
+	held := make(map[string]bool, len(existing.Permissions))
+	for _, permission := range existing.Permissions {
+		held[permission.Name] = true
+	}
+	var missing []*domain.AdminPermission
+	var missingNames []string
+	for _, permission := range template.AdminPermissions() {
+		if !held[permission.Name] {
+			missing = append(missing, permission)
+			missingNames = append(missingNames, permission.Name)
+		}
+	}
+	if len(missing) == 0 {
+		return existing, nil
+	}
+	if err := s.roleRepo.AddPermissions(ctx, existing.ID, missing); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, audit.AuditActionUpdate, existing.ID, actorID, map[string]interface{}{
+		"template":          template.Key,
+		"added_permissions": missingNames,
+	})
+	return s.roleRepo.FindByID(ctx, existing.ID)
+}
+
+func (s *adminRoleService) ListRoles(ctx context.Context) ([]*AdminRoleDTO, error) {
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*AdminRoleDTO, len(roles))
+	for i, role := range roles {
+		dtos[i] = ToAdminRoleDTO(role)
+	}
+	return dtos, nil
+}
+
+func (s *adminRoleService) GetRole(ctx context.Context, roleID int64) (*AdminRoleDTO, error) {
+	role, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	return ToAdminRoleDTO(role), nil
+}
+
+func (s *adminRoleService) CloneRole(ctx context.Context, roleID int64, req *CloneRoleRequest, actorID string) (*AdminRoleDTO, error) {
+	source, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.roleRepo.FindByName(ctx, req.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if taken != nil {
+		return nil, errors.Conflict("an admin role named " + req.Name + " already exists")
+	}
+
+	role, err := source.Clone(req.Name, req.Description)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, audit.AuditActionCreate, role.ID, actorID, map[string]interface{}{
+		"cloned_from": source.ID,
+		"permissions": role.PermissionNames(),
+	})
+
+	created, err := s.roleRepo.FindByID(ctx, role.ID)
+	if err != nil {
+		return nil, err
+	}
+	return ToAdminRoleDTO(created), nil
+}
+
+func (s *adminRoleService) GetUserPermissions(ctx context.Context, adminUserID int64, compareRoleID *int64) (*UserPermissionsDTO, error) {
+	if _, err := s.userRepo.FindByID(ctx, adminUserID); err != nil {
+		return nil, err
+	}
+	roles, err := s.roleRepo.FindByAdminUserID(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	direct, err := s.roleRepo.FindUserPermissions(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Which roles grant each permission; "direct" marks grants outside any role
+	grants := make(map[string]*EffectivePermissionDTO)
+	grant := func(permission *domain.AdminPermission, source string) {
+		effective, ok := grants[permission.Name]
+		if !ok {
+			effective = &EffectivePermissionDTO{
+				Name:        permission.Name,
+				Description: permission.Description,
+				Type:        string(permission.Type),
+			}
+			grants[permission.Name] = effective
+		}
+		effective.GrantedBy = append(effective.GrantedBy, source)
+	}
+	for _, role := range roles {
+		for _, permission := range role.Permissions {
+			grant(permission, role.Name)
+		}
+	}
+	for _, permission := range direct {
+		grant(permission, directPermissionSource)
+	}
+
+	dto := &UserPermissionsDTO{
+		AdminUserID:       adminUserID,
+		Roles:             make([]*UserRoleDTO, len(roles)),
+		DirectPermissions: make([]string, 0, len(direct)),
+		Effective:         make([]*EffectivePermissionDTO, 0, len(grants)),
+	}
+	for _, permission := range direct {
+		dto.DirectPermissions = append(dto.DirectPermissions, permission.Name)
+	}
+	for i, role := range roles {
+		// Permissions no other role or direct grant covers are lost if the role is removed
+		unique := []string{}
+		for _, name := range role.PermissionNames() {
+			if len(grants[name].GrantedBy) == 1 {
+				unique = append(unique, name)
+			}
+		}
+		dto.Roles[i] = &UserRoleDTO{
+			AdminRoleDTO:      ToAdminRoleDTO(role),
+			UniquePermissions: unique,
+		}
+	}
+	for _, effective := range grants {
+		dto.Effective = append(dto.Effective, effective)
+	}
+	sort.Slice(dto.Effective, func(i, j int) bool { return dto.Effective[i].Name < dto.Effective[j].Name })
+
+	if compareRoleID != nil {
+		compareRole, err := s.roleRepo.FindByID(ctx, *compareRoleID)
+		if err != nil {
+			return nil, err
+		}
+		dto.Comparison = diffRolePermissions(compareRole, grants)
+	}
+	return dto, nil
+}
+
+func (s *adminRoleService) HasPermission(ctx context.Context, userID string, permission string) (bool, error) {
+	adminUserID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return false, nil
+	}
+
+	roles, err := s.roleRepo.FindByAdminUserID(ctx, adminUserID)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		for _, granted := range role.Permissions {
+			if granted.Name == permission {
+				return true, nil
+			}
+		}
+	}
+
+	direct, err := s.roleRepo.FindUserPermissions(ctx, adminUserID)
+	if err != nil {
+		return false, err
+	}
+	for _, granted := range direct {
+		if granted.Name == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// diffRolePermissions compares a role's permissions with a user's effective permissions
+func diffRolePermissions(role *domain.AdminRole, grants map[string]*EffectivePermissionDTO) *RoleComparisonDTO {
+	comparison := &RoleComparisonDTO{
+		RoleID:   role.ID,
+		RoleName: role.Name,
+		Shared:   []string{},
+		OnlyRole: []string{},
+		OnlyUser: []string{},
+	}
+	inRole := make(map[string]bool, len(role.Permissions))
+	for _, name := range role.PermissionNames() {
+		inRole[name] = true
+		if _, ok := grants[name]; ok {
+			comparison.Shared = append(comparison.Shared, name)
+		} else {
+			comparison.OnlyRole = append(comparison.OnlyRole, name)
+		}
+	}
+	for name := range grants {
+		if !inRole[name] {
+			comparison.OnlyUser = append(comparison.OnlyUser, name)
+		}
+	}
+	sort.Strings(comparison.OnlyUser)
+	return comparison
+}
+
+func (s *adminRoleService) recordChange(ctx context.Context, action audit.AuditAction, roleID int64, actorID string, changes map[string]interface{}) {
+	var userID *string
+	if actorID != "" {
+		userID = &actorID
+	}
+	entityID := strconv.FormatInt(roleID, 10)
+
+	var err error
+	if action == audit.AuditActionCreate {
+		err = s.auditService.LogCreate(ctx, adminRoleEntityType, entityID, userID, changes)
+	} else {
+		err = s.auditService.LogUpdate(ctx, adminRoleEntityType, entityID, userID, changes)
+	}
+	if err != nil {
+		s.log.WithError(err).WithField("admin_role_id", roleID).Warn("failed to record admin role change")
+	}
+}
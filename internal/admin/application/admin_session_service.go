@@ -0,0 +1,223 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/auth"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// activityTouchInterval limits how often session activity is written back
+const activityTouchInterval = time.Minute
+
+// adminRole is the role granted to admin API tokens
+const adminRole = "admin"
+
+// AdminSessionService defines the application service for admin logins and sessions.
+type AdminSessionService interface {
+	// Login authenticates an admin user and starts a new session.
+	Login(ctx context.Context, req *LoginRequest, device, ipAddress string) (*LoginResponse, error)
+
+	// ListSessions lists the sessions of an admin user.
+	ListSessions(ctx context.Context, adminUserID int64, activeOnly bool, currentSessionID string) ([]*AdminSessionDTO, error)
+
+	// RevokeSession revokes a session of an admin user and blacklists its token.
+	RevokeSession(ctx context.Context, adminUserID int64, sessionID string) error
+
+	// RevokeAllSessions revokes every active session of an admin user except keepSessionID.
+	RevokeAllSessions(ctx context.Context, adminUserID int64, keepSessionID string) (int, error)
+
+	// ValidateSession checks that the session behind a token is still active and records activity.
+	ValidateSession(ctx context.Context, claims *auth.Claims) error
+}
+
+type adminSessionService struct {
+	userRepo        domain.AdminUserRepository
+	sessionRepo     domain.AdminSessionRepository
+	jwtService      *auth.JWTService
+	passwordService *auth.PasswordService
+	blacklist       *auth.TokenBlacklist
+	auditService    *audit.AuditService
+	idleTimeout     time.Duration
+	log             *logger.Logger
+}
+
+// NewAdminSessionService creates a new instance of AdminSessionService.
+func NewAdminSessionService(
+	userRepo domain.AdminUserRepository,
+	sessionRepo domain.AdminSessionRepository,
+	jwtService *auth.JWTService,
+	passwordService *auth.PasswordService,
+	blacklist *auth.TokenBlacklist,
+	auditService *audit.AuditService,
+	idleTimeout time.Duration,
+	log *logger.Logger,
+) AdminSessionService {
+	return &adminSessionService{
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		jwtService:      jwtService,
+		passwordService: passwordService,
+		blacklist:       blacklist,
+		auditService:    auditService,
+		idleTimeout:     idleTimeout,
+		log:             log,
+	}
+}
+
+func (s *adminSessionService) Login(ctx context.Context, req *LoginRequest, device, ipAddress string) (*LoginResponse, error) {
+	loginFailed := func(reason string, userID *string) error {
+		s.recordSecurityEvent(ctx, audit.AuditActionLoginFailed, userID, &req.Login, ipAddress, device, map[string]interface{}{"reason": reason})
+		return errors.Unauthorized("invalid login or password")
+	}
+
+	user, err := s.userRepo.FindByLogin(ctx, req.Login)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, loginFailed("unknown_login", nil)
+		}
+		return nil, err
+	}
+	userID := strconv.FormatInt(user.ID, 10)
+	if !user.CanSignIn() {
+		return nil, loginFailed("account_disabled", &userID)
+	}
+	if err := s.passwordService.VerifyPassword(user.PasswordHash, req.Password); err != nil {
+		return nil, loginFailed("invalid_password", &userID)
+	}
+
+	session, err := domain.NewAdminSession(user.ID, device, ipAddress)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	token, expiresAt, err := s.jwtService.GenerateSessionToken(session.ID, userID, user.Email, []string{adminRole})
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to generate token")
+	}
+	session.ExpiresAt = expiresAt
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.log.WithField("admin_user_id", user.ID).WithField("session_id", session.ID).Info("admin signed in")
+	s.recordSecurityEvent(ctx, audit.AuditActionLogin, &userID, &user.Email, ipAddress, device, map[string]interface{}{"session_id": session.ID})
+
+	return &LoginResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		Session:   ToAdminSessionDTO(session, session.ID),
+	}, nil
+}
+
+func (s *adminSessionService) ListSessions(ctx context.Context, adminUserID int64, activeOnly bool, currentSessionID string) ([]*AdminSessionDTO, error) {
+	sessions, err := s.sessionRepo.FindByAdminUserID(ctx, adminUserID, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dtos := make([]*AdminSessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		// The repository cannot apply the idle timeout, so idle sessions are dropped here
+		if activeOnly && session.IsIdle(now, s.idleTimeout) {
+			continue
+		}
+		dtos = append(dtos, ToAdminSessionDTO(session, currentSessionID))
+	}
+	return dtos, nil
+}
+
+func (s *adminSessionService) RevokeSession(ctx context.Context, adminUserID int64, sessionID string) error {
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	// Sessions of other admin users are not found rather than forbidden, so
+	// session IDs cannot be probed
+	if session.AdminUserID != adminUserID {
+		return errors.NotFound("admin session")
+	}
+	return s.revoke(ctx, session)
+}
+
+func (s *adminSessionService) RevokeAllSessions(ctx context.Context, adminUserID int64, keepSessionID string) (int, error) {
+	sessions, err := s.sessionRepo.FindByAdminUserID(ctx, adminUserID, true)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.ID == keepSessionID {
+			continue
+		}
+		if err := s.revoke(ctx, session); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+func (s *adminSessionService) ValidateSession(ctx context.Context, claims *auth.Claims) error {
+	// The blacklist answers the common revoked case without a database round trip
+	revoked, err := s.blacklist.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		s.log.WithError(err).Warn("failed to check token blacklist, falling back to session store")
+	} else if revoked {
+		return errors.Unauthorized("session has been revoked")
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, claims.ID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return errors.Unauthorized("session not found")
+		}
+		return err
+	}
+
+	now := time.Now()
+	if !session.IsActive(now, s.idleTimeout) {
+		return errors.Unauthorized("session is no longer active")
+	}
+
+	if now.Sub(session.LastActivityAt) >= activityTouchInterval {
+		if err := s.sessionRepo.UpdateLastActivity(ctx, session.ID, now); err != nil {
+			// Activity tracking must not block the request
+			s.log.WithError(err).WithField("session_id", session.ID).Warn("failed to record session activity")
+		}
+	}
+	return nil
+}
+
+func (s *adminSessionService) revoke(ctx context.Context, session *domain.AdminSession) error {
+	if err := session.Revoke(); err != nil {
+		return errors.Conflict(err.Error())
+	}
+	if err := s.sessionRepo.Revoke(ctx, session.ID, *session.RevokedAt); err != nil {
+		return err
+	}
+	if err := s.blacklist.Revoke(ctx, session.ID, session.ExpiresAt); err != nil {
+		// The session store still rejects the token, only the fast path is lost
+		s.log.WithError(err).WithField("session_id", session.ID).Warn("failed to blacklist revoked session token")
+	}
+
+	s.log.WithField("admin_user_id", session.AdminUserID).WithField("session_id", session.ID).Info("admin session revoked")
+	userID := strconv.FormatInt(session.AdminUserID, 10)
+	s.recordSecurityEvent(ctx, audit.AuditActionSessionRevoked, &userID, nil, "", "", map[string]interface{}{"session_id": session.ID})
+	return nil
+}
+
+// recordSecurityEvent writes a security event to the audit log; failures never block sign-in
+func (s *adminSessionService) recordSecurityEvent(ctx context.Context, action audit.AuditAction, userID, username *string, ipAddress, userAgent string, metadata map[string]interface{}) {
+	if err := s.auditService.LogSecurityEvent(ctx, action, userID, username, ipAddress, userAgent, metadata); err != nil {
+		s.log.WithError(err).WithField("action", action).Warn("failed to record admin security event")
+	}
+}
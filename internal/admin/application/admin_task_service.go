@@ -0,0 +1,420 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const (
+	// adminTaskEntityType is the audit log entity type of admin tasks
+	adminTaskEntityType = "admin_task"
+
+	// defaultAdminTaskPageSize is used when an inbox query does not set a limit
+	defaultAdminTaskPageSize = 50
+	// maxAdminTaskPageSize caps the tasks returned per inbox page
+	maxAdminTaskPageSize = 200
+)
+
+// AdminTaskService keeps the inbox of tasks raised by workflows needing an admin
+// decision, such as return approvals, fraud reviews, catalog publishing and
+// stocktake variances. A task is assigned to an admin user or to the members of a
+// role; an assignee claims it, then completes it with an outcome. Workflows learn
+// the outcome from the admin.task.completed event.
+type AdminTaskService interface {
+	// CreateTask raises a task for a workflow. While a task of the same type about
+	// the same record is open or claimed, that task is returned instead, so a
+	// workflow can raise its task again safely.
+	CreateTask(ctx context.Context, req *CreateAdminTaskRequest, createdBy string) (*AdminTaskDTO, error)
+
+	// CancelTasks cancels the open and claimed tasks of a type about a record, once
+	// the workflow resolved it another way.
+	CancelTasks(ctx context.Context, taskType, entityType, entityID, note string) error
+
+	// ListTasks lists the tasks assigned to the admin user or to one of their roles.
+	ListTasks(ctx context.Context, query *AdminTaskQuery, actorID string) (*AdminTaskPageDTO, error)
+
+	// GetTask retrieves a task the admin user is, or was, an assignee of.
+	GetTask(ctx context.Context, taskID int64, actorID string) (*AdminTaskDTO, error)
+
+	// ClaimTask takes an open task for the admin user.
+	ClaimTask(ctx context.Context, taskID int64, actorID string) (*AdminTaskDTO, error)
+
+	// ReleaseTask puts a task claimed by the admin user back in the inbox.
+	ReleaseTask(ctx context.Context, taskID int64, actorID string) (*AdminTaskDTO, error)
+
+	// CompleteTask closes a task claimed by the admin user with an outcome.
+	CompleteTask(ctx context.Context, taskID int64, req *CompleteAdminTaskRequest, actorID string) (*AdminTaskDTO, error)
+
+	// AssignTask reassigns a task of the admin user to another admin user or role.
+	AssignTask(ctx context.Context, taskID int64, req *AssignAdminTaskRequest, actorID string) (*AdminTaskDTO, error)
+}
+
+type adminTaskService struct {
+	taskRepo     domain.AdminTaskRepository
+	roleRepo     domain.AdminRoleRepository
+	userRepo     domain.AdminUserRepository
+	eventBus     event.Bus
+	auditService *audit.AuditService
+	log          *logger.Logger
+}
+
+// NewAdminTaskService creates a new instance of AdminTaskService
+func NewAdminTaskService(
+	taskRepo domain.AdminTaskRepository,
+	roleRepo domain.AdminRoleRepository,
+	userRepo domain.AdminUserRepository,
+	eventBus event.Bus,
+	auditService *audit.AuditService,
+	log *logger.Logger,
+) AdminTaskService {
+	return &adminTaskService{
+		taskRepo:     taskRepo,
+		roleRepo:     roleRepo,
+		userRepo:     userRepo,
+		eventBus:     eventBus,
+		auditService: auditService,
+		log:          log,
+	}
+}
+
+func (s *adminTaskService) CreateTask(ctx context.Context, req *CreateAdminTaskRequest, createdBy string) (*AdminTaskDTO, error) {
+	task, err := domain.NewAdminTask(req.Type, req.Title, req.EntityType, req.EntityID, req.DueAt, createdBy)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if task.EntityID != "" {
+		existing, err := s.taskRepo.FindActiveByEntity(ctx, task.Type, task.EntityType, task.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 {
+			return ToAdminTaskDTO(existing[0]), nil
+		}
+	}
+
+	roleID := req.RoleID
+	if roleID == nil && req.RoleName != "" {
+		role, err := s.roleRepo.FindByName(ctx, req.RoleName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, errors.ValidationError(fmt.Sprintf("admin role %q does not exist", req.RoleName))
+			}
+			return nil, err
+		}
+		roleID = &role.ID
+	}
+	if err := s.checkAssignees(ctx, roleID, req.UserID); err != nil {
+		return nil, err
+	}
+	if err := task.Assign(roleID, req.UserID); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	task.Description = req.Description
+	for key, value := range req.Data {
+		task.Data[key] = value
+	}
+
+	if err := s.taskRepo.Create(ctx, task); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, task, createdBy, audit.AuditActionCreate, map[string]interface{}{
+		"type":             task.Type,
+		"entity_type":      task.EntityType,
+		"entity_id":        task.EntityID,
+		"assigned_role_id": task.AssignedRoleID,
+		"assigned_user_id": task.AssignedUserID,
+		"due_at":           task.DueAt,
+	})
+	s.publish(ctx, domain.EventAdminTaskCreated, task)
+	return ToAdminTaskDTO(task), nil
+}
+
+func (s *adminTaskService) CancelTasks(ctx context.Context, taskType, entityType, entityID, note string) error {
+	tasks, err := s.taskRepo.FindActiveByEntity(ctx, taskType, entityType, entityID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := task.Cancel(note); err != nil {
+			return errors.Conflict(err.Error())
+		}
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return err
+		}
+		s.recordChange(ctx, task, "", audit.AuditActionUpdate, map[string]interface{}{
+			"status": task.Status,
+			"note":   task.Note,
+		})
+		s.publish(ctx, domain.EventAdminTaskCancelled, task)
+	}
+	return nil
+}
+
+func (s *adminTaskService) ListTasks(ctx context.Context, query *AdminTaskQuery, actorID string) (*AdminTaskPageDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs, err := s.roleIDs(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &domain.AdminTaskFilter{
+		AssigneeUserID:  adminUserID,
+		AssigneeRoleIDs: roleIDs,
+		Type:            query.Type,
+		EntityType:      query.EntityType,
+		EntityID:        query.EntityID,
+		Limit:           query.Limit,
+		Offset:          query.Offset,
+	}
+	for _, status := range query.Statuses {
+		if !domain.AdminTaskStatus(status).IsValid() {
+			return nil, errors.ValidationError(fmt.Sprintf("unknown task status %q", status))
+		}
+		filter.Statuses = append(filter.Statuses, domain.AdminTaskStatus(status))
+	}
+	if len(filter.Statuses) == 0 || query.OverdueOnly {
+		filter.Statuses = []domain.AdminTaskStatus{domain.AdminTaskStatusOpen, domain.AdminTaskStatusClaimed}
+	}
+	if query.ClaimedOnly {
+		filter.ClaimedBy = adminUserID
+	}
+	if query.OverdueOnly {
+		now := time.Now()
+		filter.DueBefore = &now
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAdminTaskPageSize
+	}
+	if filter.Limit > maxAdminTaskPageSize {
+		filter.Limit = maxAdminTaskPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	tasks, total, err := s.taskRepo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	page := &AdminTaskPageDTO{Tasks: make([]*AdminTaskDTO, 0, len(tasks)), Total: total}
+	for _, task := range tasks {
+		page.Tasks = append(page.Tasks, ToAdminTaskDTO(task))
+	}
+	return page, nil
+}
+
+func (s *adminTaskService) GetTask(ctx context.Context, taskID int64, actorID string) (*AdminTaskDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.findVisible(ctx, taskID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	return ToAdminTaskDTO(task), nil
+}
+
+func (s *adminTaskService) ClaimTask(ctx context.Context, taskID int64, actorID string) (*AdminTaskDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.findAssigned(ctx, taskID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := task.Claim(adminUserID); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, task, actorID, audit.AuditActionUpdate, map[string]interface{}{
+		"status":     task.Status,
+		"claimed_by": task.ClaimedBy,
+	})
+	return ToAdminTaskDTO(task), nil
+}
+
+func (s *adminTaskService) ReleaseTask(ctx context.Context, taskID int64, actorID string) (*AdminTaskDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.findVisible(ctx, taskID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := task.Release(adminUserID); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, task, actorID, audit.AuditActionUpdate, map[string]interface{}{
+		"status": task.Status,
+	})
+	return ToAdminTaskDTO(task), nil
+}
+
+func (s *adminTaskService) CompleteTask(ctx context.Context, taskID int64, req *CompleteAdminTaskRequest, actorID string) (*AdminTaskDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.findVisible(ctx, taskID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := task.Complete(adminUserID, req.Outcome, req.Note); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, task, actorID, audit.AuditActionUpdate, map[string]interface{}{
+		"status":  task.Status,
+		"outcome": task.Outcome,
+		"note":    task.Note,
+	})
+	s.publish(ctx, domain.EventAdminTaskCompleted, task)
+	return ToAdminTaskDTO(task), nil
+}
+
+func (s *adminTaskService) AssignTask(ctx context.Context, taskID int64, req *AssignAdminTaskRequest, actorID string) (*AdminTaskDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.findAssigned(ctx, taskID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAssignees(ctx, req.RoleID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	previousRoleID, previousUserID := task.AssignedRoleID, task.AssignedUserID
+	if err := task.Assign(req.RoleID, req.UserID); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, task, actorID, audit.AuditActionUpdate, map[string]interface{}{
+		"assigned_role_id":          task.AssignedRoleID,
+		"assigned_user_id":          task.AssignedUserID,
+		"previous_assigned_role_id": previousRoleID,
+		"previous_assigned_user_id": previousUserID,
+		"status":                    task.Status,
+	})
+	return ToAdminTaskDTO(task), nil
+}
+
+// findVisible loads a task the admin user is an assignee of, claimed or completed.
+// Other tasks are reported as missing.
+func (s *adminTaskService) findVisible(ctx context.Context, taskID, adminUserID int64) (*domain.AdminTask, error) {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ClaimedBy != nil && *task.ClaimedBy == adminUserID || task.CompletedBy != nil && *task.CompletedBy == adminUserID {
+		return task, nil
+	}
+	roleIDs, err := s.roleIDs(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsAssignedTo(adminUserID, roleIDs) {
+		return nil, errors.NotFound(fmt.Sprintf("admin task %d", taskID))
+	}
+	return task, nil
+}
+
+// findAssigned loads a task the admin user is currently an assignee of
+func (s *adminTaskService) findAssigned(ctx context.Context, taskID, adminUserID int64) (*domain.AdminTask, error) {
+	task, err := s.findVisible(ctx, taskID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs, err := s.roleIDs(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsAssignedTo(adminUserID, roleIDs) {
+		return nil, errors.Forbidden("the task is no longer assigned to you")
+	}
+	return task, nil
+}
+
+// checkAssignees rejects a role or admin user that does not exist
+func (s *adminTaskService) checkAssignees(ctx context.Context, roleID, userID *int64) error {
+	if roleID != nil {
+		if _, err := s.roleRepo.FindByID(ctx, *roleID); err != nil {
+			if errors.IsNotFound(err) {
+				return errors.ValidationError(fmt.Sprintf("admin role %d does not exist", *roleID))
+			}
+			return err
+		}
+	}
+	if userID != nil {
+		if _, err := s.userRepo.FindByID(ctx, *userID); err != nil {
+			if errors.IsNotFound(err) {
+				return errors.ValidationError(fmt.Sprintf("admin user %d does not exist", *userID))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *adminTaskService) roleIDs(ctx context.Context, adminUserID int64) ([]int64, error) {
+	roles, err := s.roleRepo.FindByAdminUserID(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	return roleIDs, nil
+}
+
+func (s *adminTaskService) recordChange(ctx context.Context, task *domain.AdminTask, actorID string, action audit.AuditAction, changes map[string]interface{}) {
+	var userID *string
+	if actorID != "" {
+		userID = &actorID
+	}
+	entityID := strconv.FormatInt(task.ID, 10)
+	var err error
+	if action == audit.AuditActionCreate {
+		err = s.auditService.LogCreate(ctx, adminTaskEntityType, entityID, userID, changes)
+	} else {
+		err = s.auditService.LogUpdate(ctx, adminTaskEntityType, entityID, userID, changes)
+	}
+	if err != nil {
+		s.log.WithError(err).WithField("admin_task_id", task.ID).Warn("failed to record admin task change")
+	}
+}
+
+func (s *adminTaskService) publish(ctx context.Context, eventType string, task *domain.AdminTask) {
+	if err := s.eventBus.Publish(ctx, domain.NewAdminTaskEvent(eventType, task)); err != nil {
+		s.log.WithError(err).WithField("admin_task_id", task.ID).Error("failed to publish admin task event")
+	}
+}
@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// defaultAuditLogPageSize is used when a query does not set a limit
+const defaultAuditLogPageSize = 50
+
+// AuditLogService defines the application service for browsing and retaining the admin audit log.
+type AuditLogService interface {
+	// SearchAuditLogs returns a page of audit entries matching the query.
+	SearchAuditLogs(ctx context.Context, query *AuditLogQuery) (*AuditLogPageDTO, error)
+
+	// SearchSecurityEvents returns a page of sign-in related entries matching the query.
+	SearchSecurityEvents(ctx context.Context, query *AuditLogQuery) (*AuditLogPageDTO, error)
+
+	// ArchiveExpired moves entries past the retention period to cold storage.
+	ArchiveExpired(ctx context.Context) (int64, error)
+
+	// StartScheduledArchival archives expired entries periodically until ctx is cancelled.
+	StartScheduledArchival(ctx context.Context, interval time.Duration)
+}
+
+type auditLogService struct {
+	repo      domain.AuditLogRepository
+	retention time.Duration
+	batchSize int
+	log       *logger.Logger
+
+	// archiveMu prevents overlapping archival runs
+	archiveMu sync.Mutex
+}
+
+// NewAuditLogService creates a new instance of AuditLogService. A zero retention keeps entries forever.
+func NewAuditLogService(repo domain.AuditLogRepository, retention time.Duration, batchSize int, log *logger.Logger) AuditLogService {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	return &auditLogService{repo: repo, retention: retention, batchSize: batchSize, log: log}
+}
+
+func (s *auditLogService) SearchAuditLogs(ctx context.Context, query *AuditLogQuery) (*AuditLogPageDTO, error) {
+	filter, err := query.toFilter()
+	if err != nil {
+		return nil, err
+	}
+	return s.search(ctx, filter)
+}
+
+func (s *auditLogService) SearchSecurityEvents(ctx context.Context, query *AuditLogQuery) (*AuditLogPageDTO, error) {
+	filter, err := query.toFilter()
+	if err != nil {
+		return nil, err
+	}
+	if len(filter.Actions) == 0 {
+		filter.Actions = audit.SecurityActions
+	} else if !isSecurityAction(filter.Actions[0]) {
+		return nil, errors.ValidationError(fmt.Sprintf("%s is not a security event action", filter.Actions[0]))
+	}
+	return s.search(ctx, filter)
+}
+
+func (s *auditLogService) ArchiveExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	if !s.archiveMu.TryLock() {
+		return 0, errors.Conflict("audit log archival already in progress")
+	}
+	defer s.archiveMu.Unlock()
+
+	cutoff := time.Now().Add(-s.retention)
+	var archived int64
+	for {
+		moved, err := s.repo.ArchiveBefore(ctx, cutoff, s.batchSize)
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive audit log: %w", err)
+		}
+		archived += moved
+		if moved < int64(s.batchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if archived > 0 {
+		s.log.WithField("archived", archived).WithField("cutoff", cutoff).Info("Archived expired audit log entries")
+	}
+	return archived, nil
+}
+
+func (s *auditLogService) StartScheduledArchival(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || s.retention <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.ArchiveExpired(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled audit log archival failed")
+				}
+			}
+		}
+	}()
+}
+
+func (s *auditLogService) search(ctx context.Context, filter *domain.AuditLogFilter) (*AuditLogPageDTO, error) {
+	page, err := s.repo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return ToAuditLogPageDTO(page), nil
+}
+
+func isSecurityAction(action audit.AuditAction) bool {
+	for _, a := range audit.SecurityActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,655 @@
+package application
+
+import (
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/requestlog"
+)
+
+// LoginRequest is the payload to sign in to the admin API
+type LoginRequest struct {
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// LoginResponse is returned after a successful admin login
+type LoginResponse struct {
+	Token     string           `json:"token"`
+	ExpiresAt time.Time        `json:"expires_at"`
+	Session   *AdminSessionDTO `json:"session"`
+}
+
+// AdminSessionDTO represents an admin session
+type AdminSessionDTO struct {
+	ID             string     `json:"id"`
+	AdminUserID    int64      `json:"admin_user_id"`
+	Device         string     `json:"device"`
+	IPAddress      string     `json:"ip_address"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastActivityAt time.Time  `json:"last_activity_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	Current        bool       `json:"current"`
+}
+
+// ToAdminSessionDTO converts a domain session to a DTO
+func ToAdminSessionDTO(session *domain.AdminSession, currentSessionID string) *AdminSessionDTO {
+	return &AdminSessionDTO{
+		ID:             session.ID,
+		AdminUserID:    session.AdminUserID,
+		Device:         session.Device,
+		IPAddress:      session.IPAddress,
+		CreatedAt:      session.CreatedAt,
+		LastActivityAt: session.LastActivityAt,
+		ExpiresAt:      session.ExpiresAt,
+		RevokedAt:      session.RevokedAt,
+		Current:        session.ID == currentSessionID,
+	}
+}
+
+// AuditLogQuery holds the filters of an audit log search
+type AuditLogQuery struct {
+	From       *time.Time
+	To         *time.Time
+	ActorID    string
+	EntityType string
+	EntityID   string
+	Action     string
+	IPAddress  string
+	Cursor     string
+	Limit      int
+}
+
+func (q *AuditLogQuery) toFilter() (*domain.AuditLogFilter, error) {
+	if q.From != nil && q.To != nil && q.To.Before(*q.From) {
+		return nil, errors.ValidationError("to must not be before from")
+	}
+
+	filter := &domain.AuditLogFilter{
+		From:       q.From,
+		To:         q.To,
+		ActorID:    q.ActorID,
+		EntityType: q.EntityType,
+		EntityID:   q.EntityID,
+		IPAddress:  q.IPAddress,
+		Limit:      q.Limit,
+	}
+	if q.Action != "" {
+		filter.Actions = []audit.AuditAction{audit.AuditAction(strings.ToUpper(q.Action))}
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLogPageSize
+	}
+	if filter.Limit > domain.MaxAuditLogPageSize {
+		filter.Limit = domain.MaxAuditLogPageSize
+	}
+	if q.Cursor != "" {
+		cursor, err := domain.DecodeAuditLogCursor(q.Cursor)
+		if err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+		filter.Cursor = cursor
+	}
+	return filter, nil
+}
+
+// AuditLogEntryDTO represents an audit log entry
+type AuditLogEntryDTO struct {
+	ID         string                 `json:"id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	ActorID    *string                `json:"actor_id,omitempty"`
+	ActorName  *string                `json:"actor_name,omitempty"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id,omitempty"`
+	Action     string                 `json:"action"`
+	IPAddress  *string                `json:"ip_address,omitempty"`
+	UserAgent  *string                `json:"user_agent,omitempty"`
+	Changes    map[string]interface{} `json:"changes,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// AuditLogPageDTO is a page of audit log entries
+type AuditLogPageDTO struct {
+	Entries    []*AuditLogEntryDTO `json:"entries"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	Total      int64               `json:"total"`
+}
+
+// ToAuditLogPageDTO converts a domain audit log page to a DTO
+func ToAuditLogPageDTO(page *domain.AuditLogPage) *AuditLogPageDTO {
+	dto := &AuditLogPageDTO{
+		Entries: make([]*AuditLogEntryDTO, len(page.Entries)),
+		Total:   page.Total,
+	}
+	for i, entry := range page.Entries {
+		dto.Entries[i] = &AuditLogEntryDTO{
+			ID:         entry.ID,
+			OccurredAt: entry.Timestamp,
+			ActorID:    entry.UserID,
+			ActorName:  entry.Username,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			Action:     string(entry.Action),
+			IPAddress:  entry.IPAddress,
+			UserAgent:  entry.UserAgent,
+			Changes:    entry.Changes,
+			Metadata:   entry.Metadata,
+		}
+	}
+	if page.NextCursor != nil {
+		dto.NextCursor = page.NextCursor.Encode()
+	}
+	return dto
+}
+
+// directPermissionSource marks permissions granted to an admin user outside any role
+const directPermissionSource = "direct"
+
+// CloneRoleRequest is the payload to clone an admin role
+type CloneRoleRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+// SeedRoleTemplatesRequest selects the role templates to seed; empty seeds all of them
+type SeedRoleTemplatesRequest struct {
+	Templates []string `json:"templates"`
+}
+
+// RoleTemplateDTO represents a predefined role template
+type RoleTemplateDTO struct {
+	Key         string   `json:"key"`
+	RoleName    string   `json:"role_name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// ToRoleTemplateDTO converts a role template to a DTO
+func ToRoleTemplateDTO(template domain.RoleTemplate) *RoleTemplateDTO {
+	return &RoleTemplateDTO{
+		Key:         template.Key,
+		RoleName:    template.RoleName,
+		Description: template.Description,
+		Permissions: template.Permissions,
+	}
+}
+
+// AdminPermissionDTO represents an admin permission
+type AdminPermissionDTO struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+}
+
+// AdminRoleDTO represents an admin role with its permissions
+type AdminRoleDTO struct {
+	ID          int64                 `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Permissions []*AdminPermissionDTO `json:"permissions"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// ToAdminRoleDTO converts a domain admin role to a DTO
+func ToAdminRoleDTO(role *domain.AdminRole) *AdminRoleDTO {
+	dto := &AdminRoleDTO{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: make([]*AdminPermissionDTO, len(role.Permissions)),
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+	for i, permission := range role.Permissions {
+		dto.Permissions[i] = &AdminPermissionDTO{
+			ID:          permission.ID,
+			Name:        permission.Name,
+			Description: permission.Description,
+			Type:        string(permission.Type),
+		}
+	}
+	return dto
+}
+
+// UserRoleDTO is a role held by an admin user. UniquePermissions are granted by
+// no other role of the user, so removing the role revokes them.
+type UserRoleDTO struct {
+	*AdminRoleDTO
+	UniquePermissions []string `json:"unique_permissions"`
+}
+
+// EffectivePermissionDTO is a permission an admin user holds, with the roles
+// granting it ("direct" for grants outside any role)
+type EffectivePermissionDTO struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	GrantedBy   []string `json:"granted_by"`
+}
+
+// RoleComparisonDTO compares a role's permissions with an admin user's effective permissions
+type RoleComparisonDTO struct {
+	RoleID   int64    `json:"role_id"`
+	RoleName string   `json:"role_name"`
+	Shared   []string `json:"shared"`
+	OnlyRole []string `json:"only_role"`
+	OnlyUser []string `json:"only_user"`
+}
+
+// UserPermissionsDTO shows the effective permissions of an admin user across its roles
+type UserPermissionsDTO struct {
+	AdminUserID       int64                     `json:"admin_user_id"`
+	Roles             []*UserRoleDTO            `json:"roles"`
+	DirectPermissions []string                  `json:"direct_permissions"`
+	Effective         []*EffectivePermissionDTO `json:"effective"`
+	Comparison        *RoleComparisonDTO        `json:"comparison,omitempty"`
+}
+
+// FeatureFlagDTO is the state of a runtime flag with its description
+type FeatureFlagDTO struct {
+	Key         string     `json:"key"`
+	Description string     `json:"description"`
+	Default     bool       `json:"default"`
+	Enabled     bool       `json:"enabled"`
+	Note        string     `json:"note,omitempty"`
+	UpdatedBy   string     `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// SetFeatureFlagRequest is the payload to switch a runtime flag
+type SetFeatureFlagRequest struct {
+	Enabled *bool  `json:"enabled" validate:"required"`
+	Note    string `json:"note"`
+}
+
+// SiteSettingDTO is the value of a site setting with its definition
+type SiteSettingDTO struct {
+	Key         string     `json:"key"`
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+	Default     string     `json:"default"`
+	Value       string     `json:"value"`
+	UpdatedBy   string     `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"` // Unset while the default applies
+}
+
+// SetSiteSettingRequest is the payload to change a site setting
+type SetSiteSettingRequest struct {
+	Value *string `json:"value" validate:"required"`
+}
+
+// SiteSettingChangeDTO is a change of a site setting recorded in the audit log
+type SiteSettingChangeDTO struct {
+	Value         string    `json:"value"`
+	PreviousValue string    `json:"previous_value"`
+	Reset         bool      `json:"reset,omitempty"` // Set back to the default
+	ChangedBy     string    `json:"changed_by,omitempty"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+// RateLimitUsageDTO is the storefront quota of a customer, partner or IP in each bucket
+type RateLimitUsageDTO struct {
+	Subject string                     `json:"subject"`
+	Buckets []*RateLimitBucketUsageDTO `json:"buckets"`
+}
+
+// RateLimitBucketUsageDTO is the quota of a subject in a bucket in the current window
+type RateLimitBucketUsageDTO struct {
+	Bucket    string                `json:"bucket"`
+	BaseLimit int                   `json:"base_limit"` // Configured limit, without override
+	Limit     int                   `json:"limit"`
+	Window    string                `json:"window"`
+	Used      *int64                `json:"used,omitempty"` // Omitted when the storefront counters cannot be read
+	Remaining *int64                `json:"remaining,omitempty"`
+	ResetAt   time.Time             `json:"reset_at"`
+	Override  *RateLimitOverrideDTO `json:"override,omitempty"`
+}
+
+// RateLimitOverrideDTO is a temporary raise of a subject's limit in a bucket
+type RateLimitOverrideDTO struct {
+	ID        int64     `json:"id"`
+	Subject   string    `json:"subject"`
+	Bucket    string    `json:"bucket"`
+	Requests  int       `json:"requests"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Note      string    `json:"note,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SetRateLimitOverrideRequest is the payload to raise the limit of a subject in
+// a bucket until a time, replacing its current override
+type SetRateLimitOverrideRequest struct {
+	Subject   string    `json:"subject" validate:"required"` // customer:<id>, key:<partner> or ip:<address>
+	Bucket    string    `json:"bucket"`                      // Empty raises the default bucket
+	Requests  int       `json:"requests" validate:"required,gt=0"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+	Note      string    `json:"note"`
+}
+
+// SavedViewRequest is the payload to save the filters and columns of an admin list
+type SavedViewRequest struct {
+	List    string            `json:"list" validate:"required,oneof=orders products customers"`
+	Name    string            `json:"name" validate:"required,max=255"`
+	Filters map[string]string `json:"filters"`
+	Columns []string          `json:"columns"`
+}
+
+// ShareSavedViewRequest is the payload to share a saved view; it replaces the roles
+// the view is shared with, so an empty list makes it private again
+type ShareSavedViewRequest struct {
+	RoleIDs []int64 `json:"role_ids"`
+}
+
+// SavedViewDTO represents a saved admin list view
+type SavedViewDTO struct {
+	ID            int64             `json:"id"`
+	AdminUserID   int64             `json:"admin_user_id"`
+	List          string            `json:"list"`
+	Name          string            `json:"name"`
+	Filters       map[string]string `json:"filters"`
+	Columns       []string          `json:"columns"`
+	SharedRoleIDs []int64           `json:"shared_role_ids"`
+	Owned         bool              `json:"owned"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// ToSavedViewDTO converts a domain saved view to a DTO, as seen by the given admin user
+func ToSavedViewDTO(view *domain.SavedView, adminUserID int64) *SavedViewDTO {
+	return &SavedViewDTO{
+		ID:            view.ID,
+		AdminUserID:   view.AdminUserID,
+		List:          string(view.List),
+		Name:          view.Name,
+		Filters:       view.Filters,
+		Columns:       view.Columns,
+		SharedRoleIDs: view.SharedRoleIDs,
+		Owned:         view.IsOwnedBy(adminUserID),
+		CreatedAt:     view.CreatedAt,
+		UpdatedAt:     view.UpdatedAt,
+	}
+}
+
+// RequestLogQuery holds the filters of a request log search
+type RequestLogQuery struct {
+	From       *time.Time
+	To         *time.Time
+	ActorID    string
+	SessionID  string
+	Method     string
+	PathPrefix string
+	Status     int
+	ErrorsOnly bool
+	IPAddress  string
+	Cursor     string
+	Limit      int
+}
+
+func (q *RequestLogQuery) toFilter() (*domain.RequestLogFilter, error) {
+	if q.From != nil && q.To != nil && q.To.Before(*q.From) {
+		return nil, errors.ValidationError("to must not be before from")
+	}
+
+	filter := &domain.RequestLogFilter{
+		From:       q.From,
+		To:         q.To,
+		ActorID:    q.ActorID,
+		SessionID:  q.SessionID,
+		Method:     strings.ToUpper(q.Method),
+		PathPrefix: q.PathPrefix,
+		Status:     q.Status,
+		ErrorsOnly: q.ErrorsOnly,
+		IPAddress:  q.IPAddress,
+		Limit:      q.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLogPageSize
+	}
+	if filter.Limit > domain.MaxAuditLogPageSize {
+		filter.Limit = domain.MaxAuditLogPageSize
+	}
+	if q.Cursor != "" {
+		cursor, err := domain.DecodeAuditLogCursor(q.Cursor)
+		if err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+		filter.Cursor = cursor
+	}
+	return filter, nil
+}
+
+// RequestLogEntryDTO represents a logged admin API request. Headers and
+// bodies are only filled in when a single entry is fetched.
+type RequestLogEntryDTO struct {
+	ID                string            `json:"id"`
+	OccurredAt        time.Time         `json:"occurred_at"`
+	Method            string            `json:"method"`
+	Path              string            `json:"path"`
+	Query             string            `json:"query,omitempty"`
+	Status            int               `json:"status"`
+	DurationMs        int64             `json:"duration_ms"`
+	ActorID           string            `json:"actor_id,omitempty"`
+	ActorEmail        string            `json:"actor_email,omitempty"`
+	SessionID         string            `json:"session_id,omitempty"`
+	IPAddress         string            `json:"ip_address,omitempty"`
+	UserAgent         string            `json:"user_agent,omitempty"`
+	CorrelationID     string            `json:"correlation_id,omitempty"`
+	RequestHeaders    map[string]string `json:"request_headers,omitempty"`
+	RequestBody       string            `json:"request_body,omitempty"`
+	ResponseBody      string            `json:"response_body,omitempty"`
+	RequestTruncated  bool              `json:"request_truncated"`
+	ResponseTruncated bool              `json:"response_truncated"`
+}
+
+// RequestLogPageDTO is a page of request log entries
+type RequestLogPageDTO struct {
+	Entries    []*RequestLogEntryDTO `json:"entries"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	Total      int64                 `json:"total"`
+}
+
+// ToRequestLogEntryDTO converts a logged request to a DTO
+func ToRequestLogEntryDTO(entry *requestlog.Entry) *RequestLogEntryDTO {
+	return &RequestLogEntryDTO{
+		ID:                entry.ID,
+		OccurredAt:        entry.OccurredAt,
+		Method:            entry.Method,
+		Path:              entry.Path,
+		Query:             entry.Query,
+		Status:            entry.Status,
+		DurationMs:        entry.DurationMs,
+		ActorID:           entry.ActorID,
+		ActorEmail:        entry.ActorEmail,
+		SessionID:         entry.SessionID,
+		IPAddress:         entry.IPAddress,
+		UserAgent:         entry.UserAgent,
+		CorrelationID:     entry.CorrelationID,
+		RequestHeaders:    entry.RequestHeaders,
+		RequestBody:       entry.RequestBody,
+		ResponseBody:      entry.ResponseBody,
+		RequestTruncated:  entry.RequestTruncated,
+		ResponseTruncated: entry.ResponseTruncated,
+	}
+}
+
+// ToRequestLogPageDTO converts a domain request log page to a DTO
+func ToRequestLogPageDTO(page *domain.RequestLogPage) *RequestLogPageDTO {
+	dto := &RequestLogPageDTO{
+		Entries: make([]*RequestLogEntryDTO, len(page.Entries)),
+		Total:   page.Total,
+	}
+	for i, entry := range page.Entries {
+		dto.Entries[i] = ToRequestLogEntryDTO(entry)
+	}
+	if page.NextCursor != nil {
+		dto.NextCursor = page.NextCursor.Encode()
+	}
+	return dto
+}
+
+// CreateAdminTaskRequest is the payload a workflow raises a task with. The task is
+// assigned to an admin user, or to a role given by ID or name.
+type CreateAdminTaskRequest struct {
+	Type        string            `json:"type" validate:"required,max=64"`
+	Title       string            `json:"title" validate:"required,max=255"`
+	Description string            `json:"description"`
+	EntityType  string            `json:"entity_type" validate:"max=64"`
+	EntityID    string            `json:"entity_id" validate:"max=255"`
+	Data        map[string]string `json:"data"`
+	RoleID      *int64            `json:"role_id"`
+	RoleName    string            `json:"role_name"`
+	UserID      *int64            `json:"user_id"`
+	DueAt       *time.Time        `json:"due_at"`
+}
+
+// AssignAdminTaskRequest is the payload to reassign a task to an admin user or role
+type AssignAdminTaskRequest struct {
+	RoleID *int64 `json:"role_id"`
+	UserID *int64 `json:"user_id"`
+}
+
+// CompleteAdminTaskRequest is the payload to complete a claimed task
+type CompleteAdminTaskRequest struct {
+	Outcome string `json:"outcome" validate:"max=64"`
+	Note    string `json:"note"`
+}
+
+// AdminTaskQuery holds the filters of an admin user's task inbox
+type AdminTaskQuery struct {
+	Statuses    []string // Defaults to the open and claimed tasks
+	Type        string
+	EntityType  string
+	EntityID    string
+	ClaimedOnly bool // Only the tasks the admin user claimed
+	OverdueOnly bool
+	Limit       int
+	Offset      int
+}
+
+// AdminTaskDTO represents a task in an admin inbox
+type AdminTaskDTO struct {
+	ID             int64             `json:"id"`
+	Type           string            `json:"type"`
+	Title          string            `json:"title"`
+	Description    string            `json:"description,omitempty"`
+	EntityType     string            `json:"entity_type,omitempty"`
+	EntityID       string            `json:"entity_id,omitempty"`
+	Data           map[string]string `json:"data"`
+	AssignedRoleID *int64            `json:"assigned_role_id,omitempty"`
+	AssignedUserID *int64            `json:"assigned_user_id,omitempty"`
+	Status         string            `json:"status"`
+	ClaimedBy      *int64            `json:"claimed_by,omitempty"`
+	ClaimedAt      *time.Time        `json:"claimed_at,omitempty"`
+	DueAt          *time.Time        `json:"due_at,omitempty"`
+	Overdue        bool              `json:"overdue"`
+	Outcome        string            `json:"outcome,omitempty"`
+	Note           string            `json:"note,omitempty"`
+	CompletedBy    *int64            `json:"completed_by,omitempty"`
+	CompletedAt    *time.Time        `json:"completed_at,omitempty"`
+	CreatedBy      string            `json:"created_by,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// AdminTaskPageDTO is a page of an admin user's tasks
+type AdminTaskPageDTO struct {
+	Tasks []*AdminTaskDTO `json:"tasks"`
+	Total int64           `json:"total"`
+}
+
+// ToAdminTaskDTO converts a domain admin task to a DTO
+func ToAdminTaskDTO(task *domain.AdminTask) *AdminTaskDTO {
+	return &AdminTaskDTO{
+		ID:             task.ID,
+		Type:           task.Type,
+		Title:          task.Title,
+		Description:    task.Description,
+		EntityType:     task.EntityType,
+		EntityID:       task.EntityID,
+		Data:           task.Data,
+		AssignedRoleID: task.AssignedRoleID,
+		AssignedUserID: task.AssignedUserID,
+		Status:         string(task.Status),
+		ClaimedBy:      task.ClaimedBy,
+		ClaimedAt:      task.ClaimedAt,
+		DueAt:          task.DueAt,
+		Overdue:        task.IsOverdue(time.Now()),
+		Outcome:        task.Outcome,
+		Note:           task.Note,
+		CompletedBy:    task.CompletedBy,
+		CompletedAt:    task.CompletedAt,
+		CreatedBy:      task.CreatedBy,
+		CreatedAt:      task.CreatedAt,
+		UpdatedAt:      task.UpdatedAt,
+	}
+}
+
+// ReportDTO describes a report admins can subscribe to
+type ReportDTO struct {
+	Name   string   `json:"name"`
+	Title  string   `json:"title"`
+	Params []string `json:"params"`
+}
+
+// ReportSubscriptionRequest is the payload to subscribe to a report. Weekday
+// (e.g. "monday") applies to weekly reports; hour is the hour of delivery in UTC.
+type ReportSubscriptionRequest struct {
+	Report     string            `json:"report" validate:"required"`
+	Params     map[string]string `json:"params"`
+	Frequency  string            `json:"frequency" validate:"required,oneof=daily weekly"`
+	Weekday    string            `json:"weekday"`
+	Hour       int               `json:"hour" validate:"min=0,max=23"`
+	Format     string            `json:"format" validate:"required,oneof=csv pdf"`
+	Recipients []string          `json:"recipients" validate:"required"`
+	Active     *bool             `json:"active"`
+}
+
+// ReportSubscriptionDTO represents a scheduled report subscription
+type ReportSubscriptionDTO struct {
+	ID          int64             `json:"id"`
+	AdminUserID int64             `json:"admin_user_id"`
+	Report      string            `json:"report"`
+	Params      map[string]string `json:"params"`
+	Frequency   string            `json:"frequency"`
+	Weekday     string            `json:"weekday,omitempty"`
+	Hour        int               `json:"hour"`
+	Format      string            `json:"format"`
+	Recipients  []string          `json:"recipients"`
+	Active      bool              `json:"active"`
+	NextRunAt   time.Time         `json:"next_run_at"`
+	LastRunAt   *time.Time        `json:"last_run_at,omitempty"`
+	LastJobID   string            `json:"last_job_id,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// ToReportSubscriptionDTO converts a domain report subscription to a DTO
+func ToReportSubscriptionDTO(sub *domain.ReportSubscription) *ReportSubscriptionDTO {
+	dto := &ReportSubscriptionDTO{
+		ID:          sub.ID,
+		AdminUserID: sub.AdminUserID,
+		Report:      sub.Report,
+		Params:      sub.Params,
+		Frequency:   string(sub.Frequency),
+		Hour:        sub.Hour,
+		Format:      string(sub.Format),
+		Recipients:  sub.Recipients,
+		Active:      sub.Active,
+		NextRunAt:   sub.NextRunAt,
+		LastRunAt:   sub.LastRunAt,
+		LastJobID:   sub.LastJobID,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+	if sub.Frequency == domain.ReportFrequencyWeekly {
+		dto.Weekday = strings.ToLower(sub.Weekday.String())
+	}
+	return dto
+}
@@ -0,0 +1,228 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/ratelimit"
+)
+
+const (
+	// rateLimitOverrideEntityType is the audit log entity type of rate limit overrides
+	rateLimitOverrideEntityType = "rate_limit_override"
+
+	// maxRateLimitOverride is how far ahead an override may expire; raises are temporary
+	maxRateLimitOverride = 30 * 24 * time.Hour
+)
+
+// RateLimitService inspects the storefront API quotas of customers, partner
+// API keys and IPs, and raises them temporarily, e.g. for a partner's bulk
+// sync. Storefront instances pick up overrides at their next refresh.
+type RateLimitService interface {
+	// GetUsage returns the limit and usage of a subject in each bucket.
+	GetUsage(ctx context.Context, subject string) (*RateLimitUsageDTO, error)
+
+	// ListOverrides lists the overrides not expired yet.
+	ListOverrides(ctx context.Context) ([]*RateLimitOverrideDTO, error)
+
+	// SetOverride raises the limit of a subject in a bucket until a time.
+	SetOverride(ctx context.Context, req *SetRateLimitOverrideRequest, actorID string) (*RateLimitOverrideDTO, error)
+
+	// RemoveOverride ends an override early.
+	RemoveOverride(ctx context.Context, id int64, actorID string) error
+}
+
+type rateLimitService struct {
+	quotas       *ratelimit.Quotas
+	policy       ratelimit.Policy
+	counters     ratelimit.Inspector
+	auditService *audit.AuditService
+	log          *logger.Logger
+}
+
+// NewRateLimitService creates a new instance of RateLimitService. counters
+// reads the storefront's shared counters; it is nil when the storefront counts
+// in memory, and usage is then reported without them.
+func NewRateLimitService(
+	quotas *ratelimit.Quotas,
+	policy ratelimit.Policy,
+	counters ratelimit.Inspector,
+	auditService *audit.AuditService,
+	log *logger.Logger,
+) RateLimitService {
+	return &rateLimitService{
+		quotas:       quotas,
+		policy:       policy,
+		counters:     counters,
+		auditService: auditService,
+		log:          log,
+	}
+}
+
+func (s *rateLimitService) GetUsage(ctx context.Context, subject string) (*RateLimitUsageDTO, error) {
+	if err := s.validateSubject(subject); err != nil {
+		return nil, err
+	}
+	// Read the store rather than the snapshot so overrides set by another admin instance show up
+	if err := s.quotas.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	overrides := make(map[string]*ratelimit.Override)
+	for _, override := range s.quotas.List() {
+		if override.Subject == subject {
+			overrides[override.Bucket] = override
+		}
+	}
+
+	usage := &RateLimitUsageDTO{Subject: subject, Buckets: make([]*RateLimitBucketUsageDTO, 0)}
+	now := time.Now()
+	for _, bucket := range s.policy.BucketNames() {
+		base, window, _ := s.policy.Limit(subject, bucket)
+		dto := &RateLimitBucketUsageDTO{
+			Bucket:    bucket,
+			BaseLimit: base,
+			Limit:     s.quotas.Limit(subject, bucket, base),
+			Window:    window.String(),
+			ResetAt:   now.Truncate(window).Add(window),
+		}
+		if override, ok := overrides[bucket]; ok {
+			dto.Override = toRateLimitOverrideDTO(override)
+		}
+		if s.counters != nil {
+			used, err := s.counters.Count(ctx, ratelimit.CounterKey(subject, bucket), window)
+			if err != nil {
+				return nil, errors.InternalWrap(err, "failed to read rate limit counters")
+			}
+			remaining := int64(dto.Limit) - used
+			if remaining < 0 {
+				remaining = 0
+			}
+			dto.Used, dto.Remaining = &used, &remaining
+		}
+		usage.Buckets = append(usage.Buckets, dto)
+	}
+	return usage, nil
+}
+
+func (s *rateLimitService) ListOverrides(ctx context.Context) ([]*RateLimitOverrideDTO, error) {
+	if err := s.quotas.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	overrides := s.quotas.List()
+	dtos := make([]*RateLimitOverrideDTO, 0, len(overrides))
+	for _, override := range overrides {
+		dtos = append(dtos, toRateLimitOverrideDTO(override))
+	}
+	return dtos, nil
+}
+
+func (s *rateLimitService) SetOverride(ctx context.Context, req *SetRateLimitOverrideRequest, actorID string) (*RateLimitOverrideDTO, error) {
+	if err := s.validateSubject(req.Subject); err != nil {
+		return nil, err
+	}
+	bucket := req.Bucket
+	if bucket == "" {
+		bucket = ratelimit.DefaultBucket
+	}
+	base, _, ok := s.policy.Limit(req.Subject, bucket)
+	if !ok {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown rate limit bucket %s", bucket))
+	}
+	if req.Requests <= 0 {
+		return nil, errors.ValidationError("requests must be positive")
+	}
+	now := time.Now()
+	if !req.ExpiresAt.After(now) || req.ExpiresAt.After(now.Add(maxRateLimitOverride)) {
+		return nil, errors.ValidationError(fmt.Sprintf("expires_at must be in the next %s", maxRateLimitOverride))
+	}
+
+	override, err := s.quotas.Set(ctx, &ratelimit.Override{
+		Subject:   req.Subject,
+		Bucket:    bucket,
+		Requests:  req.Requests,
+		ExpiresAt: req.ExpiresAt,
+		Note:      req.Note,
+		CreatedBy: actorID,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{
+		"subject":    override.Subject,
+		"bucket":     override.Bucket,
+		"requests":   override.Requests,
+		"base_limit": base,
+		"expires_at": override.ExpiresAt,
+	}
+	if override.Note != "" {
+		changes["note"] = override.Note
+	}
+	var userID *string
+	if actorID != "" {
+		userID = &actorID
+	}
+	if err := s.auditService.LogCreate(ctx, rateLimitOverrideEntityType, strconv.FormatInt(override.ID, 10), userID, changes); err != nil {
+		s.log.WithError(err).WithField("rate_limit_override_id", override.ID).Warn("failed to record rate limit override")
+	}
+	s.log.WithFields(logger.Fields{
+		"subject":    override.Subject,
+		"bucket":     override.Bucket,
+		"requests":   override.Requests,
+		"expires_at": override.ExpiresAt,
+		"created_by": actorID,
+	}).Info("Rate limit override set")
+	return toRateLimitOverrideDTO(override), nil
+}
+
+func (s *rateLimitService) RemoveOverride(ctx context.Context, id int64, actorID string) error {
+	deleted, err := s.quotas.Remove(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.NotFound("rate limit override")
+	}
+
+	var userID *string
+	if actorID != "" {
+		userID = &actorID
+	}
+	if err := s.auditService.LogDelete(ctx, rateLimitOverrideEntityType, strconv.FormatInt(id, 10), userID); err != nil {
+		s.log.WithError(err).WithField("rate_limit_override_id", id).Warn("failed to record rate limit override removal")
+	}
+	s.log.WithFields(logger.Fields{"rate_limit_override_id": id, "removed_by": actorID}).Info("Rate limit override removed")
+	return nil
+}
+
+// validateSubject checks that a subject names an IP, customer or configured partner
+func (s *rateLimitService) validateSubject(subject string) error {
+	if !ratelimit.ValidSubject(subject) {
+		return errors.ValidationError("subject must be customer:<id>, key:<partner> or ip:<address>")
+	}
+	if partner, ok := ratelimit.SubjectPartner(subject); ok {
+		if _, known := s.policy.Partners[partner]; !known {
+			return errors.NotFound(fmt.Sprintf("partner %s", partner))
+		}
+	}
+	return nil
+}
+
+func toRateLimitOverrideDTO(o *ratelimit.Override) *RateLimitOverrideDTO {
+	return &RateLimitOverrideDTO{
+		ID:        o.ID,
+		Subject:   o.Subject,
+		Bucket:    o.Bucket,
+		Requests:  o.Requests,
+		ExpiresAt: o.ExpiresAt,
+		Note:      o.Note,
+		CreatedBy: o.CreatedBy,
+		CreatedAt: o.CreatedAt,
+	}
+}
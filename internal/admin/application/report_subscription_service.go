@@ -0,0 +1,367 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/export"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const (
+	// reportDeliveryLease keeps a claimed subscription from being delivered twice
+	// when its delivery is slow to be recorded
+	reportDeliveryLease = 10 * time.Minute
+	// reportDeliveryBatch bounds the deliveries started per scheduler tick
+	reportDeliveryBatch = 50
+)
+
+// ReportSubscriptionService emails reports such as the sales summary, low stock and
+// offer performance on a daily or weekly schedule. Deliveries are background export
+// jobs whose file is attached as CSV or PDF. Subscriptions are managed by their owner.
+type ReportSubscriptionService interface {
+	// ListReports lists the reports admins can subscribe to.
+	ListReports(ctx context.Context) []*ReportDTO
+
+	// ListSubscriptions lists the subscriptions of the admin user.
+	ListSubscriptions(ctx context.Context, actorID string) ([]*ReportSubscriptionDTO, error)
+
+	// GetSubscription retrieves a subscription of the admin user.
+	GetSubscription(ctx context.Context, subscriptionID int64, actorID string) (*ReportSubscriptionDTO, error)
+
+	// CreateSubscription subscribes the admin user to a report.
+	CreateSubscription(ctx context.Context, req *ReportSubscriptionRequest, actorID string) (*ReportSubscriptionDTO, error)
+
+	// UpdateSubscription replaces the parameters, schedule, format and recipients of a
+	// subscription of the admin user, and pauses or resumes it.
+	UpdateSubscription(ctx context.Context, subscriptionID int64, req *ReportSubscriptionRequest, actorID string) (*ReportSubscriptionDTO, error)
+
+	// DeleteSubscription unsubscribes from a report.
+	DeleteSubscription(ctx context.Context, subscriptionID int64, actorID string) error
+
+	// DeliverNow emails the report of the last completed period right away, without
+	// changing the schedule.
+	DeliverNow(ctx context.Context, subscriptionID int64, actorID string) (*export.Job, error)
+
+	// DeliverDue starts the deliveries that are due and returns how many started.
+	DeliverDue(ctx context.Context) (int, error)
+
+	// StartScheduledDelivery delivers due reports periodically until ctx is cancelled.
+	StartScheduledDelivery(ctx context.Context, interval time.Duration)
+}
+
+type reportSubscriptionService struct {
+	repo    domain.ReportSubscriptionRepository
+	reports map[string]export.Report
+	jobs    *export.JobManager
+	log     *logger.Logger
+}
+
+// NewReportSubscriptionService creates a new instance of ReportSubscriptionService
+// offering the given reports
+func NewReportSubscriptionService(
+	repo domain.ReportSubscriptionRepository,
+	reports []export.Report,
+	jobs *export.JobManager,
+	log *logger.Logger,
+) ReportSubscriptionService {
+	byName := make(map[string]export.Report, len(reports))
+	for _, report := range reports {
+		byName[report.Name] = report
+	}
+	return &reportSubscriptionService{
+		repo:    repo,
+		reports: byName,
+		jobs:    jobs,
+		log:     log,
+	}
+}
+
+func (s *reportSubscriptionService) ListReports(ctx context.Context) []*ReportDTO {
+	dtos := make([]*ReportDTO, 0, len(s.reports))
+	for _, report := range s.reports {
+		dtos = append(dtos, &ReportDTO{Name: report.Name, Title: report.Title, Params: report.Params})
+	}
+	sort.Slice(dtos, func(i, j int) bool { return dtos[i].Name < dtos[j].Name })
+	return dtos
+}
+
+func (s *reportSubscriptionService) ListSubscriptions(ctx context.Context, actorID string) ([]*ReportSubscriptionDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := s.repo.FindByAdminUserID(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*ReportSubscriptionDTO, 0, len(subs))
+	for _, sub := range subs {
+		dtos = append(dtos, ToReportSubscriptionDTO(sub))
+	}
+	return dtos, nil
+}
+
+func (s *reportSubscriptionService) GetSubscription(ctx context.Context, subscriptionID int64, actorID string) (*ReportSubscriptionDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.findOwned(ctx, subscriptionID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	return ToReportSubscriptionDTO(sub), nil
+}
+
+func (s *reportSubscriptionService) CreateSubscription(ctx context.Context, req *ReportSubscriptionRequest, actorID string) (*ReportSubscriptionDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	report, ok := s.reports[req.Report]
+	if !ok {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown report %q", req.Report))
+	}
+	schedule, err := reportSchedule(req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sub, err := domain.NewReportSubscription(adminUserID, report.Name, schedule, now)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if req.Active != nil {
+		sub.SetActive(*req.Active, now)
+	}
+	if err := s.checkParams(report, sub); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"subscription_id": sub.ID,
+		"report":          sub.Report,
+		"frequency":       sub.Frequency,
+	}).Info("Report subscription created")
+	return ToReportSubscriptionDTO(sub), nil
+}
+
+func (s *reportSubscriptionService) UpdateSubscription(ctx context.Context, subscriptionID int64, req *ReportSubscriptionRequest, actorID string) (*ReportSubscriptionDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.findOwned(ctx, subscriptionID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Report != "" && req.Report != sub.Report {
+		return nil, errors.ValidationError("the report of a subscription cannot be changed")
+	}
+	report, ok := s.reports[sub.Report]
+	if !ok {
+		return nil, errors.ValidationError(fmt.Sprintf("report %q is no longer available", sub.Report))
+	}
+	schedule, err := reportSchedule(req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := sub.Update(schedule, now); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if req.Active != nil {
+		sub.SetActive(*req.Active, now)
+	}
+	if err := s.checkParams(report, sub); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return ToReportSubscriptionDTO(sub), nil
+}
+
+func (s *reportSubscriptionService) DeleteSubscription(ctx context.Context, subscriptionID int64, actorID string) error {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.findOwned(ctx, subscriptionID, adminUserID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, subscriptionID)
+}
+
+func (s *reportSubscriptionService) DeliverNow(ctx context.Context, subscriptionID int64, actorID string) (*export.Job, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.findOwned(ctx, subscriptionID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job, err := s.deliver(sub, now)
+	if err != nil {
+		return nil, err
+	}
+	sub.LastRunAt = &now
+	sub.LastJobID = job.ID
+	sub.UpdatedAt = now
+	if err := s.repo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *reportSubscriptionService) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	subs, err := s.repo.ClaimDue(ctx, now, reportDeliveryLease, reportDeliveryBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due report subscriptions: %w", err)
+	}
+
+	started := 0
+	for _, sub := range subs {
+		job, err := s.deliver(sub, now)
+		if err != nil {
+			// The next delivery is scheduled all the same so a broken subscription is
+			// retried on its schedule rather than every tick
+			s.log.WithError(err).WithField("subscription_id", sub.ID).Error("Failed to start scheduled report delivery")
+			sub.RecordRun("", now)
+		} else {
+			sub.RecordRun(job.ID, now)
+			started++
+		}
+		if err := s.repo.Update(ctx, sub); err != nil {
+			s.log.WithError(err).WithField("subscription_id", sub.ID).Error("Failed to record report delivery")
+		}
+	}
+	return started, nil
+}
+
+func (s *reportSubscriptionService) StartScheduledDelivery(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.DeliverDue(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled report delivery failed")
+				}
+			}
+		}
+	}()
+}
+
+// deliver starts the export job emailing the report of the period before runAt
+func (s *reportSubscriptionService) deliver(sub *domain.ReportSubscription, runAt time.Time) (*export.Job, error) {
+	report, ok := s.reports[sub.Report]
+	if !ok {
+		return nil, errors.ValidationError(fmt.Sprintf("report %q is no longer available", sub.Report))
+	}
+	from, to := sub.Period(runAt)
+	exp, err := report.Build(from, to, sub.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	period := from.Format("2006-01-02")
+	if sub.Frequency == domain.ReportFrequencyWeekly {
+		period += " to " + to.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	delivery := export.Delivery{
+		To:      sub.Recipients,
+		Subject: fmt.Sprintf("%s report, %s", report.Title, period),
+		Body: fmt.Sprintf("The %s %s report for %s is attached.\n\nManage this subscription at /admin/report-subscriptions/%d.",
+			sub.Frequency, strings.ToLower(report.Title), period, sub.ID),
+		Format: export.Format(sub.Format),
+		Title:  report.Title + ", " + period,
+	}
+	job := s.jobs.StartDelivery(exp, strconv.FormatInt(sub.AdminUserID, 10), delivery)
+
+	s.log.WithFields(logger.Fields{
+		"subscription_id": sub.ID,
+		"report":          sub.Report,
+		"job_id":          job.ID,
+		"recipients":      len(sub.Recipients),
+	}).Info("Report delivery started")
+	return job, nil
+}
+
+// checkParams rejects parameters the report does not take or cannot use
+func (s *reportSubscriptionService) checkParams(report export.Report, sub *domain.ReportSubscription) error {
+	for key := range sub.Params {
+		if !report.AcceptsParam(key) {
+			return errors.ValidationError(fmt.Sprintf("parameter %q is not supported by the %s report", key, report.Name))
+		}
+	}
+	from, to := sub.Period(time.Now())
+	if _, err := report.Build(from, to, sub.Params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findOwned loads a subscription of the admin user; others' are reported as missing
+func (s *reportSubscriptionService) findOwned(ctx context.Context, subscriptionID, adminUserID int64) (*domain.ReportSubscription, error) {
+	sub, err := s.repo.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.IsOwnedBy(adminUserID) {
+		return nil, errors.NotFound(fmt.Sprintf("report subscription %d", subscriptionID))
+	}
+	return sub, nil
+}
+
+// reportSchedule reads the schedule of a subscription request
+func reportSchedule(req *ReportSubscriptionRequest) (domain.ReportSchedule, error) {
+	schedule := domain.ReportSchedule{
+		Params:     req.Params,
+		Frequency:  domain.ReportFrequency(strings.ToLower(req.Frequency)),
+		Hour:       req.Hour,
+		Format:     domain.ReportFormat(strings.ToLower(req.Format)),
+		Recipients: req.Recipients,
+	}
+	if schedule.Frequency == domain.ReportFrequencyWeekly {
+		weekday, ok := parseWeekday(req.Weekday)
+		if !ok {
+			return schedule, errors.ValidationError("weekly reports require a weekday, e.g. monday")
+		}
+		schedule.Weekday = weekday
+	}
+	return schedule, nil
+}
+
+func parseWeekday(value string) (time.Weekday, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == value {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// RequestLogService defines the application service for browsing and purging the admin API request log.
+type RequestLogService interface {
+	// SearchRequestLogs returns a page of logged requests matching the query, without headers and bodies.
+	SearchRequestLogs(ctx context.Context, query *RequestLogQuery) (*RequestLogPageDTO, error)
+
+	// GetRequestLog returns one logged request with its redacted headers and bodies.
+	GetRequestLog(ctx context.Context, id string) (*RequestLogEntryDTO, error)
+
+	// PurgeExpired deletes entries past the retention period.
+	PurgeExpired(ctx context.Context) (int64, error)
+
+	// StartScheduledPurge purges expired entries periodically until ctx is cancelled.
+	StartScheduledPurge(ctx context.Context, interval time.Duration)
+}
+
+type requestLogService struct {
+	repo      domain.RequestLogRepository
+	retention time.Duration
+	batchSize int
+	log       *logger.Logger
+
+	// purgeMu prevents overlapping purge runs
+	purgeMu sync.Mutex
+}
+
+// NewRequestLogService creates a new instance of RequestLogService. A zero retention keeps entries forever.
+func NewRequestLogService(repo domain.RequestLogRepository, retention time.Duration, batchSize int, log *logger.Logger) RequestLogService {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	return &requestLogService{repo: repo, retention: retention, batchSize: batchSize, log: log}
+}
+
+func (s *requestLogService) SearchRequestLogs(ctx context.Context, query *RequestLogQuery) (*RequestLogPageDTO, error) {
+	filter, err := query.toFilter()
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.repo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return ToRequestLogPageDTO(page), nil
+}
+
+func (s *requestLogService) GetRequestLog(ctx context.Context, id string) (*RequestLogEntryDTO, error) {
+	entry, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToRequestLogEntryDTO(entry), nil
+}
+
+func (s *requestLogService) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	if !s.purgeMu.TryLock() {
+		return 0, errors.Conflict("request log purge already in progress")
+	}
+	defer s.purgeMu.Unlock()
+
+	cutoff := time.Now().Add(-s.retention)
+	var purged int64
+	for {
+		deleted, err := s.repo.DeleteBefore(ctx, cutoff, s.batchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge request log: %w", err)
+		}
+		purged += deleted
+		if deleted < int64(s.batchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if purged > 0 {
+		s.log.WithField("purged", purged).WithField("cutoff", cutoff).Info("Purged expired request log entries")
+	}
+	return purged, nil
+}
+
+func (s *requestLogService) StartScheduledPurge(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || s.retention <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.PurgeExpired(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled request log purge failed")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,269 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// savedViewEntityType is the audit log entity type of saved admin list views
+const savedViewEntityType = "admin_saved_view"
+
+// SavedViewService manages the saved filters and columns of the admin order,
+// product and customer lists. Views are edited by their owner only; roles they
+// are shared with can use them.
+type SavedViewService interface {
+	// ListViews lists the views the admin user owns or can use through a role,
+	// optionally restricted to one list.
+	ListViews(ctx context.Context, actorID string, list string) ([]*SavedViewDTO, error)
+
+	// GetView retrieves a view the admin user can use.
+	GetView(ctx context.Context, viewID int64, actorID string) (*SavedViewDTO, error)
+
+	// CreateView saves a new view owned by the admin user.
+	CreateView(ctx context.Context, req *SavedViewRequest, actorID string) (*SavedViewDTO, error)
+
+	// UpdateView replaces the name, filters and columns of a view of the admin user.
+	UpdateView(ctx context.Context, viewID int64, req *SavedViewRequest, actorID string) (*SavedViewDTO, error)
+
+	// ShareView replaces the roles a view of the admin user is shared with.
+	ShareView(ctx context.Context, viewID int64, req *ShareSavedViewRequest, actorID string) (*SavedViewDTO, error)
+
+	// DeleteView deletes a view of the admin user.
+	DeleteView(ctx context.Context, viewID int64, actorID string) error
+
+	// ResolveSavedView returns the filters and columns of a view of the given list
+	// the admin user can use, to apply them to a list request.
+	ResolveSavedView(ctx context.Context, userID string, viewID int64, list string) (map[string]string, []string, error)
+}
+
+type savedViewService struct {
+	viewRepo     domain.SavedViewRepository
+	roleRepo     domain.AdminRoleRepository
+	auditService *audit.AuditService
+	log          *logger.Logger
+}
+
+// NewSavedViewService creates a new instance of SavedViewService
+func NewSavedViewService(
+	viewRepo domain.SavedViewRepository,
+	roleRepo domain.AdminRoleRepository,
+	auditService *audit.AuditService,
+	log *logger.Logger,
+) SavedViewService {
+	return &savedViewService{
+		viewRepo:     viewRepo,
+		roleRepo:     roleRepo,
+		auditService: auditService,
+		log:          log,
+	}
+}
+
+func (s *savedViewService) ListViews(ctx context.Context, actorID string, list string) ([]*SavedViewDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if list != "" && !domain.SavedViewList(list).IsValid() {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown list %q", list))
+	}
+
+	views, err := s.viewRepo.FindVisible(ctx, adminUserID, domain.SavedViewList(list))
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*SavedViewDTO, 0, len(views))
+	for _, view := range views {
+		dtos = append(dtos, ToSavedViewDTO(view, adminUserID))
+	}
+	return dtos, nil
+}
+
+func (s *savedViewService) GetView(ctx context.Context, viewID int64, actorID string) (*SavedViewDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	view, err := s.findVisible(ctx, viewID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	return ToSavedViewDTO(view, adminUserID), nil
+}
+
+func (s *savedViewService) CreateView(ctx context.Context, req *SavedViewRequest, actorID string) (*SavedViewDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := domain.NewSavedView(adminUserID, domain.SavedViewList(req.List), req.Name, req.Filters, req.Columns)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.checkNameFree(ctx, view); err != nil {
+		return nil, err
+	}
+	if err := s.viewRepo.Create(ctx, view); err != nil {
+		return nil, err
+	}
+	return ToSavedViewDTO(view, adminUserID), nil
+}
+
+func (s *savedViewService) UpdateView(ctx context.Context, viewID int64, req *SavedViewRequest, actorID string) (*SavedViewDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	view, err := s.findOwned(ctx, viewID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	if req.List != "" && domain.SavedViewList(req.List) != view.List {
+		return nil, errors.ValidationError("the list of a saved view cannot be changed")
+	}
+
+	if err := view.Update(req.Name, req.Filters, req.Columns); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.checkNameFree(ctx, view); err != nil {
+		return nil, err
+	}
+	if err := s.viewRepo.Update(ctx, view); err != nil {
+		return nil, err
+	}
+	return ToSavedViewDTO(view, adminUserID), nil
+}
+
+func (s *savedViewService) ShareView(ctx context.Context, viewID int64, req *ShareSavedViewRequest, actorID string) (*SavedViewDTO, error) {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	view, err := s.findOwned(ctx, viewID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, roleID := range req.RoleIDs {
+		if _, err := s.roleRepo.FindByID(ctx, roleID); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, errors.ValidationError(fmt.Sprintf("admin role %d does not exist", roleID))
+			}
+			return nil, err
+		}
+	}
+
+	previous := view.SharedRoleIDs
+	view.ShareWith(req.RoleIDs)
+	if err := s.viewRepo.Update(ctx, view); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, view.ID, actorID, map[string]interface{}{
+		"shared_role_ids":          view.SharedRoleIDs,
+		"previous_shared_role_ids": previous,
+	})
+	return ToSavedViewDTO(view, adminUserID), nil
+}
+
+func (s *savedViewService) DeleteView(ctx context.Context, viewID int64, actorID string) error {
+	adminUserID, err := parseAdminUserID(actorID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.findOwned(ctx, viewID, adminUserID); err != nil {
+		return err
+	}
+	return s.viewRepo.Delete(ctx, viewID)
+}
+
+func (s *savedViewService) ResolveSavedView(ctx context.Context, userID string, viewID int64, list string) (map[string]string, []string, error) {
+	adminUserID, err := parseAdminUserID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	view, err := s.findVisible(ctx, viewID, adminUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if view.List != domain.SavedViewList(list) {
+		return nil, nil, errors.ValidationError(fmt.Sprintf("saved view %d belongs to the %s list", viewID, view.List))
+	}
+	return view.Filters, view.Columns, nil
+}
+
+// findVisible loads a view the admin user owns or can use through a role. Views
+// the user cannot see are reported as missing.
+func (s *savedViewService) findVisible(ctx context.Context, viewID, adminUserID int64) (*domain.SavedView, error) {
+	view, err := s.viewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return nil, err
+	}
+	if view.IsOwnedBy(adminUserID) {
+		return view, nil
+	}
+
+	roles, err := s.roleRepo.FindByAdminUserID(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if !view.VisibleTo(adminUserID, roleIDs) {
+		return nil, errors.NotFound(fmt.Sprintf("saved view %d", viewID))
+	}
+	return view, nil
+}
+
+// findOwned loads a view for editing by its owner
+func (s *savedViewService) findOwned(ctx context.Context, viewID, adminUserID int64) (*domain.SavedView, error) {
+	view, err := s.findVisible(ctx, viewID, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !view.IsOwnedBy(adminUserID) {
+		return nil, errors.Forbidden("only the owner of a saved view can change it")
+	}
+	return view, nil
+}
+
+// checkNameFree rejects a view named like another view of the same owner and list
+func (s *savedViewService) checkNameFree(ctx context.Context, view *domain.SavedView) error {
+	views, err := s.viewRepo.FindVisible(ctx, view.AdminUserID, view.List)
+	if err != nil {
+		return err
+	}
+	for _, other := range views {
+		if other.ID != view.ID && other.IsOwnedBy(view.AdminUserID) && other.Name == view.Name {
+			return errors.Conflict("a saved view named " + view.Name + " already exists")
+		}
+	}
+	return nil
+}
+
+func (s *savedViewService) recordChange(ctx context.Context, viewID int64, actorID string, changes map[string]interface{}) {
+	var userID *string
+	if actorID != "" {
+		userID = &actorID
+	}
+	entityID := strconv.FormatInt(viewID, 10)
+	if err := s.auditService.LogUpdate(ctx, savedViewEntityType, entityID, userID, changes); err != nil {
+		s.log.WithError(err).WithField("saved_view_id", viewID).Warn("failed to record saved view change")
+	}
+}
+
+// parseAdminUserID reads the admin user ID authenticated requests carry
+func parseAdminUserID(userID string) (int64, error) {
+	adminUserID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil || adminUserID == 0 {
+		return 0, errors.Unauthorized("admin user required")
+	}
+	return adminUserID, nil
+}
@@ -0,0 +1,167 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/settings"
+)
+
+// siteSettingEntityType is the audit log entity type of site settings
+const siteSettingEntityType = "site_setting"
+
+// SiteSettingService changes the site settings read by the admin and
+// storefront services, such as the store name and the order number prefix.
+type SiteSettingService interface {
+	// ListSettings lists every known setting with its current value.
+	ListSettings(ctx context.Context) ([]*SiteSettingDTO, error)
+
+	// GetSetting returns a setting with its current value.
+	GetSetting(ctx context.Context, key string) (*SiteSettingDTO, error)
+
+	// SetSetting changes a setting; storefront instances pick it up at their next refresh.
+	SetSetting(ctx context.Context, key string, req *SetSiteSettingRequest, actorID string) (*SiteSettingDTO, error)
+
+	// ResetSetting sets a setting back to its default.
+	ResetSetting(ctx context.Context, key, actorID string) (*SiteSettingDTO, error)
+
+	// GetSettingHistory lists the recorded changes of a setting, latest first.
+	GetSettingHistory(ctx context.Context, key string) ([]*SiteSettingChangeDTO, error)
+}
+
+type siteSettingService struct {
+	settings     *settings.Settings
+	auditService *audit.AuditService
+	log          *logger.Logger
+}
+
+// NewSiteSettingService creates a new instance of SiteSettingService
+func NewSiteSettingService(siteSettings *settings.Settings, auditService *audit.AuditService, log *logger.Logger) SiteSettingService {
+	return &siteSettingService{
+		settings:     siteSettings,
+		auditService: auditService,
+		log:          log,
+	}
+}
+
+func (s *siteSettingService) ListSettings(ctx context.Context) ([]*SiteSettingDTO, error) {
+	// Read the store rather than the snapshot so settings changed by another admin instance show up
+	if err := s.settings.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	current := s.settings.List()
+	dtos := make([]*SiteSettingDTO, 0, len(current))
+	for _, setting := range current {
+		dtos = append(dtos, s.toDTO(setting))
+	}
+	return dtos, nil
+}
+
+func (s *siteSettingService) GetSetting(ctx context.Context, key string) (*SiteSettingDTO, error) {
+	if !settings.IsKnown(key) {
+		return nil, errors.NotFound(fmt.Sprintf("site setting %s", key))
+	}
+	if err := s.settings.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	setting, _ := s.settings.Get(key)
+	return s.toDTO(setting), nil
+}
+
+func (s *siteSettingService) SetSetting(ctx context.Context, key string, req *SetSiteSettingRequest, actorID string) (*SiteSettingDTO, error) {
+	if !settings.IsKnown(key) {
+		return nil, errors.NotFound(fmt.Sprintf("site setting %s", key))
+	}
+	if req.Value == nil {
+		return nil, errors.ValidationError("value is required")
+	}
+	if _, err := settings.Normalize(key, *req.Value); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	previous, _ := s.settings.Get(key)
+	setting, err := s.settings.Set(ctx, key, *req.Value, actorID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, key, previous.Value, setting.Value, false, actorID)
+	return s.toDTO(setting), nil
+}
+
+func (s *siteSettingService) ResetSetting(ctx context.Context, key, actorID string) (*SiteSettingDTO, error) {
+	if !settings.IsKnown(key) {
+		return nil, errors.NotFound(fmt.Sprintf("site setting %s", key))
+	}
+
+	previous, _ := s.settings.Get(key)
+	setting, err := s.settings.Reset(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, key, previous.Value, setting.Value, true, actorID)
+	return s.toDTO(setting), nil
+}
+
+func (s *siteSettingService) GetSettingHistory(ctx context.Context, key string) ([]*SiteSettingChangeDTO, error) {
+	if !settings.IsKnown(key) {
+		return nil, errors.NotFound(fmt.Sprintf("site setting %s", key))
+	}
+	entries, err := s.auditService.GetAuditTrail(ctx, siteSettingEntityType, key)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to read site setting history")
+	}
+
+	changes := make([]*SiteSettingChangeDTO, 0, len(entries))
+	for _, entry := range entries {
+		change := &SiteSettingChangeDTO{ChangedAt: entry.Timestamp}
+		change.Value, _ = entry.Changes["value"].(string)
+		change.PreviousValue, _ = entry.Changes["previous_value"].(string)
+		change.Reset, _ = entry.Changes["reset"].(bool)
+		if entry.UserID != nil {
+			change.ChangedBy = *entry.UserID
+		}
+		changes = append(changes, change)
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.After(changes[j].ChangedAt) })
+	return changes, nil
+}
+
+// recordChange records a change in the audit log; a failure to record it does not undo the change
+func (s *siteSettingService) recordChange(ctx context.Context, key, previous, value string, reset bool, actorID string) {
+	var userID *string
+	if actorID != "" {
+		userID = &actorID
+	}
+	changes := map[string]interface{}{
+		"value":          value,
+		"previous_value": previous,
+	}
+	if reset {
+		changes["reset"] = true
+	}
+	if err := s.auditService.LogUpdate(ctx, siteSettingEntityType, key, userID, changes); err != nil {
+		s.log.WithError(err).WithField("setting", key).Warn("failed to record site setting change")
+	}
+	s.log.WithFields(logger.Fields{"setting": key, "value": value, "updated_by": actorID}).Info("Site setting changed")
+}
+
+func (s *siteSettingService) toDTO(setting *settings.Setting) *SiteSettingDTO {
+	definition := settings.Definitions[setting.Key]
+	dto := &SiteSettingDTO{
+		Key:         setting.Key,
+		Type:        string(definition.Type),
+		Description: definition.Description,
+		Default:     s.settings.Default(setting.Key),
+		Value:       setting.Value,
+		UpdatedBy:   setting.UpdatedBy,
+	}
+	if !setting.UpdatedAt.IsZero() {
+		updatedAt := setting.UpdatedAt
+		dto.UpdatedAt = &updatedAt
+	}
+	return dto
+}
@@ -0,0 +1,236 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PermissionType classifies what an admin permission allows, as in the legacy schema
+type PermissionType string
+
+const (
+	PermissionTypeRead  PermissionType = "READ"
+	PermissionTypeAll   PermissionType = "ALL"
+	PermissionTypeOther PermissionType = "OTHER"
+)
+
+// Permissions granted by the role templates
+const (
+	PermissionReadCatalog      = "PERMISSION_READ_CATALOG"
+	PermissionAllCatalog       = "PERMISSION_ALL_CATALOG"
+	PermissionApproveCatalog   = "PERMISSION_APPROVE_CATALOG"
+	PermissionAllMerchandising = "PERMISSION_ALL_MERCHANDISING"
+	PermissionAllInventory     = "PERMISSION_ALL_INVENTORY"
+	PermissionReadCustomer     = "PERMISSION_READ_CUSTOMER"
+	PermissionAllCustomer      = "PERMISSION_ALL_CUSTOMER"
+	PermissionReadOrder        = "PERMISSION_READ_ORDER"
+	PermissionAllOrder         = "PERMISSION_ALL_ORDER"
+	PermissionOverrideOrder    = "PERMISSION_OVERRIDE_ORDER"
+	PermissionReadFulfillment  = "PERMISSION_READ_FULFILLMENT"
+	PermissionAllFulfillment   = "PERMISSION_ALL_FULFILLMENT"
+	PermissionReadPayment      = "PERMISSION_READ_PAYMENT"
+	PermissionRefundPayment    = "PERMISSION_REFUND_PAYMENT"
+	PermissionReadOffer        = "PERMISSION_READ_OFFER"
+	PermissionAllOffer         = "PERMISSION_ALL_OFFER"
+	PermissionReadReports      = "PERMISSION_READ_REPORTS"
+	PermissionExportData       = "PERMISSION_EXPORT_DATA"
+	PermissionManageSessions   = "PERMISSION_MANAGE_SESSIONS"
+)
+
+// AdminPermission is a named grant that admin roles and users hold
+type AdminPermission struct {
+	ID          int64
+	Name        string
+	Description string
+	Type        PermissionType
+}
+
+// AdminRole is a named bundle of admin permissions
+type AdminRole struct {
+	ID          int64
+	Name        string
+	Description string
+	Permissions []*AdminPermission
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewAdminRole creates a new admin role holding the given permissions
+func NewAdminRole(name, description string, permissions []*AdminPermission) (*AdminRole, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, NewDomainError("role name is required")
+	}
+	if strings.TrimSpace(description) == "" {
+		description = name
+	}
+	now := time.Now()
+	return &AdminRole{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Clone creates an unsaved copy of the role with the same permissions
+func (r *AdminRole) Clone(name, description string) (*AdminRole, error) {
+	permissions := make([]*AdminPermission, len(r.Permissions))
+	copy(permissions, r.Permissions)
+	return NewAdminRole(name, description, permissions)
+}
+
+// PermissionNames returns the sorted names of the role's permissions
+func (r *AdminRole) PermissionNames() []string {
+	names := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		names = append(names, permission.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RoleTemplate is a predefined permission bundle that can be seeded as a role
+type RoleTemplate struct {
+	Key         string
+	RoleName    string
+	Description string
+	Permissions []string
+}
+
+// permissionCatalog describes every permission the role templates grant
+var permissionCatalog = map[string]AdminPermission{
+	PermissionReadCatalog:      {Name: PermissionReadCatalog, Description: "View products, categories and SKUs", Type: PermissionTypeRead},
+	PermissionAllCatalog:       {Name: PermissionAllCatalog, Description: "Manage products, categories and SKUs", Type: PermissionTypeAll},
+	PermissionApproveCatalog:   {Name: PermissionApproveCatalog, Description: "Approve product drafts for the storefront", Type: PermissionTypeOther},
+	PermissionAllMerchandising: {Name: PermissionAllMerchandising, Description: "Manage category merchandising, badges and search settings", Type: PermissionTypeAll},
+	PermissionAllInventory:     {Name: PermissionAllInventory, Description: "Manage inventory and allocations", Type: PermissionTypeAll},
+	PermissionReadCustomer:     {Name: PermissionReadCustomer, Description: "View customers", Type: PermissionTypeRead},
+	PermissionAllCustomer:      {Name: PermissionAllCustomer, Description: "Manage customers", Type: PermissionTypeAll},
+	PermissionReadOrder:        {Name: PermissionReadOrder, Description: "View orders", Type: PermissionTypeRead},
+	PermissionAllOrder:         {Name: PermissionAllOrder, Description: "Manage orders, notes and assisted orders", Type: PermissionTypeAll},
+	PermissionOverrideOrder:    {Name: PermissionOverrideOrder, Description: "Override prices on assisted orders", Type: PermissionTypeOther},
+	PermissionReadFulfillment:  {Name: PermissionReadFulfillment, Description: "View shipments", Type: PermissionTypeRead},
+	PermissionAllFulfillment:   {Name: PermissionAllFulfillment, Description: "Manage shipments and deallocations", Type: PermissionTypeAll},
+	PermissionReadPayment:      {Name: PermissionReadPayment, Description: "View payments", Type: PermissionTypeRead},
+	PermissionRefundPayment:    {Name: PermissionRefundPayment, Description: "Refund payments and send payment links", Type: PermissionTypeOther},
+	PermissionReadOffer:        {Name: PermissionReadOffer, Description: "View offers and offer reports", Type: PermissionTypeRead},
+	PermissionAllOffer:         {Name: PermissionAllOffer, Description: "Manage offers", Type: PermissionTypeAll},
+	PermissionReadReports:      {Name: PermissionReadReports, Description: "View analytics reports", Type: PermissionTypeRead},
+	PermissionExportData:       {Name: PermissionExportData, Description: "Run data exports", Type: PermissionTypeOther},
+	PermissionManageSessions:   {Name: PermissionManageSessions, Description: "View and revoke the sessions of other admin users", Type: PermissionTypeOther},
+}
+
+// roleTemplates are the predefined role templates, in display order
+var roleTemplates = []RoleTemplate{
+	{
+		Key:         "catalog_manager",
+		RoleName:    "ROLE_CATALOG_MANAGER",
+		Description: "Catalog Manager",
+		Permissions: []string{
+			PermissionReadCatalog, PermissionAllCatalog, PermissionAllMerchandising,
+			PermissionAllInventory, PermissionReadOffer, PermissionReadReports,
+		},
+	},
+	{
+		Key:         "catalog_reviewer",
+		RoleName:    "ROLE_CATALOG_REVIEWER",
+		Description: "Catalog Reviewer",
+		Permissions: []string{
+			PermissionReadCatalog, PermissionApproveCatalog, PermissionReadReports,
+		},
+	},
+	{
+		Key:         "csr",
+		RoleName:    "ROLE_CSR",
+		Description: "Customer Service Representative",
+		Permissions: []string{
+			PermissionReadCatalog, PermissionReadCustomer, PermissionAllCustomer,
+			PermissionReadOrder, PermissionAllOrder, PermissionReadFulfillment, PermissionReadPayment,
+		},
+	},
+	{
+		Key:         "fulfillment",
+		RoleName:    "ROLE_FULFILLMENT",
+		Description: "Fulfillment",
+		Permissions: []string{
+			PermissionReadOrder, PermissionReadFulfillment, PermissionAllFulfillment, PermissionAllInventory,
+		},
+	},
+	{
+		Key:         "finance",
+		RoleName:    "ROLE_FINANCE",
+		Description: "Finance",
+		Permissions: []string{
+			PermissionReadOrder, PermissionReadPayment, PermissionRefundPayment,
+			PermissionReadOffer, PermissionReadReports, PermissionExportData,
+		},
+	},
+	{
+		Key:         "security_admin",
+		RoleName:    "ROLE_SECURITY_ADMIN",
+		Description: "Security Administrator",
+		Permissions: []string{
+			PermissionManageSessions,
+		},
+	},
+}
+
+// RoleTemplates returns the predefined role templates
+func RoleTemplates() []RoleTemplate {
+	templates := make([]RoleTemplate, len(roleTemplates))
+	copy(templates, roleTemplates)
+	return templates
+}
+
+// FindRoleTemplate returns the role template with the given key
+func FindRoleTemplate(key string) (RoleTemplate, bool) {
+	for _, template := range roleTemplates {
+		if template.Key == key {
+			return template, true
+		}
+	}
+	return RoleTemplate{}, false
+}
+
+// NewRole creates an unsaved role holding the template's permissions
+func (t RoleTemplate) NewRole() (*AdminRole, error) {
+	return NewAdminRole(t.RoleName, t.Description, t.AdminPermissions())
+}
+
+// AdminPermissions returns unsaved permissions for the template's permission names
+func (t RoleTemplate) AdminPermissions() []*AdminPermission {
+	permissions := make([]*AdminPermission, 0, len(t.Permissions))
+	for _, name := range t.Permissions {
+		permission := permissionCatalog[name]
+		permissions = append(permissions, &permission)
+	}
+	return permissions
+}
+
+// AdminRoleRepository defines the interface for admin role persistence
+type AdminRoleRepository interface {
+	// Create stores a new role with its permissions, creating permissions that do not exist yet
+	Create(ctx context.Context, role *AdminRole) error
+
+	// AddPermissions grants permissions to a role, creating permissions that do not exist yet
+	AddPermissions(ctx context.Context, roleID int64, permissions []*AdminPermission) error
+
+	// FindByID retrieves a role with its permissions
+	FindByID(ctx context.Context, id int64) (*AdminRole, error)
+
+	// FindByName retrieves a role with its permissions by name
+	FindByName(ctx context.Context, name string) (*AdminRole, error)
+
+	// FindAll retrieves every role with its permissions
+	FindAll(ctx context.Context) ([]*AdminRole, error)
+
+	// FindByAdminUserID retrieves the roles assigned to an admin user
+	FindByAdminUserID(ctx context.Context, adminUserID int64) ([]*AdminRole, error)
+
+	// FindUserPermissions retrieves the permissions granted to an admin user directly, outside any role
+	FindUserPermissions(ctx context.Context, adminUserID int64) ([]*AdminPermission, error)
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminSession is the server-side record of an admin login. Its ID is the ID of
+// the JWT issued for the login, so revoking the session revokes the token.
+type AdminSession struct {
+	ID             string
+	AdminUserID    int64
+	Device         string // User agent of the client that signed in
+	IPAddress      string
+	CreatedAt      time.Time
+	LastActivityAt time.Time
+	ExpiresAt      time.Time
+	RevokedAt      *time.Time
+}
+
+// NewAdminSession creates a new admin session
+func NewAdminSession(adminUserID int64, device, ipAddress string) (*AdminSession, error) {
+	if adminUserID == 0 {
+		return nil, NewDomainError("admin user ID is required")
+	}
+	now := time.Now()
+	return &AdminSession{
+		ID:             uuid.New().String(),
+		AdminUserID:    adminUserID,
+		Device:         device,
+		IPAddress:      ipAddress,
+		CreatedAt:      now,
+		LastActivityAt: now,
+	}, nil
+}
+
+// IsRevoked checks if the session has been revoked
+func (s *AdminSession) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// IsExpired checks if the session's token has expired
+func (s *AdminSession) IsExpired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && now.After(s.ExpiresAt)
+}
+
+// IsIdle checks if the session has had no activity within the idle timeout.
+// A zero timeout disables the idle check.
+func (s *AdminSession) IsIdle(now time.Time, idleTimeout time.Duration) bool {
+	return idleTimeout > 0 && now.Sub(s.LastActivityAt) > idleTimeout
+}
+
+// IsActive checks if the session can still be used
+func (s *AdminSession) IsActive(now time.Time, idleTimeout time.Duration) bool {
+	return !s.IsRevoked() && !s.IsExpired(now) && !s.IsIdle(now, idleTimeout)
+}
+
+// Revoke marks the session as revoked
+func (s *AdminSession) Revoke() error {
+	if s.IsRevoked() {
+		return NewDomainError("session is already revoked")
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	return nil
+}
+
+// AdminSessionRepository defines the interface for admin session persistence
+type AdminSessionRepository interface {
+	// Create saves a new session
+	Create(ctx context.Context, session *AdminSession) error
+
+	// FindByID retrieves a session by ID
+	FindByID(ctx context.Context, id string) (*AdminSession, error)
+
+	// FindByAdminUserID retrieves the sessions of an admin user, newest first.
+	// When activeOnly is set, revoked and expired sessions are excluded.
+	FindByAdminUserID(ctx context.Context, adminUserID int64, activeOnly bool) ([]*AdminSession, error)
+
+	// UpdateLastActivity records activity on a session
+	UpdateLastActivity(ctx context.Context, id string, at time.Time) error
+
+	// Revoke marks a session as revoked
+	Revoke(ctx context.Context, id string, at time.Time) error
+}
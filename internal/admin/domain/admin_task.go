@@ -0,0 +1,236 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// AdminTaskStatus is the state of an admin task
+type AdminTaskStatus string
+
+const (
+	AdminTaskStatusOpen      AdminTaskStatus = "OPEN"      // Waiting for an assignee to claim it
+	AdminTaskStatusClaimed   AdminTaskStatus = "CLAIMED"   // Being worked on by the admin user who claimed it
+	AdminTaskStatusCompleted AdminTaskStatus = "COMPLETED" // Done, with the outcome chosen
+	AdminTaskStatusCancelled AdminTaskStatus = "CANCELLED" // No longer needed, e.g. resolved by its workflow
+)
+
+// IsValid reports whether the status is one tasks can be in
+func (s AdminTaskStatus) IsValid() bool {
+	switch s {
+	case AdminTaskStatusOpen, AdminTaskStatusClaimed, AdminTaskStatusCompleted, AdminTaskStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Task types raised by the workflows needing an admin decision. The inbox accepts
+// any type, so new workflows can raise tasks without changes here.
+const (
+	AdminTaskTypeReturnApproval    = "return_approval"
+	AdminTaskTypeFraudReview       = "fraud_review"
+	AdminTaskTypeCatalogPublish    = "catalog_publish"
+	AdminTaskTypeStocktakeVariance = "stocktake_variance"
+)
+
+// maxAdminTaskTypeLength matches the admin_task.task_type column size
+const maxAdminTaskTypeLength = 64
+
+// AdminTask is a piece of work raised by a workflow, such as approving a return,
+// waiting in the inbox of an admin user or of the members of an admin role.
+// An assignee claims the task, then completes it with an outcome the workflow
+// acts on; the workflow cancels it when it is resolved another way.
+type AdminTask struct {
+	ID             int64
+	Type           string
+	Title          string
+	Description    string
+	EntityType     string            // Kind of record the task is about, e.g. "order"
+	EntityID       string            // ID of that record
+	Data           map[string]string // Details the workflow needs back, e.g. the amounts of a variance
+	AssignedRoleID *int64            // Members of the role can claim the task
+	AssignedUserID *int64            // Only this admin user can claim the task; takes precedence over the role
+	Status         AdminTaskStatus
+	ClaimedBy      *int64
+	ClaimedAt      *time.Time
+	DueAt          *time.Time
+	Outcome        string // Chosen on completion, e.g. "approved" or "rejected"
+	Note           string // Left on completion or cancellation
+	CompletedBy    *int64
+	CompletedAt    *time.Time
+	CreatedBy      string // Workflow or admin user that raised the task
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewAdminTask creates an open task about a record; it must be assigned before it is stored
+func NewAdminTask(taskType, title, entityType, entityID string, dueAt *time.Time, createdBy string) (*AdminTask, error) {
+	taskType = strings.TrimSpace(taskType)
+	if taskType == "" {
+		return nil, NewDomainError("task type is required")
+	}
+	if len(taskType) > maxAdminTaskTypeLength {
+		return nil, NewDomainError("task type cannot be longer than 64 characters")
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, NewDomainError("task title is required")
+	}
+
+	now := time.Now()
+	return &AdminTask{
+		Type:       taskType,
+		Title:      title,
+		EntityType: strings.TrimSpace(entityType),
+		EntityID:   strings.TrimSpace(entityID),
+		Data:       make(map[string]string),
+		Status:     AdminTaskStatusOpen,
+		DueAt:      dueAt,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// Assign routes the task to an admin user, or to the members of a role. A claim
+// by someone who can no longer work on the task is dropped.
+func (t *AdminTask) Assign(roleID, userID *int64) error {
+	if !t.IsActive() {
+		return NewDomainError("only open or claimed tasks can be reassigned")
+	}
+	if roleID == nil && userID == nil {
+		return NewDomainError("a task must be assigned to an admin user or role")
+	}
+
+	t.AssignedRoleID = roleID
+	t.AssignedUserID = userID
+	if t.Status == AdminTaskStatusClaimed && userID != nil && *userID != *t.ClaimedBy {
+		t.Status = AdminTaskStatusOpen
+		t.ClaimedBy = nil
+		t.ClaimedAt = nil
+	}
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsAssignedTo reports whether an admin user holding the given roles is an assignee of the task
+func (t *AdminTask) IsAssignedTo(adminUserID int64, roleIDs []int64) bool {
+	if t.AssignedUserID != nil {
+		return *t.AssignedUserID == adminUserID
+	}
+	if t.AssignedRoleID != nil {
+		for _, roleID := range roleIDs {
+			if roleID == *t.AssignedRoleID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Claim takes the task for an assignee, so other assignees leave it alone
+func (t *AdminTask) Claim(adminUserID int64) error {
+	switch t.Status {
+	case AdminTaskStatusOpen:
+	case AdminTaskStatusClaimed:
+		if *t.ClaimedBy == adminUserID {
+			return nil
+		}
+		return NewDomainError("task is already claimed by another admin user")
+	default:
+		return NewDomainError("task is " + strings.ToLower(string(t.Status)))
+	}
+
+	now := time.Now()
+	t.Status = AdminTaskStatusClaimed
+	t.ClaimedBy = &adminUserID
+	t.ClaimedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// Release puts a claimed task back in its assignees' inboxes
+func (t *AdminTask) Release(adminUserID int64) error {
+	if t.Status != AdminTaskStatusClaimed || *t.ClaimedBy != adminUserID {
+		return NewDomainError("only the admin user who claimed the task can release it")
+	}
+	t.Status = AdminTaskStatusOpen
+	t.ClaimedBy = nil
+	t.ClaimedAt = nil
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Complete closes a task claimed by the admin user with an outcome
+func (t *AdminTask) Complete(adminUserID int64, outcome, note string) error {
+	if t.Status != AdminTaskStatusClaimed || *t.ClaimedBy != adminUserID {
+		return NewDomainError("a task must be claimed by the admin user completing it")
+	}
+
+	now := time.Now()
+	t.Status = AdminTaskStatusCompleted
+	t.Outcome = strings.TrimSpace(outcome)
+	t.Note = strings.TrimSpace(note)
+	t.CompletedBy = &adminUserID
+	t.CompletedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// Cancel closes a task that is no longer needed
+func (t *AdminTask) Cancel(note string) error {
+	if !t.IsActive() {
+		return NewDomainError("task is " + strings.ToLower(string(t.Status)))
+	}
+
+	now := time.Now()
+	t.Status = AdminTaskStatusCancelled
+	t.Note = strings.TrimSpace(note)
+	t.CompletedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// IsActive reports whether the task still waits for work
+func (t *AdminTask) IsActive() bool {
+	return t.Status == AdminTaskStatusOpen || t.Status == AdminTaskStatusClaimed
+}
+
+// IsOverdue reports whether the task is still active past its due date
+func (t *AdminTask) IsOverdue(now time.Time) bool {
+	return t.IsActive() && t.DueAt != nil && now.After(*t.DueAt)
+}
+
+// AdminTaskFilter selects tasks. Tasks are returned by due date, those without
+// one last, then oldest first.
+type AdminTaskFilter struct {
+	AssigneeUserID  int64 // Tasks assigned to this admin user, or to one of AssigneeRoleIDs
+	AssigneeRoleIDs []int64
+	ClaimedBy       int64 // 0 matches any
+	Statuses        []AdminTaskStatus
+	Type            string
+	EntityType      string
+	EntityID        string
+	DueBefore       *time.Time
+	Limit           int
+	Offset          int
+}
+
+// AdminTaskRepository defines the interface for admin task persistence
+type AdminTaskRepository interface {
+	// Create stores a new task
+	Create(ctx context.Context, task *AdminTask) error
+
+	// Update stores the assignment and state of a task
+	Update(ctx context.Context, task *AdminTask) error
+
+	// FindByID retrieves a task
+	FindByID(ctx context.Context, id int64) (*AdminTask, error)
+
+	// FindActiveByEntity retrieves the open or claimed tasks of a type about a record
+	FindActiveByEntity(ctx context.Context, taskType, entityType, entityID string) ([]*AdminTask, error)
+
+	// Search retrieves a page of tasks matching the filter with the total count
+	Search(ctx context.Context, filter *AdminTaskFilter) ([]*AdminTask, int64, error)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+// AdminUser represents a back-office user allowed to sign in to the admin API
+type AdminUser struct {
+	ID           int64
+	Name         string
+	Login        string
+	Email        string
+	PasswordHash string
+	Active       bool
+	Archived     bool
+}
+
+// CanSignIn checks if the admin user is allowed to start a session
+func (u *AdminUser) CanSignIn() bool {
+	return u.Active && !u.Archived
+}
+
+// AdminUserRepository defines the interface for admin user lookups
+type AdminUserRepository interface {
+	// FindByLogin retrieves an admin user by login name or email
+	FindByLogin(ctx context.Context, login string) (*AdminUser, error)
+
+	// FindByID retrieves an admin user by ID
+	FindByID(ctx context.Context, id int64) (*AdminUser, error)
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/audit"
+)
+
+// MaxAuditLogPageSize caps the entries returned per audit log page
+const MaxAuditLogPageSize = 200
+
+// AuditLogFilter selects audit log entries. Entries are returned newest first.
+type AuditLogFilter struct {
+	From       *time.Time
+	To         *time.Time
+	ActorID    string
+	EntityType string
+	EntityID   string
+	Actions    []audit.AuditAction
+	IPAddress  string
+	Cursor     *AuditLogCursor // Position after which the page starts
+	Limit      int
+}
+
+// AuditLogPage is a page of audit log entries
+type AuditLogPage struct {
+	Entries    []*audit.AuditEntry
+	NextCursor *AuditLogCursor // Nil on the last page
+	Total      int64           // Entries matching the filter across all pages
+}
+
+// AuditLogCursor marks the last entry of a page
+type AuditLogCursor struct {
+	OccurredAt time.Time
+	ID         string
+}
+
+// Encode returns the opaque form of the cursor used by API clients
+func (c *AuditLogCursor) Encode() string {
+	raw := c.OccurredAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeAuditLogCursor parses a cursor produced by Encode
+func DecodeAuditLogCursor(value string) (*AuditLogCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, NewDomainError("invalid cursor")
+	}
+	occurredAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, NewDomainError("invalid cursor")
+	}
+	at, err := time.Parse(time.RFC3339Nano, occurredAt)
+	if err != nil {
+		return nil, NewDomainError("invalid cursor")
+	}
+	return &AuditLogCursor{OccurredAt: at, ID: id}, nil
+}
+
+// AuditLogRepository stores the admin audit log. It implements audit.AuditLogger
+// so the audit service can write to it directly.
+type AuditLogRepository interface {
+	audit.AuditLogger
+
+	// Search returns a page of entries matching the filter with the total count
+	Search(ctx context.Context, filter *AuditLogFilter) (*AuditLogPage, error)
+
+	// ArchiveBefore moves up to batchSize entries older than cutoff to cold storage
+	ArchiveBefore(ctx context.Context, cutoff time.Time, batchSize int) (int64, error)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"strconv"
+
+	"github.com/qhato/ecommerce/pkg/event"
+)
+
+const (
+	// EventAdminTaskCreated is published when a task lands in an inbox
+	EventAdminTaskCreated = "admin.task.created"
+	// EventAdminTaskCompleted is published when an assignee completes a task, so the
+	// workflow that raised it can act on the outcome
+	EventAdminTaskCompleted = "admin.task.completed"
+	// EventAdminTaskCancelled is published when a task is cancelled
+	EventAdminTaskCancelled = "admin.task.cancelled"
+)
+
+func init() {
+	event.RegisterType(EventAdminTaskCreated, func() event.Event { return &AdminTaskEvent{} })
+	event.RegisterType(EventAdminTaskCompleted, func() event.Event { return &AdminTaskEvent{} })
+	event.RegisterType(EventAdminTaskCancelled, func() event.Event { return &AdminTaskEvent{} })
+}
+
+// AdminTaskEvent carries a task's state when it is created, completed or cancelled;
+// workflows subscribe by type and pick their tasks by TaskType
+type AdminTaskEvent struct {
+	event.BaseEvent
+	TaskID      int64             `json:"task_id"`
+	TaskType    string            `json:"task_type"`
+	EntityType  string            `json:"entity_type"`
+	EntityID    string            `json:"entity_id"`
+	Data        map[string]string `json:"data,omitempty"`
+	Status      AdminTaskStatus   `json:"status"`
+	Outcome     string            `json:"outcome,omitempty"`
+	Note        string            `json:"note,omitempty"`
+	CompletedBy *int64            `json:"completed_by,omitempty"`
+}
+
+// NewAdminTaskEvent creates an event of the given type with the task's current state
+func NewAdminTaskEvent(eventType string, task *AdminTask) *AdminTaskEvent {
+	return &AdminTaskEvent{
+		BaseEvent:   event.NewBaseEvent(eventType, strconv.FormatInt(task.ID, 10), nil),
+		TaskID:      task.ID,
+		TaskType:    task.Type,
+		EntityType:  task.EntityType,
+		EntityID:    task.EntityID,
+		Data:        task.Data,
+		Status:      task.Status,
+		Outcome:     task.Outcome,
+		Note:        task.Note,
+		CompletedBy: task.CompletedBy,
+	}
+}
@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// ReportFrequency is how often a subscribed report is delivered
+type ReportFrequency string
+
+const (
+	ReportFrequencyDaily  ReportFrequency = "daily"  // Covers the previous day
+	ReportFrequencyWeekly ReportFrequency = "weekly" // Covers the previous seven days
+)
+
+// IsValid reports whether reports can be delivered at the frequency
+func (f ReportFrequency) IsValid() bool {
+	return f == ReportFrequencyDaily || f == ReportFrequencyWeekly
+}
+
+// ReportFormat is the file format a subscribed report is attached in
+type ReportFormat string
+
+const (
+	ReportFormatCSV ReportFormat = "csv"
+	ReportFormatPDF ReportFormat = "pdf"
+)
+
+// IsValid reports whether reports can be attached in the format
+func (f ReportFormat) IsValid() bool {
+	return f == ReportFormatCSV || f == ReportFormatPDF
+}
+
+// maxReportRecipients bounds the addresses a subscription emails
+const maxReportRecipients = 20
+
+// ReportSubscription emails a report to recipients on a schedule. It is owned by
+// the admin user who created it. Deliveries happen at Hour (UTC) every day, or
+// on Weekday for weekly reports, and cover the whole days before the delivery.
+type ReportSubscription struct {
+	ID          int64
+	AdminUserID int64
+	Report      string            // Name of the report, e.g. "sales_summary"
+	Params      map[string]string // Query parameters of the report endpoint
+	Frequency   ReportFrequency
+	Weekday     time.Weekday // Day of weekly deliveries
+	Hour        int          // Hour of deliveries, in UTC
+	Format      ReportFormat
+	Recipients  []string
+	Active      bool
+	NextRunAt   time.Time
+	LastRunAt   *time.Time
+	LastJobID   string // Export job of the last delivery, visible to the owner under /admin/exports/jobs
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ReportSchedule holds the settings of a subscription admins can change
+type ReportSchedule struct {
+	Params     map[string]string
+	Frequency  ReportFrequency
+	Weekday    time.Weekday
+	Hour       int
+	Format     ReportFormat
+	Recipients []string
+}
+
+// NewReportSubscription creates an active subscription of an admin user to a report
+func NewReportSubscription(adminUserID int64, report string, schedule ReportSchedule, now time.Time) (*ReportSubscription, error) {
+	if adminUserID == 0 {
+		return nil, NewDomainError("a report subscription requires an admin user")
+	}
+	report = strings.TrimSpace(report)
+	if report == "" {
+		return nil, NewDomainError("report is required")
+	}
+
+	sub := &ReportSubscription{
+		AdminUserID: adminUserID,
+		Report:      report,
+		Active:      true,
+		CreatedAt:   now,
+	}
+	if err := sub.Update(schedule, now); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Update replaces the parameters, schedule, format and recipients, and schedules
+// the next delivery accordingly
+func (s *ReportSubscription) Update(schedule ReportSchedule, now time.Time) error {
+	if !schedule.Frequency.IsValid() {
+		return NewDomainError(fmt.Sprintf("unknown frequency %q, expected daily or weekly", schedule.Frequency))
+	}
+	if schedule.Weekday < time.Sunday || schedule.Weekday > time.Saturday {
+		return NewDomainError("invalid weekday")
+	}
+	if schedule.Hour < 0 || schedule.Hour > 23 {
+		return NewDomainError("hour must be between 0 and 23")
+	}
+	if !schedule.Format.IsValid() {
+		return NewDomainError(fmt.Sprintf("unknown format %q, expected csv or pdf", schedule.Format))
+	}
+
+	recipients := make([]string, 0, len(schedule.Recipients))
+	seen := make(map[string]bool, len(schedule.Recipients))
+	for _, recipient := range schedule.Recipients {
+		recipient = strings.TrimSpace(recipient)
+		if recipient == "" || seen[strings.ToLower(recipient)] {
+			continue
+		}
+		if address, err := mail.ParseAddress(recipient); err != nil || address.Address != recipient {
+			return NewDomainError(fmt.Sprintf("invalid recipient %q", recipient))
+		}
+		seen[strings.ToLower(recipient)] = true
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) == 0 {
+		return NewDomainError("at least one recipient is required")
+	}
+	if len(recipients) > maxReportRecipients {
+		return NewDomainError(fmt.Sprintf("a report is emailed to at most %d recipients", maxReportRecipients))
+	}
+
+	s.Params = make(map[string]string, len(schedule.Params))
+	for key, value := range schedule.Params {
+		s.Params[key] = value
+	}
+	s.Frequency = schedule.Frequency
+	s.Weekday = schedule.Weekday
+	if s.Frequency != ReportFrequencyWeekly {
+		s.Weekday = time.Sunday
+	}
+	s.Hour = schedule.Hour
+	s.Format = schedule.Format
+	s.Recipients = recipients
+	s.NextRunAt = s.NextRun(now)
+	s.UpdatedAt = now
+	return nil
+}
+
+// SetActive pauses or resumes deliveries. A resumed subscription skips the
+// deliveries missed while paused.
+func (s *ReportSubscription) SetActive(active bool, now time.Time) {
+	if active && !s.Active {
+		s.NextRunAt = s.NextRun(now)
+	}
+	s.Active = active
+	s.UpdatedAt = now
+}
+
+// NextRun returns the first delivery time of the schedule after the given time
+func (s *ReportSubscription) NextRun(after time.Time) time.Time {
+	after = after.UTC()
+	next := time.Date(after.Year(), after.Month(), after.Day(), s.Hour, 0, 0, 0, time.UTC)
+	if s.Frequency == ReportFrequencyWeekly {
+		next = next.AddDate(0, 0, (int(s.Weekday)-int(next.Weekday())+7)%7)
+		if !next.After(after) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	}
+	if !next.After(after) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Period returns the range [from, to) a delivery at runAt reports on: the day
+// before it, or the seven days before it for weekly reports
+func (s *ReportSubscription) Period(runAt time.Time) (time.Time, time.Time) {
+	runAt = runAt.UTC()
+	to := time.Date(runAt.Year(), runAt.Month(), runAt.Day(), 0, 0, 0, 0, time.UTC)
+	if s.Frequency == ReportFrequencyWeekly {
+		return to.AddDate(0, 0, -7), to
+	}
+	return to.AddDate(0, 0, -1), to
+}
+
+// RecordRun records a delivery and schedules the next one. Deliveries missed while
+// the scheduler was down are skipped rather than sent in a burst.
+func (s *ReportSubscription) RecordRun(jobID string, now time.Time) {
+	s.LastRunAt = &now
+	s.LastJobID = jobID
+	s.NextRunAt = s.NextRun(now)
+	s.UpdatedAt = now
+}
+
+// IsOwnedBy reports whether the admin user created the subscription
+func (s *ReportSubscription) IsOwnedBy(adminUserID int64) bool {
+	return s.AdminUserID == adminUserID
+}
+
+// ReportSubscriptionRepository defines the interface for report subscription persistence
+type ReportSubscriptionRepository interface {
+	// Create stores a new subscription
+	Create(ctx context.Context, sub *ReportSubscription) error
+
+	// Update stores the subscription
+	Update(ctx context.Context, sub *ReportSubscription) error
+
+	// Delete removes a subscription
+	Delete(ctx context.Context, id int64) error
+
+	// FindByID retrieves a subscription
+	FindByID(ctx context.Context, id int64) (*ReportSubscription, error)
+
+	// FindByAdminUserID retrieves the subscriptions of an admin user
+	FindByAdminUserID(ctx context.Context, adminUserID int64) ([]*ReportSubscription, error)
+
+	// ClaimDue leases up to limit active subscriptions due by now, pushing their next
+	// run to the end of the lease so other instances skip them
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*ReportSubscription, error)
+}
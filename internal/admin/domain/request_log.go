@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/requestlog"
+)
+
+// RequestLogFilter selects admin API request log entries. Entries are returned newest first.
+type RequestLogFilter struct {
+	From       *time.Time
+	To         *time.Time
+	ActorID    string
+	SessionID  string
+	Method     string
+	PathPrefix string
+	Status     int  // Exact status code; 0 matches any
+	ErrorsOnly bool // Only requests answered with 4xx or 5xx
+	IPAddress  string
+	Cursor     *AuditLogCursor // Same keyset cursor as the audit log
+	Limit      int
+}
+
+// RequestLogPage is a page of request log entries
+type RequestLogPage struct {
+	Entries    []*requestlog.Entry
+	NextCursor *AuditLogCursor // Nil on the last page
+	Total      int64           // Entries matching the filter across all pages
+}
+
+// RequestLogRepository stores the admin API request log. It implements
+// requestlog.Store so the request log middleware can write to it directly.
+type RequestLogRepository interface {
+	requestlog.Store
+
+	// FindByID returns one entry with its headers and bodies
+	FindByID(ctx context.Context, id string) (*requestlog.Entry, error)
+
+	// Search returns a page of entries matching the filter with the total count
+	Search(ctx context.Context, filter *RequestLogFilter) (*RequestLogPage, error)
+
+	// DeleteBefore deletes up to batchSize entries older than cutoff
+	DeleteBefore(ctx context.Context, cutoff time.Time, batchSize int) (int64, error)
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SavedViewList names the admin list endpoint a saved view applies to
+type SavedViewList string
+
+const (
+	SavedViewListOrders     SavedViewList = "orders"
+	SavedViewListProducts   SavedViewList = "products"
+	SavedViewListCustomers  SavedViewList = "customers"
+	SavedViewListOrderQueue SavedViewList = "order_queue" // Ops work queues of tagged orders
+)
+
+// savedViewFilters lists the query parameters each admin list accepts, so a
+// saved view only stores filters its list understands
+var savedViewFilters = map[SavedViewList][]string{
+	SavedViewListOrders:     {"status", "customer_id", "sort_by", "sort_order", "page_size"},
+	SavedViewListProducts:   {"include_archived", "active_only", "preview_date", "publish_status", "sort_by", "sort_order", "page_size"},
+	SavedViewListCustomers:  {"include_archived", "active_only", "registered_only", "q", "tag", "sort_by", "sort_order", "page_size"},
+	SavedViewListOrderQueue: {"all_tags", "any_tags", "exclude_tags", "status", "sort", "page_size"},
+}
+
+// IsValid reports whether the list is one saved views apply to
+func (l SavedViewList) IsValid() bool {
+	_, ok := savedViewFilters[l]
+	return ok
+}
+
+// AllowsFilter reports whether the list accepts the query parameter
+func (l SavedViewList) AllowsFilter(key string) bool {
+	for _, allowed := range savedViewFilters[l] {
+		if allowed == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SavedView is a named set of filters and visible columns of an admin list,
+// owned by the admin user who saved it and optionally shared with roles
+type SavedView struct {
+	ID            int64
+	AdminUserID   int64
+	List          SavedViewList
+	Name          string
+	Filters       map[string]string // List query parameters, as sent on the URL
+	Columns       []string          // Columns to display, in order; empty shows the defaults
+	SharedRoleIDs []int64           // Roles whose members can use the view
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewSavedView creates a new saved view of an admin list
+func NewSavedView(adminUserID int64, list SavedViewList, name string, filters map[string]string, columns []string) (*SavedView, error) {
+	if adminUserID == 0 {
+		return nil, NewDomainError("a saved view requires an admin user")
+	}
+	if !list.IsValid() {
+		return nil, NewDomainError(fmt.Sprintf("unknown list %q", list))
+	}
+
+	now := time.Now()
+	view := &SavedView{
+		AdminUserID:   adminUserID,
+		List:          list,
+		SharedRoleIDs: make([]int64, 0),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := view.Update(name, filters, columns); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// Update replaces the name, filters and columns of the view
+func (v *SavedView) Update(name string, filters map[string]string, columns []string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return NewDomainError("saved view name is required")
+	}
+	for key := range filters {
+		if !v.List.AllowsFilter(key) {
+			return NewDomainError(fmt.Sprintf("filter %q is not supported by the %s list", key, v.List))
+		}
+	}
+
+	v.Name = name
+	v.Filters = make(map[string]string, len(filters))
+	for key, value := range filters {
+		v.Filters[key] = value
+	}
+	v.Columns = make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column = strings.TrimSpace(column); column != "" {
+			v.Columns = append(v.Columns, column)
+		}
+	}
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// ShareWith replaces the roles the view is shared with
+func (v *SavedView) ShareWith(roleIDs []int64) {
+	seen := make(map[int64]bool, len(roleIDs))
+	v.SharedRoleIDs = make([]int64, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		if !seen[roleID] {
+			seen[roleID] = true
+			v.SharedRoleIDs = append(v.SharedRoleIDs, roleID)
+		}
+	}
+	v.UpdatedAt = time.Now()
+}
+
+// IsOwnedBy reports whether the admin user saved the view
+func (v *SavedView) IsOwnedBy(adminUserID int64) bool {
+	return v.AdminUserID == adminUserID
+}
+
+// VisibleTo reports whether an admin user holding the given roles can use the view
+func (v *SavedView) VisibleTo(adminUserID int64, roleIDs []int64) bool {
+	if v.IsOwnedBy(adminUserID) {
+		return true
+	}
+	for _, shared := range v.SharedRoleIDs {
+		for _, roleID := range roleIDs {
+			if shared == roleID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SavedViewRepository defines the interface for saved view persistence
+type SavedViewRepository interface {
+	// Create stores a new saved view with its shared roles
+	Create(ctx context.Context, view *SavedView) error
+
+	// Update stores the view and replaces its shared roles
+	Update(ctx context.Context, view *SavedView) error
+
+	// Delete removes a saved view
+	Delete(ctx context.Context, id int64) error
+
+	// FindByID retrieves a saved view with its shared roles
+	FindByID(ctx context.Context, id int64) (*SavedView, error)
+
+	// FindVisible retrieves the views of a list owned by the admin user or
+	// shared with one of their roles; an empty list retrieves every list
+	FindVisible(ctx context.Context, adminUserID int64, list SavedViewList) ([]*SavedView, error)
+}
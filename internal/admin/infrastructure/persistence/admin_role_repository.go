@@ -0,0 +1,243 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresAdminRoleRepository implements the AdminRoleRepository interface
+// on the legacy admin role and permission tables
+type PostgresAdminRoleRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAdminRoleRepository creates a new PostgresAdminRoleRepository
+func NewPostgresAdminRoleRepository(db *database.DB) *PostgresAdminRoleRepository {
+	return &PostgresAdminRoleRepository{db: db}
+}
+
+const adminRoleColumns = `admin_role_id, name, description, date_created, date_updated`
+
+// Create stores a new role with its permissions, creating permissions that do not exist yet
+func (r *PostgresAdminRoleRepository) Create(ctx context.Context, role *domain.AdminRole) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		query := `
+			INSERT INTO blc_admin_role (name, description, date_created, date_updated)
+			VALUES ($1, $2, $3, $4)
+			RETURNING admin_role_id`
+		if err := tx.QueryRow(ctx, query, role.Name, role.Description, role.CreatedAt, role.UpdatedAt).Scan(&role.ID); err != nil {
+			return errors.InternalWrap(err, "failed to create admin role")
+		}
+		return grantPermissions(ctx, tx, role.ID, role.Permissions)
+	})
+}
+
+// AddPermissions grants permissions to a role, creating permissions that do not exist yet
+func (r *PostgresAdminRoleRepository) AddPermissions(ctx context.Context, roleID int64, permissions []*domain.AdminPermission) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE blc_admin_role SET date_updated = $2 WHERE admin_role_id = $1`, roleID, time.Now()); err != nil {
+			return errors.InternalWrap(err, "failed to update admin role")
+		}
+		return grantPermissions(ctx, tx, roleID, permissions)
+	})
+}
+
+// FindByID retrieves a role with its permissions
+func (r *PostgresAdminRoleRepository) FindByID(ctx context.Context, id int64) (*domain.AdminRole, error) {
+	query := `SELECT ` + adminRoleColumns + ` FROM blc_admin_role WHERE admin_role_id = $1`
+	return r.findOne(ctx, query, id)
+}
+
+// FindByName retrieves a role with its permissions by name
+func (r *PostgresAdminRoleRepository) FindByName(ctx context.Context, name string) (*domain.AdminRole, error) {
+	query := `SELECT ` + adminRoleColumns + ` FROM blc_admin_role WHERE name = $1 ORDER BY admin_role_id LIMIT 1`
+	return r.findOne(ctx, query, name)
+}
+
+// FindAll retrieves every role with its permissions
+func (r *PostgresAdminRoleRepository) FindAll(ctx context.Context) ([]*domain.AdminRole, error) {
+	query := `SELECT ` + adminRoleColumns + ` FROM blc_admin_role ORDER BY name, admin_role_id`
+	return r.findAll(ctx, query)
+}
+
+// FindByAdminUserID retrieves the roles assigned to an admin user
+func (r *PostgresAdminRoleRepository) FindByAdminUserID(ctx context.Context, adminUserID int64) ([]*domain.AdminRole, error) {
+	query := `
+		SELECT r.admin_role_id, r.name, r.description, r.date_created, r.date_updated
+		FROM blc_admin_role r
+		JOIN blc_admin_user_role_xref x ON x.admin_role_id = r.admin_role_id
+		WHERE x.admin_user_id = $1
+		ORDER BY r.name, r.admin_role_id`
+	return r.findAll(ctx, query, adminUserID)
+}
+
+// FindUserPermissions retrieves the permissions granted to an admin user directly, outside any role
+func (r *PostgresAdminRoleRepository) FindUserPermissions(ctx context.Context, adminUserID int64) ([]*domain.AdminPermission, error) {
+	query := `
+		SELECT p.admin_permission_id, p.name, p.description, p.permission_type
+		FROM blc_admin_permission p
+		JOIN blc_admin_user_permission_xref x ON x.admin_permission_id = p.admin_permission_id
+		WHERE x.admin_user_id = $1
+		ORDER BY p.name`
+	rows, err := r.db.Query(ctx, query, adminUserID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list admin user permissions")
+	}
+	defer rows.Close()
+
+	var permissions []*domain.AdminPermission
+	for rows.Next() {
+		permission := &domain.AdminPermission{}
+		var permissionType string
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permissionType); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan admin permission")
+		}
+		permission.Type = domain.PermissionType(permissionType)
+		permissions = append(permissions, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to list admin user permissions")
+	}
+	return permissions, nil
+}
+
+func (r *PostgresAdminRoleRepository) findOne(ctx context.Context, query string, args ...interface{}) (*domain.AdminRole, error) {
+	role, err := scanAdminRole(r.db.QueryRow(ctx, query, args...))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("admin role")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find admin role")
+	}
+	if err := r.loadPermissions(ctx, []*domain.AdminRole{role}); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *PostgresAdminRoleRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*domain.AdminRole, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list admin roles")
+	}
+	defer rows.Close()
+
+	var roles []*domain.AdminRole
+	for rows.Next() {
+		role, err := scanAdminRole(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan admin role")
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to list admin roles")
+	}
+	if err := r.loadPermissions(ctx, roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// loadPermissions fills in the permissions of the roles with one query
+func (r *PostgresAdminRoleRepository) loadPermissions(ctx context.Context, roles []*domain.AdminRole) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*domain.AdminRole, len(roles))
+	roleIDs := make([]int64, len(roles))
+	for i, role := range roles {
+		byID[role.ID] = role
+		roleIDs[i] = role.ID
+	}
+
+	query := `
+		SELECT x.admin_role_id, p.admin_permission_id, p.name, p.description, p.permission_type
+		FROM blc_admin_role_permission_xref x
+		JOIN blc_admin_permission p ON p.admin_permission_id = x.admin_permission_id
+		WHERE x.admin_role_id = ANY($1::bigint[])
+		ORDER BY p.name`
+	rows, err := r.db.Query(ctx, query, roleIDs)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to load admin role permissions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var roleID int64
+		var permissionType string
+		permission := &domain.AdminPermission{}
+		if err := rows.Scan(&roleID, &permission.ID, &permission.Name, &permission.Description, &permissionType); err != nil {
+			return errors.InternalWrap(err, "failed to scan admin permission")
+		}
+		permission.Type = domain.PermissionType(permissionType)
+		if role, ok := byID[roleID]; ok {
+			role.Permissions = append(role.Permissions, permission)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to load admin role permissions")
+	}
+	return nil
+}
+
+// grantPermissions upserts permissions by name and links them to a role
+func grantPermissions(ctx context.Context, tx pgx.Tx, roleID int64, permissions []*domain.AdminPermission) error {
+	seen := make(map[string]bool, len(permissions))
+	var names, descriptions, types []string
+	for _, permission := range permissions {
+		if seen[permission.Name] {
+			continue
+		}
+		seen[permission.Name] = true
+		names = append(names, permission.Name)
+		descriptions = append(descriptions, permission.Description)
+		types = append(types, string(permission.Type))
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	// Existing permissions keep their description and type
+	query := `
+		INSERT INTO blc_admin_permission (name, description, permission_type, is_friendly)
+		SELECT name, description, permission_type, TRUE
+		FROM unnest($1::text[], $2::text[], $3::text[]) AS p(name, description, permission_type)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING admin_permission_id`
+	rows, err := tx.Query(ctx, query, names, descriptions, types)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save admin permissions")
+	}
+	permissionIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save admin permissions")
+	}
+
+	xrefQuery := `
+		INSERT INTO blc_admin_role_permission_xref (admin_role_id, admin_permission_id)
+		SELECT $1, unnest($2::bigint[])
+		ON CONFLICT DO NOTHING`
+	if _, err := tx.Exec(ctx, xrefQuery, roleID, permissionIDs); err != nil {
+		return errors.InternalWrap(err, "failed to grant admin role permissions")
+	}
+	return nil
+}
+
+func scanAdminRole(row pgx.Row) (*domain.AdminRole, error) {
+	role := &domain.AdminRole{}
+	var createdAt, updatedAt sql.NullTime
+	if err := row.Scan(&role.ID, &role.Name, &role.Description, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	role.CreatedAt = createdAt.Time
+	role.UpdatedAt = updatedAt.Time
+	return role, nil
+}
@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresAdminSessionRepository implements the AdminSessionRepository interface
+type PostgresAdminSessionRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAdminSessionRepository creates a new PostgresAdminSessionRepository
+func NewPostgresAdminSessionRepository(db *database.DB) *PostgresAdminSessionRepository {
+	return &PostgresAdminSessionRepository{db: db}
+}
+
+const adminSessionColumns = `
+	session_id, admin_user_id, device, ip_address, created_at, last_activity_at, expires_at, revoked_at`
+
+// Create saves a new session
+func (r *PostgresAdminSessionRepository) Create(ctx context.Context, session *domain.AdminSession) error {
+	query := `
+		INSERT INTO admin_session (
+			session_id, admin_user_id, device, ip_address, created_at, last_activity_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	err := r.db.Exec(ctx, query,
+		session.ID, session.AdminUserID, session.Device, session.IPAddress,
+		session.CreatedAt, session.LastActivityAt, session.ExpiresAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create admin session")
+	}
+	return nil
+}
+
+// FindByID retrieves a session by ID
+func (r *PostgresAdminSessionRepository) FindByID(ctx context.Context, id string) (*domain.AdminSession, error) {
+	query := `SELECT` + adminSessionColumns + ` FROM admin_session WHERE session_id = $1`
+
+	session, err := scanAdminSession(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("admin session")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find admin session")
+	}
+	return session, nil
+}
+
+// FindByAdminUserID retrieves the sessions of an admin user, newest first
+func (r *PostgresAdminSessionRepository) FindByAdminUserID(ctx context.Context, adminUserID int64, activeOnly bool) ([]*domain.AdminSession, error) {
+	query := `SELECT` + adminSessionColumns + ` FROM admin_session WHERE admin_user_id = $1`
+	if activeOnly {
+		query += ` AND revoked_at IS NULL AND expires_at > NOW()`
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, adminUserID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list admin sessions")
+	}
+	defer rows.Close()
+
+	sessions := make([]*domain.AdminSession, 0)
+	for rows.Next() {
+		session, err := scanAdminSession(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan admin session")
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate admin sessions")
+	}
+	return sessions, nil
+}
+
+// UpdateLastActivity records activity on a session
+func (r *PostgresAdminSessionRepository) UpdateLastActivity(ctx context.Context, id string, at time.Time) error {
+	if err := r.db.Exec(ctx, `UPDATE admin_session SET last_activity_at = $2 WHERE session_id = $1`, id, at); err != nil {
+		return errors.InternalWrap(err, "failed to update admin session activity")
+	}
+	return nil
+}
+
+// Revoke marks a session as revoked
+func (r *PostgresAdminSessionRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	result, err := r.db.Pool().Exec(ctx,
+		`UPDATE admin_session SET revoked_at = $2 WHERE session_id = $1 AND revoked_at IS NULL`, id, at)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to revoke admin session")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("admin session")
+	}
+	return nil
+}
+
+func scanAdminSession(row pgx.Row) (*domain.AdminSession, error) {
+	session := &domain.AdminSession{}
+	err := row.Scan(
+		&session.ID, &session.AdminUserID, &session.Device, &session.IPAddress,
+		&session.CreatedAt, &session.LastActivityAt, &session.ExpiresAt, &session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
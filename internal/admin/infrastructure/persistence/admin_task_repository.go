@@ -0,0 +1,181 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresAdminTaskRepository implements the AdminTaskRepository interface
+type PostgresAdminTaskRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAdminTaskRepository creates a new PostgresAdminTaskRepository
+func NewPostgresAdminTaskRepository(db *database.DB) *PostgresAdminTaskRepository {
+	return &PostgresAdminTaskRepository{db: db}
+}
+
+const adminTaskColumns = ` admin_task_id, task_type, title, description, entity_type, entity_id, data,
+	assigned_role_id, assigned_user_id, status, claimed_by, claimed_at, due_at, outcome, note,
+	completed_by, completed_at, created_by, created_at, updated_at`
+
+// Create stores a new task
+func (r *PostgresAdminTaskRepository) Create(ctx context.Context, task *domain.AdminTask) error {
+	data, err := json.Marshal(task.Data)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode admin task data")
+	}
+
+	query := `
+		INSERT INTO admin_task (
+			task_type, title, description, entity_type, entity_id, data,
+			assigned_role_id, assigned_user_id, status, due_at, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING admin_task_id`
+	err = r.db.QueryRow(ctx, query,
+		task.Type, task.Title, task.Description, task.EntityType, task.EntityID, data,
+		task.AssignedRoleID, task.AssignedUserID, string(task.Status), task.DueAt, task.CreatedBy, task.CreatedAt, task.UpdatedAt,
+	).Scan(&task.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create admin task")
+	}
+	return nil
+}
+
+// Update stores the assignment and state of a task
+func (r *PostgresAdminTaskRepository) Update(ctx context.Context, task *domain.AdminTask) error {
+	query := `
+		UPDATE admin_task
+		SET assigned_role_id = $2, assigned_user_id = $3, status = $4, claimed_by = $5, claimed_at = $6,
+			due_at = $7, outcome = $8, note = $9, completed_by = $10, completed_at = $11, updated_at = $12
+		WHERE admin_task_id = $1`
+	tag, err := r.db.Pool().Exec(ctx, query,
+		task.ID, task.AssignedRoleID, task.AssignedUserID, string(task.Status), task.ClaimedBy, task.ClaimedAt,
+		task.DueAt, task.Outcome, task.Note, task.CompletedBy, task.CompletedAt, task.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update admin task")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("admin task %d", task.ID))
+	}
+	return nil
+}
+
+// FindByID retrieves a task
+func (r *PostgresAdminTaskRepository) FindByID(ctx context.Context, id int64) (*domain.AdminTask, error) {
+	query := `SELECT` + adminTaskColumns + ` FROM admin_task WHERE admin_task_id = $1`
+
+	task, err := scanAdminTask(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound(fmt.Sprintf("admin task %d", id))
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find admin task")
+	}
+	return task, nil
+}
+
+// FindActiveByEntity retrieves the open or claimed tasks of a type about a record
+func (r *PostgresAdminTaskRepository) FindActiveByEntity(ctx context.Context, taskType, entityType, entityID string) ([]*domain.AdminTask, error) {
+	query := `SELECT` + adminTaskColumns + `
+		FROM admin_task
+		WHERE task_type = $1 AND entity_type = $2 AND entity_id = $3 AND status IN ('OPEN', 'CLAIMED')
+		ORDER BY admin_task_id`
+	return r.queryTasks(ctx, query, taskType, entityType, entityID)
+}
+
+// Search retrieves a page of tasks matching the filter with the total count
+func (r *PostgresAdminTaskRepository) Search(ctx context.Context, filter *domain.AdminTaskFilter) ([]*domain.AdminTask, int64, error) {
+	where := auditWhere{}
+	if filter.AssigneeUserID != 0 {
+		where.add("(assigned_user_id = ? OR (assigned_user_id IS NULL AND assigned_role_id = ANY(?)))", filter.AssigneeUserID, filter.AssigneeRoleIDs)
+	}
+	if filter.ClaimedBy != 0 {
+		where.add("claimed_by = ?", filter.ClaimedBy)
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		where.add("status = ANY(?)", statuses)
+	}
+	if filter.Type != "" {
+		where.add("task_type = ?", filter.Type)
+	}
+	if filter.EntityType != "" {
+		where.add("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		where.add("entity_id = ?", filter.EntityID)
+	}
+	if filter.DueBefore != nil {
+		where.add("due_at < ?", *filter.DueBefore)
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM admin_task`+where.sql(), where.args...).Scan(&total); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count admin tasks")
+	}
+
+	args := append(where.args, filter.Limit, filter.Offset)
+	query := `SELECT` + adminTaskColumns + ` FROM admin_task` + where.sql() +
+		fmt.Sprintf(` ORDER BY due_at ASC NULLS LAST, created_at ASC, admin_task_id ASC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	tasks, err := r.queryTasks(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+func (r *PostgresAdminTaskRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*domain.AdminTask, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query admin tasks")
+	}
+	defer rows.Close()
+
+	tasks := make([]*domain.AdminTask, 0)
+	for rows.Next() {
+		task, err := scanAdminTask(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan admin task")
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate admin tasks")
+	}
+	return tasks, nil
+}
+
+func scanAdminTask(row pgx.Row) (*domain.AdminTask, error) {
+	task := &domain.AdminTask{}
+	var status string
+	var data []byte
+	err := row.Scan(
+		&task.ID, &task.Type, &task.Title, &task.Description, &task.EntityType, &task.EntityID, &data,
+		&task.AssignedRoleID, &task.AssignedUserID, &status, &task.ClaimedBy, &task.ClaimedAt, &task.DueAt, &task.Outcome, &task.Note,
+		&task.CompletedBy, &task.CompletedAt, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Status = domain.AdminTaskStatus(status)
+	if err := json.Unmarshal(data, &task.Data); err != nil {
+		return nil, fmt.Errorf("invalid data of admin task %d: %w", task.ID, err)
+	}
+	if task.Data == nil {
+		task.Data = make(map[string]string)
+	}
+	return task, nil
+}
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresAdminUserRepository implements the AdminUserRepository interface
+type PostgresAdminUserRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAdminUserRepository creates a new PostgresAdminUserRepository
+func NewPostgresAdminUserRepository(db *database.DB) *PostgresAdminUserRepository {
+	return &PostgresAdminUserRepository{db: db}
+}
+
+// FindByLogin retrieves an admin user by login name or email
+func (r *PostgresAdminUserRepository) FindByLogin(ctx context.Context, login string) (*domain.AdminUser, error) {
+	query := `
+		SELECT admin_user_id, name, login, email, password, active_status_flag, archived
+		FROM blc_admin_user
+		WHERE login = $1 OR LOWER(email) = LOWER($1)
+		ORDER BY (login = $1) DESC
+		LIMIT 1`
+	return scanAdminUser(r.db.QueryRow(ctx, query, login))
+}
+
+// FindByID retrieves an admin user by ID
+func (r *PostgresAdminUserRepository) FindByID(ctx context.Context, id int64) (*domain.AdminUser, error) {
+	query := `
+		SELECT admin_user_id, name, login, email, password, active_status_flag, archived
+		FROM blc_admin_user
+		WHERE admin_user_id = $1`
+	return scanAdminUser(r.db.QueryRow(ctx, query, id))
+}
+
+func scanAdminUser(row pgx.Row) (*domain.AdminUser, error) {
+	user := &domain.AdminUser{}
+	var password sql.NullString
+	var active sql.NullBool
+	var archived sql.NullString
+	err := row.Scan(
+		&user.ID, &user.Name, &user.Login, &user.Email, &password, &active, &archived,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("admin user")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find admin user")
+	}
+
+	user.PasswordHash = password.String
+	user.Active = !active.Valid || active.Bool
+	user.Archived = archived.Valid && archived.String == "Y"
+	return user, nil
+}
@@ -0,0 +1,263 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresAuditLogRepository implements the AuditLogRepository interface
+type PostgresAuditLogRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAuditLogRepository creates a new PostgresAuditLogRepository
+func NewPostgresAuditLogRepository(db *database.DB) *PostgresAuditLogRepository {
+	return &PostgresAuditLogRepository{db: db}
+}
+
+const auditLogColumns = `
+	audit_id, occurred_at, actor_id, actor_name, entity_type, entity_id, action,
+	ip_address, user_agent, changes, metadata`
+
+// Log saves an audit entry
+func (r *PostgresAuditLogRepository) Log(ctx context.Context, entry *audit.AuditEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	changes, err := marshalAuditJSON(entry.Changes)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode audit changes")
+	}
+	metadata, err := marshalAuditJSON(entry.Metadata)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode audit metadata")
+	}
+
+	query := `INSERT INTO admin_audit_log (` + auditLogColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	err = r.db.Exec(ctx, query,
+		entry.ID, entry.Timestamp, entry.UserID, entry.Username, entry.EntityType, entry.EntityID,
+		string(entry.Action), entry.IPAddress, entry.UserAgent, changes, metadata,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save audit entry")
+	}
+	return nil
+}
+
+// Query retrieves audit entries with offset pagination, newest first
+func (r *PostgresAuditLogRepository) Query(ctx context.Context, filter *audit.AuditFilter) ([]*audit.AuditEntry, error) {
+	where := auditWhere{}
+	if filter.EntityType != nil {
+		where.add("entity_type = ?", *filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		where.add("entity_id = ?", *filter.EntityID)
+	}
+	if filter.UserID != nil {
+		where.add("actor_id = ?", *filter.UserID)
+	}
+	if filter.Action != nil {
+		where.add("action = ?", string(*filter.Action))
+	}
+	if len(filter.Actions) > 0 {
+		where.add("action = ANY(?)", actionStrings(filter.Actions))
+	}
+	if filter.IPAddress != nil {
+		where.add("ip_address = ?", *filter.IPAddress)
+	}
+	if filter.StartTime != nil {
+		where.add("occurred_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		where.add("occurred_at <= ?", *filter.EndTime)
+	}
+
+	query := `SELECT` + auditLogColumns + ` FROM admin_audit_log` + where.sql() +
+		` ORDER BY occurred_at DESC, audit_id DESC`
+	args := where.args
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return r.queryEntries(ctx, query, args...)
+}
+
+// Search returns a page of entries after the filter's cursor with the total count
+func (r *PostgresAuditLogRepository) Search(ctx context.Context, filter *domain.AuditLogFilter) (*domain.AuditLogPage, error) {
+	where := auditWhere{}
+	if filter.From != nil {
+		where.add("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		where.add("occurred_at <= ?", *filter.To)
+	}
+	if filter.ActorID != "" {
+		where.add("actor_id = ?", filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		where.add("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		where.add("entity_id = ?", filter.EntityID)
+	}
+	if len(filter.Actions) > 0 {
+		where.add("action = ANY(?)", actionStrings(filter.Actions))
+	}
+	if filter.IPAddress != "" {
+		where.add("ip_address = ?", filter.IPAddress)
+	}
+
+	page := &domain.AuditLogPage{}
+	countQuery := `SELECT COUNT(*) FROM admin_audit_log` + where.sql()
+	if err := r.db.QueryRow(ctx, countQuery, where.args...).Scan(&page.Total); err != nil {
+		return nil, errors.InternalWrap(err, "failed to count audit entries")
+	}
+
+	// The cursor only narrows the page, not the total
+	if filter.Cursor != nil {
+		where.add("(occurred_at, audit_id) < (?, ?)", filter.Cursor.OccurredAt, filter.Cursor.ID)
+	}
+	args := append(where.args, filter.Limit+1)
+	query := `SELECT` + auditLogColumns + ` FROM admin_audit_log` + where.sql() +
+		fmt.Sprintf(` ORDER BY occurred_at DESC, audit_id DESC LIMIT $%d`, len(args))
+
+	entries, err := r.queryEntries(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+		last := entries[len(entries)-1]
+		page.NextCursor = &domain.AuditLogCursor{OccurredAt: last.Timestamp, ID: last.ID}
+	}
+	page.Entries = entries
+	return page, nil
+}
+
+// ArchiveBefore moves up to batchSize entries older than cutoff to the archive table
+func (r *PostgresAuditLogRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM admin_audit_log
+			WHERE audit_id IN (
+				SELECT audit_id FROM admin_audit_log
+				WHERE occurred_at < $1
+				ORDER BY occurred_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + auditLogColumns + `
+		)
+		INSERT INTO admin_audit_log_archive (` + auditLogColumns + `)
+		SELECT ` + auditLogColumns + ` FROM moved
+		ON CONFLICT (audit_id) DO NOTHING`
+
+	result, err := r.db.Pool().Exec(ctx, query, cutoff, batchSize)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to archive audit entries")
+	}
+	return result.RowsAffected(), nil
+}
+
+func (r *PostgresAuditLogRepository) queryEntries(ctx context.Context, query string, args ...interface{}) ([]*audit.AuditEntry, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query audit entries")
+	}
+	defer rows.Close()
+
+	entries := make([]*audit.AuditEntry, 0)
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan audit entry")
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate audit entries")
+	}
+	return entries, nil
+}
+
+func scanAuditEntry(row pgx.Row) (*audit.AuditEntry, error) {
+	entry := &audit.AuditEntry{}
+	var action string
+	var changes, metadata []byte
+	err := row.Scan(
+		&entry.ID, &entry.Timestamp, &entry.UserID, &entry.Username, &entry.EntityType, &entry.EntityID,
+		&action, &entry.IPAddress, &entry.UserAgent, &changes, &metadata,
+	)
+	if err != nil {
+		return nil, err
+	}
+	entry.Action = audit.AuditAction(action)
+	if len(changes) > 0 {
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, err
+		}
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &entry.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// auditWhere collects filter conditions, numbering their ? placeholders
+type auditWhere struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (w *auditWhere) add(condition string, args ...interface{}) {
+	for _, arg := range args {
+		w.args = append(w.args, arg)
+		condition = strings.Replace(condition, "?", fmt.Sprintf("$%d", len(w.args)), 1)
+	}
+	w.conditions = append(w.conditions, condition)
+}
+
+func (w *auditWhere) sql() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+func actionStrings(actions []audit.AuditAction) []string {
+	values := make([]string, len(actions))
+	for i, action := range actions {
+		values[i] = string(action)
+	}
+	return values
+}
+
+func marshalAuditJSON(value map[string]interface{}) ([]byte, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
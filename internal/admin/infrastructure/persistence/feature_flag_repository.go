@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/featureflag"
+)
+
+// PostgresFeatureFlagRepository implements featureflag.Store, shared by the
+// admin switching flags and the storefront reading them
+type PostgresFeatureFlagRepository struct {
+	db *database.DB
+}
+
+// NewPostgresFeatureFlagRepository creates a new PostgresFeatureFlagRepository
+func NewPostgresFeatureFlagRepository(db *database.DB) *PostgresFeatureFlagRepository {
+	return &PostgresFeatureFlagRepository{db: db}
+}
+
+// List retrieves the flags that were switched
+func (r *PostgresFeatureFlagRepository) List(ctx context.Context) ([]*featureflag.Flag, error) {
+	rows, err := r.db.Query(ctx, `SELECT flag_key, enabled, note, updated_by, updated_at FROM feature_flag ORDER BY flag_key`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query feature flags")
+	}
+	defer rows.Close()
+
+	flags := make([]*featureflag.Flag, 0)
+	for rows.Next() {
+		flag := &featureflag.Flag{}
+		if err := rows.Scan(&flag.Key, &flag.Enabled, &flag.Note, &flag.UpdatedBy, &flag.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan feature flag")
+		}
+		flags = append(flags, flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate feature flags")
+	}
+	return flags, nil
+}
+
+// Save stores the state of a flag
+func (r *PostgresFeatureFlagRepository) Save(ctx context.Context, flag *featureflag.Flag) error {
+	err := r.db.Exec(ctx, `
+		INSERT INTO feature_flag (flag_key, enabled, note, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (flag_key) DO UPDATE
+		SET enabled = EXCLUDED.enabled, note = EXCLUDED.note, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`,
+		flag.Key, flag.Enabled, flag.Note, flag.UpdatedBy, flag.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save feature flag")
+	}
+	return nil
+}
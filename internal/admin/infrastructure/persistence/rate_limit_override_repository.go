@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/ratelimit"
+)
+
+// PostgresRateLimitOverrideRepository implements ratelimit.OverrideStore, shared
+// by the admin raising limits and the storefront applying them
+type PostgresRateLimitOverrideRepository struct {
+	db *database.DB
+}
+
+// NewPostgresRateLimitOverrideRepository creates a new PostgresRateLimitOverrideRepository
+func NewPostgresRateLimitOverrideRepository(db *database.DB) *PostgresRateLimitOverrideRepository {
+	return &PostgresRateLimitOverrideRepository{db: db}
+}
+
+// ListActive retrieves the overrides not expired at now
+func (r *PostgresRateLimitOverrideRepository) ListActive(ctx context.Context, now time.Time) ([]*ratelimit.Override, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT rate_limit_override_id, subject, bucket, requests, expires_at, note, created_by, created_at
+		FROM rate_limit_override
+		WHERE expires_at > $1
+		ORDER BY subject, bucket`, now)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query rate limit overrides")
+	}
+	defer rows.Close()
+
+	overrides := make([]*ratelimit.Override, 0)
+	for rows.Next() {
+		o := &ratelimit.Override{}
+		if err := rows.Scan(&o.ID, &o.Subject, &o.Bucket, &o.Requests, &o.ExpiresAt, &o.Note, &o.CreatedBy, &o.CreatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan rate limit override")
+		}
+		overrides = append(overrides, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate rate limit overrides")
+	}
+	return overrides, nil
+}
+
+// Save stores an override, replacing that of its subject and bucket
+func (r *PostgresRateLimitOverrideRepository) Save(ctx context.Context, o *ratelimit.Override) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO rate_limit_override (subject, bucket, requests, expires_at, note, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (subject, bucket) DO UPDATE
+		SET requests = EXCLUDED.requests, expires_at = EXCLUDED.expires_at, note = EXCLUDED.note,
+			created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at
+		RETURNING rate_limit_override_id`,
+		o.Subject, o.Bucket, o.Requests, o.ExpiresAt, o.Note, o.CreatedBy, o.CreatedAt,
+	).Scan(&o.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save rate limit override")
+	}
+	return nil
+}
+
+// Delete removes an override, reporting whether it existed
+func (r *PostgresRateLimitOverrideRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM rate_limit_override WHERE rate_limit_override_id = $1`, id)
+	if err != nil {
+		return false, errors.InternalWrap(err, "failed to delete rate limit override")
+	}
+	return tag.RowsAffected() > 0, nil
+}
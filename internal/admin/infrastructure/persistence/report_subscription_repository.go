@@ -0,0 +1,186 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresReportSubscriptionRepository implements the ReportSubscriptionRepository interface
+type PostgresReportSubscriptionRepository struct {
+	db *database.DB
+}
+
+// NewPostgresReportSubscriptionRepository creates a new PostgresReportSubscriptionRepository
+func NewPostgresReportSubscriptionRepository(db *database.DB) *PostgresReportSubscriptionRepository {
+	return &PostgresReportSubscriptionRepository{db: db}
+}
+
+const reportSubscriptionColumns = `
+	subscription_id, admin_user_id, report, params, frequency, weekday, hour, format, recipients,
+	active, next_run_at, last_run_at, last_job_id, created_at, updated_at`
+
+// Create stores a new subscription
+func (r *PostgresReportSubscriptionRepository) Create(ctx context.Context, sub *domain.ReportSubscription) error {
+	params, recipients, err := encodeReportSubscription(sub)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO admin_report_subscription (
+			admin_user_id, report, params, frequency, weekday, hour, format, recipients,
+			active, next_run_at, last_run_at, last_job_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING subscription_id`
+	err = r.db.QueryRow(ctx, query,
+		sub.AdminUserID, sub.Report, params, string(sub.Frequency), int(sub.Weekday), sub.Hour, string(sub.Format), recipients,
+		sub.Active, sub.NextRunAt, sub.LastRunAt, sub.LastJobID, sub.CreatedAt, sub.UpdatedAt,
+	).Scan(&sub.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create report subscription")
+	}
+	return nil
+}
+
+// Update stores the subscription
+func (r *PostgresReportSubscriptionRepository) Update(ctx context.Context, sub *domain.ReportSubscription) error {
+	params, recipients, err := encodeReportSubscription(sub)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE admin_report_subscription
+		SET params = $2, frequency = $3, weekday = $4, hour = $5, format = $6, recipients = $7,
+			active = $8, next_run_at = $9, last_run_at = $10, last_job_id = $11, updated_at = $12
+		WHERE subscription_id = $1`
+	tag, err := r.db.Pool().Exec(ctx, query,
+		sub.ID, params, string(sub.Frequency), int(sub.Weekday), sub.Hour, string(sub.Format), recipients,
+		sub.Active, sub.NextRunAt, sub.LastRunAt, sub.LastJobID, sub.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update report subscription")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("report subscription %d", sub.ID))
+	}
+	return nil
+}
+
+// Delete removes a subscription
+func (r *PostgresReportSubscriptionRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM admin_report_subscription WHERE subscription_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete report subscription")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("report subscription %d", id))
+	}
+	return nil
+}
+
+// FindByID retrieves a subscription
+func (r *PostgresReportSubscriptionRepository) FindByID(ctx context.Context, id int64) (*domain.ReportSubscription, error) {
+	query := `SELECT` + reportSubscriptionColumns + ` FROM admin_report_subscription WHERE subscription_id = $1`
+
+	sub, err := scanReportSubscription(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound(fmt.Sprintf("report subscription %d", id))
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find report subscription")
+	}
+	return sub, nil
+}
+
+// FindByAdminUserID retrieves the subscriptions of an admin user
+func (r *PostgresReportSubscriptionRepository) FindByAdminUserID(ctx context.Context, adminUserID int64) ([]*domain.ReportSubscription, error) {
+	query := `SELECT` + reportSubscriptionColumns + `
+		FROM admin_report_subscription
+		WHERE admin_user_id = $1
+		ORDER BY report, subscription_id`
+	return r.query(ctx, query, adminUserID)
+}
+
+// ClaimDue leases up to limit active subscriptions due by now. Rows locked by
+// another instance are skipped.
+func (r *PostgresReportSubscriptionRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.ReportSubscription, error) {
+	query := `
+		UPDATE admin_report_subscription
+		SET next_run_at = $1
+		WHERE subscription_id IN (
+			SELECT subscription_id FROM admin_report_subscription
+			WHERE active AND next_run_at <= $2
+			ORDER BY next_run_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING` + reportSubscriptionColumns
+	return r.query(ctx, query, now.Add(lease), now, limit)
+}
+
+func (r *PostgresReportSubscriptionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ReportSubscription, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query report subscriptions")
+	}
+	defer rows.Close()
+
+	subs := make([]*domain.ReportSubscription, 0)
+	for rows.Next() {
+		sub, err := scanReportSubscription(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan report subscription")
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to query report subscriptions")
+	}
+	return subs, nil
+}
+
+func scanReportSubscription(row pgx.Row) (*domain.ReportSubscription, error) {
+	sub := &domain.ReportSubscription{}
+	var frequency, format string
+	var weekday int
+	var params, recipients []byte
+	err := row.Scan(
+		&sub.ID, &sub.AdminUserID, &sub.Report, &params, &frequency, &weekday, &sub.Hour, &format, &recipients,
+		&sub.Active, &sub.NextRunAt, &sub.LastRunAt, &sub.LastJobID, &sub.CreatedAt, &sub.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.Frequency = domain.ReportFrequency(frequency)
+	sub.Weekday = time.Weekday(weekday)
+	sub.Format = domain.ReportFormat(format)
+	if err := json.Unmarshal(params, &sub.Params); err != nil {
+		return nil, fmt.Errorf("invalid params of report subscription %d: %w", sub.ID, err)
+	}
+	if err := json.Unmarshal(recipients, &sub.Recipients); err != nil {
+		return nil, fmt.Errorf("invalid recipients of report subscription %d: %w", sub.ID, err)
+	}
+	return sub, nil
+}
+
+func encodeReportSubscription(sub *domain.ReportSubscription) ([]byte, []byte, error) {
+	params, err := json.Marshal(sub.Params)
+	if err != nil {
+		return nil, nil, errors.InternalWrap(err, "failed to encode report subscription params")
+	}
+	recipients, err := json.Marshal(sub.Recipients)
+	if err != nil {
+		return nil, nil, errors.InternalWrap(err, "failed to encode report subscription recipients")
+	}
+	return params, recipients, nil
+}
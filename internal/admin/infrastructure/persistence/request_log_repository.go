@@ -0,0 +1,198 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/requestlog"
+)
+
+// PostgresRequestLogRepository implements the RequestLogRepository interface
+type PostgresRequestLogRepository struct {
+	db *database.DB
+}
+
+// NewPostgresRequestLogRepository creates a new PostgresRequestLogRepository
+func NewPostgresRequestLogRepository(db *database.DB) *PostgresRequestLogRepository {
+	return &PostgresRequestLogRepository{db: db}
+}
+
+const requestLogColumns = `
+	request_id, occurred_at, method, path, query, status, duration_ms, actor_id, actor_email,
+	session_id, ip_address, user_agent, correlation_id, request_headers, request_body, response_body,
+	request_truncated, response_truncated`
+
+// requestLogSummaryColumns leave out headers and bodies, which only the detail view shows
+const requestLogSummaryColumns = `
+	request_id, occurred_at, method, path, query, status, duration_ms, actor_id, actor_email,
+	session_id, ip_address, user_agent, correlation_id, NULL, '', '', request_truncated, response_truncated`
+
+// SaveRequestLog saves a logged request
+func (r *PostgresRequestLogRepository) SaveRequestLog(ctx context.Context, entry *requestlog.Entry) error {
+	var headers []byte
+	if len(entry.RequestHeaders) > 0 {
+		encoded, err := json.Marshal(entry.RequestHeaders)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to encode request headers")
+		}
+		headers = encoded
+	}
+
+	query := `INSERT INTO admin_request_log (` + requestLogColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+	err := r.db.Exec(ctx, query,
+		entry.ID, entry.OccurredAt, entry.Method, truncate(entry.Path, 1024), entry.Query, entry.Status, entry.DurationMs,
+		entry.ActorID, entry.ActorEmail, entry.SessionID, entry.IPAddress, truncate(entry.UserAgent, 512),
+		entry.CorrelationID, headers, entry.RequestBody, entry.ResponseBody,
+		entry.RequestTruncated, entry.ResponseTruncated,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save request log entry")
+	}
+	return nil
+}
+
+// FindByID returns one entry with its headers and bodies
+func (r *PostgresRequestLogRepository) FindByID(ctx context.Context, id string) (*requestlog.Entry, error) {
+	query := `SELECT` + requestLogColumns + ` FROM admin_request_log WHERE request_id = $1`
+	entry, err := scanRequestLogEntry(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("request log entry")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to get request log entry")
+	}
+	return entry, nil
+}
+
+// Search returns a page of entries after the filter's cursor with the total count
+func (r *PostgresRequestLogRepository) Search(ctx context.Context, filter *domain.RequestLogFilter) (*domain.RequestLogPage, error) {
+	where := auditWhere{}
+	if filter.From != nil {
+		where.add("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		where.add("occurred_at <= ?", *filter.To)
+	}
+	if filter.ActorID != "" {
+		where.add("actor_id = ?", filter.ActorID)
+	}
+	if filter.SessionID != "" {
+		where.add("session_id = ?", filter.SessionID)
+	}
+	if filter.Method != "" {
+		where.add("method = ?", filter.Method)
+	}
+	if filter.PathPrefix != "" {
+		where.add("path LIKE ?", escapeLike(filter.PathPrefix)+"%")
+	}
+	if filter.Status != 0 {
+		where.add("status = ?", filter.Status)
+	}
+	if filter.ErrorsOnly {
+		where.add("status >= 400")
+	}
+	if filter.IPAddress != "" {
+		where.add("ip_address = ?", filter.IPAddress)
+	}
+
+	page := &domain.RequestLogPage{}
+	countQuery := `SELECT COUNT(*) FROM admin_request_log` + where.sql()
+	if err := r.db.QueryRow(ctx, countQuery, where.args...).Scan(&page.Total); err != nil {
+		return nil, errors.InternalWrap(err, "failed to count request log entries")
+	}
+
+	// The cursor only narrows the page, not the total
+	if filter.Cursor != nil {
+		where.add("(occurred_at, request_id) < (?, ?)", filter.Cursor.OccurredAt, filter.Cursor.ID)
+	}
+	args := append(where.args, filter.Limit+1)
+	query := `SELECT` + requestLogSummaryColumns + ` FROM admin_request_log` + where.sql() +
+		fmt.Sprintf(` ORDER BY occurred_at DESC, request_id DESC LIMIT $%d`, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query request log entries")
+	}
+	defer rows.Close()
+
+	entries := make([]*requestlog.Entry, 0)
+	for rows.Next() {
+		entry, err := scanRequestLogEntry(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan request log entry")
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate request log entries")
+	}
+
+	if len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+		last := entries[len(entries)-1]
+		page.NextCursor = &domain.AuditLogCursor{OccurredAt: last.OccurredAt, ID: last.ID}
+	}
+	page.Entries = entries
+	return page, nil
+}
+
+// DeleteBefore deletes up to batchSize entries older than cutoff
+func (r *PostgresRequestLogRepository) DeleteBefore(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	query := `
+		DELETE FROM admin_request_log
+		WHERE request_id IN (
+			SELECT request_id FROM admin_request_log
+			WHERE occurred_at < $1
+			ORDER BY occurred_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)`
+
+	result, err := r.db.Pool().Exec(ctx, query, cutoff, batchSize)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to delete request log entries")
+	}
+	return result.RowsAffected(), nil
+}
+
+func scanRequestLogEntry(row pgx.Row) (*requestlog.Entry, error) {
+	entry := &requestlog.Entry{}
+	var headers []byte
+	err := row.Scan(
+		&entry.ID, &entry.OccurredAt, &entry.Method, &entry.Path, &entry.Query, &entry.Status, &entry.DurationMs,
+		&entry.ActorID, &entry.ActorEmail, &entry.SessionID, &entry.IPAddress, &entry.UserAgent,
+		&entry.CorrelationID, &headers, &entry.RequestBody, &entry.ResponseBody,
+		&entry.RequestTruncated, &entry.ResponseTruncated,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &entry.RequestHeaders); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// escapeLike escapes LIKE wildcards so a prefix matches literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// truncate cuts a value to the width of its column
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return strings.ToValidUTF8(value[:max], "")
+}
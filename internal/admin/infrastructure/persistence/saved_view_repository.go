@@ -0,0 +1,226 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/admin/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresSavedViewRepository implements the SavedViewRepository interface
+type PostgresSavedViewRepository struct {
+	db *database.DB
+}
+
+// NewPostgresSavedViewRepository creates a new PostgresSavedViewRepository
+func NewPostgresSavedViewRepository(db *database.DB) *PostgresSavedViewRepository {
+	return &PostgresSavedViewRepository{db: db}
+}
+
+const savedViewColumns = `v.saved_view_id, v.admin_user_id, v.list_type, v.name, v.filters, v.columns, v.created_at, v.updated_at`
+
+// Create stores a new saved view with its shared roles
+func (r *PostgresSavedViewRepository) Create(ctx context.Context, view *domain.SavedView) error {
+	filters, columns, err := encodeSavedView(view)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		query := `
+			INSERT INTO admin_saved_view (admin_user_id, list_type, name, filters, columns, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING saved_view_id`
+		err := tx.QueryRow(ctx, query,
+			view.AdminUserID, string(view.List), view.Name, filters, columns, view.CreatedAt, view.UpdatedAt,
+		).Scan(&view.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create saved view")
+		}
+		return replaceSharedRoles(ctx, tx, view)
+	})
+}
+
+// Update stores the view and replaces its shared roles
+func (r *PostgresSavedViewRepository) Update(ctx context.Context, view *domain.SavedView) error {
+	filters, columns, err := encodeSavedView(view)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		query := `
+			UPDATE admin_saved_view
+			SET name = $2, filters = $3, columns = $4, updated_at = $5
+			WHERE saved_view_id = $1`
+		tag, err := tx.Exec(ctx, query, view.ID, view.Name, filters, columns, view.UpdatedAt)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to update saved view")
+		}
+		if tag.RowsAffected() == 0 {
+			return errors.NotFound(fmt.Sprintf("saved view %d", view.ID))
+		}
+		return replaceSharedRoles(ctx, tx, view)
+	})
+}
+
+// Delete removes a saved view
+func (r *PostgresSavedViewRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM admin_saved_view WHERE saved_view_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete saved view")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("saved view %d", id))
+	}
+	return nil
+}
+
+// FindByID retrieves a saved view with its shared roles
+func (r *PostgresSavedViewRepository) FindByID(ctx context.Context, id int64) (*domain.SavedView, error) {
+	query := `SELECT ` + savedViewColumns + ` FROM admin_saved_view v WHERE v.saved_view_id = $1`
+
+	view, err := scanSavedView(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound(fmt.Sprintf("saved view %d", id))
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find saved view")
+	}
+	if err := r.loadSharedRoles(ctx, []*domain.SavedView{view}); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// FindVisible retrieves the views of a list owned by the admin user or shared
+// with one of their roles; an empty list retrieves every list
+func (r *PostgresSavedViewRepository) FindVisible(ctx context.Context, adminUserID int64, list domain.SavedViewList) ([]*domain.SavedView, error) {
+	query := `
+		SELECT ` + savedViewColumns + `
+		FROM admin_saved_view v
+		WHERE ($2 = '' OR v.list_type = $2)
+		  AND (v.admin_user_id = $1 OR EXISTS (
+			SELECT 1
+			FROM admin_saved_view_role s
+			JOIN blc_admin_user_role_xref x ON x.admin_role_id = s.admin_role_id
+			WHERE s.saved_view_id = v.saved_view_id AND x.admin_user_id = $1
+		  ))
+		ORDER BY v.list_type, v.name, v.saved_view_id`
+
+	rows, err := r.db.Query(ctx, query, adminUserID, string(list))
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list saved views")
+	}
+	defer rows.Close()
+
+	views := make([]*domain.SavedView, 0)
+	for rows.Next() {
+		view, err := scanSavedView(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan saved view")
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to list saved views")
+	}
+	if err := r.loadSharedRoles(ctx, views); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func (r *PostgresSavedViewRepository) loadSharedRoles(ctx context.Context, views []*domain.SavedView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*domain.SavedView, len(views))
+	ids := make([]int64, 0, len(views))
+	for _, view := range views {
+		byID[view.ID] = view
+		ids = append(ids, view.ID)
+	}
+
+	query := `
+		SELECT saved_view_id, admin_role_id
+		FROM admin_saved_view_role
+		WHERE saved_view_id = ANY($1)
+		ORDER BY saved_view_id, admin_role_id`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to load saved view roles")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var viewID, roleID int64
+		if err := rows.Scan(&viewID, &roleID); err != nil {
+			return errors.InternalWrap(err, "failed to scan saved view role")
+		}
+		if view, ok := byID[viewID]; ok {
+			view.SharedRoleIDs = append(view.SharedRoleIDs, roleID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to load saved view roles")
+	}
+	return nil
+}
+
+func replaceSharedRoles(ctx context.Context, tx pgx.Tx, view *domain.SavedView) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM admin_saved_view_role WHERE saved_view_id = $1`, view.ID); err != nil {
+		return errors.InternalWrap(err, "failed to clear saved view roles")
+	}
+	for _, roleID := range view.SharedRoleIDs {
+		query := `INSERT INTO admin_saved_view_role (saved_view_id, admin_role_id) VALUES ($1, $2)`
+		if _, err := tx.Exec(ctx, query, view.ID, roleID); err != nil {
+			return errors.InternalWrap(err, "failed to share saved view")
+		}
+	}
+	return nil
+}
+
+func encodeSavedView(view *domain.SavedView) ([]byte, []byte, error) {
+	filters, err := json.Marshal(view.Filters)
+	if err != nil {
+		return nil, nil, errors.InternalWrap(err, "failed to encode saved view filters")
+	}
+	columns, err := json.Marshal(view.Columns)
+	if err != nil {
+		return nil, nil, errors.InternalWrap(err, "failed to encode saved view columns")
+	}
+	return filters, columns, nil
+}
+
+func scanSavedView(row pgx.Row) (*domain.SavedView, error) {
+	view := &domain.SavedView{SharedRoleIDs: make([]int64, 0)}
+	var list string
+	var filters, columns []byte
+	err := row.Scan(
+		&view.ID, &view.AdminUserID, &list, &view.Name, &filters, &columns, &view.CreatedAt, &view.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	view.List = domain.SavedViewList(list)
+	if err := json.Unmarshal(filters, &view.Filters); err != nil {
+		return nil, fmt.Errorf("invalid filters of saved view %d: %w", view.ID, err)
+	}
+	if err := json.Unmarshal(columns, &view.Columns); err != nil {
+		return nil, fmt.Errorf("invalid columns of saved view %d: %w", view.ID, err)
+	}
+	if view.Filters == nil {
+		view.Filters = make(map[string]string)
+	}
+	if view.Columns == nil {
+		view.Columns = make([]string, 0)
+	}
+	return view, nil
+}
@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/settings"
+)
+
+// PostgresSiteSettingRepository implements settings.Store, shared by the admin
+// changing settings and the storefront reading them
+type PostgresSiteSettingRepository struct {
+	db *database.DB
+}
+
+// NewPostgresSiteSettingRepository creates a new PostgresSiteSettingRepository
+func NewPostgresSiteSettingRepository(db *database.DB) *PostgresSiteSettingRepository {
+	return &PostgresSiteSettingRepository{db: db}
+}
+
+// List retrieves the settings that were changed
+func (r *PostgresSiteSettingRepository) List(ctx context.Context) ([]*settings.Setting, error) {
+	rows, err := r.db.Query(ctx, `SELECT setting_key, value, updated_by, updated_at FROM site_setting ORDER BY setting_key`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query site settings")
+	}
+	defer rows.Close()
+
+	stored := make([]*settings.Setting, 0)
+	for rows.Next() {
+		setting := &settings.Setting{}
+		if err := rows.Scan(&setting.Key, &setting.Value, &setting.UpdatedBy, &setting.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan site setting")
+		}
+		stored = append(stored, setting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate site settings")
+	}
+	return stored, nil
+}
+
+// Save stores the value of a setting
+func (r *PostgresSiteSettingRepository) Save(ctx context.Context, setting *settings.Setting) error {
+	err := r.db.Exec(ctx, `
+		INSERT INTO site_setting (setting_key, value, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (setting_key) DO UPDATE
+		SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`,
+		setting.Key, setting.Value, setting.UpdatedBy, setting.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save site setting")
+	}
+	return nil
+}
+
+// Delete removes the stored value of a setting
+func (r *PostgresSiteSettingRepository) Delete(ctx context.Context, key string) error {
+	if err := r.db.Exec(ctx, `DELETE FROM site_setting WHERE setting_key = $1`, key); err != nil {
+		return errors.InternalWrap(err, "failed to delete site setting")
+	}
+	return nil
+}
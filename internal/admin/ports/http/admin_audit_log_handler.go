@@ -0,0 +1,137 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const auditDateLayout = "2006-01-02"
+
+// AdminAuditLogHandler handles admin audit log and security event requests
+type AdminAuditLogHandler struct {
+	auditLogService application.AuditLogService
+	authMiddleware  func(http.Handler) http.Handler
+	logger          *logger.Logger
+}
+
+// NewAdminAuditLogHandler creates a new admin audit log handler
+func NewAdminAuditLogHandler(auditLogService application.AuditLogService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminAuditLogHandler {
+	return &AdminAuditLogHandler{
+		auditLogService: auditLogService,
+		authMiddleware:  authMiddleware,
+		logger:          logger,
+	}
+}
+
+// RegisterRoutes registers audit log routes
+func (h *AdminAuditLogHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/audit-logs", h.ListAuditLogs)
+		r.Post("/admin/audit-logs/archive", h.ArchiveAuditLogs)
+		r.Get("/admin/security-events", h.ListSecurityEvents)
+	})
+}
+
+// ListAuditLogs lists audit entries, newest first.
+// Filters: from, to, actor_id, entity_type, entity_id, action, ip, cursor, limit
+func (h *AdminAuditLogHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	query, err := parseAuditLogQuery(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	page, err := h.auditLogService.SearchAuditLogs(r.Context(), query)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list audit logs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, page)
+}
+
+// ListSecurityEvents lists sign-in related entries, newest first, with the audit log filters
+func (h *AdminAuditLogHandler) ListSecurityEvents(w http.ResponseWriter, r *http.Request) {
+	query, err := parseAuditLogQuery(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	page, err := h.auditLogService.SearchSecurityEvents(r.Context(), query)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list security events")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, page)
+}
+
+// ArchiveAuditLogs moves entries past the retention period to cold storage now
+func (h *AdminAuditLogHandler) ArchiveAuditLogs(w http.ResponseWriter, r *http.Request) {
+	archived, err := h.auditLogService.ArchiveExpired(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to archive audit logs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]int64{"archived": archived})
+}
+
+func parseAuditLogQuery(r *http.Request) (*application.AuditLogQuery, error) {
+	values := r.URL.Query()
+	query := &application.AuditLogQuery{
+		ActorID:    values.Get("actor_id"),
+		EntityType: values.Get("entity_type"),
+		EntityID:   values.Get("entity_id"),
+		Action:     values.Get("action"),
+		IPAddress:  values.Get("ip"),
+		Cursor:     values.Get("cursor"),
+	}
+
+	if from := values.Get("from"); from != "" {
+		t, _, err := parseAuditTime(from)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid from, expected RFC 3339 timestamp or YYYY-MM-DD")
+		}
+		query.From = &t
+	}
+	if to := values.Get("to"); to != "" {
+		t, dateOnly, err := parseAuditTime(to)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid to, expected RFC 3339 timestamp or YYYY-MM-DD")
+		}
+		// A to date covers the whole day
+		if dateOnly {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		query.To = &t
+	}
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return nil, pkghttp.NewValidationError("invalid limit")
+		}
+		query.Limit = n
+	}
+	return query, nil
+}
+
+// parseAuditTime accepts an RFC 3339 timestamp or a date, reporting which it was
+func parseAuditTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse(auditDateLayout, value)
+	return t, true, err
+}
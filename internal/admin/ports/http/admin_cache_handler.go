@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/pkg/cache"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminCacheHandler flushes cache namespaces, e.g. after fixing data directly
+// in the database. With the in-memory cache only this instance is flushed.
+type AdminCacheHandler struct {
+	flusher        *cache.Flusher // nil when the cache cannot be flushed by namespace
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminCacheHandler creates a new admin cache handler
+func NewAdminCacheHandler(flusher *cache.Flusher, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminCacheHandler {
+	return &AdminCacheHandler{
+		flusher:        flusher,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers cache management routes
+func (h *AdminCacheHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Use(middleware.RequireRole("admin"))
+		r.Get("/admin/cache/namespaces", h.ListNamespaces)
+		r.Post("/admin/cache/namespaces/{namespace}/flush", h.FlushNamespace)
+	})
+}
+
+// ListNamespaces lists the namespaces that can be flushed
+func (h *AdminCacheHandler) ListNamespaces(w http.ResponseWriter, r *http.Request) {
+	if h.flusher == nil {
+		pkghttp.RespondError(w, errors.NotFound("cache namespaces"))
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{"namespaces": h.flusher.Namespaces()})
+}
+
+// FlushNamespace removes the entries of a namespace. ?context= limits the
+// flush to one bounded context, e.g. catalog.
+func (h *AdminCacheHandler) FlushNamespace(w http.ResponseWriter, r *http.Request) {
+	if h.flusher == nil {
+		pkghttp.RespondError(w, errors.NotFound("cache namespaces"))
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	boundedContext := r.URL.Query().Get("context")
+	removed, err := h.flusher.Flush(r.Context(), namespace, boundedContext)
+	if err != nil {
+		if errors.Is(err, cache.ErrInvalidNamespace) {
+			pkghttp.RespondError(w, pkghttp.NewValidationError(err.Error()))
+			return
+		}
+		h.logger.WithError(err).WithField("namespace", namespace).Error("failed to flush cache namespace")
+		pkghttp.RespondError(w, errors.InternalWrap(err, "failed to flush cache namespace"))
+		return
+	}
+
+	h.logger.WithField("namespace", namespace).
+		WithField("context", boundedContext).
+		WithField("removed", removed).
+		WithField("user_id", middleware.GetUserID(r.Context())).
+		Info("Flushed cache namespace")
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"namespace": namespace,
+		"context":   boundedContext,
+		"removed":   removed,
+	})
+}
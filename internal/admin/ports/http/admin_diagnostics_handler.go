@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminDiagnosticsHandler serves development diagnostics such as index suggestions
+type AdminDiagnosticsHandler struct {
+	advisor        *database.IndexAdvisor // nil outside development
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminDiagnosticsHandler creates a new admin diagnostics handler. advisor
+// is nil when queries are not explained.
+func NewAdminDiagnosticsHandler(advisor *database.IndexAdvisor, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminDiagnosticsHandler {
+	return &AdminDiagnosticsHandler{
+		advisor:        advisor,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers diagnostics routes
+func (h *AdminDiagnosticsHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/diagnostics/indexes", h.ListIndexSuggestions)
+		r.Delete("/admin/diagnostics/indexes", h.ResetIndexSuggestions)
+	})
+}
+
+// ListIndexSuggestions lists the sequential scans EXPLAIN found on watched
+// tables, most frequent first, with the index that would avoid them
+func (h *AdminDiagnosticsHandler) ListIndexSuggestions(w http.ResponseWriter, r *http.Request) {
+	if h.advisor == nil {
+		pkghttp.RespondError(w, errors.NotFound("index advisory (development only)"))
+		return
+	}
+
+	suggestions, err := h.advisor.Suggestions(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list index suggestions")
+		pkghttp.RespondError(w, errors.InternalWrap(err, "failed to list index suggestions"))
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{"suggestions": suggestions})
+}
+
+// ResetIndexSuggestions clears the suggestions so queries are explained again,
+// e.g. once the suggested indexes exist
+func (h *AdminDiagnosticsHandler) ResetIndexSuggestions(w http.ResponseWriter, r *http.Request) {
+	if h.advisor == nil {
+		pkghttp.RespondError(w, errors.NotFound("index advisory (development only)"))
+		return
+	}
+
+	if err := h.advisor.Reset(r.Context()); err != nil {
+		h.logger.WithError(err).Error("failed to reset index suggestions")
+		pkghttp.RespondError(w, errors.InternalWrap(err, "failed to reset index suggestions"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/pkg/export"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminExportHandler handles background export job requests. Jobs are only
+// visible to the admin who started them.
+type AdminExportHandler struct {
+	jobs           *export.JobManager
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminExportHandler creates a new admin export handler
+func NewAdminExportHandler(jobs *export.JobManager, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminExportHandler {
+	return &AdminExportHandler{
+		jobs:           jobs,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers export job routes
+func (h *AdminExportHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Use(middleware.RequireRole("admin"))
+		r.Get("/admin/exports/jobs", h.ListJobs)
+		r.Get("/admin/exports/jobs/{id}", h.GetJob)
+		r.Get("/admin/exports/jobs/{id}/download", h.DownloadJob)
+	})
+}
+
+// ListJobs lists the current admin's export jobs
+func (h *AdminExportHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := h.jobs.List(middleware.GetUserID(r.Context()))
+	pkghttp.RespondJSON(w, http.StatusOK, jobs)
+}
+
+// GetJob returns the status of an export job
+func (h *AdminExportHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobs.Get(chi.URLParam(r, "id"), middleware.GetUserID(r.Context()))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+	pkghttp.RespondJSON(w, http.StatusOK, job)
+}
+
+// DownloadJob sends the file of a completed export job
+func (h *AdminExportHandler) DownloadJob(w http.ResponseWriter, r *http.Request) {
+	f, job, err := h.jobs.Open(chi.URLParam(r, "id"), middleware.GetUserID(r.Context()))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.Filename))
+	if _, err := io.Copy(w, f); err != nil {
+		h.logger.WithError(err).WithField("job_id", job.ID).Warn("export download interrupted")
+	}
+}
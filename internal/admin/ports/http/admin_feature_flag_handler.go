@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminFeatureFlagHandler handles the runtime flags of the storefront:
+// maintenance mode and the kill switches of checkout, search and reviews
+type AdminFeatureFlagHandler struct {
+	flagService    application.FeatureFlagService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminFeatureFlagHandler creates a new admin feature flag handler
+func NewAdminFeatureFlagHandler(flagService application.FeatureFlagService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminFeatureFlagHandler {
+	return &AdminFeatureFlagHandler{
+		flagService:    flagService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers feature flag routes
+func (h *AdminFeatureFlagHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/feature-flags", h.ListFlags)
+		r.Put("/admin/feature-flags/{key}", h.SetFlag)
+	})
+}
+
+// ListFlags lists every known flag with its state
+func (h *AdminFeatureFlagHandler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.flagService.ListFlags(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list feature flags")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, flags)
+}
+
+// SetFlag turns a flag on or off, e.g. {"enabled": true} on "maintenance" to
+// put the storefront in maintenance mode
+func (h *AdminFeatureFlagHandler) SetFlag(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+
+	var req application.SetFeatureFlagRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	flag, err := h.flagService.SetFlag(r.Context(), key, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("flag", key).Error("failed to set feature flag")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, flag)
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminRateLimitHandler handles the storefront API quotas of customers,
+// partner API keys and IPs, and their temporary overrides
+type AdminRateLimitHandler struct {
+	rateLimitService application.RateLimitService
+	authMiddleware   func(http.Handler) http.Handler
+	logger           *logger.Logger
+}
+
+// NewAdminRateLimitHandler creates a new admin rate limit handler
+func NewAdminRateLimitHandler(rateLimitService application.RateLimitService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminRateLimitHandler {
+	return &AdminRateLimitHandler{
+		rateLimitService: rateLimitService,
+		authMiddleware:   authMiddleware,
+		logger:           logger,
+	}
+}
+
+// RegisterRoutes registers rate limit routes
+func (h *AdminRateLimitHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/rate-limits/usage", h.GetUsage)
+		r.Get("/admin/rate-limits/overrides", h.ListOverrides)
+		r.Put("/admin/rate-limits/overrides", h.SetOverride)
+		r.Delete("/admin/rate-limits/overrides/{id}", h.RemoveOverride)
+	})
+}
+
+// GetUsage returns the quota of a subject in each bucket, e.g. ?subject=key:acme
+func (h *AdminRateLimitHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.rateLimitService.GetUsage(r.Context(), r.URL.Query().Get("subject"))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get rate limit usage")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, usage)
+}
+
+// ListOverrides lists the overrides not expired yet
+func (h *AdminRateLimitHandler) ListOverrides(w http.ResponseWriter, r *http.Request) {
+	overrides, err := h.rateLimitService.ListOverrides(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list rate limit overrides")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, overrides)
+}
+
+// SetOverride raises the limit of a subject in a bucket until a time, e.g.
+// {"subject": "key:acme", "bucket": "search", "requests": 1000, "expires_at": "..."}
+func (h *AdminRateLimitHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	var req application.SetRateLimitOverrideRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	override, err := h.rateLimitService.SetOverride(r.Context(), &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("subject", req.Subject).Error("failed to set rate limit override")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, override)
+}
+
+// RemoveOverride ends an override early
+func (h *AdminRateLimitHandler) RemoveOverride(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid rate limit override ID"))
+		return
+	}
+
+	if err := h.rateLimitService.RemoveOverride(r.Context(), id, middleware.GetUserID(r.Context())); err != nil {
+		h.logger.WithError(err).WithField("rate_limit_override_id", id).Error("failed to remove rate limit override")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
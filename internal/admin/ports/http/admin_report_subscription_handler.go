@@ -0,0 +1,161 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminReportSubscriptionHandler handles subscriptions emailing reports such as the
+// sales summary, low stock and offer performance on a daily or weekly schedule.
+// Each delivery is an export job listed under /admin/exports/jobs.
+type AdminReportSubscriptionHandler struct {
+	subscriptionService application.ReportSubscriptionService
+	authMiddleware      func(http.Handler) http.Handler
+	logger              *logger.Logger
+}
+
+// NewAdminReportSubscriptionHandler creates a new admin report subscription handler
+func NewAdminReportSubscriptionHandler(subscriptionService application.ReportSubscriptionService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminReportSubscriptionHandler {
+	return &AdminReportSubscriptionHandler{
+		subscriptionService: subscriptionService,
+		authMiddleware:      authMiddleware,
+		logger:              logger,
+	}
+}
+
+// RegisterRoutes registers report subscription routes
+func (h *AdminReportSubscriptionHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/report-subscriptions", func(r chi.Router) {
+			r.Get("/reports", h.ListReports)
+			r.Get("/", h.ListSubscriptions)
+			r.Post("/", h.CreateSubscription)
+			r.Get("/{id}", h.GetSubscription)
+			r.Put("/{id}", h.UpdateSubscription)
+			r.Delete("/{id}", h.DeleteSubscription)
+			r.Post("/{id}/deliver", h.DeliverNow)
+		})
+	})
+}
+
+// ListReports lists the reports that can be subscribed to, with the parameters they take
+func (h *AdminReportSubscriptionHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	pkghttp.RespondJSON(w, http.StatusOK, h.subscriptionService.ListReports(r.Context()))
+}
+
+// ListSubscriptions lists the report subscriptions of the current admin user
+func (h *AdminReportSubscriptionHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.subscriptionService.ListSubscriptions(r.Context(), middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list report subscriptions")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, subs)
+}
+
+// GetSubscription retrieves a report subscription
+func (h *AdminReportSubscriptionHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid report subscription ID"))
+		return
+	}
+
+	sub, err := h.subscriptionService.GetSubscription(r.Context(), subscriptionID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, sub)
+}
+
+// CreateSubscription subscribes the current admin user to a report, e.g.
+// {"report": "low_stock", "params": {"warehouse_id": "WH1"}, "frequency": "weekly",
+// "weekday": "monday", "hour": 6, "format": "pdf", "recipients": ["ops@example.com"]}
+func (h *AdminReportSubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req application.ReportSubscriptionRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	sub, err := h.subscriptionService.CreateSubscription(r.Context(), &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to create report subscription")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, sub)
+}
+
+// UpdateSubscription replaces the parameters, schedule, format and recipients of a
+// subscription; {"active": false} pauses it
+func (h *AdminReportSubscriptionHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid report subscription ID"))
+		return
+	}
+
+	var req application.ReportSubscriptionRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	sub, err := h.subscriptionService.UpdateSubscription(r.Context(), subscriptionID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("failed to update report subscription")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, sub)
+}
+
+// DeleteSubscription unsubscribes from a report
+func (h *AdminReportSubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid report subscription ID"))
+		return
+	}
+
+	if err := h.subscriptionService.DeleteSubscription(r.Context(), subscriptionID, middleware.GetUserID(r.Context())); err != nil {
+		h.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("failed to delete report subscription")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeliverNow emails the report of the last completed period right away and returns
+// the export job delivering it
+func (h *AdminReportSubscriptionHandler) DeliverNow(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid report subscription ID"))
+		return
+	}
+
+	job, err := h.subscriptionService.DeliverNow(r.Context(), subscriptionID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("subscription_id", subscriptionID).Error("failed to deliver report")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusAccepted, job)
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminRequestLogHandler handles admin API request log requests
+type AdminRequestLogHandler struct {
+	requestLogService application.RequestLogService
+	authMiddleware    func(http.Handler) http.Handler
+	logger            *logger.Logger
+}
+
+// NewAdminRequestLogHandler creates a new admin request log handler
+func NewAdminRequestLogHandler(requestLogService application.RequestLogService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminRequestLogHandler {
+	return &AdminRequestLogHandler{
+		requestLogService: requestLogService,
+		authMiddleware:    authMiddleware,
+		logger:            logger,
+	}
+}
+
+// RegisterRoutes registers request log routes
+func (h *AdminRequestLogHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/request-logs", h.ListRequestLogs)
+		r.Post("/admin/request-logs/purge", h.PurgeRequestLogs)
+		r.Get("/admin/request-logs/{id}", h.GetRequestLog)
+	})
+}
+
+// ListRequestLogs lists logged admin API requests, newest first.
+// Filters: from, to, actor_id, session_id, method, path (prefix), status, errors, ip, cursor, limit
+func (h *AdminRequestLogHandler) ListRequestLogs(w http.ResponseWriter, r *http.Request) {
+	query, err := parseRequestLogQuery(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	page, err := h.requestLogService.SearchRequestLogs(r.Context(), query)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list request logs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, page)
+}
+
+// GetRequestLog returns a logged request with its redacted headers and bodies
+func (h *AdminRequestLogHandler) GetRequestLog(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.requestLogService.GetRequestLog(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, entry)
+}
+
+// PurgeRequestLogs deletes entries past the retention period now
+func (h *AdminRequestLogHandler) PurgeRequestLogs(w http.ResponseWriter, r *http.Request) {
+	purged, err := h.requestLogService.PurgeExpired(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to purge request logs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]int64{"purged": purged})
+}
+
+func parseRequestLogQuery(r *http.Request) (*application.RequestLogQuery, error) {
+	values := r.URL.Query()
+	query := &application.RequestLogQuery{
+		ActorID:    values.Get("actor_id"),
+		SessionID:  values.Get("session_id"),
+		Method:     values.Get("method"),
+		PathPrefix: values.Get("path"),
+		IPAddress:  values.Get("ip"),
+		Cursor:     values.Get("cursor"),
+	}
+
+	if from := values.Get("from"); from != "" {
+		t, _, err := parseAuditTime(from)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid from, expected RFC 3339 timestamp or YYYY-MM-DD")
+		}
+		query.From = &t
+	}
+	if to := values.Get("to"); to != "" {
+		t, dateOnly, err := parseAuditTime(to)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid to, expected RFC 3339 timestamp or YYYY-MM-DD")
+		}
+		// A to date covers the whole day
+		if dateOnly {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		query.To = &t
+	}
+	if status := values.Get("status"); status != "" {
+		n, err := strconv.Atoi(status)
+		if err != nil || n < 100 || n > 599 {
+			return nil, pkghttp.NewValidationError("invalid status")
+		}
+		query.Status = n
+	}
+	if errorsOnly := values.Get("errors"); errorsOnly != "" {
+		b, err := strconv.ParseBool(errorsOnly)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid errors, expected true or false")
+		}
+		query.ErrorsOnly = b
+	}
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return nil, pkghttp.NewValidationError("invalid limit")
+		}
+		query.Limit = n
+	}
+	return query, nil
+}
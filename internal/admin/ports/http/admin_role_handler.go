@@ -0,0 +1,152 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminRoleHandler handles admin role, role template and permission requests
+type AdminRoleHandler struct {
+	roleService    application.AdminRoleService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminRoleHandler creates a new admin role handler
+func NewAdminRoleHandler(roleService application.AdminRoleService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminRoleHandler {
+	return &AdminRoleHandler{
+		roleService:    roleService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers admin role routes
+func (h *AdminRoleHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/roles", func(r chi.Router) {
+			r.Get("/", h.ListRoles)
+			r.Get("/templates", h.ListTemplates)
+			r.Post("/templates/seed", h.SeedTemplates)
+			r.Get("/{id}", h.GetRole)
+			r.Post("/{id}/clone", h.CloneRole)
+		})
+		r.Get("/admin/users/{id}/permissions", h.GetUserPermissions)
+	})
+}
+
+// ListTemplates lists the predefined role templates
+func (h *AdminRoleHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	pkghttp.RespondJSON(w, http.StatusOK, h.roleService.ListTemplates(r.Context()))
+}
+
+// SeedTemplates creates the roles of the predefined templates. It is safe to
+// repeat: existing roles only gain the template permissions they lack.
+func (h *AdminRoleHandler) SeedTemplates(w http.ResponseWriter, r *http.Request) {
+	var req application.SeedRoleTemplatesRequest
+	if r.ContentLength != 0 {
+		if err := pkghttp.DecodeJSON(r, &req); err != nil {
+			pkghttp.RespondError(w, err)
+			return
+		}
+	}
+
+	roles, err := h.roleService.SeedTemplates(r.Context(), req.Templates, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to seed admin role templates")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, roles)
+}
+
+// ListRoles lists every admin role with its permissions
+func (h *AdminRoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.roleService.ListRoles(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list admin roles")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, roles)
+}
+
+// GetRole retrieves an admin role with its permissions
+func (h *AdminRoleHandler) GetRole(w http.ResponseWriter, r *http.Request) {
+	roleID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid role ID"))
+		return
+	}
+
+	role, err := h.roleService.GetRole(r.Context(), roleID)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, role)
+}
+
+// CloneRole creates a new role with the permissions of an existing one
+func (h *AdminRoleHandler) CloneRole(w http.ResponseWriter, r *http.Request) {
+	roleID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid role ID"))
+		return
+	}
+
+	var req application.CloneRoleRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	role, err := h.roleService.CloneRole(r.Context(), roleID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("admin_role_id", roleID).Error("failed to clone admin role")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, role)
+}
+
+// GetUserPermissions shows the effective permissions of an admin user, which
+// roles grant each one, and optionally how they differ from the role given by
+// compare_role_id
+func (h *AdminRoleHandler) GetUserPermissions(w http.ResponseWriter, r *http.Request) {
+	adminUserID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid admin user ID"))
+		return
+	}
+
+	var compareRoleID *int64
+	if raw := r.URL.Query().Get("compare_role_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			pkghttp.RespondError(w, pkghttp.NewValidationError("invalid compare_role_id"))
+			return
+		}
+		compareRoleID = &id
+	}
+
+	permissions, err := h.roleService.GetUserPermissions(r.Context(), adminUserID, compareRoleID)
+	if err != nil {
+		h.logger.WithError(err).WithField("admin_user_id", adminUserID).Error("failed to resolve admin user permissions")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, permissions)
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminSavedViewHandler handles the saved filters and columns of the admin
+// order, product and customer lists. The lists apply a view given by its ID
+// in the savedViewId query parameter.
+type AdminSavedViewHandler struct {
+	viewService    application.SavedViewService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminSavedViewHandler creates a new admin saved view handler
+func NewAdminSavedViewHandler(viewService application.SavedViewService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminSavedViewHandler {
+	return &AdminSavedViewHandler{
+		viewService:    viewService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers saved view routes
+func (h *AdminSavedViewHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/saved-views", func(r chi.Router) {
+			r.Get("/", h.ListViews)
+			r.Post("/", h.CreateView)
+			r.Get("/{id}", h.GetView)
+			r.Put("/{id}", h.UpdateView)
+			r.Delete("/{id}", h.DeleteView)
+			r.Put("/{id}/share", h.ShareView)
+		})
+	})
+}
+
+// ListViews lists the views of the current admin user and those shared with
+// their roles, optionally of one list (?list=orders|products|customers|order_queue)
+func (h *AdminSavedViewHandler) ListViews(w http.ResponseWriter, r *http.Request) {
+	views, err := h.viewService.ListViews(r.Context(), middleware.GetUserID(r.Context()), r.URL.Query().Get("list"))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list saved views")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, views)
+}
+
+// GetView retrieves a saved view
+func (h *AdminSavedViewHandler) GetView(w http.ResponseWriter, r *http.Request) {
+	viewID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid saved view ID"))
+		return
+	}
+
+	view, err := h.viewService.GetView(r.Context(), viewID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, view)
+}
+
+// CreateView saves the filters and columns of a list for the current admin user
+func (h *AdminSavedViewHandler) CreateView(w http.ResponseWriter, r *http.Request) {
+	var req application.SavedViewRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	view, err := h.viewService.CreateView(r.Context(), &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to create saved view")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, view)
+}
+
+// UpdateView replaces the name, filters and columns of a saved view
+func (h *AdminSavedViewHandler) UpdateView(w http.ResponseWriter, r *http.Request) {
+	viewID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid saved view ID"))
+		return
+	}
+
+	var req application.SavedViewRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	view, err := h.viewService.UpdateView(r.Context(), viewID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("saved_view_id", viewID).Error("failed to update saved view")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, view)
+}
+
+// ShareView replaces the roles a saved view is shared with
+func (h *AdminSavedViewHandler) ShareView(w http.ResponseWriter, r *http.Request) {
+	viewID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid saved view ID"))
+		return
+	}
+
+	var req application.ShareSavedViewRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	view, err := h.viewService.ShareView(r.Context(), viewID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("saved_view_id", viewID).Error("failed to share saved view")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, view)
+}
+
+// DeleteView deletes a saved view
+func (h *AdminSavedViewHandler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	viewID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid saved view ID"))
+		return
+	}
+
+	if err := h.viewService.DeleteView(r.Context(), viewID, middleware.GetUserID(r.Context())); err != nil {
+		h.logger.WithError(err).WithField("saved_view_id", viewID).Error("failed to delete saved view")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminSessionHandler handles admin login and session management requests
+type AdminSessionHandler struct {
+	sessionService application.AdminSessionService
+	authMiddleware func(http.Handler) http.Handler
+	sessionManager func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminSessionHandler creates a new admin session handler. The auth middleware
+// protects every route except login; the session manager middleware also
+// protects the routes managing the sessions of other admin users.
+func NewAdminSessionHandler(
+	sessionService application.AdminSessionService,
+	authMiddleware func(http.Handler) http.Handler,
+	sessionManager func(http.Handler) http.Handler,
+	logger *logger.Logger,
+) *AdminSessionHandler {
+	return &AdminSessionHandler{
+		sessionService: sessionService,
+		authMiddleware: authMiddleware,
+		sessionManager: sessionManager,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers admin login and session routes
+func (h *AdminSessionHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/admin/auth/login", h.Login)
+
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/admin/auth/logout", h.Logout)
+		r.Route("/admin/sessions", func(r chi.Router) {
+			r.Get("/", h.ListSessions)
+			r.Delete("/", h.RevokeAllSessions)
+			r.Delete("/{sessionID}", h.RevokeSession)
+		})
+		r.Route("/admin/users/{id}/sessions", func(r chi.Router) {
+			r.Use(h.sessionManager)
+			r.Get("/", h.ListSessions)
+			r.Delete("/", h.RevokeAllSessions)
+			r.Delete("/{sessionID}", h.RevokeSession)
+		})
+	})
+}
+
+// Login authenticates an admin user and starts a session
+func (h *AdminSessionHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req application.LoginRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	resp, err := h.sessionService.Login(r.Context(), &req, r.UserAgent(), clientIP(r))
+	if err != nil {
+		h.logger.WithError(err).WithField("login", req.Login).Warn("admin login failed")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, resp)
+}
+
+// Logout revokes the current session
+func (h *AdminSessionHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	adminUserID, err := h.targetAdminUserID(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+	if err := h.sessionService.RevokeSession(r.Context(), adminUserID, middleware.GetSessionID(r.Context())); err != nil {
+		h.logger.WithError(err).Error("failed to log out admin session")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSessions lists the sessions of the current admin user, or of the admin user
+// in the path
+func (h *AdminSessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	adminUserID, err := h.targetAdminUserID(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+	activeOnly := r.URL.Query().Get("include_inactive") != "true"
+
+	sessions, err := h.sessionService.ListSessions(r.Context(), adminUserID, activeOnly, middleware.GetSessionID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("admin_user_id", adminUserID).Error("failed to list admin sessions")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, sessions)
+}
+
+// RevokeSession revokes a single session of the current admin user, or of the
+// admin user in the path
+func (h *AdminSessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	adminUserID, err := h.targetAdminUserID(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+	sessionID := chi.URLParam(r, "sessionID")
+
+	if err := h.sessionService.RevokeSession(r.Context(), adminUserID, sessionID); err != nil {
+		h.logger.WithError(err).WithField("session_id", sessionID).Error("failed to revoke admin session")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeAllSessions revokes all sessions of the current admin user except the
+// current one, or all sessions of the admin user in the path
+func (h *AdminSessionHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	adminUserID, err := h.targetAdminUserID(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	keepSessionID := ""
+	if chi.URLParam(r, "id") == "" {
+		keepSessionID = middleware.GetSessionID(r.Context())
+	}
+
+	revoked, err := h.sessionService.RevokeAllSessions(r.Context(), adminUserID, keepSessionID)
+	if err != nil {
+		h.logger.WithError(err).WithField("admin_user_id", adminUserID).Error("failed to revoke admin sessions")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]int{"revoked": revoked})
+}
+
+// targetAdminUserID returns the admin user in the path, defaulting to the current user
+func (h *AdminSessionHandler) targetAdminUserID(r *http.Request) (int64, error) {
+	raw := chi.URLParam(r, "id")
+	if raw == "" {
+		raw = middleware.GetUserID(r.Context())
+	}
+	adminUserID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, pkghttp.NewValidationError("invalid admin user ID")
+	}
+	return adminUserID, nil
+}
+
+// clientIP returns the originating client IP, preferring the first X-Forwarded-For hop
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminSiteSettingHandler handles the site settings read by the admin and
+// storefront services: store name, support and sender emails, order number
+// prefix and promotion message thresholds
+type AdminSiteSettingHandler struct {
+	settingService application.SiteSettingService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminSiteSettingHandler creates a new admin site setting handler
+func NewAdminSiteSettingHandler(settingService application.SiteSettingService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminSiteSettingHandler {
+	return &AdminSiteSettingHandler{
+		settingService: settingService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers site setting routes
+func (h *AdminSiteSettingHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/settings", func(r chi.Router) {
+			r.Get("/", h.ListSettings)
+			r.Get("/{key}", h.GetSetting)
+			r.Put("/{key}", h.SetSetting)
+			r.Delete("/{key}", h.ResetSetting)
+			r.Get("/{key}/history", h.GetSettingHistory)
+		})
+	})
+}
+
+// ListSettings lists every known setting with its value, type and default
+func (h *AdminSiteSettingHandler) ListSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.settingService.ListSettings(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list site settings")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, settings)
+}
+
+// GetSetting returns a setting with its value, type and default
+func (h *AdminSiteSettingHandler) GetSetting(w http.ResponseWriter, r *http.Request) {
+	setting, err := h.settingService.GetSetting(r.Context(), chi.URLParam(r, "key"))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, setting)
+}
+
+// SetSetting changes a setting, e.g. {"value": "WEB-"} on "order.number_prefix".
+// Values are checked against the type of the setting.
+func (h *AdminSiteSettingHandler) SetSetting(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+
+	var req application.SetSiteSettingRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	setting, err := h.settingService.SetSetting(r.Context(), key, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("setting", key).Error("failed to set site setting")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, setting)
+}
+
+// ResetSetting sets a setting back to its default
+func (h *AdminSiteSettingHandler) ResetSetting(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+
+	setting, err := h.settingService.ResetSetting(r.Context(), key, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("setting", key).Error("failed to reset site setting")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, setting)
+}
+
+// GetSettingHistory lists who changed a setting, when and from what value
+func (h *AdminSiteSettingHandler) GetSettingHistory(w http.ResponseWriter, r *http.Request) {
+	changes, err := h.settingService.GetSettingHistory(r.Context(), chi.URLParam(r, "key"))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, changes)
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/admin/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminTaskHandler handles the task inbox of the current admin user: tasks raised
+// by workflows and assigned to the user or to one of their roles
+type AdminTaskHandler struct {
+	taskService    application.AdminTaskService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminTaskHandler creates a new admin task handler
+func NewAdminTaskHandler(taskService application.AdminTaskService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminTaskHandler {
+	return &AdminTaskHandler{
+		taskService:    taskService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers task inbox routes
+func (h *AdminTaskHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/tasks", func(r chi.Router) {
+			r.Get("/", h.ListTasks)
+			r.Get("/{id}", h.GetTask)
+			r.Post("/{id}/claim", h.ClaimTask)
+			r.Post("/{id}/release", h.ReleaseTask)
+			r.Post("/{id}/complete", h.CompleteTask)
+			r.Put("/{id}/assignment", h.AssignTask)
+		})
+	})
+}
+
+// ListTasks lists the tasks of the current admin user, open and claimed ones by
+// default (?status=OPEN,CLAIMED,COMPLETED,CANCELLED&type=&entity_type=&entity_id=
+// &claimed=true&overdue=true&limit=&offset=)
+func (h *AdminTaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query := &application.AdminTaskQuery{
+		Type:       values.Get("type"),
+		EntityType: values.Get("entity_type"),
+		EntityID:   values.Get("entity_id"),
+	}
+	for _, status := range strings.Split(values.Get("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			query.Statuses = append(query.Statuses, strings.ToUpper(status))
+		}
+	}
+	for name, target := range map[string]*bool{"claimed": &query.ClaimedOnly, "overdue": &query.OverdueOnly} {
+		if value := values.Get(name); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				pkghttp.RespondError(w, pkghttp.NewValidationError("invalid "+name+", expected true or false"))
+				return
+			}
+			*target = b
+		}
+	}
+	for name, target := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		if value := values.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				pkghttp.RespondError(w, pkghttp.NewValidationError("invalid "+name))
+				return
+			}
+			*target = n
+		}
+	}
+
+	page, err := h.taskService.ListTasks(r.Context(), query, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list admin tasks")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, page)
+}
+
+// GetTask retrieves a task
+func (h *AdminTaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid task ID"))
+		return
+	}
+
+	task, err := h.taskService.GetTask(r.Context(), taskID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, task)
+}
+
+// ClaimTask takes an open task for the current admin user
+func (h *AdminTaskHandler) ClaimTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid task ID"))
+		return
+	}
+
+	task, err := h.taskService.ClaimTask(r.Context(), taskID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("admin_task_id", taskID).Error("failed to claim admin task")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, task)
+}
+
+// ReleaseTask puts a task claimed by the current admin user back in the inbox
+func (h *AdminTaskHandler) ReleaseTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid task ID"))
+		return
+	}
+
+	task, err := h.taskService.ReleaseTask(r.Context(), taskID, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("admin_task_id", taskID).Error("failed to release admin task")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, task)
+}
+
+// CompleteTask closes a claimed task with an outcome, e.g. {"outcome": "approved", "note": "..."}
+func (h *AdminTaskHandler) CompleteTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid task ID"))
+		return
+	}
+
+	var req application.CompleteAdminTaskRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	task, err := h.taskService.CompleteTask(r.Context(), taskID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("admin_task_id", taskID).Error("failed to complete admin task")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, task)
+}
+
+// AssignTask reassigns a task to another admin user or role, e.g. {"role_id": 3}
+func (h *AdminTaskHandler) AssignTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid task ID"))
+		return
+	}
+
+	var req application.AssignAdminTaskRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	task, err := h.taskService.AssignTask(r.Context(), taskID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("admin_task_id", taskID).Error("failed to assign admin task")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,119 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AnalyticsService defines the application service for customer analytics.
+type AnalyticsService interface {
+	// RefreshSummaries recomputes the summary tables from order history.
+	RefreshSummaries(ctx context.Context) error
+
+	// GetLTVSummary returns aggregated LTV and repeat purchase rate.
+	GetLTVSummary(ctx context.Context, filter *domain.AnalyticsFilter) (*LTVSummaryDTO, error)
+
+	// GetTopCustomers returns the customers with the highest lifetime revenue.
+	GetTopCustomers(ctx context.Context, filter *domain.AnalyticsFilter) ([]*CustomerLTVDTO, error)
+
+	// GetCustomerLTV returns the LTV summary of a single customer.
+	GetCustomerLTV(ctx context.Context, customerID int64) (*CustomerLTVDTO, error)
+
+	// GetCohorts returns monthly cohorts shaped for charting.
+	GetCohorts(ctx context.Context, filter *domain.AnalyticsFilter) ([]*CohortDTO, error)
+
+	// StartScheduledRefresh refreshes the summaries periodically until ctx is cancelled.
+	StartScheduledRefresh(ctx context.Context, interval time.Duration)
+}
+
+type analyticsService struct {
+	repo domain.AnalyticsRepository
+	log  *logger.Logger
+
+	// refreshMu prevents overlapping refreshes from the scheduler and the admin endpoint
+	refreshMu sync.Mutex
+}
+
+// NewAnalyticsService creates a new instance of AnalyticsService.
+func NewAnalyticsService(repo domain.AnalyticsRepository, log *logger.Logger) AnalyticsService {
+	return &analyticsService{repo: repo, log: log}
+}
+
+func (s *analyticsService) RefreshSummaries(ctx context.Context) error {
+	if !s.refreshMu.TryLock() {
+		return errors.Conflict("analytics refresh already in progress")
+	}
+	defer s.refreshMu.Unlock()
+
+	start := time.Now()
+	if err := s.repo.RefreshSummaries(ctx); err != nil {
+		return fmt.Errorf("failed to refresh analytics summaries: %w", err)
+	}
+	s.log.WithField("duration_ms", time.Since(start).Milliseconds()).Info("Analytics summaries refreshed")
+	return nil
+}
+
+func (s *analyticsService) GetLTVSummary(ctx context.Context, filter *domain.AnalyticsFilter) (*LTVSummaryDTO, error) {
+	summary, err := s.repo.FindLTVSummary(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load LTV summary: %w", err)
+	}
+	return ToLTVSummaryDTO(summary), nil
+}
+
+func (s *analyticsService) GetTopCustomers(ctx context.Context, filter *domain.AnalyticsFilter) ([]*CustomerLTVDTO, error) {
+	customers, err := s.repo.FindTopCustomers(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load top customers: %w", err)
+	}
+	dtos := make([]*CustomerLTVDTO, len(customers))
+	for i, c := range customers {
+		dtos[i] = ToCustomerLTVDTO(c)
+	}
+	return dtos, nil
+}
+
+func (s *analyticsService) GetCustomerLTV(ctx context.Context, customerID int64) (*CustomerLTVDTO, error) {
+	ltv, err := s.repo.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load customer LTV: %w", err)
+	}
+	if ltv == nil {
+		return nil, nil
+	}
+	return ToCustomerLTVDTO(ltv), nil
+}
+
+func (s *analyticsService) GetCohorts(ctx context.Context, filter *domain.AnalyticsFilter) ([]*CohortDTO, error) {
+	periods, err := s.repo.FindCohorts(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cohorts: %w", err)
+	}
+	return ToCohortDTOs(periods), nil
+}
+
+func (s *analyticsService) StartScheduledRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.RefreshSummaries(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled analytics refresh failed")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	"github.com/qhato/ecommerce/pkg/export"
+)
+
+// NewSalesSummaryReport is the channel revenue report admins can subscribe to.
+// It takes the channel parameter of /admin/analytics/channels/revenue.
+func NewSalesSummaryReport(service ChannelReportService) export.Report {
+	return export.Report{
+		Name:   "sales_summary",
+		Title:  "Sales summary",
+		Params: []string{"channel"},
+		Build: func(from, to time.Time, params map[string]string) (export.Export, error) {
+			filter := &domain.ChannelReportFilter{From: from, To: to}
+			for _, channel := range strings.Split(params["channel"], ",") {
+				if channel = strings.TrimSpace(channel); channel != "" {
+					filter.Channels = append(filter.Channels, channel)
+				}
+			}
+			if err := validateChannelReportFilter(filter); err != nil {
+				return export.Export{}, err
+			}
+			return NewSalesSummaryExport(service, filter), nil
+		},
+	}
+}
+
+// NewSalesSummaryExport describes a CSV export of the orders, revenue and average
+// order value of each sales channel and currency over the filter's range
+func NewSalesSummaryExport(service ChannelReportService, filter *domain.ChannelReportFilter) export.Export {
+	return export.Export{
+		Kind:     "sales-summary",
+		Filename: "sales-summary-" + filter.From.Format("20060102") + ".csv",
+		Header:   []string{"from", "to", "channel", "currency_code", "orders", "revenue", "average_order_value"},
+		Rows: func(ctx context.Context, w export.RowWriter) error {
+			report, err := service.GetRevenue(ctx, filter)
+			if err != nil {
+				return err
+			}
+			for _, row := range report.Rows {
+				if err := w.Write([]string{
+					report.From,
+					report.To,
+					row.Channel,
+					row.CurrencyCode,
+					strconv.FormatInt(row.Orders, 10),
+					strconv.FormatFloat(row.Revenue, 'f', 2, 64),
+					strconv.FormatFloat(row.AverageOrderValue, 'f', 2, 64),
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	orderDomain "github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// maxChannelReportDays bounds the date range of a channel report
+const maxChannelReportDays = 366
+
+// ChannelReportService defines the application service for sales channel reports.
+type ChannelReportService interface {
+	// GetRevenue returns the revenue of the orders submitted through each channel.
+	GetRevenue(ctx context.Context, filter *domain.ChannelReportFilter) (*ChannelRevenueReportDTO, error)
+
+	// GetConversion returns how many carts of each channel became orders.
+	GetConversion(ctx context.Context, filter *domain.ChannelReportFilter) (*ChannelConversionReportDTO, error)
+}
+
+type channelReportService struct {
+	repo domain.ChannelReportRepository
+	log  *logger.Logger
+}
+
+// NewChannelReportService creates a new instance of ChannelReportService.
+func NewChannelReportService(repo domain.ChannelReportRepository, log *logger.Logger) ChannelReportService {
+	return &channelReportService{repo: repo, log: log}
+}
+
+func (s *channelReportService) GetRevenue(ctx context.Context, filter *domain.ChannelReportFilter) (*ChannelRevenueReportDTO, error) {
+	if err := validateChannelReportFilter(filter); err != nil {
+		return nil, err
+	}
+	revenues, err := s.repo.FindRevenue(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load channel revenue: %w", err)
+	}
+	return ToChannelRevenueReportDTO(filter, revenues), nil
+}
+
+func (s *channelReportService) GetConversion(ctx context.Context, filter *domain.ChannelReportFilter) (*ChannelConversionReportDTO, error) {
+	if err := validateChannelReportFilter(filter); err != nil {
+		return nil, err
+	}
+	conversions, err := s.repo.FindConversion(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load channel conversion: %w", err)
+	}
+
+	// Channels without carts in the range are reported with zeros
+	found := make(map[string]bool, len(conversions))
+	for _, c := range conversions {
+		found[c.Channel] = true
+	}
+	for _, channel := range reportChannels(filter) {
+		if !found[channel] {
+			conversions = append(conversions, &domain.ChannelConversion{Channel: channel})
+		}
+	}
+	return ToChannelConversionReportDTO(filter, conversions), nil
+}
+
+// validateChannelReportFilter checks the date range and normalizes the channels of a filter
+func validateChannelReportFilter(filter *domain.ChannelReportFilter) error {
+	if !filter.From.Before(filter.To) {
+		return errors.ValidationError("report start must be before its end")
+	}
+	if filter.To.Sub(filter.From).Hours() > maxChannelReportDays*24 {
+		return errors.ValidationError(fmt.Sprintf("reports cover at most %d days", maxChannelReportDays))
+	}
+	for i, channel := range filter.Channels {
+		channel = strings.ToUpper(strings.TrimSpace(channel))
+		if !orderDomain.OrderChannel(channel).IsValid() {
+			return errors.ValidationError(fmt.Sprintf("unknown order channel %q", channel))
+		}
+		filter.Channels[i] = channel
+	}
+	return nil
+}
+
+// reportChannels is the channels a report covers: those of the filter, or all of them
+func reportChannels(filter *domain.ChannelReportFilter) []string {
+	if len(filter.Channels) > 0 {
+		return filter.Channels
+	}
+	channels := make([]string, len(orderDomain.OrderChannels))
+	for i, channel := range orderDomain.OrderChannels {
+		channels[i] = string(channel)
+	}
+	return channels
+}
@@ -0,0 +1,265 @@
+package application
+
+import (
+	"time"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+)
+
+// CustomerLTVDTO represents a customer's lifetime value
+type CustomerLTVDTO struct {
+	CustomerID        int64     `json:"customer_id"`
+	CohortMonth       string    `json:"cohort_month"`
+	FirstOrderDate    time.Time `json:"first_order_date"`
+	LastOrderDate     time.Time `json:"last_order_date"`
+	OrderCount        int64     `json:"order_count"`
+	TotalRevenue      float64   `json:"total_revenue"`
+	AverageOrderValue float64   `json:"average_order_value"`
+}
+
+// LTVSummaryDTO represents aggregated lifetime value metrics
+type LTVSummaryDTO struct {
+	Customers          int64      `json:"customers"`
+	RepeatCustomers    int64      `json:"repeat_customers"`
+	RepeatPurchaseRate float64    `json:"repeat_purchase_rate"`
+	AverageLTV         float64    `json:"average_ltv"`
+	AverageOrderValue  float64    `json:"average_order_value"`
+	AverageOrderCount  float64    `json:"average_order_count"`
+	RefreshedAt        *time.Time `json:"refreshed_at,omitempty"`
+}
+
+// CohortDTO is a cohort row shaped for charting: one series per cohort month
+type CohortDTO struct {
+	CohortMonth string            `json:"cohort_month"`
+	CohortSize  int64             `json:"cohort_size"`
+	Periods     []CohortPeriodDTO `json:"periods"`
+}
+
+// CohortPeriodDTO is one point of a cohort series
+type CohortPeriodDTO struct {
+	MonthsSinceFirst int     `json:"months_since_first"`
+	ActiveCustomers  int64   `json:"active_customers"`
+	Revenue          float64 `json:"revenue"`
+	RetentionRate    float64 `json:"retention_rate"`
+}
+
+// cohortMonthLayout is the format used for cohort month labels
+const cohortMonthLayout = "2006-01"
+
+// ToCustomerLTVDTO converts a domain CustomerLifetimeValue to CustomerLTVDTO
+func ToCustomerLTVDTO(ltv *domain.CustomerLifetimeValue) *CustomerLTVDTO {
+	return &CustomerLTVDTO{
+		CustomerID:        ltv.CustomerID,
+		CohortMonth:       ltv.CohortMonth.Format(cohortMonthLayout),
+		FirstOrderDate:    ltv.FirstOrderDate,
+		LastOrderDate:     ltv.LastOrderDate,
+		OrderCount:        ltv.OrderCount,
+		TotalRevenue:      ltv.TotalRevenue,
+		AverageOrderValue: ltv.AverageOrderValue,
+	}
+}
+
+// ToLTVSummaryDTO converts a domain LTVSummary to LTVSummaryDTO
+func ToLTVSummaryDTO(summary *domain.LTVSummary) *LTVSummaryDTO {
+	return &LTVSummaryDTO{
+		Customers:          summary.Customers,
+		RepeatCustomers:    summary.RepeatCustomers,
+		RepeatPurchaseRate: summary.RepeatPurchaseRate,
+		AverageLTV:         summary.AverageLTV,
+		AverageOrderValue:  summary.AverageOrderValue,
+		AverageOrderCount:  summary.AverageOrderCount,
+		RefreshedAt:        summary.RefreshedAt,
+	}
+}
+
+// ToCohortDTOs groups flat cohort periods into one series per cohort month
+func ToCohortDTOs(periods []*domain.CohortPeriod) []*CohortDTO {
+	cohorts := make([]*CohortDTO, 0)
+	index := make(map[string]*CohortDTO)
+	for _, p := range periods {
+		key := p.CohortMonth.Format(cohortMonthLayout)
+		cohort, ok := index[key]
+		if !ok {
+			cohort = &CohortDTO{CohortMonth: key, CohortSize: p.CohortSize, Periods: make([]CohortPeriodDTO, 0)}
+			index[key] = cohort
+			cohorts = append(cohorts, cohort)
+		}
+		cohort.Periods = append(cohort.Periods, CohortPeriodDTO{
+			MonthsSinceFirst: p.MonthsSinceFirst,
+			ActiveCustomers:  p.ActiveCustomers,
+			Revenue:          p.Revenue,
+			RetentionRate:    p.RetentionRate,
+		})
+	}
+	return cohorts
+}
+
+// DatasetExportDTO is the export state of a data warehouse dataset
+type DatasetExportDTO struct {
+	Dataset       string                 `json:"dataset"`
+	Description   string                 `json:"description"`
+	Location      string                 `json:"location"`
+	SchemaVersion int                    `json:"schema_version"`
+	SchemaMajor   int                    `json:"schema_major"`
+	Columns       []domain.DatasetColumn `json:"columns"`
+	LastTimestamp *time.Time             `json:"last_timestamp,omitempty"`
+	LastKey       string                 `json:"last_key,omitempty"`
+	RowsExported  int64                  `json:"rows_exported"`
+	FilesExported int64                  `json:"files_exported"`
+	LastRunAt     *time.Time             `json:"last_run_at,omitempty"`
+	LastSuccessAt *time.Time             `json:"last_success_at,omitempty"`
+	LastError     string                 `json:"last_error,omitempty"`
+}
+
+// ExportRunDTO is the outcome of exporting a dataset
+type ExportRunDTO struct {
+	Dataset       string `json:"dataset"`
+	SchemaVersion int    `json:"schema_version"`
+	SchemaChange  string `json:"schema_change"`
+	Rows          int64  `json:"rows"`
+	Files         int    `json:"files"`
+	DurationMs    int64  `json:"duration_ms"`
+	Error         string `json:"error,omitempty"`
+}
+
+// RunExportRequest selects the datasets to export; empty exports all of them
+type RunExportRequest struct {
+	Datasets []string `json:"datasets"`
+}
+
+// ToDatasetExportDTO converts a dataset and its watermark to DatasetExportDTO
+func ToDatasetExportDTO(dataset *domain.Dataset, watermark *domain.ExportWatermark, location string) *DatasetExportDTO {
+	return &DatasetExportDTO{
+		Dataset:       dataset.Name,
+		Description:   dataset.Description,
+		Location:      location,
+		SchemaVersion: watermark.SchemaVersion,
+		SchemaMajor:   watermark.SchemaMajor,
+		Columns:       watermark.Schema,
+		LastTimestamp: watermark.LastTimestamp,
+		LastKey:       watermark.LastKey,
+		RowsExported:  watermark.RowsExported,
+		FilesExported: watermark.FilesExported,
+		LastRunAt:     watermark.LastRunAt,
+		LastSuccessAt: watermark.LastSuccessAt,
+		LastError:     watermark.LastError,
+	}
+}
+
+// channelReportDateLayout is the format of channel report dates
+const channelReportDateLayout = "2006-01-02"
+
+// ChannelRevenueReportDTO is the revenue of each sales channel over a date range
+type ChannelRevenueReportDTO struct {
+	From     string              `json:"from"`
+	To       string              `json:"to"` // Exclusive
+	Channels []string            `json:"channels,omitempty"`
+	Rows     []ChannelRevenueDTO `json:"rows"`
+}
+
+// ChannelRevenueDTO is the revenue of a sales channel in one currency
+type ChannelRevenueDTO struct {
+	Channel           string  `json:"channel"`
+	CurrencyCode      string  `json:"currency_code"`
+	Orders            int64   `json:"orders"`
+	Revenue           float64 `json:"revenue"`
+	AverageOrderValue float64 `json:"average_order_value"`
+}
+
+// ChannelConversionReportDTO is the cart conversion of each sales channel over a date range
+type ChannelConversionReportDTO struct {
+	From     string                 `json:"from"`
+	To       string                 `json:"to"` // Exclusive
+	Channels []string               `json:"channels,omitempty"`
+	Rows     []ChannelConversionDTO `json:"rows"`
+}
+
+// ChannelConversionDTO is the cart conversion of a sales channel
+type ChannelConversionDTO struct {
+	Channel        string  `json:"channel"`
+	Carts          int64   `json:"carts"`
+	Orders         int64   `json:"orders"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// ToChannelRevenueReportDTO converts channel revenues to ChannelRevenueReportDTO
+func ToChannelRevenueReportDTO(filter *domain.ChannelReportFilter, revenues []*domain.ChannelRevenue) *ChannelRevenueReportDTO {
+	rows := make([]ChannelRevenueDTO, len(revenues))
+	for i, rev := range revenues {
+		rows[i] = ChannelRevenueDTO{
+			Channel:           rev.Channel,
+			CurrencyCode:      rev.CurrencyCode,
+			Orders:            rev.Orders,
+			Revenue:           rev.Revenue,
+			AverageOrderValue: rev.AverageOrderValue,
+		}
+	}
+	return &ChannelRevenueReportDTO{
+		From:     filter.From.Format(channelReportDateLayout),
+		To:       filter.To.Format(channelReportDateLayout),
+		Channels: filter.Channels,
+		Rows:     rows,
+	}
+}
+
+// ToChannelConversionReportDTO converts channel conversions to ChannelConversionReportDTO
+func ToChannelConversionReportDTO(filter *domain.ChannelReportFilter, conversions []*domain.ChannelConversion) *ChannelConversionReportDTO {
+	rows := make([]ChannelConversionDTO, len(conversions))
+	for i, c := range conversions {
+		rows[i] = ChannelConversionDTO{
+			Channel:        c.Channel,
+			Carts:          c.Carts,
+			Orders:         c.Orders,
+			ConversionRate: c.ConversionRate,
+		}
+	}
+	return &ChannelConversionReportDTO{
+		From:     filter.From.Format(channelReportDateLayout),
+		To:       filter.To.Format(channelReportDateLayout),
+		Channels: filter.Channels,
+		Rows:     rows,
+	}
+}
+
+// TaxLiabilityReportDTO is the tax collected for each jurisdiction over a date range
+type TaxLiabilityReportDTO struct {
+	From string            `json:"from"`
+	To   string            `json:"to"` // Exclusive
+	Rows []TaxLiabilityDTO `json:"rows"`
+}
+
+// TaxLiabilityDTO is the tax collected for a jurisdiction at one rate and in one currency
+type TaxLiabilityDTO struct {
+	Country          string  `json:"country"`
+	Region           string  `json:"region,omitempty"`
+	JurisdictionName string  `json:"jurisdiction_name"`
+	TaxName          string  `json:"tax_name"`
+	Rate             float64 `json:"rate"`
+	CurrencyCode     string  `json:"currency_code"`
+	Orders           int64   `json:"orders"`
+	TaxableAmount    float64 `json:"taxable_amount"`
+	Amount           float64 `json:"amount"`
+}
+
+// ToTaxLiabilityReportDTO converts tax liabilities to TaxLiabilityReportDTO
+func ToTaxLiabilityReportDTO(filter *domain.TaxReportFilter, liabilities []*domain.TaxLiability) *TaxLiabilityReportDTO {
+	rows := make([]TaxLiabilityDTO, len(liabilities))
+	for i, l := range liabilities {
+		rows[i] = TaxLiabilityDTO{
+			Country:          l.Country,
+			Region:           l.Region,
+			JurisdictionName: l.JurisdictionName,
+			TaxName:          l.TaxName,
+			Rate:             l.Rate,
+			CurrencyCode:     l.CurrencyCode,
+			Orders:           l.Orders,
+			TaxableAmount:    l.TaxableAmount,
+			Amount:           l.Amount,
+		}
+	}
+	return &TaxLiabilityReportDTO{
+		From: filter.From.Format(channelReportDateLayout),
+		To:   filter.To.Format(channelReportDateLayout),
+		Rows: rows,
+	}
+}
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// TaxReportService defines the application service for tax reports.
+type TaxReportService interface {
+	// GetLiabilities returns the tax collected for each jurisdiction and rate.
+	GetLiabilities(ctx context.Context, filter *domain.TaxReportFilter) (*TaxLiabilityReportDTO, error)
+}
+
+type taxReportService struct {
+	repo domain.TaxReportRepository
+	log  *logger.Logger
+}
+
+// NewTaxReportService creates a new instance of TaxReportService.
+func NewTaxReportService(repo domain.TaxReportRepository, log *logger.Logger) TaxReportService {
+	return &taxReportService{repo: repo, log: log}
+}
+
+func (s *taxReportService) GetLiabilities(ctx context.Context, filter *domain.TaxReportFilter) (*TaxLiabilityReportDTO, error) {
+	if !filter.From.Before(filter.To) {
+		return nil, errors.ValidationError("report start must be before its end")
+	}
+	if filter.To.Sub(filter.From).Hours() > maxChannelReportDays*24 {
+		return nil, errors.ValidationError(fmt.Sprintf("reports cover at most %d days", maxChannelReportDays))
+	}
+	liabilities, err := s.repo.FindLiabilities(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tax liabilities: %w", err)
+	}
+	return ToTaxLiabilityReportDTO(filter, liabilities), nil
+}
@@ -0,0 +1,365 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/objectstore"
+	"github.com/qhato/ecommerce/pkg/parquet"
+)
+
+const (
+	// parquetContentType is the media type of exported files
+	parquetContentType = "application/vnd.apache.parquet"
+
+	// exportSettleTime keeps rows this recent out of an export, so rows committed by
+	// transactions still in flight are not skipped by the watermark
+	exportSettleTime = time.Minute
+
+	// defaultExportBatchSize is used when no batch size is configured
+	defaultExportBatchSize = 50000
+)
+
+// WarehouseExportService exports orders, order items, inventory transactions and offer
+// redemptions to the data warehouse as Parquet files partitioned by day:
+//
+//	{dataset}/v{major}/dt=YYYY-MM-DD/part-{run}-{seq}.parquet
+//	{dataset}/_schema/v{version}.json
+//
+// Exports are incremental: each dataset resumes after the watermark of its last exported
+// row, which only advances once the files are uploaded. Delivery is at least once, so a
+// row can appear in more than one file, e.g. orders are exported again whenever they
+// change; readers keep the latest row per key. Added columns start a new schema version
+// in the same path, and other schema changes start a new major version path exported
+// from the beginning.
+type WarehouseExportService interface {
+	// RunExport exports the named datasets, or all of them, up to the present.
+	RunExport(ctx context.Context, datasets []string) ([]*ExportRunDTO, error)
+
+	// ListExports returns the export state of every dataset.
+	ListExports(ctx context.Context) ([]*DatasetExportDTO, error)
+
+	// ResetExport makes the next run export a dataset from the beginning again.
+	ResetExport(ctx context.Context, dataset string) (*DatasetExportDTO, error)
+
+	// StartScheduledExport exports all datasets periodically until ctx is cancelled.
+	StartScheduledExport(ctx context.Context, interval time.Duration)
+}
+
+type warehouseExportService struct {
+	repo      domain.WarehouseExportRepository
+	store     objectstore.Store
+	batchSize int
+	log       *logger.Logger
+
+	// exportMu prevents overlapping runs from the scheduler and the admin endpoints
+	exportMu sync.Mutex
+}
+
+// NewWarehouseExportService creates a new instance of WarehouseExportService.
+func NewWarehouseExportService(repo domain.WarehouseExportRepository, store objectstore.Store, batchSize int, log *logger.Logger) WarehouseExportService {
+	if batchSize <= 0 {
+		batchSize = defaultExportBatchSize
+	}
+	return &warehouseExportService{repo: repo, store: store, batchSize: batchSize, log: log}
+}
+
+func (s *warehouseExportService) RunExport(ctx context.Context, names []string) ([]*ExportRunDTO, error) {
+	datasets := domain.WarehouseDatasets
+	if len(names) > 0 {
+		datasets = make([]*domain.Dataset, 0, len(names))
+		for _, name := range names {
+			dataset := domain.FindDataset(name)
+			if dataset == nil {
+				return nil, errors.ValidationError(fmt.Sprintf("unknown dataset %q", name))
+			}
+			datasets = append(datasets, dataset)
+		}
+	}
+
+	if !s.exportMu.TryLock() {
+		return nil, errors.Conflict("warehouse export already in progress")
+	}
+	defer s.exportMu.Unlock()
+
+	start := time.Now().UTC()
+	runID := start.Format("20060102T150405.000Z")
+	until := start.Add(-exportSettleTime)
+
+	results := make([]*ExportRunDTO, 0, len(datasets))
+	for _, dataset := range datasets {
+		result := s.exportDataset(ctx, dataset, runID, until)
+		log := s.log.WithFields(logger.Fields{
+			"dataset":        result.Dataset,
+			"rows":           result.Rows,
+			"files":          result.Files,
+			"schema_version": result.SchemaVersion,
+			"duration_ms":    result.DurationMs,
+		})
+		if result.Error != "" {
+			log.WithField("error", result.Error).Warn("Warehouse export failed")
+		} else {
+			log.Info("Warehouse export completed")
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *warehouseExportService) ListExports(ctx context.Context) ([]*DatasetExportDTO, error) {
+	watermarks, err := s.repo.FindWatermarks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load export watermarks: %w", err)
+	}
+	byDataset := make(map[string]*domain.ExportWatermark, len(watermarks))
+	for _, watermark := range watermarks {
+		byDataset[watermark.Dataset] = watermark
+	}
+
+	dtos := make([]*DatasetExportDTO, 0, len(domain.WarehouseDatasets))
+	for _, dataset := range domain.WarehouseDatasets {
+		watermark, ok := byDataset[dataset.Name]
+		if !ok {
+			watermark = domain.NewExportWatermark(dataset)
+		}
+		dtos = append(dtos, ToDatasetExportDTO(dataset, watermark, s.store.Location(dataPrefix(dataset, watermark))))
+	}
+	return dtos, nil
+}
+
+func (s *warehouseExportService) ResetExport(ctx context.Context, name string) (*DatasetExportDTO, error) {
+	dataset := domain.FindDataset(name)
+	if dataset == nil {
+		return nil, errors.NotFound("dataset")
+	}
+
+	if !s.exportMu.TryLock() {
+		return nil, errors.Conflict("warehouse export already in progress")
+	}
+	defer s.exportMu.Unlock()
+
+	watermark, err := s.repo.FindWatermark(ctx, dataset.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load export watermark: %w", err)
+	}
+	if watermark == nil {
+		watermark = domain.NewExportWatermark(dataset)
+	}
+	watermark.Reset()
+	if err := s.repo.SaveWatermark(ctx, watermark); err != nil {
+		return nil, fmt.Errorf("failed to reset export watermark: %w", err)
+	}
+
+	s.log.WithField("dataset", dataset.Name).Info("Warehouse export reset")
+	return ToDatasetExportDTO(dataset, watermark, s.store.Location(dataPrefix(dataset, watermark))), nil
+}
+
+func (s *warehouseExportService) StartScheduledExport(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.RunExport(ctx, nil); err != nil {
+					s.log.WithError(err).Warn("Scheduled warehouse export failed")
+				}
+			}
+		}
+	}()
+}
+
+// exportDataset exports the rows of a dataset changed since its watermark, in batches.
+// The watermark is saved after every uploaded batch, so a failed run resumes where it stopped.
+func (s *warehouseExportService) exportDataset(ctx context.Context, dataset *domain.Dataset, runID string, until time.Time) *ExportRunDTO {
+	start := time.Now()
+	result := &ExportRunDTO{Dataset: dataset.Name}
+
+	watermark, err := s.repo.FindWatermark(ctx, dataset.Name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	change := domain.SchemaNew
+	if watermark == nil {
+		watermark = domain.NewExportWatermark(dataset)
+	} else {
+		change = watermark.ApplySchema(dataset.Columns)
+	}
+	result.SchemaChange = string(change)
+	result.SchemaVersion = watermark.SchemaVersion
+
+	// The schema is only saved once published, so a failed upload is retried on the next run
+	if change != domain.SchemaUnchanged {
+		if err := s.writeSchemaManifest(ctx, dataset, watermark, change); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	runAt := time.Now()
+	watermark.LastRunAt = &runAt
+
+	err = s.exportBatches(ctx, dataset, watermark, runID, until, result)
+	if err != nil {
+		watermark.LastError = err.Error()
+		result.Error = err.Error()
+	} else {
+		watermark.LastError = ""
+		watermark.LastSuccessAt = &runAt
+	}
+	if saveErr := s.repo.SaveWatermark(ctx, watermark); saveErr != nil && result.Error == "" {
+		result.Error = saveErr.Error()
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
+
+func (s *warehouseExportService) exportBatches(ctx context.Context, dataset *domain.Dataset, watermark *domain.ExportWatermark,
+	runID string, until time.Time, result *ExportRunDTO) error {
+	seq := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := s.repo.ExtractBatch(ctx, dataset, watermark, until, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		files, err := s.writeBatch(ctx, dataset, watermark, runID, &seq, batch)
+		if err != nil {
+			return err
+		}
+		watermark.Advance(batch[len(batch)-1], len(batch), files)
+		if err := s.repo.SaveWatermark(ctx, watermark); err != nil {
+			return err
+		}
+		result.Rows += int64(len(batch))
+		result.Files += files
+
+		if len(batch) < s.batchSize {
+			return nil
+		}
+	}
+}
+
+// writeBatch writes a batch as one Parquet file per day it spans and returns the number of files
+func (s *warehouseExportService) writeBatch(ctx context.Context, dataset *domain.Dataset, watermark *domain.ExportWatermark,
+	runID string, seq *int, batch []*domain.ExtractedRow) (int, error) {
+	partitions := make([]string, 0, 1)
+	rowsByPartition := make(map[string][]*domain.ExtractedRow)
+	for _, row := range batch {
+		partition := row.Timestamp.Format("2006-01-02")
+		if _, ok := rowsByPartition[partition]; !ok {
+			partitions = append(partitions, partition)
+		}
+		rowsByPartition[partition] = append(rowsByPartition[partition], row)
+	}
+
+	columns := make([]parquet.Column, len(dataset.Columns))
+	for i, column := range dataset.Columns {
+		columns[i] = parquet.Column{Name: column.Name, Type: parquetType(column.Type)}
+	}
+
+	for _, partition := range partitions {
+		var buf bytes.Buffer
+		writer, err := parquet.NewWriter(&buf, columns)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create %s file: %w", dataset.Name, err)
+		}
+		writer.SetMetadata("dataset", dataset.Name)
+		writer.SetMetadata("schema_version", fmt.Sprint(watermark.SchemaVersion))
+		writer.SetMetadata("run_id", runID)
+		for _, row := range rowsByPartition[partition] {
+			if err := writer.Write(row.Values); err != nil {
+				return 0, fmt.Errorf("failed to write %s row %s: %w", dataset.Name, row.Key, err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return 0, fmt.Errorf("failed to write %s file: %w", dataset.Name, err)
+		}
+
+		*seq++
+		key := fmt.Sprintf("%s/dt=%s/part-%s-%05d.parquet", dataPrefix(dataset, watermark), partition, runID, *seq)
+		if err := s.store.Put(ctx, key, buf.Bytes(), parquetContentType); err != nil {
+			return 0, err
+		}
+	}
+	return len(partitions), nil
+}
+
+// schemaManifest describes a schema version of a dataset for warehouse loaders
+type schemaManifest struct {
+	Dataset   string                 `json:"dataset"`
+	Version   int                    `json:"version"`
+	Major     int                    `json:"major"`
+	Change    string                 `json:"change"`
+	Path      string                 `json:"path"`
+	Columns   []domain.DatasetColumn `json:"columns"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+func (s *warehouseExportService) writeSchemaManifest(ctx context.Context, dataset *domain.Dataset, watermark *domain.ExportWatermark, change domain.SchemaChange) error {
+	manifest, err := json.MarshalIndent(schemaManifest{
+		Dataset:   dataset.Name,
+		Version:   watermark.SchemaVersion,
+		Major:     watermark.SchemaMajor,
+		Change:    string(change),
+		Path:      dataPrefix(dataset, watermark) + "/",
+		Columns:   dataset.Columns,
+		CreatedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s schema: %w", dataset.Name, err)
+	}
+
+	key := fmt.Sprintf("%s/_schema/v%d.json", dataset.Name, watermark.SchemaVersion)
+	if err := s.store.Put(ctx, key, manifest, "application/json"); err != nil {
+		return err
+	}
+	s.log.WithFields(logger.Fields{
+		"dataset":        dataset.Name,
+		"schema_version": watermark.SchemaVersion,
+		"schema_major":   watermark.SchemaMajor,
+		"change":         change,
+	}).Info("Warehouse export schema published")
+	return nil
+}
+
+// dataPrefix is where the files of the current major schema version of a dataset are written
+func dataPrefix(dataset *domain.Dataset, watermark *domain.ExportWatermark) string {
+	return fmt.Sprintf("%s/v%d", dataset.Name, watermark.SchemaMajor)
+}
+
+func parquetType(columnType domain.ColumnType) parquet.Type {
+	switch columnType {
+	case domain.ColumnDouble:
+		return parquet.Double
+	case domain.ColumnString:
+		return parquet.String
+	case domain.ColumnBoolean:
+		return parquet.Boolean
+	case domain.ColumnTimestamp:
+		return parquet.Timestamp
+	default:
+		return parquet.Int64
+	}
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CustomerLifetimeValue is the per-customer summary computed from order history
+type CustomerLifetimeValue struct {
+	CustomerID        int64
+	CohortMonth       time.Time // Month of the customer's first submitted order
+	FirstOrderDate    time.Time
+	LastOrderDate     time.Time
+	OrderCount        int64
+	TotalRevenue      float64
+	AverageOrderValue float64
+	RefreshedAt       time.Time
+}
+
+// IsRepeatCustomer checks if the customer placed more than one order
+func (c *CustomerLifetimeValue) IsRepeatCustomer() bool {
+	return c.OrderCount > 1
+}
+
+// CohortPeriod is the activity of a monthly acquisition cohort N months after acquisition
+type CohortPeriod struct {
+	CohortMonth      time.Time
+	MonthsSinceFirst int
+	CohortSize       int64
+	ActiveCustomers  int64
+	Revenue          float64
+	RetentionRate    float64 // ActiveCustomers / CohortSize
+	RefreshedAt      time.Time
+}
+
+// LTVSummary aggregates lifetime value metrics across all customers
+type LTVSummary struct {
+	Customers          int64
+	RepeatCustomers    int64
+	RepeatPurchaseRate float64 // RepeatCustomers / Customers
+	AverageLTV         float64
+	AverageOrderValue  float64
+	AverageOrderCount  float64
+	RefreshedAt        *time.Time
+}
+
+// AnalyticsFilter restricts analytics queries to a cohort range
+type AnalyticsFilter struct {
+	CohortFrom *time.Time
+	CohortTo   *time.Time
+	Limit      int
+	Offset     int
+}
+
+// NewAnalyticsFilter creates a default analytics filter covering the last 12 cohorts
+func NewAnalyticsFilter() *AnalyticsFilter {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -11, 0)
+	return &AnalyticsFilter{
+		CohortFrom: &from,
+		Limit:      50,
+	}
+}
+
+// AnalyticsRepository computes and reads customer analytics summary tables
+type AnalyticsRepository interface {
+	// RefreshSummaries recomputes the LTV and cohort summary tables from order history
+	RefreshSummaries(ctx context.Context) error
+
+	// FindLTVSummary aggregates the customer LTV table
+	FindLTVSummary(ctx context.Context, filter *AnalyticsFilter) (*LTVSummary, error)
+
+	// FindTopCustomers retrieves customers ordered by lifetime revenue
+	FindTopCustomers(ctx context.Context, filter *AnalyticsFilter) ([]*CustomerLifetimeValue, error)
+
+	// FindByCustomerID retrieves the LTV summary of a single customer
+	FindByCustomerID(ctx context.Context, customerID int64) (*CustomerLifetimeValue, error)
+
+	// FindCohorts retrieves monthly cohort periods
+	FindCohorts(ctx context.Context, filter *AnalyticsFilter) ([]*CohortPeriod, error)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ChannelReportFilter restricts channel reports to a date range and some sales channels
+type ChannelReportFilter struct {
+	From     time.Time // Inclusive
+	To       time.Time // Exclusive
+	Channels []string  // Empty for all channels
+}
+
+// NewChannelReportFilter creates a default channel report filter covering the last 30 days
+func NewChannelReportFilter() *ChannelReportFilter {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	return &ChannelReportFilter{
+		From: to.AddDate(0, 0, -30),
+		To:   to,
+	}
+}
+
+// ChannelRevenue is the revenue of the orders submitted through a sales channel in one currency
+type ChannelRevenue struct {
+	Channel           string
+	CurrencyCode      string
+	Orders            int64
+	Revenue           float64
+	AverageOrderValue float64
+}
+
+// ChannelConversion is how many of the carts started in a sales channel became submitted orders
+type ChannelConversion struct {
+	Channel        string
+	Carts          int64
+	Orders         int64
+	ConversionRate float64 // Orders / Carts
+}
+
+// ChannelReportRepository computes sales channel reports from order history
+type ChannelReportRepository interface {
+	// FindRevenue aggregates the orders submitted in the range by channel and currency
+	FindRevenue(ctx context.Context, filter *ChannelReportFilter) ([]*ChannelRevenue, error)
+
+	// FindConversion counts the carts created in the range by channel and those since submitted
+	FindConversion(ctx context.Context, filter *ChannelReportFilter) ([]*ChannelConversion, error)
+}
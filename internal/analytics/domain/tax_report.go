@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TaxReportFilter restricts tax reports to the orders whose tax was calculated in a date range
+type TaxReportFilter struct {
+	From time.Time // Inclusive
+	To   time.Time // Exclusive
+}
+
+// NewTaxReportFilter creates a default tax report filter covering the last 30 days
+func NewTaxReportFilter() *TaxReportFilter {
+	channels := NewChannelReportFilter()
+	return &TaxReportFilter{From: channels.From, To: channels.To}
+}
+
+// TaxLiability is the tax collected for a jurisdiction at one rate and in one currency
+type TaxLiability struct {
+	Country          string
+	Region           string
+	JurisdictionName string
+	TaxName          string
+	Rate             float64
+	CurrencyCode     string
+	Orders           int64
+	TaxableAmount    float64
+	Amount           float64
+}
+
+// TaxReportRepository computes tax reports from the tax details recorded on orders
+type TaxReportRepository interface {
+	// FindLiabilities aggregates the tax details calculated in the range by jurisdiction, rate and currency
+	FindLiabilities(ctx context.Context, filter *TaxReportFilter) ([]*TaxLiability, error)
+}
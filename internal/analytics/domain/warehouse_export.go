@@ -0,0 +1,240 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ColumnType is the type of a column of an exported dataset
+type ColumnType string
+
+const (
+	ColumnInt64     ColumnType = "int64"
+	ColumnDouble    ColumnType = "double"
+	ColumnString    ColumnType = "string"
+	ColumnBoolean   ColumnType = "boolean"
+	ColumnTimestamp ColumnType = "timestamp" // UTC
+)
+
+// DatasetColumn is a column of an exported dataset
+type DatasetColumn struct {
+	Name string     `json:"name"`
+	Type ColumnType `json:"type"`
+}
+
+// Dataset is a table exported to the data warehouse. Rows are extracted in the
+// order of a watermark (a timestamp and a unique key), so each run only exports
+// what changed since the previous one. The repository selects the columns in the
+// order they are listed here.
+type Dataset struct {
+	Name        string
+	Description string
+	Columns     []DatasetColumn
+}
+
+// Exported datasets
+const (
+	DatasetOrders                = "orders"
+	DatasetOrderItems            = "order_items"
+	DatasetInventoryTransactions = "inventory_transactions"
+	DatasetOfferRedemptions      = "offer_redemptions"
+)
+
+// WarehouseDatasets lists the datasets exported to the data warehouse. Columns may
+// be added freely; removing, renaming or retyping a column is a breaking change
+// that starts a new major schema version.
+var WarehouseDatasets = []*Dataset{
+	{
+		Name:        DatasetOrders,
+		Description: "Orders, exported again whenever they change",
+		Columns: []DatasetColumn{
+			{Name: "order_id", Type: ColumnInt64},
+			{Name: "order_number", Type: ColumnString},
+			{Name: "customer_id", Type: ColumnInt64},
+			{Name: "email_address", Type: ColumnString},
+			{Name: "order_status", Type: ColumnString},
+			{Name: "currency_code", Type: ColumnString},
+			{Name: "locale_code", Type: ColumnString},
+			{Name: "order_subtotal", Type: ColumnDouble},
+			{Name: "total_tax", Type: ColumnDouble},
+			{Name: "total_shipping", Type: ColumnDouble},
+			{Name: "order_total", Type: ColumnDouble},
+			{Name: "is_preview", Type: ColumnBoolean},
+			{Name: "submit_date", Type: ColumnTimestamp},
+			{Name: "date_created", Type: ColumnTimestamp},
+			{Name: "date_updated", Type: ColumnTimestamp},
+		},
+	},
+	{
+		Name:        DatasetOrderItems,
+		Description: "Order lines, exported again with their order whenever it changes",
+		Columns: []DatasetColumn{
+			{Name: "order_item_id", Type: ColumnInt64},
+			{Name: "order_id", Type: ColumnInt64},
+			{Name: "name", Type: ColumnString},
+			{Name: "order_item_type", Type: ColumnString},
+			{Name: "category_id", Type: ColumnInt64},
+			{Name: "quantity", Type: ColumnInt64},
+			{Name: "retail_price", Type: ColumnDouble},
+			{Name: "sale_price", Type: ColumnDouble},
+			{Name: "price", Type: ColumnDouble},
+			{Name: "total_tax", Type: ColumnDouble},
+			{Name: "currency_code", Type: ColumnString},
+			{Name: "order_updated", Type: ColumnTimestamp},
+		},
+	},
+	{
+		Name:        DatasetInventoryTransactions,
+		Description: "Changes to quantities on hand from the inventory adjustment ledger",
+		Columns: []DatasetColumn{
+			{Name: "adjustment_id", Type: ColumnString},
+			{Name: "inventory_id", Type: ColumnString},
+			{Name: "sku_id", Type: ColumnString},
+			{Name: "warehouse_id", Type: ColumnString},
+			{Name: "qty_before", Type: ColumnInt64},
+			{Name: "qty_after", Type: ColumnInt64},
+			{Name: "qty_change", Type: ColumnInt64},
+			{Name: "reason", Type: ColumnString},
+			{Name: "reference", Type: ColumnString},
+			{Name: "actor_id", Type: ColumnString},
+			{Name: "date_created", Type: ColumnTimestamp},
+		},
+	},
+	{
+		Name:        DatasetOfferRedemptions,
+		Description: "Offers applied to orders and order lines",
+		Columns: []DatasetColumn{
+			{Name: "redemption_id", Type: ColumnString},
+			{Name: "level", Type: ColumnString}, // ORDER or ITEM
+			{Name: "offer_id", Type: ColumnInt64},
+			{Name: "offer_name", Type: ColumnString},
+			{Name: "order_id", Type: ColumnInt64},
+			{Name: "order_item_id", Type: ColumnInt64},
+			{Name: "customer_id", Type: ColumnInt64},
+			{Name: "adjustment_reason", Type: ColumnString},
+			{Name: "adjustment_value", Type: ColumnDouble},
+			{Name: "currency_code", Type: ColumnString},
+			{Name: "created_at", Type: ColumnTimestamp},
+		},
+	},
+}
+
+// FindDataset finds an exported dataset by name
+func FindDataset(name string) *Dataset {
+	for _, dataset := range WarehouseDatasets {
+		if dataset.Name == name {
+			return dataset
+		}
+	}
+	return nil
+}
+
+// SchemaChange is how the columns of a dataset changed since its last export
+type SchemaChange string
+
+const (
+	SchemaNew       SchemaChange = "NEW" // The dataset was never exported
+	SchemaUnchanged SchemaChange = "UNCHANGED"
+	SchemaAdditive  SchemaChange = "ADDITIVE" // Columns were only added
+	SchemaBreaking  SchemaChange = "BREAKING" // Columns were removed, renamed or retyped
+)
+
+// CompareSchemas classifies the change from the previous to the current columns
+func CompareSchemas(previous, current []DatasetColumn) SchemaChange {
+	types := make(map[string]ColumnType, len(current))
+	for _, column := range current {
+		types[column.Name] = column.Type
+	}
+	for _, column := range previous {
+		columnType, ok := types[column.Name]
+		if !ok || columnType != column.Type {
+			return SchemaBreaking
+		}
+	}
+	if len(current) > len(previous) {
+		return SchemaAdditive
+	}
+	return SchemaUnchanged
+}
+
+// ExportWatermark records how far a dataset has been exported and with which schema.
+// Exports resume after (LastTimestamp, LastKey).
+type ExportWatermark struct {
+	Dataset       string
+	LastTimestamp *time.Time
+	LastKey       string
+	SchemaVersion int // Incremented on every schema change
+	SchemaMajor   int // Incremented on breaking changes; files are written under v{SchemaMajor}
+	Schema        []DatasetColumn
+	RowsExported  int64
+	FilesExported int64
+	LastRunAt     *time.Time
+	LastSuccessAt *time.Time
+	LastError     string
+	UpdatedAt     time.Time
+}
+
+// NewExportWatermark creates the watermark of a dataset that was never exported
+func NewExportWatermark(dataset *Dataset) *ExportWatermark {
+	return &ExportWatermark{
+		Dataset:       dataset.Name,
+		SchemaVersion: 1,
+		SchemaMajor:   1,
+		Schema:        dataset.Columns,
+	}
+}
+
+// ApplySchema moves the watermark to the current columns of its dataset. A breaking
+// change also restarts the export from the beginning, so the new major version holds
+// the full history.
+func (w *ExportWatermark) ApplySchema(columns []DatasetColumn) SchemaChange {
+	change := CompareSchemas(w.Schema, columns)
+	switch change {
+	case SchemaAdditive:
+		w.SchemaVersion++
+	case SchemaBreaking:
+		w.SchemaVersion++
+		w.SchemaMajor++
+		w.Reset()
+	}
+	w.Schema = columns
+	return change
+}
+
+// Advance moves the watermark past an exported batch
+func (w *ExportWatermark) Advance(last *ExtractedRow, rows, files int) {
+	timestamp := last.Timestamp
+	w.LastTimestamp = &timestamp
+	w.LastKey = last.Key
+	w.RowsExported += int64(rows)
+	w.FilesExported += int64(files)
+}
+
+// Reset makes the next export start from the beginning
+func (w *ExportWatermark) Reset() {
+	w.LastTimestamp = nil
+	w.LastKey = ""
+}
+
+// ExtractedRow is a row of a dataset with its position in watermark order
+type ExtractedRow struct {
+	Timestamp time.Time
+	Key       string
+	Values    []interface{} // In the order of the dataset columns; nil is null
+}
+
+// WarehouseExportRepository reads datasets for export and keeps their watermarks
+type WarehouseExportRepository interface {
+	// FindWatermarks retrieves the watermarks of all datasets exported so far
+	FindWatermarks(ctx context.Context) ([]*ExportWatermark, error)
+
+	// FindWatermark retrieves the watermark of a dataset, or nil if it was never exported
+	FindWatermark(ctx context.Context, dataset string) (*ExportWatermark, error)
+
+	// SaveWatermark creates or updates a watermark
+	SaveWatermark(ctx context.Context, watermark *ExportWatermark) error
+
+	// ExtractBatch reads up to limit rows of a dataset after the watermark and before
+	// until, in watermark order
+	ExtractBatch(ctx context.Context, dataset *Dataset, after *ExportWatermark, until time.Time, limit int) ([]*ExtractedRow, error)
+}
@@ -0,0 +1,219 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// countedOrders selects the orders that count towards analytics
+const countedOrders = `
+	SELECT order_id, customer_id, submit_date, COALESCE(order_total, 0) AS order_total
+	FROM blc_order
+	WHERE submit_date IS NOT NULL
+		AND COALESCE(is_preview, FALSE) = FALSE
+		AND order_status NOT IN ('CANCELLED', 'REFUNDED')`
+
+// PostgresAnalyticsRepository implements the AnalyticsRepository interface
+type PostgresAnalyticsRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAnalyticsRepository creates a new PostgresAnalyticsRepository
+func NewPostgresAnalyticsRepository(db *database.DB) *PostgresAnalyticsRepository {
+	return &PostgresAnalyticsRepository{db: db}
+}
+
+// RefreshSummaries recomputes the LTV and cohort summary tables in a single transaction
+// so dashboards never observe a partially refreshed state.
+func (r *PostgresAnalyticsRepository) RefreshSummaries(ctx context.Context) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM analytics_customer_ltv`); err != nil {
+			return errors.InternalWrap(err, "failed to clear customer ltv summary")
+		}
+
+		ltvQuery := `
+			INSERT INTO analytics_customer_ltv (
+				customer_id, cohort_month, first_order_date, last_order_date,
+				order_count, total_revenue, average_order_value, refreshed_at
+			)
+			SELECT customer_id, date_trunc('month', MIN(submit_date)), MIN(submit_date), MAX(submit_date),
+				COUNT(*), SUM(order_total), AVG(order_total), NOW()
+			FROM (` + countedOrders + `) o
+			GROUP BY customer_id`
+		if _, err := tx.Exec(ctx, ltvQuery); err != nil {
+			return errors.InternalWrap(err, "failed to refresh customer ltv summary")
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM analytics_cohort_period`); err != nil {
+			return errors.InternalWrap(err, "failed to clear cohort summary")
+		}
+
+		cohortQuery := `
+			INSERT INTO analytics_cohort_period (
+				cohort_month, months_since_first, cohort_size, active_customers, revenue, refreshed_at
+			)
+			SELECT l.cohort_month,
+				((EXTRACT(YEAR FROM o.submit_date) - EXTRACT(YEAR FROM l.cohort_month)) * 12
+					+ EXTRACT(MONTH FROM o.submit_date) - EXTRACT(MONTH FROM l.cohort_month))::int AS months_since_first,
+				s.cohort_size, COUNT(DISTINCT o.customer_id), SUM(o.order_total), NOW()
+			FROM (` + countedOrders + `) o
+			JOIN analytics_customer_ltv l ON l.customer_id = o.customer_id
+			JOIN (
+				SELECT cohort_month, COUNT(*) AS cohort_size FROM analytics_customer_ltv GROUP BY cohort_month
+			) s ON s.cohort_month = l.cohort_month
+			GROUP BY l.cohort_month, months_since_first, s.cohort_size`
+		if _, err := tx.Exec(ctx, cohortQuery); err != nil {
+			return errors.InternalWrap(err, "failed to refresh cohort summary")
+		}
+
+		return nil
+	})
+}
+
+// FindLTVSummary aggregates the customer LTV table
+func (r *PostgresAnalyticsRepository) FindLTVSummary(ctx context.Context, filter *domain.AnalyticsFilter) (*domain.LTVSummary, error) {
+	query := `
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE order_count > 1),
+			COALESCE(AVG(total_revenue), 0),
+			COALESCE(SUM(total_revenue) / NULLIF(SUM(order_count), 0), 0),
+			COALESCE(AVG(order_count), 0),
+			MAX(refreshed_at)
+		FROM analytics_customer_ltv
+		WHERE ($1::timestamp IS NULL OR cohort_month >= $1)
+			AND ($2::timestamp IS NULL OR cohort_month <= $2)`
+
+	summary := &domain.LTVSummary{}
+	var refreshedAt sql.NullTime
+	err := r.db.QueryRow(ctx, query, filter.CohortFrom, filter.CohortTo).Scan(
+		&summary.Customers,
+		&summary.RepeatCustomers,
+		&summary.AverageLTV,
+		&summary.AverageOrderValue,
+		&summary.AverageOrderCount,
+		&refreshedAt,
+	)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find ltv summary")
+	}
+
+	if summary.Customers > 0 {
+		summary.RepeatPurchaseRate = float64(summary.RepeatCustomers) / float64(summary.Customers)
+	}
+	if refreshedAt.Valid {
+		summary.RefreshedAt = &refreshedAt.Time
+	}
+	return summary, nil
+}
+
+// FindTopCustomers retrieves customers ordered by lifetime revenue
+func (r *PostgresAnalyticsRepository) FindTopCustomers(ctx context.Context, filter *domain.AnalyticsFilter) ([]*domain.CustomerLifetimeValue, error) {
+	query := `
+		SELECT customer_id, cohort_month, first_order_date, last_order_date,
+			order_count, total_revenue, average_order_value, refreshed_at
+		FROM analytics_customer_ltv
+		WHERE ($1::timestamp IS NULL OR cohort_month >= $1)
+			AND ($2::timestamp IS NULL OR cohort_month <= $2)
+		ORDER BY total_revenue DESC, customer_id
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(ctx, query, filter.CohortFrom, filter.CohortTo, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find top customers")
+	}
+	defer rows.Close()
+
+	customers := make([]*domain.CustomerLifetimeValue, 0)
+	for rows.Next() {
+		ltv, err := scanCustomerLTV(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, ltv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate top customers")
+	}
+	return customers, nil
+}
+
+// FindByCustomerID retrieves the LTV summary of a single customer
+func (r *PostgresAnalyticsRepository) FindByCustomerID(ctx context.Context, customerID int64) (*domain.CustomerLifetimeValue, error) {
+	query := `
+		SELECT customer_id, cohort_month, first_order_date, last_order_date,
+			order_count, total_revenue, average_order_value, refreshed_at
+		FROM analytics_customer_ltv
+		WHERE customer_id = $1`
+
+	ltv, err := scanCustomerLTV(r.db.QueryRow(ctx, query, customerID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return ltv, err
+}
+
+// FindCohorts retrieves monthly cohort periods
+func (r *PostgresAnalyticsRepository) FindCohorts(ctx context.Context, filter *domain.AnalyticsFilter) ([]*domain.CohortPeriod, error) {
+	query := `
+		SELECT cohort_month, months_since_first, cohort_size, active_customers, revenue, refreshed_at
+		FROM analytics_cohort_period
+		WHERE ($1::timestamp IS NULL OR cohort_month >= $1)
+			AND ($2::timestamp IS NULL OR cohort_month <= $2)
+		ORDER BY cohort_month, months_since_first`
+
+	rows, err := r.db.Query(ctx, query, filter.CohortFrom, filter.CohortTo)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find cohorts")
+	}
+	defer rows.Close()
+
+	periods := make([]*domain.CohortPeriod, 0)
+	for rows.Next() {
+		p := &domain.CohortPeriod{}
+		if err := rows.Scan(
+			&p.CohortMonth,
+			&p.MonthsSinceFirst,
+			&p.CohortSize,
+			&p.ActiveCustomers,
+			&p.Revenue,
+			&p.RefreshedAt,
+		); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan cohort")
+		}
+		if p.CohortSize > 0 {
+			p.RetentionRate = float64(p.ActiveCustomers) / float64(p.CohortSize)
+		}
+		periods = append(periods, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate cohorts")
+	}
+	return periods, nil
+}
+
+func scanCustomerLTV(row pgx.Row) (*domain.CustomerLifetimeValue, error) {
+	ltv := &domain.CustomerLifetimeValue{}
+	err := row.Scan(
+		&ltv.CustomerID,
+		&ltv.CohortMonth,
+		&ltv.FirstOrderDate,
+		&ltv.LastOrderDate,
+		&ltv.OrderCount,
+		&ltv.TotalRevenue,
+		&ltv.AverageOrderValue,
+		&ltv.RefreshedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to scan customer ltv")
+	}
+	return ltv, nil
+}
@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresChannelReportRepository implements the ChannelReportRepository interface
+type PostgresChannelReportRepository struct {
+	db *database.DB
+}
+
+// NewPostgresChannelReportRepository creates a new PostgresChannelReportRepository
+func NewPostgresChannelReportRepository(db *database.DB) *PostgresChannelReportRepository {
+	return &PostgresChannelReportRepository{db: db}
+}
+
+// FindRevenue aggregates the orders submitted in the range by channel and currency.
+// Orders are counted like the other analytics: previews, cancellations and
+// refunds are left out.
+func (r *PostgresChannelReportRepository) FindRevenue(ctx context.Context, filter *domain.ChannelReportFilter) ([]*domain.ChannelRevenue, error) {
+	query := `
+		SELECT channel, currency_code, COUNT(*), COALESCE(SUM(order_total), 0), COALESCE(AVG(order_total), 0)
+		FROM blc_order
+		WHERE submit_date >= $1 AND submit_date < $2
+			AND COALESCE(is_preview, FALSE) = FALSE
+			AND order_status NOT IN ('CANCELLED', 'REFUNDED')
+			AND (cardinality($3::text[]) = 0 OR channel = ANY($3))
+		GROUP BY channel, currency_code
+		ORDER BY channel, currency_code`
+
+	rows, err := r.db.Query(ctx, query, filter.From, filter.To, channelArg(filter))
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find channel revenue")
+	}
+	defer rows.Close()
+
+	revenues := make([]*domain.ChannelRevenue, 0)
+	for rows.Next() {
+		rev := &domain.ChannelRevenue{}
+		if err := rows.Scan(&rev.Channel, &rev.CurrencyCode, &rev.Orders, &rev.Revenue, &rev.AverageOrderValue); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan channel revenue")
+		}
+		revenues = append(revenues, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate channel revenue")
+	}
+	return revenues, nil
+}
+
+// FindConversion counts the carts created in the range by channel, and how many of
+// them have since been submitted and not cancelled
+func (r *PostgresChannelReportRepository) FindConversion(ctx context.Context, filter *domain.ChannelReportFilter) ([]*domain.ChannelConversion, error) {
+	query := `
+		SELECT channel, COUNT(*),
+			COUNT(*) FILTER (WHERE submit_date IS NOT NULL AND order_status NOT IN ('CANCELLED', 'REFUNDED'))
+		FROM blc_order
+		WHERE date_created >= $1 AND date_created < $2
+			AND COALESCE(is_preview, FALSE) = FALSE
+			AND (cardinality($3::text[]) = 0 OR channel = ANY($3))
+		GROUP BY channel
+		ORDER BY channel`
+
+	rows, err := r.db.Query(ctx, query, filter.From, filter.To, channelArg(filter))
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find channel conversion")
+	}
+	defer rows.Close()
+
+	conversions := make([]*domain.ChannelConversion, 0)
+	for rows.Next() {
+		c := &domain.ChannelConversion{}
+		if err := rows.Scan(&c.Channel, &c.Carts, &c.Orders); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan channel conversion")
+		}
+		if c.Carts > 0 {
+			c.ConversionRate = float64(c.Orders) / float64(c.Carts)
+		}
+		conversions = append(conversions, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate channel conversion")
+	}
+	return conversions, nil
+}
+
+// channelArg is the channels of the filter as a non-nil text array
+func channelArg(filter *domain.ChannelReportFilter) []string {
+	if filter.Channels == nil {
+		return []string{}
+	}
+	return filter.Channels
+}
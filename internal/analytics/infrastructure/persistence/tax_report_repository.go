@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresTaxReportRepository implements the TaxReportRepository interface
+type PostgresTaxReportRepository struct {
+	db *database.DB
+}
+
+// NewPostgresTaxReportRepository creates a new PostgresTaxReportRepository
+func NewPostgresTaxReportRepository(db *database.DB) *PostgresTaxReportRepository {
+	return &PostgresTaxReportRepository{db: db}
+}
+
+// FindLiabilities aggregates the tax details calculated in the range by jurisdiction,
+// rate and currency. Archived orders are still reported; cancelled and refunded
+// ones are left out like in the other analytics.
+func (r *PostgresTaxReportRepository) FindLiabilities(ctx context.Context, filter *domain.TaxReportFilter) ([]*domain.TaxLiability, error) {
+	query := `
+		SELECT d.tax_country, d.tax_region, d.jurisdiction_name, d.tax_name, d.rate::float8,
+			COALESCE(d.currency_code, ''), COUNT(DISTINCT d.order_id),
+			COALESCE(SUM(d.taxable_amount), 0)::float8, COALESCE(SUM(d.amount), 0)::float8
+		FROM order_tax_detail d
+		LEFT JOIN blc_order o ON o.order_id = d.order_id
+		LEFT JOIN blc_order_archive a ON a.order_id = d.order_id
+		WHERE d.calculated_at >= $1 AND d.calculated_at < $2
+			AND COALESCE(o.order_status, a.order_status) NOT IN ('CANCELLED', 'REFUNDED')
+		GROUP BY d.tax_country, d.tax_region, d.jurisdiction_name, d.tax_name, d.rate, d.currency_code
+		ORDER BY d.tax_country, d.tax_region, d.jurisdiction_name, d.tax_name, d.rate, d.currency_code`
+
+	rows, err := r.db.Query(ctx, query, filter.From, filter.To)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find tax liabilities")
+	}
+	defer rows.Close()
+
+	liabilities := make([]*domain.TaxLiability, 0)
+	for rows.Next() {
+		l := &domain.TaxLiability{}
+		err := rows.Scan(&l.Country, &l.Region, &l.JurisdictionName, &l.TaxName, &l.Rate,
+			&l.CurrencyCode, &l.Orders, &l.TaxableAmount, &l.Amount)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan tax liability")
+		}
+		liabilities = append(liabilities, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate tax liabilities")
+	}
+	return liabilities, nil
+}
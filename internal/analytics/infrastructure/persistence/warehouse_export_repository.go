@@ -0,0 +1,272 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// Extraction queries select the watermark timestamp and key, then the dataset columns
+// in order. Parameters are the watermark timestamp ($1) and key ($2), the exclusive
+// upper bound of the timestamp ($3) and the row limit ($4). Numeric amounts are read
+// as double precision and integer keys are zero-padded so they sort as text.
+var extractionQueries = map[string]string{
+	domain.DatasetOrders: `
+		SELECT wm_ts, wm_key, order_id, order_number, customer_id, email_address, order_status,
+			currency_code, locale_code, order_subtotal, total_tax, total_shipping, order_total,
+			is_preview, submit_date, date_created, date_updated
+		FROM (
+			SELECT COALESCE(o.date_updated, o.date_created, o.created_at AT TIME ZONE 'UTC') AS wm_ts,
+				lpad(o.order_id::text, 20, '0') AS wm_key,
+				o.order_id, o.order_number, o.customer_id, o.email_address, o.order_status,
+				o.currency_code, o.locale_code, o.order_subtotal::float8 AS order_subtotal,
+				o.total_tax::float8 AS total_tax, o.total_shipping::float8 AS total_shipping,
+				o.order_total::float8 AS order_total, o.is_preview, o.submit_date, o.date_created, o.date_updated
+			FROM blc_order o
+			WHERE COALESCE(o.date_updated, o.date_created, o.created_at AT TIME ZONE 'UTC') >= $1::timestamp
+		) t
+		WHERE (wm_ts, wm_key) > ($1::timestamp, $2::text) AND wm_ts < $3::timestamp
+		ORDER BY wm_ts, wm_key
+		LIMIT $4`,
+
+	domain.DatasetOrderItems: `
+		SELECT wm_ts, wm_key, order_item_id, order_id, name, order_item_type, category_id, quantity,
+			retail_price, sale_price, price, total_tax, currency_code, wm_ts
+		FROM (
+			SELECT COALESCE(o.date_updated, o.date_created, o.created_at AT TIME ZONE 'UTC') AS wm_ts,
+				lpad(o.order_id::text, 20, '0') || '-' || lpad(i.order_item_id::text, 20, '0') AS wm_key,
+				i.order_item_id, i.order_id, i.name, i.order_item_type, i.category_id, i.quantity,
+				i.retail_price::float8 AS retail_price, i.sale_price::float8 AS sale_price,
+				i.price::float8 AS price, i.total_tax::float8 AS total_tax, o.currency_code
+			FROM blc_order o
+			JOIN blc_order_item i ON i.order_id = o.order_id
+			WHERE COALESCE(o.date_updated, o.date_created, o.created_at AT TIME ZONE 'UTC') >= $1::timestamp
+		) t
+		WHERE (wm_ts, wm_key) > ($1::timestamp, $2::text) AND wm_ts < $3::timestamp
+		ORDER BY wm_ts, wm_key
+		LIMIT $4`,
+
+	domain.DatasetInventoryTransactions: `
+		SELECT a.date_created, a.id, a.id, a.inventory_id, a.sku_id, a.warehouse_id,
+			a.qty_before, a.qty_after, a.qty_after - a.qty_before, a.reason, a.reference, a.actor_id, a.date_created
+		FROM blc_inventory_adjustment a
+		WHERE a.stock = 'ON_HAND' -- Damaged and quarantined units are not on hand
+			AND a.date_created >= $1::timestamp
+			AND (a.date_created, a.id) > ($1::timestamp, $2::text)
+			AND a.date_created < $3::timestamp
+		ORDER BY a.date_created, a.id
+		LIMIT $4`,
+
+	domain.DatasetOfferRedemptions: `
+		SELECT wm_ts, wm_key, redemption_id, level, offer_id, offer_name, order_id, order_item_id,
+			customer_id, adjustment_reason, adjustment_value, currency_code, wm_ts
+		FROM (
+			SELECT a.created_at AT TIME ZONE 'UTC' AS wm_ts,
+				'ORDER-' || lpad(a.order_adjustment_id::text, 20, '0') AS wm_key,
+				'ORDER-' || a.order_adjustment_id AS redemption_id, 'ORDER' AS level,
+				a.offer_id, f.offer_name, a.order_id, NULL::bigint AS order_item_id, o.customer_id,
+				a.adjustment_reason, a.adjustment_value::float8 AS adjustment_value, o.currency_code
+			FROM blc_order_adjustment a
+			LEFT JOIN blc_offer f ON f.offer_id = a.offer_id
+			LEFT JOIN blc_order o ON o.order_id = a.order_id
+			WHERE a.created_at >= $1::timestamp AT TIME ZONE 'UTC'
+			UNION ALL
+			SELECT a.created_at AT TIME ZONE 'UTC',
+				'ITEM-' || lpad(a.order_item_adjustment_id::text, 20, '0'),
+				'ITEM-' || a.order_item_adjustment_id, 'ITEM',
+				a.offer_id, f.offer_name, i.order_id, a.order_item_id, o.customer_id,
+				a.adjustment_reason, a.adjustment_value::float8, o.currency_code
+			FROM blc_order_item_adjustment a
+			LEFT JOIN blc_offer f ON f.offer_id = a.offer_id
+			LEFT JOIN blc_order_item i ON i.order_item_id = a.order_item_id
+			LEFT JOIN blc_order o ON o.order_id = i.order_id
+			WHERE a.created_at >= $1::timestamp AT TIME ZONE 'UTC'
+		) t
+		WHERE (wm_ts, wm_key) > ($1::timestamp, $2::text) AND wm_ts < $3::timestamp
+		ORDER BY wm_ts, wm_key
+		LIMIT $4`,
+}
+
+// PostgresWarehouseExportRepository implements the WarehouseExportRepository interface
+type PostgresWarehouseExportRepository struct {
+	db *database.DB
+}
+
+// NewPostgresWarehouseExportRepository creates a new PostgresWarehouseExportRepository
+func NewPostgresWarehouseExportRepository(db *database.DB) *PostgresWarehouseExportRepository {
+	return &PostgresWarehouseExportRepository{db: db}
+}
+
+const watermarkColumns = `dataset, last_timestamp, last_key, schema_version, schema_major, schema_columns,
+	rows_exported, files_exported, last_run_at, last_success_at, last_error, updated_at`
+
+// FindWatermarks retrieves the watermarks of all datasets exported so far
+func (r *PostgresWarehouseExportRepository) FindWatermarks(ctx context.Context) ([]*domain.ExportWatermark, error) {
+	query := `SELECT ` + watermarkColumns + ` FROM analytics_export_watermark ORDER BY dataset`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find export watermarks")
+	}
+	defer rows.Close()
+
+	watermarks := make([]*domain.ExportWatermark, 0)
+	for rows.Next() {
+		watermark, err := scanWatermark(rows)
+		if err != nil {
+			return nil, err
+		}
+		watermarks = append(watermarks, watermark)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate export watermarks")
+	}
+	return watermarks, nil
+}
+
+// FindWatermark retrieves the watermark of a dataset, or nil if it was never exported
+func (r *PostgresWarehouseExportRepository) FindWatermark(ctx context.Context, dataset string) (*domain.ExportWatermark, error) {
+	query := `SELECT ` + watermarkColumns + ` FROM analytics_export_watermark WHERE dataset = $1`
+
+	watermark, err := scanWatermark(r.db.QueryRow(ctx, query, dataset))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return watermark, err
+}
+
+// SaveWatermark creates or updates a watermark
+func (r *PostgresWarehouseExportRepository) SaveWatermark(ctx context.Context, watermark *domain.ExportWatermark) error {
+	schema, err := json.Marshal(watermark.Schema)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to marshal export schema")
+	}
+	watermark.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO analytics_export_watermark (` + watermarkColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (dataset) DO UPDATE SET
+			last_timestamp = EXCLUDED.last_timestamp,
+			last_key = EXCLUDED.last_key,
+			schema_version = EXCLUDED.schema_version,
+			schema_major = EXCLUDED.schema_major,
+			schema_columns = EXCLUDED.schema_columns,
+			rows_exported = EXCLUDED.rows_exported,
+			files_exported = EXCLUDED.files_exported,
+			last_run_at = EXCLUDED.last_run_at,
+			last_success_at = EXCLUDED.last_success_at,
+			last_error = EXCLUDED.last_error,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.Pool().Exec(ctx, query,
+		watermark.Dataset,
+		watermark.LastTimestamp,
+		watermark.LastKey,
+		watermark.SchemaVersion,
+		watermark.SchemaMajor,
+		schema,
+		watermark.RowsExported,
+		watermark.FilesExported,
+		watermark.LastRunAt,
+		watermark.LastSuccessAt,
+		watermark.LastError,
+		watermark.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save export watermark")
+	}
+	return nil
+}
+
+// ExtractBatch reads up to limit rows of a dataset after the watermark and before until
+func (r *PostgresWarehouseExportRepository) ExtractBatch(ctx context.Context, dataset *domain.Dataset, after *domain.ExportWatermark, until time.Time, limit int) ([]*domain.ExtractedRow, error) {
+	query, ok := extractionQueries[dataset.Name]
+	if !ok {
+		return nil, errors.InternalWrap(fmt.Errorf("no extraction query for dataset %s", dataset.Name), "failed to extract dataset")
+	}
+
+	var afterTimestamp time.Time
+	var afterKey string
+	if after != nil && after.LastTimestamp != nil {
+		afterTimestamp = *after.LastTimestamp
+		afterKey = after.LastKey
+	}
+
+	rows, err := r.db.Query(ctx, query, afterTimestamp, afterKey, until, limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to extract "+dataset.Name)
+	}
+	defer rows.Close()
+
+	batch := make([]*domain.ExtractedRow, 0, limit)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan "+dataset.Name)
+		}
+		if len(values) != len(dataset.Columns)+2 {
+			return nil, errors.InternalWrap(fmt.Errorf("query returned %d columns, want %d", len(values)-2, len(dataset.Columns)),
+				"failed to extract "+dataset.Name)
+		}
+		timestamp, _ := values[0].(time.Time)
+		key, _ := values[1].(string)
+		batch = append(batch, &domain.ExtractedRow{
+			Timestamp: timestamp,
+			Key:       key,
+			Values:    values[2:],
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate "+dataset.Name)
+	}
+	return batch, nil
+}
+
+func scanWatermark(row pgx.Row) (*domain.ExportWatermark, error) {
+	watermark := &domain.ExportWatermark{}
+	var lastTimestamp, lastRunAt, lastSuccessAt sql.NullTime
+	var schema []byte
+	err := row.Scan(
+		&watermark.Dataset,
+		&lastTimestamp,
+		&watermark.LastKey,
+		&watermark.SchemaVersion,
+		&watermark.SchemaMajor,
+		&schema,
+		&watermark.RowsExported,
+		&watermark.FilesExported,
+		&lastRunAt,
+		&lastSuccessAt,
+		&watermark.LastError,
+		&watermark.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to scan export watermark")
+	}
+
+	if err := json.Unmarshal(schema, &watermark.Schema); err != nil {
+		return nil, errors.InternalWrap(err, "failed to unmarshal export schema")
+	}
+	if lastTimestamp.Valid {
+		watermark.LastTimestamp = &lastTimestamp.Time
+	}
+	if lastRunAt.Valid {
+		watermark.LastRunAt = &lastRunAt.Time
+	}
+	if lastSuccessAt.Valid {
+		watermark.LastSuccessAt = &lastSuccessAt.Time
+	}
+	return watermark, nil
+}
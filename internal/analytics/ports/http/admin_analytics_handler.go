@@ -0,0 +1,161 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/analytics/application"
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// cohortParamLayout is the accepted format for cohort_from/cohort_to query parameters
+const cohortParamLayout = "2006-01"
+
+// AdminAnalyticsHandler handles admin customer analytics requests
+type AdminAnalyticsHandler struct {
+	analyticsService application.AnalyticsService
+	logger           *logger.Logger
+}
+
+// NewAdminAnalyticsHandler creates a new admin analytics handler
+func NewAdminAnalyticsHandler(analyticsService application.AnalyticsService, logger *logger.Logger) *AdminAnalyticsHandler {
+	return &AdminAnalyticsHandler{
+		analyticsService: analyticsService,
+		logger:           logger,
+	}
+}
+
+// RegisterRoutes registers admin analytics routes
+func (h *AdminAnalyticsHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/analytics", func(r chi.Router) {
+		r.Get("/ltv", h.GetLTVSummary)
+		r.Get("/cohorts", h.GetCohorts)
+		r.Get("/customers/top", h.GetTopCustomers)
+		r.Get("/customers/{id}", h.GetCustomerLTV)
+		r.Post("/refresh", h.RefreshSummaries)
+	})
+}
+
+// GetLTVSummary returns average LTV, order value and repeat purchase rate
+func (h *AdminAnalyticsHandler) GetLTVSummary(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAnalyticsFilter(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	summary, err := h.analyticsService.GetLTVSummary(r.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get ltv summary")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, summary)
+}
+
+// GetCohorts returns monthly cohorts as chart series
+func (h *AdminAnalyticsHandler) GetCohorts(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAnalyticsFilter(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	cohorts, err := h.analyticsService.GetCohorts(r.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get cohorts")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, cohorts)
+}
+
+// GetTopCustomers returns customers ordered by lifetime revenue
+func (h *AdminAnalyticsHandler) GetTopCustomers(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAnalyticsFilter(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	customers, err := h.analyticsService.GetTopCustomers(r.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get top customers")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, customers)
+}
+
+// GetCustomerLTV returns the lifetime value of a single customer
+func (h *AdminAnalyticsHandler) GetCustomerLTV(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid customer ID"))
+		return
+	}
+
+	ltv, err := h.analyticsService.GetCustomerLTV(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get customer ltv")
+		pkghttp.RespondError(w, err)
+		return
+	}
+	if ltv == nil {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError("no analytics for customer"))
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, ltv)
+}
+
+// RefreshSummaries triggers a recomputation of the summary tables
+func (h *AdminAnalyticsHandler) RefreshSummaries(w http.ResponseWriter, r *http.Request) {
+	if err := h.analyticsService.RefreshSummaries(r.Context()); err != nil {
+		h.logger.WithError(err).Error("failed to refresh analytics")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"refreshed": true,
+	})
+}
+
+func parseAnalyticsFilter(r *http.Request) (*domain.AnalyticsFilter, error) {
+	filter := domain.NewAnalyticsFilter()
+	query := r.URL.Query()
+
+	if from := query.Get("cohort_from"); from != "" {
+		t, err := time.Parse(cohortParamLayout, from)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid cohort_from, expected YYYY-MM")
+		}
+		filter.CohortFrom = &t
+	}
+	if to := query.Get("cohort_to"); to != "" {
+		t, err := time.Parse(cohortParamLayout, to)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid cohort_to, expected YYYY-MM")
+		}
+		filter.CohortTo = &t
+	}
+
+	filter.Limit = pkghttp.GetQueryParamInt(r, "limit", filter.Limit)
+	if filter.Limit < 1 || filter.Limit > 500 {
+		filter.Limit = 50
+	}
+	filter.Offset = pkghttp.GetQueryParamInt(r, "offset", 0)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/analytics/application"
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// reportDateParamLayout is the accepted format for from/to query parameters
+const reportDateParamLayout = "2006-01-02"
+
+// AdminChannelReportHandler handles admin sales channel report requests
+type AdminChannelReportHandler struct {
+	reportService application.ChannelReportService
+	logger        *logger.Logger
+}
+
+// NewAdminChannelReportHandler creates a new admin channel report handler
+func NewAdminChannelReportHandler(reportService application.ChannelReportService, logger *logger.Logger) *AdminChannelReportHandler {
+	return &AdminChannelReportHandler{
+		reportService: reportService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes registers admin channel report routes
+func (h *AdminChannelReportHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/analytics/channels", func(r chi.Router) {
+		r.Get("/revenue", h.GetRevenue)
+		r.Get("/conversion", h.GetConversion)
+	})
+}
+
+// GetRevenue returns the revenue of each sales channel
+func (h *AdminChannelReportHandler) GetRevenue(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseChannelReportFilter(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	report, err := h.reportService.GetRevenue(r.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get channel revenue")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, report)
+}
+
+// GetConversion returns the cart conversion of each sales channel
+func (h *AdminChannelReportHandler) GetConversion(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseChannelReportFilter(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	report, err := h.reportService.GetConversion(r.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get channel conversion")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, report)
+}
+
+// parseChannelReportFilter reads from and to (YYYY-MM-DD, both inclusive) and channel,
+// which may be repeated or comma-separated
+func parseChannelReportFilter(r *http.Request) (*domain.ChannelReportFilter, error) {
+	filter := domain.NewChannelReportFilter()
+	query := r.URL.Query()
+
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse(reportDateParamLayout, from)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid from, expected YYYY-MM-DD")
+		}
+		filter.From = t
+	}
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse(reportDateParamLayout, to)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid to, expected YYYY-MM-DD")
+		}
+		filter.To = t.AddDate(0, 0, 1)
+	}
+
+	for _, value := range query["channel"] {
+		for _, channel := range strings.Split(value, ",") {
+			if channel = strings.TrimSpace(channel); channel != "" {
+				filter.Channels = append(filter.Channels, channel)
+			}
+		}
+	}
+
+	return filter, nil
+}
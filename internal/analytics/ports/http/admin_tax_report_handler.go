@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/analytics/application"
+	"github.com/qhato/ecommerce/internal/analytics/domain"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminTaxReportHandler handles admin tax report requests
+type AdminTaxReportHandler struct {
+	reportService application.TaxReportService
+	logger        *logger.Logger
+}
+
+// NewAdminTaxReportHandler creates a new admin tax report handler
+func NewAdminTaxReportHandler(reportService application.TaxReportService, logger *logger.Logger) *AdminTaxReportHandler {
+	return &AdminTaxReportHandler{
+		reportService: reportService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes registers admin tax report routes
+func (h *AdminTaxReportHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/admin/analytics/tax", h.GetLiabilities)
+}
+
+// GetLiabilities returns the tax collected for each jurisdiction
+func (h *AdminTaxReportHandler) GetLiabilities(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTaxReportFilter(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	report, err := h.reportService.GetLiabilities(r.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get tax liabilities")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, report)
+}
+
+// parseTaxReportFilter reads from and to (YYYY-MM-DD, both inclusive)
+func parseTaxReportFilter(r *http.Request) (*domain.TaxReportFilter, error) {
+	filter := domain.NewTaxReportFilter()
+	query := r.URL.Query()
+
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse(reportDateParamLayout, from)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid from, expected YYYY-MM-DD")
+		}
+		filter.From = t
+	}
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse(reportDateParamLayout, to)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid to, expected YYYY-MM-DD")
+		}
+		filter.To = t.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/analytics/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminWarehouseExportHandler handles admin requests for the data warehouse export
+type AdminWarehouseExportHandler struct {
+	exportService application.WarehouseExportService
+	logger        *logger.Logger
+}
+
+// NewAdminWarehouseExportHandler creates a new admin warehouse export handler
+func NewAdminWarehouseExportHandler(exportService application.WarehouseExportService, logger *logger.Logger) *AdminWarehouseExportHandler {
+	return &AdminWarehouseExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes registers admin warehouse export routes
+func (h *AdminWarehouseExportHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/analytics/warehouse-exports", func(r chi.Router) {
+		r.Get("/", h.ListExports)
+		r.Post("/run", h.RunExport)
+		r.Post("/{dataset}/reset", h.ResetExport)
+	})
+}
+
+// ListExports returns the export state and schema of every dataset
+func (h *AdminWarehouseExportHandler) ListExports(w http.ResponseWriter, r *http.Request) {
+	exports, err := h.exportService.ListExports(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list warehouse exports")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, exports)
+}
+
+// RunExport exports the requested datasets, or all of them, and waits for the run to finish
+func (h *AdminWarehouseExportHandler) RunExport(w http.ResponseWriter, r *http.Request) {
+	var req application.RunExportRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+			return
+		}
+	}
+
+	results, err := h.exportService.RunExport(r.Context(), req.Datasets)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to run warehouse export")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, results)
+}
+
+// ResetExport makes the next run export a dataset from the beginning
+func (h *AdminWarehouseExportHandler) ResetExport(w http.ResponseWriter, r *http.Request) {
+	export, err := h.exportService.ResetExport(r.Context(), chi.URLParam(r, "dataset"))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to reset warehouse export")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, export)
+}
@@ -0,0 +1,204 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/anonymize/domain"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// RunAnonymizationCommand selects what an anonymization run rewrites
+type RunAnonymizationCommand struct {
+	Tables    []string // Tables to anonymize, among the default rules; empty anonymizes all
+	BatchSize int
+	Restart   bool // Anonymize the tables again instead of resuming; only for a freshly restored snapshot
+}
+
+// TableReport is the outcome of anonymizing one table
+type TableReport struct {
+	Table         string `json:"table"`
+	Status        string `json:"status"` // COMPLETED, SKIPPED or INTERRUPTED
+	SkippedReason string `json:"skipped_reason,omitempty"`
+	Columns       int    `json:"columns"`
+	Rows          int64  `json:"rows"`
+	LastKey       int64  `json:"last_key"`
+	Resumed       bool   `json:"resumed,omitempty"`
+	Duration      string `json:"duration"`
+}
+
+// Report statuses
+const (
+	TableCompleted   = "COMPLETED"
+	TableSkipped     = "SKIPPED"
+	TableInterrupted = "INTERRUPTED"
+)
+
+// AnonymizeService rewrites personal data in place, so a production snapshot
+// can be used in non-production environments
+type AnonymizeService interface {
+	// Run anonymizes the selected tables batch by batch. A run that is
+	// interrupted resumes, table by table, after the last committed batch;
+	// rows are never anonymized twice, which would break the match between
+	// tables.
+	Run(ctx context.Context, cmd *RunAnonymizationCommand) ([]*TableReport, error)
+}
+
+type anonymizeService struct {
+	repo   domain.Repository
+	faker  *Faker
+	rules  []domain.TableRule
+	logger *logger.Logger
+}
+
+// NewAnonymizeService creates a new anonymization service using the default table rules
+func NewAnonymizeService(repo domain.Repository, faker *Faker, logger *logger.Logger) AnonymizeService {
+	return &anonymizeService{
+		repo:   repo,
+		faker:  faker,
+		rules:  domain.DefaultTableRules,
+		logger: logger,
+	}
+}
+
+func (s *anonymizeService) Run(ctx context.Context, cmd *RunAnonymizationCommand) ([]*TableReport, error) {
+	if cmd.BatchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be greater than zero")
+	}
+	rules, err := s.selectRules(cmd.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Restart {
+		tables := make([]string, len(rules))
+		for i, rule := range rules {
+			tables[i] = rule.Table
+		}
+		if err := s.repo.ResetProgress(ctx, tables); err != nil {
+			return nil, err
+		}
+	}
+
+	reports := make([]*TableReport, 0, len(rules))
+	for _, rule := range rules {
+		report, err := s.runTable(ctx, rule, cmd.BatchSize)
+		if err != nil {
+			return reports, fmt.Errorf("failed to anonymize %s: %w", rule.Table, err)
+		}
+		reports = append(reports, report)
+		if report.Status == TableInterrupted {
+			break
+		}
+	}
+	return reports, nil
+}
+
+// runTable anonymizes the columns of a table that exist, from where an
+// earlier run stopped
+func (s *anonymizeService) runTable(ctx context.Context, rule domain.TableRule, batchSize int) (*TableReport, error) {
+	started := time.Now()
+	log := s.logger.WithField("table", rule.Table)
+	report := &TableReport{Table: rule.Table}
+
+	columns, err := s.repo.Columns(ctx, rule.Table)
+	if err != nil {
+		return nil, err
+	}
+	if columns == nil {
+		report.Status, report.SkippedReason = TableSkipped, "table does not exist"
+		log.Info("Table not present, skipping")
+		return report, nil
+	}
+	// Tables from older Broadleaf schemas may lack some columns
+	present := rule
+	present.Columns = nil
+	for _, column := range rule.Columns {
+		if columns[column.Column] {
+			present.Columns = append(present.Columns, column)
+		}
+	}
+	report.Columns = len(present.Columns)
+	if !columns[rule.KeyColumn] || len(present.Columns) == 0 {
+		report.Status, report.SkippedReason = TableSkipped, "no anonymized columns"
+		log.Info("Table has no anonymized columns, skipping")
+		return report, nil
+	}
+
+	progress, err := s.repo.FindProgress(ctx, rule.Table)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		progress = &domain.TableProgress{Table: rule.Table, StartedAt: started}
+	} else {
+		report.Resumed = progress.LastKey > 0 || progress.IsCompleted()
+	}
+
+	for !progress.IsCompleted() {
+		if ctx.Err() != nil {
+			break
+		}
+		rows, err := s.repo.ReadBatch(ctx, present, progress.LastKey, batchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			for i, column := range present.Columns {
+				if row.Values[i] == nil {
+					continue
+				}
+				fake := s.faker.Fake(column.Kind, *row.Values[i])
+				row.Values[i] = &fake
+			}
+		}
+
+		now := time.Now()
+		if len(rows) > 0 {
+			progress.LastKey = rows[len(rows)-1].Key
+			progress.Rows += int64(len(rows))
+		}
+		if len(rows) < batchSize {
+			progress.CompletedAt = &now
+		}
+		progress.UpdatedAt = now
+
+		// A batch cut short by an interrupt is committed as a whole or not at all
+		if err := s.repo.WriteBatch(context.WithoutCancel(ctx), present, rows, progress); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Rows = progress.Rows
+	report.LastKey = progress.LastKey
+	report.Duration = time.Since(started).Round(time.Millisecond).String()
+	if progress.IsCompleted() {
+		report.Status = TableCompleted
+		log.WithField("rows", progress.Rows).Info("Table anonymized")
+	} else {
+		report.Status = TableInterrupted
+		log.WithField("last_key", progress.LastKey).Warn("Anonymization interrupted; rerun to resume")
+	}
+	return report, nil
+}
+
+func (s *anonymizeService) selectRules(tables []string) ([]domain.TableRule, error) {
+	if len(tables) == 0 {
+		return s.rules, nil
+	}
+	byTable := make(map[string]domain.TableRule, len(s.rules))
+	for _, rule := range s.rules {
+		byTable[rule.Table] = rule
+	}
+	rules := make([]domain.TableRule, 0, len(tables))
+	for _, table := range tables {
+		rule, ok := byTable[table]
+		if !ok {
+			return nil, fmt.Errorf("no anonymization rule for table %q", table)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,184 @@
+package application
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/anonymize/domain"
+)
+
+var (
+	firstNames = []string{
+		"Alex", "Amara", "Ben", "Carmen", "Chloe", "Daniel", "Diego", "Elena", "Emma", "Felix",
+		"Grace", "Hana", "Hugo", "Isla", "Ivan", "Jade", "Jonas", "Kai", "Laura", "Leo",
+		"Lucia", "Maya", "Mateo", "Nina", "Noah", "Olivia", "Omar", "Paula", "Quinn", "Rosa",
+		"Sam", "Sofia", "Theo", "Uma", "Victor", "Wen", "Xavier", "Yara", "Zane", "Zoe",
+	}
+	lastNames = []string{
+		"Abbott", "Bauer", "Castillo", "Dalton", "Ellis", "Fischer", "Garcia", "Hale", "Ibarra", "Jensen",
+		"Keller", "Lambert", "Moreno", "Novak", "Ortega", "Porter", "Quintero", "Reyes", "Sato", "Thornton",
+		"Underwood", "Vargas", "Walsh", "Xu", "Young", "Zimmer", "Baker", "Cruz", "Doyle", "Fleming",
+		"Gomez", "Harper", "Iverson", "Kowalski", "Larsen", "Meyer", "Nakamura", "Olsen", "Price", "Rossi",
+	}
+	streetNames = []string{
+		"Maple", "Oak", "Cedar", "Pine", "Elm", "Willow", "Birch", "Aspen", "Juniper", "Magnolia",
+		"Harbor", "Meadow", "River", "Summit", "Valley", "Lake", "Forest", "Hillside", "Orchard", "Sunset",
+	}
+	streetTypes    = []string{"St", "Ave", "Rd", "Ln", "Blvd", "Way", "Ct", "Dr"}
+	companySuffix  = []string{"Ltd", "LLC", "Group", "Trading", "Partners", "Studio", "Supply", "Works"}
+	secondaryTypes = []string{"Apt", "Suite", "Unit", "Floor"}
+	fillerWords    = []string{
+		"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+		"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+		"ad", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip",
+	}
+)
+
+const (
+	fakeEmailDomain  = "example.com" // Reserved for documentation, so staging never mails a real inbox
+	maxFillerWords   = 60
+	letterAlphabet   = "abcdefghijklmnopqrstuvwxyz"
+	digitAlphabet    = "0123456789"
+	maxKeptPrefixLen = 4
+)
+
+// Faker replaces values deterministically: a value keyed with the same key
+// always gets the same replacement, in every table it appears in, so customer
+// emails still match their orders' emails and transaction IDs their tenders.
+// Without the key a replacement cannot be traced back to its value.
+type Faker struct {
+	key          []byte
+	passwordHash string
+}
+
+// NewFaker creates a Faker. passwordHash replaces every account's password;
+// empty leaves accounts without a password, so none can sign in.
+func NewFaker(key []byte, passwordHash string) (*Faker, error) {
+	if len(key) < 16 {
+		return nil, fmt.Errorf("the anonymization key must be at least 16 bytes")
+	}
+	return &Faker{key: key, passwordHash: passwordHash}, nil
+}
+
+// Fake returns the replacement of a value. Empty values stay empty.
+func (f *Faker) Fake(kind domain.FieldKind, value string) string {
+	if kind == domain.FieldPassword {
+		return f.passwordHash
+	}
+	if strings.TrimSpace(value) == "" || kind == domain.FieldBlank {
+		return ""
+	}
+
+	switch kind {
+	case domain.FieldEmail:
+		return f.email(value)
+	case domain.FieldUserName:
+		if strings.Contains(value, "@") {
+			return f.email(value)
+		}
+		sum := f.sum("user_name", strings.ToLower(strings.TrimSpace(value)))
+		return strings.ToLower(pick(firstNames, sum, 0)+"."+pick(lastNames, sum, 4)) + "." + hex.EncodeToString(sum[8:14])
+	case domain.FieldFirstName:
+		return pick(firstNames, f.sum("first_name", normalizeName(value)), 0)
+	case domain.FieldLastName:
+		return pick(lastNames, f.sum("last_name", normalizeName(value)), 0)
+	case domain.FieldCompany:
+		sum := f.sum("company", normalizeName(value))
+		return pick(lastNames, sum, 0) + " " + pick(companySuffix, sum, 4)
+	case domain.FieldStreet:
+		sum := f.sum("street", normalizeName(value))
+		number := binary.BigEndian.Uint32(sum[8:12])%9999 + 1
+		return fmt.Sprintf("%d %s %s", number, pick(streetNames, sum, 0), pick(streetTypes, sum, 4))
+	case domain.FieldAddressLine:
+		sum := f.sum("address_line", normalizeName(value))
+		return fmt.Sprintf("%s %d", pick(secondaryTypes, sum, 0), binary.BigEndian.Uint32(sum[4:8])%999+1)
+	case domain.FieldPhone:
+		return f.scramble("phone", value, false)
+	case domain.FieldReference:
+		return f.scramble("reference", value, true)
+	case domain.FieldFreeText:
+		return f.filler(value)
+	default:
+		return ""
+	}
+}
+
+// email replaces an address, ignoring case and surrounding spaces so the
+// same customer matches across tables. The suffix keeps replacements unique.
+func (f *Faker) email(value string) string {
+	sum := f.sum("email", strings.ToLower(strings.TrimSpace(value)))
+	local := strings.ToLower(pick(firstNames, sum, 0) + "." + pick(lastNames, sum, 4))
+	return local + "." + hex.EncodeToString(sum[8:14]) + "@" + fakeEmailDomain
+}
+
+// scramble replaces each letter with a letter and each digit with a digit,
+// keeping case, length and separators. With keepPrefix a short lowercase
+// prefix such as the "pi_" of a gateway ID is kept, so its type stays readable.
+func (f *Faker) scramble(purpose, value string, keepPrefix bool) string {
+	prefixLen := 0
+	if keepPrefix {
+		if i := strings.IndexByte(value, '_'); i > 0 && i <= maxKeptPrefixLen && strings.Trim(value[:i], letterAlphabet) == "" {
+			prefixLen = i + 1
+		}
+	}
+
+	stream := f.stream(purpose, value, len(value))
+	out := []byte(value)
+	for i := prefixLen; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			out[i] = letterAlphabet[int(stream[i])%len(letterAlphabet)]
+		case c >= 'A' && c <= 'Z':
+			out[i] = letterAlphabet[int(stream[i])%len(letterAlphabet)] - 'a' + 'A'
+		case c >= '0' && c <= '9':
+			out[i] = digitAlphabet[int(stream[i])%len(digitAlphabet)]
+		}
+	}
+	return string(out)
+}
+
+// filler replaces text with as many filler words as it had, up to a limit
+func (f *Faker) filler(value string) string {
+	count := len(strings.Fields(value))
+	if count > maxFillerWords {
+		count = maxFillerWords
+	}
+	stream := f.stream("free_text", value, count)
+	words := make([]string, count)
+	for i := range words {
+		words[i] = fillerWords[int(stream[i])%len(fillerWords)]
+	}
+	return strings.Join(words, " ")
+}
+
+// sum keys a value for one purpose, so equal values of different kinds get unrelated replacements
+func (f *Faker) sum(purpose, value string) []byte {
+	mac := hmac.New(sha256.New, f.key)
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// stream returns n keyed bytes for a value
+func (f *Faker) stream(purpose, value string, n int) []byte {
+	out := make([]byte, 0, n+sha256.Size)
+	for block := 0; len(out) < n; block++ {
+		out = append(out, f.sum(fmt.Sprintf("%s#%d", purpose, block), value)...)
+	}
+	return out[:n]
+}
+
+// pick chooses an entry of a list from four bytes of a sum
+func pick(list []string, sum []byte, offset int) string {
+	return list[binary.BigEndian.Uint32(sum[offset:offset+4])%uint32(len(list))]
+}
+
+func normalizeName(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
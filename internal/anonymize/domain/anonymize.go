@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// FieldKind decides how the value of a column is replaced
+type FieldKind string
+
+const (
+	FieldEmail       FieldKind = "email"        // A fake address at example.com; the same email always gets the same fake
+	FieldUserName    FieldKind = "user_name"    // A fake email when the user name is an email, a fake handle otherwise
+	FieldFirstName   FieldKind = "first_name"   // A name from a fixed list
+	FieldLastName    FieldKind = "last_name"    // A name from a fixed list
+	FieldCompany     FieldKind = "company"      // A fake company name
+	FieldStreet      FieldKind = "street"       // A fake street address
+	FieldAddressLine FieldKind = "address_line" // A fake apartment or suite
+	FieldPhone       FieldKind = "phone"        // Digits replaced, formatting kept
+	FieldReference   FieldKind = "reference"    // Letters and digits replaced, length and separators kept
+	FieldFreeText    FieldKind = "free_text"    // Filler words, as many as the original had
+	FieldPassword    FieldKind = "password"     // One hash shared by every account
+	FieldBlank       FieldKind = "blank"        // Emptied
+)
+
+// ColumnRule anonymizes one column of a table
+type ColumnRule struct {
+	Column string
+	Kind   FieldKind
+}
+
+// TableRule anonymizes the columns of a table, walking it in order of its
+// integer key column
+type TableRule struct {
+	Table     string
+	KeyColumn string
+	Columns   []ColumnRule
+}
+
+// DefaultTableRules are the tables holding customer PII, order emails and
+// payment references. Cities, regions, postal and country codes are kept so
+// tax, shipping and reporting behave as in production.
+var DefaultTableRules = []TableRule{
+	{Table: "blc_customer", KeyColumn: "customer_id", Columns: []ColumnRule{
+		{Column: "email_address", Kind: FieldEmail},
+		{Column: "user_name", Kind: FieldUserName},
+		{Column: "first_name", Kind: FieldFirstName},
+		{Column: "last_name", Kind: FieldLastName},
+		{Column: "password", Kind: FieldPassword},
+		{Column: "challenge_answer", Kind: FieldBlank},
+		{Column: "tax_exemption_code", Kind: FieldReference},
+	}},
+	{Table: "blc_address", KeyColumn: "address_id", Columns: []ColumnRule{
+		{Column: "first_name", Kind: FieldFirstName},
+		{Column: "last_name", Kind: FieldLastName},
+		{Column: "company_name", Kind: FieldCompany},
+		{Column: "address_line1", Kind: FieldStreet},
+		{Column: "address_line2", Kind: FieldAddressLine},
+		{Column: "address_line3", Kind: FieldAddressLine},
+		{Column: "primary_phone", Kind: FieldPhone},
+	}},
+	{Table: "customer_note", KeyColumn: "customer_note_id", Columns: []ColumnRule{
+		{Column: "body", Kind: FieldFreeText},
+	}},
+	{Table: "customer_merge", KeyColumn: "merge_id", Columns: []ColumnRule{
+		{Column: "merged_email", Kind: FieldEmail},
+	}},
+	{Table: "customer_import_error", KeyColumn: "error_id", Columns: []ColumnRule{
+		{Column: "email_address", Kind: FieldEmail},
+	}},
+	{Table: "blc_order", KeyColumn: "order_id", Columns: []ColumnRule{
+		{Column: "email_address", Kind: FieldEmail},
+	}},
+	{Table: "blc_order_archive", KeyColumn: "order_id", Columns: []ColumnRule{
+		{Column: "email_address", Kind: FieldEmail},
+	}},
+	{Table: "order_note", KeyColumn: "order_note_id", Columns: []ColumnRule{
+		{Column: "body", Kind: FieldFreeText},
+	}},
+	{Table: "order_hold", KeyColumn: "hold_id", Columns: []ColumnRule{
+		{Column: "note", Kind: FieldFreeText},
+		{Column: "resolution_note", Kind: FieldFreeText},
+	}},
+	{Table: "blc_personal_message", KeyColumn: "personal_message_id", Columns: []ColumnRule{
+		{Column: "message", Kind: FieldFreeText},
+	}},
+	{Table: "blc_offer_code", KeyColumn: "offer_code_id", Columns: []ColumnRule{
+		{Column: "email_address", Kind: FieldEmail},
+	}},
+	{Table: "blc_order_payment", KeyColumn: "payment_id", Columns: []ColumnRule{
+		{Column: "transaction_id", Kind: FieldReference},
+		{Column: "authorization_code", Kind: FieldReference},
+	}},
+	{Table: "order_payment_tender", KeyColumn: "id", Columns: []ColumnRule{
+		{Column: "transaction_id", Kind: FieldReference},
+	}},
+	{Table: "order_offline_payment", KeyColumn: "order_id", Columns: []ColumnRule{
+		{Column: "reference", Kind: FieldReference},
+		{Column: "receipt_reference", Kind: FieldReference},
+		{Column: "note", Kind: FieldFreeText},
+	}},
+	{Table: "order_payment_link", KeyColumn: "id", Columns: []ColumnRule{
+		{Column: "token", Kind: FieldReference},
+	}},
+}
+
+// TableProgress is how far a table has been anonymized. LastKey is the key of
+// the last row committed, from which an interrupted run resumes.
+type TableProgress struct {
+	Table       string
+	LastKey     int64
+	Rows        int64
+	StartedAt   time.Time
+	CompletedAt *time.Time
+	UpdatedAt   time.Time
+}
+
+// IsCompleted reports whether every row of the table has been anonymized
+func (p *TableProgress) IsCompleted() bool {
+	return p.CompletedAt != nil
+}
+
+// Row is a row of a table read for anonymization. Values follow the order of
+// the rule's columns; nil is NULL.
+type Row struct {
+	Key    int64
+	Values []*string
+}
+
+// Repository reads and rewrites the tables being anonymized
+type Repository interface {
+	// Columns lists the columns a table has; nil when the table does not exist
+	Columns(ctx context.Context, table string) (map[string]bool, error)
+
+	// FindProgress retrieves the progress of a table; nil when it was never started
+	FindProgress(ctx context.Context, table string) (*TableProgress, error)
+
+	// ReadBatch retrieves up to limit rows with a key greater than afterKey, ordered by key
+	ReadBatch(ctx context.Context, rule TableRule, afterKey int64, limit int) ([]*Row, error)
+
+	// WriteBatch stores anonymized rows together with the table's progress, in one transaction
+	WriteBatch(ctx context.Context, rule TableRule, rows []*Row, progress *TableProgress) error
+
+	// ResetProgress forgets the progress of the given tables
+	ResetProgress(ctx context.Context, tables []string) error
+}
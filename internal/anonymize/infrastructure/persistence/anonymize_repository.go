@@ -0,0 +1,160 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/anonymize/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresAnonymizeRepository implements the anonymization Repository using PostgreSQL
+type PostgresAnonymizeRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAnonymizeRepository creates a new PostgresAnonymizeRepository
+func NewPostgresAnonymizeRepository(db *database.DB) *PostgresAnonymizeRepository {
+	return &PostgresAnonymizeRepository{db: db}
+}
+
+// Columns lists the columns of a table in the current schema
+func (r *PostgresAnonymizeRepository) Columns(ctx context.Context, table string) (map[string]bool, error) {
+	query := `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1`
+	rows, err := r.db.Query(ctx, query, table)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list table columns")
+	}
+	defer rows.Close()
+
+	var columns map[string]bool
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan table column")
+		}
+		if columns == nil {
+			columns = make(map[string]bool)
+		}
+		columns[column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate table columns")
+	}
+	return columns, nil
+}
+
+// FindProgress retrieves the progress of a table
+func (r *PostgresAnonymizeRepository) FindProgress(ctx context.Context, table string) (*domain.TableProgress, error) {
+	query := `
+		SELECT table_name, last_key, rows_anonymized, started_at, completed_at, updated_at
+		FROM anonymization_progress
+		WHERE table_name = $1`
+	p := &domain.TableProgress{}
+	err := r.db.QueryRow(ctx, query, table).Scan(&p.Table, &p.LastKey, &p.Rows, &p.StartedAt, &p.CompletedAt, &p.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find anonymization progress")
+	}
+	return p, nil
+}
+
+// ReadBatch retrieves rows after afterKey with their anonymized columns as text
+func (r *PostgresAnonymizeRepository) ReadBatch(ctx context.Context, rule domain.TableRule, afterKey int64, limit int) ([]*domain.Row, error) {
+	key := pgx.Identifier{rule.KeyColumn}.Sanitize()
+	selected := make([]string, len(rule.Columns))
+	for i, column := range rule.Columns {
+		selected[i] = pgx.Identifier{column.Column}.Sanitize() + "::text"
+	}
+	query := fmt.Sprintf(`SELECT %s, %s FROM %s WHERE %s > $1 ORDER BY %s LIMIT $2`,
+		key, strings.Join(selected, ", "), pgx.Identifier{rule.Table}.Sanitize(), key, key)
+
+	rows, err := r.db.Query(ctx, query, afterKey, limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, fmt.Sprintf("failed to read %s", rule.Table))
+	}
+	defer rows.Close()
+
+	batch := make([]*domain.Row, 0, limit)
+	for rows.Next() {
+		row := &domain.Row{Values: make([]*string, len(rule.Columns))}
+		dest := make([]interface{}, 0, len(rule.Columns)+1)
+		dest = append(dest, &row.Key)
+		for i := range row.Values {
+			dest = append(dest, &row.Values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.InternalWrap(err, fmt.Sprintf("failed to scan %s", rule.Table))
+		}
+		batch = append(batch, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, fmt.Sprintf("failed to iterate %s", rule.Table))
+	}
+	return batch, nil
+}
+
+// WriteBatch updates the rows from arrays unnested side by side, one per
+// column, and stores the progress in the same transaction
+func (r *PostgresAnonymizeRepository) WriteBatch(ctx context.Context, rule domain.TableRule, rows []*domain.Row, progress *domain.TableProgress) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if len(rows) > 0 {
+			keys := make([]int64, len(rows))
+			args := []interface{}{keys}
+			for i, row := range rows {
+				keys[i] = row.Key
+			}
+
+			unnested := []string{"unnest($1::bigint[]) AS k"}
+			assignments := make([]string, len(rule.Columns))
+			for c, column := range rule.Columns {
+				values := make([]*string, len(rows))
+				for i, row := range rows {
+					values[i] = row.Values[c]
+				}
+				args = append(args, values)
+
+				alias := fmt.Sprintf("c%d", c)
+				unnested = append(unnested, fmt.Sprintf("unnest($%d::text[]) AS %s", c+2, alias))
+				assignments[c] = fmt.Sprintf("%s = v.%s", pgx.Identifier{column.Column}.Sanitize(), alias)
+			}
+
+			query := fmt.Sprintf(`UPDATE %s AS t SET %s FROM (SELECT %s) AS v WHERE t.%s = v.k`,
+				pgx.Identifier{rule.Table}.Sanitize(), strings.Join(assignments, ", "),
+				strings.Join(unnested, ", "), pgx.Identifier{rule.KeyColumn}.Sanitize())
+			if _, err := tx.Exec(ctx, query, args...); err != nil {
+				return errors.InternalWrap(err, fmt.Sprintf("failed to anonymize %s", rule.Table))
+			}
+		}
+
+		query := `
+			INSERT INTO anonymization_progress (table_name, last_key, rows_anonymized, started_at, completed_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (table_name) DO UPDATE SET
+				last_key = EXCLUDED.last_key,
+				rows_anonymized = EXCLUDED.rows_anonymized,
+				completed_at = EXCLUDED.completed_at,
+				updated_at = EXCLUDED.updated_at`
+		if _, err := tx.Exec(ctx, query, progress.Table, progress.LastKey, progress.Rows,
+			progress.StartedAt, progress.CompletedAt, progress.UpdatedAt); err != nil {
+			return errors.InternalWrap(err, "failed to save anonymization progress")
+		}
+		return nil
+	})
+}
+
+// ResetProgress forgets the progress of the given tables
+func (r *PostgresAnonymizeRepository) ResetProgress(ctx context.Context, tables []string) error {
+	if err := r.db.Exec(ctx, `DELETE FROM anonymization_progress WHERE table_name = ANY($1)`, tables); err != nil {
+		return errors.InternalWrap(err, "failed to reset anonymization progress")
+	}
+	return nil
+}
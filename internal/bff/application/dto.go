@@ -0,0 +1,167 @@
+package application
+
+import (
+	"time"
+
+	catalogApp "github.com/qhato/ecommerce/internal/catalog/application"
+	offerApp "github.com/qhato/ecommerce/internal/offer/application"
+	orderApp "github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/money"
+)
+
+// Sections of the composed pages, as named in SectionErrorDTO
+const (
+	SectionNavigation        = "navigation"
+	SectionBestSellers       = "best_sellers"
+	SectionPromotions        = "promotions"
+	SectionSKUs              = "skus"
+	SectionAvailability      = "availability"
+	SectionBreadcrumbs       = "breadcrumbs"
+	SectionPromotionMessages = "promotion_messages"
+)
+
+// SectionErrorDTO reports a section left out of a composed page because its source failed or was too slow
+type SectionErrorDTO struct {
+	Section string `json:"section"`
+	Message string `json:"message"`
+}
+
+// HomePageDTO is the home page: navigation, best sellers and advertised promotions
+type HomePageDTO struct {
+	Navigation  []*catalogApp.NavigationNodeDTO `json:"navigation"`
+	BestSellers []*ProductCardDTO               `json:"best_sellers"`
+	Promotions  []*PromotionDTO                 `json:"promotions"`
+	Partial     bool                            `json:"partial"` // Some sections are missing, see Errors
+	Errors      []*SectionErrorDTO              `json:"errors,omitempty"`
+}
+
+// ProductPageDTO is a product detail page: the product with its SKUs, their
+// availability, the category breadcrumbs and advertised promotions
+type ProductPageDTO struct {
+	Product      *catalogApp.ProductDTO    `json:"product"`
+	SKUs         []*catalogApp.SkuDTO      `json:"skus"`
+	Availability *ProductAvailabilityDTO   `json:"availability,omitempty"`
+	Breadcrumbs  []*catalogApp.CategoryDTO `json:"breadcrumbs"`
+	Promotions   []*PromotionDTO           `json:"promotions"`
+	Partial      bool                      `json:"partial"`
+	Errors       []*SectionErrorDTO        `json:"errors,omitempty"`
+}
+
+// CartSummaryDTO is the cart shown in the mini cart and on the cart page. A
+// customer without a cart gets an empty summary.
+type CartSummaryDTO struct {
+	CartID            int64                           `json:"cart_id,omitempty"`
+	ItemCount         int                             `json:"item_count"` // Units across all lines
+	Subtotal          float64                         `json:"subtotal"`
+	Discounts         float64                         `json:"discounts"` // Order-level offers, negative
+	Total             float64                         `json:"total"`
+	CurrencyCode      string                          `json:"currency_code,omitempty"`
+	Display           *orderApp.OrderTotalsDisplayDTO `json:"display,omitempty"`
+	Lines             []*CartSummaryLineDTO           `json:"lines"`
+	PromotionMessages []*offerApp.QualificationGapDTO `json:"promotion_messages"`
+	Partial           bool                            `json:"partial"`
+	Errors            []*SectionErrorDTO              `json:"errors,omitempty"`
+}
+
+// CartSummaryLineDTO is a line of a cart summary
+type CartSummaryLineDTO struct {
+	ItemID     int64   `json:"item_id"`
+	SKUID      int64   `json:"sku_id"`
+	ProductID  int64   `json:"product_id,omitempty"`
+	Name       string  `json:"name"`
+	Quantity   int     `json:"quantity"`
+	Price      float64 `json:"price"`
+	TotalPrice float64 `json:"total_price"`
+}
+
+// ProductCardDTO is a product as shown in listings, priced from its default SKU
+type ProductCardDTO struct {
+	ID             int64                 `json:"id"`
+	URL            string                `json:"url"`
+	Name           string                `json:"name"`
+	RetailPrice    float64               `json:"retail_price"`
+	SalePrice      float64               `json:"sale_price,omitempty"`
+	EffectivePrice float64               `json:"effective_price"`
+	DisplayPrice   string                `json:"display_price"`
+	CurrencyCode   string                `json:"currency_code"`
+	InStock        bool                  `json:"in_stock"`
+	Badges         []catalogApp.BadgeDTO `json:"badges,omitempty"`
+}
+
+// PromotionDTO is an automatic offer advertised to every visitor
+type PromotionDTO struct {
+	OfferID          int64      `json:"offer_id"`
+	Name             string     `json:"name"`
+	MarketingMessage string     `json:"marketing_message"`
+	EndDate          *time.Time `json:"end_date,omitempty"`
+}
+
+// ProductAvailabilityDTO is the available-to-promise stock of a product's SKUs
+type ProductAvailabilityDTO struct {
+	InStock bool                  `json:"in_stock"`
+	SKUs    []*SKUAvailabilityDTO `json:"skus"`
+}
+
+// SKUAvailabilityDTO is the available-to-promise quantity of a SKU
+type SKUAvailabilityDTO struct {
+	SKUID          int64 `json:"sku_id"`
+	Available      int   `json:"available"`
+	Inbound        int   `json:"inbound"`
+	AllowBackorder bool  `json:"allow_backorder"`
+	InStock        bool  `json:"in_stock"`
+}
+
+// toProductCardDTO prices a product from its default SKU; sku may be nil
+func toProductCardDTO(product *catalogApp.ProductDTO, sku *catalogApp.SkuDTO) *ProductCardDTO {
+	card := &ProductCardDTO{
+		ID:      product.ID,
+		URL:     product.URL,
+		InStock: product.InStock,
+		Badges:  product.Badges,
+	}
+	if sku != nil {
+		card.Name = sku.Name
+		card.RetailPrice = sku.RetailPrice
+		card.SalePrice = sku.SalePrice
+		card.EffectivePrice = sku.EffectivePrice
+		card.DisplayPrice = sku.DisplayPrice
+		card.CurrencyCode = sku.CurrencyCode
+	}
+	return card
+}
+
+// toCartSummaryDTO summarizes a cart; child items such as gift wraps are listed after their parent
+func toCartSummaryDTO(cart *orderApp.OrderDTO) *CartSummaryDTO {
+	summary := &CartSummaryDTO{
+		CartID:            cart.ID,
+		Subtotal:          cart.OrderSubtotal,
+		Total:             cart.OrderTotal,
+		CurrencyCode:      cart.CurrencyCode,
+		Display:           cart.Display,
+		Lines:             []*CartSummaryLineDTO{},
+		PromotionMessages: []*offerApp.QualificationGapDTO{},
+	}
+	for _, adjustment := range cart.OrderAdjustments {
+		summary.Discounts += adjustment.AdjustmentValue
+	}
+	summary.Discounts = money.Round(summary.Discounts, cart.CurrencyCode)
+
+	var addLines func(items []*orderApp.OrderItemDTO)
+	addLines = func(items []*orderApp.OrderItemDTO) {
+		for _, item := range items {
+			summary.ItemCount += item.Quantity
+			summary.Lines = append(summary.Lines, &CartSummaryLineDTO{
+				ItemID:     item.ID,
+				SKUID:      item.SKUID,
+				ProductID:  item.ProductID,
+				Name:       item.Name,
+				Quantity:   item.Quantity,
+				Price:      item.Price,
+				TotalPrice: item.TotalPrice,
+			})
+			addLines(item.ChildItems)
+		}
+	}
+	addLines(cart.Items)
+	return summary
+}
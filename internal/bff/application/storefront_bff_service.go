@@ -0,0 +1,413 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	catalogApp "github.com/qhato/ecommerce/internal/catalog/application"
+	catalogQueries "github.com/qhato/ecommerce/internal/catalog/application/queries"
+	catalogDomain "github.com/qhato/ecommerce/internal/catalog/domain"
+	inventoryApp "github.com/qhato/ecommerce/internal/inventory/application"
+	offerApp "github.com/qhato/ecommerce/internal/offer/application"
+	orderApp "github.com/qhato/ecommerce/internal/order/application"
+	orderDomain "github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// StorefrontBFFService composes storefront pages from the catalog, inventory,
+// offer and order contexts in one call. Sections are fetched in parallel; a
+// section whose source fails or exceeds the section timeout is left out and
+// reported in the page's errors instead of failing the page.
+type StorefrontBFFService interface {
+	// GetHomePage returns the navigation, best sellers and advertised promotions.
+	GetHomePage(ctx context.Context) (*HomePageDTO, error)
+
+	// GetProductPage returns a published product by URL with its SKUs, availability,
+	// breadcrumbs and advertised promotions.
+	GetProductPage(ctx context.Context, url string) (*ProductPageDTO, error)
+
+	// GetCartSummary returns a customer's cart with its upsell messages. Without a
+	// cart ID the customer's most recent cart is used.
+	GetCartSummary(ctx context.Context, customerID int64, cartID *int64) (*CartSummaryDTO, error)
+}
+
+// BFFSources are the services the composed pages read from
+type BFFSources struct {
+	Products   *catalogQueries.ProductQueryHandler
+	Categories *catalogQueries.CategoryQueryHandler
+	SKUs       *catalogQueries.SKUQueryHandler
+	Popularity catalogDomain.CatalogPopularityRepository
+	ATP        inventoryApp.ATPService
+	Offers     offerApp.OfferService
+	Promotions offerApp.PromotionMessageService
+	Orders     orderApp.OrderService
+}
+
+// BFFConfig sizes the composed pages
+type BFFConfig struct {
+	SectionTimeout time.Duration // Sections slower than this are left out; 0 waits for every section
+	BestSellers    int           // Products in the home page best sellers
+	BestSellerDays int           // Order history ranking the best sellers
+	Promotions     int           // Promotions advertised per page; 0 advertises all of them
+}
+
+type storefrontBFFService struct {
+	sources BFFSources
+	cfg     BFFConfig
+	log     *logger.Logger
+}
+
+// NewStorefrontBFFService creates a new instance of StorefrontBFFService.
+func NewStorefrontBFFService(sources BFFSources, cfg BFFConfig, log *logger.Logger) StorefrontBFFService {
+	if cfg.BestSellers <= 0 {
+		cfg.BestSellers = 8
+	}
+	if cfg.BestSellerDays <= 0 {
+		cfg.BestSellerDays = 30
+	}
+	return &storefrontBFFService{sources: sources, cfg: cfg, log: log}
+}
+
+func (s *storefrontBFFService) GetHomePage(ctx context.Context) (*HomePageDTO, error) {
+	page := &HomePageDTO{
+		Navigation:  []*catalogApp.NavigationNodeDTO{},
+		BestSellers: []*ProductCardDTO{},
+		Promotions:  []*PromotionDTO{},
+	}
+
+	sections := s.newSections(ctx)
+	runSection(sections, SectionNavigation, s.sources.Categories.HandleGetNavigationTree, func(tree []*catalogApp.NavigationNodeDTO) {
+		page.Navigation = tree
+	})
+	runSection(sections, SectionBestSellers, s.bestSellers, func(cards []*ProductCardDTO) {
+		page.BestSellers = cards
+	})
+	runSection(sections, SectionPromotions, s.promotions, func(promotions []*PromotionDTO) {
+		page.Promotions = promotions
+	})
+
+	page.Errors = sections.wait()
+	page.Partial = len(page.Errors) > 0
+	return page, nil
+}
+
+func (s *storefrontBFFService) GetProductPage(ctx context.Context, url string) (*ProductPageDTO, error) {
+	// The product itself is the page; without it there is nothing to compose
+	product, err := s.sources.Products.HandleGetProductByURL(ctx, &catalogQueries.GetProductByURLQuery{URL: url})
+	if err != nil {
+		return nil, err
+	}
+	if !storefrontVisible(product) {
+		return nil, errors.NotFound("product")
+	}
+
+	page := &ProductPageDTO{
+		Product:     product,
+		SKUs:        []*catalogApp.SkuDTO{},
+		Breadcrumbs: []*catalogApp.CategoryDTO{},
+		Promotions:  []*PromotionDTO{},
+	}
+
+	sections := s.newSections(ctx)
+	runSection(sections, SectionSKUs, func(ctx context.Context) (*skuSection, error) {
+		skus, err := s.sources.SKUs.HandleListSKUsByProduct(ctx, &catalogQueries.ListSKUsByProductQuery{ProductID: product.ID})
+		if err != nil {
+			return nil, err
+		}
+		// Availability needs the SKUs but fails on its own
+		availability, err := s.availability(ctx, skus)
+		return &skuSection{skus: skus, availability: availability, availabilityErr: err}, nil
+	}, func(section *skuSection) {
+		page.SKUs = section.skus
+		if section.availabilityErr != nil {
+			sections.fail(SectionAvailability, section.availabilityErr)
+			return
+		}
+		page.Availability = section.availability
+	})
+	if product.DefaultCategoryID != nil {
+		runSection(sections, SectionBreadcrumbs, func(ctx context.Context) ([]*catalogApp.CategoryDTO, error) {
+			return s.sources.Categories.HandleGetCategoryPath(ctx, &catalogQueries.GetCategoryPathQuery{CategoryID: *product.DefaultCategoryID})
+		}, func(path []*catalogApp.CategoryDTO) {
+			page.Breadcrumbs = path
+		})
+	}
+	runSection(sections, SectionPromotions, s.promotions, func(promotions []*PromotionDTO) {
+		page.Promotions = promotions
+	})
+
+	page.Errors = sections.wait()
+	page.Partial = len(page.Errors) > 0
+	return page, nil
+}
+
+func (s *storefrontBFFService) GetCartSummary(ctx context.Context, customerID int64, cartID *int64) (*CartSummaryDTO, error) {
+	cart, err := s.findCart(ctx, customerID, cartID)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return &CartSummaryDTO{Lines: []*CartSummaryLineDTO{}, PromotionMessages: []*offerApp.QualificationGapDTO{}}, nil
+	}
+
+	summary := toCartSummaryDTO(cart)
+	sections := s.newSections(ctx)
+	runSection(sections, SectionPromotionMessages, func(ctx context.Context) ([]*offerApp.QualificationGapDTO, error) {
+		return s.sources.Promotions.GetQualificationGaps(ctx, orderApp.ToCartSnapshot(cart))
+	}, func(messages []*offerApp.QualificationGapDTO) {
+		summary.PromotionMessages = messages
+	})
+
+	summary.Errors = sections.wait()
+	summary.Partial = len(summary.Errors) > 0
+	return summary, nil
+}
+
+// findCart returns the customer's cart by ID, or their most recent one; nil when they have none
+func (s *storefrontBFFService) findCart(ctx context.Context, customerID int64, cartID *int64) (*orderApp.OrderDTO, error) {
+	if cartID == nil {
+		pending := orderDomain.OrderStatusPending
+		orders, _, err := s.sources.Orders.ListOrders(ctx, &orderDomain.OrderFilter{
+			Page:       1,
+			PageSize:   1,
+			CustomerID: &customerID,
+			Status:     &pending,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(orders) == 0 {
+			return nil, nil
+		}
+		cartID = &orders[0].ID
+	}
+
+	cart, err := s.sources.Orders.HandleGetOrderByID(ctx, *cartID)
+	if err != nil {
+		return nil, err
+	}
+	// Another customer's cart is reported as missing rather than forbidden
+	if cart.CustomerID != customerID {
+		return nil, errors.NotFound(fmt.Sprintf("cart %d", *cartID))
+	}
+	if cart.SubmitDate != nil {
+		return nil, errors.BadRequest(fmt.Sprintf("order %d is not a cart", *cartID))
+	}
+	return cart, nil
+}
+
+// bestSellers returns the best-selling published products as cards, priced in parallel
+func (s *storefrontBFFService) bestSellers(ctx context.Context) ([]*ProductCardDTO, error) {
+	since := time.Now().AddDate(0, 0, -s.cfg.BestSellerDays)
+	// Ask for extra products as some may no longer be published
+	ids, err := s.sources.Popularity.TopProductIDs(ctx, since, s.cfg.BestSellers*2)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*ProductCardDTO, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id int64) {
+			defer wg.Done()
+			card, err := s.productCard(ctx, id)
+			if err != nil {
+				s.log.WithError(err).WithField("product_id", id).Warn("Failed to load best seller")
+				return
+			}
+			cards[i] = card
+		}(i, id)
+	}
+	wg.Wait()
+
+	result := make([]*ProductCardDTO, 0, s.cfg.BestSellers)
+	for _, card := range cards {
+		if card != nil && len(result) < s.cfg.BestSellers {
+			result = append(result, card)
+		}
+	}
+	return result, nil
+}
+
+// productCard returns a published product priced from its default SKU; nil when not published
+func (s *storefrontBFFService) productCard(ctx context.Context, id int64) (*ProductCardDTO, error) {
+	product, err := s.sources.Products.HandleGetProductByID(ctx, &catalogQueries.GetProductByIDQuery{ID: id})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !storefrontVisible(product) {
+		return nil, nil
+	}
+
+	var sku *catalogApp.SkuDTO
+	if product.DefaultSKUID != nil {
+		sku, err = s.sources.SKUs.HandleGetSKUByID(ctx, &catalogQueries.GetSKUByIDQuery{ID: *product.DefaultSKUID})
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return toProductCardDTO(product, sku), nil
+}
+
+// promotions returns the automatic offers with a marketing message that are open to every visitor
+func (s *storefrontBFFService) promotions(ctx context.Context) ([]*PromotionDTO, error) {
+	offers, err := s.sources.Offers.GetActiveOffers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions := make([]*PromotionDTO, 0)
+	for _, offer := range offers {
+		if !offer.AutomaticallyAdded || offer.MarketingMessage == "" || targeted(offer) {
+			continue
+		}
+		promotions = append(promotions, &PromotionDTO{
+			OfferID:          offer.ID,
+			Name:             offer.Name,
+			MarketingMessage: offer.MarketingMessage,
+			EndDate:          offer.EndDate,
+		})
+		if s.cfg.Promotions > 0 && len(promotions) == s.cfg.Promotions {
+			break
+		}
+	}
+	return promotions, nil
+}
+
+// availability returns the available-to-promise stock of the SKUs that can be bought
+func (s *storefrontBFFService) availability(ctx context.Context, skus []*catalogApp.SkuDTO) (*ProductAvailabilityDTO, error) {
+	availability := &ProductAvailabilityDTO{SKUs: []*SKUAvailabilityDTO{}}
+	var buyable []*catalogApp.SkuDTO
+	skuIDs := make([]string, 0, len(skus))
+	for _, sku := range skus {
+		if sku.Available && sku.IsActive {
+			buyable = append(buyable, sku)
+			skuIDs = append(skuIDs, strconv.FormatInt(sku.ID, 10))
+		}
+	}
+	if len(skuIDs) == 0 {
+		return availability, nil
+	}
+
+	atps, err := s.sources.ATP.GetAvailableToPromise(ctx, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, sku := range buyable {
+		atp := atps[i]
+		view := &SKUAvailabilityDTO{
+			SKUID:          sku.ID,
+			Available:      atp.Available,
+			Inbound:        atp.Inbound,
+			AllowBackorder: atp.AllowBackorder,
+			InStock:        sku.InventoryType == "ALWAYS_AVAILABLE" || atp.AllowBackorder || atp.Available > 0,
+		}
+		availability.InStock = availability.InStock || view.InStock
+		availability.SKUs = append(availability.SKUs, view)
+	}
+	return availability, nil
+}
+
+// storefrontVisible reports whether shoppers may see a product: not archived, active and not a draft
+func storefrontVisible(product *catalogApp.ProductDTO) bool {
+	return !product.Archived && product.IsActive && product.PublishStatus != string(catalogDomain.ProductStatusDraft)
+}
+
+// targeted reports whether an offer is limited to some customers, so it is not advertised to everyone
+func targeted(offer *offerApp.OfferDTO) bool {
+	return len(offer.CustomerTags) > 0 || len(offer.CustomerSegments) > 0 || offer.FirstOrderOnly ||
+		offer.MinLifetimeSpend != nil || offer.MinAccountAgeDays != nil
+}
+
+// sections runs the sections of a page in parallel and collects the ones that failed
+type sections struct {
+	ctx     context.Context
+	timeout time.Duration
+	log     *logger.Logger
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	errors  []*SectionErrorDTO
+}
+
+func (s *storefrontBFFService) newSections(ctx context.Context) *sections {
+	return &sections{ctx: ctx, timeout: s.cfg.SectionTimeout, log: s.log}
+}
+
+// skuSection is the SKUs of a product page with their availability
+type skuSection struct {
+	skus            []*catalogApp.SkuDTO
+	availability    *ProductAvailabilityDTO
+	availabilityErr error
+}
+
+// runSection fetches a section in the background and hands it to apply once
+// fetched in time. A section that times out is abandoned: its fetch may still
+// be running but its result is dropped, so apply is the only place writing to
+// the page.
+func runSection[T any](g *sections, name string, fetch func(ctx context.Context) (T, error), apply func(T)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		ctx := g.ctx
+		if g.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, g.timeout)
+			defer cancel()
+		}
+
+		type result struct {
+			value T
+			err   error
+		}
+		done := make(chan result, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					done <- result{err: fmt.Errorf("panic: %v", r)}
+				}
+			}()
+			value, err := fetch(ctx)
+			done <- result{value: value, err: err}
+		}()
+
+		select {
+		case res := <-done:
+			if res.err != nil {
+				g.fail(name, res.err)
+				return
+			}
+			apply(res.value)
+		case <-ctx.Done():
+			g.fail(name, ctx.Err())
+		}
+	}()
+}
+
+// fail records a section left out of the page
+func (g *sections) fail(name string, err error) {
+	g.log.WithError(err).WithField("section", name).Warn("Storefront page section unavailable")
+	message := "unavailable"
+	if err == context.DeadlineExceeded {
+		message = "timed out"
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errors = append(g.errors, &SectionErrorDTO{Section: name, Message: message})
+}
+
+// wait returns once every section finished or timed out, with the sections that failed
+func (g *sections) wait() []*SectionErrorDTO {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.errors
+}
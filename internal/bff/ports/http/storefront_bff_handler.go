@@ -0,0 +1,106 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/bff/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// StorefrontBFFHandler serves composed storefront pages, saving clients a round trip per section
+type StorefrontBFFHandler struct {
+	bffService application.StorefrontBFFService
+	logger     *logger.Logger
+}
+
+// NewStorefrontBFFHandler creates a new storefront BFF handler
+func NewStorefrontBFFHandler(bffService application.StorefrontBFFService, logger *logger.Logger) *StorefrontBFFHandler {
+	return &StorefrontBFFHandler{
+		bffService: bffService,
+		logger:     logger,
+	}
+}
+
+// RegisterRoutes registers the composed page routes
+func (h *StorefrontBFFHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/bff", func(r chi.Router) {
+		r.Get("/home", h.GetHomePage)
+		r.Get("/product/{url}", h.GetProductPage)
+		r.Get("/cart-summary", h.GetCartSummary)
+	})
+}
+
+// GetHomePage returns the navigation, best sellers and advertised promotions.
+// Sections that failed are listed in errors and the page is marked partial.
+func (h *StorefrontBFFHandler) GetHomePage(w http.ResponseWriter, r *http.Request) {
+	page, err := h.bffService.GetHomePage(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to compose home page")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, page)
+}
+
+// GetProductPage returns a product by URL with its SKUs, availability, breadcrumbs and promotions
+func (h *StorefrontBFFHandler) GetProductPage(w http.ResponseWriter, r *http.Request) {
+	url := chi.URLParam(r, "url")
+	if url == "" {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("URL is required"))
+		return
+	}
+
+	page, err := h.bffService.GetProductPage(r.Context(), url)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			h.logger.WithError(err).WithField("url", url).Error("failed to compose product page")
+		}
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, page)
+}
+
+// GetCartSummary returns the signed-in customer's cart with its upsell messages.
+// ?cart_id= selects a cart; otherwise the most recent one is used.
+func (h *StorefrontBFFHandler) GetCartSummary(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		pkghttp.RespondError(w, errors.Unauthorized("authentication required"))
+		return
+	}
+	customerID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, errors.Unauthorized("invalid customer").WithInternal(err))
+		return
+	}
+
+	var cartID *int64
+	if value := r.URL.Query().Get("cart_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			pkghttp.RespondError(w, pkghttp.NewValidationError("invalid cart ID"))
+			return
+		}
+		cartID = &id
+	}
+
+	summary, err := h.bffService.GetCartSummary(r.Context(), customerID, cartID)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			h.logger.WithError(err).WithField("customer_id", customerID).Error("failed to compose cart summary")
+		}
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	pkghttp.RespondJSON(w, http.StatusOK, summary)
+}
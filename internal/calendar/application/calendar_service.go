@@ -0,0 +1,430 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/calendar/domain"
+	"github.com/qhato/ecommerce/pkg/calendar"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// CalendarService manages the holiday sets and the business calendars of
+// sites and warehouses, and resolves the calendar in force for one of them
+// for ship-by deadlines and delivery estimates.
+type CalendarService interface {
+	calendar.Resolver
+
+	// ListHolidaySets lists the holiday sets by name.
+	ListHolidaySets(ctx context.Context) ([]*HolidaySetDTO, error)
+
+	// GetHolidaySet retrieves a holiday set with its holidays from a date (YYYY-MM-DD) on;
+	// an empty date starts at the beginning of the current year.
+	GetHolidaySet(ctx context.Context, id int64, from string) (*HolidaySetDTO, error)
+
+	// CreateHolidaySet creates an empty holiday set.
+	CreateHolidaySet(ctx context.Context, req *SaveHolidaySetRequest) (*HolidaySetDTO, error)
+
+	// UpdateHolidaySet renames a holiday set.
+	UpdateHolidaySet(ctx context.Context, id int64, req *SaveHolidaySetRequest) (*HolidaySetDTO, error)
+
+	// DeleteHolidaySet removes a holiday set; calendars stop observing it.
+	DeleteHolidaySet(ctx context.Context, id int64) error
+
+	// SaveHoliday adds or renames a holiday of a set by date (YYYY-MM-DD).
+	SaveHoliday(ctx context.Context, setID int64, date string, req *SaveHolidayRequest) (*HolidayDTO, error)
+
+	// DeleteHoliday removes a holiday of a set by date (YYYY-MM-DD).
+	DeleteHoliday(ctx context.Context, setID int64, date string) error
+
+	// ImportICal adds the holidays of an iCalendar feed to a set, replacing
+	// its holidays when replace is set.
+	ImportICal(ctx context.Context, setID int64, feed io.Reader, replace bool) (*ICalImportResultDTO, error)
+
+	// ListCalendars lists the calendars of the default scope, sites and warehouses.
+	ListCalendars(ctx context.Context) ([]*BusinessCalendarDTO, error)
+
+	// SaveCalendar sets the calendar of a scope.
+	SaveCalendar(ctx context.Context, scope domain.ScopeType, scopeID string, req *SaveBusinessCalendarRequest, updatedBy string) (*BusinessCalendarDTO, error)
+
+	// DeleteCalendar removes the calendar of a scope, which inherits again.
+	DeleteCalendar(ctx context.Context, scope domain.ScopeType, scopeID string) error
+
+	// Preview resolves the calendar of a site or warehouse and works out the
+	// deadline of work started at from and due within days business days.
+	Preview(ctx context.Context, siteID, warehouseID string, from time.Time, days int) (*ResolvedCalendarDTO, error)
+}
+
+// CalendarConfig holds the default working week, used where no stored
+// calendar sets one, and the caching and import of calendars
+type CalendarConfig struct {
+	Location    *time.Location
+	Workdays    []time.Weekday
+	CutoffHour  int           // Work started at or after this hour counts from the next business day; 0 has no cutoff
+	CacheTTL    time.Duration // How long resolved calendars are reused; other processes see edits after it
+	ImportYears int           // How many years ahead yearly recurring iCal events are expanded
+}
+
+// holidayLookback is how far back holidays are loaded into resolved
+// calendars, for the deadlines of work started in the past
+const holidayLookback = 366 * 24 * time.Hour
+
+// maxImportHolidays caps the holidays read from one iCalendar feed
+const maxImportHolidays = 5000
+
+// maxPreviewDays caps the business days a preview counts
+const maxPreviewDays = 365
+
+type cachedCalendar struct {
+	calendar  *calendar.Calendar
+	expiresAt time.Time
+}
+
+type calendarService struct {
+	repo domain.CalendarRepository
+	cfg  CalendarConfig
+	log  *logger.Logger
+
+	cacheMu sync.Mutex
+	cache   map[string]cachedCalendar
+}
+
+// NewCalendarService creates a new instance of CalendarService
+func NewCalendarService(repo domain.CalendarRepository, cfg CalendarConfig, log *logger.Logger) CalendarService {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if len(cfg.Workdays) == 0 {
+		cfg.Workdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+	if cfg.ImportYears <= 0 {
+		cfg.ImportYears = 5
+	}
+	return &calendarService{
+		repo:  repo,
+		cfg:   cfg,
+		log:   log,
+		cache: make(map[string]cachedCalendar),
+	}
+}
+
+func (s *calendarService) ListHolidaySets(ctx context.Context) ([]*HolidaySetDTO, error) {
+	sets, err := s.repo.FindHolidaySets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*HolidaySetDTO, len(sets))
+	for i, set := range sets {
+		dtos[i] = ToHolidaySetDTO(set)
+	}
+	return dtos, nil
+}
+
+func (s *calendarService) GetHolidaySet(ctx context.Context, id int64, from string) (*HolidaySetDTO, error) {
+	start := time.Date(time.Now().Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	if from != "" {
+		day, err := parseDate(from)
+		if err != nil {
+			return nil, err
+		}
+		start = day
+	}
+
+	set, err := s.repo.FindHolidaySet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.repo.FindHolidays(ctx, []int64{id}, start)
+	if err != nil {
+		return nil, err
+	}
+	dto := ToHolidaySetDTO(set)
+	dto.Holidays = ToHolidayDTOs(holidays)
+	return dto, nil
+}
+
+func (s *calendarService) CreateHolidaySet(ctx context.Context, req *SaveHolidaySetRequest) (*HolidaySetDTO, error) {
+	set, err := domain.NewHolidaySet(req.Name, req.Description)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.SaveHolidaySet(ctx, set); err != nil {
+		return nil, err
+	}
+	return ToHolidaySetDTO(set), nil
+}
+
+func (s *calendarService) UpdateHolidaySet(ctx context.Context, id int64, req *SaveHolidaySetRequest) (*HolidaySetDTO, error) {
+	set, err := s.repo.FindHolidaySet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := set.Update(req.Name, req.Description); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.SaveHolidaySet(ctx, set); err != nil {
+		return nil, err
+	}
+	return ToHolidaySetDTO(set), nil
+}
+
+func (s *calendarService) DeleteHolidaySet(ctx context.Context, id int64) error {
+	if err := s.repo.DeleteHolidaySet(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *calendarService) SaveHoliday(ctx context.Context, setID int64, date string, req *SaveHolidayRequest) (*HolidayDTO, error) {
+	day, err := parseDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindHolidaySet(ctx, setID); err != nil {
+		return nil, err
+	}
+	holiday := &domain.Holiday{SetID: setID, Date: day, Name: strings.TrimSpace(req.Name)}
+	if err := s.repo.SaveHolidays(ctx, setID, []*domain.Holiday{holiday}, false); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return &HolidayDTO{Date: day.Format(calendar.DateLayout), Name: holiday.Name}, nil
+}
+
+func (s *calendarService) DeleteHoliday(ctx context.Context, setID int64, date string) error {
+	day, err := parseDate(date)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteHoliday(ctx, setID, day); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *calendarService) ImportICal(ctx context.Context, setID int64, feed io.Reader, replace bool) (*ICalImportResultDTO, error) {
+	set, err := s.repo.FindHolidaySet(ctx, setID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := from.AddDate(s.cfg.ImportYears, 0, -1)
+	parsed, err := calendar.ParseICal(feed, from, until)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if len(parsed.Holidays) > maxImportHolidays {
+		return nil, errors.ValidationError(fmt.Sprintf("the feed has %d holidays, at most %d can be imported at once", len(parsed.Holidays), maxImportHolidays))
+	}
+
+	// Dates repeated in the feed keep the name of their last event
+	byDate := make(map[string]*domain.Holiday, len(parsed.Holidays))
+	for _, h := range parsed.Holidays {
+		byDate[h.Date.Format(calendar.DateLayout)] = &domain.Holiday{SetID: setID, Date: h.Date, Name: h.Name}
+	}
+	holidays := make([]*domain.Holiday, 0, len(byDate))
+	for _, holiday := range byDate {
+		holidays = append(holidays, holiday)
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+
+	if err := s.repo.SaveHolidays(ctx, setID, holidays, replace); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.log.WithFields(logger.Fields{
+		"holiday_set_id": setID,
+		"holiday_set":    set.Name,
+		"imported":       len(holidays),
+		"skipped":        len(parsed.Skipped),
+		"replaced":       replace,
+	}).Info("Holidays imported from iCalendar feed")
+	return &ICalImportResultDTO{Imported: len(holidays), Replaced: replace, Skipped: parsed.Skipped}, nil
+}
+
+func (s *calendarService) ListCalendars(ctx context.Context) ([]*BusinessCalendarDTO, error) {
+	calendars, err := s.repo.FindCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*BusinessCalendarDTO, len(calendars))
+	for i, c := range calendars {
+		dtos[i] = ToBusinessCalendarDTO(c)
+	}
+	return dtos, nil
+}
+
+func (s *calendarService) SaveCalendar(ctx context.Context, scope domain.ScopeType, scopeID string, req *SaveBusinessCalendarRequest, updatedBy string) (*BusinessCalendarDTO, error) {
+	var workdays []time.Weekday
+	if req.Workdays != nil {
+		days, err := calendar.ParseWeekdays(req.Workdays)
+		if err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+		workdays = days
+	}
+	timeZone := req.TimeZone
+	if timeZone != nil && strings.TrimSpace(*timeZone) == "" {
+		timeZone = nil
+	}
+
+	c, err := domain.NewBusinessCalendar(scope, scopeID, timeZone, workdays, req.CutoffHour, req.HolidaySetIDs, updatedBy)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.SaveCalendar(ctx, c); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.log.WithFields(logger.Fields{
+		"scope":      string(c.ScopeType),
+		"scope_id":   c.ScopeID,
+		"updated_by": updatedBy,
+	}).Info("Business calendar updated")
+	return ToBusinessCalendarDTO(c), nil
+}
+
+func (s *calendarService) DeleteCalendar(ctx context.Context, scope domain.ScopeType, scopeID string) error {
+	if err := s.repo.DeleteCalendar(ctx, scope, scopeID); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *calendarService) Preview(ctx context.Context, siteID, warehouseID string, from time.Time, days int) (*ResolvedCalendarDTO, error) {
+	if days < 0 || days > maxPreviewDays {
+		return nil, errors.ValidationError(fmt.Sprintf("days must be between 0 and %d", maxPreviewDays))
+	}
+	c, err := s.Calendar(ctx, siteID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	workdays := make([]time.Weekday, 0, len(c.Workdays))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if c.Workdays[day] {
+			workdays = append(workdays, day)
+		}
+	}
+	fromDate := from.In(c.Location).Format(calendar.DateLayout)
+	holidays := make([]*HolidayDTO, 0)
+	for date, name := range c.Holidays {
+		if date >= fromDate {
+			holidays = append(holidays, &HolidayDTO{Date: date, Name: name})
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date < holidays[j].Date })
+
+	return &ResolvedCalendarDTO{
+		TimeZone:   c.Location.String(),
+		Workdays:   weekdayNames(workdays),
+		CutoffHour: c.CutoffHour,
+		Holidays:   holidays,
+		From:       from,
+		StartDay:   c.StartDay(from).Format(calendar.DateLayout),
+		Days:       days,
+		Deadline:   c.Deadline(from, days),
+	}, nil
+}
+
+// Calendar resolves the calendar of a warehouse or site: each field comes
+// from the warehouse's calendar, else the site's, else the default
+// calendar's, else the configuration. Resolved calendars are cached.
+func (s *calendarService) Calendar(ctx context.Context, siteID, warehouseID string) (*calendar.Calendar, error) {
+	key := strings.ToLower(siteID) + "|" + strings.ToLower(warehouseID)
+	now := time.Now()
+	s.cacheMu.Lock()
+	cached, ok := s.cache[key]
+	s.cacheMu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.calendar, nil
+	}
+
+	levels := make([]*domain.BusinessCalendar, 0, 3)
+	scopes := []struct {
+		scope domain.ScopeType
+		id    string
+	}{{domain.ScopeWarehouse, warehouseID}, {domain.ScopeSite, siteID}, {domain.ScopeDefault, ""}}
+	for _, sc := range scopes {
+		if sc.scope != domain.ScopeDefault && sc.id == "" {
+			continue
+		}
+		c, err := s.repo.FindCalendar(ctx, sc.scope, sc.id)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			levels = append(levels, c)
+		}
+	}
+
+	loc, workdays, cutoffHour := s.cfg.Location, s.cfg.Workdays, s.cfg.CutoffHour
+	var setIDs []int64
+	for i := len(levels) - 1; i >= 0; i-- {
+		level := levels[i]
+		if level.TimeZone != nil {
+			if l, err := time.LoadLocation(*level.TimeZone); err == nil {
+				loc = l
+			} else {
+				s.log.WithError(err).WithField("scope_id", level.ScopeID).Warn("Ignoring invalid business calendar time zone")
+			}
+		}
+		if level.Workdays != nil {
+			workdays = level.Workdays
+		}
+		if level.CutoffHour != nil {
+			cutoffHour = *level.CutoffHour
+		}
+		if len(level.HolidaySetIDs) > 0 {
+			setIDs = level.HolidaySetIDs
+		}
+	}
+
+	resolved := calendar.New(loc, workdays, cutoffHour)
+	if len(setIDs) > 0 {
+		holidays, err := s.repo.FindHolidays(ctx, setIDs, now.Add(-holidayLookback))
+		if err != nil {
+			return nil, err
+		}
+		for _, holiday := range holidays {
+			if _, ok := resolved.Holidays[holiday.Date.Format(calendar.DateLayout)]; !ok {
+				resolved.AddHoliday(holiday.Date, holiday.Name)
+			}
+		}
+	}
+
+	if s.cfg.CacheTTL > 0 {
+		s.cacheMu.Lock()
+		s.cache[key] = cachedCalendar{calendar: resolved, expiresAt: now.Add(s.cfg.CacheTTL)}
+		s.cacheMu.Unlock()
+	}
+	return resolved, nil
+}
+
+// invalidate drops the resolved calendars of this process after an edit
+func (s *calendarService) invalidate() {
+	s.cacheMu.Lock()
+	s.cache = make(map[string]cachedCalendar)
+	s.cacheMu.Unlock()
+}
+
+func parseDate(date string) (time.Time, error) {
+	day, err := time.Parse(calendar.DateLayout, strings.TrimSpace(date))
+	if err != nil {
+		return time.Time{}, errors.ValidationError("date must be YYYY-MM-DD")
+	}
+	return day, nil
+}
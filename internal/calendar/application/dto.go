@@ -0,0 +1,129 @@
+package application
+
+import (
+	"time"
+
+	"github.com/qhato/ecommerce/internal/calendar/domain"
+	"github.com/qhato/ecommerce/pkg/calendar"
+)
+
+// HolidaySetDTO represents a named set of holidays
+type HolidaySetDTO struct {
+	ID           int64         `json:"id"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	HolidayCount int           `json:"holiday_count"`
+	Holidays     []*HolidayDTO `json:"holidays,omitempty"` // From the requested date on, when one set is retrieved
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// SaveHolidaySetRequest is the payload to create or rename a holiday set
+type SaveHolidaySetRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+}
+
+// HolidayDTO represents a holiday
+type HolidayDTO struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+// SaveHolidayRequest is the payload to add or rename a holiday
+type SaveHolidayRequest struct {
+	Name string `json:"name" validate:"max=255"`
+}
+
+// ICalImportResultDTO reports the holidays imported from an iCalendar feed
+type ICalImportResultDTO struct {
+	Imported int      `json:"imported"`
+	Replaced bool     `json:"replaced"`          // The set's other holidays were removed
+	Skipped  []string `json:"skipped,omitempty"` // Events that could not be read as holidays, with the reason
+}
+
+// BusinessCalendarDTO represents the calendar of a scope. Fields left out
+// are inherited.
+type BusinessCalendarDTO struct {
+	ID            int64     `json:"id"`
+	Scope         string    `json:"scope"`
+	ScopeID       string    `json:"scope_id,omitempty"`
+	TimeZone      *string   `json:"time_zone,omitempty"`
+	Workdays      []string  `json:"workdays,omitempty"`
+	CutoffHour    *int      `json:"cutoff_hour,omitempty"`
+	HolidaySetIDs []int64   `json:"holiday_set_ids"`
+	UpdatedBy     string    `json:"updated_by,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SaveBusinessCalendarRequest is the payload to set the calendar of a scope.
+// Fields left out are inherited.
+type SaveBusinessCalendarRequest struct {
+	TimeZone      *string  `json:"time_zone"`   // IANA zone, e.g. Europe/Berlin
+	Workdays      []string `json:"workdays"`    // MON, TUE, ...
+	CutoffHour    *int     `json:"cutoff_hour"` // Work started at or after this hour counts from the next business day; 0 has no cutoff
+	HolidaySetIDs []int64  `json:"holiday_set_ids"`
+}
+
+// ResolvedCalendarDTO is the calendar in force for a site or warehouse after
+// inheritance, with a deadline worked out with it
+type ResolvedCalendarDTO struct {
+	TimeZone   string        `json:"time_zone"`
+	Workdays   []string      `json:"workdays"`
+	CutoffHour int           `json:"cutoff_hour"`
+	Holidays   []*HolidayDTO `json:"holidays"` // Upcoming, from the start date on
+	From       time.Time     `json:"from"`
+	StartDay   string        `json:"start_day"` // Business day work started at From counts from
+	Days       int           `json:"days"`
+	Deadline   time.Time     `json:"deadline"` // End of the Days-th business day after the start day
+}
+
+// ToHolidaySetDTO converts a holiday set to its DTO
+func ToHolidaySetDTO(set *domain.HolidaySet) *HolidaySetDTO {
+	return &HolidaySetDTO{
+		ID:           set.ID,
+		Name:         set.Name,
+		Description:  set.Description,
+		HolidayCount: set.HolidayCount,
+		CreatedAt:    set.CreatedAt,
+		UpdatedAt:    set.UpdatedAt,
+	}
+}
+
+// ToHolidayDTOs converts holidays to their DTOs
+func ToHolidayDTOs(holidays []*domain.Holiday) []*HolidayDTO {
+	dtos := make([]*HolidayDTO, len(holidays))
+	for i, holiday := range holidays {
+		dtos[i] = &HolidayDTO{Date: holiday.Date.Format(calendar.DateLayout), Name: holiday.Name}
+	}
+	return dtos
+}
+
+// ToBusinessCalendarDTO converts a business calendar to its DTO
+func ToBusinessCalendarDTO(c *domain.BusinessCalendar) *BusinessCalendarDTO {
+	dto := &BusinessCalendarDTO{
+		ID:            c.ID,
+		Scope:         string(c.ScopeType),
+		ScopeID:       c.ScopeID,
+		TimeZone:      c.TimeZone,
+		CutoffHour:    c.CutoffHour,
+		HolidaySetIDs: c.HolidaySetIDs,
+		UpdatedBy:     c.UpdatedBy,
+		UpdatedAt:     c.UpdatedAt,
+	}
+	if c.Workdays != nil {
+		dto.Workdays = weekdayNames(c.Workdays)
+	}
+	if dto.HolidaySetIDs == nil {
+		dto.HolidaySetIDs = []int64{}
+	}
+	return dto
+}
+
+func weekdayNames(days []time.Weekday) []string {
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = calendar.WeekdayName(day)
+	}
+	return names
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScopeType is what a business calendar applies to
+type ScopeType string
+
+const (
+	ScopeDefault   ScopeType = "DEFAULT"   // Every site and warehouse without its own calendar
+	ScopeSite      ScopeType = "SITE"      // A storefront site, by site ID
+	ScopeWarehouse ScopeType = "WAREHOUSE" // A warehouse, by warehouse ID
+)
+
+// IsValid checks the scope is a known scope type
+func (s ScopeType) IsValid() bool {
+	return s == ScopeDefault || s == ScopeSite || s == ScopeWarehouse
+}
+
+// HolidaySet is a named list of holidays, such as a country's public
+// holidays, that calendars observe
+type HolidaySet struct {
+	ID           int64
+	Name         string
+	Description  string
+	HolidayCount int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewHolidaySet creates an empty holiday set
+func NewHolidaySet(name, description string) (*HolidaySet, error) {
+	now := time.Now()
+	set := &HolidaySet{CreatedAt: now}
+	if err := set.Update(name, description); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// Update renames the set and replaces its description
+func (s *HolidaySet) Update(name, description string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 255 {
+		return fmt.Errorf("holiday set name is required and must be at most 255 characters")
+	}
+	s.Name = name
+	s.Description = strings.TrimSpace(description)
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Holiday is a date of a holiday set
+type Holiday struct {
+	SetID int64
+	Date  time.Time // Midnight UTC of the date
+	Name  string
+}
+
+// BusinessCalendar is the working week of the default calendar, a site or a
+// warehouse. Unset fields are inherited: a warehouse from the site it is
+// resolved with, a site from the default calendar, and the default calendar
+// from the configuration.
+type BusinessCalendar struct {
+	ID            int64
+	ScopeType     ScopeType
+	ScopeID       string // Site or warehouse ID; empty for the default calendar
+	TimeZone      *string
+	Workdays      []time.Weekday // nil inherits; never empty otherwise
+	CutoffHour    *int
+	HolidaySetIDs []int64 // Empty inherits the holiday sets
+	UpdatedBy     string
+	UpdatedAt     time.Time
+}
+
+// NewBusinessCalendar creates the calendar of a scope, validating its fields
+func NewBusinessCalendar(scopeType ScopeType, scopeID string, timeZone *string, workdays []time.Weekday, cutoffHour *int, holidaySetIDs []int64, updatedBy string) (*BusinessCalendar, error) {
+	scopeID = strings.TrimSpace(scopeID)
+	switch {
+	case !scopeType.IsValid():
+		return nil, fmt.Errorf("scope must be DEFAULT, SITE or WAREHOUSE")
+	case scopeType == ScopeDefault && scopeID != "":
+		return nil, fmt.Errorf("the default calendar has no scope ID")
+	case scopeType != ScopeDefault && scopeID == "":
+		return nil, fmt.Errorf("a %s calendar needs the ID of its %s", strings.ToLower(string(scopeType)), strings.ToLower(string(scopeType)))
+	case len(scopeID) > 255:
+		return nil, fmt.Errorf("scope ID must be at most 255 characters")
+	case workdays != nil && len(workdays) == 0:
+		return nil, fmt.Errorf("a calendar needs at least one workday, or none set to inherit them")
+	case cutoffHour != nil && (*cutoffHour < 0 || *cutoffHour > 23):
+		return nil, fmt.Errorf("cutoff hour must be between 0 and 23")
+	}
+	if timeZone != nil {
+		if _, err := time.LoadLocation(*timeZone); err != nil || *timeZone == "" {
+			return nil, fmt.Errorf("invalid time zone %q", *timeZone)
+		}
+	}
+	return &BusinessCalendar{
+		ScopeType:     scopeType,
+		ScopeID:       scopeID,
+		TimeZone:      timeZone,
+		Workdays:      workdays,
+		CutoffHour:    cutoffHour,
+		HolidaySetIDs: holidaySetIDs,
+		UpdatedBy:     updatedBy,
+		UpdatedAt:     time.Now(),
+	}, nil
+}
+
+// CalendarRepository defines the interface for holiday set and business calendar persistence
+type CalendarRepository interface {
+	// FindHolidaySets retrieves every holiday set with its number of holidays, by name.
+	FindHolidaySets(ctx context.Context) ([]*HolidaySet, error)
+
+	// FindHolidaySet retrieves a holiday set by ID.
+	FindHolidaySet(ctx context.Context, id int64) (*HolidaySet, error)
+
+	// SaveHolidaySet creates or updates a holiday set; names are unique regardless of case.
+	SaveHolidaySet(ctx context.Context, set *HolidaySet) error
+
+	// DeleteHolidaySet removes a holiday set, its holidays and its use by calendars.
+	DeleteHolidaySet(ctx context.Context, id int64) error
+
+	// FindHolidays retrieves the holidays of sets from a date on, in date order.
+	FindHolidays(ctx context.Context, setIDs []int64, from time.Time) ([]*Holiday, error)
+
+	// SaveHolidays creates or renames holidays of a set, removing its other
+	// holidays first when replace is set.
+	SaveHolidays(ctx context.Context, setID int64, holidays []*Holiday, replace bool) error
+
+	// DeleteHoliday removes a holiday of a set.
+	DeleteHoliday(ctx context.Context, setID int64, date time.Time) error
+
+	// FindCalendars retrieves every business calendar, by scope.
+	FindCalendars(ctx context.Context) ([]*BusinessCalendar, error)
+
+	// FindCalendar retrieves the calendar of a scope, nil if it has none.
+	FindCalendar(ctx context.Context, scopeType ScopeType, scopeID string) (*BusinessCalendar, error)
+
+	// SaveCalendar creates or replaces the calendar of a scope with its holiday sets.
+	SaveCalendar(ctx context.Context, calendar *BusinessCalendar) error
+
+	// DeleteCalendar removes the calendar of a scope.
+	DeleteCalendar(ctx context.Context, scopeType ScopeType, scopeID string) error
+}
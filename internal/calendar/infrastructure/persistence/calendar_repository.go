@@ -0,0 +1,332 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/calendar/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCalendarRepository implements the CalendarRepository interface using PostgreSQL
+type PostgresCalendarRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCalendarRepository creates a new PostgresCalendarRepository
+func NewPostgresCalendarRepository(db *database.DB) *PostgresCalendarRepository {
+	return &PostgresCalendarRepository{db: db}
+}
+
+const holidaySetColumns = `
+	s.holiday_set_id, s.name, s.description,
+	(SELECT COUNT(*) FROM holiday h WHERE h.holiday_set_id = s.holiday_set_id),
+	s.created_at, s.updated_at`
+
+const businessCalendarColumns = `
+	c.calendar_id, c.scope_type, c.scope_id, c.time_zone, c.workdays, c.cutoff_hour,
+	ARRAY(SELECT chs.holiday_set_id FROM business_calendar_holiday_set chs WHERE chs.calendar_id = c.calendar_id ORDER BY chs.holiday_set_id),
+	c.updated_by, c.updated_at`
+
+// FindHolidaySets retrieves every holiday set with its number of holidays, by name
+func (r *PostgresCalendarRepository) FindHolidaySets(ctx context.Context) ([]*domain.HolidaySet, error) {
+	rows, err := r.db.Query(ctx, `SELECT`+holidaySetColumns+` FROM holiday_set s ORDER BY LOWER(s.name)`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query holiday sets")
+	}
+	defer rows.Close()
+
+	sets := make([]*domain.HolidaySet, 0)
+	for rows.Next() {
+		set, err := scanHolidaySet(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan holiday set")
+		}
+		sets = append(sets, set)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate holiday sets")
+	}
+	return sets, nil
+}
+
+// FindHolidaySet retrieves a holiday set by ID
+func (r *PostgresCalendarRepository) FindHolidaySet(ctx context.Context, id int64) (*domain.HolidaySet, error) {
+	set, err := scanHolidaySet(r.db.QueryRow(ctx, `SELECT`+holidaySetColumns+` FROM holiday_set s WHERE s.holiday_set_id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("holiday set")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find holiday set")
+	}
+	return set, nil
+}
+
+// SaveHolidaySet creates or updates a holiday set. Another set with the same
+// name regardless of case is a conflict.
+func (r *PostgresCalendarRepository) SaveHolidaySet(ctx context.Context, set *domain.HolidaySet) error {
+	var taken bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM holiday_set WHERE LOWER(name) = LOWER($1) AND holiday_set_id <> $2)`,
+		set.Name, set.ID,
+	).Scan(&taken)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to check holiday set name")
+	}
+	if taken {
+		return errors.Conflict(fmt.Sprintf("a holiday set named %s already exists", set.Name))
+	}
+
+	if set.ID == 0 {
+		err := r.db.QueryRow(ctx, `
+			INSERT INTO holiday_set (name, description, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING holiday_set_id`,
+			set.Name, set.Description, set.CreatedAt, set.UpdatedAt,
+		).Scan(&set.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create holiday set")
+		}
+		return nil
+	}
+
+	tag, err := r.db.Pool().Exec(ctx, `
+		UPDATE holiday_set SET name = $2, description = $3, updated_at = $4
+		WHERE holiday_set_id = $1`,
+		set.ID, set.Name, set.Description, set.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update holiday set")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("holiday set")
+	}
+	return nil
+}
+
+// DeleteHolidaySet removes a holiday set; its holidays and its use by
+// calendars are removed by cascade
+func (r *PostgresCalendarRepository) DeleteHolidaySet(ctx context.Context, id int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM holiday_set WHERE holiday_set_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete holiday set")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("holiday set")
+	}
+	return nil
+}
+
+// FindHolidays retrieves the holidays of sets from a date on, in date order
+func (r *PostgresCalendarRepository) FindHolidays(ctx context.Context, setIDs []int64, from time.Time) ([]*domain.Holiday, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT holiday_set_id, holiday_date, name
+		FROM holiday
+		WHERE holiday_set_id = ANY($1) AND holiday_date >= $2
+		ORDER BY holiday_date, holiday_set_id`,
+		setIDs, from,
+	)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query holidays")
+	}
+	defer rows.Close()
+
+	holidays := make([]*domain.Holiday, 0)
+	for rows.Next() {
+		holiday := &domain.Holiday{}
+		if err := rows.Scan(&holiday.SetID, &holiday.Date, &holiday.Name); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan holiday")
+		}
+		holidays = append(holidays, holiday)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate holidays")
+	}
+	return holidays, nil
+}
+
+// SaveHolidays creates or renames holidays of a set in one transaction,
+// removing its other holidays first when replace is set
+func (r *PostgresCalendarRepository) SaveHolidays(ctx context.Context, setID int64, holidays []*domain.Holiday, replace bool) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if replace {
+			if _, err := tx.Exec(ctx, `DELETE FROM holiday WHERE holiday_set_id = $1`, setID); err != nil {
+				return errors.InternalWrap(err, "failed to clear holidays")
+			}
+		}
+		batch := &pgx.Batch{}
+		for _, holiday := range holidays {
+			batch.Queue(`
+				INSERT INTO holiday (holiday_set_id, holiday_date, name)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (holiday_set_id, holiday_date) DO UPDATE SET name = EXCLUDED.name`,
+				setID, holiday.Date, holiday.Name,
+			)
+		}
+		batch.Queue(`UPDATE holiday_set SET updated_at = NOW() WHERE holiday_set_id = $1`, setID)
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return errors.InternalWrap(err, "failed to save holidays")
+		}
+		return nil
+	})
+}
+
+// DeleteHoliday removes a holiday of a set
+func (r *PostgresCalendarRepository) DeleteHoliday(ctx context.Context, setID int64, date time.Time) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM holiday WHERE holiday_set_id = $1 AND holiday_date = $2`, setID, date)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete holiday")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("holiday")
+	}
+	return nil
+}
+
+// FindCalendars retrieves every business calendar, the default first
+func (r *PostgresCalendarRepository) FindCalendars(ctx context.Context) ([]*domain.BusinessCalendar, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT`+businessCalendarColumns+` FROM business_calendar c
+		ORDER BY CASE c.scope_type WHEN 'DEFAULT' THEN 0 WHEN 'SITE' THEN 1 ELSE 2 END, c.scope_id`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query business calendars")
+	}
+	defer rows.Close()
+
+	calendars := make([]*domain.BusinessCalendar, 0)
+	for rows.Next() {
+		calendar, err := scanBusinessCalendar(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan business calendar")
+		}
+		calendars = append(calendars, calendar)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate business calendars")
+	}
+	return calendars, nil
+}
+
+// FindCalendar retrieves the calendar of a scope, nil if it has none
+func (r *PostgresCalendarRepository) FindCalendar(ctx context.Context, scopeType domain.ScopeType, scopeID string) (*domain.BusinessCalendar, error) {
+	calendar, err := scanBusinessCalendar(r.db.QueryRow(ctx,
+		`SELECT`+businessCalendarColumns+` FROM business_calendar c WHERE c.scope_type = $1 AND c.scope_id = $2`,
+		string(scopeType), scopeID,
+	))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find business calendar")
+	}
+	return calendar, nil
+}
+
+// SaveCalendar creates or replaces the calendar of a scope with its holiday
+// sets. Sets that do not exist are a validation error.
+func (r *PostgresCalendarRepository) SaveCalendar(ctx context.Context, calendar *domain.BusinessCalendar) error {
+	var workdays []int16
+	if calendar.Workdays != nil {
+		workdays = make([]int16, len(calendar.Workdays))
+		for i, day := range calendar.Workdays {
+			workdays[i] = int16(day)
+		}
+	}
+	setIDs := calendar.HolidaySetIDs
+	if setIDs == nil {
+		setIDs = []int64{}
+	}
+
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var found int
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM holiday_set WHERE holiday_set_id = ANY($1)`, setIDs).Scan(&found); err != nil {
+			return errors.InternalWrap(err, "failed to check holiday sets")
+		}
+		if found != len(setIDs) {
+			return errors.ValidationError("one or more holiday sets do not exist")
+		}
+
+		err := tx.QueryRow(ctx, `
+			INSERT INTO business_calendar (scope_type, scope_id, time_zone, workdays, cutoff_hour, updated_by, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (scope_type, scope_id) DO UPDATE
+			SET time_zone = EXCLUDED.time_zone,
+				workdays = EXCLUDED.workdays,
+				cutoff_hour = EXCLUDED.cutoff_hour,
+				updated_by = EXCLUDED.updated_by,
+				updated_at = EXCLUDED.updated_at
+			RETURNING calendar_id`,
+			string(calendar.ScopeType), calendar.ScopeID, calendar.TimeZone, workdays, calendar.CutoffHour,
+			calendar.UpdatedBy, calendar.UpdatedAt,
+		).Scan(&calendar.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to save business calendar")
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM business_calendar_holiday_set WHERE calendar_id = $1`, calendar.ID); err != nil {
+			return errors.InternalWrap(err, "failed to clear calendar holiday sets")
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO business_calendar_holiday_set (calendar_id, holiday_set_id)
+			SELECT $1, UNNEST($2::BIGINT[])
+			ON CONFLICT DO NOTHING`,
+			calendar.ID, setIDs,
+		); err != nil {
+			return errors.InternalWrap(err, "failed to save calendar holiday sets")
+		}
+		return nil
+	})
+}
+
+// DeleteCalendar removes the calendar of a scope
+func (r *PostgresCalendarRepository) DeleteCalendar(ctx context.Context, scopeType domain.ScopeType, scopeID string) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM business_calendar WHERE scope_type = $1 AND scope_id = $2`, string(scopeType), scopeID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete business calendar")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("business calendar")
+	}
+	return nil
+}
+
+func scanHolidaySet(row pgx.Row) (*domain.HolidaySet, error) {
+	set := &domain.HolidaySet{}
+	err := row.Scan(&set.ID, &set.Name, &set.Description, &set.HolidayCount, &set.CreatedAt, &set.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+func scanBusinessCalendar(row pgx.Row) (*domain.BusinessCalendar, error) {
+	var (
+		calendar   = &domain.BusinessCalendar{}
+		scopeType  string
+		workdays   []int16
+		cutoffHour *int16
+	)
+	err := row.Scan(
+		&calendar.ID, &scopeType, &calendar.ScopeID, &calendar.TimeZone, &workdays, &cutoffHour,
+		&calendar.HolidaySetIDs, &calendar.UpdatedBy, &calendar.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	calendar.ScopeType = domain.ScopeType(scopeType)
+	if workdays != nil {
+		calendar.Workdays = make([]time.Weekday, len(workdays))
+		for i, day := range workdays {
+			calendar.Workdays[i] = time.Weekday(day)
+		}
+	}
+	if cutoffHour != nil {
+		hour := int(*cutoffHour)
+		calendar.CutoffHour = &hour
+	}
+	return calendar, nil
+}
@@ -0,0 +1,322 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/calendar/application"
+	"github.com/qhato/ecommerce/internal/calendar/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminCalendarHandler handles the holiday sets, their iCalendar import and
+// the business calendars of sites and warehouses
+type AdminCalendarHandler struct {
+	calendarService application.CalendarService
+	authMiddleware  func(http.Handler) http.Handler
+	maxImportBytes  int64
+	log             *logger.Logger
+}
+
+// NewAdminCalendarHandler creates a new AdminCalendarHandler. maxImportBytes
+// caps the size of an uploaded iCalendar feed.
+func NewAdminCalendarHandler(
+	calendarService application.CalendarService,
+	authMiddleware func(http.Handler) http.Handler,
+	maxImportBytes int64,
+	log *logger.Logger,
+) *AdminCalendarHandler {
+	return &AdminCalendarHandler{
+		calendarService: calendarService,
+		authMiddleware:  authMiddleware,
+		maxImportBytes:  maxImportBytes,
+		log:             log,
+	}
+}
+
+// RegisterRoutes registers business calendar routes
+func (h *AdminCalendarHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/calendar", func(r chi.Router) {
+			r.Get("/holiday-sets", h.ListHolidaySets)
+			r.Post("/holiday-sets", h.CreateHolidaySet)
+			r.Get("/holiday-sets/{id}", h.GetHolidaySet)
+			r.Put("/holiday-sets/{id}", h.UpdateHolidaySet)
+			r.Delete("/holiday-sets/{id}", h.DeleteHolidaySet)
+			r.Put("/holiday-sets/{id}/holidays/{date}", h.SaveHoliday)
+			r.Delete("/holiday-sets/{id}/holidays/{date}", h.DeleteHoliday)
+			r.Post("/holiday-sets/{id}/import", h.ImportICal)
+			r.Get("/calendars", h.ListCalendars)
+			r.Put("/calendars/default", h.SaveCalendar)
+			r.Delete("/calendars/default", h.DeleteCalendar)
+			r.Put("/calendars/{scope}/{scopeId}", h.SaveCalendar)
+			r.Delete("/calendars/{scope}/{scopeId}", h.DeleteCalendar)
+			r.Get("/resolve", h.Resolve)
+		})
+	})
+}
+
+// ListHolidaySets lists the holiday sets
+func (h *AdminCalendarHandler) ListHolidaySets(w http.ResponseWriter, r *http.Request) {
+	sets, err := h.calendarService.ListHolidaySets(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list holiday sets")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, sets)
+}
+
+// CreateHolidaySet creates an empty holiday set
+func (h *AdminCalendarHandler) CreateHolidaySet(w http.ResponseWriter, r *http.Request) {
+	var req application.SaveHolidaySetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	set, err := h.calendarService.CreateHolidaySet(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to create holiday set")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, set)
+}
+
+// GetHolidaySet retrieves a holiday set with its holidays from ?from=YYYY-MM-DD
+// on, the start of the current year by default
+func (h *AdminCalendarHandler) GetHolidaySet(w http.ResponseWriter, r *http.Request) {
+	id, ok := holidaySetID(w, r)
+	if !ok {
+		return
+	}
+
+	set, err := h.calendarService.GetHolidaySet(r.Context(), id, r.URL.Query().Get("from"))
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, set)
+}
+
+// UpdateHolidaySet renames a holiday set
+func (h *AdminCalendarHandler) UpdateHolidaySet(w http.ResponseWriter, r *http.Request) {
+	id, ok := holidaySetID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.SaveHolidaySetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	set, err := h.calendarService.UpdateHolidaySet(r.Context(), id, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("holiday_set_id", id).Error("failed to update holiday set")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, set)
+}
+
+// DeleteHolidaySet removes a holiday set
+func (h *AdminCalendarHandler) DeleteHolidaySet(w http.ResponseWriter, r *http.Request) {
+	id, ok := holidaySetID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarService.DeleteHolidaySet(r.Context(), id); err != nil {
+		h.log.WithError(err).WithField("holiday_set_id", id).Error("failed to delete holiday set")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SaveHoliday adds or renames a holiday of a set
+func (h *AdminCalendarHandler) SaveHoliday(w http.ResponseWriter, r *http.Request) {
+	id, ok := holidaySetID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.SaveHolidayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	holiday, err := h.calendarService.SaveHoliday(r.Context(), id, chi.URLParam(r, "date"), &req)
+	if err != nil {
+		h.log.WithError(err).WithField("holiday_set_id", id).Error("failed to save holiday")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, holiday)
+}
+
+// DeleteHoliday removes a holiday of a set
+func (h *AdminCalendarHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	id, ok := holidaySetID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarService.DeleteHoliday(r.Context(), id, chi.URLParam(r, "date")); err != nil {
+		h.log.WithError(err).WithField("holiday_set_id", id).Error("failed to delete holiday")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ImportICal adds the holidays of the iCalendar feed in the request body to a
+// set; ?replace=true removes the set's other holidays
+func (h *AdminCalendarHandler) ImportICal(w http.ResponseWriter, r *http.Request) {
+	id, ok := holidaySetID(w, r)
+	if !ok {
+		return
+	}
+	replace, _ := strconv.ParseBool(r.URL.Query().Get("replace"))
+
+	feed := http.MaxBytesReader(w, r.Body, h.maxImportBytes)
+	result, err := h.calendarService.ImportICal(r.Context(), id, feed, replace)
+	if err != nil {
+		h.log.WithError(err).WithField("holiday_set_id", id).Error("failed to import holidays")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// ListCalendars lists the business calendars of the default scope, sites and warehouses
+func (h *AdminCalendarHandler) ListCalendars(w http.ResponseWriter, r *http.Request) {
+	calendars, err := h.calendarService.ListCalendars(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list business calendars")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, calendars)
+}
+
+// SaveCalendar sets the calendar of the default scope, a site or a warehouse
+func (h *AdminCalendarHandler) SaveCalendar(w http.ResponseWriter, r *http.Request) {
+	scope, scopeID, ok := calendarScope(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.SaveBusinessCalendarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	updatedBy := "admin"
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		updatedBy = email
+	}
+	calendar, err := h.calendarService.SaveCalendar(r.Context(), scope, scopeID, &req, updatedBy)
+	if err != nil {
+		h.log.WithError(err).WithField("scope_id", scopeID).Error("failed to save business calendar")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, calendar)
+}
+
+// DeleteCalendar removes the calendar of a scope, which inherits again
+func (h *AdminCalendarHandler) DeleteCalendar(w http.ResponseWriter, r *http.Request) {
+	scope, scopeID, ok := calendarScope(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarService.DeleteCalendar(r.Context(), scope, scopeID); err != nil {
+		h.log.WithError(err).WithField("scope_id", scopeID).Error("failed to delete business calendar")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Resolve shows the calendar in force for ?site_id= and ?warehouse_id= and the
+// deadline of work started at ?from= (RFC3339, now by default) due within ?days= business days
+func (h *AdminCalendarHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from := time.Now()
+	if raw := query.Get("from"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			httpPkg.RespondError(w, errors.BadRequest("from must be an RFC3339 time").WithInternal(err))
+			return
+		}
+		from = parsed
+	}
+	days := 0
+	if raw := query.Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			httpPkg.RespondError(w, errors.BadRequest("days must be a number").WithInternal(err))
+			return
+		}
+		days = parsed
+	}
+
+	resolved, err := h.calendarService.Preview(r.Context(), query.Get("site_id"), query.Get("warehouse_id"), from, days)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, resolved)
+}
+
+func holidaySetID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid holiday set ID").WithInternal(err))
+		return 0, false
+	}
+	return id, true
+}
+
+// calendarScope reads the scope of a calendar route: the default calendar,
+// or site/{id} or warehouse/{id}
+func calendarScope(w http.ResponseWriter, r *http.Request) (domain.ScopeType, string, bool) {
+	scopeID := chi.URLParam(r, "scopeId")
+	switch strings.ToLower(chi.URLParam(r, "scope")) {
+	case "":
+		return domain.ScopeDefault, "", true
+	case "site", "sites":
+		return domain.ScopeSite, scopeID, true
+	case "warehouse", "warehouses":
+		return domain.ScopeWarehouse, scopeID, true
+	}
+	httpPkg.RespondError(w, errors.BadRequest("calendar scope must be default, site or warehouse"))
+	return "", "", false
+}
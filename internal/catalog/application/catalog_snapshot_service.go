@@ -0,0 +1,465 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// snapshotPageSize is the page size used to read the live catalog
+const snapshotPageSize = 500
+
+// CatalogSnapshotService exports the catalog as a snapshot and promotes snapshots
+// exported from another environment. Imports are reviewed first: the diff of a
+// snapshot against the live catalog carries an ID, and the import only applies
+// when the diff it would apply still has that ID.
+type CatalogSnapshotService interface {
+	// ExportSnapshot returns a snapshot of the live catalog, without archived products and categories.
+	ExportSnapshot(ctx context.Context) (*domain.CatalogSnapshot, error)
+
+	// DiffSnapshot compares a snapshot against the live catalog.
+	DiffSnapshot(ctx context.Context, snapshot *domain.CatalogSnapshot) (*CatalogSnapshotDiffDTO, error)
+
+	// ImportSnapshot applies a reviewed diff to the live catalog.
+	ImportSnapshot(ctx context.Context, cmd *ImportCatalogSnapshotCommand) (*CatalogSnapshotImportDTO, error)
+}
+
+// CatalogSnapshotDiffDTO is the reviewable difference between a snapshot and the live catalog
+type CatalogSnapshotDiffDTO struct {
+	DiffID string `json:"diff_id"` // Passed back to the import to apply exactly this diff
+	Source string `json:"source,omitempty"`
+	Empty  bool   `json:"empty"`
+	*domain.CatalogDiff
+}
+
+// ImportCatalogSnapshotCommand applies a snapshot whose diff was reviewed
+type ImportCatalogSnapshotCommand struct {
+	Snapshot      *domain.CatalogSnapshot `json:"snapshot"`
+	DiffID        string                  `json:"diff_id"`
+	ApplyRemovals bool                    `json:"apply_removals"` // Archive products and categories and withdraw SKUs missing from the snapshot
+}
+
+// CatalogSnapshotImportDTO summarizes an import
+type CatalogSnapshotImportDTO struct {
+	DiffID     string                `json:"diff_id"`
+	Categories *SnapshotImportCounts `json:"categories"`
+	Products   *SnapshotImportCounts `json:"products"`
+	SKUs       *SnapshotImportCounts `json:"skus"`
+}
+
+// SnapshotImportCounts counts the entities of one kind changed by an import
+type SnapshotImportCounts struct {
+	Added   int `json:"added"`
+	Changed int `json:"changed"`
+	Removed int `json:"removed"`
+}
+
+type catalogSnapshotService struct {
+	productRepo  domain.ProductRepository
+	categoryRepo domain.CategoryRepository
+	skuRepo      domain.SKURepository
+	eventBus     event.Bus
+	source       string
+	log          *logger.Logger
+}
+
+// NewCatalogSnapshotService creates a new instance of CatalogSnapshotService.
+// The source names this environment in exported snapshots.
+func NewCatalogSnapshotService(
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	skuRepo domain.SKURepository,
+	eventBus event.Bus,
+	source string,
+	log *logger.Logger,
+) CatalogSnapshotService {
+	return &catalogSnapshotService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		skuRepo:      skuRepo,
+		eventBus:     eventBus,
+		source:       source,
+		log:          log,
+	}
+}
+
+// liveCatalog holds the live catalog entities with their snapshot
+type liveCatalog struct {
+	snapshot   *domain.CatalogSnapshot
+	categories map[string]*domain.Category
+	products   map[string]*domain.Product
+	skus       map[string]*domain.SKU
+}
+
+func (s *catalogSnapshotService) ExportSnapshot(ctx context.Context) (*domain.CatalogSnapshot, error) {
+	live, err := s.loadLiveCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return live.snapshot, nil
+}
+
+func (s *catalogSnapshotService) DiffSnapshot(ctx context.Context, snapshot *domain.CatalogSnapshot) (*CatalogSnapshotDiffDTO, error) {
+	live, err := s.loadLiveCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.diff(live, snapshot)
+}
+
+func (s *catalogSnapshotService) ImportSnapshot(ctx context.Context, cmd *ImportCatalogSnapshotCommand) (*CatalogSnapshotImportDTO, error) {
+	if cmd.Snapshot == nil {
+		return nil, errors.ValidationError("snapshot is required")
+	}
+	if cmd.DiffID == "" {
+		return nil, errors.ValidationError("diff_id is required, review the snapshot's diff first")
+	}
+
+	live, err := s.loadLiveCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := s.diff(live, cmd.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if diff.DiffID != cmd.DiffID {
+		return nil, errors.Conflict("the snapshot or the live catalog changed since the diff was reviewed, review the diff again")
+	}
+
+	result := &CatalogSnapshotImportDTO{
+		DiffID:     diff.DiffID,
+		Categories: &SnapshotImportCounts{},
+		Products:   &SnapshotImportCounts{},
+		SKUs:       &SnapshotImportCounts{},
+	}
+	// Entities are applied one by one; an interrupted import is completed by diffing and importing again
+	if err := s.applyCategories(ctx, live, cmd.Snapshot, diff.Categories, result.Categories); err != nil {
+		return nil, err
+	}
+	if err := s.applyProducts(ctx, live, cmd.Snapshot, diff.Products, result.Products); err != nil {
+		return nil, err
+	}
+	if err := s.applySKUs(ctx, live, cmd.Snapshot, diff.SKUs, result.SKUs); err != nil {
+		return nil, err
+	}
+	if err := s.applyDefaultSKUs(ctx, live, cmd.Snapshot, diff.Products); err != nil {
+		return nil, err
+	}
+	if cmd.ApplyRemovals {
+		if err := s.applyRemovals(ctx, live, diff.CatalogDiff, result); err != nil {
+			return nil, err
+		}
+	}
+
+	s.log.WithFields(logger.Fields{
+		"diff_id":            diff.DiffID,
+		"source":             cmd.Snapshot.Source,
+		"categories_added":   result.Categories.Added,
+		"categories_changed": result.Categories.Changed,
+		"products_added":     result.Products.Added,
+		"products_changed":   result.Products.Changed,
+		"skus_added":         result.SKUs.Added,
+		"skus_changed":       result.SKUs.Changed,
+		"apply_removals":     cmd.ApplyRemovals,
+	}).Info("Catalog snapshot imported")
+	return result, nil
+}
+
+func (s *catalogSnapshotService) diff(live *liveCatalog, snapshot *domain.CatalogSnapshot) (*CatalogSnapshotDiffDTO, error) {
+	if err := snapshot.Validate(live.snapshot); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	diff, err := domain.DiffCatalogSnapshots(live.snapshot, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff catalog snapshot: %w", err)
+	}
+
+	// The diff ID covers the diff and the snapshot values it would apply
+	data, err := json.Marshal(struct {
+		Diff     *domain.CatalogDiff     `json:"diff"`
+		Snapshot *domain.CatalogSnapshot `json:"snapshot"`
+	}{diff, &domain.CatalogSnapshot{
+		FormatVersion: snapshot.FormatVersion,
+		Categories:    snapshot.Categories,
+		Products:      snapshot.Products,
+		SKUs:          snapshot.SKUs,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode catalog diff: %w", err)
+	}
+	sum := sha256.Sum256(data)
+
+	return &CatalogSnapshotDiffDTO{
+		DiffID:      hex.EncodeToString(sum[:]),
+		Source:      snapshot.Source,
+		Empty:       diff.IsEmpty(),
+		CatalogDiff: diff,
+	}, nil
+}
+
+func (s *catalogSnapshotService) loadLiveCatalog(ctx context.Context) (*liveCatalog, error) {
+	var categories []*domain.Category
+	for page := 1; ; page++ {
+		filter := &domain.CategoryFilter{Page: page, PageSize: snapshotPageSize, SortBy: "created_at", SortOrder: "asc"}
+		batch, total, err := s.categoryRepo.FindAll(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list categories: %w", err)
+		}
+		categories = append(categories, batch...)
+		if len(batch) == 0 || int64(len(categories)) >= total {
+			break
+		}
+	}
+
+	var products []*domain.Product
+	for page := 1; ; page++ {
+		filter := &domain.ProductFilter{Page: page, PageSize: snapshotPageSize, SortBy: "created_at", SortOrder: "asc"}
+		batch, total, err := s.productRepo.FindAll(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %w", err)
+		}
+		products = append(products, batch...)
+		if len(batch) == 0 || int64(len(products)) >= total {
+			break
+		}
+	}
+
+	var skus []*domain.SKU
+	for page := 1; ; page++ {
+		filter := &domain.SKUFilter{Page: page, PageSize: snapshotPageSize, SortBy: "created_at", SortOrder: "asc"}
+		batch, total, err := s.skuRepo.FindAll(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list SKUs: %w", err)
+		}
+		skus = append(skus, batch...)
+		if len(batch) == 0 || int64(len(skus)) >= total {
+			break
+		}
+	}
+
+	live := &liveCatalog{
+		snapshot:   domain.NewCatalogSnapshot(s.source, categories, products, skus),
+		categories: make(map[string]*domain.Category, len(categories)),
+		products:   make(map[string]*domain.Product, len(products)),
+		skus:       make(map[string]*domain.SKU, len(skus)),
+	}
+	for _, c := range categories {
+		live.categories[domain.CategorySnapshotKey(c)] = c
+	}
+	for _, p := range products {
+		live.products[domain.ProductSnapshotKey(p)] = p
+	}
+	for _, sku := range skus {
+		live.skus[domain.SKUSnapshotKey(sku)] = sku
+	}
+	return live, nil
+}
+
+// applyCategories creates and updates categories, parents before their children
+func (s *catalogSnapshotService) applyCategories(ctx context.Context, live *liveCatalog, snapshot *domain.CatalogSnapshot, diff *domain.EntityDiff, counts *SnapshotImportCounts) error {
+	pending := make(map[string]bool, len(diff.Added)+len(diff.Changed))
+	for _, key := range diff.Added {
+		pending[key] = true
+	}
+	for _, change := range diff.Changed {
+		pending[change.Key] = true
+	}
+
+	for len(pending) > 0 {
+		progressed := false
+		for _, cs := range snapshot.Categories {
+			if !pending[cs.Key] || pending[cs.ParentKey] {
+				continue
+			}
+			var parentID *int64
+			if cs.ParentKey != "" {
+				id := live.categories[cs.ParentKey].ID
+				parentID = &id
+			}
+
+			category, exists := live.categories[cs.Key]
+			if !exists {
+				category = domain.NewCategory(cs.Name, cs.Description, cs.URL, cs.URLKey)
+			}
+			cs.ApplyTo(category)
+			category.DefaultParentCategoryID = parentID
+
+			if exists {
+				if err := s.categoryRepo.Update(ctx, category); err != nil {
+					return fmt.Errorf("failed to update category %s: %w", cs.Key, err)
+				}
+				counts.Changed++
+				s.publish(ctx, domain.NewCategoryUpdatedEvent(category.ID, map[string]interface{}{"source": snapshot.Source}))
+			} else {
+				if err := s.categoryRepo.Create(ctx, category); err != nil {
+					return fmt.Errorf("failed to create category %s: %w", cs.Key, err)
+				}
+				live.categories[cs.Key] = category
+				counts.Added++
+				s.publish(ctx, domain.NewCategoryCreatedEvent(category.ID, category.Name, parentID))
+			}
+			delete(pending, cs.Key)
+			progressed = true
+		}
+		if !progressed {
+			return errors.ValidationError("the snapshot's category parents form a cycle")
+		}
+	}
+	return nil
+}
+
+func (s *catalogSnapshotService) applyProducts(ctx context.Context, live *liveCatalog, snapshot *domain.CatalogSnapshot, diff *domain.EntityDiff, counts *SnapshotImportCounts) error {
+	for _, ps := range snapshot.Products {
+		if !diffTouches(diff, ps.Key) {
+			continue
+		}
+		product, exists := live.products[ps.Key]
+		if !exists {
+			product = domain.NewProduct(ps.Manufacture, ps.Model, ps.URL, ps.URLKey, ps.CanSellWithoutOptions, ps.EnableDefaultSKUInInventory)
+		}
+		ps.ApplyTo(product)
+		product.DefaultCategoryID = nil
+		if ps.DefaultCategoryKey != "" {
+			product.SetDefaultCategory(live.categories[ps.DefaultCategoryKey].ID)
+		}
+
+		if exists {
+			if err := s.productRepo.Update(ctx, product); err != nil {
+				return fmt.Errorf("failed to update product %s: %w", ps.Key, err)
+			}
+			counts.Changed++
+			s.publish(ctx, domain.NewProductUpdatedEvent(product.ID, map[string]interface{}{"source": snapshot.Source}))
+		} else {
+			if err := s.productRepo.Create(ctx, product); err != nil {
+				return fmt.Errorf("failed to create product %s: %w", ps.Key, err)
+			}
+			live.products[ps.Key] = product
+			counts.Added++
+			s.publish(ctx, domain.NewProductCreatedEvent(product.ID, product.Model, product.Manufacture))
+		}
+	}
+	return nil
+}
+
+func (s *catalogSnapshotService) applySKUs(ctx context.Context, live *liveCatalog, snapshot *domain.CatalogSnapshot, diff *domain.EntityDiff, counts *SnapshotImportCounts) error {
+	for _, ss := range snapshot.SKUs {
+		if !diffTouches(diff, ss.Key) {
+			continue
+		}
+		sku, exists := live.skus[ss.Key]
+		if !exists {
+			sku = domain.NewSKU(ss.Name, ss.Description, ss.UPC, ss.CurrencyCode, ss.Cost, ss.RetailPrice, ss.SalePrice)
+		}
+		oldPrice, oldEffectivePrice := sku.RetailPrice, sku.EffectivePrice()
+		ss.ApplyTo(sku)
+		sku.DefaultProductID = nil
+		if ss.ProductKey != "" {
+			id := live.products[ss.ProductKey].ID
+			sku.DefaultProductID = &id
+		}
+
+		if exists {
+			if err := s.skuRepo.Update(ctx, sku); err != nil {
+				return fmt.Errorf("failed to update SKU %s: %w", ss.Key, err)
+			}
+			counts.Changed++
+			if oldPrice != sku.RetailPrice || oldEffectivePrice != sku.EffectivePrice() {
+				s.publish(ctx, domain.NewSKUPriceChangedEvent(sku.ID, oldPrice, sku.RetailPrice, oldEffectivePrice, sku.EffectivePrice()))
+			}
+		} else {
+			if err := s.skuRepo.Create(ctx, sku); err != nil {
+				return fmt.Errorf("failed to create SKU %s: %w", ss.Key, err)
+			}
+			live.skus[ss.Key] = sku
+			counts.Added++
+			s.publish(ctx, domain.NewSKUCreatedEvent(sku.ID, sku.DefaultProductID, sku.Name, sku.RetailPrice))
+		}
+	}
+	return nil
+}
+
+// applyDefaultSKUs links products to their default SKUs once the SKUs exist
+func (s *catalogSnapshotService) applyDefaultSKUs(ctx context.Context, live *liveCatalog, snapshot *domain.CatalogSnapshot, diff *domain.EntityDiff) error {
+	for _, ps := range snapshot.Products {
+		if !diffTouches(diff, ps.Key) {
+			continue
+		}
+		product := live.products[ps.Key]
+		var skuID *int64
+		if ps.DefaultSKUKey != "" {
+			id := live.skus[ps.DefaultSKUKey].ID
+			skuID = &id
+		}
+		if sameID(product.DefaultSkuID, skuID) {
+			continue
+		}
+		product.DefaultSkuID = skuID
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to set default SKU of product %s: %w", ps.Key, err)
+		}
+	}
+	return nil
+}
+
+// applyRemovals archives products and categories and withdraws SKUs missing from the snapshot
+func (s *catalogSnapshotService) applyRemovals(ctx context.Context, live *liveCatalog, diff *domain.CatalogDiff, result *CatalogSnapshotImportDTO) error {
+	for _, key := range diff.SKUs.Removed {
+		sku := live.skus[key]
+		if err := s.skuRepo.UpdateAvailability(ctx, sku.ID, false); err != nil {
+			return fmt.Errorf("failed to withdraw SKU %s: %w", key, err)
+		}
+		result.SKUs.Removed++
+		s.publish(ctx, domain.NewSKUAvailabilityChangedEvent(sku.ID, false))
+	}
+	for _, key := range diff.Products.Removed {
+		product := live.products[key]
+		if err := s.productRepo.Delete(ctx, product.ID); err != nil {
+			return fmt.Errorf("failed to archive product %s: %w", key, err)
+		}
+		result.Products.Removed++
+		s.publish(ctx, domain.NewProductArchivedEvent(product.ID))
+	}
+	for _, key := range diff.Categories.Removed {
+		if err := s.categoryRepo.Delete(ctx, live.categories[key].ID); err != nil {
+			return fmt.Errorf("failed to archive category %s: %w", key, err)
+		}
+		result.Categories.Removed++
+	}
+	return nil
+}
+
+func (s *catalogSnapshotService) publish(ctx context.Context, evt event.Event) {
+	if err := s.eventBus.Publish(ctx, evt); err != nil {
+		s.log.WithError(err).WithField("event_type", evt.EventType()).Error("failed to publish catalog snapshot import event")
+	}
+}
+
+// diffTouches checks if an import adds or changes the entity with the key
+func diffTouches(diff *domain.EntityDiff, key string) bool {
+	for _, k := range diff.Added {
+		if k == key {
+			return true
+		}
+	}
+	for _, change := range diff.Changed {
+		if change.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// CategoryMerchandisingDTO represents the listing rules of a category
+type CategoryMerchandisingDTO struct {
+	CategoryID       int64      `json:"category_id"`
+	PinnedProductIDs []int64    `json:"pinned_product_ids"`
+	SortRule         string     `json:"sort_rule"`
+	BestSellerDays   int        `json:"best_seller_days"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"` // Unset when the category has no rules
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+// CategoryMerchandisingRequest is the payload to set the listing rules of a category.
+// Pinned products that are not in the category are ignored by listings.
+type CategoryMerchandisingRequest struct {
+	PinnedProductIDs []int64 `json:"pinned_product_ids"`
+	SortRule         string  `json:"sort_rule"` // MANUAL, NEWEST, MARGIN, BEST_SELLERS or empty for the regular sort
+	BestSellerDays   int     `json:"best_seller_days"`
+}
+
+// CategoryMerchandisingService defines the application service for category listing rules.
+type CategoryMerchandisingService interface {
+	// GetMerchandising returns the listing rules of a category, empty if none are set.
+	GetMerchandising(ctx context.Context, categoryID int64) (*CategoryMerchandisingDTO, error)
+
+	// SaveMerchandising replaces the listing rules of a category.
+	SaveMerchandising(ctx context.Context, categoryID int64, req *CategoryMerchandisingRequest) (*CategoryMerchandisingDTO, error)
+
+	// DeleteMerchandising removes the listing rules of a category.
+	DeleteMerchandising(ctx context.Context, categoryID int64) error
+
+	// MerchandisingCriteria returns the listing criteria of a category, nil if it has no rules.
+	MerchandisingCriteria(ctx context.Context, categoryID int64) (*domain.MerchandisingCriteria, error)
+
+	// PreviewCriteria returns the listing criteria of unsaved rules.
+	PreviewCriteria(ctx context.Context, categoryID int64, req *CategoryMerchandisingRequest) (*domain.MerchandisingCriteria, error)
+}
+
+type categoryMerchandisingService struct {
+	repo         domain.CategoryMerchandisingRepository
+	categoryRepo domain.CategoryRepository
+}
+
+// NewCategoryMerchandisingService creates a new instance of CategoryMerchandisingService.
+func NewCategoryMerchandisingService(repo domain.CategoryMerchandisingRepository, categoryRepo domain.CategoryRepository) CategoryMerchandisingService {
+	return &categoryMerchandisingService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *categoryMerchandisingService) GetMerchandising(ctx context.Context, categoryID int64) (*CategoryMerchandisingDTO, error) {
+	if _, err := s.categoryRepo.FindByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	merchandising, err := s.repo.FindByCategoryID(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category merchandising: %w", err)
+	}
+	if merchandising == nil {
+		return &CategoryMerchandisingDTO{
+			CategoryID:       categoryID,
+			PinnedProductIDs: []int64{},
+			BestSellerDays:   domain.DefaultBestSellerDays,
+		}, nil
+	}
+	return ToCategoryMerchandisingDTO(merchandising), nil
+}
+
+func (s *categoryMerchandisingService) SaveMerchandising(ctx context.Context, categoryID int64, req *CategoryMerchandisingRequest) (*CategoryMerchandisingDTO, error) {
+	if _, err := s.categoryRepo.FindByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	merchandising, err := s.repo.FindByCategoryID(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category merchandising: %w", err)
+	}
+	if merchandising == nil {
+		if merchandising, err = domain.NewCategoryMerchandising(categoryID); err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+	}
+	if err := merchandising.Update(req.PinnedProductIDs, domain.MerchandisingSort(req.SortRule), req.BestSellerDays); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if err := s.repo.Save(ctx, merchandising); err != nil {
+		return nil, fmt.Errorf("failed to save category merchandising: %w", err)
+	}
+	return ToCategoryMerchandisingDTO(merchandising), nil
+}
+
+func (s *categoryMerchandisingService) DeleteMerchandising(ctx context.Context, categoryID int64) error {
+	return s.repo.Delete(ctx, categoryID)
+}
+
+func (s *categoryMerchandisingService) MerchandisingCriteria(ctx context.Context, categoryID int64) (*domain.MerchandisingCriteria, error) {
+	merchandising, err := s.repo.FindByCategoryID(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category merchandising: %w", err)
+	}
+	if merchandising == nil {
+		return nil, nil
+	}
+	return merchandising.Criteria(time.Now()), nil
+}
+
+func (s *categoryMerchandisingService) PreviewCriteria(ctx context.Context, categoryID int64, req *CategoryMerchandisingRequest) (*domain.MerchandisingCriteria, error) {
+	if _, err := s.categoryRepo.FindByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	merchandising, err := domain.NewCategoryMerchandising(categoryID)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := merchandising.Update(req.PinnedProductIDs, domain.MerchandisingSort(req.SortRule), req.BestSellerDays); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	return merchandising.Criteria(time.Now()), nil
+}
+
+// ToCategoryMerchandisingDTO converts domain category merchandising to a DTO
+func ToCategoryMerchandisingDTO(merchandising *domain.CategoryMerchandising) *CategoryMerchandisingDTO {
+	createdAt := merchandising.CreatedAt
+	updatedAt := merchandising.UpdatedAt
+	return &CategoryMerchandisingDTO{
+		CategoryID:       merchandising.CategoryID,
+		PinnedProductIDs: merchandising.PinnedProductIDs,
+		SortRule:         string(merchandising.SortRule),
+		BestSellerDays:   merchandising.BestSellerDays,
+		CreatedAt:        &createdAt,
+		UpdatedAt:        &updatedAt,
+	}
+}
@@ -2,12 +2,12 @@ package commands
 
 import (
 	"context"
-	"time"
 
 	"github.com/qhato/ecommerce/internal/catalog/domain"
 	"github.com/qhato/ecommerce/pkg/errors"
 	"github.com/qhato/ecommerce/pkg/event"
 	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/schedule"
 	"github.com/qhato/ecommerce/pkg/validator"
 )
 
@@ -17,9 +17,9 @@ type CreateCategoryCommand struct {
 	Description             string            `json:"description,omitempty"`
 	LongDescription         string            `json:"long_description,omitempty"`
 	URL                     string            `json:"url" validate:"required,url"`
-	URLKey                  string            `json:"url_key" validate:"required"`
-	ActiveStartDate         *time.Time        `json:"active_start_date,omitempty"`
-	ActiveEndDate           *time.Time        `json:"active_end_date,omitempty"`
+	URLKey                  string            `json:"url_key"`                     // Generated from the name when empty
+	ActiveStartDate         *schedule.Time    `json:"active_start_date,omitempty"` // Dates without an offset are in the site's time zone
+	ActiveEndDate           *schedule.Time    `json:"active_end_date,omitempty"`   // A date alone ends at the end of that day
 	DisplayTemplate         string            `json:"display_template,omitempty"`
 	ExternalID              string            `json:"external_id,omitempty"`
 	FulfillmentType         string            `json:"fulfillment_type,omitempty"`
@@ -41,8 +41,8 @@ type UpdateCategoryCommand struct {
 	LongDescription         string            `json:"long_description,omitempty"`
 	URL                     string            `json:"url,omitempty" validate:"omitempty,url"`
 	URLKey                  string            `json:"url_key,omitempty"`
-	ActiveStartDate         *time.Time        `json:"active_start_date,omitempty"`
-	ActiveEndDate           *time.Time        `json:"active_end_date,omitempty"`
+	ActiveStartDate         *schedule.Time    `json:"active_start_date,omitempty"` // Dates without an offset are in the site's time zone
+	ActiveEndDate           *schedule.Time    `json:"active_end_date,omitempty"`   // A date alone ends at the end of that day
 	DisplayTemplate         string            `json:"display_template,omitempty"`
 	MetaDescription         string            `json:"meta_description,omitempty"`
 	MetaTitle               string            `json:"meta_title,omitempty"`
@@ -65,6 +65,9 @@ type CategoryCommandHandler struct {
 	eventBus  event.Bus
 	validator *validator.Validator
 	logger    *logger.Logger
+
+	// urlKeys generates and checks URL keys; unset stores them as given
+	urlKeys URLKeyAssigner
 }
 
 // NewCategoryCommandHandler creates a new category command handler
@@ -84,6 +87,11 @@ func NewCategoryCommandHandler(
 	}
 }
 
+// SetURLKeys sets the generation and uniqueness checks of category URL keys
+func (h *CategoryCommandHandler) SetURLKeys(urlKeys URLKeyAssigner) {
+	h.urlKeys = urlKeys
+}
+
 // HandleCreateCategory handles the create category command
 func (h *CategoryCommandHandler) HandleCreateCategory(ctx context.Context, cmd *CreateCategoryCommand) (int64, error) {
 	// Validate command
@@ -91,12 +99,20 @@ func (h *CategoryCommandHandler) HandleCreateCategory(ctx context.Context, cmd *
 		return 0, errors.ValidationError("invalid create category command").WithInternal(err)
 	}
 
+	urlKey, err := h.urlKey(ctx, 0, cmd.URLKey, cmd.Name)
+	if err != nil {
+		return 0, err
+	}
+	if urlKey == "" {
+		return 0, errors.ValidationError("url_key is required")
+	}
+
 	// Create category entity
 	category := domain.NewCategory(
 		cmd.Name,
 		cmd.Description,
 		cmd.URL,
-		cmd.URLKey,
+		urlKey,
 	)
 
 	// Set optional fields
@@ -114,12 +130,16 @@ func (h *CategoryCommandHandler) HandleCreateCategory(ctx context.Context, cmd *
 
 	// Set active dates
 	if cmd.ActiveStartDate != nil || cmd.ActiveEndDate != nil {
-		category.SetActiveDate(cmd.ActiveStartDate, cmd.ActiveEndDate)
+		loc := schedule.Location(ctx)
+		category.SetActiveDate(schedule.StartOf(cmd.ActiveStartDate, loc), schedule.EndOf(cmd.ActiveEndDate, loc))
 	}
 
 	// Save to repository
 	if err := h.repo.Create(ctx, category); err != nil {
 		h.logger.WithError(err).Error("failed to create category")
+		if errors.IsConflict(err) {
+			return 0, err
+		}
 		return 0, errors.InternalWrap(err, "failed to create category")
 	}
 
@@ -176,8 +196,14 @@ func (h *CategoryCommandHandler) HandleUpdateCategory(ctx context.Context, cmd *
 		changes["description"] = true
 	}
 	if cmd.URL != "" && cmd.URL != category.URL {
+		// A new URL comes with a new key, generated from the name unless given
+		urlKey, err := h.urlKey(ctx, category.ID, cmd.URLKey, category.Name)
+		if err != nil {
+			return err
+		}
+		changes["previous_url"] = category.URL
 		overrideGenerated := cmd.OverrideGeneratedURL != nil && *cmd.OverrideGeneratedURL
-		category.UpdateURLs(cmd.URL, cmd.URLKey, overrideGenerated)
+		category.UpdateURLs(cmd.URL, urlKey, overrideGenerated)
 		changes["url"] = cmd.URL
 	}
 	if cmd.MetaTitle != "" || cmd.MetaDescription != "" {
@@ -197,7 +223,8 @@ func (h *CategoryCommandHandler) HandleUpdateCategory(ctx context.Context, cmd *
 		changes["parent_category_id"] = *cmd.DefaultParentCategoryID
 	}
 	if cmd.ActiveStartDate != nil || cmd.ActiveEndDate != nil {
-		category.SetActiveDate(cmd.ActiveStartDate, cmd.ActiveEndDate)
+		loc := schedule.Location(ctx)
+		category.SetActiveDate(schedule.StartOf(cmd.ActiveStartDate, loc), schedule.EndOf(cmd.ActiveEndDate, loc))
 		changes["active_dates"] = true
 	}
 
@@ -218,6 +245,9 @@ func (h *CategoryCommandHandler) HandleUpdateCategory(ctx context.Context, cmd *
 	// Save to repository
 	if err := h.repo.Update(ctx, category); err != nil {
 		h.logger.WithField("category_id", cmd.ID).WithError(err).Error("failed to update category")
+		if errors.IsConflict(err) {
+			return err
+		}
 		return errors.InternalWrap(err, "failed to update category")
 	}
 
@@ -233,6 +263,14 @@ func (h *CategoryCommandHandler) HandleUpdateCategory(ctx context.Context, cmd *
 	return nil
 }
 
+// urlKey returns the URL key a category gets: without a URL key assigner, key as given
+func (h *CategoryCommandHandler) urlKey(ctx context.Context, categoryID int64, key, name string) (string, error) {
+	if h.urlKeys == nil {
+		return key, nil
+	}
+	return h.urlKeys.AssignURLKey(ctx, domain.URLKeyEntityCategory, categoryID, domain.URLKeyScope{}, key, name)
+}
+
 // HandleDeleteCategory handles the delete category command
 func (h *CategoryCommandHandler) HandleDeleteCategory(ctx context.Context, cmd *DeleteCategoryCommand) error {
 	// Validate command
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// ApproveProductCommand represents a command to publish a draft product, or
+// the pending edits of a published one
+type ApproveProductCommand struct {
+	ID         int64  `json:"id" validate:"required"`
+	ReviewedBy string `json:"-"`
+}
+
+// RejectProductDraftCommand represents a command to send the pending edits of
+// a product back to its contributors
+type RejectProductDraftCommand struct {
+	ID         int64  `json:"id" validate:"required"`
+	Note       string `json:"note" validate:"required,max=2000"`
+	ReviewedBy string `json:"-"`
+}
+
+// DiscardProductDraftCommand represents a command to drop the pending edits of a product
+type DiscardProductDraftCommand struct {
+	ID int64 `json:"id" validate:"required"`
+}
+
+// HandleApproveProduct handles the approve product command
+func (h *ProductCommandHandler) HandleApproveProduct(ctx context.Context, cmd *ApproveProductCommand) error {
+	// Validate command
+	if err := h.validator.Validate(cmd); err != nil {
+		return errors.ValidationError("invalid approve product command").WithInternal(err)
+	}
+
+	product, err := h.repo.FindByID(ctx, cmd.ID)
+	if err != nil {
+		return err
+	}
+	if product.IsArchived() {
+		return errors.Conflict("cannot publish archived product")
+	}
+
+	// A published product is approved through its draft; a draft product is published as is
+	var draft *domain.ProductDraft
+	changes := map[string]interface{}{"publish_status": domain.ProductStatusPublished}
+	if product.IsPublished() {
+		draft, err = h.draftRepo.FindByProductID(ctx, product.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to load product draft")
+		}
+		if draft == nil {
+			return errors.Conflict("product has no changes awaiting approval")
+		}
+		if draft.Status == domain.ProductDraftRejected {
+			return errors.Conflict("product draft was rejected; edit it to submit it again")
+		}
+		for field, value := range draft.Changes {
+			changes[field] = value
+		}
+		delete(changes, "previous_url")
+		if draft.Product.URL != product.URL {
+			changes["previous_url"] = product.URL
+		}
+		draft.ApplyTo(product)
+	} else if h.publishGate != nil {
+		if err := h.publishGate.CheckPublishable(ctx, product.ID); err != nil {
+			return err
+		}
+	}
+	product.Publish(cmd.ReviewedBy)
+
+	if err := h.repo.Update(ctx, product); err != nil {
+		h.logger.WithField("product_id", product.ID).WithError(err).Error("failed to publish product")
+		if errors.IsConflict(err) {
+			// Another product took the draft's URL key since it was saved
+			return err
+		}
+		return errors.InternalWrap(err, "failed to publish product")
+	}
+
+	// The draft is deleted last, so a failure leaves it to be approved again
+	if draft != nil {
+		for name, value := range draft.Attributes {
+			attr, err := domain.NewProductAttribute(product.ID, name, value)
+			if err != nil {
+				return err
+			}
+			if err := h.attrRepo.Save(ctx, attr); err != nil {
+				return errors.InternalWrap(err, "failed to save product attribute")
+			}
+		}
+		if draft.CategoryIDs != nil {
+			if err := h.assignCategories(ctx, product, draft.CategoryIDs); err != nil {
+				return err
+			}
+		}
+		if err := h.draftRepo.Delete(ctx, product.ID); err != nil {
+			return errors.InternalWrap(err, "failed to delete product draft")
+		}
+	}
+
+	// Publish domain event
+	event := domain.NewProductUpdatedEvent(product.ID, changes)
+	if err := h.eventBus.Publish(ctx, event); err != nil {
+		h.logger.WithError(err).Error("failed to publish product updated event")
+	}
+
+	h.logger.WithFields(logger.Fields{
+		"product_id":  product.ID,
+		"reviewed_by": cmd.ReviewedBy,
+	}).Info("product published")
+	return nil
+}
+
+// HandleRejectProductDraft handles the reject product draft command
+func (h *ProductCommandHandler) HandleRejectProductDraft(ctx context.Context, cmd *RejectProductDraftCommand) error {
+	// Validate command
+	if err := h.validator.Validate(cmd); err != nil {
+		return errors.ValidationError("invalid reject product draft command").WithInternal(err)
+	}
+
+	draft, err := h.draftRepo.FindByProductID(ctx, cmd.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to load product draft")
+	}
+	if draft == nil {
+		return errors.NotFound("product draft")
+	}
+	if draft.Status == domain.ProductDraftRejected {
+		return errors.Conflict("product draft is already rejected")
+	}
+
+	draft.Reject(cmd.ReviewedBy, cmd.Note)
+	if err := h.draftRepo.Save(ctx, draft); err != nil {
+		h.logger.WithField("product_id", cmd.ID).WithError(err).Error("failed to reject product draft")
+		return errors.InternalWrap(err, "failed to reject product draft")
+	}
+
+	h.logger.WithFields(logger.Fields{
+		"product_id":  cmd.ID,
+		"reviewed_by": cmd.ReviewedBy,
+	}).Info("product draft rejected")
+	return nil
+}
+
+// HandleDiscardProductDraft handles the discard product draft command
+func (h *ProductCommandHandler) HandleDiscardProductDraft(ctx context.Context, cmd *DiscardProductDraftCommand) error {
+	// Validate command
+	if err := h.validator.Validate(cmd); err != nil {
+		return errors.ValidationError("invalid discard product draft command").WithInternal(err)
+	}
+
+	draft, err := h.draftRepo.FindByProductID(ctx, cmd.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to load product draft")
+	}
+	if draft == nil {
+		return errors.NotFound("product draft")
+	}
+
+	if err := h.draftRepo.Delete(ctx, cmd.ID); err != nil {
+		h.logger.WithField("product_id", cmd.ID).WithError(err).Error("failed to discard product draft")
+		return errors.InternalWrap(err, "failed to discard product draft")
+	}
+
+	h.logger.WithField("product_id", cmd.ID).Info("product draft discarded")
+	return nil
+}
@@ -2,11 +2,13 @@ package commands
 
 import (
 	"context"
+	"time"
 
 	"github.com/qhato/ecommerce/internal/catalog/domain"
 	"github.com/qhato/ecommerce/pkg/errors"
 	"github.com/qhato/ecommerce/pkg/event"
 	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/schedule"
 	"github.com/qhato/ecommerce/pkg/validator"
 )
 
@@ -15,7 +17,7 @@ type CreateProductCommand struct {
 	Manufacture           string            `json:"manufacture" validate:"required"`
 	Model                 string            `json:"model" validate:"required"`
 	URL                   string            `json:"url" validate:"required,url"`
-	URLKey                string            `json:"url_key" validate:"required"`
+	URLKey                string            `json:"url_key"` // Generated from the model when empty
 	CanSellWithoutOptions bool              `json:"can_sell_without_options"`
 	EnableDefaultSKU      bool              `json:"enable_default_sku"`
 	CanonicalURL          string            `json:"canonical_url,omitempty" validate:"omitempty,url"`
@@ -24,6 +26,9 @@ type CreateProductCommand struct {
 	MetaTitle             string            `json:"meta_title,omitempty"`
 	OverrideGeneratedURL  bool              `json:"override_generated_url"`
 	DefaultCategoryID     *int64            `json:"default_category_id,omitempty"`
+	CategoryIDs           []int64           `json:"category_ids,omitempty"`
+	ActiveStartDate       *schedule.Time    `json:"active_start_date,omitempty"` // Dates without an offset are in the site's time zone
+	ActiveEndDate         *schedule.Time    `json:"active_end_date,omitempty"`   // A date alone ends at the end of that day
 	Attributes            map[string]string `json:"attributes,omitempty"`
 }
 
@@ -42,8 +47,12 @@ type UpdateProductCommand struct {
 	MetaTitle             string            `json:"meta_title,omitempty"`
 	OverrideGeneratedURL  *bool             `json:"override_generated_url,omitempty"`
 	DefaultCategoryID     *int64            `json:"default_category_id,omitempty"`
+	CategoryIDs           []int64           `json:"category_ids,omitempty"` // Replaces the product's categories when set
 	DefaultSKUID          *int64            `json:"default_sku_id,omitempty"`
+	ActiveStartDate       *schedule.Time    `json:"active_start_date,omitempty"` // Dates without an offset are in the site's time zone
+	ActiveEndDate         *schedule.Time    `json:"active_end_date,omitempty"`   // A date alone ends at the end of that day
 	Attributes            map[string]string `json:"attributes,omitempty"`
+	EditedBy              string            `json:"-"` // Admin user making the edit
 }
 
 // DeleteProductCommand represents a command to delete a product
@@ -56,19 +65,40 @@ type ArchiveProductCommand struct {
 	ID int64 `json:"id" validate:"required"`
 }
 
+// PublishGate decides whether a product may be published for the first time
+type PublishGate interface {
+	CheckPublishable(ctx context.Context, productID int64) error
+}
+
+// URLKeyAssigner picks the URL key of a product or category: the given key once
+// checked to be a free slug, or a free one generated from its name
+type URLKeyAssigner interface {
+	AssignURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, scope domain.URLKeyScope, key, name string) (string, error)
+}
+
 // ProductCommandHandler handles product commands
 type ProductCommandHandler struct {
 	repo      domain.ProductRepository
 	attrRepo  domain.ProductAttributeRepository
+	xrefRepo  domain.CategoryProductXrefRepository
+	draftRepo domain.ProductDraftRepository
 	eventBus  event.Bus
 	validator *validator.Validator
 	logger    *logger.Logger
+
+	// publishGate rejects draft products that are not ready for the storefront; unset publishes any
+	publishGate PublishGate
+
+	// urlKeys generates and checks URL keys; unset stores them as given
+	urlKeys URLKeyAssigner
 }
 
 // NewProductCommandHandler creates a new product command handler
 func NewProductCommandHandler(
 	repo domain.ProductRepository,
 	attrRepo domain.ProductAttributeRepository,
+	xrefRepo domain.CategoryProductXrefRepository,
+	draftRepo domain.ProductDraftRepository,
 	eventBus event.Bus,
 	validator *validator.Validator,
 	logger *logger.Logger,
@@ -76,12 +106,24 @@ func NewProductCommandHandler(
 	return &ProductCommandHandler{
 		repo:      repo,
 		attrRepo:  attrRepo,
+		xrefRepo:  xrefRepo,
+		draftRepo: draftRepo,
 		eventBus:  eventBus,
 		validator: validator,
 		logger:    logger,
 	}
 }
 
+// SetPublishGate sets the checks a draft product must pass to be published
+func (h *ProductCommandHandler) SetPublishGate(gate PublishGate) {
+	h.publishGate = gate
+}
+
+// SetURLKeys sets the generation and uniqueness checks of product URL keys
+func (h *ProductCommandHandler) SetURLKeys(urlKeys URLKeyAssigner) {
+	h.urlKeys = urlKeys
+}
+
 // HandleCreateProduct handles the create product command
 func (h *ProductCommandHandler) HandleCreateProduct(ctx context.Context, cmd *CreateProductCommand) (int64, error) {
 	// Validate command
@@ -89,12 +131,20 @@ func (h *ProductCommandHandler) HandleCreateProduct(ctx context.Context, cmd *Cr
 		return 0, errors.ValidationError("invalid create product command").WithInternal(err)
 	}
 
+	urlKey, err := h.urlKey(ctx, 0, cmd.URLKey, cmd.Model)
+	if err != nil {
+		return 0, err
+	}
+	if urlKey == "" {
+		return 0, errors.ValidationError("url_key is required")
+	}
+
 	// Create product entity
 	product := domain.NewProduct(
 		cmd.Manufacture,
 		cmd.Model,
 		cmd.URL,
-		cmd.URLKey,
+		urlKey,
 		cmd.CanSellWithoutOptions,
 		cmd.EnableDefaultSKU,
 	)
@@ -108,10 +158,17 @@ func (h *ProductCommandHandler) HandleCreateProduct(ctx context.Context, cmd *Cr
 	if cmd.DefaultCategoryID != nil {
 		product.SetDefaultCategory(*cmd.DefaultCategoryID)
 	}
+	if cmd.ActiveStartDate != nil || cmd.ActiveEndDate != nil {
+		loc := schedule.Location(ctx)
+		product.SetActiveDate(schedule.StartOf(cmd.ActiveStartDate, loc), schedule.EndOf(cmd.ActiveEndDate, loc))
+	}
 
 	// Save to repository
 	if err := h.repo.Create(ctx, product); err != nil {
 		h.logger.WithError(err).Error("failed to create product")
+		if errors.IsConflict(err) {
+			return 0, err
+		}
 		return 0, errors.InternalWrap(err, "failed to create product")
 	}
 
@@ -128,6 +185,13 @@ func (h *ProductCommandHandler) HandleCreateProduct(ctx context.Context, cmd *Cr
 		}
 	}
 
+	// Assign categories
+	if len(cmd.CategoryIDs) > 0 {
+		if err := h.assignCategories(ctx, product, cmd.CategoryIDs); err != nil {
+			return 0, err
+		}
+	}
+
 	// Publish domain event
 	event := domain.NewProductCreatedEvent(product.ID, product.Model, product.Manufacture)
 	if err := h.eventBus.Publish(ctx, event); err != nil {
@@ -138,70 +202,73 @@ func (h *ProductCommandHandler) HandleCreateProduct(ctx context.Context, cmd *Cr
 	return product.ID, nil
 }
 
-// HandleUpdateProduct handles the update product command
-func (h *ProductCommandHandler) HandleUpdateProduct(ctx context.Context, cmd *UpdateProductCommand) error {
+// HandleUpdateProduct handles the update product command. Products that were
+// never published are edited directly; edits of a published product are kept
+// in its draft until a reviewer approves them. It reports whether the edit
+// awaits approval.
+func (h *ProductCommandHandler) HandleUpdateProduct(ctx context.Context, cmd *UpdateProductCommand) (bool, error) {
 	// Validate command
 	if err := h.validator.Validate(cmd); err != nil {
-		return errors.ValidationError("invalid update product command").WithInternal(err)
+		return false, errors.ValidationError("invalid update product command").WithInternal(err)
 	}
 
 	// Find existing product
 	product, err := h.repo.FindByID(ctx, cmd.ID)
 	if err != nil {
-		return errors.InternalWrap(err, "product not found")
+		return false, errors.InternalWrap(err, "product not found")
 	}
 
 	if product.IsArchived() {
-		return errors.Conflict("cannot update archived product")
+		return false, errors.Conflict("cannot update archived product")
 	}
 
-	// Track changes for event
-	changes := make(map[string]interface{})
-
-	// Update fields if provided
-	if cmd.Manufacture != "" && cmd.Manufacture != product.Manufacture {
-		changes["manufacture"] = cmd.Manufacture
-		product.Manufacture = cmd.Manufacture
-	}
-	if cmd.Model != "" && cmd.Model != product.Model {
-		changes["model"] = cmd.Model
-		product.Model = cmd.Model
-	}
+	// A new URL comes with a new key, generated from the model unless given
 	if cmd.URL != "" && cmd.URL != product.URL {
-		product.UpdateURLs(cmd.URL, cmd.URLKey, cmd.OverrideGeneratedURL != nil && *cmd.OverrideGeneratedURL)
-		changes["url"] = cmd.URL
-	}
-	if cmd.MetaTitle != "" || cmd.MetaDescription != "" {
-		product.UpdateMetadata(cmd.MetaTitle, cmd.MetaDescription)
-		changes["metadata"] = true
-	}
-	if cmd.DefaultCategoryID != nil {
-		product.SetDefaultCategory(*cmd.DefaultCategoryID)
-		changes["default_category_id"] = *cmd.DefaultCategoryID
+		model := cmd.Model
+		if model == "" {
+			model = product.Model
+		}
+		if cmd.URLKey, err = h.urlKey(ctx, product.ID, cmd.URLKey, model); err != nil {
+			return false, err
+		}
 	}
-	if cmd.DefaultSKUID != nil {
-		product.SetDefaultSKU(*cmd.DefaultSKUID)
-		changes["default_sku_id"] = *cmd.DefaultSKUID
+
+	if product.IsPublished() {
+		return true, h.saveDraft(ctx, product, cmd)
 	}
 
+	// Update fields if provided, tracking changes for the event
+	changes := applyProductUpdate(product, cmd, schedule.Location(ctx))
+
 	// Update attributes
 	if cmd.Attributes != nil {
 		for name, value := range cmd.Attributes {
 			attr, err := domain.NewProductAttribute(product.ID, name, value)
 			if err != nil {
-				return err
+				return false, err
 			}
 			if err := h.attrRepo.Save(ctx, attr); err != nil {
-				return errors.InternalWrap(err, "failed to save product attribute")
+				return false, errors.InternalWrap(err, "failed to save product attribute")
 			}
 		}
 		changes["attributes"] = true
 	}
 
+	// Replace categories
+	if cmd.CategoryIDs != nil {
+		if err := h.assignCategories(ctx, product, cmd.CategoryIDs); err != nil {
+			return false, err
+		}
+		changes["category_ids"] = cmd.CategoryIDs
+	}
+
 	// Save to repository
 	if err := h.repo.Update(ctx, product); err != nil {
 		h.logger.WithField("product_id", cmd.ID).WithError(err).Error("failed to update product")
-		return errors.InternalWrap(err, "failed to update product")
+		if errors.IsConflict(err) {
+			return false, err
+		}
+		return false, errors.InternalWrap(err, "failed to update product")
 	}
 
 	// Publish domain event
@@ -213,9 +280,93 @@ func (h *ProductCommandHandler) HandleUpdateProduct(ctx context.Context, cmd *Up
 	}
 
 	h.logger.WithField("product_id", product.ID).Info("product updated")
+	return false, nil
+}
+
+// saveDraft records an edit of a published product in its draft, leaving the
+// published version untouched
+func (h *ProductCommandHandler) saveDraft(ctx context.Context, product *domain.Product, cmd *UpdateProductCommand) error {
+	draft, err := h.draftRepo.FindByProductID(ctx, product.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to load product draft")
+	}
+	if draft == nil {
+		if draft, err = domain.NewProductDraft(product); err != nil {
+			return err
+		}
+	}
+
+	changes := applyProductUpdate(draft.Product, cmd, schedule.Location(ctx))
+	if cmd.Attributes != nil {
+		for name, value := range cmd.Attributes {
+			if _, err := domain.NewProductAttribute(product.ID, name, value); err != nil {
+				return err
+			}
+			draft.Attributes[name] = value
+		}
+		changes["attributes"] = true
+	}
+	if cmd.CategoryIDs != nil {
+		draft.CategoryIDs = cmd.CategoryIDs
+		changes["category_ids"] = cmd.CategoryIDs
+	}
+	draft.RecordEdit(cmd.EditedBy, changes)
+
+	if err := h.draftRepo.Save(ctx, draft); err != nil {
+		h.logger.WithField("product_id", product.ID).WithError(err).Error("failed to save product draft")
+		return errors.InternalWrap(err, "failed to save product draft")
+	}
+
+	h.logger.WithField("product_id", product.ID).Info("product draft saved for approval")
 	return nil
 }
 
+// urlKey returns the URL key a product gets: without a URL key assigner, key as given
+func (h *ProductCommandHandler) urlKey(ctx context.Context, productID int64, key, model string) (string, error) {
+	if h.urlKeys == nil {
+		return key, nil
+	}
+	return h.urlKeys.AssignURLKey(ctx, domain.URLKeyEntityProduct, productID, domain.URLKeyScope{}, key, model)
+}
+
+// applyProductUpdate sets the fields given in the command on a product and
+// returns the changes made. Active dates without an offset are read in loc.
+func applyProductUpdate(product *domain.Product, cmd *UpdateProductCommand, loc *time.Location) map[string]interface{} {
+	changes := make(map[string]interface{})
+
+	if cmd.Manufacture != "" && cmd.Manufacture != product.Manufacture {
+		changes["manufacture"] = cmd.Manufacture
+		product.Manufacture = cmd.Manufacture
+	}
+	if cmd.Model != "" && cmd.Model != product.Model {
+		changes["model"] = cmd.Model
+		product.Model = cmd.Model
+	}
+	if cmd.URL != "" && cmd.URL != product.URL {
+		changes["previous_url"] = product.URL
+		product.UpdateURLs(cmd.URL, cmd.URLKey, cmd.OverrideGeneratedURL != nil && *cmd.OverrideGeneratedURL)
+		changes["url"] = cmd.URL
+	}
+	if cmd.MetaTitle != "" || cmd.MetaDescription != "" {
+		product.UpdateMetadata(cmd.MetaTitle, cmd.MetaDescription)
+		changes["metadata"] = true
+	}
+	if cmd.DefaultCategoryID != nil {
+		product.SetDefaultCategory(*cmd.DefaultCategoryID)
+		changes["default_category_id"] = *cmd.DefaultCategoryID
+	}
+	if cmd.DefaultSKUID != nil {
+		product.SetDefaultSKU(*cmd.DefaultSKUID)
+		changes["default_sku_id"] = *cmd.DefaultSKUID
+	}
+	if cmd.ActiveStartDate != nil || cmd.ActiveEndDate != nil {
+		product.SetActiveDate(schedule.StartOf(cmd.ActiveStartDate, loc), schedule.EndOf(cmd.ActiveEndDate, loc))
+		changes["active_dates"] = true
+	}
+
+	return changes
+}
+
 // HandleDeleteProduct handles the delete product command
 func (h *ProductCommandHandler) HandleDeleteProduct(ctx context.Context, cmd *DeleteProductCommand) error {
 	// Validate command
@@ -280,3 +431,40 @@ func (h *ProductCommandHandler) HandleArchiveProduct(ctx context.Context, cmd *A
 	h.logger.WithField("product_id", cmd.ID).Info("product archived")
 	return nil
 }
+
+// assignCategories makes categoryIDs the product's exact set of categories,
+// ordered as given, with one upsert and one delete whatever their number
+func (h *ProductCommandHandler) assignCategories(ctx context.Context, product *domain.Product, categoryIDs []int64) error {
+	existing, err := h.xrefRepo.FindByProductID(ctx, product.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to load product categories")
+	}
+
+	wanted := make(map[int64]bool, len(categoryIDs))
+	xrefs := make([]*domain.CategoryProductXref, 0, len(categoryIDs))
+	for i, categoryID := range categoryIDs {
+		xref, err := domain.NewCategoryProductXref(categoryID, product.ID)
+		if err != nil {
+			return err
+		}
+		xref.DisplayOrder = float64(i)
+		xref.DefaultReference = product.DefaultCategoryID != nil && *product.DefaultCategoryID == categoryID
+		wanted[categoryID] = true
+		xrefs = append(xrefs, xref)
+	}
+
+	var stale []int64
+	for _, xref := range existing {
+		if !wanted[xref.CategoryID] {
+			stale = append(stale, xref.CategoryID)
+		}
+	}
+
+	if err := h.xrefRepo.SaveAll(ctx, xrefs); err != nil {
+		return errors.InternalWrap(err, "failed to save product categories")
+	}
+	if err := h.xrefRepo.RemoveCategoryProductXrefs(ctx, product.ID, stale); err != nil {
+		return errors.InternalWrap(err, "failed to remove product categories")
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ type CreateSKUCommand struct {
 	TaxCode          string            `json:"tax_code,omitempty"`
 	DefaultProductID *int64            `json:"default_product_id,omitempty"`
 	Attributes       map[string]string `json:"attributes,omitempty"`
+	OptionValueIDs   []int64           `json:"product_option_value_ids,omitempty"`
 }
 
 // UpdateSKUCommand represents a command to update a SKU
@@ -43,6 +44,7 @@ type UpdateSKUCommand struct {
 	Taxable         *bool             `json:"taxable,omitempty"`
 	TaxCode         string            `json:"tax_code,omitempty"`
 	Attributes      map[string]string `json:"attributes,omitempty"`
+	OptionValueIDs  []int64           `json:"product_option_value_ids,omitempty"` // Replaces the SKU's option values when set
 }
 
 // UpdateSKUPricingCommand represents a command to update SKU pricing
@@ -67,6 +69,7 @@ type DeleteSKUCommand struct {
 type SKUCommandHandler struct {
 	repo      domain.SKURepository
 	attrRepo  domain.SKUAttributeRepository
+	xrefRepo  domain.SkuProductOptionValueXrefRepository
 	eventBus  event.Bus
 	validator *validator.Validator
 	logger    *logger.Logger
@@ -76,6 +79,7 @@ type SKUCommandHandler struct {
 func NewSKUCommandHandler(
 	repo domain.SKURepository,
 	attrRepo domain.SKUAttributeRepository,
+	xrefRepo domain.SkuProductOptionValueXrefRepository,
 	eventBus event.Bus,
 	validator *validator.Validator,
 	logger *logger.Logger,
@@ -83,6 +87,7 @@ func NewSKUCommandHandler(
 	return &SKUCommandHandler{
 		repo:      repo,
 		attrRepo:  attrRepo,
+		xrefRepo:  xrefRepo,
 		eventBus:  eventBus,
 		validator: validator,
 		logger:    logger,
@@ -134,6 +139,13 @@ func (h *SKUCommandHandler) HandleCreateSKU(ctx context.Context, cmd *CreateSKUC
 		}
 	}
 
+	// Assign product option values
+	if len(cmd.OptionValueIDs) > 0 {
+		if err := h.assignOptionValues(ctx, sku.ID, cmd.OptionValueIDs); err != nil {
+			return 0, err
+		}
+	}
+
 	// Publish domain event
 	event := domain.NewSKUCreatedEvent(sku.ID, sku.DefaultProductID, sku.Name, sku.RetailPrice)
 	if err := h.eventBus.Publish(ctx, event); err != nil {
@@ -201,6 +213,13 @@ func (h *SKUCommandHandler) HandleUpdateSKU(ctx context.Context, cmd *UpdateSKUC
 		}
 	}
 
+	// Replace product option values
+	if cmd.OptionValueIDs != nil {
+		if err := h.assignOptionValues(ctx, sku.ID, cmd.OptionValueIDs); err != nil {
+			return err
+		}
+	}
+
 	// Save to repository
 	if err := h.repo.Update(ctx, sku); err != nil {
 		h.logger.WithField("sku_id", cmd.ID).WithError(err).Error("failed to update SKU")
@@ -224,8 +243,8 @@ func (h *SKUCommandHandler) HandleUpdateSKUPricing(ctx context.Context, cmd *Upd
 		return errors.InternalWrap(err, "SKU not found")
 	}
 
-	// Track old price for event
-	oldPrice := sku.RetailPrice
+	// Track old prices for event
+	oldPrice, oldEffectivePrice := sku.RetailPrice, sku.EffectivePrice()
 
 	// Update pricing
 	sku.UpdatePricing(cmd.RetailPrice, cmd.SalePrice)
@@ -236,9 +255,9 @@ func (h *SKUCommandHandler) HandleUpdateSKUPricing(ctx context.Context, cmd *Upd
 		return errors.InternalWrap(err, "failed to update SKU pricing")
 	}
 
-	// Publish price changed event if price actually changed
-	if oldPrice != cmd.RetailPrice {
-		event := domain.NewSKUPriceChangedEvent(sku.ID, oldPrice, cmd.RetailPrice)
+	// Publish price changed event if the retail or effective price actually changed
+	if oldPrice != cmd.RetailPrice || oldEffectivePrice != sku.EffectivePrice() {
+		event := domain.NewSKUPriceChangedEvent(sku.ID, oldPrice, cmd.RetailPrice, oldEffectivePrice, sku.EffectivePrice())
 		if err := h.eventBus.Publish(ctx, event); err != nil {
 			h.logger.WithError(err).Error("failed to publish SKU price changed event")
 		}
@@ -293,3 +312,38 @@ func (h *SKUCommandHandler) HandleDeleteSKU(ctx context.Context, cmd *DeleteSKUC
 	h.logger.WithField("sku_id", cmd.ID).Info("SKU deleted")
 	return nil
 }
+
+// assignOptionValues makes optionValueIDs the SKU's exact set of product
+// option values, with one upsert and one delete whatever their number
+func (h *SKUCommandHandler) assignOptionValues(ctx context.Context, skuID int64, optionValueIDs []int64) error {
+	existing, err := h.xrefRepo.FindBySKUID(ctx, skuID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to load SKU option values")
+	}
+
+	wanted := make(map[int64]bool, len(optionValueIDs))
+	xrefs := make([]*domain.SkuProductOptionValueXref, 0, len(optionValueIDs))
+	for _, optionValueID := range optionValueIDs {
+		xref, err := domain.NewSkuProductOptionValueXref(skuID, optionValueID)
+		if err != nil {
+			return err
+		}
+		wanted[optionValueID] = true
+		xrefs = append(xrefs, xref)
+	}
+
+	var stale []int64
+	for _, xref := range existing {
+		if !wanted[xref.ProductOptionValueID] {
+			stale = append(stale, xref.ProductOptionValueID)
+		}
+	}
+
+	if err := h.xrefRepo.SaveAll(ctx, xrefs); err != nil {
+		return errors.InternalWrap(err, "failed to save SKU option values")
+	}
+	if err := h.xrefRepo.RemoveSkuProductOptionValueXrefs(ctx, skuID, stale); err != nil {
+		return errors.InternalWrap(err, "failed to remove SKU option values")
+	}
+	return nil
+}
@@ -0,0 +1,467 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/httpclient"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/webhook"
+)
+
+// ContentPublishedWebhookEvent names the webhook payload and its X-Webhook-Event header
+const ContentPublishedWebhookEvent = "content.published"
+
+// ContentPublishConfig configures content published notifications
+type ContentPublishConfig struct {
+	BaseURL       string        // Storefront origin prefixed to relative URLs; empty sends paths
+	WebhookURLs   []string      // Endpoints receiving each notification as a JSON POST
+	WebhookSecret string        // Signs the timestamp and body with HMAC-SHA256 in X-Webhook-Signature; empty sends it unsigned
+	Events        bool          // Publish ContentPublishedEvent on the event bus as well
+	ContentPath   string        // Storefront path of CMS content; {slug} is replaced
+	GlobalPaths   []string      // Pages showing the navigation tree, purged on category changes
+	BatchWindow   time.Duration // Changes within the window are sent in one notification
+}
+
+// ContentPurgeRequest asks to notify the storefront URLs of an entity, e.g.
+// after CMS content is published or to purge pages by hand
+type ContentPurgeRequest struct {
+	EntityType domain.PublishedEntityType `json:"entity_type"`
+	EntityID   string                     `json:"entity_id"` // Product, category or SKU ID, or content slug
+	URLs       []string                   `json:"urls"`      // Extra storefront paths or URLs
+}
+
+// ContentPublishedDTO is the content published notification sent to webhooks
+type ContentPublishedDTO struct {
+	Event       string                   `json:"event"`
+	ID          string                   `json:"id"`
+	PublishedAt time.Time                `json:"published_at"`
+	Entities    []domain.PublishedEntity `json:"entities"`
+	URLs        []string                 `json:"urls"`
+}
+
+// ContentPublishService tells headless frontends and CDNs which storefront URLs
+// to purge when catalog or CMS entities change, by webhook and/or event bus message.
+type ContentPublishService interface {
+	// AffectedURLs returns the storefront URLs showing an entity.
+	AffectedURLs(ctx context.Context, entity domain.PublishedEntity) ([]string, error)
+
+	// Purge sends a notification for an entity and extra URLs right away.
+	Purge(ctx context.Context, req *ContentPurgeRequest) (*ContentPublishedDTO, error)
+
+	// Subscribe queues notifications for catalog events.
+	Subscribe(bus event.Bus) error
+
+	// Start sends the queued notifications every batch window until ctx is cancelled.
+	Start(ctx context.Context)
+}
+
+type contentPublishService struct {
+	productRepo  domain.ProductRepository
+	categoryRepo domain.CategoryRepository
+	skuRepo      domain.SKURepository
+	xrefRepo     domain.CategoryProductXrefRepository
+	eventBus     event.Bus
+	client       *httpclient.Client
+	cfg          ContentPublishConfig
+	log          *logger.Logger
+
+	// Entities changed since the last flush, with the URLs they moved away from
+	mu       sync.Mutex
+	entities map[domain.PublishedEntity]bool
+	urls     map[string]bool
+}
+
+// NewContentPublishService creates a new instance of ContentPublishService.
+// client may be nil when no webhook is configured.
+func NewContentPublishService(
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	skuRepo domain.SKURepository,
+	xrefRepo domain.CategoryProductXrefRepository,
+	eventBus event.Bus,
+	client *httpclient.Client,
+	cfg ContentPublishConfig,
+	log *logger.Logger,
+) ContentPublishService {
+	return &contentPublishService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		skuRepo:      skuRepo,
+		xrefRepo:     xrefRepo,
+		eventBus:     eventBus,
+		client:       client,
+		cfg:          cfg,
+		log:          log,
+		entities:     make(map[domain.PublishedEntity]bool),
+		urls:         make(map[string]bool),
+	}
+}
+
+func (s *contentPublishService) AffectedURLs(ctx context.Context, entity domain.PublishedEntity) ([]string, error) {
+	if !entity.Type.IsValid() {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown entity type %q", entity.Type))
+	}
+	if entity.ID == "" {
+		return nil, errors.ValidationError("entity ID is required")
+	}
+	if entity.Type == domain.PublishedContent {
+		return []string{s.absolute(strings.ReplaceAll(s.cfg.ContentPath, "{slug}", entity.ID))}, nil
+	}
+
+	id, err := strconv.ParseInt(entity.ID, 10, 64)
+	if err != nil {
+		return nil, errors.ValidationError(fmt.Sprintf("invalid %s ID", entity.Type))
+	}
+	paths := make(map[string]bool)
+	switch entity.Type {
+	case domain.PublishedProduct:
+		err = s.productPaths(ctx, id, paths)
+	case domain.PublishedCategory:
+		err = s.categoryPaths(ctx, id, paths)
+	case domain.PublishedSKU:
+		err = s.skuPaths(ctx, id, paths)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.absoluteAll(paths), nil
+}
+
+func (s *contentPublishService) Purge(ctx context.Context, req *ContentPurgeRequest) (*ContentPublishedDTO, error) {
+	var entities []domain.PublishedEntity
+	urls := make(map[string]bool)
+	if req.EntityType != "" || req.EntityID != "" {
+		entity := domain.PublishedEntity{Type: req.EntityType, ID: req.EntityID}
+		affected, err := s.AffectedURLs(ctx, entity)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+		for _, url := range affected {
+			urls[url] = true
+		}
+	}
+	for _, url := range req.URLs {
+		if url = strings.TrimSpace(url); url != "" {
+			urls[s.absolute(url)] = true
+		}
+	}
+	if len(urls) == 0 {
+		return nil, errors.ValidationError("an entity or URLs to purge are required")
+	}
+
+	notification := newContentPublished(entities, sortedKeys(urls))
+	if err := s.notify(ctx, notification); err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// Subscribe queues the products, categories and SKUs of catalog events; storefront
+// pages listing them are resolved when the batch is sent
+func (s *contentPublishService) Subscribe(bus event.Bus) error {
+	handlers := map[string]event.Handler{
+		domain.EventProductCreated:         s.handleCatalogEvent,
+		domain.EventProductUpdated:         s.handleCatalogEvent,
+		domain.EventProductArchived:        s.handleCatalogEvent,
+		domain.EventCategoryCreated:        s.handleCatalogEvent,
+		domain.EventCategoryUpdated:        s.handleCatalogEvent,
+		domain.EventSKUCreated:             s.handleCatalogEvent,
+		domain.EventSKUAvailabilityChanged: s.handleCatalogEvent,
+		domain.EventSKUPriceChanged:        s.handleCatalogEvent,
+	}
+	for eventType, handler := range handlers {
+		if err := bus.Subscribe(eventType, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *contentPublishService) handleCatalogEvent(ctx context.Context, evt event.Event) error {
+	if !s.enabled() {
+		return nil
+	}
+
+	var entity domain.PublishedEntity
+	var previousURL string
+	switch e := evt.(type) {
+	case *domain.ProductCreatedEvent:
+		entity = productEntity(e.ProductID)
+	case *domain.ProductUpdatedEvent:
+		entity = productEntity(e.ProductID)
+		previousURL, _ = e.Changes["previous_url"].(string)
+	case *domain.ProductArchivedEvent:
+		entity = productEntity(e.ProductID)
+	case *domain.CategoryCreatedEvent:
+		entity = categoryEntity(e.CategoryID)
+	case *domain.CategoryUpdatedEvent:
+		entity = categoryEntity(e.CategoryID)
+		previousURL, _ = e.Changes["previous_url"].(string)
+	case *domain.SKUCreatedEvent:
+		entity = skuEntity(e.SKUID)
+	case *domain.SKUAvailabilityChangedEvent:
+		entity = skuEntity(e.SKUID)
+	case *domain.SKUPriceChangedEvent:
+		entity = skuEntity(e.SKUID)
+	default:
+		return nil
+	}
+
+	s.mu.Lock()
+	s.entities[entity] = true
+	if previousURL != "" {
+		s.urls[s.absolute(previousURL)] = true
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *contentPublishService) Start(ctx context.Context) {
+	if !s.enabled() || s.cfg.BatchWindow <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.BatchWindow)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// Send what is left so a deploy does not leave stale pages cached
+				flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				s.flush(flushCtx)
+				cancel()
+				return
+			case <-ticker.C:
+				s.flush(ctx)
+			}
+		}
+	}()
+}
+
+// flush resolves the queued entities to their URLs and sends one notification.
+// Entities that no longer exist only purge the URLs they moved away from.
+func (s *contentPublishService) flush(ctx context.Context) {
+	s.mu.Lock()
+	if len(s.entities) == 0 && len(s.urls) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	entities, urls := s.entities, s.urls
+	s.entities, s.urls = make(map[domain.PublishedEntity]bool), make(map[string]bool)
+	s.mu.Unlock()
+
+	published := make([]domain.PublishedEntity, 0, len(entities))
+	for entity := range entities {
+		affected, err := s.AffectedURLs(ctx, entity)
+		if err != nil && !errors.IsNotFound(err) {
+			s.log.WithError(err).WithFields(logger.Fields{
+				"entity_type": entity.Type,
+				"entity_id":   entity.ID,
+			}).Warn("failed to resolve storefront URLs of changed entity")
+			continue
+		}
+		published = append(published, entity)
+		for _, url := range affected {
+			urls[url] = true
+		}
+	}
+	sort.Slice(published, func(i, j int) bool {
+		if published[i].Type != published[j].Type {
+			return published[i].Type < published[j].Type
+		}
+		return published[i].ID < published[j].ID
+	})
+	if len(urls) == 0 {
+		return
+	}
+
+	if err := s.notify(ctx, newContentPublished(published, sortedKeys(urls))); err != nil {
+		s.log.WithError(err).WithField("urls", len(urls)).Error("failed to send content published notification")
+	}
+}
+
+// notify sends a notification to every webhook and the event bus
+func (s *contentPublishService) notify(ctx context.Context, notification *ContentPublishedDTO) error {
+	if s.cfg.Events {
+		if err := s.eventBus.Publish(ctx, domain.NewContentPublishedEvent(notification.Entities, notification.URLs)); err != nil {
+			s.log.WithError(err).Error("failed to publish content published event")
+		}
+	}
+	if len(s.cfg.WebhookURLs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode content published notification: %w", err)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	webhook.SetHeaders(header, s.cfg.WebhookSecret, notification.ID, ContentPublishedWebhookEvent, time.Now(), body)
+
+	failed := 0
+	for _, url := range s.cfg.WebhookURLs {
+		// Receivers deduplicate retries by X-Webhook-ID
+		_, err := s.client.Do(ctx, &httpclient.Request{
+			Method:     http.MethodPost,
+			Path:       url,
+			Header:     header,
+			Body:       body,
+			Idempotent: true,
+		})
+		if err != nil {
+			failed++
+			s.log.WithError(err).WithField("webhook", url).Warn("content published webhook failed")
+		}
+	}
+	if failed > 0 {
+		return errors.ServiceUnavailable(fmt.Sprintf("%d of %d content published webhooks failed", failed, len(s.cfg.WebhookURLs)))
+	}
+
+	s.log.WithFields(logger.Fields{
+		"notification_id": notification.ID,
+		"entities":        len(notification.Entities),
+		"urls":            len(notification.URLs),
+	}).Debug("Content published notification sent")
+	return nil
+}
+
+// productPaths adds the product page and the listings of its categories
+func (s *contentPublishService) productPaths(ctx context.Context, productID int64, paths map[string]bool) error {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	addPath(paths, product.URL)
+	addPath(paths, product.CanonicalURL)
+
+	categoryIDs := make(map[int64]bool)
+	if product.DefaultCategoryID != nil {
+		categoryIDs[*product.DefaultCategoryID] = true
+	}
+	xrefs, err := s.xrefRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to find product categories: %w", err)
+	}
+	for _, xref := range xrefs {
+		categoryIDs[xref.CategoryID] = true
+	}
+	for categoryID := range categoryIDs {
+		category, err := s.categoryRepo.FindByID(ctx, categoryID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		addPath(paths, category.URL)
+	}
+	return nil
+}
+
+// categoryPaths adds the category listing, the listings of its parents showing
+// its subcategories and the pages showing the navigation tree
+func (s *contentPublishService) categoryPaths(ctx context.Context, categoryID int64, paths map[string]bool) error {
+	categories, err := s.categoryRepo.GetCategoryPath(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	for _, category := range categories {
+		addPath(paths, category.URL)
+	}
+	for _, path := range s.cfg.GlobalPaths {
+		addPath(paths, path)
+	}
+	return nil
+}
+
+// skuPaths adds the pages of the products selling the SKU
+func (s *contentPublishService) skuPaths(ctx context.Context, skuID int64, paths map[string]bool) error {
+	sku, err := s.skuRepo.FindByID(ctx, skuID)
+	if err != nil {
+		return err
+	}
+	for _, productID := range []*int64{sku.DefaultProductID, sku.AdditionalProductID} {
+		if productID == nil {
+			continue
+		}
+		if err := s.productPaths(ctx, *productID, paths); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *contentPublishService) enabled() bool {
+	return len(s.cfg.WebhookURLs) > 0 || s.cfg.Events
+}
+
+// absolute prefixes a storefront path with the base URL; absolute URLs, e.g.
+// canonical URLs on another host, are kept
+func (s *contentPublishService) absolute(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") || s.cfg.BaseURL == "" {
+		return path
+	}
+	return strings.TrimRight(s.cfg.BaseURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+func (s *contentPublishService) absoluteAll(paths map[string]bool) []string {
+	urls := make(map[string]bool, len(paths))
+	for path := range paths {
+		urls[s.absolute(path)] = true
+	}
+	return sortedKeys(urls)
+}
+
+func addPath(paths map[string]bool, path string) {
+	if path = strings.TrimSpace(path); path != "" {
+		paths[path] = true
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newContentPublished(entities []domain.PublishedEntity, urls []string) *ContentPublishedDTO {
+	if entities == nil {
+		entities = []domain.PublishedEntity{}
+	}
+	return &ContentPublishedDTO{
+		Event:       ContentPublishedWebhookEvent,
+		ID:          uuid.New().String(),
+		PublishedAt: time.Now().UTC(),
+		Entities:    entities,
+		URLs:        urls,
+	}
+}
+
+func productEntity(id int64) domain.PublishedEntity {
+	return domain.PublishedEntity{Type: domain.PublishedProduct, ID: strconv.FormatInt(id, 10)}
+}
+
+func categoryEntity(id int64) domain.PublishedEntity {
+	return domain.PublishedEntity{Type: domain.PublishedCategory, ID: strconv.FormatInt(id, 10)}
+}
+
+func skuEntity(id int64) domain.PublishedEntity {
+	return domain.PublishedEntity{Type: domain.PublishedSKU, ID: strconv.FormatInt(id, 10)}
+}
@@ -0,0 +1,158 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+const (
+	defaultQualityReportLimit = 500
+	maxQualityReportLimit     = 5000
+)
+
+// ProductQualityDTO is the completeness score of a product
+type ProductQualityDTO struct {
+	ProductID   int64                   `json:"product_id"`
+	Name        string                  `json:"name,omitempty"`
+	Score       int                     `json:"score"`       // Percentage of the rule weight passed
+	Publishable bool                    `json:"publishable"` // No blocking check fails
+	Failures    []domain.QualityFailure `json:"failures,omitempty"`
+}
+
+// QualityReportDTO lists the products awaiting publication that blocking checks hold back
+type QualityReportDTO struct {
+	GeneratedAt     time.Time                   `json:"generated_at"`
+	Evaluated       int                         `json:"evaluated"` // Products awaiting publication scored
+	Blocked         int                         `json:"blocked"`
+	AverageScore    float64                     `json:"average_score"`
+	FailuresByCheck map[domain.QualityCheck]int `json:"failures_by_check"` // Across all evaluated products
+	Rules           []domain.QualityRule        `json:"rules"`
+	Items           []*ProductQualityDTO        `json:"items"` // Blocked products, lowest score first
+}
+
+// DataQualityService scores products for completeness against the configured rules.
+type DataQualityService interface {
+	// Rules returns the rules products are scored against.
+	Rules() []domain.QualityRule
+
+	// ProductQuality scores a product.
+	ProductQuality(ctx context.Context, productID int64) (*ProductQualityDTO, error)
+
+	// ProductQualityScores scores products, keyed by product ID.
+	ProductQualityScores(ctx context.Context, productIDs []int64) (map[int64]*ProductQualityDTO, error)
+
+	// PublicationReport scores the products awaiting publication and lists those blocked.
+	PublicationReport(ctx context.Context, limit int) (*QualityReportDTO, error)
+
+	// CheckPublishable rejects a product failing a blocking check.
+	CheckPublishable(ctx context.Context, productID int64) error
+}
+
+type dataQualityService struct {
+	repo  domain.ProductQualityRepository
+	rules domain.QualityRules
+}
+
+// NewDataQualityService creates a new instance of DataQualityService.
+// rules are expected to pass Validate.
+func NewDataQualityService(repo domain.ProductQualityRepository, rules domain.QualityRules) DataQualityService {
+	return &dataQualityService{repo: repo, rules: rules}
+}
+
+func (s *dataQualityService) Rules() []domain.QualityRule {
+	return s.rules.Rules
+}
+
+func (s *dataQualityService) ProductQuality(ctx context.Context, productID int64) (*ProductQualityDTO, error) {
+	scores, err := s.ProductQualityScores(ctx, []int64{productID})
+	if err != nil {
+		return nil, err
+	}
+	quality, ok := scores[productID]
+	if !ok {
+		return nil, errors.NotFound("product")
+	}
+	return quality, nil
+}
+
+func (s *dataQualityService) ProductQualityScores(ctx context.Context, productIDs []int64) (map[int64]*ProductQualityDTO, error) {
+	inputs, err := s.repo.FindInputs(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product quality inputs: %w", err)
+	}
+
+	scores := make(map[int64]*ProductQualityDTO, len(inputs))
+	for productID, in := range inputs {
+		scores[productID] = ToProductQualityDTO(in, s.rules.Evaluate(in))
+	}
+	return scores, nil
+}
+
+func (s *dataQualityService) PublicationReport(ctx context.Context, limit int) (*QualityReportDTO, error) {
+	if limit <= 0 || limit > maxQualityReportLimit {
+		limit = defaultQualityReportLimit
+	}
+	inputs, err := s.repo.FindAwaitingPublication(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load products awaiting publication: %w", err)
+	}
+
+	report := &QualityReportDTO{
+		GeneratedAt:     time.Now(),
+		Evaluated:       len(inputs),
+		FailuresByCheck: make(map[domain.QualityCheck]int),
+		Rules:           s.rules.Rules,
+		Items:           make([]*ProductQualityDTO, 0),
+	}
+	total := 0
+	for _, in := range inputs {
+		score := s.rules.Evaluate(in)
+		total += score.Score
+		for _, failure := range score.Failures {
+			report.FailuresByCheck[failure.Check]++
+		}
+		if !score.IsPublishable() {
+			report.Items = append(report.Items, ToProductQualityDTO(in, score))
+		}
+	}
+	if len(inputs) > 0 {
+		report.AverageScore = float64(total) / float64(len(inputs))
+	}
+	report.Blocked = len(report.Items)
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		return report.Items[i].Score < report.Items[j].Score
+	})
+	return report, nil
+}
+
+func (s *dataQualityService) CheckPublishable(ctx context.Context, productID int64) error {
+	inputs, err := s.repo.FindInputs(ctx, []int64{productID})
+	if err != nil {
+		return fmt.Errorf("failed to load product quality inputs: %w", err)
+	}
+	in, ok := inputs[productID]
+	if !ok {
+		return errors.NotFound("product")
+	}
+
+	if blocking := s.rules.Evaluate(in).BlockingFailures(); len(blocking) > 0 {
+		return errors.Conflict("product fails quality checks required for publication").WithDetail("failures", blocking)
+	}
+	return nil
+}
+
+// ToProductQualityDTO converts a product completeness score to a DTO
+func ToProductQualityDTO(in *domain.ProductQualityInput, score *domain.QualityScore) *ProductQualityDTO {
+	return &ProductQualityDTO{
+		ProductID:   in.ProductID,
+		Name:        in.Name,
+		Score:       score.Score,
+		Publishable: score.IsPublishable(),
+		Failures:    score.Failures,
+	}
+}
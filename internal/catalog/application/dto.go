@@ -4,28 +4,79 @@ import (
 	"time"
 
 	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/money"
 )
 
 // ProductDTO represents a product data transfer object
 type ProductDTO struct {
-	ID                    int64             `json:"id"`
-	Archived              bool              `json:"archived"`
-	CanSellWithoutOptions bool              `json:"can_sell_without_options"`
-	CanonicalURL          string            `json:"canonical_url,omitempty"`
-	DisplayTemplate       string            `json:"display_template,omitempty"`
-	EnableDefaultSKU      bool              `json:"enable_default_sku"`
-	Manufacture           string            `json:"manufacture"`
-	MetaDescription       string            `json:"meta_description,omitempty"`
-	MetaTitle             string            `json:"meta_title,omitempty"`
-	Model                 string            `json:"model"`
-	OverrideGeneratedURL  bool              `json:"override_generated_url"`
-	URL                   string            `json:"url"`
-	URLKey                string            `json:"url_key"`
-	DefaultCategoryID     *int64            `json:"default_category_id,omitempty"`
-	DefaultSKUID          *int64            `json:"default_sku_id,omitempty"`
-	Attributes            map[string]string `json:"attributes,omitempty"`
-	CreatedAt             time.Time         `json:"created_at"`
-	UpdatedAt             time.Time         `json:"updated_at"`
+	ID                    int64              `json:"id"`
+	Archived              bool               `json:"archived"`
+	CanSellWithoutOptions bool               `json:"can_sell_without_options"`
+	CanonicalURL          string             `json:"canonical_url,omitempty"`
+	DisplayTemplate       string             `json:"display_template,omitempty"`
+	EnableDefaultSKU      bool               `json:"enable_default_sku"`
+	Manufacture           string             `json:"manufacture"`
+	MetaDescription       string             `json:"meta_description,omitempty"`
+	MetaTitle             string             `json:"meta_title,omitempty"`
+	Model                 string             `json:"model"`
+	OverrideGeneratedURL  bool               `json:"override_generated_url"`
+	URL                   string             `json:"url"`
+	URLKey                string             `json:"url_key"`
+	DefaultCategoryID     *int64             `json:"default_category_id,omitempty"`
+	DefaultSKUID          *int64             `json:"default_sku_id,omitempty"`
+	ActiveStartDate       *time.Time         `json:"active_start_date,omitempty"`
+	ActiveEndDate         *time.Time         `json:"active_end_date,omitempty"`
+	IsActive              bool               `json:"is_active"`
+	InStock               bool               `json:"in_stock"`
+	PublishStatus         string             `json:"publish_status,omitempty"`
+	PublishedAt           *time.Time         `json:"published_at,omitempty"`
+	PublishedBy           string             `json:"published_by,omitempty"`
+	Badges                []BadgeDTO         `json:"badges,omitempty"`
+	Deal                  *DealDTO           `json:"deal,omitempty"`    // Live sale event, with its countdown
+	Quality               *ProductQualityDTO `json:"quality,omitempty"` // Completeness score, shown to admins
+	Attributes            map[string]string  `json:"attributes,omitempty"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	Links                 Links              `json:"_links,omitempty"`
+}
+
+// ProductDraftDTO represents edits of a product awaiting approval
+type ProductDraftDTO struct {
+	ProductID   int64                  `json:"product_id"`
+	Status      string                 `json:"status"`
+	Product     *ProductDTO            `json:"product"`
+	Attributes  map[string]string      `json:"attributes,omitempty"`
+	CategoryIDs []int64                `json:"category_ids,omitempty"`
+	Changes     map[string]interface{} `json:"changes,omitempty"`
+	EditedBy    string                 `json:"edited_by,omitempty"`
+	ReviewedBy  string                 `json:"reviewed_by,omitempty"`
+	ReviewNote  string                 `json:"review_note,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// ProductVersionsDTO shows the published version of a product next to the
+// version awaiting approval; either is omitted when the product has none
+type ProductVersionsDTO struct {
+	ProductID     int64            `json:"product_id"`
+	PublishStatus string           `json:"publish_status"`
+	Published     *ProductDTO      `json:"published,omitempty"`
+	Draft         *ProductDraftDTO `json:"draft,omitempty"`
+}
+
+// LinkDTO represents a hypermedia link to a related resource or action
+type LinkDTO struct {
+	Href   string `json:"href"`
+	Method string `json:"method,omitempty"` // Omitted for GET
+}
+
+// Links maps link relations (self, category, skus, add_to_cart...) to links
+type Links map[string]LinkDTO
+
+// BadgeDTO represents a badge shown on a product, e.g. New, Sale or a campaign badge
+type BadgeDTO struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
 }
 
 // ProductAttributeDTO represents a product attribute data transfer object.
@@ -70,6 +121,16 @@ type CategoryDTO struct {
 	IsActive                bool              `json:"is_active"`
 	CreatedAt               time.Time         `json:"created_at"`
 	UpdatedAt               time.Time         `json:"updated_at"`
+	Links                   Links             `json:"_links,omitempty"`
+}
+
+// NavigationNodeDTO represents a category in the storefront navigation tree
+type NavigationNodeDTO struct {
+	ID       int64                `json:"id"`
+	Name     string               `json:"name"`
+	URL      string               `json:"url"`
+	URLKey   string               `json:"url_key"`
+	Children []*NavigationNodeDTO `json:"children,omitempty"`
 }
 
 // CategoryAttributeDTO represents a category attribute data transfer object
@@ -117,6 +178,7 @@ type SkuDTO struct {
 	RetailPrice            float64           `json:"retail_price"`
 	SalePrice              float64           `json:"sale_price,omitempty"`
 	EffectivePrice         float64           `json:"effective_price"`
+	DisplayPrice           string            `json:"display_price"`
 	Taxable                bool              `json:"taxable"`
 	TaxCode                string            `json:"tax_code,omitempty"`
 	UPC                    string            `json:"upc,omitempty"`
@@ -127,9 +189,11 @@ type SkuDTO struct {
 	DefaultProductID       *int64            `json:"default_product_id,omitempty"`
 	AdditionalProductID    *int64            `json:"additional_product_id,omitempty"`
 	Attributes             map[string]string `json:"attributes,omitempty"`
+	Deal                   *DealDTO          `json:"deal,omitempty"` // Live sale event, with its countdown
 	IsActive               bool              `json:"is_active"`
 	CreatedAt              time.Time         `json:"created_at"`
 	UpdatedAt              time.Time         `json:"updated_at"`
+	Links                  Links             `json:"_links,omitempty"`
 }
 
 // SkuAttributeDTO represents a SKU attribute data transfer object.
@@ -168,12 +232,45 @@ func ToProductDTO(product *domain.Product) *ProductDTO {
 		URLKey:                product.URLKey,
 		DefaultCategoryID:     product.DefaultCategoryID,
 		DefaultSKUID:          product.DefaultSkuID,
+		ActiveStartDate:       product.ActiveStartDate,
+		ActiveEndDate:         product.ActiveEndDate,
+		IsActive:              product.IsActive(),
+		InStock:               product.InStock,
+		PublishStatus:         string(product.PublishStatus),
+		PublishedAt:           product.PublishedAt,
+		PublishedBy:           product.PublishedBy,
 		Attributes:            attributes,
 		CreatedAt:             product.CreatedAt,
 		UpdatedAt:             product.UpdatedAt,
 	}
 }
 
+// ToProductDraftDTO converts a domain ProductDraft to ProductDraftDTO
+func ToProductDraftDTO(draft *domain.ProductDraft) *ProductDraftDTO {
+	return &ProductDraftDTO{
+		ProductID:   draft.ProductID,
+		Status:      string(draft.Status),
+		Product:     ToProductDTO(draft.Product),
+		Attributes:  draft.Attributes,
+		CategoryIDs: draft.CategoryIDs,
+		Changes:     draft.Changes,
+		EditedBy:    draft.EditedBy,
+		ReviewedBy:  draft.ReviewedBy,
+		ReviewNote:  draft.ReviewNote,
+		CreatedAt:   draft.CreatedAt,
+		UpdatedAt:   draft.UpdatedAt,
+	}
+}
+
+// ToBadgeDTOs converts the badges shown on a product to DTOs
+func ToBadgeDTOs(badges []domain.AppliedBadge) []BadgeDTO {
+	dtos := make([]BadgeDTO, len(badges))
+	for i, badge := range badges {
+		dtos[i] = BadgeDTO{Code: badge.Code, Label: badge.Label}
+	}
+	return dtos
+}
+
 // ToCategoryDTO converts a domain Category to CategoryDTO
 func ToCategoryDTO(category *domain.Category) *CategoryDTO {
 	// Attributes are fetched separately
@@ -208,14 +305,19 @@ func ToCategoryDTO(category *domain.Category) *CategoryDTO {
 	}
 }
 
+// LocalizePrice formats the display price for a locale
+func (d *SkuDTO) LocalizePrice(locale string) {
+	d.DisplayPrice = money.Format(d.EffectivePrice, d.CurrencyCode, locale)
+}
+
 // ToSkuDTO converts a domain SKU to SkuDTO
 func ToSkuDTO(sku *domain.SKU) *SkuDTO {
 	// Attributes are fetched separately
 	var attributes map[string]string
 
-	effectivePrice := sku.RetailPrice
+	effectivePrice := money.Round(sku.RetailPrice, sku.CurrencyCode)
 	if sku.SalePrice > 0 {
-		effectivePrice = sku.SalePrice
+		effectivePrice = money.Round(sku.SalePrice, sku.CurrencyCode)
 	}
 
 	return &SkuDTO{
@@ -241,10 +343,11 @@ func ToSkuDTO(sku *domain.SKU) *SkuDTO {
 		InventoryType:          sku.InventoryType,
 		IsMachineSortable:      sku.IsMachineSortable,
 		// OverrideGeneratedURL:   sku.OverrideGeneratedURL, // Does not exist on SKU
-		Price:                  sku.RetailPrice,
-		RetailPrice:            sku.RetailPrice,
-		SalePrice:              sku.SalePrice,
+		Price:                  money.Round(sku.RetailPrice, sku.CurrencyCode),
+		RetailPrice:            money.Round(sku.RetailPrice, sku.CurrencyCode),
+		SalePrice:              money.Round(sku.SalePrice, sku.CurrencyCode),
 		EffectivePrice:         effectivePrice,
+		DisplayPrice:           money.Format(effectivePrice, sku.CurrencyCode, money.DefaultLocale()),
 		Taxable:                sku.Taxable,
 		TaxCode:                sku.TaxCode,
 		UPC:                    sku.UPC,
@@ -317,6 +420,7 @@ type PaginatedResponse struct {
 	PageSize   int         `json:"page_size"`
 	TotalItems int64       `json:"total_items"`
 	TotalPages int64       `json:"total_pages"`
+	Links      Links       `json:"_links,omitempty"`
 }
 
 // NewPaginatedResponse creates a new paginated response
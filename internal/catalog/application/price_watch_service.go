@@ -0,0 +1,496 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/notification"
+	"github.com/qhato/ecommerce/pkg/schedule"
+)
+
+const (
+	dueChangesBatch        = 100 // Scheduled price changes applied per run
+	defaultSKUWatchesLimit = 50
+	maxSKUWatchesLimit     = 500
+)
+
+// PriceWatchDTO represents a customer's price-drop alert on a SKU
+type PriceWatchDTO struct {
+	ID               int64                   `json:"id"`
+	SKUID            int64                   `json:"sku_id"`
+	CustomerID       int64                   `json:"customer_id"`
+	WatchedPrice     float64                 `json:"watched_price"`
+	Status           domain.PriceWatchStatus `json:"status"`
+	NotifiedPrice    *float64                `json:"notified_price,omitempty"`
+	NotifiedAt       *time.Time              `json:"notified_at,omitempty"`
+	ConvertedOrderID *int64                  `json:"converted_order_id,omitempty"`
+	ConvertedAt      *time.Time              `json:"converted_at,omitempty"`
+	CreatedAt        time.Time               `json:"created_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+}
+
+// WatchPriceRequest is the payload to watch the price of a SKU
+type WatchPriceRequest struct {
+	SKUID        int64    `json:"sku_id"`
+	WatchedPrice *float64 `json:"watched_price"` // Defaults to the current effective price, alerting on any drop
+}
+
+// SKUPriceWatchesDTO lists the watches of a SKU with how they turned out
+type SKUPriceWatchesDTO struct {
+	SKUID          int64                    `json:"sku_id"`
+	EffectivePrice float64                  `json:"effective_price"`
+	Summary        domain.PriceWatchSummary `json:"summary"`
+	Watches        []*PriceWatchDTO         `json:"watches"`
+}
+
+// ScheduledPriceChangeDTO represents SKU prices applied at a future time
+type ScheduledPriceChangeDTO struct {
+	ID          int64      `json:"id"`
+	SKUID       int64      `json:"sku_id"`
+	RetailPrice float64    `json:"retail_price"`
+	SalePrice   float64    `json:"sale_price"`
+	EffectiveAt time.Time  `json:"effective_at"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// SchedulePriceChangeRequest is the payload to schedule new prices of a SKU
+type SchedulePriceChangeRequest struct {
+	RetailPrice float64        `json:"retail_price"`
+	SalePrice   float64        `json:"sale_price"`   // 0 clears the sale price
+	EffectiveAt *schedule.Time `json:"effective_at"` // Dates without an offset are in the site's time zone
+}
+
+// PriceWatchConfig configures price-drop alerts
+type PriceWatchConfig struct {
+	Attribution time.Duration // Orders with the SKU placed this long after an alert count as converted
+}
+
+// PriceWatchNotifier sends price-drop alerts to customers by email and to their storefront inbox
+type PriceWatchNotifier interface {
+	SendEmail(ctx context.Context, to, subject, body string) error
+	SendInApp(ctx context.Context, customerID int64, category, subject, body, link string) error
+}
+
+// PriceWatchService manages price-drop alerts and the scheduled price changes that may trigger them.
+type PriceWatchService interface {
+	// Watch subscribes a customer to drops of a SKU's effective price, restarting an existing watch.
+	Watch(ctx context.Context, customerID int64, req *WatchPriceRequest) (*PriceWatchDTO, error)
+
+	// ListCustomerWatches lists the watches of a customer that are not cancelled.
+	ListCustomerWatches(ctx context.Context, customerID int64) ([]*PriceWatchDTO, error)
+
+	// CancelWatch stops a watch of a customer.
+	CancelWatch(ctx context.Context, customerID, id int64) error
+
+	// ListSKUWatches lists the watches of a SKU with their counts by status.
+	ListSKUWatches(ctx context.Context, skuID int64, limit int) (*SKUPriceWatchesDTO, error)
+
+	// SchedulePriceChange schedules new prices of a SKU.
+	SchedulePriceChange(ctx context.Context, skuID int64, req *SchedulePriceChangeRequest, createdBy string) (*ScheduledPriceChangeDTO, error)
+
+	// ListScheduledPriceChanges lists the scheduled price changes of a SKU, applied or not.
+	ListScheduledPriceChanges(ctx context.Context, skuID int64) ([]*ScheduledPriceChangeDTO, error)
+
+	// CancelScheduledPriceChange removes a price change that was not applied yet.
+	CancelScheduledPriceChange(ctx context.Context, id int64) error
+
+	// ApplyDuePriceChanges applies the scheduled price changes that are due, returning how many.
+	ApplyDuePriceChanges(ctx context.Context) (int, error)
+
+	// NotifyPriceDrop alerts the customers watching a SKU above its new effective price.
+	NotifyPriceDrop(ctx context.Context, skuID int64, effectivePrice float64) (int, error)
+
+	// AttributeConversions records the orders placed by alerted customers, returning how many.
+	AttributeConversions(ctx context.Context) (int64, error)
+
+	// Subscribe alerts watchers when SKU price changes lower the effective price.
+	Subscribe(bus event.Bus) error
+
+	// StartScheduler applies due price changes and attributes conversions periodically until ctx is cancelled.
+	StartScheduler(ctx context.Context, interval time.Duration)
+}
+
+type priceWatchService struct {
+	skuRepo    domain.SKURepository
+	watchRepo  domain.PriceWatchRepository
+	changeRepo domain.ScheduledPriceChangeRepository
+	eventBus   event.Bus
+	notifier   PriceWatchNotifier
+	cfg        PriceWatchConfig
+	log        *logger.Logger
+
+	// runMu prevents overlapping scheduler runs
+	runMu sync.Mutex
+}
+
+// NewPriceWatchService creates a new instance of PriceWatchService.
+// notifier may be nil, leaving triggered watches active until one is configured.
+func NewPriceWatchService(
+	skuRepo domain.SKURepository,
+	watchRepo domain.PriceWatchRepository,
+	changeRepo domain.ScheduledPriceChangeRepository,
+	eventBus event.Bus,
+	notifier PriceWatchNotifier,
+	cfg PriceWatchConfig,
+	log *logger.Logger,
+) PriceWatchService {
+	return &priceWatchService{
+		skuRepo:    skuRepo,
+		watchRepo:  watchRepo,
+		changeRepo: changeRepo,
+		eventBus:   eventBus,
+		notifier:   notifier,
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+func (s *priceWatchService) Watch(ctx context.Context, customerID int64, req *WatchPriceRequest) (*PriceWatchDTO, error) {
+	sku, err := s.skuRepo.FindByID(ctx, req.SKUID)
+	if err != nil {
+		return nil, err
+	}
+	if !sku.IsActive() {
+		return nil, errors.ValidationError("SKU is not for sale")
+	}
+	watchedPrice := sku.EffectivePrice()
+	if req.WatchedPrice != nil {
+		watchedPrice = *req.WatchedPrice
+	}
+
+	watch, err := s.watchRepo.FindByCustomerAndSKU(ctx, customerID, sku.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find price watch: %w", err)
+	}
+	if watch == nil {
+		watch, err = domain.NewPriceWatch(sku.ID, customerID, watchedPrice)
+	} else {
+		err = watch.Rewatch(watchedPrice)
+	}
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if err := s.watchRepo.Save(ctx, watch); err != nil {
+		return nil, fmt.Errorf("failed to save price watch: %w", err)
+	}
+	return ToPriceWatchDTO(watch), nil
+}
+
+func (s *priceWatchService) ListCustomerWatches(ctx context.Context, customerID int64) ([]*PriceWatchDTO, error) {
+	watches, err := s.watchRepo.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price watches: %w", err)
+	}
+	return toPriceWatchDTOs(watches), nil
+}
+
+func (s *priceWatchService) CancelWatch(ctx context.Context, customerID, id int64) error {
+	watch, err := s.watchRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// Another customer's watch is reported as missing rather than forbidden
+	if watch.CustomerID != customerID {
+		return errors.NotFound("price watch")
+	}
+
+	watch.Cancel()
+	if err := s.watchRepo.Save(ctx, watch); err != nil {
+		return fmt.Errorf("failed to cancel price watch: %w", err)
+	}
+	return nil
+}
+
+func (s *priceWatchService) ListSKUWatches(ctx context.Context, skuID int64, limit int) (*SKUPriceWatchesDTO, error) {
+	if limit <= 0 || limit > maxSKUWatchesLimit {
+		limit = defaultSKUWatchesLimit
+	}
+	sku, err := s.skuRepo.FindByID(ctx, skuID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.watchRepo.SummarizeSKU(ctx, skuID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize price watches: %w", err)
+	}
+	watches, err := s.watchRepo.FindBySKUID(ctx, skuID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price watches: %w", err)
+	}
+
+	return &SKUPriceWatchesDTO{
+		SKUID:          sku.ID,
+		EffectivePrice: sku.EffectivePrice(),
+		Summary:        *summary,
+		Watches:        toPriceWatchDTOs(watches),
+	}, nil
+}
+
+func (s *priceWatchService) SchedulePriceChange(ctx context.Context, skuID int64, req *SchedulePriceChangeRequest, createdBy string) (*ScheduledPriceChangeDTO, error) {
+	if _, err := s.skuRepo.FindByID(ctx, skuID); err != nil {
+		return nil, err
+	}
+	if req.EffectiveAt == nil {
+		return nil, errors.ValidationError("effective date is required")
+	}
+	effectiveAt := req.EffectiveAt.Start(schedule.Location(ctx))
+	if !effectiveAt.After(time.Now()) {
+		return nil, errors.ValidationError("effective date must be in the future")
+	}
+
+	change, err := domain.NewScheduledPriceChange(skuID, req.RetailPrice, req.SalePrice, effectiveAt, createdBy)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.changeRepo.Save(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to schedule price change: %w", err)
+	}
+	return ToScheduledPriceChangeDTO(change), nil
+}
+
+func (s *priceWatchService) ListScheduledPriceChanges(ctx context.Context, skuID int64) ([]*ScheduledPriceChangeDTO, error) {
+	changes, err := s.changeRepo.FindBySKUID(ctx, skuID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled price changes: %w", err)
+	}
+
+	dtos := make([]*ScheduledPriceChangeDTO, len(changes))
+	for i, change := range changes {
+		dtos[i] = ToScheduledPriceChangeDTO(change)
+	}
+	return dtos, nil
+}
+
+func (s *priceWatchService) CancelScheduledPriceChange(ctx context.Context, id int64) error {
+	change, err := s.changeRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if change.IsApplied() {
+		return errors.Conflict("scheduled price change was already applied")
+	}
+	return s.changeRepo.Delete(ctx, id)
+}
+
+func (s *priceWatchService) ApplyDuePriceChanges(ctx context.Context) (int, error) {
+	changes, err := s.changeRepo.FindDue(ctx, time.Now(), dueChangesBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find due price changes: %w", err)
+	}
+
+	applied := 0
+	for _, change := range changes {
+		if err := s.applyPriceChange(ctx, change); err != nil {
+			s.log.WithError(err).WithFields(logger.Fields{
+				"scheduled_price_change_id": change.ID,
+				"sku_id":                    change.SKUID,
+			}).Error("failed to apply scheduled price change")
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// applyPriceChange updates the SKU prices and publishes the change like a manual edit,
+// so watchers are alerted the same way
+func (s *priceWatchService) applyPriceChange(ctx context.Context, change *domain.ScheduledPriceChange) error {
+	sku, err := s.skuRepo.FindByID(ctx, change.SKUID)
+	if err != nil {
+		return err
+	}
+	oldPrice, oldEffectivePrice := sku.RetailPrice, sku.EffectivePrice()
+	sku.UpdatePricing(change.RetailPrice, change.SalePrice)
+	if err := s.skuRepo.Update(ctx, sku); err != nil {
+		return fmt.Errorf("failed to update SKU pricing: %w", err)
+	}
+
+	change.MarkApplied()
+	if err := s.changeRepo.Save(ctx, change); err != nil {
+		return fmt.Errorf("failed to mark price change applied: %w", err)
+	}
+
+	if sku.RetailPrice != oldPrice || sku.EffectivePrice() != oldEffectivePrice {
+		evt := domain.NewSKUPriceChangedEvent(sku.ID, oldPrice, sku.RetailPrice, oldEffectivePrice, sku.EffectivePrice())
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			s.log.WithError(err).WithField("sku_id", sku.ID).Error("failed to publish SKU price changed event")
+		}
+	}
+	return nil
+}
+
+func (s *priceWatchService) NotifyPriceDrop(ctx context.Context, skuID int64, effectivePrice float64) (int, error) {
+	if s.notifier == nil {
+		return 0, nil
+	}
+	triggered, err := s.watchRepo.FindTriggered(ctx, skuID, effectivePrice)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find triggered price watches: %w", err)
+	}
+	if len(triggered) == 0 {
+		return 0, nil
+	}
+	sku, err := s.skuRepo.FindByID(ctx, skuID)
+	if err != nil {
+		return 0, err
+	}
+
+	notified := 0
+	for _, t := range triggered {
+		subject := fmt.Sprintf("Price drop: %s", sku.Name)
+		body := fmt.Sprintf("%s is now %.2f, below the %.2f you were waiting for.",
+			sku.Name, effectivePrice, t.Watch.WatchedPrice)
+
+		// The inbox copy also reaches customers without an email address; the
+		// watch counts as notified once either channel delivered the alert
+		delivered := false
+		link := fmt.Sprintf("/catalog/skus/%d", sku.ID)
+		if err := s.notifier.SendInApp(ctx, t.Watch.CustomerID, notification.CategoryPromotion, subject, body, link); err != nil {
+			s.log.WithError(err).WithField("price_watch_id", t.Watch.ID).Warn("Failed to deliver in-app price-drop alert")
+		} else {
+			delivered = true
+		}
+		if t.Email != "" {
+			emailBody := body
+			if t.Name != "" {
+				emailBody = fmt.Sprintf("Hi %s,\n\n%s", t.Name, body)
+			}
+			if err := s.notifier.SendEmail(ctx, t.Email, subject, emailBody); err != nil {
+				s.log.WithError(err).WithField("price_watch_id", t.Watch.ID).Warn("Failed to email price-drop alert")
+			} else {
+				delivered = true
+			}
+		}
+		if !delivered {
+			// The watch stays active so the next drop alerts the customer again
+			continue
+		}
+
+		t.Watch.MarkNotified(effectivePrice)
+		if err := s.watchRepo.Save(ctx, t.Watch); err != nil {
+			s.log.WithError(err).WithField("price_watch_id", t.Watch.ID).Error("failed to mark price watch notified")
+			continue
+		}
+		notified++
+	}
+	return notified, nil
+}
+
+func (s *priceWatchService) AttributeConversions(ctx context.Context) (int64, error) {
+	if s.cfg.Attribution <= 0 {
+		return 0, nil
+	}
+	converted, err := s.watchRepo.AttributeConversions(ctx, s.cfg.Attribution)
+	if err != nil {
+		return 0, fmt.Errorf("failed to attribute price watch conversions: %w", err)
+	}
+	return converted, nil
+}
+
+// Subscribe alerts watchers of SKUs whose effective price dropped, from manual
+// edits, catalog snapshots and scheduled changes alike
+func (s *priceWatchService) Subscribe(bus event.Bus) error {
+	return bus.Subscribe(domain.EventSKUPriceChanged, s.handlePriceChanged)
+}
+
+func (s *priceWatchService) handlePriceChanged(ctx context.Context, evt event.Event) error {
+	e, ok := evt.(*domain.SKUPriceChangedEvent)
+	if !ok || e.NewEffectivePrice >= e.OldEffectivePrice {
+		return nil
+	}
+	notified, err := s.NotifyPriceDrop(ctx, e.SKUID, e.NewEffectivePrice)
+	if err != nil {
+		return err
+	}
+	if notified > 0 {
+		s.log.WithFields(logger.Fields{
+			"sku_id":   e.SKUID,
+			"notified": notified,
+		}).Info("Price-drop alerts sent")
+	}
+	return nil
+}
+
+func (s *priceWatchService) StartScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runScheduled(ctx)
+			}
+		}
+	}()
+}
+
+func (s *priceWatchService) runScheduled(ctx context.Context) {
+	if !s.runMu.TryLock() {
+		return
+	}
+	defer s.runMu.Unlock()
+
+	if applied, err := s.ApplyDuePriceChanges(ctx); err != nil {
+		s.log.WithError(err).Warn("Scheduled price changes failed")
+	} else if applied > 0 {
+		s.log.WithField("applied", applied).Info("Scheduled price changes applied")
+	}
+	if converted, err := s.AttributeConversions(ctx); err != nil {
+		s.log.WithError(err).Warn("Price watch conversion attribution failed")
+	} else if converted > 0 {
+		s.log.WithField("converted", converted).Debug("Price watch conversions attributed")
+	}
+}
+
+// ToPriceWatchDTO converts a domain price watch to a DTO
+func ToPriceWatchDTO(watch *domain.PriceWatch) *PriceWatchDTO {
+	return &PriceWatchDTO{
+		ID:               watch.ID,
+		SKUID:            watch.SKUID,
+		CustomerID:       watch.CustomerID,
+		WatchedPrice:     watch.WatchedPrice,
+		Status:           watch.Status,
+		NotifiedPrice:    watch.NotifiedPrice,
+		NotifiedAt:       watch.NotifiedAt,
+		ConvertedOrderID: watch.ConvertedOrderID,
+		ConvertedAt:      watch.ConvertedAt,
+		CreatedAt:        watch.CreatedAt,
+		UpdatedAt:        watch.UpdatedAt,
+	}
+}
+
+func toPriceWatchDTOs(watches []*domain.PriceWatch) []*PriceWatchDTO {
+	dtos := make([]*PriceWatchDTO, len(watches))
+	for i, watch := range watches {
+		dtos[i] = ToPriceWatchDTO(watch)
+	}
+	return dtos
+}
+
+// ToScheduledPriceChangeDTO converts a domain scheduled price change to a DTO
+func ToScheduledPriceChangeDTO(change *domain.ScheduledPriceChange) *ScheduledPriceChangeDTO {
+	return &ScheduledPriceChangeDTO{
+		ID:          change.ID,
+		SKUID:       change.SKUID,
+		RetailPrice: change.RetailPrice,
+		SalePrice:   change.SalePrice,
+		EffectiveAt: change.EffectiveAt,
+		AppliedAt:   change.AppliedAt,
+		CreatedBy:   change.CreatedBy,
+		CreatedAt:   change.CreatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// ProductAvailabilityService maintains the product availability read model used by listings.
+type ProductAvailabilityService interface {
+	// RefreshAvailability rebuilds the read model from inventory levels.
+	RefreshAvailability(ctx context.Context) error
+
+	// StartScheduledRefresh refreshes the read model periodically until ctx is cancelled.
+	StartScheduledRefresh(ctx context.Context, interval time.Duration)
+}
+
+type productAvailabilityService struct {
+	repo domain.ProductAvailabilityRepository
+	log  *logger.Logger
+
+	// refreshMu prevents overlapping rebuilds
+	refreshMu sync.Mutex
+}
+
+// NewProductAvailabilityService creates a new instance of ProductAvailabilityService.
+func NewProductAvailabilityService(repo domain.ProductAvailabilityRepository, log *logger.Logger) ProductAvailabilityService {
+	return &productAvailabilityService{repo: repo, log: log}
+}
+
+func (s *productAvailabilityService) RefreshAvailability(ctx context.Context) error {
+	if !s.refreshMu.TryLock() {
+		return errors.Conflict("product availability refresh already in progress")
+	}
+	defer s.refreshMu.Unlock()
+
+	start := time.Now()
+	if err := s.repo.Refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh product availability: %w", err)
+	}
+	s.log.WithField("duration_ms", time.Since(start).Milliseconds()).Debug("Product availability refreshed")
+	return nil
+}
+
+func (s *productAvailabilityService) StartScheduledRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.RefreshAvailability(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled product availability refresh failed")
+				}
+			}
+		}
+	}()
+}
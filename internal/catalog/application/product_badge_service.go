@@ -0,0 +1,178 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/schedule"
+)
+
+// ProductBadgeDTO represents a manual product badge
+type ProductBadgeDTO struct {
+	ID        int64      `json:"id"`
+	ProductID int64      `json:"product_id"`
+	Code      string     `json:"code"`
+	Label     string     `json:"label"`
+	Campaign  string     `json:"campaign,omitempty"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	Active    bool       `json:"active"` // Shown now
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ProductBadgeRequest is the payload to create or update a manual product badge
+type ProductBadgeRequest struct {
+	Code     string         `json:"code" validate:"required"`
+	Label    string         `json:"label" validate:"required"`
+	Campaign string         `json:"campaign"`
+	StartsAt *schedule.Time `json:"starts_at"` // Dates without an offset are in the site's time zone
+	EndsAt   *schedule.Time `json:"ends_at"`   // Exclusive; a date alone ends at its midnight
+}
+
+// ProductBadgeService defines the application service for computed and manual product badges.
+type ProductBadgeService interface {
+	// CreateBadge adds a manual badge to a product.
+	CreateBadge(ctx context.Context, productID int64, req *ProductBadgeRequest) (*ProductBadgeDTO, error)
+
+	// UpdateBadge replaces a manual badge.
+	UpdateBadge(ctx context.Context, id int64, req *ProductBadgeRequest) (*ProductBadgeDTO, error)
+
+	// DeleteBadge removes a manual badge.
+	DeleteBadge(ctx context.Context, id int64) error
+
+	// ListBadges lists the manual badges of a product, scheduled or not.
+	ListBadges(ctx context.Context, productID int64) ([]*ProductBadgeDTO, error)
+
+	// ProductBadges returns the badges shown now on each product, keyed by product ID.
+	ProductBadges(ctx context.Context, productIDs []int64) (map[int64][]domain.AppliedBadge, error)
+
+	// BadgeCriteria returns the criteria restricting a listing to products showing a badge now.
+	BadgeCriteria(code string) *domain.BadgeCriteria
+}
+
+type productBadgeService struct {
+	repo        domain.ProductBadgeRepository
+	productRepo domain.ProductRepository
+	rules       domain.BadgeRules
+}
+
+// NewProductBadgeService creates a new instance of ProductBadgeService.
+func NewProductBadgeService(repo domain.ProductBadgeRepository, productRepo domain.ProductRepository, rules domain.BadgeRules) ProductBadgeService {
+	return &productBadgeService{
+		repo:        repo,
+		productRepo: productRepo,
+		rules:       rules,
+	}
+}
+
+func (s *productBadgeService) CreateBadge(ctx context.Context, productID int64, req *ProductBadgeRequest) (*ProductBadgeDTO, error) {
+	if _, err := s.productRepo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	badge, err := domain.NewProductBadge(productID, req.Code, req.Label)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	loc := schedule.Location(ctx)
+	if err := badge.Schedule(req.Campaign, schedule.StartOf(req.StartsAt, loc), schedule.StartOf(req.EndsAt, loc)); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if err := s.repo.Save(ctx, badge); err != nil {
+		return nil, fmt.Errorf("failed to create product badge: %w", err)
+	}
+	return ToProductBadgeDTO(badge, time.Now()), nil
+}
+
+func (s *productBadgeService) UpdateBadge(ctx context.Context, id int64, req *ProductBadgeRequest) (*ProductBadgeDTO, error) {
+	badge, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := badge.Update(req.Code, req.Label); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	loc := schedule.Location(ctx)
+	if err := badge.Schedule(req.Campaign, schedule.StartOf(req.StartsAt, loc), schedule.StartOf(req.EndsAt, loc)); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if err := s.repo.Save(ctx, badge); err != nil {
+		return nil, fmt.Errorf("failed to update product badge: %w", err)
+	}
+	return ToProductBadgeDTO(badge, time.Now()), nil
+}
+
+func (s *productBadgeService) DeleteBadge(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *productBadgeService) ListBadges(ctx context.Context, productID int64) ([]*ProductBadgeDTO, error) {
+	badges, err := s.repo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product badges: %w", err)
+	}
+
+	now := time.Now()
+	dtos := make([]*ProductBadgeDTO, len(badges))
+	for i, badge := range badges {
+		dtos[i] = ToProductBadgeDTO(badge, now)
+	}
+	return dtos, nil
+}
+
+func (s *productBadgeService) ProductBadges(ctx context.Context, productIDs []int64) (map[int64][]domain.AppliedBadge, error) {
+	applied := make(map[int64][]domain.AppliedBadge, len(productIDs))
+	if len(productIDs) == 0 {
+		return applied, nil
+	}
+
+	now := time.Now()
+	computed, err := s.repo.FindComputedBadges(ctx, productIDs, s.rules, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute product badges: %w", err)
+	}
+	manual, err := s.repo.FindActiveByProductIDs(ctx, productIDs, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find product badges: %w", err)
+	}
+
+	manualByProduct := make(map[int64][]*domain.ProductBadge)
+	for _, badge := range manual {
+		manualByProduct[badge.ProductID] = append(manualByProduct[badge.ProductID], badge)
+	}
+	for _, productID := range productIDs {
+		if badges := domain.ApplyBadges(computed[productID], manualByProduct[productID]); len(badges) > 0 {
+			applied[productID] = badges
+		}
+	}
+	return applied, nil
+}
+
+func (s *productBadgeService) BadgeCriteria(code string) *domain.BadgeCriteria {
+	return &domain.BadgeCriteria{
+		Code:  domain.NormalizeBadgeCode(code),
+		Rules: s.rules,
+		At:    time.Now(),
+	}
+}
+
+// ToProductBadgeDTO converts a domain product badge to a DTO
+func ToProductBadgeDTO(badge *domain.ProductBadge, now time.Time) *ProductBadgeDTO {
+	return &ProductBadgeDTO{
+		ID:        badge.ID,
+		ProductID: badge.ProductID,
+		Code:      badge.Code,
+		Label:     badge.Label,
+		Campaign:  badge.Campaign,
+		StartsAt:  badge.StartsAt,
+		EndsAt:    badge.EndsAt,
+		Active:    badge.IsActiveAt(now),
+		CreatedAt: badge.CreatedAt,
+		UpdatedAt: badge.UpdatedAt,
+	}
+}
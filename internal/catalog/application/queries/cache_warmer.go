@@ -0,0 +1,226 @@
+package queries
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/cache"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// CacheWarmConfig selects the catalog cache entries kept warm
+type CacheWarmConfig struct {
+	ProductIDs     []int64 // Products always warmed
+	CategoryIDs    []int64 // Categories always warmed
+	TopProducts    int     // Best-selling products warmed as well; 0 disables
+	TopCategories  int     // Best-selling categories warmed as well; 0 disables
+	PopularityDays int     // Order history ranking the best sellers
+	Navigation     bool    // Warm the storefront navigation tree
+}
+
+// CacheWarmer preloads the catalog entries most requests hit, so the first
+// visitors after a deploy or a catalog edit are not the ones paying for the
+// database round trips
+type CacheWarmer struct {
+	products   *ProductQueryHandler
+	categories *CategoryQueryHandler
+	popularity domain.CatalogPopularityRepository
+	cache      cache.Cache
+	cfg        CacheWarmConfig
+	logger     *logger.Logger
+
+	// Entries warmed by the last WarmAll; edits to them are re-warmed
+	mu          sync.RWMutex
+	productIDs  map[int64]bool
+	categoryIDs map[int64]bool
+}
+
+// NewCacheWarmer creates a new catalog cache warmer. popularity may be nil
+// when only the configured entries are warmed.
+func NewCacheWarmer(
+	products *ProductQueryHandler,
+	categories *CategoryQueryHandler,
+	popularity domain.CatalogPopularityRepository,
+	cache cache.Cache,
+	cfg CacheWarmConfig,
+	logger *logger.Logger,
+) *CacheWarmer {
+	return &CacheWarmer{
+		products:    products,
+		categories:  categories,
+		popularity:  popularity,
+		cache:       cache,
+		cfg:         cfg,
+		logger:      logger,
+		productIDs:  make(map[int64]bool),
+		categoryIDs: make(map[int64]bool),
+	}
+}
+
+// WarmAll loads the navigation tree, the configured categories and products,
+// and the current best sellers into the cache. Entries failing to load are
+// logged and skipped.
+func (w *CacheWarmer) WarmAll(ctx context.Context) {
+	start := time.Now()
+	productIDs, categoryIDs := w.selectEntries(ctx)
+
+	w.mu.Lock()
+	w.productIDs, w.categoryIDs = productIDs, categoryIDs
+	w.mu.Unlock()
+
+	if w.cfg.Navigation {
+		w.warmNavigation(ctx)
+	}
+	warmedCategories := 0
+	for id := range categoryIDs {
+		if w.warmCategory(ctx, id) {
+			warmedCategories++
+		}
+	}
+	warmedProducts := 0
+	for id := range productIDs {
+		if w.warmProduct(ctx, id) {
+			warmedProducts++
+		}
+	}
+
+	w.logger.WithFields(logger.Fields{
+		"categories":  warmedCategories,
+		"products":    warmedProducts,
+		"navigation":  w.cfg.Navigation,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}).Info("Catalog cache warmed")
+}
+
+// Subscribe drops the cached entries of edited products and categories and
+// re-warms the ones WarmAll selected, along with the navigation tree
+func (w *CacheWarmer) Subscribe(bus event.Bus) error {
+	handlers := map[string]event.Handler{
+		domain.EventProductUpdated:  w.handleProductEvent,
+		domain.EventProductArchived: w.handleProductEvent,
+		domain.EventCategoryCreated: w.handleCategoryEvent,
+		domain.EventCategoryUpdated: w.handleCategoryEvent,
+	}
+	for eventType, handler := range handlers {
+		if err := bus.Subscribe(eventType, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *CacheWarmer) handleProductEvent(ctx context.Context, evt event.Event) error {
+	var productID int64
+	archived := false
+	switch e := evt.(type) {
+	case *domain.ProductUpdatedEvent:
+		productID = e.ProductID
+	case *domain.ProductArchivedEvent:
+		productID, archived = e.ProductID, true
+	default:
+		return nil
+	}
+
+	if err := w.cache.Delete(ctx, productCacheKey(productID)); err != nil {
+		w.logger.WithError(err).WithField("product_id", productID).Warn("failed to invalidate cached product")
+	}
+	w.mu.RLock()
+	warm := w.productIDs[productID]
+	w.mu.RUnlock()
+	if warm && !archived {
+		w.warmProduct(ctx, productID)
+	}
+	return nil
+}
+
+func (w *CacheWarmer) handleCategoryEvent(ctx context.Context, evt event.Event) error {
+	var categoryID int64
+	switch e := evt.(type) {
+	case *domain.CategoryCreatedEvent:
+		categoryID = e.CategoryID
+	case *domain.CategoryUpdatedEvent:
+		categoryID = e.CategoryID
+	default:
+		return nil
+	}
+
+	if err := w.cache.Delete(ctx, categoryCacheKey(categoryID)); err != nil {
+		w.logger.WithError(err).WithField("category_id", categoryID).Warn("failed to invalidate cached category")
+	}
+	w.mu.RLock()
+	warm := w.categoryIDs[categoryID]
+	w.mu.RUnlock()
+	if warm {
+		w.warmCategory(ctx, categoryID)
+	}
+
+	// Names, parents and active dates all show in the navigation tree
+	if err := w.cache.Delete(ctx, navigationCacheKey); err != nil {
+		w.logger.WithError(err).Warn("failed to invalidate cached navigation tree")
+	}
+	if w.cfg.Navigation {
+		w.warmNavigation(ctx)
+	}
+	return nil
+}
+
+// selectEntries combines the configured entries with the current best sellers
+func (w *CacheWarmer) selectEntries(ctx context.Context) (map[int64]bool, map[int64]bool) {
+	productIDs := make(map[int64]bool, len(w.cfg.ProductIDs)+w.cfg.TopProducts)
+	for _, id := range w.cfg.ProductIDs {
+		productIDs[id] = true
+	}
+	categoryIDs := make(map[int64]bool, len(w.cfg.CategoryIDs)+w.cfg.TopCategories)
+	for _, id := range w.cfg.CategoryIDs {
+		categoryIDs[id] = true
+	}
+	if w.popularity == nil {
+		return productIDs, categoryIDs
+	}
+
+	since := time.Now().AddDate(0, 0, -w.cfg.PopularityDays)
+	if w.cfg.TopProducts > 0 {
+		ids, err := w.popularity.TopProductIDs(ctx, since, w.cfg.TopProducts)
+		if err != nil {
+			w.logger.WithError(err).Warn("failed to rank products for cache warming")
+		}
+		for _, id := range ids {
+			productIDs[id] = true
+		}
+	}
+	if w.cfg.TopCategories > 0 {
+		ids, err := w.popularity.TopCategoryIDs(ctx, since, w.cfg.TopCategories)
+		if err != nil {
+			w.logger.WithError(err).Warn("failed to rank categories for cache warming")
+		}
+		for _, id := range ids {
+			categoryIDs[id] = true
+		}
+	}
+	return productIDs, categoryIDs
+}
+
+func (w *CacheWarmer) warmProduct(ctx context.Context, id int64) bool {
+	if _, err := w.products.HandleGetProductByID(ctx, &GetProductByIDQuery{ID: id}); err != nil {
+		w.logger.WithError(err).WithField("product_id", id).Warn("failed to warm cached product")
+		return false
+	}
+	return true
+}
+
+func (w *CacheWarmer) warmCategory(ctx context.Context, id int64) bool {
+	if _, err := w.categories.HandleGetCategoryByID(ctx, &GetCategoryByIDQuery{ID: id}); err != nil {
+		w.logger.WithError(err).WithField("category_id", id).Warn("failed to warm cached category")
+		return false
+	}
+	return true
+}
+
+func (w *CacheWarmer) warmNavigation(ctx context.Context) {
+	if _, err := w.categories.HandleGetNavigationTree(ctx); err != nil {
+		w.logger.WithError(err).Warn("failed to warm cached navigation tree")
+	}
+}
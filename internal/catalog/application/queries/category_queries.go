@@ -3,7 +3,6 @@ package queries
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/qhato/ecommerce/internal/catalog/application"
@@ -54,6 +53,13 @@ type ListRootCategoriesQuery struct {
 	SortOrder       string `json:"sort_order"`
 }
 
+const (
+	// defaultNavigationDepth is the number of category levels in the navigation tree unless configured
+	defaultNavigationDepth = 3
+	// navigationLevelSize caps the categories listed under one parent in the navigation tree
+	navigationLevelSize = 200
+)
+
 // GetCategoryPathQuery represents a query to get the category path
 type GetCategoryPathQuery struct {
 	CategoryID int64 `json:"category_id" validate:"required"`
@@ -61,9 +67,10 @@ type GetCategoryPathQuery struct {
 
 // CategoryQueryHandler handles category queries
 type CategoryQueryHandler struct {
-	repo   domain.CategoryRepository
-	cache  cache.Cache
-	logger *logger.Logger
+	repo            domain.CategoryRepository
+	cache           cache.Cache
+	logger          *logger.Logger
+	navigationDepth int
 }
 
 // NewCategoryQueryHandler creates a new category query handler
@@ -73,9 +80,17 @@ func NewCategoryQueryHandler(
 	logger *logger.Logger,
 ) *CategoryQueryHandler {
 	return &CategoryQueryHandler{
-		repo:   repo,
-		cache:  cache,
-		logger: logger,
+		repo:            repo,
+		cache:           cache,
+		logger:          logger,
+		navigationDepth: defaultNavigationDepth,
+	}
+}
+
+// SetNavigationDepth sets how many category levels the navigation tree holds
+func (h *CategoryQueryHandler) SetNavigationDepth(depth int) {
+	if depth > 0 {
+		h.navigationDepth = depth
 	}
 }
 
@@ -266,7 +281,77 @@ func (h *CategoryQueryHandler) HandleGetCategoryPath(ctx context.Context, query
 	return categoryDTOs, nil
 }
 
+// HandleGetNavigationTree returns the active categories from the roots down to
+// the navigation depth, ordered for display. The tree is cached as a whole.
+func (h *CategoryQueryHandler) HandleGetNavigationTree(ctx context.Context) ([]*application.NavigationNodeDTO, error) {
+	if cached, err := h.cache.Get(ctx, navigationCacheKey); err == nil && len(cached) > 0 {
+		var tree []*application.NavigationNodeDTO
+		if err := json.Unmarshal(cached, &tree); err == nil {
+			return tree, nil
+		}
+	}
+
+	roots, _, err := h.repo.FindRootCategories(ctx, navigationFilter())
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list root categories")
+	}
+	tree, err := h.navigationNodes(ctx, roots, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(tree); err == nil {
+		if err := h.cache.Set(ctx, navigationCacheKey, data, 5*time.Minute); err != nil {
+			h.logger.WithError(err).Warn("failed to cache navigation tree")
+		}
+	}
+
+	return tree, nil
+}
+
+// navigationNodes converts categories at a level of the tree, loading their
+// children until the navigation depth is reached
+func (h *CategoryQueryHandler) navigationNodes(ctx context.Context, categories []*domain.Category, level int) ([]*application.NavigationNodeDTO, error) {
+	nodes := make([]*application.NavigationNodeDTO, 0, len(categories))
+	for _, category := range categories {
+		node := &application.NavigationNodeDTO{
+			ID:     category.ID,
+			Name:   category.Name,
+			URL:    category.URL,
+			URLKey: category.URLKey,
+		}
+		if level < h.navigationDepth {
+			children, _, err := h.repo.FindByParentID(ctx, category.ID, navigationFilter())
+			if err != nil {
+				return nil, errors.InternalWrap(err, "failed to list child categories")
+			}
+			if node.Children, err = h.navigationNodes(ctx, children, level+1); err != nil {
+				return nil, err
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// navigationFilter selects the active categories of a navigation level
+func navigationFilter() *domain.CategoryFilter {
+	return &domain.CategoryFilter{
+		Page:       1,
+		PageSize:   navigationLevelSize,
+		ActiveOnly: true,
+		SortBy:     "display_order",
+		SortOrder:  "asc",
+	}
+}
+
+// categoryKeys builds the cache keys of categories
+var categoryKeys = cache.NewKeyBuilder("catalog", "category", 1)
+
+// navigationCacheKey is the cache key of the storefront navigation tree
+var navigationCacheKey = cache.NewKeyBuilder("catalog", "navigation", 1).Key()
+
 // categoryCacheKey generates a cache key for a category
 func categoryCacheKey(id int64) string {
-	return fmt.Sprintf("catalog:category:%d", id)
+	return categoryKeys.Key(id)
 }
\ No newline at end of file
@@ -3,7 +3,6 @@ package queries
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/qhato/ecommerce/internal/catalog/application"
@@ -25,38 +24,96 @@ type GetProductByURLQuery struct {
 
 // ListProductsQuery represents a query to list products
 type ListProductsQuery struct {
-	Page            int    `json:"page" validate:"min=1"`
-	PageSize        int    `json:"page_size" validate:"min=1,max=100"`
-	IncludeArchived bool   `json:"include_archived"`
-	SortBy          string `json:"sort_by"`
-	SortOrder       string `json:"sort_order"`
+	Page            int        `json:"page" validate:"min=1"`
+	PageSize        int        `json:"page_size" validate:"min=1,max=100"`
+	IncludeArchived bool       `json:"include_archived"`
+	ActiveOnly      bool       `json:"active_only"`
+	ActiveAt        *time.Time `json:"active_at,omitempty"`      // Preview active windows at a future date
+	PublishStatus   string     `json:"publish_status,omitempty"` // Only products in this publishing state
+	SortBy          string     `json:"sort_by"`
+	SortOrder       string     `json:"sort_order"`
 }
 
 // ListProductsByCategoryQuery represents a query to list products by category
 type ListProductsByCategoryQuery struct {
-	CategoryID      int64  `json:"category_id" validate:"required"`
-	Page            int    `json:"page" validate:"min=1"`
-	PageSize        int    `json:"page_size" validate:"min=1,max=100"`
-	IncludeArchived bool   `json:"include_archived"`
-	SortBy          string `json:"sort_by"`
-	SortOrder       string `json:"sort_order"`
+	CategoryID      int64      `json:"category_id" validate:"required"`
+	Page            int        `json:"page" validate:"min=1"`
+	PageSize        int        `json:"page_size" validate:"min=1,max=100"`
+	IncludeArchived bool       `json:"include_archived"`
+	ActiveOnly      bool       `json:"active_only"`
+	ActiveAt        *time.Time `json:"active_at,omitempty"`      // Preview active windows at a future date
+	PublishStatus   string     `json:"publish_status,omitempty"` // Only products in this publishing state
+	SortBy          string     `json:"sort_by"`
+	SortOrder       string     `json:"sort_order"`
+
+	// Merchandising overrides the category's saved rules (admin preview)
+	Merchandising *domain.MerchandisingCriteria `json:"-"`
 }
 
 // SearchProductsQuery represents a query to search products
 type SearchProductsQuery struct {
-	Query           string `json:"query" validate:"required"`
-	Page            int    `json:"page" validate:"min=1"`
-	PageSize        int    `json:"page_size" validate:"min=1,max=100"`
-	IncludeArchived bool   `json:"include_archived"`
-	SortBy          string `json:"sort_by"`
-	SortOrder       string `json:"sort_order"`
+	Query           string     `json:"query" validate:"required"`
+	Page            int        `json:"page" validate:"min=1"`
+	PageSize        int        `json:"page_size" validate:"min=1,max=100"`
+	IncludeArchived bool       `json:"include_archived"`
+	ActiveOnly      bool       `json:"active_only"`
+	ActiveAt        *time.Time `json:"active_at,omitempty"`      // Preview active windows at a future date
+	PublishStatus   string     `json:"publish_status,omitempty"` // Only products in this publishing state
+	SortBy          string     `json:"sort_by"`
+	SortOrder       string     `json:"sort_order"`
+	Badge           string     `json:"badge,omitempty"` // Only products showing this badge
+}
+
+// SearchQueryAnalyzer turns a raw search query into analyzed criteria
+// (synonyms, stopwords, field boosts and pinned products)
+type SearchQueryAnalyzer interface {
+	AnalyzeSearchQuery(ctx context.Context, query string) (*domain.SearchCriteria, error)
+}
+
+// CategoryMerchandiser provides the admin-managed listing rules of a category
+// (pinned products and sort rule)
+type CategoryMerchandiser interface {
+	MerchandisingCriteria(ctx context.Context, categoryID int64) (*domain.MerchandisingCriteria, error)
+}
+
+// ProductBadgeProvider provides the computed and manual badges shown on products
+type ProductBadgeProvider interface {
+	ProductBadges(ctx context.Context, productIDs []int64) (map[int64][]domain.AppliedBadge, error)
+	BadgeCriteria(code string) *domain.BadgeCriteria
+}
+
+// DealProvider provides the live sale events products and SKUs are on sale in
+type DealProvider interface {
+	ProductDeals(ctx context.Context, productIDs []int64) (map[int64]*application.DealDTO, error)
+	SKUDeals(ctx context.Context, skuIDs []int64) (map[int64]*application.DealDTO, error)
+}
+
+// ProductQualityScorer scores the completeness of products
+type ProductQualityScorer interface {
+	ProductQualityScores(ctx context.Context, productIDs []int64) (map[int64]*application.ProductQualityDTO, error)
 }
 
 // ProductQueryHandler handles product queries
 type ProductQueryHandler struct {
-	repo   domain.ProductRepository
-	cache  cache.Cache
-	logger *logger.Logger
+	repo     domain.ProductRepository
+	cache    cache.Cache
+	logger   *logger.Logger
+	analyzer SearchQueryAnalyzer
+
+	// merchandiser orders category listings the shopper did not sort explicitly
+	merchandiser CategoryMerchandiser
+
+	// badges are added to product DTOs and filter searches; unset leaves DTOs without badges
+	badges ProductBadgeProvider
+
+	// deals are added to product DTOs with their countdown; unset leaves DTOs without deals
+	deals DealProvider
+
+	// outOfStock is applied to listings and search; admin handlers leave it unset
+	outOfStock domain.OutOfStockPolicy
+
+	// quality scores are added to product DTOs for admins; storefront handlers leave it unset
+	quality ProductQualityScorer
 }
 
 // NewProductQueryHandler creates a new product query handler
@@ -72,6 +129,36 @@ func NewProductQueryHandler(
 	}
 }
 
+// SetSearchAnalyzer sets the analyzer applied to search queries
+func (h *ProductQueryHandler) SetSearchAnalyzer(analyzer SearchQueryAnalyzer) {
+	h.analyzer = analyzer
+}
+
+// SetMerchandiser sets the category listing rules applied to category listings
+func (h *ProductQueryHandler) SetMerchandiser(merchandiser CategoryMerchandiser) {
+	h.merchandiser = merchandiser
+}
+
+// SetBadgeProvider sets the provider of the badges shown on products
+func (h *ProductQueryHandler) SetBadgeProvider(badges ProductBadgeProvider) {
+	h.badges = badges
+}
+
+// SetDealProvider sets the provider of the sale events shown on products
+func (h *ProductQueryHandler) SetDealProvider(deals DealProvider) {
+	h.deals = deals
+}
+
+// SetQualityScorer sets the scorer of the completeness shown on products
+func (h *ProductQueryHandler) SetQualityScorer(quality ProductQualityScorer) {
+	h.quality = quality
+}
+
+// SetOutOfStockPolicy sets how out-of-stock products appear in listings and search
+func (h *ProductQueryHandler) SetOutOfStockPolicy(policy domain.OutOfStockPolicy) {
+	h.outOfStock = policy
+}
+
 // HandleGetProductByID handles the get product by ID query
 func (h *ProductQueryHandler) HandleGetProductByID(ctx context.Context, query *GetProductByIDQuery) (*application.ProductDTO, error) {
 	// Try to get from cache first
@@ -81,7 +168,11 @@ func (h *ProductQueryHandler) HandleGetProductByID(ctx context.Context, query *G
 	if cached, err := h.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
 		if err := json.Unmarshal(cached, &product); err == nil {
 			h.logger.WithField("product_id", query.ID).Debug("product found in cache")
-			return application.ToProductDTO(product), nil
+			dto := application.ToProductDTO(product)
+			h.applyBadges(ctx, dto)
+			h.applyDeals(ctx, dto)
+			h.applyQuality(ctx, dto)
+			return dto, nil
 		}
 	}
 
@@ -98,7 +189,11 @@ func (h *ProductQueryHandler) HandleGetProductByID(ctx context.Context, query *G
 		}
 	}
 
-	return application.ToProductDTO(product), nil
+	dto := application.ToProductDTO(product)
+	h.applyBadges(ctx, dto)
+	h.applyDeals(ctx, dto)
+	h.applyQuality(ctx, dto)
+	return dto, nil
 }
 
 // HandleGetProductByURL handles the get product by URL query
@@ -116,7 +211,11 @@ func (h *ProductQueryHandler) HandleGetProductByURL(ctx context.Context, query *
 		}
 	}
 
-	return application.ToProductDTO(product), nil
+	dto := application.ToProductDTO(product)
+	h.applyBadges(ctx, dto)
+	h.applyDeals(ctx, dto)
+	h.applyQuality(ctx, dto)
+	return dto, nil
 }
 
 // HandleListProducts handles the list products query
@@ -140,6 +239,10 @@ func (h *ProductQueryHandler) HandleListProducts(ctx context.Context, query *Lis
 		Page:            query.Page,
 		PageSize:        query.PageSize,
 		IncludeArchived: query.IncludeArchived,
+		ActiveOnly:      query.ActiveOnly,
+		ActiveAt:        query.ActiveAt,
+		OutOfStock:      h.outOfStock,
+		PublishStatus:   domain.ProductPublishStatus(query.PublishStatus),
 		SortBy:          query.SortBy,
 		SortOrder:       query.SortOrder,
 	}
@@ -155,6 +258,9 @@ func (h *ProductQueryHandler) HandleListProducts(ctx context.Context, query *Lis
 	for i, product := range products {
 		productDTOs[i] = application.ToProductDTO(product)
 	}
+	h.applyBadges(ctx, productDTOs...)
+	h.applyDeals(ctx, productDTOs...)
+	h.applyQuality(ctx, productDTOs...)
 
 	return application.NewPaginatedResponse(productDTOs, query.Page, query.PageSize, total), nil
 }
@@ -168,6 +274,18 @@ func (h *ProductQueryHandler) HandleListProductsByCategory(ctx context.Context,
 	if query.PageSize < 1 {
 		query.PageSize = 20
 	}
+
+	// Category rules apply unless the shopper chose a sort; fall back to the regular sort on failure
+	merchandising := query.Merchandising
+	if merchandising == nil && query.SortBy == "" && h.merchandiser != nil {
+		criteria, err := h.merchandiser.MerchandisingCriteria(ctx, query.CategoryID)
+		if err != nil {
+			h.logger.WithError(err).WithField("category_id", query.CategoryID).Warn("failed to load category merchandising")
+		} else {
+			merchandising = criteria
+		}
+	}
+
 	if query.SortBy == "" {
 		query.SortBy = "created_at"
 	}
@@ -180,9 +298,16 @@ func (h *ProductQueryHandler) HandleListProductsByCategory(ctx context.Context,
 		Page:            query.Page,
 		PageSize:        query.PageSize,
 		IncludeArchived: query.IncludeArchived,
+		ActiveOnly:      query.ActiveOnly,
+		ActiveAt:        query.ActiveAt,
+		OutOfStock:      h.outOfStock,
+		PublishStatus:   domain.ProductPublishStatus(query.PublishStatus),
 		SortBy:          query.SortBy,
 		SortOrder:       query.SortOrder,
 	}
+	if !merchandising.IsEmpty() {
+		filter.Merchandising = merchandising
+	}
 
 	// Get from repository
 	products, total, err := h.repo.FindByCategoryID(ctx, query.CategoryID, filter)
@@ -195,6 +320,9 @@ func (h *ProductQueryHandler) HandleListProductsByCategory(ctx context.Context,
 	for i, product := range products {
 		productDTOs[i] = application.ToProductDTO(product)
 	}
+	h.applyBadges(ctx, productDTOs...)
+	h.applyDeals(ctx, productDTOs...)
+	h.applyQuality(ctx, productDTOs...)
 
 	return application.NewPaginatedResponse(productDTOs, query.Page, query.PageSize, total), nil
 }
@@ -210,6 +338,9 @@ func (h *ProductQueryHandler) HandleSearchProducts(ctx context.Context, query *S
 	}
 	if query.SortBy == "" {
 		query.SortBy = "created_at"
+		if h.analyzer != nil {
+			query.SortBy = "relevance"
+		}
 	}
 	if query.SortOrder == "" {
 		query.SortOrder = "desc"
@@ -220,10 +351,28 @@ func (h *ProductQueryHandler) HandleSearchProducts(ctx context.Context, query *S
 		Page:            query.Page,
 		PageSize:        query.PageSize,
 		IncludeArchived: query.IncludeArchived,
+		ActiveOnly:      query.ActiveOnly,
+		ActiveAt:        query.ActiveAt,
+		OutOfStock:      h.outOfStock,
+		PublishStatus:   domain.ProductPublishStatus(query.PublishStatus),
 		SortBy:          query.SortBy,
 		SortOrder:       query.SortOrder,
 	}
 
+	// Apply synonyms, stopwords, boosts and pins; fall back to a plain search on failure
+	if h.analyzer != nil {
+		criteria, err := h.analyzer.AnalyzeSearchQuery(ctx, query.Query)
+		if err != nil {
+			h.logger.WithError(err).Warn("failed to analyze search query")
+		} else {
+			filter.Search = criteria
+		}
+	}
+
+	if query.Badge != "" && h.badges != nil {
+		filter.Badge = h.badges.BadgeCriteria(query.Badge)
+	}
+
 	// Search from repository
 	products, total, err := h.repo.Search(ctx, query.Query, filter)
 	if err != nil {
@@ -235,11 +384,79 @@ func (h *ProductQueryHandler) HandleSearchProducts(ctx context.Context, query *S
 	for i, product := range products {
 		productDTOs[i] = application.ToProductDTO(product)
 	}
+	h.applyBadges(ctx, productDTOs...)
+	h.applyDeals(ctx, productDTOs...)
+	h.applyQuality(ctx, productDTOs...)
 
 	return application.NewPaginatedResponse(productDTOs, query.Page, query.PageSize, total), nil
 }
 
+// applyBadges adds the badges shown on the products; listings are served without badges on failure
+func (h *ProductQueryHandler) applyBadges(ctx context.Context, products ...*application.ProductDTO) {
+	if h.badges == nil || len(products) == 0 {
+		return
+	}
+
+	productIDs := make([]int64, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+	}
+	badges, err := h.badges.ProductBadges(ctx, productIDs)
+	if err != nil {
+		h.logger.WithError(err).Warn("failed to load product badges")
+		return
+	}
+	for _, product := range products {
+		if applied := badges[product.ID]; len(applied) > 0 {
+			product.Badges = application.ToBadgeDTOs(applied)
+		}
+	}
+}
+
+// applyDeals adds the live sale events of the products; listings are served without deals on failure
+func (h *ProductQueryHandler) applyDeals(ctx context.Context, products ...*application.ProductDTO) {
+	if h.deals == nil || len(products) == 0 {
+		return
+	}
+
+	productIDs := make([]int64, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+	}
+	deals, err := h.deals.ProductDeals(ctx, productIDs)
+	if err != nil {
+		h.logger.WithError(err).Warn("failed to load product deals")
+		return
+	}
+	for _, product := range products {
+		product.Deal = deals[product.ID]
+	}
+}
+
+// applyQuality adds the completeness scores of the products; listings are served without scores on failure
+func (h *ProductQueryHandler) applyQuality(ctx context.Context, products ...*application.ProductDTO) {
+	if h.quality == nil || len(products) == 0 {
+		return
+	}
+
+	productIDs := make([]int64, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+	}
+	scores, err := h.quality.ProductQualityScores(ctx, productIDs)
+	if err != nil {
+		h.logger.WithError(err).Warn("failed to score product quality")
+		return
+	}
+	for _, product := range products {
+		product.Quality = scores[product.ID]
+	}
+}
+
+// productKeys builds the cache keys of products
+var productKeys = cache.NewKeyBuilder("catalog", "product", 1)
+
 // productCacheKey generates a cache key for a product
 func productCacheKey(id int64) string {
-	return fmt.Sprintf("catalog:product:%d", id)
-}
\ No newline at end of file
+	return productKeys.Key(id)
+}
@@ -0,0 +1,104 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// GetProductVersionsQuery represents a query to get the published and draft versions of a product
+type GetProductVersionsQuery struct {
+	ID int64 `json:"id" validate:"required"`
+}
+
+// ListProductDraftsQuery represents a query to list the product drafts awaiting review
+type ListProductDraftsQuery struct {
+	Status   string `json:"status,omitempty"` // PENDING or REJECTED; empty lists both
+	Page     int    `json:"page" validate:"min=1"`
+	PageSize int    `json:"page_size" validate:"min=1,max=100"`
+}
+
+// ProductVersionQueryHandler handles queries on the draft and published
+// versions of products. It reads the repositories directly, as reviewers must
+// see the latest edits.
+type ProductVersionQueryHandler struct {
+	repo      domain.ProductRepository
+	draftRepo domain.ProductDraftRepository
+	logger    *logger.Logger
+}
+
+// NewProductVersionQueryHandler creates a new product version query handler
+func NewProductVersionQueryHandler(
+	repo domain.ProductRepository,
+	draftRepo domain.ProductDraftRepository,
+	logger *logger.Logger,
+) *ProductVersionQueryHandler {
+	return &ProductVersionQueryHandler{
+		repo:      repo,
+		draftRepo: draftRepo,
+		logger:    logger,
+	}
+}
+
+// HandleGetProductVersions handles the get product versions query. A product
+// that was never published is its own draft.
+func (h *ProductVersionQueryHandler) HandleGetProductVersions(ctx context.Context, query *GetProductVersionsQuery) (*application.ProductVersionsDTO, error) {
+	product, err := h.repo.FindByID(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := &application.ProductVersionsDTO{
+		ProductID:     product.ID,
+		PublishStatus: string(product.PublishStatus),
+	}
+	if !product.IsPublished() {
+		versions.Draft = &application.ProductDraftDTO{
+			ProductID: product.ID,
+			Status:    string(domain.ProductDraftPending),
+			Product:   application.ToProductDTO(product),
+		}
+		return versions, nil
+	}
+
+	versions.Published = application.ToProductDTO(product)
+	draft, err := h.draftRepo.FindByProductID(ctx, product.ID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to load product draft")
+	}
+	if draft != nil {
+		versions.Draft = application.ToProductDraftDTO(draft)
+	}
+	return versions, nil
+}
+
+// HandleListProductDrafts handles the list product drafts query. Products never
+// published are listed by the product list filtered on the DRAFT publish status.
+func (h *ProductVersionQueryHandler) HandleListProductDrafts(ctx context.Context, query *ListProductDraftsQuery) (*application.PaginatedResponse, error) {
+	// Set defaults
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = 20
+	}
+
+	drafts, total, err := h.draftRepo.FindAll(ctx, &domain.ProductDraftFilter{
+		Status:   domain.ProductDraftStatus(query.Status),
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list product drafts")
+	}
+
+	draftDTOs := make([]*application.ProductDraftDTO, len(drafts))
+	for i, draft := range drafts {
+		draftDTOs[i] = application.ToProductDraftDTO(draft)
+	}
+
+	return application.NewPaginatedResponse(draftDTOs, query.Page, query.PageSize, total), nil
+}
@@ -3,7 +3,6 @@ package queries
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/qhato/ecommerce/internal/catalog/application"
@@ -43,6 +42,9 @@ type SKUQueryHandler struct {
 	repo   domain.SKURepository
 	cache  cache.Cache
 	logger *logger.Logger
+
+	// deals are added to SKU DTOs with their countdown; unset leaves DTOs without deals
+	deals DealProvider
 }
 
 // NewSKUQueryHandler creates a new SKU query handler
@@ -58,6 +60,11 @@ func NewSKUQueryHandler(
 	}
 }
 
+// SetDealProvider sets the provider of the sale events shown on SKUs
+func (h *SKUQueryHandler) SetDealProvider(deals DealProvider) {
+	h.deals = deals
+}
+
 // HandleGetSKUByID handles the get SKU by ID query
 func (h *SKUQueryHandler) HandleGetSKUByID(ctx context.Context, query *GetSKUByIDQuery) (*application.SkuDTO, error) {
 	// Try to get from cache first
@@ -67,7 +74,9 @@ func (h *SKUQueryHandler) HandleGetSKUByID(ctx context.Context, query *GetSKUByI
 	if cached, err := h.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
 		if err := json.Unmarshal(cached, &sku); err == nil {
 			h.logger.WithField("sku_id", query.ID).Debug("SKU found in cache")
-			return application.ToSkuDTO(sku), nil
+			dto := application.ToSkuDTO(sku)
+			h.applyDeals(ctx, dto)
+			return dto, nil
 		}
 	}
 
@@ -84,7 +93,9 @@ func (h *SKUQueryHandler) HandleGetSKUByID(ctx context.Context, query *GetSKUByI
 		}
 	}
 
-	return application.ToSkuDTO(sku), nil
+	dto := application.ToSkuDTO(sku)
+	h.applyDeals(ctx, dto)
+	return dto, nil
 }
 
 // HandleGetSKUByUPC handles the get SKU by UPC query
@@ -102,7 +113,9 @@ func (h *SKUQueryHandler) HandleGetSKUByUPC(ctx context.Context, query *GetSKUBy
 		}
 	}
 
-	return application.ToSkuDTO(sku), nil
+	dto := application.ToSkuDTO(sku)
+	h.applyDeals(ctx, dto)
+	return dto, nil
 }
 
 // HandleListSKUs handles the list SKUs query
@@ -143,6 +156,8 @@ func (h *SKUQueryHandler) HandleListSKUs(ctx context.Context, query *ListSKUsQue
 		skuDTOs[i] = application.ToSkuDTO(sku)
 	}
 
+	h.applyDeals(ctx, skuDTOs...)
+
 	return application.NewPaginatedResponse(skuDTOs, query.Page, query.PageSize, total), nil
 }
 
@@ -160,10 +175,35 @@ func (h *SKUQueryHandler) HandleListSKUsByProduct(ctx context.Context, query *Li
 		skuDTOs[i] = application.ToSkuDTO(sku)
 	}
 
+	h.applyDeals(ctx, skuDTOs...)
+
 	return skuDTOs, nil
 }
 
+// applyDeals adds the live sale events of the SKUs; SKUs are served without deals on failure
+func (h *SKUQueryHandler) applyDeals(ctx context.Context, skus ...*application.SkuDTO) {
+	if h.deals == nil || len(skus) == 0 {
+		return
+	}
+
+	skuIDs := make([]int64, len(skus))
+	for i, sku := range skus {
+		skuIDs[i] = sku.ID
+	}
+	deals, err := h.deals.SKUDeals(ctx, skuIDs)
+	if err != nil {
+		h.logger.WithError(err).Warn("failed to load SKU deals")
+		return
+	}
+	for _, sku := range skus {
+		sku.Deal = deals[sku.ID]
+	}
+}
+
+// skuKeys builds the cache keys of SKUs
+var skuKeys = cache.NewKeyBuilder("catalog", "sku", 1)
+
 // skuCacheKey generates a cache key for a SKU
 func skuCacheKey(id int64) string {
-	return fmt.Sprintf("catalog:sku:%d", id)
+	return skuKeys.Key(id)
 }
@@ -0,0 +1,709 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/schedule"
+)
+
+const (
+	defaultSaleEventsLimit = 50
+	maxSaleEventsLimit     = 500
+)
+
+// SaleEventItemDTO represents a SKU of a sale event
+type SaleEventItemDTO struct {
+	SKUID             int64   `json:"sku_id"`
+	SalePrice         float64 `json:"sale_price,omitempty"`
+	RetailPrice       float64 `json:"retail_price,omitempty"`        // When the event started
+	PreviousSalePrice float64 `json:"previous_sale_price,omitempty"` // When the event started, restored at its end
+	Applied           bool    `json:"applied"`
+}
+
+// SaleEventDTO represents a scheduled sale event
+type SaleEventDTO struct {
+	ID          int64                  `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	StartsAt    time.Time              `json:"starts_at"`
+	EndsAt      time.Time              `json:"ends_at"`
+	Status      domain.SaleEventStatus `json:"status"`
+	OfferID     *int64                 `json:"offer_id,omitempty"`
+	CreatedBy   string                 `json:"created_by,omitempty"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	EndedAt     *time.Time             `json:"ended_at,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	Items       []*SaleEventItemDTO    `json:"items"`
+}
+
+// SaleEventItemRequest is a SKU put on sale by an event
+type SaleEventItemRequest struct {
+	SKUID     int64   `json:"sku_id"`
+	SalePrice float64 `json:"sale_price"` // 0 leaves the price to the event's offer
+}
+
+// CreateSaleEventRequest is the payload to schedule a sale event
+type CreateSaleEventRequest struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	StartsAt    *schedule.Time         `json:"starts_at"` // Dates without an offset are in the site's time zone
+	EndsAt      *schedule.Time         `json:"ends_at"`
+	OfferID     *int64                 `json:"offer_id"` // Offer run over the same window, e.g. a percentage off
+	Items       []SaleEventItemRequest `json:"items"`
+}
+
+// DealDTO is the sale event a product or SKU is on sale in, with a countdown to its end
+type DealDTO struct {
+	SaleEventID   int64     `json:"sale_event_id"`
+	Name          string    `json:"name"`
+	SalePrice     *float64  `json:"sale_price,omitempty"` // Omitted when the discount comes from an offer
+	EndsAt        time.Time `json:"ends_at"`
+	EndsInSeconds int64     `json:"ends_in_seconds"`
+}
+
+// StorefrontDealItemDTO is a SKU of a sale event shown on the storefront
+type StorefrontDealItemDTO struct {
+	SKUID     int64    `json:"sku_id"`
+	SalePrice *float64 `json:"sale_price,omitempty"` // Revealed once the event is live
+}
+
+// StorefrontSaleEventDTO is a live or upcoming sale event with its countdown
+type StorefrontSaleEventDTO struct {
+	ID              int64                    `json:"id"`
+	Name            string                   `json:"name"`
+	Description     string                   `json:"description,omitempty"`
+	Status          string                   `json:"status"` // LIVE or UPCOMING
+	StartsAt        time.Time                `json:"starts_at"`
+	EndsAt          time.Time                `json:"ends_at"`
+	StartsInSeconds int64                    `json:"starts_in_seconds"` // 0 once live
+	EndsInSeconds   int64                    `json:"ends_in_seconds"`
+	Items           []*StorefrontDealItemDTO `json:"items"`
+}
+
+// StorefrontDealsDTO lists the live and upcoming sale events. ServerTime lets
+// clients correct their clock before counting down.
+type StorefrontDealsDTO struct {
+	ServerTime time.Time                 `json:"server_time"`
+	Events     []*StorefrontSaleEventDTO `json:"events"`
+}
+
+// Storefront sale event statuses
+const (
+	DealLive     = "LIVE"
+	DealUpcoming = "UPCOMING"
+)
+
+// SalesDTO sums the sales of the SKUs of a sale event over a window
+type SalesDTO struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Orders  int64     `json:"orders"`
+	Units   int64     `json:"units"`
+	Revenue float64   `json:"revenue"`
+}
+
+// SaleEventSKUPerformanceDTO compares the sales of a SKU during an event with the baseline
+type SaleEventSKUPerformanceDTO struct {
+	SKUID           int64   `json:"sku_id"`
+	SalePrice       float64 `json:"sale_price,omitempty"`
+	Units           int64   `json:"units"`
+	Revenue         float64 `json:"revenue"`
+	BaselineUnits   int64   `json:"baseline_units"`
+	BaselineRevenue float64 `json:"baseline_revenue"`
+}
+
+// SaleEventPerformanceDTO reports the sales of a sale event against the same
+// SKUs over a baseline window of equal length just before it. Lifts are
+// percentages, omitted when the baseline had no sales.
+type SaleEventPerformanceDTO struct {
+	SaleEventID int64                         `json:"sale_event_id"`
+	Name        string                        `json:"name"`
+	Status      domain.SaleEventStatus        `json:"status"`
+	Event       SalesDTO                      `json:"event"`
+	Baseline    SalesDTO                      `json:"baseline"`
+	UnitsLift   *float64                      `json:"units_lift,omitempty"`
+	RevenueLift *float64                      `json:"revenue_lift,omitempty"`
+	SKUs        []*SaleEventSKUPerformanceDTO `json:"skus"`
+}
+
+// SaleEventConfig configures sale events
+type SaleEventConfig struct {
+	Upcoming time.Duration // Scheduled events starting within it are listed on the storefront
+}
+
+// SaleEventOfferScheduler schedules the offer of a sale event over its window
+type SaleEventOfferScheduler interface {
+	ScheduleOffer(ctx context.Context, offerID int64, startDate, endDate time.Time) error
+}
+
+// SaleEventService manages time-boxed sale events ("daily deals") and the
+// deal prices they apply to SKUs.
+type SaleEventService interface {
+	// CreateSaleEvent schedules a sale event on a set of SKUs.
+	CreateSaleEvent(ctx context.Context, req *CreateSaleEventRequest, createdBy string) (*SaleEventDTO, error)
+
+	// GetSaleEvent retrieves a sale event.
+	GetSaleEvent(ctx context.Context, id int64) (*SaleEventDTO, error)
+
+	// ListSaleEvents lists sale events, optionally of one status, latest start first.
+	ListSaleEvents(ctx context.Context, status string, limit int) ([]*SaleEventDTO, error)
+
+	// CancelSaleEvent cancels a scheduled event, or ends an active one early restoring its prices.
+	CancelSaleEvent(ctx context.Context, id int64) (*SaleEventDTO, error)
+
+	// GetPerformance reports the sales of an event that started against its baseline.
+	GetPerformance(ctx context.Context, id int64) (*SaleEventPerformanceDTO, error)
+
+	// ListStorefrontDeals lists the live events and the upcoming ones with their countdowns.
+	ListStorefrontDeals(ctx context.Context) (*StorefrontDealsDTO, error)
+
+	// ProductDeals returns the live deal of each product with a SKU on sale in an event.
+	ProductDeals(ctx context.Context, productIDs []int64) (map[int64]*DealDTO, error)
+
+	// SKUDeals returns the live deal of each SKU on sale in an event.
+	SKUDeals(ctx context.Context, skuIDs []int64) (map[int64]*DealDTO, error)
+
+	// RunDueEvents starts the events whose start passed and ends the ones whose end passed.
+	RunDueEvents(ctx context.Context) (started, ended int, err error)
+
+	// StartScheduler starts and ends events periodically until ctx is cancelled.
+	StartScheduler(ctx context.Context, interval time.Duration)
+}
+
+type saleEventService struct {
+	repo     domain.SaleEventRepository
+	skuRepo  domain.SKURepository
+	eventBus event.Bus
+	offers   SaleEventOfferScheduler
+	cfg      SaleEventConfig
+	log      *logger.Logger
+
+	// runMu prevents overlapping scheduler runs
+	runMu sync.Mutex
+}
+
+// NewSaleEventService creates a new instance of SaleEventService.
+// offers may be nil, in which case events cannot run an offer.
+func NewSaleEventService(
+	repo domain.SaleEventRepository,
+	skuRepo domain.SKURepository,
+	eventBus event.Bus,
+	offers SaleEventOfferScheduler,
+	cfg SaleEventConfig,
+	log *logger.Logger,
+) SaleEventService {
+	return &saleEventService{
+		repo:     repo,
+		skuRepo:  skuRepo,
+		eventBus: eventBus,
+		offers:   offers,
+		cfg:      cfg,
+		log:      log,
+	}
+}
+
+func (s *saleEventService) CreateSaleEvent(ctx context.Context, req *CreateSaleEventRequest, createdBy string) (*SaleEventDTO, error) {
+	if req.StartsAt == nil || req.EndsAt == nil {
+		return nil, errors.ValidationError("start and end dates are required")
+	}
+	loc := schedule.Location(ctx)
+	startsAt, endsAt := req.StartsAt.Start(loc), req.EndsAt.End(loc)
+	if !endsAt.After(time.Now()) {
+		return nil, errors.ValidationError("end date must be in the future")
+	}
+	if req.OfferID != nil && s.offers == nil {
+		return nil, errors.ValidationError("sale events cannot run offers on this server")
+	}
+
+	items := make([]*domain.SaleEventItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = &domain.SaleEventItem{SKUID: item.SKUID, SalePrice: item.SalePrice}
+	}
+	saleEvent, err := domain.NewSaleEvent(req.Name, req.Description, startsAt, endsAt, req.OfferID, items, createdBy)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	for _, item := range saleEvent.Items {
+		sku, err := s.skuRepo.FindByID(ctx, item.SKUID)
+		if err != nil {
+			return nil, err
+		}
+		// A sale price at or above the retail price would not change what the SKU sells at
+		if item.SalePrice > 0 && item.SalePrice >= sku.RetailPrice {
+			return nil, errors.ValidationError(fmt.Sprintf("sale price of SKU %d must be below its retail price of %.2f", sku.ID, sku.RetailPrice))
+		}
+	}
+	overlapping, err := s.repo.FindOverlapping(ctx, saleEvent.SKUIDs(), startsAt, endsAt, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check overlapping sale events: %w", err)
+	}
+	if len(overlapping) > 0 {
+		return nil, errors.Conflict(fmt.Sprintf("SKUs %v are in another sale event in that period", overlapping))
+	}
+
+	if saleEvent.OfferID != nil {
+		if err := s.offers.ScheduleOffer(ctx, *saleEvent.OfferID, startsAt, endsAt); err != nil {
+			return nil, fmt.Errorf("failed to schedule sale event offer: %w", err)
+		}
+	}
+	if err := s.repo.Save(ctx, saleEvent); err != nil {
+		return nil, fmt.Errorf("failed to save sale event: %w", err)
+	}
+	return ToSaleEventDTO(saleEvent), nil
+}
+
+func (s *saleEventService) GetSaleEvent(ctx context.Context, id int64) (*SaleEventDTO, error) {
+	saleEvent, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToSaleEventDTO(saleEvent), nil
+}
+
+func (s *saleEventService) ListSaleEvents(ctx context.Context, status string, limit int) ([]*SaleEventDTO, error) {
+	if limit <= 0 || limit > maxSaleEventsLimit {
+		limit = defaultSaleEventsLimit
+	}
+	events, err := s.repo.FindAll(ctx, domain.SaleEventFilter{Status: domain.SaleEventStatus(status), Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sale events: %w", err)
+	}
+
+	dtos := make([]*SaleEventDTO, len(events))
+	for i, saleEvent := range events {
+		dtos[i] = ToSaleEventDTO(saleEvent)
+	}
+	return dtos, nil
+}
+
+func (s *saleEventService) CancelSaleEvent(ctx context.Context, id int64) (*SaleEventDTO, error) {
+	saleEvent, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch saleEvent.Status {
+	case domain.SaleEventStatusScheduled:
+		if err := saleEvent.Cancel(); err != nil {
+			return nil, errors.Conflict(err.Error())
+		}
+		ok, err := s.repo.Transition(ctx, saleEvent.ID, domain.SaleEventStatusScheduled, domain.SaleEventStatusCancelled)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.Conflict("sale event started meanwhile; cancel it again to end it")
+		}
+		// The offer is left with an empty window so it never runs
+		s.rescheduleOffer(ctx, saleEvent, saleEvent.StartsAt)
+	case domain.SaleEventStatusActive:
+		ended, err := s.endEvent(ctx, saleEvent)
+		if err != nil {
+			return nil, err
+		}
+		if !ended {
+			return nil, errors.Conflict("sale event already ended")
+		}
+		s.rescheduleOffer(ctx, saleEvent, *saleEvent.EndedAt)
+	default:
+		return nil, errors.Conflict("sale event already ended")
+	}
+	return ToSaleEventDTO(saleEvent), nil
+}
+
+// rescheduleOffer moves the end of the event's offer to a time; a failure is
+// logged for the offer to be ended by hand
+func (s *saleEventService) rescheduleOffer(ctx context.Context, saleEvent *domain.SaleEvent, endDate time.Time) {
+	if saleEvent.OfferID == nil || s.offers == nil {
+		return
+	}
+	if err := s.offers.ScheduleOffer(ctx, *saleEvent.OfferID, saleEvent.StartsAt, endDate); err != nil {
+		s.log.WithError(err).WithFields(logger.Fields{
+			"sale_event_id": saleEvent.ID,
+			"offer_id":      *saleEvent.OfferID,
+		}).Error("failed to reschedule sale event offer")
+	}
+}
+
+func (s *saleEventService) GetPerformance(ctx context.Context, id int64) (*SaleEventPerformanceDTO, error) {
+	saleEvent, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if saleEvent.StartedAt == nil {
+		return nil, errors.ValidationError("sale event has not started")
+	}
+
+	from, to := *saleEvent.StartedAt, saleEvent.EndsAt
+	if saleEvent.EndedAt != nil {
+		to = *saleEvent.EndedAt
+	}
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	baselineFrom := from.Add(-to.Sub(from))
+
+	skuIDs := saleEvent.SKUIDs()
+	sales, err := s.repo.SumSales(ctx, skuIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum sale event sales: %w", err)
+	}
+	baseline, err := s.repo.SumSales(ctx, skuIDs, baselineFrom, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum baseline sales: %w", err)
+	}
+
+	report := &SaleEventPerformanceDTO{
+		SaleEventID: saleEvent.ID,
+		Name:        saleEvent.Name,
+		Status:      saleEvent.Status,
+		Event:       SalesDTO{From: from, To: to, Orders: sales.Orders, Units: sales.Units, Revenue: sales.Revenue},
+		Baseline:    SalesDTO{From: baselineFrom, To: from, Orders: baseline.Orders, Units: baseline.Units, Revenue: baseline.Revenue},
+		UnitsLift:   lift(float64(sales.Units), float64(baseline.Units)),
+		RevenueLift: lift(sales.Revenue, baseline.Revenue),
+		SKUs:        make([]*SaleEventSKUPerformanceDTO, len(saleEvent.Items)),
+	}
+
+	bySKU := make(map[int64]*SaleEventSKUPerformanceDTO, len(saleEvent.Items))
+	for i, item := range saleEvent.Items {
+		report.SKUs[i] = &SaleEventSKUPerformanceDTO{SKUID: item.SKUID, SalePrice: item.SalePrice}
+		bySKU[item.SKUID] = report.SKUs[i]
+	}
+	for _, sku := range sales.SKUs {
+		if perf := bySKU[sku.SKUID]; perf != nil {
+			perf.Units, perf.Revenue = sku.Units, sku.Revenue
+		}
+	}
+	for _, sku := range baseline.SKUs {
+		if perf := bySKU[sku.SKUID]; perf != nil {
+			perf.BaselineUnits, perf.BaselineRevenue = sku.Units, sku.Revenue
+		}
+	}
+	return report, nil
+}
+
+// lift is the change from a baseline in percent, nil without a baseline
+func lift(value, baseline float64) *float64 {
+	if baseline <= 0 {
+		return nil
+	}
+	l := math.Round((value-baseline)/baseline*10000) / 100
+	return &l
+}
+
+func (s *saleEventService) ListStorefrontDeals(ctx context.Context) (*StorefrontDealsDTO, error) {
+	now := time.Now()
+	events, err := s.repo.FindStorefront(ctx, now.Add(s.cfg.Upcoming))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sale events: %w", err)
+	}
+
+	deals := &StorefrontDealsDTO{ServerTime: now, Events: make([]*StorefrontSaleEventDTO, 0, len(events))}
+	for _, saleEvent := range events {
+		// Events past their end wait for the scheduler, but are no longer shown
+		if !saleEvent.EndsAt.After(now) {
+			continue
+		}
+		live := saleEvent.Status == domain.SaleEventStatusActive
+		dto := &StorefrontSaleEventDTO{
+			ID:            saleEvent.ID,
+			Name:          saleEvent.Name,
+			Description:   saleEvent.Description,
+			Status:        DealUpcoming,
+			StartsAt:      saleEvent.StartsAt,
+			EndsAt:        saleEvent.EndsAt,
+			EndsInSeconds: secondsUntil(now, saleEvent.EndsAt),
+			Items:         make([]*StorefrontDealItemDTO, len(saleEvent.Items)),
+		}
+		if live {
+			dto.Status = DealLive
+		} else {
+			dto.StartsInSeconds = secondsUntil(now, saleEvent.StartsAt)
+		}
+		for i, item := range saleEvent.Items {
+			dto.Items[i] = &StorefrontDealItemDTO{SKUID: item.SKUID}
+			if live && item.SalePrice > 0 {
+				salePrice := item.SalePrice
+				dto.Items[i].SalePrice = &salePrice
+			}
+		}
+		deals.Events = append(deals.Events, dto)
+	}
+	return deals, nil
+}
+
+func (s *saleEventService) ProductDeals(ctx context.Context, productIDs []int64) (map[int64]*DealDTO, error) {
+	if len(productIDs) == 0 {
+		return map[int64]*DealDTO{}, nil
+	}
+	deals, err := s.repo.FindActiveDealsByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find product deals: %w", err)
+	}
+
+	// Deals come ending soonest first; a product shows that event, from its lowest deal price
+	now := time.Now()
+	byProduct := make(map[int64]*DealDTO, len(deals))
+	for _, deal := range deals {
+		if deal.ProductID == nil || !deal.EndsAt.After(now) {
+			continue
+		}
+		dto, ok := byProduct[*deal.ProductID]
+		if !ok {
+			byProduct[*deal.ProductID] = toDealDTO(deal, now)
+			continue
+		}
+		if dto.SaleEventID == deal.SaleEventID && deal.SalePrice > 0 && (dto.SalePrice == nil || deal.SalePrice < *dto.SalePrice) {
+			salePrice := deal.SalePrice
+			dto.SalePrice = &salePrice
+		}
+	}
+	return byProduct, nil
+}
+
+func (s *saleEventService) SKUDeals(ctx context.Context, skuIDs []int64) (map[int64]*DealDTO, error) {
+	if len(skuIDs) == 0 {
+		return map[int64]*DealDTO{}, nil
+	}
+	deals, err := s.repo.FindActiveDealsBySKUIDs(ctx, skuIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find SKU deals: %w", err)
+	}
+
+	now := time.Now()
+	bySKU := make(map[int64]*DealDTO, len(deals))
+	for _, deal := range deals {
+		if _, ok := bySKU[deal.SKUID]; ok || !deal.EndsAt.After(now) {
+			continue
+		}
+		bySKU[deal.SKUID] = toDealDTO(deal, now)
+	}
+	return bySKU, nil
+}
+
+func toDealDTO(deal *domain.SaleEventDeal, now time.Time) *DealDTO {
+	dto := &DealDTO{
+		SaleEventID:   deal.SaleEventID,
+		Name:          deal.Name,
+		EndsAt:        deal.EndsAt,
+		EndsInSeconds: secondsUntil(now, deal.EndsAt),
+	}
+	if deal.SalePrice > 0 {
+		salePrice := deal.SalePrice
+		dto.SalePrice = &salePrice
+	}
+	return dto
+}
+
+// secondsUntil counts down to a time, rounding up so a countdown shows 0 only once it passed
+func secondsUntil(now, at time.Time) int64 {
+	if !at.After(now) {
+		return 0
+	}
+	return int64(math.Ceil(at.Sub(now).Seconds()))
+}
+
+func (s *saleEventService) RunDueEvents(ctx context.Context) (int, int, error) {
+	now := time.Now()
+	events, err := s.repo.FindDue(ctx, now)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find due sale events: %w", err)
+	}
+
+	started, ended := 0, 0
+	for _, saleEvent := range events {
+		log := s.log.WithField("sale_event_id", saleEvent.ID)
+		switch {
+		case saleEvent.IsDueToStart(now):
+			ok, err := s.startEvent(ctx, saleEvent)
+			if err != nil {
+				log.WithError(err).Error("failed to start sale event")
+				continue
+			}
+			if ok {
+				started++
+			}
+			// An event started late may already be past its end
+			if !saleEvent.IsDueToEnd(now) {
+				continue
+			}
+			fallthrough
+		case saleEvent.IsDueToEnd(now):
+			ok, err := s.endEvent(ctx, saleEvent)
+			if err != nil {
+				log.WithError(err).Error("failed to end sale event")
+				continue
+			}
+			if ok {
+				ended++
+			}
+		}
+	}
+	return started, ended, nil
+}
+
+// startEvent claims a scheduled event and applies its deal prices, recording
+// the prices to restore. It reports false when another server started it.
+func (s *saleEventService) startEvent(ctx context.Context, saleEvent *domain.SaleEvent) (bool, error) {
+	ok, err := s.repo.Transition(ctx, saleEvent.ID, domain.SaleEventStatusScheduled, domain.SaleEventStatusActive)
+	if err != nil || !ok {
+		return false, err
+	}
+	saleEvent.Start()
+
+	for _, item := range saleEvent.Items {
+		if item.SalePrice <= 0 {
+			continue
+		}
+		sku, err := s.skuRepo.FindByID(ctx, item.SKUID)
+		if err != nil {
+			s.log.WithError(err).WithField("sku_id", item.SKUID).Error("failed to find sale event SKU")
+			continue
+		}
+		item.RetailPrice, item.PreviousSalePrice = sku.RetailPrice, sku.SalePrice
+		if err := s.reprice(ctx, sku, item.SalePrice); err != nil {
+			s.log.WithError(err).WithField("sku_id", item.SKUID).Error("failed to apply sale event price")
+			continue
+		}
+		item.Applied = true
+	}
+
+	if err := s.repo.Save(ctx, saleEvent); err != nil {
+		return true, fmt.Errorf("failed to save started sale event: %w", err)
+	}
+	s.log.WithFields(logger.Fields{
+		"sale_event_id": saleEvent.ID,
+		"skus":          len(saleEvent.Items),
+	}).Info("Sale event started")
+	return true, nil
+}
+
+// endEvent claims an active event and restores the prices its SKUs had before
+// it started. A SKU repriced by hand during the event keeps its new price. It
+// reports false when another server ended it.
+func (s *saleEventService) endEvent(ctx context.Context, saleEvent *domain.SaleEvent) (bool, error) {
+	ok, err := s.repo.Transition(ctx, saleEvent.ID, domain.SaleEventStatusActive, domain.SaleEventStatusEnded)
+	if err != nil || !ok {
+		return false, err
+	}
+	saleEvent.End()
+
+	for _, item := range saleEvent.Items {
+		if !item.Applied {
+			continue
+		}
+		log := s.log.WithFields(logger.Fields{"sale_event_id": saleEvent.ID, "sku_id": item.SKUID})
+		sku, err := s.skuRepo.FindByID(ctx, item.SKUID)
+		if err != nil {
+			log.WithError(err).Error("failed to find sale event SKU")
+			continue
+		}
+		if sku.SalePrice != item.SalePrice {
+			log.Warn("SKU repriced during the sale event; keeping its current price")
+			continue
+		}
+		if err := s.reprice(ctx, sku, item.PreviousSalePrice); err != nil {
+			log.WithError(err).Error("failed to restore SKU price after sale event")
+		}
+	}
+
+	if err := s.repo.Save(ctx, saleEvent); err != nil {
+		return true, fmt.Errorf("failed to save ended sale event: %w", err)
+	}
+	s.log.WithField("sale_event_id", saleEvent.ID).Info("Sale event ended")
+	return true, nil
+}
+
+// reprice sets the sale price of a SKU and publishes the change like a manual
+// edit, so price watches, badges and content caches follow
+func (s *saleEventService) reprice(ctx context.Context, sku *domain.SKU, salePrice float64) error {
+	oldEffectivePrice := sku.EffectivePrice()
+	sku.UpdatePricing(sku.RetailPrice, salePrice)
+	if err := s.skuRepo.Update(ctx, sku); err != nil {
+		return fmt.Errorf("failed to update SKU pricing: %w", err)
+	}
+
+	if sku.EffectivePrice() != oldEffectivePrice {
+		evt := domain.NewSKUPriceChangedEvent(sku.ID, sku.RetailPrice, sku.RetailPrice, oldEffectivePrice, sku.EffectivePrice())
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			s.log.WithError(err).WithField("sku_id", sku.ID).Error("failed to publish SKU price changed event")
+		}
+	}
+	return nil
+}
+
+func (s *saleEventService) StartScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runScheduled(ctx)
+			}
+		}
+	}()
+}
+
+func (s *saleEventService) runScheduled(ctx context.Context) {
+	if !s.runMu.TryLock() {
+		return
+	}
+	defer s.runMu.Unlock()
+
+	started, ended, err := s.RunDueEvents(ctx)
+	if err != nil {
+		s.log.WithError(err).Warn("Sale event scheduler failed")
+		return
+	}
+	if started > 0 || ended > 0 {
+		s.log.WithFields(logger.Fields{
+			"started": started,
+			"ended":   ended,
+		}).Info("Sale events updated")
+	}
+}
+
+// ToSaleEventDTO converts a domain sale event to a DTO
+func ToSaleEventDTO(saleEvent *domain.SaleEvent) *SaleEventDTO {
+	dto := &SaleEventDTO{
+		ID:          saleEvent.ID,
+		Name:        saleEvent.Name,
+		Description: saleEvent.Description,
+		StartsAt:    saleEvent.StartsAt,
+		EndsAt:      saleEvent.EndsAt,
+		Status:      saleEvent.Status,
+		OfferID:     saleEvent.OfferID,
+		CreatedBy:   saleEvent.CreatedBy,
+		StartedAt:   saleEvent.StartedAt,
+		EndedAt:     saleEvent.EndedAt,
+		CreatedAt:   saleEvent.CreatedAt,
+		UpdatedAt:   saleEvent.UpdatedAt,
+		Items:       make([]*SaleEventItemDTO, len(saleEvent.Items)),
+	}
+	for i, item := range saleEvent.Items {
+		dto.Items[i] = &SaleEventItemDTO{
+			SKUID:             item.SKUID,
+			SalePrice:         item.SalePrice,
+			RetailPrice:       item.RetailPrice,
+			PreviousSalePrice: item.PreviousSalePrice,
+			Applied:           item.Applied,
+		}
+	}
+	return dto
+}
@@ -0,0 +1,214 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// Restriction violation codes returned to the storefront
+const (
+	RestrictionDestination     = "DESTINATION_RESTRICTED"
+	RestrictionAgeVerification = "AGE_VERIFICATION_REQUIRED"
+)
+
+// ShippingRestrictionDTO represents a shipping restriction
+type ShippingRestrictionDTO struct {
+	ID                      int64     `json:"id"`
+	ProductID               *int64    `json:"product_id,omitempty"`
+	SKUID                   *int64    `json:"sku_id,omitempty"`
+	BlockedCountries        []string  `json:"blocked_countries"`
+	BlockedRegions          []string  `json:"blocked_regions"`
+	AgeVerificationRequired bool      `json:"age_verification_required"`
+	MinimumAge              int       `json:"minimum_age,omitempty"`
+	Reason                  string    `json:"reason"`
+	CreatedAt               time.Time `json:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+// ShippingRestrictionRequest is the payload to create or update a shipping restriction
+type ShippingRestrictionRequest struct {
+	ProductID               *int64   `json:"product_id"`
+	SKUID                   *int64   `json:"sku_id"`
+	BlockedCountries        []string `json:"blocked_countries"`
+	BlockedRegions          []string `json:"blocked_regions"`
+	AgeVerificationRequired bool     `json:"age_verification_required"`
+	MinimumAge              int      `json:"minimum_age" validate:"gte=0"`
+	Reason                  string   `json:"reason" validate:"required"`
+}
+
+// RestrictionCheckLine identifies one item to check against shipping restrictions
+type RestrictionCheckLine struct {
+	ProductID int64
+	SKUID     int64
+}
+
+// RestrictionViolationDTO explains why an item cannot be shipped to a destination
+type RestrictionViolationDTO struct {
+	Code          string `json:"code"`
+	ProductID     int64  `json:"product_id,omitempty"`
+	SKUID         int64  `json:"sku_id,omitempty"`
+	Reason        string `json:"reason"`
+	MinimumAge    int    `json:"minimum_age,omitempty"`
+	RestrictionID int64  `json:"restriction_id"`
+}
+
+// ShippingRestrictionService defines the application service for product shipping restrictions.
+type ShippingRestrictionService interface {
+	// CreateRestriction creates a shipping restriction.
+	CreateRestriction(ctx context.Context, req *ShippingRestrictionRequest) (*ShippingRestrictionDTO, error)
+
+	// UpdateRestriction replaces a shipping restriction.
+	UpdateRestriction(ctx context.Context, id int64, req *ShippingRestrictionRequest) (*ShippingRestrictionDTO, error)
+
+	// DeleteRestriction removes a shipping restriction.
+	DeleteRestriction(ctx context.Context, id int64) error
+
+	// ListRestrictions lists all restrictions, or those of a product and its SKUs.
+	ListRestrictions(ctx context.Context, productID *int64) ([]*ShippingRestrictionDTO, error)
+
+	// CheckDestination returns the restrictions that prevent shipping the items to a destination.
+	CheckDestination(ctx context.Context, lines []RestrictionCheckLine, country, region string, ageVerified bool) ([]*RestrictionViolationDTO, error)
+}
+
+type shippingRestrictionService struct {
+	repo domain.ShippingRestrictionRepository
+}
+
+// NewShippingRestrictionService creates a new instance of ShippingRestrictionService.
+func NewShippingRestrictionService(repo domain.ShippingRestrictionRepository) ShippingRestrictionService {
+	return &shippingRestrictionService{repo: repo}
+}
+
+func (s *shippingRestrictionService) CreateRestriction(ctx context.Context, req *ShippingRestrictionRequest) (*ShippingRestrictionDTO, error) {
+	restriction, err := domain.NewShippingRestriction(req.ProductID, req.SKUID, req.Reason)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := applyRestrictionRules(restriction, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, restriction); err != nil {
+		return nil, fmt.Errorf("failed to create shipping restriction: %w", err)
+	}
+	return ToShippingRestrictionDTO(restriction), nil
+}
+
+func (s *shippingRestrictionService) UpdateRestriction(ctx context.Context, id int64, req *ShippingRestrictionRequest) (*ShippingRestrictionDTO, error) {
+	restriction, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := restriction.Update(req.ProductID, req.SKUID, req.Reason); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := applyRestrictionRules(restriction, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, restriction); err != nil {
+		return nil, fmt.Errorf("failed to update shipping restriction: %w", err)
+	}
+	return ToShippingRestrictionDTO(restriction), nil
+}
+
+func (s *shippingRestrictionService) DeleteRestriction(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *shippingRestrictionService) ListRestrictions(ctx context.Context, productID *int64) ([]*ShippingRestrictionDTO, error) {
+	var restrictions []*domain.ShippingRestriction
+	var err error
+	if productID != nil {
+		restrictions, err = s.repo.FindByProductID(ctx, *productID)
+	} else {
+		restrictions, err = s.repo.FindAll(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipping restrictions: %w", err)
+	}
+
+	dtos := make([]*ShippingRestrictionDTO, len(restrictions))
+	for i, restriction := range restrictions {
+		dtos[i] = ToShippingRestrictionDTO(restriction)
+	}
+	return dtos, nil
+}
+
+func (s *shippingRestrictionService) CheckDestination(ctx context.Context, lines []RestrictionCheckLine, country, region string, ageVerified bool) ([]*RestrictionViolationDTO, error) {
+	violations := make([]*RestrictionViolationDTO, 0)
+	if len(lines) == 0 {
+		return violations, nil
+	}
+
+	productIDs := make([]int64, 0, len(lines))
+	skuIDs := make([]int64, 0, len(lines))
+	for _, line := range lines {
+		productIDs = append(productIDs, line.ProductID)
+		skuIDs = append(skuIDs, line.SKUID)
+	}
+	restrictions, err := s.repo.FindApplicable(ctx, productIDs, skuIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shipping restrictions: %w", err)
+	}
+
+	for _, line := range lines {
+		for _, restriction := range restrictions {
+			if !restriction.AppliesTo(line.ProductID, line.SKUID) {
+				continue
+			}
+			if restriction.BlocksDestination(country, region) {
+				violations = append(violations, &RestrictionViolationDTO{
+					Code:          RestrictionDestination,
+					ProductID:     line.ProductID,
+					SKUID:         line.SKUID,
+					Reason:        restriction.Reason,
+					RestrictionID: restriction.ID,
+				})
+			}
+			if restriction.AgeVerificationRequired && !ageVerified {
+				violations = append(violations, &RestrictionViolationDTO{
+					Code:          RestrictionAgeVerification,
+					ProductID:     line.ProductID,
+					SKUID:         line.SKUID,
+					Reason:        restriction.Reason,
+					MinimumAge:    restriction.MinimumAge,
+					RestrictionID: restriction.ID,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// applyRestrictionRules copies the destination and age rules of a request onto a restriction
+func applyRestrictionRules(restriction *domain.ShippingRestriction, req *ShippingRestrictionRequest) error {
+	restriction.SetDestinations(req.BlockedCountries, req.BlockedRegions)
+	if err := restriction.SetAgeVerification(req.AgeVerificationRequired, req.MinimumAge); err != nil {
+		return errors.ValidationError(err.Error())
+	}
+	if len(restriction.BlockedCountries) == 0 && len(restriction.BlockedRegions) == 0 && !restriction.AgeVerificationRequired {
+		return errors.ValidationError("a shipping restriction needs blocked destinations or age verification")
+	}
+	return nil
+}
+
+// ToShippingRestrictionDTO converts a domain shipping restriction to a DTO
+func ToShippingRestrictionDTO(restriction *domain.ShippingRestriction) *ShippingRestrictionDTO {
+	return &ShippingRestrictionDTO{
+		ID:                      restriction.ID,
+		ProductID:               restriction.ProductID,
+		SKUID:                   restriction.SKUID,
+		BlockedCountries:        restriction.BlockedCountries,
+		BlockedRegions:          restriction.BlockedRegions,
+		AgeVerificationRequired: restriction.AgeVerificationRequired,
+		MinimumAge:              restriction.MinimumAge,
+		Reason:                  restriction.Reason,
+		CreatedAt:               restriction.CreatedAt,
+		UpdatedAt:               restriction.UpdatedAt,
+	}
+}
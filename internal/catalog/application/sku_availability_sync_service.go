@@ -0,0 +1,294 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	inventoryApp "github.com/qhato/ecommerce/internal/inventory/application"
+	inventoryDomain "github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const (
+	defaultStockoutsLimit = 100
+	maxStockoutsLimit     = 1000
+	maxSyncSKUs           = 500 // SKUs synced per request
+)
+
+// The stock change fields read by the sync, checked against the inventory event schemas
+func init() {
+	event.RegisterContract("catalog.sku_availability_sync", inventoryDomain.EventStockChanged, "sku_ids")
+	event.RegisterContract("catalog.sku_availability_sync", domain.EventSKUAvailabilityChanged, "sku_id")
+}
+
+// SKUStockoutDTO represents a SKU the inventory sync made unavailable
+type SKUStockoutDTO struct {
+	SKUID              int64     `json:"sku_id"`
+	AvailableToPromise int       `json:"available_to_promise"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// SKUAvailabilitySyncDTO reports what a sync did with a SKU
+type SKUAvailabilitySyncDTO struct {
+	SKUID              int64  `json:"sku_id"`
+	Available          bool   `json:"available"`
+	Changed            bool   `json:"changed"`
+	Tracked            bool   `json:"tracked"`
+	AvailableToPromise int    `json:"available_to_promise"`
+	Error              string `json:"error,omitempty"`
+}
+
+// SyncSKUAvailabilityRequest is the payload to sync SKUs by hand, e.g. after a bulk fix
+type SyncSKUAvailabilityRequest struct {
+	SKUIDs []int64 `json:"sku_ids"`
+}
+
+// SKUAvailabilitySyncService keeps the availability flag of SKUs in line with their
+// inventory: a SKU whose available-to-promise across warehouses runs out is made
+// unavailable, and made available again once it is restocked. Thresholds apart leave a
+// gap so a SKU selling its last units while restocked does not flap. SKUs made
+// unavailable by an admin are never made available by the sync.
+type SKUAvailabilitySyncService interface {
+	// SyncSKUs flips the SKUs whose stock crossed a threshold and reports each SKU.
+	SyncSKUs(ctx context.Context, skuIDs []int64) ([]*SKUAvailabilitySyncDTO, error)
+
+	// ListStockouts lists the SKUs the sync made unavailable, oldest first.
+	ListStockouts(ctx context.Context, limit int) ([]*SKUStockoutDTO, error)
+
+	// Subscribe syncs SKUs when their stock changes, and forgets the stockouts of
+	// SKUs whose availability an admin changes.
+	Subscribe(bus event.Bus) error
+}
+
+// SKUAvailabilitySyncConfig configures the inventory sync of SKU availability
+type SKUAvailabilitySyncConfig struct {
+	Hysteresis domain.StockHysteresis
+}
+
+type skuAvailabilitySyncService struct {
+	skuRepo      domain.SKURepository
+	stockoutRepo domain.SKUStockoutRepository
+	atpService   inventoryApp.ATPService
+	eventBus     event.Bus
+	cfg          SKUAvailabilitySyncConfig
+	log          *logger.Logger
+
+	// mu serializes syncs, so concurrent stock changes of a SKU flip it once
+	mu sync.Mutex
+}
+
+// NewSKUAvailabilitySyncService creates a new instance of SKUAvailabilitySyncService
+func NewSKUAvailabilitySyncService(
+	skuRepo domain.SKURepository,
+	stockoutRepo domain.SKUStockoutRepository,
+	atpService inventoryApp.ATPService,
+	eventBus event.Bus,
+	cfg SKUAvailabilitySyncConfig,
+	log *logger.Logger,
+) SKUAvailabilitySyncService {
+	return &skuAvailabilitySyncService{
+		skuRepo:      skuRepo,
+		stockoutRepo: stockoutRepo,
+		atpService:   atpService,
+		eventBus:     eventBus,
+		cfg:          cfg,
+		log:          log,
+	}
+}
+
+func (s *skuAvailabilitySyncService) SyncSKUs(ctx context.Context, skuIDs []int64) ([]*SKUAvailabilitySyncDTO, error) {
+	if len(skuIDs) == 0 {
+		return nil, errors.ValidationError("sku_ids is required")
+	}
+	if len(skuIDs) > maxSyncSKUs {
+		return nil, errors.ValidationError("too many SKUs to sync at once").WithDetail("max", maxSyncSKUs)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, len(skuIDs))
+	for i, skuID := range skuIDs {
+		ids[i] = strconv.FormatInt(skuID, 10)
+	}
+	atps, err := s.atpService.GetAvailableToPromise(ctx, ids)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to read available to promise")
+	}
+	stock := make(map[int64]*inventoryApp.AvailableToPromiseDTO, len(atps))
+	for _, atp := range atps {
+		if id, err := strconv.ParseInt(atp.SKUID, 10, 64); err == nil {
+			stock[id] = atp
+		}
+	}
+	stockouts, err := s.stockoutRepo.FindBySKUIDs(ctx, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SKUAvailabilitySyncDTO, 0, len(skuIDs))
+	seen := make(map[int64]bool, len(skuIDs))
+	for _, skuID := range skuIDs {
+		if seen[skuID] {
+			continue
+		}
+		seen[skuID] = true
+
+		result, err := s.sync(ctx, skuID, stock[skuID], stockouts[skuID] != nil)
+		if err != nil {
+			s.log.WithError(err).WithField("sku_id", skuID).Warn("Failed to sync SKU availability")
+			result = &SKUAvailabilitySyncDTO{SKUID: skuID, Error: err.Error()}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// sync flips a SKU when its stock crossed a threshold
+func (s *skuAvailabilitySyncService) sync(ctx context.Context, skuID int64, atp *inventoryApp.AvailableToPromiseDTO, stockedOut bool) (*SKUAvailabilitySyncDTO, error) {
+	sku, err := s.skuRepo.FindByID(ctx, skuID)
+	if err != nil {
+		return nil, err
+	}
+	if sku == nil {
+		return nil, errors.NotFound("SKU")
+	}
+
+	stock := domain.SKUStock{Tracked: tracksInventory(sku.InventoryType) && atp != nil && len(atp.Warehouses) > 0}
+	if atp != nil {
+		stock.AvailableToPromise = atp.Available
+		stock.AllowBackorder = atp.AllowBackorder
+	}
+	result := &SKUAvailabilitySyncDTO{
+		SKUID:              skuID,
+		Available:          sku.Available,
+		Tracked:            stock.Tracked,
+		AvailableToPromise: stock.AvailableToPromise,
+	}
+
+	decision := s.cfg.Hysteresis.Decide(sku.Available, stockedOut, stock)
+	if decision == domain.StockClearStockout {
+		// An admin made the SKU available again; it is judged afresh from now on
+		if err := s.stockoutRepo.Delete(ctx, skuID); err != nil {
+			return nil, err
+		}
+		decision = s.cfg.Hysteresis.Decide(sku.Available, false, stock)
+	}
+
+	switch decision {
+	case domain.StockTakeOut:
+		if err := s.skuRepo.UpdateAvailability(ctx, skuID, false); err != nil {
+			return nil, err
+		}
+		stockout := &domain.SKUStockout{SKUID: skuID, AvailableToPromise: stock.AvailableToPromise, CreatedAt: time.Now()}
+		if err := s.stockoutRepo.Save(ctx, stockout); err != nil {
+			return nil, err
+		}
+	case domain.StockPutBack:
+		if err := s.skuRepo.UpdateAvailability(ctx, skuID, true); err != nil {
+			return nil, err
+		}
+		if err := s.stockoutRepo.Delete(ctx, skuID); err != nil {
+			return nil, err
+		}
+	default:
+		return result, nil
+	}
+
+	result.Available = decision == domain.StockPutBack
+	result.Changed = true
+	s.publish(ctx, skuID, result.Available)
+	s.log.WithFields(logger.Fields{
+		"sku_id":               skuID,
+		"available":            result.Available,
+		"available_to_promise": stock.AvailableToPromise,
+	}).Info("SKU availability synced from inventory")
+	return result, nil
+}
+
+func (s *skuAvailabilitySyncService) publish(ctx context.Context, skuID int64, available bool) {
+	if s.eventBus == nil {
+		return
+	}
+	evt := domain.NewSKUAvailabilityChangedEvent(skuID, available)
+	evt.Source = domain.AvailabilitySourceInventorySync
+	if err := s.eventBus.Publish(ctx, evt); err != nil {
+		s.log.WithError(err).Error("failed to publish SKU availability changed event")
+	}
+}
+
+func (s *skuAvailabilitySyncService) ListStockouts(ctx context.Context, limit int) ([]*SKUStockoutDTO, error) {
+	if limit <= 0 {
+		limit = defaultStockoutsLimit
+	}
+	if limit > maxStockoutsLimit {
+		limit = maxStockoutsLimit
+	}
+	stockouts, err := s.stockoutRepo.FindAll(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*SKUStockoutDTO, len(stockouts))
+	for i, stockout := range stockouts {
+		dtos[i] = &SKUStockoutDTO{
+			SKUID:              stockout.SKUID,
+			AvailableToPromise: stockout.AvailableToPromise,
+			CreatedAt:          stockout.CreatedAt,
+		}
+	}
+	return dtos, nil
+}
+
+func (s *skuAvailabilitySyncService) Subscribe(bus event.Bus) error {
+	if err := bus.Subscribe(inventoryDomain.EventStockChanged, s.handleStockChanged); err != nil {
+		return err
+	}
+	return bus.Subscribe(domain.EventSKUAvailabilityChanged, s.handleAvailabilityChanged)
+}
+
+func (s *skuAvailabilitySyncService) handleStockChanged(ctx context.Context, evt event.Event) error {
+	e, ok := evt.(*inventoryDomain.StockChangedEvent)
+	if !ok {
+		return nil
+	}
+	skuIDs := make([]int64, 0, len(e.SKUIDs))
+	for _, id := range e.SKUIDs {
+		skuID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue // Inventory of SKUs outside the catalog
+		}
+		skuIDs = append(skuIDs, skuID)
+	}
+	for start := 0; start < len(skuIDs); start += maxSyncSKUs {
+		end := min(start+maxSyncSKUs, len(skuIDs))
+		if _, err := s.SyncSKUs(ctx, skuIDs[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleAvailabilityChanged forgets the stockout of a SKU an admin made
+// unavailable, so restocking it does not override the admin
+func (s *skuAvailabilitySyncService) handleAvailabilityChanged(ctx context.Context, evt event.Event) error {
+	e, ok := evt.(*domain.SKUAvailabilityChangedEvent)
+	if !ok || e.Source == domain.AvailabilitySourceInventorySync || e.Available {
+		return nil
+	}
+	return s.stockoutRepo.Delete(ctx, e.SKUID)
+}
+
+// tracksInventory checks whether the availability of SKUs of an inventory type follows their stock
+func tracksInventory(inventoryType string) bool {
+	switch inventoryType {
+	case "ALWAYS_AVAILABLE", "UNAVAILABLE", "PRODUCT_BUNDLE":
+		return false
+	default:
+		return true
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
 )
 
 // SkuService defines the application service for SKU-related operations.
@@ -189,7 +190,7 @@ func (s *skuService) GetSkuByID(ctx context.Context, id int64) (*SkuDTO, error)
 		return nil, fmt.Errorf("failed to find SKU by ID: %w", err)
 	}
 	if sku == nil {
-		return nil, fmt.Errorf("SKU with ID %d not found", id)
+		return nil, errors.NotFound(fmt.Sprintf("SKU with ID %d", id))
 	}
 	return ToSkuDTO(sku), nil
 }
@@ -0,0 +1,245 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/slug"
+)
+
+// maxURLKeySuffix bounds the numbered alternatives tried for a taken key
+const maxURLKeySuffix = 1000
+
+// URLKeysDTO represents the default and localized URL keys of a product or category
+type URLKeysDTO struct {
+	Entity    string                `json:"entity"`
+	EntityID  int64                 `json:"entity_id"`
+	URLKey    string                `json:"url_key"` // Default key, served wherever no localized key applies
+	Localized []*LocalizedURLKeyDTO `json:"localized"`
+}
+
+// LocalizedURLKeyDTO represents the URL key of an entity on a site and/or locale
+type LocalizedURLKeyDTO struct {
+	SiteID    string    `json:"site_id,omitempty"`
+	Locale    string    `json:"locale,omitempty"`
+	URLKey    string    `json:"url_key"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LocalizedURLKeyRequest sets the URL key of an entity on a site and/or locale.
+// Without url_key, one is generated from name, e.g. the translated product name.
+type LocalizedURLKeyRequest struct {
+	SiteID string `json:"site_id"`
+	Locale string `json:"locale"`
+	URLKey string `json:"url_key"`
+	Name   string `json:"name"`
+}
+
+// URLKeyCheckDTO tells admin clients whether a URL key can be used and, when it
+// cannot, the key to use instead
+type URLKeyCheckDTO struct {
+	URLKey     string `json:"url_key"`
+	Available  bool   `json:"available"`
+	OwnerID    int64  `json:"owner_id,omitempty"` // Entity already using the key
+	Suggestion string `json:"suggestion"`
+}
+
+// URLKeyService defines the application service for product and category URL keys.
+type URLKeyService interface {
+	// AssignURLKey returns the key an entity gets in a scope: key itself once checked to
+	// be a free slug, or a free slug generated from name when key is empty. A taken key
+	// fails with a URL key taken error suggesting a free alternative.
+	AssignURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, scope domain.URLKeyScope, key, name string) (string, error)
+
+	// CheckURLKey reports whether key, or the key generated from name, is free for an
+	// entity in a scope, suggesting a free alternative.
+	CheckURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, scope domain.URLKeyScope, key, name string) (*URLKeyCheckDTO, error)
+
+	// GetURLKeys returns the default and localized keys of an entity.
+	GetURLKeys(ctx context.Context, entity domain.URLKeyEntity, entityID int64) (*URLKeysDTO, error)
+
+	// SetLocalizedURLKey sets the key of an entity on a site and/or locale.
+	SetLocalizedURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, req *LocalizedURLKeyRequest) (*URLKeysDTO, error)
+
+	// DeleteLocalizedURLKey removes the key of an entity on a site and/or locale, so its default key applies there.
+	DeleteLocalizedURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, scope domain.URLKeyScope) error
+
+	// ResolveURLKey returns the entity a key leads to on a site and locale.
+	ResolveURLKey(ctx context.Context, entity domain.URLKeyEntity, scope domain.URLKeyScope, key string) (int64, error)
+}
+
+type urlKeyService struct {
+	repo         domain.URLKeyRepository
+	productRepo  domain.ProductRepository
+	categoryRepo domain.CategoryRepository
+}
+
+// NewURLKeyService creates a new instance of URLKeyService.
+func NewURLKeyService(repo domain.URLKeyRepository, productRepo domain.ProductRepository, categoryRepo domain.CategoryRepository) URLKeyService {
+	return &urlKeyService{
+		repo:         repo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *urlKeyService) AssignURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, scope domain.URLKeyScope, key, name string) (string, error) {
+	check, err := s.CheckURLKey(ctx, entity, entityID, scope, key, name)
+	if err != nil {
+		return "", err
+	}
+	if check.Available {
+		return check.URLKey, nil
+	}
+	if strings.TrimSpace(key) == "" {
+		// Generated keys take the first free alternative
+		return check.Suggestion, nil
+	}
+	return "", errors.URLKeyTaken(check.URLKey, check.Suggestion)
+}
+
+func (s *urlKeyService) CheckURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, scope domain.URLKeyScope, key, name string) (*URLKeyCheckDTO, error) {
+	if !entity.IsValid() {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown URL key entity %q, expected product or category", entity))
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		key = slug.Make(name)
+		if key == "" {
+			return nil, errors.ValidationError("url_key is required when the name has no letters or digits")
+		}
+	} else if !slug.IsValid(key) {
+		message := fmt.Sprintf("url_key %q must be lower case letters and digits separated by hyphens", key)
+		if suggestion := slug.Make(key); suggestion != "" {
+			message += fmt.Sprintf(", e.g. %q", suggestion)
+		}
+		return nil, errors.ValidationError(message)
+	}
+
+	ownerID, err := s.repo.FindOwner(ctx, entity, scope, key, entityID)
+	if err != nil {
+		return nil, err
+	}
+	check := &URLKeyCheckDTO{URLKey: key, Available: ownerID == 0, OwnerID: ownerID, Suggestion: key}
+	if check.Available {
+		return check, nil
+	}
+
+	taken, err := s.repo.FindTaken(ctx, entity, scope, key, entityID)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool, len(taken))
+	for _, t := range taken {
+		used[t] = true
+	}
+	for n := 2; n <= maxURLKeySuffix; n++ {
+		if candidate := slug.WithSuffix(key, n); !used[candidate] {
+			check.Suggestion = candidate
+			return check, nil
+		}
+	}
+	return nil, errors.Conflict(fmt.Sprintf("URL key %q and its numbered alternatives are all in use", key))
+}
+
+func (s *urlKeyService) GetURLKeys(ctx context.Context, entity domain.URLKeyEntity, entityID int64) (*URLKeysDTO, error) {
+	defaultKey, _, err := s.entity(ctx, entity, entityID)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s.repo.FindByEntity(ctx, entity, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list URL keys: %w", err)
+	}
+
+	dto := &URLKeysDTO{
+		Entity:    string(entity),
+		EntityID:  entityID,
+		URLKey:    defaultKey,
+		Localized: make([]*LocalizedURLKeyDTO, len(keys)),
+	}
+	for i, key := range keys {
+		dto.Localized[i] = &LocalizedURLKeyDTO{
+			SiteID:    key.Scope.SiteID,
+			Locale:    key.Scope.Locale,
+			URLKey:    key.URLKey,
+			UpdatedAt: key.UpdatedAt,
+		}
+	}
+	return dto, nil
+}
+
+func (s *urlKeyService) SetLocalizedURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, req *LocalizedURLKeyRequest) (*URLKeysDTO, error) {
+	_, name, err := s.entity(ctx, entity, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Name) != "" {
+		name = req.Name
+	}
+
+	scope := domain.URLKeyScope{SiteID: strings.TrimSpace(req.SiteID), Locale: strings.TrimSpace(req.Locale)}
+	if scope.IsDefault() {
+		return nil, errors.ValidationError("site_id or locale is required; the default URL key is set on the product or category")
+	}
+	urlKey, err := s.AssignURLKey(ctx, entity, entityID, scope, req.URLKey, name)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := domain.NewLocalizedURLKey(entity, entityID, scope, urlKey)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.Save(ctx, key); err != nil {
+		return nil, err
+	}
+	return s.GetURLKeys(ctx, entity, entityID)
+}
+
+func (s *urlKeyService) DeleteLocalizedURLKey(ctx context.Context, entity domain.URLKeyEntity, entityID int64, scope domain.URLKeyScope) error {
+	if !entity.IsValid() {
+		return errors.ValidationError(fmt.Sprintf("unknown URL key entity %q, expected product or category", entity))
+	}
+	if scope.IsDefault() {
+		return errors.ValidationError("site_id or locale is required; the default URL key cannot be removed")
+	}
+	return s.repo.Delete(ctx, entity, entityID, scope)
+}
+
+func (s *urlKeyService) ResolveURLKey(ctx context.Context, entity domain.URLKeyEntity, scope domain.URLKeyScope, key string) (int64, error) {
+	entityID, err := s.repo.FindOwner(ctx, entity, scope, key, 0)
+	if err != nil {
+		return 0, err
+	}
+	if entityID == 0 {
+		return 0, errors.NotFound(string(entity))
+	}
+	return entityID, nil
+}
+
+// entity returns the default key of a product or category and the name keys are
+// generated from: the model of a product, the name of a category
+func (s *urlKeyService) entity(ctx context.Context, entity domain.URLKeyEntity, entityID int64) (string, string, error) {
+	switch entity {
+	case domain.URLKeyEntityProduct:
+		product, err := s.productRepo.FindByID(ctx, entityID)
+		if err != nil {
+			return "", "", err
+		}
+		return product.URLKey, product.Model, nil
+	case domain.URLKeyEntityCategory:
+		category, err := s.categoryRepo.FindByID(ctx, entityID)
+		if err != nil {
+			return "", "", err
+		}
+		return category.URLKey, category.Name, nil
+	default:
+		return "", "", errors.ValidationError(fmt.Sprintf("unknown URL key entity %q, expected product or category", entity))
+	}
+}
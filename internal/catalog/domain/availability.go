@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// OutOfStockPolicy controls how out-of-stock products appear in storefront listings
+type OutOfStockPolicy string
+
+const (
+	// OutOfStockBadge keeps out-of-stock products in place, flagged as out of stock
+	OutOfStockBadge OutOfStockPolicy = "badge"
+	// OutOfStockBottom lists out-of-stock products after in-stock ones
+	OutOfStockBottom OutOfStockPolicy = "bottom"
+	// OutOfStockHide excludes out-of-stock products from listings
+	OutOfStockHide OutOfStockPolicy = "hide"
+)
+
+// ProductAvailabilityRepository maintains the product availability read model
+// that listings join against instead of looking up inventory per item
+type ProductAvailabilityRepository interface {
+	// Refresh rebuilds the read model from SKU inventory levels
+	Refresh(ctx context.Context) error
+}
+
+// AvailabilitySourceInventorySync marks the SKU availability changes made by the
+// inventory sync, as opposed to those an admin makes
+const AvailabilitySourceInventorySync = "inventory_sync"
+
+// StockHysteresis sets when the inventory sync flips a SKU. A SKU goes out of
+// stock once its available-to-promise falls to OutOfStockAt and comes back once
+// it reaches BackInStockAt, so a SKU hovering around zero does not flap.
+type StockHysteresis struct {
+	OutOfStockAt  int
+	BackInStockAt int
+}
+
+// SKUStock is the stock of a SKU the inventory sync decides on
+type SKUStock struct {
+	Tracked            bool // Has inventory levels and an inventory type that follows them
+	AvailableToPromise int  // Across warehouses
+	AllowBackorder     bool // Any warehouse sells past zero
+}
+
+// SKUStockout records a SKU the inventory sync took out of stock. The sync only
+// puts back the SKUs it took out, so SKUs an admin made unavailable stay so.
+type SKUStockout struct {
+	SKUID              int64
+	AvailableToPromise int // When it was taken out
+	CreatedAt          time.Time
+}
+
+// StockDecision is what the inventory sync does with a SKU
+type StockDecision int
+
+const (
+	StockKeep          StockDecision = iota // Leave the SKU as it is
+	StockTakeOut                            // Make the SKU unavailable and record the stockout
+	StockPutBack                            // Make the SKU available and clear its stockout
+	StockClearStockout                      // Forget the stockout of a SKU an admin made available again
+)
+
+// Decide returns what the inventory sync does with a SKU, given whether it is
+// available, whether the sync took it out and its current stock
+func (h StockHysteresis) Decide(available, stockedOut bool, stock SKUStock) StockDecision {
+	sellable := !stock.Tracked || stock.AllowBackorder
+	switch {
+	case stockedOut && available:
+		return StockClearStockout
+	case stockedOut:
+		if sellable || stock.AvailableToPromise >= h.BackInStockAt {
+			return StockPutBack
+		}
+	case available:
+		if !sellable && stock.AvailableToPromise <= h.OutOfStockAt {
+			return StockTakeOut
+		}
+	}
+	return StockKeep
+}
+
+// SKUStockoutRepository stores the SKUs the inventory sync took out of stock
+type SKUStockoutRepository interface {
+	// Save records a stockout
+	Save(ctx context.Context, stockout *SKUStockout) error
+
+	// FindBySKUIDs returns the stockouts of the given SKUs, by SKU ID
+	FindBySKUIDs(ctx context.Context, skuIDs []int64) (map[int64]*SKUStockout, error)
+
+	// FindAll lists the stockouts, oldest first
+	FindAll(ctx context.Context, limit int) ([]*SKUStockout, error)
+
+	// Delete forgets the stockout of a SKU
+	Delete(ctx context.Context, skuID int64) error
+}
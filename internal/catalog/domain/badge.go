@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Computed badge codes. Manual badges may use these codes too, e.g. to flag a
+// product as new outside its computed window.
+const (
+	BadgeNew        = "NEW"        // Activated within the new badge window
+	BadgeSale       = "SALE"       // An available SKU sells below its retail price
+	BadgeBestseller = "BESTSELLER" // Enough units ordered within the bestseller window
+)
+
+// computedBadgeLabels are the display labels of computed badges, in display order
+var computedBadgeLabels = []struct{ Code, Label string }{
+	{BadgeNew, "New"},
+	{BadgeSale, "Sale"},
+	{BadgeBestseller, "Bestseller"},
+}
+
+// IsComputedBadge checks if a badge code is computed from catalog and order data
+func IsComputedBadge(code string) bool {
+	for _, badge := range computedBadgeLabels {
+		if badge.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeBadgeCode trims and upper-cases a badge code
+func NormalizeBadgeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// BadgeRules configures the computed badges
+type BadgeRules struct {
+	NewDays            int // Days after activation a product is new
+	BestsellerDays     int // Order history considered for the bestseller badge
+	BestsellerMinUnits int // Units ordered within the window to be a bestseller
+}
+
+// ProductBadge is a manual badge shown on a product, optionally scheduled for a campaign
+type ProductBadge struct {
+	ID        int64
+	ProductID int64
+	Code      string
+	Label     string
+	Campaign  string
+	StartsAt  *time.Time
+	EndsAt    *time.Time // Exclusive
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewProductBadge creates a new manual product badge
+func NewProductBadge(productID int64, code, label string) (*ProductBadge, error) {
+	if productID <= 0 {
+		return nil, NewDomainError("product ID is required")
+	}
+	now := time.Now()
+	badge := &ProductBadge{ProductID: productID, CreatedAt: now}
+	if err := badge.Update(code, label); err != nil {
+		return nil, err
+	}
+	return badge, nil
+}
+
+// Update changes the code and label of the badge
+func (b *ProductBadge) Update(code, label string) error {
+	code = NormalizeBadgeCode(code)
+	if code == "" {
+		return NewDomainError("badge code is required")
+	}
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return NewDomainError("badge label is required")
+	}
+	b.Code = code
+	b.Label = label
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+// Schedule sets the campaign and the window the badge is shown in
+func (b *ProductBadge) Schedule(campaign string, startsAt, endsAt *time.Time) error {
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return NewDomainError("badge end must be after its start")
+	}
+	b.Campaign = strings.TrimSpace(campaign)
+	b.StartsAt = startsAt
+	b.EndsAt = endsAt
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsActiveAt checks if the badge is shown at the given time
+func (b *ProductBadge) IsActiveAt(t time.Time) bool {
+	if b.StartsAt != nil && t.Before(*b.StartsAt) {
+		return false
+	}
+	if b.EndsAt != nil && !t.Before(*b.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// AppliedBadge is a badge shown on a product
+type AppliedBadge struct {
+	Code     string
+	Label    string
+	Computed bool
+}
+
+// ApplyBadges combines the computed badge codes of a product with its active
+// manual badges. Computed badges come first; a manual badge with the same code
+// replaces the computed label.
+func ApplyBadges(computed []string, manual []*ProductBadge) []AppliedBadge {
+	applied := make([]AppliedBadge, 0, len(computed)+len(manual))
+	index := make(map[string]int)
+	for _, badge := range computedBadgeLabels {
+		for _, code := range computed {
+			if code == badge.Code {
+				index[code] = len(applied)
+				applied = append(applied, AppliedBadge{Code: code, Label: badge.Label, Computed: true})
+				break
+			}
+		}
+	}
+	for _, badge := range manual {
+		if i, ok := index[badge.Code]; ok {
+			applied[i].Label = badge.Label
+			applied[i].Computed = false
+			continue
+		}
+		index[badge.Code] = len(applied)
+		applied = append(applied, AppliedBadge{Code: badge.Code, Label: badge.Label})
+	}
+	return applied
+}
+
+// BadgeCriteria restricts a product listing to products showing a badge at a time
+type BadgeCriteria struct {
+	Code  string
+	Rules BadgeRules
+	At    time.Time
+}
+
+// ProductBadgeRepository defines the interface for product badge persistence
+type ProductBadgeRepository interface {
+	// Save creates or updates a manual badge
+	Save(ctx context.Context, badge *ProductBadge) error
+
+	// Delete removes a manual badge
+	Delete(ctx context.Context, id int64) error
+
+	// FindByID retrieves a manual badge by ID
+	FindByID(ctx context.Context, id int64) (*ProductBadge, error)
+
+	// FindByProductID retrieves the manual badges of a product, scheduled or not
+	FindByProductID(ctx context.Context, productID int64) ([]*ProductBadge, error)
+
+	// FindActiveByProductIDs retrieves the manual badges of the products shown at a time
+	FindActiveByProductIDs(ctx context.Context, productIDs []int64, at time.Time) ([]*ProductBadge, error)
+
+	// FindComputedBadges returns the computed badge codes of the products at a time
+	FindComputedBadges(ctx context.Context, productIDs []int64, rules BadgeRules, at time.Time) (map[int64][]string, error)
+}
@@ -0,0 +1,564 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CatalogSnapshotFormatVersion is the version of the snapshot format written by exports
+const CatalogSnapshotFormatVersion = 1
+
+// CatalogSnapshot is a portable copy of the catalog, used to promote content between
+// environments (e.g. staging to production). IDs differ between environments, so
+// entities are identified and reference each other by key: categories and products
+// by URL, SKUs by UPC or external ID.
+type CatalogSnapshot struct {
+	FormatVersion int                 `json:"format_version"`
+	Source        string              `json:"source,omitempty"` // Environment the snapshot was exported from
+	ExportedAt    time.Time           `json:"exported_at"`
+	Categories    []*CategorySnapshot `json:"categories"`
+	Products      []*ProductSnapshot  `json:"products"`
+	SKUs          []*SKUSnapshot      `json:"skus"`
+}
+
+// CategorySnapshot is the portable form of a category
+type CategorySnapshot struct {
+	Key                  string     `json:"key"`
+	Name                 string     `json:"name"`
+	Description          string     `json:"description,omitempty"`
+	LongDescription      string     `json:"long_description,omitempty"`
+	URL                  string     `json:"url,omitempty"`
+	URLKey               string     `json:"url_key,omitempty"`
+	MetaTitle            string     `json:"meta_title,omitempty"`
+	MetaDescription      string     `json:"meta_description,omitempty"`
+	DisplayTemplate      string     `json:"display_template,omitempty"`
+	ExternalID           string     `json:"external_id,omitempty"`
+	TaxCode              string     `json:"tax_code,omitempty"`
+	FulfillmentType      string     `json:"fulfillment_type,omitempty"`
+	InventoryType        string     `json:"inventory_type,omitempty"`
+	RootDisplayOrder     float64    `json:"root_display_order"`
+	OverrideGeneratedURL bool       `json:"override_generated_url"`
+	ParentKey            string     `json:"parent_key,omitempty"`
+	ActiveStartDate      *time.Time `json:"active_start_date,omitempty"`
+	ActiveEndDate        *time.Time `json:"active_end_date,omitempty"`
+}
+
+// ProductSnapshot is the portable form of a product
+type ProductSnapshot struct {
+	Key                         string     `json:"key"`
+	URL                         string     `json:"url,omitempty"`
+	URLKey                      string     `json:"url_key,omitempty"`
+	CanonicalURL                string     `json:"canonical_url,omitempty"`
+	Manufacture                 string     `json:"manufacture,omitempty"`
+	Model                       string     `json:"model,omitempty"`
+	MetaTitle                   string     `json:"meta_title,omitempty"`
+	MetaDescription             string     `json:"meta_description,omitempty"`
+	DisplayTemplate             string     `json:"display_template,omitempty"`
+	CanSellWithoutOptions       bool       `json:"can_sell_without_options"`
+	EnableDefaultSKUInInventory bool       `json:"enable_default_sku_in_inventory"`
+	OverrideGeneratedURL        bool       `json:"override_generated_url"`
+	DefaultCategoryKey          string     `json:"default_category_key,omitempty"`
+	DefaultSKUKey               string     `json:"default_sku_key,omitempty"`
+	ActiveStartDate             *time.Time `json:"active_start_date,omitempty"`
+	ActiveEndDate               *time.Time `json:"active_end_date,omitempty"`
+}
+
+// SKUSnapshot is the portable form of a SKU
+type SKUSnapshot struct {
+	Key                    string     `json:"key"`
+	Name                   string     `json:"name"`
+	Description            string     `json:"description,omitempty"`
+	LongDescription        string     `json:"long_description,omitempty"`
+	UPC                    string     `json:"upc,omitempty"`
+	ExternalID             string     `json:"external_id,omitempty"`
+	URLKey                 string     `json:"url_key,omitempty"`
+	CurrencyCode           string     `json:"currency_code,omitempty"`
+	RetailPrice            float64    `json:"retail_price"`
+	SalePrice              float64    `json:"sale_price"`
+	Cost                   float64    `json:"cost"`
+	Available              bool       `json:"available"`
+	Discountable           bool       `json:"discountable"`
+	Taxable                bool       `json:"taxable"`
+	TaxCode                string     `json:"tax_code,omitempty"`
+	InventoryType          string     `json:"inventory_type,omitempty"`
+	FulfillmentType        string     `json:"fulfillment_type,omitempty"`
+	DisplayTemplate        string     `json:"display_template,omitempty"`
+	Weight                 float64    `json:"weight"`
+	WeightUnitOfMeasure    string     `json:"weight_unit_of_measure,omitempty"`
+	Height                 float64    `json:"height"`
+	Width                  float64    `json:"width"`
+	Depth                  float64    `json:"depth"`
+	Girth                  float64    `json:"girth"`
+	DimensionUnitOfMeasure string     `json:"dimension_unit_of_measure,omitempty"`
+	ContainerShape         string     `json:"container_shape,omitempty"`
+	ContainerSize          string     `json:"container_size,omitempty"`
+	IsMachineSortable      bool       `json:"is_machine_sortable"`
+	ProductKey             string     `json:"product_key,omitempty"`
+	ActiveStartDate        *time.Time `json:"active_start_date,omitempty"`
+	ActiveEndDate          *time.Time `json:"active_end_date,omitempty"`
+}
+
+// CategorySnapshotKey returns the key identifying a category across environments
+func CategorySnapshotKey(c *Category) string {
+	if c.URL != "" {
+		return c.URL
+	}
+	return "category:" + strconv.FormatInt(c.ID, 10)
+}
+
+// ProductSnapshotKey returns the key identifying a product across environments
+func ProductSnapshotKey(p *Product) string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return "product:" + strconv.FormatInt(p.ID, 10)
+}
+
+// SKUSnapshotKey returns the key identifying a SKU across environments
+func SKUSnapshotKey(s *SKU) string {
+	switch {
+	case s.UPC != "":
+		return "upc:" + s.UPC
+	case s.ExternalID != "":
+		return "external:" + s.ExternalID
+	default:
+		return "sku:" + strconv.FormatInt(s.ID, 10)
+	}
+}
+
+// NewCatalogSnapshot builds a snapshot of catalog entities, replacing ID references by keys.
+// References to entities outside the snapshot are dropped.
+func NewCatalogSnapshot(source string, categories []*Category, products []*Product, skus []*SKU) *CatalogSnapshot {
+	categoryKeys := make(map[int64]string, len(categories))
+	for _, c := range categories {
+		categoryKeys[c.ID] = CategorySnapshotKey(c)
+	}
+	productKeys := make(map[int64]string, len(products))
+	for _, p := range products {
+		productKeys[p.ID] = ProductSnapshotKey(p)
+	}
+	skuKeys := make(map[int64]string, len(skus))
+	for _, s := range skus {
+		skuKeys[s.ID] = SKUSnapshotKey(s)
+	}
+	keyOf := func(keys map[int64]string, id *int64) string {
+		if id == nil {
+			return ""
+		}
+		return keys[*id]
+	}
+
+	snapshot := &CatalogSnapshot{
+		FormatVersion: CatalogSnapshotFormatVersion,
+		Source:        source,
+		ExportedAt:    time.Now().UTC(),
+		Categories:    make([]*CategorySnapshot, 0, len(categories)),
+		Products:      make([]*ProductSnapshot, 0, len(products)),
+		SKUs:          make([]*SKUSnapshot, 0, len(skus)),
+	}
+	for _, c := range categories {
+		snapshot.Categories = append(snapshot.Categories, &CategorySnapshot{
+			Key:                  categoryKeys[c.ID],
+			Name:                 c.Name,
+			Description:          c.Description,
+			LongDescription:      c.LongDescription,
+			URL:                  c.URL,
+			URLKey:               c.URLKey,
+			MetaTitle:            c.MetaTitle,
+			MetaDescription:      c.MetaDescription,
+			DisplayTemplate:      c.DisplayTemplate,
+			ExternalID:           c.ExternalID,
+			TaxCode:              c.TaxCode,
+			FulfillmentType:      c.FulfillmentType,
+			InventoryType:        c.InventoryType,
+			RootDisplayOrder:     c.RootDisplayOrder,
+			OverrideGeneratedURL: c.OverrideGeneratedURL,
+			ParentKey:            keyOf(categoryKeys, c.DefaultParentCategoryID),
+			ActiveStartDate:      c.ActiveStartDate,
+			ActiveEndDate:        c.ActiveEndDate,
+		})
+	}
+	for _, p := range products {
+		snapshot.Products = append(snapshot.Products, &ProductSnapshot{
+			Key:                         productKeys[p.ID],
+			URL:                         p.URL,
+			URLKey:                      p.URLKey,
+			CanonicalURL:                p.CanonicalURL,
+			Manufacture:                 p.Manufacture,
+			Model:                       p.Model,
+			MetaTitle:                   p.MetaTitle,
+			MetaDescription:             p.MetaDescription,
+			DisplayTemplate:             p.DisplayTemplate,
+			CanSellWithoutOptions:       p.CanSellWithoutOptions,
+			EnableDefaultSKUInInventory: p.EnableDefaultSKUInInventory,
+			OverrideGeneratedURL:        p.OverrideGeneratedURL,
+			DefaultCategoryKey:          keyOf(categoryKeys, p.DefaultCategoryID),
+			DefaultSKUKey:               keyOf(skuKeys, p.DefaultSkuID),
+			ActiveStartDate:             p.ActiveStartDate,
+			ActiveEndDate:               p.ActiveEndDate,
+		})
+	}
+	for _, s := range skus {
+		snapshot.SKUs = append(snapshot.SKUs, &SKUSnapshot{
+			Key:                    skuKeys[s.ID],
+			Name:                   s.Name,
+			Description:            s.Description,
+			LongDescription:        s.LongDescription,
+			UPC:                    s.UPC,
+			ExternalID:             s.ExternalID,
+			URLKey:                 s.URLKey,
+			CurrencyCode:           s.CurrencyCode,
+			RetailPrice:            s.RetailPrice,
+			SalePrice:              s.SalePrice,
+			Cost:                   s.Cost,
+			Available:              s.Available,
+			Discountable:           s.Discountable,
+			Taxable:                s.Taxable,
+			TaxCode:                s.TaxCode,
+			InventoryType:          s.InventoryType,
+			FulfillmentType:        s.FulfillmentType,
+			DisplayTemplate:        s.DisplayTemplate,
+			Weight:                 s.Weight,
+			WeightUnitOfMeasure:    s.WeightUnitOfMeasure,
+			Height:                 s.Height,
+			Width:                  s.Width,
+			Depth:                  s.Depth,
+			Girth:                  s.Girth,
+			DimensionUnitOfMeasure: s.DimensionUnitOfMeasure,
+			ContainerShape:         s.ContainerShape,
+			ContainerSize:          s.ContainerSize,
+			IsMachineSortable:      s.IsMachineSortable,
+			ProductKey:             keyOf(productKeys, s.DefaultProductID),
+			ActiveStartDate:        s.ActiveStartDate,
+			ActiveEndDate:          s.ActiveEndDate,
+		})
+	}
+	snapshot.normalize()
+	return snapshot
+}
+
+// ApplyTo copies the snapshot's fields to a category; the parent reference is resolved by the caller
+func (s *CategorySnapshot) ApplyTo(c *Category) {
+	c.Name = s.Name
+	c.Description = s.Description
+	c.LongDescription = s.LongDescription
+	c.URL = s.URL
+	c.URLKey = s.URLKey
+	c.MetaTitle = s.MetaTitle
+	c.MetaDescription = s.MetaDescription
+	c.DisplayTemplate = s.DisplayTemplate
+	c.ExternalID = s.ExternalID
+	c.TaxCode = s.TaxCode
+	c.FulfillmentType = s.FulfillmentType
+	c.InventoryType = s.InventoryType
+	c.RootDisplayOrder = s.RootDisplayOrder
+	c.OverrideGeneratedURL = s.OverrideGeneratedURL
+	c.ActiveStartDate = s.ActiveStartDate
+	c.ActiveEndDate = s.ActiveEndDate
+	c.UpdatedAt = time.Now()
+}
+
+// ApplyTo copies the snapshot's fields to a product; category and SKU references are resolved by the caller
+func (s *ProductSnapshot) ApplyTo(p *Product) {
+	p.URL = s.URL
+	p.URLKey = s.URLKey
+	p.CanonicalURL = s.CanonicalURL
+	p.Manufacture = s.Manufacture
+	p.Model = s.Model
+	p.MetaTitle = s.MetaTitle
+	p.MetaDescription = s.MetaDescription
+	p.DisplayTemplate = s.DisplayTemplate
+	p.CanSellWithoutOptions = s.CanSellWithoutOptions
+	p.EnableDefaultSKUInInventory = s.EnableDefaultSKUInInventory
+	p.OverrideGeneratedURL = s.OverrideGeneratedURL
+	p.ActiveStartDate = s.ActiveStartDate
+	p.ActiveEndDate = s.ActiveEndDate
+	p.UpdatedAt = time.Now()
+}
+
+// ApplyTo copies the snapshot's fields to a SKU; the product reference is resolved by the caller
+func (s *SKUSnapshot) ApplyTo(sku *SKU) {
+	sku.Name = s.Name
+	sku.Description = s.Description
+	sku.LongDescription = s.LongDescription
+	sku.UPC = s.UPC
+	sku.ExternalID = s.ExternalID
+	sku.URLKey = s.URLKey
+	sku.CurrencyCode = s.CurrencyCode
+	sku.RetailPrice = s.RetailPrice
+	sku.SalePrice = s.SalePrice
+	sku.Cost = s.Cost
+	sku.Available = s.Available
+	sku.Discountable = s.Discountable
+	sku.Taxable = s.Taxable
+	sku.TaxCode = s.TaxCode
+	sku.InventoryType = s.InventoryType
+	sku.FulfillmentType = s.FulfillmentType
+	sku.DisplayTemplate = s.DisplayTemplate
+	sku.Weight = s.Weight
+	sku.WeightUnitOfMeasure = s.WeightUnitOfMeasure
+	sku.Height = s.Height
+	sku.Width = s.Width
+	sku.Depth = s.Depth
+	sku.Girth = s.Girth
+	sku.DimensionUnitOfMeasure = s.DimensionUnitOfMeasure
+	sku.ContainerShape = s.ContainerShape
+	sku.ContainerSize = s.ContainerSize
+	sku.IsMachineSortable = s.IsMachineSortable
+	sku.ActiveStartDate = s.ActiveStartDate
+	sku.ActiveEndDate = s.ActiveEndDate
+	sku.UpdatedAt = time.Now()
+}
+
+// Validate checks that the snapshot can be compared with and imported into a catalog:
+// a supported format, unique keys, and references that resolve within the snapshot
+// or to one of the known keys of the live catalog.
+func (s *CatalogSnapshot) Validate(live *CatalogSnapshot) error {
+	if s.FormatVersion < 1 || s.FormatVersion > CatalogSnapshotFormatVersion {
+		return NewDomainError(fmt.Sprintf("Unsupported catalog snapshot format version %d", s.FormatVersion))
+	}
+
+	categories, err := snapshotKeys("category", len(s.Categories), func(i int) string { return s.Categories[i].Key })
+	if err != nil {
+		return err
+	}
+	products, err := snapshotKeys("product", len(s.Products), func(i int) string { return s.Products[i].Key })
+	if err != nil {
+		return err
+	}
+	skus, err := snapshotKeys("SKU", len(s.SKUs), func(i int) string { return s.SKUs[i].Key })
+	if err != nil {
+		return err
+	}
+	for _, c := range live.Categories {
+		categories[c.Key] = true
+	}
+	for _, p := range live.Products {
+		products[p.Key] = true
+	}
+	for _, sku := range live.SKUs {
+		skus[sku.Key] = true
+	}
+
+	for _, c := range s.Categories {
+		if c.ParentKey != "" && !categories[c.ParentKey] {
+			return NewDomainError(fmt.Sprintf("Category %s references unknown parent %s", c.Key, c.ParentKey))
+		}
+	}
+	for _, p := range s.Products {
+		if p.DefaultCategoryKey != "" && !categories[p.DefaultCategoryKey] {
+			return NewDomainError(fmt.Sprintf("Product %s references unknown category %s", p.Key, p.DefaultCategoryKey))
+		}
+		if p.DefaultSKUKey != "" && !skus[p.DefaultSKUKey] {
+			return NewDomainError(fmt.Sprintf("Product %s references unknown SKU %s", p.Key, p.DefaultSKUKey))
+		}
+	}
+	for _, sku := range s.SKUs {
+		if sku.ProductKey != "" && !products[sku.ProductKey] {
+			return NewDomainError(fmt.Sprintf("SKU %s references unknown product %s", sku.Key, sku.ProductKey))
+		}
+	}
+	s.normalize()
+	return nil
+}
+
+// normalize converts dates to UTC so snapshots from different environments compare equal
+func (s *CatalogSnapshot) normalize() {
+	utc := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		u := t.UTC()
+		return &u
+	}
+	for _, c := range s.Categories {
+		c.ActiveStartDate, c.ActiveEndDate = utc(c.ActiveStartDate), utc(c.ActiveEndDate)
+	}
+	for _, p := range s.Products {
+		p.ActiveStartDate, p.ActiveEndDate = utc(p.ActiveStartDate), utc(p.ActiveEndDate)
+	}
+	for _, sku := range s.SKUs {
+		sku.ActiveStartDate, sku.ActiveEndDate = utc(sku.ActiveStartDate), utc(sku.ActiveEndDate)
+	}
+}
+
+func snapshotKeys(entity string, n int, key func(i int) string) (map[string]bool, error) {
+	keys := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		k := key(i)
+		if k == "" {
+			return nil, NewDomainError(fmt.Sprintf("Every %s in the snapshot needs a key", entity))
+		}
+		if keys[k] {
+			return nil, NewDomainError(fmt.Sprintf("Duplicate %s key %s in the snapshot", entity, k))
+		}
+		keys[k] = true
+	}
+	return keys, nil
+}
+
+// CatalogDiff lists what importing a snapshot would change in the live catalog
+type CatalogDiff struct {
+	Categories *EntityDiff `json:"categories"`
+	Products   *EntityDiff `json:"products"`
+	SKUs       *EntityDiff `json:"skus"`
+}
+
+// EntityDiff lists the added, changed and removed entities of one kind, by key
+type EntityDiff struct {
+	Added   []string        `json:"added"`
+	Changed []*EntityChange `json:"changed"`
+	Removed []string        `json:"removed"`
+}
+
+// EntityChange lists the fields of an entity that differ between the live catalog and the snapshot
+type EntityChange struct {
+	Key    string         `json:"key"`
+	Fields []*FieldChange `json:"fields"`
+}
+
+// FieldChange is a field value in the live catalog and in the snapshot
+type FieldChange struct {
+	Field    string      `json:"field"`
+	Live     interface{} `json:"live"`
+	Snapshot interface{} `json:"snapshot"`
+}
+
+// IsEmpty checks if the snapshot matches the live catalog
+func (d *CatalogDiff) IsEmpty() bool {
+	for _, e := range []*EntityDiff{d.Categories, d.Products, d.SKUs} {
+		if len(e.Added) > 0 || len(e.Changed) > 0 || len(e.Removed) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffCatalogSnapshots compares a snapshot against a snapshot of the live catalog.
+// SKUs are withdrawn rather than deleted, so live SKUs that are already unavailable
+// are not reported as removed.
+func DiffCatalogSnapshots(live, incoming *CatalogSnapshot) (*CatalogDiff, error) {
+	categories, err := diffEntities(
+		keyed(live.Categories, func(c *CategorySnapshot) string { return c.Key }),
+		keyed(incoming.Categories, func(c *CategorySnapshot) string { return c.Key }),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	products, err := diffEntities(
+		keyed(live.Products, func(p *ProductSnapshot) string { return p.Key }),
+		keyed(incoming.Products, func(p *ProductSnapshot) string { return p.Key }),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	withdrawn := make(map[string]bool)
+	for _, sku := range live.SKUs {
+		if !sku.Available {
+			withdrawn[sku.Key] = true
+		}
+	}
+	skus, err := diffEntities(
+		keyed(live.SKUs, func(s *SKUSnapshot) string { return s.Key }),
+		keyed(incoming.SKUs, func(s *SKUSnapshot) string { return s.Key }),
+		withdrawn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &CatalogDiff{Categories: categories, Products: products, SKUs: skus}, nil
+}
+
+func keyed[T any](entities []T, key func(T) string) map[string]interface{} {
+	byKey := make(map[string]interface{}, len(entities))
+	for _, e := range entities {
+		byKey[key(e)] = e
+	}
+	return byKey
+}
+
+// diffEntities compares entities field by field through their snapshot JSON form
+func diffEntities(live, incoming map[string]interface{}, notRemovable map[string]bool) (*EntityDiff, error) {
+	diff := &EntityDiff{Added: []string{}, Changed: []*EntityChange{}, Removed: []string{}}
+	for key, entity := range incoming {
+		current, ok := live[key]
+		if !ok {
+			diff.Added = append(diff.Added, key)
+			continue
+		}
+		fields, err := diffFields(current, entity)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			diff.Changed = append(diff.Changed, &EntityChange{Key: key, Fields: fields})
+		}
+	}
+	for key := range live {
+		if _, ok := incoming[key]; !ok && !notRemovable[key] {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Key < diff.Changed[j].Key })
+	return diff, nil
+}
+
+func diffFields(live, incoming interface{}) ([]*FieldChange, error) {
+	liveFields, err := snapshotFields(live)
+	if err != nil {
+		return nil, err
+	}
+	incomingFields, err := snapshotFields(incoming)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(liveFields)+len(incomingFields))
+	for name := range liveFields {
+		names[name] = true
+	}
+	for name := range incomingFields {
+		names[name] = true
+	}
+
+	var changes []*FieldChange
+	for name := range names {
+		if name == "key" {
+			continue
+		}
+		a, b := liveFields[name], incomingFields[name]
+		if string(a) != string(b) {
+			changes = append(changes, &FieldChange{Field: name, Live: rawValue(a), Snapshot: rawValue(b)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func snapshotFields(entity interface{}) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// rawValue returns an omitted field as nil
+func rawValue(raw json.RawMessage) interface{} {
+	if raw == nil {
+		return nil
+	}
+	return raw
+}
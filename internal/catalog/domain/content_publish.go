@@ -0,0 +1,27 @@
+package domain
+
+// PublishedEntityType is the kind of entity a content published notification is about
+type PublishedEntityType string
+
+const (
+	PublishedProduct  PublishedEntityType = "product"
+	PublishedCategory PublishedEntityType = "category"
+	PublishedSKU      PublishedEntityType = "sku"
+	PublishedContent  PublishedEntityType = "content" // CMS structured content, identified by slug
+)
+
+// IsValid checks whether the entity type is known
+func (t PublishedEntityType) IsValid() bool {
+	switch t {
+	case PublishedProduct, PublishedCategory, PublishedSKU, PublishedContent:
+		return true
+	}
+	return false
+}
+
+// PublishedEntity identifies a changed entity: a product, category or SKU ID,
+// or the slug of CMS content
+type PublishedEntity struct {
+	Type PublishedEntityType `json:"type"`
+	ID   string              `json:"id"`
+}
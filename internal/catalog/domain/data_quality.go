@@ -0,0 +1,250 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// QualityCheck is an aspect of a product evaluated for completeness
+type QualityCheck string
+
+const (
+	QualityCheckImages          QualityCheck = "IMAGES"           // Image attributes of the product or its default SKU
+	QualityCheckDescription     QualityCheck = "DESCRIPTION"      // Default SKU description
+	QualityCheckLongDescription QualityCheck = "LONG_DESCRIPTION" // Default SKU long description
+	QualityCheckAttributes      QualityCheck = "ATTRIBUTES"       // Product and default SKU attributes other than images
+	QualityCheckMetaTitle       QualityCheck = "META_TITLE"
+	QualityCheckMetaDescription QualityCheck = "META_DESCRIPTION"
+	QualityCheckCategory        QualityCheck = "CATEGORY"
+	QualityCheckPrice           QualityCheck = "PRICE"     // Default SKU retail price
+	QualityCheckInventory       QualityCheck = "INVENTORY" // Units available in the product availability read model
+)
+
+// IsValid checks if the check is known
+func (c QualityCheck) IsValid() bool {
+	switch c {
+	case QualityCheckImages, QualityCheckDescription, QualityCheckLongDescription, QualityCheckAttributes,
+		QualityCheckMetaTitle, QualityCheckMetaDescription, QualityCheckCategory, QualityCheckPrice, QualityCheckInventory:
+		return true
+	}
+	return false
+}
+
+// QualityRule weighs a check in the completeness score of products
+type QualityRule struct {
+	Check      QualityCheck `json:"check"`
+	Weight     int          `json:"weight"`               // Share of the score the check is worth
+	Blocking   bool         `json:"blocking"`             // Products failing it cannot be published
+	MinLength  int          `json:"min_length,omitempty"` // Text checks: characters required
+	MinCount   int          `json:"min_count,omitempty"`  // Images, attributes and inventory: values or units required
+	Attributes []string     `json:"attributes,omitempty"` // Attributes check: names that must be set
+}
+
+// DefaultQualityRules returns the rules used when none are configured
+func DefaultQualityRules() []QualityRule {
+	return []QualityRule{
+		{Check: QualityCheckImages, Weight: 20, MinCount: 1},
+		{Check: QualityCheckDescription, Weight: 15, Blocking: true, MinLength: 20},
+		{Check: QualityCheckLongDescription, Weight: 10, MinLength: 100},
+		{Check: QualityCheckAttributes, Weight: 10, MinCount: 1},
+		{Check: QualityCheckMetaTitle, Weight: 10, MinLength: 10},
+		{Check: QualityCheckMetaDescription, Weight: 10, MinLength: 50},
+		{Check: QualityCheckCategory, Weight: 5},
+		{Check: QualityCheckPrice, Weight: 15, Blocking: true},
+		{Check: QualityCheckInventory, Weight: 5, MinCount: 1},
+	}
+}
+
+// QualityRules configures product completeness scoring
+type QualityRules struct {
+	Rules       []QualityRule
+	ImagePrefix string // Attributes whose name starts with it hold image URLs, e.g. image or image_2
+}
+
+// Validate checks the rules can score products
+func (r QualityRules) Validate() error {
+	if len(r.Rules) == 0 {
+		return NewDomainError("at least one quality rule is required")
+	}
+	total := 0
+	seen := make(map[QualityCheck]bool)
+	for _, rule := range r.Rules {
+		if !rule.Check.IsValid() {
+			return NewDomainError(fmt.Sprintf("unknown quality check %q", rule.Check))
+		}
+		if seen[rule.Check] {
+			return NewDomainError(fmt.Sprintf("quality check %s is configured twice", rule.Check))
+		}
+		seen[rule.Check] = true
+		if rule.Weight < 0 || rule.MinLength < 0 || rule.MinCount < 0 {
+			return NewDomainError(fmt.Sprintf("quality check %s cannot have negative limits", rule.Check))
+		}
+		total += rule.Weight
+	}
+	if total == 0 {
+		return NewDomainError("quality rules must have a positive total weight")
+	}
+	if r.ImagePrefix == "" && seen[QualityCheckImages] {
+		return NewDomainError("an image attribute prefix is required to check images")
+	}
+	return nil
+}
+
+// ProductQualityInput is the product data the completeness checks read
+type ProductQualityInput struct {
+	ProductID       int64
+	Name            string // Default SKU name
+	PublishStatus   ProductPublishStatus
+	MetaTitle       string
+	MetaDescription string
+	Description     string
+	LongDescription string
+	HasDefaultSKU   bool
+	RetailPrice     float64
+	HasCategory     bool              // Default category or category assignments
+	QtyAvailable    *int              // Nil until the availability read model covers the product
+	Attributes      map[string]string // Product and default SKU attributes with a value; product values win
+}
+
+// QualityFailure is a check a product does not pass
+type QualityFailure struct {
+	Check    QualityCheck `json:"check"`
+	Message  string       `json:"message"`
+	Blocking bool         `json:"blocking"`
+}
+
+// QualityScore is the completeness of a product
+type QualityScore struct {
+	ProductID int64
+	Score     int // Percentage of the rule weight passed
+	Failures  []QualityFailure
+}
+
+// BlockingFailures returns the failures preventing publication
+func (s *QualityScore) BlockingFailures() []QualityFailure {
+	var blocking []QualityFailure
+	for _, failure := range s.Failures {
+		if failure.Blocking {
+			blocking = append(blocking, failure)
+		}
+	}
+	return blocking
+}
+
+// IsPublishable checks if no blocking check fails
+func (s *QualityScore) IsPublishable() bool {
+	return len(s.BlockingFailures()) == 0
+}
+
+// Evaluate scores a product against the rules
+func (r QualityRules) Evaluate(in *ProductQualityInput) *QualityScore {
+	score := &QualityScore{ProductID: in.ProductID}
+	total, passed := 0, 0
+	for _, rule := range r.Rules {
+		total += rule.Weight
+		message := r.check(rule, in)
+		if message == "" {
+			passed += rule.Weight
+			continue
+		}
+		score.Failures = append(score.Failures, QualityFailure{
+			Check:    rule.Check,
+			Message:  message,
+			Blocking: rule.Blocking,
+		})
+	}
+	if total > 0 {
+		score.Score = passed * 100 / total
+	}
+	return score
+}
+
+// check returns why the product fails a rule, empty when it passes
+func (r QualityRules) check(rule QualityRule, in *ProductQualityInput) string {
+	switch rule.Check {
+	case QualityCheckImages:
+		return minCount(r.countImages(in), rule.MinCount, "image")
+	case QualityCheckDescription:
+		return minText(in.Description, rule.MinLength, "description")
+	case QualityCheckLongDescription:
+		return minText(in.LongDescription, rule.MinLength, "long description")
+	case QualityCheckAttributes:
+		var missing []string
+		for _, name := range rule.Attributes {
+			if in.Attributes[name] == "" {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return "missing attributes: " + strings.Join(missing, ", ")
+		}
+		return minCount(len(in.Attributes)-r.countImages(in), rule.MinCount, "attribute")
+	case QualityCheckMetaTitle:
+		return minText(in.MetaTitle, rule.MinLength, "meta title")
+	case QualityCheckMetaDescription:
+		return minText(in.MetaDescription, rule.MinLength, "meta description")
+	case QualityCheckCategory:
+		if !in.HasCategory {
+			return "not assigned to a category"
+		}
+	case QualityCheckPrice:
+		if !in.HasDefaultSKU {
+			return "no default SKU"
+		}
+		if in.RetailPrice <= 0 {
+			return "no retail price"
+		}
+	case QualityCheckInventory:
+		if in.QtyAvailable == nil {
+			return "no inventory recorded"
+		}
+		if required := max(rule.MinCount, 1); *in.QtyAvailable < required {
+			return fmt.Sprintf("%d units available, %d required", *in.QtyAvailable, required)
+		}
+	}
+	return ""
+}
+
+func (r QualityRules) countImages(in *ProductQualityInput) int {
+	if r.ImagePrefix == "" {
+		return 0
+	}
+	count := 0
+	for name := range in.Attributes {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(r.ImagePrefix)) {
+			count++
+		}
+	}
+	return count
+}
+
+func minText(value string, minLength int, field string) string {
+	length := len([]rune(strings.TrimSpace(value)))
+	if length == 0 {
+		return "no " + field
+	}
+	if length < minLength {
+		return fmt.Sprintf("%s has %d characters, %d required", field, length, minLength)
+	}
+	return ""
+}
+
+func minCount(count, required int, noun string) string {
+	if count >= required {
+		return ""
+	}
+	if required == 1 {
+		return "no " + noun
+	}
+	return fmt.Sprintf("%d %ss, %d required", count, noun, required)
+}
+
+// ProductQualityRepository defines the interface for loading the data products are scored on
+type ProductQualityRepository interface {
+	// FindInputs loads the quality inputs of products, keyed by product ID; missing products are skipped
+	FindInputs(ctx context.Context, productIDs []int64) (map[int64]*ProductQualityInput, error)
+
+	// FindAwaitingPublication loads the quality inputs of unarchived products that were never published, oldest first
+	FindAwaitingPublication(ctx context.Context, limit int) ([]*ProductQualityInput, error)
+}
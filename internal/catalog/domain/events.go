@@ -25,8 +25,24 @@ const (
 	EventSKUDeleted             = "catalog.sku.deleted"
 	EventSKUAvailabilityChanged = "catalog.sku.availability_changed"
 	EventSKUPriceChanged        = "catalog.sku.price_changed"
+
+	// Storefront content events
+	EventContentPublished = "catalog.content.published"
 )
 
+// Catalog events are rebuilt as their own types when read from a serializing bus
+func init() {
+	event.RegisterType(EventProductCreated, func() event.Event { return &ProductCreatedEvent{} })
+	event.RegisterType(EventProductUpdated, func() event.Event { return &ProductUpdatedEvent{} })
+	event.RegisterType(EventProductArchived, func() event.Event { return &ProductArchivedEvent{} })
+	event.RegisterType(EventCategoryCreated, func() event.Event { return &CategoryCreatedEvent{} })
+	event.RegisterType(EventCategoryUpdated, func() event.Event { return &CategoryUpdatedEvent{} })
+	event.RegisterType(EventSKUCreated, func() event.Event { return &SKUCreatedEvent{} })
+	event.RegisterType(EventSKUAvailabilityChanged, func() event.Event { return &SKUAvailabilityChangedEvent{} })
+	event.RegisterType(EventSKUPriceChanged, func() event.Event { return &SKUPriceChangedEvent{} })
+	event.RegisterType(EventContentPublished, func() event.Event { return &ContentPublishedEvent{} })
+}
+
 // ProductCreatedEvent is published when a product is created
 type ProductCreatedEvent struct {
 	event.BaseEvent
@@ -150,8 +166,9 @@ func NewSKUCreatedEvent(skuID int64, productID *int64, name string, price float6
 // SKUAvailabilityChangedEvent is published when SKU availability changes
 type SKUAvailabilityChangedEvent struct {
 	event.BaseEvent
-	SKUID     int64 `json:"sku_id"`
-	Available bool  `json:"available"`
+	SKUID     int64  `json:"sku_id"`
+	Available bool   `json:"available"`
+	Source    string `json:"source,omitempty"` // e.g. AvailabilitySourceInventorySync; empty when set by an admin
 }
 
 // NewSKUAvailabilityChangedEvent creates a new SKUAvailabilityChangedEvent
@@ -166,23 +183,47 @@ func NewSKUAvailabilityChangedEvent(skuID int64, available bool) *SKUAvailabilit
 	}
 }
 
-// SKUPriceChangedEvent is published when SKU price changes
+// SKUPriceChangedEvent is published when the retail or effective price of a SKU changes
 type SKUPriceChangedEvent struct {
 	event.BaseEvent
-	SKUID    int64   `json:"sku_id"`
-	OldPrice float64 `json:"old_price"`
-	NewPrice float64 `json:"new_price"`
+	SKUID             int64   `json:"sku_id"`
+	OldPrice          float64 `json:"old_price"`
+	NewPrice          float64 `json:"new_price"`
+	OldEffectivePrice float64 `json:"old_effective_price"` // Sale price when below retail
+	NewEffectivePrice float64 `json:"new_effective_price"`
 }
 
 // NewSKUPriceChangedEvent creates a new SKUPriceChangedEvent
-func NewSKUPriceChangedEvent(skuID int64, oldPrice, newPrice float64) *SKUPriceChangedEvent {
+func NewSKUPriceChangedEvent(skuID int64, oldPrice, newPrice, oldEffectivePrice, newEffectivePrice float64) *SKUPriceChangedEvent {
 	return &SKUPriceChangedEvent{
 		BaseEvent: event.BaseEvent{
 			Type:       EventSKUPriceChanged,
 			OccurredOn: time.Now(),
 		},
-		SKUID:    skuID,
-		OldPrice: oldPrice,
-		NewPrice: newPrice,
+		SKUID:             skuID,
+		OldPrice:          oldPrice,
+		NewPrice:          newPrice,
+		OldEffectivePrice: oldEffectivePrice,
+		NewEffectivePrice: newEffectivePrice,
+	}
+}
+
+// ContentPublishedEvent is published when catalog or CMS changes make cached
+// storefront pages stale, listing the URLs headless frontends and CDNs purge
+type ContentPublishedEvent struct {
+	event.BaseEvent
+	Entities []PublishedEntity `json:"entities"`
+	URLs     []string          `json:"urls"`
+}
+
+// NewContentPublishedEvent creates a new ContentPublishedEvent
+func NewContentPublishedEvent(entities []PublishedEntity, urls []string) *ContentPublishedEvent {
+	return &ContentPublishedEvent{
+		BaseEvent: event.BaseEvent{
+			Type:       EventContentPublished,
+			OccurredOn: time.Now(),
+		},
+		Entities: entities,
+		URLs:     urls,
 	}
 }
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MerchandisingSort is the rule ordering the unpinned products of a category
+type MerchandisingSort string
+
+const (
+	// MerchandisingSortDefault keeps the listing's regular sort
+	MerchandisingSortDefault     MerchandisingSort = ""
+	MerchandisingSortManual      MerchandisingSort = "MANUAL"       // Category display order
+	MerchandisingSortNewest      MerchandisingSort = "NEWEST"       // Most recently created first
+	MerchandisingSortMargin      MerchandisingSort = "MARGIN"       // Highest unit margin of any available SKU first
+	MerchandisingSortBestSellers MerchandisingSort = "BEST_SELLERS" // Most units ordered within the best seller window first
+)
+
+// DefaultBestSellerDays is the best seller window used when none is configured
+const DefaultBestSellerDays = 30
+
+// MaxPinnedProducts bounds the number of products pinned to a category
+const MaxPinnedProducts = 100
+
+// IsValid checks if the sort rule is known
+func (s MerchandisingSort) IsValid() bool {
+	switch s {
+	case MerchandisingSortDefault, MerchandisingSortManual, MerchandisingSortNewest,
+		MerchandisingSortMargin, MerchandisingSortBestSellers:
+		return true
+	}
+	return false
+}
+
+// CategoryMerchandising holds the admin-managed listing rules of a category:
+// pinned products come first in their configured order, then the rest by the sort rule
+type CategoryMerchandising struct {
+	CategoryID       int64
+	PinnedProductIDs []int64
+	SortRule         MerchandisingSort
+	BestSellerDays   int // Order history considered by BEST_SELLERS
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// NewCategoryMerchandising creates the merchandising rules of a category
+func NewCategoryMerchandising(categoryID int64) (*CategoryMerchandising, error) {
+	if categoryID <= 0 {
+		return nil, NewDomainError("category ID is required")
+	}
+	now := time.Now()
+	return &CategoryMerchandising{
+		CategoryID:       categoryID,
+		PinnedProductIDs: []int64{},
+		BestSellerDays:   DefaultBestSellerDays,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}, nil
+}
+
+// Update replaces the pins and sort rule. Duplicate pins keep their first position.
+func (m *CategoryMerchandising) Update(pinnedProductIDs []int64, sortRule MerchandisingSort, bestSellerDays int) error {
+	if !sortRule.IsValid() {
+		return NewDomainError("invalid merchandising sort rule: " + string(sortRule))
+	}
+	if bestSellerDays < 0 {
+		return NewDomainError("best seller days cannot be negative")
+	}
+	if bestSellerDays == 0 {
+		bestSellerDays = DefaultBestSellerDays
+	}
+
+	pins := make([]int64, 0, len(pinnedProductIDs))
+	seen := make(map[int64]bool, len(pinnedProductIDs))
+	for _, id := range pinnedProductIDs {
+		if id <= 0 {
+			return NewDomainError("pinned product IDs must be positive")
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		pins = append(pins, id)
+	}
+	if len(pins) > MaxPinnedProducts {
+		return NewDomainError("too many pinned products")
+	}
+
+	m.PinnedProductIDs = pins
+	m.SortRule = sortRule
+	m.BestSellerDays = bestSellerDays
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+// Criteria returns the listing criteria of the rules evaluated at now
+func (m *CategoryMerchandising) Criteria(now time.Time) *MerchandisingCriteria {
+	criteria := &MerchandisingCriteria{
+		PinnedProductIDs: m.PinnedProductIDs,
+		SortRule:         m.SortRule,
+	}
+	if m.SortRule == MerchandisingSortBestSellers {
+		criteria.BestSellersSince = now.AddDate(0, 0, -m.BestSellerDays)
+	}
+	return criteria
+}
+
+// MerchandisingCriteria orders a category listing. It replaces the listing's
+// regular sort unless SortRule is empty, in which case only the pins apply.
+type MerchandisingCriteria struct {
+	PinnedProductIDs []int64 // Returned first, in this order
+	SortRule         MerchandisingSort
+	BestSellersSince time.Time // Start of the order history used by BEST_SELLERS
+}
+
+// IsEmpty checks if the criteria leaves the listing unchanged
+func (c *MerchandisingCriteria) IsEmpty() bool {
+	return c == nil || (len(c.PinnedProductIDs) == 0 && c.SortRule == MerchandisingSortDefault)
+}
+
+// CategoryMerchandisingRepository defines the interface for category merchandising persistence
+type CategoryMerchandisingRepository interface {
+	// Save creates or replaces the merchandising rules of a category
+	Save(ctx context.Context, merchandising *CategoryMerchandising) error
+
+	// Delete removes the merchandising rules of a category
+	Delete(ctx context.Context, categoryID int64) error
+
+	// FindByCategoryID retrieves the merchandising rules of a category, nil if none
+	FindByCategoryID(ctx context.Context, categoryID int64) (*CategoryMerchandising, error)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CatalogPopularityRepository ranks catalog entries by the units ordered from
+// them, leaving out cancelled and refunded orders
+type CatalogPopularityRepository interface {
+	// TopProductIDs returns the best-selling unarchived products since a time, best first
+	TopProductIDs(ctx context.Context, since time.Time, limit int) ([]int64, error)
+
+	// TopCategoryIDs returns the unarchived categories whose products sold the most since a time, best first
+	TopCategoryIDs(ctx context.Context, since time.Time, limit int) ([]int64, error)
+}
@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PriceWatchStatus represents the state of a customer's price watch
+type PriceWatchStatus string
+
+const (
+	PriceWatchStatusActive    PriceWatchStatus = "ACTIVE"    // Waiting for the price to drop
+	PriceWatchStatusNotified  PriceWatchStatus = "NOTIFIED"  // Customer was told about a drop
+	PriceWatchStatusConverted PriceWatchStatus = "CONVERTED" // Customer bought the SKU after the alert
+	PriceWatchStatusCancelled PriceWatchStatus = "CANCELLED"
+)
+
+// PriceWatch is a customer's subscription to a price drop of a SKU below a watched price
+type PriceWatch struct {
+	ID               int64
+	SKUID            int64
+	CustomerID       int64
+	WatchedPrice     float64 // Alert when the effective price drops below it
+	Status           PriceWatchStatus
+	NotifiedPrice    *float64 // Effective price the customer was alerted at
+	NotifiedAt       *time.Time
+	ConvertedOrderID *int64 // First order with the SKU placed within the attribution window of the alert
+	ConvertedAt      *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// NewPriceWatch creates a price watch of a customer on a SKU
+func NewPriceWatch(skuID, customerID int64, watchedPrice float64) (*PriceWatch, error) {
+	if skuID <= 0 {
+		return nil, NewDomainError("SKU ID is required")
+	}
+	if customerID <= 0 {
+		return nil, NewDomainError("customer ID is required")
+	}
+	if watchedPrice <= 0 {
+		return nil, NewDomainError("watched price must be greater than zero")
+	}
+	now := time.Now()
+	return &PriceWatch{
+		SKUID:        skuID,
+		CustomerID:   customerID,
+		WatchedPrice: watchedPrice,
+		Status:       PriceWatchStatusActive,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// IsTriggeredBy checks if an effective price alerts the customer
+func (w *PriceWatch) IsTriggeredBy(effectivePrice float64) bool {
+	return w.Status == PriceWatchStatusActive && effectivePrice < w.WatchedPrice
+}
+
+// MarkNotified records that the customer was alerted about a drop to a price
+func (w *PriceWatch) MarkNotified(effectivePrice float64) {
+	now := time.Now()
+	w.Status = PriceWatchStatusNotified
+	w.NotifiedPrice = &effectivePrice
+	w.NotifiedAt = &now
+	w.UpdatedAt = now
+}
+
+// Rewatch restarts the watch at a new price, e.g. after the customer was alerted and wants a lower one
+func (w *PriceWatch) Rewatch(watchedPrice float64) error {
+	if watchedPrice <= 0 {
+		return NewDomainError("watched price must be greater than zero")
+	}
+	w.WatchedPrice = watchedPrice
+	w.Status = PriceWatchStatusActive
+	w.NotifiedPrice = nil
+	w.NotifiedAt = nil
+	w.ConvertedOrderID = nil
+	w.ConvertedAt = nil
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// Cancel stops the watch
+func (w *PriceWatch) Cancel() {
+	w.Status = PriceWatchStatusCancelled
+	w.UpdatedAt = time.Now()
+}
+
+// TriggeredPriceWatch is a watch whose customer is to be alerted, with where to reach them
+type TriggeredPriceWatch struct {
+	Watch *PriceWatch
+	Email string
+	Name  string
+}
+
+// PriceWatchSummary counts the watches of a SKU by status
+type PriceWatchSummary struct {
+	Active    int `json:"active"`
+	Notified  int `json:"notified"`
+	Converted int `json:"converted"`
+	Cancelled int `json:"cancelled"`
+}
+
+// ScheduledPriceChange is a SKU pricing change applied at a future time, e.g. for a sale
+type ScheduledPriceChange struct {
+	ID          int64
+	SKUID       int64
+	RetailPrice float64
+	SalePrice   float64 // 0 clears the sale price
+	EffectiveAt time.Time
+	AppliedAt   *time.Time
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+// NewScheduledPriceChange schedules new prices of a SKU
+func NewScheduledPriceChange(skuID int64, retailPrice, salePrice float64, effectiveAt time.Time, createdBy string) (*ScheduledPriceChange, error) {
+	if skuID <= 0 {
+		return nil, NewDomainError("SKU ID is required")
+	}
+	if retailPrice < 0 || salePrice < 0 {
+		return nil, NewDomainError("prices cannot be negative")
+	}
+	if effectiveAt.IsZero() {
+		return nil, NewDomainError("effective date is required")
+	}
+	return &ScheduledPriceChange{
+		SKUID:       skuID,
+		RetailPrice: retailPrice,
+		SalePrice:   salePrice,
+		EffectiveAt: effectiveAt,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// IsApplied checks if the change was applied to the SKU
+func (c *ScheduledPriceChange) IsApplied() bool {
+	return c.AppliedAt != nil
+}
+
+// MarkApplied records that the change was applied to the SKU
+func (c *ScheduledPriceChange) MarkApplied() {
+	now := time.Now()
+	c.AppliedAt = &now
+}
+
+// PriceWatchRepository defines the interface for price watch persistence
+type PriceWatchRepository interface {
+	// Save creates or updates a price watch and sets its ID
+	Save(ctx context.Context, watch *PriceWatch) error
+
+	// FindByID retrieves a price watch by ID
+	FindByID(ctx context.Context, id int64) (*PriceWatch, error)
+
+	// FindByCustomerAndSKU retrieves the watch of a customer on a SKU, nil when there is none
+	FindByCustomerAndSKU(ctx context.Context, customerID, skuID int64) (*PriceWatch, error)
+
+	// FindByCustomerID retrieves the watches of a customer that are not cancelled, newest first
+	FindByCustomerID(ctx context.Context, customerID int64) ([]*PriceWatch, error)
+
+	// FindBySKUID retrieves the watches of a SKU, newest first
+	FindBySKUID(ctx context.Context, skuID int64, limit int) ([]*PriceWatch, error)
+
+	// FindTriggered retrieves the active watches of a SKU watched above an effective price
+	FindTriggered(ctx context.Context, skuID int64, effectivePrice float64) ([]*TriggeredPriceWatch, error)
+
+	// SummarizeSKU counts the watches of a SKU by status
+	SummarizeSKU(ctx context.Context, skuID int64) (*PriceWatchSummary, error)
+
+	// AttributeConversions marks notified watches converted by the first order of the
+	// customer with the SKU submitted within the window after the alert, returning how many
+	AttributeConversions(ctx context.Context, window time.Duration) (int64, error)
+}
+
+// ScheduledPriceChangeRepository defines the interface for scheduled price change persistence
+type ScheduledPriceChangeRepository interface {
+	// Save creates or updates a scheduled price change and sets its ID
+	Save(ctx context.Context, change *ScheduledPriceChange) error
+
+	// FindByID retrieves a scheduled price change by ID
+	FindByID(ctx context.Context, id int64) (*ScheduledPriceChange, error)
+
+	// FindBySKUID retrieves the scheduled price changes of a SKU, applied or not, by effective date
+	FindBySKUID(ctx context.Context, skuID int64) ([]*ScheduledPriceChange, error)
+
+	// FindDue retrieves the unapplied changes effective at or before a time, oldest first
+	FindDue(ctx context.Context, at time.Time, limit int) ([]*ScheduledPriceChange, error)
+
+	// Delete removes a scheduled price change
+	Delete(ctx context.Context, id int64) error
+}
@@ -2,6 +2,14 @@ package domain
 
 import "time"
 
+// ProductPublishStatus is where a product stands in the publishing workflow
+type ProductPublishStatus string
+
+const (
+	ProductStatusDraft     ProductPublishStatus = "DRAFT"     // Never approved; hidden from the storefront
+	ProductStatusPublished ProductPublishStatus = "PUBLISHED" // Approved and served on the storefront
+)
+
 // Product represents a product in the catalog
 type Product struct {
 	ID                          int64
@@ -19,6 +27,12 @@ type Product struct {
 	URLKey                      string
 	DefaultCategoryID           *int64 // From blc_product.default_category_id
 	DefaultSkuID                *int64 // From blc_product.default_sku_id
+	ActiveStartDate             *time.Time
+	ActiveEndDate               *time.Time
+	InStock                     bool // From the catalog_product_availability read model
+	PublishStatus               ProductPublishStatus
+	PublishedAt                 *time.Time
+	PublishedBy                 string // Reviewer who approved the published version
 	CreatedAt                   time.Time
 	UpdatedAt                   time.Time
 }
@@ -37,6 +51,8 @@ func NewProduct(
 		CanSellWithoutOptions:       canSellWithoutOptions,
 		EnableDefaultSKUInInventory: enableDefaultSKUInInventory,
 		Archived:                    false,
+		InStock:                     true,
+		PublishStatus:               ProductStatusDraft,
 		CreatedAt:                   now,
 		UpdatedAt:                   now,
 	}
@@ -54,6 +70,20 @@ func (p *Product) Unarchive() {
 	p.UpdatedAt = time.Now()
 }
 
+// Publish marks the product as approved for the storefront
+func (p *Product) Publish(reviewedBy string) {
+	now := time.Now()
+	p.PublishStatus = ProductStatusPublished
+	p.PublishedAt = &now
+	p.PublishedBy = reviewedBy
+	p.UpdatedAt = now
+}
+
+// IsPublished checks if the product has been approved for the storefront
+func (p *Product) IsPublished() bool {
+	return p.PublishStatus == ProductStatusPublished
+}
+
 // SetDefaultCategory sets the default category for the product
 func (p *Product) SetDefaultCategory(categoryID int64) {
 	p.DefaultCategoryID = &categoryID
@@ -66,6 +96,33 @@ func (p *Product) SetDefaultSKU(skuID int64) {
 	p.UpdatedAt = time.Now()
 }
 
+// SetActiveDate sets the active date range
+func (p *Product) SetActiveDate(startDate, endDate *time.Time) {
+	p.ActiveStartDate = startDate
+	p.ActiveEndDate = endDate
+	p.UpdatedAt = time.Now()
+}
+
+// IsActive checks if the product is currently active
+func (p *Product) IsActive() bool {
+	return p.IsActiveAt(time.Now())
+}
+
+// IsActiveAt checks if the product is active at the given time
+func (p *Product) IsActiveAt(at time.Time) bool {
+	if p.Archived {
+		return false
+	}
+	if p.ActiveStartDate != nil && at.Before(*p.ActiveStartDate) {
+		return false
+	}
+	if p.ActiveEndDate != nil && at.After(*p.ActiveEndDate) {
+		return false
+	}
+
+	return true
+}
+
 // IsArchived checks if the product is archived
 func (p *Product) IsArchived() bool {
 	return p.Archived
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ProductDraftStatus is the review state of a product draft
+type ProductDraftStatus string
+
+const (
+	ProductDraftPending  ProductDraftStatus = "PENDING"  // Awaiting a reviewer
+	ProductDraftRejected ProductDraftStatus = "REJECTED" // Sent back to its contributors; editing it resubmits it
+)
+
+// ProductDraft holds the edits of a published product until a reviewer approves
+// them. The product keeps serving its published version on the storefront in
+// the meantime. Products that were never published are edited directly, as
+// they are not served yet.
+type ProductDraft struct {
+	ProductID   int64
+	Product     *Product               // Edited version of the product
+	Attributes  map[string]string      // Attribute values to set on approval
+	CategoryIDs []int64                // Categories to assign on approval; nil leaves them unchanged
+	Changes     map[string]interface{} // Fields edited since the published version
+	Status      ProductDraftStatus
+	EditedBy    string
+	ReviewedBy  string
+	ReviewNote  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewProductDraft starts a draft from the published version of a product
+func NewProductDraft(published *Product) (*ProductDraft, error) {
+	if published.ID == 0 {
+		return nil, NewDomainError("a product draft requires a saved product")
+	}
+	if !published.IsPublished() {
+		return nil, NewDomainError("only published products are edited through drafts")
+	}
+
+	edited := *published
+	now := time.Now()
+	return &ProductDraft{
+		ProductID:  published.ID,
+		Product:    &edited,
+		Attributes: make(map[string]string),
+		Changes:    make(map[string]interface{}),
+		Status:     ProductDraftPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// RecordEdit submits the draft for review again after an edit
+func (d *ProductDraft) RecordEdit(editedBy string, changes map[string]interface{}) {
+	for field, value := range changes {
+		d.Changes[field] = value
+	}
+	d.Status = ProductDraftPending
+	d.EditedBy = editedBy
+	d.ReviewedBy = ""
+	d.ReviewNote = ""
+	d.UpdatedAt = time.Now()
+}
+
+// Reject sends the draft back to its contributors with the reviewer's note
+func (d *ProductDraft) Reject(reviewedBy, note string) {
+	d.Status = ProductDraftRejected
+	d.ReviewedBy = reviewedBy
+	d.ReviewNote = note
+	d.UpdatedAt = time.Now()
+}
+
+// ApplyTo copies the edited fields onto the published product. The archived
+// flag and the publishing state stay those of the live product.
+func (d *ProductDraft) ApplyTo(product *Product) {
+	edited := d.Product
+	product.Manufacture = edited.Manufacture
+	product.Model = edited.Model
+	product.URL = edited.URL
+	product.URLKey = edited.URLKey
+	product.CanonicalURL = edited.CanonicalURL
+	product.DisplayTemplate = edited.DisplayTemplate
+	product.MetaTitle = edited.MetaTitle
+	product.MetaDescription = edited.MetaDescription
+	product.OverrideGeneratedURL = edited.OverrideGeneratedURL
+	product.CanSellWithoutOptions = edited.CanSellWithoutOptions
+	product.EnableDefaultSKUInInventory = edited.EnableDefaultSKUInInventory
+	product.DefaultCategoryID = edited.DefaultCategoryID
+	product.DefaultSkuID = edited.DefaultSkuID
+	product.ActiveStartDate = edited.ActiveStartDate
+	product.ActiveEndDate = edited.ActiveEndDate
+	product.UpdatedAt = time.Now()
+}
+
+// ProductDraftFilter filters listed product drafts
+type ProductDraftFilter struct {
+	Status   ProductDraftStatus // Empty lists drafts in any state
+	Page     int
+	PageSize int
+}
+
+// ProductDraftRepository defines the interface for product draft persistence
+type ProductDraftRepository interface {
+	// Save creates or replaces the draft of a product
+	Save(ctx context.Context, draft *ProductDraft) error
+
+	// FindByProductID retrieves the draft of a product, or nil when it has none
+	FindByProductID(ctx context.Context, productID int64) (*ProductDraft, error)
+
+	// FindAll lists drafts, most recently edited first, with the total count
+	FindAll(ctx context.Context, filter *ProductDraftFilter) ([]*ProductDraft, int64, error)
+
+	// Delete removes the draft of a product
+	Delete(ctx context.Context, productID int64) error
+}
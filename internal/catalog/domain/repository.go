@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // ProductRepository defines the interface for product persistence
@@ -98,6 +99,12 @@ type CategoryProductXrefRepository interface {
 
 	// RemoveCategoryProductXref removes a specific category-product cross-reference by category ID and product ID.
 	RemoveCategoryProductXref(ctx context.Context, categoryID, productID int64) error
+
+	// SaveAll upserts many category-product cross-references in a single statement, setting their IDs.
+	SaveAll(ctx context.Context, xrefs []*CategoryProductXref) error
+
+	// RemoveCategoryProductXrefs removes the cross-references of a product to many categories in a single statement.
+	RemoveCategoryProductXrefs(ctx context.Context, productID int64, categoryIDs []int64) error
 }
 
 // CategoryRepository defines the interface for category persistence
@@ -221,6 +228,12 @@ type SkuProductOptionValueXrefRepository interface {
 
 	// RemoveSkuProductOptionValueXref removes a specific SKU product option value cross-reference by SKU ID and product option value ID.
 	RemoveSkuProductOptionValueXref(ctx context.Context, skuID, productOptionValueID int64) error
+
+	// SaveAll upserts many SKU product option value cross-references in a single statement, setting their IDs.
+	SaveAll(ctx context.Context, xrefs []*SkuProductOptionValueXref) error
+
+	// RemoveSkuProductOptionValueXrefs removes the cross-references of a SKU to many product option values in a single statement.
+	RemoveSkuProductOptionValueXrefs(ctx context.Context, skuID int64, productOptionValueIDs []int64) error
 }
 
 // ProductOptionRepository defines the interface for ProductOption persistence
@@ -261,8 +274,15 @@ type ProductFilter struct {
 	Page            int
 	PageSize        int
 	IncludeArchived bool
-	SortBy          string // "name", "created_at", "updated_at", "price"
-	SortOrder       string // "asc", "desc"
+	ActiveOnly      bool
+	ActiveAt        *time.Time             // Evaluates active windows at this time instead of now (admin preview)
+	OutOfStock      OutOfStockPolicy       // How out-of-stock products are listed; empty lists them in place
+	PublishStatus   ProductPublishStatus   // Only products in this publishing state; empty lists all (storefront lists PUBLISHED)
+	SortBy          string                 // "name", "created_at", "updated_at", "price", "relevance"
+	SortOrder       string                 // "asc", "desc"
+	Search          *SearchCriteria        // Optional analyzed search, used by Search
+	Merchandising   *MerchandisingCriteria // Optional category rules, used by FindByCategoryID
+	Badge           *BadgeCriteria         // Optional badge the products must show, used by Search
 }
 
 // CategoryFilter represents filtering and pagination options for categories
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SaleEventStatus represents the state of a scheduled sale event
+type SaleEventStatus string
+
+const (
+	SaleEventStatusScheduled SaleEventStatus = "SCHEDULED" // Waiting for its start
+	SaleEventStatusActive    SaleEventStatus = "ACTIVE"    // Deal prices applied to the SKUs
+	SaleEventStatusEnded     SaleEventStatus = "ENDED"     // Prices restored, at its end or when stopped early
+	SaleEventStatusCancelled SaleEventStatus = "CANCELLED" // Cancelled before it started
+)
+
+// SaleEvent is a time-boxed deal, e.g. a daily deal, putting a set of SKUs on
+// sale between two dates. Items with a sale price are repriced while the event
+// runs; an offer, when set, is scheduled over the same window.
+type SaleEvent struct {
+	ID          int64
+	Name        string
+	Description string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Status      SaleEventStatus
+	OfferID     *int64
+	CreatedBy   string
+	StartedAt   *time.Time
+	EndedAt     *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Items       []*SaleEventItem
+}
+
+// SaleEventItem is a SKU featured in a sale event
+type SaleEventItem struct {
+	SKUID     int64
+	SalePrice float64 // Deal price applied while the event runs; 0 leaves the price to the offer
+	// Prices of the SKU when the event started, restored when it ends
+	RetailPrice       float64
+	PreviousSalePrice float64
+	Applied           bool // The deal price was applied to the SKU
+}
+
+// NewSaleEvent creates a sale event on a set of SKUs
+func NewSaleEvent(name, description string, startsAt, endsAt time.Time, offerID *int64, items []*SaleEventItem, createdBy string) (*SaleEvent, error) {
+	if name == "" {
+		return nil, NewDomainError("name is required")
+	}
+	if startsAt.IsZero() || endsAt.IsZero() {
+		return nil, NewDomainError("start and end dates are required")
+	}
+	if !endsAt.After(startsAt) {
+		return nil, NewDomainError("end date must be after the start date")
+	}
+	if len(items) == 0 {
+		return nil, NewDomainError("at least one SKU is required")
+	}
+	seen := make(map[int64]bool, len(items))
+	for _, item := range items {
+		if item.SKUID <= 0 {
+			return nil, NewDomainError("SKU ID is required")
+		}
+		if seen[item.SKUID] {
+			return nil, NewDomainError("SKUs can only be listed once")
+		}
+		seen[item.SKUID] = true
+		if item.SalePrice < 0 {
+			return nil, NewDomainError("sale prices cannot be negative")
+		}
+		if item.SalePrice == 0 && offerID == nil {
+			return nil, NewDomainError("every SKU needs a sale price when the event has no offer")
+		}
+	}
+
+	now := time.Now()
+	return &SaleEvent{
+		Name:        name,
+		Description: description,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		Status:      SaleEventStatusScheduled,
+		OfferID:     offerID,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Items:       items,
+	}, nil
+}
+
+// SKUIDs returns the IDs of the SKUs featured in the event
+func (e *SaleEvent) SKUIDs() []int64 {
+	ids := make([]int64, len(e.Items))
+	for i, item := range e.Items {
+		ids[i] = item.SKUID
+	}
+	return ids
+}
+
+// IsDueToStart checks if a scheduled event should start at a time
+func (e *SaleEvent) IsDueToStart(at time.Time) bool {
+	return e.Status == SaleEventStatusScheduled && !e.StartsAt.After(at)
+}
+
+// IsDueToEnd checks if an active event should end at a time
+func (e *SaleEvent) IsDueToEnd(at time.Time) bool {
+	return e.Status == SaleEventStatusActive && !e.EndsAt.After(at)
+}
+
+// Start records that the event started
+func (e *SaleEvent) Start() {
+	now := time.Now()
+	e.Status = SaleEventStatusActive
+	e.StartedAt = &now
+	e.UpdatedAt = now
+}
+
+// End records that the event ended, at its end date or when stopped early
+func (e *SaleEvent) End() {
+	now := time.Now()
+	e.Status = SaleEventStatusEnded
+	e.EndedAt = &now
+	e.UpdatedAt = now
+}
+
+// Cancel cancels an event that has not started
+func (e *SaleEvent) Cancel() error {
+	if e.Status != SaleEventStatusScheduled {
+		return NewDomainError("only scheduled sale events can be cancelled")
+	}
+	e.Status = SaleEventStatusCancelled
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// SaleEventDeal is a SKU on sale in an active event, as shown on the storefront
+type SaleEventDeal struct {
+	SaleEventID int64
+	Name        string
+	SKUID       int64
+	ProductID   *int64
+	SalePrice   float64 // 0 when the discount comes from the event's offer
+	EndsAt      time.Time
+}
+
+// SaleEventFilter filters listed sale events
+type SaleEventFilter struct {
+	Status SaleEventStatus // Empty lists every status
+	Limit  int
+}
+
+// SKUSales sums the sales of a SKU over a period
+type SKUSales struct {
+	SKUID   int64
+	Orders  int64
+	Units   int64
+	Revenue float64
+}
+
+// SaleEventSales sums the sales of the SKUs of a sale event over a period, from
+// submitted orders that were not cancelled or refunded. Orders counts each order
+// once, however many of the SKUs it has.
+type SaleEventSales struct {
+	Orders  int64
+	Units   int64
+	Revenue float64
+	SKUs    []*SKUSales
+}
+
+// SaleEventRepository defines the interface for sale event persistence
+type SaleEventRepository interface {
+	// Save creates or updates a sale event with its items and sets its ID
+	Save(ctx context.Context, event *SaleEvent) error
+
+	// Transition moves an event from one status to another, reporting false when
+	// its status is no longer from, e.g. when another server got to it first
+	Transition(ctx context.Context, id int64, from, to SaleEventStatus) (bool, error)
+
+	// FindByID retrieves a sale event with its items
+	FindByID(ctx context.Context, id int64) (*SaleEvent, error)
+
+	// FindAll retrieves sale events with their items, latest start first
+	FindAll(ctx context.Context, filter SaleEventFilter) ([]*SaleEvent, error)
+
+	// FindDue retrieves the scheduled events due to start and the active events
+	// due to end at a time, with their items, oldest first
+	FindDue(ctx context.Context, at time.Time) ([]*SaleEvent, error)
+
+	// FindStorefront retrieves the active events and the scheduled events
+	// starting before a time, with their items, by start date
+	FindStorefront(ctx context.Context, startingBefore time.Time) ([]*SaleEvent, error)
+
+	// FindOverlapping lists the SKUs among skuIDs that are in another scheduled or
+	// active event overlapping a period
+	FindOverlapping(ctx context.Context, skuIDs []int64, startsAt, endsAt time.Time, excludeID int64) ([]int64, error)
+
+	// FindActiveDealsBySKUIDs retrieves the deals of active events on the given SKUs
+	FindActiveDealsBySKUIDs(ctx context.Context, skuIDs []int64) ([]*SaleEventDeal, error)
+
+	// FindActiveDealsByProductIDs retrieves the deals of active events on the SKUs of the given products
+	FindActiveDealsByProductIDs(ctx context.Context, productIDs []int64) ([]*SaleEventDeal, error)
+
+	// SumSales sums the sales of SKUs, in total and by SKU, in orders submitted within a period
+	SumSales(ctx context.Context, skuIDs []int64, from, to time.Time) (*SaleEventSales, error)
+}
@@ -0,0 +1,16 @@
+package domain
+
+// SearchCriteria is an analyzed product search. Every entry in TermGroups must
+// match at least one searchable field; any alternative within a group may match.
+// It is produced by the search context from admin-managed synonyms, stopwords,
+// field boosts and pinned products.
+type SearchCriteria struct {
+	TermGroups       [][]string
+	FieldBoosts      map[string]float64 // column name -> boost
+	PinnedProductIDs []int64            // returned first, in this order
+}
+
+// HasTerms checks if the criteria contains at least one term to match
+func (c *SearchCriteria) HasTerms() bool {
+	return c != nil && len(c.TermGroups) > 0
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ShippingRestriction limits where a product or SKU may be shipped and whether
+// the buyer must pass age verification
+type ShippingRestriction struct {
+	ID                      int64
+	ProductID               *int64   // Applies to every SKU of the product
+	SKUID                   *int64   // Applies to a single SKU
+	BlockedCountries        []string // ISO alpha-2 codes
+	BlockedRegions          []string // ISO 3166-2 codes, e.g. US-CA
+	AgeVerificationRequired bool
+	MinimumAge              int
+	Reason                  string // Shown to shoppers when the restriction applies
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+// NewShippingRestriction creates a new shipping restriction
+func NewShippingRestriction(productID, skuID *int64, reason string) (*ShippingRestriction, error) {
+	now := time.Now()
+	restriction := &ShippingRestriction{CreatedAt: now}
+	if err := restriction.Update(productID, skuID, reason); err != nil {
+		return nil, err
+	}
+	restriction.UpdatedAt = now
+	return restriction, nil
+}
+
+// Update changes the target and reason of the restriction
+func (r *ShippingRestriction) Update(productID, skuID *int64, reason string) error {
+	if (productID == nil) == (skuID == nil) {
+		return NewDomainError("a shipping restriction applies to exactly one product or SKU")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return NewDomainError("shipping restriction reason is required")
+	}
+	r.ProductID = productID
+	r.SKUID = skuID
+	r.Reason = reason
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetDestinations sets the blocked countries and regions, normalized to upper case
+func (r *ShippingRestriction) SetDestinations(countries, regions []string) {
+	r.BlockedCountries = normalizeCodes(countries)
+	r.BlockedRegions = normalizeCodes(regions)
+	r.UpdatedAt = time.Now()
+}
+
+// SetAgeVerification sets the age verification requirement
+func (r *ShippingRestriction) SetAgeVerification(required bool, minimumAge int) error {
+	if required && minimumAge <= 0 {
+		return NewDomainError("minimum age is required when age verification is required")
+	}
+	r.AgeVerificationRequired = required
+	r.MinimumAge = minimumAge
+	if !required {
+		r.MinimumAge = 0
+	}
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// BlocksDestination checks if shipping to the country and region is disallowed.
+// The region may be given as "CA" or "US-CA".
+func (r *ShippingRestriction) BlocksDestination(country, region string) bool {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region != "" && !strings.Contains(region, "-") && country != "" {
+		region = country + "-" + region
+	}
+	for _, blocked := range r.BlockedCountries {
+		if blocked == country {
+			return true
+		}
+	}
+	for _, blocked := range r.BlockedRegions {
+		if region != "" && blocked == region {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesTo checks if the restriction targets the given product or SKU
+func (r *ShippingRestriction) AppliesTo(productID, skuID int64) bool {
+	return (r.ProductID != nil && *r.ProductID == productID) || (r.SKUID != nil && *r.SKUID == skuID)
+}
+
+func normalizeCodes(codes []string) []string {
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+	return normalized
+}
+
+// ShippingRestrictionRepository defines the interface for shipping restriction persistence
+type ShippingRestrictionRepository interface {
+	// Save creates or updates a shipping restriction
+	Save(ctx context.Context, restriction *ShippingRestriction) error
+
+	// Delete removes a shipping restriction
+	Delete(ctx context.Context, id int64) error
+
+	// FindByID retrieves a shipping restriction by ID
+	FindByID(ctx context.Context, id int64) (*ShippingRestriction, error)
+
+	// FindAll retrieves all shipping restrictions
+	FindAll(ctx context.Context) ([]*ShippingRestriction, error)
+
+	// FindByProductID retrieves the restrictions of a product and of its SKUs
+	FindByProductID(ctx context.Context, productID int64) ([]*ShippingRestriction, error)
+
+	// FindApplicable retrieves the restrictions targeting any of the products or SKUs
+	FindApplicable(ctx context.Context, productIDs, skuIDs []int64) ([]*ShippingRestriction, error)
+}
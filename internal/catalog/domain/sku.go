@@ -99,6 +99,19 @@ func (s *SKU) UpdatePricing(retailPrice, salePrice float64) {
 	s.UpdatedAt = time.Now()
 }
 
+// EffectivePrice returns the price the SKU sells at: its sale price when below the retail price
+func (s *SKU) EffectivePrice() float64 {
+	return EffectivePrice(s.RetailPrice, s.SalePrice)
+}
+
+// EffectivePrice returns the sale price when it is set and below the retail price, the retail price otherwise
+func EffectivePrice(retailPrice, salePrice float64) float64 {
+	if salePrice > 0 && salePrice < retailPrice {
+		return salePrice
+	}
+	return retailPrice
+}
+
 // SetDimensions sets the physical dimensions
 func (s *SKU) SetDimensions(height, width, depth, girth float64, containerShape, dimensionUnit, containerSize string) {
 	s.Height = height
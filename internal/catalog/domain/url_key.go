@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/slug"
+)
+
+// URLKeyEntity is the kind of catalog entity a URL key leads to. Products and
+// categories have separate key spaces, so a product and a category may share a key.
+type URLKeyEntity string
+
+const (
+	URLKeyEntityProduct  URLKeyEntity = "product"
+	URLKeyEntityCategory URLKeyEntity = "category"
+)
+
+// IsValid reports whether the entity type has URL keys
+func (e URLKeyEntity) IsValid() bool {
+	return e == URLKeyEntityProduct || e == URLKeyEntityCategory
+}
+
+// URLKeyScope is the site and locale a URL key is served on. An empty field
+// matches every site or locale, so the zero scope is the default key stored on
+// the product or category. Two scopes overlap when a storefront request could be
+// served by both, and the same key may then not lead to two entities.
+type URLKeyScope struct {
+	SiteID string
+	Locale string
+}
+
+// IsDefault reports whether the scope is that of default keys
+func (s URLKeyScope) IsDefault() bool {
+	return s.SiteID == "" && s.Locale == ""
+}
+
+// LocalizedURLKey is the URL key of a product or category on a site and/or
+// locale, e.g. the German key of a product, replacing its default key there
+type LocalizedURLKey struct {
+	Entity    URLKeyEntity
+	EntityID  int64
+	Scope     URLKeyScope
+	URLKey    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewLocalizedURLKey creates the key of an entity in a scope
+func NewLocalizedURLKey(entity URLKeyEntity, entityID int64, scope URLKeyScope, urlKey string) (*LocalizedURLKey, error) {
+	if !entity.IsValid() {
+		return nil, NewDomainError(fmt.Sprintf("unknown URL key entity %q, expected product or category", entity))
+	}
+	if entityID == 0 {
+		return nil, NewDomainError("a URL key requires a product or category")
+	}
+	scope.SiteID = strings.TrimSpace(scope.SiteID)
+	scope.Locale = strings.TrimSpace(scope.Locale)
+	if scope.IsDefault() {
+		return nil, NewDomainError("a localized URL key requires a site or locale")
+	}
+	if !slug.IsValid(urlKey) {
+		return nil, NewDomainError(fmt.Sprintf("URL key %q must be lower case letters and digits separated by hyphens", urlKey))
+	}
+
+	now := time.Now()
+	return &LocalizedURLKey{
+		Entity:    entity,
+		EntityID:  entityID,
+		Scope:     scope,
+		URLKey:    urlKey,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// URLKeyRepository defines the interface for localized URL key persistence. A key
+// leads to at most one entity of a type wherever it is served: it may not be used
+// by another entity in an overlapping scope, including as its default key.
+type URLKeyRepository interface {
+	// Save stores a localized key, replacing the entity's key in the same scope. It
+	// fails with a conflict when another entity uses the key in an overlapping scope.
+	Save(ctx context.Context, key *LocalizedURLKey) error
+
+	// Delete removes the localized key of an entity in a scope
+	Delete(ctx context.Context, entity URLKeyEntity, entityID int64, scope URLKeyScope) error
+
+	// FindByEntity retrieves the localized keys of an entity
+	FindByEntity(ctx context.Context, entity URLKeyEntity, entityID int64) ([]*LocalizedURLKey, error)
+
+	// FindOwner returns the entity other than excludeID using urlKey, as its default
+	// key or as a localized key in a scope overlapping scope, or 0 when it is free
+	FindOwner(ctx context.Context, entity URLKeyEntity, scope URLKeyScope, urlKey string, excludeID int64) (int64, error)
+
+	// FindTaken returns the keys equal to base or to base followed by a hyphen that
+	// entities other than excludeID use in scopes overlapping scope
+	FindTaken(ctx context.Context, entity URLKeyEntity, scope URLKeyScope, base string, excludeID int64) ([]string, error)
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCatalogPopularityRepository implements the CatalogPopularityRepository interface using PostgreSQL
+type PostgresCatalogPopularityRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCatalogPopularityRepository creates a new PostgresCatalogPopularityRepository
+func NewPostgresCatalogPopularityRepository(db *database.DB) *PostgresCatalogPopularityRepository {
+	return &PostgresCatalogPopularityRepository{db: db}
+}
+
+// TopProductIDs returns the best-selling unarchived products since a time
+func (r *PostgresCatalogPopularityRepository) TopProductIDs(ctx context.Context, since time.Time, limit int) ([]int64, error) {
+	query := `
+		SELECT p.product_id
+		FROM blc_order_item oi
+		INNER JOIN blc_order o ON o.order_id = oi.order_id
+		INNER JOIN blc_sku s ON s.sku_id = oi.sku_id
+		INNER JOIN blc_product p ON p.product_id = s.default_product_id
+		WHERE o.submit_date >= $1
+			AND o.order_status NOT IN ('CANCELLED', 'REFUNDED')
+			AND p.archived = 'N'
+		GROUP BY p.product_id
+		ORDER BY SUM(oi.quantity) DESC, p.product_id
+		LIMIT $2`
+	return r.findIDs(ctx, query, "failed to rank products", since, limit)
+}
+
+// TopCategoryIDs returns the unarchived categories whose products sold the most since a time
+func (r *PostgresCatalogPopularityRepository) TopCategoryIDs(ctx context.Context, since time.Time, limit int) ([]int64, error) {
+	query := `
+		SELECT c.category_id
+		FROM blc_order_item oi
+		INNER JOIN blc_order o ON o.order_id = oi.order_id
+		INNER JOIN blc_sku s ON s.sku_id = oi.sku_id
+		INNER JOIN blc_category_product_xref xref ON xref.product_id = s.default_product_id
+		INNER JOIN blc_category c ON c.category_id = xref.category_id
+		WHERE o.submit_date >= $1
+			AND o.order_status NOT IN ('CANCELLED', 'REFUNDED')
+			AND c.archived = 'N'
+		GROUP BY c.category_id
+		ORDER BY SUM(oi.quantity) DESC, c.category_id
+		LIMIT $2`
+	return r.findIDs(ctx, query, "failed to rank categories", since, limit)
+}
+
+func (r *PostgresCatalogPopularityRepository) findIDs(ctx context.Context, query, failure string, args ...interface{}) ([]int64, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, failure)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.InternalWrap(err, failure)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, failure)
+	}
+	return ids, nil
+}
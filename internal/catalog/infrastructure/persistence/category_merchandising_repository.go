@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCategoryMerchandisingRepository implements the CategoryMerchandisingRepository interface
+type PostgresCategoryMerchandisingRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCategoryMerchandisingRepository creates a new PostgresCategoryMerchandisingRepository
+func NewPostgresCategoryMerchandisingRepository(db *database.DB) *PostgresCategoryMerchandisingRepository {
+	return &PostgresCategoryMerchandisingRepository{db: db}
+}
+
+// Save creates or replaces the merchandising rules of a category
+func (r *PostgresCategoryMerchandisingRepository) Save(ctx context.Context, merchandising *domain.CategoryMerchandising) error {
+	query := `
+		INSERT INTO catalog_category_merchandising (
+			category_id, pinned_product_ids, sort_rule, best_seller_days, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (category_id) DO UPDATE SET
+			pinned_product_ids = EXCLUDED.pinned_product_ids,
+			sort_rule = EXCLUDED.sort_rule,
+			best_seller_days = EXCLUDED.best_seller_days,
+			updated_at = EXCLUDED.updated_at`
+	err := r.db.Exec(ctx, query,
+		merchandising.CategoryID, merchandising.PinnedProductIDs, string(merchandising.SortRule),
+		merchandising.BestSellerDays, merchandising.CreatedAt, merchandising.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save category merchandising")
+	}
+	return nil
+}
+
+// Delete removes the merchandising rules of a category
+func (r *PostgresCategoryMerchandisingRepository) Delete(ctx context.Context, categoryID int64) error {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM catalog_category_merchandising WHERE category_id = $1`, categoryID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete category merchandising")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("category merchandising")
+	}
+	return nil
+}
+
+// FindByCategoryID retrieves the merchandising rules of a category, nil if none
+func (r *PostgresCategoryMerchandisingRepository) FindByCategoryID(ctx context.Context, categoryID int64) (*domain.CategoryMerchandising, error) {
+	query := `
+		SELECT category_id, pinned_product_ids, sort_rule, best_seller_days, created_at, updated_at
+		FROM catalog_category_merchandising
+		WHERE category_id = $1`
+
+	merchandising := &domain.CategoryMerchandising{}
+	var sortRule string
+	err := r.db.QueryRow(ctx, query, categoryID).Scan(
+		&merchandising.CategoryID, &merchandising.PinnedProductIDs, &sortRule,
+		&merchandising.BestSellerDays, &merchandising.CreatedAt, &merchandising.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find category merchandising")
+	}
+	merchandising.SortRule = domain.MerchandisingSort(sortRule)
+	return merchandising, nil
+}
@@ -2,9 +2,14 @@ package persistence
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/qhato/ecommerce/internal/catalog/domain"
 	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
 )
 
 // PostgresCategoryProductXrefRepository implements the CategoryProductXrefRepository interface
@@ -17,38 +22,158 @@ func NewPostgresCategoryProductXrefRepository(db *database.DB) *PostgresCategory
 	return &PostgresCategoryProductXrefRepository{db: db}
 }
 
-// Save stores a new category-product cross-reference.
+const categoryProductXrefColumns = `category_product_id, category_id, product_id, default_reference, display_order, created_at, updated_at`
+
+// Save stores a new category-product cross-reference or updates an existing one.
 func (r *PostgresCategoryProductXrefRepository) Save(ctx context.Context, xref *domain.CategoryProductXref) error {
-	// TODO: Implement actual persistence logic
+	return r.SaveAll(ctx, []*domain.CategoryProductXref{xref})
+}
+
+// SaveAll upserts category-product cross-references with one multi-row
+// statement. When the same pair appears more than once, the last one wins.
+func (r *PostgresCategoryProductXrefRepository) SaveAll(ctx context.Context, xrefs []*domain.CategoryProductXref) error {
+	type pair struct{ categoryID, productID int64 }
+	byPair := make(map[pair][]*domain.CategoryProductXref, len(xrefs))
+	var categoryIDs, productIDs []int64
+	var defaultReferences []bool
+	var displayOrders []float64
+	var createdAts, updatedAts []time.Time
+	for i := len(xrefs) - 1; i >= 0; i-- {
+		xref := xrefs[i]
+		key := pair{xref.CategoryID, xref.ProductID}
+		if _, ok := byPair[key]; !ok {
+			categoryIDs = append(categoryIDs, xref.CategoryID)
+			productIDs = append(productIDs, xref.ProductID)
+			defaultReferences = append(defaultReferences, xref.DefaultReference)
+			displayOrders = append(displayOrders, xref.DisplayOrder)
+			createdAts = append(createdAts, xref.CreatedAt)
+			updatedAts = append(updatedAts, xref.UpdatedAt)
+		}
+		byPair[key] = append(byPair[key], xref)
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO blc_category_product_xref (category_id, product_id, default_reference, display_order, created_at, updated_at)
+		SELECT * FROM unnest($1::bigint[], $2::bigint[], $3::boolean[], $4::numeric[], $5::timestamptz[], $6::timestamptz[])
+		ON CONFLICT (category_id, product_id) DO UPDATE SET
+			default_reference = EXCLUDED.default_reference,
+			display_order = EXCLUDED.display_order,
+			updated_at = EXCLUDED.updated_at
+		RETURNING category_product_id, category_id, product_id`
+
+	rows, err := r.db.Query(ctx, query, categoryIDs, productIDs, defaultReferences, displayOrders, createdAts, updatedAts)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save category product xrefs")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var key pair
+		if err := rows.Scan(&id, &key.categoryID, &key.productID); err != nil {
+			return errors.InternalWrap(err, "failed to scan category product xref")
+		}
+		for _, xref := range byPair[key] {
+			xref.ID = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to save category product xrefs")
+	}
 	return nil
 }
 
 // FindByID retrieves a category-product cross-reference by its unique identifier.
 func (r *PostgresCategoryProductXrefRepository) FindByID(ctx context.Context, id int64) (*domain.CategoryProductXref, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + categoryProductXrefColumns + ` FROM blc_category_product_xref WHERE category_product_id = $1`
+	xref, err := scanCategoryProductXref(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find category product xref")
+	}
+	return xref, nil
 }
 
 // FindByCategoryID retrieves all category-product cross-references for a given category ID.
 func (r *PostgresCategoryProductXrefRepository) FindByCategoryID(ctx context.Context, categoryID int64) ([]*domain.CategoryProductXref, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + categoryProductXrefColumns + `
+		FROM blc_category_product_xref
+		WHERE category_id = $1
+		ORDER BY display_order, category_product_id`
+	return r.findAll(ctx, query, categoryID)
 }
 
 // FindByProductID retrieves all category-product cross-references for a given product ID.
 func (r *PostgresCategoryProductXrefRepository) FindByProductID(ctx context.Context, productID int64) ([]*domain.CategoryProductXref, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + categoryProductXrefColumns + `
+		FROM blc_category_product_xref
+		WHERE product_id = $1
+		ORDER BY category_product_id`
+	return r.findAll(ctx, query, productID)
 }
 
 // Delete removes a category-product cross-reference by its unique identifier.
 func (r *PostgresCategoryProductXrefRepository) Delete(ctx context.Context, id int64) error {
-	// TODO: Implement actual persistence logic
+	if err := r.db.Exec(ctx, `DELETE FROM blc_category_product_xref WHERE category_product_id = $1`, id); err != nil {
+		return errors.InternalWrap(err, "failed to delete category product xref")
+	}
 	return nil
 }
 
 // RemoveCategoryProductXref removes a specific category-product cross-reference by category ID and product ID.
 func (r *PostgresCategoryProductXrefRepository) RemoveCategoryProductXref(ctx context.Context, categoryID, productID int64) error {
-	// TODO: Implement actual persistence logic
+	return r.RemoveCategoryProductXrefs(ctx, productID, []int64{categoryID})
+}
+
+// RemoveCategoryProductXrefs removes the cross-references of a product to many categories with one statement.
+func (r *PostgresCategoryProductXrefRepository) RemoveCategoryProductXrefs(ctx context.Context, productID int64, categoryIDs []int64) error {
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+	query := `DELETE FROM blc_category_product_xref WHERE product_id = $1 AND category_id = ANY($2::bigint[])`
+	if err := r.db.Exec(ctx, query, productID, categoryIDs); err != nil {
+		return errors.InternalWrap(err, "failed to remove category product xrefs")
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *PostgresCategoryProductXrefRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*domain.CategoryProductXref, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list category product xrefs")
+	}
+	defer rows.Close()
+
+	var xrefs []*domain.CategoryProductXref
+	for rows.Next() {
+		xref, err := scanCategoryProductXref(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan category product xref")
+		}
+		xrefs = append(xrefs, xref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to list category product xrefs")
+	}
+	return xrefs, nil
+}
+
+func scanCategoryProductXref(row pgx.Row) (*domain.CategoryProductXref, error) {
+	xref := &domain.CategoryProductXref{}
+	var defaultReference sql.NullBool
+	var displayOrder sql.NullFloat64
+	err := row.Scan(
+		&xref.ID, &xref.CategoryID, &xref.ProductID, &defaultReference, &displayOrder, &xref.CreatedAt, &xref.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	xref.DefaultReference = defaultReference.Bool
+	xref.DisplayOrder = displayOrder.Float64
+	return xref, nil
+}
@@ -65,6 +65,9 @@ func (r *PostgresCategoryRepository) Create(ctx context.Context, category *domai
 		category.DefaultParentCategoryID,
 	).Scan(&category.ID)
 
+	if database.IsUniqueViolation(err, "uq_blc_category_url_key") {
+		return errors.Conflict(fmt.Sprintf("URL key %q is already used by another category", category.URLKey))
+	}
 	if err != nil {
 		return errors.InternalWrap(err, "failed to create category")
 	}
@@ -126,6 +129,9 @@ func (r *PostgresCategoryRepository) Update(ctx context.Context, category *domai
 		category.DefaultParentCategoryID,
 		category.ID,
 	)
+	if database.IsUniqueViolation(err, "uq_blc_category_url_key") {
+		return errors.Conflict(fmt.Sprintf("URL key %q is already used by another category", category.URLKey))
+	}
 	if err != nil {
 		return errors.InternalWrap(err, "failed to get rows affected")
 	}
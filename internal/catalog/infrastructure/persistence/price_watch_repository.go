@@ -0,0 +1,350 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresPriceWatchRepository implements the PriceWatchRepository interface
+type PostgresPriceWatchRepository struct {
+	db *database.DB
+}
+
+// NewPostgresPriceWatchRepository creates a new PostgresPriceWatchRepository
+func NewPostgresPriceWatchRepository(db *database.DB) *PostgresPriceWatchRepository {
+	return &PostgresPriceWatchRepository{db: db}
+}
+
+const priceWatchColumns = `
+	w.price_watch_id, w.sku_id, w.customer_id, w.watched_price, w.status, w.notified_price,
+	w.notified_at, w.converted_order_id, w.converted_at, w.created_at, w.updated_at`
+
+// Save creates or updates a price watch
+func (r *PostgresPriceWatchRepository) Save(ctx context.Context, watch *domain.PriceWatch) error {
+	if watch.ID == 0 {
+		query := `
+			INSERT INTO catalog_price_watch (
+				sku_id, customer_id, watched_price, status, notified_price, notified_at,
+				converted_order_id, converted_at, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING price_watch_id`
+		err := r.db.QueryRow(ctx, query,
+			watch.SKUID, watch.CustomerID, watch.WatchedPrice, string(watch.Status), watch.NotifiedPrice,
+			watch.NotifiedAt, watch.ConvertedOrderID, watch.ConvertedAt, watch.CreatedAt, watch.UpdatedAt,
+		).Scan(&watch.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create price watch")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE catalog_price_watch SET
+			watched_price = $2, status = $3, notified_price = $4, notified_at = $5,
+			converted_order_id = $6, converted_at = $7, updated_at = $8
+		WHERE price_watch_id = $1`
+	result, err := r.db.Pool().Exec(ctx, query,
+		watch.ID, watch.WatchedPrice, string(watch.Status), watch.NotifiedPrice, watch.NotifiedAt,
+		watch.ConvertedOrderID, watch.ConvertedAt, watch.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update price watch")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("price watch")
+	}
+	return nil
+}
+
+// FindByID retrieves a price watch by ID
+func (r *PostgresPriceWatchRepository) FindByID(ctx context.Context, id int64) (*domain.PriceWatch, error) {
+	query := `SELECT` + priceWatchColumns + ` FROM catalog_price_watch w WHERE w.price_watch_id = $1`
+
+	watch, err := scanPriceWatch(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("price watch")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find price watch")
+	}
+	return watch, nil
+}
+
+// FindByCustomerAndSKU retrieves the watch of a customer on a SKU, nil when there is none
+func (r *PostgresPriceWatchRepository) FindByCustomerAndSKU(ctx context.Context, customerID, skuID int64) (*domain.PriceWatch, error) {
+	query := `SELECT` + priceWatchColumns + ` FROM catalog_price_watch w WHERE w.customer_id = $1 AND w.sku_id = $2`
+
+	watch, err := scanPriceWatch(r.db.QueryRow(ctx, query, customerID, skuID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find price watch")
+	}
+	return watch, nil
+}
+
+// FindByCustomerID retrieves the watches of a customer that are not cancelled, newest first
+func (r *PostgresPriceWatchRepository) FindByCustomerID(ctx context.Context, customerID int64) ([]*domain.PriceWatch, error) {
+	query := `SELECT` + priceWatchColumns + `
+		FROM catalog_price_watch w
+		WHERE w.customer_id = $1 AND w.status <> 'CANCELLED'
+		ORDER BY w.created_at DESC, w.price_watch_id DESC`
+	return r.query(ctx, query, customerID)
+}
+
+// FindBySKUID retrieves the watches of a SKU, newest first
+func (r *PostgresPriceWatchRepository) FindBySKUID(ctx context.Context, skuID int64, limit int) ([]*domain.PriceWatch, error) {
+	query := `SELECT` + priceWatchColumns + `
+		FROM catalog_price_watch w
+		WHERE w.sku_id = $1
+		ORDER BY w.created_at DESC, w.price_watch_id DESC
+		LIMIT $2`
+	return r.query(ctx, query, skuID, limit)
+}
+
+// FindTriggered retrieves the active watches of a SKU watched above an effective
+// price, with the email address and name of each customer
+func (r *PostgresPriceWatchRepository) FindTriggered(ctx context.Context, skuID int64, effectivePrice float64) ([]*domain.TriggeredPriceWatch, error) {
+	query := `SELECT` + priceWatchColumns + `, COALESCE(c.email_address, ''), TRIM(COALESCE(c.first_name, '') || ' ' || COALESCE(c.last_name, ''))
+		FROM catalog_price_watch w
+		JOIN blc_customer c ON c.customer_id = w.customer_id
+		WHERE w.sku_id = $1 AND w.status = 'ACTIVE' AND w.watched_price > $2
+		ORDER BY w.price_watch_id`
+	rows, err := r.db.Query(ctx, query, skuID, effectivePrice)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find triggered price watches")
+	}
+	defer rows.Close()
+
+	triggered := make([]*domain.TriggeredPriceWatch, 0)
+	for rows.Next() {
+		t := &domain.TriggeredPriceWatch{Watch: &domain.PriceWatch{}}
+		var status string
+		err := rows.Scan(
+			&t.Watch.ID, &t.Watch.SKUID, &t.Watch.CustomerID, &t.Watch.WatchedPrice, &status, &t.Watch.NotifiedPrice,
+			&t.Watch.NotifiedAt, &t.Watch.ConvertedOrderID, &t.Watch.ConvertedAt, &t.Watch.CreatedAt, &t.Watch.UpdatedAt,
+			&t.Email, &t.Name,
+		)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan triggered price watch")
+		}
+		t.Watch.Status = domain.PriceWatchStatus(status)
+		triggered = append(triggered, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate triggered price watches")
+	}
+	return triggered, nil
+}
+
+// SummarizeSKU counts the watches of a SKU by status
+func (r *PostgresPriceWatchRepository) SummarizeSKU(ctx context.Context, skuID int64) (*domain.PriceWatchSummary, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'ACTIVE'),
+			COUNT(*) FILTER (WHERE status = 'NOTIFIED'),
+			COUNT(*) FILTER (WHERE status = 'CONVERTED'),
+			COUNT(*) FILTER (WHERE status = 'CANCELLED')
+		FROM catalog_price_watch
+		WHERE sku_id = $1`
+	summary := &domain.PriceWatchSummary{}
+	err := r.db.QueryRow(ctx, query, skuID).Scan(&summary.Active, &summary.Notified, &summary.Converted, &summary.Cancelled)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to summarize price watches")
+	}
+	return summary, nil
+}
+
+// AttributeConversions marks notified watches converted by the first order of the
+// customer with the SKU submitted within the window after the alert
+func (r *PostgresPriceWatchRepository) AttributeConversions(ctx context.Context, window time.Duration) (int64, error) {
+	query := `
+		UPDATE catalog_price_watch w SET
+			status = 'CONVERTED', converted_order_id = c.order_id, converted_at = c.submit_date, updated_at = NOW()
+		FROM (
+			SELECT DISTINCT ON (n.price_watch_id) n.price_watch_id, o.order_id, o.submit_date
+			FROM catalog_price_watch n
+			JOIN blc_order o ON o.customer_id = n.customer_id
+			JOIN blc_order_item oi ON oi.order_id = o.order_id AND oi.sku_id = n.sku_id
+			WHERE n.status = 'NOTIFIED'
+				AND o.submit_date >= n.notified_at
+				AND o.submit_date < n.notified_at + make_interval(secs => $1)
+				AND COALESCE(o.is_preview, FALSE) = FALSE
+				AND o.order_status NOT IN ('CANCELLED', 'REFUNDED')
+			ORDER BY n.price_watch_id, o.submit_date
+		) c
+		WHERE w.price_watch_id = c.price_watch_id`
+	result, err := r.db.Pool().Exec(ctx, query, window.Seconds())
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to attribute price watch conversions")
+	}
+	return result.RowsAffected(), nil
+}
+
+func (r *PostgresPriceWatchRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.PriceWatch, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find price watches")
+	}
+	defer rows.Close()
+
+	watches := make([]*domain.PriceWatch, 0)
+	for rows.Next() {
+		watch, err := scanPriceWatch(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan price watch")
+		}
+		watches = append(watches, watch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate price watches")
+	}
+	return watches, nil
+}
+
+func scanPriceWatch(row pgx.Row) (*domain.PriceWatch, error) {
+	watch := &domain.PriceWatch{}
+	var status string
+	err := row.Scan(
+		&watch.ID, &watch.SKUID, &watch.CustomerID, &watch.WatchedPrice, &status, &watch.NotifiedPrice,
+		&watch.NotifiedAt, &watch.ConvertedOrderID, &watch.ConvertedAt, &watch.CreatedAt, &watch.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	watch.Status = domain.PriceWatchStatus(status)
+	return watch, nil
+}
+
+// PostgresScheduledPriceChangeRepository implements the ScheduledPriceChangeRepository interface
+type PostgresScheduledPriceChangeRepository struct {
+	db *database.DB
+}
+
+// NewPostgresScheduledPriceChangeRepository creates a new PostgresScheduledPriceChangeRepository
+func NewPostgresScheduledPriceChangeRepository(db *database.DB) *PostgresScheduledPriceChangeRepository {
+	return &PostgresScheduledPriceChangeRepository{db: db}
+}
+
+const scheduledPriceChangeColumns = `
+	scheduled_price_change_id, sku_id, retail_price, sale_price, effective_at, applied_at,
+	COALESCE(created_by, ''), created_at`
+
+// Save creates or updates a scheduled price change
+func (r *PostgresScheduledPriceChangeRepository) Save(ctx context.Context, change *domain.ScheduledPriceChange) error {
+	if change.ID == 0 {
+		query := `
+			INSERT INTO catalog_scheduled_price_change (
+				sku_id, retail_price, sale_price, effective_at, applied_at, created_by, created_at
+			) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+			RETURNING scheduled_price_change_id`
+		err := r.db.QueryRow(ctx, query,
+			change.SKUID, change.RetailPrice, change.SalePrice, change.EffectiveAt, change.AppliedAt,
+			change.CreatedBy, change.CreatedAt,
+		).Scan(&change.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create scheduled price change")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE catalog_scheduled_price_change SET
+			retail_price = $2, sale_price = $3, effective_at = $4, applied_at = $5
+		WHERE scheduled_price_change_id = $1`
+	result, err := r.db.Pool().Exec(ctx, query,
+		change.ID, change.RetailPrice, change.SalePrice, change.EffectiveAt, change.AppliedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update scheduled price change")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("scheduled price change")
+	}
+	return nil
+}
+
+// FindByID retrieves a scheduled price change by ID
+func (r *PostgresScheduledPriceChangeRepository) FindByID(ctx context.Context, id int64) (*domain.ScheduledPriceChange, error) {
+	query := `SELECT` + scheduledPriceChangeColumns + ` FROM catalog_scheduled_price_change WHERE scheduled_price_change_id = $1`
+
+	change, err := scanScheduledPriceChange(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("scheduled price change")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find scheduled price change")
+	}
+	return change, nil
+}
+
+// FindBySKUID retrieves the scheduled price changes of a SKU by effective date
+func (r *PostgresScheduledPriceChangeRepository) FindBySKUID(ctx context.Context, skuID int64) ([]*domain.ScheduledPriceChange, error) {
+	query := `SELECT` + scheduledPriceChangeColumns + `
+		FROM catalog_scheduled_price_change
+		WHERE sku_id = $1
+		ORDER BY effective_at, scheduled_price_change_id`
+	return r.query(ctx, query, skuID)
+}
+
+// FindDue retrieves the unapplied changes effective at or before a time, oldest first
+func (r *PostgresScheduledPriceChangeRepository) FindDue(ctx context.Context, at time.Time, limit int) ([]*domain.ScheduledPriceChange, error) {
+	query := `SELECT` + scheduledPriceChangeColumns + `
+		FROM catalog_scheduled_price_change
+		WHERE applied_at IS NULL AND effective_at <= $1
+		ORDER BY effective_at, scheduled_price_change_id
+		LIMIT $2`
+	return r.query(ctx, query, at, limit)
+}
+
+// Delete removes a scheduled price change
+func (r *PostgresScheduledPriceChangeRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM catalog_scheduled_price_change WHERE scheduled_price_change_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete scheduled price change")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("scheduled price change")
+	}
+	return nil
+}
+
+func (r *PostgresScheduledPriceChangeRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ScheduledPriceChange, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find scheduled price changes")
+	}
+	defer rows.Close()
+
+	changes := make([]*domain.ScheduledPriceChange, 0)
+	for rows.Next() {
+		change, err := scanScheduledPriceChange(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan scheduled price change")
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate scheduled price changes")
+	}
+	return changes, nil
+}
+
+func scanScheduledPriceChange(row pgx.Row) (*domain.ScheduledPriceChange, error) {
+	change := &domain.ScheduledPriceChange{}
+	err := row.Scan(
+		&change.ID, &change.SKUID, &change.RetailPrice, &change.SalePrice, &change.EffectiveAt,
+		&change.AppliedAt, &change.CreatedBy, &change.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
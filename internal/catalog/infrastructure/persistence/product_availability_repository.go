@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresProductAvailabilityRepository implements the ProductAvailabilityRepository interface
+type PostgresProductAvailabilityRepository struct {
+	db *database.DB
+}
+
+// NewPostgresProductAvailabilityRepository creates a new PostgresProductAvailabilityRepository
+func NewPostgresProductAvailabilityRepository(db *database.DB) *PostgresProductAvailabilityRepository {
+	return &PostgresProductAvailabilityRepository{db: db}
+}
+
+// Refresh rebuilds catalog_product_availability from SKU inventory levels.
+// A product is in stock when any of its available SKUs is always available,
+// has quantity available, allows backorders, or is not inventory tracked.
+func (r *PostgresProductAvailabilityRepository) Refresh(ctx context.Context) error {
+	query := `
+		INSERT INTO catalog_product_availability (product_id, in_stock, qty_available, refreshed_at)
+		SELECT
+			p.product_id,
+			COALESCE(BOOL_OR(
+				ss.inventory_type = 'ALWAYS_AVAILABLE'
+				OR (ss.inventory_type IS DISTINCT FROM 'UNAVAILABLE'
+					AND (ss.qty_available > 0 OR ss.allow_backorder OR ss.levels = 0))
+			), FALSE),
+			COALESCE(SUM(ss.qty_available), 0),
+			NOW()
+		FROM blc_product p
+		LEFT JOIN (
+			SELECT
+				s.default_product_id AS product_id,
+				s.inventory_type,
+				COALESCE(SUM(il.qty_available), 0) AS qty_available,
+				COALESCE(BOOL_OR(il.allow_backorder), FALSE) AS allow_backorder,
+				COUNT(il.sku_id) AS levels
+			FROM blc_sku s
+			LEFT JOIN blc_inventory_level il ON il.sku_id = s.sku_id::text
+			WHERE s.default_product_id IS NOT NULL
+				AND COALESCE(s.available_flag, 'Y') <> 'N'
+			GROUP BY s.sku_id, s.default_product_id, s.inventory_type
+		) ss ON ss.product_id = p.product_id
+		GROUP BY p.product_id`
+
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM catalog_product_availability`); err != nil {
+			return errors.InternalWrap(err, "failed to clear product availability")
+		}
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return errors.InternalWrap(err, "failed to rebuild product availability")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,230 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresProductBadgeRepository implements the ProductBadgeRepository interface
+type PostgresProductBadgeRepository struct {
+	db *database.DB
+}
+
+// NewPostgresProductBadgeRepository creates a new PostgresProductBadgeRepository
+func NewPostgresProductBadgeRepository(db *database.DB) *PostgresProductBadgeRepository {
+	return &PostgresProductBadgeRepository{db: db}
+}
+
+const productBadgeColumns = `
+	badge_id, product_id, code, label, COALESCE(campaign, ''), starts_at, ends_at, created_at, updated_at`
+
+// Save creates or updates a manual badge
+func (r *PostgresProductBadgeRepository) Save(ctx context.Context, badge *domain.ProductBadge) error {
+	if badge.ID == 0 {
+		query := `
+			INSERT INTO catalog_product_badge (
+				product_id, code, label, campaign, starts_at, ends_at, created_at, updated_at
+			) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+			RETURNING badge_id`
+		err := r.db.QueryRow(ctx, query,
+			badge.ProductID, badge.Code, badge.Label, badge.Campaign,
+			badge.StartsAt, badge.EndsAt, badge.CreatedAt, badge.UpdatedAt,
+		).Scan(&badge.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create product badge")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE catalog_product_badge SET
+			code = $2, label = $3, campaign = NULLIF($4, ''), starts_at = $5, ends_at = $6, updated_at = $7
+		WHERE badge_id = $1`
+	result, err := r.db.Pool().Exec(ctx, query,
+		badge.ID, badge.Code, badge.Label, badge.Campaign, badge.StartsAt, badge.EndsAt, badge.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update product badge")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("product badge")
+	}
+	return nil
+}
+
+// Delete removes a manual badge
+func (r *PostgresProductBadgeRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM catalog_product_badge WHERE badge_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete product badge")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("product badge")
+	}
+	return nil
+}
+
+// FindByID retrieves a manual badge by ID
+func (r *PostgresProductBadgeRepository) FindByID(ctx context.Context, id int64) (*domain.ProductBadge, error) {
+	query := `SELECT` + productBadgeColumns + ` FROM catalog_product_badge WHERE badge_id = $1`
+
+	badge, err := scanProductBadge(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("product badge")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find product badge")
+	}
+	return badge, nil
+}
+
+// FindByProductID retrieves the manual badges of a product, scheduled or not
+func (r *PostgresProductBadgeRepository) FindByProductID(ctx context.Context, productID int64) ([]*domain.ProductBadge, error) {
+	query := `SELECT` + productBadgeColumns + `
+		FROM catalog_product_badge
+		WHERE product_id = $1
+		ORDER BY starts_at NULLS FIRST, badge_id`
+	return r.query(ctx, query, productID)
+}
+
+// FindActiveByProductIDs retrieves the manual badges of the products shown at a time
+func (r *PostgresProductBadgeRepository) FindActiveByProductIDs(ctx context.Context, productIDs []int64, at time.Time) ([]*domain.ProductBadge, error) {
+	query := `SELECT` + productBadgeColumns + `
+		FROM catalog_product_badge
+		WHERE product_id = ANY($1) AND ` + badgeWindowCondition(2) + `
+		ORDER BY product_id, starts_at NULLS FIRST, badge_id`
+	return r.query(ctx, query, productIDs, at)
+}
+
+// FindComputedBadges returns the computed badge codes of the products at a time
+func (r *PostgresProductBadgeRepository) FindComputedBadges(ctx context.Context, productIDs []int64, rules domain.BadgeRules, at time.Time) (map[int64][]string, error) {
+	badges := make(map[int64][]string)
+	if len(productIDs) == 0 {
+		return badges, nil
+	}
+
+	codes := []string{domain.BadgeNew, domain.BadgeSale, domain.BadgeBestseller}
+	args := []interface{}{productIDs}
+	columns := make([]string, len(codes))
+	for i, code := range codes {
+		columns[i], args = computedBadgeCondition(code, rules, at, "p.", args)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT p.product_id, %s, %s, %s
+		FROM blc_product p
+		WHERE p.product_id = ANY($1)`, columns[0], columns[1], columns[2])
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to compute product badges")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int64
+		flags := make([]bool, len(codes))
+		if err := rows.Scan(&productID, &flags[0], &flags[1], &flags[2]); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan product badges")
+		}
+		for i, flag := range flags {
+			if flag {
+				badges[productID] = append(badges[productID], codes[i])
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate product badges")
+	}
+	return badges, nil
+}
+
+func (r *PostgresProductBadgeRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ProductBadge, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list product badges")
+	}
+	defer rows.Close()
+
+	badges := make([]*domain.ProductBadge, 0)
+	for rows.Next() {
+		badge, err := scanProductBadge(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan product badge")
+		}
+		badges = append(badges, badge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate product badges")
+	}
+	return badges, nil
+}
+
+func scanProductBadge(row pgx.Row) (*domain.ProductBadge, error) {
+	badge := &domain.ProductBadge{}
+	err := row.Scan(
+		&badge.ID, &badge.ProductID, &badge.Code, &badge.Label, &badge.Campaign,
+		&badge.StartsAt, &badge.EndsAt, &badge.CreatedAt, &badge.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return badge, nil
+}
+
+// badgeWindowCondition matches manual badges shown at the time bound to $argIndex
+func badgeWindowCondition(argIndex int) string {
+	return fmt.Sprintf("(starts_at IS NULL OR starts_at <= $%[1]d) AND (ends_at IS NULL OR ends_at > $%[1]d)", argIndex)
+}
+
+// badgeCondition matches products showing the criteria's badge, computed or manual
+func badgeCondition(criteria *domain.BadgeCriteria, alias string, args []interface{}) (string, []interface{}) {
+	code := domain.NormalizeBadgeCode(criteria.Code)
+	args = append(args, code, criteria.At)
+	manual := fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM catalog_product_badge b
+			WHERE b.product_id = %sproduct_id AND b.code = $%d
+				AND (b.starts_at IS NULL OR b.starts_at <= $%[3]d) AND (b.ends_at IS NULL OR b.ends_at > $%[3]d)
+		)`, alias, len(args)-1, len(args))
+	if !domain.IsComputedBadge(code) {
+		return manual, args
+	}
+
+	var computed string
+	computed, args = computedBadgeCondition(code, criteria.Rules, criteria.At, alias, args)
+	return "(" + computed + " OR " + manual + ")", args
+}
+
+// computedBadgeCondition evaluates a computed badge of the product table aliased
+// by alias; a rule of 0 disables its badge
+func computedBadgeCondition(code string, rules domain.BadgeRules, at time.Time, alias string, args []interface{}) (string, []interface{}) {
+	switch code {
+	case domain.BadgeNew:
+		if rules.NewDays <= 0 {
+			return "FALSE", args
+		}
+		args = append(args, at.AddDate(0, 0, -rules.NewDays), at)
+		return fmt.Sprintf("(COALESCE(%[1]sactive_start_date, %[1]screated_at) > $%[2]d AND COALESCE(%[1]sactive_start_date, %[1]screated_at) <= $%[3]d)",
+			alias, len(args)-1, len(args)), args
+	case domain.BadgeSale:
+		return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM blc_sku s
+			WHERE s.default_product_id = %sproduct_id AND COALESCE(s.available_flag, 'Y') <> 'N'
+				AND s.sale_price > 0 AND s.sale_price < s.retail_price
+		)`, alias), args
+	case domain.BadgeBestseller:
+		if rules.BestsellerDays <= 0 || rules.BestsellerMinUnits <= 0 {
+			return "FALSE", args
+		}
+		args = append(args, at.AddDate(0, 0, -rules.BestsellerDays), rules.BestsellerMinUnits)
+		return fmt.Sprintf("(%s >= $%d)", unitsOrderedExpr(alias+"product_id", len(args)-1), len(args)), args
+	}
+	return "FALSE", args
+}
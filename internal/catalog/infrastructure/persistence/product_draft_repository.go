@@ -0,0 +1,159 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresProductDraftRepository implements the ProductDraftRepository interface
+type PostgresProductDraftRepository struct {
+	db *database.DB
+}
+
+// NewPostgresProductDraftRepository creates a new PostgresProductDraftRepository
+func NewPostgresProductDraftRepository(db *database.DB) *PostgresProductDraftRepository {
+	return &PostgresProductDraftRepository{db: db}
+}
+
+const productDraftColumns = `
+	product_id, status, data, edited_by, reviewed_by, review_note, created_at, updated_at`
+
+// productDraftData is the edited content of a draft, stored as JSON
+type productDraftData struct {
+	Product     *domain.Product        `json:"product"`
+	Attributes  map[string]string      `json:"attributes,omitempty"`
+	CategoryIDs []int64                `json:"category_ids"` // null leaves the categories unchanged
+	Changes     map[string]interface{} `json:"changes,omitempty"`
+}
+
+// Save creates or replaces the draft of a product
+func (r *PostgresProductDraftRepository) Save(ctx context.Context, draft *domain.ProductDraft) error {
+	data, err := json.Marshal(productDraftData{
+		Product:     draft.Product,
+		Attributes:  draft.Attributes,
+		CategoryIDs: draft.CategoryIDs,
+		Changes:     draft.Changes,
+	})
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode product draft")
+	}
+
+	query := `
+		INSERT INTO catalog_product_draft (
+			product_id, status, data, edited_by, reviewed_by, review_note, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (product_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			data = EXCLUDED.data,
+			edited_by = EXCLUDED.edited_by,
+			reviewed_by = EXCLUDED.reviewed_by,
+			review_note = EXCLUDED.review_note,
+			updated_at = EXCLUDED.updated_at`
+	err = r.db.Exec(ctx, query,
+		draft.ProductID, string(draft.Status), data, draft.EditedBy, draft.ReviewedBy, draft.ReviewNote,
+		draft.CreatedAt, draft.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save product draft")
+	}
+	return nil
+}
+
+// FindByProductID retrieves the draft of a product, or nil when it has none
+func (r *PostgresProductDraftRepository) FindByProductID(ctx context.Context, productID int64) (*domain.ProductDraft, error) {
+	query := `SELECT` + productDraftColumns + ` FROM catalog_product_draft WHERE product_id = $1`
+
+	draft, err := scanProductDraft(r.db.QueryRow(ctx, query, productID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find product draft")
+	}
+	return draft, nil
+}
+
+// FindAll lists drafts, most recently edited first, with the total count
+func (r *PostgresProductDraftRepository) FindAll(ctx context.Context, filter *domain.ProductDraftFilter) ([]*domain.ProductDraft, int64, error) {
+	where := " WHERE 1=1"
+	args := make([]interface{}, 0)
+	if filter != nil && filter.Status != "" {
+		args = append(args, string(filter.Status))
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM catalog_product_draft"+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count product drafts")
+	}
+
+	query := `SELECT` + productDraftColumns + ` FROM catalog_product_draft` + where + ` ORDER BY updated_at DESC, product_id DESC`
+	if filter != nil && filter.PageSize > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, filter.PageSize, (max(filter.Page, 1)-1)*filter.PageSize)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to find product drafts")
+	}
+	defer rows.Close()
+
+	drafts := make([]*domain.ProductDraft, 0)
+	for rows.Next() {
+		draft, err := scanProductDraft(rows)
+		if err != nil {
+			return nil, 0, errors.InternalWrap(err, "failed to scan product draft")
+		}
+		drafts = append(drafts, draft)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to iterate product drafts")
+	}
+	return drafts, total, nil
+}
+
+// Delete removes the draft of a product
+func (r *PostgresProductDraftRepository) Delete(ctx context.Context, productID int64) error {
+	if err := r.db.Exec(ctx, `DELETE FROM catalog_product_draft WHERE product_id = $1`, productID); err != nil {
+		return errors.InternalWrap(err, "failed to delete product draft")
+	}
+	return nil
+}
+
+func scanProductDraft(row pgx.Row) (*domain.ProductDraft, error) {
+	draft := &domain.ProductDraft{}
+	var status string
+	var raw []byte
+	err := row.Scan(
+		&draft.ProductID, &status, &raw, &draft.EditedBy, &draft.ReviewedBy, &draft.ReviewNote,
+		&draft.CreatedAt, &draft.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var data productDraftData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid data of product draft %d: %w", draft.ProductID, err)
+	}
+	draft.Status = domain.ProductDraftStatus(status)
+	draft.Product = data.Product
+	draft.Attributes = data.Attributes
+	draft.CategoryIDs = data.CategoryIDs
+	draft.Changes = data.Changes
+	if draft.Attributes == nil {
+		draft.Attributes = make(map[string]string)
+	}
+	if draft.Changes == nil {
+		draft.Changes = make(map[string]interface{})
+	}
+	return draft, nil
+}
@@ -0,0 +1,155 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresProductQualityRepository implements the ProductQualityRepository interface
+type PostgresProductQualityRepository struct {
+	db *database.DB
+}
+
+// NewPostgresProductQualityRepository creates a new PostgresProductQualityRepository
+func NewPostgresProductQualityRepository(db *database.DB) *PostgresProductQualityRepository {
+	return &PostgresProductQualityRepository{db: db}
+}
+
+const productQualitySelect = `
+	SELECT p.product_id, COALESCE(s.name, ''), p.publish_status,
+		COALESCE(p.meta_title, ''), COALESCE(p.meta_desc, ''),
+		COALESCE(s.description, ''), COALESCE(s.long_description, ''),
+		s.sku_id IS NOT NULL, COALESCE(s.retail_price, 0),
+		p.default_category_id IS NOT NULL OR EXISTS (
+			SELECT 1 FROM blc_category_product_xref x WHERE x.product_id = p.product_id
+		),
+		a.qty_available
+	FROM blc_product p
+	LEFT JOIN blc_sku s ON s.sku_id = p.default_sku_id
+	LEFT JOIN catalog_product_availability a ON a.product_id = p.product_id`
+
+// FindInputs loads the quality inputs of products, keyed by product ID
+func (r *PostgresProductQualityRepository) FindInputs(ctx context.Context, productIDs []int64) (map[int64]*domain.ProductQualityInput, error) {
+	inputs := make(map[int64]*domain.ProductQualityInput, len(productIDs))
+	if len(productIDs) == 0 {
+		return inputs, nil
+	}
+
+	found, err := r.query(ctx, productQualitySelect+` WHERE p.product_id = ANY($1)`, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, in := range found {
+		inputs[in.ProductID] = in
+	}
+	return inputs, nil
+}
+
+// FindAwaitingPublication loads the quality inputs of unarchived products that were never published
+func (r *PostgresProductQualityRepository) FindAwaitingPublication(ctx context.Context, limit int) ([]*domain.ProductQualityInput, error) {
+	query := productQualitySelect + `
+		WHERE p.archived = 'N' AND p.publish_status = 'DRAFT'
+		ORDER BY p.created_at, p.product_id
+		LIMIT $1`
+	return r.query(ctx, query, limit)
+}
+
+func (r *PostgresProductQualityRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ProductQualityInput, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to load product quality inputs")
+	}
+	defer rows.Close()
+
+	var inputs []*domain.ProductQualityInput
+	for rows.Next() {
+		in, err := scanProductQualityInput(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan product quality input")
+		}
+		inputs = append(inputs, in)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate product quality inputs")
+	}
+
+	if err := r.loadAttributes(ctx, inputs); err != nil {
+		return nil, err
+	}
+	return inputs, nil
+}
+
+// loadAttributes adds the attributes of the products and their default SKUs
+func (r *PostgresProductQualityRepository) loadAttributes(ctx context.Context, inputs []*domain.ProductQualityInput) error {
+	if len(inputs) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*domain.ProductQualityInput, len(inputs))
+	productIDs := make([]int64, len(inputs))
+	for i, in := range inputs {
+		byID[in.ProductID] = in
+		productIDs[i] = in.ProductID
+	}
+
+	// SKU attributes come first so product attributes of the same name override them
+	query := `
+		SELECT p.product_id, sa.name, sa.value, 0 AS precedence
+		FROM blc_product p
+		JOIN blc_sku_attribute sa ON sa.sku_id = p.default_sku_id
+		WHERE p.product_id = ANY($1) AND sa.value <> ''
+		UNION ALL
+		SELECT pa.product_id, pa.name, pa.value, 1 AS precedence
+		FROM blc_product_attribute pa
+		WHERE pa.product_id = ANY($1) AND COALESCE(pa.value, '') <> ''
+		ORDER BY precedence`
+	rows, err := r.db.Query(ctx, query, productIDs)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to load product quality attributes")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int64
+		var name, value string
+		var precedence int
+		if err := rows.Scan(&productID, &name, &value, &precedence); err != nil {
+			return errors.InternalWrap(err, "failed to scan product quality attribute")
+		}
+		if in := byID[productID]; in != nil {
+			in.Attributes[name] = value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to iterate product quality attributes")
+	}
+	return nil
+}
+
+func scanProductQualityInput(row pgx.Row) (*domain.ProductQualityInput, error) {
+	in := &domain.ProductQualityInput{Attributes: make(map[string]string)}
+	var status string
+	var qtyAvailable sql.NullInt32
+	err := row.Scan(
+		&in.ProductID, &in.Name, &status,
+		&in.MetaTitle, &in.MetaDescription,
+		&in.Description, &in.LongDescription,
+		&in.HasDefaultSKU, &in.RetailPrice,
+		&in.HasCategory,
+		&qtyAvailable,
+	)
+	if err != nil {
+		return nil, err
+	}
+	in.PublishStatus = domain.ProductPublishStatus(status)
+	if qtyAvailable.Valid {
+		qty := int(qtyAvailable.Int32)
+		in.QtyAvailable = &qty
+	}
+	return in, nil
+}
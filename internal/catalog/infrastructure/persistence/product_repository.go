@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -47,9 +49,10 @@ func (r *PostgresProductRepository) Create(ctx context.Context, product *domain.
 			product_id, archived, can_sell_without_options, canonical_url,
 			display_template, enable_default_sku_in_inventory, manufacture,
 			meta_desc, meta_title, model, override_generated_url,
-			url, url_key, default_category_id, default_sku_id
+			url, url_key, default_category_id, default_sku_id,
+			active_start_date, active_end_date, publish_status, published_at, published_by
 		) VALUES (
-			nextval('blc_product_seq'), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			nextval('blc_product_seq'), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
 		) RETURNING product_id`
 
 	archivedFlag := "N"
@@ -72,8 +75,16 @@ func (r *PostgresProductRepository) Create(ctx context.Context, product *domain.
 		product.URLKey,
 		product.DefaultCategoryID,
 		product.DefaultSkuID,
+		product.ActiveStartDate,
+		product.ActiveEndDate,
+		string(publishStatus(product)),
+		product.PublishedAt,
+		product.PublishedBy,
 	).Scan(&product.ID)
 
+	if database.IsUniqueViolation(err, "uq_blc_product_url_key") {
+		return errors.Conflict(fmt.Sprintf("URL key %q is already used by another product", product.URLKey))
+	}
 	if err != nil {
 		return errors.InternalWrap(err, "failed to create product")
 	}
@@ -111,8 +122,13 @@ func (r *PostgresProductRepository) Update(ctx context.Context, product *domain.
 			url = $11,
 			url_key = $12,
 			default_category_id = $13,
-			default_sku_id = $14
-		WHERE product_id = $15`
+			default_sku_id = $14,
+			active_start_date = $15,
+			active_end_date = $16,
+			publish_status = $17,
+			published_at = $18,
+			published_by = $19
+		WHERE product_id = $20`
 
 	archivedFlag := "N"
 	if product.Archived {
@@ -134,9 +150,17 @@ func (r *PostgresProductRepository) Update(ctx context.Context, product *domain.
 		product.URLKey,
 		product.DefaultCategoryID,
 		product.DefaultSkuID,
+		product.ActiveStartDate,
+		product.ActiveEndDate,
+		string(publishStatus(product)),
+		product.PublishedAt,
+		product.PublishedBy,
 		product.ID,
 	)
 
+	if database.IsUniqueViolation(err, "uq_blc_product_url_key") {
+		return errors.Conflict(fmt.Sprintf("URL key %q is already used by another product", product.URLKey))
+	}
 	if err != nil {
 		return errors.InternalWrap(err, "failed to update product")
 	}
@@ -176,13 +200,17 @@ func (r *PostgresProductRepository) FindByID(ctx context.Context, id int64) (*do
 			product_id, archived, can_sell_without_options, canonical_url,
 			display_template, enable_default_sku_in_inventory, manufacture,
 			meta_desc, meta_title, model, override_generated_url,
-			url, url_key, default_category_id, default_sku_id
+			url, url_key, default_category_id, default_sku_id,
+			active_start_date, active_end_date, publish_status, published_at, published_by,
+			COALESCE(pa.in_stock, TRUE)
 		FROM blc_product
+		LEFT JOIN catalog_product_availability pa USING (product_id)
 		WHERE product_id = $1`
 
 	product := &domain.Product{}
-	var archivedFlag string
+	var archivedFlag, status string
 	var defaultCategoryID, defaultSKUID sql.NullInt64
+	var activeStartDate, activeEndDate sql.NullTime
 
 	// Usamos r.db.Pool() directamente ya que es una lectura simple
 	err := r.db.QueryRow(ctx, query, id).Scan(
@@ -201,6 +229,12 @@ func (r *PostgresProductRepository) FindByID(ctx context.Context, id int64) (*do
 		&product.URLKey,
 		&defaultCategoryID,
 		&defaultSKUID,
+		&activeStartDate,
+		&activeEndDate,
+		&status,
+		&product.PublishedAt,
+		&product.PublishedBy,
+		&product.InStock,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -211,12 +245,19 @@ func (r *PostgresProductRepository) FindByID(ctx context.Context, id int64) (*do
 	}
 
 	product.Archived = archivedFlag == "Y"
+	product.PublishStatus = domain.ProductPublishStatus(status)
 	if defaultCategoryID.Valid {
 		product.DefaultCategoryID = &defaultCategoryID.Int64
 	}
 	if defaultSKUID.Valid {
 		product.DefaultSkuID = &defaultSKUID.Int64
 	}
+	if activeStartDate.Valid {
+		product.ActiveStartDate = &activeStartDate.Time
+	}
+	if activeEndDate.Valid {
+		product.ActiveEndDate = &activeEndDate.Time
+	}
 
 	return product, nil
 }
@@ -263,20 +304,36 @@ func (r *PostgresProductRepository) FindByURLKey(ctx context.Context, urlKey str
 
 // FindAll retrieves all products with pagination (Optimized for N+1)
 func (r *PostgresProductRepository) FindAll(ctx context.Context, filter *domain.ProductFilter) ([]*domain.Product, int64, error) {
-	whereClause := ""
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
 	if !filter.IncludeArchived {
-		whereClause = "WHERE archived = 'N'"
+		conditions = append(conditions, "archived = 'N'")
+	}
+	if filter.ActiveOnly {
+		args = append(args, activeAt(filter))
+		conditions = append(conditions, activeWindowCondition("", len(args)))
+	}
+	if filter.OutOfStock == domain.OutOfStockHide {
+		conditions = append(conditions, inStockExpr)
+	}
+	if filter.PublishStatus != "" {
+		args = append(args, string(filter.PublishStatus))
+		conditions = append(conditions, fmt.Sprintf("publish_status = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
 	// 1. Contar total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM blc_product %s", whereClause)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM blc_product LEFT JOIN catalog_product_availability pa USING (product_id) %s", whereClause)
 	var total int64
-	if err := r.db.QueryRow(ctx, countQuery).Scan(&total); err != nil {
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, 0, errors.InternalWrap(err, "failed to count products")
 	}
 
 	// 2. Obtener productos (solo datos base)
-	orderByClause := r.buildOrderByClause(filter.SortBy, filter.SortOrder)
+	orderByClause := applyStockOrdering(r.buildOrderByClause(filter.SortBy, filter.SortOrder), filter)
 	offset := (filter.Page - 1) * filter.PageSize
 
 	query := fmt.Sprintf(`
@@ -284,16 +341,21 @@ func (r *PostgresProductRepository) FindAll(ctx context.Context, filter *domain.
 			product_id, archived, can_sell_without_options, canonical_url,
 			display_template, enable_default_sku_in_inventory, manufacture,
 			meta_desc, meta_title, model, override_generated_url,
-			url, url_key, default_category_id, default_sku_id
+			url, url_key, default_category_id, default_sku_id,
+			active_start_date, active_end_date, publish_status, published_at, published_by,
+			COALESCE(pa.in_stock, TRUE)
 		FROM blc_product
+		LEFT JOIN catalog_product_availability pa USING (product_id)
 		%s
 		%s
-		LIMIT $1 OFFSET $2`,
+		LIMIT $%d OFFSET $%d`,
 		whereClause,
 		orderByClause,
+		len(args)+1,
+		len(args)+2,
 	)
 
-	rows, err := r.db.Query(ctx, query, filter.PageSize, offset)
+	rows, err := r.db.Query(ctx, query, append(args, filter.PageSize, offset)...)
 	if err != nil {
 		return nil, 0, errors.InternalWrap(err, "failed to list products")
 	}
@@ -310,40 +372,72 @@ func (r *PostgresProductRepository) FindAll(ctx context.Context, filter *domain.
 // FindByCategoryID retrieves products by category ID (Optimized for N+1)
 func (r *PostgresProductRepository) FindByCategoryID(ctx context.Context, categoryID int64, filter *domain.ProductFilter) ([]*domain.Product, int64, error) {
 	whereClause := "WHERE xref.category_id = $1"
+	args := []interface{}{categoryID}
 	if !filter.IncludeArchived {
 		whereClause += " AND p.archived = 'N'"
 	}
+	if filter.ActiveOnly {
+		args = append(args, activeAt(filter))
+		whereClause += " AND " + activeWindowCondition("p.", len(args))
+	}
+	if filter.OutOfStock == domain.OutOfStockHide {
+		whereClause += " AND " + inStockExpr
+	}
+	if filter.PublishStatus != "" {
+		args = append(args, string(filter.PublishStatus))
+		whereClause += fmt.Sprintf(" AND p.publish_status = $%d", len(args))
+	}
 
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(DISTINCT p.product_id)
 		FROM blc_product p
 		INNER JOIN blc_category_product_xref xref ON p.product_id = xref.product_id
+		LEFT JOIN catalog_product_availability pa ON pa.product_id = p.product_id
 		%s`, whereClause)
 
 	var total int64
-	if err := r.db.QueryRow(ctx, countQuery, categoryID).Scan(&total); err != nil {
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, 0, errors.InternalWrap(err, "failed to count products by category")
 	}
 
 	orderByClause := r.buildOrderByClause(filter.SortBy, filter.SortOrder)
+	if filter.Merchandising != nil && filter.Merchandising.SortRule != domain.MerchandisingSortDefault {
+		var sortExpr string
+		sortExpr, args = buildMerchandisingSortExpression(filter.Merchandising, args)
+		orderByClause = fmt.Sprintf("ORDER BY %s, p.product_id DESC", sortExpr)
+	}
+	orderByClause = applyStockOrdering(orderByClause, filter)
+	if filter.Merchandising != nil && len(filter.Merchandising.PinnedProductIDs) > 0 {
+		// Pinned products come first in their configured order
+		args = append(args, filter.Merchandising.PinnedProductIDs)
+		orderByClause = strings.Replace(orderByClause, "ORDER BY ",
+			fmt.Sprintf("ORDER BY array_position($%d::bigint[], p.product_id) NULLS LAST, ", len(args)), 1)
+	}
 	offset := (filter.Page - 1) * filter.PageSize
 
+	// A product is linked to a category at most once, so no DISTINCT is needed
+	// and the ORDER BY may use columns outside the select list
 	query := fmt.Sprintf(`
-		SELECT DISTINCT
+		SELECT
 			p.product_id, p.archived, p.can_sell_without_options, p.canonical_url,
 			p.display_template, p.enable_default_sku_in_inventory, p.manufacture,
 			p.meta_desc, p.meta_title, p.model, p.override_generated_url,
-			p.url, p.url_key, p.default_category_id, p.default_sku_id
+			p.url, p.url_key, p.default_category_id, p.default_sku_id,
+			p.active_start_date, p.active_end_date, p.publish_status, p.published_at, p.published_by,
+			COALESCE(pa.in_stock, TRUE)
 		FROM blc_product p
 		INNER JOIN blc_category_product_xref xref ON p.product_id = xref.product_id
+		LEFT JOIN catalog_product_availability pa ON pa.product_id = p.product_id
 		%s
 		%s
-		LIMIT $2 OFFSET $3`,
+		LIMIT $%d OFFSET $%d`,
 		whereClause,
 		orderByClause,
+		len(args)+1,
+		len(args)+2,
 	)
 
-	rows, err := r.db.Query(ctx, query, categoryID, filter.PageSize, offset)
+	rows, err := r.db.Query(ctx, query, append(args, filter.PageSize, offset)...)
 	if err != nil {
 		return nil, 0, errors.InternalWrap(err, "failed to list products by category")
 	}
@@ -359,44 +453,75 @@ func (r *PostgresProductRepository) FindByCategoryID(ctx context.Context, catego
 
 // Search searches products by query (Optimized and Secure)
 func (r *PostgresProductRepository) Search(ctx context.Context, queryTerm string, filter *domain.ProductFilter) ([]*domain.Product, int64, error) {
-	whereClause := `
-		WHERE (
-			model ILIKE $1 OR
-			manufacture ILIKE $1 OR
-			meta_title ILIKE $1 OR
-			meta_desc ILIKE $1
-		)`
+	criteria := filter.Search
+	if !criteria.HasTerms() {
+		criteria = &domain.SearchCriteria{TermGroups: [][]string{{queryTerm}}}
+	}
 
+	whereClause, args := buildSearchWhereClause(criteria)
 	if !filter.IncludeArchived {
 		whereClause += " AND archived = 'N'"
 	}
+	if filter.ActiveOnly {
+		args = append(args, activeAt(filter))
+		whereClause += " AND " + activeWindowCondition("", len(args))
+	}
+	if filter.OutOfStock == domain.OutOfStockHide {
+		whereClause += " AND " + inStockExpr
+	}
+	if filter.PublishStatus != "" {
+		args = append(args, string(filter.PublishStatus))
+		whereClause += fmt.Sprintf(" AND publish_status = $%d", len(args))
+	}
+	if filter.Badge != nil {
+		var condition string
+		condition, args = badgeCondition(filter.Badge, "blc_product.", args)
+		whereClause += " AND " + condition
+	}
 
-	searchTerm := "%" + queryTerm + "%"
-
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM blc_product %s", whereClause)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM blc_product LEFT JOIN catalog_product_availability pa USING (product_id) %s", whereClause)
 	var total int64
-	if err := r.db.QueryRow(ctx, countQuery, searchTerm).Scan(&total); err != nil {
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, 0, errors.InternalWrap(err, "failed to count search results")
 	}
 
 	orderByClause := r.buildOrderByClause(filter.SortBy, filter.SortOrder)
+	if filter.SortBy == "relevance" {
+		var relevance string
+		relevance, args = buildRelevanceExpression(criteria, args)
+		orderByClause = fmt.Sprintf("ORDER BY %s DESC, product_id DESC", relevance)
+	}
+	orderByClause = applyStockOrdering(orderByClause, filter)
+	if len(criteria.PinnedProductIDs) > 0 {
+		// Pinned products come first in their configured order
+		args = append(args, criteria.PinnedProductIDs)
+		orderByClause = strings.Replace(orderByClause, "ORDER BY ",
+			fmt.Sprintf("ORDER BY array_position($%d::bigint[], product_id) NULLS LAST, ", len(args)), 1)
+	}
+
 	offset := (filter.Page - 1) * filter.PageSize
+	args = append(args, filter.PageSize, offset)
 
 	searchQuery := fmt.Sprintf(`
 		SELECT
 			product_id, archived, can_sell_without_options, canonical_url,
 			display_template, enable_default_sku_in_inventory, manufacture,
 			meta_desc, meta_title, model, override_generated_url,
-			url, url_key, default_category_id, default_sku_id
+			url, url_key, default_category_id, default_sku_id,
+			active_start_date, active_end_date, publish_status, published_at, published_by,
+			COALESCE(pa.in_stock, TRUE)
 		FROM blc_product
+		LEFT JOIN catalog_product_availability pa USING (product_id)
 		%s
 		%s
-		LIMIT $2 OFFSET $3`,
+		LIMIT $%d OFFSET $%d`,
 		whereClause,
 		orderByClause,
+		len(args)-1,
+		len(args),
 	)
 
-	rows, err := r.db.Query(ctx, searchQuery, searchTerm, filter.PageSize, offset)
+	rows, err := r.db.Query(ctx, searchQuery, args...)
 	if err != nil {
 		return nil, 0, errors.InternalWrap(err, "failed to search products")
 	}
@@ -410,6 +535,129 @@ func (r *PostgresProductRepository) Search(ctx context.Context, queryTerm string
 	return products, total, nil
 }
 
+// publishStatus returns the publishing state stored for a product; products
+// built without one are saved as drafts
+func publishStatus(product *domain.Product) domain.ProductPublishStatus {
+	if product.PublishStatus == "" {
+		return domain.ProductStatusDraft
+	}
+	return product.PublishStatus
+}
+
+// inStockExpr reads the availability read model; products not yet in it count as in stock
+const inStockExpr = "COALESCE(pa.in_stock, TRUE)"
+
+// applyStockOrdering pushes out-of-stock products to the bottom when the policy asks for it
+func applyStockOrdering(orderByClause string, filter *domain.ProductFilter) string {
+	if filter.OutOfStock != domain.OutOfStockBottom {
+		return orderByClause
+	}
+	return strings.Replace(orderByClause, "ORDER BY ", "ORDER BY "+inStockExpr+" DESC, ", 1)
+}
+
+// buildMerchandisingSortExpression orders a category listing by a merchandising sort rule
+func buildMerchandisingSortExpression(criteria *domain.MerchandisingCriteria, args []interface{}) (string, []interface{}) {
+	switch criteria.SortRule {
+	case domain.MerchandisingSortManual:
+		return "xref.display_order ASC NULLS LAST", args
+	case domain.MerchandisingSortNewest:
+		return "p.created_at DESC", args
+	case domain.MerchandisingSortMargin:
+		return `(
+			SELECT MAX(COALESCE(NULLIF(s.sale_price, 0), s.retail_price) - COALESCE(s.cost, 0))
+			FROM blc_sku s
+			WHERE s.default_product_id = p.product_id AND COALESCE(s.available_flag, 'Y') <> 'N'
+		) DESC NULLS LAST`, args
+	case domain.MerchandisingSortBestSellers:
+		args = append(args, criteria.BestSellersSince)
+		return unitsOrderedExpr("p.product_id", len(args)) + " DESC", args
+	}
+	return "p.product_id DESC", args
+}
+
+// unitsOrderedExpr sums the units of a product ordered since the time bound to
+// $sinceArg, leaving out cancelled and refunded orders
+func unitsOrderedExpr(productColumn string, sinceArg int) string {
+	return fmt.Sprintf(`COALESCE((
+			SELECT SUM(oi.quantity)
+			FROM blc_order_item oi
+			INNER JOIN blc_order o ON o.order_id = oi.order_id
+			INNER JOIN blc_sku s ON s.sku_id = oi.sku_id
+			WHERE s.default_product_id = %s
+				AND o.submit_date >= $%d
+				AND o.order_status NOT IN ('CANCELLED', 'REFUNDED')
+		), 0)`, productColumn, sinceArg)
+}
+
+// activeAt returns the time active windows are evaluated at
+func activeAt(filter *domain.ProductFilter) time.Time {
+	if filter.ActiveAt != nil {
+		return *filter.ActiveAt
+	}
+	return time.Now()
+}
+
+// activeWindowCondition matches products whose active window contains the time bound to $argIndex
+func activeWindowCondition(alias string, argIndex int) string {
+	return fmt.Sprintf("(%[1]sactive_start_date IS NULL OR %[1]sactive_start_date <= $%[2]d) AND (%[1]sactive_end_date IS NULL OR %[1]sactive_end_date >= $%[2]d)", alias, argIndex)
+}
+
+// searchColumns are the product columns matched by Search
+var searchColumns = []string{"model", "manufacture", "meta_title", "meta_desc"}
+
+// buildSearchWhereClause builds the WHERE clause for analyzed criteria. Each term group
+// becomes an AND-ed condition of OR-ed ILIKE matches; pinned products always match.
+func buildSearchWhereClause(criteria *domain.SearchCriteria) (string, []interface{}) {
+	args := make([]interface{}, 0)
+	groups := make([]string, 0, len(criteria.TermGroups))
+
+	for _, alternatives := range criteria.TermGroups {
+		args = append(args, likePatterns(alternatives))
+
+		matches := make([]string, len(searchColumns))
+		for i, column := range searchColumns {
+			matches[i] = fmt.Sprintf("%s ILIKE ANY($%d::text[])", column, len(args))
+		}
+		groups = append(groups, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	condition := strings.Join(groups, " AND ")
+	if len(criteria.PinnedProductIDs) > 0 {
+		args = append(args, criteria.PinnedProductIDs)
+		condition = fmt.Sprintf("(%s) OR product_id = ANY($%d::bigint[])", condition, len(args))
+	}
+
+	return "WHERE (" + condition + ")", args
+}
+
+// buildRelevanceExpression scores a product by the boosted sum of the fields matching any term
+func buildRelevanceExpression(criteria *domain.SearchCriteria, args []interface{}) (string, []interface{}) {
+	allTerms := make([]string, 0)
+	for _, alternatives := range criteria.TermGroups {
+		allTerms = append(allTerms, alternatives...)
+	}
+	args = append(args, likePatterns(allTerms))
+
+	scores := make([]string, len(searchColumns))
+	for i, column := range searchColumns {
+		boost, ok := criteria.FieldBoosts[column]
+		if !ok {
+			boost = 1
+		}
+		scores[i] = fmt.Sprintf("(CASE WHEN %s ILIKE ANY($%d::text[]) THEN %f ELSE 0 END)", column, len(args), boost)
+	}
+
+	return "(" + strings.Join(scores, " + ") + ")", args
+}
+
+func likePatterns(terms []string) []string {
+	patterns := make([]string, len(terms))
+	for i, term := range terms {
+		patterns[i] = "%" + term + "%"
+	}
+	return patterns
+}
+
 func (r *PostgresProductRepository) AddToCategory(ctx context.Context, productID, categoryID int64) error {
 	query := `
 		INSERT INTO blc_category_product_xref (category_product_id, product_id, category_id)
@@ -442,8 +690,9 @@ func (r *PostgresProductRepository) scanProducts(rows pgx.Rows) ([]*domain.Produ
 
 	for rows.Next() {
 		product := &domain.Product{}
-		var archivedFlag string
+		var archivedFlag, status string
 		var defaultCategoryID, defaultSKUID sql.NullInt64
+		var activeStartDate, activeEndDate sql.NullTime
 
 		err := rows.Scan(
 			&product.ID,
@@ -461,18 +710,31 @@ func (r *PostgresProductRepository) scanProducts(rows pgx.Rows) ([]*domain.Produ
 			&product.URLKey,
 			&defaultCategoryID,
 			&defaultSKUID,
+			&activeStartDate,
+			&activeEndDate,
+			&status,
+			&product.PublishedAt,
+			&product.PublishedBy,
+			&product.InStock,
 		)
 		if err != nil {
 			return nil, nil, errors.InternalWrap(err, "failed to scan product")
 		}
 
 		product.Archived = archivedFlag == "Y"
+		product.PublishStatus = domain.ProductPublishStatus(status)
 		if defaultCategoryID.Valid {
 			product.DefaultCategoryID = &defaultCategoryID.Int64
 		}
 		if defaultSKUID.Valid {
 			product.DefaultSkuID = &defaultSKUID.Int64
 		}
+		if activeStartDate.Valid {
+			product.ActiveStartDate = &activeStartDate.Time
+		}
+		if activeEndDate.Valid {
+			product.ActiveEndDate = &activeEndDate.Time
+		}
 
 		products = append(products, product)
 		ids = append(ids, product.ID)
@@ -0,0 +1,310 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresSaleEventRepository implements the SaleEventRepository interface
+type PostgresSaleEventRepository struct {
+	db *database.DB
+}
+
+// NewPostgresSaleEventRepository creates a new PostgresSaleEventRepository
+func NewPostgresSaleEventRepository(db *database.DB) *PostgresSaleEventRepository {
+	return &PostgresSaleEventRepository{db: db}
+}
+
+const saleEventColumns = `
+	e.sale_event_id, e.name, COALESCE(e.description, ''), e.starts_at, e.ends_at, e.status, e.offer_id,
+	COALESCE(e.created_by, ''), e.started_at, e.ended_at, e.created_at, e.updated_at`
+
+// Save creates or updates a sale event, replacing its items
+func (r *PostgresSaleEventRepository) Save(ctx context.Context, event *domain.SaleEvent) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if event.ID == 0 {
+			query := `
+				INSERT INTO catalog_sale_event (
+					name, description, starts_at, ends_at, status, offer_id, created_by,
+					started_at, ended_at, created_at, updated_at
+				) VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11)
+				RETURNING sale_event_id`
+			err := tx.QueryRow(ctx, query,
+				event.Name, event.Description, event.StartsAt, event.EndsAt, string(event.Status), event.OfferID,
+				event.CreatedBy, event.StartedAt, event.EndedAt, event.CreatedAt, event.UpdatedAt,
+			).Scan(&event.ID)
+			if err != nil {
+				return errors.InternalWrap(err, "failed to create sale event")
+			}
+		} else {
+			query := `
+				UPDATE catalog_sale_event SET
+					name = $2, description = NULLIF($3, ''), starts_at = $4, ends_at = $5, status = $6,
+					offer_id = $7, started_at = $8, ended_at = $9, updated_at = $10
+				WHERE sale_event_id = $1`
+			result, err := tx.Exec(ctx, query,
+				event.ID, event.Name, event.Description, event.StartsAt, event.EndsAt, string(event.Status),
+				event.OfferID, event.StartedAt, event.EndedAt, event.UpdatedAt,
+			)
+			if err != nil {
+				return errors.InternalWrap(err, "failed to update sale event")
+			}
+			if result.RowsAffected() == 0 {
+				return errors.NotFound("sale event")
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM catalog_sale_event_item WHERE sale_event_id = $1`, event.ID); err != nil {
+				return errors.InternalWrap(err, "failed to replace sale event items")
+			}
+		}
+
+		for _, item := range event.Items {
+			query := `
+				INSERT INTO catalog_sale_event_item (
+					sale_event_id, sku_id, sale_price, retail_price, previous_sale_price, applied
+				) VALUES ($1, $2, $3, $4, $5, $6)`
+			if _, err := tx.Exec(ctx, query,
+				event.ID, item.SKUID, item.SalePrice, item.RetailPrice, item.PreviousSalePrice, item.Applied,
+			); err != nil {
+				return errors.InternalWrap(err, "failed to save sale event item")
+			}
+		}
+		return nil
+	})
+}
+
+// Transition moves an event from one status to another if it still has the first
+func (r *PostgresSaleEventRepository) Transition(ctx context.Context, id int64, from, to domain.SaleEventStatus) (bool, error) {
+	query := `UPDATE catalog_sale_event SET status = $3, updated_at = NOW() WHERE sale_event_id = $1 AND status = $2`
+	result, err := r.db.Pool().Exec(ctx, query, id, string(from), string(to))
+	if err != nil {
+		return false, errors.InternalWrap(err, "failed to update sale event status")
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// FindByID retrieves a sale event with its items
+func (r *PostgresSaleEventRepository) FindByID(ctx context.Context, id int64) (*domain.SaleEvent, error) {
+	query := `SELECT` + saleEventColumns + ` FROM catalog_sale_event e WHERE e.sale_event_id = $1`
+
+	event, err := scanSaleEvent(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("sale event")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find sale event")
+	}
+	if err := r.loadItems(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// FindAll retrieves sale events with their items, latest start first
+func (r *PostgresSaleEventRepository) FindAll(ctx context.Context, filter domain.SaleEventFilter) ([]*domain.SaleEvent, error) {
+	query := `SELECT` + saleEventColumns + `
+		FROM catalog_sale_event e
+		WHERE ($1 = '' OR e.status = $1)
+		ORDER BY e.starts_at DESC, e.sale_event_id DESC
+		LIMIT $2`
+	return r.query(ctx, query, string(filter.Status), filter.Limit)
+}
+
+// FindDue retrieves the scheduled events due to start and the active events due to end
+func (r *PostgresSaleEventRepository) FindDue(ctx context.Context, at time.Time) ([]*domain.SaleEvent, error) {
+	query := `SELECT` + saleEventColumns + `
+		FROM catalog_sale_event e
+		WHERE (e.status = 'SCHEDULED' AND e.starts_at <= $1) OR (e.status = 'ACTIVE' AND e.ends_at <= $1)
+		ORDER BY LEAST(e.starts_at, e.ends_at), e.sale_event_id`
+	return r.query(ctx, query, at)
+}
+
+// FindStorefront retrieves the active events and the scheduled events starting before a time
+func (r *PostgresSaleEventRepository) FindStorefront(ctx context.Context, startingBefore time.Time) ([]*domain.SaleEvent, error) {
+	query := `SELECT` + saleEventColumns + `
+		FROM catalog_sale_event e
+		WHERE e.status = 'ACTIVE' OR (e.status = 'SCHEDULED' AND e.starts_at < $1)
+		ORDER BY e.starts_at, e.sale_event_id`
+	return r.query(ctx, query, startingBefore)
+}
+
+// FindOverlapping lists the SKUs in another scheduled or active event overlapping a period
+func (r *PostgresSaleEventRepository) FindOverlapping(ctx context.Context, skuIDs []int64, startsAt, endsAt time.Time, excludeID int64) ([]int64, error) {
+	query := `
+		SELECT DISTINCT i.sku_id
+		FROM catalog_sale_event_item i
+		JOIN catalog_sale_event e ON e.sale_event_id = i.sale_event_id
+		WHERE i.sku_id = ANY($1)
+			AND e.status IN ('SCHEDULED', 'ACTIVE')
+			AND e.starts_at < $3 AND e.ends_at > $2
+			AND e.sale_event_id <> $4
+		ORDER BY i.sku_id`
+	rows, err := r.db.Query(ctx, query, skuIDs, startsAt, endsAt, excludeID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find overlapping sale events")
+	}
+	defer rows.Close()
+
+	overlapping := make([]int64, 0)
+	for rows.Next() {
+		var skuID int64
+		if err := rows.Scan(&skuID); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan overlapping SKU")
+		}
+		overlapping = append(overlapping, skuID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate overlapping SKUs")
+	}
+	return overlapping, nil
+}
+
+const saleEventDealQuery = `
+	SELECT e.sale_event_id, e.name, i.sku_id, s.default_product_id, i.sale_price, e.ends_at
+	FROM catalog_sale_event_item i
+	JOIN catalog_sale_event e ON e.sale_event_id = i.sale_event_id
+	JOIN blc_sku s ON s.sku_id = i.sku_id
+	WHERE e.status = 'ACTIVE'`
+
+// FindActiveDealsBySKUIDs retrieves the deals of active events on the given SKUs
+func (r *PostgresSaleEventRepository) FindActiveDealsBySKUIDs(ctx context.Context, skuIDs []int64) ([]*domain.SaleEventDeal, error) {
+	return r.queryDeals(ctx, saleEventDealQuery+` AND i.sku_id = ANY($1) ORDER BY e.ends_at, i.sku_id`, skuIDs)
+}
+
+// FindActiveDealsByProductIDs retrieves the deals of active events on the SKUs of the given products
+func (r *PostgresSaleEventRepository) FindActiveDealsByProductIDs(ctx context.Context, productIDs []int64) ([]*domain.SaleEventDeal, error) {
+	return r.queryDeals(ctx, saleEventDealQuery+` AND s.default_product_id = ANY($1) ORDER BY e.ends_at, i.sku_id`, productIDs)
+}
+
+// SumSales sums the sales of SKUs in orders submitted within a period; the
+// grand total row of the grouping sets counts orders with several SKUs once
+func (r *PostgresSaleEventRepository) SumSales(ctx context.Context, skuIDs []int64, from, to time.Time) (*domain.SaleEventSales, error) {
+	query := `
+		SELECT oi.sku_id, COUNT(DISTINCT o.order_id), COALESCE(SUM(oi.quantity), 0), COALESCE(SUM(oi.price * oi.quantity), 0)
+		FROM blc_order_item oi
+		JOIN blc_order o ON o.order_id = oi.order_id
+		WHERE oi.sku_id = ANY($1)
+			AND o.submit_date >= $2 AND o.submit_date < $3
+			AND COALESCE(o.is_preview, FALSE) = FALSE
+			AND o.order_status NOT IN ('CANCELLED', 'REFUNDED')
+		GROUP BY GROUPING SETS ((oi.sku_id), ())
+		ORDER BY oi.sku_id NULLS FIRST`
+	rows, err := r.db.Query(ctx, query, skuIDs, from, to)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to sum sale event sales")
+	}
+	defer rows.Close()
+
+	sales := &domain.SaleEventSales{SKUs: make([]*domain.SKUSales, 0)}
+	for rows.Next() {
+		var skuID *int64
+		s := &domain.SKUSales{}
+		if err := rows.Scan(&skuID, &s.Orders, &s.Units, &s.Revenue); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan sale event sales")
+		}
+		if skuID == nil {
+			sales.Orders, sales.Units, sales.Revenue = s.Orders, s.Units, s.Revenue
+			continue
+		}
+		s.SKUID = *skuID
+		sales.SKUs = append(sales.SKUs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate sale event sales")
+	}
+	return sales, nil
+}
+
+func (r *PostgresSaleEventRepository) queryDeals(ctx context.Context, query string, ids []int64) ([]*domain.SaleEventDeal, error) {
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find sale event deals")
+	}
+	defer rows.Close()
+
+	deals := make([]*domain.SaleEventDeal, 0)
+	for rows.Next() {
+		deal := &domain.SaleEventDeal{}
+		if err := rows.Scan(&deal.SaleEventID, &deal.Name, &deal.SKUID, &deal.ProductID, &deal.SalePrice, &deal.EndsAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan sale event deal")
+		}
+		deals = append(deals, deal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate sale event deals")
+	}
+	return deals, nil
+}
+
+func (r *PostgresSaleEventRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.SaleEvent, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find sale events")
+	}
+	defer rows.Close()
+
+	events := make([]*domain.SaleEvent, 0)
+	for rows.Next() {
+		event, err := scanSaleEvent(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan sale event")
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate sale events")
+	}
+	rows.Close()
+
+	for _, event := range events {
+		if err := r.loadItems(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+func (r *PostgresSaleEventRepository) loadItems(ctx context.Context, event *domain.SaleEvent) error {
+	query := `
+		SELECT sku_id, sale_price, retail_price, previous_sale_price, applied
+		FROM catalog_sale_event_item
+		WHERE sale_event_id = $1
+		ORDER BY sku_id`
+	rows, err := r.db.Query(ctx, query, event.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to find sale event items")
+	}
+	defer rows.Close()
+
+	event.Items = make([]*domain.SaleEventItem, 0)
+	for rows.Next() {
+		item := &domain.SaleEventItem{}
+		if err := rows.Scan(&item.SKUID, &item.SalePrice, &item.RetailPrice, &item.PreviousSalePrice, &item.Applied); err != nil {
+			return errors.InternalWrap(err, "failed to scan sale event item")
+		}
+		event.Items = append(event.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to iterate sale event items")
+	}
+	return nil
+}
+
+func scanSaleEvent(row pgx.Row) (*domain.SaleEvent, error) {
+	event := &domain.SaleEvent{}
+	var status string
+	err := row.Scan(
+		&event.ID, &event.Name, &event.Description, &event.StartsAt, &event.EndsAt, &status, &event.OfferID,
+		&event.CreatedBy, &event.StartedAt, &event.EndedAt, &event.CreatedAt, &event.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	event.Status = domain.SaleEventStatus(status)
+	return event, nil
+}
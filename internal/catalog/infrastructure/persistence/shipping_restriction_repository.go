@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresShippingRestrictionRepository implements the ShippingRestrictionRepository interface
+type PostgresShippingRestrictionRepository struct {
+	db *database.DB
+}
+
+// NewPostgresShippingRestrictionRepository creates a new PostgresShippingRestrictionRepository
+func NewPostgresShippingRestrictionRepository(db *database.DB) *PostgresShippingRestrictionRepository {
+	return &PostgresShippingRestrictionRepository{db: db}
+}
+
+const shippingRestrictionColumns = `
+	restriction_id, product_id, sku_id, blocked_countries, blocked_regions,
+	age_verification_required, minimum_age, reason, created_at, updated_at`
+
+// Save creates or updates a shipping restriction
+func (r *PostgresShippingRestrictionRepository) Save(ctx context.Context, restriction *domain.ShippingRestriction) error {
+	if restriction.ID == 0 {
+		query := `
+			INSERT INTO catalog_shipping_restriction (
+				product_id, sku_id, blocked_countries, blocked_regions,
+				age_verification_required, minimum_age, reason, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING restriction_id`
+		err := r.db.QueryRow(ctx, query,
+			restriction.ProductID, restriction.SKUID, restriction.BlockedCountries, restriction.BlockedRegions,
+			restriction.AgeVerificationRequired, restriction.MinimumAge, restriction.Reason,
+			restriction.CreatedAt, restriction.UpdatedAt,
+		).Scan(&restriction.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create shipping restriction")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE catalog_shipping_restriction SET
+			product_id = $2, sku_id = $3, blocked_countries = $4, blocked_regions = $5,
+			age_verification_required = $6, minimum_age = $7, reason = $8, updated_at = $9
+		WHERE restriction_id = $1`
+	result, err := r.db.Pool().Exec(ctx, query,
+		restriction.ID, restriction.ProductID, restriction.SKUID, restriction.BlockedCountries, restriction.BlockedRegions,
+		restriction.AgeVerificationRequired, restriction.MinimumAge, restriction.Reason, restriction.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update shipping restriction")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("shipping restriction")
+	}
+	return nil
+}
+
+// Delete removes a shipping restriction
+func (r *PostgresShippingRestrictionRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM catalog_shipping_restriction WHERE restriction_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete shipping restriction")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("shipping restriction")
+	}
+	return nil
+}
+
+// FindByID retrieves a shipping restriction by ID
+func (r *PostgresShippingRestrictionRepository) FindByID(ctx context.Context, id int64) (*domain.ShippingRestriction, error) {
+	query := `SELECT` + shippingRestrictionColumns + ` FROM catalog_shipping_restriction WHERE restriction_id = $1`
+
+	restriction, err := scanShippingRestriction(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("shipping restriction")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find shipping restriction")
+	}
+	return restriction, nil
+}
+
+// FindAll retrieves all shipping restrictions
+func (r *PostgresShippingRestrictionRepository) FindAll(ctx context.Context) ([]*domain.ShippingRestriction, error) {
+	query := `SELECT` + shippingRestrictionColumns + ` FROM catalog_shipping_restriction ORDER BY restriction_id`
+	return r.query(ctx, query)
+}
+
+// FindByProductID retrieves the restrictions of a product and of its SKUs
+func (r *PostgresShippingRestrictionRepository) FindByProductID(ctx context.Context, productID int64) ([]*domain.ShippingRestriction, error) {
+	query := `SELECT` + shippingRestrictionColumns + `
+		FROM catalog_shipping_restriction
+		WHERE product_id = $1
+		   OR sku_id IN (SELECT sku_id FROM blc_sku WHERE default_product_id = $1 OR addl_product_id = $1)
+		ORDER BY restriction_id`
+	return r.query(ctx, query, productID)
+}
+
+// FindApplicable retrieves the restrictions targeting any of the products or SKUs
+func (r *PostgresShippingRestrictionRepository) FindApplicable(ctx context.Context, productIDs, skuIDs []int64) ([]*domain.ShippingRestriction, error) {
+	query := `SELECT` + shippingRestrictionColumns + `
+		FROM catalog_shipping_restriction
+		WHERE product_id = ANY($1) OR sku_id = ANY($2)
+		ORDER BY restriction_id`
+	return r.query(ctx, query, productIDs, skuIDs)
+}
+
+func (r *PostgresShippingRestrictionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ShippingRestriction, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list shipping restrictions")
+	}
+	defer rows.Close()
+
+	restrictions := make([]*domain.ShippingRestriction, 0)
+	for rows.Next() {
+		restriction, err := scanShippingRestriction(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan shipping restriction")
+		}
+		restrictions = append(restrictions, restriction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate shipping restrictions")
+	}
+	return restrictions, nil
+}
+
+func scanShippingRestriction(row pgx.Row) (*domain.ShippingRestriction, error) {
+	restriction := &domain.ShippingRestriction{}
+	err := row.Scan(
+		&restriction.ID, &restriction.ProductID, &restriction.SKUID,
+		&restriction.BlockedCountries, &restriction.BlockedRegions,
+		&restriction.AgeVerificationRequired, &restriction.MinimumAge, &restriction.Reason,
+		&restriction.CreatedAt, &restriction.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return restriction, nil
+}
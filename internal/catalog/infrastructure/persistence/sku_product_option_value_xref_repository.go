@@ -2,9 +2,13 @@ package persistence
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/qhato/ecommerce/internal/catalog/domain"
 	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
 )
 
 // PostgresSkuProductOptionValueXrefRepository implements the SkuProductOptionValueXrefRepository interface
@@ -17,50 +21,158 @@ func NewPostgresSkuProductOptionValueXrefRepository(db *database.DB) *PostgresSk
 	return &PostgresSkuProductOptionValueXrefRepository{db: db}
 }
 
+const skuOptionValueXrefColumns = `sku_option_value_xref_id, sku_id, product_option_value_id, created_at, updated_at`
+
 // Save stores a new SKU product option value cross-reference.
 func (r *PostgresSkuProductOptionValueXrefRepository) Save(ctx context.Context, xref *domain.SkuProductOptionValueXref) error {
-	// TODO: Implement actual persistence logic
+	return r.SaveAll(ctx, []*domain.SkuProductOptionValueXref{xref})
+}
+
+// SaveAll upserts SKU product option value cross-references with one multi-row statement.
+func (r *PostgresSkuProductOptionValueXrefRepository) SaveAll(ctx context.Context, xrefs []*domain.SkuProductOptionValueXref) error {
+	type pair struct{ skuID, productOptionValueID int64 }
+	byPair := make(map[pair][]*domain.SkuProductOptionValueXref, len(xrefs))
+	var skuIDs, productOptionValueIDs []int64
+	var createdAts, updatedAts []time.Time
+	for _, xref := range xrefs {
+		key := pair{xref.SKUID, xref.ProductOptionValueID}
+		if _, ok := byPair[key]; !ok {
+			skuIDs = append(skuIDs, xref.SKUID)
+			productOptionValueIDs = append(productOptionValueIDs, xref.ProductOptionValueID)
+			createdAts = append(createdAts, xref.CreatedAt)
+			updatedAts = append(updatedAts, xref.UpdatedAt)
+		}
+		byPair[key] = append(byPair[key], xref)
+	}
+	if len(skuIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO blc_sku_option_value_xref (sku_id, product_option_value_id, created_at, updated_at)
+		SELECT * FROM unnest($1::bigint[], $2::bigint[], $3::timestamptz[], $4::timestamptz[])
+		ON CONFLICT (sku_id, product_option_value_id) DO UPDATE SET updated_at = EXCLUDED.updated_at
+		RETURNING sku_option_value_xref_id, sku_id, product_option_value_id`
+
+	rows, err := r.db.Query(ctx, query, skuIDs, productOptionValueIDs, createdAts, updatedAts)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save SKU option value xrefs")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var key pair
+		if err := rows.Scan(&id, &key.skuID, &key.productOptionValueID); err != nil {
+			return errors.InternalWrap(err, "failed to scan SKU option value xref")
+		}
+		for _, xref := range byPair[key] {
+			xref.ID = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to save SKU option value xrefs")
+	}
 	return nil
 }
 
 // FindByID retrieves a SKU product option value cross-reference by its unique identifier.
 func (r *PostgresSkuProductOptionValueXrefRepository) FindByID(ctx context.Context, id int64) (*domain.SkuProductOptionValueXref, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + skuOptionValueXrefColumns + ` FROM blc_sku_option_value_xref WHERE sku_option_value_xref_id = $1`
+	xref, err := scanSkuOptionValueXref(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find SKU option value xref")
+	}
+	return xref, nil
 }
 
 // FindBySKUID retrieves all SKU product option value cross-references for a given SKU ID.
 func (r *PostgresSkuProductOptionValueXrefRepository) FindBySKUID(ctx context.Context, skuID int64) ([]*domain.SkuProductOptionValueXref, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + skuOptionValueXrefColumns + `
+		FROM blc_sku_option_value_xref
+		WHERE sku_id = $1
+		ORDER BY sku_option_value_xref_id`
+	return r.findAll(ctx, query, skuID)
 }
 
 // FindByProductOptionValueID retrieves all SKU product option value cross-references for a given product option value ID.
 func (r *PostgresSkuProductOptionValueXrefRepository) FindByProductOptionValueID(ctx context.Context, productOptionValueID int64) ([]*domain.SkuProductOptionValueXref, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + skuOptionValueXrefColumns + `
+		FROM blc_sku_option_value_xref
+		WHERE product_option_value_id = $1
+		ORDER BY sku_option_value_xref_id`
+	return r.findAll(ctx, query, productOptionValueID)
 }
 
 // Delete removes a SKU product option value cross-reference by its unique identifier.
 func (r *PostgresSkuProductOptionValueXrefRepository) Delete(ctx context.Context, id int64) error {
-	// TODO: Implement actual persistence logic
+	if err := r.db.Exec(ctx, `DELETE FROM blc_sku_option_value_xref WHERE sku_option_value_xref_id = $1`, id); err != nil {
+		return errors.InternalWrap(err, "failed to delete SKU option value xref")
+	}
 	return nil
 }
 
 // DeleteBySKUID removes all SKU product option value cross-references for a given SKU ID.
 func (r *PostgresSkuProductOptionValueXrefRepository) DeleteBySKUID(ctx context.Context, skuID int64) error {
-	// TODO: Implement actual persistence logic
+	if err := r.db.Exec(ctx, `DELETE FROM blc_sku_option_value_xref WHERE sku_id = $1`, skuID); err != nil {
+		return errors.InternalWrap(err, "failed to delete SKU option value xrefs")
+	}
 	return nil
 }
 
 // DeleteByProductOptionValueID removes all SKU product option value cross-references for a given product option value ID.
 func (r *PostgresSkuProductOptionValueXrefRepository) DeleteByProductOptionValueID(ctx context.Context, productOptionValueID int64) error {
-	// TODO: Implement actual persistence logic
+	if err := r.db.Exec(ctx, `DELETE FROM blc_sku_option_value_xref WHERE product_option_value_id = $1`, productOptionValueID); err != nil {
+		return errors.InternalWrap(err, "failed to delete SKU option value xrefs")
+	}
 	return nil
 }
 
 // RemoveSkuProductOptionValueXref removes a specific SKU product option value cross-reference by SKU ID and product option value ID.
 func (r *PostgresSkuProductOptionValueXrefRepository) RemoveSkuProductOptionValueXref(ctx context.Context, skuID, productOptionValueID int64) error {
-	// TODO: Implement actual persistence logic
+	return r.RemoveSkuProductOptionValueXrefs(ctx, skuID, []int64{productOptionValueID})
+}
+
+// RemoveSkuProductOptionValueXrefs removes the cross-references of a SKU to many product option values with one statement.
+func (r *PostgresSkuProductOptionValueXrefRepository) RemoveSkuProductOptionValueXrefs(ctx context.Context, skuID int64, productOptionValueIDs []int64) error {
+	if len(productOptionValueIDs) == 0 {
+		return nil
+	}
+	query := `DELETE FROM blc_sku_option_value_xref WHERE sku_id = $1 AND product_option_value_id = ANY($2::bigint[])`
+	if err := r.db.Exec(ctx, query, skuID, productOptionValueIDs); err != nil {
+		return errors.InternalWrap(err, "failed to remove SKU option value xrefs")
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *PostgresSkuProductOptionValueXrefRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*domain.SkuProductOptionValueXref, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list SKU option value xrefs")
+	}
+	defer rows.Close()
+
+	var xrefs []*domain.SkuProductOptionValueXref
+	for rows.Next() {
+		xref, err := scanSkuOptionValueXref(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan SKU option value xref")
+		}
+		xrefs = append(xrefs, xref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to list SKU option value xrefs")
+	}
+	return xrefs, nil
+}
+
+func scanSkuOptionValueXref(row pgx.Row) (*domain.SkuProductOptionValueXref, error) {
+	xref := &domain.SkuProductOptionValueXref{}
+	if err := row.Scan(&xref.ID, &xref.SKUID, &xref.ProductOptionValueID, &xref.CreatedAt, &xref.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return xref, nil
+}
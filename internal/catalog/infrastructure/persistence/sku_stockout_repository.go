@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresSKUStockoutRepository implements the SKUStockoutRepository interface
+type PostgresSKUStockoutRepository struct {
+	db *database.DB
+}
+
+// NewPostgresSKUStockoutRepository creates a new PostgresSKUStockoutRepository
+func NewPostgresSKUStockoutRepository(db *database.DB) *PostgresSKUStockoutRepository {
+	return &PostgresSKUStockoutRepository{db: db}
+}
+
+// Save records a stockout, replacing an earlier one of the SKU
+func (r *PostgresSKUStockoutRepository) Save(ctx context.Context, stockout *domain.SKUStockout) error {
+	query := `
+		INSERT INTO catalog_sku_stockout (sku_id, available_to_promise, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (sku_id) DO UPDATE SET
+			available_to_promise = EXCLUDED.available_to_promise,
+			created_at = EXCLUDED.created_at`
+	if _, err := r.db.Pool().Exec(ctx, query, stockout.SKUID, stockout.AvailableToPromise, stockout.CreatedAt); err != nil {
+		return errors.InternalWrap(err, "failed to save SKU stockout")
+	}
+	return nil
+}
+
+// FindBySKUIDs returns the stockouts of the given SKUs, by SKU ID
+func (r *PostgresSKUStockoutRepository) FindBySKUIDs(ctx context.Context, skuIDs []int64) (map[int64]*domain.SKUStockout, error) {
+	stockouts := make(map[int64]*domain.SKUStockout, len(skuIDs))
+	if len(skuIDs) == 0 {
+		return stockouts, nil
+	}
+
+	query := `
+		SELECT sku_id, available_to_promise, created_at
+		FROM catalog_sku_stockout
+		WHERE sku_id = ANY($1)`
+	found, err := r.query(ctx, query, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, stockout := range found {
+		stockouts[stockout.SKUID] = stockout
+	}
+	return stockouts, nil
+}
+
+// FindAll lists the stockouts, oldest first
+func (r *PostgresSKUStockoutRepository) FindAll(ctx context.Context, limit int) ([]*domain.SKUStockout, error) {
+	query := `
+		SELECT sku_id, available_to_promise, created_at
+		FROM catalog_sku_stockout
+		ORDER BY created_at, sku_id
+		LIMIT $1`
+	return r.query(ctx, query, limit)
+}
+
+// Delete forgets the stockout of a SKU
+func (r *PostgresSKUStockoutRepository) Delete(ctx context.Context, skuID int64) error {
+	if _, err := r.db.Pool().Exec(ctx, `DELETE FROM catalog_sku_stockout WHERE sku_id = $1`, skuID); err != nil {
+		return errors.InternalWrap(err, "failed to delete SKU stockout")
+	}
+	return nil
+}
+
+func (r *PostgresSKUStockoutRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.SKUStockout, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find SKU stockouts")
+	}
+	defer rows.Close()
+
+	stockouts := make([]*domain.SKUStockout, 0)
+	for rows.Next() {
+		stockout := &domain.SKUStockout{}
+		if err := rows.Scan(&stockout.SKUID, &stockout.AvailableToPromise, &stockout.CreatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan SKU stockout")
+		}
+		stockouts = append(stockouts, stockout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate SKU stockouts")
+	}
+	return stockouts, nil
+}
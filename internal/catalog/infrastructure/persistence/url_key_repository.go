@@ -0,0 +1,177 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// urlKeyTables maps entity types to the table and ID column holding their default keys
+var urlKeyTables = map[domain.URLKeyEntity]struct{ table, idColumn string }{
+	domain.URLKeyEntityProduct:  {"blc_product", "product_id"},
+	domain.URLKeyEntityCategory: {"blc_category", "category_id"},
+}
+
+// PostgresURLKeyRepository implements the URLKeyRepository interface
+type PostgresURLKeyRepository struct {
+	db *database.DB
+}
+
+// NewPostgresURLKeyRepository creates a new PostgresURLKeyRepository
+func NewPostgresURLKeyRepository(db *database.DB) *PostgresURLKeyRepository {
+	return &PostgresURLKeyRepository{db: db}
+}
+
+// Save stores a localized key. Saves of the same key are serialized with an
+// advisory lock so two entities cannot claim it in overlapping scopes at once.
+func (r *PostgresURLKeyRepository) Save(ctx context.Context, key *domain.LocalizedURLKey) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('catalog_url_key:' || $1 || ':' || $2))`,
+			string(key.Entity), key.URLKey); err != nil {
+			return errors.InternalWrap(err, "failed to lock URL key")
+		}
+
+		ownerID, err := r.findOwner(ctx, tx, key.Entity, key.Scope, key.URLKey, key.EntityID)
+		if err != nil {
+			return err
+		}
+		if ownerID != 0 {
+			return errors.Conflict(fmt.Sprintf("URL key %q is already used by %s %d", key.URLKey, key.Entity, ownerID))
+		}
+
+		query := `
+			INSERT INTO catalog_url_key (entity_type, entity_id, site_id, locale, url_key, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (entity_type, entity_id, site_id, locale)
+			DO UPDATE SET url_key = EXCLUDED.url_key, updated_at = EXCLUDED.updated_at
+			RETURNING created_at`
+		err = tx.QueryRow(ctx, query,
+			string(key.Entity), key.EntityID, key.Scope.SiteID, key.Scope.Locale, key.URLKey, key.CreatedAt, key.UpdatedAt,
+		).Scan(&key.CreatedAt)
+		if database.IsUniqueViolation(err, "uq_catalog_url_key") {
+			return errors.Conflict(fmt.Sprintf("URL key %q is already in use", key.URLKey))
+		}
+		if err != nil {
+			return errors.InternalWrap(err, "failed to save URL key")
+		}
+		return nil
+	})
+}
+
+// Delete removes the localized key of an entity in a scope
+func (r *PostgresURLKeyRepository) Delete(ctx context.Context, entity domain.URLKeyEntity, entityID int64, scope domain.URLKeyScope) error {
+	query := `DELETE FROM catalog_url_key WHERE entity_type = $1 AND entity_id = $2 AND site_id = $3 AND locale = $4`
+	tag, err := r.db.Pool().Exec(ctx, query, string(entity), entityID, scope.SiteID, scope.Locale)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete URL key")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("URL key of %s %d", entity, entityID))
+	}
+	return nil
+}
+
+// FindByEntity retrieves the localized keys of an entity
+func (r *PostgresURLKeyRepository) FindByEntity(ctx context.Context, entity domain.URLKeyEntity, entityID int64) ([]*domain.LocalizedURLKey, error) {
+	query := `
+		SELECT site_id, locale, url_key, created_at, updated_at
+		FROM catalog_url_key
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY site_id, locale`
+	rows, err := r.db.Query(ctx, query, string(entity), entityID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query URL keys")
+	}
+	defer rows.Close()
+
+	keys := make([]*domain.LocalizedURLKey, 0)
+	for rows.Next() {
+		key := &domain.LocalizedURLKey{Entity: entity, EntityID: entityID}
+		if err := rows.Scan(&key.Scope.SiteID, &key.Scope.Locale, &key.URLKey, &key.CreatedAt, &key.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan URL key")
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to query URL keys")
+	}
+	return keys, nil
+}
+
+// FindOwner returns the entity other than excludeID using urlKey where scope is served
+func (r *PostgresURLKeyRepository) FindOwner(ctx context.Context, entity domain.URLKeyEntity, scope domain.URLKeyScope, urlKey string, excludeID int64) (int64, error) {
+	return r.findOwner(ctx, r.db.Pool(), entity, scope, urlKey, excludeID)
+}
+
+// FindTaken returns the keys equal to base or starting with base and a hyphen used where scope is served
+func (r *PostgresURLKeyRepository) FindTaken(ctx context.Context, entity domain.URLKeyEntity, scope domain.URLKeyScope, base string, excludeID int64) ([]string, error) {
+	target, ok := urlKeyTables[entity]
+	if !ok {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown URL key entity %q", entity))
+	}
+
+	// Slugs hold no LIKE wildcards, so base is matched literally
+	query := fmt.Sprintf(`
+		SELECT url_key FROM %[1]s
+		WHERE (url_key = $1 OR url_key LIKE $1 || '-%%') AND archived = 'N' AND %[2]s <> $2
+		UNION
+		SELECT url_key FROM catalog_url_key
+		WHERE entity_type = $3 AND (url_key = $1 OR url_key LIKE $1 || '-%%') AND entity_id <> $2
+			AND ($4 = '' OR site_id = '' OR site_id = $4)
+			AND ($5 = '' OR locale = '' OR locale = $5)`, target.table, target.idColumn)
+	rows, err := r.db.Query(ctx, query, base, excludeID, string(entity), scope.SiteID, scope.Locale)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query URL keys")
+	}
+	defer rows.Close()
+
+	taken := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan URL key")
+		}
+		taken = append(taken, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to query URL keys")
+	}
+	return taken, nil
+}
+
+// findOwner looks up the owner of a key with db, a pool or a transaction. Localized
+// keys come first, so a storefront request resolves to the key of its own scope.
+func (r *PostgresURLKeyRepository) findOwner(ctx context.Context, db DBTX, entity domain.URLKeyEntity, scope domain.URLKeyScope, urlKey string, excludeID int64) (int64, error) {
+	target, ok := urlKeyTables[entity]
+	if !ok {
+		return 0, errors.ValidationError(fmt.Sprintf("unknown URL key entity %q", entity))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT entity_id FROM (
+			SELECT entity_id, 0 AS rank FROM catalog_url_key
+			WHERE entity_type = $1 AND url_key = $2 AND entity_id <> $3
+				AND ($4 = '' OR site_id = '' OR site_id = $4)
+				AND ($5 = '' OR locale = '' OR locale = $5)
+			UNION ALL
+			SELECT %[2]s, 1 AS rank FROM %[1]s
+			WHERE url_key = $2 AND archived = 'N' AND %[2]s <> $3
+		) owners
+		ORDER BY rank
+		LIMIT 1`, target.table, target.idColumn)
+
+	var ownerID int64
+	err := db.QueryRow(ctx, query, string(entity), urlKey, excludeID, scope.SiteID, scope.Locale).Scan(&ownerID)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to find URL key owner")
+	}
+	return ownerID, nil
+}
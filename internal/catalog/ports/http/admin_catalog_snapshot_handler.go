@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// maxSnapshotUploadBytes bounds uploaded catalog snapshots
+const maxSnapshotUploadBytes = 64 << 20
+
+// AdminCatalogSnapshotHandler handles catalog promotion between environments:
+// export a snapshot, review its diff against the live catalog, then import it
+type AdminCatalogSnapshotHandler struct {
+	snapshotService application.CatalogSnapshotService
+	authMiddleware  func(http.Handler) http.Handler
+	logger          *logger.Logger
+}
+
+// NewAdminCatalogSnapshotHandler creates a new admin catalog snapshot handler
+func NewAdminCatalogSnapshotHandler(snapshotService application.CatalogSnapshotService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminCatalogSnapshotHandler {
+	return &AdminCatalogSnapshotHandler{
+		snapshotService: snapshotService,
+		authMiddleware:  authMiddleware,
+		logger:          logger,
+	}
+}
+
+// RegisterRoutes registers catalog snapshot routes
+func (h *AdminCatalogSnapshotHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/catalog/snapshot", h.ExportSnapshot)
+		r.Post("/admin/catalog/snapshot/diff", h.DiffSnapshot)
+		r.Post("/admin/catalog/snapshot/import", h.ImportSnapshot)
+	})
+}
+
+// ExportSnapshot downloads a snapshot of the live catalog
+func (h *AdminCatalogSnapshotHandler) ExportSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.snapshotService.ExportSnapshot(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to export catalog snapshot")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("catalog-snapshot-%s.json", snapshot.ExportedAt.Format("20060102-150405"))
+	if snapshot.Source != "" {
+		filename = fmt.Sprintf("catalog-snapshot-%s-%s.json", snapshot.Source, snapshot.ExportedAt.Format("20060102-150405"))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	pkghttp.RespondJSON(w, http.StatusOK, snapshot)
+}
+
+// DiffSnapshot compares an uploaded snapshot against the live catalog.
+// The response's diff_id is required to import the snapshot.
+func (h *AdminCatalogSnapshotHandler) DiffSnapshot(w http.ResponseWriter, r *http.Request) {
+	var snapshot domain.CatalogSnapshot
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSnapshotUploadBytes)).Decode(&snapshot); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid catalog snapshot").WithInternal(err))
+		return
+	}
+
+	diff, err := h.snapshotService.DiffSnapshot(r.Context(), &snapshot)
+	if err != nil {
+		h.logger.WithError(err).WithField("source", snapshot.Source).Error("failed to diff catalog snapshot")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, diff)
+}
+
+// ImportSnapshot applies a snapshot whose diff was reviewed
+func (h *AdminCatalogSnapshotHandler) ImportSnapshot(w http.ResponseWriter, r *http.Request) {
+	var cmd application.ImportCatalogSnapshotCommand
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSnapshotUploadBytes)).Decode(&cmd); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	result, err := h.snapshotService.ImportSnapshot(r.Context(), &cmd)
+	if err != nil {
+		h.logger.WithError(err).WithField("diff_id", cmd.DiffID).Error("failed to import catalog snapshot")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	h.logger.WithField("diff_id", result.DiffID).WithField("admin_id", middleware.GetUserID(r.Context())).Info("catalog snapshot import requested")
+	pkghttp.RespondJSON(w, http.StatusOK, result)
+}
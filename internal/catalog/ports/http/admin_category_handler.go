@@ -45,7 +45,8 @@ func (h *AdminCategoryHandler) RegisterRoutes(r chi.Router) {
 	})
 }
 
-// CreateCategory creates a new category
+// CreateCategory creates a new category. Without url_key, one is generated from the
+// name; a taken url_key is rejected with 409 and a suggested alternative.
 func (h *AdminCategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
 	var cmd commands.CreateCategoryCommand
 	if err := pkghttp.DecodeJSON(r, &cmd); err != nil {
@@ -56,7 +57,7 @@ func (h *AdminCategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Req
 	categoryID, err := h.commandHandler.HandleCreateCategory(r.Context(), &cmd)
 	if err != nil {
 		h.logger.WithError(err).Error("failed to create category")
-		pkghttp.RespondError(w, err)
+		respondURLKeyError(w, err)
 		return
 	}
 
@@ -175,7 +176,7 @@ func (h *AdminCategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Req
 
 	if err := h.commandHandler.HandleUpdateCategory(r.Context(), &cmd); err != nil {
 		h.logger.WithError(err).WithField("category_id", id).Error("failed to update category")
-		pkghttp.RespondError(w, err)
+		respondURLKeyError(w, err)
 		return
 	}
 
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	"github.com/qhato/ecommerce/internal/catalog/application/queries"
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminCategoryMerchandisingHandler handles category listing rules: pinned
+// products, sort rule and a preview of the resulting storefront listing
+type AdminCategoryMerchandisingHandler struct {
+	merchandisingService application.CategoryMerchandisingService
+	productQueryHandler  *queries.ProductQueryHandler // Configured like the storefront's
+	authMiddleware       func(http.Handler) http.Handler
+	logger               *logger.Logger
+}
+
+// NewAdminCategoryMerchandisingHandler creates a new admin category merchandising handler
+func NewAdminCategoryMerchandisingHandler(
+	merchandisingService application.CategoryMerchandisingService,
+	productQueryHandler *queries.ProductQueryHandler,
+	authMiddleware func(http.Handler) http.Handler,
+	logger *logger.Logger,
+) *AdminCategoryMerchandisingHandler {
+	return &AdminCategoryMerchandisingHandler{
+		merchandisingService: merchandisingService,
+		productQueryHandler:  productQueryHandler,
+		authMiddleware:       authMiddleware,
+		logger:               logger,
+	}
+}
+
+// RegisterRoutes registers category merchandising routes
+func (h *AdminCategoryMerchandisingHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/categories/{id}/merchandising", h.GetMerchandising)
+		r.Put("/admin/categories/{id}/merchandising", h.SaveMerchandising)
+		r.Delete("/admin/categories/{id}/merchandising", h.DeleteMerchandising)
+		r.Post("/admin/categories/{id}/merchandising/preview", h.PreviewListing)
+	})
+}
+
+// GetMerchandising returns the listing rules of a category
+func (h *AdminCategoryMerchandisingHandler) GetMerchandising(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid category ID"))
+		return
+	}
+
+	merchandising, err := h.merchandisingService.GetMerchandising(r.Context(), categoryID)
+	if err != nil {
+		h.logger.WithError(err).WithField("category_id", categoryID).Error("failed to get category merchandising")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, merchandising)
+}
+
+// SaveMerchandising replaces the listing rules of a category
+func (h *AdminCategoryMerchandisingHandler) SaveMerchandising(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid category ID"))
+		return
+	}
+
+	var req application.CategoryMerchandisingRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	merchandising, err := h.merchandisingService.SaveMerchandising(r.Context(), categoryID, &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("category_id", categoryID).Error("failed to save category merchandising")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, merchandising)
+}
+
+// DeleteMerchandising removes the listing rules of a category, restoring the regular sort
+func (h *AdminCategoryMerchandisingHandler) DeleteMerchandising(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid category ID"))
+		return
+	}
+
+	if err := h.merchandisingService.DeleteMerchandising(r.Context(), categoryID); err != nil {
+		h.logger.WithError(err).WithField("category_id", categoryID).Error("failed to delete category merchandising")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PreviewListing returns the storefront listing of a category. A request body
+// previews unsaved rules; without one the saved rules are used.
+// Query params: page, page_size
+func (h *AdminCategoryMerchandisingHandler) PreviewListing(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid category ID"))
+		return
+	}
+
+	var criteria *domain.MerchandisingCriteria
+	if r.ContentLength != 0 {
+		var req application.CategoryMerchandisingRequest
+		if err := pkghttp.DecodeJSON(r, &req); err != nil {
+			pkghttp.RespondError(w, err)
+			return
+		}
+		criteria, err = h.merchandisingService.PreviewCriteria(r.Context(), categoryID, &req)
+	} else {
+		criteria, err = h.merchandisingService.MerchandisingCriteria(r.Context(), categoryID)
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("category_id", categoryID).Error("failed to build category merchandising preview")
+		pkghttp.RespondError(w, err)
+		return
+	}
+	if criteria == nil {
+		criteria = &domain.MerchandisingCriteria{}
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	query := &queries.ListProductsByCategoryQuery{
+		CategoryID:    categoryID,
+		Page:          page,
+		PageSize:      pageSize,
+		ActiveOnly:    true,
+		PublishStatus: string(domain.ProductStatusPublished),
+		Merchandising: criteria,
+	}
+	result, err := h.productQueryHandler.HandleListProductsByCategory(r.Context(), query)
+	if err != nil {
+		h.logger.WithError(err).WithField("category_id", categoryID).Error("failed to preview category listing")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminContentPublishHandler previews the storefront URLs of catalog and CMS
+// entities and purges them from headless frontends and CDNs on demand
+type AdminContentPublishHandler struct {
+	publishService application.ContentPublishService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminContentPublishHandler creates a new admin content publish handler
+func NewAdminContentPublishHandler(publishService application.ContentPublishService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminContentPublishHandler {
+	return &AdminContentPublishHandler{
+		publishService: publishService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers content publish routes
+func (h *AdminContentPublishHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/content-publish/urls", h.GetAffectedURLs)
+		r.Post("/admin/content-publish/purge", h.Purge)
+	})
+}
+
+// GetAffectedURLs lists the storefront URLs showing an entity, given by the
+// entity_type and entity_id query parameters
+func (h *AdminContentPublishHandler) GetAffectedURLs(w http.ResponseWriter, r *http.Request) {
+	entity := domain.PublishedEntity{
+		Type: domain.PublishedEntityType(r.URL.Query().Get("entity_type")),
+		ID:   r.URL.Query().Get("entity_id"),
+	}
+
+	urls, err := h.publishService.AffectedURLs(r.Context(), entity)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logger.Fields{
+			"entity_type": entity.Type,
+			"entity_id":   entity.ID,
+		}).Error("failed to resolve storefront URLs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"entity": entity,
+		"urls":   urls,
+	})
+}
+
+// Purge sends a content published notification for an entity and extra URLs
+func (h *AdminContentPublishHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	var req application.ContentPurgeRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	notification, err := h.publishService.Purge(r.Context(), &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("entity_type", req.EntityType).Error("failed to purge storefront URLs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusAccepted, notification)
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminDataQualityHandler handles product completeness scores and the report
+// of products that blocking checks hold back from publication
+type AdminDataQualityHandler struct {
+	qualityService application.DataQualityService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminDataQualityHandler creates a new admin data quality handler
+func NewAdminDataQualityHandler(qualityService application.DataQualityService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminDataQualityHandler {
+	return &AdminDataQualityHandler{
+		qualityService: qualityService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers data quality routes
+func (h *AdminDataQualityHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/catalog/quality-rules", h.ListRules)
+		r.Get("/admin/catalog/quality-report", h.GetReport)
+		r.Get("/admin/products/{id}/quality", h.GetProductQuality)
+	})
+}
+
+// ListRules lists the rules products are scored against
+func (h *AdminDataQualityHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	pkghttp.RespondJSON(w, http.StatusOK, h.qualityService.Rules())
+}
+
+// GetReport scores the products awaiting publication and lists those blocked
+func (h *AdminDataQualityHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	report, err := h.qualityService.PublicationReport(r.Context(), limit)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to build catalog quality report")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, report)
+}
+
+// GetProductQuality scores a product and lists the checks it fails
+func (h *AdminDataQualityHandler) GetProductQuality(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	quality, err := h.qualityService.ProductQuality(r.Context(), productID)
+	if err != nil {
+		h.logger.WithError(err).WithField("product_id", productID).Error("failed to score product quality")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, quality)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminPriceWatchHandler handles the price-drop alerts customers set on SKUs and
+// the scheduled price changes that may trigger them
+type AdminPriceWatchHandler struct {
+	priceWatchService application.PriceWatchService
+	authMiddleware    func(http.Handler) http.Handler
+	logger            *logger.Logger
+}
+
+// NewAdminPriceWatchHandler creates a new admin price watch handler
+func NewAdminPriceWatchHandler(priceWatchService application.PriceWatchService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminPriceWatchHandler {
+	return &AdminPriceWatchHandler{
+		priceWatchService: priceWatchService,
+		authMiddleware:    authMiddleware,
+		logger:            logger,
+	}
+}
+
+// RegisterRoutes registers price watch and scheduled price routes
+func (h *AdminPriceWatchHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/skus/{id}/price-watches", h.ListWatches)
+		r.Get("/admin/skus/{id}/scheduled-prices", h.ListScheduledPrices)
+		r.Post("/admin/skus/{id}/scheduled-prices", h.SchedulePrice)
+		r.Delete("/admin/scheduled-prices/{changeId}", h.CancelScheduledPrice)
+	})
+}
+
+// ListWatches lists the watches of a SKU with how many were notified and converted
+func (h *AdminPriceWatchHandler) ListWatches(w http.ResponseWriter, r *http.Request) {
+	skuID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid SKU ID"))
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	watches, err := h.priceWatchService.ListSKUWatches(r.Context(), skuID, limit)
+	if err != nil {
+		h.logger.WithError(err).WithField("sku_id", skuID).Error("failed to list price watches")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, watches)
+}
+
+// ListScheduledPrices lists the scheduled price changes of a SKU, applied or not
+func (h *AdminPriceWatchHandler) ListScheduledPrices(w http.ResponseWriter, r *http.Request) {
+	skuID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid SKU ID"))
+		return
+	}
+
+	changes, err := h.priceWatchService.ListScheduledPriceChanges(r.Context(), skuID)
+	if err != nil {
+		h.logger.WithError(err).WithField("sku_id", skuID).Error("failed to list scheduled price changes")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, changes)
+}
+
+// SchedulePrice schedules new prices of a SKU
+func (h *AdminPriceWatchHandler) SchedulePrice(w http.ResponseWriter, r *http.Request) {
+	skuID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid SKU ID"))
+		return
+	}
+
+	var req application.SchedulePriceChangeRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	change, err := h.priceWatchService.SchedulePriceChange(r.Context(), skuID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).WithField("sku_id", skuID).Error("failed to schedule price change")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, change)
+}
+
+// CancelScheduledPrice removes a price change that was not applied yet
+func (h *AdminPriceWatchHandler) CancelScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "changeId"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid scheduled price change ID"))
+		return
+	}
+
+	if err := h.priceWatchService.CancelScheduledPriceChange(r.Context(), id); err != nil {
+		h.logger.WithError(err).WithField("scheduled_price_change_id", id).Error("failed to cancel scheduled price change")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
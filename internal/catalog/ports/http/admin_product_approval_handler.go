@@ -0,0 +1,171 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application/commands"
+	"github.com/qhato/ecommerce/internal/catalog/application/queries"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminProductApprovalHandler handles the review of product drafts: contributors
+// see the draft and published versions of products, and reviewers approve
+// drafts to the storefront or send them back
+type AdminProductApprovalHandler struct {
+	commandHandler     *commands.ProductCommandHandler
+	queryHandler       *queries.ProductVersionQueryHandler
+	authMiddleware     func(http.Handler) http.Handler
+	reviewerMiddleware func(http.Handler) http.Handler
+	logger             *logger.Logger
+}
+
+// NewAdminProductApprovalHandler creates a new admin product approval handler.
+// reviewerMiddleware guards approvals and rejections.
+func NewAdminProductApprovalHandler(
+	commandHandler *commands.ProductCommandHandler,
+	queryHandler *queries.ProductVersionQueryHandler,
+	authMiddleware func(http.Handler) http.Handler,
+	reviewerMiddleware func(http.Handler) http.Handler,
+	logger *logger.Logger,
+) *AdminProductApprovalHandler {
+	return &AdminProductApprovalHandler{
+		commandHandler:     commandHandler,
+		queryHandler:       queryHandler,
+		authMiddleware:     authMiddleware,
+		reviewerMiddleware: reviewerMiddleware,
+		logger:             logger,
+	}
+}
+
+// RegisterRoutes registers product approval routes
+func (h *AdminProductApprovalHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/product-drafts", h.ListDrafts)
+		r.Get("/admin/products/{id}/versions", h.GetVersions)
+		r.Delete("/admin/products/{id}/draft", h.DiscardDraft)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.reviewerMiddleware)
+			r.Post("/admin/products/{id}/approve", h.Approve)
+			r.Post("/admin/products/{id}/reject", h.Reject)
+		})
+	})
+}
+
+// ListDrafts lists the edits of published products awaiting review, optionally
+// filtered by status (PENDING or REJECTED)
+func (h *AdminProductApprovalHandler) ListDrafts(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	query := &queries.ListProductDraftsQuery{
+		Status:   r.URL.Query().Get("status"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	result, err := h.queryHandler.HandleListProductDrafts(r.Context(), query)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list product drafts")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, result)
+}
+
+// GetVersions shows the published version of a product next to its draft
+func (h *AdminProductApprovalHandler) GetVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	versions, err := h.queryHandler.HandleGetProductVersions(r.Context(), &queries.GetProductVersionsQuery{ID: id})
+	if err != nil {
+		h.logger.WithError(err).WithField("product_id", id).Error("failed to get product versions")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, versions)
+}
+
+// Approve publishes a draft product, or applies the draft of a published one
+func (h *AdminProductApprovalHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	cmd := &commands.ApproveProductCommand{ID: id, ReviewedBy: middleware.GetUserID(r.Context())}
+	if err := h.commandHandler.HandleApproveProduct(r.Context(), cmd); err != nil {
+		h.logger.WithError(err).WithField("product_id", id).Error("failed to approve product")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "product published successfully",
+	})
+}
+
+// Reject sends the draft of a product back to its contributors with a note
+func (h *AdminProductApprovalHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	var cmd commands.RejectProductDraftCommand
+	if err := pkghttp.DecodeJSON(r, &cmd); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+	cmd.ID = id
+	cmd.ReviewedBy = middleware.GetUserID(r.Context())
+
+	if err := h.commandHandler.HandleRejectProductDraft(r.Context(), &cmd); err != nil {
+		h.logger.WithError(err).WithField("product_id", id).Error("failed to reject product draft")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "product draft rejected",
+	})
+}
+
+// DiscardDraft drops the pending edits of a product
+func (h *AdminProductApprovalHandler) DiscardDraft(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	if err := h.commandHandler.HandleDiscardProductDraft(r.Context(), &commands.DiscardProductDraftCommand{ID: id}); err != nil {
+		h.logger.WithError(err).WithField("product_id", id).Error("failed to discard product draft")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "product draft discarded",
+	})
+}
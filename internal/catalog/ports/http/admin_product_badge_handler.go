@@ -0,0 +1,122 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminProductBadgeHandler handles manual product badges. Computed badges
+// (New, Sale, Bestseller) are configured in the catalog settings.
+type AdminProductBadgeHandler struct {
+	badgeService   application.ProductBadgeService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminProductBadgeHandler creates a new admin product badge handler
+func NewAdminProductBadgeHandler(badgeService application.ProductBadgeService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminProductBadgeHandler {
+	return &AdminProductBadgeHandler{
+		badgeService:   badgeService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers product badge routes
+func (h *AdminProductBadgeHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/products/{id}/badges", h.ListBadges)
+		r.Post("/admin/products/{id}/badges", h.CreateBadge)
+		r.Put("/admin/product-badges/{badgeId}", h.UpdateBadge)
+		r.Delete("/admin/product-badges/{badgeId}", h.DeleteBadge)
+	})
+}
+
+// ListBadges lists the manual badges of a product, scheduled or not
+func (h *AdminProductBadgeHandler) ListBadges(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	badges, err := h.badgeService.ListBadges(r.Context(), productID)
+	if err != nil {
+		h.logger.WithError(err).WithField("product_id", productID).Error("failed to list product badges")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, badges)
+}
+
+// CreateBadge adds a manual badge to a product
+func (h *AdminProductBadgeHandler) CreateBadge(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	var req application.ProductBadgeRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	badge, err := h.badgeService.CreateBadge(r.Context(), productID, &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("product_id", productID).Error("failed to create product badge")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, badge)
+}
+
+// UpdateBadge replaces a manual badge
+func (h *AdminProductBadgeHandler) UpdateBadge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "badgeId"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid badge ID"))
+		return
+	}
+
+	var req application.ProductBadgeRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	badge, err := h.badgeService.UpdateBadge(r.Context(), id, &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("badge_id", id).Error("failed to update product badge")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, badge)
+}
+
+// DeleteBadge removes a manual badge
+func (h *AdminProductBadgeHandler) DeleteBadge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "badgeId"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid badge ID"))
+		return
+	}
+
+	if err := h.badgeService.DeleteBadge(r.Context(), id); err != nil {
+		h.logger.WithError(err).WithField("badge_id", id).Error("failed to delete product badge")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
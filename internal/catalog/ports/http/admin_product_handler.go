@@ -3,12 +3,15 @@ package http
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/qhato/ecommerce/internal/catalog/application/commands"
 	"github.com/qhato/ecommerce/internal/catalog/application/queries"
 	pkghttp "github.com/qhato/ecommerce/pkg/http"
 	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+	"github.com/qhato/ecommerce/pkg/schedule"
 )
 
 // AdminProductHandler handles admin product HTTP requests
@@ -44,7 +47,8 @@ func (h *AdminProductHandler) RegisterRoutes(r chi.Router) {
 	})
 }
 
-// CreateProduct creates a new product
+// CreateProduct creates a new product. Without url_key, one is generated from the
+// model; a taken url_key is rejected with 409 and a suggested alternative.
 func (h *AdminProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	var cmd commands.CreateProductCommand
 	if err := pkghttp.DecodeJSON(r, &cmd); err != nil {
@@ -55,7 +59,7 @@ func (h *AdminProductHandler) CreateProduct(w http.ResponseWriter, r *http.Reque
 	productID, err := h.commandHandler.HandleCreateProduct(r.Context(), &cmd)
 	if err != nil {
 		h.logger.WithError(err).Error("failed to create product")
-		pkghttp.RespondError(w, err)
+		respondURLKeyError(w, err)
 		return
 	}
 
@@ -77,13 +81,23 @@ func (h *AdminProductHandler) ListProducts(w http.ResponseWriter, r *http.Reques
 	}
 
 	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	activeOnly := r.URL.Query().Get("active_only") == "true"
 	sortBy := r.URL.Query().Get("sort_by")
 	sortOrder := r.URL.Query().Get("sort_order")
 
+	previewDate, err := parsePreviewDate(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
 	query := &queries.ListProductsQuery{
 		Page:            page,
 		PageSize:        pageSize,
 		IncludeArchived: includeArchived,
+		ActiveOnly:      activeOnly || previewDate != nil,
+		ActiveAt:        previewDate,
+		PublishStatus:   r.URL.Query().Get("publish_status"),
 		SortBy:          sortBy,
 		SortOrder:       sortOrder,
 	}
@@ -133,13 +147,22 @@ func (h *AdminProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	cmd.ID = id
+	cmd.EditedBy = middleware.GetUserID(r.Context())
 
-	if err := h.commandHandler.HandleUpdateProduct(r.Context(), &cmd); err != nil {
+	pendingApproval, err := h.commandHandler.HandleUpdateProduct(r.Context(), &cmd)
+	if err != nil {
 		h.logger.WithError(err).WithField("product_id", id).Error("failed to update product")
-		pkghttp.RespondError(w, err)
+		respondURLKeyError(w, err)
 		return
 	}
 
+	if pendingApproval {
+		pkghttp.RespondJSON(w, http.StatusAccepted, map[string]interface{}{
+			"message":          "product changes saved as a draft awaiting approval",
+			"pending_approval": true,
+		})
+		return
+	}
 	pkghttp.RespondJSON(w, http.StatusOK, map[string]interface{}{
 		"message": "product updated successfully",
 	})
@@ -206,14 +229,24 @@ func (h *AdminProductHandler) SearchProducts(w http.ResponseWriter, r *http.Requ
 	}
 
 	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	activeOnly := r.URL.Query().Get("active_only") == "true"
 	sortBy := r.URL.Query().Get("sort_by")
 	sortOrder := r.URL.Query().Get("sort_order")
 
+	previewDate, err := parsePreviewDate(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
 	query := &queries.SearchProductsQuery{
 		Query:           searchQuery,
 		Page:            page,
 		PageSize:        pageSize,
 		IncludeArchived: includeArchived,
+		ActiveOnly:      activeOnly || previewDate != nil,
+		ActiveAt:        previewDate,
+		PublishStatus:   r.URL.Query().Get("publish_status"),
 		SortBy:          sortBy,
 		SortOrder:       sortOrder,
 	}
@@ -226,4 +259,20 @@ func (h *AdminProductHandler) SearchProducts(w http.ResponseWriter, r *http.Requ
 	}
 
 	pkghttp.RespondJSON(w, http.StatusOK, result)
-}
\ No newline at end of file
+}
+
+// parsePreviewDate reads the optional preview_date parameter, which shows the
+// catalog as the storefront would at that date (RFC3339, or a local date and
+// time in the site's time zone)
+func parsePreviewDate(r *http.Request) (*time.Time, error) {
+	raw := r.URL.Query().Get("preview_date")
+	if raw == "" {
+		return nil, nil
+	}
+
+	date, err := schedule.Parse(raw)
+	if err != nil {
+		return nil, pkghttp.NewValidationError("invalid preview_date, expected RFC3339, YYYY-MM-DDTHH:MM:SS or YYYY-MM-DD")
+	}
+	return schedule.StartOf(&date, schedule.Location(r.Context())), nil
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminSaleEventHandler handles scheduled sale events ("daily deals") and their reports
+type AdminSaleEventHandler struct {
+	saleEventService application.SaleEventService
+	authMiddleware   func(http.Handler) http.Handler
+	logger           *logger.Logger
+}
+
+// NewAdminSaleEventHandler creates a new admin sale event handler
+func NewAdminSaleEventHandler(saleEventService application.SaleEventService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminSaleEventHandler {
+	return &AdminSaleEventHandler{
+		saleEventService: saleEventService,
+		authMiddleware:   authMiddleware,
+		logger:           logger,
+	}
+}
+
+// RegisterRoutes registers sale event routes
+func (h *AdminSaleEventHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/sale-events", h.ListSaleEvents)
+		r.Post("/admin/sale-events", h.CreateSaleEvent)
+		r.Get("/admin/sale-events/{id}", h.GetSaleEvent)
+		r.Post("/admin/sale-events/{id}/cancel", h.CancelSaleEvent)
+		r.Get("/admin/sale-events/{id}/performance", h.GetPerformance)
+	})
+}
+
+// ListSaleEvents lists sale events, optionally of one status
+func (h *AdminSaleEventHandler) ListSaleEvents(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	events, err := h.saleEventService.ListSaleEvents(r.Context(), r.URL.Query().Get("status"), limit)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list sale events")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, events)
+}
+
+// CreateSaleEvent schedules a sale event on a set of SKUs
+func (h *AdminSaleEventHandler) CreateSaleEvent(w http.ResponseWriter, r *http.Request) {
+	var req application.CreateSaleEventRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	saleEvent, err := h.saleEventService.CreateSaleEvent(r.Context(), &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to create sale event")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, saleEvent)
+}
+
+// GetSaleEvent retrieves a sale event with its SKUs
+func (h *AdminSaleEventHandler) GetSaleEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := saleEventID(w, r)
+	if !ok {
+		return
+	}
+
+	saleEvent, err := h.saleEventService.GetSaleEvent(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("sale_event_id", id).Error("failed to get sale event")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, saleEvent)
+}
+
+// CancelSaleEvent cancels a scheduled sale event, or ends an active one early
+func (h *AdminSaleEventHandler) CancelSaleEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := saleEventID(w, r)
+	if !ok {
+		return
+	}
+
+	saleEvent, err := h.saleEventService.CancelSaleEvent(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("sale_event_id", id).Error("failed to cancel sale event")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, saleEvent)
+}
+
+// GetPerformance reports the sales of a sale event against the window before it
+func (h *AdminSaleEventHandler) GetPerformance(w http.ResponseWriter, r *http.Request) {
+	id, ok := saleEventID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.saleEventService.GetPerformance(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("sale_event_id", id).Error("failed to report sale event performance")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, report)
+}
+
+func saleEventID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid sale event ID"))
+		return 0, false
+	}
+	return id, true
+}
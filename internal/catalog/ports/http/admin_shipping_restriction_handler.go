@@ -0,0 +1,116 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminShippingRestrictionHandler handles admin shipping restriction requests
+type AdminShippingRestrictionHandler struct {
+	restrictionService application.ShippingRestrictionService
+	logger             *logger.Logger
+}
+
+// NewAdminShippingRestrictionHandler creates a new admin shipping restriction handler
+func NewAdminShippingRestrictionHandler(restrictionService application.ShippingRestrictionService, logger *logger.Logger) *AdminShippingRestrictionHandler {
+	return &AdminShippingRestrictionHandler{
+		restrictionService: restrictionService,
+		logger:             logger,
+	}
+}
+
+// RegisterRoutes registers admin shipping restriction routes
+func (h *AdminShippingRestrictionHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/shipping-restrictions", func(r chi.Router) {
+		r.Get("/", h.ListRestrictions)
+		r.Post("/", h.CreateRestriction)
+		r.Put("/{id}", h.UpdateRestriction)
+		r.Delete("/{id}", h.DeleteRestriction)
+	})
+}
+
+// ListRestrictions lists shipping restrictions, optionally filtered by product
+func (h *AdminShippingRestrictionHandler) ListRestrictions(w http.ResponseWriter, r *http.Request) {
+	var productID *int64
+	if productIDStr := r.URL.Query().Get("product_id"); productIDStr != "" {
+		id, err := strconv.ParseInt(productIDStr, 10, 64)
+		if err != nil {
+			pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+			return
+		}
+		productID = &id
+	}
+
+	restrictions, err := h.restrictionService.ListRestrictions(r.Context(), productID)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list shipping restrictions")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, restrictions)
+}
+
+// CreateRestriction creates a shipping restriction for a product or SKU
+func (h *AdminShippingRestrictionHandler) CreateRestriction(w http.ResponseWriter, r *http.Request) {
+	var req application.ShippingRestrictionRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	restriction, err := h.restrictionService.CreateRestriction(r.Context(), &req)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to create shipping restriction")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, restriction)
+}
+
+// UpdateRestriction replaces the rules of a shipping restriction
+func (h *AdminShippingRestrictionHandler) UpdateRestriction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid shipping restriction ID"))
+		return
+	}
+
+	var req application.ShippingRestrictionRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	restriction, err := h.restrictionService.UpdateRestriction(r.Context(), id, &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("restriction_id", id).Error("failed to update shipping restriction")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, restriction)
+}
+
+// DeleteRestriction removes a shipping restriction
+func (h *AdminShippingRestrictionHandler) DeleteRestriction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid shipping restriction ID"))
+		return
+	}
+
+	if err := h.restrictionService.DeleteRestriction(r.Context(), id); err != nil {
+		h.logger.WithError(err).WithField("restriction_id", id).Error("failed to delete shipping restriction")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
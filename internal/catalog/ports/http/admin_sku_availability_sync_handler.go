@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminSKUAvailabilitySyncHandler handles the SKUs the inventory sync made
+// unavailable, and syncing SKUs by hand
+type AdminSKUAvailabilitySyncHandler struct {
+	syncService    application.SKUAvailabilitySyncService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminSKUAvailabilitySyncHandler creates a new admin SKU availability sync handler
+func NewAdminSKUAvailabilitySyncHandler(syncService application.SKUAvailabilitySyncService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminSKUAvailabilitySyncHandler {
+	return &AdminSKUAvailabilitySyncHandler{
+		syncService:    syncService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers SKU availability sync routes
+func (h *AdminSKUAvailabilitySyncHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/skus/stockouts", h.ListStockouts)
+		r.Post("/admin/skus/availability-sync", h.SyncSKUs)
+	})
+}
+
+// ListStockouts lists the SKUs the inventory sync made unavailable, oldest first
+func (h *AdminSKUAvailabilitySyncHandler) ListStockouts(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	stockouts, err := h.syncService.ListStockouts(r.Context(), limit)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list SKU stockouts")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, stockouts)
+}
+
+// SyncSKUs syncs the availability of SKUs with their inventory now, e.g.
+// {"sku_ids": [101, 102]}, reporting what was done with each
+func (h *AdminSKUAvailabilitySyncHandler) SyncSKUs(w http.ResponseWriter, r *http.Request) {
+	var req application.SyncSKUAvailabilityRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	results, err := h.syncService.SyncSKUs(r.Context(), req.SKUIDs)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to sync SKU availability")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, results)
+}
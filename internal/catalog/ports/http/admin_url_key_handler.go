@@ -0,0 +1,152 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminURLKeyHandler handles product and category URL keys: availability checks
+// with suggested alternatives, and the localized keys of each site and locale
+type AdminURLKeyHandler struct {
+	urlKeyService  application.URLKeyService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminURLKeyHandler creates a new admin URL key handler
+func NewAdminURLKeyHandler(urlKeyService application.URLKeyService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminURLKeyHandler {
+	return &AdminURLKeyHandler{
+		urlKeyService:  urlKeyService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers URL key routes
+func (h *AdminURLKeyHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/catalog/url-keys", func(r chi.Router) {
+			r.Get("/check", h.CheckURLKey)
+			r.Get("/{entity}/{id}", h.GetURLKeys)
+			r.Put("/{entity}/{id}", h.SetLocalizedURLKey)
+			r.Delete("/{entity}/{id}", h.DeleteLocalizedURLKey)
+		})
+	})
+}
+
+// CheckURLKey reports whether a key is free before a product or category is saved,
+// e.g. ?entity=product&key=blue-shirt&exclude_id=42, or ?entity=category&name=Sale
+// for the key generated from a name. site_id and locale check a localized key.
+func (h *AdminURLKeyHandler) CheckURLKey(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var excludeID int64
+	if value := query.Get("exclude_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			pkghttp.RespondError(w, pkghttp.NewValidationError("invalid exclude_id"))
+			return
+		}
+		excludeID = id
+	}
+
+	scope := domain.URLKeyScope{SiteID: query.Get("site_id"), Locale: query.Get("locale")}
+	check, err := h.urlKeyService.CheckURLKey(r.Context(), domain.URLKeyEntity(query.Get("entity")), excludeID, scope, query.Get("key"), query.Get("name"))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, check)
+}
+
+// GetURLKeys lists the default and localized keys of a product or category
+func (h *AdminURLKeyHandler) GetURLKeys(w http.ResponseWriter, r *http.Request) {
+	entity, id, ok := urlKeyEntity(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.urlKeyService.GetURLKeys(r.Context(), entity, id)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, keys)
+}
+
+// SetLocalizedURLKey sets the key of a product or category on a site and/or locale,
+// e.g. {"locale": "de-DE", "name": "Blaues Hemd"}. A taken key is rejected with 409
+// and a suggested alternative.
+func (h *AdminURLKeyHandler) SetLocalizedURLKey(w http.ResponseWriter, r *http.Request) {
+	entity, id, ok := urlKeyEntity(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.LocalizedURLKeyRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	keys, err := h.urlKeyService.SetLocalizedURLKey(r.Context(), entity, id, &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("entity_id", id).Error("failed to set localized URL key")
+		respondURLKeyError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, keys)
+}
+
+// DeleteLocalizedURLKey removes the key of a product or category on the site and/or
+// locale given as query parameters, so its default key applies there
+func (h *AdminURLKeyHandler) DeleteLocalizedURLKey(w http.ResponseWriter, r *http.Request) {
+	entity, id, ok := urlKeyEntity(w, r)
+	if !ok {
+		return
+	}
+
+	scope := domain.URLKeyScope{SiteID: r.URL.Query().Get("site_id"), Locale: r.URL.Query().Get("locale")}
+	if err := h.urlKeyService.DeleteLocalizedURLKey(r.Context(), entity, id, scope); err != nil {
+		h.logger.WithError(err).WithField("entity_id", id).Error("failed to delete localized URL key")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// urlKeyEntity reads the entity type and ID of the path
+func urlKeyEntity(w http.ResponseWriter, r *http.Request) (domain.URLKeyEntity, int64, bool) {
+	entity := domain.URLKeyEntity(chi.URLParam(r, "entity"))
+	if !entity.IsValid() {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("entity must be product or category"))
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid "+string(entity)+" ID"))
+		return "", 0, false
+	}
+	return entity, id, true
+}
+
+// respondURLKeyError responds with err, rendering taken URL keys with the
+// suggested alternative so admin clients can offer it
+func respondURLKeyError(w http.ResponseWriter, err error) {
+	if errors.IsURLKeyTaken(err) {
+		errors.HandleHTTPError(w, err)
+		return
+	}
+	pkghttp.RespondError(w, err)
+}
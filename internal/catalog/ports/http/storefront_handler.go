@@ -7,8 +7,11 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/qhato/ecommerce/internal/catalog/application"
 	"github.com/qhato/ecommerce/internal/catalog/application/queries"
+	"github.com/qhato/ecommerce/internal/catalog/domain"
+	inventoryApp "github.com/qhato/ecommerce/internal/inventory/application"
 	pkghttp "github.com/qhato/ecommerce/pkg/http"
 	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/requestctx"
 )
 
 // StorefrontCatalogHandler handles public storefront catalog HTTP requests (read-only)
@@ -16,6 +19,11 @@ type StorefrontCatalogHandler struct {
 	productQueryHandler  *queries.ProductQueryHandler
 	categoryQueryHandler *queries.CategoryQueryHandler
 	skuQueryHandler      *queries.SKUQueryHandler
+	restrictionService   application.ShippingRestrictionService
+	atpService           inventoryApp.ATPService
+	saleEventService     application.SaleEventService
+	urlKeyService        application.URLKeyService
+	addToCartPath        string
 	logger               *logger.Logger
 }
 
@@ -24,16 +32,38 @@ func NewStorefrontCatalogHandler(
 	productQueryHandler *queries.ProductQueryHandler,
 	categoryQueryHandler *queries.CategoryQueryHandler,
 	skuQueryHandler *queries.SKUQueryHandler,
+	restrictionService application.ShippingRestrictionService,
 	logger *logger.Logger,
 ) *StorefrontCatalogHandler {
 	return &StorefrontCatalogHandler{
 		productQueryHandler:  productQueryHandler,
 		categoryQueryHandler: categoryQueryHandler,
 		skuQueryHandler:      skuQueryHandler,
+		restrictionService:   restrictionService,
 		logger:               logger,
 	}
 }
 
+// SetAvailableToPromise enables product availability from available-to-promise inventory
+func (h *StorefrontCatalogHandler) SetAvailableToPromise(atpService inventoryApp.ATPService) {
+	h.atpService = atpService
+}
+
+// SetSaleEvents enables the listing of live and upcoming sale events
+func (h *StorefrontCatalogHandler) SetSaleEvents(saleEventService application.SaleEventService) {
+	h.saleEventService = saleEventService
+}
+
+// SetURLKeys enables product and category lookups by the URL key of the request's site and locale
+func (h *StorefrontCatalogHandler) SetURLKeys(urlKeyService application.URLKeyService) {
+	h.urlKeyService = urlKeyService
+}
+
+// SetAddToCartPath sets the cart endpoint that product and SKU links point to for adding items
+func (h *StorefrontCatalogHandler) SetAddToCartPath(path string) {
+	h.addToCartPath = path
+}
+
 // RegisterRoutes registers storefront catalog routes
 func (h *StorefrontCatalogHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/catalog", func(r chi.Router) {
@@ -41,21 +71,29 @@ func (h *StorefrontCatalogHandler) RegisterRoutes(r chi.Router) {
 		r.Get("/products", h.ListProducts)
 		r.Get("/products/{id}", h.GetProduct)
 		r.Get("/products/url/{url}", h.GetProductByURL)
+		r.Get("/products/key/{key}", h.GetProductByURLKey)
 		r.Get("/products/search", h.SearchProducts)
+		r.Get("/products/{id}/shipping-restrictions", h.GetProductShippingRestrictions)
+		r.Get("/products/{id}/availability", h.GetProductAvailability)
 
 		// Category routes
 		r.Get("/categories", h.ListRootCategories)
 		r.Get("/categories/{id}", h.GetCategory)
 		r.Get("/categories/url/{url}", h.GetCategoryByURL)
+		r.Get("/categories/key/{key}", h.GetCategoryByURLKey)
 		r.Get("/categories/{id}/children", h.ListChildCategories)
 		r.Get("/categories/{id}/products", h.ListProductsByCategory)
 		r.Get("/categories/{id}/path", h.GetCategoryPath)
+		r.Get("/navigation", h.GetNavigationTree)
 
 		// SKU routes
 		r.Get("/skus", h.ListSKUs)
 		r.Get("/skus/{id}", h.GetSKU)
 		r.Get("/skus/upc/{upc}", h.GetSKUByUPC)
 		r.Get("/skus/product/{product_id}", h.ListSKUsByProduct)
+
+		// Sale event routes
+		r.Get("/deals", h.ListDeals)
 	})
 }
 
@@ -80,6 +118,8 @@ func (h *StorefrontCatalogHandler) ListProducts(w http.ResponseWriter, r *http.R
 		Page:            page,
 		PageSize:        pageSize,
 		IncludeArchived: false, // Storefront never shows archived products
+		ActiveOnly:      true,  // Only products within their active window
+		PublishStatus:   string(domain.ProductStatusPublished),
 		SortBy:          sortBy,
 		SortOrder:       sortOrder,
 	}
@@ -91,7 +131,7 @@ func (h *StorefrontCatalogHandler) ListProducts(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, result)
+	h.respond(w, r, result)
 }
 
 // GetProduct retrieves a product by ID
@@ -111,13 +151,118 @@ func (h *StorefrontCatalogHandler) GetProduct(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Check if archived (storefront shouldn't show archived products)
-	if product.Archived {
+	// Check if archived, inactive or not yet approved (storefront shouldn't show them)
+	if product.Archived || !product.IsActive || product.PublishStatus == string(domain.ProductStatusDraft) {
 		pkghttp.RespondError(w, pkghttp.NewNotFoundError("product not found"))
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, product)
+	h.respond(w, r, product)
+}
+
+// GetProductShippingRestrictions lists the shipping restrictions of a product and its SKUs.
+// When a country is given, the product-level restrictions preventing delivery there are reported instead.
+func (h *StorefrontCatalogHandler) GetProductShippingRestrictions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	country := r.URL.Query().Get("country")
+	if country == "" {
+		restrictions, err := h.restrictionService.ListRestrictions(r.Context(), &id)
+		if err != nil {
+			h.logger.WithError(err).WithField("product_id", id).Error("failed to list shipping restrictions")
+			pkghttp.RespondError(w, err)
+			return
+		}
+		pkghttp.RespondJSON(w, http.StatusOK, restrictions)
+		return
+	}
+
+	lines := []application.RestrictionCheckLine{{ProductID: id}}
+	ageVerified := r.URL.Query().Get("age_verified") == "true"
+	violations, err := h.restrictionService.CheckDestination(r.Context(), lines, country, r.URL.Query().Get("region"), ageVerified)
+	if err != nil {
+		h.logger.WithError(err).WithField("product_id", id).Error("failed to check shipping restrictions")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, violations)
+}
+
+// ProductAvailabilityResponse is the availability of a product's SKUs on the product page
+type ProductAvailabilityResponse struct {
+	ProductID int64                  `json:"product_id"`
+	InStock   bool                   `json:"in_stock"`
+	SKUs      []*SKUAvailabilityView `json:"skus"`
+}
+
+// SKUAvailabilityView is the available-to-promise quantity of a SKU
+type SKUAvailabilityView struct {
+	SKUID          int64  `json:"sku_id"`
+	Name           string `json:"name"`
+	Available      int    `json:"available"`
+	Inbound        int    `json:"inbound"`
+	AllowBackorder bool   `json:"allow_backorder"`
+	InStock        bool   `json:"in_stock"`
+}
+
+// GetProductAvailability returns the available-to-promise quantity of a product's available SKUs
+func (h *StorefrontCatalogHandler) GetProductAvailability(w http.ResponseWriter, r *http.Request) {
+	if h.atpService == nil {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError("product availability is not enabled"))
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	skus, err := h.skuQueryHandler.HandleListSKUsByProduct(r.Context(), &queries.ListSKUsByProductQuery{ProductID: id})
+	if err != nil {
+		h.logger.WithError(err).WithField("product_id", id).Error("failed to list SKUs by product")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	var available []*application.SkuDTO
+	skuIDs := make([]string, 0, len(skus))
+	for _, sku := range skus {
+		if sku.Available && sku.IsActive {
+			available = append(available, sku)
+			skuIDs = append(skuIDs, strconv.FormatInt(sku.ID, 10))
+		}
+	}
+
+	response := &ProductAvailabilityResponse{ProductID: id, SKUs: []*SKUAvailabilityView{}}
+	if len(skuIDs) > 0 {
+		atps, err := h.atpService.GetAvailableToPromise(r.Context(), skuIDs)
+		if err != nil {
+			h.logger.WithError(err).WithField("product_id", id).Error("failed to get product availability")
+			pkghttp.RespondError(w, err)
+			return
+		}
+		for i, sku := range available {
+			atp := atps[i]
+			view := &SKUAvailabilityView{
+				SKUID:          sku.ID,
+				Name:           sku.Name,
+				Available:      atp.Available,
+				Inbound:        atp.Inbound,
+				AllowBackorder: atp.AllowBackorder,
+				InStock:        sku.InventoryType == "ALWAYS_AVAILABLE" || atp.AllowBackorder || atp.Available > 0,
+			}
+			response.InStock = response.InStock || view.InStock
+			response.SKUs = append(response.SKUs, view)
+		}
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, response)
 }
 
 // GetProductByURL retrieves a product by URL
@@ -136,7 +281,34 @@ func (h *StorefrontCatalogHandler) GetProductByURL(w http.ResponseWriter, r *htt
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, product)
+	if product.Archived || !product.IsActive || product.PublishStatus == string(domain.ProductStatusDraft) {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError("product not found"))
+		return
+	}
+
+	h.respond(w, r, product)
+}
+
+// GetProductByURLKey retrieves a product by its URL key on the request's site and locale
+func (h *StorefrontCatalogHandler) GetProductByURLKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.resolveURLKey(w, r, domain.URLKeyEntityProduct)
+	if !ok {
+		return
+	}
+
+	product, err := h.productQueryHandler.HandleGetProductByID(r.Context(), &queries.GetProductByIDQuery{ID: id})
+	if err != nil {
+		h.logger.WithError(err).WithField("product_id", id).Error("failed to get product")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	if product.Archived || !product.IsActive || product.PublishStatus == string(domain.ProductStatusDraft) {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError("product not found"))
+		return
+	}
+
+	h.respond(w, r, product)
 }
 
 // SearchProducts searches for products
@@ -165,8 +337,11 @@ func (h *StorefrontCatalogHandler) SearchProducts(w http.ResponseWriter, r *http
 		Page:            page,
 		PageSize:        pageSize,
 		IncludeArchived: false, // Storefront never shows archived products
+		ActiveOnly:      true,  // Only products within their active window
+		PublishStatus:   string(domain.ProductStatusPublished),
 		SortBy:          sortBy,
 		SortOrder:       sortOrder,
+		Badge:           r.URL.Query().Get("badge"),
 	}
 
 	result, err := h.productQueryHandler.HandleSearchProducts(r.Context(), query)
@@ -176,7 +351,7 @@ func (h *StorefrontCatalogHandler) SearchProducts(w http.ResponseWriter, r *http
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, result)
+	h.respond(w, r, result)
 }
 
 // ListProductsByCategory lists products by category
@@ -206,6 +381,8 @@ func (h *StorefrontCatalogHandler) ListProductsByCategory(w http.ResponseWriter,
 		Page:            page,
 		PageSize:        pageSize,
 		IncludeArchived: false,
+		ActiveOnly:      true,
+		PublishStatus:   string(domain.ProductStatusPublished),
 		SortBy:          sortBy,
 		SortOrder:       sortOrder,
 	}
@@ -217,7 +394,7 @@ func (h *StorefrontCatalogHandler) ListProductsByCategory(w http.ResponseWriter,
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, result)
+	h.respond(w, r, result)
 }
 
 // Category Handlers
@@ -253,7 +430,7 @@ func (h *StorefrontCatalogHandler) ListRootCategories(w http.ResponseWriter, r *
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, result)
+	h.respond(w, r, result)
 }
 
 // GetCategory retrieves a category by ID
@@ -279,7 +456,46 @@ func (h *StorefrontCatalogHandler) GetCategory(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, category)
+	h.respond(w, r, category)
+}
+
+// GetCategoryByURLKey retrieves a category by its URL key on the request's site and locale
+func (h *StorefrontCatalogHandler) GetCategoryByURLKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.resolveURLKey(w, r, domain.URLKeyEntityCategory)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryQueryHandler.HandleGetCategoryByID(r.Context(), &queries.GetCategoryByIDQuery{ID: id})
+	if err != nil {
+		h.logger.WithError(err).WithField("category_id", id).Error("failed to get category")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	if category.Archived || !category.IsActive {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError("category not found"))
+		return
+	}
+
+	h.respond(w, r, category)
+}
+
+// resolveURLKey returns the product or category the key of the path leads to on the
+// request's site and locale
+func (h *StorefrontCatalogHandler) resolveURLKey(w http.ResponseWriter, r *http.Request, entity domain.URLKeyEntity) (int64, bool) {
+	if h.urlKeyService == nil {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError(string(entity)+" not found"))
+		return 0, false
+	}
+
+	scope := domain.URLKeyScope{SiteID: requestctx.SiteID(r.Context()), Locale: requestctx.Locale(r.Context())}
+	id, err := h.urlKeyService.ResolveURLKey(r.Context(), entity, scope, chi.URLParam(r, "key"))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return 0, false
+	}
+	return id, true
 }
 
 // GetCategoryByURL retrieves a category by URL
@@ -298,7 +514,12 @@ func (h *StorefrontCatalogHandler) GetCategoryByURL(w http.ResponseWriter, r *ht
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, category)
+	if category.Archived || !category.IsActive {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError("category not found"))
+		return
+	}
+
+	h.respond(w, r, category)
 }
 
 // ListChildCategories lists active child categories
@@ -340,7 +561,7 @@ func (h *StorefrontCatalogHandler) ListChildCategories(w http.ResponseWriter, r
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, result)
+	h.respond(w, r, result)
 }
 
 // GetCategoryPath retrieves the full path from root to category
@@ -360,7 +581,19 @@ func (h *StorefrontCatalogHandler) GetCategoryPath(w http.ResponseWriter, r *htt
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, path)
+	h.respond(w, r, path)
+}
+
+// GetNavigationTree returns the active category tree shown in storefront menus
+func (h *StorefrontCatalogHandler) GetNavigationTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.categoryQueryHandler.HandleGetNavigationTree(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get navigation tree")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, tree)
 }
 
 // SKU Handlers
@@ -396,7 +629,7 @@ func (h *StorefrontCatalogHandler) ListSKUs(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, result)
+	h.respond(w, r, result)
 }
 
 // GetSKU retrieves a SKU by ID
@@ -422,7 +655,8 @@ func (h *StorefrontCatalogHandler) GetSKU(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, sku)
+	sku.LocalizePrice(requestctx.Locale(r.Context()))
+	h.respond(w, r, sku)
 }
 
 // GetSKUByUPC retrieves a SKU by UPC
@@ -441,7 +675,13 @@ func (h *StorefrontCatalogHandler) GetSKUByUPC(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, sku)
+	if !sku.Available || !sku.IsActive {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError("SKU not found"))
+		return
+	}
+
+	sku.LocalizePrice(requestctx.Locale(r.Context()))
+	h.respond(w, r, sku)
 }
 
 // ListSKUsByProduct lists SKUs by product ID
@@ -461,13 +701,37 @@ func (h *StorefrontCatalogHandler) ListSKUsByProduct(w http.ResponseWriter, r *h
 		return
 	}
 
-	// Filter only available and active SKUs for storefront
+	// Filter only available and active SKUs priced in the request currency
+	currency := requestctx.Currency(r.Context())
 	var availableSKUs []*application.SkuDTO
 	for _, sku := range skus {
+		if currency != "" && sku.CurrencyCode != "" && sku.CurrencyCode != currency {
+			continue
+		}
 		if sku.Available && sku.IsActive {
+			sku.LocalizePrice(requestctx.Locale(r.Context()))
 			availableSKUs = append(availableSKUs, sku)
 		}
 	}
 
-	pkghttp.RespondJSON(w, http.StatusOK, availableSKUs)
-}
\ No newline at end of file
+	h.respond(w, r, availableSKUs)
+}
+
+// Sale Event Handlers
+
+// ListDeals lists the live sale events and the upcoming ones with their countdowns
+func (h *StorefrontCatalogHandler) ListDeals(w http.ResponseWriter, r *http.Request) {
+	if h.saleEventService == nil {
+		pkghttp.RespondError(w, pkghttp.NewNotFoundError("sale events are not enabled"))
+		return
+	}
+
+	deals, err := h.saleEventService.ListStorefrontDeals(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list sale events")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, deals)
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+)
+
+// respond writes a storefront catalog response. Clients accepting
+// application/hal+json (or passing ?links=true) get hypermedia links so they
+// can navigate the catalog without hardcoding URL patterns.
+func (h *StorefrontCatalogHandler) respond(w http.ResponseWriter, r *http.Request, data interface{}) {
+	w.Header().Add("Vary", "Accept")
+	if !pkghttp.WantsLinks(r) {
+		pkghttp.RespondJSON(w, http.StatusOK, data)
+		return
+	}
+	h.addLinks(r, data)
+	pkghttp.RespondHAL(w, http.StatusOK, data)
+}
+
+// addLinks sets the links of catalog DTOs and of the pages listing them
+func (h *StorefrontCatalogHandler) addLinks(r *http.Request, data interface{}) {
+	switch v := data.(type) {
+	case *application.PaginatedResponse:
+		v.Links = pageLinks(r, v)
+		h.addLinks(r, v.Data)
+	case *application.ProductDTO:
+		v.Links = h.productLinks(v)
+	case []*application.ProductDTO:
+		for _, product := range v {
+			product.Links = h.productLinks(product)
+		}
+	case *application.CategoryDTO:
+		v.Links = categoryLinks(v)
+	case []*application.CategoryDTO:
+		for _, category := range v {
+			category.Links = categoryLinks(category)
+		}
+	case *application.SkuDTO:
+		v.Links = h.skuLinks(v)
+	case []*application.SkuDTO:
+		for _, sku := range v {
+			sku.Links = h.skuLinks(sku)
+		}
+	}
+}
+
+func (h *StorefrontCatalogHandler) productLinks(product *application.ProductDTO) application.Links {
+	links := application.Links{
+		"self":         {Href: fmt.Sprintf("/catalog/products/%d", product.ID)},
+		"skus":         {Href: fmt.Sprintf("/catalog/skus/product/%d", product.ID)},
+		"availability": {Href: fmt.Sprintf("/catalog/products/%d/availability", product.ID)},
+	}
+	if product.DefaultCategoryID != nil {
+		links["category"] = application.LinkDTO{Href: fmt.Sprintf("/catalog/categories/%d", *product.DefaultCategoryID)}
+	}
+	if product.DefaultSKUID != nil {
+		links["default_sku"] = application.LinkDTO{Href: fmt.Sprintf("/catalog/skus/%d", *product.DefaultSKUID)}
+		if link, ok := h.addToCartLink(*product.DefaultSKUID); ok {
+			links["add_to_cart"] = link
+		}
+	}
+	return links
+}
+
+func categoryLinks(category *application.CategoryDTO) application.Links {
+	links := application.Links{
+		"self":     {Href: fmt.Sprintf("/catalog/categories/%d", category.ID)},
+		"products": {Href: fmt.Sprintf("/catalog/categories/%d/products", category.ID)},
+		"children": {Href: fmt.Sprintf("/catalog/categories/%d/children", category.ID)},
+		"path":     {Href: fmt.Sprintf("/catalog/categories/%d/path", category.ID)},
+	}
+	if category.DefaultParentCategoryID != nil {
+		links["parent"] = application.LinkDTO{Href: fmt.Sprintf("/catalog/categories/%d", *category.DefaultParentCategoryID)}
+	}
+	return links
+}
+
+func (h *StorefrontCatalogHandler) skuLinks(sku *application.SkuDTO) application.Links {
+	links := application.Links{
+		"self": {Href: fmt.Sprintf("/catalog/skus/%d", sku.ID)},
+	}
+	if sku.DefaultProductID != nil {
+		links["product"] = application.LinkDTO{Href: fmt.Sprintf("/catalog/products/%d", *sku.DefaultProductID)}
+	}
+	if sku.Available && sku.IsActive {
+		if link, ok := h.addToCartLink(sku.ID); ok {
+			links["add_to_cart"] = link
+		}
+	}
+	return links
+}
+
+// addToCartLink links to the configured cart endpoint; without one the
+// storefront has nowhere to add items and the link is omitted
+func (h *StorefrontCatalogHandler) addToCartLink(skuID int64) (application.LinkDTO, bool) {
+	if h.addToCartPath == "" {
+		return application.LinkDTO{}, false
+	}
+	return application.LinkDTO{
+		Href:   fmt.Sprintf("%s?sku_id=%d", h.addToCartPath, skuID),
+		Method: http.MethodPost,
+	}, true
+}
+
+// pageLinks links a page to its neighbours, keeping the other query parameters
+func pageLinks(r *http.Request, page *application.PaginatedResponse) application.Links {
+	pageHref := func(n int) string {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(n))
+		return r.URL.Path + "?" + query.Encode()
+	}
+
+	links := application.Links{
+		"self":  {Href: pageHref(page.Page)},
+		"first": {Href: pageHref(1)},
+	}
+	if page.Page > 1 {
+		links["prev"] = application.LinkDTO{Href: pageHref(page.Page - 1)}
+	}
+	if int64(page.Page) < page.TotalPages {
+		links["next"] = application.LinkDTO{Href: pageHref(page.Page + 1)}
+	}
+	if page.TotalPages > 0 {
+		links["last"] = application.LinkDTO{Href: pageHref(int(page.TotalPages))}
+	}
+	return links
+}
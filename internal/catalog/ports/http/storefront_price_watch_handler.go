@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/catalog/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/requestctx"
+)
+
+// StorefrontPriceWatchHandler handles the signed-in customer's price-drop alerts
+type StorefrontPriceWatchHandler struct {
+	priceWatchService application.PriceWatchService
+	logger            *logger.Logger
+}
+
+// NewStorefrontPriceWatchHandler creates a new storefront price watch handler
+func NewStorefrontPriceWatchHandler(priceWatchService application.PriceWatchService, logger *logger.Logger) *StorefrontPriceWatchHandler {
+	return &StorefrontPriceWatchHandler{
+		priceWatchService: priceWatchService,
+		logger:            logger,
+	}
+}
+
+// RegisterRoutes registers price watch routes
+func (h *StorefrontPriceWatchHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/account/price-watches", func(r chi.Router) {
+		r.Get("/", h.ListWatches)
+		r.Post("/", h.Watch)
+		r.Delete("/{id}", h.CancelWatch)
+	})
+}
+
+// ListWatches lists the signed-in customer's price watches
+func (h *StorefrontPriceWatchHandler) ListWatches(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := watchingCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	watches, err := h.priceWatchService.ListCustomerWatches(r.Context(), customerID)
+	if err != nil {
+		h.logger.WithError(err).WithField("customer_id", customerID).Error("failed to list price watches")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, watches)
+}
+
+// Watch alerts the signed-in customer when the price of a SKU drops below the
+// watched price, or below the current price when none is given
+func (h *StorefrontPriceWatchHandler) Watch(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := watchingCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.WatchPriceRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	watch, err := h.priceWatchService.Watch(r.Context(), customerID, &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("sku_id", req.SKUID).Error("failed to watch SKU price")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, watch)
+}
+
+// CancelWatch stops one of the signed-in customer's price watches
+func (h *StorefrontPriceWatchHandler) CancelWatch(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := watchingCustomer(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid price watch ID"))
+		return
+	}
+
+	if err := h.priceWatchService.CancelWatch(r.Context(), customerID, id); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// watchingCustomer reads the customer of the request, responding with an error for anonymous shoppers
+func watchingCustomer(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	customerID := requestctx.CustomerID(r.Context())
+	if customerID == 0 {
+		pkghttp.RespondError(w, errors.Unauthorized("sign in to watch prices"))
+		return 0, false
+	}
+	return customerID, true
+}
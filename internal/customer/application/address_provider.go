@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/httpclient"
+)
+
+// internalAddressProviderName marks addresses checked by the internal normalizer only
+const internalAddressProviderName = "internal"
+
+// AddressVerification is a provider's answer for an address
+type AddressVerification struct {
+	Deliverable bool                  `json:"deliverable"`
+	Candidates  []*AddressDTO         `json:"candidates"` // Standardized or corrected addresses, best first
+	Issues      []domain.AddressIssue `json:"issues"`
+}
+
+// AddressProvider verifies addresses with an external address verification service
+// such as Loqate or SmartyStreets
+type AddressProvider interface {
+	// Name identifies the provider on validation results
+	Name() string
+
+	// Verify checks an address is deliverable and proposes corrections
+	Verify(ctx context.Context, address *domain.Address) (*AddressVerification, error)
+}
+
+// httpAddressProvider posts addresses to an external verification service
+type httpAddressProvider struct {
+	client *httpclient.Client
+	path   string
+}
+
+// NewHTTPAddressProvider creates a provider posting addresses to an external verification
+// service. The service answers an AddressDTO with an AddressVerification.
+func NewHTTPAddressProvider(client *httpclient.Client, path string) AddressProvider {
+	return &httpAddressProvider{client: client, path: path}
+}
+
+func (p *httpAddressProvider) Name() string {
+	return "external"
+}
+
+func (p *httpAddressProvider) Verify(ctx context.Context, address *domain.Address) (*AddressVerification, error) {
+	var result AddressVerification
+	if err := p.client.DoJSON(ctx, http.MethodPost, p.path, ToAddressDTO(address), &result); err != nil {
+		return nil, fmt.Errorf("external address verification failed: %w", err)
+	}
+	return &result, nil
+}
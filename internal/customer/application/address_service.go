@@ -0,0 +1,223 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AddressService validates and normalizes addresses and keeps customers' address books.
+// Addresses are normalized internally (whitespace, country and postal code formats) and
+// then checked with the address provider, when one is configured. Addresses that are
+// invalid, or that the provider suggests corrections for, are rejected with an
+// errors.AddressInvalid error carrying the validation so the storefront can offer the
+// corrections.
+type AddressService interface {
+	// ValidateAddress checks an address without saving it.
+	ValidateAddress(ctx context.Context, address *AddressDTO) (*AddressValidationDTO, error)
+
+	// ListAddresses lists the address book of a customer.
+	ListAddresses(ctx context.Context, customerID int64) ([]*CustomerAddressDTO, error)
+
+	// CreateAddress validates an address and adds it to a customer's address book.
+	CreateAddress(ctx context.Context, customerID int64, req *SaveAddressRequest) (*CustomerAddressDTO, error)
+
+	// UpdateAddress validates and replaces an address of a customer's address book.
+	UpdateAddress(ctx context.Context, customerID, customerAddressID int64, req *SaveAddressRequest) (*CustomerAddressDTO, error)
+
+	// CheckAddress validates a saved address before it is used at checkout. Addresses
+	// already verified, or kept as entered by the customer, are not checked again.
+	CheckAddress(ctx context.Context, addressID int64) error
+}
+
+type addressService struct {
+	addressRepo domain.AddressRepository
+	provider    AddressProvider
+	log         *logger.Logger
+}
+
+// NewAddressService creates a new instance of AddressService. provider may be nil,
+// in which case addresses are only normalized internally.
+func NewAddressService(addressRepo domain.AddressRepository, provider AddressProvider, log *logger.Logger) AddressService {
+	return &addressService{
+		addressRepo: addressRepo,
+		provider:    provider,
+		log:         log,
+	}
+}
+
+func (s *addressService) ValidateAddress(ctx context.Context, address *AddressDTO) (*AddressValidationDTO, error) {
+	return ToAddressValidationDTO(s.validate(ctx, ToAddressDomain(address))), nil
+}
+
+func (s *addressService) ListAddresses(ctx context.Context, customerID int64) ([]*CustomerAddressDTO, error) {
+	customerAddresses, err := s.addressRepo.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*CustomerAddressDTO, len(customerAddresses))
+	for i, customerAddress := range customerAddresses {
+		dtos[i] = ToCustomerAddressDTO(customerAddress)
+	}
+	return dtos, nil
+}
+
+func (s *addressService) CreateAddress(ctx context.Context, customerID int64, req *SaveAddressRequest) (*CustomerAddressDTO, error) {
+	address, err := s.addressToSave(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	customerAddress := &domain.CustomerAddress{
+		AddressName: req.Name,
+		CustomerID:  customerID,
+		Address:     address,
+	}
+	if err := s.addressRepo.CreateCustomerAddress(ctx, customerAddress); err != nil {
+		return nil, err
+	}
+	return ToCustomerAddressDTO(customerAddress), nil
+}
+
+func (s *addressService) UpdateAddress(ctx context.Context, customerID, customerAddressID int64, req *SaveAddressRequest) (*CustomerAddressDTO, error) {
+	customerAddresses, err := s.addressRepo.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	var customerAddress *domain.CustomerAddress
+	for _, candidate := range customerAddresses {
+		if candidate.ID == customerAddressID {
+			customerAddress = candidate
+			break
+		}
+	}
+	if customerAddress == nil {
+		return nil, errors.NotFound("customer address")
+	}
+
+	address, err := s.addressToSave(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	address.ID = customerAddress.AddressID
+	customerAddress.Address = address
+	if req.Name != "" {
+		customerAddress.AddressName = req.Name
+	}
+	if err := s.addressRepo.UpdateCustomerAddress(ctx, customerAddress); err != nil {
+		return nil, err
+	}
+	return ToCustomerAddressDTO(customerAddress), nil
+}
+
+func (s *addressService) CheckAddress(ctx context.Context, addressID int64) error {
+	address, err := s.addressRepo.FindByID(ctx, addressID)
+	if err != nil {
+		return fmt.Errorf("failed to find address %d: %w", addressID, err)
+	}
+	if address == nil {
+		return errors.NotFound(fmt.Sprintf("address %d", addressID))
+	}
+	if address.VerificationLevel == domain.AddressVerificationVerified ||
+		address.VerificationLevel == domain.AddressVerificationOverridden {
+		return nil
+	}
+
+	validation := s.validate(ctx, address)
+	switch validation.Verdict {
+	case domain.AddressVerdictInvalid, domain.AddressVerdictSuggested:
+		return errors.AddressInvalid(ToAddressValidationDTO(validation))
+	case domain.AddressVerdictValid:
+		if validation.Address.VerificationLevel == address.VerificationLevel && domain.SameAddress(validation.Address, address) {
+			return nil
+		}
+		// Keep the normalized form so a verified address is not checked again
+		if err := s.addressRepo.Update(ctx, validation.Address); err != nil {
+			s.log.WithError(err).WithField("address_id", addressID).Warn("Failed to save verified address")
+		}
+	}
+	return nil
+}
+
+// addressToSave validates an address to be saved, rejecting it unless it is valid,
+// unverified, or the customer chose to keep it as entered against the suggestions
+func (s *addressService) addressToSave(ctx context.Context, req *SaveAddressRequest) (*domain.Address, error) {
+	validation := s.validate(ctx, ToAddressDomain(&req.Address))
+	switch validation.Verdict {
+	case domain.AddressVerdictInvalid:
+		return nil, errors.AddressInvalid(ToAddressValidationDTO(validation))
+	case domain.AddressVerdictSuggested:
+		if !req.AcceptAsEntered {
+			return nil, errors.AddressInvalid(ToAddressValidationDTO(validation))
+		}
+		validation.Address.VerificationLevel = domain.AddressVerificationOverridden
+	}
+	return validation.Address, nil
+}
+
+// validate normalizes an address and checks it with the provider. The provider being
+// unavailable never blocks a customer: the address is then UNVERIFIED.
+func (s *addressService) validate(ctx context.Context, address *domain.Address) *domain.AddressValidation {
+	normalized, issues := domain.NormalizeAddress(address)
+	normalized.Standardized = true
+	normalized.VerificationLevel = domain.AddressVerificationNormalized
+	validation := &domain.AddressValidation{
+		Verdict:  domain.AddressVerdictValid,
+		Address:  normalized,
+		Provider: internalAddressProviderName,
+	}
+	if len(issues) > 0 {
+		validation.Verdict = domain.AddressVerdictInvalid
+		validation.Issues = issues
+		return validation
+	}
+	if s.provider == nil {
+		return validation
+	}
+
+	validation.Provider = s.provider.Name()
+	result, err := s.provider.Verify(ctx, normalized)
+	if err != nil {
+		s.log.WithError(err).WithField("country", normalized.IsoCountryAlpha2).Warn("Address verification unavailable")
+		validation.Verdict = domain.AddressVerdictUnverified
+		return validation
+	}
+
+	for _, candidate := range result.Candidates {
+		suggestion, candidateIssues := domain.NormalizeAddress(ToAddressDomain(candidate))
+		if len(candidateIssues) > 0 {
+			continue // The provider's own formatting does not pass the internal checks
+		}
+		// Providers answer with the postal address; the recipient stays as entered
+		suggestion.ID = normalized.ID
+		suggestion.FirstName = normalized.FirstName
+		suggestion.LastName = normalized.LastName
+		suggestion.CompanyName = normalized.CompanyName
+		suggestion.PrimaryPhone = normalized.PrimaryPhone
+		suggestion.Standardized = true
+		suggestion.VerificationLevel = domain.AddressVerificationVerified
+		validation.Suggestions = append(validation.Suggestions, suggestion)
+	}
+
+	switch {
+	case result.Deliverable && (len(validation.Suggestions) == 0 || domain.SameAddress(validation.Suggestions[0], normalized)):
+		validation.Address.VerificationLevel = domain.AddressVerificationVerified
+		validation.Suggestions = nil
+	case len(validation.Suggestions) > 0:
+		validation.Verdict = domain.AddressVerdictSuggested
+		validation.Issues = result.Issues
+	default:
+		validation.Verdict = domain.AddressVerdictInvalid
+		validation.Issues = result.Issues
+		if len(validation.Issues) == 0 {
+			validation.Issues = []domain.AddressIssue{{
+				Field:   "address",
+				Code:    domain.AddressIssueUndeliverable,
+				Message: "Address could not be found",
+			}}
+		}
+	}
+	return validation
+}
@@ -40,6 +40,12 @@ type ChangePasswordCommand struct {
 	NewPassword string `json:"new_password" validate:"required,min=8"`
 }
 
+// AuthenticateCustomerCommand represents a command to sign a customer in
+type AuthenticateCustomerCommand struct {
+	EmailAddress string `json:"email_address" validate:"required,email"`
+	Password     string `json:"password" validate:"required"`
+}
+
 // DeactivateCustomerCommand represents a command to deactivate a customer
 type DeactivateCustomerCommand struct {
 	ID int64 `json:"id" validate:"required"`
@@ -53,6 +59,7 @@ type ActivateCustomerCommand struct {
 // CustomerCommandHandler handles customer commands
 type CustomerCommandHandler struct {
 	repo            domain.CustomerRepository
+	noteRepo        domain.CustomerNoteRepository
 	eventBus        event.Bus
 	validator       *validator.Validator
 	logger          *logger.Logger
@@ -62,12 +69,14 @@ type CustomerCommandHandler struct {
 // NewCustomerCommandHandler creates a new customer command handler
 func NewCustomerCommandHandler(
 	repo domain.CustomerRepository,
+	noteRepo domain.CustomerNoteRepository,
 	eventBus event.Bus,
 	validator *validator.Validator,
 	logger *logger.Logger,
 ) *CustomerCommandHandler {
 	return &CustomerCommandHandler{
 		repo:            repo,
+		noteRepo:        noteRepo,
 		eventBus:        eventBus,
 		validator:       validator,
 		logger:          logger,
@@ -266,6 +275,47 @@ func (h *CustomerCommandHandler) HandleChangePassword(ctx context.Context, cmd *
 	return nil
 }
 
+// HandleAuthenticateCustomer checks a customer's credentials. A password
+// stored as a legacy hash by an import, or with an outdated bcrypt cost, is
+// rehashed now that the plain password is known.
+func (h *CustomerCommandHandler) HandleAuthenticateCustomer(ctx context.Context, cmd *AuthenticateCustomerCommand) (*domain.Customer, error) {
+	// Validate command
+	if err := h.validator.Validate(cmd); err != nil {
+		return nil, errors.ValidationError("invalid authenticate customer command").WithInternal(err)
+	}
+
+	// Unknown emails and wrong passwords are not told apart
+	customer, err := h.repo.FindByEmail(ctx, cmd.EmailAddress)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.Unauthorized("invalid email address or password")
+		}
+		return nil, errors.InternalWrap(err, "failed to find customer")
+	}
+	if customer.Password == "" || h.passwordService.VerifyPassword(customer.Password, cmd.Password) != nil {
+		return nil, errors.Unauthorized("invalid email address or password")
+	}
+	if customer.Archived || customer.Deactivated {
+		return nil, errors.Forbidden("customer account is not active")
+	}
+
+	if h.passwordService.NeedsRehash(customer.Password) {
+		hashedPassword, err := h.passwordService.HashPassword(cmd.Password)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to hash password")
+		}
+		// The customer is signed in even if the rehash is not stored; it is retried next time
+		if err := h.repo.UpdatePassword(ctx, customer.ID, hashedPassword); err != nil {
+			h.logger.WithError(err).WithField("customer_id", customer.ID).Error("failed to rehash password")
+		} else {
+			customer.Password = hashedPassword
+			h.logger.WithField("customer_id", customer.ID).Info("password rehashed")
+		}
+	}
+
+	return customer, nil
+}
+
 // HandleDeactivateCustomer handles the deactivate customer command
 func (h *CustomerCommandHandler) HandleDeactivateCustomer(ctx context.Context, cmd *DeactivateCustomerCommand) error {
 	// Validate command
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/customer/application"
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// AddCustomerNoteCommand represents a command to add a note to a customer
+type AddCustomerNoteCommand struct {
+	CustomerID      int64  `json:"customer_id" validate:"required"`
+	Author          string `json:"author" validate:"required"`
+	Body            string `json:"body" validate:"required"`
+	CustomerVisible bool   `json:"customer_visible"`
+}
+
+// HandleAddCustomerNote handles the add customer note command
+func (h *CustomerCommandHandler) HandleAddCustomerNote(ctx context.Context, cmd *AddCustomerNoteCommand) (*application.CustomerNoteDTO, error) {
+	cmd.Author = strings.TrimSpace(cmd.Author)
+	cmd.Body = strings.TrimSpace(cmd.Body)
+
+	// Validate command
+	if err := h.validator.Validate(cmd); err != nil {
+		return nil, errors.ValidationError("invalid add customer note command").WithInternal(err)
+	}
+
+	// Ensure the customer exists
+	if _, err := h.repo.FindByID(ctx, cmd.CustomerID); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeNotFound, "customer not found", http.StatusNotFound)
+	}
+
+	note := domain.NewCustomerNote(cmd.CustomerID, cmd.Author, cmd.Body, cmd.CustomerVisible)
+	if err := h.noteRepo.Create(ctx, note); err != nil {
+		h.logger.WithError(err).WithField("customer_id", cmd.CustomerID).Error("failed to add customer note")
+		return nil, errors.InternalWrap(err, "failed to add customer note")
+	}
+
+	return application.ToCustomerNoteDTO(note), nil
+}
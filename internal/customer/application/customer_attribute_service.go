@@ -0,0 +1,220 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// CustomerAttributeService defines the application service for the tags and typed
+// attributes CRM integrations keep on customers. Every change publishes a
+// customer.tags_changed event carrying the customer's current tags and attributes.
+type CustomerAttributeService interface {
+	// GetCustomerTags retrieves the tags and attributes of a customer.
+	GetCustomerTags(ctx context.Context, customerID int64) (*CustomerTagsDTO, error)
+
+	// SetAttributes creates or replaces attributes of a customer by name.
+	SetAttributes(ctx context.Context, customerID int64, req *SetCustomerAttributesRequest) (*CustomerTagsDTO, error)
+
+	// DeleteAttribute removes an attribute of a customer.
+	DeleteAttribute(ctx context.Context, customerID int64, name string) (*CustomerTagsDTO, error)
+
+	// AddTags tags a customer; tags it already carries are ignored.
+	AddTags(ctx context.Context, customerID int64, req *AddCustomerTagsRequest) (*CustomerTagsDTO, error)
+
+	// RemoveTag removes a tag from a customer.
+	RemoveTag(ctx context.Context, customerID int64, tag string) (*CustomerTagsDTO, error)
+
+	// ListTags lists every tag in use with the number of customers carrying it.
+	ListTags(ctx context.Context) ([]*CustomerTagCountDTO, error)
+}
+
+type customerAttributeService struct {
+	customerRepo  domain.CustomerRepository
+	attributeRepo domain.CustomerAttributeRepository
+	eventBus      event.Bus
+	logger        *logger.Logger
+}
+
+// NewCustomerAttributeService creates a new instance of CustomerAttributeService.
+func NewCustomerAttributeService(
+	customerRepo domain.CustomerRepository,
+	attributeRepo domain.CustomerAttributeRepository,
+	eventBus event.Bus,
+	log *logger.Logger,
+) CustomerAttributeService {
+	return &customerAttributeService{
+		customerRepo:  customerRepo,
+		attributeRepo: attributeRepo,
+		eventBus:      eventBus,
+		logger:        log,
+	}
+}
+
+func (s *customerAttributeService) GetCustomerTags(ctx context.Context, customerID int64) (*CustomerTagsDTO, error) {
+	if err := s.requireCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+	tags, attributes, err := s.load(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return toCustomerTagsDTO(customerID, tags, attributes), nil
+}
+
+func (s *customerAttributeService) SetAttributes(ctx context.Context, customerID int64, req *SetCustomerAttributesRequest) (*CustomerTagsDTO, error) {
+	if len(req.Attributes) == 0 {
+		return nil, errors.ValidationError("at least one attribute is required")
+	}
+	attributes := make([]*domain.CustomerAttribute, 0, len(req.Attributes))
+	names := make(map[string]bool, len(req.Attributes))
+	for _, input := range req.Attributes {
+		attribute, err := domain.NewCustomerAttribute(customerID, input.Name, input.Value, domain.AttributeType(input.Type))
+		if err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+		if names[attribute.Name] {
+			return nil, errors.ValidationError("attribute " + attribute.Name + " is given more than once")
+		}
+		names[attribute.Name] = true
+		attributes = append(attributes, attribute)
+	}
+
+	if err := s.requireCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+	if err := s.attributeRepo.SaveAttributes(ctx, customerID, attributes); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, customerID)
+}
+
+func (s *customerAttributeService) DeleteAttribute(ctx context.Context, customerID int64, name string) (*CustomerTagsDTO, error) {
+	if err := s.requireCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+	if err := s.attributeRepo.DeleteAttribute(ctx, customerID, name); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, customerID)
+}
+
+func (s *customerAttributeService) AddTags(ctx context.Context, customerID int64, req *AddCustomerTagsRequest) (*CustomerTagsDTO, error) {
+	if len(req.Tags) == 0 {
+		return nil, errors.ValidationError("at least one tag is required")
+	}
+	tags := make([]string, 0, len(req.Tags))
+	for _, input := range req.Tags {
+		tag, err := domain.NormalizeCustomerTag(input)
+		if err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := s.requireCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+	current, err := s.attributeRepo.FindTags(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[string]bool, len(current)+len(tags))
+	for _, tag := range current {
+		held[tag] = true
+	}
+	for _, tag := range tags {
+		held[tag] = true
+	}
+	if len(held) > domain.MaxCustomerTags {
+		return nil, errors.ValidationError(fmt.Sprintf("a customer can carry at most %d tags", domain.MaxCustomerTags))
+	}
+	if len(held) == len(current) {
+		return s.GetCustomerTags(ctx, customerID) // Nothing new, nothing to announce
+	}
+
+	if err := s.attributeRepo.AddTags(ctx, customerID, tags); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, customerID)
+}
+
+func (s *customerAttributeService) RemoveTag(ctx context.Context, customerID int64, tag string) (*CustomerTagsDTO, error) {
+	if err := s.requireCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+	normalized, err := domain.NormalizeCustomerTag(tag)
+	if err != nil {
+		return nil, errors.NotFound("customer tag")
+	}
+	if err := s.attributeRepo.RemoveTag(ctx, customerID, normalized); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, customerID)
+}
+
+func (s *customerAttributeService) ListTags(ctx context.Context) ([]*CustomerTagCountDTO, error) {
+	counts, err := s.attributeRepo.CountTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*CustomerTagCountDTO, len(counts))
+	for i, count := range counts {
+		dtos[i] = &CustomerTagCountDTO{Tag: count.Tag, Customers: count.Customers}
+	}
+	return dtos, nil
+}
+
+func (s *customerAttributeService) requireCustomer(ctx context.Context, customerID int64) error {
+	customer, err := s.customerRepo.FindByID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return errors.NotFound("customer")
+	}
+	return nil
+}
+
+func (s *customerAttributeService) load(ctx context.Context, customerID int64) ([]string, []*domain.CustomerAttribute, error) {
+	tags, err := s.attributeRepo.FindTags(ctx, customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attributes, err := s.attributeRepo.FindAttributes(ctx, customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tags, attributes, nil
+}
+
+// changed reloads a customer's tags and attributes after a change and announces them
+func (s *customerAttributeService) changed(ctx context.Context, customerID int64) (*CustomerTagsDTO, error) {
+	tags, attributes, err := s.load(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.eventBus.Publish(ctx, domain.NewCustomerTagsChangedEvent(customerID, tags, attributes)); err != nil {
+		s.logger.WithError(err).WithField("customer_id", customerID).Error("failed to publish customer tags changed event")
+	}
+	return toCustomerTagsDTO(customerID, tags, attributes), nil
+}
+
+func toCustomerTagsDTO(customerID int64, tags []string, attributes []*domain.CustomerAttribute) *CustomerTagsDTO {
+	dto := &CustomerTagsDTO{
+		CustomerID: customerID,
+		Tags:       tags,
+		Attributes: make([]*CustomerAttributeDTO, len(attributes)),
+	}
+	if dto.Tags == nil {
+		dto.Tags = []string{}
+	}
+	for i, attribute := range attributes {
+		dto.Attributes[i] = ToCustomerAttributeDTO(attribute)
+	}
+	return dto
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/export"
+)
+
+// NewCustomerExport describes a CSV export of the customers matching filter
+func NewCustomerExport(repo domain.CustomerExportRepository, filter *domain.CustomerExportFilter) export.Export {
+	return export.Export{
+		Kind:     "customers",
+		Filename: "customers-" + time.Now().Format("20060102-150405") + ".csv",
+		Header: []string{
+			"customer_id", "email", "first_name", "last_name", "username",
+			"registered", "deactivated", "archived", "receive_email", "locale", "created_at",
+		},
+		Rows: func(ctx context.Context, w export.RowWriter) error {
+			return repo.StreamCustomers(ctx, filter, func(row *domain.CustomerExportRow) error {
+				return w.Write([]string{
+					strconv.FormatInt(row.ID, 10),
+					row.EmailAddress,
+					row.FirstName,
+					row.LastName,
+					row.UserName,
+					strconv.FormatBool(row.IsRegistered),
+					strconv.FormatBool(row.Deactivated),
+					strconv.FormatBool(row.Archived),
+					strconv.FormatBool(row.ReceiveEmail),
+					row.LocaleCode,
+					row.CreatedAt.Format(time.RFC3339),
+				})
+			})
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package application
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+)
+
+// importReader reads the records of a customer import file in order. Next
+// returns io.EOF after the last record, and a *recordError for a record that
+// cannot be parsed but can be skipped; any other error ends the import.
+type importReader interface {
+	Next() (*domain.CustomerImportRecord, error)
+}
+
+// recordError is a record of an import file that cannot be parsed
+type recordError struct {
+	row int64
+	err error
+}
+
+func (e *recordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.row, e.err)
+}
+
+func newImportReader(format domain.ImportFormat, r io.Reader) (importReader, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return newCSVImportReader(r)
+	case domain.ImportFormatJSON:
+		return newJSONImportReader(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// csvImportReader reads one customer per line. Columns are named as the JSON
+// fields of domain.CustomerImportRecord; a customer's single address is read
+// from the address_ columns, e.g. address_line1, address_city, address_phone.
+type csvImportReader struct {
+	csv     *csv.Reader
+	columns map[string]int
+	row     int64
+}
+
+func newCSVImportReader(r io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheets save a byte order mark before the first column name
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	if _, ok := columns["email_address"]; !ok {
+		return nil, fmt.Errorf("the CSV header has no email_address column")
+	}
+	return &csvImportReader{csv: reader, columns: columns}, nil
+}
+
+func (r *csvImportReader) Next() (*domain.CustomerImportRecord, error) {
+	fields, err := r.csv.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	r.row++
+	if err != nil {
+		// A malformed line is reported; the reader continues with the next one
+		if _, ok := err.(*csv.ParseError); ok {
+			return nil, &recordError{row: r.row, err: err}
+		}
+		return nil, err
+	}
+
+	get := func(column string) string {
+		if i, ok := r.columns[column]; ok && i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+
+	record := &domain.CustomerImportRecord{
+		Row:                  r.row,
+		EmailAddress:         get("email_address"),
+		UserName:             get("user_name"),
+		FirstName:            get("first_name"),
+		LastName:             get("last_name"),
+		ExternalID:           get("external_id"),
+		LocaleCode:           get("locale_code"),
+		PasswordHash:         get("password_hash"),
+		PasswordAlgorithm:    get("password_algorithm"),
+		PasswordSalt:         get("password_salt"),
+		PasswordSaltPosition: get("password_salt_position"),
+		PasswordEncoding:     get("password_encoding"),
+	}
+	if value := get("receive_email"); value != "" {
+		receiveEmail, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, &recordError{row: r.row, err: fmt.Errorf("invalid receive_email %q", value)}
+		}
+		record.ReceiveEmail = &receiveEmail
+	}
+
+	address := &domain.CustomerImportAddress{
+		AddressName:         get("address_name"),
+		FirstName:           get("address_first_name"),
+		LastName:            get("address_last_name"),
+		CompanyName:         get("address_company_name"),
+		AddressLine1:        get("address_line1"),
+		AddressLine2:        get("address_line2"),
+		City:                get("address_city"),
+		StateProvinceRegion: get("address_state_province_region"),
+		PostalCode:          get("address_postal_code"),
+		CountryCode:         get("address_country_code"),
+		PrimaryPhone:        get("address_phone"),
+	}
+	if address.AddressLine1 != "" || address.City != "" || address.PostalCode != "" || address.CountryCode != "" {
+		record.Addresses = []*domain.CustomerImportAddress{address}
+	}
+	return record, nil
+}
+
+// jsonImportReader reads a JSON array of customer objects one element at a
+// time, so large files are never held in memory
+type jsonImportReader struct {
+	decoder *json.Decoder
+	row     int64
+}
+
+func newJSONImportReader(r io.Reader) (*jsonImportReader, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the JSON file: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("the JSON file must contain an array of customers")
+	}
+	return &jsonImportReader{decoder: decoder}, nil
+}
+
+func (r *jsonImportReader) Next() (*domain.CustomerImportRecord, error) {
+	if !r.decoder.More() {
+		return nil, io.EOF
+	}
+	r.row++
+
+	record := &domain.CustomerImportRecord{}
+	if err := r.decoder.Decode(record); err != nil {
+		// A field of the wrong type leaves the decoder after the element, so the
+		// element is reported and the next one read; broken JSON ends the import
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+			return nil, &recordError{row: r.row, err: fmt.Errorf("invalid %s: expected %s", typeErr.Field, typeErr.Type)}
+		}
+		return nil, fmt.Errorf("failed to read record %d of the JSON file: %w", r.row, err)
+	}
+	record.Row = r.row
+	return record, nil
+}
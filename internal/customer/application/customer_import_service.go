@@ -0,0 +1,647 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/auth"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/export"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// CustomerImportConfig holds customer import settings
+type CustomerImportConfig struct {
+	Dir       string        // Directory uploaded files are kept in until their import completes
+	BatchSize int           // Records committed at a time; an interrupted import resumes after the last batch
+	Lease     time.Duration // How long a job stays with its worker without progress before another may take it
+}
+
+// CustomerImportService defines the application service for importing
+// customers migrated from other platforms. Imports run in the background, one
+// at a time per instance, and resume where they stopped after a restart.
+type CustomerImportService interface {
+	// StartImport stores an uploaded file and queues its import.
+	StartImport(ctx context.Context, req *StartCustomerImportRequest, file io.Reader, requestedBy string) (*CustomerImportJobDTO, error)
+
+	// GetImport retrieves an import with its progress.
+	GetImport(ctx context.Context, id int64) (*CustomerImportJobDTO, error)
+
+	// ListImports lists imports, newest first.
+	ListImports(ctx context.Context, page, pageSize int) (*PaginatedResponse, error)
+
+	// ResumeImport queues a failed import again; it continues after the last committed batch.
+	ResumeImport(ctx context.Context, id int64) (*CustomerImportJobDTO, error)
+
+	// ErrorReport describes the CSV of the records an import could not import.
+	ErrorReport(ctx context.Context, id int64) (export.Export, error)
+
+	// StartWorker runs queued and interrupted imports until ctx is cancelled.
+	StartWorker(ctx context.Context, interval time.Duration)
+}
+
+type customerImportService struct {
+	importRepo   domain.CustomerImportRepository
+	customerRepo domain.CustomerRepository
+	addressRepo  domain.AddressRepository
+	eventBus     event.Bus
+	cfg          CustomerImportConfig
+	logger       *logger.Logger
+}
+
+// NewCustomerImportService creates a new instance of CustomerImportService.
+func NewCustomerImportService(
+	importRepo domain.CustomerImportRepository,
+	customerRepo domain.CustomerRepository,
+	addressRepo domain.AddressRepository,
+	eventBus event.Bus,
+	cfg CustomerImportConfig,
+	log *logger.Logger,
+) (CustomerImportService, error) {
+	if cfg.Dir == "" {
+		cfg.Dir = filepath.Join(os.TempDir(), "customer-imports")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 2 * time.Minute
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create customer import directory: %w", err)
+	}
+	return &customerImportService{
+		importRepo:   importRepo,
+		customerRepo: customerRepo,
+		addressRepo:  addressRepo,
+		eventBus:     eventBus,
+		cfg:          cfg,
+		logger:       log,
+	}, nil
+}
+
+func (s *customerImportService) StartImport(ctx context.Context, req *StartCustomerImportRequest, file io.Reader, requestedBy string) (*CustomerImportJobDTO, error) {
+	format := domain.ImportFormat(strings.ToUpper(req.Format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(req.Filename)) {
+		case ".csv":
+			format = domain.ImportFormatCSV
+		case ".json":
+			format = domain.ImportFormatJSON
+		default:
+			return nil, errors.BadRequest("format is required unless the file ends in .csv or .json")
+		}
+	}
+	strategy := domain.DuplicateStrategy(strings.ToUpper(req.DuplicateStrategy))
+	if strategy == "" {
+		strategy = domain.DuplicateSkip
+	}
+
+	path := filepath.Join(s.cfg.Dir, uuid.New().String()+"."+strings.ToLower(string(format)))
+	job, err := domain.NewCustomerImportJob(filepath.Base(req.Filename), path, format, strategy, requestedBy)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if err := saveImportFile(path, file); err != nil {
+		return nil, errors.InternalWrap(err, "failed to store import file")
+	}
+	// A file whose header cannot be read is rejected now rather than failing in the background
+	if err := checkImportFile(path, format); err != nil {
+		_ = os.Remove(path)
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if err := s.importRepo.Create(ctx, job); err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	s.logger.WithFields(logger.Fields{
+		"import_id": job.ID,
+		"filename":  job.Filename,
+		"format":    job.Format,
+		"strategy":  job.DuplicateStrategy,
+	}).Info("Customer import queued")
+	return ToCustomerImportJobDTO(job), nil
+}
+
+func (s *customerImportService) GetImport(ctx context.Context, id int64) (*CustomerImportJobDTO, error) {
+	job, err := s.importRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToCustomerImportJobDTO(job), nil
+}
+
+func (s *customerImportService) ListImports(ctx context.Context, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	jobs, total, err := s.importRepo.FindAll(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*CustomerImportJobDTO, len(jobs))
+	for i, job := range jobs {
+		dtos[i] = ToCustomerImportJobDTO(job)
+	}
+	return NewPaginatedResponse(dtos, page, pageSize, total), nil
+}
+
+func (s *customerImportService) ResumeImport(ctx context.Context, id int64) (*CustomerImportJobDTO, error) {
+	job, err := s.importRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := job.Resume(); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if _, err := os.Stat(job.FilePath); err != nil {
+		return nil, errors.Conflict(fmt.Sprintf("the file of customer import %d is no longer available", id))
+	}
+	if err := s.importRepo.Resume(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("import_id", job.ID).WithField("cursor", job.Cursor).Info("Customer import resumed")
+	return ToCustomerImportJobDTO(job), nil
+}
+
+func (s *customerImportService) ErrorReport(ctx context.Context, id int64) (export.Export, error) {
+	job, err := s.importRepo.FindByID(ctx, id)
+	if err != nil {
+		return export.Export{}, err
+	}
+	return export.Export{
+		Kind:     "customer_import_errors",
+		Filename: fmt.Sprintf("customer-import-%d-errors.csv", job.ID),
+		Header:   []string{"record", "email_address", "error"},
+		Rows: func(ctx context.Context, w export.RowWriter) error {
+			return s.importRepo.StreamErrors(ctx, job.ID, func(e *domain.CustomerImportError) error {
+				return w.Write([]string{strconv.FormatInt(e.Row, 10), e.EmailAddress, e.Message})
+			})
+		},
+	}, nil
+}
+
+func (s *customerImportService) StartWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Drain the queue before waiting for the next tick
+				for ctx.Err() == nil {
+					job, err := s.importRepo.Claim(ctx, s.cfg.Lease)
+					if err != nil {
+						s.logger.WithError(err).Warn("Failed to claim customer import")
+						break
+					}
+					if job == nil {
+						break
+					}
+					s.run(ctx, job)
+				}
+			}
+		}
+	}()
+}
+
+// importOutcome is what happened to an imported record
+type importOutcome int
+
+const (
+	outcomeImported importOutcome = iota
+	outcomeUpdated
+	outcomeSkipped
+)
+
+// run imports a claimed job from its cursor, committing progress and errors
+// one batch at a time. A job stopped by shutdown keeps its lease and is
+// claimed again, by any instance, once the lease expires.
+func (s *customerImportService) run(ctx context.Context, job *domain.CustomerImportJob) {
+	log := s.logger.WithField("import_id", job.ID)
+	log.WithField("cursor", job.Cursor).WithField("attempt", job.Attempts).Info("Running customer import")
+
+	f, err := os.Open(job.FilePath)
+	if err != nil {
+		s.fail(ctx, job, fmt.Errorf("failed to open import file: %w", err))
+		return
+	}
+	defer f.Close()
+
+	reader, err := newImportReader(job.Format, bufio.NewReader(f))
+	if err != nil {
+		s.fail(ctx, job, err)
+		return
+	}
+	// Records before the cursor were committed by an earlier attempt
+	for i := int64(0); i < job.Cursor; i++ {
+		if _, err := reader.Next(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			if _, ok := err.(*recordError); !ok {
+				s.fail(ctx, job, err)
+				return
+			}
+		}
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		done, err := s.runBatch(ctx, job, reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.IsConflict(err) {
+				log.WithError(err).Warn("Customer import taken over by another worker")
+				return
+			}
+			s.fail(ctx, job, err)
+			return
+		}
+		if done {
+			break
+		}
+	}
+
+	if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).Warn("Failed to remove customer import file")
+	}
+	log.WithFields(logger.Fields{
+		"imported": job.Imported,
+		"updated":  job.Updated,
+		"skipped":  job.Skipped,
+		"failed":   job.Failed,
+	}).Info("Customer import completed")
+}
+
+// runBatch imports up to a batch of records and commits them with their
+// errors. An error that is not about a record, such as the database being
+// unavailable, stops the import before the batch is committed; the records
+// imported so far are recognized when the batch is run again.
+func (s *customerImportService) runBatch(ctx context.Context, job *domain.CustomerImportJob, reader importReader) (bool, error) {
+	var (
+		records, imported, updated, skipped int64
+		importErrors                        []*domain.CustomerImportError
+		done                                bool
+	)
+	for records < int64(s.cfg.BatchSize) {
+		record, err := reader.Next()
+		if err == io.EOF {
+			done = true
+			break
+		}
+		if err != nil {
+			if recErr, ok := err.(*recordError); ok {
+				records++
+				importErrors = append(importErrors, newImportError(job.ID, recErr.row, "", recErr.err.Error()))
+				continue
+			}
+			return false, err
+		}
+		records++
+
+		outcome, err := s.importRecord(ctx, job, record)
+		if err != nil {
+			if errors.GetStatusCode(err) >= 500 {
+				return false, err
+			}
+			importErrors = append(importErrors, newImportError(job.ID, record.Row, record.EmailAddress, importErrorMessage(err)))
+			continue
+		}
+		switch outcome {
+		case outcomeImported:
+			imported++
+		case outcomeUpdated:
+			updated++
+		case outcomeSkipped:
+			skipped++
+		}
+	}
+
+	job.Advance(records, imported, updated, skipped, int64(len(importErrors)))
+	if done {
+		job.Complete()
+	}
+	if err := s.importRepo.SaveProgress(context.WithoutCancel(ctx), job, importErrors, s.cfg.Lease); err != nil {
+		return false, err
+	}
+	return done, nil
+}
+
+// importRecord creates the customer of a record, or applies the job's
+// duplicate strategy when its email is already registered
+func (s *customerImportService) importRecord(ctx context.Context, job *domain.CustomerImportJob, record *domain.CustomerImportRecord) (importOutcome, error) {
+	email := strings.TrimSpace(record.EmailAddress)
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return 0, errors.ValidationError("invalid email address")
+	}
+	password, err := importedPassword(record)
+	if err != nil {
+		return 0, errors.ValidationError(err.Error())
+	}
+	addresses, err := importedAddresses(record.Addresses)
+	if err != nil {
+		return 0, errors.ValidationError(err.Error())
+	}
+	// Customers without an external ID are tagged with the job and record so
+	// a batch run again after an interruption recognizes what it imported
+	externalID := record.ExternalID
+	if externalID == "" {
+		externalID = fmt.Sprintf("import:%d:%d", job.ID, record.Row)
+	}
+
+	existing, err := s.customerRepo.FindByEmail(ctx, email)
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, err
+	}
+	if existing != nil {
+		if existing.ExternalID == externalID && !existing.CreatedAt.Before(job.CreatedAt) {
+			return outcomeImported, nil
+		}
+		switch job.DuplicateStrategy {
+		case domain.DuplicateSkip:
+			return outcomeSkipped, nil
+		case domain.DuplicateReject:
+			return 0, errors.Conflict("email address already registered")
+		}
+		return outcomeUpdated, s.updateCustomer(ctx, existing, record, password, addresses)
+	}
+
+	userName := record.UserName
+	if userName == "" {
+		userName = email
+	}
+	taken, err := s.customerRepo.ExistsByUsername(ctx, userName)
+	if err != nil {
+		return 0, err
+	}
+	if taken {
+		return 0, errors.Conflict("user name already taken")
+	}
+
+	customer := domain.NewCustomer(email, userName, password, record.FirstName, record.LastName)
+	customer.ExternalID = externalID
+	customer.LocaleCode = record.LocaleCode
+	if record.ReceiveEmail != nil {
+		customer.ReceiveEmail = *record.ReceiveEmail
+	}
+	// Customers imported without a password set one before signing in
+	customer.PasswordChangeRequired = password == ""
+	if err := s.customerRepo.Create(ctx, customer); err != nil {
+		return 0, err
+	}
+	if err := s.addAddresses(ctx, customer.ID, nil, addresses); err != nil {
+		return 0, err
+	}
+
+	evt := domain.NewCustomerRegisteredEvent(customer.ID, customer.EmailAddress, customer.UserName, customer.FirstName, customer.LastName)
+	if err := s.eventBus.Publish(ctx, evt); err != nil {
+		s.logger.WithError(err).Error("failed to publish customer registered event")
+	}
+	return outcomeImported, nil
+}
+
+// updateCustomer applies the non-empty fields of a record to an existing
+// customer and adds the addresses it does not have yet
+func (s *customerImportService) updateCustomer(ctx context.Context, customer *domain.Customer, record *domain.CustomerImportRecord, password string, addresses []*importedAddress) error {
+	changes := make(map[string]interface{})
+	if record.FirstName != "" && record.FirstName != customer.FirstName {
+		customer.FirstName = record.FirstName
+		changes["first_name"] = record.FirstName
+	}
+	if record.LastName != "" && record.LastName != customer.LastName {
+		customer.LastName = record.LastName
+		changes["last_name"] = record.LastName
+	}
+	if record.LocaleCode != "" && record.LocaleCode != customer.LocaleCode {
+		customer.LocaleCode = record.LocaleCode
+		changes["locale_code"] = record.LocaleCode
+	}
+	if record.ReceiveEmail != nil && *record.ReceiveEmail != customer.ReceiveEmail {
+		customer.ReceiveEmail = *record.ReceiveEmail
+		changes["receive_email"] = customer.ReceiveEmail
+	}
+	if customer.ExternalID == "" && record.ExternalID != "" {
+		customer.ExternalID = record.ExternalID
+		changes["external_id"] = record.ExternalID
+	}
+	if password != "" {
+		customer.Password = password
+		customer.PasswordChangeRequired = false
+		changes["password"] = "imported"
+	}
+
+	if len(changes) > 0 {
+		customer.UpdatedAt = time.Now()
+		if err := s.customerRepo.Update(ctx, customer); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.addressRepo.FindByCustomerID(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.addAddresses(ctx, customer.ID, existing, addresses); err != nil {
+		return err
+	}
+
+	if len(changes) > 0 {
+		if err := s.eventBus.Publish(ctx, domain.NewCustomerUpdatedEvent(customer.ID, changes)); err != nil {
+			s.logger.WithError(err).Error("failed to publish customer updated event")
+		}
+	}
+	return nil
+}
+
+// addAddresses adds the addresses that are not already in the address book
+func (s *customerImportService) addAddresses(ctx context.Context, customerID int64, existing []*domain.CustomerAddress, addresses []*importedAddress) error {
+	known := make(map[string]bool, len(existing))
+	for _, ca := range existing {
+		if ca.Address != nil {
+			known[addressKey(ca.Address)] = true
+		}
+	}
+	for _, address := range addresses {
+		if known[addressKey(address.Address)] {
+			continue
+		}
+		customerAddress := &domain.CustomerAddress{
+			AddressName: address.name,
+			CustomerID:  customerID,
+			Address:     address.Address,
+		}
+		if err := s.addressRepo.CreateCustomerAddress(ctx, customerAddress); err != nil {
+			return err
+		}
+		known[addressKey(address.Address)] = true
+	}
+	return nil
+}
+
+func (s *customerImportService) fail(ctx context.Context, job *domain.CustomerImportJob, err error) {
+	job.Fail(err)
+	log := s.logger.WithField("import_id", job.ID)
+	log.WithError(err).Error("Customer import failed")
+	if err := s.importRepo.SaveProgress(context.WithoutCancel(ctx), job, nil, s.cfg.Lease); err != nil {
+		log.WithError(err).Error("Failed to save failed customer import")
+	}
+}
+
+// importedPassword converts the password of a record to the hash stored on
+// the customer: bcrypt hashes as they are, salted SHA hashes in their legacy
+// form until the customer next signs in
+func importedPassword(record *domain.CustomerImportRecord) (string, error) {
+	if record.PasswordHash == "" {
+		return "", nil
+	}
+	algorithm := strings.ToLower(record.PasswordAlgorithm)
+	if algorithm == "" && auth.IsBcryptHash(record.PasswordHash) {
+		algorithm = "bcrypt"
+	}
+	if algorithm == "bcrypt" {
+		if !auth.IsBcryptHash(record.PasswordHash) {
+			return "", fmt.Errorf("password_hash is not a bcrypt hash")
+		}
+		return record.PasswordHash, nil
+	}
+	if algorithm == "" {
+		return "", fmt.Errorf("password_algorithm is required unless password_hash is a bcrypt hash")
+	}
+	return auth.EncodeLegacyHash(auth.LegacyHash{
+		Algorithm:    algorithm,
+		Salt:         record.PasswordSalt,
+		SaltPosition: strings.ToLower(record.PasswordSaltPosition),
+		Encoding:     strings.ToLower(record.PasswordEncoding),
+		Digest:       record.PasswordHash,
+	})
+}
+
+// importedAddress is a validated address of an imported record
+type importedAddress struct {
+	name string
+	*domain.Address
+}
+
+// importedAddresses validates the addresses of a record and names the unnamed ones
+func importedAddresses(addresses []*domain.CustomerImportAddress) ([]*importedAddress, error) {
+	result := make([]*importedAddress, 0, len(addresses))
+	for i, a := range addresses {
+		if a == nil {
+			continue
+		}
+		if strings.TrimSpace(a.AddressLine1) == "" || strings.TrimSpace(a.City) == "" {
+			return nil, fmt.Errorf("address %d: address_line1 and city are required", i+1)
+		}
+		country := strings.ToUpper(strings.TrimSpace(a.CountryCode))
+		if len(country) != 2 {
+			return nil, fmt.Errorf("address %d: country_code must be an ISO 3166-1 alpha-2 code", i+1)
+		}
+		name := strings.TrimSpace(a.AddressName)
+		if name == "" {
+			name = fmt.Sprintf("Address %d", i+1)
+		}
+		result = append(result, &importedAddress{
+			name: name,
+			Address: &domain.Address{
+				AddressLine1:        strings.TrimSpace(a.AddressLine1),
+				AddressLine2:        strings.TrimSpace(a.AddressLine2),
+				City:                strings.TrimSpace(a.City),
+				CompanyName:         a.CompanyName,
+				FirstName:           a.FirstName,
+				LastName:            a.LastName,
+				PrimaryPhone:        a.PrimaryPhone,
+				PostalCode:          strings.TrimSpace(a.PostalCode),
+				StateProvinceRegion: a.StateProvinceRegion,
+				CountryCode:         country,
+				IsoCountryAlpha2:    country,
+			},
+		})
+	}
+	return result, nil
+}
+
+// addressKey identifies an address regardless of case and spacing
+func addressKey(a *domain.Address) string {
+	normalize := func(value string) string {
+		return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	}
+	return normalize(a.AddressLine1) + "|" + normalize(a.PostalCode) + "|" + normalize(a.CountryCode)
+}
+
+// importErrorMessage is the message of a record error shown in the error report
+func importErrorMessage(err error) string {
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return err.Error()
+}
+
+func newImportError(jobID, row int64, email, message string) *domain.CustomerImportError {
+	return &domain.CustomerImportError{
+		JobID:        jobID,
+		Row:          row,
+		EmailAddress: email,
+		Message:      message,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// saveImportFile writes an uploaded file to a temporary name and moves it into place once complete
+func saveImportFile(path string, file io.Reader) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	if _, err := io.Copy(f, file); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// checkImportFile reads the header of an import file
+func checkImportFile(path string, format domain.ImportFormat) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = newImportReader(format, bufio.NewReader(f))
+	return err
+}
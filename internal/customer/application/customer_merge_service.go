@@ -0,0 +1,292 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/audit"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const customerEntityType = "Customer"
+
+// CustomerMergeService defines the application service for duplicate account
+// detection and merges. Suspected duplicates are queued for admin review;
+// merging one account into another moves its orders, addresses, reviews and
+// the rest of what it owns to the surviving account and archives it.
+type CustomerMergeService interface {
+	// ScanDuplicates queues the pairs of accounts that look like the same person.
+	ScanDuplicates(ctx context.Context) (*DuplicateScanResultDTO, error)
+
+	// ListDuplicates lists the review queue, highest score first. An empty
+	// status lists every status; a customer ID lists only its pairs.
+	ListDuplicates(ctx context.Context, status string, customerID int64, page, pageSize int) (*PaginatedResponse, error)
+
+	// GetDuplicate retrieves a suspected duplicate with both accounts.
+	GetDuplicate(ctx context.Context, id int64) (*CustomerDuplicateDTO, error)
+
+	// DismissDuplicate records that the accounts belong to different people.
+	DismissDuplicate(ctx context.Context, id int64, actorID string) (*CustomerDuplicateDTO, error)
+
+	// MergeDuplicate merges the other account of a pending pair into the one kept.
+	MergeDuplicate(ctx context.Context, id int64, req *MergeDuplicateRequest, actorID string) (*CustomerMergeDTO, error)
+
+	// MergeCustomers merges an account into the surviving customer, whether
+	// or not the pair was detected.
+	MergeCustomers(ctx context.Context, survivingCustomerID int64, req *MergeCustomerRequest, actorID string) (*CustomerMergeDTO, error)
+
+	// ListMerges lists the merges into or of a customer, newest first.
+	ListMerges(ctx context.Context, customerID int64) ([]*CustomerMergeDTO, error)
+
+	// StartScheduledScan scans for duplicates periodically until ctx is cancelled.
+	StartScheduledScan(ctx context.Context, interval time.Duration)
+}
+
+type customerMergeService struct {
+	customerRepo  domain.CustomerRepository
+	duplicateRepo domain.CustomerDuplicateRepository
+	auditService  *audit.AuditService
+	eventBus      event.Bus
+	logger        *logger.Logger
+	scanMu        sync.Mutex
+}
+
+// NewCustomerMergeService creates a new instance of CustomerMergeService.
+func NewCustomerMergeService(
+	customerRepo domain.CustomerRepository,
+	duplicateRepo domain.CustomerDuplicateRepository,
+	auditService *audit.AuditService,
+	eventBus event.Bus,
+	log *logger.Logger,
+) CustomerMergeService {
+	return &customerMergeService{
+		customerRepo:  customerRepo,
+		duplicateRepo: duplicateRepo,
+		auditService:  auditService,
+		eventBus:      eventBus,
+		logger:        log,
+	}
+}
+
+func (s *customerMergeService) ScanDuplicates(ctx context.Context) (*DuplicateScanResultDTO, error) {
+	if !s.scanMu.TryLock() {
+		return nil, errors.Conflict("a duplicate customer scan is already running")
+	}
+	defer s.scanMu.Unlock()
+
+	queued, err := s.duplicateRepo.Detect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if queued > 0 {
+		s.logger.WithField("queued", queued).Info("Duplicate customers queued for review")
+	}
+	return &DuplicateScanResultDTO{Queued: queued, ScannedAt: time.Now()}, nil
+}
+
+func (s *customerMergeService) ListDuplicates(ctx context.Context, status string, customerID int64, page, pageSize int) (*PaginatedResponse, error) {
+	filter := &domain.DuplicateFilter{
+		Status:     domain.DuplicateStatus(status),
+		CustomerID: customerID,
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	switch filter.Status {
+	case "", domain.DuplicateStatusPending, domain.DuplicateStatusDismissed, domain.DuplicateStatusMerged:
+	default:
+		return nil, errors.ValidationError("status must be PENDING, DISMISSED or MERGED")
+	}
+
+	duplicates, total, err := s.duplicateRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*CustomerDuplicateDTO, len(duplicates))
+	for i, duplicate := range duplicates {
+		if dtos[i], err = s.toDuplicateDTO(ctx, duplicate); err != nil {
+			return nil, err
+		}
+	}
+	return NewPaginatedResponse(dtos, page, pageSize, total), nil
+}
+
+func (s *customerMergeService) GetDuplicate(ctx context.Context, id int64) (*CustomerDuplicateDTO, error) {
+	duplicate, err := s.duplicateRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toDuplicateDTO(ctx, duplicate)
+}
+
+func (s *customerMergeService) DismissDuplicate(ctx context.Context, id int64, actorID string) (*CustomerDuplicateDTO, error) {
+	duplicate, err := s.duplicateRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := duplicate.Dismiss(actorID); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.duplicateRepo.UpdateStatus(ctx, duplicate); err != nil {
+		return nil, err
+	}
+	return s.toDuplicateDTO(ctx, duplicate)
+}
+
+func (s *customerMergeService) MergeDuplicate(ctx context.Context, id int64, req *MergeDuplicateRequest, actorID string) (*CustomerMergeDTO, error) {
+	duplicate, err := s.duplicateRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate.Status != domain.DuplicateStatusPending {
+		return nil, errors.Conflict(fmt.Sprintf("duplicate %d is already %s", id, duplicate.Status))
+	}
+	if !duplicate.Involves(req.SurvivingCustomerID) {
+		return nil, errors.ValidationError("surviving_customer_id must be one of the pair")
+	}
+	mergedID := duplicate.CustomerID
+	if mergedID == req.SurvivingCustomerID {
+		mergedID = duplicate.OtherCustomerID
+	}
+	return s.merge(ctx, req.SurvivingCustomerID, mergedID, actorID)
+}
+
+func (s *customerMergeService) MergeCustomers(ctx context.Context, survivingCustomerID int64, req *MergeCustomerRequest, actorID string) (*CustomerMergeDTO, error) {
+	if req.MergedCustomerID == 0 {
+		return nil, errors.ValidationError("merged_customer_id is required")
+	}
+	return s.merge(ctx, survivingCustomerID, req.MergedCustomerID, actorID)
+}
+
+func (s *customerMergeService) ListMerges(ctx context.Context, customerID int64) ([]*CustomerMergeDTO, error) {
+	merges, err := s.duplicateRepo.FindMerges(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*CustomerMergeDTO, len(merges))
+	for i, merge := range merges {
+		dtos[i] = ToCustomerMergeDTO(merge)
+	}
+	return dtos, nil
+}
+
+func (s *customerMergeService) StartScheduledScan(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.ScanDuplicates(ctx); err != nil {
+					s.logger.WithError(err).Warn("Scheduled duplicate customer scan failed")
+				}
+			}
+		}
+	}()
+}
+
+// merge moves what the merged account owns to the surviving one, then
+// records the merge in the audit trail of both accounts and announces it
+func (s *customerMergeService) merge(ctx context.Context, survivingID, mergedID int64, actorID string) (*CustomerMergeDTO, error) {
+	surviving, err := s.requireActiveCustomer(ctx, survivingID)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := s.requireActiveCustomer(ctx, mergedID)
+	if err != nil {
+		return nil, err
+	}
+	merge, err := domain.NewCustomerMerge(surviving.ID, merged.ID, merged.EmailAddress, actorID)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.duplicateRepo.Merge(ctx, merge); err != nil {
+		return nil, err
+	}
+
+	var userID *string
+	if actorID != "" {
+		userID = &actorID
+	}
+	metadata := map[string]interface{}{
+		"merge_id":              merge.ID,
+		"surviving_customer_id": merge.SurvivingCustomerID,
+		"merged_customer_id":    merge.MergedCustomerID,
+		"merged_email":          merge.MergedEmail,
+		"moved":                 merge.Moved,
+	}
+	for _, id := range []int64{merge.SurvivingCustomerID, merge.MergedCustomerID} {
+		if err := s.auditService.LogCustomAction(ctx, audit.AuditActionMerge, customerEntityType, strconv.FormatInt(id, 10), userID, metadata); err != nil {
+			s.logger.WithError(err).WithField("customer_id", id).Warn("failed to record customer merge")
+		}
+	}
+	if err := s.eventBus.Publish(ctx, domain.NewCustomerMergedEvent(merge)); err != nil {
+		s.logger.WithError(err).WithField("customer_id", merge.SurvivingCustomerID).Error("failed to publish customer merged event")
+	}
+
+	s.logger.WithFields(logger.Fields{
+		"surviving_customer_id": merge.SurvivingCustomerID,
+		"merged_customer_id":    merge.MergedCustomerID,
+		"merged_by":             actorID,
+	}).Info("Customers merged")
+	return ToCustomerMergeDTO(merge), nil
+}
+
+func (s *customerMergeService) requireActiveCustomer(ctx context.Context, customerID int64) (*domain.Customer, error) {
+	customer, err := s.customerRepo.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, errors.NotFound(fmt.Sprintf("customer %d", customerID))
+	}
+	if customer.Archived {
+		return nil, errors.Conflict(fmt.Sprintf("customer %d is archived", customerID))
+	}
+	return customer, nil
+}
+
+func (s *customerMergeService) toDuplicateDTO(ctx context.Context, duplicate *domain.CustomerDuplicate) (*CustomerDuplicateDTO, error) {
+	dto := &CustomerDuplicateDTO{
+		ID:         duplicate.ID,
+		Reasons:    make([]string, len(duplicate.Reasons)),
+		Score:      duplicate.Score,
+		Status:     string(duplicate.Status),
+		DetectedAt: duplicate.DetectedAt,
+		ReviewedBy: duplicate.ReviewedBy,
+		ReviewedAt: duplicate.ReviewedAt,
+	}
+	for i, reason := range duplicate.Reasons {
+		dto.Reasons[i] = string(reason)
+	}
+	var err error
+	if dto.Customer, err = s.customerSummary(ctx, duplicate.CustomerID); err != nil {
+		return nil, err
+	}
+	if dto.OtherCustomer, err = s.customerSummary(ctx, duplicate.OtherCustomerID); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// customerSummary loads an account of a pair; one removed since is shown by ID only
+func (s *customerMergeService) customerSummary(ctx context.Context, customerID int64) (*CustomerDTO, error) {
+	customer, err := s.customerRepo.FindByID(ctx, customerID)
+	if errors.IsNotFound(err) || (err == nil && customer == nil) {
+		return &CustomerDTO{ID: customerID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ToCustomerDTO(customer), nil
+}
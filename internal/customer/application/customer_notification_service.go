@@ -0,0 +1,129 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/notification"
+)
+
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+	maxNotificationsMarkedRead  = 500 // IDs accepted by one mark-read request
+)
+
+// CustomerNotificationService defines the application service for customers'
+// storefront inboxes. Messages are delivered by the notification pipeline
+// through InboxSender, the in-app channel next to email and SMS.
+type CustomerNotificationService interface {
+	// List returns a page of a customer's inbox, newest first.
+	List(ctx context.Context, customerID int64, filter domain.CustomerNotificationFilter) (*CustomerNotificationListDTO, error)
+
+	// MarkRead marks messages of a customer as read, returning how many were unread.
+	MarkRead(ctx context.Context, customerID int64, req *MarkNotificationsReadRequest) (int64, error)
+
+	// MarkAllRead marks the whole inbox of a customer as read, returning how many were unread.
+	MarkAllRead(ctx context.Context, customerID int64) (int64, error)
+
+	// Deliver stores a message in a customer's inbox.
+	Deliver(ctx context.Context, customerID int64, category, title, body, link string) (*CustomerNotificationDTO, error)
+}
+
+type customerNotificationService struct {
+	repo   domain.CustomerNotificationRepository
+	logger *logger.Logger
+}
+
+// NewCustomerNotificationService creates a new instance of CustomerNotificationService.
+func NewCustomerNotificationService(repo domain.CustomerNotificationRepository, log *logger.Logger) CustomerNotificationService {
+	return &customerNotificationService{repo: repo, logger: log}
+}
+
+func (s *customerNotificationService) List(ctx context.Context, customerID int64, filter domain.CustomerNotificationFilter) (*CustomerNotificationListDTO, error) {
+	if filter.Category != "" && !domain.IsNotificationCategory(filter.Category) {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown notification category %q", filter.Category))
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultNotificationPageSize
+	}
+	if filter.Limit > maxNotificationPageSize {
+		filter.Limit = maxNotificationPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	notifications, total, err := s.repo.FindByCustomerID(ctx, customerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*CustomerNotificationDTO, len(notifications))
+	for i, n := range notifications {
+		dtos[i] = ToCustomerNotificationDTO(n)
+	}
+	return &CustomerNotificationListDTO{Notifications: dtos, Total: total, Unread: unread}, nil
+}
+
+func (s *customerNotificationService) MarkRead(ctx context.Context, customerID int64, req *MarkNotificationsReadRequest) (int64, error) {
+	if len(req.IDs) == 0 {
+		return 0, errors.ValidationError("at least one notification ID is required")
+	}
+	if len(req.IDs) > maxNotificationsMarkedRead {
+		return 0, errors.ValidationError(fmt.Sprintf("at most %d notifications can be marked read at once", maxNotificationsMarkedRead))
+	}
+	return s.repo.MarkRead(ctx, customerID, req.IDs, time.Now())
+}
+
+func (s *customerNotificationService) MarkAllRead(ctx context.Context, customerID int64) (int64, error) {
+	return s.repo.MarkAllRead(ctx, customerID, time.Now())
+}
+
+func (s *customerNotificationService) Deliver(ctx context.Context, customerID int64, category, title, body, link string) (*CustomerNotificationDTO, error) {
+	n, err := domain.NewCustomerNotification(customerID, category, title, body, link)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.Create(ctx, n); err != nil {
+		return nil, err
+	}
+	return ToCustomerNotificationDTO(n), nil
+}
+
+// InboxSender is the in-app channel of the notification pipeline: it keeps
+// notifications in the storefront inbox of the customer named by the recipient
+type InboxSender struct {
+	service CustomerNotificationService
+}
+
+// NewInboxSender creates an in-app notification sender delivering to service
+func NewInboxSender(service CustomerNotificationService) *InboxSender {
+	return &InboxSender{service: service}
+}
+
+// GetType returns the notification type handled by the sender
+func (s *InboxSender) GetType() notification.NotificationType {
+	return notification.NotificationTypeInApp
+}
+
+// Send stores the notification in the inbox of the customer whose ID is the recipient
+func (s *InboxSender) Send(ctx context.Context, n *notification.Notification) error {
+	customerID, err := strconv.ParseInt(n.Recipient, 10, 64)
+	if err != nil {
+		return fmt.Errorf("in-app recipient %q is not a customer ID", n.Recipient)
+	}
+	if _, err := s.service.Deliver(ctx, customerID, n.Category, n.Subject, n.Body, n.Link); err != nil {
+		return fmt.Errorf("failed to deliver in-app notification: %w", err)
+	}
+	return nil
+}
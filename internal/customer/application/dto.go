@@ -36,6 +36,28 @@ func ToCustomerDTO(c *domain.Customer) *CustomerDTO {
 	}
 }
 
+// CustomerNoteDTO represents a customer note data transfer object
+type CustomerNoteDTO struct {
+	ID              int64     `json:"id"`
+	CustomerID      int64     `json:"customer_id"`
+	Author          string    `json:"author"`
+	Body            string    `json:"body"`
+	CustomerVisible bool      `json:"customer_visible"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ToCustomerNoteDTO converts a domain CustomerNote to CustomerNoteDTO
+func ToCustomerNoteDTO(n *domain.CustomerNote) *CustomerNoteDTO {
+	return &CustomerNoteDTO{
+		ID:              n.ID,
+		CustomerID:      n.CustomerID,
+		Author:          n.Author,
+		Body:            n.Body,
+		CustomerVisible: n.CustomerVisible,
+		CreatedAt:       n.CreatedAt,
+	}
+}
+
 // PaginatedResponse represents a paginated response (reusing structure if not imported)
 // Ideally this should be shared, but defining here for independence or using the one from catalog if imported.
 // customer_queries.go was trying to use application.PaginatedResponse.
@@ -62,3 +84,338 @@ func NewPaginatedResponse(data interface{}, page, pageSize int, totalItems int64
 		TotalPages: totalPages,
 	}
 }
+
+// LocalizationOptionsDTO lists the locales and currencies a site offers and the visitor's selection
+type LocalizationOptionsDTO struct {
+	SiteID          string                `json:"site_id"`
+	DefaultLocale   string                `json:"default_locale"`
+	DefaultCurrency string                `json:"default_currency"`
+	Locales         []LocaleOptionDTO     `json:"locales"`
+	Currencies      []string              `json:"currencies"`
+	Selected        *VisitorPreferenceDTO `json:"selected"`
+	Country         string                `json:"country,omitempty"` // Located by GeoIP; carts estimate tax for it until an address is entered
+	Region          string                `json:"region,omitempty"`
+}
+
+// LocaleOptionDTO represents a locale offered on a site
+type LocaleOptionDTO struct {
+	Code     string `json:"code"`
+	Currency string `json:"currency,omitempty"` // Selected with the locale unless the visitor picks a currency
+}
+
+// SetVisitorPreferenceRequest selects the locale and/or currency of a visitor
+type SetVisitorPreferenceRequest struct {
+	Locale   string `json:"locale"`
+	Currency string `json:"currency"`
+}
+
+// VisitorPreferenceDTO represents the locale and currency a visitor browses with
+type VisitorPreferenceDTO struct {
+	Locale    string `json:"locale"`
+	Currency  string `json:"currency"`
+	Persisted bool   `json:"persisted"` // Stored for the customer or session rather than only in cookies
+}
+
+// CustomerAttributeDTO represents a customer attribute with its typed value
+type CustomerAttributeDTO struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// CustomerTagsDTO represents the tags and attributes of a customer
+type CustomerTagsDTO struct {
+	CustomerID int64                   `json:"customer_id"`
+	Tags       []string                `json:"tags"`
+	Attributes []*CustomerAttributeDTO `json:"attributes"`
+}
+
+// SetCustomerAttributesRequest creates or replaces attributes of a customer by name.
+// Type is STRING, NUMBER, BOOLEAN or DATE (YYYY-MM-DD), and defaults to STRING.
+type SetCustomerAttributesRequest struct {
+	Attributes []struct {
+		Name  string `json:"name"`
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"attributes"`
+}
+
+// AddCustomerTagsRequest tags a customer
+type AddCustomerTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// CustomerTagCountDTO represents a tag in use and the number of customers carrying it
+type CustomerTagCountDTO struct {
+	Tag       string `json:"tag"`
+	Customers int64  `json:"customers"`
+}
+
+// CustomerDuplicateDTO represents a pair of accounts suspected to belong to
+// the same person, with both accounts for side-by-side review
+type CustomerDuplicateDTO struct {
+	ID            int64        `json:"id"`
+	Customer      *CustomerDTO `json:"customer"`
+	OtherCustomer *CustomerDTO `json:"other_customer"`
+	Reasons       []string     `json:"reasons"`
+	Score         int          `json:"score"`
+	Status        string       `json:"status"`
+	DetectedAt    time.Time    `json:"detected_at"`
+	ReviewedBy    string       `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time   `json:"reviewed_at,omitempty"`
+}
+
+// DuplicateScanResultDTO reports a scan for duplicate accounts
+type DuplicateScanResultDTO struct {
+	Queued    int64     `json:"queued"` // Pairs newly queued or with new reasons
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// MergeDuplicateRequest merges a suspected duplicate into the account kept
+type MergeDuplicateRequest struct {
+	SurvivingCustomerID int64 `json:"surviving_customer_id"` // One of the pair; the other is merged into it
+}
+
+// MergeCustomerRequest merges another account into the customer
+type MergeCustomerRequest struct {
+	MergedCustomerID int64 `json:"merged_customer_id"`
+}
+
+// CustomerMergeDTO represents an account merged into another one, with the
+// rows moved to the surviving account by what they are
+type CustomerMergeDTO struct {
+	ID                  int64            `json:"id"`
+	SurvivingCustomerID int64            `json:"surviving_customer_id"`
+	MergedCustomerID    int64            `json:"merged_customer_id"`
+	MergedEmail         string           `json:"merged_email"`
+	Moved               map[string]int64 `json:"moved"`
+	MergedBy            string           `json:"merged_by"`
+	MergedAt            time.Time        `json:"merged_at"`
+}
+
+// ToCustomerMergeDTO converts a domain CustomerMerge to a CustomerMergeDTO
+func ToCustomerMergeDTO(merge *domain.CustomerMerge) *CustomerMergeDTO {
+	return &CustomerMergeDTO{
+		ID:                  merge.ID,
+		SurvivingCustomerID: merge.SurvivingCustomerID,
+		MergedCustomerID:    merge.MergedCustomerID,
+		MergedEmail:         merge.MergedEmail,
+		Moved:               merge.Moved,
+		MergedBy:            merge.MergedBy,
+		MergedAt:            merge.MergedAt,
+	}
+}
+
+// ToCustomerAttributeDTO converts a domain CustomerAttribute to a CustomerAttributeDTO
+func ToCustomerAttributeDTO(attribute *domain.CustomerAttribute) *CustomerAttributeDTO {
+	return &CustomerAttributeDTO{
+		Name:  attribute.Name,
+		Type:  string(attribute.Type),
+		Value: attribute.TypedValue(),
+	}
+}
+
+// AddressDTO represents an address data transfer object
+type AddressDTO struct {
+	ID                  int64  `json:"id,omitempty"`
+	FirstName           string `json:"first_name,omitempty"`
+	LastName            string `json:"last_name,omitempty"`
+	CompanyName         string `json:"company_name,omitempty"`
+	AddressLine1        string `json:"address_line1"`
+	AddressLine2        string `json:"address_line2,omitempty"`
+	AddressLine3        string `json:"address_line3,omitempty"`
+	City                string `json:"city"`
+	County              string `json:"county,omitempty"`
+	StateProvinceRegion string `json:"state_province_region,omitempty"`
+	PostalCode          string `json:"postal_code,omitempty"`
+	Country             string `json:"country"` // ISO 3166-1 alpha-2
+	PrimaryPhone        string `json:"primary_phone,omitempty"`
+	Standardized        bool   `json:"standardized,omitempty"`
+	VerificationLevel   string `json:"verification_level,omitempty"`
+}
+
+// CustomerAddressDTO represents an address of a customer's address book
+type CustomerAddressDTO struct {
+	ID      int64       `json:"id"`
+	Name    string      `json:"name"`
+	Address *AddressDTO `json:"address"`
+}
+
+// AddressValidationDTO represents the result of validating an address. Address is the
+// address normalized as it would be saved; Suggestions are corrections the customer
+// may pick instead.
+type AddressValidationDTO struct {
+	Verdict     string                `json:"verdict"`
+	Address     *AddressDTO           `json:"address"`
+	Suggestions []*AddressDTO         `json:"suggestions"`
+	Issues      []domain.AddressIssue `json:"issues"`
+	Provider    string                `json:"provider"`
+}
+
+// SaveAddressRequest adds or changes an address of the customer's address book.
+// Addresses the provider suggests corrections for are rejected with the suggestions
+// unless AcceptAsEntered is set.
+type SaveAddressRequest struct {
+	Name            string     `json:"name"`
+	Address         AddressDTO `json:"address"`
+	AcceptAsEntered bool       `json:"accept_as_entered"`
+}
+
+// ToAddressDTO converts a domain Address to an AddressDTO
+func ToAddressDTO(a *domain.Address) *AddressDTO {
+	country := a.IsoCountryAlpha2
+	if country == "" {
+		country = a.CountryCode
+	}
+	return &AddressDTO{
+		ID:                  a.ID,
+		FirstName:           a.FirstName,
+		LastName:            a.LastName,
+		CompanyName:         a.CompanyName,
+		AddressLine1:        a.AddressLine1,
+		AddressLine2:        a.AddressLine2,
+		AddressLine3:        a.AddressLine3,
+		City:                a.City,
+		County:              a.County,
+		StateProvinceRegion: a.StateProvinceRegion,
+		PostalCode:          a.PostalCode,
+		Country:             country,
+		PrimaryPhone:        a.PrimaryPhone,
+		Standardized:        a.Standardized,
+		VerificationLevel:   a.VerificationLevel,
+	}
+}
+
+// ToAddressDomain converts an AddressDTO to a domain Address
+func ToAddressDomain(dto *AddressDTO) *domain.Address {
+	return &domain.Address{
+		ID:                  dto.ID,
+		FirstName:           dto.FirstName,
+		LastName:            dto.LastName,
+		CompanyName:         dto.CompanyName,
+		AddressLine1:        dto.AddressLine1,
+		AddressLine2:        dto.AddressLine2,
+		AddressLine3:        dto.AddressLine3,
+		City:                dto.City,
+		County:              dto.County,
+		StateProvinceRegion: dto.StateProvinceRegion,
+		PostalCode:          dto.PostalCode,
+		CountryCode:         dto.Country,
+		IsoCountryAlpha2:    dto.Country,
+		PrimaryPhone:        dto.PrimaryPhone,
+	}
+}
+
+// ToCustomerAddressDTO converts a domain CustomerAddress to a CustomerAddressDTO
+func ToCustomerAddressDTO(ca *domain.CustomerAddress) *CustomerAddressDTO {
+	return &CustomerAddressDTO{
+		ID:      ca.ID,
+		Name:    ca.AddressName,
+		Address: ToAddressDTO(ca.Address),
+	}
+}
+
+// ToAddressValidationDTO converts a domain AddressValidation to an AddressValidationDTO
+func ToAddressValidationDTO(v *domain.AddressValidation) *AddressValidationDTO {
+	dto := &AddressValidationDTO{
+		Verdict:     string(v.Verdict),
+		Address:     ToAddressDTO(v.Address),
+		Suggestions: make([]*AddressDTO, len(v.Suggestions)),
+		Issues:      v.Issues,
+		Provider:    v.Provider,
+	}
+	for i, suggestion := range v.Suggestions {
+		dto.Suggestions[i] = ToAddressDTO(suggestion)
+	}
+	if dto.Issues == nil {
+		dto.Issues = []domain.AddressIssue{}
+	}
+	return dto
+}
+
+// StartCustomerImportRequest describes an uploaded customer import file
+type StartCustomerImportRequest struct {
+	Filename          string `json:"filename"`
+	Format            string `json:"format,omitempty"`             // CSV or JSON; inferred from the file extension when empty
+	DuplicateStrategy string `json:"duplicate_strategy,omitempty"` // SKIP, UPDATE or REJECT; SKIP when empty
+}
+
+// CustomerImportJobDTO represents a customer import and its progress
+type CustomerImportJobDTO struct {
+	ID                int64      `json:"id"`
+	Filename          string     `json:"filename"`
+	Format            string     `json:"format"`
+	DuplicateStrategy string     `json:"duplicate_strategy"`
+	Status            string     `json:"status"`
+	Processed         int64      `json:"processed"`
+	Imported          int64      `json:"imported"`
+	Updated           int64      `json:"updated"`
+	Skipped           int64      `json:"skipped"`
+	Failed            int64      `json:"failed"`
+	Attempts          int        `json:"attempts"`
+	LastError         string     `json:"last_error,omitempty"`
+	RequestedBy       string     `json:"requested_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+}
+
+// ToCustomerImportJobDTO converts a domain CustomerImportJob to a CustomerImportJobDTO
+func ToCustomerImportJobDTO(j *domain.CustomerImportJob) *CustomerImportJobDTO {
+	return &CustomerImportJobDTO{
+		ID:                j.ID,
+		Filename:          j.Filename,
+		Format:            string(j.Format),
+		DuplicateStrategy: string(j.DuplicateStrategy),
+		Status:            string(j.Status),
+		Processed:         j.Cursor,
+		Imported:          j.Imported,
+		Updated:           j.Updated,
+		Skipped:           j.Skipped,
+		Failed:            j.Failed,
+		Attempts:          j.Attempts,
+		LastError:         j.LastError,
+		RequestedBy:       j.RequestedBy,
+		CreatedAt:         j.CreatedAt,
+		StartedAt:         j.StartedAt,
+		FinishedAt:        j.FinishedAt,
+	}
+}
+
+// CustomerNotificationDTO is a message in a customer's storefront inbox
+type CustomerNotificationDTO struct {
+	ID        int64      `json:"id"`
+	Category  string     `json:"category"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body,omitempty"`
+	Link      string     `json:"link,omitempty"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CustomerNotificationListDTO is a page of a customer's inbox
+type CustomerNotificationListDTO struct {
+	Notifications []*CustomerNotificationDTO `json:"notifications"`
+	Total         int64                      `json:"total"`  // Messages matching the filter
+	Unread        int64                      `json:"unread"` // Unread messages in the whole inbox
+}
+
+// MarkNotificationsReadRequest selects the inbox messages to mark read
+type MarkNotificationsReadRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+// ToCustomerNotificationDTO converts a domain CustomerNotification to a CustomerNotificationDTO
+func ToCustomerNotificationDTO(n *domain.CustomerNotification) *CustomerNotificationDTO {
+	return &CustomerNotificationDTO{
+		ID:        n.ID,
+		Category:  n.Category,
+		Title:     n.Title,
+		Body:      n.Body,
+		Link:      n.Link,
+		Read:      n.IsRead(),
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
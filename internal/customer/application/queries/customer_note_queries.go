@@ -0,0 +1,28 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/customer/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// ListCustomerNotesQuery represents a query to list the notes of a customer
+type ListCustomerNotesQuery struct {
+	CustomerID          int64 `json:"customer_id" validate:"required"`
+	CustomerVisibleOnly bool  `json:"customer_visible_only"`
+}
+
+// HandleListCustomerNotes handles the list customer notes query
+func (h *CustomerQueryHandler) HandleListCustomerNotes(ctx context.Context, query *ListCustomerNotesQuery) ([]*application.CustomerNoteDTO, error) {
+	notes, err := h.noteRepo.FindByCustomerID(ctx, query.CustomerID, query.CustomerVisibleOnly)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list customer notes")
+	}
+
+	dtos := make([]*application.CustomerNoteDTO, len(notes))
+	for i, note := range notes {
+		dtos[i] = application.ToCustomerNoteDTO(note)
+	}
+	return dtos, nil
+}
@@ -1,15 +1,16 @@
 package queries
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/qhato/ecommerce/internal/customer/application"
 	"github.com/qhato/ecommerce/internal/customer/domain"
 	"github.com/qhato/ecommerce/pkg/cache"
 	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
 	"github.com/qhato/ecommerce/pkg/logger"
 )
 
@@ -33,79 +34,145 @@ type ListCustomersQuery struct {
 	SortBy          string `json:"sort_by"`
 	SortOrder       string `json:"sort_order"`
 	SearchQuery     string `json:"search_query"`
+	Tag             string `json:"tag"`
 }
 
+// Default lifetimes of cached customer profiles
+const (
+	defaultCustomerCacheTTL         = 5 * time.Minute
+	defaultCustomerNegativeCacheTTL = 30 * time.Second
+)
+
+// customerNotFoundMarker is cached for IDs with no customer, so probing
+// nonexistent IDs does not reach the database each time
+var customerNotFoundMarker = []byte("null")
+
 // CustomerQueryHandler handles customer queries
 type CustomerQueryHandler struct {
-	repo   domain.CustomerRepository
-	cache  cache.Cache
-	logger *logger.Logger
+	repo             domain.CustomerRepository
+	noteRepo         domain.CustomerNoteRepository
+	cache            cache.Cache
+	cacheTTL         time.Duration
+	negativeCacheTTL time.Duration
+	logger           *logger.Logger
 }
 
 // NewCustomerQueryHandler creates a new customer query handler
 func NewCustomerQueryHandler(
 	repo domain.CustomerRepository,
+	noteRepo domain.CustomerNoteRepository,
 	cache cache.Cache,
 	logger *logger.Logger,
 ) *CustomerQueryHandler {
 	return &CustomerQueryHandler{
-		repo:   repo,
-		cache:  cache,
-		logger: logger,
+		repo:             repo,
+		noteRepo:         noteRepo,
+		cache:            cache,
+		cacheTTL:         defaultCustomerCacheTTL,
+		negativeCacheTTL: defaultCustomerNegativeCacheTTL,
+		logger:           logger,
+	}
+}
+
+// SetCacheTTLs sets how long customer profiles, and IDs with no customer, stay
+// cached. Non-positive values keep the defaults.
+func (h *CustomerQueryHandler) SetCacheTTLs(ttl, negativeTTL time.Duration) {
+	if ttl > 0 {
+		h.cacheTTL = ttl
+	}
+	if negativeTTL > 0 {
+		h.negativeCacheTTL = negativeTTL
 	}
 }
 
-// HandleGetCustomerByID handles the get customer by ID query
+// Subscribe drops cached profiles when their customer changes
+func (h *CustomerQueryHandler) Subscribe(bus event.Bus) error {
+	eventTypes := []string{
+		domain.EventCustomerRegistered,
+		domain.EventCustomerUpdated,
+		domain.EventCustomerDeactivated,
+		domain.EventCustomerActivated,
+		domain.EventCustomerPasswordChanged,
+		domain.EventCustomerMerged,
+	}
+	for _, eventType := range eventTypes {
+		if err := bus.Subscribe(eventType, h.handleCustomerEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *CustomerQueryHandler) handleCustomerEvent(ctx context.Context, evt event.Event) error {
+	var customerID int64
+	switch e := evt.(type) {
+	case *domain.CustomerRegisteredEvent:
+		customerID = e.CustomerID // Drops a cached not found for the new ID
+	case *domain.CustomerUpdatedEvent:
+		customerID = e.CustomerID
+	case *domain.CustomerDeactivatedEvent:
+		customerID = e.CustomerID
+	case *domain.CustomerActivatedEvent:
+		customerID = e.CustomerID
+	case *domain.CustomerPasswordChangedEvent:
+		customerID = e.CustomerID
+	case *domain.CustomerMergedEvent:
+		h.InvalidateCache(ctx, e.SurvivingCustomerID)
+		customerID = e.MergedCustomerID
+	default:
+		return nil
+	}
+	h.InvalidateCache(ctx, customerID)
+	return nil
+}
+
+// HandleGetCustomerByID handles the get customer by ID query. Profiles are read
+// through the cache; IDs with no customer are cached briefly as well.
 func (h *CustomerQueryHandler) HandleGetCustomerByID(ctx context.Context, query *GetCustomerByIDQuery) (*application.CustomerDTO, error) {
 	// Try to get from cache first
 	cacheKey := customerCacheKey(query.ID)
-	cached, err := h.cache.Get(ctx, cacheKey)
-	if err == nil && cached != nil {
-		var customer *domain.Customer
-		if err := json.Unmarshal(cached, &customer); err == nil {
+	if cached, err := h.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
+		if bytes.Equal(cached, customerNotFoundMarker) {
+			return nil, errors.NotFound("customer")
+		}
+		var dto application.CustomerDTO
+		if err := json.Unmarshal(cached, &dto); err == nil {
 			h.logger.WithField("customer_id", query.ID).Debug("customer found in cache")
-			return application.ToCustomerDTO(customer), nil
+			return &dto, nil
 		}
 	}
 
 	// Get from repository
 	customer, err := h.repo.FindByID(ctx, query.ID)
 	if err != nil {
-		return nil, errors.NotFound("customer not found")
+		return nil, errors.InternalWrap(err, "failed to get customer")
 	}
-
-	// Cache the result
-	serialized, err := json.Marshal(customer)
-	if err == nil {
-		if err := h.cache.Set(ctx, cacheKey, serialized, 5*time.Minute); err != nil {
-			h.logger.WithError(err).WithField("customer_id", query.ID).Warn("failed to cache customer")
+	if customer == nil {
+		if err := h.cache.Set(ctx, cacheKey, customerNotFoundMarker, h.negativeCacheTTL); err != nil {
+			h.logger.WithError(err).WithField("customer_id", query.ID).Warn("failed to cache missing customer")
 		}
-	} else {
-		h.logger.WithError(err).WithField("customer_id", query.ID).Warn("failed to serialize customer for caching")
+		return nil, errors.NotFound("customer")
 	}
 
-	return application.ToCustomerDTO(customer), nil
+	dto := application.ToCustomerDTO(customer)
+	h.cacheCustomer(ctx, dto)
+	return dto, nil
 }
 
 // HandleGetCustomerByEmail handles the get customer by email query
 func (h *CustomerQueryHandler) HandleGetCustomerByEmail(ctx context.Context, query *GetCustomerByEmailQuery) (*application.CustomerDTO, error) {
 	customer, err := h.repo.FindByEmail(ctx, query.Email)
 	if err != nil {
-		return nil, errors.NotFound("customer not found")
+		return nil, errors.InternalWrap(err, "failed to get customer")
 	}
-
-	// Cache the result
-	cacheKey := customerCacheKey(customer.ID)
-	serialized, err := json.Marshal(customer)
-	if err == nil {
-		if err := h.cache.Set(ctx, cacheKey, serialized, 5*time.Minute); err != nil {
-			h.logger.WithError(err).WithField("customer_id", customer.ID).Warn("failed to cache customer")
-		}
-	} else {
-		h.logger.WithError(err).WithField("customer_id", customer.ID).Warn("failed to serialize customer for caching")
+	if customer == nil {
+		return nil, errors.NotFound("customer")
 	}
 
-	return application.ToCustomerDTO(customer), nil
+	// Prime the by-ID cache
+	dto := application.ToCustomerDTO(customer)
+	h.cacheCustomer(ctx, dto)
+	return dto, nil
 }
 
 // HandleListCustomers handles the list customers query
@@ -134,6 +201,7 @@ func (h *CustomerQueryHandler) HandleListCustomers(ctx context.Context, query *L
 		SortBy:          query.SortBy,
 		SortOrder:       query.SortOrder,
 		SearchQuery:     query.SearchQuery,
+		Tag:             query.Tag,
 	}
 
 	// Get from repository
@@ -159,7 +227,22 @@ func (h *CustomerQueryHandler) InvalidateCache(ctx context.Context, customerID i
 	}
 }
 
+// cacheCustomer stores a customer profile under its ID
+func (h *CustomerQueryHandler) cacheCustomer(ctx context.Context, dto *application.CustomerDTO) {
+	serialized, err := json.Marshal(dto)
+	if err != nil {
+		h.logger.WithError(err).WithField("customer_id", dto.ID).Warn("failed to serialize customer for caching")
+		return
+	}
+	if err := h.cache.Set(ctx, customerCacheKey(dto.ID), serialized, h.cacheTTL); err != nil {
+		h.logger.WithError(err).WithField("customer_id", dto.ID).Warn("failed to cache customer")
+	}
+}
+
+// customerProfileKeys builds the cache keys of customer profiles
+var customerProfileKeys = cache.NewKeyBuilder("customer", "profile", 1)
+
 // customerCacheKey generates a cache key for a customer
 func customerCacheKey(id int64) string {
-	return fmt.Sprintf("customer:%d", id)
+	return customerProfileKeys.Key(id)
 }
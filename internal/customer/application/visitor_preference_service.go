@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/cache"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/requestctx"
+)
+
+// VisitorPreferenceService defines the application service for the locale and
+// currency storefront visitors browse with.
+type VisitorPreferenceService interface {
+	// GetLocalizationOptions lists what the request's site offers and the current selection.
+	GetLocalizationOptions(ctx context.Context) *LocalizationOptionsDTO
+
+	// SetPreference selects the visitor's locale and currency, storing them for the
+	// signed-in customer or the session.
+	SetPreference(ctx context.Context, req *SetVisitorPreferenceRequest) (*VisitorPreferenceDTO, error)
+
+	// Preference returns the stored locale and currency of a customer or session,
+	// or "" for what was not selected. It is used by the storefront context middleware.
+	Preference(ctx context.Context, siteID string, customerID int64, sessionID string) (locale, currency string)
+}
+
+type visitorPreferenceService struct {
+	repo          domain.VisitorPreferenceRepository
+	localizations requestctx.Localizations
+	cache         cache.Cache
+	cacheTTL      time.Duration
+	logger        *logger.Logger
+}
+
+// NewVisitorPreferenceService creates a new instance of VisitorPreferenceService.
+// A zero cacheTTL looks preferences up on every request.
+func NewVisitorPreferenceService(
+	repo domain.VisitorPreferenceRepository,
+	localizations requestctx.Localizations,
+	cache cache.Cache,
+	cacheTTL time.Duration,
+	log *logger.Logger,
+) VisitorPreferenceService {
+	return &visitorPreferenceService{
+		repo:          repo,
+		localizations: localizations,
+		cache:         cache,
+		cacheTTL:      cacheTTL,
+		logger:        log,
+	}
+}
+
+func (s *visitorPreferenceService) GetLocalizationOptions(ctx context.Context) *LocalizationOptionsDTO {
+	siteID := requestctx.SiteID(ctx)
+	localization := s.localizations.ForSite(siteID)
+
+	dto := &LocalizationOptionsDTO{
+		SiteID:          siteID,
+		DefaultLocale:   localization.DefaultLocale,
+		DefaultCurrency: localization.DefaultCurrency,
+		Locales:         make([]LocaleOptionDTO, 0, len(localization.Locales)),
+		Currencies:      make([]string, 0, len(localization.Currencies)),
+		Selected: &VisitorPreferenceDTO{
+			Locale:   requestctx.Locale(ctx),
+			Currency: requestctx.Currency(ctx),
+		},
+		Country: requestctx.Country(ctx),
+		Region:  requestctx.Region(ctx),
+	}
+	for _, locale := range localization.Locales {
+		option := LocaleOptionDTO{Code: locale}
+		if currency, ok := localization.MatchCurrency(localization.LocaleCurrency(locale)); ok {
+			option.Currency = currency
+		}
+		dto.Locales = append(dto.Locales, option)
+	}
+	for _, currency := range localization.Currencies {
+		if code, ok := localization.MatchCurrency(currency); ok {
+			dto.Currencies = append(dto.Currencies, code)
+		}
+	}
+	return dto
+}
+
+func (s *visitorPreferenceService) SetPreference(ctx context.Context, req *SetVisitorPreferenceRequest) (*VisitorPreferenceDTO, error) {
+	if req.Locale == "" && req.Currency == "" {
+		return nil, errors.ValidationError("locale or currency is required")
+	}
+	rc, ok := requestctx.FromContext(ctx)
+	if !ok {
+		return nil, errors.BadRequest("the storefront context was not resolved")
+	}
+	localization := s.localizations.ForSite(rc.SiteID)
+
+	locale := rc.Locale
+	if req.Locale != "" {
+		if locale, ok = localization.MatchLocale(req.Locale); !ok {
+			return nil, errors.ValidationError(fmt.Sprintf("locale %s is not offered on site %s", req.Locale, rc.SiteID))
+		}
+	}
+	currency := rc.Currency
+	if req.Currency != "" {
+		if currency, ok = localization.MatchCurrency(req.Currency); !ok {
+			return nil, errors.ValidationError(fmt.Sprintf("currency %s is not offered on site %s", req.Currency, rc.SiteID))
+		}
+	} else if localeCurrency, ok := localization.MatchCurrency(localization.LocaleCurrency(locale)); ok {
+		// Switching locale switches to its currency unless one was picked as well
+		currency = localeCurrency
+	}
+
+	dto := &VisitorPreferenceDTO{Locale: locale, Currency: currency}
+	if rc.CustomerID == 0 && rc.SessionID == "" {
+		// Cookies set by the handler are all an anonymous visitor without a session has
+		return dto, nil
+	}
+
+	preference := domain.NewVisitorPreference(rc.SiteID, rc.CustomerID, rc.SessionID, locale, currency)
+	if err := s.repo.Save(ctx, preference); err != nil {
+		return nil, fmt.Errorf("failed to save visitor preference: %w", err)
+	}
+	if s.cacheTTL > 0 {
+		if err := s.cache.Delete(ctx, preferenceCacheKey(preference.SiteID, preference.CustomerID, preference.SessionID)); err != nil {
+			s.logger.WithError(err).Warn("failed to invalidate cached visitor preference")
+		}
+	}
+	dto.Persisted = true
+	return dto, nil
+}
+
+// cachedPreference is a stored preference, or its absence, as cached
+type cachedPreference struct {
+	Locale   string `json:"locale"`
+	Currency string `json:"currency"`
+}
+
+func (s *visitorPreferenceService) Preference(ctx context.Context, siteID string, customerID int64, sessionID string) (string, string) {
+	cacheKey := preferenceCacheKey(siteID, customerID, sessionID)
+	if s.cacheTTL > 0 {
+		if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != nil {
+			var preference cachedPreference
+			if err := json.Unmarshal(cached, &preference); err == nil {
+				return preference.Locale, preference.Currency
+			}
+		}
+	}
+
+	var stored *domain.VisitorPreference
+	var err error
+	if customerID != 0 {
+		stored, err = s.repo.FindByCustomer(ctx, siteID, customerID)
+	} else if sessionID != "" {
+		stored, err = s.repo.FindBySession(ctx, siteID, sessionID)
+	}
+	if err != nil {
+		// Browsing goes on with the other locale and currency sources
+		s.logger.WithError(err).WithField("site_id", siteID).Warn("failed to look up visitor preference")
+		return "", ""
+	}
+
+	var preference cachedPreference
+	if stored != nil {
+		preference = cachedPreference{Locale: stored.Locale, Currency: stored.Currency}
+	}
+	if s.cacheTTL > 0 {
+		// Absent preferences are cached too so visitors who never chose cost no query
+		if serialized, err := json.Marshal(preference); err == nil {
+			if err := s.cache.Set(ctx, cacheKey, serialized, s.cacheTTL); err != nil {
+				s.logger.WithError(err).Warn("failed to cache visitor preference")
+			}
+		}
+	}
+	return preference.Locale, preference.Currency
+}
+
+// preferenceKeys builds the cache keys of visitor preferences
+var preferenceKeys = cache.NewKeyBuilder("customer", "visitor_preference", 1)
+
+// preferenceCacheKey keys customers by ID and anonymous visitors by session
+func preferenceCacheKey(siteID string, customerID int64, sessionID string) string {
+	if customerID != 0 {
+		return preferenceKeys.Key(siteID, "customer", customerID)
+	}
+	return preferenceKeys.Key(siteID, "session", sessionID)
+}
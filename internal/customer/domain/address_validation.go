@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AddressVerdict is the outcome of validating an address
+type AddressVerdict string
+
+const (
+	AddressVerdictValid      AddressVerdict = "VALID"      // Deliverable; formatting may have been normalized
+	AddressVerdictSuggested  AddressVerdict = "SUGGESTED"  // The provider proposes corrections
+	AddressVerdictInvalid    AddressVerdict = "INVALID"    // Incomplete, malformed or undeliverable
+	AddressVerdictUnverified AddressVerdict = "UNVERIFIED" // Well formed, but the provider could not be reached
+)
+
+// Address verification levels stored in blc_address.verification_level
+const (
+	AddressVerificationNormalized = "NORMALIZED" // Formatted by the internal normalizer only
+	AddressVerificationVerified   = "VERIFIED"   // Confirmed deliverable by the provider
+	AddressVerificationOverridden = "OVERRIDDEN" // Kept as entered against the provider's verdict
+)
+
+// Address issue codes
+const (
+	AddressIssueRequired      = "REQUIRED"
+	AddressIssueInvalidFormat = "INVALID_FORMAT"
+	AddressIssueUndeliverable = "UNDELIVERABLE"
+)
+
+// AddressIssue is a problem found with a field of an address
+type AddressIssue struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// AddressValidation is the result of validating an address
+type AddressValidation struct {
+	Verdict     AddressVerdict
+	Address     *Address   // The address as it should be saved
+	Suggestions []*Address // Corrections proposed by the provider, best first
+	Issues      []AddressIssue
+	Provider    string
+}
+
+// postalCodeFormat checks a postal code with its spaces and dashes removed and lays it out
+// the way the country writes it
+type postalCodeFormat struct {
+	pattern *regexp.Regexp
+	layout  func(compact string) string
+}
+
+func compactPostalCode(compact string) string { return compact }
+
+// splitPostalCode lays out a postal code with sep inserted at position at
+func splitPostalCode(at int, sep string) func(string) string {
+	return func(compact string) string {
+		if at < 0 {
+			at = len(compact) + at
+		}
+		return compact[:at] + sep + compact[at:]
+	}
+}
+
+// postalCodeFormats holds the postal code formats of the countries the normalizer knows.
+// Postal codes of other countries are only trimmed and uppercased.
+var postalCodeFormats = map[string]postalCodeFormat{
+	"US": {regexp.MustCompile(`^\d{5}(\d{4})?$`), func(compact string) string {
+		if len(compact) == 9 {
+			return compact[:5] + "-" + compact[5:]
+		}
+		return compact
+	}},
+	"CA": {regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[A-Z]\d[A-Z]\d$`), splitPostalCode(3, " ")},
+	"GB": {regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]?\d[A-Z]{2}$`), splitPostalCode(-3, " ")},
+	"NL": {regexp.MustCompile(`^\d{4}[A-Z]{2}$`), splitPostalCode(4, " ")},
+	"SE": {regexp.MustCompile(`^\d{5}$`), splitPostalCode(3, " ")},
+	"PL": {regexp.MustCompile(`^\d{5}$`), splitPostalCode(2, "-")},
+	"PT": {regexp.MustCompile(`^\d{7}$`), splitPostalCode(4, "-")},
+	"JP": {regexp.MustCompile(`^\d{7}$`), splitPostalCode(3, "-")},
+	"BR": {regexp.MustCompile(`^\d{8}$`), splitPostalCode(5, "-")},
+	"DE": {regexp.MustCompile(`^\d{5}$`), compactPostalCode},
+	"FR": {regexp.MustCompile(`^\d{5}$`), compactPostalCode},
+	"ES": {regexp.MustCompile(`^\d{5}$`), compactPostalCode},
+	"IT": {regexp.MustCompile(`^\d{5}$`), compactPostalCode},
+	"MX": {regexp.MustCompile(`^\d{5}$`), compactPostalCode},
+	"AU": {regexp.MustCompile(`^\d{4}$`), compactPostalCode},
+	"AT": {regexp.MustCompile(`^\d{4}$`), compactPostalCode},
+	"BE": {regexp.MustCompile(`^\d{4}$`), compactPostalCode},
+	"CH": {regexp.MustCompile(`^\d{4}$`), compactPostalCode},
+	"DK": {regexp.MustCompile(`^\d{4}$`), compactPostalCode},
+	"NO": {regexp.MustCompile(`^\d{4}$`), compactPostalCode},
+	"IN": {regexp.MustCompile(`^\d{6}$`), compactPostalCode},
+}
+
+// regionRequired lists the countries whose addresses need a state or province
+var regionRequired = map[string]bool{"US": true, "CA": true, "AU": true}
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// NormalizeAddress returns a copy of an address with its whitespace collapsed, its country
+// and region codes uppercased and its postal code laid out as its country writes it,
+// together with the issues that keep it from being deliverable.
+func NormalizeAddress(address *Address) (*Address, []AddressIssue) {
+	normalized := *address
+	normalized.AddressLine1 = collapseSpaces(address.AddressLine1)
+	normalized.AddressLine2 = collapseSpaces(address.AddressLine2)
+	normalized.AddressLine3 = collapseSpaces(address.AddressLine3)
+	normalized.City = collapseSpaces(address.City)
+	normalized.County = collapseSpaces(address.County)
+	normalized.CompanyName = collapseSpaces(address.CompanyName)
+	normalized.FirstName = collapseSpaces(address.FirstName)
+	normalized.LastName = collapseSpaces(address.LastName)
+	normalized.PrimaryPhone = strings.TrimSpace(address.PrimaryPhone)
+
+	country := strings.ToUpper(strings.TrimSpace(address.IsoCountryAlpha2))
+	if country == "" {
+		country = strings.ToUpper(strings.TrimSpace(address.CountryCode))
+	}
+	normalized.IsoCountryAlpha2 = country
+	normalized.CountryCode = country
+
+	region := collapseSpaces(address.StateProvinceRegion)
+	if len(region) <= 3 {
+		region = strings.ToUpper(region) // Subdivision codes such as CA, NSW or QC
+	}
+	normalized.StateProvinceRegion = region
+
+	var issues []AddressIssue
+	if normalized.AddressLine1 == "" {
+		issues = append(issues, AddressIssue{Field: "address_line1", Code: AddressIssueRequired, Message: "Street address is required"})
+	}
+	if normalized.City == "" {
+		issues = append(issues, AddressIssue{Field: "city", Code: AddressIssueRequired, Message: "City is required"})
+	}
+	if !countryCodePattern.MatchString(country) {
+		issues = append(issues, AddressIssue{Field: "country", Code: AddressIssueInvalidFormat, Message: "Country must be a two-letter ISO code"})
+	}
+	if regionRequired[country] && region == "" {
+		issues = append(issues, AddressIssue{Field: "state_province_region", Code: AddressIssueRequired, Message: "State or province is required"})
+	}
+
+	postalCode := strings.ToUpper(collapseSpaces(address.PostalCode))
+	normalized.PostalCode = postalCode
+	if format, ok := postalCodeFormats[country]; ok {
+		compact := strings.NewReplacer(" ", "", "-", "").Replace(postalCode)
+		switch {
+		case compact == "":
+			issues = append(issues, AddressIssue{Field: "postal_code", Code: AddressIssueRequired, Message: "Postal code is required"})
+		case !format.pattern.MatchString(compact):
+			issues = append(issues, AddressIssue{Field: "postal_code", Code: AddressIssueInvalidFormat, Message: "Postal code is not valid for " + country})
+		default:
+			normalized.PostalCode = format.layout(compact)
+		}
+	}
+
+	return &normalized, issues
+}
+
+// SameAddress reports whether two addresses deliver to the same place, ignoring case
+func SameAddress(a, b *Address) bool {
+	return strings.EqualFold(a.AddressLine1, b.AddressLine1) &&
+		strings.EqualFold(a.AddressLine2, b.AddressLine2) &&
+		strings.EqualFold(a.City, b.City) &&
+		strings.EqualFold(a.StateProvinceRegion, b.StateProvinceRegion) &&
+		strings.EqualFold(a.PostalCode, b.PostalCode) &&
+		strings.EqualFold(a.IsoCountryAlpha2, b.IsoCountryAlpha2)
+}
+
+func collapseSpaces(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -58,6 +58,8 @@ type Address struct {
 	StateProvinceRegion string
 	CountryCode         string
 	IsoCountryAlpha2    string
+	Standardized        bool   // Formatted by the address normalizer or provider
+	VerificationLevel   string // How the address was verified, e.g. VERIFIED or OVERRIDDEN
 }
 
 // CustomerPhone represents a customer phone number
@@ -82,6 +84,7 @@ type CustomerAttribute struct {
 	ID         int64
 	Name       string
 	Value      string
+	Type       AttributeType
 	CustomerID int64
 }
 
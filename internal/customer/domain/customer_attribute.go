@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AttributeType is the type of a customer attribute's value
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "STRING"
+	AttributeTypeNumber  AttributeType = "NUMBER"
+	AttributeTypeBoolean AttributeType = "BOOLEAN"
+	AttributeTypeDate    AttributeType = "DATE" // YYYY-MM-DD
+)
+
+// attributeDateLayout is the format of DATE attribute values
+const attributeDateLayout = "2006-01-02"
+
+// Limits on customer attributes and tags
+const (
+	MaxAttributeValueLength = 255
+	MaxCustomerTags         = 100
+)
+
+// attributeKeyPattern matches attribute names and tags: lowercase, starting with a
+// letter or digit, up to 64 characters. Colons allow namespaces, e.g. "crm:vip".
+var attributeKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]{0,63}$`)
+
+// NewCustomerAttribute creates a typed attribute, checking the value parses as the type and
+// storing it in a canonical form. Free-form attributes have no type and are stored as STRING.
+func NewCustomerAttribute(customerID int64, name, value string, attrType AttributeType) (*CustomerAttribute, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !attributeKeyPattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid attribute name %q: use up to 64 lowercase letters, digits, '_', '.', ':' or '-'", name)
+	}
+	if attrType == "" {
+		attrType = AttributeTypeString
+	}
+
+	value = strings.TrimSpace(value)
+	switch attrType {
+	case AttributeTypeString:
+		if len(value) > MaxAttributeValueLength {
+			return nil, fmt.Errorf("attribute %s exceeds %d characters", name, MaxAttributeValueLength)
+		}
+	case AttributeTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s must be a number", name)
+		}
+		value = strconv.FormatFloat(number, 'f', -1, 64)
+	case AttributeTypeBoolean:
+		flag, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s must be true or false", name)
+		}
+		value = strconv.FormatBool(flag)
+	case AttributeTypeDate:
+		if _, err := time.Parse(attributeDateLayout, value); err != nil {
+			return nil, fmt.Errorf("attribute %s must be a date formatted YYYY-MM-DD", name)
+		}
+	default:
+		return nil, fmt.Errorf("unknown attribute type %q", attrType)
+	}
+
+	return &CustomerAttribute{
+		Name:       name,
+		Value:      value,
+		Type:       attrType,
+		CustomerID: customerID,
+	}, nil
+}
+
+// TypedValue returns the attribute value as a float64 or bool for NUMBER and BOOLEAN
+// attributes, or as a string. Dates stay YYYY-MM-DD strings, which compare in date order.
+// Values that no longer parse are returned as strings.
+func (a *CustomerAttribute) TypedValue() interface{} {
+	switch a.Type {
+	case AttributeTypeNumber:
+		if number, err := strconv.ParseFloat(a.Value, 64); err == nil {
+			return number
+		}
+	case AttributeTypeBoolean:
+		if flag, err := strconv.ParseBool(a.Value); err == nil {
+			return flag
+		}
+	}
+	return a.Value
+}
+
+// NormalizeCustomerTag lowercases and checks a tag
+func NormalizeCustomerTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !attributeKeyPattern.MatchString(tag) {
+		return "", fmt.Errorf("invalid tag %q: use up to 64 lowercase letters, digits, '_', '.', ':' or '-'", tag)
+	}
+	return tag, nil
+}
+
+// CustomerTagCount is the number of customers carrying a tag
+type CustomerTagCount struct {
+	Tag       string
+	Customers int64
+}
+
+// CustomerAttributeRepository defines the interface for customer attribute and tag persistence
+type CustomerAttributeRepository interface {
+	// FindAttributes retrieves the attributes of a customer, ordered by name
+	FindAttributes(ctx context.Context, customerID int64) ([]*CustomerAttribute, error)
+
+	// SaveAttributes creates or replaces the given attributes of a customer by name
+	SaveAttributes(ctx context.Context, customerID int64, attributes []*CustomerAttribute) error
+
+	// DeleteAttribute removes an attribute of a customer
+	DeleteAttribute(ctx context.Context, customerID int64, name string) error
+
+	// FindTags retrieves the tags of a customer, ordered by tag
+	FindTags(ctx context.Context, customerID int64) ([]string, error)
+
+	// AddTags tags a customer; tags it already carries are ignored
+	AddTags(ctx context.Context, customerID int64, tags []string) error
+
+	// RemoveTag removes a tag from a customer
+	RemoveTag(ctx context.Context, customerID int64, tag string) error
+
+	// CountTags lists every tag in use with the number of customers carrying it
+	CountTags(ctx context.Context) ([]*CustomerTagCount, error)
+}
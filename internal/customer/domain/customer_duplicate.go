@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DuplicateReason says why two customer accounts look like the same person
+type DuplicateReason string
+
+const (
+	// DuplicateReasonEmail matches accounts whose emails reach the same
+	// mailbox once case, +tags and the dots of Gmail addresses are ignored
+	DuplicateReasonEmail DuplicateReason = "EMAIL_VARIANT"
+	// DuplicateReasonNameAddress matches accounts with the same first and last
+	// name and a saved address on the same street line and postal code
+	DuplicateReasonNameAddress DuplicateReason = "NAME_ADDRESS"
+)
+
+// Scores of each reason; a pair matched on both scores 100
+const (
+	DuplicateScoreEmail       = 70
+	DuplicateScoreNameAddress = 50
+)
+
+// DuplicateStatus is where a suspected duplicate is in the review queue
+type DuplicateStatus string
+
+const (
+	DuplicateStatusPending   DuplicateStatus = "PENDING"
+	DuplicateStatusDismissed DuplicateStatus = "DISMISSED" // Reviewed as different people; not queued again
+	DuplicateStatusMerged    DuplicateStatus = "MERGED"
+)
+
+// CustomerDuplicate is a pair of accounts suspected to belong to the same
+// person. CustomerID is always the lower of the two IDs.
+type CustomerDuplicate struct {
+	ID              int64
+	CustomerID      int64
+	OtherCustomerID int64
+	Reasons         []DuplicateReason
+	Score           int
+	Status          DuplicateStatus
+	DetectedAt      time.Time
+	ReviewedBy      string
+	ReviewedAt      *time.Time
+}
+
+// Involves reports whether the customer is one of the pair
+func (d *CustomerDuplicate) Involves(customerID int64) bool {
+	return d.CustomerID == customerID || d.OtherCustomerID == customerID
+}
+
+// Dismiss records that the accounts belong to different people
+func (d *CustomerDuplicate) Dismiss(reviewedBy string) error {
+	if d.Status != DuplicateStatusPending {
+		return fmt.Errorf("duplicate %d is already %s", d.ID, d.Status)
+	}
+	now := time.Now()
+	d.Status = DuplicateStatusDismissed
+	d.ReviewedBy = reviewedBy
+	d.ReviewedAt = &now
+	return nil
+}
+
+// CustomerMerge records an account merged into another one. Moved counts the
+// rows re-linked to the surviving account, by what they are.
+type CustomerMerge struct {
+	ID                  int64
+	SurvivingCustomerID int64
+	MergedCustomerID    int64
+	MergedEmail         string
+	Moved               map[string]int64
+	MergedBy            string
+	MergedAt            time.Time
+}
+
+// NewCustomerMerge creates the merge of one account into another
+func NewCustomerMerge(survivingCustomerID, mergedCustomerID int64, mergedEmail, mergedBy string) (*CustomerMerge, error) {
+	if survivingCustomerID == mergedCustomerID {
+		return nil, fmt.Errorf("a customer cannot be merged into itself")
+	}
+	return &CustomerMerge{
+		SurvivingCustomerID: survivingCustomerID,
+		MergedCustomerID:    mergedCustomerID,
+		MergedEmail:         mergedEmail,
+		Moved:               make(map[string]int64),
+		MergedBy:            mergedBy,
+		MergedAt:            time.Now(),
+	}, nil
+}
+
+// DuplicateFilter selects suspected duplicates in the review queue
+type DuplicateFilter struct {
+	Status     DuplicateStatus // Empty lists every status
+	CustomerID int64           // Only pairs involving the customer; 0 for all
+	Page       int
+	PageSize   int
+}
+
+// CustomerDuplicateRepository defines the interface for duplicate detection
+// and account merge persistence
+type CustomerDuplicateRepository interface {
+	// Detect queues the pairs of active accounts matching on any reason,
+	// updating the reasons of pairs still pending. Dismissed and merged pairs
+	// are left as reviewed, and pending pairs with an archived account are
+	// dropped. It returns the number of pairs queued or updated.
+	Detect(ctx context.Context) (int64, error)
+
+	// FindByID retrieves a suspected duplicate
+	FindByID(ctx context.Context, id int64) (*CustomerDuplicate, error)
+
+	// FindAll retrieves suspected duplicates, highest score first
+	FindAll(ctx context.Context, filter *DuplicateFilter) ([]*CustomerDuplicate, int64, error)
+
+	// UpdateStatus stores the review of a suspected duplicate
+	UpdateStatus(ctx context.Context, duplicate *CustomerDuplicate) error
+
+	// Merge re-links everything the merged account owns to the surviving one
+	// and archives it, in one transaction. The counts of moved rows are set on
+	// the merge, which is stored with them; pairs involving the merged account
+	// are closed.
+	Merge(ctx context.Context, merge *CustomerMerge) error
+
+	// FindMerges retrieves the merges into or of a customer, newest first
+	FindMerges(ctx context.Context, customerID int64) ([]*CustomerMerge, error)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CustomerExportFilter selects the customers included in an export
+type CustomerExportFilter struct {
+	IncludeArchived bool
+	ActiveOnly      bool
+	RegisteredOnly  bool
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+	SearchQuery     string // Matches email, name or username
+	Tag             string // Only customers carrying the tag
+}
+
+// CustomerExportRow is the flat view of a customer written to exports
+type CustomerExportRow struct {
+	ID           int64
+	EmailAddress string
+	FirstName    string
+	LastName     string
+	UserName     string
+	IsRegistered bool
+	Deactivated  bool
+	Archived     bool
+	ReceiveEmail bool
+	LocaleCode   string
+	CreatedAt    time.Time
+}
+
+// CustomerExportRepository streams customers for exports
+type CustomerExportRepository interface {
+	// StreamCustomers calls fn for each matching customer, oldest first, without loading them all
+	StreamCustomers(ctx context.Context, filter *CustomerExportFilter, fn func(*CustomerExportRow) error) error
+}
@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ImportFormat is the file format of a customer import
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "CSV"
+	ImportFormatJSON ImportFormat = "JSON" // An array of customer objects
+)
+
+// DuplicateStrategy decides what happens to an imported customer whose email
+// is already registered
+type DuplicateStrategy string
+
+const (
+	DuplicateSkip   DuplicateStrategy = "SKIP"   // Keep the existing customer untouched
+	DuplicateUpdate DuplicateStrategy = "UPDATE" // Update the existing customer and add new addresses
+	DuplicateReject DuplicateStrategy = "REJECT" // Report the row as an error
+)
+
+// ImportJobStatus is where a customer import is in its run
+type ImportJobStatus string
+
+const (
+	ImportJobPending   ImportJobStatus = "PENDING"
+	ImportJobRunning   ImportJobStatus = "RUNNING"
+	ImportJobCompleted ImportJobStatus = "COMPLETED"
+	ImportJobFailed    ImportJobStatus = "FAILED"
+)
+
+// CustomerImportJob imports customers from an uploaded file in the
+// background. Cursor counts the records read and committed so an interrupted
+// job resumes after them; a running job holds a lease so a crashed worker's
+// job is picked up again once the lease expires.
+type CustomerImportJob struct {
+	ID                int64
+	Filename          string
+	FilePath          string
+	Format            ImportFormat
+	DuplicateStrategy DuplicateStrategy
+	Status            ImportJobStatus
+	Cursor            int64
+	Imported          int64
+	Updated           int64
+	Skipped           int64
+	Failed            int64
+	Attempts          int
+	LastError         string
+	RequestedBy       string
+	LeaseUntil        *time.Time
+	CreatedAt         time.Time
+	StartedAt         *time.Time
+	FinishedAt        *time.Time
+	UpdatedAt         time.Time
+}
+
+// NewCustomerImportJob queues the import of an uploaded file
+func NewCustomerImportJob(filename, filePath string, format ImportFormat, strategy DuplicateStrategy, requestedBy string) (*CustomerImportJob, error) {
+	switch format {
+	case ImportFormatCSV, ImportFormatJSON:
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+	switch strategy {
+	case DuplicateSkip, DuplicateUpdate, DuplicateReject:
+	default:
+		return nil, fmt.Errorf("unsupported duplicate strategy %q", strategy)
+	}
+
+	now := time.Now()
+	return &CustomerImportJob{
+		Filename:          filename,
+		FilePath:          filePath,
+		Format:            format,
+		DuplicateStrategy: strategy,
+		Status:            ImportJobPending,
+		RequestedBy:       requestedBy,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+// Advance records the outcome of a committed batch and moves the cursor past it
+func (j *CustomerImportJob) Advance(records, imported, updated, skipped, failed int64) {
+	j.Cursor += records
+	j.Imported += imported
+	j.Updated += updated
+	j.Skipped += skipped
+	j.Failed += failed
+	j.UpdatedAt = time.Now()
+}
+
+// Complete marks the job as completed
+func (j *CustomerImportJob) Complete() {
+	now := time.Now()
+	j.Status = ImportJobCompleted
+	j.LeaseUntil = nil
+	j.FinishedAt = &now
+	j.UpdatedAt = now
+}
+
+// Fail marks the job as failed, keeping the cursor for a later resume
+func (j *CustomerImportJob) Fail(err error) {
+	j.Status = ImportJobFailed
+	j.LeaseUntil = nil
+	if err != nil {
+		j.LastError = err.Error()
+	}
+	j.UpdatedAt = time.Now()
+}
+
+// Resume queues a failed job again, to continue from its cursor
+func (j *CustomerImportJob) Resume() error {
+	if j.Status != ImportJobFailed {
+		return fmt.Errorf("customer import %d is %s, only failed imports can be resumed", j.ID, j.Status)
+	}
+	j.Status = ImportJobPending
+	j.LastError = ""
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsFinished reports whether the job will not run again on its own
+func (j *CustomerImportJob) IsFinished() bool {
+	return j.Status == ImportJobCompleted || j.Status == ImportJobFailed
+}
+
+// CustomerImportRecord is a customer read from an import file. Row is its
+// 1-based position among the file's records.
+type CustomerImportRecord struct {
+	Row                  int64                    `json:"-"`
+	EmailAddress         string                   `json:"email_address"`
+	UserName             string                   `json:"user_name"`
+	FirstName            string                   `json:"first_name"`
+	LastName             string                   `json:"last_name"`
+	ExternalID           string                   `json:"external_id"`
+	LocaleCode           string                   `json:"locale_code"`
+	ReceiveEmail         *bool                    `json:"receive_email"`
+	PasswordHash         string                   `json:"password_hash"`
+	PasswordAlgorithm    string                   `json:"password_algorithm"` // bcrypt, sha1, sha256 or sha512
+	PasswordSalt         string                   `json:"password_salt"`
+	PasswordSaltPosition string                   `json:"password_salt_position"` // prefix, suffix or braces
+	PasswordEncoding     string                   `json:"password_encoding"`      // hex or base64
+	Addresses            []*CustomerImportAddress `json:"addresses"`
+}
+
+// CustomerImportAddress is an address of an imported customer
+type CustomerImportAddress struct {
+	AddressName         string `json:"address_name"`
+	FirstName           string `json:"first_name"`
+	LastName            string `json:"last_name"`
+	CompanyName         string `json:"company_name"`
+	AddressLine1        string `json:"address_line1"`
+	AddressLine2        string `json:"address_line2"`
+	City                string `json:"city"`
+	StateProvinceRegion string `json:"state_province_region"`
+	PostalCode          string `json:"postal_code"`
+	CountryCode         string `json:"country_code"`
+	PrimaryPhone        string `json:"phone"`
+}
+
+// CustomerImportError is a record of an import that could not be imported,
+// listed in the job's error report
+type CustomerImportError struct {
+	JobID        int64
+	Row          int64
+	EmailAddress string
+	Message      string
+	CreatedAt    time.Time
+}
+
+// CustomerImportRepository defines the interface for customer import persistence
+type CustomerImportRepository interface {
+	// Create stores a new import job
+	Create(ctx context.Context, job *CustomerImportJob) error
+
+	// FindByID retrieves an import job
+	FindByID(ctx context.Context, id int64) (*CustomerImportJob, error)
+
+	// FindAll retrieves import jobs, newest first
+	FindAll(ctx context.Context, page, pageSize int) ([]*CustomerImportJob, int64, error)
+
+	// Claim leases the oldest pending job, or a running job whose lease
+	// expired, marks it running and counts the attempt. It returns nil when
+	// there is nothing to run.
+	Claim(ctx context.Context, lease time.Duration) (*CustomerImportJob, error)
+
+	// SaveProgress stores the job's counters, cursor and status together with
+	// the errors of the batch it just committed, extending its lease by lease.
+	// It fails with a conflict when another worker claimed the job meanwhile.
+	SaveProgress(ctx context.Context, job *CustomerImportJob, errors []*CustomerImportError, lease time.Duration) error
+
+	// Resume queues a failed job again
+	Resume(ctx context.Context, job *CustomerImportJob) error
+
+	// StreamErrors calls fn for each error of a job, in row order
+	StreamErrors(ctx context.Context, jobID int64, fn func(*CustomerImportError) error) error
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CustomerNote is a note left on a customer account by staff. Notes are
+// internal unless marked customer-visible.
+type CustomerNote struct {
+	ID              int64
+	CustomerID      int64
+	Author          string
+	Body            string
+	CustomerVisible bool
+	CreatedAt       time.Time
+}
+
+// NewCustomerNote creates a new customer note
+func NewCustomerNote(customerID int64, author, body string, customerVisible bool) *CustomerNote {
+	return &CustomerNote{
+		CustomerID:      customerID,
+		Author:          author,
+		Body:            body,
+		CustomerVisible: customerVisible,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// CustomerNoteRepository defines the interface for customer note persistence
+type CustomerNoteRepository interface {
+	// Create stores a new customer note
+	Create(ctx context.Context, note *CustomerNote) error
+
+	// FindByCustomerID retrieves the notes of a customer, newest first.
+	// When customerVisibleOnly is set, internal notes are excluded.
+	FindByCustomerID(ctx context.Context, customerID int64, customerVisibleOnly bool) ([]*CustomerNote, error)
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/notification"
+)
+
+// maxNotificationTitleLength bounds the stored title of an inbox message
+const maxNotificationTitleLength = 255
+
+// CustomerNotification is a message in a customer's storefront inbox, such as an
+// order update, a promotion or a back-in-stock alert
+type CustomerNotification struct {
+	ID         int64
+	CustomerID int64
+	Category   string // notification.CategoryOrderUpdate, CategoryPromotion or CategoryBackInStock
+	Title      string
+	Body       string
+	Link       string // Storefront path the message leads to, if any
+	ReadAt     *time.Time
+	CreatedAt  time.Time
+}
+
+// NewCustomerNotification creates an unread inbox message
+func NewCustomerNotification(customerID int64, category, title, body, link string) (*CustomerNotification, error) {
+	if customerID <= 0 {
+		return nil, fmt.Errorf("notification customer must be positive")
+	}
+	if !IsNotificationCategory(category) {
+		return nil, fmt.Errorf("unknown notification category %q", category)
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("notification title is required")
+	}
+	if runes := []rune(title); len(runes) > maxNotificationTitleLength {
+		title = string(runes[:maxNotificationTitleLength])
+	}
+	return &CustomerNotification{
+		CustomerID: customerID,
+		Category:   category,
+		Title:      title,
+		Body:       strings.TrimSpace(body),
+		Link:       strings.TrimSpace(link),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// IsRead checks if the customer has read the message
+func (n *CustomerNotification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// IsNotificationCategory checks if a category can be kept in the inbox
+func IsNotificationCategory(category string) bool {
+	switch category {
+	case notification.CategoryOrderUpdate, notification.CategoryPromotion, notification.CategoryBackInStock:
+		return true
+	}
+	return false
+}
+
+// CustomerNotificationFilter selects and pages the messages of an inbox
+type CustomerNotificationFilter struct {
+	UnreadOnly bool
+	Category   string // Empty for all categories
+	Limit      int
+	Offset     int
+}
+
+// CustomerNotificationRepository defines the interface for customer inbox persistence
+type CustomerNotificationRepository interface {
+	// Create stores a new message and sets its ID.
+	Create(ctx context.Context, n *CustomerNotification) error
+
+	// FindByCustomerID retrieves a page of the messages of a customer, newest first,
+	// with the number of messages matching the filter.
+	FindByCustomerID(ctx context.Context, customerID int64, filter CustomerNotificationFilter) ([]*CustomerNotification, int64, error)
+
+	// CountUnread counts the unread messages of a customer.
+	CountUnread(ctx context.Context, customerID int64) (int64, error)
+
+	// MarkRead marks messages of a customer as read, returning how many were unread.
+	// Messages of other customers are left alone.
+	MarkRead(ctx context.Context, customerID int64, ids []int64, at time.Time) (int64, error)
+
+	// MarkAllRead marks every message of a customer as read, returning how many were unread.
+	MarkAllRead(ctx context.Context, customerID int64, at time.Time) (int64, error)
+}
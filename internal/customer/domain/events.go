@@ -13,8 +13,21 @@ const (
 	EventCustomerActivated       = "customer.activated"
 	EventCustomerPasswordChanged = "customer.password_changed"
 	EventCustomerArchived        = "customer.archived"
+	EventCustomerTagsChanged     = "customer.tags_changed"
+	EventCustomerMerged          = "customer.merged"
 )
 
+// Customer events are rebuilt as their own types when read from a serializing bus
+func init() {
+	event.RegisterType(EventCustomerRegistered, func() event.Event { return &CustomerRegisteredEvent{} })
+	event.RegisterType(EventCustomerUpdated, func() event.Event { return &CustomerUpdatedEvent{} })
+	event.RegisterType(EventCustomerDeactivated, func() event.Event { return &CustomerDeactivatedEvent{} })
+	event.RegisterType(EventCustomerActivated, func() event.Event { return &CustomerActivatedEvent{} })
+	event.RegisterType(EventCustomerPasswordChanged, func() event.Event { return &CustomerPasswordChangedEvent{} })
+	event.RegisterType(EventCustomerTagsChanged, func() event.Event { return &CustomerTagsChangedEvent{} })
+	event.RegisterType(EventCustomerMerged, func() event.Event { return &CustomerMergedEvent{} })
+}
+
 // CustomerRegisteredEvent is published when a customer registers
 type CustomerRegisteredEvent struct {
 	event.BaseEvent
@@ -134,3 +147,66 @@ func NewCustomerPasswordChangedEvent(customerID int64) *CustomerPasswordChangedE
 func (e *CustomerPasswordChangedEvent) Type() string {
 	return e.BaseEvent.Type
 }
+
+// CustomerTagsChangedEvent is published when a customer's tags or attributes change. It
+// carries the full current tags and attributes, so consumers such as CRM webhooks need
+// no further lookup.
+type CustomerTagsChangedEvent struct {
+	event.BaseEvent
+	CustomerID int64                  `json:"customer_id"`
+	Tags       []string               `json:"tags"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// NewCustomerTagsChangedEvent creates a new CustomerTagsChangedEvent
+func NewCustomerTagsChangedEvent(customerID int64, tags []string, attributes []*CustomerAttribute) *CustomerTagsChangedEvent {
+	if tags == nil {
+		tags = []string{}
+	}
+	values := make(map[string]interface{}, len(attributes))
+	for _, attribute := range attributes {
+		values[attribute.Name] = attribute.TypedValue()
+	}
+	return &CustomerTagsChangedEvent{
+		BaseEvent: event.BaseEvent{
+			Type:       EventCustomerTagsChanged,
+			OccurredOn: time.Now(),
+		},
+		CustomerID: customerID,
+		Tags:       tags,
+		Attributes: values,
+	}
+}
+
+// Type returns the event type
+func (e *CustomerTagsChangedEvent) Type() string {
+	return e.BaseEvent.Type
+}
+
+// CustomerMergedEvent is published when a duplicate account is merged into
+// another one. The merged account is archived; what it owned now belongs to
+// the surviving account.
+type CustomerMergedEvent struct {
+	event.BaseEvent
+	SurvivingCustomerID int64            `json:"surviving_customer_id"`
+	MergedCustomerID    int64            `json:"merged_customer_id"`
+	Moved               map[string]int64 `json:"moved"`
+}
+
+// NewCustomerMergedEvent creates a new CustomerMergedEvent
+func NewCustomerMergedEvent(merge *CustomerMerge) *CustomerMergedEvent {
+	return &CustomerMergedEvent{
+		BaseEvent: event.BaseEvent{
+			Type:       EventCustomerMerged,
+			OccurredOn: merge.MergedAt,
+		},
+		SurvivingCustomerID: merge.SurvivingCustomerID,
+		MergedCustomerID:    merge.MergedCustomerID,
+		Moved:               merge.Moved,
+	}
+}
+
+// Type returns the event type
+func (e *CustomerMergedEvent) Type() string {
+	return e.BaseEvent.Type
+}
@@ -53,6 +53,12 @@ type AddressRepository interface {
 
 	// FindByCustomerID retrieves addresses by customer ID
 	FindByCustomerID(ctx context.Context, customerID int64) ([]*CustomerAddress, error)
+
+	// CreateCustomerAddress creates an address and adds it to a customer's address book
+	CreateCustomerAddress(ctx context.Context, customerAddress *CustomerAddress) error
+
+	// UpdateCustomerAddress updates an address of a customer's address book and its name
+	UpdateCustomerAddress(ctx context.Context, customerAddress *CustomerAddress) error
 }
 
 // CustomerFilter represents filtering and pagination options for customers
@@ -65,6 +71,7 @@ type CustomerFilter struct {
 	SortBy          string // "name", "email", "created_at"
 	SortOrder       string // "asc", "desc"
 	SearchQuery     string
+	Tag             string // Only customers carrying the tag
 }
 
 // NewCustomerFilter creates a default customer filter
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// VisitorPreference is the locale and currency a storefront visitor selected on
+// a site. Signed-in customers keep their selection across sessions; anonymous
+// visitors keep it for their session.
+type VisitorPreference struct {
+	SiteID     string
+	CustomerID int64  // 0 for anonymous visitors
+	SessionID  string // Set for anonymous visitors
+	Locale     string
+	Currency   string
+	UpdatedAt  time.Time
+}
+
+// NewVisitorPreference creates the preference of a customer or, for anonymous
+// visitors, of their session
+func NewVisitorPreference(siteID string, customerID int64, sessionID, locale, currency string) *VisitorPreference {
+	preference := &VisitorPreference{
+		SiteID:     siteID,
+		CustomerID: customerID,
+		Locale:     locale,
+		Currency:   currency,
+		UpdatedAt:  time.Now(),
+	}
+	if customerID == 0 {
+		preference.SessionID = sessionID
+	}
+	return preference
+}
+
+// VisitorPreferenceRepository defines the interface for visitor preference persistence
+type VisitorPreferenceRepository interface {
+	// Save creates or replaces the preference of a customer or session on a site
+	Save(ctx context.Context, preference *VisitorPreference) error
+
+	// FindByCustomer returns the preference of a customer on a site, or nil
+	FindByCustomer(ctx context.Context, siteID string, customerID int64) (*VisitorPreference, error)
+
+	// FindBySession returns the preference of an anonymous session on a site, or nil
+	FindBySession(ctx context.Context, siteID, sessionID string) (*VisitorPreference, error)
+}
@@ -0,0 +1,231 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// addressQuerier is satisfied by both the connection pool and a transaction
+type addressQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// addressColumns are the blc_address columns scanned by scanAddress
+const addressColumns = `
+	a.address_id, a.address_line1, COALESCE(a.address_line2, ''), COALESCE(a.address_line3, ''),
+	a.city, COALESCE(a.company_name, ''), COALESCE(a.county, ''), COALESCE(a.first_name, ''),
+	COALESCE(a.last_name, ''), COALESCE(a.primary_phone, ''), COALESCE(a.postal_code, ''),
+	COALESCE(a.iso_country_sub, ''), COALESCE(a.iso_country_alpha2, ''),
+	COALESCE(a.standardized, false), COALESCE(a.verification_level, '')`
+
+// PostgresAddressRepository implements the AddressRepository interface using PostgreSQL
+type PostgresAddressRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAddressRepository creates a new PostgresAddressRepository
+func NewPostgresAddressRepository(db *database.DB) *PostgresAddressRepository {
+	return &PostgresAddressRepository{db: db}
+}
+
+// Create creates a new address
+func (r *PostgresAddressRepository) Create(ctx context.Context, address *domain.Address) error {
+	return insertAddress(ctx, r.db.Pool(), address)
+}
+
+// Update updates an existing address
+func (r *PostgresAddressRepository) Update(ctx context.Context, address *domain.Address) error {
+	return updateAddress(ctx, r.db.Pool(), address)
+}
+
+// Delete deletes an address by ID, removing it from any address book
+func (r *PostgresAddressRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM blc_customer_address WHERE address_id = $1`, id); err != nil {
+			return errors.InternalWrap(err, "failed to remove address from address books")
+		}
+		result, err := tx.Exec(ctx, `DELETE FROM blc_address WHERE address_id = $1`, id)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to delete address")
+		}
+		if result.RowsAffected() == 0 {
+			return errors.NotFound("address")
+		}
+		return nil
+	})
+}
+
+// FindByID retrieves an address by ID
+func (r *PostgresAddressRepository) FindByID(ctx context.Context, id int64) (*domain.Address, error) {
+	query := `SELECT ` + addressColumns + ` FROM blc_address a WHERE a.address_id = $1`
+
+	address, err := scanAddress(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find address")
+	}
+	return address, nil
+}
+
+// FindByCustomerID retrieves the addresses of a customer's address book that are not archived
+func (r *PostgresAddressRepository) FindByCustomerID(ctx context.Context, customerID int64) ([]*domain.CustomerAddress, error) {
+	query := `
+		SELECT ca.customer_address_id, COALESCE(ca.address_name, ''), ca.customer_id, ` + addressColumns + `
+		FROM blc_customer_address ca
+		JOIN blc_address a ON a.address_id = ca.address_id
+		WHERE ca.customer_id = $1 AND COALESCE(ca.archived, 'N') <> 'Y'
+		ORDER BY ca.customer_address_id`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer addresses")
+	}
+	defer rows.Close()
+
+	customerAddresses := make([]*domain.CustomerAddress, 0)
+	for rows.Next() {
+		customerAddress := &domain.CustomerAddress{Address: &domain.Address{}}
+		a := customerAddress.Address
+		if err := rows.Scan(
+			&customerAddress.ID, &customerAddress.AddressName, &customerAddress.CustomerID,
+			&a.ID, &a.AddressLine1, &a.AddressLine2, &a.AddressLine3,
+			&a.City, &a.CompanyName, &a.County, &a.FirstName,
+			&a.LastName, &a.PrimaryPhone, &a.PostalCode,
+			&a.StateProvinceRegion, &a.IsoCountryAlpha2,
+			&a.Standardized, &a.VerificationLevel,
+		); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan customer address")
+		}
+		a.CountryCode = a.IsoCountryAlpha2
+		customerAddress.AddressID = a.ID
+		customerAddresses = append(customerAddresses, customerAddress)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate customer addresses")
+	}
+	return customerAddresses, nil
+}
+
+// CreateCustomerAddress creates an address and adds it to a customer's address book
+func (r *PostgresAddressRepository) CreateCustomerAddress(ctx context.Context, customerAddress *domain.CustomerAddress) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := insertAddress(ctx, tx, customerAddress.Address); err != nil {
+			return err
+		}
+		customerAddress.AddressID = customerAddress.Address.ID
+
+		query := `
+			INSERT INTO blc_customer_address (address_name, archived, address_id, customer_id)
+			VALUES ($1, 'N', $2, $3)
+			RETURNING customer_address_id`
+		if err := tx.QueryRow(ctx, query,
+			customerAddress.AddressName, customerAddress.AddressID, customerAddress.CustomerID,
+		).Scan(&customerAddress.ID); err != nil {
+			return errors.InternalWrap(err, "failed to add address to customer")
+		}
+		return nil
+	})
+}
+
+// UpdateCustomerAddress updates an address of a customer's address book and its name
+func (r *PostgresAddressRepository) UpdateCustomerAddress(ctx context.Context, customerAddress *domain.CustomerAddress) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx,
+			`UPDATE blc_customer_address SET address_name = $1 WHERE customer_address_id = $2 AND customer_id = $3`,
+			customerAddress.AddressName, customerAddress.ID, customerAddress.CustomerID,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to update customer address")
+		}
+		if result.RowsAffected() == 0 {
+			return errors.NotFound("customer address")
+		}
+		return updateAddress(ctx, tx, customerAddress.Address)
+	})
+}
+
+func insertAddress(ctx context.Context, q addressQuerier, address *domain.Address) error {
+	query := `
+		INSERT INTO blc_address (
+			address_line1, address_line2, address_line3, city, company_name, county,
+			first_name, last_name, primary_phone, postal_code, iso_country_sub,
+			iso_country_alpha2, standardized, verification_level, is_active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, true)
+		RETURNING address_id`
+
+	if err := q.QueryRow(ctx, query, addressParams(address)...).Scan(&address.ID); err != nil {
+		return errors.InternalWrap(err, "failed to create address")
+	}
+	return nil
+}
+
+func updateAddress(ctx context.Context, q addressQuerier, address *domain.Address) error {
+	query := `
+		UPDATE blc_address SET
+			address_line1 = $1, address_line2 = $2, address_line3 = $3, city = $4,
+			company_name = $5, county = $6, first_name = $7, last_name = $8,
+			primary_phone = $9, postal_code = $10, iso_country_sub = $11,
+			iso_country_alpha2 = $12, standardized = $13, verification_level = $14
+		WHERE address_id = $15`
+
+	result, err := q.Exec(ctx, query, append(addressParams(address), address.ID)...)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update address")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("address")
+	}
+	return nil
+}
+
+// addressParams lists the address values bound by insertAddress and updateAddress
+func addressParams(address *domain.Address) []interface{} {
+	return []interface{}{
+		address.AddressLine1,
+		nullableString(address.AddressLine2),
+		nullableString(address.AddressLine3),
+		address.City,
+		nullableString(address.CompanyName),
+		nullableString(address.County),
+		nullableString(address.FirstName),
+		nullableString(address.LastName),
+		nullableString(address.PrimaryPhone),
+		nullableString(address.PostalCode),
+		nullableString(address.StateProvinceRegion),
+		nullableString(address.IsoCountryAlpha2),
+		address.Standardized,
+		nullableString(address.VerificationLevel),
+	}
+}
+
+func scanAddress(row pgx.Row) (*domain.Address, error) {
+	a := &domain.Address{}
+	if err := row.Scan(
+		&a.ID, &a.AddressLine1, &a.AddressLine2, &a.AddressLine3,
+		&a.City, &a.CompanyName, &a.County, &a.FirstName,
+		&a.LastName, &a.PrimaryPhone, &a.PostalCode,
+		&a.StateProvinceRegion, &a.IsoCountryAlpha2,
+		&a.Standardized, &a.VerificationLevel,
+	); err != nil {
+		return nil, err
+	}
+	a.CountryCode = a.IsoCountryAlpha2
+	return a, nil
+}
+
+// nullableString stores empty strings as NULL
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCustomerAttributeRepository implements the CustomerAttributeRepository interface using PostgreSQL
+type PostgresCustomerAttributeRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCustomerAttributeRepository creates a new PostgresCustomerAttributeRepository
+func NewPostgresCustomerAttributeRepository(db *database.DB) *PostgresCustomerAttributeRepository {
+	return &PostgresCustomerAttributeRepository{db: db}
+}
+
+// FindAttributes retrieves the attributes of a customer, ordered by name
+func (r *PostgresCustomerAttributeRepository) FindAttributes(ctx context.Context, customerID int64) ([]*domain.CustomerAttribute, error) {
+	query := `
+		SELECT customer_attr_id, name, COALESCE(value, ''), value_type, customer_id
+		FROM blc_customer_attribute
+		WHERE customer_id = $1
+		ORDER BY name`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer attributes")
+	}
+	defer rows.Close()
+
+	attributes := make([]*domain.CustomerAttribute, 0)
+	for rows.Next() {
+		attribute := &domain.CustomerAttribute{}
+		if err := rows.Scan(&attribute.ID, &attribute.Name, &attribute.Value, &attribute.Type, &attribute.CustomerID); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan customer attribute")
+		}
+		attributes = append(attributes, attribute)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate customer attributes")
+	}
+	return attributes, nil
+}
+
+// SaveAttributes creates or replaces the given attributes of a customer by name
+func (r *PostgresCustomerAttributeRepository) SaveAttributes(ctx context.Context, customerID int64, attributes []*domain.CustomerAttribute) error {
+	batch := &pgx.Batch{}
+	for _, attribute := range attributes {
+		batch.Queue(`
+			INSERT INTO blc_customer_attribute (customer_id, name, value, value_type)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (customer_id, name) DO UPDATE SET value = EXCLUDED.value, value_type = EXCLUDED.value_type
+			RETURNING customer_attr_id`,
+			customerID, attribute.Name, attribute.Value, attribute.Type,
+		).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&attribute.ID)
+		})
+	}
+
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return errors.InternalWrap(err, "failed to save customer attributes")
+		}
+		return nil
+	})
+}
+
+// DeleteAttribute removes an attribute of a customer
+func (r *PostgresCustomerAttributeRepository) DeleteAttribute(ctx context.Context, customerID int64, name string) error {
+	query := `DELETE FROM blc_customer_attribute WHERE customer_id = $1 AND name = $2`
+	tag, err := r.db.Pool().Exec(ctx, query, customerID, name)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete customer attribute")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("customer attribute")
+	}
+	return nil
+}
+
+// FindTags retrieves the tags of a customer, ordered by tag
+func (r *PostgresCustomerAttributeRepository) FindTags(ctx context.Context, customerID int64) ([]string, error) {
+	query := `SELECT tag FROM blc_customer_tag WHERE customer_id = $1 ORDER BY tag`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer tags")
+	}
+	tags, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to scan customer tags")
+	}
+	return tags, nil
+}
+
+// AddTags tags a customer; tags it already carries are ignored
+func (r *PostgresCustomerAttributeRepository) AddTags(ctx context.Context, customerID int64, tags []string) error {
+	query := `
+		INSERT INTO blc_customer_tag (customer_id, tag)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (customer_id, tag) DO NOTHING`
+	if err := r.db.Exec(ctx, query, customerID, tags); err != nil {
+		return errors.InternalWrap(err, "failed to add customer tags")
+	}
+	return nil
+}
+
+// RemoveTag removes a tag from a customer
+func (r *PostgresCustomerAttributeRepository) RemoveTag(ctx context.Context, customerID int64, tag string) error {
+	query := `DELETE FROM blc_customer_tag WHERE customer_id = $1 AND tag = $2`
+	result, err := r.db.Pool().Exec(ctx, query, customerID, tag)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to remove customer tag")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("customer tag")
+	}
+	return nil
+}
+
+// CountTags lists every tag in use with the number of customers carrying it
+func (r *PostgresCustomerAttributeRepository) CountTags(ctx context.Context) ([]*domain.CustomerTagCount, error) {
+	query := `
+		SELECT tag, COUNT(*)
+		FROM blc_customer_tag
+		GROUP BY tag
+		ORDER BY tag`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to count customer tags")
+	}
+	defer rows.Close()
+
+	counts := make([]*domain.CustomerTagCount, 0)
+	for rows.Next() {
+		count := &domain.CustomerTagCount{}
+		if err := rows.Scan(&count.Tag, &count.Customers); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan customer tag count")
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate customer tag counts")
+	}
+	return counts, nil
+}
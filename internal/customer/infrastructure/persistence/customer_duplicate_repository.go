@@ -0,0 +1,365 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCustomerDuplicateRepository implements the CustomerDuplicateRepository interface using PostgreSQL
+type PostgresCustomerDuplicateRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCustomerDuplicateRepository creates a new PostgresCustomerDuplicateRepository
+func NewPostgresCustomerDuplicateRepository(db *database.DB) *PostgresCustomerDuplicateRepository {
+	return &PostgresCustomerDuplicateRepository{db: db}
+}
+
+// detectDuplicatesQuery pairs active accounts reaching the same mailbox, and
+// accounts with the same name and a saved address on the same street and
+// postal code. Emails are compared without case and +tags, Gmail addresses
+// without dots; streets and postal codes without case, spaces or punctuation.
+const detectDuplicatesQuery = `
+	WITH customers AS (
+		SELECT customer_id,
+			LOWER(TRIM(COALESCE(email_address, ''))) AS email,
+			LOWER(TRIM(COALESCE(first_name, ''))) AS first_name,
+			LOWER(TRIM(COALESCE(last_name, ''))) AS last_name
+		FROM blc_customer
+		WHERE COALESCE(archived, false) = false AND COALESCE(is_preview, false) = false
+	),
+	mailboxes AS (
+		SELECT customer_id,
+			CASE WHEN domain IN ('gmail.com', 'googlemail.com') THEN REPLACE(local, '.', '') || '@gmail.com'
+				ELSE local || '@' || domain END AS mailbox
+		FROM (
+			SELECT customer_id, SPLIT_PART(SPLIT_PART(email, '@', 1), '+', 1) AS local, SPLIT_PART(email, '@', 2) AS domain
+			FROM customers
+			WHERE email LIKE '_%@_%'
+		) e
+		WHERE local <> ''
+	),
+	streets AS (
+		SELECT DISTINCT c.customer_id, c.first_name, c.last_name,
+			REGEXP_REPLACE(LOWER(a.address_line1), '[^a-z0-9]', '', 'g') AS street,
+			REGEXP_REPLACE(LOWER(COALESCE(a.postal_code, '')), '[^a-z0-9]', '', 'g') AS postal_code
+		FROM customers c
+		JOIN blc_customer_address ca ON ca.customer_id = c.customer_id AND COALESCE(ca.archived, 'N') <> 'Y'
+		JOIN blc_address a ON a.address_id = ca.address_id
+		WHERE c.first_name <> '' AND c.last_name <> ''
+	),
+	matches AS (
+		SELECT a.customer_id, b.customer_id AS other_customer_id, 'EMAIL_VARIANT'::text AS reason
+		FROM mailboxes a
+		JOIN mailboxes b ON b.mailbox = a.mailbox AND b.customer_id > a.customer_id
+		UNION
+		SELECT a.customer_id, b.customer_id, 'NAME_ADDRESS'::text
+		FROM streets a
+		JOIN streets b ON b.last_name = a.last_name AND b.first_name = a.first_name
+			AND b.street = a.street AND b.postal_code = a.postal_code AND b.customer_id > a.customer_id
+		WHERE a.street <> '' AND a.postal_code <> ''
+	),
+	pairs AS (
+		SELECT customer_id, other_customer_id, ARRAY_AGG(reason ORDER BY reason) AS reasons
+		FROM matches
+		GROUP BY customer_id, other_customer_id
+	)
+	INSERT INTO customer_duplicate (customer_id, other_customer_id, reasons, score, status, detected_at)
+	SELECT customer_id, other_customer_id, reasons,
+		LEAST(100,
+			CASE WHEN 'EMAIL_VARIANT' = ANY(reasons) THEN $1::int ELSE 0 END +
+			CASE WHEN 'NAME_ADDRESS' = ANY(reasons) THEN $2::int ELSE 0 END),
+		'PENDING', NOW()
+	FROM pairs
+	ON CONFLICT (customer_id, other_customer_id) DO UPDATE
+	SET reasons = EXCLUDED.reasons, score = EXCLUDED.score
+	WHERE customer_duplicate.status = 'PENDING' AND customer_duplicate.reasons <> EXCLUDED.reasons`
+
+// Detect queues the pairs of active accounts matching on any reason
+func (r *PostgresCustomerDuplicateRepository) Detect(ctx context.Context) (int64, error) {
+	var queued int64
+	err := r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			DELETE FROM customer_duplicate d
+			WHERE d.status = 'PENDING' AND EXISTS (
+				SELECT 1 FROM blc_customer c
+				WHERE c.customer_id IN (d.customer_id, d.other_customer_id) AND c.archived = true
+			)`)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to drop duplicates of archived customers")
+		}
+		tag, err := tx.Exec(ctx, detectDuplicatesQuery, domain.DuplicateScoreEmail, domain.DuplicateScoreNameAddress)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to detect duplicate customers")
+		}
+		queued = tag.RowsAffected()
+		return nil
+	})
+	return queued, err
+}
+
+const duplicateColumns = `
+	duplicate_id, customer_id, other_customer_id, reasons, score, status,
+	detected_at, COALESCE(reviewed_by, ''), reviewed_at`
+
+// FindByID retrieves a suspected duplicate
+func (r *PostgresCustomerDuplicateRepository) FindByID(ctx context.Context, id int64) (*domain.CustomerDuplicate, error) {
+	query := `SELECT ` + duplicateColumns + ` FROM customer_duplicate WHERE duplicate_id = $1`
+	duplicate, err := scanDuplicate(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound(fmt.Sprintf("customer duplicate %d", id))
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer duplicate")
+	}
+	return duplicate, nil
+}
+
+// FindAll retrieves suspected duplicates, highest score first
+func (r *PostgresCustomerDuplicateRepository) FindAll(ctx context.Context, filter *domain.DuplicateFilter) ([]*domain.CustomerDuplicate, int64, error) {
+	where := ` WHERE 1=1`
+	args := make([]interface{}, 0, 4)
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.CustomerID != 0 {
+		args = append(args, filter.CustomerID)
+		where += fmt.Sprintf(" AND (customer_id = $%d OR other_customer_id = $%d)", len(args), len(args))
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM customer_duplicate`+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count customer duplicates")
+	}
+
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query := `SELECT ` + duplicateColumns + ` FROM customer_duplicate` + where +
+		fmt.Sprintf(" ORDER BY score DESC, duplicate_id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to list customer duplicates")
+	}
+	defer rows.Close()
+
+	duplicates := make([]*domain.CustomerDuplicate, 0)
+	for rows.Next() {
+		duplicate, err := scanDuplicate(rows)
+		if err != nil {
+			return nil, 0, errors.InternalWrap(err, "failed to scan customer duplicate")
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to iterate customer duplicates")
+	}
+	return duplicates, total, nil
+}
+
+// UpdateStatus stores the review of a suspected duplicate
+func (r *PostgresCustomerDuplicateRepository) UpdateStatus(ctx context.Context, duplicate *domain.CustomerDuplicate) error {
+	query := `
+		UPDATE customer_duplicate
+		SET status = $2, reviewed_by = $3, reviewed_at = $4
+		WHERE duplicate_id = $1`
+	tag, err := r.db.Pool().Exec(ctx, query, duplicate.ID, duplicate.Status, duplicate.ReviewedBy, duplicate.ReviewedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update customer duplicate")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("customer duplicate %d", duplicate.ID))
+	}
+	return nil
+}
+
+// mergeMove re-links one kind of row from the merged account ($2) to the
+// surviving one ($1)
+type mergeMove struct {
+	name  string
+	query string
+}
+
+// mergeMoves lists what an account owns. Rows the survivor already has an
+// equivalent of (a phone or attribute of the same name, the same saved card,
+// tag, offer or watched SKU) stay with the archived account. Open carts are
+// not moved, so the survivor keeps a single one.
+//
+// Loyalty balances are not listed: no context stores points, credit or any
+// other per-customer balance yet. Whichever adds one must append its move
+// here (summing into the survivor's row, not re-linking a second one) so it
+// runs in the same transaction and shows up in the merge audit.
+var mergeMoves = []mergeMove{
+	{"orders", `UPDATE blc_order SET customer_id = $1 WHERE customer_id = $2 AND COALESCE(order_status, '') <> 'PENDING'`},
+	{"archived_orders", `UPDATE blc_order_archive SET customer_id = $1 WHERE customer_id = $2`},
+	{"addresses", `UPDATE blc_customer_address SET customer_id = $1 WHERE customer_id = $2`},
+	{"phones", `
+		UPDATE blc_customer_phone m SET customer_id = $1
+		WHERE m.customer_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM blc_customer_phone s WHERE s.customer_id = $1 AND s.phone_name = m.phone_name)`},
+	{"payment_methods", `
+		UPDATE blc_customer_payment m SET customer_id = $1, is_default = false
+		WHERE m.customer_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM blc_customer_payment s WHERE s.customer_id = $1 AND s.payment_token = m.payment_token)`},
+	{"attributes", `
+		UPDATE blc_customer_attribute m SET customer_id = $1
+		WHERE m.customer_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM blc_customer_attribute s WHERE s.customer_id = $1 AND s.name = m.name)`},
+	{"tags", `
+		UPDATE blc_customer_tag m SET customer_id = $1
+		WHERE m.customer_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM blc_customer_tag s WHERE s.customer_id = $1 AND s.tag = m.tag)`},
+	{"offers", `
+		UPDATE blc_customer_offer_xref m SET customer_id = $1
+		WHERE m.customer_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM blc_customer_offer_xref s WHERE s.customer_id = $1 AND s.offer_id = m.offer_id)`},
+	{"offer_uses", `UPDATE blc_offer_audit SET customer_id = $1 WHERE customer_id = $2`},
+	{"reviews", `UPDATE blc_review_detail SET customer_id = $1 WHERE customer_id = $2`},
+	{"ratings", `UPDATE blc_rating_detail SET customer_id = $1 WHERE customer_id = $2`},
+	{"review_feedback", `UPDATE blc_review_feedback SET customer_id = $1 WHERE customer_id = $2`},
+	{"notes", `UPDATE customer_note SET customer_id = $1 WHERE customer_id = $2`},
+	{"notifications", `UPDATE customer_notification SET customer_id = $1 WHERE customer_id = $2`},
+	{"product_registrations", `UPDATE product_registration SET customer_id = $1 WHERE customer_id = $2`},
+	{"price_watches", `
+		UPDATE catalog_price_watch m SET customer_id = $1
+		WHERE m.customer_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM catalog_price_watch s WHERE s.customer_id = $1 AND s.sku_id = m.sku_id)`},
+}
+
+// Merge re-links everything the merged account owns to the surviving one and archives it
+func (r *PostgresCustomerDuplicateRepository) Merge(ctx context.Context, merge *domain.CustomerMerge) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Both accounts are locked so a concurrent merge of either waits
+		rows, err := tx.Query(ctx, `
+			SELECT customer_id, COALESCE(archived, false) FROM blc_customer
+			WHERE customer_id IN ($1, $2) ORDER BY customer_id FOR UPDATE`,
+			merge.SurvivingCustomerID, merge.MergedCustomerID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to lock customers")
+		}
+		archived := make(map[int64]bool, 2)
+		for rows.Next() {
+			var id int64
+			var isArchived bool
+			if err := rows.Scan(&id, &isArchived); err != nil {
+				rows.Close()
+				return errors.InternalWrap(err, "failed to scan customer")
+			}
+			archived[id] = isArchived
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return errors.InternalWrap(err, "failed to lock customers")
+		}
+		for _, id := range []int64{merge.SurvivingCustomerID, merge.MergedCustomerID} {
+			isArchived, ok := archived[id]
+			if !ok {
+				return errors.NotFound(fmt.Sprintf("customer %d", id))
+			}
+			if isArchived {
+				return errors.Conflict(fmt.Sprintf("customer %d is archived", id))
+			}
+		}
+
+		for _, move := range mergeMoves {
+			tag, err := tx.Exec(ctx, move.query, merge.SurvivingCustomerID, merge.MergedCustomerID)
+			if err != nil {
+				return errors.InternalWrap(err, "failed to move customer "+move.name)
+			}
+			merge.Moved[move.name] = tag.RowsAffected()
+		}
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE blc_customer SET archived = true, deactivated = true, date_updated = $2
+			WHERE customer_id = $1`, merge.MergedCustomerID, merge.MergedAt); err != nil {
+			return errors.InternalWrap(err, "failed to archive merged customer")
+		}
+
+		moved, err := json.Marshal(merge.Moved)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to encode moved rows")
+		}
+		err = tx.QueryRow(ctx, `
+			INSERT INTO customer_merge (surviving_customer_id, merged_customer_id, merged_email, moved, merged_by, merged_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING merge_id`,
+			merge.SurvivingCustomerID, merge.MergedCustomerID, merge.MergedEmail, moved, merge.MergedBy, merge.MergedAt,
+		).Scan(&merge.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to record customer merge")
+		}
+
+		// The merged pair is closed; other pending pairs of the merged account
+		// are dropped, the next scan queues them against the survivor if they
+		// still match
+		low, high := merge.SurvivingCustomerID, merge.MergedCustomerID
+		if low > high {
+			low, high = high, low
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE customer_duplicate SET status = 'MERGED', reviewed_by = $3, reviewed_at = $4
+			WHERE customer_id = $1 AND other_customer_id = $2`, low, high, merge.MergedBy, merge.MergedAt); err != nil {
+			return errors.InternalWrap(err, "failed to close customer duplicate")
+		}
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM customer_duplicate
+			WHERE status = 'PENDING' AND (customer_id = $1 OR other_customer_id = $1)`, merge.MergedCustomerID); err != nil {
+			return errors.InternalWrap(err, "failed to drop duplicates of merged customer")
+		}
+		return nil
+	})
+}
+
+// FindMerges retrieves the merges into or of a customer, newest first
+func (r *PostgresCustomerDuplicateRepository) FindMerges(ctx context.Context, customerID int64) ([]*domain.CustomerMerge, error) {
+	query := `
+		SELECT merge_id, surviving_customer_id, merged_customer_id, merged_email, moved, merged_by, merged_at
+		FROM customer_merge
+		WHERE surviving_customer_id = $1 OR merged_customer_id = $1
+		ORDER BY merged_at DESC, merge_id DESC`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list customer merges")
+	}
+	defer rows.Close()
+
+	merges := make([]*domain.CustomerMerge, 0)
+	for rows.Next() {
+		merge := &domain.CustomerMerge{}
+		var moved []byte
+		if err := rows.Scan(&merge.ID, &merge.SurvivingCustomerID, &merge.MergedCustomerID, &merge.MergedEmail,
+			&moved, &merge.MergedBy, &merge.MergedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan customer merge")
+		}
+		if err := json.Unmarshal(moved, &merge.Moved); err != nil {
+			return nil, errors.InternalWrap(err, "failed to decode moved rows")
+		}
+		merges = append(merges, merge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate customer merges")
+	}
+	return merges, nil
+}
+
+func scanDuplicate(row pgx.Row) (*domain.CustomerDuplicate, error) {
+	duplicate := &domain.CustomerDuplicate{}
+	var reasons []string
+	if err := row.Scan(&duplicate.ID, &duplicate.CustomerID, &duplicate.OtherCustomerID, &reasons, &duplicate.Score,
+		&duplicate.Status, &duplicate.DetectedAt, &duplicate.ReviewedBy, &duplicate.ReviewedAt); err != nil {
+		return nil, err
+	}
+	duplicate.Reasons = make([]domain.DuplicateReason, len(reasons))
+	for i, reason := range reasons {
+		duplicate.Reasons[i] = domain.DuplicateReason(reason)
+	}
+	return duplicate, nil
+}
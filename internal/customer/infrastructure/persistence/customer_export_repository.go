@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCustomerExportRepository implements the CustomerExportRepository interface
+type PostgresCustomerExportRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCustomerExportRepository creates a new PostgresCustomerExportRepository
+func NewPostgresCustomerExportRepository(db *database.DB) *PostgresCustomerExportRepository {
+	return &PostgresCustomerExportRepository{db: db}
+}
+
+// StreamCustomers calls fn for each matching customer as rows arrive from the database
+func (r *PostgresCustomerExportRepository) StreamCustomers(ctx context.Context, filter *domain.CustomerExportFilter, fn func(*domain.CustomerExportRow) error) error {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	if !filter.IncludeArchived {
+		conditions = append(conditions, "archived = false")
+	}
+	if filter.ActiveOnly {
+		conditions = append(conditions, "deactivated = false")
+	}
+	if filter.RegisteredOnly {
+		conditions = append(conditions, "is_registered = true")
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("date_created >= $%d", len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("date_created < $%d", len(args)))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		conditions = append(conditions, fmt.Sprintf("customer_id IN (SELECT customer_id FROM blc_customer_tag WHERE tag = $%d)", len(args)))
+	}
+	if filter.SearchQuery != "" {
+		args = append(args, "%"+filter.SearchQuery+"%")
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf(
+			"(email_address ILIKE $%d OR first_name ILIKE $%d OR last_name ILIKE $%d OR user_name ILIKE $%d)", n, n, n, n))
+	}
+
+	query := `
+		SELECT customer_id, COALESCE(email_address, ''), COALESCE(first_name, ''), COALESCE(last_name, ''),
+			   COALESCE(user_name, ''), is_registered, deactivated, archived, receive_email,
+			   COALESCE(locale_code, ''), date_created
+		FROM blc_customer`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY customer_id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to export customers")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &domain.CustomerExportRow{}
+		if err := rows.Scan(
+			&row.ID, &row.EmailAddress, &row.FirstName, &row.LastName,
+			&row.UserName, &row.IsRegistered, &row.Deactivated, &row.Archived, &row.ReceiveEmail,
+			&row.LocaleCode, &row.CreatedAt,
+		); err != nil {
+			return errors.InternalWrap(err, "failed to scan exported customer")
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to iterate exported customers")
+	}
+	return nil
+}
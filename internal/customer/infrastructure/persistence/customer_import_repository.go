@@ -0,0 +1,201 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCustomerImportRepository implements the CustomerImportRepository interface using PostgreSQL
+type PostgresCustomerImportRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCustomerImportRepository creates a new PostgresCustomerImportRepository
+func NewPostgresCustomerImportRepository(db *database.DB) *PostgresCustomerImportRepository {
+	return &PostgresCustomerImportRepository{db: db}
+}
+
+const importJobColumns = `
+	job_id, filename, file_path, format, duplicate_strategy, status, cursor_position,
+	imported, updated, skipped, failed, attempts, COALESCE(last_error, ''), requested_by,
+	lease_until, created_at, started_at, finished_at, updated_at`
+
+// Create stores a new import job
+func (r *PostgresCustomerImportRepository) Create(ctx context.Context, job *domain.CustomerImportJob) error {
+	query := `
+		INSERT INTO customer_import_job (filename, file_path, format, duplicate_strategy, status, requested_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING job_id`
+	if err := r.db.QueryRow(ctx, query, job.Filename, job.FilePath, job.Format, job.DuplicateStrategy,
+		job.Status, job.RequestedBy, job.CreatedAt, job.UpdatedAt).Scan(&job.ID); err != nil {
+		return errors.InternalWrap(err, "failed to create customer import")
+	}
+	return nil
+}
+
+// FindByID retrieves an import job
+func (r *PostgresCustomerImportRepository) FindByID(ctx context.Context, id int64) (*domain.CustomerImportJob, error) {
+	query := `SELECT ` + importJobColumns + ` FROM customer_import_job WHERE job_id = $1`
+	job, err := scanImportJob(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound(fmt.Sprintf("customer import %d", id))
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer import")
+	}
+	return job, nil
+}
+
+// FindAll retrieves import jobs, newest first
+func (r *PostgresCustomerImportRepository) FindAll(ctx context.Context, page, pageSize int) ([]*domain.CustomerImportJob, int64, error) {
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM customer_import_job`).Scan(&total); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count customer imports")
+	}
+
+	query := `SELECT ` + importJobColumns + ` FROM customer_import_job ORDER BY job_id DESC LIMIT $1 OFFSET $2`
+	rows, err := r.db.Query(ctx, query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to list customer imports")
+	}
+	defer rows.Close()
+
+	jobs := make([]*domain.CustomerImportJob, 0)
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			return nil, 0, errors.InternalWrap(err, "failed to scan customer import")
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to iterate customer imports")
+	}
+	return jobs, total, nil
+}
+
+// Claim leases the oldest pending job, or a running job whose lease expired.
+// Jobs locked by another worker are skipped.
+func (r *PostgresCustomerImportRepository) Claim(ctx context.Context, lease time.Duration) (*domain.CustomerImportJob, error) {
+	query := `
+		UPDATE customer_import_job
+		SET status = 'RUNNING', lease_until = $1, attempts = attempts + 1,
+			started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE job_id = (
+			SELECT job_id FROM customer_import_job
+			WHERE status = 'PENDING' OR (status = 'RUNNING' AND lease_until < NOW())
+			ORDER BY created_at, job_id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + importJobColumns
+	job, err := scanImportJob(r.db.QueryRow(ctx, query, time.Now().Add(lease)))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to claim customer import")
+	}
+	return job, nil
+}
+
+// SaveProgress stores the job together with the errors of its last batch in
+// one transaction, provided no other worker has claimed it since
+func (r *PostgresCustomerImportRepository) SaveProgress(ctx context.Context, job *domain.CustomerImportJob, importErrors []*domain.CustomerImportError, lease time.Duration) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if len(importErrors) > 0 {
+			rows := make([][]interface{}, len(importErrors))
+			for i, e := range importErrors {
+				rows[i] = []interface{}{e.JobID, e.Row, e.EmailAddress, e.Message, e.CreatedAt}
+			}
+			if _, err := tx.CopyFrom(ctx,
+				pgx.Identifier{"customer_import_error"},
+				[]string{"job_id", "record_number", "email_address", "message", "created_at"},
+				pgx.CopyFromRows(rows),
+			); err != nil {
+				return errors.InternalWrap(err, "failed to store customer import errors")
+			}
+		}
+
+		if job.Status == domain.ImportJobRunning {
+			leaseUntil := time.Now().Add(lease)
+			job.LeaseUntil = &leaseUntil
+		}
+		query := `
+			UPDATE customer_import_job
+			SET status = $2, cursor_position = $3, imported = $4, updated = $5, skipped = $6, failed = $7,
+				last_error = NULLIF($8, ''), lease_until = $9, finished_at = $10, updated_at = $11
+			WHERE job_id = $1 AND attempts = $12`
+		tag, err := tx.Exec(ctx, query, job.ID, job.Status, job.Cursor, job.Imported, job.Updated, job.Skipped,
+			job.Failed, job.LastError, job.LeaseUntil, job.FinishedAt, job.UpdatedAt, job.Attempts)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to save customer import progress")
+		}
+		// Another worker claimed the job after this one's lease expired
+		if tag.RowsAffected() == 0 {
+			return errors.Conflict(fmt.Sprintf("customer import %d was claimed by another worker", job.ID))
+		}
+		return nil
+	})
+}
+
+// Resume queues a failed job again
+func (r *PostgresCustomerImportRepository) Resume(ctx context.Context, job *domain.CustomerImportJob) error {
+	query := `
+		UPDATE customer_import_job
+		SET status = $2, last_error = NULL, updated_at = $3
+		WHERE job_id = $1 AND status = 'FAILED'`
+	tag, err := r.db.Pool().Exec(ctx, query, job.ID, job.Status, job.UpdatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to resume customer import")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.Conflict(fmt.Sprintf("customer import %d is no longer failed", job.ID))
+	}
+	return nil
+}
+
+// StreamErrors calls fn for each error of a job, in row order
+func (r *PostgresCustomerImportRepository) StreamErrors(ctx context.Context, jobID int64, fn func(*domain.CustomerImportError) error) error {
+	query := `
+		SELECT job_id, record_number, email_address, message, created_at
+		FROM customer_import_error
+		WHERE job_id = $1
+		ORDER BY record_number, error_id`
+	rows, err := r.db.Query(ctx, query, jobID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to list customer import errors")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := &domain.CustomerImportError{}
+		if err := rows.Scan(&e.JobID, &e.Row, &e.EmailAddress, &e.Message, &e.CreatedAt); err != nil {
+			return errors.InternalWrap(err, "failed to scan customer import error")
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to iterate customer import errors")
+	}
+	return nil
+}
+
+func scanImportJob(row pgx.Row) (*domain.CustomerImportJob, error) {
+	job := &domain.CustomerImportJob{}
+	if err := row.Scan(&job.ID, &job.Filename, &job.FilePath, &job.Format, &job.DuplicateStrategy, &job.Status,
+		&job.Cursor, &job.Imported, &job.Updated, &job.Skipped, &job.Failed, &job.Attempts, &job.LastError,
+		&job.RequestedBy, &job.LeaseUntil, &job.CreatedAt, &job.StartedAt, &job.FinishedAt, &job.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
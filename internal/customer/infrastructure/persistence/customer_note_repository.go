@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCustomerNoteRepository implements the CustomerNoteRepository interface using PostgreSQL
+type PostgresCustomerNoteRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCustomerNoteRepository creates a new PostgresCustomerNoteRepository
+func NewPostgresCustomerNoteRepository(db *database.DB) *PostgresCustomerNoteRepository {
+	return &PostgresCustomerNoteRepository{db: db}
+}
+
+// Create stores a new customer note
+func (r *PostgresCustomerNoteRepository) Create(ctx context.Context, note *domain.CustomerNote) error {
+	query := `
+		INSERT INTO customer_note (customer_id, author, body, customer_visible, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING customer_note_id`
+	err := r.db.QueryRow(ctx, query, note.CustomerID, note.Author, note.Body, note.CustomerVisible, note.CreatedAt).
+		Scan(&note.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create customer note")
+	}
+	return nil
+}
+
+// FindByCustomerID retrieves the notes of a customer, newest first
+func (r *PostgresCustomerNoteRepository) FindByCustomerID(ctx context.Context, customerID int64, customerVisibleOnly bool) ([]*domain.CustomerNote, error) {
+	query := `
+		SELECT customer_note_id, customer_id, author, body, customer_visible, created_at
+		FROM customer_note
+		WHERE customer_id = $1`
+	if customerVisibleOnly {
+		query += ` AND customer_visible = TRUE`
+	}
+	query += ` ORDER BY created_at DESC, customer_note_id DESC`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list customer notes")
+	}
+	defer rows.Close()
+
+	notes := make([]*domain.CustomerNote, 0)
+	for rows.Next() {
+		note := &domain.CustomerNote{}
+		if err := rows.Scan(&note.ID, &note.CustomerID, &note.Author, &note.Body, &note.CustomerVisible, &note.CreatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan customer note")
+		}
+		notes = append(notes, note)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate customer notes")
+	}
+	return notes, nil
+}
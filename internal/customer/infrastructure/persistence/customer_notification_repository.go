@@ -0,0 +1,133 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCustomerNotificationRepository implements the CustomerNotificationRepository interface using PostgreSQL
+type PostgresCustomerNotificationRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCustomerNotificationRepository creates a new PostgresCustomerNotificationRepository
+func NewPostgresCustomerNotificationRepository(db *database.DB) *PostgresCustomerNotificationRepository {
+	return &PostgresCustomerNotificationRepository{db: db}
+}
+
+// Create stores a new message and sets its ID
+func (r *PostgresCustomerNotificationRepository) Create(ctx context.Context, n *domain.CustomerNotification) error {
+	query := `
+		INSERT INTO customer_notification (customer_id, category, title, body, link, read_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING customer_notification_id`
+	err := r.db.QueryRow(ctx, query, n.CustomerID, n.Category, n.Title, n.Body, n.Link, n.ReadAt, n.CreatedAt).
+		Scan(&n.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create customer notification")
+	}
+	return nil
+}
+
+// FindByCustomerID retrieves a page of the messages of a customer, newest first
+func (r *PostgresCustomerNotificationRepository) FindByCustomerID(ctx context.Context, customerID int64, filter domain.CustomerNotificationFilter) ([]*domain.CustomerNotification, int64, error) {
+	where := ` WHERE customer_id = $1`
+	args := []interface{}{customerID}
+	if filter.UnreadOnly {
+		where += ` AND read_at IS NULL`
+	}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		where += fmt.Sprintf(` AND category = $%d`, len(args))
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM customer_notification`+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count customer notifications")
+	}
+
+	query := `
+		SELECT customer_notification_id, customer_id, category, title, body, link, read_at, created_at
+		FROM customer_notification` + where + `
+		ORDER BY created_at DESC, customer_notification_id DESC` +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to list customer notifications")
+	}
+	defer rows.Close()
+
+	notifications := make([]*domain.CustomerNotification, 0)
+	for rows.Next() {
+		n, err := scanCustomerNotification(rows)
+		if err != nil {
+			return nil, 0, errors.InternalWrap(err, "failed to scan customer notification")
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to iterate customer notifications")
+	}
+	return notifications, total, nil
+}
+
+// CountUnread counts the unread messages of a customer
+func (r *PostgresCustomerNotificationRepository) CountUnread(ctx context.Context, customerID int64) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM customer_notification WHERE customer_id = $1 AND read_at IS NULL`,
+		customerID,
+	).Scan(&count)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to count unread customer notifications")
+	}
+	return count, nil
+}
+
+// MarkRead marks unread messages of a customer as read
+func (r *PostgresCustomerNotificationRepository) MarkRead(ctx context.Context, customerID int64, ids []int64, at time.Time) (int64, error) {
+	tag, err := r.db.Pool().Exec(ctx, `
+		UPDATE customer_notification SET read_at = $3
+		WHERE customer_id = $1 AND customer_notification_id = ANY($2) AND read_at IS NULL`,
+		customerID, ids, at,
+	)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to mark customer notifications read")
+	}
+	return tag.RowsAffected(), nil
+}
+
+// MarkAllRead marks every unread message of a customer as read
+func (r *PostgresCustomerNotificationRepository) MarkAllRead(ctx context.Context, customerID int64, at time.Time) (int64, error) {
+	tag, err := r.db.Pool().Exec(ctx, `
+		UPDATE customer_notification SET read_at = $2
+		WHERE customer_id = $1 AND read_at IS NULL`,
+		customerID, at,
+	)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to mark customer notifications read")
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanCustomerNotification(row pgx.Row) (*domain.CustomerNotification, error) {
+	n := &domain.CustomerNotification{}
+	var readAt sql.NullTime
+	if err := row.Scan(&n.ID, &n.CustomerID, &n.Category, &n.Title, &n.Body, &n.Link, &readAt, &n.CreatedAt); err != nil {
+		return nil, err
+	}
+	if readAt.Valid {
+		n.ReadAt = &readAt.Time
+	}
+	return n, nil
+}
@@ -396,6 +396,11 @@ func (r *PostgresCustomerRepository) FindAll(ctx context.Context, filter *domain
 		if !filter.IncludeArchived {
 			query += " AND archived = false"
 		}
+		if filter.Tag != "" {
+			query += fmt.Sprintf(" AND customer_id IN (SELECT customer_id FROM blc_customer_tag WHERE tag = $%d)", argIndex)
+			args = append(args, filter.Tag)
+			argIndex++
+		}
 	}
 
 	// Count total
@@ -408,6 +413,10 @@ func (r *PostgresCustomerRepository) FindAll(ctx context.Context, filter *domain
 		if !filter.IncludeArchived {
 			countQuery += " AND archived = false"
 		}
+		if filter.Tag != "" {
+			countQuery += " AND customer_id IN (SELECT customer_id FROM blc_customer_tag WHERE tag = $1)"
+			countArgs = append(countArgs, filter.Tag)
+		}
 	}
 
 	var total int64
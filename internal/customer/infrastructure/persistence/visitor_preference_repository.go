@@ -0,0 +1,85 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresVisitorPreferenceRepository implements the VisitorPreferenceRepository interface using PostgreSQL
+type PostgresVisitorPreferenceRepository struct {
+	db *database.DB
+}
+
+// NewPostgresVisitorPreferenceRepository creates a new PostgresVisitorPreferenceRepository
+func NewPostgresVisitorPreferenceRepository(db *database.DB) *PostgresVisitorPreferenceRepository {
+	return &PostgresVisitorPreferenceRepository{db: db}
+}
+
+const visitorPreferenceColumns = `site_id, customer_id, session_id, locale, currency, updated_at`
+
+// Save creates or replaces the preference of a customer or session on a site
+func (r *PostgresVisitorPreferenceRepository) Save(ctx context.Context, preference *domain.VisitorPreference) error {
+	var query string
+	var owner interface{}
+	if preference.CustomerID != 0 {
+		query = `
+			INSERT INTO storefront_visitor_preference (site_id, customer_id, locale, currency, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (site_id, customer_id) WHERE customer_id IS NOT NULL DO UPDATE SET
+				locale = EXCLUDED.locale, currency = EXCLUDED.currency, updated_at = EXCLUDED.updated_at`
+		owner = preference.CustomerID
+	} else {
+		query = `
+			INSERT INTO storefront_visitor_preference (site_id, session_id, locale, currency, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (site_id, session_id) WHERE customer_id IS NULL DO UPDATE SET
+				locale = EXCLUDED.locale, currency = EXCLUDED.currency, updated_at = EXCLUDED.updated_at`
+		owner = preference.SessionID
+	}
+
+	err := r.db.Exec(ctx, query, preference.SiteID, owner, preference.Locale, preference.Currency, preference.UpdatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save visitor preference")
+	}
+	return nil
+}
+
+// FindByCustomer returns the preference of a customer on a site, or nil
+func (r *PostgresVisitorPreferenceRepository) FindByCustomer(ctx context.Context, siteID string, customerID int64) (*domain.VisitorPreference, error) {
+	query := `SELECT ` + visitorPreferenceColumns + `
+		FROM storefront_visitor_preference
+		WHERE site_id = $1 AND customer_id = $2`
+	return r.findOne(ctx, query, siteID, customerID)
+}
+
+// FindBySession returns the preference of an anonymous session on a site, or nil
+func (r *PostgresVisitorPreferenceRepository) FindBySession(ctx context.Context, siteID, sessionID string) (*domain.VisitorPreference, error) {
+	query := `SELECT ` + visitorPreferenceColumns + `
+		FROM storefront_visitor_preference
+		WHERE site_id = $1 AND session_id = $2 AND customer_id IS NULL`
+	return r.findOne(ctx, query, siteID, sessionID)
+}
+
+func (r *PostgresVisitorPreferenceRepository) findOne(ctx context.Context, query string, args ...interface{}) (*domain.VisitorPreference, error) {
+	preference := &domain.VisitorPreference{}
+	var customerID sql.NullInt64
+	var sessionID sql.NullString
+	err := r.db.QueryRow(ctx, query, args...).Scan(
+		&preference.SiteID, &customerID, &sessionID, &preference.Locale, &preference.Currency, &preference.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find visitor preference")
+	}
+	preference.CustomerID = customerID.Int64
+	preference.SessionID = sessionID.String
+	return preference, nil
+}
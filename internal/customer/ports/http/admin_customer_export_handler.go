@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/customer/application"
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/export"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminCustomerExportHandler handles customer list exports
+type AdminCustomerExportHandler struct {
+	exportRepo     domain.CustomerExportRepository
+	jobs           *export.JobManager
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminCustomerExportHandler creates a new AdminCustomerExportHandler
+func NewAdminCustomerExportHandler(
+	exportRepo domain.CustomerExportRepository,
+	jobs *export.JobManager,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminCustomerExportHandler {
+	return &AdminCustomerExportHandler{
+		exportRepo:     exportRepo,
+		jobs:           jobs,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers customer export routes; exports require the admin role
+func (h *AdminCustomerExportHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Use(middleware.RequireRole("admin"))
+		r.Get("/admin/exports/customers", h.ExportCustomers)
+	})
+}
+
+// ExportCustomers streams the filtered customer list as CSV, or queues it with ?async=true.
+// Filters: include_archived, active_only, registered_only, q, tag, created_from, created_to
+func (h *AdminCustomerExportHandler) ExportCustomers(w http.ResponseWriter, r *http.Request) {
+	from, to, err := export.ParseCreatedRange(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	values := r.URL.Query()
+	filter := &domain.CustomerExportFilter{
+		IncludeArchived: values.Get("include_archived") == "true",
+		ActiveOnly:      values.Get("active_only") == "true",
+		RegisteredOnly:  values.Get("registered_only") == "true",
+		CreatedFrom:     from,
+		CreatedTo:       to,
+		SearchQuery:     values.Get("q"),
+		Tag:             strings.ToLower(strings.TrimSpace(values.Get("tag"))),
+	}
+
+	export.Serve(w, r, h.jobs, application.NewCustomerExport(h.exportRepo, filter), h.log)
+}
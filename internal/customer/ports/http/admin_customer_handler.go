@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/qhato/ecommerce/internal/customer/application/commands"
 	"github.com/qhato/ecommerce/internal/customer/application/queries"
 	httpPkg "github.com/qhato/ecommerce/pkg/http"
 	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
 	"github.com/qhato/ecommerce/pkg/validator"
 	"github.com/qhato/ecommerce/pkg/errors" // Import pkg/errors
 )
@@ -47,6 +49,8 @@ func (h *AdminCustomerHandler) RegisterRoutes(r chi.Router) {
 		r.Put("/{id}/password", h.ChangePassword)
 		r.Post("/{id}/deactivate", h.DeactivateCustomer)
 		r.Post("/{id}/activate", h.ActivateCustomer)
+		r.Get("/{id}/notes", h.ListCustomerNotes)
+		r.Post("/{id}/notes", h.AddCustomerNote)
 		r.Get("/email/{email}", h.GetCustomerByEmail)
 	})
 }
@@ -143,6 +147,7 @@ func (h *AdminCustomerHandler) ListCustomers(w http.ResponseWriter, r *http.Requ
 	activeOnly := r.URL.Query().Get("active_only") == "true"
 	registeredOnly := r.URL.Query().Get("registered_only") == "true"
 	searchQuery := r.URL.Query().Get("q")
+	tag := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag")))
 
 
 	query := &queries.ListCustomersQuery{ // Use query struct
@@ -154,6 +159,7 @@ func (h *AdminCustomerHandler) ListCustomers(w http.ResponseWriter, r *http.Requ
 		ActiveOnly:      activeOnly,
 		RegisteredOnly:  registeredOnly,
 		SearchQuery:     searchQuery,
+		Tag:             tag,
 	}
 
 	result, err := h.queryHandler.HandleListCustomers(r.Context(), query) // Call HandleListCustomers
@@ -300,3 +306,56 @@ func (h *AdminCustomerHandler) ActivateCustomer(w http.ResponseWriter, r *http.R
 
 	httpPkg.RespondJSON(w, http.StatusOK, map[string]string{"message": "customer activated successfully"})
 }
+
+// AddCustomerNote adds an internal or customer-visible note to a customer
+func (h *AdminCustomerHandler) AddCustomerNote(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid customer ID").WithInternal(err))
+		return
+	}
+
+	var cmd commands.AddCustomerNoteCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	cmd.CustomerID = id
+
+	// The authenticated admin is the author when one is known
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		cmd.Author = email
+	}
+
+	note, err := h.commandHandler.HandleAddCustomerNote(r.Context(), &cmd)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			httpPkg.RespondError(w, errors.NotFound(err.Error()))
+		} else {
+			httpPkg.RespondError(w, err)
+		}
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, note)
+}
+
+// ListCustomerNotes lists all notes of a customer, internal and customer-visible
+func (h *AdminCustomerHandler) ListCustomerNotes(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid customer ID").WithInternal(err))
+		return
+	}
+
+	query := &queries.ListCustomerNotesQuery{CustomerID: id}
+	notes, err := h.queryHandler.HandleListCustomerNotes(r.Context(), query)
+	if err != nil {
+		httpPkg.RespondError(w, errors.Internal("failed to list customer notes").WithInternal(err))
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, notes)
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/customer/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/export"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminCustomerImportHandler handles bulk customer imports from other platforms
+type AdminCustomerImportHandler struct {
+	importService  application.CustomerImportService
+	jobs           *export.JobManager
+	maxUploadBytes int64
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminCustomerImportHandler creates a new AdminCustomerImportHandler.
+// maxUploadBytes bounds uploaded import files.
+func NewAdminCustomerImportHandler(
+	importService application.CustomerImportService,
+	jobs *export.JobManager,
+	maxUploadBytes int64,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminCustomerImportHandler {
+	return &AdminCustomerImportHandler{
+		importService:  importService,
+		jobs:           jobs,
+		maxUploadBytes: maxUploadBytes,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers customer import routes; imports require the admin role
+func (h *AdminCustomerImportHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Use(middleware.RequireRole("admin"))
+		r.Route("/admin/customer-imports", func(r chi.Router) {
+			r.Post("/", h.StartImport)
+			r.Get("/", h.ListImports)
+			r.Get("/{importId}", h.GetImport)
+			r.Post("/{importId}/resume", h.ResumeImport)
+			r.Get("/{importId}/errors", h.ExportErrors)
+		})
+	})
+}
+
+// StartImport queues the import of the "file" field of a multipart form, a
+// CSV or a JSON array of customers. Form fields: format (CSV or JSON, from the
+// file extension by default) and duplicates (SKIP, UPDATE or REJECT, SKIP by
+// default). Responds 202 with the queued import.
+func (h *AdminCustomerImportHandler) StartImport(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadBytes)
+
+	upload, header, err := r.FormFile("file")
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("customer import requires a file field").WithInternal(err))
+		return
+	}
+	defer upload.Close()
+
+	req := &application.StartCustomerImportRequest{
+		Filename:          header.Filename,
+		Format:            r.FormValue("format"),
+		DuplicateStrategy: r.FormValue("duplicates"),
+	}
+	job, err := h.importService.StartImport(r.Context(), req, upload, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).Error("failed to start customer import")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusAccepted, job)
+}
+
+// ListImports lists customer imports, newest first
+func (h *AdminCustomerImportHandler) ListImports(w http.ResponseWriter, r *http.Request) {
+	pagination := httpPkg.GetPaginationParams(r)
+
+	result, err := h.importService.ListImports(r.Context(), pagination.Page, pagination.PerPage)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// GetImport retrieves a customer import with its progress
+func (h *AdminCustomerImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.importID(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.importService.GetImport(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, job)
+}
+
+// ResumeImport queues a failed import again; it continues after the last committed batch
+func (h *AdminCustomerImportHandler) ResumeImport(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.importID(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.importService.ResumeImport(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusAccepted, job)
+}
+
+// ExportErrors streams the records an import could not import as CSV, or queues them with ?async=true
+func (h *AdminCustomerImportHandler) ExportErrors(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.importID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.importService.ErrorReport(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	export.Serve(w, r, h.jobs, report, h.log)
+}
+
+func (h *AdminCustomerImportHandler) importID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "importId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, httpPkg.NewValidationError("invalid import ID"))
+		return 0, false
+	}
+	return id, true
+}
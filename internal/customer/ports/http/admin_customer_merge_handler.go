@@ -0,0 +1,195 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/customer/application"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminCustomerMergeHandler handles the duplicate account review queue and account merges
+type AdminCustomerMergeHandler struct {
+	mergeService   application.CustomerMergeService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminCustomerMergeHandler creates a new AdminCustomerMergeHandler
+func NewAdminCustomerMergeHandler(
+	mergeService application.CustomerMergeService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminCustomerMergeHandler {
+	return &AdminCustomerMergeHandler{
+		mergeService:   mergeService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers duplicate review and merge routes
+func (h *AdminCustomerMergeHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/customer-duplicates", func(r chi.Router) {
+			r.Get("/", h.ListDuplicates)
+			r.Post("/scan", h.ScanDuplicates)
+			r.Get("/{duplicateId}", h.GetDuplicate)
+			r.Post("/{duplicateId}/dismiss", h.DismissDuplicate)
+			r.Post("/{duplicateId}/merge", h.MergeDuplicate)
+		})
+		r.Post("/admin/customers/{id}/merge", h.MergeCustomers)
+		r.Get("/admin/customers/{id}/merges", h.ListMerges)
+	})
+}
+
+// ListDuplicates lists suspected duplicates, highest score first. ?status=
+// defaults to PENDING, ALL lists every status; ?customer_id= lists one account's pairs.
+func (h *AdminCustomerMergeHandler) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	status := strings.ToUpper(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = "PENDING"
+	case "ALL":
+		status = ""
+	}
+	var customerID int64
+	if raw := r.URL.Query().Get("customer_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			httpPkg.RespondError(w, httpPkg.NewValidationError("invalid customer ID"))
+			return
+		}
+		customerID = id
+	}
+	pagination := httpPkg.GetPaginationParams(r)
+
+	result, err := h.mergeService.ListDuplicates(r.Context(), status, customerID, pagination.Page, pagination.PerPage)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// ScanDuplicates queues the pairs of accounts that look like the same person
+func (h *AdminCustomerMergeHandler) ScanDuplicates(w http.ResponseWriter, r *http.Request) {
+	result, err := h.mergeService.ScanDuplicates(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to scan for duplicate customers")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// GetDuplicate retrieves a suspected duplicate with both accounts
+func (h *AdminCustomerMergeHandler) GetDuplicate(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseDuplicateID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.mergeService.GetDuplicate(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// DismissDuplicate records that the accounts belong to different people
+func (h *AdminCustomerMergeHandler) DismissDuplicate(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseDuplicateID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.mergeService.DismissDuplicate(r.Context(), id, middleware.GetUserID(r.Context()))
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// MergeDuplicate merges the other account of a suspected duplicate into the one kept
+func (h *AdminCustomerMergeHandler) MergeDuplicate(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseDuplicateID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.MergeDuplicateRequest
+	if err := httpPkg.DecodeJSON(r, &req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	result, err := h.mergeService.MergeDuplicate(r.Context(), id, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).WithField("duplicate_id", id).Error("failed to merge duplicate customers")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// MergeCustomers merges another account into the customer
+func (h *AdminCustomerMergeHandler) MergeCustomers(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.MergeCustomerRequest
+	if err := httpPkg.DecodeJSON(r, &req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	result, err := h.mergeService.MergeCustomers(r.Context(), customerID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to merge customers")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// ListMerges lists the merges into or of a customer
+func (h *AdminCustomerMergeHandler) ListMerges(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.mergeService.ListMerges(r.Context(), customerID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// parseDuplicateID reads the duplicate ID route parameter, responding with an error when it is invalid
+func parseDuplicateID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "duplicateId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, httpPkg.NewValidationError("invalid duplicate ID"))
+		return 0, false
+	}
+	return id, true
+}
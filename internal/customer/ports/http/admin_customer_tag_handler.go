@@ -0,0 +1,162 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/customer/application"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminCustomerTagHandler handles customer tag and attribute requests from the admin and CRM integrations
+type AdminCustomerTagHandler struct {
+	attributeService application.CustomerAttributeService
+	authMiddleware   func(http.Handler) http.Handler
+	log              *logger.Logger
+}
+
+// NewAdminCustomerTagHandler creates a new AdminCustomerTagHandler
+func NewAdminCustomerTagHandler(
+	attributeService application.CustomerAttributeService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminCustomerTagHandler {
+	return &AdminCustomerTagHandler{
+		attributeService: attributeService,
+		authMiddleware:   authMiddleware,
+		log:              log,
+	}
+}
+
+// RegisterRoutes registers customer tag and attribute routes
+func (h *AdminCustomerTagHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/customer-tags", h.ListTags)
+		r.Route("/admin/customers/{id}", func(r chi.Router) {
+			r.Get("/tags", h.GetCustomerTags)
+			r.Post("/tags", h.AddTags)
+			r.Delete("/tags/{tag}", h.RemoveTag)
+			r.Put("/attributes", h.SetAttributes)
+			r.Delete("/attributes/{name}", h.DeleteAttribute)
+		})
+	})
+}
+
+// ListTags lists every tag in use with the number of customers carrying it
+func (h *AdminCustomerTagHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.attributeService.ListTags(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list customer tags")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, tags)
+}
+
+// GetCustomerTags retrieves the tags and attributes of a customer
+func (h *AdminCustomerTagHandler) GetCustomerTags(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.attributeService.GetCustomerTags(r.Context(), customerID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// AddTags tags a customer
+func (h *AdminCustomerTagHandler) AddTags(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.AddCustomerTagsRequest
+	if err := httpPkg.DecodeJSON(r, &req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	result, err := h.attributeService.AddTags(r.Context(), customerID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to tag customer")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// RemoveTag removes a tag from a customer
+func (h *AdminCustomerTagHandler) RemoveTag(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.attributeService.RemoveTag(r.Context(), customerID, chi.URLParam(r, "tag"))
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// SetAttributes creates or replaces attributes of a customer by name
+func (h *AdminCustomerTagHandler) SetAttributes(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.SetCustomerAttributesRequest
+	if err := httpPkg.DecodeJSON(r, &req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	result, err := h.attributeService.SetAttributes(r.Context(), customerID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to set customer attributes")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// DeleteAttribute removes an attribute of a customer
+func (h *AdminCustomerTagHandler) DeleteAttribute(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.attributeService.DeleteAttribute(r.Context(), customerID, chi.URLParam(r, "name"))
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// parseCustomerID reads the customer ID route parameter, responding with an error when it is invalid
+func parseCustomerID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	customerID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, httpPkg.NewValidationError("invalid customer ID"))
+		return 0, false
+	}
+	return customerID, true
+}
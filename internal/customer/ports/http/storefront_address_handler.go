@@ -0,0 +1,134 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/customer/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/requestctx"
+)
+
+// StorefrontAddressHandler handles address validation and the signed-in customer's address book.
+// Rejected addresses are answered with an ADDRESS_INVALID error whose details carry the
+// validation, including any suggested corrections.
+type StorefrontAddressHandler struct {
+	addressService application.AddressService
+	log            *logger.Logger
+}
+
+// NewStorefrontAddressHandler creates a new StorefrontAddressHandler
+func NewStorefrontAddressHandler(addressService application.AddressService, log *logger.Logger) *StorefrontAddressHandler {
+	return &StorefrontAddressHandler{
+		addressService: addressService,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers address routes
+func (h *StorefrontAddressHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/addresses/validate", h.ValidateAddress)
+	r.Route("/account/addresses", func(r chi.Router) {
+		r.Get("/", h.ListAddresses)
+		r.Post("/", h.CreateAddress)
+		r.Put("/{id}", h.UpdateAddress)
+	})
+}
+
+// ValidateAddress normalizes an address and returns the verdict with suggested corrections
+func (h *StorefrontAddressHandler) ValidateAddress(w http.ResponseWriter, r *http.Request) {
+	var req application.AddressDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	validation, err := h.addressService.ValidateAddress(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to validate address")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, validation)
+}
+
+// ListAddresses lists the signed-in customer's address book
+func (h *StorefrontAddressHandler) ListAddresses(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := signedInCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	addresses, err := h.addressService.ListAddresses(r.Context(), customerID)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to list addresses")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, addresses)
+}
+
+// CreateAddress validates an address and adds it to the signed-in customer's address book
+func (h *StorefrontAddressHandler) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := signedInCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.SaveAddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	address, err := h.addressService.CreateAddress(r.Context(), customerID, &req)
+	if err != nil {
+		errors.HandleHTTPError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, address)
+}
+
+// UpdateAddress validates and replaces an address of the signed-in customer's address book
+func (h *StorefrontAddressHandler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := signedInCustomer(w, r)
+	if !ok {
+		return
+	}
+	customerAddressID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, httpPkg.NewValidationError("invalid address ID"))
+		return
+	}
+
+	var req application.SaveAddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	address, err := h.addressService.UpdateAddress(r.Context(), customerID, customerAddressID, &req)
+	if err != nil {
+		errors.HandleHTTPError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, address)
+}
+
+// signedInCustomer reads the customer of the request, responding with an error for anonymous shoppers
+func signedInCustomer(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	customerID := requestctx.CustomerID(r.Context())
+	if customerID == 0 {
+		httpPkg.RespondError(w, errors.Unauthorized("sign in to manage addresses"))
+		return 0, false
+	}
+	return customerID, true
+}
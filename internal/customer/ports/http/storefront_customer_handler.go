@@ -8,6 +8,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/qhato/ecommerce/internal/customer/application/commands"
 	"github.com/qhato/ecommerce/internal/customer/application/queries"
+	"github.com/qhato/ecommerce/pkg/auth"
 	httpPkg "github.com/qhato/ecommerce/pkg/http"
 	"github.com/qhato/ecommerce/pkg/logger"
 	"github.com/qhato/ecommerce/pkg/validator"
@@ -18,6 +19,7 @@ import (
 type StorefrontCustomerHandler struct {
 	commandHandler *commands.CustomerCommandHandler
 	queryHandler   *queries.CustomerQueryHandler
+	jwtService     *auth.JWTService
 	validator      *validator.Validator
 	log            *logger.Logger
 }
@@ -26,12 +28,14 @@ type StorefrontCustomerHandler struct {
 func NewStorefrontCustomerHandler(
 	commandHandler *commands.CustomerCommandHandler,
 	queryHandler *queries.CustomerQueryHandler,
+	jwtService *auth.JWTService,
 	validator *validator.Validator,
 	log *logger.Logger,
 ) *StorefrontCustomerHandler {
 	return &StorefrontCustomerHandler{
 		commandHandler: commandHandler,
 		queryHandler:   queryHandler,
+		jwtService:     jwtService,
 		validator:      validator,
 		log:            log,
 	}
@@ -41,6 +45,7 @@ func NewStorefrontCustomerHandler(
 func (h *StorefrontCustomerHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/customers", func(r chi.Router) {
 		r.Post("/register", h.RegisterCustomer)
+		r.Post("/login", h.Login)
 		r.Get("/{id}/profile", h.GetProfile)
 		r.Put("/{id}/profile", h.UpdateProfile)
 		r.Put("/{id}/password", h.ChangePassword)
@@ -101,6 +106,34 @@ func (h *StorefrontCustomerHandler) GetProfile(w http.ResponseWriter, r *http.Re
 	httpPkg.RespondJSON(w, http.StatusOK, customer) // Removed redundant application.ToCustomerDTO(customer)
 }
 
+// Login signs a customer in and returns a bearer token
+func (h *StorefrontCustomerHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var cmd commands.AuthenticateCustomerCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	customer, err := h.commandHandler.HandleAuthenticateCustomer(r.Context(), &cmd)
+	if err != nil {
+		h.log.WithError(err).Warn("customer login failed")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	token, err := h.jwtService.GenerateToken(strconv.FormatInt(customer.ID, 10), customer.EmailAddress, []string{"customer"})
+	if err != nil {
+		httpPkg.RespondError(w, errors.Internal("failed to generate token").WithInternal(err))
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"token":                    token,
+		"customer_id":              customer.ID,
+		"password_change_required": customer.PasswordChangeRequired,
+	})
+}
+
 // UpdateProfile updates a customer's profile
 func (h *StorefrontCustomerHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
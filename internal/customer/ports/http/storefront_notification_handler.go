@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/customer/application"
+	"github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/requestctx"
+)
+
+// StorefrontNotificationHandler handles the signed-in customer's notification inbox
+type StorefrontNotificationHandler struct {
+	notificationService application.CustomerNotificationService
+	log                 *logger.Logger
+}
+
+// NewStorefrontNotificationHandler creates a new StorefrontNotificationHandler
+func NewStorefrontNotificationHandler(notificationService application.CustomerNotificationService, log *logger.Logger) *StorefrontNotificationHandler {
+	return &StorefrontNotificationHandler{
+		notificationService: notificationService,
+		log:                 log,
+	}
+}
+
+// RegisterRoutes registers notification inbox routes
+func (h *StorefrontNotificationHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/account/notifications", func(r chi.Router) {
+		r.Get("/", h.ListNotifications)
+		r.Post("/read", h.MarkRead)
+		r.Post("/read-all", h.MarkAllRead)
+	})
+}
+
+// ListNotifications lists the inbox newest first. Query parameters: unread=true
+// for unread messages only, category, limit and offset.
+func (h *StorefrontNotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := inboxCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	filter := domain.CustomerNotificationFilter{
+		UnreadOnly: r.URL.Query().Get("unread") == "true",
+		Category:   strings.ToUpper(r.URL.Query().Get("category")),
+		Limit:      httpPkg.GetQueryParamInt(r, "limit", 0),
+		Offset:     httpPkg.GetQueryParamInt(r, "offset", 0),
+	}
+
+	inbox, err := h.notificationService.List(r.Context(), customerID, filter)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to list customer notifications")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, inbox)
+}
+
+// MarkRead marks the messages named in the body as read
+func (h *StorefrontNotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := inboxCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.MarkNotificationsReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	marked, err := h.notificationService.MarkRead(r.Context(), customerID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to mark customer notifications read")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"marked": marked,
+	})
+}
+
+// MarkAllRead marks the whole inbox as read
+func (h *StorefrontNotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := inboxCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	marked, err := h.notificationService.MarkAllRead(r.Context(), customerID)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to mark customer notifications read")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"marked": marked,
+	})
+}
+
+// inboxCustomer reads the customer of the request, responding with an error for anonymous shoppers
+func inboxCustomer(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	customerID := requestctx.CustomerID(r.Context())
+	if customerID == 0 {
+		httpPkg.RespondError(w, errors.Unauthorized("sign in to see notifications"))
+		return 0, false
+	}
+	return customerID, true
+}
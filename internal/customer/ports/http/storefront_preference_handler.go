@@ -0,0 +1,76 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/customer/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// preferenceCookieMaxAge is how long the locale and currency cookies are kept
+const preferenceCookieMaxAge = 365 * 24 * time.Hour
+
+// StorefrontPreferenceHandler handles the storefront locale and currency switcher
+type StorefrontPreferenceHandler struct {
+	preferenceService application.VisitorPreferenceService
+	log               *logger.Logger
+}
+
+// NewStorefrontPreferenceHandler creates a new StorefrontPreferenceHandler
+func NewStorefrontPreferenceHandler(preferenceService application.VisitorPreferenceService, log *logger.Logger) *StorefrontPreferenceHandler {
+	return &StorefrontPreferenceHandler{
+		preferenceService: preferenceService,
+		log:               log,
+	}
+}
+
+// RegisterRoutes registers locale and currency switcher routes
+func (h *StorefrontPreferenceHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/localization", h.GetLocalizationOptions)
+	r.Put("/localization/preference", h.SetPreference)
+}
+
+// GetLocalizationOptions lists the locales and currencies of the site and the visitor's selection
+func (h *StorefrontPreferenceHandler) GetLocalizationOptions(w http.ResponseWriter, r *http.Request) {
+	httpPkg.RespondJSON(w, http.StatusOK, h.preferenceService.GetLocalizationOptions(r.Context()))
+}
+
+// SetPreference selects the visitor's locale and currency. The selection is
+// stored for the customer or session and kept in cookies, so later catalog and
+// cart requests are served in it.
+func (h *StorefrontPreferenceHandler) SetPreference(w http.ResponseWriter, r *http.Request) {
+	var req application.SetVisitorPreferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	preference, err := h.preferenceService.SetPreference(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to set visitor preference")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	setPreferenceCookie(w, r, middleware.LocaleCookieName, preference.Locale)
+	setPreferenceCookie(w, r, middleware.CurrencyCookieName, preference.Currency)
+	w.Header().Set("Content-Language", preference.Locale)
+	httpPkg.RespondJSON(w, http.StatusOK, preference)
+}
+
+func setPreferenceCookie(w http.ResponseWriter, r *http.Request, name, value string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(preferenceCookieMaxAge.Seconds()),
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -1,9 +1,11 @@
 package application
 
 import (
+	"strings"
 	"time"
 
 	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	taxApp "github.com/qhato/ecommerce/internal/tax/application"
 )
 
 // ShipmentDTO represents shipment data for transfer
@@ -22,6 +24,8 @@ type ShipmentDTO struct {
 	Notes           string     `json:"notes,omitempty"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+
+	SerialNumbers []*ShipmentSerialDTO `json:"serial_numbers,omitempty"`
 }
 
 // AddressDTO represents address data for transfer
@@ -48,6 +52,27 @@ type CreateShipmentRequest struct {
 // ShipRequest represents a request to mark shipment as shipped
 type ShipRequest struct {
 	TrackingNumber string `json:"tracking_number" validate:"required"`
+	// Serials optionally records the serial numbers of the shipped items
+	Serials []SerialItemRequest `json:"serials,omitempty" validate:"dive"`
+}
+
+// RecordSerialsRequest represents a request to record the serial numbers of a shipment
+type RecordSerialsRequest struct {
+	Items []SerialItemRequest `json:"items" validate:"dive"`
+}
+
+// SerialItemRequest holds the serial numbers of the shipped units of an order item
+type SerialItemRequest struct {
+	OrderItemID   int64    `json:"order_item_id" validate:"required"`
+	SerialNumbers []string `json:"serial_numbers" validate:"required,min=1"`
+}
+
+// ValidateSerialRequest represents a warranty or return check of a serial
+// number, optionally against the order and SKU it is claimed for
+type ValidateSerialRequest struct {
+	SerialNumber string `json:"serial_number" validate:"required"`
+	OrderID      int64  `json:"order_id,omitempty"`
+	SKUID        int64  `json:"sku_id,omitempty"`
 }
 
 // UpdateTrackingRequest represents a request to update tracking
@@ -56,6 +81,71 @@ type UpdateTrackingRequest struct {
 	Notes          string `json:"notes"`
 }
 
+// ShipmentSerialDTO represents a recorded serial number for transfer
+type ShipmentSerialDTO struct {
+	ID           int64     `json:"id"`
+	ShipmentID   int64     `json:"shipment_id"`
+	OrderID      int64     `json:"order_id"`
+	OrderItemID  int64     `json:"order_item_id"`
+	SKUID        int64     `json:"sku_id"`
+	SerialNumber string    `json:"serial_number"`
+	RecordedBy   string    `json:"recorded_by,omitempty"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// SerialSearchQuery represents a search of recorded serial numbers
+type SerialSearchQuery struct {
+	Page         int
+	PageSize     int
+	SerialNumber string // Prefix
+	OrderID      int64
+	SKUID        int64
+}
+
+// SerialSearchResultDTO represents a page of recorded serial numbers
+type SerialSearchResultDTO struct {
+	Data       []*ShipmentSerialDTO `json:"data"`
+	Page       int                  `json:"page"`
+	PageSize   int                  `json:"page_size"`
+	TotalItems int64                `json:"total_items"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// SerialValidationDTO is the outcome of a warranty or return check of a
+// serial number; Reason explains why an invalid serial was rejected
+type SerialValidationDTO struct {
+	SerialNumber   string             `json:"serial_number"`
+	Valid          bool               `json:"valid"`
+	Reason         string             `json:"reason,omitempty"`
+	Serial         *ShipmentSerialDTO `json:"serial,omitempty"`
+	ShipmentStatus string             `json:"shipment_status,omitempty"`
+	ShippedDate    *time.Time         `json:"shipped_date,omitempty"`
+	DeliveredDate  *time.Time         `json:"delivered_date,omitempty"`
+}
+
+// ToShipmentSerialDTO converts a domain ShipmentSerial to ShipmentSerialDTO
+func ToShipmentSerialDTO(serial *domain.ShipmentSerial) *ShipmentSerialDTO {
+	return &ShipmentSerialDTO{
+		ID:           serial.ID,
+		ShipmentID:   serial.ShipmentID,
+		OrderID:      serial.OrderID,
+		OrderItemID:  serial.OrderItemID,
+		SKUID:        serial.SKUID,
+		SerialNumber: serial.SerialNumber,
+		RecordedBy:   serial.RecordedBy,
+		RecordedAt:   serial.RecordedAt,
+	}
+}
+
+// ToShipmentSerialDTOs converts a slice of domain ShipmentSerials to ShipmentSerialDTOs
+func ToShipmentSerialDTOs(serials []*domain.ShipmentSerial) []*ShipmentSerialDTO {
+	dtos := make([]*ShipmentSerialDTO, len(serials))
+	for i, serial := range serials {
+		dtos[i] = ToShipmentSerialDTO(serial)
+	}
+	return dtos
+}
+
 // ToShipmentDTO converts domain Shipment to ShipmentDTO
 func ToShipmentDTO(shipment *domain.Shipment) *ShipmentDTO {
 	if shipment == nil {
@@ -89,6 +179,24 @@ func ToShipmentDTO(shipment *domain.Shipment) *ShipmentDTO {
 	}
 }
 
+// LabelRequestDTO is what a carrier's label API is sent to label a shipment; a
+// shipment abroad carries the customs declaration of its contents
+type LabelRequestDTO struct {
+	ShipmentID      int64                      `json:"shipment_id"`
+	OrderID         int64                      `json:"order_id"`
+	Carrier         string                     `json:"carrier"`
+	ShippingMethod  string                     `json:"shipping_method"`
+	ShipFromCountry string                     `json:"ship_from_country"`
+	ShipTo          AddressDTO                 `json:"ship_to"`
+	Customs         *taxApp.CustomsDeclaration `json:"customs,omitempty"`
+}
+
+// IsCrossBorder reports whether a shipment leaves the country it ships from
+func IsCrossBorder(shipment *domain.Shipment, shipFromCountry string) bool {
+	country := strings.ToUpper(strings.TrimSpace(shipment.ShippingAddress.Country))
+	return country != "" && country != strings.ToUpper(shipFromCountry)
+}
+
 // ToShipmentDTOs converts a slice of domain Shipments to ShipmentDTOs
 func ToShipmentDTOs(shipments []*domain.Shipment) []ShipmentDTO {
 	dtos := make([]ShipmentDTO, len(shipments))
@@ -100,3 +208,149 @@ func ToShipmentDTOs(shipments []*domain.Shipment) []ShipmentDTO {
 	}
 	return dtos
 }
+
+// FulfillmentSLADTO represents a fulfillment group against its ship-by deadline
+type FulfillmentSLADTO struct {
+	ShipmentID     int64      `json:"shipment_id"`
+	OrderID        int64      `json:"order_id"`
+	ShippingMethod string     `json:"shipping_method"`
+	ShipmentStatus string     `json:"shipment_status"`
+	StartedAt      time.Time  `json:"started_at"`
+	ShipWithinDays int        `json:"ship_within_days"`
+	ShipBy         time.Time  `json:"ship_by"`
+	Status         string     `json:"status"`
+	ShippedAt      *time.Time `json:"shipped_at,omitempty"`
+	AlertedAt      *time.Time `json:"alerted_at,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// SLADashboardDTO summarizes fulfillment SLAs for the operations team
+type SLADashboardDTO struct {
+	OnTrack      int64                `json:"on_track"`
+	AtRisk       int64                `json:"at_risk"`
+	Breached     int64                `json:"breached"`
+	MetLast30    int64                `json:"met_last_30_days"`
+	MissedLast30 int64                `json:"missed_last_30_days"`
+	OnTimeRate   *float64             `json:"on_time_rate,omitempty"` // Share shipped on time in the last 30 days
+	AtRiskGroups []*FulfillmentSLADTO `json:"at_risk_groups"`
+	Breaches     []*FulfillmentSLADTO `json:"breaches"`
+}
+
+// SLACheckResultDTO reports a run of the SLA monitor
+type SLACheckResultDTO struct {
+	Evaluated int `json:"evaluated"`
+	AtRisk    int `json:"at_risk"`
+	Breached  int `json:"breached"`
+	Alerted   int `json:"alerted"`
+}
+
+// SLAPolicyDTO represents the business days a shipping method has to ship
+type SLAPolicyDTO struct {
+	ShippingMethod string     `json:"shipping_method"` // Empty for every other method
+	ShipWithinDays int        `json:"ship_within_days"`
+	Configured     bool       `json:"configured"` // False for the built-in default
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// SaveSLAPolicyRequest represents a request to set the SLA of a shipping method
+type SaveSLAPolicyRequest struct {
+	ShippingMethod string `json:"shipping_method"` // Empty sets the default
+	ShipWithinDays int    `json:"ship_within_days" validate:"min=0,max=60"`
+}
+
+// ToFulfillmentSLADTO converts a domain FulfillmentSLA to a FulfillmentSLADTO
+func ToFulfillmentSLADTO(sla *domain.FulfillmentSLA) *FulfillmentSLADTO {
+	return &FulfillmentSLADTO{
+		ShipmentID:     sla.ShipmentID,
+		OrderID:        sla.OrderID,
+		ShippingMethod: sla.ShippingMethod,
+		ShipmentStatus: string(sla.ShipmentStatus),
+		StartedAt:      sla.StartedAt,
+		ShipWithinDays: sla.ShipWithinDays,
+		ShipBy:         sla.ShipBy,
+		Status:         string(sla.Status),
+		ShippedAt:      sla.ShippedAt,
+		AlertedAt:      sla.AlertedAt,
+		UpdatedAt:      sla.UpdatedAt,
+	}
+}
+
+// ToFulfillmentSLADTOs converts domain FulfillmentSLAs to FulfillmentSLADTOs
+func ToFulfillmentSLADTOs(slas []*domain.FulfillmentSLA) []*FulfillmentSLADTO {
+	dtos := make([]*FulfillmentSLADTO, len(slas))
+	for i, sla := range slas {
+		dtos[i] = ToFulfillmentSLADTO(sla)
+	}
+	return dtos
+}
+
+// FulfillmentOptionDTO represents a fulfillment option offered at checkout
+type FulfillmentOptionDTO struct {
+	ID             int64              `json:"id"`
+	Name           string             `json:"name"`
+	Description    string             `json:"description,omitempty"`
+	Type           string             `json:"type"`
+	Carrier        string             `json:"carrier,omitempty"`
+	ServiceCode    string             `json:"service_code,omitempty"`
+	TransitDaysMin int                `json:"transit_days_min"`
+	TransitDaysMax int                `json:"transit_days_max"`
+	PriceBasis     string             `json:"price_basis"`
+	PriceBands     []domain.PriceBand `json:"price_bands"`
+	MinWeightKg    *float64           `json:"min_weight_kg,omitempty"`
+	MaxWeightKg    *float64           `json:"max_weight_kg,omitempty"`
+	Countries      []string           `json:"countries"`
+	Regions        []string           `json:"regions"`
+	Active         bool               `json:"active"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// SaveFulfillmentOptionRequest represents a request to create or replace a fulfillment option
+type SaveFulfillmentOptionRequest struct {
+	Name           string             `json:"name" validate:"required"`
+	Description    string             `json:"description"`
+	Type           string             `json:"type" validate:"required,oneof=PHYSICAL_SHIP PHYSICAL_PICKUP DIGITAL"`
+	Carrier        string             `json:"carrier"`
+	ServiceCode    string             `json:"service_code"`
+	TransitDaysMin int                `json:"transit_days_min" validate:"min=0,max=90"`
+	TransitDaysMax int                `json:"transit_days_max" validate:"min=0,max=90"`
+	PriceBasis     string             `json:"price_basis" validate:"omitempty,oneof=FLAT WEIGHT SUBTOTAL"` // FLAT when empty
+	PriceBands     []domain.PriceBand `json:"price_bands" validate:"required,min=1"`
+	MinWeightKg    *float64           `json:"min_weight_kg"`
+	MaxWeightKg    *float64           `json:"max_weight_kg"`
+	Countries      []string           `json:"countries"` // With regions empty, every destination
+	Regions        []string           `json:"regions"`   // As US-CA
+	Active         *bool              `json:"active"`    // True when omitted
+}
+
+// ToFulfillmentOptionDTO converts a domain FulfillmentOption to a FulfillmentOptionDTO
+func ToFulfillmentOptionDTO(option *domain.FulfillmentOption) *FulfillmentOptionDTO {
+	return &FulfillmentOptionDTO{
+		ID:             option.ID,
+		Name:           option.Name,
+		Description:    option.Description,
+		Type:           string(option.Type),
+		Carrier:        option.Carrier,
+		ServiceCode:    option.ServiceCode,
+		TransitDaysMin: option.TransitDaysMin,
+		TransitDaysMax: option.TransitDaysMax,
+		PriceBasis:     string(option.PriceBasis),
+		PriceBands:     option.PriceBands,
+		MinWeightKg:    option.MinWeightKg,
+		MaxWeightKg:    option.MaxWeightKg,
+		Countries:      option.Countries,
+		Regions:        option.Regions,
+		Active:         option.Active,
+		CreatedAt:      option.CreatedAt,
+		UpdatedAt:      option.UpdatedAt,
+	}
+}
+
+// ToFulfillmentOptionDTOs converts domain FulfillmentOptions to FulfillmentOptionDTOs
+func ToFulfillmentOptionDTOs(options []*domain.FulfillmentOption) []*FulfillmentOptionDTO {
+	dtos := make([]*FulfillmentOptionDTO, len(options))
+	for i, option := range options {
+		dtos[i] = ToFulfillmentOptionDTO(option)
+	}
+	return dtos
+}
@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// FulfillmentOptionService manages the fulfillment options offered at checkout
+type FulfillmentOptionService interface {
+	// ListOptions lists the fulfillment options by name, only the active ones if activeOnly.
+	ListOptions(ctx context.Context, activeOnly bool) ([]*FulfillmentOptionDTO, error)
+
+	// GetOption retrieves a fulfillment option.
+	GetOption(ctx context.Context, id int64) (*FulfillmentOptionDTO, error)
+
+	// CreateOption creates a fulfillment option.
+	CreateOption(ctx context.Context, req *SaveFulfillmentOptionRequest) (*FulfillmentOptionDTO, error)
+
+	// UpdateOption replaces the settings of a fulfillment option.
+	UpdateOption(ctx context.Context, id int64, req *SaveFulfillmentOptionRequest) (*FulfillmentOptionDTO, error)
+
+	// DeleteOption removes a fulfillment option. Groups already placed with it keep its ID.
+	DeleteOption(ctx context.Context, id int64) error
+}
+
+type fulfillmentOptionService struct {
+	repo domain.FulfillmentOptionRepository
+	log  *logger.Logger
+}
+
+// NewFulfillmentOptionService creates a new instance of FulfillmentOptionService.
+func NewFulfillmentOptionService(repo domain.FulfillmentOptionRepository, log *logger.Logger) FulfillmentOptionService {
+	return &fulfillmentOptionService{repo: repo, log: log}
+}
+
+func (s *fulfillmentOptionService) ListOptions(ctx context.Context, activeOnly bool) ([]*FulfillmentOptionDTO, error) {
+	options, err := s.repo.FindAll(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	return ToFulfillmentOptionDTOs(options), nil
+}
+
+func (s *fulfillmentOptionService) GetOption(ctx context.Context, id int64) (*FulfillmentOptionDTO, error) {
+	option, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToFulfillmentOptionDTO(option), nil
+}
+
+func (s *fulfillmentOptionService) CreateOption(ctx context.Context, req *SaveFulfillmentOptionRequest) (*FulfillmentOptionDTO, error) {
+	option, err := domain.NewFulfillmentOption(req.Name, domain.FulfillmentOptionType(strings.ToUpper(req.Type)))
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := applyFulfillmentOptionRequest(option, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, option); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"fulfillment_option_id": option.ID,
+		"name":                  option.Name,
+	}).Info("Fulfillment option created")
+	return ToFulfillmentOptionDTO(option), nil
+}
+
+func (s *fulfillmentOptionService) UpdateOption(ctx context.Context, id int64, req *SaveFulfillmentOptionRequest) (*FulfillmentOptionDTO, error) {
+	option, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := option.Rename(req.Name, domain.FulfillmentOptionType(strings.ToUpper(req.Type))); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := applyFulfillmentOptionRequest(option, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, option); err != nil {
+		return nil, err
+	}
+
+	s.log.WithField("fulfillment_option_id", option.ID).Info("Fulfillment option updated")
+	return ToFulfillmentOptionDTO(option), nil
+}
+
+func (s *fulfillmentOptionService) DeleteOption(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.log.WithField("fulfillment_option_id", id).Info("Fulfillment option deleted")
+	return nil
+}
+
+// applyFulfillmentOptionRequest sets the service, pricing, eligibility and status of an option
+func applyFulfillmentOptionRequest(option *domain.FulfillmentOption, req *SaveFulfillmentOptionRequest) error {
+	option.Description = strings.TrimSpace(req.Description)
+	if err := option.SetService(req.Carrier, req.ServiceCode, req.TransitDaysMin, req.TransitDaysMax); err != nil {
+		return errors.ValidationError(err.Error())
+	}
+	basis := domain.PriceBasis(strings.ToUpper(strings.TrimSpace(req.PriceBasis)))
+	if basis == "" {
+		basis = domain.PriceBasisFlat
+	}
+	if err := option.SetPricing(basis, req.PriceBands); err != nil {
+		return errors.ValidationError(err.Error())
+	}
+	if err := option.SetEligibility(req.MinWeightKg, req.MaxWeightKg, req.Countries, req.Regions); err != nil {
+		return errors.ValidationError(err.Error())
+	}
+	if req.Active == nil || *req.Active {
+		option.Activate()
+	} else {
+		option.Deactivate()
+	}
+	return nil
+}
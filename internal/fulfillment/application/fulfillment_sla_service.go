@@ -0,0 +1,329 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	"github.com/qhato/ecommerce/pkg/calendar"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// FulfillmentSLAService tracks fulfillment groups against a ship-by deadline
+// counted in business days from the submission of their order. The deadline
+// honors the working days, cutoff hour and holidays of the default business
+// calendar. A monitor
+// flags groups at risk of missing it or past it, and emails operations when a
+// group becomes at risk or breached.
+type FulfillmentSLAService interface {
+	// Dashboard summarizes the open groups by status and the on-time rate of the last 30 days.
+	Dashboard(ctx context.Context) (*SLADashboardDTO, error)
+
+	// ListGroups lists the groups in an SLA status, earliest deadline first.
+	ListGroups(ctx context.Context, status string, limit int) ([]*FulfillmentSLADTO, error)
+
+	// GetShipmentSLA retrieves the SLA of a fulfillment group.
+	GetShipmentSLA(ctx context.Context, shipmentID int64) (*FulfillmentSLADTO, error)
+
+	// ListPolicies lists the business days each shipping method has to ship.
+	ListPolicies(ctx context.Context) ([]*SLAPolicyDTO, error)
+
+	// SavePolicy sets the business days a shipping method has to ship.
+	SavePolicy(ctx context.Context, req *SaveSLAPolicyRequest) (*SLAPolicyDTO, error)
+
+	// DeletePolicy removes the policy of a shipping method, which falls back to the default.
+	DeletePolicy(ctx context.Context, shippingMethod string) error
+
+	// CheckSLAs evaluates every open group and alerts about the newly at-risk and breached ones.
+	CheckSLAs(ctx context.Context) (*SLACheckResultDTO, error)
+
+	// StartMonitor checks SLAs periodically until ctx is cancelled.
+	StartMonitor(ctx context.Context, interval time.Duration)
+}
+
+// FulfillmentSLAConfig holds the deadlines and alerting of fulfillment SLAs
+type FulfillmentSLAConfig struct {
+	DefaultShipWithinDays int           // For methods without a policy when no default policy is set
+	AtRisk                time.Duration // How close to the deadline an unshipped group is at risk
+	AlertEmail            string        // Where at-risk and breached groups are reported; empty only logs them
+}
+
+// SLANotifier emails operations about at-risk and breached groups
+type SLANotifier interface {
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
+// dashboardWindow is how far back shipped groups count toward the on-time rate
+const dashboardWindow = 30 * 24 * time.Hour
+
+// dashboardListLimit caps the groups listed per status on the dashboard
+const dashboardListLimit = 50
+
+type fulfillmentSLAService struct {
+	repo      domain.FulfillmentSLARepository
+	calendars calendar.Resolver
+	notifier  SLANotifier
+	cfg       FulfillmentSLAConfig
+	log       *logger.Logger
+	checkMu   sync.Mutex
+}
+
+// NewFulfillmentSLAService creates a new instance of FulfillmentSLAService. notifier may
+// be nil, in which case at-risk and breached groups are only logged.
+func NewFulfillmentSLAService(
+	repo domain.FulfillmentSLARepository,
+	calendars calendar.Resolver,
+	notifier SLANotifier,
+	cfg FulfillmentSLAConfig,
+	log *logger.Logger,
+) FulfillmentSLAService {
+	return &fulfillmentSLAService{
+		repo:      repo,
+		calendars: calendars,
+		notifier:  notifier,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+func (s *fulfillmentSLAService) Dashboard(ctx context.Context) (*SLADashboardDTO, error) {
+	summary, err := s.repo.Summarize(ctx, time.Now().Add(-dashboardWindow))
+	if err != nil {
+		return nil, err
+	}
+	atRisk, err := s.repo.FindByStatus(ctx, domain.SLAStatusAtRisk, dashboardListLimit)
+	if err != nil {
+		return nil, err
+	}
+	breached, err := s.repo.FindByStatus(ctx, domain.SLAStatusBreached, dashboardListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &SLADashboardDTO{
+		OnTrack:      summary.OnTrack,
+		AtRisk:       summary.AtRisk,
+		Breached:     summary.Breached,
+		MetLast30:    summary.Met,
+		MissedLast30: summary.Missed,
+		AtRiskGroups: ToFulfillmentSLADTOs(atRisk),
+		Breaches:     ToFulfillmentSLADTOs(breached),
+	}
+	if shipped := summary.Met + summary.Missed; shipped > 0 {
+		rate := float64(summary.Met) / float64(shipped)
+		dashboard.OnTimeRate = &rate
+	}
+	return dashboard, nil
+}
+
+func (s *fulfillmentSLAService) ListGroups(ctx context.Context, status string, limit int) ([]*FulfillmentSLADTO, error) {
+	slaStatus := domain.SLAStatus(strings.ToUpper(strings.TrimSpace(status)))
+	if !slaStatus.IsValid() {
+		return nil, errors.ValidationError("status must be ON_TRACK, AT_RISK, BREACHED, MET, MISSED or CANCELLED")
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	slas, err := s.repo.FindByStatus(ctx, slaStatus, limit)
+	if err != nil {
+		return nil, err
+	}
+	return ToFulfillmentSLADTOs(slas), nil
+}
+
+func (s *fulfillmentSLAService) GetShipmentSLA(ctx context.Context, shipmentID int64) (*FulfillmentSLADTO, error) {
+	sla, err := s.repo.FindByShipmentID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if sla == nil {
+		return nil, errors.NotFound(fmt.Sprintf("SLA of shipment %d", shipmentID))
+	}
+	return ToFulfillmentSLADTO(sla), nil
+}
+
+func (s *fulfillmentSLAService) ListPolicies(ctx context.Context) ([]*SLAPolicyDTO, error) {
+	policies, err := s.repo.FindPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*SLAPolicyDTO, 0, len(policies)+1)
+	hasDefault := false
+	for _, policy := range policies {
+		updatedAt := policy.UpdatedAt
+		hasDefault = hasDefault || policy.ShippingMethod == ""
+		dtos = append(dtos, &SLAPolicyDTO{
+			ShippingMethod: policy.ShippingMethod,
+			ShipWithinDays: policy.ShipWithinDays,
+			Configured:     true,
+			UpdatedAt:      &updatedAt,
+		})
+	}
+	if !hasDefault {
+		dtos = append([]*SLAPolicyDTO{{ShipWithinDays: s.cfg.DefaultShipWithinDays}}, dtos...)
+	}
+	return dtos, nil
+}
+
+func (s *fulfillmentSLAService) SavePolicy(ctx context.Context, req *SaveSLAPolicyRequest) (*SLAPolicyDTO, error) {
+	policy, err := domain.NewSLAPolicy(strings.TrimSpace(req.ShippingMethod), req.ShipWithinDays)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.SavePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"shipping_method":  policy.ShippingMethod,
+		"ship_within_days": policy.ShipWithinDays,
+	}).Info("Fulfillment SLA policy updated")
+	return &SLAPolicyDTO{
+		ShippingMethod: policy.ShippingMethod,
+		ShipWithinDays: policy.ShipWithinDays,
+		Configured:     true,
+		UpdatedAt:      &policy.UpdatedAt,
+	}, nil
+}
+
+func (s *fulfillmentSLAService) DeletePolicy(ctx context.Context, shippingMethod string) error {
+	return s.repo.DeletePolicy(ctx, strings.TrimSpace(shippingMethod))
+}
+
+func (s *fulfillmentSLAService) CheckSLAs(ctx context.Context) (*SLACheckResultDTO, error) {
+	if !s.checkMu.TryLock() {
+		return nil, errors.Conflict("fulfillment SLA check already in progress")
+	}
+	defer s.checkMu.Unlock()
+
+	cal, shipWithinDays, err := s.loadCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slas, err := s.repo.FindToEvaluate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fulfillment groups to evaluate: %w", err)
+	}
+
+	now := time.Now()
+	result := &SLACheckResultDTO{}
+	alerts := make([]*domain.FulfillmentSLA, 0)
+	for _, sla := range slas {
+		sla.Evaluate(cal, shipWithinDays(sla.ShippingMethod), s.cfg.AtRisk, now)
+		result.Evaluated++
+		switch sla.Status {
+		case domain.SLAStatusAtRisk:
+			result.AtRisk++
+		case domain.SLAStatusBreached:
+			result.Breached++
+		}
+		if sla.NeedsAlert() {
+			alerts = append(alerts, sla)
+			continue
+		}
+		if err := s.repo.Save(ctx, sla); err != nil {
+			s.log.WithError(err).WithField("shipment_id", sla.ShipmentID).Error("Failed to save fulfillment SLA")
+		}
+	}
+
+	for _, sla := range alerts {
+		s.log.WithFields(logger.Fields{
+			"shipment_id": sla.ShipmentID,
+			"order_id":    sla.OrderID,
+			"status":      string(sla.Status),
+			"ship_by":     sla.ShipBy,
+		}).Warn("Fulfillment group is late or at risk of shipping late")
+	}
+	// Unsent alerts are retried on the next run rather than marked as alerted
+	alerted := len(alerts) > 0 && s.emailAlerts(ctx, cal, alerts)
+	for _, sla := range alerts {
+		if alerted {
+			sla.MarkAlerted(now)
+			result.Alerted++
+		}
+		if err := s.repo.Save(ctx, sla); err != nil {
+			s.log.WithError(err).WithField("shipment_id", sla.ShipmentID).Error("Failed to save fulfillment SLA")
+		}
+	}
+	return result, nil
+}
+
+func (s *fulfillmentSLAService) StartMonitor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.CheckSLAs(ctx); err != nil {
+					s.log.WithError(err).Warn("Fulfillment SLA check failed")
+				}
+			}
+		}
+	}()
+}
+
+// loadCalendar resolves the default business calendar, as fulfillment
+// groups carry no warehouse, and returns how many business days each
+// shipping method has to ship
+func (s *fulfillmentSLAService) loadCalendar(ctx context.Context) (*calendar.Calendar, func(method string) int, error) {
+	cal, err := s.calendars.Calendar(ctx, "", "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve business calendar: %w", err)
+	}
+	policies, err := s.repo.FindPolicies(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byMethod := make(map[string]int, len(policies))
+	defaultDays := s.cfg.DefaultShipWithinDays
+	for _, policy := range policies {
+		if policy.ShippingMethod == "" {
+			defaultDays = policy.ShipWithinDays
+			continue
+		}
+		byMethod[strings.ToLower(policy.ShippingMethod)] = policy.ShipWithinDays
+	}
+	shipWithinDays := func(method string) int {
+		if days, ok := byMethod[strings.ToLower(method)]; ok {
+			return days
+		}
+		return defaultDays
+	}
+	return cal, shipWithinDays, nil
+}
+
+// emailAlerts sends one alert listing the groups that became at risk or breached,
+// reporting whether operations were told. Without an alert address the log is the notification.
+func (s *fulfillmentSLAService) emailAlerts(ctx context.Context, cal *calendar.Calendar, slas []*domain.FulfillmentSLA) bool {
+	if s.notifier == nil || s.cfg.AlertEmail == "" {
+		return true
+	}
+
+	var body strings.Builder
+	body.WriteString("These fulfillment groups are late or at risk of shipping late:\n\n")
+	for _, sla := range slas {
+		fmt.Fprintf(&body, "- Shipment %d of order %d (%s): %s, ship by %s\n",
+			sla.ShipmentID, sla.OrderID, sla.ShippingMethod, sla.Status,
+			sla.ShipBy.In(cal.Location).Format(time.RFC1123))
+	}
+	body.WriteString("\nSee the fulfillment SLA dashboard for every open group.")
+
+	subject := fmt.Sprintf("%d fulfillment group(s) at risk of missing or past their ship-by date", len(slas))
+	if err := s.notifier.SendEmail(ctx, s.cfg.AlertEmail, subject, body.String()); err != nil {
+		s.log.WithError(err).Warn("Failed to email fulfillment SLA alerts")
+		return false
+	}
+	return true
+}
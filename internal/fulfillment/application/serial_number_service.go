@@ -0,0 +1,292 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	orderDomain "github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// Reasons a serial number fails a warranty or return check
+const (
+	SerialReasonNotFound      = "not_found"
+	SerialReasonNotShipped    = "not_shipped"
+	SerialReasonCancelled     = "shipment_cancelled"
+	SerialReasonOrderMismatch = "order_mismatch"
+	SerialReasonSKUMismatch   = "sku_mismatch"
+)
+
+// SerialNumberService records the serial numbers of high-value items as they
+// are shipped, and looks them up for the admin, invoices and warranty and
+// return checks. Serials are optional: items shipped without them are not
+// tracked.
+type SerialNumberService interface {
+	// RecordSerials replaces the serials recorded for the items of a shipment
+	RecordSerials(ctx context.Context, shipmentID int64, items []SerialItemRequest, recordedBy string) ([]*ShipmentSerialDTO, error)
+
+	// ShipmentSerials lists the serials recorded for a shipment
+	ShipmentSerials(ctx context.Context, shipmentID int64) ([]*ShipmentSerialDTO, error)
+
+	// OrderSerialNumbers returns the serials shipped for each item of an
+	// order, by order item ID; cancelled shipments are left out
+	OrderSerialNumbers(ctx context.Context, orderID int64) (map[int64][]string, error)
+
+	// SearchSerials searches recorded serials by serial number prefix, order or SKU
+	SearchSerials(ctx context.Context, query *SerialSearchQuery) (*SerialSearchResultDTO, error)
+
+	// ValidateSerial checks that a serial number was shipped, optionally on
+	// the order and SKU a warranty claim or return is made for
+	ValidateSerial(ctx context.Context, req *ValidateSerialRequest) (*SerialValidationDTO, error)
+}
+
+type serialNumberService struct {
+	serialRepo    domain.ShipmentSerialRepository
+	shipmentRepo  domain.ShipmentRepository
+	orderItemRepo orderDomain.OrderItemRepository
+}
+
+// NewSerialNumberService creates a new instance of SerialNumberService.
+func NewSerialNumberService(
+	serialRepo domain.ShipmentSerialRepository,
+	shipmentRepo domain.ShipmentRepository,
+	orderItemRepo orderDomain.OrderItemRepository,
+) SerialNumberService {
+	return &serialNumberService{
+		serialRepo:    serialRepo,
+		shipmentRepo:  shipmentRepo,
+		orderItemRepo: orderItemRepo,
+	}
+}
+
+func (s *serialNumberService) RecordSerials(ctx context.Context, shipmentID int64, items []SerialItemRequest, recordedBy string) ([]*ShipmentSerialDTO, error) {
+	shipment, err := s.findShipment(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.Status == domain.ShipmentStatusCancelled {
+		return nil, errors.Conflict("cannot record serial numbers of a cancelled shipment")
+	}
+
+	orderItems, err := s.orderItemRepo.FindByOrderID(ctx, shipment.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+	itemsByID := make(map[int64]*orderDomain.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		itemsByID[item.ID] = item
+	}
+
+	// Units of each item already serialized on the other shipments of the order
+	shippedElsewhere, err := s.serializedUnits(ctx, shipment.OrderID, shipment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	serials := make([]*domain.ShipmentSerial, 0)
+	seen := make(map[string]bool)
+	for _, req := range items {
+		item, ok := itemsByID[req.OrderItemID]
+		if !ok {
+			return nil, errors.ValidationError(fmt.Sprintf("order item %d is not part of order %d", req.OrderItemID, shipment.OrderID))
+		}
+		if units := shippedElsewhere[item.ID] + len(req.SerialNumbers); units > item.Quantity {
+			return nil, errors.ValidationError(fmt.Sprintf("order item %d has %d units, got %d serial numbers", item.ID, item.Quantity, units))
+		}
+		for _, raw := range req.SerialNumbers {
+			serial := domain.NewShipmentSerial(shipment, item.ID, item.SKUID, raw, recordedBy)
+			if serial.SerialNumber == "" {
+				return nil, errors.ValidationError(fmt.Sprintf("empty serial number for order item %d", item.ID))
+			}
+			if seen[serial.SerialNumber] {
+				return nil, errors.ValidationError(fmt.Sprintf("serial number %s is given more than once", serial.SerialNumber))
+			}
+			seen[serial.SerialNumber] = true
+			if err := s.checkNotShippedElsewhere(ctx, serial); err != nil {
+				return nil, err
+			}
+			serials = append(serials, serial)
+		}
+	}
+
+	if err := s.serialRepo.ReplaceForShipment(ctx, shipment.ID, serials); err != nil {
+		return nil, err
+	}
+	return ToShipmentSerialDTOs(serials), nil
+}
+
+func (s *serialNumberService) ShipmentSerials(ctx context.Context, shipmentID int64) ([]*ShipmentSerialDTO, error) {
+	if _, err := s.findShipment(ctx, shipmentID); err != nil {
+		return nil, err
+	}
+	serials, err := s.serialRepo.FindByShipmentID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	return ToShipmentSerialDTOs(serials), nil
+}
+
+func (s *serialNumberService) OrderSerialNumbers(ctx context.Context, orderID int64) (map[int64][]string, error) {
+	serials, err := s.activeOrderSerials(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	byItem := make(map[int64][]string)
+	for _, serial := range serials {
+		byItem[serial.OrderItemID] = append(byItem[serial.OrderItemID], serial.SerialNumber)
+	}
+	return byItem, nil
+}
+
+func (s *serialNumberService) SearchSerials(ctx context.Context, query *SerialSearchQuery) (*SerialSearchResultDTO, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 || query.PageSize > 100 {
+		query.PageSize = 20
+	}
+
+	serials, total, err := s.serialRepo.Search(ctx, &domain.ShipmentSerialFilter{
+		Page:         query.Page,
+		PageSize:     query.PageSize,
+		SerialNumber: domain.NormalizeSerialNumber(query.SerialNumber),
+		OrderID:      query.OrderID,
+		SKUID:        query.SKUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize > 0 {
+		totalPages++
+	}
+	return &SerialSearchResultDTO{
+		Data:       ToShipmentSerialDTOs(serials),
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *serialNumberService) ValidateSerial(ctx context.Context, req *ValidateSerialRequest) (*SerialValidationDTO, error) {
+	result := &SerialValidationDTO{SerialNumber: domain.NormalizeSerialNumber(req.SerialNumber)}
+	if result.SerialNumber == "" {
+		return nil, errors.ValidationError("serial number is required")
+	}
+
+	records, err := s.serialRepo.FindBySerialNumber(ctx, result.SerialNumber)
+	if err != nil {
+		return nil, err
+	}
+	result.Reason = SerialReasonNotFound
+	for _, record := range records {
+		shipment, err := s.shipmentRepo.FindByID(ctx, record.ShipmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find shipment: %w", err)
+		}
+		if shipment == nil {
+			continue
+		}
+		result.Serial = ToShipmentSerialDTO(record)
+		result.ShipmentStatus = string(shipment.Status)
+		result.ShippedDate = shipment.ShippedDate
+		result.DeliveredDate = shipment.DeliveredDate
+		if shipment.Status == domain.ShipmentStatusCancelled {
+			// A serial only counts on the shipment it actually left with
+			result.Reason = SerialReasonCancelled
+			continue
+		}
+
+		switch {
+		case shipment.ShippedDate == nil:
+			result.Reason = SerialReasonNotShipped
+		case req.OrderID > 0 && record.OrderID != req.OrderID:
+			result.Reason = SerialReasonOrderMismatch
+		case req.SKUID > 0 && record.SKUID != req.SKUID:
+			result.Reason = SerialReasonSKUMismatch
+		default:
+			result.Valid = true
+			result.Reason = ""
+		}
+		return result, nil
+	}
+	return result, nil
+}
+
+func (s *serialNumberService) findShipment(ctx context.Context, shipmentID int64) (*domain.Shipment, error) {
+	shipment, err := s.shipmentRepo.FindByID(ctx, shipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shipment: %w", err)
+	}
+	if shipment == nil {
+		return nil, errors.NotFound(fmt.Sprintf("shipment %d", shipmentID))
+	}
+	return shipment, nil
+}
+
+// activeOrderSerials returns the serials of an order on shipments that were not cancelled
+func (s *serialNumberService) activeOrderSerials(ctx context.Context, orderID int64) ([]*domain.ShipmentSerial, error) {
+	shipments, err := s.shipmentRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shipments: %w", err)
+	}
+	cancelled := make(map[int64]bool)
+	for _, shipment := range shipments {
+		if shipment.Status == domain.ShipmentStatusCancelled {
+			cancelled[shipment.ID] = true
+		}
+	}
+
+	serials, err := s.serialRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*domain.ShipmentSerial, 0, len(serials))
+	for _, serial := range serials {
+		if !cancelled[serial.ShipmentID] {
+			active = append(active, serial)
+		}
+	}
+	return active, nil
+}
+
+// serializedUnits counts the serials of each order item recorded on the
+// active shipments of an order other than the given one
+func (s *serialNumberService) serializedUnits(ctx context.Context, orderID, exceptShipmentID int64) (map[int64]int, error) {
+	serials, err := s.activeOrderSerials(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	units := make(map[int64]int)
+	for _, serial := range serials {
+		if serial.ShipmentID != exceptShipmentID {
+			units[serial.OrderItemID]++
+		}
+	}
+	return units, nil
+}
+
+// checkNotShippedElsewhere rejects a serial number already recorded on another
+// shipment that was not cancelled
+func (s *serialNumberService) checkNotShippedElsewhere(ctx context.Context, serial *domain.ShipmentSerial) error {
+	records, err := s.serialRepo.FindBySerialNumber(ctx, serial.SerialNumber)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if record.ShipmentID == serial.ShipmentID {
+			continue
+		}
+		shipment, err := s.shipmentRepo.FindByID(ctx, record.ShipmentID)
+		if err != nil {
+			return fmt.Errorf("failed to find shipment: %w", err)
+		}
+		if shipment != nil && shipment.Status != domain.ShipmentStatusCancelled {
+			return errors.Conflict(fmt.Sprintf("serial number %s is already recorded on shipment %d", serial.SerialNumber, shipment.ID))
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"time"
 )
 
 // ShippingService defines the application service for shipping-related operations.
@@ -25,4 +26,8 @@ type ShippingMethodDTO struct {
 	Cost                float64
 	DeliveryEstimate    string
 	FulfillmentOptionID int64
+	TransitDaysMin      int // Business days in transit once shipped
+	TransitDaysMax      int
+	EarliestDelivery    *time.Time // Dates delivery is expected between, from the business calendar of the site
+	LatestDelivery      *time.Time
 }
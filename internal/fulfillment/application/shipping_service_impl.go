@@ -2,27 +2,51 @@ package application
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	"github.com/qhato/ecommerce/pkg/calendar"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/requestctx"
 )
 
 type shippingService struct {
-	// Add dependencies here, e.g., FulfillmentOptionRepository, AddressRepository
+	options   domain.FulfillmentOptionRepository
+	profiles  domain.ShipmentProfileRepository
+	calendars calendar.Resolver
 }
 
-// NewShippingService creates a new instance of ShippingService.
-func NewShippingService() ShippingService {
-	return &shippingService{}
+// NewShippingService creates a new instance of ShippingService that offers the
+// active fulfillment options an order is eligible for. calendars may be nil, in
+// which case methods carry no delivery dates.
+func NewShippingService(
+	options domain.FulfillmentOptionRepository,
+	profiles domain.ShipmentProfileRepository,
+	calendars calendar.Resolver,
+) ShippingService {
+	return &shippingService{options: options, profiles: profiles, calendars: calendars}
 }
 
-// CalculateShippingCost calculates the shipping cost for a given order.
+// CalculateShippingCost prices the fulfillment option for the order shipped to the
+// address, rejecting options that are inactive or not available to it.
 func (s *shippingService) CalculateShippingCost(ctx context.Context, orderID int64, shippingAddressID int64, fulfillmentOptionID int64) (float64, error) {
-	// Placeholder logic: In a real system, this would involve:
-	// 1. Fetching order details (items, weights, dimensions) using orderID.
-	// 2. Fetching shipping address details using shippingAddressID.
-	// 3. Fetching fulfillment option details using fulfillmentOptionID.
-	// 4. Calling an external shipping carrier API or using internal rate tables.
-
-	// For demonstration, return a fixed cost
-	return 10.00, nil
+	if fulfillmentOptionID <= 0 {
+		return 0, errors.ValidationError("fulfillment option is required")
+	}
+	option, err := s.options.FindByID(ctx, fulfillmentOptionID)
+	if err != nil {
+		return 0, err
+	}
+	profile, err := s.profiles.FindProfile(ctx, orderID, shippingAddressID)
+	if err != nil {
+		return 0, err
+	}
+	if !option.IsEligible(profile) {
+		return 0, errors.ValidationError(fmt.Sprintf("fulfillment option %q is not available for this order and address", option.Name))
+	}
+	return option.PriceFor(profile), nil
 }
 
 // ValidateShippingAddress validates a given shipping address.
@@ -35,24 +59,72 @@ func (s *shippingService) ValidateShippingAddress(ctx context.Context, addressID
 	return true, nil
 }
 
-// GetShippingMethods retrieves available shipping methods for an order/address combination.
+// GetShippingMethods lists the active fulfillment options the order is eligible
+// for when shipped to the address, priced by their bands, cheapest first.
 func (s *shippingService) GetShippingMethods(ctx context.Context, orderID int64, shippingAddressID int64) ([]*ShippingMethodDTO, error) {
-	// Placeholder logic: In a real system, this would involve:
-	// 1. Fetching order items to determine total weight/dimensions.
-	// 2. Fetching shipping address to determine destination.
-	// 3. Querying configured shipping options (e.g., flat rate, by weight, carrier-specific).
+	profile, err := s.profiles.FindProfile(ctx, orderID, shippingAddressID)
+	if err != nil {
+		return nil, err
+	}
+	options, err := s.options.FindAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]*ShippingMethodDTO, 0, len(options))
+	for _, option := range options {
+		if !option.IsEligible(profile) {
+			continue
+		}
+		methods = append(methods, &ShippingMethodDTO{
+			ID:                  option.ID,
+			Name:                option.Name,
+			Description:         option.Description,
+			Cost:                option.PriceFor(profile),
+			DeliveryEstimate:    deliveryEstimate(option),
+			FulfillmentOptionID: option.ID,
+			TransitDaysMin:      option.TransitDaysMin,
+			TransitDaysMax:      option.TransitDaysMax,
+		})
+	}
+	sort.SliceStable(methods, func(i, j int) bool { return methods[i].Cost < methods[j].Cost })
+
+	if err := s.estimateDelivery(ctx, methods, time.Now()); err != nil {
+		return nil, err
+	}
+	return methods, nil
+}
+
+// deliveryEstimate describes the transit time of an option, as "3-5 days"
+func deliveryEstimate(option *domain.FulfillmentOption) string {
+	if option.TransitDaysMin == option.TransitDaysMax {
+		if option.TransitDaysMax == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", option.TransitDaysMax)
+	}
+	return fmt.Sprintf("%d-%d days", option.TransitDaysMin, option.TransitDaysMax)
+}
 
-	// For demonstration, return some dummy methods
-	return []*ShippingMethodDTO{
-		{
-			ID: 1, Name: "Standard Shipping", Description: "3-5 business days", Cost: 5.99,
-			DeliveryEstimate: "3-5 days", FulfillmentOptionID: 101,
-		},
-		{
-			ID: 2, Name: "Express Shipping", Description: "1-2 business days", Cost: 15.99,
-			DeliveryEstimate: "1-2 days", FulfillmentOptionID: 102,
-		},
-	}, nil
+// estimateDelivery dates the delivery of each method: an order placed at now
+// ships on the business day it counts from (the next one after the cutoff)
+// and arrives its transit days of business days later.
+func (s *shippingService) estimateDelivery(ctx context.Context, methods []*ShippingMethodDTO, now time.Time) error {
+	if s.calendars == nil {
+		return nil
+	}
+	cal, err := s.calendars.Calendar(ctx, requestctx.SiteID(ctx), "")
+	if err != nil {
+		return err
+	}
+	shipDay := cal.StartDay(now)
+	for _, method := range methods {
+		earliest := cal.AddBusinessDays(shipDay, method.TransitDaysMin)
+		latest := cal.AddBusinessDays(shipDay, method.TransitDaysMax)
+		method.EarliestDelivery = &earliest
+		method.LatestDelivery = &latest
+	}
+	return nil
 }
 
 // NewDomainError creates a new DomainError.
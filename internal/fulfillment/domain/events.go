@@ -13,6 +13,14 @@ const (
 	EventShipmentCancelled = "shipment.cancelled"
 )
 
+// Shipment events are rebuilt as their own types when read from a serializing bus
+func init() {
+	event.RegisterType(EventShipmentCreated, func() event.Event { return &ShipmentCreatedEvent{} })
+	event.RegisterType(EventShipmentShipped, func() event.Event { return &ShipmentShippedEvent{} })
+	event.RegisterType(EventShipmentDelivered, func() event.Event { return &ShipmentDeliveredEvent{} })
+	event.RegisterType(EventShipmentCancelled, func() event.Event { return &ShipmentCancelledEvent{} })
+}
+
 type ShipmentCreatedEvent struct {
 	event.BaseEvent
 	ShipmentID     int64   `json:"shipment_id"`
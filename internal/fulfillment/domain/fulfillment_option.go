@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FulfillmentOptionType is how the goods of a fulfillment option reach the customer
+type FulfillmentOptionType string
+
+const (
+	FulfillmentOptionTypeShip    FulfillmentOptionType = "PHYSICAL_SHIP"
+	FulfillmentOptionTypePickup  FulfillmentOptionType = "PHYSICAL_PICKUP"
+	FulfillmentOptionTypeDigital FulfillmentOptionType = "DIGITAL"
+)
+
+// IsValid checks the type is a known fulfillment option type
+func (t FulfillmentOptionType) IsValid() bool {
+	return t == FulfillmentOptionTypeShip || t == FulfillmentOptionTypePickup || t == FulfillmentOptionTypeDigital
+}
+
+// PriceBasis is what the price bands of a fulfillment option are measured against
+type PriceBasis string
+
+const (
+	PriceBasisFlat     PriceBasis = "FLAT"     // A single band priced for every order
+	PriceBasisWeight   PriceBasis = "WEIGHT"   // Bands start at a total weight in kilograms
+	PriceBasisSubtotal PriceBasis = "SUBTOTAL" // Bands start at an order subtotal
+)
+
+// IsValid checks the basis is a known price basis
+func (b PriceBasis) IsValid() bool {
+	return b == PriceBasisFlat || b == PriceBasisWeight || b == PriceBasisSubtotal
+}
+
+// MaxTransitDays caps the business days an option may take in transit
+const MaxTransitDays = 90
+
+// PriceBand is the price of an option for orders measuring From or more, up to
+// the From of the next band
+type PriceBand struct {
+	From  float64 `json:"from"`
+	Price float64 `json:"price"`
+}
+
+// ShipmentProfile is what decides which options an order may use and at what
+// price: its weight, subtotal and destination
+type ShipmentProfile struct {
+	WeightKg float64
+	Subtotal float64
+	Country  string // ISO 3166-1 alpha-2
+	Region   string // ISO 3166-2 subdivision, as "CA" or "US-CA"
+}
+
+// FulfillmentOption is a way to fulfill an order offered at checkout, such as
+// standard or express shipping, with its carrier service, price bands and the
+// orders it is available to
+type FulfillmentOption struct {
+	ID             int64
+	Name           string
+	Description    string
+	Type           FulfillmentOptionType
+	Carrier        string // Carrier shipments are booked with, e.g. UPS
+	ServiceCode    string // Service of the carrier, e.g. GROUND
+	TransitDaysMin int
+	TransitDaysMax int
+	PriceBasis     PriceBasis
+	PriceBands     []PriceBand // Ascending by From, the first one from zero
+	MinWeightKg    *float64    // Lightest order the option takes, nil for no minimum
+	MaxWeightKg    *float64    // Heaviest order the option takes, nil for no maximum
+	Countries      []string    // Destination countries; with Regions empty, everywhere
+	Regions        []string    // Destination regions as "US-CA"
+	Active         bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewFulfillmentOption creates an active fulfillment option
+func NewFulfillmentOption(name string, optionType FulfillmentOptionType) (*FulfillmentOption, error) {
+	now := time.Now()
+	option := &FulfillmentOption{
+		Active:     true,
+		PriceBasis: PriceBasisFlat,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := option.Rename(name, optionType); err != nil {
+		return nil, err
+	}
+	return option, nil
+}
+
+// Rename sets the name and type of the option
+func (o *FulfillmentOption) Rename(name string, optionType FulfillmentOptionType) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return NewFulfillmentError("fulfillment option name is required")
+	}
+	if !optionType.IsValid() {
+		return NewFulfillmentError("fulfillment option type must be PHYSICAL_SHIP, PHYSICAL_PICKUP or DIGITAL")
+	}
+	o.Name = name
+	o.Type = optionType
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetService sets the carrier service shipments of the option are booked with and its transit time
+func (o *FulfillmentOption) SetService(carrier, serviceCode string, transitDaysMin, transitDaysMax int) error {
+	if transitDaysMin < 0 || transitDaysMax < transitDaysMin || transitDaysMax > MaxTransitDays {
+		return NewFulfillmentError("transit days must be between 0 and 90, the minimum not above the maximum")
+	}
+	o.Carrier = strings.TrimSpace(carrier)
+	o.ServiceCode = strings.ToUpper(strings.TrimSpace(serviceCode))
+	o.TransitDaysMin = transitDaysMin
+	o.TransitDaysMax = transitDaysMax
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetPricing sets the price bands of the option. Bands are sorted by From; the
+// first must start at zero so every order has a price, and a flat price has one band.
+func (o *FulfillmentOption) SetPricing(basis PriceBasis, bands []PriceBand) error {
+	if !basis.IsValid() {
+		return NewFulfillmentError("price basis must be FLAT, WEIGHT or SUBTOTAL")
+	}
+	if len(bands) == 0 {
+		return NewFulfillmentError("at least one price band is required")
+	}
+	if basis == PriceBasisFlat && len(bands) > 1 {
+		return NewFulfillmentError("a flat price has a single band")
+	}
+
+	sorted := make([]PriceBand, len(bands))
+	copy(sorted, bands)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+	for i, band := range sorted {
+		if band.Price < 0 {
+			return NewFulfillmentError("band prices cannot be negative")
+		}
+		if i > 0 && band.From == sorted[i-1].From {
+			return NewFulfillmentError("price bands must start at different values")
+		}
+	}
+	if sorted[0].From != 0 {
+		return NewFulfillmentError("the first price band must start at zero")
+	}
+
+	o.PriceBasis = basis
+	o.PriceBands = sorted
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetEligibility sets the weights and destinations the option is available to
+func (o *FulfillmentOption) SetEligibility(minWeightKg, maxWeightKg *float64, countries, regions []string) error {
+	if (minWeightKg != nil && *minWeightKg < 0) || (maxWeightKg != nil && *maxWeightKg <= 0) {
+		return NewFulfillmentError("weight limits must be positive")
+	}
+	if minWeightKg != nil && maxWeightKg != nil && *minWeightKg > *maxWeightKg {
+		return NewFulfillmentError("minimum weight cannot be above the maximum weight")
+	}
+	regions = normalizeCodes(regions)
+	for _, region := range regions {
+		if !strings.Contains(region, "-") {
+			return NewFulfillmentError("regions must include their country, as US-CA")
+		}
+	}
+	o.MinWeightKg = minWeightKg
+	o.MaxWeightKg = maxWeightKg
+	o.Countries = normalizeCodes(countries)
+	o.Regions = regions
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// Activate offers the option at checkout again
+func (o *FulfillmentOption) Activate() {
+	o.Active = true
+	o.UpdatedAt = time.Now()
+}
+
+// Deactivate stops offering the option at checkout
+func (o *FulfillmentOption) Deactivate() {
+	o.Active = false
+	o.UpdatedAt = time.Now()
+}
+
+// IsEligible checks if an order with the profile may use the option
+func (o *FulfillmentOption) IsEligible(profile *ShipmentProfile) bool {
+	if !o.Active {
+		return false
+	}
+	if o.MinWeightKg != nil && profile.WeightKg < *o.MinWeightKg {
+		return false
+	}
+	if o.MaxWeightKg != nil && profile.WeightKg > *o.MaxWeightKg {
+		return false
+	}
+	return o.ServesDestination(profile.Country, profile.Region)
+}
+
+// ServesDestination checks if the option delivers to the country and region. An
+// option without countries or regions delivers everywhere; otherwise the country
+// or the region must be listed. The region may be given as "CA" or "US-CA".
+func (o *FulfillmentOption) ServesDestination(country, region string) bool {
+	if len(o.Countries) == 0 && len(o.Regions) == 0 {
+		return true
+	}
+	country = strings.ToUpper(strings.TrimSpace(country))
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region != "" && !strings.Contains(region, "-") && country != "" {
+		region = country + "-" + region
+	}
+	for _, c := range o.Countries {
+		if c == country {
+			return true
+		}
+	}
+	for _, r := range o.Regions {
+		if region != "" && r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// PriceFor returns the price of the option for an order with the profile: that
+// of the last band starting at or below its weight or subtotal
+func (o *FulfillmentOption) PriceFor(profile *ShipmentProfile) float64 {
+	measure := 0.0
+	switch o.PriceBasis {
+	case PriceBasisWeight:
+		measure = profile.WeightKg
+	case PriceBasisSubtotal:
+		measure = profile.Subtotal
+	}
+	price := 0.0
+	for _, band := range o.PriceBands {
+		if band.From > measure {
+			break
+		}
+		price = band.Price
+	}
+	return price
+}
+
+// normalizeCodes upper-cases country and region codes, dropping blanks and duplicates
+func normalizeCodes(codes []string) []string {
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+	return normalized
+}
+
+// FulfillmentOptionRepository defines the interface for fulfillment option persistence
+type FulfillmentOptionRepository interface {
+	// Save creates or updates a fulfillment option, setting the ID of a new one.
+	Save(ctx context.Context, option *FulfillmentOption) error
+
+	// FindByID retrieves a fulfillment option by ID.
+	FindByID(ctx context.Context, id int64) (*FulfillmentOption, error)
+
+	// FindAll retrieves the fulfillment options by name, only the active ones if activeOnly.
+	FindAll(ctx context.Context, activeOnly bool) ([]*FulfillmentOption, error)
+
+	// Delete removes a fulfillment option.
+	Delete(ctx context.Context, id int64) error
+}
+
+// ShipmentProfileRepository reads the shipment profile of an order shipped to an address
+type ShipmentProfileRepository interface {
+	// FindProfile computes the weight in kilograms and subtotal of an order and
+	// the destination of an address.
+	FindProfile(ctx context.Context, orderID, addressID int64) (*ShipmentProfile, error)
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/calendar"
+)
+
+// SLAStatus is where a fulfillment group stands against its ship-by deadline
+type SLAStatus string
+
+const (
+	SLAStatusOnTrack   SLAStatus = "ON_TRACK"
+	SLAStatusAtRisk    SLAStatus = "AT_RISK"   // Unshipped and the deadline is near
+	SLAStatusBreached  SLAStatus = "BREACHED"  // Unshipped past the deadline
+	SLAStatusMet       SLAStatus = "MET"       // Shipped by the deadline
+	SLAStatusMissed    SLAStatus = "MISSED"    // Shipped after the deadline
+	SLAStatusCancelled SLAStatus = "CANCELLED" // Cancelled before it shipped
+)
+
+// IsOpen reports whether the group has not shipped yet, so its status may still change
+func (s SLAStatus) IsOpen() bool {
+	return s == SLAStatusOnTrack || s == SLAStatusAtRisk || s == SLAStatusBreached
+}
+
+// IsValid checks the status is a known SLA status
+func (s SLAStatus) IsValid() bool {
+	return s.IsOpen() || s == SLAStatusMet || s == SLAStatusMissed || s == SLAStatusCancelled
+}
+
+// MaxShipWithinDays caps the business days a policy allows to ship
+const MaxShipWithinDays = 60
+
+// SLAPolicy is how many business days groups shipped with a method have to
+// ship. The policy without a method applies to every other method.
+type SLAPolicy struct {
+	ShippingMethod string
+	ShipWithinDays int
+	UpdatedAt      time.Time
+}
+
+// NewSLAPolicy creates the SLA policy of a shipping method
+func NewSLAPolicy(shippingMethod string, shipWithinDays int) (*SLAPolicy, error) {
+	if shipWithinDays < 0 || shipWithinDays > MaxShipWithinDays {
+		return nil, NewFulfillmentError("ship within days must be between 0 and 60")
+	}
+	return &SLAPolicy{
+		ShippingMethod: shippingMethod,
+		ShipWithinDays: shipWithinDays,
+		UpdatedAt:      time.Now(),
+	}, nil
+}
+
+// FulfillmentSLA tracks a fulfillment group (a shipment) against the deadline
+// of its SLA policy, from the submission of its order until it ships
+type FulfillmentSLA struct {
+	ShipmentID     int64
+	OrderID        int64
+	ShippingMethod string
+	ShipmentStatus ShipmentStatus
+	StartedAt      time.Time // Submission of the order, or creation of the group
+	ShipWithinDays int
+	ShipBy         time.Time
+	Status         SLAStatus
+	ShippedAt      *time.Time
+	AlertedStatus  SLAStatus // Last status staff were told about
+	AlertedAt      *time.Time
+	UpdatedAt      time.Time
+}
+
+// Evaluate computes the deadline of the group with the business calendar and
+// policy in force and its status at now
+func (s *FulfillmentSLA) Evaluate(cal *calendar.Calendar, shipWithinDays int, atRisk time.Duration, now time.Time) {
+	s.ShipWithinDays = shipWithinDays
+	s.ShipBy = cal.Deadline(s.StartedAt, shipWithinDays)
+	s.UpdatedAt = now
+
+	switch {
+	case s.ShippedAt == nil && (s.ShipmentStatus == ShipmentStatusCancelled || s.ShipmentStatus == ShipmentStatusFailed):
+		s.Status = SLAStatusCancelled
+	case s.ShippedAt != nil && s.ShippedAt.After(s.ShipBy):
+		s.Status = SLAStatusMissed
+	case s.ShippedAt != nil:
+		s.Status = SLAStatusMet
+	case now.After(s.ShipBy):
+		s.Status = SLAStatusBreached
+	case atRisk > 0 && s.ShipBy.Sub(now) <= atRisk:
+		s.Status = SLAStatusAtRisk
+	default:
+		s.Status = SLAStatusOnTrack
+	}
+}
+
+// NeedsAlert reports whether the group became at risk or breached since staff were last told
+func (s *FulfillmentSLA) NeedsAlert() bool {
+	if s.Status != SLAStatusAtRisk && s.Status != SLAStatusBreached {
+		return false
+	}
+	return s.AlertedStatus != s.Status && s.AlertedStatus != SLAStatusBreached
+}
+
+// MarkAlerted records that staff were told about the current status
+func (s *FulfillmentSLA) MarkAlerted(at time.Time) {
+	s.AlertedStatus = s.Status
+	s.AlertedAt = &at
+}
+
+// SLASummary counts groups by SLA status: the open ones, and the ones shipped since a time
+type SLASummary struct {
+	OnTrack  int64
+	AtRisk   int64
+	Breached int64
+	Met      int64
+	Missed   int64
+}
+
+// FulfillmentSLARepository defines the interface for fulfillment SLA persistence
+type FulfillmentSLARepository interface {
+	// FindToEvaluate retrieves the groups whose SLA may change: unshipped groups
+	// not tracked yet and tracked groups still open, with their shipment status.
+	FindToEvaluate(ctx context.Context) ([]*FulfillmentSLA, error)
+
+	// Save creates or updates the SLA of a group.
+	Save(ctx context.Context, sla *FulfillmentSLA) error
+
+	// FindByShipmentID retrieves the SLA of a group, nil if it is not tracked.
+	FindByShipmentID(ctx context.Context, shipmentID int64) (*FulfillmentSLA, error)
+
+	// FindByStatus retrieves the groups in a status, earliest deadline first.
+	FindByStatus(ctx context.Context, status SLAStatus, limit int) ([]*FulfillmentSLA, error)
+
+	// Summarize counts open groups by status, and the groups shipped since shippedSince.
+	Summarize(ctx context.Context, shippedSince time.Time) (*SLASummary, error)
+
+	// FindPolicies retrieves every SLA policy.
+	FindPolicies(ctx context.Context) ([]*SLAPolicy, error)
+
+	// SavePolicy creates or replaces the policy of a shipping method.
+	SavePolicy(ctx context.Context, policy *SLAPolicy) error
+
+	// DeletePolicy removes the policy of a shipping method.
+	DeletePolicy(ctx context.Context, shippingMethod string) error
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // ShipmentStatus represents the status of a shipment
 type ShipmentStatus string
@@ -45,6 +48,18 @@ type Address struct {
 	Phone      string
 }
 
+// Lines formats the address for a label or packing slip, skipping empty parts
+func (a Address) Lines() []string {
+	locality := strings.TrimSpace(strings.Join(strings.Fields(a.City+" "+a.State+" "+a.PostalCode), " "))
+	lines := make([]string, 0, 5)
+	for _, line := range []string{a.Name, a.Line1, a.Line2, locality, a.Country} {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
 // NewShipment creates a new shipment
 func NewShipment(orderID int64, carrier, shippingMethod string, shippingCost float64, address Address) *Shipment {
 	now := time.Now()
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ShipmentSerial is the serial number of one unit of an order item, recorded
+// by the warehouse when the unit is packed in a shipment
+type ShipmentSerial struct {
+	ID           int64
+	ShipmentID   int64
+	OrderID      int64
+	OrderItemID  int64
+	SKUID        int64
+	SerialNumber string
+	RecordedBy   string
+	RecordedAt   time.Time
+}
+
+// NewShipmentSerial creates a serial number of an order item packed in a shipment
+func NewShipmentSerial(shipment *Shipment, orderItemID, skuID int64, serialNumber, recordedBy string) *ShipmentSerial {
+	return &ShipmentSerial{
+		ShipmentID:   shipment.ID,
+		OrderID:      shipment.OrderID,
+		OrderItemID:  orderItemID,
+		SKUID:        skuID,
+		SerialNumber: NormalizeSerialNumber(serialNumber),
+		RecordedBy:   recordedBy,
+		RecordedAt:   time.Now(),
+	}
+}
+
+// NormalizeSerialNumber trims and uppercases a serial number, so that serials
+// scanned or typed differently match
+func NormalizeSerialNumber(serialNumber string) string {
+	return strings.ToUpper(strings.TrimSpace(serialNumber))
+}
+
+// ShipmentSerialRepository defines the interface for shipment serial persistence
+type ShipmentSerialRepository interface {
+	// ReplaceForShipment replaces the serials recorded for a shipment
+	ReplaceForShipment(ctx context.Context, shipmentID int64, serials []*ShipmentSerial) error
+	FindByShipmentID(ctx context.Context, shipmentID int64) ([]*ShipmentSerial, error)
+	FindByOrderID(ctx context.Context, orderID int64) ([]*ShipmentSerial, error)
+	// FindBySerialNumber finds the records of a normalized serial number, newest first
+	FindBySerialNumber(ctx context.Context, serialNumber string) ([]*ShipmentSerial, error)
+	Search(ctx context.Context, filter *ShipmentSerialFilter) ([]*ShipmentSerial, int64, error)
+}
+
+// ShipmentSerialFilter represents filtering options for shipment serials
+type ShipmentSerialFilter struct {
+	Page         int
+	PageSize     int
+	SerialNumber string // Prefix of the normalized serial number
+	OrderID      int64
+	SKUID        int64
+	ShipmentID   int64
+}
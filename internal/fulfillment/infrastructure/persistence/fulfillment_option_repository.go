@@ -0,0 +1,191 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+const fulfillmentOptionColumns = `
+	fulfillment_option_id, name, description, fulfillment_type, carrier, service_code,
+	transit_days_min, transit_days_max, price_basis, price_bands, min_weight_kg, max_weight_kg,
+	countries, regions, active, created_at, updated_at`
+
+// PostgresFulfillmentOptionRepository implements the FulfillmentOptionRepository and
+// ShipmentProfileRepository interfaces using PostgreSQL
+type PostgresFulfillmentOptionRepository struct {
+	db *database.DB
+}
+
+// NewPostgresFulfillmentOptionRepository creates a new PostgresFulfillmentOptionRepository
+func NewPostgresFulfillmentOptionRepository(db *database.DB) *PostgresFulfillmentOptionRepository {
+	return &PostgresFulfillmentOptionRepository{db: db}
+}
+
+// Save creates or updates a fulfillment option
+func (r *PostgresFulfillmentOptionRepository) Save(ctx context.Context, option *domain.FulfillmentOption) error {
+	bands, err := json.Marshal(option.PriceBands)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode price bands")
+	}
+
+	if option.ID == 0 {
+		err := r.db.QueryRow(ctx, `
+			INSERT INTO blc_fulfillment_option (
+				name, description, fulfillment_type, carrier, service_code,
+				transit_days_min, transit_days_max, price_basis, price_bands, min_weight_kg, max_weight_kg,
+				countries, regions, active, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			RETURNING fulfillment_option_id`,
+			option.Name, option.Description, option.Type, option.Carrier, option.ServiceCode,
+			option.TransitDaysMin, option.TransitDaysMax, option.PriceBasis, bands, option.MinWeightKg, option.MaxWeightKg,
+			option.Countries, option.Regions, option.Active, option.CreatedAt, option.UpdatedAt,
+		).Scan(&option.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create fulfillment option")
+		}
+		return nil
+	}
+
+	result, err := r.db.Pool().Exec(ctx, `
+		UPDATE blc_fulfillment_option SET
+			name = $2, description = $3, fulfillment_type = $4, carrier = $5, service_code = $6,
+			transit_days_min = $7, transit_days_max = $8, price_basis = $9, price_bands = $10,
+			min_weight_kg = $11, max_weight_kg = $12, countries = $13, regions = $14, active = $15, updated_at = $16
+		WHERE fulfillment_option_id = $1`,
+		option.ID, option.Name, option.Description, option.Type, option.Carrier, option.ServiceCode,
+		option.TransitDaysMin, option.TransitDaysMax, option.PriceBasis, bands,
+		option.MinWeightKg, option.MaxWeightKg, option.Countries, option.Regions, option.Active, option.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update fulfillment option")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("fulfillment option")
+	}
+	return nil
+}
+
+// FindByID retrieves a fulfillment option by ID
+func (r *PostgresFulfillmentOptionRepository) FindByID(ctx context.Context, id int64) (*domain.FulfillmentOption, error) {
+	option, err := scanFulfillmentOption(r.db.QueryRow(ctx, `SELECT`+fulfillmentOptionColumns+`
+		FROM blc_fulfillment_option WHERE fulfillment_option_id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("fulfillment option")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find fulfillment option")
+	}
+	return option, nil
+}
+
+// FindAll retrieves the fulfillment options by name, only the active ones if activeOnly
+func (r *PostgresFulfillmentOptionRepository) FindAll(ctx context.Context, activeOnly bool) ([]*domain.FulfillmentOption, error) {
+	rows, err := r.db.Query(ctx, `SELECT`+fulfillmentOptionColumns+`
+		FROM blc_fulfillment_option
+		WHERE active OR NOT $1
+		ORDER BY name, fulfillment_option_id`, activeOnly)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list fulfillment options")
+	}
+	defer rows.Close()
+
+	options := make([]*domain.FulfillmentOption, 0)
+	for rows.Next() {
+		option, err := scanFulfillmentOption(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan fulfillment option")
+		}
+		options = append(options, option)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate fulfillment options")
+	}
+	return options, nil
+}
+
+// Delete removes a fulfillment option
+func (r *PostgresFulfillmentOptionRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM blc_fulfillment_option WHERE fulfillment_option_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete fulfillment option")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("fulfillment option")
+	}
+	return nil
+}
+
+// FindProfile computes the weight of an order's items in kilograms, converting
+// from the unit of each SKU (kilograms when it has none), its subtotal and the
+// destination of the address
+func (r *PostgresFulfillmentOptionRepository) FindProfile(ctx context.Context, orderID, addressID int64) (*domain.ShipmentProfile, error) {
+	profile := &domain.ShipmentProfile{}
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(SUM(oi.quantity * COALESCE(s.weight, 0) *
+				CASE UPPER(COALESCE(s.weight_unit_of_measure, ''))
+					WHEN 'POUNDS' THEN 0.45359237
+					WHEN 'LB' THEN 0.45359237
+					WHEN 'LBS' THEN 0.45359237
+					WHEN 'OUNCES' THEN 0.028349523125
+					WHEN 'OZ' THEN 0.028349523125
+					WHEN 'GRAMS' THEN 0.001
+					WHEN 'G' THEN 0.001
+					ELSE 1
+				END), 0)::float8
+		FROM blc_order_item oi
+		LEFT JOIN blc_sku s ON s.sku_id = oi.sku_id
+		WHERE oi.order_id = $1`, orderID,
+	).Scan(&profile.WeightKg)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to compute order weight")
+	}
+
+	err = r.db.QueryRow(ctx, `SELECT COALESCE(order_subtotal, 0)::float8 FROM blc_order WHERE order_id = $1`, orderID).Scan(&profile.Subtotal)
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("order")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order subtotal")
+	}
+
+	var country, region sql.NullString
+	err = r.db.QueryRow(ctx, `
+		SELECT iso_country_alpha2, COALESCE(iso_country_sub, state_prov_region)
+		FROM blc_address
+		WHERE address_id = $1`, addressID,
+	).Scan(&country, &region)
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("address")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find shipping destination")
+	}
+	profile.Country = country.String
+	profile.Region = region.String
+	return profile, nil
+}
+
+func scanFulfillmentOption(row pgx.Row) (*domain.FulfillmentOption, error) {
+	option := &domain.FulfillmentOption{}
+	var bands []byte
+	err := row.Scan(
+		&option.ID, &option.Name, &option.Description, &option.Type, &option.Carrier, &option.ServiceCode,
+		&option.TransitDaysMin, &option.TransitDaysMax, &option.PriceBasis, &bands,
+		&option.MinWeightKg, &option.MaxWeightKg, &option.Countries, &option.Regions,
+		&option.Active, &option.CreatedAt, &option.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bands, &option.PriceBands); err != nil {
+		return nil, err
+	}
+	return option, nil
+}
@@ -0,0 +1,212 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+const fulfillmentSLAColumns = `s.fulfillment_group_id, s.order_id, COALESCE(fg.shipping_method, ''), fg.status,
+	s.started_at, s.ship_within_days, s.ship_by, s.status, s.shipped_at,
+	s.alerted_status, s.alerted_at, s.updated_at`
+
+// PostgresFulfillmentSLARepository implements the FulfillmentSLARepository interface using PostgreSQL
+type PostgresFulfillmentSLARepository struct {
+	db *database.DB
+}
+
+// NewPostgresFulfillmentSLARepository creates a new PostgresFulfillmentSLARepository
+func NewPostgresFulfillmentSLARepository(db *database.DB) *PostgresFulfillmentSLARepository {
+	return &PostgresFulfillmentSLARepository{db: db}
+}
+
+// FindToEvaluate retrieves the unshipped groups not tracked yet, started when
+// their order was submitted, and the tracked groups still open
+func (r *PostgresFulfillmentSLARepository) FindToEvaluate(ctx context.Context) ([]*domain.FulfillmentSLA, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT fg.fulfillment_group_id, fg.order_id, COALESCE(fg.shipping_method, ''), fg.status,
+			   COALESCE(s.started_at, o.submit_date, fg.date_created), fg.shipped_date,
+			   COALESCE(s.status, ''), COALESCE(s.alerted_status, ''), s.alerted_at
+		FROM blc_fulfillment_group fg
+		LEFT JOIN fulfillment_sla s ON s.fulfillment_group_id = fg.fulfillment_group_id
+		LEFT JOIN blc_order o ON o.order_id = fg.order_id
+		WHERE (s.fulfillment_group_id IS NULL AND fg.status IN ('PENDING', 'PROCESSING'))
+		   OR s.status IN ('ON_TRACK', 'AT_RISK', 'BREACHED')
+		ORDER BY fg.fulfillment_group_id`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find fulfillment groups to evaluate")
+	}
+	defer rows.Close()
+
+	slas := make([]*domain.FulfillmentSLA, 0)
+	for rows.Next() {
+		sla := &domain.FulfillmentSLA{}
+		err := rows.Scan(
+			&sla.ShipmentID, &sla.OrderID, &sla.ShippingMethod, &sla.ShipmentStatus,
+			&sla.StartedAt, &sla.ShippedAt,
+			&sla.Status, &sla.AlertedStatus, &sla.AlertedAt,
+		)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan fulfillment group")
+		}
+		slas = append(slas, sla)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate fulfillment groups")
+	}
+	return slas, nil
+}
+
+// Save creates or updates the SLA of a group
+func (r *PostgresFulfillmentSLARepository) Save(ctx context.Context, sla *domain.FulfillmentSLA) error {
+	err := r.db.Exec(ctx, `
+		INSERT INTO fulfillment_sla (
+			fulfillment_group_id, order_id, started_at, ship_within_days, ship_by, status,
+			shipped_at, alerted_status, alerted_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (fulfillment_group_id) DO UPDATE
+		SET ship_within_days = EXCLUDED.ship_within_days,
+			ship_by = EXCLUDED.ship_by,
+			status = EXCLUDED.status,
+			shipped_at = EXCLUDED.shipped_at,
+			alerted_status = EXCLUDED.alerted_status,
+			alerted_at = EXCLUDED.alerted_at,
+			updated_at = EXCLUDED.updated_at`,
+		sla.ShipmentID, sla.OrderID, sla.StartedAt, sla.ShipWithinDays, sla.ShipBy, sla.Status,
+		sla.ShippedAt, sla.AlertedStatus, sla.AlertedAt, sla.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save fulfillment SLA")
+	}
+	return nil
+}
+
+// FindByShipmentID retrieves the SLA of a group, nil if it is not tracked
+func (r *PostgresFulfillmentSLARepository) FindByShipmentID(ctx context.Context, shipmentID int64) (*domain.FulfillmentSLA, error) {
+	sla, err := scanFulfillmentSLA(r.db.QueryRow(ctx, `SELECT `+fulfillmentSLAColumns+`
+		FROM fulfillment_sla s
+		JOIN blc_fulfillment_group fg ON fg.fulfillment_group_id = s.fulfillment_group_id
+		WHERE s.fulfillment_group_id = $1`, shipmentID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find fulfillment SLA")
+	}
+	return sla, nil
+}
+
+// FindByStatus retrieves the groups in a status, earliest deadline first
+func (r *PostgresFulfillmentSLARepository) FindByStatus(ctx context.Context, status domain.SLAStatus, limit int) ([]*domain.FulfillmentSLA, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+fulfillmentSLAColumns+`
+		FROM fulfillment_sla s
+		JOIN blc_fulfillment_group fg ON fg.fulfillment_group_id = s.fulfillment_group_id
+		WHERE s.status = $1
+		ORDER BY s.ship_by, s.fulfillment_group_id
+		LIMIT $2`, status, limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find fulfillment SLAs")
+	}
+	defer rows.Close()
+
+	slas := make([]*domain.FulfillmentSLA, 0)
+	for rows.Next() {
+		sla, err := scanFulfillmentSLA(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan fulfillment SLA")
+		}
+		slas = append(slas, sla)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate fulfillment SLAs")
+	}
+	return slas, nil
+}
+
+// Summarize counts open groups by status, and the groups shipped since shippedSince
+func (r *PostgresFulfillmentSLARepository) Summarize(ctx context.Context, shippedSince time.Time) (*domain.SLASummary, error) {
+	summary := &domain.SLASummary{}
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status = 'ON_TRACK'),
+			   COUNT(*) FILTER (WHERE status = 'AT_RISK'),
+			   COUNT(*) FILTER (WHERE status = 'BREACHED'),
+			   COUNT(*) FILTER (WHERE status = 'MET' AND shipped_at >= $1),
+			   COUNT(*) FILTER (WHERE status = 'MISSED' AND shipped_at >= $1)
+		FROM fulfillment_sla`, shippedSince,
+	).Scan(&summary.OnTrack, &summary.AtRisk, &summary.Breached, &summary.Met, &summary.Missed)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to summarize fulfillment SLAs")
+	}
+	return summary, nil
+}
+
+// FindPolicies retrieves every SLA policy
+func (r *PostgresFulfillmentSLARepository) FindPolicies(ctx context.Context) ([]*domain.SLAPolicy, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT shipping_method, ship_within_days, updated_at
+		FROM fulfillment_sla_policy
+		ORDER BY shipping_method`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find SLA policies")
+	}
+	defer rows.Close()
+
+	policies := make([]*domain.SLAPolicy, 0)
+	for rows.Next() {
+		policy := &domain.SLAPolicy{}
+		if err := rows.Scan(&policy.ShippingMethod, &policy.ShipWithinDays, &policy.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan SLA policy")
+		}
+		policies = append(policies, policy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate SLA policies")
+	}
+	return policies, nil
+}
+
+// SavePolicy creates or replaces the policy of a shipping method
+func (r *PostgresFulfillmentSLARepository) SavePolicy(ctx context.Context, policy *domain.SLAPolicy) error {
+	err := r.db.Exec(ctx, `
+		INSERT INTO fulfillment_sla_policy (shipping_method, ship_within_days, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (shipping_method) DO UPDATE
+		SET ship_within_days = EXCLUDED.ship_within_days, updated_at = EXCLUDED.updated_at`,
+		policy.ShippingMethod, policy.ShipWithinDays, policy.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save SLA policy")
+	}
+	return nil
+}
+
+// DeletePolicy removes the policy of a shipping method
+func (r *PostgresFulfillmentSLARepository) DeletePolicy(ctx context.Context, shippingMethod string) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM fulfillment_sla_policy WHERE shipping_method = $1`, shippingMethod)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete SLA policy")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("SLA policy %q", shippingMethod))
+	}
+	return nil
+}
+
+func scanFulfillmentSLA(row pgx.Row) (*domain.FulfillmentSLA, error) {
+	sla := &domain.FulfillmentSLA{}
+	err := row.Scan(
+		&sla.ShipmentID, &sla.OrderID, &sla.ShippingMethod, &sla.ShipmentStatus,
+		&sla.StartedAt, &sla.ShipWithinDays, &sla.ShipBy, &sla.Status, &sla.ShippedAt,
+		&sla.AlertedStatus, &sla.AlertedAt, &sla.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sla, nil
+}
@@ -0,0 +1,192 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+const shipmentSerialColumns = `shipment_serial_id, shipment_id, order_id, order_item_id, sku_id,
+	serial_number, recorded_by, recorded_at`
+
+// PostgresShipmentSerialRepository implements the ShipmentSerialRepository interface using PostgreSQL
+type PostgresShipmentSerialRepository struct {
+	db *database.DB
+}
+
+// NewPostgresShipmentSerialRepository creates a new PostgresShipmentSerialRepository
+func NewPostgresShipmentSerialRepository(db *database.DB) *PostgresShipmentSerialRepository {
+	return &PostgresShipmentSerialRepository{db: db}
+}
+
+// ReplaceForShipment replaces the serials recorded for a shipment
+func (r *PostgresShipmentSerialRepository) ReplaceForShipment(ctx context.Context, shipmentID int64, serials []*domain.ShipmentSerial) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM fulfillment_shipment_serial WHERE shipment_id = $1`, shipmentID); err != nil {
+			return errors.InternalWrap(err, "failed to clear shipment serials")
+		}
+
+		query := `
+			INSERT INTO fulfillment_shipment_serial (
+				shipment_id, order_id, order_item_id, sku_id, serial_number, recorded_by, recorded_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING shipment_serial_id`
+		for _, serial := range serials {
+			err := tx.QueryRow(ctx, query,
+				shipmentID,
+				serial.OrderID,
+				serial.OrderItemID,
+				serial.SKUID,
+				serial.SerialNumber,
+				sql.NullString{String: serial.RecordedBy, Valid: serial.RecordedBy != ""},
+				serial.RecordedAt,
+			).Scan(&serial.ID)
+			if err != nil {
+				return errors.InternalWrap(err, "failed to record shipment serial")
+			}
+		}
+		return nil
+	})
+}
+
+// FindByShipmentID finds the serials recorded for a shipment
+func (r *PostgresShipmentSerialRepository) FindByShipmentID(ctx context.Context, shipmentID int64) ([]*domain.ShipmentSerial, error) {
+	query := `SELECT ` + shipmentSerialColumns + `
+		FROM fulfillment_shipment_serial
+		WHERE shipment_id = $1
+		ORDER BY order_item_id, serial_number`
+
+	rows, err := r.db.Query(ctx, query, shipmentID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find shipment serials")
+	}
+	defer rows.Close()
+
+	return scanShipmentSerials(rows)
+}
+
+// FindByOrderID finds the serials recorded for all shipments of an order
+func (r *PostgresShipmentSerialRepository) FindByOrderID(ctx context.Context, orderID int64) ([]*domain.ShipmentSerial, error) {
+	query := `SELECT ` + shipmentSerialColumns + `
+		FROM fulfillment_shipment_serial
+		WHERE order_id = $1
+		ORDER BY order_item_id, serial_number`
+
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order serials")
+	}
+	defer rows.Close()
+
+	return scanShipmentSerials(rows)
+}
+
+// FindBySerialNumber finds the records of a normalized serial number, newest first
+func (r *PostgresShipmentSerialRepository) FindBySerialNumber(ctx context.Context, serialNumber string) ([]*domain.ShipmentSerial, error) {
+	query := `SELECT ` + shipmentSerialColumns + `
+		FROM fulfillment_shipment_serial
+		WHERE serial_number = $1
+		ORDER BY recorded_at DESC`
+
+	rows, err := r.db.Query(ctx, query, serialNumber)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find serial number")
+	}
+	defer rows.Close()
+
+	return scanShipmentSerials(rows)
+}
+
+// Search finds serials by serial number prefix, order, SKU or shipment
+func (r *PostgresShipmentSerialRepository) Search(ctx context.Context, filter *domain.ShipmentSerialFilter) ([]*domain.ShipmentSerial, int64, error) {
+	where := " WHERE 1=1"
+	args := make([]interface{}, 0)
+	argIndex := 1
+
+	if filter != nil {
+		if filter.SerialNumber != "" {
+			where += fmt.Sprintf(" AND serial_number LIKE $%d", argIndex)
+			args = append(args, escapeLike(filter.SerialNumber)+"%")
+			argIndex++
+		}
+		if filter.OrderID > 0 {
+			where += fmt.Sprintf(" AND order_id = $%d", argIndex)
+			args = append(args, filter.OrderID)
+			argIndex++
+		}
+		if filter.SKUID > 0 {
+			where += fmt.Sprintf(" AND sku_id = $%d", argIndex)
+			args = append(args, filter.SKUID)
+			argIndex++
+		}
+		if filter.ShipmentID > 0 {
+			where += fmt.Sprintf(" AND shipment_id = $%d", argIndex)
+			args = append(args, filter.ShipmentID)
+			argIndex++
+		}
+	}
+
+	var total int64
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM fulfillment_shipment_serial"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count shipment serials")
+	}
+
+	query := `SELECT ` + shipmentSerialColumns + ` FROM fulfillment_shipment_serial` + where +
+		" ORDER BY recorded_at DESC, shipment_serial_id DESC"
+	if filter != nil && filter.PageSize > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to search shipment serials")
+	}
+	defer rows.Close()
+
+	serials, err := scanShipmentSerials(rows)
+	return serials, total, err
+}
+
+// scanShipmentSerials scans shipment serial rows
+func scanShipmentSerials(rows pgx.Rows) ([]*domain.ShipmentSerial, error) {
+	serials := make([]*domain.ShipmentSerial, 0)
+	for rows.Next() {
+		serial := &domain.ShipmentSerial{}
+		var recordedBy sql.NullString
+		err := rows.Scan(
+			&serial.ID,
+			&serial.ShipmentID,
+			&serial.OrderID,
+			&serial.OrderItemID,
+			&serial.SKUID,
+			&serial.SerialNumber,
+			&recordedBy,
+			&serial.RecordedAt,
+		)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan shipment serial")
+		}
+		serial.RecordedBy = recordedBy.String
+		serials = append(serials, serial)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to read shipment serials")
+	}
+	return serials, nil
+}
+
+// likeEscaper escapes the LIKE wildcards of a search prefix
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
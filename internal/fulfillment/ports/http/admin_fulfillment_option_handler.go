@@ -0,0 +1,141 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/fulfillment/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminFulfillmentOptionHandler handles the management of the fulfillment options offered at checkout
+type AdminFulfillmentOptionHandler struct {
+	optionService  application.FulfillmentOptionService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminFulfillmentOptionHandler creates a new AdminFulfillmentOptionHandler
+func NewAdminFulfillmentOptionHandler(
+	optionService application.FulfillmentOptionService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminFulfillmentOptionHandler {
+	return &AdminFulfillmentOptionHandler{
+		optionService:  optionService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers fulfillment option routes
+func (h *AdminFulfillmentOptionHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/fulfillment/options", func(r chi.Router) {
+			r.Get("/", h.ListOptions)
+			r.Post("/", h.CreateOption)
+			r.Get("/{id}", h.GetOption)
+			r.Put("/{id}", h.UpdateOption)
+			r.Delete("/{id}", h.DeleteOption)
+		})
+	})
+}
+
+// ListOptions lists the fulfillment options, only the active ones with ?active=true
+func (h *AdminFulfillmentOptionHandler) ListOptions(w http.ResponseWriter, r *http.Request) {
+	options, err := h.optionService.ListOptions(r.Context(), r.URL.Query().Get("active") == "true")
+	if err != nil {
+		h.log.WithError(err).Error("failed to list fulfillment options")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, options)
+}
+
+// GetOption retrieves a fulfillment option
+func (h *AdminFulfillmentOptionHandler) GetOption(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseFulfillmentOptionID(w, r)
+	if !ok {
+		return
+	}
+
+	option, err := h.optionService.GetOption(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, option)
+}
+
+// CreateOption creates a fulfillment option
+func (h *AdminFulfillmentOptionHandler) CreateOption(w http.ResponseWriter, r *http.Request) {
+	var req application.SaveFulfillmentOptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	option, err := h.optionService.CreateOption(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to create fulfillment option")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, option)
+}
+
+// UpdateOption replaces the settings of a fulfillment option
+func (h *AdminFulfillmentOptionHandler) UpdateOption(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseFulfillmentOptionID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.SaveFulfillmentOptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	option, err := h.optionService.UpdateOption(r.Context(), id, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("fulfillment_option_id", id).Error("failed to update fulfillment option")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, option)
+}
+
+// DeleteOption removes a fulfillment option
+func (h *AdminFulfillmentOptionHandler) DeleteOption(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseFulfillmentOptionID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.optionService.DeleteOption(r.Context(), id); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseFulfillmentOptionID reads the option ID of the path, responding with an error when it is invalid
+func parseFulfillmentOptionID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid fulfillment option ID").WithInternal(err))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/fulfillment/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminFulfillmentSLAHandler handles the fulfillment SLA dashboard and the SLA
+// policies of shipping methods
+type AdminFulfillmentSLAHandler struct {
+	slaService     application.FulfillmentSLAService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminFulfillmentSLAHandler creates a new AdminFulfillmentSLAHandler
+func NewAdminFulfillmentSLAHandler(
+	slaService application.FulfillmentSLAService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminFulfillmentSLAHandler {
+	return &AdminFulfillmentSLAHandler{
+		slaService:     slaService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers fulfillment SLA routes
+func (h *AdminFulfillmentSLAHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/fulfillment/sla", func(r chi.Router) {
+			r.Get("/", h.Dashboard)
+			r.Get("/groups", h.ListGroups)
+			r.Get("/shipments/{id}", h.GetShipmentSLA)
+			r.Post("/check", h.CheckSLAs)
+			r.Get("/policies", h.ListPolicies)
+			r.Put("/policies", h.SavePolicy)
+			r.Delete("/policies", h.DeletePolicy)
+		})
+	})
+}
+
+// Dashboard summarizes the open groups by SLA status and lists the late and at-risk ones
+func (h *AdminFulfillmentSLAHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	dashboard, err := h.slaService.Dashboard(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to load fulfillment SLA dashboard")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, dashboard)
+}
+
+// ListGroups lists the groups in the SLA ?status=, earliest deadline first
+func (h *AdminFulfillmentSLAHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	groups, err := h.slaService.ListGroups(r.Context(), r.URL.Query().Get("status"), limit)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, groups)
+}
+
+// GetShipmentSLA retrieves the SLA of a fulfillment group
+func (h *AdminFulfillmentSLAHandler) GetShipmentSLA(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid shipment ID").WithInternal(err))
+		return
+	}
+
+	sla, err := h.slaService.GetShipmentSLA(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, sla)
+}
+
+// CheckSLAs evaluates the open groups now instead of waiting for the monitor
+func (h *AdminFulfillmentSLAHandler) CheckSLAs(w http.ResponseWriter, r *http.Request) {
+	result, err := h.slaService.CheckSLAs(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to check fulfillment SLAs")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// ListPolicies lists the business days each shipping method has to ship
+func (h *AdminFulfillmentSLAHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.slaService.ListPolicies(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list fulfillment SLA policies")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, policies)
+}
+
+// SavePolicy sets the business days a shipping method has to ship
+func (h *AdminFulfillmentSLAHandler) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	var req application.SaveSLAPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	policy, err := h.slaService.SavePolicy(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to save fulfillment SLA policy")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, policy)
+}
+
+// DeletePolicy removes the policy of the ?shipping_method=, which falls back to the default
+func (h *AdminFulfillmentSLAHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	if err := h.slaService.DeletePolicy(r.Context(), r.URL.Query().Get("shipping_method")); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -9,9 +9,12 @@ import (
 	"github.com/qhato/ecommerce/internal/fulfillment/application"
 	"github.com/qhato/ecommerce/internal/fulfillment/application/commands"
 	"github.com/qhato/ecommerce/internal/fulfillment/domain"
+	orderApp "github.com/qhato/ecommerce/internal/order/application"
+	orderHttp "github.com/qhato/ecommerce/internal/order/ports/http"
 	"github.com/qhato/ecommerce/pkg/errors"
 	httpPkg "github.com/qhato/ecommerce/pkg/http"
 	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
 	"github.com/qhato/ecommerce/pkg/validator"
 )
 
@@ -19,22 +22,32 @@ import (
 type AdminShipmentHandler struct {
 	commandHandler *commands.ShipmentCommandHandler
 	repo           domain.ShipmentRepository
+	serials        application.SerialNumberService
+	documents      orderApp.OrderDocumentService
 	validator      *validator.Validator
 	log            *logger.Logger
+
+	shipFromCountry string
 }
 
 // NewAdminShipmentHandler creates a new AdminShipmentHandler
 func NewAdminShipmentHandler(
 	commandHandler *commands.ShipmentCommandHandler,
 	repo domain.ShipmentRepository,
+	serials application.SerialNumberService,
+	documents orderApp.OrderDocumentService,
 	validator *validator.Validator,
 	log *logger.Logger,
+	shipFromCountry string,
 ) *AdminShipmentHandler {
 	return &AdminShipmentHandler{
-		commandHandler: commandHandler,
-		repo:           repo,
-		validator:      validator,
-		log:            log,
+		commandHandler:  commandHandler,
+		repo:            repo,
+		serials:         serials,
+		documents:       documents,
+		validator:       validator,
+		log:             log,
+		shipFromCountry: shipFromCountry,
 	}
 }
 
@@ -43,7 +56,14 @@ func (h *AdminShipmentHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/shipments", func(r chi.Router) {
 		r.Post("/", h.CreateShipment)
 		r.Get("/", h.ListShipments)
+		r.Get("/serials", h.SearchSerials)
+		r.Post("/serials/validate", h.ValidateSerial)
 		r.Get("/{id}", h.GetShipment)
+		r.Get("/{id}/serials", h.GetShipmentSerials)
+		r.Put("/{id}/serials", h.RecordSerials)
+		r.Get("/{id}/packing-slip.pdf", h.GetPackingSlip)
+		r.Get("/{id}/commercial-invoice.pdf", h.GetCommercialInvoice)
+		r.Get("/{id}/label-request", h.GetLabelRequest)
 		r.Post("/{id}/ship", h.ShipShipment)
 		r.Post("/{id}/deliver", h.DeliverShipment)
 		r.Post("/{id}/cancel", h.CancelShipment)
@@ -113,7 +133,216 @@ func (h *AdminShipmentHandler) GetShipment(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	httpPkg.RespondJSON(w, http.StatusOK, application.ToShipmentDTO(shipment))
+	serials, err := h.serials.ShipmentSerials(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	dto := application.ToShipmentDTO(shipment)
+	dto.SerialNumbers = serials
+	httpPkg.RespondJSON(w, http.StatusOK, dto)
+}
+
+// GetShipmentSerials lists the serial numbers recorded for a shipment
+func (h *AdminShipmentHandler) GetShipmentSerials(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid shipment ID").WithInternal(err))
+		return
+	}
+
+	serials, err := h.serials.ShipmentSerials(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, serials)
+}
+
+// RecordSerials replaces the serial numbers recorded for the items of a shipment
+func (h *AdminShipmentHandler) RecordSerials(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid shipment ID").WithInternal(err))
+		return
+	}
+
+	var req application.RecordSerialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("validation failed").WithInternal(err))
+		return
+	}
+
+	serials, err := h.serials.RecordSerials(r.Context(), id, req.Items, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).WithField("shipment_id", id).Error("failed to record serial numbers")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, serials)
+}
+
+// SearchSerials searches recorded serial numbers by prefix (?serial=), order or SKU
+func (h *AdminShipmentHandler) SearchSerials(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	orderID, _ := strconv.ParseInt(r.URL.Query().Get("order_id"), 10, 64)
+	skuID, _ := strconv.ParseInt(r.URL.Query().Get("sku_id"), 10, 64)
+
+	result, err := h.serials.SearchSerials(r.Context(), &application.SerialSearchQuery{
+		Page:         page,
+		PageSize:     pageSize,
+		SerialNumber: r.URL.Query().Get("serial"),
+		OrderID:      orderID,
+		SKUID:        skuID,
+	})
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// ValidateSerial checks a serial number for a warranty claim or return
+func (h *AdminShipmentHandler) ValidateSerial(w http.ResponseWriter, r *http.Request) {
+	var req application.ValidateSerialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("validation failed").WithInternal(err))
+		return
+	}
+
+	result, err := h.serials.ValidateSerial(r.Context(), &req)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// GetPackingSlip renders the packing slip packed with a shipment
+func (h *AdminShipmentHandler) GetPackingSlip(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid shipment ID").WithInternal(err))
+		return
+	}
+
+	shipment, err := h.repo.FindByID(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, errors.InternalWrap(err, "failed to get shipment"))
+		return
+	}
+	if shipment == nil {
+		httpPkg.RespondError(w, errors.NotFound("shipment not found"))
+		return
+	}
+
+	document, err := h.documents.RenderPackingSlip(r.Context(), shipment.OrderID, &orderApp.PackingSlipShipmentDTO{
+		ShipmentID:     shipment.ID,
+		Carrier:        shipment.Carrier,
+		ShippingMethod: shipment.ShippingMethod,
+		TrackingNumber: shipment.TrackingNumber,
+		AddressLines:   shipment.ShippingAddress.Lines(),
+	})
+	if err != nil {
+		h.log.WithError(err).WithField("shipment_id", id).Error("failed to render packing slip")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	orderHttp.RespondDocument(w, document)
+}
+
+// GetCommercialInvoice renders the commercial invoice a cross-border shipment clears customs with
+func (h *AdminShipmentHandler) GetCommercialInvoice(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid shipment ID").WithInternal(err))
+		return
+	}
+
+	shipment, err := h.repo.FindByID(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, errors.InternalWrap(err, "failed to get shipment"))
+		return
+	}
+	if shipment == nil {
+		httpPkg.RespondError(w, errors.NotFound("shipment not found"))
+		return
+	}
+	if !application.IsCrossBorder(shipment, h.shipFromCountry) {
+		httpPkg.RespondError(w, errors.Conflict("domestic shipments need no commercial invoice"))
+		return
+	}
+
+	document, err := h.documents.RenderCommercialInvoice(r.Context(), shipment.OrderID, &orderApp.PackingSlipShipmentDTO{
+		ShipmentID:     shipment.ID,
+		Carrier:        shipment.Carrier,
+		ShippingMethod: shipment.ShippingMethod,
+		TrackingNumber: shipment.TrackingNumber,
+		AddressLines:   shipment.ShippingAddress.Lines(),
+	})
+	if err != nil {
+		h.log.WithError(err).WithField("shipment_id", id).Error("failed to render commercial invoice")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	orderHttp.RespondDocument(w, document)
+}
+
+// GetLabelRequest builds the request a carrier's label API is sent for a shipment,
+// with the customs declaration of its contents when it ships abroad
+func (h *AdminShipmentHandler) GetLabelRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid shipment ID").WithInternal(err))
+		return
+	}
+
+	shipment, err := h.repo.FindByID(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, errors.InternalWrap(err, "failed to get shipment"))
+		return
+	}
+	if shipment == nil {
+		httpPkg.RespondError(w, errors.NotFound("shipment not found"))
+		return
+	}
+
+	request := &application.LabelRequestDTO{
+		ShipmentID:      shipment.ID,
+		OrderID:         shipment.OrderID,
+		Carrier:         shipment.Carrier,
+		ShippingMethod:  shipment.ShippingMethod,
+		ShipFromCountry: h.shipFromCountry,
+		ShipTo:          application.ToShipmentDTO(shipment).ShippingAddress,
+	}
+	if application.IsCrossBorder(shipment, h.shipFromCountry) {
+		request.Customs, err = h.documents.GetCustomsDeclaration(r.Context(), shipment.OrderID)
+		if err != nil {
+			h.log.WithError(err).WithField("shipment_id", id).Error("failed to declare shipment to customs")
+			httpPkg.RespondError(w, err)
+			return
+		}
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, request)
 }
 
 // GetShipmentByTracking retrieves a shipment by tracking number
@@ -226,6 +455,15 @@ func (h *AdminShipmentHandler) ShipShipment(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Serials are recorded first so that a rejected serial leaves the shipment unshipped
+	if len(req.Serials) > 0 {
+		if _, err := h.serials.RecordSerials(r.Context(), id, req.Serials, middleware.GetUserID(r.Context())); err != nil {
+			h.log.WithError(err).WithField("shipment_id", id).Error("failed to record serial numbers")
+			httpPkg.RespondError(w, err)
+			return
+		}
+	}
+
 	err = h.commandHandler.ShipShipment(r.Context(), id, req.TrackingNumber)
 	if err != nil {
 		httpPkg.RespondError(w, errors.InternalWrap(err, "failed to ship shipment"))
@@ -0,0 +1,312 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+)
+
+// ATPService computes the available-to-promise quantity of SKUs: stock on hand,
+// less what is reserved or allocated to orders, plus inbound receipts expected
+// within the inbound horizon.
+type ATPService interface {
+	// GetAvailableToPromise returns the ATP of each SKU, with a breakdown per warehouse.
+	// SKUs without inventory levels or inbound receipts have nothing to promise.
+	GetAvailableToPromise(ctx context.Context, skuIDs []string) ([]*AvailableToPromiseDTO, error)
+
+	// CanPromise checks if the quantity of a SKU can be promised to a customer.
+	CanPromise(ctx context.Context, skuID string, quantity int) (bool, error)
+
+	// CreateInboundReceipt records stock expected to arrive.
+	CreateInboundReceipt(ctx context.Context, cmd *CreateInboundReceiptCommand) (*InboundReceiptDTO, error)
+
+	// ReceiveInboundReceipt adds the receipt's quantity to the stock on hand of its warehouse.
+	ReceiveInboundReceipt(ctx context.Context, id string) (*InboundReceiptDTO, error)
+
+	// CancelInboundReceipt stops counting a receipt that will not arrive.
+	CancelInboundReceipt(ctx context.Context, id string) (*InboundReceiptDTO, error)
+}
+
+// AvailableToPromiseDTO represents the ATP of a SKU across warehouses
+type AvailableToPromiseDTO struct {
+	SKUID          string                            `json:"sku_id"`
+	OnHand         int                               `json:"on_hand"`
+	Reserved       int                               `json:"reserved"`
+	Inbound        int                               `json:"inbound"`
+	Available      int                               `json:"available"`
+	AllowBackorder bool                              `json:"allow_backorder"`
+	Warehouses     []*WarehouseAvailableToPromiseDTO `json:"warehouses"`
+}
+
+// WarehouseAvailableToPromiseDTO represents the ATP of a SKU at one warehouse
+type WarehouseAvailableToPromiseDTO struct {
+	WarehouseID    *string `json:"warehouse_id,omitempty"`
+	OnHand         int     `json:"on_hand"`
+	Reserved       int     `json:"reserved"`
+	Inbound        int     `json:"inbound"`
+	Available      int     `json:"available"`
+	AllowBackorder bool    `json:"allow_backorder"`
+}
+
+// InboundReceiptDTO represents an inbound receipt data transfer object
+type InboundReceiptDTO struct {
+	ID          string     `json:"id"`
+	SKUID       string     `json:"sku_id"`
+	WarehouseID *string    `json:"warehouse_id,omitempty"`
+	Quantity    int        `json:"quantity"`
+	ExpectedAt  time.Time  `json:"expected_at"`
+	Reference   string     `json:"reference,omitempty"`
+	Status      string     `json:"status"`
+	ReceivedAt  *time.Time `json:"received_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CreateInboundReceiptCommand is a command to record expected stock
+type CreateInboundReceiptCommand struct {
+	SKUID       string    `json:"sku_id"`
+	WarehouseID *string   `json:"warehouse_id"`
+	Quantity    int       `json:"quantity"`
+	ExpectedAt  time.Time `json:"expected_at"`
+	Reference   string    `json:"reference"`
+}
+
+type atpService struct {
+	inventoryRepo  domain.InventoryRepository
+	receiptRepo    domain.InboundReceiptRepository
+	inboundHorizon time.Duration
+	stockEvents    stockEvents
+}
+
+// NewATPService creates a new instance of ATPService.
+// Receipts expected further out than inboundHorizon are not promised; 0 counts every receipt.
+func NewATPService(
+	inventoryRepo domain.InventoryRepository,
+	receiptRepo domain.InboundReceiptRepository,
+	inboundHorizon time.Duration,
+	eventBus event.Bus, // Optional, nil publishes no stock changes
+) ATPService {
+	return &atpService{
+		inventoryRepo:  inventoryRepo,
+		receiptRepo:    receiptRepo,
+		inboundHorizon: inboundHorizon,
+		stockEvents:    stockEvents{eventBus: eventBus},
+	}
+}
+
+func (s *atpService) GetAvailableToPromise(ctx context.Context, skuIDs []string) ([]*AvailableToPromiseDTO, error) {
+	if len(skuIDs) == 0 {
+		return []*AvailableToPromiseDTO{}, nil
+	}
+
+	levels, err := s.inventoryRepo.FindBySKUIDs(ctx, skuIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inventory levels: %w", err)
+	}
+
+	expectedBy := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	if s.inboundHorizon > 0 {
+		expectedBy = time.Now().Add(s.inboundHorizon)
+	}
+	receipts, err := s.receiptRepo.FindExpectedBySKUIDs(ctx, skuIDs, expectedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inbound receipts: %w", err)
+	}
+
+	// Warehouses are keyed per SKU; levels and receipts without a warehouse share the empty key
+	type warehouseStock struct {
+		warehouseID *string
+		level       *domain.InventoryLevel
+		inbound     int
+	}
+	stock := make(map[string]map[string]*warehouseStock, len(skuIDs))
+	order := make(map[string][]string, len(skuIDs))
+	warehouse := func(skuID string, warehouseID *string) *warehouseStock {
+		key := ""
+		if warehouseID != nil {
+			key = *warehouseID
+		}
+		if stock[skuID] == nil {
+			stock[skuID] = make(map[string]*warehouseStock)
+		}
+		ws, ok := stock[skuID][key]
+		if !ok {
+			ws = &warehouseStock{warehouseID: warehouseID}
+			stock[skuID][key] = ws
+			order[skuID] = append(order[skuID], key)
+		}
+		return ws
+	}
+	for _, level := range levels {
+		ws := warehouse(level.SKUID, level.WarehouseID)
+		if ws.level == nil {
+			ws.level = level
+			continue
+		}
+		// Several locations in one warehouse add up
+		merged := *ws.level
+		merged.QuantityOnHand += level.QuantityOnHand
+		merged.QuantityReserved += level.QuantityReserved
+		merged.QuantityAllocated += level.QuantityAllocated
+		merged.AllowBackorder = merged.AllowBackorder || level.AllowBackorder
+		ws.level = &merged
+	}
+	for _, receipt := range receipts {
+		warehouse(receipt.SKUID, receipt.WarehouseID).inbound += receipt.Quantity
+	}
+
+	result := make([]*AvailableToPromiseDTO, 0, len(skuIDs))
+	seen := make(map[string]bool, len(skuIDs))
+	for _, skuID := range skuIDs {
+		if seen[skuID] {
+			continue
+		}
+		seen[skuID] = true
+
+		dto := &AvailableToPromiseDTO{SKUID: skuID, Warehouses: []*WarehouseAvailableToPromiseDTO{}}
+		for _, key := range order[skuID] {
+			ws := stock[skuID][key]
+			atp := domain.NewAvailableToPromise(skuID, ws.warehouseID, ws.level, ws.inbound)
+			dto.OnHand += atp.OnHand
+			dto.Reserved += atp.Reserved
+			dto.Inbound += atp.Inbound
+			dto.Available += atp.Available
+			dto.AllowBackorder = dto.AllowBackorder || atp.AllowBackorder
+			dto.Warehouses = append(dto.Warehouses, toWarehouseATPDTO(atp))
+		}
+		result = append(result, dto)
+	}
+	return result, nil
+}
+
+func (s *atpService) CanPromise(ctx context.Context, skuID string, quantity int) (bool, error) {
+	atps, err := s.GetAvailableToPromise(ctx, []string{skuID})
+	if err != nil {
+		return false, err
+	}
+	atp := atps[0]
+	return atp.AllowBackorder || atp.Available >= quantity, nil
+}
+
+func (s *atpService) CreateInboundReceipt(ctx context.Context, cmd *CreateInboundReceiptCommand) (*InboundReceiptDTO, error) {
+	receipt, err := domain.NewInboundReceipt(cmd.SKUID, cmd.WarehouseID, cmd.Quantity, cmd.ExpectedAt, cmd.Reference)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.receiptRepo.Save(ctx, receipt); err != nil {
+		return nil, fmt.Errorf("failed to save inbound receipt: %w", err)
+	}
+	s.stockEvents.publish(ctx, domain.StockChangeInbound, receipt.SKUID)
+	return toInboundReceiptDTO(receipt), nil
+}
+
+func (s *atpService) ReceiveInboundReceipt(ctx context.Context, id string) (*InboundReceiptDTO, error) {
+	receipt, err := s.findReceipt(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := receipt.Receive(); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+
+	level, err := s.receivingLevel(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+	if err := level.Increment(receipt.Quantity); err != nil {
+		return nil, fmt.Errorf("failed to increment inventory: %w", err)
+	}
+	if err := s.inventoryRepo.Save(ctx, level); err != nil {
+		return nil, fmt.Errorf("failed to save inventory level after receipt: %w", err)
+	}
+
+	if err := s.receiptRepo.Save(ctx, receipt); err != nil {
+		return nil, fmt.Errorf("failed to save inbound receipt: %w", err)
+	}
+	s.stockEvents.publish(ctx, domain.StockChangeInbound, receipt.SKUID)
+	return toInboundReceiptDTO(receipt), nil
+}
+
+func (s *atpService) CancelInboundReceipt(ctx context.Context, id string) (*InboundReceiptDTO, error) {
+	receipt, err := s.findReceipt(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := receipt.Cancel(); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.receiptRepo.Save(ctx, receipt); err != nil {
+		return nil, fmt.Errorf("failed to save inbound receipt: %w", err)
+	}
+	s.stockEvents.publish(ctx, domain.StockChangeInbound, receipt.SKUID)
+	return toInboundReceiptDTO(receipt), nil
+}
+
+func (s *atpService) findReceipt(ctx context.Context, id string) (*domain.InboundReceipt, error) {
+	receipt, err := s.receiptRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inbound receipt: %w", err)
+	}
+	if receipt == nil {
+		return nil, errors.NotFound("inbound receipt")
+	}
+	return receipt, nil
+}
+
+// receivingLevel finds the inventory level a receipt is received into, creating
+// one when the SKU is not stocked at the receipt's warehouse yet
+func (s *atpService) receivingLevel(ctx context.Context, receipt *domain.InboundReceipt) (*domain.InventoryLevel, error) {
+	levels, err := s.inventoryRepo.FindBySKUIDs(ctx, []string{receipt.SKUID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inventory levels: %w", err)
+	}
+	for _, level := range levels {
+		if sameWarehouse(level.WarehouseID, receipt.WarehouseID) {
+			return level, nil
+		}
+	}
+
+	level, err := domain.NewInventoryLevel(receipt.SKUID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inventory level domain entity: %w", err)
+	}
+	level.WarehouseID = receipt.WarehouseID
+	return level, nil
+}
+
+func sameWarehouse(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func toWarehouseATPDTO(atp *domain.AvailableToPromise) *WarehouseAvailableToPromiseDTO {
+	return &WarehouseAvailableToPromiseDTO{
+		WarehouseID:    atp.WarehouseID,
+		OnHand:         atp.OnHand,
+		Reserved:       atp.Reserved,
+		Inbound:        atp.Inbound,
+		Available:      atp.Available,
+		AllowBackorder: atp.AllowBackorder,
+	}
+}
+
+func toInboundReceiptDTO(receipt *domain.InboundReceipt) *InboundReceiptDTO {
+	return &InboundReceiptDTO{
+		ID:          receipt.ID,
+		SKUID:       receipt.SKUID,
+		WarehouseID: receipt.WarehouseID,
+		Quantity:    receipt.Quantity,
+		ExpectedAt:  receipt.ExpectedAt,
+		Reference:   receipt.Reference,
+		Status:      string(receipt.Status),
+		ReceivedAt:  receipt.ReceivedAt,
+		CreatedAt:   receipt.CreatedAt,
+		UpdatedAt:   receipt.UpdatedAt,
+	}
+}
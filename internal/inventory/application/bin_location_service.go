@@ -0,0 +1,446 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// maxPickListOrders bounds the orders batched on one pick list
+const maxPickListOrders = 200
+
+// pickableOrderStatuses are the order statuses that can be picked
+var pickableOrderStatuses = map[string]bool{
+	"SUBMITTED":  true,
+	"PROCESSING": true,
+	"CONFIRMED":  true,
+}
+
+// BinLocationService manages the storage bins of warehouses, the SKUs assigned to them,
+// and builds pick lists that walk the bins along a single path.
+type BinLocationService interface {
+	// CreateBin adds a bin location to a warehouse.
+	CreateBin(ctx context.Context, warehouseID string, cmd *BinLocationCommand) (*BinLocationDTO, error)
+
+	// UpdateBin changes the address, capacity or active flag of a bin. The capacity
+	// cannot drop below the units assigned to the bin.
+	UpdateBin(ctx context.Context, binID int64, cmd *BinLocationCommand) (*BinLocationDTO, error)
+
+	// GetBin retrieves a bin location with the SKUs assigned to it.
+	GetBin(ctx context.Context, binID int64) (*BinLocationDTO, error)
+
+	// ListBins lists the bin locations of a warehouse, optionally of one zone.
+	ListBins(ctx context.Context, warehouseID, zone string) ([]*BinLocationDTO, error)
+
+	// DeleteBin removes an empty bin location.
+	DeleteBin(ctx context.Context, binID int64) error
+
+	// AssignSKU assigns a SKU to a bin, or updates its assignment, within the bin's capacity.
+	AssignSKU(ctx context.Context, binID int64, cmd *AssignBinCommand) (*BinLocationDTO, error)
+
+	// UnassignSKU removes a SKU from a bin.
+	UnassignSKU(ctx context.Context, binID int64, skuID string) error
+
+	// GeneratePickList builds the pick list of a batch of orders in a warehouse, its
+	// stops sorted along the pick path.
+	GeneratePickList(ctx context.Context, warehouseID string, cmd *GeneratePickListCommand) (*PickListDTO, error)
+}
+
+// BinLocationDTO represents a bin location
+type BinLocationDTO struct {
+	ID          int64               `json:"id"`
+	WarehouseID string              `json:"warehouse_id"`
+	Code        string              `json:"code"`
+	Zone        string              `json:"zone,omitempty"`
+	Aisle       int                 `json:"aisle"`
+	Bay         int                 `json:"bay"`
+	Level       int                 `json:"level"`
+	Capacity    int                 `json:"capacity"`         // 0 is unlimited
+	Stored      *int                `json:"stored,omitempty"` // Units assigned; only with the assignments
+	Active      bool                `json:"active"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Assignments []*BinAssignmentDTO `json:"assignments,omitempty"`
+}
+
+// BinAssignmentDTO represents a SKU assigned to a bin
+type BinAssignmentDTO struct {
+	SKUID     string    `json:"sku_id"`
+	Quantity  int       `json:"quantity"`
+	Primary   bool      `json:"primary"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PickListDTO represents the pick list of a batch of orders
+type PickListDTO struct {
+	WarehouseID   string         `json:"warehouse_id"`
+	OrderIDs      []int64        `json:"order_ids"`                // Orders on the list
+	SkippedOrders []*SkippedPick `json:"skipped_orders,omitempty"` // Orders left off the list, with why
+	TotalUnits    int            `json:"total_units"`
+	Stops         []*PickStopDTO `json:"stops"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+}
+
+// SkippedPick is an order left off a pick list
+type SkippedPick struct {
+	OrderID int64  `json:"order_id"`
+	Reason  string `json:"reason"`
+}
+
+// PickStopDTO represents a stop on a pick list
+type PickStopDTO struct {
+	Sequence int             `json:"sequence"`
+	BinID    *int64          `json:"bin_id,omitempty"` // Unset when the SKU has no bin in the warehouse
+	BinCode  string          `json:"bin_code,omitempty"`
+	Zone     string          `json:"zone,omitempty"`
+	Aisle    int             `json:"aisle"`
+	Bay      int             `json:"bay"`
+	Level    int             `json:"level"`
+	SKUID    string          `json:"sku_id"`
+	SKUName  string          `json:"sku_name,omitempty"`
+	Quantity int             `json:"quantity"`
+	Orders   []*PickOrderDTO `json:"orders"`
+}
+
+// PickOrderDTO represents the units of a pick stop that go to an order
+type PickOrderDTO struct {
+	OrderID     int64  `json:"order_id"`
+	OrderNumber string `json:"order_number,omitempty"`
+	Quantity    int    `json:"quantity"`
+}
+
+// BinLocationCommand is a command to create or update a bin location
+type BinLocationCommand struct {
+	Code     string `json:"code"`
+	Zone     string `json:"zone"`
+	Aisle    int    `json:"aisle"`
+	Bay      int    `json:"bay"`
+	Level    int    `json:"level"`
+	Capacity int    `json:"capacity"`
+	Active   *bool  `json:"active"` // Left unchanged when unset
+}
+
+// AssignBinCommand is a command to assign a SKU to a bin
+type AssignBinCommand struct {
+	SKUID    string `json:"sku_id"`
+	Quantity int    `json:"quantity"`
+	Primary  bool   `json:"primary"`
+}
+
+// GeneratePickListCommand is a command to build a pick list
+type GeneratePickListCommand struct {
+	OrderIDs []int64 `json:"order_ids"`
+}
+
+type binLocationService struct {
+	repo          domain.BinLocationRepository
+	inventoryRepo domain.InventoryRepository
+	log           *logger.Logger
+}
+
+// NewBinLocationService creates a new instance of BinLocationService
+func NewBinLocationService(repo domain.BinLocationRepository, inventoryRepo domain.InventoryRepository, log *logger.Logger) BinLocationService {
+	return &binLocationService{
+		repo:          repo,
+		inventoryRepo: inventoryRepo,
+		log:           log,
+	}
+}
+
+func (s *binLocationService) CreateBin(ctx context.Context, warehouseID string, cmd *BinLocationCommand) (*BinLocationDTO, error) {
+	bin, err := domain.NewBinLocation(warehouseID, cmd.Code, cmd.Zone, cmd.Aisle, cmd.Bay, cmd.Level, cmd.Capacity)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if cmd.Active != nil {
+		bin.Active = *cmd.Active
+	}
+	if err := s.checkCodeFree(ctx, bin); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, bin); err != nil {
+		return nil, err
+	}
+	return toBinLocationDTO(bin), nil
+}
+
+func (s *binLocationService) UpdateBin(ctx context.Context, binID int64, cmd *BinLocationCommand) (*BinLocationDTO, error) {
+	bin, err := s.repo.FindByID(ctx, binID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bin.Update(cmd.Code, cmd.Zone, cmd.Aisle, cmd.Bay, cmd.Level, cmd.Capacity); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if cmd.Active != nil {
+		bin.Active = *cmd.Active
+	}
+	if err := s.checkCodeFree(ctx, bin); err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.repo.FindAssignments(ctx, bin.ID)
+	if err != nil {
+		return nil, err
+	}
+	if stored := storedUnits(assignments); !bin.Fits(stored) {
+		return nil, errors.Conflict(fmt.Sprintf("bin %s holds %d units, more than a capacity of %d", bin.Code, stored, bin.Capacity))
+	}
+
+	if err := s.repo.Save(ctx, bin); err != nil {
+		return nil, err
+	}
+	return toBinLocationDTOWithAssignments(bin, assignments), nil
+}
+
+func (s *binLocationService) GetBin(ctx context.Context, binID int64) (*BinLocationDTO, error) {
+	bin, err := s.repo.FindByID(ctx, binID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.repo.FindAssignments(ctx, bin.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toBinLocationDTOWithAssignments(bin, assignments), nil
+}
+
+func (s *binLocationService) ListBins(ctx context.Context, warehouseID, zone string) ([]*BinLocationDTO, error) {
+	if warehouseID == "" {
+		return nil, errors.ValidationError("warehouse_id is required")
+	}
+	bins, err := s.repo.FindByWarehouse(ctx, warehouseID, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*BinLocationDTO, len(bins))
+	for i, bin := range bins {
+		dtos[i] = toBinLocationDTO(bin)
+	}
+	return dtos, nil
+}
+
+func (s *binLocationService) DeleteBin(ctx context.Context, binID int64) error {
+	bin, err := s.repo.FindByID(ctx, binID)
+	if err != nil {
+		return err
+	}
+	assignments, err := s.repo.FindAssignments(ctx, bin.ID)
+	if err != nil {
+		return err
+	}
+	if len(assignments) > 0 {
+		return errors.Conflict(fmt.Sprintf("bin %s still has %d SKUs assigned", bin.Code, len(assignments)))
+	}
+	return s.repo.Delete(ctx, bin.ID)
+}
+
+func (s *binLocationService) AssignSKU(ctx context.Context, binID int64, cmd *AssignBinCommand) (*BinLocationDTO, error) {
+	bin, err := s.repo.FindByID(ctx, binID)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := domain.NewBinAssignment(bin.ID, cmd.SKUID, cmd.Quantity, cmd.Primary)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	missing, err := s.inventoryRepo.FindMissingSKUIDs(ctx, []string{assignment.SKUID})
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, errors.NotFound("SKU " + assignment.SKUID)
+	}
+
+	assignments, err := s.repo.FindAssignments(ctx, bin.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The units already assigned to the SKU are replaced, not added to
+	stored := assignment.Quantity
+	for _, existing := range assignments {
+		if existing.SKUID != assignment.SKUID {
+			stored += existing.Quantity
+		}
+	}
+	if !bin.Fits(stored) {
+		return nil, errors.Conflict(fmt.Sprintf("bin %s has a capacity of %d units, the assignment would store %d", bin.Code, bin.Capacity, stored))
+	}
+
+	if err := s.repo.SaveAssignment(ctx, bin.WarehouseID, assignment); err != nil {
+		return nil, err
+	}
+	s.log.WithField("bin_id", bin.ID).WithField("sku_id", assignment.SKUID).Info("SKU assigned to bin")
+
+	return s.GetBin(ctx, bin.ID)
+}
+
+func (s *binLocationService) UnassignSKU(ctx context.Context, binID int64, skuID string) error {
+	if skuID == "" {
+		return errors.ValidationError("sku_id is required")
+	}
+	return s.repo.DeleteAssignment(ctx, binID, skuID)
+}
+
+func (s *binLocationService) GeneratePickList(ctx context.Context, warehouseID string, cmd *GeneratePickListCommand) (*PickListDTO, error) {
+	if warehouseID == "" {
+		return nil, errors.ValidationError("warehouse_id is required")
+	}
+	orderIDs := uniqueOrderIDs(cmd.OrderIDs)
+	if len(orderIDs) == 0 {
+		return nil, errors.ValidationError("order_ids is required")
+	}
+	if len(orderIDs) > maxPickListOrders {
+		return nil, errors.ValidationError(fmt.Sprintf("a pick list holds at most %d orders", maxPickListOrders))
+	}
+
+	lines, err := s.repo.FindPickLines(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	pickList := &PickListDTO{
+		WarehouseID: warehouseID,
+		OrderIDs:    make([]int64, 0, len(orderIDs)),
+		GeneratedAt: time.Now(),
+	}
+	statuses := make(map[int64]string, len(orderIDs))
+	for _, line := range lines {
+		statuses[line.OrderID] = line.OrderStatus
+	}
+	for _, orderID := range orderIDs {
+		status, found := statuses[orderID]
+		switch {
+		case !found:
+			pickList.SkippedOrders = append(pickList.SkippedOrders, &SkippedPick{OrderID: orderID, Reason: "order not found or has no items"})
+		case !pickableOrderStatuses[status]:
+			pickList.SkippedOrders = append(pickList.SkippedOrders, &SkippedPick{OrderID: orderID, Reason: "order is " + status})
+		default:
+			pickList.OrderIDs = append(pickList.OrderIDs, orderID)
+		}
+	}
+
+	pickLines := make([]*domain.PickLine, 0, len(lines))
+	skuIDs := make([]string, 0, len(lines))
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		if !pickableOrderStatuses[line.OrderStatus] {
+			continue
+		}
+		pickLines = append(pickLines, line)
+		if !seen[line.SKUID] {
+			seen[line.SKUID] = true
+			skuIDs = append(skuIDs, line.SKUID)
+		}
+	}
+
+	locations := make(map[string][]*domain.SKULocation)
+	if len(skuIDs) > 0 {
+		locations, err = s.repo.FindLocations(ctx, warehouseID, skuIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stops := domain.PlanPickPath(pickLines, locations)
+	pickList.Stops = make([]*PickStopDTO, len(stops))
+	for i, stop := range stops {
+		pickList.Stops[i] = toPickStopDTO(stop)
+		pickList.TotalUnits += stop.Quantity
+	}
+	return pickList, nil
+}
+
+// checkCodeFree fails when another bin of the warehouse has the code of bin
+func (s *binLocationService) checkCodeFree(ctx context.Context, bin *domain.BinLocation) error {
+	existing, err := s.repo.FindByCode(ctx, bin.WarehouseID, bin.Code)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != bin.ID {
+		return errors.Conflict(fmt.Sprintf("warehouse %s already has a bin %s", bin.WarehouseID, bin.Code))
+	}
+	return nil
+}
+
+func storedUnits(assignments []*domain.BinAssignment) int {
+	stored := 0
+	for _, assignment := range assignments {
+		stored += assignment.Quantity
+	}
+	return stored
+}
+
+func uniqueOrderIDs(orderIDs []int64) []int64 {
+	unique := make([]int64, 0, len(orderIDs))
+	seen := make(map[int64]bool, len(orderIDs))
+	for _, id := range orderIDs {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func toBinLocationDTO(bin *domain.BinLocation) *BinLocationDTO {
+	return &BinLocationDTO{
+		ID:          bin.ID,
+		WarehouseID: bin.WarehouseID,
+		Code:        bin.Code,
+		Zone:        bin.Zone,
+		Aisle:       bin.Aisle,
+		Bay:         bin.Bay,
+		Level:       bin.Level,
+		Capacity:    bin.Capacity,
+		Active:      bin.Active,
+		CreatedAt:   bin.CreatedAt,
+		UpdatedAt:   bin.UpdatedAt,
+	}
+}
+
+func toBinLocationDTOWithAssignments(bin *domain.BinLocation, assignments []*domain.BinAssignment) *BinLocationDTO {
+	dto := toBinLocationDTO(bin)
+	stored := storedUnits(assignments)
+	dto.Stored = &stored
+	dto.Assignments = make([]*BinAssignmentDTO, len(assignments))
+	for i, assignment := range assignments {
+		dto.Assignments[i] = &BinAssignmentDTO{
+			SKUID:     assignment.SKUID,
+			Quantity:  assignment.Quantity,
+			Primary:   assignment.Primary,
+			UpdatedAt: assignment.UpdatedAt,
+		}
+	}
+	return dto
+}
+
+func toPickStopDTO(stop *domain.PickStop) *PickStopDTO {
+	dto := &PickStopDTO{
+		Sequence: stop.Sequence,
+		SKUID:    stop.SKUID,
+		SKUName:  stop.SKUName,
+		Quantity: stop.Quantity,
+		Orders:   make([]*PickOrderDTO, len(stop.Orders)),
+	}
+	if stop.Bin != nil {
+		binID := stop.Bin.ID
+		dto.BinID = &binID
+		dto.BinCode = stop.Bin.Code
+		dto.Zone = stop.Bin.Zone
+		dto.Aisle = stop.Bin.Aisle
+		dto.Bay = stop.Bin.Bay
+		dto.Level = stop.Bin.Level
+	}
+	for i, order := range stop.Orders {
+		dto.Orders[i] = &PickOrderDTO{OrderID: order.OrderID, OrderNumber: order.OrderNumber, Quantity: order.Quantity}
+	}
+	return dto
+}
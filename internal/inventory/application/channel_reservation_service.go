@@ -0,0 +1,299 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// expiredReservationBatch bounds the expired reservations released per sweep
+const expiredReservationBatch = 500
+
+// SalesChannel is an external sales channel, such as a marketplace
+// integration, allowed to reserve stock
+type SalesChannel struct {
+	Code           string
+	Allocation     domain.ChannelAllocation // Cap applied to every SKU
+	SKUCaps        map[string]int           // SKU ID -> absolute cap overriding Allocation.MaxQuantity; 0 offers none
+	ReservationTTL time.Duration            // Expiry of reservations that do not ask for one
+	MaxTTL         time.Duration            // Longest expiry a reservation may ask for
+}
+
+// allocationFor returns the allocation of a SKU
+func (c *SalesChannel) allocationFor(skuID string) domain.ChannelAllocation {
+	allocation := c.Allocation
+	if limit, ok := c.SKUCaps[skuID]; ok {
+		allocation.MaxQuantity = limit
+		if limit == 0 {
+			allocation.Percent = 0 // The SKU is not offered on the channel
+		}
+	}
+	return allocation
+}
+
+// ttl returns the expiry asked for in seconds, or the channel's default
+func (c *SalesChannel) ttl(seconds int) (time.Duration, error) {
+	if seconds < 0 {
+		return 0, errors.ValidationError("ttl_seconds cannot be negative")
+	}
+	if seconds == 0 {
+		return c.ReservationTTL, nil
+	}
+	ttl := time.Duration(seconds) * time.Second
+	if c.MaxTTL > 0 && ttl > c.MaxTTL {
+		return 0, errors.ValidationError(fmt.Sprintf("ttl_seconds cannot exceed %d", int(c.MaxTTL.Seconds())))
+	}
+	return ttl, nil
+}
+
+// ChannelReservationService lets external sales channels hold stock for their
+// orders. Reservations count as reserved inventory and each channel may only
+// hold its allocation of a SKU, so marketplace orders never oversell the website.
+type ChannelReservationService interface {
+	// PlaceReservation reserves stock for a channel order. Placing a reservation again with
+	// the same reference returns the existing one, so integrations can safely retry.
+	PlaceReservation(ctx context.Context, channel string, cmd *PlaceChannelReservationCommand) (*ChannelReservationDTO, bool, error)
+
+	// ExtendReservation pushes back the expiry of an active reservation.
+	ExtendReservation(ctx context.Context, channel, id string, cmd *ExtendChannelReservationCommand) (*ChannelReservationDTO, error)
+
+	// ReleaseReservation returns the stock of a reservation; releasing it again is a no-op.
+	ReleaseReservation(ctx context.Context, channel, id string) (*ChannelReservationDTO, error)
+
+	// GetReservation retrieves a reservation of a channel.
+	GetReservation(ctx context.Context, channel, id string) (*ChannelReservationDTO, error)
+
+	// ReleaseExpired releases the reservations whose expiry has passed.
+	ReleaseExpired(ctx context.Context) (int, error)
+
+	// StartExpirySweep releases expired reservations periodically until ctx is cancelled.
+	StartExpirySweep(ctx context.Context, interval time.Duration)
+}
+
+// ChannelReservationDTO represents a channel reservation data transfer object
+type ChannelReservationDTO struct {
+	ID         string     `json:"id"`
+	Channel    string     `json:"channel"`
+	Reference  string     `json:"reference"`
+	SKUID      string     `json:"sku_id"`
+	Quantity   int        `json:"quantity"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// PlaceChannelReservationCommand is a command to reserve stock for a channel order
+type PlaceChannelReservationCommand struct {
+	Reference  string `json:"reference"` // The channel's order or line reference
+	SKUID      string `json:"sku_id"`
+	Quantity   int    `json:"quantity"`
+	TTLSeconds int    `json:"ttl_seconds"` // 0 uses the channel's reservation TTL
+}
+
+// ExtendChannelReservationCommand is a command to extend a reservation
+type ExtendChannelReservationCommand struct {
+	TTLSeconds int `json:"ttl_seconds"` // New expiry from now; 0 uses the channel's reservation TTL
+}
+
+type channelReservationService struct {
+	repo        domain.ChannelReservationRepository
+	channels    map[string]*SalesChannel
+	stockEvents stockEvents
+	log         *logger.Logger
+}
+
+// NewChannelReservationService creates a new instance of ChannelReservationService.
+// eventBus may be nil, publishing no stock changes.
+func NewChannelReservationService(repo domain.ChannelReservationRepository, channels []*SalesChannel, eventBus event.Bus, log *logger.Logger) ChannelReservationService {
+	s := &channelReservationService{
+		repo:        repo,
+		channels:    make(map[string]*SalesChannel, len(channels)),
+		stockEvents: stockEvents{eventBus: eventBus},
+		log:         log,
+	}
+	for _, channel := range channels {
+		s.channels[channel.Code] = channel
+	}
+	return s
+}
+
+func (s *channelReservationService) PlaceReservation(ctx context.Context, channelCode string, cmd *PlaceChannelReservationCommand) (*ChannelReservationDTO, bool, error) {
+	channel, err := s.channel(channelCode)
+	if err != nil {
+		return nil, false, err
+	}
+	ttl, err := channel.ttl(cmd.TTLSeconds)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := s.repo.FindByReference(ctx, channel.Code, cmd.Reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		if existing.SKUID != cmd.SKUID || existing.Quantity != cmd.Quantity {
+			return nil, false, errors.Conflict(fmt.Sprintf("reservation %s was placed for a different SKU or quantity", cmd.Reference))
+		}
+		return toChannelReservationDTO(existing), false, nil
+	}
+
+	reservation, err := domain.NewChannelReservation(channel.Code, cmd.Reference, cmd.SKUID, cmd.Quantity, ttl)
+	if err != nil {
+		return nil, false, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.Reserve(ctx, reservation, channel.allocationFor(cmd.SKUID)); err != nil {
+		return nil, false, err
+	}
+	s.stockEvents.publish(ctx, domain.StockChangeReserved, reservation.SKUID)
+
+	s.log.WithFields(logger.Fields{
+		"channel":        reservation.Channel,
+		"reference":      reservation.Reference,
+		"sku_id":         reservation.SKUID,
+		"quantity":       reservation.Quantity,
+		"reservation_id": reservation.ID,
+	}).Info("Channel reservation placed")
+	return toChannelReservationDTO(reservation), true, nil
+}
+
+func (s *channelReservationService) ExtendReservation(ctx context.Context, channelCode, id string, cmd *ExtendChannelReservationCommand) (*ChannelReservationDTO, error) {
+	channel, err := s.channel(channelCode)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := channel.ttl(cmd.TTLSeconds)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := s.find(ctx, channel.Code, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reservation.Extend(ttl); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.repo.UpdateExpiry(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return toChannelReservationDTO(reservation), nil
+}
+
+func (s *channelReservationService) ReleaseReservation(ctx context.Context, channelCode, id string) (*ChannelReservationDTO, error) {
+	channel, err := s.channel(channelCode)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := s.find(ctx, channel.Code, id)
+	if err != nil {
+		return nil, err
+	}
+
+	released, err := s.repo.Release(ctx, reservation.ID, domain.ChannelReservationReleased)
+	if err != nil {
+		return nil, err
+	}
+	if released {
+		s.stockEvents.publish(ctx, domain.StockChangeReserved, reservation.SKUID)
+		s.log.WithFields(logger.Fields{"channel": reservation.Channel, "reservation_id": reservation.ID}).Info("Channel reservation released")
+	}
+	// Read it back for the final status, which may be EXPIRED if the sweep got there first
+	return s.GetReservation(ctx, channel.Code, reservation.ID)
+}
+
+func (s *channelReservationService) GetReservation(ctx context.Context, channelCode, id string) (*ChannelReservationDTO, error) {
+	reservation, err := s.find(ctx, channelCode, id)
+	if err != nil {
+		return nil, err
+	}
+	return toChannelReservationDTO(reservation), nil
+}
+
+func (s *channelReservationService) ReleaseExpired(ctx context.Context) (int, error) {
+	expired, err := s.repo.FindExpired(ctx, time.Now(), expiredReservationBatch)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	skuIDs := make([]string, 0, len(expired))
+	defer func() { s.stockEvents.publish(ctx, domain.StockChangeReserved, skuIDs...) }()
+	for _, reservation := range expired {
+		released, err := s.repo.Release(ctx, reservation.ID, domain.ChannelReservationExpired)
+		if err != nil {
+			return count, err
+		}
+		if released {
+			count++
+			skuIDs = append(skuIDs, reservation.SKUID)
+		}
+	}
+	return count, nil
+}
+
+func (s *channelReservationService) StartExpirySweep(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := s.ReleaseExpired(ctx)
+				if err != nil {
+					s.log.WithError(err).Warn("Channel reservation expiry sweep failed")
+				}
+				if count > 0 {
+					s.log.WithField("count", count).Info("Expired channel reservations released")
+				}
+			}
+		}
+	}()
+}
+
+// channel returns a configured channel
+func (s *channelReservationService) channel(code string) (*SalesChannel, error) {
+	channel, ok := s.channels[code]
+	if !ok {
+		return nil, errors.Forbidden(fmt.Sprintf("unknown sales channel %s", code))
+	}
+	return channel, nil
+}
+
+// find retrieves a reservation of a channel; reservations of other channels are not found
+func (s *channelReservationService) find(ctx context.Context, channel, id string) (*domain.ChannelReservation, error) {
+	reservation, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Channel != channel {
+		return nil, errors.NotFound("channel reservation")
+	}
+	return reservation, nil
+}
+
+func toChannelReservationDTO(reservation *domain.ChannelReservation) *ChannelReservationDTO {
+	return &ChannelReservationDTO{
+		ID:         reservation.ID,
+		Channel:    reservation.Channel,
+		Reference:  reservation.Reference,
+		SKUID:      reservation.SKUID,
+		Quantity:   reservation.Quantity,
+		Status:     string(reservation.Status),
+		ExpiresAt:  reservation.ExpiresAt,
+		ReleasedAt: reservation.ReleasedAt,
+		CreatedAt:  reservation.CreatedAt,
+		UpdatedAt:  reservation.UpdatedAt,
+	}
+}
@@ -0,0 +1,367 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// flashReconciliationHistory bounds the reconciliations listed with a flash allocation
+const flashReconciliationHistory = 50
+
+// FlashAllocationService serves reservations of SKUs in a flash sale from atomic token
+// counters in front of the inventory levels. Reservations are first come, first served
+// and fail fast once the counter is empty instead of queueing on row locks. The counters
+// are reconciled with the inventory levels periodically: the reserved quantities are
+// applied to the levels and recorded in a ledger, and the counters are corrected for
+// stock changed outside them.
+type FlashAllocationService interface {
+	// Reserve takes quantity tokens of a SKU in flash allocation mode. handled is false
+	// when the SKU is not in the mode, or the counters cannot be reached, and the caller
+	// must reserve from the inventory levels itself. Fails with insufficient stock when
+	// the counter runs short.
+	Reserve(ctx context.Context, skuID string, quantity int) (handled bool, err error)
+
+	// Release gives back tokens taken by Reserve. handled is false when the SKU left
+	// flash allocation mode, or the counters cannot be reached, and the caller must
+	// release from the inventory levels.
+	Release(ctx context.Context, skuID string, quantity int) (handled bool)
+
+	// Start puts a SKU in flash allocation mode, seeding its counter with the stock available.
+	Start(ctx context.Context, cmd *StartFlashAllocationCommand) (*FlashAllocationDTO, error)
+
+	// Stop takes a SKU out of flash allocation mode after a final reconciliation.
+	Stop(ctx context.Context, skuID string) (*FlashAllocationDTO, error)
+
+	// GetAllocation retrieves the flash allocation of a SKU with its latest reconciliations.
+	GetAllocation(ctx context.Context, skuID string) (*FlashAllocationDTO, error)
+
+	// ListAllocations lists the flash allocations; activeOnly leaves out stopped ones.
+	ListAllocations(ctx context.Context, activeOnly bool) ([]*FlashAllocationDTO, error)
+
+	// Reconcile applies the reservations of one SKU to its inventory levels now.
+	Reconcile(ctx context.Context, skuID string) (*FlashAllocationDTO, error)
+
+	// ReconcileAll reconciles every active flash allocation and returns how many succeeded.
+	ReconcileAll(ctx context.Context) (int, error)
+
+	// StartReconciler reconciles the active flash allocations periodically until ctx is cancelled.
+	StartReconciler(ctx context.Context, interval time.Duration)
+}
+
+// FlashAllocationDTO represents a SKU in flash allocation mode
+type FlashAllocationDTO struct {
+	SKUID            string                    `json:"sku_id"`
+	Status           string                    `json:"status"`
+	SeededQuantity   int                       `json:"seeded_quantity"`
+	ReservedQuantity int                       `json:"reserved_quantity"` // Applied to the inventory levels so far
+	Tokens           *int                      `json:"tokens,omitempty"`  // Left in the counter; unknown when it cannot be read
+	StartedBy        string                    `json:"started_by,omitempty"`
+	StartedAt        time.Time                 `json:"started_at"`
+	StoppedAt        *time.Time                `json:"stopped_at,omitempty"`
+	LastReconciledAt *time.Time                `json:"last_reconciled_at,omitempty"`
+	Reconciliations  []*FlashReconciliationDTO `json:"reconciliations,omitempty"`
+}
+
+// FlashReconciliationDTO represents a reconciliation of a flash allocation
+type FlashReconciliationDTO struct {
+	ID             int64     `json:"id"`
+	ReservedDelta  int       `json:"reserved_delta"`
+	TokensBefore   int       `json:"tokens_before"`
+	AvailableAfter int       `json:"available_after"`
+	Drift          int       `json:"drift"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// StartFlashAllocationCommand is a command to put a SKU in flash allocation mode
+type StartFlashAllocationCommand struct {
+	SKUID     string `json:"sku_id"`
+	StartedBy string `json:"started_by"` // Defaults to the authenticated admin
+}
+
+type flashAllocationService struct {
+	repo        domain.FlashAllocationRepository
+	tokens      domain.FlashTokenStore
+	stockEvents stockEvents
+	log         *logger.Logger
+}
+
+// NewFlashAllocationService creates a new instance of FlashAllocationService. tokens may be
+// nil when no Redis is configured, in which case no SKU can enter flash allocation mode.
+// eventBus may be nil, publishing no stock changes when reservations are reconciled.
+func NewFlashAllocationService(repo domain.FlashAllocationRepository, tokens domain.FlashTokenStore, eventBus event.Bus, log *logger.Logger) FlashAllocationService {
+	return &flashAllocationService{
+		repo:        repo,
+		tokens:      tokens,
+		stockEvents: stockEvents{eventBus: eventBus},
+		log:         log,
+	}
+}
+
+func (s *flashAllocationService) Reserve(ctx context.Context, skuID string, quantity int) (bool, error) {
+	if s.tokens == nil {
+		return false, nil
+	}
+	taken, remaining, active, err := s.tokens.Take(ctx, skuID, quantity)
+	if err != nil {
+		// The levels still hold every reconciled reservation, so they remain a safe fallback
+		s.log.WithError(err).WithField("sku_id", skuID).Warn("Flash token store unavailable, reserving from inventory levels")
+		return false, nil
+	}
+	if !active {
+		return false, nil
+	}
+	if !taken {
+		return true, errors.InsufficientStock(skuID, quantity, remaining)
+	}
+	return true, nil
+}
+
+func (s *flashAllocationService) Release(ctx context.Context, skuID string, quantity int) bool {
+	if s.tokens == nil {
+		return false
+	}
+	returned, err := s.tokens.Return(ctx, skuID, quantity)
+	if err != nil {
+		// Stock released from the levels shows up as drift and is added back to the counter
+		s.log.WithError(err).WithField("sku_id", skuID).Warn("Flash token store unavailable, releasing to inventory levels")
+		return false
+	}
+	return returned
+}
+
+func (s *flashAllocationService) Start(ctx context.Context, cmd *StartFlashAllocationCommand) (*FlashAllocationDTO, error) {
+	if s.tokens == nil {
+		return nil, errors.ServiceUnavailable("flash allocation requires Redis")
+	}
+	if cmd.SKUID == "" {
+		return nil, errors.ValidationError("sku_id is required")
+	}
+	existing, err := s.repo.FindBySKUID(ctx, cmd.SKUID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.IsActive() {
+		return nil, errors.Conflict(fmt.Sprintf("SKU %s is already in flash allocation mode", cmd.SKUID))
+	}
+
+	available, err := s.repo.SumAvailable(ctx, cmd.SKUID)
+	if err != nil {
+		return nil, err
+	}
+	allocation, err := domain.NewFlashAllocation(cmd.SKUID, available, cmd.StartedBy)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.Save(ctx, allocation); err != nil {
+		return nil, err
+	}
+	// Reconciliations correct the counter for reservations made between the sum and the seed
+	if err := s.tokens.Seed(ctx, allocation.SKUID, available); err != nil {
+		if stopErr := allocation.Stop(); stopErr == nil {
+			if saveErr := s.repo.Save(ctx, allocation); saveErr != nil {
+				s.log.WithError(saveErr).WithField("sku_id", allocation.SKUID).Error("Failed to stop unseeded flash allocation")
+			}
+		}
+		return nil, errors.ServiceUnavailable("failed to seed flash allocation").WithInternal(err)
+	}
+
+	s.log.WithFields(logger.Fields{
+		"sku_id":     allocation.SKUID,
+		"tokens":     available,
+		"started_by": allocation.StartedBy,
+	}).Info("Flash allocation started")
+	return s.toDTO(ctx, allocation, nil), nil
+}
+
+func (s *flashAllocationService) Stop(ctx context.Context, skuID string) (*FlashAllocationDTO, error) {
+	allocation, err := s.find(ctx, skuID)
+	if err != nil {
+		return nil, err
+	}
+	if !allocation.IsActive() {
+		return nil, errors.Conflict(fmt.Sprintf("flash allocation of SKU %s is already stopped", skuID))
+	}
+
+	// New reservations go to the levels once the counter is gone; what it took is applied last
+	if s.tokens != nil {
+		if err := s.tokens.Clear(ctx, skuID); err != nil {
+			return nil, errors.ServiceUnavailable("failed to clear flash allocation").WithInternal(err)
+		}
+		if err := s.reconcile(ctx, allocation); err != nil {
+			return nil, err
+		}
+	}
+	if err := allocation.Stop(); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.repo.Save(ctx, allocation); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"sku_id":   skuID,
+		"reserved": allocation.ReservedQuantity,
+	}).Info("Flash allocation stopped")
+	return s.toDTO(ctx, allocation, nil), nil
+}
+
+func (s *flashAllocationService) GetAllocation(ctx context.Context, skuID string) (*FlashAllocationDTO, error) {
+	allocation, err := s.find(ctx, skuID)
+	if err != nil {
+		return nil, err
+	}
+	reconciliations, err := s.repo.FindReconciliations(ctx, skuID, flashReconciliationHistory)
+	if err != nil {
+		return nil, err
+	}
+	return s.toDTO(ctx, allocation, reconciliations), nil
+}
+
+func (s *flashAllocationService) ListAllocations(ctx context.Context, activeOnly bool) ([]*FlashAllocationDTO, error) {
+	allocations, err := s.repo.FindAll(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*FlashAllocationDTO, len(allocations))
+	for i, allocation := range allocations {
+		dtos[i] = s.toDTO(ctx, allocation, nil)
+	}
+	return dtos, nil
+}
+
+func (s *flashAllocationService) Reconcile(ctx context.Context, skuID string) (*FlashAllocationDTO, error) {
+	allocation, err := s.find(ctx, skuID)
+	if err != nil {
+		return nil, err
+	}
+	if !allocation.IsActive() {
+		return nil, errors.Conflict(fmt.Sprintf("flash allocation of SKU %s is stopped", skuID))
+	}
+	if s.tokens == nil {
+		return nil, errors.ServiceUnavailable("flash allocation requires Redis")
+	}
+	if err := s.reconcile(ctx, allocation); err != nil {
+		return nil, err
+	}
+	return s.GetAllocation(ctx, skuID)
+}
+
+func (s *flashAllocationService) ReconcileAll(ctx context.Context) (int, error) {
+	if s.tokens == nil {
+		return 0, nil
+	}
+	allocations, err := s.repo.FindAll(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	reconciled := 0
+	for _, allocation := range allocations {
+		if err := s.reconcile(ctx, allocation); err != nil {
+			s.log.WithError(err).WithField("sku_id", allocation.SKUID).Error("Failed to reconcile flash allocation")
+			continue
+		}
+		reconciled++
+	}
+	return reconciled, nil
+}
+
+func (s *flashAllocationService) StartReconciler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || s.tokens == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.ReconcileAll(ctx); err != nil {
+					s.log.WithError(err).Warn("Flash allocation reconciliation failed")
+				}
+			}
+		}
+	}()
+}
+
+// reconcile drains the reservations taken from the counter of a SKU, applies them to its
+// inventory levels and corrects the counter by the drift found. Reservations taken while
+// this runs stay pending for the next reconciliation, and the correction is relative, so
+// it never loses them.
+func (s *flashAllocationService) reconcile(ctx context.Context, allocation *domain.FlashAllocation) error {
+	pending, tokens, err := s.tokens.Drain(ctx, allocation.SKUID)
+	if err != nil {
+		return err
+	}
+	reconciliation := domain.NewFlashReconciliation(allocation.SKUID, pending, tokens, 0)
+	if err := s.repo.ApplyReconciliation(ctx, allocation, reconciliation); err != nil {
+		if requeueErr := s.tokens.Requeue(ctx, allocation.SKUID, pending); requeueErr != nil {
+			s.log.WithError(requeueErr).WithFields(logger.Fields{
+				"sku_id":  allocation.SKUID,
+				"pending": pending,
+			}).Error("Failed to requeue flash reservations; they must be applied by hand")
+		}
+		return err
+	}
+	if pending != 0 {
+		s.stockEvents.publish(ctx, domain.StockChangeFlashSale, allocation.SKUID)
+	}
+	if drift := reconciliation.Drift(); drift != 0 {
+		if err := s.tokens.Adjust(ctx, allocation.SKUID, drift); err != nil {
+			return err
+		}
+		s.log.WithFields(logger.Fields{
+			"sku_id": allocation.SKUID,
+			"drift":  drift,
+		}).Info("Flash allocation counter corrected")
+	}
+	return nil
+}
+
+func (s *flashAllocationService) find(ctx context.Context, skuID string) (*domain.FlashAllocation, error) {
+	allocation, err := s.repo.FindBySKUID(ctx, skuID)
+	if err != nil {
+		return nil, err
+	}
+	if allocation == nil {
+		return nil, errors.NotFound(fmt.Sprintf("flash allocation of SKU %s", skuID))
+	}
+	return allocation, nil
+}
+
+// toDTO converts a flash allocation, reading the tokens left of active ones
+func (s *flashAllocationService) toDTO(ctx context.Context, allocation *domain.FlashAllocation, reconciliations []*domain.FlashReconciliation) *FlashAllocationDTO {
+	dto := &FlashAllocationDTO{
+		SKUID:            allocation.SKUID,
+		Status:           string(allocation.Status),
+		SeededQuantity:   allocation.SeededQuantity,
+		ReservedQuantity: allocation.ReservedQuantity,
+		StartedBy:        allocation.StartedBy,
+		StartedAt:        allocation.StartedAt,
+		StoppedAt:        allocation.StoppedAt,
+		LastReconciledAt: allocation.LastReconciledAt,
+	}
+	if allocation.IsActive() && s.tokens != nil {
+		if tokens, active, err := s.tokens.Tokens(ctx, allocation.SKUID); err == nil && active {
+			dto.Tokens = &tokens
+		}
+	}
+	for _, rec := range reconciliations {
+		dto.Reconciliations = append(dto.Reconciliations, &FlashReconciliationDTO{
+			ID:             rec.ID,
+			ReservedDelta:  rec.ReservedDelta,
+			TokensBefore:   rec.TokensBefore,
+			AvailableAfter: rec.AvailableAfter,
+			Drift:          rec.Drift(),
+			CreatedAt:      rec.CreatedAt,
+		})
+	}
+	return dto
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/export"
+)
+
+// NewInventoryExport describes a CSV export of current inventory levels, of one warehouse or
+// of all when warehouseID is empty. Its sku_id, warehouse_id and quantity columns can be
+// edited and fed back to the inventory import.
+func NewInventoryExport(repo domain.InventoryExportRepository, warehouseID string) export.Export {
+	return export.Export{
+		Kind:     "inventory",
+		Filename: "inventory-" + time.Now().Format("20060102-150405") + ".csv",
+		Header: []string{
+			"sku_id", "warehouse_id", "quantity", "reserved", "allocated",
+			"available", "in_transit", "damaged", "last_count_date", "updated_at",
+		},
+		Rows: func(ctx context.Context, w export.RowWriter) error {
+			return repo.StreamLevels(ctx, warehouseID, func(row *domain.InventoryExportRow) error {
+				lastCount := ""
+				if row.LastCountDate != nil {
+					lastCount = row.LastCountDate.Format(time.RFC3339)
+				}
+				return w.Write([]string{
+					row.SKUID,
+					row.WarehouseID,
+					strconv.Itoa(row.QuantityOnHand),
+					strconv.Itoa(row.QuantityReserved),
+					strconv.Itoa(row.QuantityAllocated),
+					strconv.Itoa(row.QuantityAvailable),
+					strconv.Itoa(row.QuantityInTransit),
+					strconv.Itoa(row.QuantityDamaged),
+					lastCount,
+					row.UpdatedAt.Format(time.RFC3339),
+				})
+			})
+		},
+	}
+}
+
+// NewLowStockReport is the low stock report admins can subscribe to. Stock is a
+// snapshot, so the period of a delivery is ignored. It takes the warehouse_id
+// parameter of /admin/exports/inventory/low-stock.
+func NewLowStockReport(repo domain.InventoryExportRepository) export.Report {
+	return export.Report{
+		Name:   "low_stock",
+		Title:  "Low stock",
+		Params: []string{"warehouse_id"},
+		Build: func(from, to time.Time, params map[string]string) (export.Export, error) {
+			return NewLowStockExport(repo, params["warehouse_id"]), nil
+		},
+	}
+}
+
+// NewLowStockExport describes a CSV export of the inventory levels at or below their
+// reorder point, of one warehouse or of all when warehouseID is empty
+func NewLowStockExport(repo domain.InventoryExportRepository, warehouseID string) export.Export {
+	return export.Export{
+		Kind:     "low-stock",
+		Filename: "low-stock-" + time.Now().Format("20060102-150405") + ".csv",
+		Header: []string{
+			"sku_id", "warehouse_id", "quantity", "in_transit", "reserved", "available",
+			"reorder_point", "reorder_qty", "updated_at",
+		},
+		Rows: func(ctx context.Context, w export.RowWriter) error {
+			return repo.StreamLowStock(ctx, warehouseID, func(row *domain.InventoryExportRow) error {
+				return w.Write([]string{
+					row.SKUID,
+					row.WarehouseID,
+					strconv.Itoa(row.QuantityOnHand),
+					strconv.Itoa(row.QuantityInTransit),
+					strconv.Itoa(row.QuantityReserved),
+					strconv.Itoa(row.QuantityAvailable),
+					strconv.Itoa(row.ReorderPoint),
+					strconv.Itoa(row.ReorderQuantity),
+					row.UpdatedAt.Format(time.RFC3339),
+				})
+			})
+		},
+	}
+}
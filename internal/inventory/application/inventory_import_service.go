@@ -0,0 +1,356 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// maxInventoryImportRows bounds the data rows of one import file
+const maxInventoryImportRows = 50000
+
+// Columns an inventory import file must have; others are ignored
+var inventoryImportColumns = []string{"sku_id", "warehouse_id", "quantity"}
+
+// InventoryImportService sets quantities on hand in bulk from CSV files, e.g. to onboard
+// a warehouse or reconcile with a WMS count. Every change is recorded in the adjustment ledger.
+type InventoryImportService interface {
+	// Import validates every row of a CSV file of sku_id, warehouse_id and quantity and, when
+	// all rows are valid and dryRun is false, sets each quantity on hand. Invalid rows are
+	// reported on the result and nothing is applied.
+	Import(ctx context.Context, file io.Reader, dryRun bool, actorID string) (*InventoryImportResultDTO, error)
+
+	// ListAdjustments lists the ledger entries of an import reference, or the latest of a SKU.
+	ListAdjustments(ctx context.Context, reference, skuID string, limit int) ([]*InventoryAdjustmentDTO, error)
+}
+
+// InventoryImportResultDTO summarizes an inventory import
+type InventoryImportResultDTO struct {
+	Reference   string                     `json:"reference,omitempty"`
+	DryRun      bool                       `json:"dry_run"`
+	Applied     bool                       `json:"applied"`
+	Rows        int                        `json:"rows"`
+	Created     int                        `json:"created"`
+	Updated     int                        `json:"updated"`
+	Unchanged   int                        `json:"unchanged"`
+	Errors      []*InventoryImportErrorDTO `json:"errors"`
+	Adjustments []*InventoryAdjustmentDTO  `json:"adjustments"`
+}
+
+// InventoryImportErrorDTO reports an invalid row of an import file
+type InventoryImportErrorDTO struct {
+	Line    int    `json:"line"`
+	SKUID   string `json:"sku_id,omitempty"`
+	Message string `json:"message"`
+}
+
+// InventoryAdjustmentDTO represents an inventory adjustment ledger entry
+type InventoryAdjustmentDTO struct {
+	ID             string    `json:"id,omitempty"`
+	InventoryID    string    `json:"inventory_id"`
+	SKUID          string    `json:"sku_id"`
+	WarehouseID    *string   `json:"warehouse_id,omitempty"`
+	Stock          string    `json:"stock"`
+	QuantityBefore int       `json:"quantity_before"`
+	QuantityAfter  int       `json:"quantity_after"`
+	Delta          int       `json:"delta"`
+	Reason         string    `json:"reason"`
+	Reference      string    `json:"reference"`
+	ActorID        string    `json:"actor_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ToInventoryAdjustmentDTO converts a domain InventoryAdjustment to an InventoryAdjustmentDTO
+func ToInventoryAdjustmentDTO(a *domain.InventoryAdjustment) *InventoryAdjustmentDTO {
+	return &InventoryAdjustmentDTO{
+		ID:             a.ID,
+		InventoryID:    a.InventoryID,
+		SKUID:          a.SKUID,
+		WarehouseID:    a.WarehouseID,
+		Stock:          a.Stock,
+		QuantityBefore: a.QuantityBefore,
+		QuantityAfter:  a.QuantityAfter,
+		Delta:          a.Delta(),
+		Reason:         a.Reason,
+		Reference:      a.Reference,
+		ActorID:        a.ActorID,
+		CreatedAt:      a.CreatedAt,
+	}
+}
+
+// inventoryImportRow is a parsed row of an import file
+type inventoryImportRow struct {
+	line        int
+	skuID       string
+	warehouseID string
+	quantity    int
+}
+
+type inventoryImportService struct {
+	inventoryRepo  domain.InventoryRepository
+	adjustmentRepo domain.InventoryAdjustmentRepository
+	stockEvents    stockEvents
+	log            *logger.Logger
+}
+
+// NewInventoryImportService creates a new instance of InventoryImportService.
+func NewInventoryImportService(
+	inventoryRepo domain.InventoryRepository,
+	adjustmentRepo domain.InventoryAdjustmentRepository,
+	eventBus event.Bus, // Optional, nil publishes no stock changes
+	log *logger.Logger,
+) InventoryImportService {
+	return &inventoryImportService{
+		inventoryRepo:  inventoryRepo,
+		adjustmentRepo: adjustmentRepo,
+		stockEvents:    stockEvents{eventBus: eventBus},
+		log:            log,
+	}
+}
+
+func (s *inventoryImportService) Import(ctx context.Context, file io.Reader, dryRun bool, actorID string) (*InventoryImportResultDTO, error) {
+	result := &InventoryImportResultDTO{
+		DryRun:      dryRun,
+		Errors:      []*InventoryImportErrorDTO{},
+		Adjustments: []*InventoryAdjustmentDTO{},
+	}
+
+	rows, err := parseInventoryImport(file, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		if len(result.Errors) == 0 {
+			return nil, errors.ValidationError("inventory import file has no rows")
+		}
+		return result, nil
+	}
+
+	skuIDs := make([]string, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if !seen[row.skuID] {
+			seen[row.skuID] = true
+			skuIDs = append(skuIDs, row.skuID)
+		}
+	}
+
+	missing, err := s.inventoryRepo.FindMissingSKUIDs(ctx, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		unknown := make(map[string]bool, len(missing))
+		for _, skuID := range missing {
+			unknown[skuID] = true
+		}
+		for _, row := range rows {
+			if unknown[row.skuID] {
+				result.addError(row.line, row.skuID, "unknown SKU")
+			}
+		}
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	existing, err := s.inventoryRepo.FindBySKUIDs(ctx, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[string]*domain.InventoryLevel, len(existing))
+	for _, level := range existing {
+		if level.WarehouseID != nil {
+			levels[inventoryImportKey(level.SKUID, *level.WarehouseID)] = level
+		}
+	}
+
+	reference := uuid.New().String()
+	var changed []*domain.InventoryLevel
+	var adjustments []*domain.InventoryAdjustment
+	for _, row := range rows {
+		level, ok := levels[inventoryImportKey(row.skuID, row.warehouseID)]
+		before := 0
+		if ok {
+			if level.QuantityOnHand == row.quantity {
+				result.Unchanged++
+				continue
+			}
+			before = level.QuantityOnHand
+			level.RecordCount(row.quantity)
+			result.Updated++
+		} else {
+			level, err = domain.NewInventoryLevel(row.skuID, row.quantity)
+			if err != nil {
+				return nil, errors.ValidationError(err.Error())
+			}
+			warehouseID := row.warehouseID
+			level.WarehouseID = &warehouseID
+			now := level.CreatedAt
+			level.LastCountDate = &now
+			result.Created++
+		}
+		changed = append(changed, level)
+		adjustments = append(adjustments, domain.NewInventoryAdjustment(level, before, domain.AdjustmentReasonImport, reference, actorID))
+	}
+
+	for _, adjustment := range adjustments {
+		dto := ToInventoryAdjustmentDTO(adjustment)
+		if dryRun {
+			dto.ID = ""
+			dto.Reference = ""
+		}
+		result.Adjustments = append(result.Adjustments, dto)
+	}
+	if dryRun || len(changed) == 0 {
+		return result, nil
+	}
+
+	if err := s.adjustmentRepo.SaveWithLevels(ctx, changed, adjustments); err != nil {
+		return nil, err
+	}
+	result.Reference = reference
+	result.Applied = true
+
+	changedSKUIDs := make([]string, len(changed))
+	for i, level := range changed {
+		changedSKUIDs[i] = level.SKUID
+	}
+	s.stockEvents.publish(ctx, domain.StockChangeImported, changedSKUIDs...)
+
+	s.log.WithField("reference", reference).
+		WithField("created", result.Created).
+		WithField("updated", result.Updated).
+		Info("inventory import applied")
+	return result, nil
+}
+
+func (s *inventoryImportService) ListAdjustments(ctx context.Context, reference, skuID string, limit int) ([]*InventoryAdjustmentDTO, error) {
+	var adjustments []*domain.InventoryAdjustment
+	var err error
+	switch {
+	case reference != "":
+		adjustments, err = s.adjustmentRepo.FindByReference(ctx, reference)
+	case skuID != "":
+		adjustments, err = s.adjustmentRepo.FindBySKUID(ctx, skuID, limit)
+	default:
+		return nil, errors.ValidationError("reference or sku_id is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*InventoryAdjustmentDTO, len(adjustments))
+	for i, adjustment := range adjustments {
+		dtos[i] = ToInventoryAdjustmentDTO(adjustment)
+	}
+	return dtos, nil
+}
+
+// parseInventoryImport reads the rows of an import file, reporting invalid rows on result
+func parseInventoryImport(file io.Reader, result *InventoryImportResultDTO) ([]*inventoryImportRow, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.ValidationError("inventory import file is empty")
+	}
+	if err != nil {
+		return nil, errors.ValidationError("invalid inventory import file: " + err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range inventoryImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.ValidationError("inventory import file is missing the " + name + " column")
+		}
+	}
+	field := func(record []string, name string) string {
+		if i := columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []*inventoryImportRow
+	lines := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Rows++
+			result.addError(parseErr.StartLine, "", "malformed row: "+parseErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, errors.ValidationError("invalid inventory import file: " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := &inventoryImportRow{
+			line:        line,
+			skuID:       field(record, "sku_id"),
+			warehouseID: field(record, "warehouse_id"),
+		}
+		if row.skuID == "" && row.warehouseID == "" && field(record, "quantity") == "" {
+			continue
+		}
+		if result.Rows++; result.Rows > maxInventoryImportRows {
+			return nil, errors.ValidationError(fmt.Sprintf("inventory import file exceeds %d rows", maxInventoryImportRows))
+		}
+		if row.skuID == "" {
+			result.addError(line, "", "sku_id is required")
+			continue
+		}
+		if row.warehouseID == "" {
+			result.addError(line, row.skuID, "warehouse_id is required")
+			continue
+		}
+		quantity, err := strconv.Atoi(field(record, "quantity"))
+		if err != nil {
+			result.addError(line, row.skuID, "quantity must be a whole number")
+			continue
+		}
+		if quantity < 0 {
+			result.addError(line, row.skuID, "quantity cannot be negative")
+			continue
+		}
+		row.quantity = quantity
+
+		key := inventoryImportKey(row.skuID, row.warehouseID)
+		if first, ok := lines[key]; ok {
+			result.addError(line, row.skuID, fmt.Sprintf("duplicate of line %d for warehouse %s", first, row.warehouseID))
+			continue
+		}
+		lines[key] = line
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (r *InventoryImportResultDTO) addError(line int, skuID, message string) {
+	r.Errors = append(r.Errors, &InventoryImportErrorDTO{Line: line, SKUID: skuID, Message: message})
+}
+
+func inventoryImportKey(skuID, warehouseID string) string {
+	return skuID + "\x00" + warehouseID
+}
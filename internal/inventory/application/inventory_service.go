@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/event"
 )
 
 // InventoryService defines the application service for inventory-related operations.
@@ -52,12 +53,17 @@ type CreateInventoryLevelCommand struct {
 
 type inventoryService struct {
 	inventoryRepo domain.InventoryRepository
+	stockEvents   stockEvents
 }
 
 // NewInventoryService creates a new instance of InventoryService.
-func NewInventoryService(inventoryRepo domain.InventoryRepository) InventoryService {
+func NewInventoryService(
+	inventoryRepo domain.InventoryRepository,
+	eventBus event.Bus, // Optional, nil publishes no stock changes
+) InventoryService {
 	return &inventoryService{
 		inventoryRepo: inventoryRepo,
+		stockEvents:   stockEvents{eventBus: eventBus},
 	}
 }
 
@@ -71,6 +77,7 @@ func (s *inventoryService) CreateInventoryLevel(ctx context.Context, cmd *Create
 	if err != nil {
 		return nil, fmt.Errorf("failed to save inventory level: %w", err)
 	}
+	s.stockEvents.publish(ctx, domain.StockChangeAdjusted, level.SKUID)
 
 	return toInventoryLevelDTO(level), nil
 }
@@ -114,6 +121,7 @@ func (s *inventoryService) IncrementInventory(ctx context.Context, id string, qu
 	if err != nil {
 		return nil, fmt.Errorf("failed to save inventory level after increment: %w", err)
 	}
+	s.stockEvents.publish(ctx, domain.StockChangeAdjusted, level.SKUID)
 	return toInventoryLevelDTO(level), nil
 }
 
@@ -134,6 +142,7 @@ func (s *inventoryService) DecrementInventory(ctx context.Context, id string, qu
 	if err != nil {
 		return nil, fmt.Errorf("failed to save inventory level after decrement: %w", err)
 	}
+	s.stockEvents.publish(ctx, domain.StockChangeAdjusted, level.SKUID)
 	return toInventoryLevelDTO(level), nil
 }
 
@@ -154,6 +163,7 @@ func (s *inventoryService) ReserveInventory(ctx context.Context, id string, quan
 	if err != nil {
 		return nil, fmt.Errorf("failed to save inventory level after reservation: %w", err)
 	}
+	s.stockEvents.publish(ctx, domain.StockChangeReserved, level.SKUID)
 	return toInventoryLevelDTO(level), nil
 }
 
@@ -174,14 +184,22 @@ func (s *inventoryService) ReleaseInventory(ctx context.Context, id string, quan
 	if err != nil {
 		return nil, fmt.Errorf("failed to save inventory level after release: %w", err)
 	}
+	s.stockEvents.publish(ctx, domain.StockChangeReserved, level.SKUID)
 	return toInventoryLevelDTO(level), nil
 }
 
 func (s *inventoryService) DeleteInventoryLevel(ctx context.Context, id string) error {
-	err := s.inventoryRepo.Delete(ctx, id)
+	level, err := s.inventoryRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find inventory level by ID for delete: %w", err)
+	}
+	err = s.inventoryRepo.Delete(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete inventory level: %w", err)
 	}
+	if level != nil {
+		s.stockEvents.publish(ctx, domain.StockChangeAdjusted, level.SKUID)
+	}
 	return nil
 }
 
@@ -196,12 +214,17 @@ func (s *inventoryService) UpdateInventoryQuantities(ctx context.Context, id str
 
 	level.QuantityOnHand = quantityOnHand
 	level.QuantityReserved = quantityReserved
+	level.QuantityAvailable = quantityOnHand - quantityReserved - level.QuantityAllocated
+	if level.QuantityAvailable < 0 {
+		level.QuantityAvailable = 0
+	}
 	level.UpdatedAt = time.Now() // Update timestamp
 
 	err = s.inventoryRepo.Save(ctx, level)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save inventory level after quantity update: %w", err)
 	}
+	s.stockEvents.publish(ctx, domain.StockChangeAdjusted, level.SKUID)
 	return toInventoryLevelDTO(level), nil
 }
 
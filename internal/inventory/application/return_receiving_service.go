@@ -0,0 +1,435 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const (
+	maxReturnLines           = 500 // Lines of one received return
+	defaultQuarantinedLimit  = 100
+	maxQuarantinedLimit      = 1000
+	maxReturnReportRangeDays = 366
+)
+
+// ReceiveReturnCommand is the payload to receive a customer return at a warehouse
+type ReceiveReturnCommand struct {
+	Reference   string                 `json:"reference"`
+	OrderID     *int64                 `json:"order_id,omitempty"`
+	WarehouseID *string                `json:"warehouse_id,omitempty"`
+	Lines       []ReceiveReturnLineCmd `json:"lines"`
+}
+
+// ReceiveReturnLineCmd is a returned quantity of a SKU and what to do with it
+type ReceiveReturnLineCmd struct {
+	SKUID       string `json:"sku_id"`
+	Quantity    int    `json:"quantity"`
+	Disposition string `json:"disposition"` // RESTOCK, DAMAGED or QUARANTINE
+	Reason      string `json:"reason,omitempty"`
+}
+
+// ResolveQuarantineCommand is the payload to give a quarantined line its final disposition
+type ResolveQuarantineCommand struct {
+	Disposition string `json:"disposition"` // RESTOCK or DAMAGED
+}
+
+// ReturnReceiptDTO represents a received return
+type ReturnReceiptDTO struct {
+	ID          string                    `json:"id"`
+	Reference   string                    `json:"reference"`
+	OrderID     *int64                    `json:"order_id,omitempty"`
+	WarehouseID *string                   `json:"warehouse_id,omitempty"`
+	Lines       []*ReturnLineDTO          `json:"lines"`
+	ReceivedBy  string                    `json:"received_by,omitempty"`
+	ReceivedAt  time.Time                 `json:"received_at"`
+	Adjustments []*InventoryAdjustmentDTO `json:"adjustments,omitempty"` // Ledger entries the request posted
+}
+
+// ReturnLineDTO represents a line of a received return
+type ReturnLineDTO struct {
+	ID          string     `json:"id"`
+	SKUID       string     `json:"sku_id"`
+	Quantity    int        `json:"quantity"`
+	Disposition string     `json:"disposition"`
+	Reason      string     `json:"reason,omitempty"`
+	Quarantined bool       `json:"quarantined"`
+	ResolvedBy  string     `json:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+// ReturnStockRateDTO reports the returned units of a SKU by disposition
+type ReturnStockRateDTO struct {
+	SKUID       string  `json:"sku_id,omitempty"`
+	Returned    int     `json:"returned"`
+	Restocked   int     `json:"restocked"`
+	Damaged     int     `json:"damaged"`
+	Quarantined int     `json:"quarantined"`
+	RestockRate float64 `json:"restock_rate"`
+}
+
+// ReturnToStockReportDTO reports the share of returned units put back in stock
+type ReturnToStockReportDTO struct {
+	From  time.Time             `json:"from"`
+	To    time.Time             `json:"to"`
+	Total *ReturnStockRateDTO   `json:"total"`
+	SKUs  []*ReturnStockRateDTO `json:"skus"`
+}
+
+// ReturnReceivingService receives customer returns into inventory. Each line is restocked
+// on hand, counted as damaged, or held in quarantine until inspected, and every quantity it
+// changes is recorded in the adjustment ledger under the receipt ID.
+type ReturnReceivingService interface {
+	// ReceiveReturn records a return and posts its lines to the inventory levels of its warehouse.
+	ReceiveReturn(ctx context.Context, cmd *ReceiveReturnCommand, actorID string) (*ReturnReceiptDTO, error)
+
+	// GetReturn retrieves a received return.
+	GetReturn(ctx context.Context, id string) (*ReturnReceiptDTO, error)
+
+	// ResolveQuarantine releases a quarantined line to restocked or damaged.
+	ResolveQuarantine(ctx context.Context, lineID string, cmd *ResolveQuarantineCommand, actorID string) (*ReturnReceiptDTO, error)
+
+	// ListQuarantined lists the returns with lines still in quarantine, oldest first.
+	ListQuarantined(ctx context.Context, limit int) ([]*ReturnReceiptDTO, error)
+
+	// GetReturnToStockReport reports by SKU the units returned between from and to and how many were restocked.
+	GetReturnToStockReport(ctx context.Context, from, to time.Time) (*ReturnToStockReportDTO, error)
+}
+
+type returnReceivingService struct {
+	returnRepo    domain.ReturnReceiptRepository
+	inventoryRepo domain.InventoryRepository
+	stockEvents   stockEvents
+	log           *logger.Logger
+
+	// mu serializes the postings of this instance, so a line is not resolved twice and
+	// quarantine sums stay exact. Levels are moved by the units posted, not written over.
+	mu sync.Mutex
+}
+
+// NewReturnReceivingService creates a new instance of ReturnReceivingService.
+func NewReturnReceivingService(
+	returnRepo domain.ReturnReceiptRepository,
+	inventoryRepo domain.InventoryRepository,
+	eventBus event.Bus, // Optional, nil publishes no stock changes
+	log *logger.Logger,
+) ReturnReceivingService {
+	return &returnReceivingService{
+		returnRepo:    returnRepo,
+		inventoryRepo: inventoryRepo,
+		stockEvents:   stockEvents{eventBus: eventBus},
+		log:           log,
+	}
+}
+
+// returnPosting collects the levels and ledger entries the lines of a return change
+type returnPosting struct {
+	receipt     *domain.ReturnReceipt
+	actorID     string
+	levels      map[string]*domain.InventoryLevel // By SKU ID
+	restocked   map[string]int                    // Units restocked by SKU ID
+	damaged     map[string]int                    // Units written off as damaged by SKU ID
+	quarantined map[string]int                    // Units in quarantine by SKU ID
+	adjustments []*domain.InventoryAdjustment
+}
+
+func (s *returnReceivingService) ReceiveReturn(ctx context.Context, cmd *ReceiveReturnCommand, actorID string) (*ReturnReceiptDTO, error) {
+	if len(cmd.Lines) == 0 {
+		return nil, errors.ValidationError("lines is required")
+	}
+	if len(cmd.Lines) > maxReturnLines {
+		return nil, errors.ValidationError("too many return lines").WithDetail("max", maxReturnLines)
+	}
+	if cmd.WarehouseID != nil && *cmd.WarehouseID == "" {
+		cmd.WarehouseID = nil
+	}
+
+	receipt := domain.NewReturnReceipt(cmd.Reference, cmd.OrderID, cmd.WarehouseID, actorID)
+	for i, line := range cmd.Lines {
+		if _, err := receipt.AddLine(line.SKUID, line.Quantity, domain.ReturnDisposition(line.Disposition), line.Reason); err != nil {
+			return nil, errors.ValidationError(err.Error()).WithDetail("line", i)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	posting := s.newPosting(receipt, actorID)
+	for _, line := range receipt.Lines {
+		if err := s.post(ctx, posting, line, line.Disposition); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.save(ctx, posting); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"return_id": receipt.ID,
+		"reference": receipt.Reference,
+		"lines":     len(receipt.Lines),
+	}).Info("Return received")
+	return toReturnReceiptDTO(receipt, posting.adjustments), nil
+}
+
+func (s *returnReceivingService) GetReturn(ctx context.Context, id string) (*ReturnReceiptDTO, error) {
+	receipt, err := s.returnRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, errors.NotFound("return receipt")
+	}
+	return toReturnReceiptDTO(receipt, nil), nil
+}
+
+func (s *returnReceivingService) ResolveQuarantine(ctx context.Context, lineID string, cmd *ResolveQuarantineCommand, actorID string) (*ReturnReceiptDTO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipt, err := s.returnRepo.FindByLineID(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, errors.NotFound("return line")
+	}
+	line := receipt.Line(lineID)
+	if line == nil {
+		return nil, errors.NotFound("return line")
+	}
+
+	disposition := domain.ReturnDisposition(cmd.Disposition)
+	if line.Disposition != domain.ReturnDispositionQuarantine {
+		return nil, errors.Conflict("return line is not in quarantine")
+	}
+	if err := line.Resolve(disposition, actorID); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	posting := s.newPosting(receipt, actorID)
+	if err := s.release(ctx, posting, line); err != nil {
+		return nil, err
+	}
+	if err := s.post(ctx, posting, line, disposition); err != nil {
+		return nil, err
+	}
+	if err := s.save(ctx, posting); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"return_id":   receipt.ID,
+		"line_id":     line.ID,
+		"disposition": string(disposition),
+	}).Info("Quarantined return resolved")
+	return toReturnReceiptDTO(receipt, posting.adjustments), nil
+}
+
+func (s *returnReceivingService) ListQuarantined(ctx context.Context, limit int) ([]*ReturnReceiptDTO, error) {
+	if limit <= 0 {
+		limit = defaultQuarantinedLimit
+	}
+	if limit > maxQuarantinedLimit {
+		limit = maxQuarantinedLimit
+	}
+	receipts, err := s.returnRepo.FindQuarantined(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*ReturnReceiptDTO, len(receipts))
+	for i, receipt := range receipts {
+		dtos[i] = toReturnReceiptDTO(receipt, nil)
+	}
+	return dtos, nil
+}
+
+func (s *returnReceivingService) GetReturnToStockReport(ctx context.Context, from, to time.Time) (*ReturnToStockReportDTO, error) {
+	if !to.After(from) {
+		return nil, errors.ValidationError("to must be after from")
+	}
+	if to.Sub(from) > maxReturnReportRangeDays*24*time.Hour {
+		return nil, errors.ValidationError("report range is too long").WithDetail("max_days", maxReturnReportRangeDays)
+	}
+
+	rates, err := s.returnRepo.FindStockRates(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report := &ReturnToStockReportDTO{From: from, To: to, SKUs: make([]*ReturnStockRateDTO, len(rates))}
+	total := domain.ReturnStockRate{}
+	for i, rate := range rates {
+		report.SKUs[i] = toReturnStockRateDTO(*rate)
+		total.Returned += rate.Returned
+		total.Restocked += rate.Restocked
+		total.Damaged += rate.Damaged
+		total.Quarantined += rate.Quarantined
+	}
+	report.Total = toReturnStockRateDTO(total)
+	return report, nil
+}
+
+func (s *returnReceivingService) newPosting(receipt *domain.ReturnReceipt, actorID string) *returnPosting {
+	return &returnPosting{
+		receipt:     receipt,
+		actorID:     actorID,
+		levels:      make(map[string]*domain.InventoryLevel),
+		restocked:   make(map[string]int),
+		damaged:     make(map[string]int),
+		quarantined: make(map[string]int),
+	}
+}
+
+// post applies the units of a line to the stock its disposition says
+func (s *returnReceivingService) post(ctx context.Context, p *returnPosting, line *domain.ReturnLine, disposition domain.ReturnDisposition) error {
+	level, err := s.level(ctx, p, line.SKUID)
+	if err != nil {
+		return err
+	}
+
+	switch disposition {
+	case domain.ReturnDispositionRestock:
+		before := level.QuantityOnHand
+		if err := level.Increment(line.Quantity); err != nil {
+			return errors.ValidationError(err.Error())
+		}
+		p.adjustments = append(p.adjustments, domain.NewInventoryAdjustment(level, before, domain.AdjustmentReasonReturnRestock, p.receipt.ID, p.actorID))
+		p.restocked[line.SKUID] += line.Quantity
+	case domain.ReturnDispositionDamaged:
+		before := level.QuantityDamaged
+		level.QuantityDamaged += line.Quantity
+		level.UpdatedAt = time.Now()
+		p.damaged[line.SKUID] += line.Quantity
+		p.adjustments = append(p.adjustments, domain.NewStockAdjustment(level, domain.AdjustmentStockDamaged, before, level.QuantityDamaged, domain.AdjustmentReasonReturnDamaged, p.receipt.ID, p.actorID))
+	case domain.ReturnDispositionQuarantine:
+		before, err := s.quarantined(ctx, p, line.SKUID)
+		if err != nil {
+			return err
+		}
+		p.quarantined[line.SKUID] = before + line.Quantity
+		p.adjustments = append(p.adjustments, domain.NewStockAdjustment(level, domain.AdjustmentStockQuarantine, before, before+line.Quantity, domain.AdjustmentReasonReturnQuarantine, p.receipt.ID, p.actorID))
+	}
+	return nil
+}
+
+// release takes the units of a resolved line out of quarantine. The line's new
+// disposition is not stored yet, so the stored sum still counts them.
+func (s *returnReceivingService) release(ctx context.Context, p *returnPosting, line *domain.ReturnLine) error {
+	level, err := s.level(ctx, p, line.SKUID)
+	if err != nil {
+		return err
+	}
+	before, err := s.quarantined(ctx, p, line.SKUID)
+	if err != nil {
+		return err
+	}
+	after := max(before-line.Quantity, 0)
+	p.quarantined[line.SKUID] = after
+	p.adjustments = append(p.adjustments, domain.NewStockAdjustment(level, domain.AdjustmentStockQuarantine, before, after, domain.AdjustmentReasonReturnQuarantine, p.receipt.ID, p.actorID))
+	return nil
+}
+
+// level finds the inventory level a SKU of the return is received into, creating
+// one when the SKU is not stocked at the return's warehouse yet
+func (s *returnReceivingService) level(ctx context.Context, p *returnPosting, skuID string) (*domain.InventoryLevel, error) {
+	if level, ok := p.levels[skuID]; ok {
+		return level, nil
+	}
+
+	levels, err := s.inventoryRepo.FindBySKUIDs(ctx, []string{skuID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inventory levels: %w", err)
+	}
+	for _, level := range levels {
+		if sameWarehouse(level.WarehouseID, p.receipt.WarehouseID) {
+			p.levels[skuID] = level
+			return level, nil
+		}
+	}
+
+	level, err := domain.NewInventoryLevel(skuID, 0)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	level.WarehouseID = p.receipt.WarehouseID
+	p.levels[skuID] = level
+	return level, nil
+}
+
+// quarantined returns the units of a SKU in quarantine at the return's warehouse
+func (s *returnReceivingService) quarantined(ctx context.Context, p *returnPosting, skuID string) (int, error) {
+	if quantity, ok := p.quarantined[skuID]; ok {
+		return quantity, nil
+	}
+	quantity, err := s.returnRepo.SumQuarantined(ctx, skuID, p.receipt.WarehouseID)
+	if err != nil {
+		return 0, err
+	}
+	p.quarantined[skuID] = quantity
+	return quantity, nil
+}
+
+func (s *returnReceivingService) save(ctx context.Context, p *returnPosting) error {
+	changes := make([]*domain.ReturnStockChange, 0, len(p.levels))
+	restocked := make([]string, 0, len(p.restocked))
+	for skuID, level := range p.levels {
+		changes = append(changes, &domain.ReturnStockChange{
+			Level:   level,
+			OnHand:  p.restocked[skuID],
+			Damaged: p.damaged[skuID],
+		})
+		if p.restocked[skuID] > 0 {
+			restocked = append(restocked, skuID)
+		}
+	}
+	if err := s.returnRepo.SaveWithAdjustments(ctx, p.receipt, changes, p.adjustments); err != nil {
+		return err
+	}
+	s.stockEvents.publish(ctx, domain.StockChangeReturned, restocked...)
+	return nil
+}
+
+func toReturnReceiptDTO(receipt *domain.ReturnReceipt, adjustments []*domain.InventoryAdjustment) *ReturnReceiptDTO {
+	dto := &ReturnReceiptDTO{
+		ID:          receipt.ID,
+		Reference:   receipt.Reference,
+		OrderID:     receipt.OrderID,
+		WarehouseID: receipt.WarehouseID,
+		Lines:       make([]*ReturnLineDTO, len(receipt.Lines)),
+		ReceivedBy:  receipt.ReceivedBy,
+		ReceivedAt:  receipt.ReceivedAt,
+	}
+	for i, line := range receipt.Lines {
+		dto.Lines[i] = &ReturnLineDTO{
+			ID:          line.ID,
+			SKUID:       line.SKUID,
+			Quantity:    line.Quantity,
+			Disposition: string(line.Disposition),
+			Reason:      line.Reason,
+			Quarantined: line.Quarantined,
+			ResolvedBy:  line.ResolvedBy,
+			ResolvedAt:  line.ResolvedAt,
+		}
+	}
+	for _, adjustment := range adjustments {
+		dto.Adjustments = append(dto.Adjustments, ToInventoryAdjustmentDTO(adjustment))
+	}
+	return dto
+}
+
+func toReturnStockRateDTO(rate domain.ReturnStockRate) *ReturnStockRateDTO {
+	return &ReturnStockRateDTO{
+		SKUID:       rate.SKUID,
+		Returned:    rate.Returned,
+		Restocked:   rate.Restocked,
+		Damaged:     rate.Damaged,
+		Quarantined: rate.Quarantined,
+		RestockRate: rate.RestockRate(),
+	}
+}
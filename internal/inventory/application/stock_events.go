@@ -0,0 +1,38 @@
+package application
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// stockEvents publishes the stock changes of SKUs once they are stored. A nil
+// bus publishes nothing; a failure to publish is logged and does not undo the
+// change, since subscribers recompute from the current quantities on the next one.
+type stockEvents struct {
+	eventBus event.Bus
+}
+
+func (p stockEvents) publish(ctx context.Context, reason string, skuIDs ...string) {
+	if p.eventBus == nil || len(skuIDs) == 0 {
+		return
+	}
+
+	unique := make([]string, 0, len(skuIDs))
+	seen := make(map[string]bool, len(skuIDs))
+	for _, skuID := range skuIDs {
+		if skuID != "" && !seen[skuID] {
+			seen[skuID] = true
+			unique = append(unique, skuID)
+		}
+	}
+	if len(unique) == 0 {
+		return
+	}
+
+	if err := p.eventBus.Publish(ctx, domain.NewStockChangedEvent(reason, unique)); err != nil {
+		logger.WithError(err).WithField("reason", reason).Error("failed to publish stock changed event")
+	}
+}
@@ -0,0 +1,40 @@
+package domain
+
+// AvailableToPromise is the quantity of a SKU at a warehouse that can still be promised
+// to customers: stock on hand, less what is reserved or allocated to orders, plus
+// expected inbound receipts.
+type AvailableToPromise struct {
+	SKUID          string
+	WarehouseID    *string
+	OnHand         int
+	Reserved       int // Reserved and allocated to orders
+	Inbound        int
+	Available      int
+	AllowBackorder bool
+}
+
+// NewAvailableToPromise computes the available-to-promise quantity of an inventory level.
+// A nil level stands for a warehouse that only has inbound stock.
+func NewAvailableToPromise(skuID string, warehouseID *string, level *InventoryLevel, inbound int) *AvailableToPromise {
+	atp := &AvailableToPromise{
+		SKUID:       skuID,
+		WarehouseID: warehouseID,
+		Inbound:     inbound,
+	}
+	if level != nil {
+		atp.OnHand = level.QuantityOnHand
+		atp.Reserved = level.QuantityReserved + level.QuantityAllocated
+		atp.AllowBackorder = level.AllowBackorder
+	}
+
+	atp.Available = atp.OnHand - atp.Reserved + atp.Inbound
+	if atp.Available < 0 {
+		atp.Available = 0
+	}
+	return atp
+}
+
+// CanPromise checks if the requested quantity can be promised
+func (a *AvailableToPromise) CanPromise(quantity int) bool {
+	return a.AllowBackorder || a.Available >= quantity
+}
@@ -0,0 +1,309 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BinLocation is a storage location in a warehouse, addressed by zone, aisle, bay and level
+type BinLocation struct {
+	ID          int64
+	WarehouseID string
+	Code        string // Label on the bin, unique in its warehouse, e.g. A-03-02-B
+	Zone        string // Zones are walked in the order of their codes
+	Aisle       int
+	Bay         int // Position along the aisle, from its entrance
+	Level       int // Shelf, from the floor
+	Capacity    int // Units the bin holds; 0 is unlimited
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewBinLocation creates an active bin location in a warehouse
+func NewBinLocation(warehouseID, code, zone string, aisle, bay, level, capacity int) (*BinLocation, error) {
+	bin := &BinLocation{WarehouseID: strings.TrimSpace(warehouseID), Active: true}
+	if bin.WarehouseID == "" {
+		return nil, NewDomainError("WarehouseID is required")
+	}
+	if err := bin.Update(code, zone, aisle, bay, level, capacity); err != nil {
+		return nil, err
+	}
+	bin.CreatedAt = bin.UpdatedAt
+	return bin, nil
+}
+
+// Update changes the address and capacity of the bin
+func (b *BinLocation) Update(code, zone string, aisle, bay, level, capacity int) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return NewDomainError("bin code is required")
+	}
+	if aisle < 0 || bay < 0 || level < 0 {
+		return NewDomainError("aisle, bay and level cannot be negative")
+	}
+	if capacity < 0 {
+		return NewDomainError("capacity cannot be negative")
+	}
+	b.Code = code
+	b.Zone = strings.ToUpper(strings.TrimSpace(zone))
+	b.Aisle = aisle
+	b.Bay = bay
+	b.Level = level
+	b.Capacity = capacity
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+// Fits checks if the bin can hold a number of units
+func (b *BinLocation) Fits(units int) bool {
+	return b.Capacity == 0 || units <= b.Capacity
+}
+
+// BinAssignment stores a SKU in a bin
+type BinAssignment struct {
+	BinID     int64
+	SKUID     string
+	Quantity  int  // Units stored in the bin; 0 when not tracked
+	Primary   bool // Picked from first; a SKU has one primary bin per warehouse
+	UpdatedAt time.Time
+}
+
+// NewBinAssignment assigns a SKU to a bin
+func NewBinAssignment(binID int64, skuID string, quantity int, primary bool) (*BinAssignment, error) {
+	if skuID == "" {
+		return nil, NewDomainError("SKUID is required")
+	}
+	if quantity < 0 {
+		return nil, NewDomainError("quantity cannot be negative")
+	}
+	return &BinAssignment{
+		BinID:     binID,
+		SKUID:     skuID,
+		Quantity:  quantity,
+		Primary:   primary,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// SKULocation is a bin a SKU is assigned to
+type SKULocation struct {
+	Bin        *BinLocation
+	Assignment *BinAssignment
+}
+
+// PickLine is an order item to pick
+type PickLine struct {
+	OrderID     int64
+	OrderNumber string
+	OrderStatus string
+	SKUID       string
+	SKUName     string
+	Quantity    int
+}
+
+// PickOrderQuantity is how many units of a stop go to an order
+type PickOrderQuantity struct {
+	OrderID     int64
+	OrderNumber string
+	Quantity    int
+}
+
+// PickStop is a bin visited on a pick path with what to take from it. Bin is nil
+// for SKUs with no bin in the warehouse, which are listed last.
+type PickStop struct {
+	Sequence int
+	Bin      *BinLocation
+	SKUID    string
+	SKUName  string
+	Quantity int
+	Orders   []*PickOrderQuantity
+}
+
+// PlanPickPath assigns the lines to the bins of their SKUs and orders the stops
+// along a serpentine path: zones in order, the aisles with picks in ascending
+// order, walking every other aisle back from its far end, so no aisle is walked
+// twice. A SKU is taken from its primary bin first, then from the bins holding
+// the most; units beyond the stock recorded in its bins come from its first bin.
+func PlanPickPath(lines []*PickLine, locations map[string][]*SKULocation) []*PickStop {
+	remaining := make(map[int64]map[string]int) // Units left in each bin as lines take them
+	stops := make([]*PickStop, 0, len(lines))
+	byKey := make(map[string]*PickStop)
+
+	addStop := func(bin *BinLocation, line *PickLine, quantity int) {
+		key := line.SKUID
+		if bin != nil {
+			key = bin.Code + "\x00" + line.SKUID
+		}
+		stop, ok := byKey[key]
+		if !ok {
+			stop = &PickStop{Bin: bin, SKUID: line.SKUID, SKUName: line.SKUName}
+			byKey[key] = stop
+			stops = append(stops, stop)
+		}
+		stop.Quantity += quantity
+		if n := len(stop.Orders); n > 0 && stop.Orders[n-1].OrderID == line.OrderID {
+			stop.Orders[n-1].Quantity += quantity
+			return
+		}
+		stop.Orders = append(stop.Orders, &PickOrderQuantity{OrderID: line.OrderID, OrderNumber: line.OrderNumber, Quantity: quantity})
+	}
+
+	for _, line := range lines {
+		bins := pickOrder(locations[line.SKUID])
+		if len(bins) == 0 {
+			addStop(nil, line, line.Quantity)
+			continue
+		}
+		left := line.Quantity
+		for _, location := range bins {
+			if left == 0 {
+				break
+			}
+			stock, ok := remaining[location.Bin.ID]
+			if !ok {
+				stock = make(map[string]int)
+				remaining[location.Bin.ID] = stock
+			}
+			available, seen := stock[line.SKUID]
+			if !seen {
+				available = location.Assignment.Quantity
+			}
+			take := min(left, available)
+			stock[line.SKUID] = available - take
+			if take > 0 {
+				addStop(location.Bin, line, take)
+				left -= take
+			}
+		}
+		if left > 0 {
+			addStop(bins[0].Bin, line, left)
+		}
+	}
+
+	sortPickStops(stops)
+	for i, stop := range stops {
+		stop.Sequence = i + 1
+	}
+	return stops
+}
+
+// pickOrder orders the active bins of a SKU: the primary bin, then the ones holding the most
+func pickOrder(locations []*SKULocation) []*SKULocation {
+	bins := make([]*SKULocation, 0, len(locations))
+	for _, location := range locations {
+		if location.Bin.Active {
+			bins = append(bins, location)
+		}
+	}
+	sort.SliceStable(bins, func(i, j int) bool {
+		a, b := bins[i].Assignment, bins[j].Assignment
+		if a.Primary != b.Primary {
+			return a.Primary
+		}
+		if a.Quantity != b.Quantity {
+			return a.Quantity > b.Quantity
+		}
+		return bins[i].Bin.Code < bins[j].Bin.Code
+	})
+	return bins
+}
+
+// sortPickStops orders located stops along the serpentine path and unlocated ones last, by SKU
+func sortPickStops(stops []*PickStop) {
+	// Every other aisle with picks in a zone is walked back from its far end
+	type aisleKey struct {
+		zone  string
+		aisle int
+	}
+	aisles := make(map[aisleKey]bool)
+	for _, stop := range stops {
+		if stop.Bin != nil {
+			aisles[aisleKey{stop.Bin.Zone, stop.Bin.Aisle}] = true
+		}
+	}
+	keys := make([]aisleKey, 0, len(aisles))
+	for key := range aisles {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].zone != keys[j].zone {
+			return keys[i].zone < keys[j].zone
+		}
+		return keys[i].aisle < keys[j].aisle
+	})
+	reversed := make(map[aisleKey]bool, len(keys))
+	walked := 0
+	for i, key := range keys {
+		if i > 0 && keys[i-1].zone != key.zone {
+			walked = 0
+		}
+		reversed[key] = walked%2 == 1
+		walked++
+	}
+
+	sort.SliceStable(stops, func(i, j int) bool {
+		a, b := stops[i].Bin, stops[j].Bin
+		if a == nil || b == nil {
+			if a != nil || b != nil {
+				return a != nil
+			}
+			return stops[i].SKUID < stops[j].SKUID
+		}
+		if a.Zone != b.Zone {
+			return a.Zone < b.Zone
+		}
+		if a.Aisle != b.Aisle {
+			return a.Aisle < b.Aisle
+		}
+		if a.Bay != b.Bay {
+			if reversed[aisleKey{a.Zone, a.Aisle}] {
+				return a.Bay > b.Bay
+			}
+			return a.Bay < b.Bay
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return stops[i].SKUID < stops[j].SKUID
+	})
+}
+
+// BinLocationRepository defines the interface for bin location and assignment persistence
+type BinLocationRepository interface {
+	// Save creates or updates a bin location and sets its ID
+	Save(ctx context.Context, bin *BinLocation) error
+
+	// FindByID retrieves a bin location
+	FindByID(ctx context.Context, id int64) (*BinLocation, error)
+
+	// FindByCode retrieves the bin location of a warehouse with a code, nil when there is none
+	FindByCode(ctx context.Context, warehouseID, code string) (*BinLocation, error)
+
+	// FindByWarehouse retrieves the bin locations of a warehouse, optionally of one zone, by code
+	FindByWarehouse(ctx context.Context, warehouseID, zone string) ([]*BinLocation, error)
+
+	// Delete removes a bin location with its assignments
+	Delete(ctx context.Context, id int64) error
+
+	// SaveAssignment creates or updates the assignment of a SKU to a bin. A primary
+	// assignment clears the other primary bins of the SKU in the warehouse.
+	SaveAssignment(ctx context.Context, warehouseID string, assignment *BinAssignment) error
+
+	// DeleteAssignment removes the assignment of a SKU to a bin
+	DeleteAssignment(ctx context.Context, binID int64, skuID string) error
+
+	// FindAssignments retrieves the SKUs assigned to a bin
+	FindAssignments(ctx context.Context, binID int64) ([]*BinAssignment, error)
+
+	// FindLocations retrieves the bins the given SKUs are assigned to in a warehouse, by SKU
+	FindLocations(ctx context.Context, warehouseID string, skuIDs []string) (map[string][]*SKULocation, error)
+
+	// FindPickLines retrieves the items of the given orders, by order and item
+	FindPickLines(ctx context.Context, orderIDs []int64) ([]*PickLine, error)
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChannelReservationStatus is where a channel reservation is in its lifecycle
+type ChannelReservationStatus string
+
+const (
+	ChannelReservationActive   ChannelReservationStatus = "ACTIVE"   // Holding stock
+	ChannelReservationReleased ChannelReservationStatus = "RELEASED" // Released by the channel
+	ChannelReservationExpired  ChannelReservationStatus = "EXPIRED"  // Released after its expiry passed
+)
+
+// ChannelReservation holds stock of a SKU for an order placed on an external
+// sales channel such as a marketplace. Active reservations count as reserved
+// inventory, so the website cannot sell the same units.
+type ChannelReservation struct {
+	ID          string
+	Channel     string // Sales channel code, e.g. "amazon"
+	Reference   string // The channel's own order or line reference, unique per channel
+	SKUID       string
+	InventoryID string // Inventory level the stock is held on
+	Quantity    int
+	Status      ChannelReservationStatus
+	ExpiresAt   time.Time
+	ReleasedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewChannelReservation creates an active reservation of a channel expiring after ttl
+func NewChannelReservation(channel, reference, skuID string, quantity int, ttl time.Duration) (*ChannelReservation, error) {
+	if channel == "" || reference == "" || skuID == "" {
+		return nil, NewDomainError("Channel, reference and SKUID are required")
+	}
+	if quantity <= 0 {
+		return nil, NewDomainError("Quantity must be positive")
+	}
+	if ttl <= 0 {
+		return nil, NewDomainError("Reservation TTL must be positive")
+	}
+
+	now := time.Now()
+	return &ChannelReservation{
+		ID:        uuid.New().String(),
+		Channel:   channel,
+		Reference: reference,
+		SKUID:     skuID,
+		Quantity:  quantity,
+		Status:    ChannelReservationActive,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// IsActive checks whether the reservation still holds stock
+func (r *ChannelReservation) IsActive() bool {
+	return r.Status == ChannelReservationActive
+}
+
+// Extend moves the expiry of an active reservation to ttl from now; it never shortens it
+func (r *ChannelReservation) Extend(ttl time.Duration) error {
+	if !r.IsActive() {
+		return NewDomainError("Can only extend active reservations")
+	}
+	if ttl <= 0 {
+		return NewDomainError("Reservation TTL must be positive")
+	}
+
+	now := time.Now()
+	if expiry := now.Add(ttl); expiry.After(r.ExpiresAt) {
+		r.ExpiresAt = expiry
+	}
+	r.UpdatedAt = now
+	return nil
+}
+
+// ChannelAllocation caps the stock of a SKU a sales channel may hold reserved
+// at once, so the website keeps the rest
+type ChannelAllocation struct {
+	Percent     int // Share of the SKU's sellable stock, 1-100
+	MaxQuantity int // Absolute cap in units; 0 leaves only the share
+}
+
+// Limit returns how many units of a SKU the channel may hold reserved, given
+// the SKU's sellable stock: the units available plus those the channel
+// already holds
+func (a ChannelAllocation) Limit(stock int) int {
+	limit := stock * a.Percent / 100
+	if a.MaxQuantity > 0 && a.MaxQuantity < limit {
+		limit = a.MaxQuantity
+	}
+	return limit
+}
+
+// ChannelReservationRepository persists channel reservations together with the
+// inventory levels they hold stock on
+type ChannelReservationRepository interface {
+	// Reserve stores an active reservation and takes its quantity from the level
+	// of the SKU with the most stock available, within the channel's allocation.
+	// It fails with a conflict when the stock or the allocation falls short.
+	Reserve(ctx context.Context, reservation *ChannelReservation, allocation ChannelAllocation) error
+
+	// UpdateExpiry saves the expiry of an active reservation
+	UpdateExpiry(ctx context.Context, reservation *ChannelReservation) error
+
+	// Release ends an active reservation with the given status and returns its
+	// stock to the inventory level; false when it was no longer active
+	Release(ctx context.Context, id string, status ChannelReservationStatus) (bool, error)
+
+	// FindByID retrieves a reservation by ID
+	FindByID(ctx context.Context, id string) (*ChannelReservation, error)
+
+	// FindByReference retrieves a reservation of a channel by its reference, nil if none exists
+	FindByReference(ctx context.Context, channel, reference string) (*ChannelReservation, error)
+
+	// FindExpired lists active reservations whose expiry has passed
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*ChannelReservation, error)
+}
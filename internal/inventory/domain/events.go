@@ -1,6 +1,49 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/event"
+)
+
+// EventStockChanged is published when the stock of SKUs changes, whatever the cause
+const EventStockChanged = "inventory.stock.changed"
+
+// Stock change reasons
+const (
+	StockChangeAdjusted  = "adjusted"   // Quantities set, incremented or decremented, or a level removed
+	StockChangeReserved  = "reserved"   // Reserved or released for orders and channels
+	StockChangeImported  = "imported"   // Quantities set by an import file
+	StockChangeInbound   = "inbound"    // Inbound receipts expected, received or cancelled
+	StockChangeFlashSale = "flash_sale" // Flash sale tokens reconciled into the levels
+	StockChangeReturned  = "returned"   // Returned units restocked
+)
+
+// Stock events are rebuilt as their own types when read from a serializing bus
+func init() {
+	event.RegisterType(EventStockChanged, func() event.Event { return &StockChangedEvent{} })
+}
+
+// StockChangedEvent is published when the quantities of SKUs change, so
+// subscribers such as the SKU availability sync recompute what they derive
+// from them. It carries no quantities: subscribers read the current ones.
+type StockChangedEvent struct {
+	event.BaseEvent
+	SKUIDs []string `json:"sku_ids"`
+	Reason string   `json:"reason"`
+}
+
+// NewStockChangedEvent creates a new StockChangedEvent
+func NewStockChangedEvent(reason string, skuIDs []string) *StockChangedEvent {
+	return &StockChangedEvent{
+		BaseEvent: event.BaseEvent{
+			Type:       EventStockChanged,
+			OccurredOn: time.Now(),
+		},
+		SKUIDs: skuIDs,
+		Reason: reason,
+	}
+}
 
 // InventoryLevelCreatedEvent is published when a new inventory level is created.
 type InventoryLevelCreatedEvent struct {
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// FlashAllocationStatus is whether a SKU is in flash-sale allocation mode
+type FlashAllocationStatus string
+
+const (
+	FlashAllocationActive  FlashAllocationStatus = "ACTIVE"
+	FlashAllocationStopped FlashAllocationStatus = "STOPPED"
+)
+
+// FlashAllocation puts a SKU in flash-sale allocation mode. Reservations of the SKU take
+// tokens from an atomic counter seeded with its available stock instead of locking its
+// inventory levels, so a burst of orders is served first come, first served without
+// database contention. Reconciliations apply the reserved quantities to the levels and
+// correct the counter for stock changes made elsewhere, such as restocks or cancellations.
+type FlashAllocation struct {
+	SKUID            string
+	Status           FlashAllocationStatus
+	SeededQuantity   int // Tokens the counter started with
+	ReservedQuantity int // Reserved through the counter and applied to the levels so far
+	StartedBy        string
+	StartedAt        time.Time
+	StoppedAt        *time.Time
+	LastReconciledAt *time.Time
+}
+
+// NewFlashAllocation creates an active flash allocation of a SKU seeded with its available stock
+func NewFlashAllocation(skuID string, available int, startedBy string) (*FlashAllocation, error) {
+	if skuID == "" {
+		return nil, NewDomainError("SKUID is required")
+	}
+	if available <= 0 {
+		return nil, NewDomainError("SKU " + skuID + " has no stock available for a flash sale")
+	}
+	return &FlashAllocation{
+		SKUID:          skuID,
+		Status:         FlashAllocationActive,
+		SeededQuantity: available,
+		StartedBy:      startedBy,
+		StartedAt:      time.Now(),
+	}, nil
+}
+
+// IsActive reports whether reservations of the SKU go through the counter
+func (a *FlashAllocation) IsActive() bool {
+	return a.Status == FlashAllocationActive
+}
+
+// Reconciled records a reconciliation of the allocation
+func (a *FlashAllocation) Reconciled(r *FlashReconciliation) {
+	a.ReservedQuantity += r.ReservedDelta
+	a.LastReconciledAt = &r.CreatedAt
+}
+
+// Stop ends flash-sale allocation mode; reservations lock the inventory levels again
+func (a *FlashAllocation) Stop() error {
+	if !a.IsActive() {
+		return NewDomainError("Flash allocation of SKU " + a.SKUID + " is already stopped")
+	}
+	now := time.Now()
+	a.Status = FlashAllocationStopped
+	a.StoppedAt = &now
+	return nil
+}
+
+// FlashReconciliation is a ledger entry of the reservations made through the counter that
+// were applied to the inventory levels of a SKU
+type FlashReconciliation struct {
+	ID             int64
+	SKUID          string
+	ReservedDelta  int // Net quantity reserved, less released, since the last reconciliation
+	TokensBefore   int // Tokens left in the counter when it was drained
+	AvailableAfter int // Stock available in the levels once the reservations were applied
+	CreatedAt      time.Time
+}
+
+// NewFlashReconciliation creates a ledger entry of a reconciliation
+func NewFlashReconciliation(skuID string, reservedDelta, tokensBefore, availableAfter int) *FlashReconciliation {
+	return &FlashReconciliation{
+		SKUID:          skuID,
+		ReservedDelta:  reservedDelta,
+		TokensBefore:   tokensBefore,
+		AvailableAfter: availableAfter,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// Drift is how many tokens the counter is corrected by so it matches the stock available:
+// positive after restocks or released stock, negative after stock was taken outside it
+func (r *FlashReconciliation) Drift() int {
+	return r.AvailableAfter - r.TokensBefore
+}
+
+// IsNoop reports whether the reconciliation changed nothing worth recording
+func (r *FlashReconciliation) IsNoop() bool {
+	return r.ReservedDelta == 0 && r.Drift() == 0
+}
+
+// FlashTokenStore holds the token counters of SKUs in flash allocation mode. Operations on
+// one SKU are atomic across every instance sharing the store.
+type FlashTokenStore interface {
+	// Seed starts the counter of a SKU with tokens and no pending reservations
+	Seed(ctx context.Context, skuID string, tokens int) error
+
+	// Take takes quantity tokens of a SKU if enough are left and adds them to its pending
+	// reservations. active is false when the SKU has no counter; remaining is what is left.
+	Take(ctx context.Context, skuID string, quantity int) (taken bool, remaining int, active bool, err error)
+
+	// Return gives quantity tokens of a SKU back and removes them from its pending
+	// reservations; false when the SKU has no counter.
+	Return(ctx context.Context, skuID string, quantity int) (bool, error)
+
+	// Drain resets the pending reservations of a SKU, returning them with the tokens left.
+	Drain(ctx context.Context, skuID string) (pending, tokens int, err error)
+
+	// Requeue adds pending reservations back after a reconciliation failed to apply them.
+	Requeue(ctx context.Context, skuID string, pending int) error
+
+	// Adjust adds delta tokens to the counter of a SKU, if it still has one.
+	Adjust(ctx context.Context, skuID string, delta int) error
+
+	// Clear removes the counter of a SKU so it stops taking reservations. Pending
+	// reservations stay until drained.
+	Clear(ctx context.Context, skuID string) error
+
+	// Tokens returns the tokens left of a SKU; active is false when it has no counter.
+	Tokens(ctx context.Context, skuID string) (tokens int, active bool, err error)
+}
+
+// FlashAllocationRepository defines the interface for flash allocation persistence
+type FlashAllocationRepository interface {
+	// Save creates or updates the flash allocation of a SKU
+	Save(ctx context.Context, allocation *FlashAllocation) error
+
+	// FindBySKUID retrieves the flash allocation of a SKU, nil if it never had one
+	FindBySKUID(ctx context.Context, skuID string) (*FlashAllocation, error)
+
+	// FindAll lists flash allocations, most recently started first; activeOnly leaves out stopped ones
+	FindAll(ctx context.Context, activeOnly bool) ([]*FlashAllocation, error)
+
+	// SumAvailable returns the stock available of a SKU across its inventory levels
+	SumAvailable(ctx context.Context, skuID string) (int, error)
+
+	// ApplyReconciliation applies the reserved delta of a reconciliation to the inventory levels
+	// of its SKU, sets AvailableAfter, and records the reconciliation and the allocation's
+	// progress in one transaction
+	ApplyReconciliation(ctx context.Context, allocation *FlashAllocation, reconciliation *FlashReconciliation) error
+
+	// FindReconciliations lists the latest reconciliations of a SKU, newest first
+	FindReconciliations(ctx context.Context, skuID string, limit int) ([]*FlashReconciliation, error)
+}
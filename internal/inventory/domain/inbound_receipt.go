@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InboundReceiptStatus represents the status of an inbound receipt
+type InboundReceiptStatus string
+
+const (
+	InboundReceiptStatusExpected  InboundReceiptStatus = "EXPECTED"
+	InboundReceiptStatusReceived  InboundReceiptStatus = "RECEIVED"
+	InboundReceiptStatusCancelled InboundReceiptStatus = "CANCELLED"
+)
+
+// InboundReceipt represents stock expected to arrive at a warehouse, e.g. from a purchase order.
+// Expected receipts count towards the available-to-promise quantity.
+type InboundReceipt struct {
+	ID          string
+	SKUID       string
+	WarehouseID *string
+	Quantity    int
+	ExpectedAt  time.Time
+	Reference   string // Purchase order or shipment reference
+	Status      InboundReceiptStatus
+	ReceivedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewInboundReceipt creates a new expected inbound receipt
+func NewInboundReceipt(skuID string, warehouseID *string, quantity int, expectedAt time.Time, reference string) (*InboundReceipt, error) {
+	if skuID == "" {
+		return nil, NewDomainError("SKUID is required")
+	}
+	if quantity <= 0 {
+		return nil, NewDomainError("Quantity must be positive")
+	}
+	if expectedAt.IsZero() {
+		return nil, NewDomainError("Expected date is required")
+	}
+
+	now := time.Now()
+	return &InboundReceipt{
+		ID:          uuid.New().String(),
+		SKUID:       skuID,
+		WarehouseID: warehouseID,
+		Quantity:    quantity,
+		ExpectedAt:  expectedAt,
+		Reference:   reference,
+		Status:      InboundReceiptStatusExpected,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Receive marks the receipt as received; the stock is then on hand
+func (r *InboundReceipt) Receive() error {
+	if r.Status != InboundReceiptStatusExpected {
+		return NewDomainError("Only expected receipts can be received")
+	}
+	now := time.Now()
+	r.Status = InboundReceiptStatusReceived
+	r.ReceivedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// Cancel marks the receipt as cancelled
+func (r *InboundReceipt) Cancel() error {
+	if r.Status != InboundReceiptStatusExpected {
+		return NewDomainError("Only expected receipts can be cancelled")
+	}
+	r.Status = InboundReceiptStatusCancelled
+	r.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons recorded on inventory adjustments
+const (
+	AdjustmentReasonImport           = "IMPORT"            // Quantity on hand set by a bulk import, e.g. a warehouse count
+	AdjustmentReasonReturnRestock    = "RETURN_RESTOCK"    // Returned units put back on hand
+	AdjustmentReasonReturnDamaged    = "RETURN_DAMAGED"    // Returned units counted as damaged
+	AdjustmentReasonReturnQuarantine = "RETURN_QUARANTINE" // Returned units held for inspection, or released from it
+)
+
+// Stock an adjustment changes
+const (
+	AdjustmentStockOnHand     = "ON_HAND"    // Sellable units on hand
+	AdjustmentStockDamaged    = "DAMAGED"    // Damaged units, kept apart from those on hand
+	AdjustmentStockQuarantine = "QUARANTINE" // Returned units held for inspection
+)
+
+// InventoryAdjustment is a ledger entry recording a change to a quantity of an
+// inventory level, on hand unless Stock says otherwise
+type InventoryAdjustment struct {
+	ID             string
+	InventoryID    string
+	SKUID          string
+	WarehouseID    *string
+	Stock          string
+	QuantityBefore int
+	QuantityAfter  int
+	Reason         string
+	Reference      string // Groups the adjustments of one operation, e.g. an import
+	ActorID        string
+	CreatedAt      time.Time
+}
+
+// NewInventoryAdjustment records the change of a level's quantity on hand from before to its current quantity
+func NewInventoryAdjustment(level *InventoryLevel, before int, reason, reference, actorID string) *InventoryAdjustment {
+	return &InventoryAdjustment{
+		ID:             uuid.New().String(),
+		InventoryID:    level.ID,
+		SKUID:          level.SKUID,
+		WarehouseID:    level.WarehouseID,
+		Stock:          AdjustmentStockOnHand,
+		QuantityBefore: before,
+		QuantityAfter:  level.QuantityOnHand,
+		Reason:         reason,
+		Reference:      reference,
+		ActorID:        actorID,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// NewStockAdjustment records the change of another stock of a level, such as
+// its damaged units, from before to after
+func NewStockAdjustment(level *InventoryLevel, stock string, before, after int, reason, reference, actorID string) *InventoryAdjustment {
+	adjustment := NewInventoryAdjustment(level, before, reason, reference, actorID)
+	adjustment.Stock = stock
+	adjustment.QuantityAfter = after
+	return adjustment
+}
+
+// Delta returns the change in quantity on hand
+func (a *InventoryAdjustment) Delta() int {
+	return a.QuantityAfter - a.QuantityBefore
+}
+
+// InventoryAdjustmentRepository provides an interface for the inventory adjustment ledger.
+type InventoryAdjustmentRepository interface {
+	// SaveWithLevels creates or updates the adjusted inventory levels and appends their
+	// adjustments to the ledger in one transaction.
+	SaveWithLevels(ctx context.Context, levels []*InventoryLevel, adjustments []*InventoryAdjustment) error
+
+	// FindBySKUID retrieves the latest adjustments of a SKU, newest first.
+	FindBySKUID(ctx context.Context, skuID string, limit int) ([]*InventoryAdjustment, error)
+
+	// FindByReference retrieves the adjustments made by one operation.
+	FindByReference(ctx context.Context, reference string) ([]*InventoryAdjustment, error)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// InventoryExportRow is the flat view of an inventory level written to exports
+type InventoryExportRow struct {
+	SKUID             string
+	WarehouseID       string
+	QuantityOnHand    int
+	QuantityReserved  int
+	QuantityAllocated int
+	QuantityAvailable int
+	QuantityInTransit int
+	QuantityDamaged   int
+	ReorderPoint      int // Set on low stock rows only
+	ReorderQuantity   int // Set on low stock rows only
+	LastCountDate     *time.Time
+	UpdatedAt         time.Time
+}
+
+// InventoryExportRepository streams inventory levels for exports
+type InventoryExportRepository interface {
+	// StreamLevels calls fn for each inventory level, ordered by warehouse and SKU, without
+	// loading them all. An empty warehouseID exports every warehouse.
+	StreamLevels(ctx context.Context, warehouseID string, fn func(*InventoryExportRow) error) error
+
+	// StreamLowStock calls fn for each inventory level that needs reordering, i.e. whose
+	// quantity on hand and in transit is at or below its reorder point, ordered like
+	// StreamLevels. An empty warehouseID covers every warehouse.
+	StreamLowStock(ctx context.Context, warehouseID string, fn func(*InventoryExportRow) error) error
+}
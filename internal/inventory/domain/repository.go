@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // InventoryRepository provides an interface for managing inventory levels.
@@ -18,6 +19,12 @@ type InventoryRepository interface {
 	// FindByWarehouse retrieves inventory levels by warehouse.
 	FindByWarehouse(ctx context.Context, warehouseID string) ([]*InventoryLevel, error)
 
+	// FindBySKUIDs retrieves the inventory levels of several SKUs across all warehouses.
+	FindBySKUIDs(ctx context.Context, skuIDs []string) ([]*InventoryLevel, error)
+
+	// FindMissingSKUIDs returns the given SKU IDs that have no SKU in the catalog.
+	FindMissingSKUIDs(ctx context.Context, skuIDs []string) ([]string, error)
+
 	// Delete removes an inventory level by its unique identifier.
 	Delete(ctx context.Context, id string) error
 }
@@ -39,3 +46,15 @@ type InventoryReservationRepository interface {
 	// Delete removes a reservation by its unique identifier.
 	Delete(ctx context.Context, id string) error
 }
+
+// InboundReceiptRepository provides an interface for managing inbound receipts.
+type InboundReceiptRepository interface {
+	// Save stores a new receipt or updates an existing one.
+	Save(ctx context.Context, receipt *InboundReceipt) error
+
+	// FindByID retrieves a receipt by its unique identifier.
+	FindByID(ctx context.Context, id string) (*InboundReceipt, error)
+
+	// FindExpectedBySKUIDs retrieves the receipts of several SKUs still expected by the given time.
+	FindExpectedBySKUIDs(ctx context.Context, skuIDs []string, expectedBy time.Time) ([]*InboundReceipt, error)
+}
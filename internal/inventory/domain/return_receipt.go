@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReturnDisposition is what is done with returned units once inspected
+type ReturnDisposition string
+
+const (
+	ReturnDispositionRestock    ReturnDisposition = "RESTOCK"    // Back on hand and sellable
+	ReturnDispositionDamaged    ReturnDisposition = "DAMAGED"    // Counted as damaged stock, never sold
+	ReturnDispositionQuarantine ReturnDisposition = "QUARANTINE" // Held for inspection until restocked or found damaged
+)
+
+// IsValid checks whether the disposition is a known one
+func (d ReturnDisposition) IsValid() bool {
+	switch d {
+	case ReturnDispositionRestock, ReturnDispositionDamaged, ReturnDispositionQuarantine:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReturnReceipt records the units of SKUs a customer sent back, as received at
+// a warehouse. Each line carries the disposition staff chose for its units.
+type ReturnReceipt struct {
+	ID          string
+	Reference   string // Return authorization or carrier reference
+	OrderID     *int64 // Order the units were bought with, if known
+	WarehouseID *string
+	Lines       []*ReturnLine
+	ReceivedBy  string
+	ReceivedAt  time.Time
+}
+
+// ReturnLine is a quantity of one SKU of a return and its disposition.
+// Quarantined lines are resolved later to restocked or damaged.
+type ReturnLine struct {
+	ID          string
+	ReceiptID   string
+	SKUID       string
+	Quantity    int
+	Disposition ReturnDisposition
+	Reason      string // Why the customer returned it, e.g. "wrong size"
+	Quarantined bool   // Was held in quarantine before its disposition
+	ResolvedBy  string
+	ResolvedAt  *time.Time
+	CreatedAt   time.Time
+}
+
+// NewReturnReceipt creates a received return
+func NewReturnReceipt(reference string, orderID *int64, warehouseID *string, receivedBy string) *ReturnReceipt {
+	return &ReturnReceipt{
+		ID:          uuid.New().String(),
+		Reference:   reference,
+		OrderID:     orderID,
+		WarehouseID: warehouseID,
+		Lines:       []*ReturnLine{},
+		ReceivedBy:  receivedBy,
+		ReceivedAt:  time.Now(),
+	}
+}
+
+// AddLine adds received units of a SKU with their disposition
+func (r *ReturnReceipt) AddLine(skuID string, quantity int, disposition ReturnDisposition, reason string) (*ReturnLine, error) {
+	if skuID == "" {
+		return nil, NewDomainError("SKUID is required")
+	}
+	if quantity <= 0 {
+		return nil, NewDomainError("Quantity must be positive")
+	}
+	if !disposition.IsValid() {
+		return nil, NewDomainError("Disposition must be RESTOCK, DAMAGED or QUARANTINE")
+	}
+	line := &ReturnLine{
+		ID:          uuid.New().String(),
+		ReceiptID:   r.ID,
+		SKUID:       skuID,
+		Quantity:    quantity,
+		Disposition: disposition,
+		Reason:      reason,
+		Quarantined: disposition == ReturnDispositionQuarantine,
+		CreatedAt:   r.ReceivedAt,
+	}
+	r.Lines = append(r.Lines, line)
+	return line, nil
+}
+
+// Line returns the line of a receipt by ID
+func (r *ReturnReceipt) Line(id string) *ReturnLine {
+	for _, line := range r.Lines {
+		if line.ID == id {
+			return line
+		}
+	}
+	return nil
+}
+
+// Resolve gives a quarantined line its final disposition
+func (l *ReturnLine) Resolve(disposition ReturnDisposition, resolvedBy string) error {
+	if l.Disposition != ReturnDispositionQuarantine {
+		return NewDomainError("Only quarantined returns can be resolved")
+	}
+	if disposition != ReturnDispositionRestock && disposition != ReturnDispositionDamaged {
+		return NewDomainError("Quarantined returns are resolved as RESTOCK or DAMAGED")
+	}
+	now := time.Now()
+	l.Disposition = disposition
+	l.ResolvedBy = resolvedBy
+	l.ResolvedAt = &now
+	return nil
+}
+
+// ReturnStockRate counts the returned units of a SKU by disposition
+type ReturnStockRate struct {
+	SKUID       string
+	Returned    int
+	Restocked   int
+	Damaged     int
+	Quarantined int // Still held
+}
+
+// RestockRate returns the share of returned units put back in stock
+func (r ReturnStockRate) RestockRate() float64 {
+	if r.Returned == 0 {
+		return 0
+	}
+	return float64(r.Restocked) / float64(r.Returned)
+}
+
+// ReturnStockChange is the stock a return posts to an inventory level. It is
+// added to the stored level, so stock moved since the level was read is kept;
+// a level not stored yet is inserted as posted.
+type ReturnStockChange struct {
+	Level   *InventoryLevel
+	OnHand  int // Units restocked
+	Damaged int // Units written off as damaged
+}
+
+// ReturnReceiptRepository stores received returns
+type ReturnReceiptRepository interface {
+	// SaveWithAdjustments stores a new receipt, or the lines of one that changed, with
+	// the stock changes and ledger entries its dispositions posted, in one transaction.
+	SaveWithAdjustments(ctx context.Context, receipt *ReturnReceipt, changes []*ReturnStockChange, adjustments []*InventoryAdjustment) error
+
+	// FindByID retrieves a receipt with its lines, nil when there is none.
+	FindByID(ctx context.Context, id string) (*ReturnReceipt, error)
+
+	// FindByLineID retrieves the receipt of a line, nil when there is none.
+	FindByLineID(ctx context.Context, lineID string) (*ReturnReceipt, error)
+
+	// FindQuarantined lists the receipts with quarantined lines, oldest first.
+	FindQuarantined(ctx context.Context, limit int) ([]*ReturnReceipt, error)
+
+	// SumQuarantined returns the units of a SKU held in quarantine at a warehouse.
+	SumQuarantined(ctx context.Context, skuID string, warehouseID *string) (int, error)
+
+	// FindStockRates counts the units returned between from and to by SKU and disposition.
+	FindStockRates(ctx context.Context, from, to time.Time) ([]*ReturnStockRate, error)
+}
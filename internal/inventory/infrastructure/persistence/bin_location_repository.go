@@ -0,0 +1,276 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresBinLocationRepository implements the BinLocationRepository interface
+type PostgresBinLocationRepository struct {
+	db *database.DB
+}
+
+// NewPostgresBinLocationRepository creates a new PostgresBinLocationRepository
+func NewPostgresBinLocationRepository(db *database.DB) *PostgresBinLocationRepository {
+	return &PostgresBinLocationRepository{db: db}
+}
+
+const binLocationColumns = `
+	bin_id, warehouse_id, code, zone, aisle, bay, level, capacity, active, created_at, updated_at`
+
+// Save creates or updates a bin location and sets its ID
+func (r *PostgresBinLocationRepository) Save(ctx context.Context, bin *domain.BinLocation) error {
+	if bin.ID == 0 {
+		err := r.db.QueryRow(ctx, `
+			INSERT INTO inventory_bin_location (warehouse_id, code, zone, aisle, bay, level, capacity, active, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING bin_id`,
+			bin.WarehouseID, bin.Code, bin.Zone, bin.Aisle, bin.Bay, bin.Level, bin.Capacity, bin.Active,
+			bin.CreatedAt, bin.UpdatedAt,
+		).Scan(&bin.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create bin location")
+		}
+		return nil
+	}
+
+	tag, err := r.db.Pool().Exec(ctx, `
+		UPDATE inventory_bin_location SET
+			code = $2, zone = $3, aisle = $4, bay = $5, level = $6, capacity = $7, active = $8, updated_at = $9
+		WHERE bin_id = $1`,
+		bin.ID, bin.Code, bin.Zone, bin.Aisle, bin.Bay, bin.Level, bin.Capacity, bin.Active, bin.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update bin location")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("bin location")
+	}
+	return nil
+}
+
+// FindByID retrieves a bin location
+func (r *PostgresBinLocationRepository) FindByID(ctx context.Context, id int64) (*domain.BinLocation, error) {
+	query := `SELECT ` + binLocationColumns + ` FROM inventory_bin_location WHERE bin_id = $1`
+	bin, err := scanBinLocation(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("bin location")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find bin location")
+	}
+	return bin, nil
+}
+
+// FindByCode retrieves the bin location of a warehouse with a code, nil when there is none
+func (r *PostgresBinLocationRepository) FindByCode(ctx context.Context, warehouseID, code string) (*domain.BinLocation, error) {
+	query := `SELECT ` + binLocationColumns + ` FROM inventory_bin_location WHERE warehouse_id = $1 AND code = $2`
+	bin, err := scanBinLocation(r.db.QueryRow(ctx, query, warehouseID, code))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find bin location")
+	}
+	return bin, nil
+}
+
+// FindByWarehouse retrieves the bin locations of a warehouse, optionally of one zone, by code
+func (r *PostgresBinLocationRepository) FindByWarehouse(ctx context.Context, warehouseID, zone string) ([]*domain.BinLocation, error) {
+	query := `
+		SELECT ` + binLocationColumns + `
+		FROM inventory_bin_location
+		WHERE warehouse_id = $1 AND ($2 = '' OR zone = $2)
+		ORDER BY code`
+	rows, err := r.db.Query(ctx, query, warehouseID, zone)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find bin locations")
+	}
+	defer rows.Close()
+
+	bins := make([]*domain.BinLocation, 0)
+	for rows.Next() {
+		bin, err := scanBinLocation(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan bin location")
+		}
+		bins = append(bins, bin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate bin locations")
+	}
+	return bins, nil
+}
+
+// Delete removes a bin location with its assignments
+func (r *PostgresBinLocationRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM inventory_bin_location WHERE bin_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete bin location")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("bin location")
+	}
+	return nil
+}
+
+// SaveAssignment creates or updates the assignment of a SKU to a bin. A primary
+// assignment clears the other primary bins of the SKU in the warehouse.
+func (r *PostgresBinLocationRepository) SaveAssignment(ctx context.Context, warehouseID string, assignment *domain.BinAssignment) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if assignment.Primary {
+			_, err := tx.Exec(ctx, `
+				UPDATE inventory_bin_assignment a SET is_primary = FALSE, updated_at = $4
+				FROM inventory_bin_location b
+				WHERE b.bin_id = a.bin_id AND b.warehouse_id = $1
+					AND a.sku_id = $2 AND a.bin_id <> $3 AND a.is_primary`,
+				warehouseID, assignment.SKUID, assignment.BinID, assignment.UpdatedAt,
+			)
+			if err != nil {
+				return errors.InternalWrap(err, "failed to clear primary bin")
+			}
+		}
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO inventory_bin_assignment (bin_id, sku_id, quantity, is_primary, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (bin_id, sku_id) DO UPDATE SET
+				quantity = EXCLUDED.quantity,
+				is_primary = EXCLUDED.is_primary,
+				updated_at = EXCLUDED.updated_at`,
+			assignment.BinID, assignment.SKUID, assignment.Quantity, assignment.Primary, assignment.UpdatedAt,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to save bin assignment")
+		}
+		return nil
+	})
+}
+
+// DeleteAssignment removes the assignment of a SKU to a bin
+func (r *PostgresBinLocationRepository) DeleteAssignment(ctx context.Context, binID int64, skuID string) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM inventory_bin_assignment WHERE bin_id = $1 AND sku_id = $2`, binID, skuID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete bin assignment")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("bin assignment")
+	}
+	return nil
+}
+
+// FindAssignments retrieves the SKUs assigned to a bin
+func (r *PostgresBinLocationRepository) FindAssignments(ctx context.Context, binID int64) ([]*domain.BinAssignment, error) {
+	query := `
+		SELECT bin_id, sku_id, quantity, is_primary, updated_at
+		FROM inventory_bin_assignment
+		WHERE bin_id = $1
+		ORDER BY sku_id`
+	rows, err := r.db.Query(ctx, query, binID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find bin assignments")
+	}
+	defer rows.Close()
+
+	assignments := make([]*domain.BinAssignment, 0)
+	for rows.Next() {
+		assignment := &domain.BinAssignment{}
+		if err := rows.Scan(&assignment.BinID, &assignment.SKUID, &assignment.Quantity, &assignment.Primary, &assignment.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan bin assignment")
+		}
+		assignments = append(assignments, assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate bin assignments")
+	}
+	return assignments, nil
+}
+
+// FindLocations retrieves the bins the given SKUs are assigned to in a warehouse, by SKU
+func (r *PostgresBinLocationRepository) FindLocations(ctx context.Context, warehouseID string, skuIDs []string) (map[string][]*domain.SKULocation, error) {
+	query := `
+		SELECT b.bin_id, b.warehouse_id, b.code, b.zone, b.aisle, b.bay, b.level, b.capacity, b.active, b.created_at, b.updated_at,
+			a.sku_id, a.quantity, a.is_primary, a.updated_at
+		FROM inventory_bin_assignment a
+		JOIN inventory_bin_location b ON b.bin_id = a.bin_id
+		WHERE b.warehouse_id = $1 AND a.sku_id = ANY($2)
+		ORDER BY a.sku_id, b.code`
+	rows, err := r.db.Query(ctx, query, warehouseID, skuIDs)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find SKU locations")
+	}
+	defer rows.Close()
+
+	// Bins holding several of the SKUs are shared by their locations
+	bins := make(map[int64]*domain.BinLocation)
+	locations := make(map[string][]*domain.SKULocation)
+	for rows.Next() {
+		bin := &domain.BinLocation{}
+		assignment := &domain.BinAssignment{}
+		err := rows.Scan(
+			&bin.ID, &bin.WarehouseID, &bin.Code, &bin.Zone, &bin.Aisle, &bin.Bay, &bin.Level, &bin.Capacity,
+			&bin.Active, &bin.CreatedAt, &bin.UpdatedAt,
+			&assignment.SKUID, &assignment.Quantity, &assignment.Primary, &assignment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan SKU location")
+		}
+		if existing, ok := bins[bin.ID]; ok {
+			bin = existing
+		} else {
+			bins[bin.ID] = bin
+		}
+		assignment.BinID = bin.ID
+		locations[assignment.SKUID] = append(locations[assignment.SKUID], &domain.SKULocation{Bin: bin, Assignment: assignment})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate SKU locations")
+	}
+	return locations, nil
+}
+
+// FindPickLines retrieves the items of the given orders, by order and item
+func (r *PostgresBinLocationRepository) FindPickLines(ctx context.Context, orderIDs []int64) ([]*domain.PickLine, error) {
+	query := `
+		SELECT o.order_id, COALESCE(o.order_number, ''), COALESCE(o.order_status, ''),
+			oi.sku_id::text, COALESCE(oi.name, ''), oi.quantity
+		FROM blc_order o
+		JOIN blc_order_item oi ON oi.order_id = o.order_id
+		WHERE o.order_id = ANY($1) AND oi.sku_id IS NOT NULL AND oi.quantity > 0
+		ORDER BY o.order_id, oi.order_item_id`
+	rows, err := r.db.Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find pick lines")
+	}
+	defer rows.Close()
+
+	lines := make([]*domain.PickLine, 0)
+	for rows.Next() {
+		line := &domain.PickLine{}
+		if err := rows.Scan(&line.OrderID, &line.OrderNumber, &line.OrderStatus, &line.SKUID, &line.SKUName, &line.Quantity); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan pick line")
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate pick lines")
+	}
+	return lines, nil
+}
+
+func scanBinLocation(row pgx.Row) (*domain.BinLocation, error) {
+	bin := &domain.BinLocation{}
+	err := row.Scan(
+		&bin.ID, &bin.WarehouseID, &bin.Code, &bin.Zone, &bin.Aisle, &bin.Bay, &bin.Level, &bin.Capacity,
+		&bin.Active, &bin.CreatedAt, &bin.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return bin, nil
+}
@@ -0,0 +1,236 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresChannelReservationRepository implements the ChannelReservationRepository interface
+type PostgresChannelReservationRepository struct {
+	db *database.DB
+}
+
+// NewPostgresChannelReservationRepository creates a new PostgresChannelReservationRepository
+func NewPostgresChannelReservationRepository(db *database.DB) *PostgresChannelReservationRepository {
+	return &PostgresChannelReservationRepository{db: db}
+}
+
+const channelReservationColumns = `
+	id, channel, reference, sku_id, inventory_id, quantity, status, expires_at, released_at,
+	date_created, date_updated`
+
+// Reserve stores an active reservation and takes its quantity from the level of the SKU with
+// the most stock available. The SKU's levels stay locked until the reservation is stored, so
+// website and channel reservations of the SKU cannot oversell it in between.
+func (r *PostgresChannelReservationRepository) Reserve(ctx context.Context, reservation *domain.ChannelReservation, allocation domain.ChannelAllocation) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT id, qty_available FROM blc_inventory_level
+			WHERE sku_id = $1
+			ORDER BY qty_available DESC, id
+			FOR UPDATE`, reservation.SKUID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to lock inventory levels")
+		}
+		type level struct {
+			id        string
+			available int
+		}
+		var levels []level
+		available := 0
+		for rows.Next() {
+			var l level
+			if err := rows.Scan(&l.id, &l.available); err != nil {
+				rows.Close()
+				return errors.InternalWrap(err, "failed to scan inventory level")
+			}
+			levels = append(levels, l)
+			available += max(l.available, 0)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return errors.InternalWrap(err, "failed to iterate inventory levels")
+		}
+		if len(levels) == 0 {
+			return errors.NotFound(fmt.Sprintf("inventory of SKU %s", reservation.SKUID))
+		}
+
+		var held int
+		err = tx.QueryRow(ctx, `
+			SELECT COALESCE(SUM(quantity), 0) FROM inventory_channel_reservation
+			WHERE channel = $1 AND sku_id = $2 AND status = 'ACTIVE'`,
+			reservation.Channel, reservation.SKUID,
+		).Scan(&held)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to sum channel reservations")
+		}
+		if limit := allocation.Limit(available + held); held+reservation.Quantity > limit {
+			return errors.Conflict(fmt.Sprintf("channel %s allocation of SKU %s exceeded", reservation.Channel, reservation.SKUID)).
+				WithDetail("allocation_limit", limit).
+				WithDetail("reserved", held)
+		}
+		if levels[0].available < reservation.Quantity {
+			return errors.Conflict(fmt.Sprintf("insufficient stock of SKU %s", reservation.SKUID)).
+				WithDetail("available", levels[0].available)
+		}
+		reservation.InventoryID = levels[0].id
+
+		_, err = tx.Exec(ctx, `
+			UPDATE blc_inventory_level SET
+				qty_reserved = qty_reserved + $2,
+				qty_available = qty_available - $2,
+				date_updated = $3
+			WHERE id = $1`,
+			reservation.InventoryID, reservation.Quantity, reservation.CreatedAt,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to reserve inventory")
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO inventory_channel_reservation (
+				id, channel, reference, sku_id, inventory_id, quantity, status, expires_at,
+				date_created, date_updated
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (channel, reference) DO NOTHING
+			RETURNING id`,
+			reservation.ID, reservation.Channel, reservation.Reference, reservation.SKUID,
+			reservation.InventoryID, reservation.Quantity, string(reservation.Status), reservation.ExpiresAt,
+			reservation.CreatedAt, reservation.UpdatedAt,
+		).Scan(&reservation.ID)
+		if err == pgx.ErrNoRows {
+			return errors.Conflict(fmt.Sprintf("channel %s already has a reservation %s", reservation.Channel, reservation.Reference))
+		}
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create channel reservation")
+		}
+		return nil
+	})
+}
+
+// UpdateExpiry saves the expiry of an active reservation
+func (r *PostgresChannelReservationRepository) UpdateExpiry(ctx context.Context, reservation *domain.ChannelReservation) error {
+	query := `
+		UPDATE inventory_channel_reservation SET expires_at = $2, date_updated = $3
+		WHERE id = $1 AND status = 'ACTIVE'`
+	tag, err := r.db.Pool().Exec(ctx, query, reservation.ID, reservation.ExpiresAt, reservation.UpdatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update channel reservation expiry")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.Conflict("channel reservation is no longer active")
+	}
+	return nil
+}
+
+// Release ends an active reservation and returns its stock to its inventory level in one transaction
+func (r *PostgresChannelReservationRepository) Release(ctx context.Context, id string, status domain.ChannelReservationStatus) (bool, error) {
+	released := false
+	err := r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		now := time.Now()
+		var inventoryID string
+		var quantity int
+		err := tx.QueryRow(ctx, `
+			UPDATE inventory_channel_reservation SET status = $2, released_at = $3, date_updated = $3
+			WHERE id = $1 AND status = 'ACTIVE'
+			RETURNING inventory_id, quantity`,
+			id, string(status), now,
+		).Scan(&inventoryID, &quantity)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return errors.InternalWrap(err, "failed to release channel reservation")
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE blc_inventory_level SET
+				qty_reserved = GREATEST(qty_reserved - $2, 0),
+				qty_available = qty_available + $2,
+				date_updated = $3
+			WHERE id = $1`,
+			inventoryID, quantity, now,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to return reserved inventory")
+		}
+		released = true
+		return nil
+	})
+	return released, err
+}
+
+// FindByID retrieves a reservation by ID
+func (r *PostgresChannelReservationRepository) FindByID(ctx context.Context, id string) (*domain.ChannelReservation, error) {
+	query := `SELECT` + channelReservationColumns + ` FROM inventory_channel_reservation WHERE id = $1`
+	reservation, err := scanChannelReservation(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("channel reservation")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find channel reservation")
+	}
+	return reservation, nil
+}
+
+// FindByReference retrieves a reservation of a channel by its reference, nil if none exists
+func (r *PostgresChannelReservationRepository) FindByReference(ctx context.Context, channel, reference string) (*domain.ChannelReservation, error) {
+	query := `SELECT` + channelReservationColumns + ` FROM inventory_channel_reservation WHERE channel = $1 AND reference = $2`
+	reservation, err := scanChannelReservation(r.db.QueryRow(ctx, query, channel, reference))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find channel reservation by reference")
+	}
+	return reservation, nil
+}
+
+// FindExpired lists active reservations whose expiry has passed, oldest first
+func (r *PostgresChannelReservationRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*domain.ChannelReservation, error) {
+	query := `SELECT` + channelReservationColumns + `
+		FROM inventory_channel_reservation
+		WHERE status = 'ACTIVE' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2`
+	rows, err := r.db.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find expired channel reservations")
+	}
+	defer rows.Close()
+
+	reservations := make([]*domain.ChannelReservation, 0)
+	for rows.Next() {
+		reservation, err := scanChannelReservation(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan channel reservation")
+		}
+		reservations = append(reservations, reservation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate channel reservations")
+	}
+	return reservations, nil
+}
+
+func scanChannelReservation(row pgx.Row) (*domain.ChannelReservation, error) {
+	reservation := &domain.ChannelReservation{}
+	var status string
+	err := row.Scan(
+		&reservation.ID, &reservation.Channel, &reservation.Reference, &reservation.SKUID,
+		&reservation.InventoryID, &reservation.Quantity, &status, &reservation.ExpiresAt,
+		&reservation.ReleasedAt, &reservation.CreatedAt, &reservation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	reservation.Status = domain.ChannelReservationStatus(status)
+	return reservation, nil
+}
@@ -0,0 +1,216 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresFlashAllocationRepository implements the FlashAllocationRepository interface
+type PostgresFlashAllocationRepository struct {
+	db *database.DB
+}
+
+// NewPostgresFlashAllocationRepository creates a new PostgresFlashAllocationRepository
+func NewPostgresFlashAllocationRepository(db *database.DB) *PostgresFlashAllocationRepository {
+	return &PostgresFlashAllocationRepository{db: db}
+}
+
+const flashAllocationColumns = `
+	sku_id, status, seeded_quantity, reserved_quantity, started_by, started_at, stopped_at, last_reconciled_at`
+
+// Save creates or updates the flash allocation of a SKU; starting one again replaces the stopped one
+func (r *PostgresFlashAllocationRepository) Save(ctx context.Context, allocation *domain.FlashAllocation) error {
+	query := `
+		INSERT INTO inventory_flash_allocation (` + flashAllocationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (sku_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			seeded_quantity = EXCLUDED.seeded_quantity,
+			reserved_quantity = EXCLUDED.reserved_quantity,
+			started_by = EXCLUDED.started_by,
+			started_at = EXCLUDED.started_at,
+			stopped_at = EXCLUDED.stopped_at,
+			last_reconciled_at = EXCLUDED.last_reconciled_at`
+
+	err := r.db.Exec(ctx, query,
+		allocation.SKUID, string(allocation.Status), allocation.SeededQuantity, allocation.ReservedQuantity,
+		allocation.StartedBy, allocation.StartedAt, allocation.StoppedAt, allocation.LastReconciledAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save flash allocation")
+	}
+	return nil
+}
+
+// FindBySKUID retrieves the flash allocation of a SKU, nil if it never had one
+func (r *PostgresFlashAllocationRepository) FindBySKUID(ctx context.Context, skuID string) (*domain.FlashAllocation, error) {
+	query := `SELECT ` + flashAllocationColumns + ` FROM inventory_flash_allocation WHERE sku_id = $1`
+	allocation, err := scanFlashAllocation(r.db.QueryRow(ctx, query, skuID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find flash allocation")
+	}
+	return allocation, nil
+}
+
+// FindAll lists flash allocations, most recently started first; activeOnly leaves out stopped ones
+func (r *PostgresFlashAllocationRepository) FindAll(ctx context.Context, activeOnly bool) ([]*domain.FlashAllocation, error) {
+	query := `
+		SELECT ` + flashAllocationColumns + `
+		FROM inventory_flash_allocation
+		WHERE (NOT $1 OR status = 'ACTIVE')
+		ORDER BY started_at DESC, sku_id`
+	rows, err := r.db.Query(ctx, query, activeOnly)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find flash allocations")
+	}
+	defer rows.Close()
+
+	allocations := make([]*domain.FlashAllocation, 0)
+	for rows.Next() {
+		allocation, err := scanFlashAllocation(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan flash allocation")
+		}
+		allocations = append(allocations, allocation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate flash allocations")
+	}
+	return allocations, nil
+}
+
+// SumAvailable returns the stock available of a SKU across its inventory levels
+func (r *PostgresFlashAllocationRepository) SumAvailable(ctx context.Context, skuID string) (int, error) {
+	var available int
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(SUM(GREATEST(qty_available, 0)), 0) FROM blc_inventory_level WHERE sku_id = $1`,
+		skuID,
+	).Scan(&available)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to sum available stock")
+	}
+	return available, nil
+}
+
+// ApplyReconciliation applies the reserved delta of a reconciliation to the inventory levels of
+// its SKU: reservations go to the level with the most stock available and releases to the level
+// with the most reserved, as the website reserves without picking a warehouse
+func (r *PostgresFlashAllocationRepository) ApplyReconciliation(ctx context.Context, allocation *domain.FlashAllocation, reconciliation *domain.FlashReconciliation) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if reconciliation.ReservedDelta != 0 {
+			order := "qty_available DESC"
+			if reconciliation.ReservedDelta < 0 {
+				order = "qty_reserved DESC"
+			}
+			tag, err := tx.Exec(ctx, `
+				UPDATE blc_inventory_level SET
+					qty_reserved = GREATEST(qty_reserved + $2, 0),
+					qty_available = GREATEST(qty_on_hand - GREATEST(qty_reserved + $2, 0) - qty_allocated, 0),
+					date_updated = $3
+				WHERE id = (
+					SELECT id FROM blc_inventory_level WHERE sku_id = $1
+					ORDER BY `+order+`, id
+					LIMIT 1
+					FOR UPDATE
+				)`,
+				reconciliation.SKUID, reconciliation.ReservedDelta, reconciliation.CreatedAt,
+			)
+			if err != nil {
+				return errors.InternalWrap(err, "failed to apply flash reservations")
+			}
+			if tag.RowsAffected() == 0 {
+				return errors.NotFound("inventory of SKU " + reconciliation.SKUID)
+			}
+		}
+
+		err := tx.QueryRow(ctx, `
+			SELECT COALESCE(SUM(GREATEST(qty_available, 0)), 0) FROM blc_inventory_level WHERE sku_id = $1`,
+			reconciliation.SKUID,
+		).Scan(&reconciliation.AvailableAfter)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to sum available stock")
+		}
+
+		if !reconciliation.IsNoop() {
+			err = tx.QueryRow(ctx, `
+				INSERT INTO inventory_flash_reconciliation (sku_id, reserved_delta, tokens_before, available_after, drift, date_created)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id`,
+				reconciliation.SKUID, reconciliation.ReservedDelta, reconciliation.TokensBefore,
+				reconciliation.AvailableAfter, reconciliation.Drift(), reconciliation.CreatedAt,
+			).Scan(&reconciliation.ID)
+			if err != nil {
+				return errors.InternalWrap(err, "failed to record flash reconciliation")
+			}
+		}
+
+		allocation.Reconciled(reconciliation)
+		_, err = tx.Exec(ctx, `
+			UPDATE inventory_flash_allocation SET reserved_quantity = $2, last_reconciled_at = $3
+			WHERE sku_id = $1`,
+			allocation.SKUID, allocation.ReservedQuantity, allocation.LastReconciledAt,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to update flash allocation")
+		}
+		return nil
+	})
+}
+
+// FindReconciliations lists the latest reconciliations of a SKU, newest first
+func (r *PostgresFlashAllocationRepository) FindReconciliations(ctx context.Context, skuID string, limit int) ([]*domain.FlashReconciliation, error) {
+	query := `
+		SELECT id, sku_id, reserved_delta, tokens_before, available_after, date_created
+		FROM inventory_flash_reconciliation
+		WHERE sku_id = $1
+		ORDER BY date_created DESC, id DESC
+		LIMIT $2`
+	rows, err := r.db.Query(ctx, query, skuID, limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find flash reconciliations")
+	}
+	defer rows.Close()
+
+	reconciliations := make([]*domain.FlashReconciliation, 0)
+	for rows.Next() {
+		rec := &domain.FlashReconciliation{}
+		if err := rows.Scan(&rec.ID, &rec.SKUID, &rec.ReservedDelta, &rec.TokensBefore, &rec.AvailableAfter, &rec.CreatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan flash reconciliation")
+		}
+		reconciliations = append(reconciliations, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate flash reconciliations")
+	}
+	return reconciliations, nil
+}
+
+func scanFlashAllocation(row pgx.Row) (*domain.FlashAllocation, error) {
+	allocation := &domain.FlashAllocation{}
+	var status string
+	var stoppedAt, lastReconciledAt sql.NullTime
+	err := row.Scan(
+		&allocation.SKUID, &status, &allocation.SeededQuantity, &allocation.ReservedQuantity,
+		&allocation.StartedBy, &allocation.StartedAt, &stoppedAt, &lastReconciledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	allocation.Status = domain.FlashAllocationStatus(status)
+	if stoppedAt.Valid {
+		allocation.StoppedAt = &stoppedAt.Time
+	}
+	if lastReconciledAt.Valid {
+		allocation.LastReconciledAt = &lastReconciledAt.Time
+	}
+	return allocation, nil
+}
@@ -0,0 +1,125 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresInboundReceiptRepository implements the InboundReceiptRepository interface
+type PostgresInboundReceiptRepository struct {
+	db *database.DB
+}
+
+// NewPostgresInboundReceiptRepository creates a new PostgresInboundReceiptRepository
+func NewPostgresInboundReceiptRepository(db *database.DB) *PostgresInboundReceiptRepository {
+	return &PostgresInboundReceiptRepository{db: db}
+}
+
+const inboundReceiptColumns = `
+	id, sku_id, warehouse_id, quantity, expected_at, reference, status,
+	received_at, date_created, date_updated`
+
+// Save stores a new receipt or updates an existing one.
+func (r *PostgresInboundReceiptRepository) Save(ctx context.Context, receipt *domain.InboundReceipt) error {
+	query := `
+		INSERT INTO inventory_inbound_receipt (` + inboundReceiptColumns + `)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			warehouse_id = EXCLUDED.warehouse_id,
+			quantity = EXCLUDED.quantity,
+			expected_at = EXCLUDED.expected_at,
+			reference = EXCLUDED.reference,
+			status = EXCLUDED.status,
+			received_at = EXCLUDED.received_at,
+			date_updated = EXCLUDED.date_updated`
+
+	err := r.db.Exec(ctx, query,
+		receipt.ID,
+		receipt.SKUID,
+		receipt.WarehouseID,
+		receipt.Quantity,
+		receipt.ExpectedAt,
+		receipt.Reference,
+		string(receipt.Status),
+		receipt.ReceivedAt,
+		receipt.CreatedAt,
+		receipt.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save inbound receipt")
+	}
+	return nil
+}
+
+// FindByID retrieves a receipt by its unique identifier.
+func (r *PostgresInboundReceiptRepository) FindByID(ctx context.Context, id string) (*domain.InboundReceipt, error) {
+	query := `SELECT ` + inboundReceiptColumns + ` FROM inventory_inbound_receipt WHERE id = $1`
+
+	receipt, err := scanInboundReceipt(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find inbound receipt by ID")
+	}
+	return receipt, nil
+}
+
+// FindExpectedBySKUIDs retrieves the receipts of several SKUs still expected by the given time.
+func (r *PostgresInboundReceiptRepository) FindExpectedBySKUIDs(ctx context.Context, skuIDs []string, expectedBy time.Time) ([]*domain.InboundReceipt, error) {
+	query := `SELECT ` + inboundReceiptColumns + `
+		FROM inventory_inbound_receipt
+		WHERE sku_id = ANY($1) AND status = $2 AND expected_at <= $3
+		ORDER BY expected_at`
+
+	rows, err := r.db.Query(ctx, query, skuIDs, string(domain.InboundReceiptStatusExpected), expectedBy)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find expected inbound receipts")
+	}
+	defer rows.Close()
+
+	var receipts []*domain.InboundReceipt
+	for rows.Next() {
+		receipt, err := scanInboundReceipt(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan inbound receipt")
+		}
+		receipts = append(receipts, receipt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate inbound receipts")
+	}
+	return receipts, nil
+}
+
+func scanInboundReceipt(row pgx.Row) (*domain.InboundReceipt, error) {
+	receipt := &domain.InboundReceipt{}
+	var status string
+	var reference *string
+	err := row.Scan(
+		&receipt.ID,
+		&receipt.SKUID,
+		&receipt.WarehouseID,
+		&receipt.Quantity,
+		&receipt.ExpectedAt,
+		&reference,
+		&status,
+		&receipt.ReceivedAt,
+		&receipt.CreatedAt,
+		&receipt.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Status = domain.InboundReceiptStatus(status)
+	if reference != nil {
+		receipt.Reference = *reference
+	}
+	return receipt, nil
+}
@@ -0,0 +1,169 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresInventoryAdjustmentRepository implements the InventoryAdjustmentRepository interface
+type PostgresInventoryAdjustmentRepository struct {
+	db *database.DB
+}
+
+// NewPostgresInventoryAdjustmentRepository creates a new PostgresInventoryAdjustmentRepository
+func NewPostgresInventoryAdjustmentRepository(db *database.DB) *PostgresInventoryAdjustmentRepository {
+	return &PostgresInventoryAdjustmentRepository{db: db}
+}
+
+// SaveWithLevels upserts the adjusted levels and appends their adjustments in one transaction.
+// Available quantities move by the change on hand at write time, so reservations made since
+// the levels were read are kept.
+func (r *PostgresInventoryAdjustmentRepository) SaveWithLevels(ctx context.Context, levels []*domain.InventoryLevel, adjustments []*domain.InventoryAdjustment) error {
+	batch := &pgx.Batch{}
+	queueLevelsWithAdjustments(batch, levels, adjustments)
+
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return errors.InternalWrap(err, "failed to save inventory adjustments")
+		}
+		return nil
+	})
+}
+
+// queueLevelsWithAdjustments queues the upserts of adjusted levels and the inserts of their
+// ledger entries. Damaged units are set as read, like those on hand, so it suits stock counts
+// only; stock received or written off is added with queueReturnStockChanges.
+func queueLevelsWithAdjustments(batch *pgx.Batch, levels []*domain.InventoryLevel, adjustments []*domain.InventoryAdjustment) {
+	for _, level := range levels {
+		batch.Queue(`
+			INSERT INTO blc_inventory_level (
+				id, sku_id, warehouse_id, location_id, qty_on_hand, qty_reserved,
+				qty_available, qty_allocated, qty_backordered, qty_in_transit,
+				qty_damaged, reorder_point, reorder_qty, safety_stock,
+				allow_backorder, allow_preorder, last_count_date,
+				date_created, date_updated
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			ON CONFLICT (id) DO UPDATE SET
+				qty_available = blc_inventory_level.qty_available + (EXCLUDED.qty_on_hand - blc_inventory_level.qty_on_hand),
+				qty_on_hand = EXCLUDED.qty_on_hand,
+				qty_damaged = EXCLUDED.qty_damaged,
+				last_count_date = EXCLUDED.last_count_date,
+				date_updated = EXCLUDED.date_updated`,
+			level.ID,
+			level.SKUID,
+			level.WarehouseID,
+			level.LocationID,
+			level.QuantityOnHand,
+			level.QuantityReserved,
+			level.QuantityAvailable,
+			level.QuantityAllocated,
+			level.QuantityBackordered,
+			level.QuantityInTransit,
+			level.QuantityDamaged,
+			level.ReorderPoint,
+			level.ReorderQuantity,
+			level.SafetyStock,
+			level.AllowBackorder,
+			level.AllowPreorder,
+			level.LastCountDate,
+			level.CreatedAt,
+			level.UpdatedAt,
+		)
+	}
+	queueAdjustments(batch, adjustments)
+}
+
+// queueAdjustments queues the inserts of ledger entries
+func queueAdjustments(batch *pgx.Batch, adjustments []*domain.InventoryAdjustment) {
+	for _, adjustment := range adjustments {
+		batch.Queue(`
+			INSERT INTO blc_inventory_adjustment (
+				id, inventory_id, sku_id, warehouse_id, stock, qty_before, qty_after,
+				reason, reference, actor_id, date_created
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			adjustment.ID,
+			adjustment.InventoryID,
+			adjustment.SKUID,
+			adjustment.WarehouseID,
+			adjustment.Stock,
+			adjustment.QuantityBefore,
+			adjustment.QuantityAfter,
+			adjustment.Reason,
+			adjustment.Reference,
+			adjustment.ActorID,
+			adjustment.CreatedAt,
+		)
+	}
+}
+
+// FindBySKUID retrieves the latest adjustments of a SKU, newest first.
+func (r *PostgresInventoryAdjustmentRepository) FindBySKUID(ctx context.Context, skuID string, limit int) ([]*domain.InventoryAdjustment, error) {
+	query := `
+		SELECT id, inventory_id, sku_id, warehouse_id, stock, qty_before, qty_after,
+			   reason, reference, actor_id, date_created
+		FROM blc_inventory_adjustment
+		WHERE sku_id = $1
+		ORDER BY date_created DESC
+		LIMIT $2`
+
+	rows, err := r.db.Query(ctx, query, skuID, limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find inventory adjustments by SKU ID")
+	}
+	return scanInventoryAdjustments(rows)
+}
+
+// FindByReference retrieves the adjustments made by one operation.
+func (r *PostgresInventoryAdjustmentRepository) FindByReference(ctx context.Context, reference string) ([]*domain.InventoryAdjustment, error) {
+	query := `
+		SELECT id, inventory_id, sku_id, warehouse_id, stock, qty_before, qty_after,
+			   reason, reference, actor_id, date_created
+		FROM blc_inventory_adjustment
+		WHERE reference = $1
+		ORDER BY sku_id, warehouse_id`
+
+	rows, err := r.db.Query(ctx, query, reference)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find inventory adjustments by reference")
+	}
+	return scanInventoryAdjustments(rows)
+}
+
+func scanInventoryAdjustments(rows pgx.Rows) ([]*domain.InventoryAdjustment, error) {
+	defer rows.Close()
+
+	adjustments := make([]*domain.InventoryAdjustment, 0)
+	for rows.Next() {
+		adjustment := &domain.InventoryAdjustment{}
+		var warehouseID sql.NullString
+		if err := rows.Scan(
+			&adjustment.ID,
+			&adjustment.InventoryID,
+			&adjustment.SKUID,
+			&warehouseID,
+			&adjustment.Stock,
+			&adjustment.QuantityBefore,
+			&adjustment.QuantityAfter,
+			&adjustment.Reason,
+			&adjustment.Reference,
+			&adjustment.ActorID,
+			&adjustment.CreatedAt,
+		); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan inventory adjustment")
+		}
+		if warehouseID.Valid {
+			adjustment.WarehouseID = &warehouseID.String
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate inventory adjustments")
+	}
+	return adjustments, nil
+}
@@ -0,0 +1,99 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresInventoryExportRepository implements the InventoryExportRepository interface
+type PostgresInventoryExportRepository struct {
+	db *database.DB
+}
+
+// NewPostgresInventoryExportRepository creates a new PostgresInventoryExportRepository
+func NewPostgresInventoryExportRepository(db *database.DB) *PostgresInventoryExportRepository {
+	return &PostgresInventoryExportRepository{db: db}
+}
+
+// StreamLevels calls fn for each inventory level as rows arrive from the database
+func (r *PostgresInventoryExportRepository) StreamLevels(ctx context.Context, warehouseID string, fn func(*domain.InventoryExportRow) error) error {
+	query := `
+		SELECT sku_id, COALESCE(warehouse_id, ''), qty_on_hand, qty_reserved, qty_allocated,
+			   qty_available, qty_in_transit, qty_damaged, last_count_date, date_updated
+		FROM blc_inventory_level
+		WHERE $1 = '' OR warehouse_id = $1
+		ORDER BY warehouse_id, sku_id`
+
+	rows, err := r.db.Query(ctx, query, warehouseID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to export inventory levels")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &domain.InventoryExportRow{}
+		var lastCountDate sql.NullTime
+		if err := rows.Scan(
+			&row.SKUID, &row.WarehouseID, &row.QuantityOnHand, &row.QuantityReserved, &row.QuantityAllocated,
+			&row.QuantityAvailable, &row.QuantityInTransit, &row.QuantityDamaged, &lastCountDate, &row.UpdatedAt,
+		); err != nil {
+			return errors.InternalWrap(err, "failed to scan exported inventory level")
+		}
+		if lastCountDate.Valid {
+			row.LastCountDate = &lastCountDate.Time
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to iterate exported inventory levels")
+	}
+	return nil
+}
+
+// StreamLowStock calls fn for each inventory level at or below its reorder point, counting
+// stock in transit like InventoryLevel.NeedsReorder
+func (r *PostgresInventoryExportRepository) StreamLowStock(ctx context.Context, warehouseID string, fn func(*domain.InventoryExportRow) error) error {
+	query := `
+		SELECT sku_id, COALESCE(warehouse_id, ''), qty_on_hand, qty_reserved, qty_allocated,
+			   qty_available, qty_in_transit, qty_damaged, reorder_point, reorder_qty,
+			   last_count_date, date_updated
+		FROM blc_inventory_level
+		WHERE ($1 = '' OR warehouse_id = $1)
+			AND reorder_point > 0
+			AND qty_on_hand + qty_in_transit <= reorder_point
+		ORDER BY warehouse_id, sku_id`
+
+	rows, err := r.db.Query(ctx, query, warehouseID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to export low stock levels")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &domain.InventoryExportRow{}
+		var lastCountDate sql.NullTime
+		if err := rows.Scan(
+			&row.SKUID, &row.WarehouseID, &row.QuantityOnHand, &row.QuantityReserved, &row.QuantityAllocated,
+			&row.QuantityAvailable, &row.QuantityInTransit, &row.QuantityDamaged, &row.ReorderPoint, &row.ReorderQuantity,
+			&lastCountDate, &row.UpdatedAt,
+		); err != nil {
+			return errors.InternalWrap(err, "failed to scan low stock level")
+		}
+		if lastCountDate.Valid {
+			row.LastCountDate = &lastCountDate.Time
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to iterate low stock levels")
+	}
+	return nil
+}
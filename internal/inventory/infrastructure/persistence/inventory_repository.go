@@ -300,6 +300,76 @@ func (r *PostgresInventoryRepository) FindByWarehouse(ctx context.Context, wareh
 	return levels, nil
 }
 
+// FindBySKUIDs retrieves the inventory levels of several SKUs across all warehouses.
+func (r *PostgresInventoryRepository) FindBySKUIDs(ctx context.Context, skuIDs []string) ([]*domain.InventoryLevel, error) {
+	query := `
+		SELECT
+			id, sku_id, warehouse_id, location_id, qty_on_hand, qty_reserved,
+			qty_available, qty_allocated, qty_backordered, qty_in_transit,
+			qty_damaged, reorder_point, reorder_qty, safety_stock,
+			allow_backorder, allow_preorder, last_count_date,
+			date_created, date_updated
+		FROM blc_inventory_level
+		WHERE sku_id = ANY($1)`
+
+	rows, err := r.db.Query(ctx, query, skuIDs)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find inventory levels by SKU IDs")
+	}
+	defer rows.Close()
+
+	var levels []*domain.InventoryLevel
+	for rows.Next() {
+		level := &domain.InventoryLevel{}
+		var (
+			whID          sql.NullString
+			locID         sql.NullString
+			lastCountDate sql.NullTime
+		)
+
+		err := rows.Scan(
+			&level.ID,
+			&level.SKUID,
+			&whID,
+			&locID,
+			&level.QuantityOnHand,
+			&level.QuantityReserved,
+			&level.QuantityAvailable,
+			&level.QuantityAllocated,
+			&level.QuantityBackordered,
+			&level.QuantityInTransit,
+			&level.QuantityDamaged,
+			&level.ReorderPoint,
+			&level.ReorderQuantity,
+			&level.SafetyStock,
+			&level.AllowBackorder,
+			&level.AllowPreorder,
+			&lastCountDate,
+			&level.CreatedAt,
+			&level.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan inventory level")
+		}
+
+		if whID.Valid {
+			level.WarehouseID = &whID.String
+		}
+		if locID.Valid {
+			level.LocationID = &locID.String
+		}
+		if lastCountDate.Valid {
+			level.LastCountDate = &lastCountDate.Time
+		}
+		levels = append(levels, level)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate inventory levels")
+	}
+
+	return levels, nil
+}
+
 // Delete removes an inventory level by its unique identifier.
 func (r *PostgresInventoryRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM blc_inventory_level WHERE id = $1`
@@ -312,3 +382,21 @@ func (r *PostgresInventoryRepository) Delete(ctx context.Context, id string) err
 	}
 	return nil
 }
+
+// FindMissingSKUIDs returns the given SKU IDs that have no SKU in the catalog.
+func (r *PostgresInventoryRepository) FindMissingSKUIDs(ctx context.Context, skuIDs []string) ([]string, error) {
+	query := `
+		SELECT ids.sku_id
+		FROM unnest($1::text[]) AS ids(sku_id)
+		WHERE NOT EXISTS (SELECT 1 FROM blc_sku s WHERE s.sku_id::text = ids.sku_id)`
+
+	rows, err := r.db.Query(ctx, query, skuIDs)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to check SKU IDs")
+	}
+	missing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to scan missing SKU IDs")
+	}
+	return missing, nil
+}
@@ -0,0 +1,263 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresReturnReceiptRepository implements the ReturnReceiptRepository interface
+type PostgresReturnReceiptRepository struct {
+	db *database.DB
+}
+
+// NewPostgresReturnReceiptRepository creates a new PostgresReturnReceiptRepository
+func NewPostgresReturnReceiptRepository(db *database.DB) *PostgresReturnReceiptRepository {
+	return &PostgresReturnReceiptRepository{db: db}
+}
+
+const returnReceiptColumns = `r.id, r.reference, r.order_id, r.warehouse_id, r.received_by, r.received_at`
+
+const returnLineColumns = `
+	l.id, l.receipt_id, l.sku_id, l.quantity, l.disposition, l.reason, l.quarantined,
+	l.resolved_by, l.resolved_at, l.date_created`
+
+// SaveWithAdjustments stores the receipt and its lines with the stock changes and ledger
+// entries the dispositions posted, in one transaction. Lines already stored only change
+// disposition.
+func (r *PostgresReturnReceiptRepository) SaveWithAdjustments(ctx context.Context, receipt *domain.ReturnReceipt, changes []*domain.ReturnStockChange, adjustments []*domain.InventoryAdjustment) error {
+	batch := &pgx.Batch{}
+	batch.Queue(`
+		INSERT INTO inventory_return_receipt (id, reference, order_id, warehouse_id, received_by, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO NOTHING`,
+		receipt.ID, receipt.Reference, receipt.OrderID, receipt.WarehouseID, receipt.ReceivedBy, receipt.ReceivedAt,
+	)
+	for _, line := range receipt.Lines {
+		batch.Queue(`
+			INSERT INTO inventory_return_line (
+				id, receipt_id, sku_id, quantity, disposition, reason, quarantined,
+				resolved_by, resolved_at, date_created
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (id) DO UPDATE SET
+				disposition = EXCLUDED.disposition,
+				resolved_by = EXCLUDED.resolved_by,
+				resolved_at = EXCLUDED.resolved_at`,
+			line.ID, receipt.ID, line.SKUID, line.Quantity, string(line.Disposition), line.Reason, line.Quarantined,
+			line.ResolvedBy, line.ResolvedAt, line.CreatedAt,
+		)
+	}
+	queueReturnStockChanges(batch, changes)
+	queueAdjustments(batch, adjustments)
+
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return errors.InternalWrap(err, "failed to save return receipt")
+		}
+		return nil
+	})
+}
+
+// queueReturnStockChanges queues the upserts of the levels a return posted to. Stored levels
+// are moved by the units posted rather than set as read, so stock reserved, sold or counted
+// since then is kept.
+func queueReturnStockChanges(batch *pgx.Batch, changes []*domain.ReturnStockChange) {
+	for _, change := range changes {
+		level := change.Level
+		batch.Queue(`
+			INSERT INTO blc_inventory_level (
+				id, sku_id, warehouse_id, location_id, qty_on_hand, qty_reserved,
+				qty_available, qty_allocated, qty_backordered, qty_in_transit,
+				qty_damaged, reorder_point, reorder_qty, safety_stock,
+				allow_backorder, allow_preorder, last_count_date,
+				date_created, date_updated
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			ON CONFLICT (id) DO UPDATE SET
+				qty_on_hand = blc_inventory_level.qty_on_hand + $20,
+				qty_available = blc_inventory_level.qty_available + $20,
+				qty_damaged = blc_inventory_level.qty_damaged + $21,
+				date_updated = EXCLUDED.date_updated`,
+			level.ID,
+			level.SKUID,
+			level.WarehouseID,
+			level.LocationID,
+			level.QuantityOnHand,
+			level.QuantityReserved,
+			level.QuantityAvailable,
+			level.QuantityAllocated,
+			level.QuantityBackordered,
+			level.QuantityInTransit,
+			level.QuantityDamaged,
+			level.ReorderPoint,
+			level.ReorderQuantity,
+			level.SafetyStock,
+			level.AllowBackorder,
+			level.AllowPreorder,
+			level.LastCountDate,
+			level.CreatedAt,
+			level.UpdatedAt,
+			change.OnHand,
+			change.Damaged,
+		)
+	}
+}
+
+// FindByID retrieves a receipt with its lines, nil when there is none.
+func (r *PostgresReturnReceiptRepository) FindByID(ctx context.Context, id string) (*domain.ReturnReceipt, error) {
+	receipts, err := r.findReceipts(ctx, `SELECT `+returnReceiptColumns+` FROM inventory_return_receipt r WHERE r.id = $1`, id)
+	if err != nil || len(receipts) == 0 {
+		return nil, err
+	}
+	return receipts[0], nil
+}
+
+// FindByLineID retrieves the receipt of a line, nil when there is none.
+func (r *PostgresReturnReceiptRepository) FindByLineID(ctx context.Context, lineID string) (*domain.ReturnReceipt, error) {
+	query := `SELECT ` + returnReceiptColumns + `
+		FROM inventory_return_receipt r
+		JOIN inventory_return_line l ON l.receipt_id = r.id
+		WHERE l.id = $1`
+	receipts, err := r.findReceipts(ctx, query, lineID)
+	if err != nil || len(receipts) == 0 {
+		return nil, err
+	}
+	return receipts[0], nil
+}
+
+// FindQuarantined lists the receipts with quarantined lines, oldest first.
+func (r *PostgresReturnReceiptRepository) FindQuarantined(ctx context.Context, limit int) ([]*domain.ReturnReceipt, error) {
+	query := `SELECT ` + returnReceiptColumns + `
+		FROM inventory_return_receipt r
+		WHERE EXISTS (
+			SELECT 1 FROM inventory_return_line l
+			WHERE l.receipt_id = r.id AND l.disposition = $1
+		)
+		ORDER BY r.received_at, r.id
+		LIMIT $2`
+	return r.findReceipts(ctx, query, string(domain.ReturnDispositionQuarantine), limit)
+}
+
+// SumQuarantined returns the units of a SKU held in quarantine at a warehouse.
+func (r *PostgresReturnReceiptRepository) SumQuarantined(ctx context.Context, skuID string, warehouseID *string) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(l.quantity), 0)
+		FROM inventory_return_line l
+		JOIN inventory_return_receipt r ON r.id = l.receipt_id
+		WHERE l.sku_id = $1 AND l.disposition = $2 AND r.warehouse_id IS NOT DISTINCT FROM $3`
+
+	var quantity int
+	if err := r.db.QueryRow(ctx, query, skuID, string(domain.ReturnDispositionQuarantine), warehouseID).Scan(&quantity); err != nil {
+		return 0, errors.InternalWrap(err, "failed to sum quarantined returns")
+	}
+	return quantity, nil
+}
+
+// FindStockRates counts the units returned between from and to by SKU and disposition.
+func (r *PostgresReturnReceiptRepository) FindStockRates(ctx context.Context, from, to time.Time) ([]*domain.ReturnStockRate, error) {
+	query := `
+		SELECT l.sku_id,
+			SUM(l.quantity),
+			COALESCE(SUM(l.quantity) FILTER (WHERE l.disposition = 'RESTOCK'), 0),
+			COALESCE(SUM(l.quantity) FILTER (WHERE l.disposition = 'DAMAGED'), 0),
+			COALESCE(SUM(l.quantity) FILTER (WHERE l.disposition = 'QUARANTINE'), 0)
+		FROM inventory_return_line l
+		JOIN inventory_return_receipt r ON r.id = l.receipt_id
+		WHERE r.received_at >= $1 AND r.received_at < $2
+		GROUP BY l.sku_id
+		ORDER BY SUM(l.quantity) DESC, l.sku_id`
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find return stock rates")
+	}
+	defer rows.Close()
+
+	rates := make([]*domain.ReturnStockRate, 0)
+	for rows.Next() {
+		rate := &domain.ReturnStockRate{}
+		if err := rows.Scan(&rate.SKUID, &rate.Returned, &rate.Restocked, &rate.Damaged, &rate.Quarantined); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan return stock rate")
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate return stock rates")
+	}
+	return rates, nil
+}
+
+// findReceipts retrieves receipts and then their lines
+func (r *PostgresReturnReceiptRepository) findReceipts(ctx context.Context, query string, args ...interface{}) ([]*domain.ReturnReceipt, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find return receipts")
+	}
+	receipts := make([]*domain.ReturnReceipt, 0)
+	byID := make(map[string]*domain.ReturnReceipt)
+	for rows.Next() {
+		receipt := &domain.ReturnReceipt{Lines: []*domain.ReturnLine{}}
+		var orderID sql.NullInt64
+		var warehouseID sql.NullString
+		if err := rows.Scan(&receipt.ID, &receipt.Reference, &orderID, &warehouseID, &receipt.ReceivedBy, &receipt.ReceivedAt); err != nil {
+			rows.Close()
+			return nil, errors.InternalWrap(err, "failed to scan return receipt")
+		}
+		if orderID.Valid {
+			receipt.OrderID = &orderID.Int64
+		}
+		if warehouseID.Valid {
+			receipt.WarehouseID = &warehouseID.String
+		}
+		receipts = append(receipts, receipt)
+		byID[receipt.ID] = receipt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate return receipts")
+	}
+	if len(receipts) == 0 {
+		return receipts, nil
+	}
+
+	ids := make([]string, len(receipts))
+	for i, receipt := range receipts {
+		ids[i] = receipt.ID
+	}
+	lineRows, err := r.db.Query(ctx, `SELECT `+returnLineColumns+`
+		FROM inventory_return_line l
+		WHERE l.receipt_id = ANY($1)
+		ORDER BY l.date_created, l.id`, ids)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find return lines")
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		line := &domain.ReturnLine{}
+		var disposition string
+		var resolvedAt sql.NullTime
+		if err := lineRows.Scan(
+			&line.ID, &line.ReceiptID, &line.SKUID, &line.Quantity, &disposition, &line.Reason, &line.Quarantined,
+			&line.ResolvedBy, &resolvedAt, &line.CreatedAt,
+		); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan return line")
+		}
+		line.Disposition = domain.ReturnDisposition(disposition)
+		if resolvedAt.Valid {
+			line.ResolvedAt = &resolvedAt.Time
+		}
+		if receipt, ok := byID[line.ReceiptID]; ok {
+			receipt.Lines = append(receipt.Lines, line)
+		}
+	}
+	if err := lineRows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate return lines")
+	}
+	return receipts, nil
+}
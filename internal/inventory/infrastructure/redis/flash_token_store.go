@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Each SKU in flash allocation mode has a tokens key, the stock left to reserve, and a
+// pending key, the quantity reserved since the last reconciliation. Scripts keep the two
+// consistent across every instance.
+var (
+	takeScript = goredis.NewScript(`
+local tokens = redis.call('GET', KEYS[1])
+if not tokens then return {0, 0, 0} end
+tokens = tonumber(tokens)
+local quantity = tonumber(ARGV[1])
+if tokens < quantity then return {0, tokens, 1} end
+redis.call('DECRBY', KEYS[1], quantity)
+redis.call('INCRBY', KEYS[2], quantity)
+return {1, tokens - quantity, 1}`)
+
+	returnScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then return 0 end
+redis.call('INCRBY', KEYS[1], ARGV[1])
+redis.call('DECRBY', KEYS[2], ARGV[1])
+return 1`)
+
+	drainScript = goredis.NewScript(`
+local pending = tonumber(redis.call('GETSET', KEYS[2], 0) or '0')
+local tokens = tonumber(redis.call('GET', KEYS[1]) or '0')
+return {pending, tokens}`)
+
+	adjustScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then return 0 end
+redis.call('INCRBY', KEYS[1], ARGV[1])
+return 1`)
+)
+
+// FlashTokenStore implements the FlashTokenStore interface with Redis counters
+type FlashTokenStore struct {
+	client *goredis.Client
+	prefix string
+}
+
+// NewFlashTokenStore creates a new FlashTokenStore. Every instance reserving flash-sale
+// stock must use the same prefix.
+func NewFlashTokenStore(client *goredis.Client, prefix string) *FlashTokenStore {
+	return &FlashTokenStore{client: client, prefix: prefix}
+}
+
+// Seed starts the counter of a SKU with tokens and no pending reservations
+func (s *FlashTokenStore) Seed(ctx context.Context, skuID string, tokens int) error {
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.tokensKey(skuID), tokens, 0)
+	pipe.Set(ctx, s.pendingKey(skuID), 0, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to seed flash tokens of SKU %s: %w", skuID, err)
+	}
+	return nil
+}
+
+// Take takes quantity tokens of a SKU if enough are left and adds them to its pending reservations
+func (s *FlashTokenStore) Take(ctx context.Context, skuID string, quantity int) (bool, int, bool, error) {
+	result, err := takeScript.Run(ctx, s.client, s.keys(skuID), quantity).Int64Slice()
+	if err != nil {
+		return false, 0, false, fmt.Errorf("failed to take flash tokens of SKU %s: %w", skuID, err)
+	}
+	return result[0] == 1, int(result[1]), result[2] == 1, nil
+}
+
+// Return gives quantity tokens of a SKU back and removes them from its pending reservations
+func (s *FlashTokenStore) Return(ctx context.Context, skuID string, quantity int) (bool, error) {
+	returned, err := returnScript.Run(ctx, s.client, s.keys(skuID), quantity).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to return flash tokens of SKU %s: %w", skuID, err)
+	}
+	return returned == 1, nil
+}
+
+// Drain resets the pending reservations of a SKU, returning them with the tokens left
+func (s *FlashTokenStore) Drain(ctx context.Context, skuID string) (int, int, error) {
+	result, err := drainScript.Run(ctx, s.client, s.keys(skuID)).Int64Slice()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to drain flash reservations of SKU %s: %w", skuID, err)
+	}
+	return int(result[0]), int(result[1]), nil
+}
+
+// Requeue adds pending reservations back after a reconciliation failed to apply them
+func (s *FlashTokenStore) Requeue(ctx context.Context, skuID string, pending int) error {
+	if err := s.client.IncrBy(ctx, s.pendingKey(skuID), int64(pending)).Err(); err != nil {
+		return fmt.Errorf("failed to requeue flash reservations of SKU %s: %w", skuID, err)
+	}
+	return nil
+}
+
+// Adjust adds delta tokens to the counter of a SKU, if it still has one
+func (s *FlashTokenStore) Adjust(ctx context.Context, skuID string, delta int) error {
+	if err := adjustScript.Run(ctx, s.client, s.keys(skuID)[:1], delta).Err(); err != nil {
+		return fmt.Errorf("failed to adjust flash tokens of SKU %s: %w", skuID, err)
+	}
+	return nil
+}
+
+// Clear removes the counter of a SKU so it stops taking reservations
+func (s *FlashTokenStore) Clear(ctx context.Context, skuID string) error {
+	if err := s.client.Del(ctx, s.tokensKey(skuID)).Err(); err != nil {
+		return fmt.Errorf("failed to clear flash tokens of SKU %s: %w", skuID, err)
+	}
+	return nil
+}
+
+// Tokens returns the tokens left of a SKU
+func (s *FlashTokenStore) Tokens(ctx context.Context, skuID string) (int, bool, error) {
+	tokens, err := s.client.Get(ctx, s.tokensKey(skuID)).Int()
+	if err == goredis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read flash tokens of SKU %s: %w", skuID, err)
+	}
+	return tokens, true, nil
+}
+
+func (s *FlashTokenStore) keys(skuID string) []string {
+	return []string{s.tokensKey(skuID), s.pendingKey(skuID)}
+}
+
+func (s *FlashTokenStore) tokensKey(skuID string) string {
+	return s.prefix + ":inventory:flash:{" + skuID + "}:tokens"
+}
+
+// pendingKey shares the hash tag of tokensKey so both land on the same cluster slot
+func (s *FlashTokenStore) pendingKey(skuID string) string {
+	return s.prefix + ":inventory:flash:{" + skuID + "}:pending"
+}
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/inventory/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminBinLocationHandler handles warehouse bin locations, the SKUs assigned to them and pick lists
+type AdminBinLocationHandler struct {
+	binService     application.BinLocationService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminBinLocationHandler creates a new admin bin location handler
+func NewAdminBinLocationHandler(binService application.BinLocationService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminBinLocationHandler {
+	return &AdminBinLocationHandler{
+		binService:     binService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers admin bin location routes
+func (h *AdminBinLocationHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/inventory/warehouses/{warehouseId}/bins", h.ListBins)
+		r.Post("/admin/inventory/warehouses/{warehouseId}/bins", h.CreateBin)
+		r.Post("/admin/inventory/warehouses/{warehouseId}/pick-lists", h.GeneratePickList)
+		r.Get("/admin/inventory/bins/{binId}", h.GetBin)
+		r.Put("/admin/inventory/bins/{binId}", h.UpdateBin)
+		r.Delete("/admin/inventory/bins/{binId}", h.DeleteBin)
+		r.Post("/admin/inventory/bins/{binId}/assignments", h.AssignSKU)
+		r.Delete("/admin/inventory/bins/{binId}/assignments/{skuId}", h.UnassignSKU)
+	})
+}
+
+// ListBins lists the bin locations of a warehouse, optionally of one zone
+func (h *AdminBinLocationHandler) ListBins(w http.ResponseWriter, r *http.Request) {
+	bins, err := h.binService.ListBins(r.Context(), chi.URLParam(r, "warehouseId"), r.URL.Query().Get("zone"))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list bin locations")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, bins)
+}
+
+// CreateBin adds a bin location to a warehouse
+func (h *AdminBinLocationHandler) CreateBin(w http.ResponseWriter, r *http.Request) {
+	var cmd application.BinLocationCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	bin, err := h.binService.CreateBin(r.Context(), chi.URLParam(r, "warehouseId"), &cmd)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to create bin location")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, bin)
+}
+
+// GetBin returns a bin location with the SKUs assigned to it
+func (h *AdminBinLocationHandler) GetBin(w http.ResponseWriter, r *http.Request) {
+	binID, ok := binLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	bin, err := h.binService.GetBin(r.Context(), binID)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, bin)
+}
+
+// UpdateBin changes the address, capacity or active flag of a bin location
+func (h *AdminBinLocationHandler) UpdateBin(w http.ResponseWriter, r *http.Request) {
+	binID, ok := binLocationID(w, r)
+	if !ok {
+		return
+	}
+	var cmd application.BinLocationCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	bin, err := h.binService.UpdateBin(r.Context(), binID, &cmd)
+	if err != nil {
+		h.logger.WithError(err).WithField("bin_id", binID).Error("failed to update bin location")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, bin)
+}
+
+// DeleteBin removes an empty bin location
+func (h *AdminBinLocationHandler) DeleteBin(w http.ResponseWriter, r *http.Request) {
+	binID, ok := binLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.binService.DeleteBin(r.Context(), binID); err != nil {
+		h.logger.WithError(err).WithField("bin_id", binID).Error("failed to delete bin location")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AssignSKU assigns a SKU to a bin location, or updates its quantity and primary flag
+func (h *AdminBinLocationHandler) AssignSKU(w http.ResponseWriter, r *http.Request) {
+	binID, ok := binLocationID(w, r)
+	if !ok {
+		return
+	}
+	var cmd application.AssignBinCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	bin, err := h.binService.AssignSKU(r.Context(), binID, &cmd)
+	if err != nil {
+		h.logger.WithError(err).WithField("bin_id", binID).WithField("sku_id", cmd.SKUID).Error("failed to assign SKU to bin")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, bin)
+}
+
+// UnassignSKU removes a SKU from a bin location
+func (h *AdminBinLocationHandler) UnassignSKU(w http.ResponseWriter, r *http.Request) {
+	binID, ok := binLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	skuID := chi.URLParam(r, "skuId")
+	if err := h.binService.UnassignSKU(r.Context(), binID, skuID); err != nil {
+		h.logger.WithError(err).WithField("bin_id", binID).WithField("sku_id", skuID).Error("failed to unassign SKU from bin")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GeneratePickList builds the pick list of a batch of orders, sorted along the warehouse pick path
+func (h *AdminBinLocationHandler) GeneratePickList(w http.ResponseWriter, r *http.Request) {
+	var cmd application.GeneratePickListCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	warehouseID := chi.URLParam(r, "warehouseId")
+	pickList, err := h.binService.GeneratePickList(r.Context(), warehouseID, &cmd)
+	if err != nil {
+		h.logger.WithError(err).WithField("warehouse_id", warehouseID).Error("failed to generate pick list")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, pickList)
+}
+
+func binLocationID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "binId"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid bin ID"))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/inventory/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminFlashAllocationHandler handles starting, stopping and reconciling flash-sale allocation of SKUs
+type AdminFlashAllocationHandler struct {
+	flashService   application.FlashAllocationService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminFlashAllocationHandler creates a new admin flash allocation handler
+func NewAdminFlashAllocationHandler(flashService application.FlashAllocationService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminFlashAllocationHandler {
+	return &AdminFlashAllocationHandler{
+		flashService:   flashService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers admin flash allocation routes
+func (h *AdminFlashAllocationHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/inventory/flash-allocations", h.ListAllocations)
+		r.Post("/admin/inventory/flash-allocations", h.StartAllocation)
+		r.Get("/admin/inventory/flash-allocations/{skuId}", h.GetAllocation)
+		r.Post("/admin/inventory/flash-allocations/{skuId}/stop", h.StopAllocation)
+		r.Post("/admin/inventory/flash-allocations/{skuId}/reconcile", h.ReconcileAllocation)
+	})
+}
+
+// ListAllocations lists the flash allocations; active=true leaves out stopped ones
+func (h *AdminFlashAllocationHandler) ListAllocations(w http.ResponseWriter, r *http.Request) {
+	allocations, err := h.flashService.ListAllocations(r.Context(), pkghttp.GetQueryParamBool(r, "active", false))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list flash allocations")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, allocations)
+}
+
+// StartAllocation puts a SKU in flash allocation mode
+func (h *AdminFlashAllocationHandler) StartAllocation(w http.ResponseWriter, r *http.Request) {
+	var cmd application.StartFlashAllocationCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		cmd.StartedBy = email
+	}
+
+	allocation, err := h.flashService.Start(r.Context(), &cmd)
+	if err != nil {
+		h.logger.WithError(err).WithField("sku_id", cmd.SKUID).Error("failed to start flash allocation")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, allocation)
+}
+
+// GetAllocation returns the flash allocation of a SKU with its latest reconciliations
+func (h *AdminFlashAllocationHandler) GetAllocation(w http.ResponseWriter, r *http.Request) {
+	allocation, err := h.flashService.GetAllocation(r.Context(), chi.URLParam(r, "skuId"))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, allocation)
+}
+
+// StopAllocation takes a SKU out of flash allocation mode
+func (h *AdminFlashAllocationHandler) StopAllocation(w http.ResponseWriter, r *http.Request) {
+	skuID := chi.URLParam(r, "skuId")
+	allocation, err := h.flashService.Stop(r.Context(), skuID)
+	if err != nil {
+		h.logger.WithError(err).WithField("sku_id", skuID).Error("failed to stop flash allocation")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, allocation)
+}
+
+// ReconcileAllocation applies the flash reservations of a SKU to its inventory levels now
+func (h *AdminFlashAllocationHandler) ReconcileAllocation(w http.ResponseWriter, r *http.Request) {
+	skuID := chi.URLParam(r, "skuId")
+	allocation, err := h.flashService.Reconcile(r.Context(), skuID)
+	if err != nil {
+		h.logger.WithError(err).WithField("sku_id", skuID).Error("failed to reconcile flash allocation")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, allocation)
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/inventory/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminInventoryHandler handles admin available-to-promise and inbound receipt requests
+type AdminInventoryHandler struct {
+	atpService     application.ATPService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminInventoryHandler creates a new admin inventory handler
+func NewAdminInventoryHandler(atpService application.ATPService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminInventoryHandler {
+	return &AdminInventoryHandler{
+		atpService:     atpService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers admin inventory routes
+func (h *AdminInventoryHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/admin/inventory/atp", h.GetAvailableToPromise)
+		r.Post("/admin/inventory/inbound-receipts", h.CreateInboundReceipt)
+		r.Post("/admin/inventory/inbound-receipts/{id}/receive", h.ReceiveInboundReceipt)
+		r.Post("/admin/inventory/inbound-receipts/{id}/cancel", h.CancelInboundReceipt)
+	})
+}
+
+// GetAvailableToPromise returns the available-to-promise quantity of several SKUs, per warehouse
+func (h *AdminInventoryHandler) GetAvailableToPromise(w http.ResponseWriter, r *http.Request) {
+	respondAvailableToPromise(w, r, h.atpService, h.logger)
+}
+
+// CreateInboundReceipt records stock expected to arrive, e.g. from a purchase order
+func (h *AdminInventoryHandler) CreateInboundReceipt(w http.ResponseWriter, r *http.Request) {
+	var cmd application.CreateInboundReceiptCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	receipt, err := h.atpService.CreateInboundReceipt(r.Context(), &cmd)
+	if err != nil {
+		h.logger.WithError(err).WithField("sku_id", cmd.SKUID).Error("failed to create inbound receipt")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, receipt)
+}
+
+// ReceiveInboundReceipt moves a receipt's quantity into stock on hand
+func (h *AdminInventoryHandler) ReceiveInboundReceipt(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	receipt, err := h.atpService.ReceiveInboundReceipt(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("receipt_id", id).Error("failed to receive inbound receipt")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, receipt)
+}
+
+// CancelInboundReceipt stops counting a receipt towards available-to-promise
+func (h *AdminInventoryHandler) CancelInboundReceipt(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	receipt, err := h.atpService.CancelInboundReceipt(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("receipt_id", id).Error("failed to cancel inbound receipt")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, receipt)
+}
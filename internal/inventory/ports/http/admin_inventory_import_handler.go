@@ -0,0 +1,124 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/inventory/application"
+	"github.com/qhato/ecommerce/internal/inventory/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/export"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// maxInventoryImportBytes bounds uploaded inventory import files
+const maxInventoryImportBytes = 32 << 20
+
+// AdminInventoryImportHandler handles bulk inventory imports, the adjustment ledger and
+// inventory level exports
+type AdminInventoryImportHandler struct {
+	importService  application.InventoryImportService
+	exportRepo     domain.InventoryExportRepository
+	jobs           *export.JobManager
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminInventoryImportHandler creates a new admin inventory import handler
+func NewAdminInventoryImportHandler(
+	importService application.InventoryImportService,
+	exportRepo domain.InventoryExportRepository,
+	jobs *export.JobManager,
+	authMiddleware func(http.Handler) http.Handler,
+	logger *logger.Logger,
+) *AdminInventoryImportHandler {
+	return &AdminInventoryImportHandler{
+		importService:  importService,
+		exportRepo:     exportRepo,
+		jobs:           jobs,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers inventory import and export routes; exports require the admin role
+func (h *AdminInventoryImportHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/admin/inventory/import", h.ImportInventory)
+		r.Get("/admin/inventory/adjustments", h.ListAdjustments)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Use(middleware.RequireRole("admin"))
+		r.Get("/admin/exports/inventory", h.ExportInventory)
+		r.Get("/admin/exports/inventory/low-stock", h.ExportLowStock)
+	})
+}
+
+// ImportInventory sets quantities on hand from a CSV of sku_id, warehouse_id and quantity,
+// sent as the request body or as the "file" field of a multipart form. With ?dry_run=true
+// the changes are only reported. Files with invalid rows are rejected whole with 422.
+func (h *AdminInventoryImportHandler) ImportInventory(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxInventoryImportBytes)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		upload, _, err := r.FormFile("file")
+		if err != nil {
+			pkghttp.RespondError(w, errors.BadRequest("multipart inventory import requires a file field").WithInternal(err))
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	result, err := h.importService.Import(r.Context(), file, pkghttp.GetQueryParamBool(r, "dry_run", false), middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to import inventory")
+		pkghttp.RespondError(w, err)
+		return
+	}
+	if len(result.Errors) > 0 {
+		pkghttp.RespondJSON(w, http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, result)
+}
+
+// ListAdjustments lists the ledger entries of an import (?reference=) or the latest of a SKU
+// (?sku_id=, with ?limit=)
+func (h *AdminInventoryImportHandler) ListAdjustments(w http.ResponseWriter, r *http.Request) {
+	limit := pkghttp.GetQueryParamInt(r, "limit", 100)
+	if limit < 1 || limit > 1000 {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("limit must be between 1 and 1000"))
+		return
+	}
+
+	adjustments, err := h.importService.ListAdjustments(r.Context(), r.URL.Query().Get("reference"), r.URL.Query().Get("sku_id"), limit)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, adjustments)
+}
+
+// ExportInventory streams current inventory levels as CSV, or queues them with ?async=true.
+// Filters: warehouse_id
+func (h *AdminInventoryImportHandler) ExportInventory(w http.ResponseWriter, r *http.Request) {
+	warehouseID := r.URL.Query().Get("warehouse_id")
+	export.Serve(w, r, h.jobs, application.NewInventoryExport(h.exportRepo, warehouseID), h.logger)
+}
+
+// ExportLowStock streams the inventory levels at or below their reorder point as CSV, or
+// queues them with ?async=true. Filters: warehouse_id
+func (h *AdminInventoryImportHandler) ExportLowStock(w http.ResponseWriter, r *http.Request) {
+	warehouseID := r.URL.Query().Get("warehouse_id")
+	export.Serve(w, r, h.jobs, application.NewLowStockExport(h.exportRepo, warehouseID), h.logger)
+}
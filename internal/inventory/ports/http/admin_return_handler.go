@@ -0,0 +1,140 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/inventory/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// returnReportDateLayout is the layout of the from and to of the return-to-stock report
+const returnReportDateLayout = "2006-01-02"
+
+// AdminReturnHandler handles receiving customer returns into inventory, their quarantine
+// and the return-to-stock report
+type AdminReturnHandler struct {
+	returnService  application.ReturnReceivingService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminReturnHandler creates a new admin return handler
+func NewAdminReturnHandler(returnService application.ReturnReceivingService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminReturnHandler {
+	return &AdminReturnHandler{
+		returnService:  returnService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers admin return routes
+func (h *AdminReturnHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/admin/inventory/returns", h.ReceiveReturn)
+		r.Get("/admin/inventory/returns/quarantine", h.ListQuarantined)
+		r.Get("/admin/inventory/returns/report", h.GetReturnToStockReport)
+		r.Get("/admin/inventory/returns/{id}", h.GetReturn)
+		r.Post("/admin/inventory/returns/lines/{lineId}/resolve", h.ResolveQuarantine)
+	})
+}
+
+// ReceiveReturn receives a return, e.g. {"reference": "RMA-1001", "warehouse_id": "wh-1",
+// "lines": [{"sku_id": "101", "quantity": 2, "disposition": "QUARANTINE"}]}
+func (h *AdminReturnHandler) ReceiveReturn(w http.ResponseWriter, r *http.Request) {
+	var cmd application.ReceiveReturnCommand
+	if err := pkghttp.DecodeJSON(r, &cmd); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	receipt, err := h.returnService.ReceiveReturn(r.Context(), &cmd, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to receive return")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, receipt)
+}
+
+// GetReturn returns a received return with its lines
+func (h *AdminReturnHandler) GetReturn(w http.ResponseWriter, r *http.Request) {
+	receipt, err := h.returnService.GetReturn(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, receipt)
+}
+
+// ResolveQuarantine restocks a quarantined line or counts it as damaged, e.g. {"disposition": "RESTOCK"}
+func (h *AdminReturnHandler) ResolveQuarantine(w http.ResponseWriter, r *http.Request) {
+	var cmd application.ResolveQuarantineCommand
+	if err := pkghttp.DecodeJSON(r, &cmd); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	receipt, err := h.returnService.ResolveQuarantine(r.Context(), chi.URLParam(r, "lineId"), &cmd, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to resolve quarantined return")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, receipt)
+}
+
+// ListQuarantined lists the returns with lines still in quarantine, oldest first
+func (h *AdminReturnHandler) ListQuarantined(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	receipts, err := h.returnService.ListQuarantined(r.Context(), limit)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list quarantined returns")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, receipts)
+}
+
+// GetReturnToStockReport reports by SKU how many returned units were restocked between
+// from and to (YYYY-MM-DD, both inclusive), the last 30 days by default
+func (h *AdminReturnHandler) GetReturnToStockReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	if value := query.Get("to"); value != "" {
+		t, err := time.Parse(returnReportDateLayout, value)
+		if err != nil {
+			pkghttp.RespondError(w, pkghttp.NewValidationError("invalid to, expected YYYY-MM-DD"))
+			return
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	from := to.AddDate(0, 0, -30)
+	if value := query.Get("from"); value != "" {
+		t, err := time.Parse(returnReportDateLayout, value)
+		if err != nil {
+			pkghttp.RespondError(w, pkghttp.NewValidationError("invalid from, expected YYYY-MM-DD"))
+			return
+		}
+		from = t
+	}
+
+	report, err := h.returnService.GetReturnToStockReport(r.Context(), from, to)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get return-to-stock report")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, report)
+}
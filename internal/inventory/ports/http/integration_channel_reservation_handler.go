@@ -0,0 +1,116 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/inventory/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// IntegrationChannelReservationHandler handles stock reservations of external
+// sales channels. Integrations authenticate with their channel API key as a
+// bearer token and only see their own channel's reservations.
+type IntegrationChannelReservationHandler struct {
+	reservationService application.ChannelReservationService
+	authMiddleware     func(http.Handler) http.Handler
+	logger             *logger.Logger
+}
+
+// NewIntegrationChannelReservationHandler creates a new channel reservation handler
+func NewIntegrationChannelReservationHandler(reservationService application.ChannelReservationService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *IntegrationChannelReservationHandler {
+	return &IntegrationChannelReservationHandler{
+		reservationService: reservationService,
+		authMiddleware:     authMiddleware,
+		logger:             logger,
+	}
+}
+
+// RegisterRoutes registers channel reservation routes
+func (h *IntegrationChannelReservationHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/integrations/inventory/reservations", h.PlaceReservation)
+		r.Get("/integrations/inventory/reservations/{id}", h.GetReservation)
+		r.Post("/integrations/inventory/reservations/{id}/extend", h.ExtendReservation)
+		r.Post("/integrations/inventory/reservations/{id}/release", h.ReleaseReservation)
+	})
+}
+
+// PlaceReservation reserves stock for a channel order: 201 when placed, 200
+// when a reservation with the same reference already exists
+func (h *IntegrationChannelReservationHandler) PlaceReservation(w http.ResponseWriter, r *http.Request) {
+	var cmd application.PlaceChannelReservationCommand
+	if err := pkghttp.DecodeJSON(r, &cmd); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	channel := middleware.GetChannel(r.Context())
+	reservation, created, err := h.reservationService.PlaceReservation(r.Context(), channel, &cmd)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logger.Fields{
+			"channel":   channel,
+			"reference": cmd.Reference,
+			"sku_id":    cmd.SKUID,
+		}).Warn("failed to place channel reservation")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	pkghttp.RespondJSON(w, status, reservation)
+}
+
+// GetReservation retrieves a reservation of the channel
+func (h *IntegrationChannelReservationHandler) GetReservation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	reservation, err := h.reservationService.GetReservation(r.Context(), middleware.GetChannel(r.Context()), id)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, reservation)
+}
+
+// ExtendReservation pushes back the expiry of an active reservation
+func (h *IntegrationChannelReservationHandler) ExtendReservation(w http.ResponseWriter, r *http.Request) {
+	var cmd application.ExtendChannelReservationCommand
+	if r.ContentLength != 0 {
+		if err := pkghttp.DecodeJSON(r, &cmd); err != nil {
+			pkghttp.RespondError(w, err)
+			return
+		}
+	}
+
+	id := chi.URLParam(r, "id")
+	channel := middleware.GetChannel(r.Context())
+	reservation, err := h.reservationService.ExtendReservation(r.Context(), channel, id, &cmd)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logger.Fields{"channel": channel, "reservation_id": id}).Warn("failed to extend channel reservation")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, reservation)
+}
+
+// ReleaseReservation returns the stock of a reservation, e.g. when the channel order is cancelled
+func (h *IntegrationChannelReservationHandler) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	channel := middleware.GetChannel(r.Context())
+	reservation, err := h.reservationService.ReleaseReservation(r.Context(), channel, id)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logger.Fields{"channel": channel, "reservation_id": id}).Error("failed to release channel reservation")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, reservation)
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/inventory/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// maxATPSKUs bounds the SKUs looked up by one available-to-promise request
+const maxATPSKUs = 100
+
+// AvailableToPromiseRequest lists the SKUs to look up
+type AvailableToPromiseRequest struct {
+	SKUIDs []string `json:"sku_ids"`
+}
+
+// StorefrontInventoryHandler handles storefront inventory availability requests
+type StorefrontInventoryHandler struct {
+	atpService application.ATPService
+	logger     *logger.Logger
+}
+
+// NewStorefrontInventoryHandler creates a new storefront inventory handler
+func NewStorefrontInventoryHandler(atpService application.ATPService, logger *logger.Logger) *StorefrontInventoryHandler {
+	return &StorefrontInventoryHandler{
+		atpService: atpService,
+		logger:     logger,
+	}
+}
+
+// RegisterRoutes registers storefront inventory routes
+func (h *StorefrontInventoryHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/inventory/atp", h.GetAvailableToPromise)
+}
+
+// GetAvailableToPromise returns the available-to-promise quantity of several SKUs at once
+func (h *StorefrontInventoryHandler) GetAvailableToPromise(w http.ResponseWriter, r *http.Request) {
+	respondAvailableToPromise(w, r, h.atpService, h.logger)
+}
+
+func respondAvailableToPromise(w http.ResponseWriter, r *http.Request, atpService application.ATPService, log *logger.Logger) {
+	var req AvailableToPromiseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if len(req.SKUIDs) == 0 {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("sku_ids is required"))
+		return
+	}
+	if len(req.SKUIDs) > maxATPSKUs {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("too many sku_ids, at most 100 are allowed"))
+		return
+	}
+
+	atps, err := atpService.GetAvailableToPromise(r.Context(), req.SKUIDs)
+	if err != nil {
+		log.WithError(err).Error("failed to get available-to-promise")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, atps)
+}
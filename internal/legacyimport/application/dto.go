@@ -0,0 +1,52 @@
+package application
+
+import (
+	"time"
+
+	"github.com/qhato/ecommerce/internal/legacyimport/domain"
+)
+
+// ImportRunDTO represents an import run for reporting
+type ImportRunDTO struct {
+	ID           int64      `json:"id"`
+	EntityType   string     `json:"entity_type"`
+	Status       string     `json:"status"`
+	BatchSize    int        `json:"batch_size"`
+	LastLegacyID int64      `json:"last_legacy_id"`
+	Processed    int64      `json:"processed"`
+	Skipped      int64      `json:"skipped"`
+	Failed       int64      `json:"failed"`
+	LastError    string     `json:"last_error,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// VerificationReportDTO compares the legacy database with the imported data
+type VerificationReportDTO struct {
+	EntityType      string  `json:"entity_type"`
+	LegacyCount     int64   `json:"legacy_count"`
+	MappedCount     int64   `json:"mapped_count"`
+	MissingIDs      []int64 `json:"missing_ids"`
+	ChangedIDs      []int64 `json:"changed_ids"`
+	MissingCount    int64   `json:"missing_count"`
+	ChangedCount    int64   `json:"changed_count"`
+	Verified        bool    `json:"verified"`
+	SampleTruncated bool    `json:"sample_truncated"`
+}
+
+// ToImportRunDTO converts a domain ImportRun to ImportRunDTO
+func ToImportRunDTO(run *domain.ImportRun) *ImportRunDTO {
+	return &ImportRunDTO{
+		ID:           run.ID,
+		EntityType:   string(run.EntityType),
+		Status:       string(run.Status),
+		BatchSize:    run.BatchSize,
+		LastLegacyID: run.LastLegacyID,
+		Processed:    run.Processed,
+		Skipped:      run.Skipped,
+		Failed:       run.Failed,
+		LastError:    run.LastError,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+	}
+}
@@ -0,0 +1,412 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/legacyimport/domain"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// maxReportedIDs caps the number of IDs listed in a verification report
+const maxReportedIDs = 100
+
+// RunImportCommand is a command to import one entity type from Broadleaf
+type RunImportCommand struct {
+	EntityType domain.EntityType
+	BatchSize  int
+	MaxBatches int  // 0 means run until the source is exhausted
+	Restart    bool // ignore a resumable run and start from the beginning
+}
+
+// ImportService defines the application service for Broadleaf legacy imports.
+type ImportService interface {
+	// Run imports an entity type in batches, resuming the latest unfinished run.
+	Run(ctx context.Context, cmd *RunImportCommand) (*ImportRunDTO, error)
+
+	// Verify compares the legacy rows with the ID mapping table.
+	Verify(ctx context.Context, entityType domain.EntityType) (*VerificationReportDTO, error)
+
+	// GetLatestRun retrieves the most recent run for an entity type.
+	GetLatestRun(ctx context.Context, entityType domain.EntityType) (*ImportRunDTO, error)
+}
+
+// Transactor runs a function in a transaction that the repositories called with
+// the context it is given join
+type Transactor interface {
+	WithContextTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type importService struct {
+	runRepo     domain.ImportRunRepository
+	mappingRepo domain.IDMappingRepository
+	source      domain.LegacySource
+	importers   map[domain.EntityType]EntityImporter
+	transactor  Transactor
+	log         *logger.Logger
+}
+
+// NewImportService creates a new instance of ImportService. The transactor must
+// be the one of the database the importers and the mapping repository write to.
+func NewImportService(
+	runRepo domain.ImportRunRepository,
+	mappingRepo domain.IDMappingRepository,
+	source domain.LegacySource,
+	importers []EntityImporter,
+	transactor Transactor,
+	log *logger.Logger,
+) ImportService {
+	byType := make(map[domain.EntityType]EntityImporter, len(importers))
+	for _, importer := range importers {
+		byType[importer.EntityType()] = importer
+	}
+	return &importService{
+		runRepo:     runRepo,
+		mappingRepo: mappingRepo,
+		source:      source,
+		importers:   byType,
+		transactor:  transactor,
+		log:         log,
+	}
+}
+
+func (s *importService) Run(ctx context.Context, cmd *RunImportCommand) (*ImportRunDTO, error) {
+	importer, ok := s.importers[cmd.EntityType]
+	if !ok {
+		return nil, domain.NewDomainError(fmt.Sprintf("no importer registered for entity %s", cmd.EntityType))
+	}
+
+	run, err := s.startOrResume(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	log := s.log.WithFields(logger.Fields{
+		"entity": cmd.EntityType,
+		"run_id": run.ID,
+	})
+	log.WithField("cursor", run.LastLegacyID).Info("Starting legacy import run")
+
+	batches := 0
+	for {
+		if cmd.MaxBatches > 0 && batches >= cmd.MaxBatches {
+			run.Pause()
+			break
+		}
+
+		if err := ctx.Err(); err != nil {
+			run.Pause()
+			break
+		}
+
+		records, err := s.source.FetchBatch(ctx, cmd.EntityType, run.LastLegacyID, run.BatchSize)
+		if err != nil {
+			run.Fail(err)
+			s.saveRun(ctx, run)
+			return nil, fmt.Errorf("failed to fetch legacy batch: %w", err)
+		}
+		if len(records) == 0 {
+			run.Complete()
+			break
+		}
+
+		if err := s.importBatch(ctx, run, importer, records); err != nil {
+			run.Fail(err)
+			s.saveRun(ctx, run)
+			return nil, err
+		}
+		batches++
+
+		log.WithFields(logger.Fields{
+			"cursor":    run.LastLegacyID,
+			"processed": run.Processed,
+			"skipped":   run.Skipped,
+			"failed":    run.Failed,
+		}).Info("Legacy import batch committed")
+	}
+
+	if err := s.runRepo.Save(context.WithoutCancel(ctx), run); err != nil {
+		return nil, fmt.Errorf("failed to save import run: %w", err)
+	}
+
+	log.WithField("status", run.Status).Info("Legacy import run finished")
+	return ToImportRunDTO(run), nil
+}
+
+func (s *importService) startOrResume(ctx context.Context, cmd *RunImportCommand) (*domain.ImportRun, error) {
+	if !cmd.Restart {
+		latest, err := s.runRepo.FindLatestByEntity(ctx, cmd.EntityType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find latest import run: %w", err)
+		}
+		if latest != nil && latest.IsResumable() {
+			latest.Resume()
+			if cmd.BatchSize > 0 {
+				latest.BatchSize = cmd.BatchSize
+			}
+			if err := s.runRepo.Save(ctx, latest); err != nil {
+				return nil, fmt.Errorf("failed to save import run: %w", err)
+			}
+			return latest, nil
+		}
+	}
+
+	run, err := domain.NewImportRun(cmd.EntityType, cmd.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.runRepo.Save(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to save import run: %w", err)
+	}
+	return run, nil
+}
+
+// importBatch imports a batch of records. Records are written in one transaction
+// with their mapping and records that already have a mapping are skipped, which
+// makes re-running a batch after a crash safe. Individual record failures are
+// counted and logged; the cursor still advances past them.
+func (s *importService) importBatch(ctx context.Context, run *domain.ImportRun, importer EntityImporter, records []*domain.LegacyRecord) error {
+	legacyIDs := make([]int64, len(records))
+	for i, record := range records {
+		legacyIDs[i] = record.ID
+	}
+
+	existing, err := s.mappingRepo.FindByLegacyIDs(ctx, run.EntityType, legacyIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load existing mappings: %w", err)
+	}
+
+	var processed, skipped, failed int64
+	if batchImporter, ok := importer.(BatchImporter); ok {
+		processed, skipped, failed, err = s.importAll(ctx, run, batchImporter, records, existing)
+	} else {
+		processed, skipped, failed, err = s.importEach(ctx, run, importer, records, existing)
+	}
+	if err != nil {
+		return err
+	}
+
+	run.Advance(records[len(records)-1].ID, processed, skipped, failed)
+	if err := s.runRepo.Save(ctx, run); err != nil {
+		return fmt.Errorf("failed to save import run: %w", err)
+	}
+	return nil
+}
+
+// importEach imports the unmapped records of a batch one at a time
+func (s *importService) importEach(ctx context.Context, run *domain.ImportRun, importer EntityImporter, records []*domain.LegacyRecord, existing map[int64]*domain.IDMapping) (processed, skipped, failed int64, err error) {
+	for _, record := range records {
+		if _, ok := existing[record.ID]; ok {
+			skipped++
+			continue
+		}
+
+		var saveErr error
+		err := s.transactor.WithContextTransaction(ctx, func(ctx context.Context) error {
+			newID, err := importer.Import(ctx, record, s)
+			if err != nil {
+				return err
+			}
+			saveErr = s.saveMapping(ctx, run, record, newID)
+			return saveErr
+		})
+		if saveErr != nil {
+			return 0, 0, 0, saveErr
+		}
+		if err != nil {
+			// A cancelled context is not a record failure; keep the cursor so the batch is retried
+			if ctx.Err() != nil {
+				return 0, 0, 0, ctx.Err()
+			}
+			failed++
+			s.log.WithError(err).WithFields(logger.Fields{
+				"entity":    run.EntityType,
+				"legacy_id": record.ID,
+			}).Warn("Failed to import legacy record")
+			continue
+		}
+		processed++
+	}
+	return processed, skipped, failed, nil
+}
+
+// importAll imports the unmapped records of a batch with a single ImportBatch
+// call, in one transaction with their mappings. When the call fails, every
+// pending record is counted as failed.
+func (s *importService) importAll(ctx context.Context, run *domain.ImportRun, importer BatchImporter, records []*domain.LegacyRecord, existing map[int64]*domain.IDMapping) (processed, skipped, failed int64, err error) {
+	pending := make([]*domain.LegacyRecord, 0, len(records))
+	for _, record := range records {
+		if _, ok := existing[record.ID]; ok {
+			skipped++
+			continue
+		}
+		pending = append(pending, record)
+	}
+	if len(pending) == 0 {
+		return processed, skipped, failed, nil
+	}
+
+	var newIDs map[int64]int64
+	var saveErr error
+	err = s.transactor.WithContextTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if newIDs, err = importer.ImportBatch(ctx, pending, s); err != nil {
+			return err
+		}
+		for _, record := range pending {
+			if newID, ok := newIDs[record.ID]; ok {
+				if saveErr = s.saveMapping(ctx, run, record, newID); saveErr != nil {
+					return saveErr
+				}
+			}
+		}
+		return nil
+	})
+	if saveErr != nil {
+		return 0, 0, 0, saveErr
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, 0, 0, ctx.Err()
+		}
+		s.log.WithError(err).WithFields(logger.Fields{
+			"entity":     run.EntityType,
+			"first_id":   pending[0].ID,
+			"last_id":    pending[len(pending)-1].ID,
+			"batch_size": len(pending),
+		}).Warn("Failed to import legacy batch")
+		return processed, skipped, failed + int64(len(pending)), nil
+	}
+
+	for _, record := range pending {
+		if _, ok := newIDs[record.ID]; !ok {
+			failed++
+			s.log.WithFields(logger.Fields{
+				"entity":    run.EntityType,
+				"legacy_id": record.ID,
+			}).Warn("Failed to import legacy record")
+			continue
+		}
+		processed++
+	}
+	return processed, skipped, failed, nil
+}
+
+// saveMapping stores the mapping of an imported record
+func (s *importService) saveMapping(ctx context.Context, run *domain.ImportRun, record *domain.LegacyRecord, newID int64) error {
+	mapping := domain.NewIDMapping(run.EntityType, record.ID, newID, record.Checksum(), run.ID)
+	if err := s.mappingRepo.Save(ctx, mapping); err != nil {
+		return fmt.Errorf("failed to save id mapping for legacy %s %d: %w", run.EntityType, record.ID, err)
+	}
+	return nil
+}
+
+// saveRun persists the run state on a best-effort basis after a failure.
+func (s *importService) saveRun(ctx context.Context, run *domain.ImportRun) {
+	if err := s.runRepo.Save(context.WithoutCancel(ctx), run); err != nil {
+		s.log.WithError(err).WithField("run_id", run.ID).Error("Failed to save import run state")
+	}
+}
+
+// Resolve implements IDResolver using the ID mapping table.
+func (s *importService) Resolve(ctx context.Context, entityType domain.EntityType, legacyID *int64) (*int64, error) {
+	if legacyID == nil {
+		return nil, nil
+	}
+	mapping, err := s.mappingRepo.FindByLegacyID(ctx, entityType, *legacyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve legacy %s %d: %w", entityType, *legacyID, err)
+	}
+	if mapping == nil {
+		return nil, nil
+	}
+	return &mapping.NewID, nil
+}
+
+// ResolveMany implements IDResolver using the ID mapping table.
+func (s *importService) ResolveMany(ctx context.Context, entityType domain.EntityType, legacyIDs []int64) (map[int64]int64, error) {
+	mappings, err := s.mappingRepo.FindByLegacyIDs(ctx, entityType, legacyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve legacy %s ids: %w", entityType, err)
+	}
+	ids := make(map[int64]int64, len(mappings))
+	for legacyID, mapping := range mappings {
+		ids[legacyID] = mapping.NewID
+	}
+	return ids, nil
+}
+
+func (s *importService) Verify(ctx context.Context, entityType domain.EntityType) (*VerificationReportDTO, error) {
+	legacyCount, err := s.source.Count(ctx, entityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count legacy records: %w", err)
+	}
+	mappedCount, err := s.mappingRepo.CountByEntity(ctx, entityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count id mappings: %w", err)
+	}
+
+	report := &VerificationReportDTO{
+		EntityType:  string(entityType),
+		LegacyCount: legacyCount,
+		MappedCount: mappedCount,
+		MissingIDs:  make([]int64, 0),
+		ChangedIDs:  make([]int64, 0),
+	}
+
+	const verifyBatchSize = 1000
+	var cursor int64
+	for {
+		records, err := s.source.FetchBatch(ctx, entityType, cursor, verifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch legacy batch: %w", err)
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		legacyIDs := make([]int64, len(records))
+		for i, record := range records {
+			legacyIDs[i] = record.ID
+		}
+		mappings, err := s.mappingRepo.FindByLegacyIDs(ctx, entityType, legacyIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load id mappings: %w", err)
+		}
+
+		for _, record := range records {
+			mapping, ok := mappings[record.ID]
+			switch {
+			case !ok:
+				report.MissingCount++
+				report.MissingIDs = appendCapped(report.MissingIDs, record.ID, &report.SampleTruncated)
+			case mapping.Checksum != record.Checksum():
+				report.ChangedCount++
+				report.ChangedIDs = appendCapped(report.ChangedIDs, record.ID, &report.SampleTruncated)
+			}
+		}
+		cursor = records[len(records)-1].ID
+	}
+
+	report.Verified = report.MissingCount == 0 && report.ChangedCount == 0
+	return report, nil
+}
+
+func appendCapped(ids []int64, id int64, truncated *bool) []int64 {
+	if len(ids) >= maxReportedIDs {
+		*truncated = true
+		return ids
+	}
+	return append(ids, id)
+}
+
+func (s *importService) GetLatestRun(ctx context.Context, entityType domain.EntityType) (*ImportRunDTO, error) {
+	run, err := s.runRepo.FindLatestByEntity(ctx, entityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find latest import run: %w", err)
+	}
+	if run == nil {
+		return nil, nil
+	}
+	return ToImportRunDTO(run), nil
+}
@@ -0,0 +1,521 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	catalogDomain "github.com/qhato/ecommerce/internal/catalog/domain"
+	customerDomain "github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/internal/legacyimport/domain"
+	offerDomain "github.com/qhato/ecommerce/internal/offer/domain"
+	orderDomain "github.com/qhato/ecommerce/internal/order/domain"
+)
+
+// IDResolver translates legacy foreign keys into identifiers of this system
+type IDResolver interface {
+	// Resolve returns the new ID for a legacy ID, or nil if the entity has not been imported
+	Resolve(ctx context.Context, entityType domain.EntityType, legacyID *int64) (*int64, error)
+
+	// ResolveMany returns the new IDs of the imported legacy IDs, keyed by legacy ID
+	ResolveMany(ctx context.Context, entityType domain.EntityType, legacyIDs []int64) (map[int64]int64, error)
+}
+
+// EntityImporter maps a Broadleaf record into this system's repositories
+type EntityImporter interface {
+	// EntityType returns the entity type handled by the importer
+	EntityType() domain.EntityType
+
+	// Import persists the record and returns the new ID
+	Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error)
+}
+
+// BatchImporter is implemented by importers that persist a whole batch at once,
+// typically join tables where one statement per row dominates the run time
+type BatchImporter interface {
+	EntityImporter
+
+	// ImportBatch persists the records and returns the new IDs keyed by legacy ID.
+	// Records missing from the result could not be imported.
+	ImportBatch(ctx context.Context, records []*domain.LegacyRecord, resolver IDResolver) (map[int64]int64, error)
+}
+
+// CategoryImporter imports blc_category rows
+type CategoryImporter struct {
+	repo catalogDomain.CategoryRepository
+}
+
+// NewCategoryImporter creates a new CategoryImporter
+func NewCategoryImporter(repo catalogDomain.CategoryRepository) *CategoryImporter {
+	return &CategoryImporter{repo: repo}
+}
+
+// EntityType returns the entity type handled by the importer
+func (i *CategoryImporter) EntityType() domain.EntityType {
+	return domain.EntityCategory
+}
+
+// Import persists a legacy category
+func (i *CategoryImporter) Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error) {
+	category := catalogDomain.NewCategory(
+		record.String("name"),
+		record.String("description"),
+		record.String("url"),
+		record.String("url_key"),
+	)
+	category.LongDescription = record.String("long_description")
+	category.ActiveStartDate = record.Time("active_start_date")
+	category.ActiveEndDate = record.Time("active_end_date")
+	category.Archived = record.Bool("archived")
+	category.DisplayTemplate = record.String("display_template")
+	category.ExternalID = record.String("external_id")
+	category.FulfillmentType = record.String("fulfillment_type")
+	category.InventoryType = record.String("inventory_type")
+	category.MetaDescription = record.String("meta_desc")
+	category.MetaTitle = record.String("meta_title")
+	category.OverrideGeneratedURL = record.Bool("override_generated_url")
+	category.ProductDescPattern = record.String("product_desc_pattern_override")
+	category.ProductTitlePattern = record.String("product_title_pattern_override")
+	category.RootDisplayOrder = record.Float64("root_display_order")
+	category.TaxCode = record.String("tax_code")
+
+	// Parents are imported before children because Broadleaf assigns increasing IDs;
+	// an unresolved parent is left empty rather than failing the row.
+	parentID, err := resolver.Resolve(ctx, domain.EntityCategory, record.Int64Ptr("default_parent_category_id"))
+	if err != nil {
+		return 0, err
+	}
+	category.DefaultParentCategoryID = parentID
+
+	if err := i.repo.Create(ctx, category); err != nil {
+		return 0, err
+	}
+	return category.ID, nil
+}
+
+// ProductImporter imports blc_product rows
+type ProductImporter struct {
+	repo catalogDomain.ProductRepository
+}
+
+// NewProductImporter creates a new ProductImporter
+func NewProductImporter(repo catalogDomain.ProductRepository) *ProductImporter {
+	return &ProductImporter{repo: repo}
+}
+
+// EntityType returns the entity type handled by the importer
+func (i *ProductImporter) EntityType() domain.EntityType {
+	return domain.EntityProduct
+}
+
+// Import persists a legacy product
+func (i *ProductImporter) Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error) {
+	product := catalogDomain.NewProduct(
+		record.String("manufacture"),
+		record.String("model"),
+		record.String("url"),
+		record.String("url_key"),
+		record.Bool("can_sell_without_options"),
+		record.Bool("enable_default_sku_in_inventory"),
+	)
+	product.Archived = record.Bool("archived")
+	product.CanonicalURL = record.String("canonical_url")
+	product.DisplayTemplate = record.String("display_template")
+	product.MetaDescription = record.String("meta_desc")
+	product.MetaTitle = record.String("meta_title")
+	product.OverrideGeneratedURL = record.Bool("override_generated_url")
+	// Legacy products are live already and skip the approval workflow
+	product.Publish("")
+
+	categoryID, err := resolver.Resolve(ctx, domain.EntityCategory, record.Int64Ptr("default_category_id"))
+	if err != nil {
+		return 0, err
+	}
+	product.DefaultCategoryID = categoryID
+
+	if err := i.repo.Create(ctx, product); err != nil {
+		return 0, err
+	}
+	return product.ID, nil
+}
+
+// SKUImporter imports blc_sku rows and links default SKUs back to their product
+type SKUImporter struct {
+	repo        catalogDomain.SKURepository
+	productRepo catalogDomain.ProductRepository
+}
+
+// NewSKUImporter creates a new SKUImporter
+func NewSKUImporter(repo catalogDomain.SKURepository, productRepo catalogDomain.ProductRepository) *SKUImporter {
+	return &SKUImporter{repo: repo, productRepo: productRepo}
+}
+
+// EntityType returns the entity type handled by the importer
+func (i *SKUImporter) EntityType() domain.EntityType {
+	return domain.EntitySKU
+}
+
+// Import persists a legacy SKU
+func (i *SKUImporter) Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error) {
+	sku := catalogDomain.NewSKU(
+		record.String("name"),
+		record.String("description"),
+		record.String("upc"),
+		record.String("currency_code"),
+		record.Float64("cost"),
+		record.Float64("retail_price"),
+		record.Float64("sale_price"),
+	)
+	sku.LongDescription = record.String("long_description")
+	sku.ActiveStartDate = record.Time("active_start_date")
+	sku.ActiveEndDate = record.Time("active_end_date")
+	sku.Available = record.Bool("available_flag")
+	sku.ContainerShape = record.String("container_shape")
+	sku.Depth = record.Float64("depth")
+	sku.DimensionUnitOfMeasure = record.String("dimension_unit_of_measure")
+	sku.Girth = record.Float64("girth")
+	sku.Height = record.Float64("height")
+	sku.ContainerSize = record.String("container_size")
+	sku.Width = record.Float64("width")
+	sku.Discountable = record.Bool("discountable_flag")
+	sku.DisplayTemplate = record.String("display_template")
+	sku.ExternalID = record.String("external_id")
+	sku.FulfillmentType = record.String("fulfillment_type")
+	sku.InventoryType = record.String("inventory_type")
+	sku.IsMachineSortable = record.Bool("is_machine_sortable")
+	sku.Taxable = record.Bool("taxable_flag")
+	sku.TaxCode = record.String("tax_code")
+	sku.URLKey = record.String("url_key")
+	sku.Weight = record.Float64("weight")
+	sku.WeightUnitOfMeasure = record.String("weight_unit_of_measure")
+
+	defaultProductID, err := resolver.Resolve(ctx, domain.EntityProduct, record.Int64Ptr("default_product_id"))
+	if err != nil {
+		return 0, err
+	}
+	sku.DefaultProductID = defaultProductID
+
+	additionalProductID, err := resolver.Resolve(ctx, domain.EntityProduct, record.Int64Ptr("addl_product_id"))
+	if err != nil {
+		return 0, err
+	}
+	sku.AdditionalProductID = additionalProductID
+
+	if err := i.repo.Create(ctx, sku); err != nil {
+		return 0, err
+	}
+
+	// Products are imported before SKUs, so the default SKU link is restored here
+	if defaultProductID != nil {
+		product, err := i.productRepo.FindByID(ctx, *defaultProductID)
+		if err != nil {
+			return 0, err
+		}
+		if product != nil && product.DefaultSkuID == nil {
+			product.DefaultSkuID = &sku.ID
+			if err := i.productRepo.Update(ctx, product); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return sku.ID, nil
+}
+
+// CategoryProductXrefImporter imports blc_category_product_xref rows in batches
+type CategoryProductXrefImporter struct {
+	repo catalogDomain.CategoryProductXrefRepository
+}
+
+// NewCategoryProductXrefImporter creates a new CategoryProductXrefImporter
+func NewCategoryProductXrefImporter(repo catalogDomain.CategoryProductXrefRepository) *CategoryProductXrefImporter {
+	return &CategoryProductXrefImporter{repo: repo}
+}
+
+// EntityType returns the entity type handled by the importer
+func (i *CategoryProductXrefImporter) EntityType() domain.EntityType {
+	return domain.EntityCategoryProductXref
+}
+
+// Import persists a single legacy category-product link
+func (i *CategoryProductXrefImporter) Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error) {
+	ids, err := i.ImportBatch(ctx, []*domain.LegacyRecord{record}, resolver)
+	if err != nil {
+		return 0, err
+	}
+	id, ok := ids[record.ID]
+	if !ok {
+		return 0, domain.NewDomainError(fmt.Sprintf("category or product of legacy link %d has not been imported", record.ID))
+	}
+	return id, nil
+}
+
+// ImportBatch persists legacy category-product links with a single upsert.
+// Links whose category or product has not been imported are left out.
+func (i *CategoryProductXrefImporter) ImportBatch(ctx context.Context, records []*domain.LegacyRecord, resolver IDResolver) (map[int64]int64, error) {
+	categoryLegacyIDs := make([]int64, 0, len(records))
+	productLegacyIDs := make([]int64, 0, len(records))
+	for _, record := range records {
+		categoryLegacyIDs = append(categoryLegacyIDs, record.Int64("category_id"))
+		productLegacyIDs = append(productLegacyIDs, record.Int64("product_id"))
+	}
+	categoryIDs, err := resolver.ResolveMany(ctx, domain.EntityCategory, categoryLegacyIDs)
+	if err != nil {
+		return nil, err
+	}
+	productIDs, err := resolver.ResolveMany(ctx, domain.EntityProduct, productLegacyIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	xrefs := make(map[int64]*catalogDomain.CategoryProductXref, len(records))
+	batch := make([]*catalogDomain.CategoryProductXref, 0, len(records))
+	for _, record := range records {
+		categoryID, okCategory := categoryIDs[record.Int64("category_id")]
+		productID, okProduct := productIDs[record.Int64("product_id")]
+		if !okCategory || !okProduct {
+			continue
+		}
+		xref, err := catalogDomain.NewCategoryProductXref(categoryID, productID)
+		if err != nil {
+			continue
+		}
+		xref.DefaultReference = record.Bool("default_reference")
+		xref.DisplayOrder = record.Float64("display_order")
+		xrefs[record.ID] = xref
+		batch = append(batch, xref)
+	}
+
+	if err := i.repo.SaveAll(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int64]int64, len(xrefs))
+	for legacyID, xref := range xrefs {
+		ids[legacyID] = xref.ID
+	}
+	return ids, nil
+}
+
+// CustomerImporter imports blc_customer rows. Password hashes are copied as-is;
+// customers are flagged to change their password on next login.
+type CustomerImporter struct {
+	repo customerDomain.CustomerRepository
+}
+
+// NewCustomerImporter creates a new CustomerImporter
+func NewCustomerImporter(repo customerDomain.CustomerRepository) *CustomerImporter {
+	return &CustomerImporter{repo: repo}
+}
+
+// EntityType returns the entity type handled by the importer
+func (i *CustomerImporter) EntityType() domain.EntityType {
+	return domain.EntityCustomer
+}
+
+// Import persists a legacy customer
+func (i *CustomerImporter) Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error) {
+	customer := customerDomain.NewCustomer(
+		record.String("email_address"),
+		record.String("user_name"),
+		record.String("password"),
+		record.String("first_name"),
+		record.String("last_name"),
+	)
+	customer.Archived = record.Bool("archived")
+	customer.ChallengeAnswer = record.String("challenge_answer")
+	customer.Deactivated = record.Bool("deactivated")
+	customer.ExternalID = fmt.Sprintf("blc:%d", record.ID)
+	customer.IsTaxExempt = record.Bool("is_tax_exempt")
+	customer.PasswordChangeRequired = true
+	customer.IsPreview = record.Bool("is_preview")
+	customer.ReceiveEmail = record.Bool("receive_email")
+	customer.IsRegistered = record.Bool("is_registered")
+	customer.TaxExemptionCode = record.String("tax_exemption_code")
+	customer.ChallengeQuestionID = record.Int64Ptr("challenge_question_id")
+	customer.LocaleCode = record.String("locale_code")
+
+	if err := i.repo.Create(ctx, customer); err != nil {
+		return 0, err
+	}
+	return customer.ID, nil
+}
+
+// OfferImporter imports blc_offer rows
+type OfferImporter struct {
+	repo offerDomain.OfferRepository
+}
+
+// NewOfferImporter creates a new OfferImporter
+func NewOfferImporter(repo offerDomain.OfferRepository) *OfferImporter {
+	return &OfferImporter{repo: repo}
+}
+
+// EntityType returns the entity type handled by the importer
+func (i *OfferImporter) EntityType() domain.EntityType {
+	return domain.EntityOffer
+}
+
+// Import persists a legacy offer
+func (i *OfferImporter) Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error) {
+	startDate := record.Time("start_date")
+	if startDate == nil {
+		return 0, domain.NewDomainError("offer start date is required")
+	}
+
+	offer, err := offerDomain.NewOffer(
+		record.String("offer_name"),
+		offerDomain.OfferType(record.String("offer_type")),
+		record.Float64("offer_value"),
+		offerDomain.OfferAdjustmentType(record.String("offer_adjustment_type")),
+		*startDate,
+	)
+	if err != nil {
+		return 0, err
+	}
+	offer.ApplyToChildItems = record.Bool("apply_to_child_items")
+	offer.ApplyToSalePrice = record.Bool("apply_to_sale_price")
+	offer.Archived = record.Bool("archived")
+	offer.AutomaticallyAdded = record.Bool("automatically_added")
+	offer.CombinableWithOtherOffers = record.Bool("combinable_with_other_offers")
+	offer.OfferDescription = record.String("offer_description")
+	offer.OfferDiscountType = offerDomain.OfferDiscountType(record.String("offer_discount_type"))
+	offer.EndDate = record.Time("end_date")
+	offer.MarketingMessage = record.String("marketing_message")
+	offer.MaxUsesPerCustomer = record.Int64Ptr("max_uses_per_customer")
+	if maxUses := record.Int64Ptr("max_uses"); maxUses != nil {
+		offer.SetMaxUses(int(*maxUses))
+	}
+	offer.MaxUsesStrategy = record.String("max_uses_strategy")
+	offer.MinimumDaysPerUsage = record.Int64Ptr("minimum_days_per_usage")
+	offer.OfferItemQualifierRule = record.String("offer_item_qualifier_rule")
+	offer.OfferItemTargetRule = record.String("offer_item_target_rule")
+	offer.OrderMinTotal = record.Float64("order_min_total")
+	offer.OfferPriority = int(record.Int64("offer_priority"))
+	offer.QualifyingItemMinTotal = record.Float64("qualifying_item_min_total")
+	offer.RequiresRelatedTarQual = record.Bool("requires_related_tar_qual")
+	offer.TargetMinTotal = record.Float64("target_min_total")
+	offer.TargetSystem = record.String("target_system")
+	offer.TotalitarianOffer = record.Bool("totalitarian_offer")
+	offer.UseListForDiscounts = record.Bool("use_list_for_discounts")
+
+	if err := i.repo.Save(ctx, offer); err != nil {
+		return 0, err
+	}
+	return offer.ID, nil
+}
+
+// OrderImporter imports blc_order rows
+type OrderImporter struct {
+	repo orderDomain.OrderRepository
+}
+
+// NewOrderImporter creates a new OrderImporter
+func NewOrderImporter(repo orderDomain.OrderRepository) *OrderImporter {
+	return &OrderImporter{repo: repo}
+}
+
+// EntityType returns the entity type handled by the importer
+func (i *OrderImporter) EntityType() domain.EntityType {
+	return domain.EntityOrder
+}
+
+// Import persists a legacy order header
+func (i *OrderImporter) Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error) {
+	customerID, err := resolver.Resolve(ctx, domain.EntityCustomer, record.Int64Ptr("customer_id"))
+	if err != nil {
+		return 0, err
+	}
+	if customerID == nil {
+		return 0, domain.NewDomainError(fmt.Sprintf("customer %d of order %d has not been imported", record.Int64("customer_id"), record.ID))
+	}
+
+	order := orderDomain.NewOrder(
+		*customerID,
+		record.String("email_address"),
+		record.String("name"),
+		record.String("currency_code"),
+		record.String("locale_code"),
+	)
+	order.OrderNumber = record.String("order_number")
+	order.Channel = orderDomain.OrderChannelWeb // Legacy orders carry no channel; they count as web orders
+	order.Status = orderDomain.OrderStatus(record.String("order_status"))
+	order.OrderSubtotal = record.Float64("order_subtotal")
+	order.TotalTax = record.Float64("total_tax")
+	order.TotalShipping = record.Float64("total_shipping")
+	order.OrderTotal = record.Float64("order_total")
+	order.IsPreview = record.Bool("is_preview")
+	order.TaxOverride = record.Bool("tax_override")
+	order.SubmitDate = record.Time("submit_date")
+
+	if err := i.repo.Create(ctx, order); err != nil {
+		return 0, err
+	}
+	return order.ID, nil
+}
+
+// OrderItemImporter imports blc_order_item rows
+type OrderItemImporter struct {
+	repo orderDomain.OrderItemRepository
+}
+
+// NewOrderItemImporter creates a new OrderItemImporter
+func NewOrderItemImporter(repo orderDomain.OrderItemRepository) *OrderItemImporter {
+	return &OrderItemImporter{repo: repo}
+}
+
+// EntityType returns the entity type handled by the importer
+func (i *OrderItemImporter) EntityType() domain.EntityType {
+	return domain.EntityOrderItem
+}
+
+// Import persists a legacy order item
+func (i *OrderItemImporter) Import(ctx context.Context, record *domain.LegacyRecord, resolver IDResolver) (int64, error) {
+	orderID, err := resolver.Resolve(ctx, domain.EntityOrder, record.Int64Ptr("order_id"))
+	if err != nil {
+		return 0, err
+	}
+	skuID, err := resolver.Resolve(ctx, domain.EntitySKU, record.Int64Ptr("sku_id"))
+	if err != nil {
+		return 0, err
+	}
+	productID, err := resolver.Resolve(ctx, domain.EntityProduct, record.Int64Ptr("product_id"))
+	if err != nil {
+		return 0, err
+	}
+	if orderID == nil || skuID == nil || productID == nil {
+		return 0, domain.NewDomainError(fmt.Sprintf("order item %d references entities that have not been imported", record.ID))
+	}
+
+	item, err := orderDomain.NewOrderItem(
+		*orderID,
+		*skuID,
+		*productID,
+		record.String("name"),
+		int(record.Int64("quantity")),
+		record.Float64("retail_price"),
+		record.Float64("sale_price"),
+		record.String("tax_category"),
+	)
+	if err != nil {
+		return 0, err
+	}
+	item.Price = record.Float64("price")
+	item.TotalPrice = item.Price * float64(item.Quantity)
+	item.DiscountsAllowed = record.Bool("discounts_allowed")
+	item.HasValidationErrors = record.Bool("has_validation_errors")
+	item.ItemTaxableFlag = record.Bool("item_taxable_flag")
+	item.OrderItemType = record.String("order_item_type")
+	item.RetailPriceOverride = record.Bool("retail_price_override")
+	item.SalePriceOverride = record.Bool("sale_price_override")
+
+	categoryID, err := resolver.Resolve(ctx, domain.EntityCategory, record.Int64Ptr("category_id"))
+	if err != nil {
+		return 0, err
+	}
+	item.CategoryID = categoryID
+
+	if err := i.repo.Save(ctx, item); err != nil {
+		return 0, err
+	}
+	return item.ID, nil
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// IDMapping links a Broadleaf legacy identifier to the identifier assigned
+// by this system. Checksum is a fingerprint of the legacy row at import time
+// and is used to detect rows that changed after they were migrated.
+type IDMapping struct {
+	EntityType EntityType
+	LegacyID   int64
+	NewID      int64
+	Checksum   string
+	RunID      int64
+	ImportedAt time.Time
+}
+
+// NewIDMapping creates a new ID mapping
+func NewIDMapping(entityType EntityType, legacyID, newID int64, checksum string, runID int64) *IDMapping {
+	return &IDMapping{
+		EntityType: entityType,
+		LegacyID:   legacyID,
+		NewID:      newID,
+		Checksum:   checksum,
+		RunID:      runID,
+		ImportedAt: time.Now(),
+	}
+}
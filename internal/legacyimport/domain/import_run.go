@@ -0,0 +1,125 @@
+package domain
+
+import "time"
+
+// EntityType identifies a Broadleaf entity family that can be imported
+type EntityType string
+
+const (
+	EntityCategory            EntityType = "category"
+	EntityProduct             EntityType = "product"
+	EntitySKU                 EntityType = "sku"
+	EntityCategoryProductXref EntityType = "category_product_xref"
+	EntityCustomer            EntityType = "customer"
+	EntityOffer               EntityType = "offer"
+	EntityOrder               EntityType = "order"
+	EntityOrderItem           EntityType = "order_item"
+)
+
+// DefaultEntityOrder is the order in which entities must be imported so that
+// foreign keys (category -> product -> sku, customer -> order -> order item)
+// can be resolved through the ID mapping table.
+var DefaultEntityOrder = []EntityType{
+	EntityCategory,
+	EntityProduct,
+	EntitySKU,
+	EntityCategoryProductXref,
+	EntityCustomer,
+	EntityOffer,
+	EntityOrder,
+	EntityOrderItem,
+}
+
+// ImportRunStatus represents the status of an import run
+type ImportRunStatus string
+
+const (
+	ImportRunStatusRunning   ImportRunStatus = "RUNNING"
+	ImportRunStatusPaused    ImportRunStatus = "PAUSED"
+	ImportRunStatusCompleted ImportRunStatus = "COMPLETED"
+	ImportRunStatusFailed    ImportRunStatus = "FAILED"
+)
+
+// ImportRun tracks the progress of a batch import for one entity type.
+// LastLegacyID is the cursor used to resume an interrupted run.
+type ImportRun struct {
+	ID           int64
+	EntityType   EntityType
+	Status       ImportRunStatus
+	BatchSize    int
+	LastLegacyID int64
+	Processed    int64
+	Skipped      int64
+	Failed       int64
+	LastError    string
+	StartedAt    time.Time
+	FinishedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewImportRun creates a new import run for an entity type
+func NewImportRun(entityType EntityType, batchSize int) (*ImportRun, error) {
+	if entityType == "" {
+		return nil, NewDomainError("entity type is required")
+	}
+	if batchSize <= 0 {
+		return nil, NewDomainError("batch size must be greater than zero")
+	}
+
+	now := time.Now()
+	return &ImportRun{
+		EntityType: entityType,
+		Status:     ImportRunStatusRunning,
+		BatchSize:  batchSize,
+		StartedAt:  now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// Advance records the outcome of a processed batch and moves the cursor
+func (r *ImportRun) Advance(lastLegacyID, processed, skipped, failed int64) {
+	if lastLegacyID > r.LastLegacyID {
+		r.LastLegacyID = lastLegacyID
+	}
+	r.Processed += processed
+	r.Skipped += skipped
+	r.Failed += failed
+	r.UpdatedAt = time.Now()
+}
+
+// Resume marks a paused or failed run as running again
+func (r *ImportRun) Resume() {
+	r.Status = ImportRunStatusRunning
+	r.LastError = ""
+	r.UpdatedAt = time.Now()
+}
+
+// Pause marks the run as paused so it can be resumed from its cursor
+func (r *ImportRun) Pause() {
+	r.Status = ImportRunStatusPaused
+	r.UpdatedAt = time.Now()
+}
+
+// Complete marks the run as completed
+func (r *ImportRun) Complete() {
+	now := time.Now()
+	r.Status = ImportRunStatusCompleted
+	r.FinishedAt = &now
+	r.UpdatedAt = now
+}
+
+// Fail marks the run as failed, keeping the cursor for a later resume
+func (r *ImportRun) Fail(err error) {
+	r.Status = ImportRunStatusFailed
+	if err != nil {
+		r.LastError = err.Error()
+	}
+	r.UpdatedAt = time.Now()
+}
+
+// IsResumable checks if the run can be continued from its cursor
+func (r *ImportRun) IsResumable() bool {
+	return r.Status != ImportRunStatusCompleted
+}
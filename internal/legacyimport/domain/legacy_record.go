@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LegacyRecord is a raw row read from the Broadleaf database, keyed by column name
+type LegacyRecord struct {
+	ID     int64
+	Values map[string]interface{}
+}
+
+// String returns a column as string, or an empty string if null
+func (r *LegacyRecord) String(column string) string {
+	switch v := r.Values[column].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Int64 returns a column as int64, or zero if null
+func (r *LegacyRecord) Int64(column string) int64 {
+	switch v := r.Values[column].(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int:
+		return int64(v)
+	default:
+		return 0
+	}
+}
+
+// Int64Ptr returns a column as *int64, or nil if null or zero
+func (r *LegacyRecord) Int64Ptr(column string) *int64 {
+	v := r.Int64(column)
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+// Float64 returns a numeric column as float64, or zero if null
+func (r *LegacyRecord) Float64(column string) float64 {
+	switch v := r.Values[column].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case pgtype.Numeric:
+		f, err := v.Float64Value()
+		if err != nil || !f.Valid {
+			return 0
+		}
+		return f.Float64
+	default:
+		return 0
+	}
+}
+
+// Bool returns a column as bool. Broadleaf stores most flags as bpchar(1) 'Y'/'N'.
+func (r *LegacyRecord) Bool(column string) bool {
+	switch v := r.Values[column].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(strings.TrimSpace(v), "Y") || strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
+
+// Time returns a column as *time.Time, or nil if null
+func (r *LegacyRecord) Time(column string) *time.Time {
+	if v, ok := r.Values[column].(time.Time); ok {
+		return &v
+	}
+	return nil
+}
+
+// Checksum returns a stable fingerprint of the record values
+func (r *LegacyRecord) Checksum() string {
+	columns := make([]string, 0, len(r.Values))
+	for column := range r.Values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	h := sha256.New()
+	for _, column := range columns {
+		fmt.Fprintf(h, "%s=%v;", column, r.Values[column])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
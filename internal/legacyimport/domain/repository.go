@@ -0,0 +1,39 @@
+package domain
+
+import "context"
+
+// ImportRunRepository defines the interface for import run persistence
+type ImportRunRepository interface {
+	// Save creates or updates an import run
+	Save(ctx context.Context, run *ImportRun) error
+
+	// FindByID retrieves an import run by ID
+	FindByID(ctx context.Context, id int64) (*ImportRun, error)
+
+	// FindLatestByEntity retrieves the most recent run for an entity type
+	FindLatestByEntity(ctx context.Context, entityType EntityType) (*ImportRun, error)
+}
+
+// IDMappingRepository defines the interface for legacy ID mapping persistence
+type IDMappingRepository interface {
+	// Save stores a mapping, replacing any previous mapping for the same legacy ID
+	Save(ctx context.Context, mapping *IDMapping) error
+
+	// FindByLegacyID retrieves the mapping for a legacy ID
+	FindByLegacyID(ctx context.Context, entityType EntityType, legacyID int64) (*IDMapping, error)
+
+	// FindByLegacyIDs retrieves the mappings for a set of legacy IDs, keyed by legacy ID
+	FindByLegacyIDs(ctx context.Context, entityType EntityType, legacyIDs []int64) (map[int64]*IDMapping, error)
+
+	// CountByEntity counts the mappings stored for an entity type
+	CountByEntity(ctx context.Context, entityType EntityType) (int64, error)
+}
+
+// LegacySource reads entities from an existing Broadleaf database
+type LegacySource interface {
+	// FetchBatch retrieves up to limit records with an ID greater than afterID, ordered by ID
+	FetchBatch(ctx context.Context, entityType EntityType, afterID int64, limit int) ([]*LegacyRecord, error)
+
+	// Count counts the records available for an entity type
+	Count(ctx context.Context, entityType EntityType) (int64, error)
+}
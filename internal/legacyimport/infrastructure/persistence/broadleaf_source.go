@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/legacyimport/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// legacyTable describes where an entity lives in the Broadleaf schema
+type legacyTable struct {
+	name     string
+	idColumn string
+}
+
+var broadleafTables = map[domain.EntityType]legacyTable{
+	domain.EntityCategory:            {name: "blc_category", idColumn: "category_id"},
+	domain.EntityProduct:             {name: "blc_product", idColumn: "product_id"},
+	domain.EntitySKU:                 {name: "blc_sku", idColumn: "sku_id"},
+	domain.EntityCategoryProductXref: {name: "blc_category_product_xref", idColumn: "category_product_id"},
+	domain.EntityCustomer:            {name: "blc_customer", idColumn: "customer_id"},
+	domain.EntityOffer:               {name: "blc_offer", idColumn: "offer_id"},
+	domain.EntityOrder:               {name: "blc_order", idColumn: "order_id"},
+	domain.EntityOrderItem:           {name: "blc_order_item", idColumn: "order_item_id"},
+}
+
+// BroadleafSource reads rows from an existing Broadleaf database.
+// The connection should use a read-only role; the source never writes.
+type BroadleafSource struct {
+	db *database.DB
+}
+
+// NewBroadleafSource creates a new BroadleafSource
+func NewBroadleafSource(db *database.DB) *BroadleafSource {
+	return &BroadleafSource{db: db}
+}
+
+// FetchBatch retrieves up to limit records with an ID greater than afterID, ordered by ID
+func (s *BroadleafSource) FetchBatch(ctx context.Context, entityType domain.EntityType, afterID int64, limit int) ([]*domain.LegacyRecord, error) {
+	table, err := tableFor(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Table and column names come from the fixed map above, never from input
+	query := fmt.Sprintf(
+		`SELECT * FROM %s WHERE %s > $1 ORDER BY %s LIMIT $2`,
+		table.name, table.idColumn, table.idColumn,
+	)
+
+	rows, err := s.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, fmt.Sprintf("failed to read legacy %s", table.name))
+	}
+	defer rows.Close()
+
+	fields := rows.FieldDescriptions()
+	records := make([]*domain.LegacyRecord, 0, limit)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, errors.InternalWrap(err, fmt.Sprintf("failed to scan legacy %s", table.name))
+		}
+
+		record := &domain.LegacyRecord{Values: make(map[string]interface{}, len(fields))}
+		for i, field := range fields {
+			record.Values[field.Name] = values[i]
+		}
+		record.ID = record.Int64(table.idColumn)
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, fmt.Sprintf("failed to iterate legacy %s", table.name))
+	}
+
+	return records, nil
+}
+
+// Count counts the records available for an entity type
+func (s *BroadleafSource) Count(ctx context.Context, entityType domain.EntityType) (int64, error) {
+	table, err := tableFor(entityType)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := s.db.QueryRow(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, table.name)).Scan(&count); err != nil {
+		return 0, errors.InternalWrap(err, fmt.Sprintf("failed to count legacy %s", table.name))
+	}
+	return count, nil
+}
+
+func tableFor(entityType domain.EntityType) (legacyTable, error) {
+	table, ok := broadleafTables[entityType]
+	if !ok {
+		return legacyTable{}, domain.NewDomainError(fmt.Sprintf("unsupported legacy entity: %s", entityType))
+	}
+	return table, nil
+}
@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/legacyimport/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresIDMappingRepository implements the IDMappingRepository interface
+type PostgresIDMappingRepository struct {
+	db *database.DB
+}
+
+// NewPostgresIDMappingRepository creates a new PostgresIDMappingRepository
+func NewPostgresIDMappingRepository(db *database.DB) *PostgresIDMappingRepository {
+	return &PostgresIDMappingRepository{db: db}
+}
+
+// Save stores a mapping, replacing any previous mapping for the same legacy ID
+func (r *PostgresIDMappingRepository) Save(ctx context.Context, mapping *domain.IDMapping) error {
+	query := `
+		INSERT INTO legacy_id_map (entity_type, legacy_id, new_id, checksum, import_run_id, imported_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (entity_type, legacy_id) DO UPDATE SET
+			new_id = EXCLUDED.new_id,
+			checksum = EXCLUDED.checksum,
+			import_run_id = EXCLUDED.import_run_id,
+			imported_at = EXCLUDED.imported_at`
+
+	err := r.db.Exec(ctx, query,
+		mapping.EntityType,
+		mapping.LegacyID,
+		mapping.NewID,
+		mapping.Checksum,
+		mapping.RunID,
+		mapping.ImportedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save id mapping")
+	}
+	return nil
+}
+
+// FindByLegacyID retrieves the mapping for a legacy ID
+func (r *PostgresIDMappingRepository) FindByLegacyID(ctx context.Context, entityType domain.EntityType, legacyID int64) (*domain.IDMapping, error) {
+	query := `
+		SELECT entity_type, legacy_id, new_id, checksum, import_run_id, imported_at
+		FROM legacy_id_map
+		WHERE entity_type = $1 AND legacy_id = $2`
+
+	mapping := &domain.IDMapping{}
+	err := r.db.QueryRow(ctx, query, entityType, legacyID).Scan(
+		&mapping.EntityType,
+		&mapping.LegacyID,
+		&mapping.NewID,
+		&mapping.Checksum,
+		&mapping.RunID,
+		&mapping.ImportedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find id mapping")
+	}
+	return mapping, nil
+}
+
+// FindByLegacyIDs retrieves the mappings for a set of legacy IDs, keyed by legacy ID
+func (r *PostgresIDMappingRepository) FindByLegacyIDs(ctx context.Context, entityType domain.EntityType, legacyIDs []int64) (map[int64]*domain.IDMapping, error) {
+	query := `
+		SELECT entity_type, legacy_id, new_id, checksum, import_run_id, imported_at
+		FROM legacy_id_map
+		WHERE entity_type = $1 AND legacy_id = ANY($2)`
+
+	rows, err := r.db.Query(ctx, query, entityType, legacyIDs)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find id mappings")
+	}
+	defer rows.Close()
+
+	mappings := make(map[int64]*domain.IDMapping, len(legacyIDs))
+	for rows.Next() {
+		mapping := &domain.IDMapping{}
+		if err := rows.Scan(
+			&mapping.EntityType,
+			&mapping.LegacyID,
+			&mapping.NewID,
+			&mapping.Checksum,
+			&mapping.RunID,
+			&mapping.ImportedAt,
+		); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan id mapping")
+		}
+		mappings[mapping.LegacyID] = mapping
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate id mappings")
+	}
+	return mappings, nil
+}
+
+// CountByEntity counts the mappings stored for an entity type
+func (r *PostgresIDMappingRepository) CountByEntity(ctx context.Context, entityType domain.EntityType) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM legacy_id_map WHERE entity_type = $1`, entityType).Scan(&count)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to count id mappings")
+	}
+	return count, nil
+}
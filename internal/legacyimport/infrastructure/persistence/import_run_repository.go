@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/legacyimport/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresImportRunRepository implements the ImportRunRepository interface
+type PostgresImportRunRepository struct {
+	db *database.DB
+}
+
+// NewPostgresImportRunRepository creates a new PostgresImportRunRepository
+func NewPostgresImportRunRepository(db *database.DB) *PostgresImportRunRepository {
+	return &PostgresImportRunRepository{db: db}
+}
+
+// Save creates or updates an import run
+func (r *PostgresImportRunRepository) Save(ctx context.Context, run *domain.ImportRun) error {
+	if run.ID == 0 {
+		return r.create(ctx, run)
+	}
+	return r.update(ctx, run)
+}
+
+func (r *PostgresImportRunRepository) create(ctx context.Context, run *domain.ImportRun) error {
+	query := `
+		INSERT INTO legacy_import_run (
+			entity_type, status, batch_size, last_legacy_id, processed, skipped,
+			failed, last_error, started_at, finished_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING import_run_id`
+
+	err := r.db.QueryRow(ctx, query,
+		run.EntityType,
+		run.Status,
+		run.BatchSize,
+		run.LastLegacyID,
+		run.Processed,
+		run.Skipped,
+		run.Failed,
+		run.LastError,
+		run.StartedAt,
+		run.FinishedAt,
+		run.CreatedAt,
+		run.UpdatedAt,
+	).Scan(&run.ID)
+
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create import run")
+	}
+	return nil
+}
+
+func (r *PostgresImportRunRepository) update(ctx context.Context, run *domain.ImportRun) error {
+	query := `
+		UPDATE legacy_import_run SET
+			status = $2, batch_size = $3, last_legacy_id = $4, processed = $5,
+			skipped = $6, failed = $7, last_error = $8, finished_at = $9, updated_at = $10
+		WHERE import_run_id = $1`
+
+	tag, err := r.db.Pool().Exec(ctx, query,
+		run.ID,
+		run.Status,
+		run.BatchSize,
+		run.LastLegacyID,
+		run.Processed,
+		run.Skipped,
+		run.Failed,
+		run.LastError,
+		run.FinishedAt,
+		run.UpdatedAt,
+	)
+
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update import run")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("import run not found")
+	}
+	return nil
+}
+
+// FindByID retrieves an import run by ID
+func (r *PostgresImportRunRepository) FindByID(ctx context.Context, id int64) (*domain.ImportRun, error) {
+	query := `
+		SELECT import_run_id, entity_type, status, batch_size, last_legacy_id, processed,
+			   skipped, failed, last_error, started_at, finished_at, created_at, updated_at
+		FROM legacy_import_run
+		WHERE import_run_id = $1`
+
+	return r.scanRun(r.db.QueryRow(ctx, query, id))
+}
+
+// FindLatestByEntity retrieves the most recent run for an entity type
+func (r *PostgresImportRunRepository) FindLatestByEntity(ctx context.Context, entityType domain.EntityType) (*domain.ImportRun, error) {
+	query := `
+		SELECT import_run_id, entity_type, status, batch_size, last_legacy_id, processed,
+			   skipped, failed, last_error, started_at, finished_at, created_at, updated_at
+		FROM legacy_import_run
+		WHERE entity_type = $1
+		ORDER BY import_run_id DESC
+		LIMIT 1`
+
+	return r.scanRun(r.db.QueryRow(ctx, query, entityType))
+}
+
+func (r *PostgresImportRunRepository) scanRun(row pgx.Row) (*domain.ImportRun, error) {
+	run := &domain.ImportRun{}
+	var (
+		lastError  sql.NullString
+		finishedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&run.ID,
+		&run.EntityType,
+		&run.Status,
+		&run.BatchSize,
+		&run.LastLegacyID,
+		&run.Processed,
+		&run.Skipped,
+		&run.Failed,
+		&lastError,
+		&run.StartedAt,
+		&finishedAt,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find import run")
+	}
+
+	run.LastError = lastError.String
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return run, nil
+}
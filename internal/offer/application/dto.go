@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/schedule"
 )
 
 // OfferDTO represents an offer data transfer object.
@@ -37,10 +38,26 @@ type OfferDTO struct {
 	TargetSystem              string
 	TotalitarianOffer         bool
 	UseListForDiscounts       bool
+	CustomerTags              []string
+	CustomerSegments          []string
+	FirstOrderOnly            bool
+	MinLifetimeSpend          *float64
+	MinAccountAgeDays         *int
+	GiftSKUID                 *int64
+	GiftQuantity              int
 	CreatedAt                 time.Time
 	UpdatedAt                 time.Time
 }
 
+// CustomerConditionTypeDTO describes a customer condition offers can be limited by.
+type CustomerConditionTypeDTO struct {
+	Type        domain.CustomerConditionType `json:"type"`
+	Field       string                       `json:"field"`
+	ValueType   string                       `json:"value_type"`
+	Unit        string                       `json:"unit,omitempty"`
+	Description string                       `json:"description"`
+}
+
 // OfferCodeDTO represents an offer code data transfer object.
 type OfferCodeDTO struct {
 	ID           int64
@@ -131,11 +148,29 @@ func ToOfferDTO(offer *domain.Offer) *OfferDTO {
 		TargetSystem:              offer.TargetSystem,
 		TotalitarianOffer:         offer.TotalitarianOffer,
 		UseListForDiscounts:       offer.UseListForDiscounts,
+		CustomerTags:              offer.CustomerTags,
+		CustomerSegments:          offer.CustomerSegments,
+		FirstOrderOnly:            offer.FirstOrderOnly,
+		MinLifetimeSpend:          offer.MinLifetimeSpend,
+		MinAccountAgeDays:         offer.MinAccountAgeDays,
+		GiftSKUID:                 offer.GiftSKUID,
+		GiftQuantity:              offer.GiftQuantity,
 		CreatedAt:                 offer.CreatedAt,
 		UpdatedAt:                 offer.UpdatedAt,
 	}
 }
 
+// ToCustomerConditionTypeDTO converts a customer condition definition to a CustomerConditionTypeDTO.
+func ToCustomerConditionTypeDTO(definition domain.CustomerConditionDefinition) *CustomerConditionTypeDTO {
+	return &CustomerConditionTypeDTO{
+		Type:        definition.Type,
+		Field:       definition.Field,
+		ValueType:   definition.ValueType,
+		Unit:        definition.Unit,
+		Description: definition.Description,
+	}
+}
+
 // ToOfferCodeDTO converts a domain OfferCode to an OfferCodeDTO.
 func ToOfferCodeDTO(offerCode *domain.OfferCode) *OfferCodeDTO {
 	return &OfferCodeDTO{
@@ -236,7 +271,31 @@ func ToOfferDomain(offerDTO OfferDTO) *domain.Offer {
 		TargetSystem: offerDTO.TargetSystem,
 		TotalitarianOffer: offerDTO.TotalitarianOffer,
 		UseListForDiscounts: offerDTO.UseListForDiscounts,
+		CustomerTags: offerDTO.CustomerTags,
+		CustomerSegments: offerDTO.CustomerSegments,
+		FirstOrderOnly: offerDTO.FirstOrderOnly,
+		MinLifetimeSpend: offerDTO.MinLifetimeSpend,
+		MinAccountAgeDays: offerDTO.MinAccountAgeDays,
+		GiftSKUID: offerDTO.GiftSKUID,
+		GiftQuantity: offerDTO.GiftQuantity,
 		CreatedAt: offerDTO.CreatedAt,
 		UpdatedAt: offerDTO.UpdatedAt,
 	}
 }
+
+// ScheduleOfferRequest runs an offer over business days of a site's calendar:
+// from the first business day at or after StartsAt, for BusinessDays business
+// days or until EndsAt
+type ScheduleOfferRequest struct {
+	SiteID       string         `json:"site_id"` // Empty uses the default calendar
+	StartsAt     *schedule.Time `json:"starts_at"`
+	EndsAt       *schedule.Time `json:"ends_at"`
+	BusinessDays int            `json:"business_days"`
+}
+
+// OfferScheduleDTO is the window an offer was scheduled to run over
+type OfferScheduleDTO struct {
+	OfferID   int64     `json:"offer_id"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
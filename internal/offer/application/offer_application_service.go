@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/qhato/ecommerce/internal/offer/domain"
 	"github.com/qhato/ecommerce/pkg/logger"
@@ -40,6 +41,10 @@ func NewOfferApplicationService(
 // RuleEvaluatorAdapter adapts the rules.RuleEngine to domain.RuleEvaluator
 type RuleEvaluatorAdapter struct {
 	engine *rules.RuleEngine
+
+	// compiled caches rules by expression; offers evaluate the same expression
+	// against every cart item, and compiling dominates the evaluation cost
+	compiled sync.Map
 }
 
 // Evaluate evaluates a rule expression
@@ -48,11 +53,16 @@ func (r *RuleEvaluatorAdapter) Evaluate(ruleExpression string, context map[strin
 		return true, nil
 	}
 
-	// Create and compile rule on the fly
+	if rule, ok := r.compiled.Load(ruleExpression); ok {
+		return rule.(*rules.CompiledRule).Evaluate(context)
+	}
+
+	// Compile the rule once; concurrent callers may both compile, which is harmless
 	rule, err := rules.NewRule("dynamic", ruleExpression, "Dynamic rule")
 	if err != nil {
 		return false, err
 	}
+	r.compiled.Store(ruleExpression, rule)
 
 	return rule.Evaluate(context)
 }
@@ -0,0 +1,101 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/rules"
+)
+
+// benchOfferRepository returns a fixed set of active offers
+type benchOfferRepository struct {
+	domain.OfferRepository
+	offers []*domain.Offer
+}
+
+func (r *benchOfferRepository) FindActiveOffers(ctx context.Context) ([]*domain.Offer, error) {
+	return r.offers, nil
+}
+
+// uncachedRuleEvaluator compiles a rule on every evaluation, as rules were before
+// RuleEvaluatorAdapter cached them
+type uncachedRuleEvaluator struct{}
+
+func (uncachedRuleEvaluator) Evaluate(ruleExpression string, context map[string]interface{}) (bool, error) {
+	rule, err := rules.NewRule("dynamic", ruleExpression, "Dynamic rule")
+	if err != nil {
+		return false, err
+	}
+	return rule.Evaluate(context)
+}
+
+// BenchmarkApplyOffersToOrder evaluates the target rules of 60 active offers against
+// a cart of 120 items, compiling every rule evaluation and with the compiled rule cache
+func BenchmarkApplyOffersToOrder(b *testing.B) {
+	start := time.Now().Add(-time.Hour)
+	var offers []*domain.Offer
+	for i := 1; i <= 60; i++ {
+		offers = append(offers, &domain.Offer{
+			ID:                        int64(i),
+			Name:                      fmt.Sprintf("Offer %d", i),
+			OfferType:                 domain.OfferTypePercentageOff,
+			OfferDiscountType:         domain.OfferDiscountTypePercentDiscount,
+			AdjustmentType:            domain.OfferAdjustmentTypeOrderItem,
+			OfferValue:                5,
+			OfferPriority:             i,
+			CombinableWithOtherOffers: true,
+			OfferItemTargetRule:       fmt.Sprintf(`item.Quantity >= %d && item.SKUID != "%d"`, i%3+1, i),
+			StartDate:                 start,
+		})
+	}
+
+	orderCtx := &domain.OfferContext{}
+	for i := 1; i <= 120; i++ {
+		price := decimal.NewFromInt(int64(20 + i))
+		quantity := i%3 + 1
+		orderCtx.Items = append(orderCtx.Items, domain.OfferItem{
+			ItemID:   strconv.Itoa(i),
+			SKUID:    strconv.Itoa(i),
+			Price:    price,
+			Quantity: quantity,
+			Subtotal: price.Mul(decimal.NewFromInt(int64(quantity))),
+		})
+		orderCtx.OrderSubtotal = orderCtx.OrderSubtotal.Add(price.Mul(decimal.NewFromInt(int64(quantity))))
+	}
+	orderCtx.OrderTotal = orderCtx.OrderSubtotal
+
+	repo := &benchOfferRepository{offers: offers}
+	log := *logger.NewNopLogger()
+	ctx := context.Background()
+
+	b.Run("uncached", func(b *testing.B) {
+		svc := &OfferApplicationService{
+			offerRepo: repo,
+			processor: domain.NewOfferProcessor(uncachedRuleEvaluator{}),
+			log:       log,
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := svc.ApplyOffersToOrder(ctx, orderCtx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		svc := NewOfferApplicationService(repo, nil, log)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := svc.ApplyOffersToOrder(ctx, orderCtx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
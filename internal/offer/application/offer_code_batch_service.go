@@ -0,0 +1,503 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const (
+	// maxGeneratedOfferCodes bounds the codes of one generation request
+	maxGeneratedOfferCodes = 5000000
+
+	// maxOfferCodeImportRows bounds the data rows of one import file
+	maxOfferCodeImportRows = 1000000
+
+	// offerCodeChunkSize is how many codes are inserted per statement
+	offerCodeChunkSize = 5000
+
+	// maxOfferCodeChunkAttempts bounds how often a chunk regenerates codes that were already taken
+	maxOfferCodeChunkAttempts = 5
+
+	// maxConcurrentOfferCodeGenerations bounds the generations running at once
+	maxConcurrentOfferCodeGenerations = 2
+)
+
+// OfferCodeBatchService creates offer codes in bulk, generated from a pattern or imported
+// from a list, and records each run as a batch.
+type OfferCodeBatchService interface {
+	// GenerateCodes starts generating random unique codes for an offer and returns the
+	// running batch; its progress is read with GetBatch.
+	GenerateCodes(ctx context.Context, offerID int64, req *GenerateOfferCodesRequest, actorID string) (*OfferCodeBatchDTO, error)
+
+	// ImportCodes validates every row of a CSV file of code, with optional max_uses and
+	// email_address columns, and, when all rows are valid and dryRun is false, adds the
+	// codes to the offer. Invalid rows and codes already taken are reported on the result
+	// and nothing is added.
+	ImportCodes(ctx context.Context, offerID int64, file io.Reader, dryRun bool, actorID string) (*OfferCodeImportResultDTO, error)
+
+	// GetBatch retrieves a batch.
+	GetBatch(ctx context.Context, id int64) (*OfferCodeBatchDTO, error)
+
+	// ListBatches lists the batches of an offer, newest first.
+	ListBatches(ctx context.Context, offerID int64) ([]*OfferCodeBatchDTO, error)
+}
+
+// GenerateOfferCodesRequest requests random codes for an offer. Codes are the prefix
+// followed by the pattern, with each # of the pattern replaced by a random character.
+type GenerateOfferCodesRequest struct {
+	Quantity  int        `json:"quantity"`
+	Pattern   string     `json:"pattern,omitempty"` // Defaults to ########
+	Prefix    string     `json:"prefix,omitempty"`
+	Charset   string     `json:"charset,omitempty"`  // ALPHANUMERIC (default), ALPHA, NUMERIC, HEX or the characters to use
+	MaxUses   *int       `json:"max_uses,omitempty"` // Uses per code; defaults to 1, 0 is unlimited
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+}
+
+// OfferCodeBatchDTO represents a batch of offer codes
+type OfferCodeBatchDTO struct {
+	ID          int64                       `json:"id"`
+	OfferID     int64                       `json:"offer_id"`
+	Source      domain.OfferCodeBatchSource `json:"source"`
+	Status      domain.OfferCodeBatchStatus `json:"status"`
+	Pattern     string                      `json:"pattern,omitempty"`
+	Prefix      string                      `json:"prefix,omitempty"`
+	Charset     string                      `json:"charset,omitempty"`
+	Quantity    int                         `json:"quantity"`
+	Created     int                         `json:"created"`
+	MaxUses     *int                        `json:"max_uses,omitempty"`
+	StartDate   *time.Time                  `json:"start_date,omitempty"`
+	EndDate     *time.Time                  `json:"end_date,omitempty"`
+	Error       string                      `json:"error,omitempty"`
+	CreatedBy   string                      `json:"created_by,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	CompletedAt *time.Time                  `json:"completed_at,omitempty"`
+}
+
+// ToOfferCodeBatchDTO converts a domain OfferCodeBatch to an OfferCodeBatchDTO
+func ToOfferCodeBatchDTO(batch *domain.OfferCodeBatch) *OfferCodeBatchDTO {
+	return &OfferCodeBatchDTO{
+		ID:          batch.ID,
+		OfferID:     batch.OfferID,
+		Source:      batch.Source,
+		Status:      batch.Status,
+		Pattern:     batch.Pattern,
+		Prefix:      batch.Prefix,
+		Charset:     batch.Charset,
+		Quantity:    batch.Quantity,
+		Created:     batch.Created,
+		MaxUses:     batch.MaxUses,
+		StartDate:   batch.StartDate,
+		EndDate:     batch.EndDate,
+		Error:       batch.Error,
+		CreatedBy:   batch.CreatedBy,
+		CreatedAt:   batch.CreatedAt,
+		CompletedAt: batch.CompletedAt,
+	}
+}
+
+// OfferCodeImportResultDTO summarizes an offer code import
+type OfferCodeImportResultDTO struct {
+	DryRun  bool                       `json:"dry_run"`
+	Applied bool                       `json:"applied"`
+	Rows    int                        `json:"rows"`
+	Created int                        `json:"created"`
+	Skipped int                        `json:"skipped"` // Codes taken by someone else while the import ran
+	Batch   *OfferCodeBatchDTO         `json:"batch,omitempty"`
+	Errors  []*OfferCodeImportErrorDTO `json:"errors"`
+}
+
+// OfferCodeImportErrorDTO reports an invalid row of an import file
+type OfferCodeImportErrorDTO struct {
+	Line    int    `json:"line"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// offerCodeImportRow is a parsed row of an import file
+type offerCodeImportRow struct {
+	line         int
+	code         string
+	maxUses      *int
+	emailAddress *string
+}
+
+type offerCodeBatchService struct {
+	offerRepo domain.OfferRepository
+	batchRepo domain.OfferCodeBatchRepository
+	eventBus  event.Bus
+	log       *logger.Logger
+	slots     chan struct{}
+}
+
+// NewOfferCodeBatchService creates a new instance of OfferCodeBatchService.
+func NewOfferCodeBatchService(
+	offerRepo domain.OfferRepository,
+	batchRepo domain.OfferCodeBatchRepository,
+	eventBus event.Bus,
+	log *logger.Logger,
+) OfferCodeBatchService {
+	return &offerCodeBatchService{
+		offerRepo: offerRepo,
+		batchRepo: batchRepo,
+		eventBus:  eventBus,
+		log:       log,
+		slots:     make(chan struct{}, maxConcurrentOfferCodeGenerations),
+	}
+}
+
+func (s *offerCodeBatchService) GenerateCodes(ctx context.Context, offerID int64, req *GenerateOfferCodesRequest, actorID string) (*OfferCodeBatchDTO, error) {
+	if req.Quantity <= 0 || req.Quantity > maxGeneratedOfferCodes {
+		return nil, errors.ValidationError(fmt.Sprintf("quantity must be between 1 and %d", maxGeneratedOfferCodes))
+	}
+	maxUses, err := offerCodeMaxUses(req.MaxUses)
+	if err != nil {
+		return nil, err
+	}
+	if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return nil, errors.ValidationError("end_date must not be before start_date")
+	}
+	generator, err := domain.NewOfferCodeGenerator(req.Prefix, req.Pattern, req.Charset)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := generator.CheckCapacity(req.Quantity); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.requireOffer(ctx, offerID); err != nil {
+		return nil, err
+	}
+
+	batch, err := domain.NewOfferCodeBatch(offerID, domain.OfferCodeBatchSourceGenerated, req.Quantity, actorID)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	batch.Pattern = req.Pattern
+	if batch.Pattern == "" {
+		batch.Pattern = domain.DefaultOfferCodePattern
+	}
+	batch.Prefix = req.Prefix
+	batch.Charset = req.Charset
+	if batch.Charset == "" {
+		batch.Charset = domain.DefaultOfferCodeCharset
+	}
+	batch.MaxUses = maxUses
+	batch.StartDate = req.StartDate
+	batch.EndDate = req.EndDate
+	if err := s.batchRepo.Save(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to save offer code batch: %w", err)
+	}
+
+	dto := ToOfferCodeBatchDTO(batch)
+	go s.generate(batch, generator)
+	return dto, nil
+}
+
+// generate inserts the codes of a batch chunk by chunk, recording progress after each
+func (s *offerCodeBatchService) generate(batch *domain.OfferCodeBatch, generator *domain.OfferCodeGenerator) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	ctx := context.Background()
+	log := s.log.WithField("batch_id", batch.ID).WithField("offer_id", batch.OfferID)
+	for batch.Created < batch.Quantity {
+		inserted, err := s.insertGenerated(ctx, batch, generator, min(offerCodeChunkSize, batch.Quantity-batch.Created))
+		batch.Created += inserted
+		if err != nil {
+			batch.Fail(err)
+			log.WithError(err).WithField("created", batch.Created).Error("offer code generation failed")
+			break
+		}
+		if batch.Created < batch.Quantity {
+			if err := s.batchRepo.Save(ctx, batch); err != nil {
+				log.WithError(err).Warn("failed to record offer code generation progress")
+			}
+		}
+	}
+	if batch.Status == domain.OfferCodeBatchStatusRunning {
+		batch.Complete()
+		log.WithField("created", batch.Created).Info("offer codes generated")
+	}
+
+	if err := s.batchRepo.Save(ctx, batch); err != nil {
+		log.WithError(err).Error("failed to record offer code batch result")
+	}
+	s.offerChanged(ctx, batch.OfferID)
+}
+
+// insertGenerated inserts n new codes, regenerating those that were already taken
+func (s *offerCodeBatchService) insertGenerated(ctx context.Context, batch *domain.OfferCodeBatch, generator *domain.OfferCodeGenerator, n int) (int, error) {
+	inserted := 0
+	for attempt := 0; inserted < n; attempt++ {
+		if attempt == maxOfferCodeChunkAttempts {
+			return inserted, fmt.Errorf("codes kept colliding with existing ones after %d attempts; use a pattern with more placeholders", attempt)
+		}
+		codes, err := generator.Generate(n - inserted)
+		if err != nil {
+			return inserted, err
+		}
+		count, err := s.batchRepo.InsertCodes(ctx, batch.ID, batch.NewOfferCodes(codes))
+		if err != nil {
+			return inserted, err
+		}
+		inserted += count
+	}
+	return inserted, nil
+}
+
+func (s *offerCodeBatchService) ImportCodes(ctx context.Context, offerID int64, file io.Reader, dryRun bool, actorID string) (*OfferCodeImportResultDTO, error) {
+	if err := s.requireOffer(ctx, offerID); err != nil {
+		return nil, err
+	}
+	result := &OfferCodeImportResultDTO{
+		DryRun: dryRun,
+		Errors: []*OfferCodeImportErrorDTO{},
+	}
+
+	rows, err := parseOfferCodeImport(file, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		if len(result.Errors) == 0 {
+			return nil, errors.ValidationError("offer code import file has no rows")
+		}
+		return result, nil
+	}
+
+	for start := 0; start < len(rows); start += offerCodeChunkSize {
+		chunk := rows[start:min(start+offerCodeChunkSize, len(rows))]
+		codes := make([]string, len(chunk))
+		for i, row := range chunk {
+			codes[i] = row.code
+		}
+		existing, err := s.batchRepo.FindExistingCodes(ctx, codes)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) == 0 {
+			continue
+		}
+		taken := make(map[string]bool, len(existing))
+		for _, code := range existing {
+			taken[code] = true
+		}
+		for _, row := range chunk {
+			if taken[row.code] {
+				result.addError(row.line, row.code, "code already exists")
+			}
+		}
+	}
+	if len(result.Errors) > 0 || dryRun {
+		return result, nil
+	}
+
+	batch, err := domain.NewOfferCodeBatch(offerID, domain.OfferCodeBatchSourceImported, len(rows), actorID)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.batchRepo.Save(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to save offer code batch: %w", err)
+	}
+
+	var insertErr error
+	for start := 0; start < len(rows) && insertErr == nil; start += offerCodeChunkSize {
+		chunk := rows[start:min(start+offerCodeChunkSize, len(rows))]
+		now := time.Now()
+		codes := make([]*domain.OfferCode, len(chunk))
+		for i, row := range chunk {
+			codes[i] = &domain.OfferCode{
+				OfferID:      offerID,
+				Code:         row.code,
+				MaxUses:      row.maxUses,
+				EmailAddress: row.emailAddress,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}
+		}
+		var inserted int
+		inserted, insertErr = s.batchRepo.InsertCodes(ctx, batch.ID, codes)
+		batch.Created += inserted
+	}
+	if insertErr != nil {
+		batch.Fail(insertErr)
+	} else {
+		batch.Complete()
+	}
+	if err := s.batchRepo.Save(ctx, batch); err != nil {
+		s.log.WithError(err).WithField("batch_id", batch.ID).Error("failed to record offer code batch result")
+	}
+	if batch.Created > 0 {
+		s.offerChanged(ctx, offerID)
+	}
+	if insertErr != nil {
+		return nil, errors.InternalWrap(insertErr, fmt.Sprintf("offer code import stopped after %d codes", batch.Created))
+	}
+
+	result.Applied = true
+	result.Created = batch.Created
+	result.Skipped = len(rows) - batch.Created
+	result.Batch = ToOfferCodeBatchDTO(batch)
+
+	s.log.WithField("batch_id", batch.ID).
+		WithField("offer_id", offerID).
+		WithField("created", result.Created).
+		Info("offer code import applied")
+	return result, nil
+}
+
+func (s *offerCodeBatchService) GetBatch(ctx context.Context, id int64) (*OfferCodeBatchDTO, error) {
+	batch, err := s.batchRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find offer code batch: %w", err)
+	}
+	if batch == nil {
+		return nil, errors.NotFound("offer code batch")
+	}
+	return ToOfferCodeBatchDTO(batch), nil
+}
+
+func (s *offerCodeBatchService) ListBatches(ctx context.Context, offerID int64) ([]*OfferCodeBatchDTO, error) {
+	batches, err := s.batchRepo.FindByOfferID(ctx, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find offer code batches: %w", err)
+	}
+	dtos := make([]*OfferCodeBatchDTO, len(batches))
+	for i, batch := range batches {
+		dtos[i] = ToOfferCodeBatchDTO(batch)
+	}
+	return dtos, nil
+}
+
+func (s *offerCodeBatchService) requireOffer(ctx context.Context, offerID int64) error {
+	offer, err := s.offerRepo.FindByID(ctx, offerID)
+	if err != nil {
+		return fmt.Errorf("failed to find offer by ID: %w", err)
+	}
+	if offer == nil {
+		return errors.NotFound("offer")
+	}
+	return nil
+}
+
+// offerChanged publishes an offer change so cached code lookups are invalidated
+func (s *offerCodeBatchService) offerChanged(ctx context.Context, offerID int64) {
+	if err := s.eventBus.Publish(ctx, domain.NewOfferChangedEvent(offerID)); err != nil {
+		s.log.WithError(err).WithField("offer_id", offerID).Error("failed to publish offer changed event")
+	}
+}
+
+// offerCodeMaxUses resolves the uses allowed per code: single use by default, 0 for unlimited
+func offerCodeMaxUses(maxUses *int) (*int, error) {
+	if maxUses == nil {
+		single := 1
+		return &single, nil
+	}
+	if *maxUses < 0 {
+		return nil, errors.ValidationError("max_uses cannot be negative")
+	}
+	if *maxUses == 0 {
+		return nil, nil
+	}
+	return maxUses, nil
+}
+
+// parseOfferCodeImport reads the rows of an import file, reporting invalid rows on result
+func parseOfferCodeImport(file io.Reader, result *OfferCodeImportResultDTO) ([]*offerCodeImportRow, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.ValidationError("offer code import file is empty")
+	}
+	if err != nil {
+		return nil, errors.ValidationError("invalid offer code import file: " + err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["code"]; !ok {
+		return nil, errors.ValidationError("offer code import file is missing the code column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []*offerCodeImportRow
+	lines := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Rows++
+			result.addError(parseErr.StartLine, "", "malformed row: "+parseErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, errors.ValidationError("invalid offer code import file: " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+
+		raw := field(record, "code")
+		maxUses := field(record, "max_uses")
+		emailAddress := field(record, "email_address")
+		if raw == "" && maxUses == "" && emailAddress == "" {
+			continue
+		}
+		if result.Rows++; result.Rows > maxOfferCodeImportRows {
+			return nil, errors.ValidationError(fmt.Sprintf("offer code import file exceeds %d rows", maxOfferCodeImportRows))
+		}
+
+		code, err := domain.NormalizeOfferCode(raw)
+		if err != nil {
+			result.addError(line, raw, err.Error())
+			continue
+		}
+		row := &offerCodeImportRow{line: line, code: code}
+		if maxUses == "" {
+			row.maxUses, _ = offerCodeMaxUses(nil)
+		} else {
+			value, err := strconv.Atoi(maxUses)
+			if err != nil || value < 0 {
+				result.addError(line, code, "max_uses must be a whole number, 0 for unlimited")
+				continue
+			}
+			row.maxUses, _ = offerCodeMaxUses(&value)
+		}
+		if emailAddress != "" {
+			row.emailAddress = &emailAddress
+		}
+
+		if first, ok := lines[code]; ok {
+			result.addError(line, code, fmt.Sprintf("duplicate of line %d", first))
+			continue
+		}
+		lines[code] = line
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (r *OfferCodeImportResultDTO) addError(line int, code, message string) {
+	r.Errors = append(r.Errors, &OfferCodeImportErrorDTO{Line: line, Code: code, Message: message})
+}
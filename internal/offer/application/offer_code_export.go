@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/export"
+)
+
+// NewOfferCodeExport describes a CSV export of the codes of an offer, or of one of its
+// batches when filter.BatchID is set. Its code, max_uses and email_address columns can be
+// fed back to the offer code import.
+func NewOfferCodeExport(repo domain.OfferCodeBatchRepository, filter domain.OfferCodeExportFilter) export.Export {
+	filename := "offer-codes-" + strconv.FormatInt(filter.OfferID, 10)
+	if filter.BatchID != 0 {
+		filename += "-batch-" + strconv.FormatInt(filter.BatchID, 10)
+	}
+	return export.Export{
+		Kind:     "offer-codes",
+		Filename: filename + "-" + time.Now().Format("20060102-150405") + ".csv",
+		Header: []string{
+			"code", "max_uses", "uses", "email_address", "start_date", "end_date", "archived", "created_at",
+		},
+		Rows: func(ctx context.Context, w export.RowWriter) error {
+			return repo.StreamCodes(ctx, filter, func(code *domain.OfferCode) error {
+				maxUses, emailAddress, startDate, endDate := "0", "", "", ""
+				if code.MaxUses != nil {
+					maxUses = strconv.Itoa(*code.MaxUses)
+				}
+				if code.EmailAddress != nil {
+					emailAddress = *code.EmailAddress
+				}
+				if code.StartDate != nil {
+					startDate = code.StartDate.Format(time.RFC3339)
+				}
+				if code.EndDate != nil {
+					endDate = code.EndDate.Format(time.RFC3339)
+				}
+				return w.Write([]string{
+					code.Code,
+					maxUses,
+					strconv.Itoa(code.Uses),
+					emailAddress,
+					startDate,
+					endDate,
+					strconv.FormatBool(code.Archived),
+					code.CreatedAt.Format(time.RFC3339),
+				})
+			})
+		},
+	}
+}
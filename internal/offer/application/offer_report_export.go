@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/export"
+)
+
+// NewOfferPerformanceReport is the offer performance report admins can subscribe to.
+// It takes the offer_id parameter of /admin/reports/offers/performance.
+func NewOfferPerformanceReport(service OfferReportService) export.Report {
+	return export.Report{
+		Name:   "offer_performance",
+		Title:  "Offer performance",
+		Params: []string{"offer_id"},
+		Build: func(from, to time.Time, params map[string]string) (export.Export, error) {
+			filter := &domain.OfferReportFilter{From: &from, To: &to}
+			if value := strings.TrimSpace(params["offer_id"]); value != "" {
+				offerID, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return export.Export{}, errors.ValidationError("invalid offer_id")
+				}
+				filter.OfferID = &offerID
+			}
+			return NewOfferPerformanceExport(service, filter), nil
+		},
+	}
+}
+
+// NewOfferPerformanceExport describes a CSV export of the redemptions, discount and
+// revenue influenced of each offer over the filter's range
+func NewOfferPerformanceExport(service OfferReportService, filter *domain.OfferReportFilter) export.Export {
+	filename := "offer-performance.csv"
+	if filter.From != nil {
+		filename = "offer-performance-" + filter.From.Format("20060102") + ".csv"
+	}
+	return export.Export{
+		Kind:     "offer-performance",
+		Filename: filename,
+		Header: []string{
+			"offer_id", "offer_name", "redemptions", "total_discount", "revenue_influenced", "average_discount_per_order",
+		},
+		Rows: func(ctx context.Context, w export.RowWriter) error {
+			report, err := service.GetOfferPerformance(ctx, filter)
+			if err != nil {
+				return err
+			}
+			for _, row := range report {
+				if err := w.Write([]string{
+					strconv.FormatInt(row.OfferID, 10),
+					row.OfferName,
+					strconv.FormatInt(row.Redemptions, 10),
+					strconv.FormatFloat(row.TotalDiscount, 'f', 2, 64),
+					strconv.FormatFloat(row.RevenueInfluenced, 'f', 2, 64),
+					strconv.FormatFloat(row.AverageDiscountPerOrder, 'f', 2, 64),
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
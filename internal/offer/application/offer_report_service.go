@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/offer/domain"
+)
+
+// OfferPerformanceDTO represents per-offer performance metrics.
+type OfferPerformanceDTO struct {
+	OfferID                 int64   `json:"offer_id"`
+	OfferName               string  `json:"offer_name"`
+	Redemptions             int64   `json:"redemptions"`
+	TotalDiscount           float64 `json:"total_discount"`
+	RevenueInfluenced       float64 `json:"revenue_influenced"`
+	AverageDiscountPerOrder float64 `json:"average_discount_per_order"`
+}
+
+// CouponUsageDTO represents offer code usage in a time bucket.
+type CouponUsageDTO struct {
+	OfferCodeID int64     `json:"offer_code_id"`
+	Code        string    `json:"code"`
+	OfferID     int64     `json:"offer_id"`
+	Period      time.Time `json:"period"`
+	Uses        int64     `json:"uses"`
+}
+
+// OfferReportService defines the application service for offer and coupon reporting.
+type OfferReportService interface {
+	// GetOfferPerformance returns redemption, discount and revenue metrics per offer.
+	GetOfferPerformance(ctx context.Context, filter *domain.OfferReportFilter) ([]*OfferPerformanceDTO, error)
+
+	// GetCouponUsage returns offer code usage over time.
+	GetCouponUsage(ctx context.Context, filter *domain.OfferReportFilter) ([]*CouponUsageDTO, error)
+}
+
+type offerReportService struct {
+	reportRepo domain.OfferReportRepository
+}
+
+// NewOfferReportService creates a new instance of OfferReportService.
+func NewOfferReportService(reportRepo domain.OfferReportRepository) OfferReportService {
+	return &offerReportService{reportRepo: reportRepo}
+}
+
+func (s *offerReportService) GetOfferPerformance(ctx context.Context, filter *domain.OfferReportFilter) ([]*OfferPerformanceDTO, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reportRepo.FindOfferPerformance(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load offer performance: %w", err)
+	}
+
+	dtos := make([]*OfferPerformanceDTO, len(rows))
+	for i, row := range rows {
+		dtos[i] = &OfferPerformanceDTO{
+			OfferID:                 row.OfferID,
+			OfferName:               row.OfferName,
+			Redemptions:             row.Redemptions,
+			TotalDiscount:           row.TotalDiscount,
+			RevenueInfluenced:       row.RevenueInfluenced,
+			AverageDiscountPerOrder: row.AverageDiscountPerOrder,
+		}
+	}
+	return dtos, nil
+}
+
+func (s *offerReportService) GetCouponUsage(ctx context.Context, filter *domain.OfferReportFilter) ([]*CouponUsageDTO, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reportRepo.FindCouponUsage(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load coupon usage: %w", err)
+	}
+
+	dtos := make([]*CouponUsageDTO, len(rows))
+	for i, row := range rows {
+		dtos[i] = &CouponUsageDTO{
+			OfferCodeID: row.OfferCodeID,
+			Code:        row.Code,
+			OfferID:     row.OfferID,
+			Period:      row.Period,
+			Uses:        row.Uses,
+		}
+	}
+	return dtos, nil
+}
+
+func validateReportFilter(filter *domain.OfferReportFilter) error {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return domain.NewDomainError("report end date must not be before start date")
+	}
+	if filter.Interval != "" && !filter.Interval.IsValid() {
+		return domain.NewDomainError(fmt.Sprintf("unsupported report interval: %s", filter.Interval))
+	}
+	return nil
+}
@@ -6,8 +6,16 @@ import (
 	"time"
 
 	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/cache"
+	"github.com/qhato/ecommerce/pkg/calendar"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
 )
 
+// OfferCacheNamespace holds the cached active offers and offer code lookups
+const OfferCacheNamespace = "offers"
+
 // OfferService defines the application service for offer-related operations.
 type OfferService interface {
 	// CreateOffer creates a new offer.
@@ -19,6 +27,12 @@ type OfferService interface {
 	// UpdateOffer updates an existing offer.
 	UpdateOffer(ctx context.Context, cmd *UpdateOfferCommand) (*OfferDTO, error)
 
+	// ScheduleOffer runs an offer between two dates, unarchiving it; used by catalog sale events.
+	ScheduleOffer(ctx context.Context, id int64, startDate, endDate time.Time) error
+
+	// ScheduleOfferInBusinessDays runs an offer over business days of a site's calendar.
+	ScheduleOfferInBusinessDays(ctx context.Context, id int64, req *ScheduleOfferRequest) (*OfferScheduleDTO, error)
+
 	// DeleteOffer deletes an offer.
 	DeleteOffer(ctx context.Context, id int64) error
 
@@ -75,6 +89,12 @@ type OfferService interface {
 
 	// GetOfferByCode retrieves an offer by its code.
 	GetOfferByCode(ctx context.Context, code string) (*OfferDTO, error)
+
+	// GetCustomerTargeting retrieves the tags and attributes offers target a customer by.
+	GetCustomerTargeting(ctx context.Context, customerID int64) (*domain.CustomerTargeting, error)
+
+	// ListCustomerConditionTypes describes the customer conditions offers can be limited by.
+	ListCustomerConditionTypes(ctx context.Context) []*CustomerConditionTypeDTO
 }
 
 // CreateOfferCommand is a command to create a new offer.
@@ -106,6 +126,13 @@ type CreateOfferCommand struct {
 	TargetSystem              string
 	TotalitarianOffer         bool
 	UseListForDiscounts       bool
+	CustomerTags              []string // Limits the offer to customers carrying one; empty targets everyone
+	CustomerSegments          []string // Role names or tags the customer must have one of
+	FirstOrderOnly            bool
+	MinLifetimeSpend          *float64
+	MinAccountAgeDays         *int
+	GiftSKUID                 *int64 // Required by FREE_GIFT offers
+	GiftQuantity              int    // 0 adds one
 }
 
 // UpdateOfferCommand is a command to update an existing offer.
@@ -139,6 +166,13 @@ type UpdateOfferCommand struct {
 	TargetSystem              *string
 	TotalitarianOffer         *bool
 	UseListForDiscounts       *bool
+	CustomerTags              *[]string
+	CustomerSegments          *[]string
+	FirstOrderOnly            *bool
+	MinLifetimeSpend          *float64 // Negative clears the condition
+	MinAccountAgeDays         *int     // Negative clears the condition
+	GiftSKUID                 *int64
+	GiftQuantity              *int
 }
 
 // CreateOfferCodeCommand is a command to create a new offer code.
@@ -204,6 +238,11 @@ type offerService struct {
 	offerPriceDataRepo    domain.OfferPriceDataRepository
 	qualCritOfferXrefRepo domain.QualCritOfferXrefRepository
 	tarCritOfferXrefRepo  domain.TarCritOfferXrefRepository
+	targetingRepo         domain.CustomerTargetingRepository
+	queries               *cache.QueryCache
+	calendars             calendar.Resolver // Optional, nil disables business-day scheduling
+	eventBus              event.Bus
+	log                   *logger.Logger
 }
 
 // NewOfferService creates a new instance of OfferService.
@@ -215,6 +254,11 @@ func NewOfferService(
 	offerPriceDataRepo domain.OfferPriceDataRepository,
 	qualCritOfferXrefRepo domain.QualCritOfferXrefRepository,
 	tarCritOfferXrefRepo domain.TarCritOfferXrefRepository,
+	targetingRepo domain.CustomerTargetingRepository,
+	queries *cache.QueryCache,
+	calendars calendar.Resolver, // Optional, nil disables business-day scheduling
+	eventBus event.Bus,
+	log *logger.Logger,
 ) OfferService {
 	return &offerService{
 		offerRepo:             offerRepo,
@@ -224,6 +268,11 @@ func NewOfferService(
 		offerPriceDataRepo:    offerPriceDataRepo,
 		qualCritOfferXrefRepo: qualCritOfferXrefRepo,
 		tarCritOfferXrefRepo:  tarCritOfferXrefRepo,
+		targetingRepo:         targetingRepo,
+		queries:               queries,
+		calendars:             calendars,
+		eventBus:              eventBus,
+		log:                   log,
 	}
 }
 
@@ -256,11 +305,19 @@ func (s *offerService) CreateOffer(ctx context.Context, cmd *CreateOfferCommand)
 	offer.TargetSystem = cmd.TargetSystem
 	offer.TotalitarianOffer = cmd.TotalitarianOffer
 	offer.UseListForDiscounts = cmd.UseListForDiscounts
+	offer.SetCustomerTags(cmd.CustomerTags)
+	if err := offer.SetCustomerConditions(cmd.CustomerSegments, cmd.FirstOrderOnly, cmd.MinLifetimeSpend, cmd.MinAccountAgeDays); err != nil {
+		return nil, err
+	}
+	if err := offer.SetFreeGift(cmd.GiftSKUID, cmd.GiftQuantity); err != nil {
+		return nil, err
+	}
 
 	err = s.offerRepo.Save(ctx, offer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save offer: %w", err)
 	}
+	s.offerChanged(ctx, offer.ID)
 
 	return ToOfferDTO(offer), nil
 }
@@ -373,11 +430,55 @@ func (s *offerService) UpdateOffer(ctx context.Context, cmd *UpdateOfferCommand)
 	if cmd.UseListForDiscounts != nil {
 		offer.SetUseListForDiscounts(*cmd.UseListForDiscounts)
 	}
+	if cmd.CustomerTags != nil {
+		offer.SetCustomerTags(*cmd.CustomerTags)
+	}
+	if cmd.CustomerSegments != nil || cmd.FirstOrderOnly != nil || cmd.MinLifetimeSpend != nil || cmd.MinAccountAgeDays != nil {
+		segments, firstOrderOnly := offer.CustomerSegments, offer.FirstOrderOnly
+		minLifetimeSpend, minAccountAgeDays := offer.MinLifetimeSpend, offer.MinAccountAgeDays
+		if cmd.CustomerSegments != nil {
+			segments = *cmd.CustomerSegments
+		}
+		if cmd.FirstOrderOnly != nil {
+			firstOrderOnly = *cmd.FirstOrderOnly
+		}
+		if cmd.MinLifetimeSpend != nil {
+			minLifetimeSpend = cmd.MinLifetimeSpend
+			if *cmd.MinLifetimeSpend < 0 {
+				minLifetimeSpend = nil
+			}
+		}
+		if cmd.MinAccountAgeDays != nil {
+			minAccountAgeDays = cmd.MinAccountAgeDays
+			if *cmd.MinAccountAgeDays < 0 {
+				minAccountAgeDays = nil
+			}
+		}
+		if err := offer.SetCustomerConditions(segments, firstOrderOnly, minLifetimeSpend, minAccountAgeDays); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.OfferType != nil || cmd.GiftSKUID != nil || cmd.GiftQuantity != nil {
+		giftSKUID, giftQuantity := offer.GiftSKUID, offer.GiftQuantity
+		if cmd.GiftSKUID != nil {
+			giftSKUID = cmd.GiftSKUID
+		}
+		if cmd.GiftQuantity != nil {
+			giftQuantity = *cmd.GiftQuantity
+		}
+		if cmd.GiftSKUID == nil && offer.OfferType != domain.OfferTypeFreeGift {
+			giftSKUID = nil
+		}
+		if err := offer.SetFreeGift(giftSKUID, giftQuantity); err != nil {
+			return nil, err
+		}
+	}
 
 	err = s.offerRepo.Save(ctx, offer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update offer: %w", err)
 	}
+	s.offerChanged(ctx, offer.ID)
 
 	return ToOfferDTO(offer), nil
 }
@@ -408,9 +509,65 @@ func (s *offerService) DeleteOffer(ctx context.Context, id int64) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete offer: %w", err)
 	}
+	s.offerChanged(ctx, id)
 	return nil
 }
 
+func (s *offerService) ScheduleOffer(ctx context.Context, id int64, startDate, endDate time.Time) error {
+	offer, err := s.offerRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find offer by ID for scheduling: %w", err)
+	}
+	if offer == nil {
+		return errors.NotFound("offer")
+	}
+
+	offer.StartDate = startDate
+	offer.SetEndDate(endDate)
+	offer.Activate()
+	if err := s.offerRepo.Save(ctx, offer); err != nil {
+		return fmt.Errorf("failed to schedule offer: %w", err)
+	}
+	s.offerChanged(ctx, offer.ID)
+	return nil
+}
+
+// ScheduleOfferInBusinessDays runs an offer from the first business day of the site's
+// calendar at or after the requested start, for a number of business days or until
+// the requested end. Dates without an offset are read in the calendar's time zone.
+func (s *offerService) ScheduleOfferInBusinessDays(ctx context.Context, id int64, req *ScheduleOfferRequest) (*OfferScheduleDTO, error) {
+	if s.calendars == nil {
+		return nil, errors.ServiceUnavailable("offers cannot be scheduled in business days: no business calendar is configured")
+	}
+	if req.StartsAt == nil {
+		return nil, errors.ValidationError("starts_at is required")
+	}
+	if req.BusinessDays < 0 || (req.BusinessDays > 0) == (req.EndsAt != nil) {
+		return nil, errors.ValidationError("either ends_at or a positive number of business_days is required")
+	}
+
+	cal, err := s.calendars.Calendar(ctx, req.SiteID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve business calendar: %w", err)
+	}
+	startDate := cal.Opening(req.StartsAt.Start(cal.Location))
+	var endDate time.Time
+	if req.BusinessDays > 0 {
+		// The offer runs to the end of its last business day, the opening day counting as the first
+		endDate = cal.AddBusinessDays(startDate, req.BusinessDays-1).AddDate(0, 0, 1).Add(-time.Microsecond).UTC()
+	} else {
+		endDate = req.EndsAt.End(cal.Location)
+	}
+	if !endDate.After(startDate) {
+		return nil, errors.ValidationError("the offer must end after its first business day opens")
+	}
+
+	if err := s.ScheduleOffer(ctx, id, startDate, endDate); err != nil {
+		return nil, err
+	}
+	return &OfferScheduleDTO{OfferID: id, StartDate: startDate.UTC(), EndDate: endDate}, nil
+}
+
 func (s *offerService) CreateOfferCode(ctx context.Context, offerID int64, cmd *CreateOfferCodeCommand) (*OfferCodeDTO, error) {
 	offerCode, err := domain.NewOfferCode(offerID, cmd.Code)
 	if err != nil {
@@ -428,6 +585,7 @@ func (s *offerService) CreateOfferCode(ctx context.Context, offerID int64, cmd *
 	if err != nil {
 		return nil, fmt.Errorf("failed to save offer code: %w", err)
 	}
+	s.offerChanged(ctx, offerID)
 	return ToOfferCodeDTO(offerCode), nil
 }
 
@@ -478,14 +636,21 @@ func (s *offerService) UpdateOfferCode(ctx context.Context, id int64, cmd *Updat
 	if err != nil {
 		return nil, fmt.Errorf("failed to update offer code: %w", err)
 	}
+	s.offerChanged(ctx, offerCode.OfferID)
 	return ToOfferCodeDTO(offerCode), nil
 }
 
 func (s *offerService) DeleteOfferCode(ctx context.Context, id int64) error {
+	var offerID int64
+	if offerCode, err := s.offerCodeRepo.FindByID(ctx, id); err == nil && offerCode != nil {
+		offerID = offerCode.OfferID
+	}
+
 	err := s.offerCodeRepo.Delete(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete offer code: %w", err)
 	}
+	s.offerChanged(ctx, offerID)
 	return nil
 }
 
@@ -685,8 +850,12 @@ func (s *offerService) DeleteOfferPriceData(ctx context.Context, id int64) error
 	return nil
 }
 
-// GetActiveOffers retrieves all active offers.
+// GetActiveOffers retrieves all active offers through the query cache.
 func (s *offerService) GetActiveOffers(ctx context.Context) ([]*OfferDTO, error) {
+	return cache.GetJSON(ctx, s.queries, OfferCacheNamespace, "active", s.loadActiveOffers)
+}
+
+func (s *offerService) loadActiveOffers(ctx context.Context) ([]*OfferDTO, error) {
 	offers, err := s.offerRepo.FindAll(ctx, &domain.OfferFilter{
 		ActiveOnly: true,
 	})
@@ -701,8 +870,14 @@ func (s *offerService) GetActiveOffers(ctx context.Context) ([]*OfferDTO, error)
 	return offerDTOs, nil
 }
 
-// GetOfferByCode retrieves an offer by its code.
+// GetOfferByCode retrieves an offer by its code through the query cache.
 func (s *offerService) GetOfferByCode(ctx context.Context, code string) (*OfferDTO, error) {
+	return cache.GetJSON(ctx, s.queries, OfferCacheNamespace, "code:"+code, func(ctx context.Context) (*OfferDTO, error) {
+		return s.loadOfferByCode(ctx, code)
+	})
+}
+
+func (s *offerService) loadOfferByCode(ctx context.Context, code string) (*OfferDTO, error) {
 	// First, find the offer code
 	offerCode, err := s.offerCodeRepo.FindByCode(ctx, code)
 	if err != nil {
@@ -722,4 +897,27 @@ func (s *offerService) GetOfferByCode(ctx context.Context, code string) (*OfferD
 	}
 
 	return ToOfferDTO(offer), nil
-}
\ No newline at end of file
+}
+
+func (s *offerService) GetCustomerTargeting(ctx context.Context, customerID int64) (*domain.CustomerTargeting, error) {
+	targeting, err := s.targetingRepo.FindCustomerTargeting(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customer targeting: %w", err)
+	}
+	return targeting, nil
+}
+
+func (s *offerService) ListCustomerConditionTypes(ctx context.Context) []*CustomerConditionTypeDTO {
+	types := make([]*CustomerConditionTypeDTO, len(domain.CustomerConditionDefinitions))
+	for i, definition := range domain.CustomerConditionDefinitions {
+		types[i] = ToCustomerConditionTypeDTO(definition)
+	}
+	return types
+}
+
+// offerChanged publishes an offer change; the query cache is invalidated by its subscription
+func (s *offerService) offerChanged(ctx context.Context, offerID int64) {
+	if err := s.eventBus.Publish(ctx, domain.NewOfferChangedEvent(offerID)); err != nil {
+		s.log.WithError(err).WithField("offer_id", offerID).Error("failed to publish offer changed event")
+	}
+}
@@ -0,0 +1,180 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/money"
+	"github.com/qhato/ecommerce/pkg/settings"
+)
+
+// CartSnapshot is the content of a cart as seen by the offer engine.
+type CartSnapshot struct {
+	CustomerID   *int64             `json:"-"` // Authenticated customer, never taken from a request body
+	CurrencyCode string             `json:"currency_code"`
+	LocaleCode   string             `json:"locale_code"`
+	Subtotal     float64            `json:"subtotal"`
+	Items        []CartSnapshotItem `json:"items"`
+}
+
+// CartSnapshotItem is a cart line as seen by the offer engine.
+type CartSnapshotItem struct {
+	ItemID     string   `json:"item_id"`
+	SKUID      int64    `json:"sku_id"`
+	ProductID  *int64   `json:"product_id,omitempty"`
+	CategoryID *int64   `json:"category_id,omitempty"`
+	Price      float64  `json:"price"` // Retail unit price
+	SalePrice  *float64 `json:"sale_price,omitempty"`
+	Quantity   int      `json:"quantity"`
+}
+
+// QualificationGapDTO is an offer a cart is close to qualifying for, with the spend still needed.
+type QualificationGapDTO struct {
+	OfferID          int64   `json:"offer_id"`
+	OfferName        string  `json:"offer_name"`
+	MarketingMessage string  `json:"marketing_message,omitempty"`
+	Basis            string  `json:"basis"`
+	Threshold        float64 `json:"threshold"`
+	CurrentTotal     float64 `json:"current_total"`
+	AmountNeeded     float64 `json:"amount_needed"`
+	Message          string  `json:"message"` // e.g. "Spend $20.00 more to get Free shipping"
+}
+
+// PromotionMessageService finds offers a cart is close to qualifying for, for upsell messaging.
+type PromotionMessageService interface {
+	// GetQualificationGaps returns the automatic offers the cart misses only by spend, closest first.
+	GetQualificationGaps(ctx context.Context, cart *CartSnapshot) ([]*QualificationGapDTO, error)
+}
+
+type promotionMessageService struct {
+	offerService OfferService
+	processor    *domain.OfferProcessor
+	settings     *settings.Settings
+	log          *logger.Logger
+}
+
+// NewPromotionMessageService creates a new instance of PromotionMessageService.
+// Gaps larger than the promotion message max gap setting are not advertised, 0
+// advertises any gap; the limit setting caps the number of offers returned, 0
+// returns them all.
+func NewPromotionMessageService(offerService OfferService, siteSettings *settings.Settings, log *logger.Logger) PromotionMessageService {
+	return &promotionMessageService{
+		offerService: offerService,
+		processor:    domain.NewOfferProcessor(&RuleEvaluatorAdapter{}),
+		settings:     siteSettings,
+		log:          log,
+	}
+}
+
+func (s *promotionMessageService) GetQualificationGaps(ctx context.Context, cart *CartSnapshot) ([]*QualificationGapDTO, error) {
+	offers, err := s.offerService.GetActiveOffers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active offers: %w", err)
+	}
+
+	offerCtx := toOfferContext(cart)
+	if cart.CustomerID != nil {
+		targeting, err := s.offerService.GetCustomerTargeting(ctx, *cart.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		offerCtx.CustomerTags = targeting.Tags
+		offerCtx.CustomerAttributes = targeting.Attributes
+		offerCtx.Customer = targeting
+	}
+	maxGap := s.settings.Float(settings.PromotionMessageMaxGap)
+	gaps := make([]*domain.QualificationGap, 0)
+	for _, dto := range offers {
+		// Coupon offers are not advertised, the customer has to know the code
+		if !dto.AutomaticallyAdded {
+			continue
+		}
+		gap, err := s.processor.QualificationGap(ToOfferDomain(*dto), offerCtx)
+		if err != nil {
+			s.log.WithError(err).WithField("offer_id", dto.ID).Warn("failed to evaluate offer qualification gap")
+			continue
+		}
+		if gap == nil || (maxGap > 0 && gap.Delta.InexactFloat64() > maxGap) {
+			continue
+		}
+		gaps = append(gaps, gap)
+	}
+
+	sort.SliceStable(gaps, func(i, j int) bool {
+		if !gaps[i].Delta.Equal(gaps[j].Delta) {
+			return gaps[i].Delta.LessThan(gaps[j].Delta)
+		}
+		return gaps[i].Offer.OfferPriority < gaps[j].Offer.OfferPriority
+	})
+	if limit := s.settings.Int(settings.PromotionMessageLimit); limit > 0 && len(gaps) > limit {
+		gaps = gaps[:limit]
+	}
+
+	dtos := make([]*QualificationGapDTO, len(gaps))
+	for i, gap := range gaps {
+		dtos[i] = toQualificationGapDTO(gap, cart.CurrencyCode, cart.LocaleCode)
+	}
+	return dtos, nil
+}
+
+func toOfferContext(cart *CartSnapshot) *domain.OfferContext {
+	offerCtx := &domain.OfferContext{
+		OrderSubtotal:      decimal.NewFromFloat(cart.Subtotal),
+		OrderTotal:         decimal.NewFromFloat(cart.Subtotal),
+		Items:              make([]domain.OfferItem, 0, len(cart.Items)),
+		CustomerUsageCount: make(map[int64]int),
+	}
+	if cart.CustomerID != nil {
+		customerID := strconv.FormatInt(*cart.CustomerID, 10)
+		offerCtx.CustomerID = &customerID
+	}
+
+	for _, line := range cart.Items {
+		item := domain.OfferItem{
+			ItemID:   line.ItemID,
+			SKUID:    strconv.FormatInt(line.SKUID, 10),
+			Price:    decimal.NewFromFloat(line.Price),
+			Quantity: line.Quantity,
+		}
+		if line.SalePrice != nil {
+			salePrice := decimal.NewFromFloat(*line.SalePrice)
+			item.SalePrice = &salePrice
+		}
+		item.Subtotal = item.GetEffectivePrice(true).Mul(decimal.NewFromInt(int64(line.Quantity)))
+		if line.ProductID != nil {
+			productID := strconv.FormatInt(*line.ProductID, 10)
+			item.ProductID = &productID
+		}
+		if line.CategoryID != nil {
+			categoryID := strconv.FormatInt(*line.CategoryID, 10)
+			item.CategoryID = &categoryID
+		}
+		offerCtx.Items = append(offerCtx.Items, item)
+	}
+	return offerCtx
+}
+
+func toQualificationGapDTO(gap *domain.QualificationGap, currency, locale string) *QualificationGapDTO {
+	amountNeeded := money.Round(gap.Delta.InexactFloat64(), currency)
+	reward := gap.Offer.MarketingMessage
+	if reward == "" {
+		reward = gap.Offer.Name
+	}
+
+	return &QualificationGapDTO{
+		OfferID:          gap.Offer.ID,
+		OfferName:        gap.Offer.Name,
+		MarketingMessage: gap.Offer.MarketingMessage,
+		Basis:            string(gap.Basis),
+		Threshold:        money.Round(gap.Threshold.InexactFloat64(), currency),
+		CurrentTotal:     money.Round(gap.Current.InexactFloat64(), currency),
+		AmountNeeded:     amountNeeded,
+		Message:          fmt.Sprintf("Spend %s more to get %s", money.Format(amountNeeded, currency, locale), reward),
+	}
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CustomerConditionType names a condition an offer can place on the customer
+type CustomerConditionType string
+
+const (
+	CustomerConditionSegment          CustomerConditionType = "CUSTOMER_SEGMENT"
+	CustomerConditionFirstOrder       CustomerConditionType = "FIRST_ORDER"
+	CustomerConditionMinLifetimeSpend CustomerConditionType = "MIN_LIFETIME_SPEND"
+	CustomerConditionMinAccountAge    CustomerConditionType = "MIN_ACCOUNT_AGE"
+)
+
+// CustomerConditionDefinition describes a customer condition for the admin UI
+type CustomerConditionDefinition struct {
+	Type        CustomerConditionType
+	Field       string // Offer field holding the condition
+	ValueType   string // STRING_LIST, BOOLEAN, DECIMAL or INTEGER
+	Unit        string
+	Description string
+}
+
+// CustomerConditionDefinitions lists the customer conditions offers support
+var CustomerConditionDefinitions = []CustomerConditionDefinition{
+	{
+		Type:        CustomerConditionSegment,
+		Field:       "customer_segments",
+		ValueType:   "STRING_LIST",
+		Description: "Customer has one of the role names or tags",
+	},
+	{
+		Type:        CustomerConditionFirstOrder,
+		Field:       "first_order_only",
+		ValueType:   "BOOLEAN",
+		Description: "Customer has not placed an order yet",
+	},
+	{
+		Type:        CustomerConditionMinLifetimeSpend,
+		Field:       "min_lifetime_spend",
+		ValueType:   "DECIMAL",
+		Unit:        "currency",
+		Description: "Customer's placed orders add up to at least the amount",
+	},
+	{
+		Type:        CustomerConditionMinAccountAge,
+		Field:       "min_account_age_days",
+		ValueType:   "INTEGER",
+		Unit:        "days",
+		Description: "Customer account was created at least this many days ago",
+	},
+}
+
+// SetCustomerConditions sets the conditions a customer must meet for the offer to apply
+func (o *Offer) SetCustomerConditions(segments []string, firstOrderOnly bool, minLifetimeSpend *float64, minAccountAgeDays *int) error {
+	if minLifetimeSpend != nil && *minLifetimeSpend < 0 {
+		return NewDomainError("Minimum lifetime spend cannot be negative")
+	}
+	if minAccountAgeDays != nil && *minAccountAgeDays < 0 {
+		return NewDomainError("Minimum account age cannot be negative")
+	}
+	if firstOrderOnly && minLifetimeSpend != nil && *minLifetimeSpend > 0 {
+		return NewDomainError("A first order offer cannot require a lifetime spend")
+	}
+
+	normalized := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			normalized = append(normalized, segment)
+		}
+	}
+	o.CustomerSegments = normalized
+	o.FirstOrderOnly = firstOrderOnly
+	o.MinLifetimeSpend = minLifetimeSpend
+	o.MinAccountAgeDays = minAccountAgeDays
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// HasCustomerConditions checks if the offer places any condition on the customer
+func (o *Offer) HasCustomerConditions() bool {
+	return len(o.CustomerSegments) > 0 || o.FirstOrderOnly || o.MinLifetimeSpend != nil || o.MinAccountAgeDays != nil
+}
+
+// CustomerConditionFailure returns why the customer does not meet the offer's customer
+// conditions, or an empty string when it does. A nil customer is a guest, which meets none.
+func (o *Offer) CustomerConditionFailure(customer *CustomerTargeting, now time.Time) string {
+	if !o.HasCustomerConditions() {
+		return ""
+	}
+	if customer == nil {
+		return "Offer is limited to signed-in customers"
+	}
+
+	if len(o.CustomerSegments) > 0 && !inAnySegment(o.CustomerSegments, customer.Segments) {
+		return "Offer is limited to other customer segments"
+	}
+	if o.FirstOrderOnly && customer.PlacedOrders > 0 {
+		return "Offer is limited to a customer's first order"
+	}
+	if o.MinLifetimeSpend != nil && customer.LifetimeSpend < *o.MinLifetimeSpend {
+		return fmt.Sprintf("Customer lifetime spend below minimum of %.2f", *o.MinLifetimeSpend)
+	}
+	if o.MinAccountAgeDays != nil && *o.MinAccountAgeDays > 0 {
+		if customer.RegisteredAt == nil || now.Before(customer.RegisteredAt.AddDate(0, 0, *o.MinAccountAgeDays)) {
+			return fmt.Sprintf("Customer account is younger than %d days", *o.MinAccountAgeDays)
+		}
+	}
+	return ""
+}
+
+func inAnySegment(targets, segments []string) bool {
+	for _, target := range targets {
+		for _, segment := range segments {
+			if strings.EqualFold(target, segment) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CustomerTargeting is what offers know of a customer to decide if it is in their audience
+type CustomerTargeting struct {
+	Tags          []string
+	Attributes    map[string]interface{} // Attribute name -> float64, bool or string value
+	Segments      []string               // Role names and tags of the customer
+	PlacedOrders  int                    // Submitted orders that were not cancelled or refunded, archived ones included
+	LifetimeSpend float64                // Total of the placed orders
+	RegisteredAt  *time.Time             // When the customer account was created
+}
+
+// CustomerTargetingRepository reads the tags and attributes of customers for offer targeting
+type CustomerTargetingRepository interface {
+	// FindCustomerTargeting retrieves the tags, attributes, segments and order history of a customer
+	FindCustomerTargeting(ctx context.Context, customerID int64) (*CustomerTargeting, error)
+}
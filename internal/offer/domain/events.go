@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/event"
+)
 
 // OfferCreatedEvent is published when a new offer is successfully created.
 type OfferCreatedEvent struct {
@@ -43,3 +48,24 @@ type OfferDeletedEvent struct {
 	OfferID      int64
 	DeletionTime time.Time
 }
+
+// EventOfferChanged is published whenever an offer or one of its codes changes
+const EventOfferChanged = "offer.changed"
+
+func init() {
+	event.RegisterType(EventOfferChanged, func() event.Event { return &OfferChangedEvent{} })
+}
+
+// OfferChangedEvent signals that cached offer data is stale
+type OfferChangedEvent struct {
+	event.BaseEvent
+	OfferID int64 `json:"offer_id"`
+}
+
+// NewOfferChangedEvent creates a new OfferChangedEvent
+func NewOfferChangedEvent(offerID int64) *OfferChangedEvent {
+	return &OfferChangedEvent{
+		BaseEvent: event.NewBaseEvent(EventOfferChanged, strconv.FormatInt(offerID, 10), nil),
+		OfferID:   offerID,
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// SetFreeGift sets the SKU and quantity a FREE_GIFT offer adds to qualifying carts
+func (o *Offer) SetFreeGift(skuID *int64, quantity int) error {
+	if o.OfferType != OfferTypeFreeGift {
+		if skuID != nil {
+			return NewDomainError("Only free gift offers can have a gift SKU")
+		}
+		o.GiftSKUID = nil
+		o.GiftQuantity = 0
+		o.UpdatedAt = time.Now()
+		return nil
+	}
+
+	if skuID == nil || *skuID <= 0 {
+		return NewDomainError("A free gift offer requires a gift SKU")
+	}
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return NewDomainError("Gift quantity must be positive")
+	}
+	o.GiftSKUID = skuID
+	o.GiftQuantity = quantity
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsFreeGift checks if the offer adds a gift item instead of discounting the cart
+func (o *Offer) IsFreeGift() bool {
+	return o.OfferType == OfferTypeFreeGift && o.GiftSKUID != nil
+}
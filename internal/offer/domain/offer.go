@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // OfferType defines the type of offer (e.g., PERCENT_OFF, AMOUNT_OFF, BOGO)
 type OfferType string
@@ -14,7 +17,8 @@ type OfferDiscountType string
 const (
 	OfferTypePercentageOff OfferType = "PERCENTAGE_OFF"
 	OfferTypeAmountOff     OfferType = "AMOUNT_OFF"
-	OfferTypeBOGO          OfferType = "BOGO" // Buy One Get One
+	OfferTypeBOGO          OfferType = "BOGO"      // Buy One Get One
+	OfferTypeFreeGift      OfferType = "FREE_GIFT" // Adds GiftSKUID to qualifying carts at no charge
 
 	OfferAdjustmentTypeOrderItem OfferAdjustmentType = "ORDER_ITEM_OFFER"
 	OfferAdjustmentTypeOrder     OfferAdjustmentType = "ORDER_OFFER"
@@ -55,6 +59,13 @@ type Offer struct {
 	TargetSystem              string              // From blc_offer.target_system
 	TotalitarianOffer         bool                // From blc_offer.totalitarian_offer
 	UseListForDiscounts       bool                // From blc_offer.use_list_for_discounts
+	CustomerTags              []string            // From blc_offer.customer_tags; limits the offer to customers carrying one, empty targets everyone
+	CustomerSegments          []string            // From blc_offer.customer_segments; role names or tags the customer must have one of
+	FirstOrderOnly            bool                // From blc_offer.first_order_only; only customers without placed orders
+	MinLifetimeSpend          *float64            // From blc_offer.min_lifetime_spend (numeric(19,5))
+	MinAccountAgeDays         *int                // From blc_offer.min_account_age_days (int4)
+	GiftSKUID                 *int64              // From blc_offer.gift_sku_id; the SKU a FREE_GIFT offer adds
+	GiftQuantity              int                 // From blc_offer.gift_quantity (int4)
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -253,6 +264,33 @@ func (o *Offer) SetUseListForDiscounts(useList bool) {
 	o.UpdatedAt = time.Now()
 }
 
+// SetCustomerTags limits the offer to customers carrying one of the tags; no tags target everyone
+func (o *Offer) SetCustomerTags(tags []string) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	o.CustomerTags = normalized
+	o.UpdatedAt = time.Now()
+}
+
+// TargetsCustomer checks if a customer carrying the given tags is in the offer's audience
+func (o *Offer) TargetsCustomer(customerTags []string) bool {
+	if len(o.CustomerTags) == 0 {
+		return true
+	}
+	for _, target := range o.CustomerTags {
+		for _, tag := range customerTags {
+			if strings.EqualFold(target, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // DomainError represents a business rule validation error within the domain.
 type DomainError struct {
 	Message string
@@ -0,0 +1,291 @@
+package domain
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// OfferCodeBatchSource is how the codes of a batch were produced
+type OfferCodeBatchSource string
+
+const (
+	OfferCodeBatchSourceGenerated OfferCodeBatchSource = "GENERATED"
+	OfferCodeBatchSourceImported  OfferCodeBatchSource = "IMPORTED"
+)
+
+// OfferCodeBatchStatus is the progress of a batch
+type OfferCodeBatchStatus string
+
+const (
+	OfferCodeBatchStatusRunning   OfferCodeBatchStatus = "RUNNING"
+	OfferCodeBatchStatusCompleted OfferCodeBatchStatus = "COMPLETED"
+	OfferCodeBatchStatusFailed    OfferCodeBatchStatus = "FAILED"
+)
+
+const (
+	// OfferCodePlaceholder marks the pattern positions filled with random characters
+	OfferCodePlaceholder = '#'
+
+	// DefaultOfferCodePattern is used when a generation request has no pattern
+	DefaultOfferCodePattern = "########"
+
+	// DefaultOfferCodeCharset is used when a generation request has no charset
+	DefaultOfferCodeCharset = "ALPHANUMERIC"
+
+	// MaxOfferCodeLength is the length of blc_offer_code.offer_code
+	MaxOfferCodeLength = 255
+
+	// minOfferCodeSpaceFactor is how many possible codes a pattern must allow per code
+	// requested, so collisions stay rare and valid codes cannot be found by guessing
+	minOfferCodeSpaceFactor = 10
+
+	maxOfferCodeCharsetSize = 64
+)
+
+// OfferCodeCharsets are the named character sets codes can be generated from. Letters
+// and digits that are easily confused (0/O, 1/I/L) are left out of the alphanumeric set.
+var OfferCodeCharsets = map[string]string{
+	"ALPHANUMERIC": "ABCDEFGHJKMNPQRSTUVWXYZ23456789",
+	"ALPHA":        "ABCDEFGHJKMNPQRSTUVWXYZ",
+	"NUMERIC":      "0123456789",
+	"HEX":          "0123456789ABCDEF",
+}
+
+// OfferCodeBatch records offer codes created in bulk, generated from a pattern or
+// imported from a list
+type OfferCodeBatch struct {
+	ID          int64
+	OfferID     int64
+	Source      OfferCodeBatchSource
+	Status      OfferCodeBatchStatus
+	Pattern     string // Generated batches only
+	Prefix      string // Generated batches only
+	Charset     string // Generated batches only; a named set or the characters used
+	Quantity    int    // Codes requested, or rows imported
+	Created     int    // Codes inserted so far
+	MaxUses     *int   // Applied to generated codes; imported codes may set their own
+	StartDate   *time.Time
+	EndDate     *time.Time
+	Error       string
+	CreatedBy   string
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// NewOfferCodeBatch creates a running batch of codes for an offer
+func NewOfferCodeBatch(offerID int64, source OfferCodeBatchSource, quantity int, createdBy string) (*OfferCodeBatch, error) {
+	if offerID == 0 {
+		return nil, NewDomainError("OfferID cannot be zero for OfferCodeBatch")
+	}
+	if quantity <= 0 {
+		return nil, NewDomainError("quantity must be positive")
+	}
+	return &OfferCodeBatch{
+		OfferID:   offerID,
+		Source:    source,
+		Status:    OfferCodeBatchStatusRunning,
+		Quantity:  quantity,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NewOfferCodes creates the codes of the batch for the given code strings
+func (b *OfferCodeBatch) NewOfferCodes(codes []string) []*OfferCode {
+	now := time.Now()
+	offerCodes := make([]*OfferCode, len(codes))
+	for i, code := range codes {
+		offerCodes[i] = &OfferCode{
+			OfferID:   b.OfferID,
+			Code:      code,
+			MaxUses:   b.MaxUses,
+			StartDate: b.StartDate,
+			EndDate:   b.EndDate,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	return offerCodes
+}
+
+// Complete marks the batch as done
+func (b *OfferCodeBatch) Complete() {
+	now := time.Now()
+	b.Status = OfferCodeBatchStatusCompleted
+	b.CompletedAt = &now
+}
+
+// Fail marks the batch as stopped by an error; codes created before it are kept
+func (b *OfferCodeBatch) Fail(err error) {
+	now := time.Now()
+	b.Status = OfferCodeBatchStatusFailed
+	b.Error = err.Error()
+	b.CompletedAt = &now
+}
+
+// OfferCodeGenerator generates random codes from a prefix and a pattern, e.g. prefix
+// "SUMMER-" and pattern "####-####"
+type OfferCodeGenerator struct {
+	prefix       string
+	pattern      string
+	charset      []byte
+	placeholders int
+	random       *bufio.Reader
+}
+
+// NewOfferCodeGenerator creates a generator. charset is the name of a set in
+// OfferCodeCharsets or the characters to use; empty values use the defaults.
+func NewOfferCodeGenerator(prefix, pattern, charset string) (*OfferCodeGenerator, error) {
+	if pattern == "" {
+		pattern = DefaultOfferCodePattern
+	}
+	if charset == "" {
+		charset = DefaultOfferCodeCharset
+	}
+	characters, err := resolveOfferCodeCharset(charset)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := strings.Count(pattern, string(OfferCodePlaceholder))
+	if placeholders == 0 {
+		return nil, NewDomainError(fmt.Sprintf("pattern must contain at least one %c", OfferCodePlaceholder))
+	}
+	if len(prefix)+len(pattern) > MaxOfferCodeLength {
+		return nil, NewDomainError(fmt.Sprintf("codes cannot be longer than %d characters", MaxOfferCodeLength))
+	}
+	for _, r := range prefix + pattern {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) || unicode.IsSpace(r) {
+			return nil, NewDomainError("prefix and pattern may only contain printable ASCII characters without spaces")
+		}
+	}
+
+	return &OfferCodeGenerator{
+		prefix:       prefix,
+		pattern:      pattern,
+		charset:      characters,
+		placeholders: placeholders,
+		random:       bufio.NewReader(rand.Reader),
+	}, nil
+}
+
+// CheckCapacity rejects quantities the pattern cannot generate with few collisions
+func (g *OfferCodeGenerator) CheckCapacity(quantity int) error {
+	space := math.Pow(float64(len(g.charset)), float64(g.placeholders))
+	if space < float64(quantity)*minOfferCodeSpaceFactor {
+		return NewDomainError(fmt.Sprintf(
+			"pattern allows %.0f codes, at least %d times the quantity is required; add placeholders or characters",
+			space, minOfferCodeSpaceFactor,
+		))
+	}
+	return nil
+}
+
+// Generate returns n random codes. Codes are not checked for uniqueness.
+func (g *OfferCodeGenerator) Generate(n int) ([]string, error) {
+	// Rejection sampling keeps every character equally likely
+	limit := 256 - 256%len(g.charset)
+	codes := make([]string, n)
+	var code strings.Builder
+	for i := range codes {
+		code.Reset()
+		code.WriteString(g.prefix)
+		for j := 0; j < len(g.pattern); j++ {
+			if g.pattern[j] != OfferCodePlaceholder {
+				code.WriteByte(g.pattern[j])
+				continue
+			}
+			for {
+				b, err := g.random.ReadByte()
+				if err != nil {
+					return nil, fmt.Errorf("failed to read random bytes: %w", err)
+				}
+				if int(b) < limit {
+					code.WriteByte(g.charset[int(b)%len(g.charset)])
+					break
+				}
+			}
+		}
+		codes[i] = code.String()
+	}
+	return codes, nil
+}
+
+// resolveOfferCodeCharset returns the characters of a named set, or the distinct
+// characters of a literal one
+func resolveOfferCodeCharset(charset string) ([]byte, error) {
+	if named, ok := OfferCodeCharsets[strings.ToUpper(charset)]; ok {
+		return []byte(named), nil
+	}
+
+	seen := make(map[rune]bool)
+	var characters []byte
+	for _, r := range charset {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) || unicode.IsSpace(r) || r == OfferCodePlaceholder || r == ',' || r == '"' {
+			return nil, NewDomainError(fmt.Sprintf("charset character %q is not allowed", r))
+		}
+		if !seen[r] {
+			seen[r] = true
+			characters = append(characters, byte(r))
+		}
+	}
+	if len(characters) < 2 {
+		return nil, NewDomainError("charset must be a named set or at least 2 distinct characters")
+	}
+	if len(characters) > maxOfferCodeCharsetSize {
+		return nil, NewDomainError(fmt.Sprintf("charset cannot have more than %d characters", maxOfferCodeCharsetSize))
+	}
+	return characters, nil
+}
+
+// NormalizeOfferCode trims an externally supplied code and checks it can be stored
+func NormalizeOfferCode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return "", NewDomainError("code is required")
+	}
+	if len(code) > MaxOfferCodeLength {
+		return "", NewDomainError(fmt.Sprintf("code cannot be longer than %d characters", MaxOfferCodeLength))
+	}
+	for _, r := range code {
+		if unicode.IsControl(r) {
+			return "", NewDomainError("code cannot contain control characters")
+		}
+	}
+	return code, nil
+}
+
+// OfferCodeExportFilter selects the codes of an export
+type OfferCodeExportFilter struct {
+	OfferID int64
+	BatchID int64 // Zero exports every code of the offer
+}
+
+// OfferCodeBatchRepository defines the interface for bulk offer code persistence
+type OfferCodeBatchRepository interface {
+	// Save creates a batch or updates its progress
+	Save(ctx context.Context, batch *OfferCodeBatch) error
+
+	// FindByID retrieves a batch, nil when it does not exist
+	FindByID(ctx context.Context, id int64) (*OfferCodeBatch, error)
+
+	// FindByOfferID lists the batches of an offer, newest first
+	FindByOfferID(ctx context.Context, offerID int64) ([]*OfferCodeBatch, error)
+
+	// InsertCodes inserts codes of a batch in one statement, skipping codes that already
+	// exist, and returns how many were inserted
+	InsertCodes(ctx context.Context, batchID int64, codes []*OfferCode) (int, error)
+
+	// FindExistingCodes returns the given codes that already exist
+	FindExistingCodes(ctx context.Context, codes []string) ([]string, error)
+
+	// StreamCodes calls fn for each code matching the filter, in creation order, without
+	// loading them all
+	StreamCodes(ctx context.Context, filter OfferCodeExportFilter, fn func(*OfferCode) error) error
+}
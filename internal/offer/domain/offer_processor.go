@@ -37,6 +37,9 @@ type OfferContext struct {
 	OrderTotal         decimal.Decimal
 	OrderSubtotal      decimal.Decimal
 	CustomerID         *string
+	CustomerTags       []string               // Tags of the customer, for offers targeting tags
+	CustomerAttributes map[string]interface{} // Typed attributes of the customer, available to rules as customer.attributes
+	Customer           *CustomerTargeting     // Segments and order history for customer conditions; nil for guests
 	Items              []OfferItem
 	AppliedOffers      []*OfferAdjustment
 	AvailableOffers    []*Offer
@@ -115,6 +118,16 @@ func (p *OfferProcessor) QualifyOffer(offer *Offer, ctx *OfferContext) (*OfferQu
 		return qualification, nil
 	}
 
+	// Check the customer is in the offer's audience
+	if !offer.TargetsCustomer(ctx.CustomerTags) {
+		qualification.Reason = "Offer is limited to other customers"
+		return qualification, nil
+	}
+	if reason := offer.CustomerConditionFailure(ctx.Customer, now); reason != "" {
+		qualification.Reason = reason
+		return qualification, nil
+	}
+
 	// Check order minimum total
 	if offer.OrderMinTotal > 0 {
 		if ctx.OrderSubtotal.LessThan(decimal.NewFromFloat(offer.OrderMinTotal)) {
@@ -283,6 +296,10 @@ func (p *OfferProcessor) evaluateRule(rule string, offer *Offer, ctx *OfferConte
 	ruleCtx := map[string]interface{}{
 		"offer": offer,
 		"order": ctx,
+		"customer": map[string]interface{}{
+			"tags":       ctx.CustomerTags,
+			"attributes": ctx.CustomerAttributes,
+		},
 	}
 
 	return p.ruleEvaluator.Evaluate(rule, ruleCtx)
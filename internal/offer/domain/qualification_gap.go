@@ -0,0 +1,78 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// QualificationGapBasis names the total a near-miss offer is measured against
+type QualificationGapBasis string
+
+const (
+	QualificationGapBasisOrderSubtotal   QualificationGapBasis = "ORDER_SUBTOTAL"
+	QualificationGapBasisQualifyingItems QualificationGapBasis = "QUALIFYING_ITEMS"
+)
+
+// QualificationGap describes an offer an order misses only by spend, and how much more it needs
+type QualificationGap struct {
+	Offer     *Offer
+	Basis     QualificationGapBasis
+	Threshold decimal.Decimal // Minimum the basis total has to reach
+	Current   decimal.Decimal // Current basis total
+	Delta     decimal.Decimal // Amount still to be added to the basis total
+}
+
+// QualificationGap returns how far an order is from qualifying for an offer by spend.
+// It returns nil when the offer already qualifies, or when something other than a
+// minimum total keeps it from applying, since spending more would not help then.
+func (p *OfferProcessor) QualificationGap(offer *Offer, ctx *OfferContext) (*QualificationGap, error) {
+	if offer.OrderMinTotal <= 0 && offer.QualifyingItemMinTotal <= 0 {
+		return nil, nil
+	}
+
+	qualification, err := p.QualifyOffer(offer, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if qualification.Qualifies {
+		return nil, nil
+	}
+
+	// The offer must qualify once its minimums are out of the way
+	withoutMinimums := *offer
+	withoutMinimums.OrderMinTotal = 0
+	withoutMinimums.QualifyingItemMinTotal = 0
+	qualification, err = p.QualifyOffer(&withoutMinimums, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !qualification.Qualifies {
+		return nil, nil
+	}
+
+	// Qualifying items count towards the subtotal too, so the larger gap closes both
+	var gap *QualificationGap
+	if offer.OrderMinTotal > 0 {
+		threshold := decimal.NewFromFloat(offer.OrderMinTotal)
+		if delta := threshold.Sub(ctx.OrderSubtotal); delta.IsPositive() {
+			gap = &QualificationGap{
+				Offer:     offer,
+				Basis:     QualificationGapBasisOrderSubtotal,
+				Threshold: threshold,
+				Current:   ctx.OrderSubtotal,
+				Delta:     delta,
+			}
+		}
+	}
+	if offer.QualifyingItemMinTotal > 0 {
+		threshold := decimal.NewFromFloat(offer.QualifyingItemMinTotal)
+		current := p.calculateQualifyingItemTotal(offer, ctx)
+		if delta := threshold.Sub(current); delta.IsPositive() && (gap == nil || delta.GreaterThan(gap.Delta)) {
+			gap = &QualificationGap{
+				Offer:     offer,
+				Basis:     QualificationGapBasisQualifyingItems,
+				Threshold: threshold,
+				Current:   current,
+				Delta:     delta,
+			}
+		}
+	}
+	return gap, nil
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ReportInterval defines the bucket size for time series reports
+type ReportInterval string
+
+const (
+	ReportIntervalDay   ReportInterval = "day"
+	ReportIntervalWeek  ReportInterval = "week"
+	ReportIntervalMonth ReportInterval = "month"
+)
+
+// IsValid checks if the interval is supported
+func (i ReportInterval) IsValid() bool {
+	return i == ReportIntervalDay || i == ReportIntervalWeek || i == ReportIntervalMonth
+}
+
+// OfferPerformance aggregates how an offer performed over a period.
+// Only submitted (non-cancelled) orders are counted.
+type OfferPerformance struct {
+	OfferID                 int64
+	OfferName               string
+	Redemptions             int64   // Distinct orders the offer was applied to
+	TotalDiscount           float64 // Sum of order and order item adjustments
+	RevenueInfluenced       float64 // Sum of order totals for orders using the offer
+	AverageDiscountPerOrder float64
+}
+
+// CouponUsagePoint counts offer code usage in a time bucket
+type CouponUsagePoint struct {
+	OfferCodeID int64
+	Code        string
+	OfferID     int64
+	Period      time.Time
+	Uses        int64
+}
+
+// OfferReportFilter restricts report data to a date range and optionally one offer
+type OfferReportFilter struct {
+	From     *time.Time
+	To       *time.Time
+	OfferID  *int64
+	Interval ReportInterval
+}
+
+// NewOfferReportFilter creates a default report filter covering the last 30 days
+func NewOfferReportFilter() *OfferReportFilter {
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	return &OfferReportFilter{
+		From:     &from,
+		To:       &to,
+		Interval: ReportIntervalDay,
+	}
+}
+
+// OfferReportRepository provides read-only reporting queries over adjustment and usage data.
+type OfferReportRepository interface {
+	// FindOfferPerformance aggregates redemptions, discount and revenue per offer.
+	FindOfferPerformance(ctx context.Context, filter *OfferReportFilter) ([]*OfferPerformance, error)
+
+	// FindCouponUsage counts offer code usage per time bucket.
+	FindCouponUsage(ctx context.Context, filter *OfferReportFilter) ([]*CouponUsagePoint, error)
+}
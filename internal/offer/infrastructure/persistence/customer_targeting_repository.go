@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	customerDomain "github.com/qhato/ecommerce/internal/customer/domain"
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCustomerTargetingRepository implements the CustomerTargetingRepository interface
+type PostgresCustomerTargetingRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCustomerTargetingRepository creates a new PostgresCustomerTargetingRepository
+func NewPostgresCustomerTargetingRepository(db *database.DB) *PostgresCustomerTargetingRepository {
+	return &PostgresCustomerTargetingRepository{db: db}
+}
+
+// customerPlacedOrders selects the orders that count towards a customer's order history
+const customerPlacedOrders = `
+	SELECT order_id, COALESCE(order_total, 0) AS order_total
+	FROM blc_order
+	WHERE customer_id = $1 AND submit_date IS NOT NULL
+		AND COALESCE(is_preview, FALSE) = FALSE
+		AND order_status NOT IN ('CANCELLED', 'REFUNDED')
+	UNION ALL
+	SELECT order_id, COALESCE(order_total, 0) AS order_total
+	FROM blc_order_archive
+	WHERE customer_id = $1 AND submit_date IS NOT NULL
+		AND COALESCE(is_preview, FALSE) = FALSE
+		AND order_status NOT IN ('CANCELLED', 'REFUNDED')`
+
+// FindCustomerTargeting retrieves the tags, roles and typed attributes of a customer in
+// one query and its account age and order history in another
+func (r *PostgresCustomerTargetingRepository) FindCustomerTargeting(ctx context.Context, customerID int64) (*domain.CustomerTargeting, error) {
+	query := `
+		SELECT 'tag', tag, '', ''
+		FROM blc_customer_tag
+		WHERE customer_id = $1
+		UNION ALL
+		SELECT 'attribute', name, COALESCE(value, ''), value_type
+		FROM blc_customer_attribute
+		WHERE customer_id = $1
+		UNION ALL
+		SELECT 'role', r.role_name, '', ''
+		FROM blc_customer_role cr
+		JOIN blc_role r ON r.role_id = cr.role_id
+		WHERE cr.customer_id = $1`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer targeting")
+	}
+	defer rows.Close()
+
+	targeting := &domain.CustomerTargeting{
+		Tags:       make([]string, 0),
+		Attributes: make(map[string]interface{}),
+		Segments:   make([]string, 0),
+	}
+	for rows.Next() {
+		var kind, name, value, valueType string
+		if err := rows.Scan(&kind, &name, &value, &valueType); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan customer targeting")
+		}
+		switch kind {
+		case "tag":
+			targeting.Tags = append(targeting.Tags, name)
+			targeting.Segments = append(targeting.Segments, name)
+			continue
+		case "role":
+			targeting.Segments = append(targeting.Segments, name)
+			continue
+		}
+		attribute := customerDomain.CustomerAttribute{Name: name, Value: value, Type: customerDomain.AttributeType(valueType)}
+		targeting.Attributes[name] = attribute.TypedValue()
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate customer targeting")
+	}
+
+	historyQuery := `
+		SELECT c.date_created, COUNT(o.order_id), COALESCE(SUM(o.order_total), 0)
+		FROM blc_customer c
+		LEFT JOIN (` + customerPlacedOrders + `) o ON TRUE
+		WHERE c.customer_id = $1
+		GROUP BY c.date_created`
+	var registeredAt sql.NullTime
+	err = r.db.QueryRow(ctx, historyQuery, customerID).Scan(&registeredAt, &targeting.PlacedOrders, &targeting.LifetimeSpend)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, errors.InternalWrap(err, "failed to find customer order history")
+	}
+	if registeredAt.Valid {
+		targeting.RegisteredAt = &registeredAt.Time
+	}
+	return targeting, nil
+}
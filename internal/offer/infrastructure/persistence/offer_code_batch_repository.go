@@ -0,0 +1,226 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOfferCodeBatchRepository implements the OfferCodeBatchRepository interface
+type PostgresOfferCodeBatchRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOfferCodeBatchRepository creates a new PostgresOfferCodeBatchRepository
+func NewPostgresOfferCodeBatchRepository(db *database.DB) *PostgresOfferCodeBatchRepository {
+	return &PostgresOfferCodeBatchRepository{db: db}
+}
+
+const offerCodeBatchColumns = `
+	batch_id, offer_id, source, status, COALESCE(pattern, ''), COALESCE(prefix, ''), COALESCE(charset, ''),
+	quantity, created_count, max_uses, start_date, end_date, COALESCE(error, ''), COALESCE(created_by, ''),
+	created_at, completed_at`
+
+// Save creates a batch or updates its progress
+func (r *PostgresOfferCodeBatchRepository) Save(ctx context.Context, batch *domain.OfferCodeBatch) error {
+	if batch.ID == 0 {
+		query := `
+			INSERT INTO offer_code_batch (
+				offer_id, source, status, pattern, prefix, charset, quantity, created_count,
+				max_uses, start_date, end_date, error, created_by, created_at, completed_at
+			) VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, ''), $14, $15)
+			RETURNING batch_id`
+		err := r.db.QueryRow(ctx, query,
+			batch.OfferID, string(batch.Source), string(batch.Status), batch.Pattern, batch.Prefix, batch.Charset, batch.Quantity, batch.Created,
+			batch.MaxUses, batch.StartDate, batch.EndDate, batch.Error, batch.CreatedBy, batch.CreatedAt, batch.CompletedAt,
+		).Scan(&batch.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create offer code batch")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE offer_code_batch SET
+			status = $1, quantity = $2, created_count = $3, error = NULLIF($4, ''), completed_at = $5
+		WHERE batch_id = $6`
+	tag, err := r.db.Pool().Exec(ctx, query, string(batch.Status), batch.Quantity, batch.Created, batch.Error, batch.CompletedAt, batch.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update offer code batch")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("offer code batch not found")
+	}
+	return nil
+}
+
+// FindByID retrieves a batch, nil when it does not exist
+func (r *PostgresOfferCodeBatchRepository) FindByID(ctx context.Context, id int64) (*domain.OfferCodeBatch, error) {
+	query := `SELECT ` + offerCodeBatchColumns + ` FROM offer_code_batch WHERE batch_id = $1`
+	batch, err := scanOfferCodeBatch(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find offer code batch")
+	}
+	return batch, nil
+}
+
+// FindByOfferID lists the batches of an offer, newest first
+func (r *PostgresOfferCodeBatchRepository) FindByOfferID(ctx context.Context, offerID int64) ([]*domain.OfferCodeBatch, error) {
+	query := `SELECT ` + offerCodeBatchColumns + ` FROM offer_code_batch WHERE offer_id = $1 ORDER BY created_at DESC, batch_id DESC`
+	rows, err := r.db.Query(ctx, query, offerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find offer code batches")
+	}
+	defer rows.Close()
+
+	batches := make([]*domain.OfferCodeBatch, 0)
+	for rows.Next() {
+		batch, err := scanOfferCodeBatch(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan offer code batch")
+		}
+		batches = append(batches, batch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate offer code batches")
+	}
+	return batches, nil
+}
+
+// InsertCodes inserts codes of a batch in one statement, skipping codes that already exist
+func (r *PostgresOfferCodeBatchRepository) InsertCodes(ctx context.Context, batchID int64, codes []*domain.OfferCode) (int, error) {
+	if len(codes) == 0 {
+		return 0, nil
+	}
+
+	offerIDs := make([]int64, len(codes))
+	values := make([]string, len(codes))
+	maxUses := make([]*int, len(codes))
+	emailAddresses := make([]*string, len(codes))
+	startDates := make([]*time.Time, len(codes))
+	endDates := make([]*time.Time, len(codes))
+	createdAts := make([]time.Time, len(codes))
+	for i, code := range codes {
+		offerIDs[i] = code.OfferID
+		values[i] = code.Code
+		maxUses[i] = code.MaxUses
+		emailAddresses[i] = code.EmailAddress
+		startDates[i] = code.StartDate
+		endDates[i] = code.EndDate
+		createdAts[i] = code.CreatedAt
+	}
+
+	query := `
+		INSERT INTO blc_offer_code (
+			offer_id, offer_code, max_uses, email_address, start_date, end_date, created_at,
+			uses, archived, updated_at, batch_id
+		)
+		SELECT c.offer_id, c.offer_code, c.max_uses, c.email_address, c.start_date, c.end_date, c.created_at,
+			0, 'N', c.created_at, $8
+		FROM unnest($1::bigint[], $2::text[], $3::int[], $4::text[], $5::timestamptz[], $6::timestamptz[], $7::timestamptz[])
+			AS c(offer_id, offer_code, max_uses, email_address, start_date, end_date, created_at)
+		ON CONFLICT (offer_code) DO NOTHING`
+	tag, err := r.db.Pool().Exec(ctx, query, offerIDs, values, maxUses, emailAddresses, startDates, endDates, createdAts, batchID)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to insert offer codes")
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// FindExistingCodes returns the given codes that already exist
+func (r *PostgresOfferCodeBatchRepository) FindExistingCodes(ctx context.Context, codes []string) ([]string, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT offer_code FROM blc_offer_code WHERE offer_code = ANY($1)`, codes)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find existing offer codes")
+	}
+	defer rows.Close()
+
+	var existing []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan existing offer code")
+		}
+		existing = append(existing, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate existing offer codes")
+	}
+	return existing, nil
+}
+
+// StreamCodes calls fn for each code matching the filter, in creation order
+func (r *PostgresOfferCodeBatchRepository) StreamCodes(ctx context.Context, filter domain.OfferCodeExportFilter, fn func(*domain.OfferCode) error) error {
+	query := `
+		SELECT ` + offerCodeColumns + `
+		FROM blc_offer_code
+		WHERE offer_id = $1 AND ($2::bigint = 0 OR batch_id = $2)
+		ORDER BY offer_code_id`
+
+	rows, err := r.db.Query(ctx, query, filter.OfferID, filter.BatchID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to export offer codes")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		offerCode, err := scanOfferCode(rows)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to scan exported offer code")
+		}
+		if err := fn(offerCode); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to iterate exported offer codes")
+	}
+	return nil
+}
+
+func scanOfferCodeBatch(row pgx.Row) (*domain.OfferCodeBatch, error) {
+	batch := &domain.OfferCodeBatch{}
+	var (
+		source, status     string
+		maxUses            sql.NullInt32
+		startDate, endDate sql.NullTime
+		completedAt        sql.NullTime
+	)
+	err := row.Scan(
+		&batch.ID, &batch.OfferID, &source, &status, &batch.Pattern, &batch.Prefix, &batch.Charset,
+		&batch.Quantity, &batch.Created, &maxUses, &startDate, &endDate, &batch.Error, &batch.CreatedBy,
+		&batch.CreatedAt, &completedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	batch.Source = domain.OfferCodeBatchSource(source)
+	batch.Status = domain.OfferCodeBatchStatus(status)
+	if maxUses.Valid {
+		value := int(maxUses.Int32)
+		batch.MaxUses = &value
+	}
+	if startDate.Valid {
+		batch.StartDate = &startDate.Time
+	}
+	if endDate.Valid {
+		batch.EndDate = &endDate.Time
+	}
+	if completedAt.Valid {
+		batch.CompletedAt = &completedAt.Time
+	}
+	return batch, nil
+}
@@ -2,9 +2,13 @@ package persistence
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/qhato/ecommerce/internal/offer/domain"
 	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
 )
 
 // PostgresOfferCodeRepository implements the OfferCodeRepository interface
@@ -17,38 +21,145 @@ func NewPostgresOfferCodeRepository(db *database.DB) *PostgresOfferCodeRepositor
 	return &PostgresOfferCodeRepository{db: db}
 }
 
+const offerCodeColumns = `
+	offer_code_id, offer_id, offer_code, max_uses, COALESCE(uses, 0), email_address,
+	start_date, end_date, COALESCE(archived, 'N'), created_at, updated_at`
+
 // Save stores a new offer code or updates an existing one.
 func (r *PostgresOfferCodeRepository) Save(ctx context.Context, offerCode *domain.OfferCode) error {
-	// TODO: Implement actual persistence logic
+	archivedFlag := "N"
+	if offerCode.Archived {
+		archivedFlag = "Y"
+	}
+
+	if offerCode.ID == 0 {
+		query := `
+			INSERT INTO blc_offer_code (
+				offer_id, offer_code, max_uses, uses, email_address, start_date, end_date,
+				archived, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING offer_code_id`
+		err := r.db.QueryRow(ctx, query,
+			offerCode.OfferID, offerCode.Code, offerCode.MaxUses, offerCode.Uses, offerCode.EmailAddress,
+			offerCode.StartDate, offerCode.EndDate, archivedFlag, offerCode.CreatedAt, offerCode.UpdatedAt,
+		).Scan(&offerCode.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create offer code")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE blc_offer_code SET
+			offer_code = $1, max_uses = $2, uses = $3, email_address = $4, start_date = $5,
+			end_date = $6, archived = $7, updated_at = $8
+		WHERE offer_code_id = $9`
+	tag, err := r.db.Pool().Exec(ctx, query,
+		offerCode.Code, offerCode.MaxUses, offerCode.Uses, offerCode.EmailAddress, offerCode.StartDate,
+		offerCode.EndDate, archivedFlag, offerCode.UpdatedAt, offerCode.ID,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update offer code")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("offer code not found")
+	}
 	return nil
 }
 
 // FindByID retrieves an offer code by its unique identifier.
 func (r *PostgresOfferCodeRepository) FindByID(ctx context.Context, id int64) (*domain.OfferCode, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + offerCodeColumns + ` FROM blc_offer_code WHERE offer_code_id = $1`
+	offerCode, err := scanOfferCode(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find offer code by ID")
+	}
+	return offerCode, nil
 }
 
 // FindByCode retrieves an offer code by its code string.
 func (r *PostgresOfferCodeRepository) FindByCode(ctx context.Context, code string) (*domain.OfferCode, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + offerCodeColumns + ` FROM blc_offer_code WHERE offer_code = $1`
+	offerCode, err := scanOfferCode(r.db.QueryRow(ctx, query, code))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find offer code")
+	}
+	return offerCode, nil
 }
 
 // FindByOfferID retrieves all offer codes associated with a given offer ID.
 func (r *PostgresOfferCodeRepository) FindByOfferID(ctx context.Context, offerID int64) ([]*domain.OfferCode, error) {
-	// TODO: Implement actual persistence logic
-	return nil, nil
+	query := `SELECT ` + offerCodeColumns + ` FROM blc_offer_code WHERE offer_id = $1 ORDER BY offer_code_id`
+	rows, err := r.db.Query(ctx, query, offerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find offer codes by offer ID")
+	}
+	defer rows.Close()
+
+	var offerCodes []*domain.OfferCode
+	for rows.Next() {
+		offerCode, err := scanOfferCode(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan offer code")
+		}
+		offerCodes = append(offerCodes, offerCode)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate offer codes")
+	}
+	return offerCodes, nil
 }
 
 // Delete removes an offer code by its unique identifier.
 func (r *PostgresOfferCodeRepository) Delete(ctx context.Context, id int64) error {
-	// TODO: Implement actual persistence logic
+	if err := r.db.Exec(ctx, "DELETE FROM blc_offer_code WHERE offer_code_id = $1", id); err != nil {
+		return errors.InternalWrap(err, "failed to delete offer code")
+	}
 	return nil
 }
 
 // DeleteByOfferID removes all offer codes associated with a given offer ID.
 func (r *PostgresOfferCodeRepository) DeleteByOfferID(ctx context.Context, offerID int64) error {
-	// TODO: Implement actual persistence logic
+	if err := r.db.Exec(ctx, "DELETE FROM blc_offer_code WHERE offer_id = $1", offerID); err != nil {
+		return errors.InternalWrap(err, "failed to delete offer codes by offer ID")
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+func scanOfferCode(row pgx.Row) (*domain.OfferCode, error) {
+	offerCode := &domain.OfferCode{}
+	var (
+		archivedFlag       string
+		maxUses            sql.NullInt32
+		emailAddress       sql.NullString
+		startDate, endDate sql.NullTime
+	)
+	err := row.Scan(
+		&offerCode.ID, &offerCode.OfferID, &offerCode.Code, &maxUses, &offerCode.Uses, &emailAddress,
+		&startDate, &endDate, &archivedFlag, &offerCode.CreatedAt, &offerCode.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	offerCode.Archived = archivedFlag == "Y"
+	if maxUses.Valid {
+		value := int(maxUses.Int32)
+		offerCode.MaxUses = &value
+	}
+	if emailAddress.Valid {
+		offerCode.EmailAddress = &emailAddress.String
+	}
+	if startDate.Valid {
+		offerCode.StartDate = &startDate.Time
+	}
+	if endDate.Valid {
+		offerCode.EndDate = &endDate.Time
+	}
+	return offerCode, nil
+}
@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOfferReportRepository implements domain.OfferReportRepository for PostgreSQL.
+type PostgresOfferReportRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOfferReportRepository creates a new PostgresOfferReportRepository.
+func NewPostgresOfferReportRepository(db *database.DB) *PostgresOfferReportRepository {
+	return &PostgresOfferReportRepository{db: db}
+}
+
+// FindOfferPerformance aggregates redemptions, discount and revenue per offer.
+func (r *PostgresOfferReportRepository) FindOfferPerformance(ctx context.Context, filter *domain.OfferReportFilter) ([]*domain.OfferPerformance, error) {
+	// Order-level and item-level adjustments are combined per order first so an
+	// order with several discounted items counts as a single redemption.
+	query := `
+		WITH adjustments AS (
+			SELECT oa.offer_id, oa.order_id, oa.adjustment_value
+			FROM blc_order_adjustment oa
+			UNION ALL
+			SELECT oia.offer_id, oi.order_id, oia.adjustment_value
+			FROM blc_order_item_adjustment oia
+			JOIN blc_order_item oi ON oi.order_item_id = oia.order_item_id
+		),
+		per_order AS (
+			SELECT a.offer_id, a.order_id, SUM(a.adjustment_value) AS discount, MAX(o.order_total) AS order_total
+			FROM adjustments a
+			JOIN blc_order o ON o.order_id = a.order_id
+			WHERE o.submit_date IS NOT NULL
+				AND o.order_status <> 'CANCELLED'
+				AND ($1::timestamp IS NULL OR o.submit_date >= $1)
+				AND ($2::timestamp IS NULL OR o.submit_date < $2)
+				AND ($3::bigint IS NULL OR a.offer_id = $3)
+			GROUP BY a.offer_id, a.order_id
+		)
+		SELECT p.offer_id, COALESCE(f.offer_name, ''), COUNT(*), SUM(p.discount), SUM(COALESCE(p.order_total, 0)),
+			AVG(p.discount)
+		FROM per_order p
+		LEFT JOIN blc_offer f ON f.offer_id = p.offer_id
+		GROUP BY p.offer_id, f.offer_name
+		ORDER BY COUNT(*) DESC, p.offer_id`
+
+	rows, err := r.db.Query(ctx, query, filter.From, filter.To, filter.OfferID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query offer performance")
+	}
+	defer rows.Close()
+
+	results := make([]*domain.OfferPerformance, 0)
+	for rows.Next() {
+		p := &domain.OfferPerformance{}
+		if err := rows.Scan(
+			&p.OfferID,
+			&p.OfferName,
+			&p.Redemptions,
+			&p.TotalDiscount,
+			&p.RevenueInfluenced,
+			&p.AverageDiscountPerOrder,
+		); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan offer performance")
+		}
+		results = append(results, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate offer performance")
+	}
+	return results, nil
+}
+
+// FindCouponUsage counts offer code usage per time bucket.
+func (r *PostgresOfferReportRepository) FindCouponUsage(ctx context.Context, filter *domain.OfferReportFilter) ([]*domain.CouponUsagePoint, error) {
+	interval := filter.Interval
+	if !interval.IsValid() {
+		interval = domain.ReportIntervalDay
+	}
+
+	// date_trunc takes the interval as a literal; it is validated above
+	query := fmt.Sprintf(`
+		SELECT oc.offer_code_id, oc.offer_code, oc.offer_id, date_trunc('%s', o.submit_date) AS period, COUNT(*)
+		FROM blc_order_offer_code_xref x
+		JOIN blc_offer_code oc ON oc.offer_code_id = x.offer_code_id
+		JOIN blc_order o ON o.order_id = x.order_id
+		WHERE o.submit_date IS NOT NULL
+			AND o.order_status <> 'CANCELLED'
+			AND ($1::timestamp IS NULL OR o.submit_date >= $1)
+			AND ($2::timestamp IS NULL OR o.submit_date < $2)
+			AND ($3::bigint IS NULL OR oc.offer_id = $3)
+		GROUP BY oc.offer_code_id, oc.offer_code, oc.offer_id, period
+		ORDER BY period, oc.offer_code`, interval)
+
+	rows, err := r.db.Query(ctx, query, filter.From, filter.To, filter.OfferID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query coupon usage")
+	}
+	defer rows.Close()
+
+	results := make([]*domain.CouponUsagePoint, 0)
+	for rows.Next() {
+		p := &domain.CouponUsagePoint{}
+		if err := rows.Scan(&p.OfferCodeID, &p.Code, &p.OfferID, &p.Period, &p.Uses); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan coupon usage")
+		}
+		results = append(results, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate coupon usage")
+	}
+	return results, nil
+}
@@ -41,11 +41,12 @@ func (r *PostgresOfferRepository) create(ctx context.Context, offer *domain.Offe
 			offer_item_target_rule, order_min_total, offer_priority,
 			qualifying_item_min_total, requires_related_tar_qual, start_date,
 			target_min_total, target_system, totalitarian_offer, use_list_for_discounts,
-			date_created, date_updated
+			date_created, date_updated, customer_tags, customer_segments, first_order_only,
+			min_lifetime_spend, min_account_age_days, gift_sku_id, gift_quantity
 		) VALUES (
 			nextval('blc_offer_seq'), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
 			$12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25,
-			$26, $27, $28, $29, $30
+			$26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37
 		) RETURNING offer_id`
 
 	archivedFlag := "N"
@@ -62,7 +63,8 @@ func (r *PostgresOfferRepository) create(ctx context.Context, offer *domain.Offe
 		offer.OfferItemTargetRule, offer.OrderMinTotal, offer.OfferPriority,
 		offer.QualifyingItemMinTotal, offer.RequiresRelatedTarQual, offer.StartDate,
 		offer.TargetMinTotal, offer.TargetSystem, offer.TotalitarianOffer, offer.UseListForDiscounts,
-		offer.CreatedAt, offer.UpdatedAt,
+		offer.CreatedAt, offer.UpdatedAt, customerTagsParam(offer.CustomerTags), customerTagsParam(offer.CustomerSegments),
+		offer.FirstOrderOnly, offer.MinLifetimeSpend, offer.MinAccountAgeDays, offer.GiftSKUID, offer.GiftQuantity,
 	).Scan(&offer.ID)
 
 	if err != nil {
@@ -82,7 +84,8 @@ func (r *PostgresOfferRepository) update(ctx context.Context, offer *domain.Offe
 			offer_item_target_rule = $19, order_min_total = $20, offer_priority = $21,
 			qualifying_item_min_total = $22, requires_related_tar_qual = $23, start_date = $24,
 			target_min_total = $25, target_system = $26, totalitarian_offer = $27, use_list_for_discounts = $28,
-			date_updated = $29
+			date_updated = $29, customer_tags = $31, customer_segments = $32, first_order_only = $33,
+			min_lifetime_spend = $34, min_account_age_days = $35, gift_sku_id = $36, gift_quantity = $37
 		WHERE offer_id = $30`
 
 	archivedFlag := "N"
@@ -99,7 +102,8 @@ func (r *PostgresOfferRepository) update(ctx context.Context, offer *domain.Offe
 		offer.OfferItemTargetRule, offer.OrderMinTotal, offer.OfferPriority,
 		offer.QualifyingItemMinTotal, offer.RequiresRelatedTarQual, offer.StartDate,
 		offer.TargetMinTotal, offer.TargetSystem, offer.TotalitarianOffer, offer.UseListForDiscounts,
-		offer.UpdatedAt, offer.ID,
+		offer.UpdatedAt, offer.ID, customerTagsParam(offer.CustomerTags), customerTagsParam(offer.CustomerSegments),
+		offer.FirstOrderOnly, offer.MinLifetimeSpend, offer.MinAccountAgeDays, offer.GiftSKUID, offer.GiftQuantity,
 	)
 
 	if err != nil {
@@ -124,7 +128,8 @@ func (r *PostgresOfferRepository) FindByID(ctx context.Context, id int64) (*doma
 			offer_item_target_rule, order_min_total, offer_priority,
 			qualifying_item_min_total, requires_related_tar_qual, start_date,
 			target_min_total, target_system, totalitarian_offer, use_list_for_discounts,
-			date_created, date_updated
+			date_created, date_updated, customer_tags, customer_segments, first_order_only,
+			min_lifetime_spend, min_account_age_days, gift_sku_id, gift_quantity
 		FROM blc_offer
 		WHERE offer_id = $1`
 
@@ -151,6 +156,9 @@ func (r *PostgresOfferRepository) FindByID(ctx context.Context, id int64) (*doma
 		offerDiscountType               sql.NullString
 		offerType                       sql.NullString
 		adjustmentType                  sql.NullString
+		minLifetimeSpend                sql.NullFloat64
+		minAccountAgeDays               sql.NullInt32
+		giftSKUID                       sql.NullInt64
 	)
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
@@ -185,6 +193,13 @@ func (r *PostgresOfferRepository) FindByID(ctx context.Context, id int64) (*doma
 		&useListForDiscounts,
 		&offer.CreatedAt,
 		&offer.UpdatedAt,
+		&offer.CustomerTags,
+		&offer.CustomerSegments,
+		&offer.FirstOrderOnly,
+		&minLifetimeSpend,
+		&minAccountAgeDays,
+		&giftSKUID,
+		&offer.GiftQuantity,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -254,6 +269,16 @@ func (r *PostgresOfferRepository) FindByID(ctx context.Context, id int64) (*doma
 	if targetSystem.Valid {
 		offer.TargetSystem = targetSystem.String
 	}
+	if minLifetimeSpend.Valid {
+		offer.MinLifetimeSpend = &minLifetimeSpend.Float64
+	}
+	if minAccountAgeDays.Valid {
+		minAccountAgeDaysInt := int(minAccountAgeDays.Int32)
+		offer.MinAccountAgeDays = &minAccountAgeDaysInt
+	}
+	if giftSKUID.Valid {
+		offer.GiftSKUID = &giftSKUID.Int64
+	}
 
 	return offer, nil
 }
@@ -271,7 +296,8 @@ func (r *PostgresOfferRepository) FindAll(ctx context.Context, filter *domain.Of
 			offer_item_target_rule, order_min_total, offer_priority,
 			qualifying_item_min_total, requires_related_tar_qual, start_date,
 			target_min_total, target_system, totalitarian_offer, use_list_for_discounts,
-			date_created, date_updated
+			date_created, date_updated, customer_tags, customer_segments, first_order_only,
+			min_lifetime_spend, min_account_age_days, gift_sku_id, gift_quantity
 		FROM blc_offer
 		WHERE 1=1`
 
@@ -340,6 +366,9 @@ func (r *PostgresOfferRepository) FindAll(ctx context.Context, filter *domain.Of
 			offerDiscountType               sql.NullString
 			offerType                       sql.NullString
 			adjustmentType                  sql.NullString
+			minLifetimeSpend                sql.NullFloat64
+			minAccountAgeDays               sql.NullInt32
+			giftSKUID                       sql.NullInt64
 		)
 
 		err := rows.Scan(
@@ -374,6 +403,13 @@ func (r *PostgresOfferRepository) FindAll(ctx context.Context, filter *domain.Of
 			&useListForDiscounts,
 			&offer.CreatedAt,
 			&offer.UpdatedAt,
+			&offer.CustomerTags,
+			&offer.CustomerSegments,
+			&offer.FirstOrderOnly,
+			&minLifetimeSpend,
+			&minAccountAgeDays,
+			&giftSKUID,
+			&offer.GiftQuantity,
 		)
 		if err != nil {
 			return nil, errors.InternalWrap(err, "failed to scan offer")
@@ -439,6 +475,16 @@ func (r *PostgresOfferRepository) FindAll(ctx context.Context, filter *domain.Of
 		if targetSystem.Valid {
 			offer.TargetSystem = targetSystem.String
 		}
+		if minLifetimeSpend.Valid {
+			offer.MinLifetimeSpend = &minLifetimeSpend.Float64
+		}
+		if minAccountAgeDays.Valid {
+			minAccountAgeDaysInt := int(minAccountAgeDays.Int32)
+			offer.MinAccountAgeDays = &minAccountAgeDaysInt
+		}
+		if giftSKUID.Valid {
+			offer.GiftSKUID = &giftSKUID.Int64
+		}
 
 		offers = append(offers, offer)
 	}
@@ -470,3 +516,11 @@ func (r *PostgresOfferRepository) Delete(ctx context.Context, id int64) error {
 	}
 	return nil
 }
+
+// customerTagsParam keeps an offer without audience tags or segments from writing NULL into the NOT NULL column
+func customerTagsParam(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/offer/application"
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/export"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// maxOfferCodeImportBytes bounds uploaded offer code import files
+const maxOfferCodeImportBytes = 32 << 20
+
+// AdminOfferCodeBatchHandler handles bulk offer code generation, imports and exports
+type AdminOfferCodeBatchHandler struct {
+	batchService   application.OfferCodeBatchService
+	batchRepo      domain.OfferCodeBatchRepository
+	jobs           *export.JobManager
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminOfferCodeBatchHandler creates a new AdminOfferCodeBatchHandler
+func NewAdminOfferCodeBatchHandler(
+	batchService application.OfferCodeBatchService,
+	batchRepo domain.OfferCodeBatchRepository,
+	jobs *export.JobManager,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOfferCodeBatchHandler {
+	return &AdminOfferCodeBatchHandler{
+		batchService:   batchService,
+		batchRepo:      batchRepo,
+		jobs:           jobs,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers offer code batch routes; exports require the admin role
+func (h *AdminOfferCodeBatchHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/admin/offers/{id}/code-batches", h.GenerateCodes)
+		r.Get("/admin/offers/{id}/code-batches", h.ListBatches)
+		r.Post("/admin/offers/{id}/codes/import", h.ImportCodes)
+		r.Get("/admin/offer-code-batches/{batchId}", h.GetBatch)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Use(middleware.RequireRole("admin"))
+		r.Get("/admin/exports/offer-codes", h.ExportCodes)
+	})
+}
+
+// GenerateCodes starts generating random codes for an offer and responds with the running
+// batch; progress is read from GET /admin/offer-code-batches/{batchId}
+func (h *AdminOfferCodeBatchHandler) GenerateCodes(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid offer ID"))
+		return
+	}
+
+	var req application.GenerateOfferCodesRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	batch, err := h.batchService.GenerateCodes(r.Context(), offerID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).WithField("offer_id", offerID).Error("failed to start offer code generation")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusAccepted, batch)
+}
+
+// ListBatches lists the code batches of an offer, newest first
+func (h *AdminOfferCodeBatchHandler) ListBatches(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid offer ID"))
+		return
+	}
+
+	batches, err := h.batchService.ListBatches(r.Context(), offerID)
+	if err != nil {
+		h.log.WithError(err).WithField("offer_id", offerID).Error("failed to list offer code batches")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, batches)
+}
+
+// GetBatch reports the progress of a code batch
+func (h *AdminOfferCodeBatchHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	batchID, err := strconv.ParseInt(chi.URLParam(r, "batchId"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid batch ID"))
+		return
+	}
+
+	batch, err := h.batchService.GetBatch(r.Context(), batchID)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, batch)
+}
+
+// ImportCodes adds externally generated codes to an offer from a CSV with a code column and
+// optional max_uses and email_address columns, sent as the request body or as the "file"
+// field of a multipart form. With ?dry_run=true the file is only validated. Files with
+// invalid rows or codes already taken are rejected whole with 422.
+func (h *AdminOfferCodeBatchHandler) ImportCodes(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid offer ID"))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxOfferCodeImportBytes)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		upload, _, err := r.FormFile("file")
+		if err != nil {
+			pkghttp.RespondError(w, errors.BadRequest("multipart offer code import requires a file field").WithInternal(err))
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	result, err := h.batchService.ImportCodes(r.Context(), offerID, file, pkghttp.GetQueryParamBool(r, "dry_run", false), middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).WithField("offer_id", offerID).Error("failed to import offer codes")
+		pkghttp.RespondError(w, err)
+		return
+	}
+	if len(result.Errors) > 0 {
+		pkghttp.RespondJSON(w, http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, result)
+}
+
+// ExportCodes streams the codes of an offer as CSV, or queues them with ?async=true.
+// Filters: offer_id (required), batch_id
+func (h *AdminOfferCodeBatchHandler) ExportCodes(w http.ResponseWriter, r *http.Request) {
+	var filter domain.OfferCodeExportFilter
+	var err error
+	if filter.OfferID, err = strconv.ParseInt(r.URL.Query().Get("offer_id"), 10, 64); err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("offer_id is required"))
+		return
+	}
+	if batchID := r.URL.Query().Get("batch_id"); batchID != "" {
+		if filter.BatchID, err = strconv.ParseInt(batchID, 10, 64); err != nil {
+			pkghttp.RespondError(w, pkghttp.NewValidationError("invalid batch_id"))
+			return
+		}
+	}
+
+	export.Serve(w, r, h.jobs, application.NewOfferCodeExport(h.batchRepo, filter), h.log)
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/offer/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminOfferConditionHandler describes the conditions offers can be limited by to the admin UI
+type AdminOfferConditionHandler struct {
+	offerService   application.OfferService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminOfferConditionHandler creates a new AdminOfferConditionHandler
+func NewAdminOfferConditionHandler(
+	offerService application.OfferService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOfferConditionHandler {
+	return &AdminOfferConditionHandler{
+		offerService:   offerService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers offer condition routes
+func (h *AdminOfferConditionHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/offers/customer-conditions", h.ListCustomerConditionTypes)
+	})
+}
+
+// ListCustomerConditionTypes lists the customer conditions with the offer field and value type of each
+func (h *AdminOfferConditionHandler) ListCustomerConditionTypes(w http.ResponseWriter, r *http.Request) {
+	pkghttp.RespondJSON(w, http.StatusOK, h.offerService.ListCustomerConditionTypes(r.Context()))
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/offer/application"
+	"github.com/qhato/ecommerce/internal/offer/domain"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// reportDateLayout is the accepted format for from/to query parameters
+const reportDateLayout = "2006-01-02"
+
+// AdminOfferReportHandler handles admin offer and coupon reporting requests
+type AdminOfferReportHandler struct {
+	reportService application.OfferReportService
+	logger        *logger.Logger
+}
+
+// NewAdminOfferReportHandler creates a new admin offer report handler
+func NewAdminOfferReportHandler(reportService application.OfferReportService, logger *logger.Logger) *AdminOfferReportHandler {
+	return &AdminOfferReportHandler{
+		reportService: reportService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes registers admin offer report routes
+func (h *AdminOfferReportHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/reports/offers", func(r chi.Router) {
+		r.Get("/performance", h.GetOfferPerformance)
+		r.Get("/coupon-usage", h.GetCouponUsage)
+	})
+}
+
+// GetOfferPerformance returns per-offer redemption counts, revenue influenced and average discount.
+// Supports ?from=YYYY-MM-DD&to=YYYY-MM-DD&offer_id=N&format=csv
+func (h *AdminOfferReportHandler) GetOfferPerformance(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseReportFilter(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	report, err := h.reportService.GetOfferPerformance(r.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to build offer performance report")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	if !pkghttp.WantsCSV(r) {
+		pkghttp.RespondJSON(w, http.StatusOK, report)
+		return
+	}
+
+	cw, err := pkghttp.NewCSVWriter(w, "offer-performance.csv", []string{
+		"offer_id", "offer_name", "redemptions", "total_discount", "revenue_influenced", "average_discount_per_order",
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("failed to write offer performance csv")
+		return
+	}
+	for _, row := range report {
+		if err := cw.Write([]string{
+			strconv.FormatInt(row.OfferID, 10),
+			row.OfferName,
+			strconv.FormatInt(row.Redemptions, 10),
+			strconv.FormatFloat(row.TotalDiscount, 'f', 2, 64),
+			strconv.FormatFloat(row.RevenueInfluenced, 'f', 2, 64),
+			strconv.FormatFloat(row.AverageDiscountPerOrder, 'f', 2, 64),
+		}); err != nil {
+			h.logger.WithError(err).Error("failed to write offer performance csv")
+			return
+		}
+	}
+	cw.Flush()
+}
+
+// GetCouponUsage returns offer code usage over time.
+// Supports ?from=YYYY-MM-DD&to=YYYY-MM-DD&offer_id=N&interval=day|week|month&format=csv
+func (h *AdminOfferReportHandler) GetCouponUsage(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseReportFilter(r)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	report, err := h.reportService.GetCouponUsage(r.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to build coupon usage report")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	if !pkghttp.WantsCSV(r) {
+		pkghttp.RespondJSON(w, http.StatusOK, report)
+		return
+	}
+
+	cw, err := pkghttp.NewCSVWriter(w, "coupon-usage.csv", []string{
+		"period", "offer_code_id", "code", "offer_id", "uses",
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("failed to write coupon usage csv")
+		return
+	}
+	for _, row := range report {
+		if err := cw.Write([]string{
+			row.Period.Format(reportDateLayout),
+			strconv.FormatInt(row.OfferCodeID, 10),
+			row.Code,
+			strconv.FormatInt(row.OfferID, 10),
+			strconv.FormatInt(row.Uses, 10),
+		}); err != nil {
+			h.logger.WithError(err).Error("failed to write coupon usage csv")
+			return
+		}
+	}
+	cw.Flush()
+}
+
+func parseReportFilter(r *http.Request) (*domain.OfferReportFilter, error) {
+	filter := domain.NewOfferReportFilter()
+	query := r.URL.Query()
+
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse(reportDateLayout, from)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid from date, expected YYYY-MM-DD")
+		}
+		filter.From = &t
+	}
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse(reportDateLayout, to)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid to date, expected YYYY-MM-DD")
+		}
+		// The to date is inclusive
+		t = t.AddDate(0, 0, 1)
+		filter.To = &t
+	}
+	if offerID := query.Get("offer_id"); offerID != "" {
+		id, err := strconv.ParseInt(offerID, 10, 64)
+		if err != nil {
+			return nil, pkghttp.NewValidationError("invalid offer ID")
+		}
+		filter.OfferID = &id
+	}
+	if interval := query.Get("interval"); interval != "" {
+		filter.Interval = domain.ReportInterval(interval)
+		if !filter.Interval.IsValid() {
+			return nil, pkghttp.NewValidationError("interval must be day, week or month")
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/offer/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminOfferScheduleHandler schedules offers over business days of a site's calendar
+type AdminOfferScheduleHandler struct {
+	offerService   application.OfferService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminOfferScheduleHandler creates a new AdminOfferScheduleHandler
+func NewAdminOfferScheduleHandler(
+	offerService application.OfferService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOfferScheduleHandler {
+	return &AdminOfferScheduleHandler{
+		offerService:   offerService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers offer schedule routes
+func (h *AdminOfferScheduleHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Put("/admin/offers/{id}/schedule", h.ScheduleOffer)
+	})
+}
+
+// ScheduleOffer runs an offer from the first business day at or after starts_at,
+// for business_days business days or until ends_at
+func (h *AdminOfferScheduleHandler) ScheduleOffer(w http.ResponseWriter, r *http.Request) {
+	offerID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid offer ID"))
+		return
+	}
+
+	var req application.ScheduleOfferRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	scheduled, err := h.offerService.ScheduleOfferInBusinessDays(r.Context(), offerID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("offer_id", offerID).Error("failed to schedule offer")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, scheduled)
+}
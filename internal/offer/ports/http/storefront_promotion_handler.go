@@ -0,0 +1,68 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/offer/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/requestctx"
+)
+
+// StorefrontPromotionHandler handles storefront promotion messaging requests
+type StorefrontPromotionHandler struct {
+	messageService application.PromotionMessageService
+	logger         *logger.Logger
+}
+
+// NewStorefrontPromotionHandler creates a new storefront promotion handler
+func NewStorefrontPromotionHandler(messageService application.PromotionMessageService, logger *logger.Logger) *StorefrontPromotionHandler {
+	return &StorefrontPromotionHandler{
+		messageService: messageService,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers storefront promotion routes
+func (h *StorefrontPromotionHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/promotions/qualification-gaps", h.GetQualificationGaps)
+}
+
+// GetQualificationGaps returns the offers a cart is close to qualifying for and the spend
+// still needed, e.g. for "spend $20 more for free shipping" messages on product pages.
+// The body is the cart content; an empty cart lists every spend-based offer.
+func (h *StorefrontPromotionHandler) GetQualificationGaps(w http.ResponseWriter, r *http.Request) {
+	var cart application.CartSnapshot
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&cart); err != nil {
+			pkghttp.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+			return
+		}
+	}
+
+	// Currency, locale and customer come from the storefront request context when omitted
+	if rc, ok := requestctx.FromContext(r.Context()); ok {
+		if cart.CurrencyCode == "" {
+			cart.CurrencyCode = rc.Currency
+		}
+		if cart.LocaleCode == "" {
+			cart.LocaleCode = rc.Locale
+		}
+		if rc.CustomerID != 0 {
+			customerID := rc.CustomerID
+			cart.CustomerID = &customerID
+		}
+	}
+
+	gaps, err := h.messageService.GetQualificationGaps(r.Context(), &cart)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get offer qualification gaps")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, gaps)
+}
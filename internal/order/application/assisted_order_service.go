@@ -0,0 +1,305 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	paymentApp "github.com/qhato/ecommerce/internal/payment/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AssistedOrderService defines the application service for orders agents place
+// on behalf of phone and mail (MOTO) customers.
+type AssistedOrderService interface {
+	// CreateOrder creates an order for a customer or a new guest, adds its items
+	// and takes its payment or emails a payment link.
+	CreateOrder(ctx context.Context, agent AssistedOrderAgent, req *CreateAssistedOrderRequest) (*AssistedOrderDTO, error)
+
+	// GetAssistedOrder returns how an order was placed by an agent.
+	GetAssistedOrder(ctx context.Context, orderID int64) (*AssistedOrderDTO, error)
+}
+
+// AssistedOrderAgent is the admin user placing an assisted order
+type AssistedOrderAgent struct {
+	ID          string
+	CanOverride bool // May override prices and bypass the storefront cart policy
+}
+
+type assistedOrderService struct {
+	orderService       OrderService
+	assistedRepo       domain.AssistedOrderRepository
+	customerRepo       domain.AssistedOrderCustomerRepository
+	paymentService     paymentApp.PaymentService
+	paymentLinkService PaymentLinkService
+	logger             *logger.Logger
+}
+
+// NewAssistedOrderService creates a new instance of AssistedOrderService
+func NewAssistedOrderService(
+	orderService OrderService,
+	assistedRepo domain.AssistedOrderRepository,
+	customerRepo domain.AssistedOrderCustomerRepository,
+	paymentService paymentApp.PaymentService,
+	paymentLinkService PaymentLinkService,
+	log *logger.Logger,
+) AssistedOrderService {
+	return &assistedOrderService{
+		orderService:       orderService,
+		assistedRepo:       assistedRepo,
+		customerRepo:       customerRepo,
+		paymentService:     paymentService,
+		paymentLinkService: paymentLinkService,
+		logger:             log,
+	}
+}
+
+func (s *assistedOrderService) CreateOrder(ctx context.Context, agent AssistedOrderAgent, req *CreateAssistedOrderRequest) (*AssistedOrderDTO, error) {
+	if err := s.validateCreateRequest(agent, req); err != nil {
+		return nil, err
+	}
+
+	customer, err := s.resolveCustomer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderService.CreateOrder(ctx, &CreateOrderCommand{
+		CustomerID:   customer.ID,
+		EmailAddress: customer.EmailAddress,
+		Name:         customer.FullName(),
+		CurrencyCode: req.CurrencyCode,
+		LocaleCode:   req.LocaleCode,
+		Channel:      domain.OrderChannelMOTO,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create assisted order: %w", err)
+	}
+
+	assisted, err := domain.NewAssistedOrder(order.ID, domain.AssistedOrderChannel(req.Channel), agent.ID, domain.AssistedPaymentMethod(req.Payment.Method))
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	assisted.Guest = !customer.Registered
+	assisted.CartPolicyBypassed = req.BypassCartPolicy
+
+	for _, item := range req.Items {
+		_, err := s.orderService.AddItemToOrder(ctx, order.ID, &AddItemToOrderCommand{
+			SKUID:          item.SKUID,
+			Quantity:       item.Quantity,
+			TaxCategory:    item.TaxCategory,
+			PriceOverride:  item.Price,
+			SkipCartPolicy: req.BypassCartPolicy,
+		})
+		if err != nil {
+			s.abandon(ctx, order.ID, "assisted order item could not be added")
+			return nil, fmt.Errorf("failed to add SKU %d to assisted order: %w", item.SKUID, err)
+		}
+		if item.Price != nil {
+			assisted.PriceOverridden = true
+		}
+	}
+
+	if err := s.assistedRepo.Save(ctx, assisted); err != nil {
+		s.abandon(ctx, order.ID, "assisted order could not be recorded")
+		return nil, fmt.Errorf("failed to save assisted order: %w", err)
+	}
+
+	var paymentLink *PaymentLinkDTO
+	switch assisted.PaymentMethod {
+	case domain.AssistedPaymentCard:
+		if err := s.authorizeAndSubmit(ctx, assisted, req.Payment.PaymentMethodType, req.Payment.PaymentToken); err != nil {
+			s.abandon(ctx, order.ID, "assisted order payment declined")
+			return nil, err
+		}
+	case domain.AssistedPaymentOffline:
+		assisted.MarkPaidOffline()
+		if err := s.submit(ctx, assisted); err != nil {
+			return nil, err
+		}
+	case domain.AssistedPaymentPayByLink:
+		paymentLink, err = s.paymentLinkService.CreatePaymentLink(ctx, order.ID, agent.ID, &CreatePaymentLinkRequest{
+			Reason:    string(domain.PaymentLinkReasonAssistedOrder),
+			SendEmail: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue payment link for assisted order %d: %w", order.ID, err)
+		}
+	}
+
+	dto, err := s.toAssistedOrderDTO(ctx, assisted)
+	if err != nil {
+		return nil, err
+	}
+	if paymentLink != nil {
+		// The created link knows whether the email went out
+		dto.PaymentLink = paymentLink
+	}
+	return dto, nil
+}
+
+func (s *assistedOrderService) GetAssistedOrder(ctx context.Context, orderID int64) (*AssistedOrderDTO, error) {
+	assisted, err := s.assistedRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find assisted order: %w", err)
+	}
+	if assisted == nil {
+		return nil, errors.NotFound(fmt.Sprintf("assisted order %d", orderID))
+	}
+	return s.toAssistedOrderDTO(ctx, assisted)
+}
+
+// validateCreateRequest checks the request and that overrides are only used by agents allowed to
+func (s *assistedOrderService) validateCreateRequest(agent AssistedOrderAgent, req *CreateAssistedOrderRequest) error {
+	if agent.ID == "" {
+		return errors.Unauthorized("an agent is required to place assisted orders")
+	}
+	if (req.CustomerID == nil) == (req.Guest == nil) {
+		return errors.ValidationError("either customer_id or guest is required")
+	}
+	if req.Guest != nil && strings.TrimSpace(req.Guest.EmailAddress) == "" {
+		return errors.ValidationError("guest email_address is required")
+	}
+	if len(req.Items) == 0 {
+		return errors.ValidationError("at least one item is required")
+	}
+
+	method := domain.AssistedPaymentMethod(req.Payment.Method)
+	if !domain.IsAssistedPaymentMethod(method) {
+		return errors.ValidationError("payment method must be CARD, PAY_BY_LINK or OFFLINE")
+	}
+	if method == domain.AssistedPaymentCard && req.Payment.PaymentToken == "" {
+		return errors.ValidationError("payment_token is required for card payments")
+	}
+
+	overridesPrice := false
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return errors.ValidationError(fmt.Sprintf("quantity of SKU %d must be greater than zero", item.SKUID))
+		}
+		if item.Price != nil {
+			overridesPrice = true
+		}
+	}
+	if (overridesPrice || req.BypassCartPolicy) && !agent.CanOverride {
+		return errors.Forbidden("overriding prices or the cart policy requires the order override permission")
+	}
+	return nil
+}
+
+// resolveCustomer returns the selected customer, or the customer with the guest's
+// email address, creating a guest customer when there is none
+func (s *assistedOrderService) resolveCustomer(ctx context.Context, req *CreateAssistedOrderRequest) (*domain.AssistedOrderCustomer, error) {
+	if req.CustomerID != nil {
+		customer, err := s.customerRepo.FindByID(ctx, *req.CustomerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find customer %d: %w", *req.CustomerID, err)
+		}
+		if customer == nil {
+			return nil, errors.NotFound(fmt.Sprintf("customer %d", *req.CustomerID))
+		}
+		return customer, nil
+	}
+
+	email := strings.TrimSpace(req.Guest.EmailAddress)
+	customer, err := s.customerRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customer by email: %w", err)
+	}
+	if customer != nil {
+		return customer, nil
+	}
+
+	customer = &domain.AssistedOrderCustomer{
+		EmailAddress: email,
+		FirstName:    req.Guest.FirstName,
+		LastName:     req.Guest.LastName,
+	}
+	if err := s.customerRepo.CreateGuest(ctx, customer); err != nil {
+		return nil, fmt.Errorf("failed to create guest customer: %w", err)
+	}
+	return customer, nil
+}
+
+// authorizeAndSubmit authorizes the order total and submits the order
+func (s *assistedOrderService) authorizeAndSubmit(ctx context.Context, assisted *domain.AssistedOrder, methodType, paymentToken string) error {
+	order, err := s.orderService.HandleGetOrderByID(ctx, assisted.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order %d: %w", assisted.OrderID, err)
+	}
+	if methodType == "" {
+		methodType = "CREDIT_CARD"
+	}
+
+	payment, err := s.paymentService.AuthorizePayment(ctx, &paymentApp.AuthorizePaymentCommand{
+		OrderID:           order.ID,
+		CustomerID:        order.CustomerID,
+		Amount:            order.OrderTotal,
+		CurrencyCode:      order.CurrencyCode,
+		PaymentToken:      paymentToken,
+		PaymentMethodType: methodType,
+	})
+	if err != nil {
+		return errors.PaymentFailed(err.Error())
+	}
+	if !payment.Success {
+		return errors.PaymentFailed(payment.Message)
+	}
+
+	assisted.MarkAuthorized(payment.TransactionID)
+	return s.submit(ctx, assisted)
+}
+
+// submit saves the payment state and submits the order
+func (s *assistedOrderService) submit(ctx context.Context, assisted *domain.AssistedOrder) error {
+	if err := s.assistedRepo.Save(ctx, assisted); err != nil {
+		return fmt.Errorf("failed to save assisted order payment: %w", err)
+	}
+
+	submit := s.orderService.SubmitOrder
+	if assisted.CartPolicyBypassed {
+		submit = s.orderService.SubmitOrderSkippingCartPolicy
+	}
+	if err := submit(ctx, assisted.OrderID); err != nil {
+		return fmt.Errorf("failed to submit assisted order %d: %w", assisted.OrderID, err)
+	}
+	return nil
+}
+
+// abandon cancels an assisted order that could not be completed so its stock is released
+func (s *assistedOrderService) abandon(ctx context.Context, orderID int64, reason string) {
+	if err := s.orderService.CancelOrder(ctx, orderID, reason); err != nil {
+		s.logger.WithError(err).WithField("order_id", orderID).Error("Failed to cancel abandoned assisted order")
+	}
+}
+
+func (s *assistedOrderService) toAssistedOrderDTO(ctx context.Context, assisted *domain.AssistedOrder) (*AssistedOrderDTO, error) {
+	order, err := s.orderService.HandleGetOrderByID(ctx, assisted.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", assisted.OrderID, err)
+	}
+
+	dto := &AssistedOrderDTO{
+		Order:              order,
+		Channel:            string(assisted.Channel),
+		AgentID:            assisted.AgentID,
+		Guest:              assisted.Guest,
+		PriceOverridden:    assisted.PriceOverridden,
+		CartPolicyBypassed: assisted.CartPolicyBypassed,
+		PaymentMethod:      string(assisted.PaymentMethod),
+		PaymentStatus:      string(assisted.PaymentStatus),
+		TransactionID:      assisted.TransactionID,
+	}
+	if assisted.PaymentMethod == domain.AssistedPaymentPayByLink {
+		links, err := s.paymentLinkService.ListPaymentLinks(ctx, assisted.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if len(links) > 0 {
+			dto.PaymentLink = links[0]
+		}
+	}
+	return dto, nil
+}
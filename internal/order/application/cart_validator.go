@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// CartValidator evaluates the cart policy against the items of an order.
+type CartValidator interface {
+	// Validate returns a cart policy violation error listing every broken rule, or nil.
+	Validate(ctx context.Context, order *domain.Order, items []*domain.OrderItem) error
+}
+
+type cartValidator struct {
+	policy      *domain.CartPolicy
+	contextRepo domain.CartPolicyContextRepository
+}
+
+// NewCartValidator creates a new instance of CartValidator.
+func NewCartValidator(policy *domain.CartPolicy, contextRepo domain.CartPolicyContextRepository) CartValidator {
+	return &cartValidator{
+		policy:      policy,
+		contextRepo: contextRepo,
+	}
+}
+
+func (v *cartValidator) Validate(ctx context.Context, order *domain.Order, items []*domain.OrderItem) error {
+	var pctx domain.CartPolicyContext
+
+	// Customer and destination lookups are only needed for category rules
+	if len(v.policy.CategoryRestrictions) > 0 {
+		if order.CustomerID != 0 {
+			segments, err := v.contextRepo.FindCustomerSegments(ctx, order.CustomerID)
+			if err != nil {
+				return fmt.Errorf("failed to resolve customer segments: %w", err)
+			}
+			pctx.CustomerSegments = segments
+		}
+		country, err := v.contextRepo.FindDestinationCountry(ctx, order.ID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve destination country: %w", err)
+		}
+		pctx.DestinationCountry = country
+	}
+
+	if violations := v.policy.Evaluate(items, pctx); len(violations) > 0 {
+		return errors.CartPolicyViolation(violations)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+)
+
+// Order formats sales channels send their orders in
+const (
+	ChannelOrderFormatGeneric = "generic"
+	ChannelOrderFormatEbay    = "ebay"
+)
+
+// ChannelOrderAdapter maps the orders of a channel's format to external orders
+type ChannelOrderAdapter interface {
+	// Format returns the order format the adapter reads
+	Format() string
+
+	// ParseOrders maps a batch of orders sent by a channel
+	ParseOrders(payload []byte) ([]*domain.ExternalOrder, error)
+}
+
+// ChannelOrderAdapters returns the adapters of the supported order formats by format
+func ChannelOrderAdapters() map[string]ChannelOrderAdapter {
+	adapters := make(map[string]ChannelOrderAdapter)
+	for _, adapter := range []ChannelOrderAdapter{GenericChannelOrderAdapter{}, EbayChannelOrderAdapter{}} {
+		adapters[adapter.Format()] = adapter
+	}
+	return adapters
+}
+
+// GenericChannelOrderAdapter reads the platform's own channel order format:
+//
+//	{"orders": [{"order_id": "...", "email_address": "...", "currency_code": "USD",
+//	  "items": [{"sku": "...", "quantity": 1, "unit_price": 9.99, "reservation_id": "..."}]}]}
+type GenericChannelOrderAdapter struct{}
+
+type genericChannelOrderBatch struct {
+	Orders []struct {
+		OrderID      string `json:"order_id"`
+		EmailAddress string `json:"email_address"`
+		FirstName    string `json:"first_name"`
+		LastName     string `json:"last_name"`
+		CurrencyCode string `json:"currency_code"`
+		LocaleCode   string `json:"locale_code"`
+		Items        []struct {
+			SKU           string   `json:"sku"`
+			Quantity      int      `json:"quantity"`
+			UnitPrice     *float64 `json:"unit_price"`
+			ReservationID string   `json:"reservation_id"`
+		} `json:"items"`
+	} `json:"orders"`
+}
+
+// Format returns the order format the adapter reads
+func (GenericChannelOrderAdapter) Format() string {
+	return ChannelOrderFormatGeneric
+}
+
+// ParseOrders maps a batch of orders in the generic format
+func (GenericChannelOrderAdapter) ParseOrders(payload []byte) ([]*domain.ExternalOrder, error) {
+	var batch genericChannelOrderBatch
+	if err := json.Unmarshal(payload, &batch); err != nil {
+		return nil, fmt.Errorf("invalid generic order batch: %w", err)
+	}
+
+	orders := make([]*domain.ExternalOrder, 0, len(batch.Orders))
+	for _, o := range batch.Orders {
+		order := &domain.ExternalOrder{
+			ExternalOrderID: o.OrderID,
+			EmailAddress:    o.EmailAddress,
+			FirstName:       o.FirstName,
+			LastName:        o.LastName,
+			CurrencyCode:    o.CurrencyCode,
+			LocaleCode:      o.LocaleCode,
+		}
+		for _, item := range o.Items {
+			order.Items = append(order.Items, domain.ExternalOrderItem{
+				ExternalSKU:   item.SKU,
+				Quantity:      item.Quantity,
+				UnitPrice:     item.UnitPrice,
+				ReservationID: item.ReservationID,
+			})
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// EbayChannelOrderAdapter reads orders as returned by the eBay Fulfillment API
+// getOrders call. Line items are matched by their seller SKU, or the listing's
+// item ID when the listing has no SKU.
+type EbayChannelOrderAdapter struct{}
+
+type ebayAmount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+type ebayOrderBatch struct {
+	Orders []struct {
+		OrderID string `json:"orderId"`
+		Buyer   struct {
+			Username                 string `json:"username"`
+			BuyerRegistrationAddress struct {
+				Email    string `json:"email"`
+				FullName string `json:"fullName"`
+			} `json:"buyerRegistrationAddress"`
+		} `json:"buyer"`
+		PricingSummary struct {
+			Total ebayAmount `json:"total"`
+		} `json:"pricingSummary"`
+		LineItems []struct {
+			LineItemID   string     `json:"lineItemId"`
+			SKU          string     `json:"sku"`
+			LegacyItemID string     `json:"legacyItemId"`
+			Quantity     int        `json:"quantity"`
+			LineItemCost ebayAmount `json:"lineItemCost"` // Price of all units of the line
+		} `json:"lineItems"`
+	} `json:"orders"`
+}
+
+// Format returns the order format the adapter reads
+func (EbayChannelOrderAdapter) Format() string {
+	return ChannelOrderFormatEbay
+}
+
+// ParseOrders maps a page of eBay orders
+func (EbayChannelOrderAdapter) ParseOrders(payload []byte) ([]*domain.ExternalOrder, error) {
+	var batch ebayOrderBatch
+	if err := json.Unmarshal(payload, &batch); err != nil {
+		return nil, fmt.Errorf("invalid eBay order batch: %w", err)
+	}
+
+	orders := make([]*domain.ExternalOrder, 0, len(batch.Orders))
+	for _, o := range batch.Orders {
+		firstName, lastName := splitFullName(o.Buyer.BuyerRegistrationAddress.FullName)
+		if firstName == "" {
+			firstName = o.Buyer.Username
+		}
+		order := &domain.ExternalOrder{
+			ExternalOrderID: o.OrderID,
+			EmailAddress:    o.Buyer.BuyerRegistrationAddress.Email,
+			FirstName:       firstName,
+			LastName:        lastName,
+			CurrencyCode:    o.PricingSummary.Total.Currency,
+		}
+		for _, line := range o.LineItems {
+			sku := line.SKU
+			if sku == "" {
+				sku = line.LegacyItemID
+			}
+			item := domain.ExternalOrderItem{ExternalSKU: sku, Quantity: line.Quantity}
+			if line.LineItemCost.Value != "" && line.Quantity > 0 {
+				cost, err := strconv.ParseFloat(line.LineItemCost.Value, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid cost of eBay line item %s: %w", line.LineItemID, err)
+				}
+				unitPrice := cost / float64(line.Quantity)
+				item.UnitPrice = &unitPrice
+			}
+			order.Items = append(order.Items, item)
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// splitFullName splits a full name at its first space into first and last name
+func splitFullName(fullName string) (string, string) {
+	first, last, _ := strings.Cut(strings.TrimSpace(fullName), " ")
+	return first, strings.TrimSpace(last)
+}
@@ -0,0 +1,455 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	inventoryApp "github.com/qhato/ecommerce/internal/inventory/application"
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/httpclient"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// maxChannelOrderBatch bounds the orders ingested per request
+const maxChannelOrderBatch = 100
+
+// Results of ingesting a channel order
+const (
+	ChannelOrderResultCreated   = "CREATED"
+	ChannelOrderResultDuplicate = "DUPLICATE" // Created, or being created, by an earlier request
+	ChannelOrderResultFailed    = "FAILED"
+)
+
+// ChannelOrderAcknowledgementEvent names the acknowledgements posted to channels
+const ChannelOrderAcknowledgementEvent = "channel_orders.ingested"
+
+// OrderChannel is an external sales channel sending its orders for ingestion
+type OrderChannel struct {
+	Code               string
+	Format             string // Order format read by the channel's adapter
+	AcknowledgementURL string // Receives the result of each order; empty only answers the request
+}
+
+// ChannelOrderIngestionService creates orders placed on external sales channels,
+// such as marketplaces. Each channel order is created once, however often the
+// channel sends it, and its result is reported back to the channel.
+type ChannelOrderIngestionService interface {
+	// IngestOrders creates the orders of a batch a channel sent in its order format.
+	// Orders fail independently; the result of each is returned and acknowledged.
+	IngestOrders(ctx context.Context, channel string, payload []byte) (*ChannelOrderBatchDTO, error)
+
+	// ListChannelOrders lists ingested channel orders, newest first.
+	ListChannelOrders(ctx context.Context, filter *domain.ChannelOrderFilter) ([]*ChannelOrderDTO, int64, error)
+
+	// ListSKUMappings lists the SKU mappings of a channel.
+	ListSKUMappings(ctx context.Context, channel string) ([]*ChannelSKUMappingDTO, error)
+
+	// SaveSKUMapping maps a channel SKU to an internal SKU.
+	SaveSKUMapping(ctx context.Context, channel string, req *SaveChannelSKUMappingRequest) (*ChannelSKUMappingDTO, error)
+
+	// DeleteSKUMapping removes the mapping of a channel SKU.
+	DeleteSKUMapping(ctx context.Context, channel, externalSKU string) error
+}
+
+type channelOrderIngestionService struct {
+	orderService       OrderService
+	channelOrderRepo   domain.ChannelOrderRepository
+	skuMappingRepo     domain.ChannelSKUMappingRepository
+	customerRepo       domain.AssistedOrderCustomerRepository
+	reservationService inventoryApp.ChannelReservationService
+	client             *httpclient.Client // Posts acknowledgements; nil when no channel has an acknowledgement URL
+	channels           map[string]*OrderChannel
+	adapters           map[string]ChannelOrderAdapter
+	logger             *logger.Logger
+}
+
+// NewChannelOrderIngestionService creates a new instance of ChannelOrderIngestionService
+func NewChannelOrderIngestionService(
+	orderService OrderService,
+	channelOrderRepo domain.ChannelOrderRepository,
+	skuMappingRepo domain.ChannelSKUMappingRepository,
+	customerRepo domain.AssistedOrderCustomerRepository,
+	reservationService inventoryApp.ChannelReservationService,
+	client *httpclient.Client,
+	channels []*OrderChannel,
+	log *logger.Logger,
+) ChannelOrderIngestionService {
+	s := &channelOrderIngestionService{
+		orderService:       orderService,
+		channelOrderRepo:   channelOrderRepo,
+		skuMappingRepo:     skuMappingRepo,
+		customerRepo:       customerRepo,
+		reservationService: reservationService,
+		client:             client,
+		channels:           make(map[string]*OrderChannel, len(channels)),
+		adapters:           ChannelOrderAdapters(),
+		logger:             log,
+	}
+	for _, channel := range channels {
+		s.channels[channel.Code] = channel
+	}
+	return s
+}
+
+func (s *channelOrderIngestionService) IngestOrders(ctx context.Context, channelCode string, payload []byte) (*ChannelOrderBatchDTO, error) {
+	channel, err := s.channel(channelCode)
+	if err != nil {
+		return nil, err
+	}
+	format := channel.Format
+	if format == "" {
+		format = ChannelOrderFormatGeneric
+	}
+	adapter, ok := s.adapters[format]
+	if !ok {
+		return nil, errors.ServiceUnavailable(fmt.Sprintf("sales channel %s uses unsupported order format %s", channel.Code, format))
+	}
+
+	orders, err := adapter.ParseOrders(payload)
+	if err != nil {
+		return nil, errors.BadRequest(fmt.Sprintf("invalid %s order batch", format)).WithInternal(err)
+	}
+	if len(orders) == 0 {
+		return nil, errors.ValidationError("at least one order is required")
+	}
+	if len(orders) > maxChannelOrderBatch {
+		return nil, errors.ValidationError(fmt.Sprintf("a batch cannot have more than %d orders", maxChannelOrderBatch))
+	}
+
+	batch := &ChannelOrderBatchDTO{Channel: channel.Code, Results: make([]*ChannelOrderResultDTO, 0, len(orders))}
+	recordIDs := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		result, recordID := s.ingest(ctx, channel, order)
+		batch.Results = append(batch.Results, result)
+		if recordID != 0 {
+			recordIDs = append(recordIDs, recordID)
+		}
+		switch result.Status {
+		case ChannelOrderResultCreated:
+			batch.Created++
+		case ChannelOrderResultDuplicate:
+			batch.Duplicates++
+		default:
+			batch.Failed++
+		}
+	}
+
+	batch.Acknowledged = s.acknowledge(ctx, channel, batch, recordIDs)
+	s.logger.WithFields(logger.Fields{
+		"channel":    channel.Code,
+		"created":    batch.Created,
+		"duplicates": batch.Duplicates,
+		"failed":     batch.Failed,
+	}).Info("Channel orders ingested")
+	return batch, nil
+}
+
+// ingest creates one channel order and returns its result and the ID of its record, 0 when
+// the order was not claimed
+func (s *channelOrderIngestionService) ingest(ctx context.Context, channel *OrderChannel, external *domain.ExternalOrder) (*ChannelOrderResultDTO, int64) {
+	result := &ChannelOrderResultDTO{ExternalOrderID: external.ExternalOrderID, Status: ChannelOrderResultFailed}
+	if err := validateExternalOrder(external); err != nil {
+		result.Error = failureReason(err)
+		return result, 0
+	}
+
+	record, err := domain.NewChannelOrder(channel.Code, external.ExternalOrderID)
+	if err != nil {
+		result.Error = err.Error()
+		return result, 0
+	}
+	claimed, err := s.channelOrderRepo.Claim(ctx, record)
+	if err != nil {
+		s.logger.WithError(err).WithField("external_order_id", external.ExternalOrderID).Error("Failed to claim channel order")
+		result.Error = "order could not be recorded"
+		return result, 0
+	}
+	if !claimed {
+		existing, err := s.channelOrderRepo.FindByExternalID(ctx, channel.Code, external.ExternalOrderID)
+		if err != nil || existing == nil {
+			result.Error = "order could not be recorded"
+			return result, 0
+		}
+		result.Status = ChannelOrderResultDuplicate
+		result.OrderID = existing.OrderID
+		return result, existing.ID
+	}
+
+	order, err := s.createOrder(ctx, channel, external)
+	if err != nil {
+		record.MarkFailed(err.Error())
+		result.Error = failureReason(err)
+		s.logger.WithError(err).WithFields(logger.Fields{
+			"channel":           channel.Code,
+			"external_order_id": external.ExternalOrderID,
+		}).Warn("Failed to ingest channel order")
+	} else {
+		record.MarkCreated(order.ID)
+		result.Status = ChannelOrderResultCreated
+		result.OrderID = &order.ID
+		result.OrderNumber = order.OrderNumber
+	}
+	if err := s.channelOrderRepo.Save(ctx, record); err != nil {
+		s.logger.WithError(err).WithField("channel_order_id", record.ID).Error("Failed to save channel order result")
+	}
+	return result, record.ID
+}
+
+// createOrder creates and submits the order of an external order; an order that
+// cannot be completed is cancelled so its stock is released
+func (s *channelOrderIngestionService) createOrder(ctx context.Context, channel *OrderChannel, external *domain.ExternalOrder) (*OrderDTO, error) {
+	skus := make([]string, 0, len(external.Items))
+	for _, item := range external.Items {
+		skus = append(skus, item.ExternalSKU)
+	}
+	skuIDs, err := s.skuMappingRepo.FindSKUIDs(ctx, channel.Code, skus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map channel SKUs: %w", err)
+	}
+	for _, sku := range skus {
+		if _, ok := skuIDs[sku]; !ok {
+			return nil, errors.ValidationError(fmt.Sprintf("channel SKU %s is not mapped", sku))
+		}
+	}
+
+	customer, err := s.resolveCustomer(ctx, external)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderService.CreateOrder(ctx, &CreateOrderCommand{
+		CustomerID:   customer.ID,
+		EmailAddress: customer.EmailAddress,
+		Name:         customer.FullName(),
+		CurrencyCode: external.CurrencyCode,
+		LocaleCode:   external.LocaleCode,
+		Channel:      domain.OrderChannelMarketplace,
+		Attributes: map[string]string{
+			domain.ChannelOrderAttribute:         channel.Code,
+			domain.ChannelExternalOrderAttribute: external.ExternalOrderID,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create channel order: %w", err)
+	}
+
+	for _, item := range external.Items {
+		skuID := skuIDs[item.ExternalSKU]
+		// The stock the channel reserved is handed over to the order
+		if item.ReservationID != "" {
+			if err := s.releaseReservation(ctx, channel.Code, item.ReservationID, skuID); err != nil {
+				s.abandon(ctx, order.ID, "channel reservation could not be released")
+				return nil, err
+			}
+		}
+		_, err := s.orderService.AddItemToOrder(ctx, order.ID, &AddItemToOrderCommand{
+			SKUID:          skuID,
+			Quantity:       item.Quantity,
+			PriceOverride:  item.UnitPrice,
+			SkipCartPolicy: true, // The channel applied its own cart rules
+		})
+		if err != nil {
+			s.abandon(ctx, order.ID, "channel order item could not be added")
+			return nil, fmt.Errorf("failed to add channel SKU %s to order: %w", item.ExternalSKU, err)
+		}
+	}
+
+	if err := s.orderService.SubmitOrderSkippingCartPolicy(ctx, order.ID); err != nil {
+		s.abandon(ctx, order.ID, "channel order could not be submitted")
+		return nil, fmt.Errorf("failed to submit channel order %d: %w", order.ID, err)
+	}
+	return order, nil
+}
+
+// releaseReservation releases a channel reservation held for a SKU of an order
+func (s *channelOrderIngestionService) releaseReservation(ctx context.Context, channel, reservationID string, skuID int64) error {
+	reservation, err := s.reservationService.GetReservation(ctx, channel, reservationID)
+	if err != nil {
+		return fmt.Errorf("failed to find channel reservation %s: %w", reservationID, err)
+	}
+	if reservation.SKUID != strconv.FormatInt(skuID, 10) {
+		return errors.ValidationError(fmt.Sprintf("channel reservation %s holds a different SKU", reservationID))
+	}
+	if _, err := s.reservationService.ReleaseReservation(ctx, channel, reservationID); err != nil {
+		return fmt.Errorf("failed to release channel reservation %s: %w", reservationID, err)
+	}
+	return nil
+}
+
+// resolveCustomer returns the customer with the order's email address, creating a
+// guest customer when there is none
+func (s *channelOrderIngestionService) resolveCustomer(ctx context.Context, external *domain.ExternalOrder) (*domain.AssistedOrderCustomer, error) {
+	email := strings.TrimSpace(external.EmailAddress)
+	customer, err := s.customerRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customer by email: %w", err)
+	}
+	if customer != nil {
+		return customer, nil
+	}
+
+	customer = &domain.AssistedOrderCustomer{
+		EmailAddress: email,
+		FirstName:    external.FirstName,
+		LastName:     external.LastName,
+	}
+	if err := s.customerRepo.CreateGuest(ctx, customer); err != nil {
+		return nil, fmt.Errorf("failed to create guest customer: %w", err)
+	}
+	return customer, nil
+}
+
+// abandon cancels a channel order that could not be completed so its stock is released
+func (s *channelOrderIngestionService) abandon(ctx context.Context, orderID int64, reason string) {
+	if err := s.orderService.CancelOrder(ctx, orderID, reason); err != nil {
+		s.logger.WithError(err).WithField("order_id", orderID).Error("Failed to cancel abandoned channel order")
+	}
+}
+
+// acknowledge posts the results of a batch to the channel's acknowledgement URL and
+// records them as acknowledged; it reports whether the channel accepted them
+func (s *channelOrderIngestionService) acknowledge(ctx context.Context, channel *OrderChannel, batch *ChannelOrderBatchDTO, recordIDs []int64) bool {
+	if channel.AcknowledgementURL == "" || s.client == nil {
+		return false
+	}
+	body, err := json.Marshal(batch)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to encode channel order acknowledgement")
+		return false
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Webhook-Event", ChannelOrderAcknowledgementEvent)
+
+	// Acknowledgements are idempotent: channels match results by their own order IDs
+	_, err = s.client.Do(ctx, &httpclient.Request{
+		Method:     http.MethodPost,
+		Path:       channel.AcknowledgementURL,
+		Header:     header,
+		Body:       body,
+		Idempotent: true,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("channel", channel.Code).Warn("Channel order acknowledgement failed")
+		return false
+	}
+	if err := s.channelOrderRepo.MarkAcknowledged(ctx, recordIDs, time.Now()); err != nil {
+		s.logger.WithError(err).WithField("channel", channel.Code).Error("Failed to record channel order acknowledgement")
+	}
+	return true
+}
+
+func (s *channelOrderIngestionService) ListChannelOrders(ctx context.Context, filter *domain.ChannelOrderFilter) ([]*ChannelOrderDTO, int64, error) {
+	orders, total, err := s.channelOrderRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list channel orders: %w", err)
+	}
+	dtos := make([]*ChannelOrderDTO, len(orders))
+	for i, order := range orders {
+		dtos[i] = ToChannelOrderDTO(order)
+	}
+	return dtos, total, nil
+}
+
+func (s *channelOrderIngestionService) ListSKUMappings(ctx context.Context, channelCode string) ([]*ChannelSKUMappingDTO, error) {
+	channel, err := s.channel(channelCode)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := s.skuMappingRepo.FindByChannel(ctx, channel.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channel SKU mappings: %w", err)
+	}
+	dtos := make([]*ChannelSKUMappingDTO, len(mappings))
+	for i, mapping := range mappings {
+		dtos[i] = ToChannelSKUMappingDTO(mapping)
+	}
+	return dtos, nil
+}
+
+func (s *channelOrderIngestionService) SaveSKUMapping(ctx context.Context, channelCode string, req *SaveChannelSKUMappingRequest) (*ChannelSKUMappingDTO, error) {
+	channel, err := s.channel(channelCode)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.ExternalSKU) == "" {
+		return nil, errors.ValidationError("external_sku is required")
+	}
+	if req.SKUID <= 0 {
+		return nil, errors.ValidationError("sku_id is required")
+	}
+
+	now := time.Now()
+	mapping := &domain.ChannelSKUMapping{
+		Channel:     channel.Code,
+		ExternalSKU: strings.TrimSpace(req.ExternalSKU),
+		SKUID:       req.SKUID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.skuMappingRepo.Save(ctx, mapping); err != nil {
+		return nil, fmt.Errorf("failed to save channel SKU mapping: %w", err)
+	}
+	return ToChannelSKUMappingDTO(mapping), nil
+}
+
+func (s *channelOrderIngestionService) DeleteSKUMapping(ctx context.Context, channelCode, externalSKU string) error {
+	channel, err := s.channel(channelCode)
+	if err != nil {
+		return err
+	}
+	return s.skuMappingRepo.Delete(ctx, channel.Code, externalSKU)
+}
+
+// channel returns a configured channel
+func (s *channelOrderIngestionService) channel(code string) (*OrderChannel, error) {
+	channel, ok := s.channels[code]
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("sales channel %s", code))
+	}
+	return channel, nil
+}
+
+// failureReason returns what a channel is told about a failed order: the message of
+// client errors, never internal details
+func failureReason(err error) string {
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) && appErr.StatusCode < http.StatusInternalServerError {
+		return appErr.Message
+	}
+	return "order could not be created"
+}
+
+// validateExternalOrder checks an external order has what an order is created from
+func validateExternalOrder(order *domain.ExternalOrder) error {
+	if strings.TrimSpace(order.ExternalOrderID) == "" {
+		return errors.ValidationError("order ID is required")
+	}
+	if strings.TrimSpace(order.EmailAddress) == "" {
+		return errors.ValidationError("buyer email address is required")
+	}
+	if order.CurrencyCode == "" {
+		return errors.ValidationError("currency code is required")
+	}
+	if len(order.Items) == 0 {
+		return errors.ValidationError("at least one item is required")
+	}
+	for _, item := range order.Items {
+		if strings.TrimSpace(item.ExternalSKU) == "" {
+			return errors.ValidationError("item SKU is required")
+		}
+		if item.Quantity <= 0 {
+			return errors.ValidationError(fmt.Sprintf("quantity of SKU %s must be greater than zero", item.ExternalSKU))
+		}
+		if item.UnitPrice != nil && *item.UnitPrice < 0 {
+			return errors.ValidationError(fmt.Sprintf("unit price of SKU %s cannot be negative", item.ExternalSKU))
+		}
+	}
+	return nil
+}
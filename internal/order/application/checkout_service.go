@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	catalogApp "github.com/qhato/ecommerce/internal/catalog/application"
 	shippingApp "github.com/qhato/ecommerce/internal/fulfillment/application"
 	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
 )
 
 // CheckoutService defines the application service for managing the order checkout workflow.
@@ -16,6 +18,9 @@ type CheckoutService interface {
 	// UpdateCustomerInformation updates customer details for the order.
 	UpdateCustomerInformation(ctx context.Context, orderID int64, cmd *UpdateCustomerInformationCommand) (*OrderDTO, error)
 
+	// ListShippingMethods lists the fulfillment options the order may be shipped to the address with, priced.
+	ListShippingMethods(ctx context.Context, orderID int64, shippingAddressID int64) ([]*shippingApp.ShippingMethodDTO, error)
+
 	// SelectShippingAddressAndMethod selects shipping address and method for the order.
 	SelectShippingAddressAndMethod(ctx context.Context, orderID int64, cmd *SelectShippingCommand) (*OrderDTO, error)
 
@@ -43,6 +48,7 @@ type SelectShippingCommand struct {
 	ShippingAddressID   int64
 	ShippingMethod      string
 	FulfillmentOptionID int64 // Reference to a fulfillment option
+	AgeVerified         bool  // Customer confirmed the age required by restricted items
 }
 
 // SelectPaymentMethodCommand represents the command to select a payment method.
@@ -53,9 +59,18 @@ type SelectPaymentMethodCommand struct {
 	// Other payment details
 }
 
+// ShippingAddressValidator checks a saved address before it is used at checkout,
+// returning an errors.AddressInvalid error with suggested corrections when it cannot be used
+type ShippingAddressValidator interface {
+	CheckAddress(ctx context.Context, addressID int64) error
+}
+
 type checkoutService struct {
-	orderService    OrderService
-	shippingService shippingApp.ShippingService
+	orderService       OrderService
+	shippingService    shippingApp.ShippingService
+	restrictionService catalogApp.ShippingRestrictionService
+	destinationRepo    domain.ShippingDestinationRepository
+	addressValidator   ShippingAddressValidator
 	// customerService  CustomerService // Dependency on Customer service
 	// paymentService   PaymentService  // Dependency on Payment service
 	// fulfillmentService FulfillmentService // Dependency on Fulfillment service
@@ -65,13 +80,19 @@ type checkoutService struct {
 func NewCheckoutService(
 	orderService OrderService,
 	shippingService shippingApp.ShippingService,
+	restrictionService catalogApp.ShippingRestrictionService,
+	destinationRepo domain.ShippingDestinationRepository,
+	addressValidator ShippingAddressValidator,
 	// customerService CustomerService,
 	// paymentService PaymentService,
 	// fulfillmentService FulfillmentService,
 ) CheckoutService {
 	return &checkoutService{
-		orderService:    orderService,
-		shippingService: shippingService,
+		orderService:       orderService,
+		shippingService:    shippingService,
+		restrictionService: restrictionService,
+		destinationRepo:    destinationRepo,
+		addressValidator:   addressValidator,
 		// customerService:  customerService,
 		// paymentService:   paymentService,
 		// fulfillmentService: fulfillmentService,
@@ -120,6 +141,18 @@ func (s *checkoutService) UpdateCustomerInformation(ctx context.Context, orderID
 	return s.orderService.HandleGetOrderByID(ctx, orderID)
 }
 
+// ListShippingMethods lists the fulfillment options the order may be shipped to the address with.
+func (s *checkoutService) ListShippingMethods(ctx context.Context, orderID int64, shippingAddressID int64) ([]*shippingApp.ShippingMethodDTO, error) {
+	order, err := s.orderService.HandleGetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	if order.Status != domain.OrderStatusShipping {
+		return nil, fmt.Errorf("order %d is not in SHIPPING status (current status: %s)", orderID, order.Status)
+	}
+	return s.shippingService.GetShippingMethods(ctx, orderID, shippingAddressID)
+}
+
 // SelectShippingAddressAndMethod selects shipping address and method for the order.
 func (s *checkoutService) SelectShippingAddressAndMethod(ctx context.Context, orderID int64, cmd *SelectShippingCommand) (*OrderDTO, error) {
 	order, err := s.orderService.HandleGetOrderByID(ctx, orderID)
@@ -139,8 +172,18 @@ func (s *checkoutService) SelectShippingAddressAndMethod(ctx context.Context, or
 	if !addressValid {
 		return nil, fmt.Errorf("shipping address %d is invalid", cmd.ShippingAddressID)
 	}
+	if s.addressValidator != nil {
+		if err := s.addressValidator.CheckAddress(ctx, cmd.ShippingAddressID); err != nil {
+			return nil, err
+		}
+	}
 
-	// 2. Calculate shipping cost
+	// 1b. Enforce product shipping restrictions for the destination
+	if err := s.checkShippingRestrictions(ctx, order, cmd); err != nil {
+		return nil, err
+	}
+
+	// 2. Price the fulfillment option, which must be active and available to the order and address
 	shippingCost, err := s.shippingService.CalculateShippingCost(ctx, orderID, cmd.ShippingAddressID, cmd.FulfillmentOptionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate shipping cost for order %d: %w", orderID, err)
@@ -173,6 +216,32 @@ func (s *checkoutService) SelectShippingAddressAndMethod(ctx context.Context, or
 	return s.orderService.HandleGetOrderByID(ctx, orderID)
 }
 
+// checkShippingRestrictions rejects the address when any order item may not be
+// shipped there, returning the restriction reasons to the caller
+func (s *checkoutService) checkShippingRestrictions(ctx context.Context, order *OrderDTO, cmd *SelectShippingCommand) error {
+	if s.restrictionService == nil || s.destinationRepo == nil || len(order.Items) == 0 {
+		return nil
+	}
+
+	destination, err := s.destinationRepo.FindByAddressID(ctx, cmd.ShippingAddressID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve shipping address %d: %w", cmd.ShippingAddressID, err)
+	}
+
+	lines := make([]catalogApp.RestrictionCheckLine, len(order.Items))
+	for i, item := range order.Items {
+		lines[i] = catalogApp.RestrictionCheckLine{ProductID: item.ProductID, SKUID: item.SKUID}
+	}
+	violations, err := s.restrictionService.CheckDestination(ctx, lines, destination.Country, destination.Region, cmd.AgeVerified)
+	if err != nil {
+		return fmt.Errorf("failed to check shipping restrictions for order %d: %w", order.ID, err)
+	}
+	if len(violations) > 0 {
+		return errors.ShippingRestricted(violations)
+	}
+	return nil
+}
+
 // SelectPaymentMethod selects payment method for the order and attempts authorization.
 func (s *checkoutService) SelectPaymentMethod(ctx context.Context, orderID int64, cmd *SelectPaymentMethodCommand) (*OrderDTO, error) {
 	order, err := s.orderService.HandleGetOrderByID(ctx, orderID)
@@ -1,9 +1,12 @@
 package application
 
 import (
+	"strconv"
 	"time"
 
+	offerApp "github.com/qhato/ecommerce/internal/offer/application"
 	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/money"
 )
 
 // OrderDTO represents an order data transfer object.
@@ -17,17 +20,37 @@ type OrderDTO struct {
 	OrderSubtotal           float64                   `json:"order_subtotal"`
 	TotalTax                float64                   `json:"total_tax"`
 	TotalShipping           float64                   `json:"total_shipping"`
+	TotalDuties             float64                   `json:"total_duties,omitempty"` // Duties and import fees of a DDP cross-border order
 	OrderTotal              float64                   `json:"order_total"`
 	CurrencyCode            string                    `json:"currency_code"`
+	Display                 *OrderTotalsDisplayDTO    `json:"display"`
 	IsPreview               bool                      `json:"is_preview"`
 	TaxOverride             bool                      `json:"tax_override"`
+	EstimatedTax            *float64                  `json:"estimated_tax,omitempty"`      // Cart estimate replaced by the calculation at submission
+	TaxReconciliation       *float64                  `json:"tax_reconciliation,omitempty"` // Calculated tax minus the estimate
+	TaxProvider             string                    `json:"tax_provider,omitempty"`
 	LocaleCode              string                    `json:"locale_code"`
+	Channel                 domain.OrderChannel       `json:"channel"`
 	SubmitDate              *time.Time                `json:"submit_date"`
 	CreatedAt               time.Time                 `json:"created_at"`
 	UpdatedAt               time.Time                 `json:"updated_at"`
 	Items                   []*OrderItemDTO           `json:"items"`
 	OrderAdjustments        []*OrderAdjustmentDTO     `json:"order_adjustments"`
 	FulfillmentGroups       []*FulfillmentGroupDTO    `json:"fulfillment_groups"`
+	TaxDetails              []*OrderTaxDetailDTO      `json:"tax_details,omitempty"` // Breakdown by jurisdiction, calculated at submission
+	Notes                   []*OrderNoteDTO           `json:"notes,omitempty"`
+	PromotionMessages       []*offerApp.QualificationGapDTO `json:"promotion_messages,omitempty"` // Offers the cart is close to qualifying for
+	Attributes              map[string]string         `json:"attributes,omitempty"` // E.g. the sales channel the order came from
+	Archived                bool                      `json:"archived,omitempty"`   // Read from the archive; restore before changing it
+}
+
+// OrderTotalsDisplayDTO holds the order totals formatted for the order's locale
+type OrderTotalsDisplayDTO struct {
+	Subtotal string `json:"subtotal"`
+	Tax      string `json:"tax"`
+	Shipping string `json:"shipping"`
+	Duties   string `json:"duties,omitempty"` // Set for DDP cross-border orders
+	Total    string `json:"total"`
 }
 
 // OrderItemDTO represents an order item data transfer object.
@@ -57,6 +80,8 @@ type OrderItemDTO struct {
 	PersonalMessageID       *int64    `json:"personal_message_id"`
 	CreatedAt               time.Time `json:"created_at"`
 	UpdatedAt               time.Time `json:"updated_at"`
+	TotalWithChildren       float64   `json:"total_with_children"` // Total price including add-ons and bundle components
+	ChildItems              []*OrderItemDTO `json:"child_items,omitempty"`
 }
 
 // OrderAdjustmentDTO represents an order adjustment data transfer object.
@@ -126,6 +151,7 @@ type CreateOrderRequest struct {
 	EmailAddress string                   `json:"email_address" validate:"required,email"`
 	Name         string                   `json:"name" validate:"required"`
 	CurrencyCode string                   `json:"currency_code" validate:"required,len=3"`
+	Channel      string                   `json:"channel" validate:"required,oneof=WEB MOBILE_APP MARKETPLACE MOTO"`
 	Items        []CreateOrderItemRequest `json:"items" validate:"required,min=1,dive"`
 }
 
@@ -165,9 +191,23 @@ func ToOrderDTO(order *domain.Order) *OrderDTO {
 		return nil
 	}
 
-	items := make([]*OrderItemDTO, len(order.Items))
+	currency := order.CurrencyCode
+	orderItems := make([]*domain.OrderItem, len(order.Items))
 	for i := range order.Items {
-		items[i] = ToOrderItemDTO(&order.Items[i])
+		orderItems[i] = &order.Items[i]
+	}
+	items := toOrderItemTreeDTOs(orderItems)
+	for _, item := range items {
+		item.roundAmounts(currency)
+	}
+	display := &OrderTotalsDisplayDTO{
+		Subtotal: money.Format(order.OrderSubtotal, currency, order.LocaleCode),
+		Tax:      money.Format(order.TotalTax, currency, order.LocaleCode),
+		Shipping: money.Format(order.TotalShipping, currency, order.LocaleCode),
+		Total:    money.Format(order.OrderTotal, currency, order.LocaleCode),
+	}
+	if order.TotalDuties != 0 {
+		display.Duties = money.Format(order.TotalDuties, currency, order.LocaleCode)
 	}
 
 	return &OrderDTO{
@@ -177,18 +217,26 @@ func ToOrderDTO(order *domain.Order) *OrderDTO {
 		EmailAddress:  order.EmailAddress,
 		Name:          order.Name,
 		Status:        order.Status,
-		OrderSubtotal: order.OrderSubtotal,
-		TotalTax:      order.TotalTax,
-		TotalShipping: order.TotalShipping,
-		OrderTotal:    order.OrderTotal,
+		OrderSubtotal: money.Round(order.OrderSubtotal, currency),
+		TotalTax:      money.Round(order.TotalTax, currency),
+		TotalShipping: money.Round(order.TotalShipping, currency),
+		TotalDuties:   money.Round(order.TotalDuties, currency),
+		OrderTotal:    money.Round(order.OrderTotal, currency),
 		CurrencyCode:  order.CurrencyCode,
+		Display:       display,
 		IsPreview:     order.IsPreview,
 		TaxOverride:   order.TaxOverride,
+		EstimatedTax:      order.EstimatedTax,
+		TaxReconciliation: order.TaxReconciliation,
+		TaxProvider:       order.TaxProvider,
 		LocaleCode:    order.LocaleCode,
+		Channel:       order.Channel,
 		SubmitDate:    order.SubmitDate,
 		CreatedAt:     order.CreatedAt,
 		UpdatedAt:     order.UpdatedAt,
 		Items:         items,
+		Attributes:    order.Attributes,
+		Archived:      order.Archived,
 	}
 }
 
@@ -223,6 +271,83 @@ func ToOrderItemDTO(item *domain.OrderItem) *OrderItemDTO {
 	}
 }
 
+// roundAmounts rounds the item amounts with the order currency's rounding rule
+func (d *OrderItemDTO) roundAmounts(currency string) {
+	d.RetailPrice = money.Round(d.RetailPrice, currency)
+	d.SalePrice = money.Round(d.SalePrice, currency)
+	d.Price = money.Round(d.Price, currency)
+	d.TotalPrice = money.Round(d.TotalPrice, currency)
+	d.TaxAmount = money.Round(d.TaxAmount, currency)
+	d.ShippingAmount = money.Round(d.ShippingAmount, currency)
+	d.TotalWithChildren = money.Round(d.TotalWithChildren, currency)
+	for _, child := range d.ChildItems {
+		child.roundAmounts(currency)
+	}
+}
+
+// ToCartSnapshot converts an order into the cart content evaluated by the offer engine,
+// child items included
+func ToCartSnapshot(order *OrderDTO) *offerApp.CartSnapshot {
+	cart := &offerApp.CartSnapshot{
+		CurrencyCode: order.CurrencyCode,
+		LocaleCode:   order.LocaleCode,
+		Subtotal:     order.OrderSubtotal,
+	}
+	if order.CustomerID != 0 {
+		customerID := order.CustomerID
+		cart.CustomerID = &customerID
+	}
+
+	var addItems func(items []*OrderItemDTO)
+	addItems = func(items []*OrderItemDTO) {
+		for _, item := range items {
+			line := offerApp.CartSnapshotItem{
+				ItemID:     strconv.FormatInt(item.ID, 10),
+				SKUID:      item.SKUID,
+				CategoryID: item.CategoryID,
+				Price:      item.RetailPrice,
+				Quantity:   item.Quantity,
+			}
+			if item.ProductID != 0 {
+				productID := item.ProductID
+				line.ProductID = &productID
+			}
+			if item.SalePrice > 0 && item.SalePrice < item.RetailPrice {
+				salePrice := item.SalePrice
+				line.SalePrice = &salePrice
+			}
+			cart.Items = append(cart.Items, line)
+			addItems(item.ChildItems)
+		}
+	}
+	addItems(order.Items)
+	return cart
+}
+
+// toOrderItemTreeDTOs converts order items into top-level DTOs with their children
+// nested. Children whose parent is not among the items stay at the top level.
+func toOrderItemTreeDTOs(items []*domain.OrderItem) []*OrderItemDTO {
+	dtos := make(map[int64]*OrderItemDTO, len(items))
+	for _, item := range items {
+		dto := ToOrderItemDTO(item)
+		dto.TotalWithChildren = domain.TotalWithChildren(items, item)
+		dtos[item.ID] = dto
+	}
+
+	roots := make([]*OrderItemDTO, 0, len(items))
+	for _, item := range items {
+		dto := dtos[item.ID]
+		if item.ParentOrderItemID != nil {
+			if parent, ok := dtos[*item.ParentOrderItemID]; ok && parent != dto {
+				parent.ChildItems = append(parent.ChildItems, dto)
+				continue
+			}
+		}
+		roots = append(roots, dto)
+	}
+	return roots
+}
+
 func ToOrderAdjustmentDTO(adj *domain.OrderAdjustment) *OrderAdjustmentDTO {
 	return &OrderAdjustmentDTO{
 		ID:               adj.ID,
@@ -266,6 +391,43 @@ func ToFulfillmentGroupDTO(fg *domain.FulfillmentGroup) *FulfillmentGroupDTO {
 	}
 }
 
+// OrderTaxDetailDTO represents the tax a jurisdiction levied on an order item or on
+// the shipping of a fulfillment group
+type OrderTaxDetailDTO struct {
+	OrderItemID        *int64    `json:"order_item_id,omitempty"`
+	FulfillmentGroupID *int64    `json:"fulfillment_group_id,omitempty"`
+	JurisdictionName   string    `json:"jurisdiction_name"`
+	Country            string    `json:"country"`
+	Region             string    `json:"region"`
+	TaxName            string    `json:"tax_name"`
+	Rate               float64   `json:"rate"`
+	TaxableAmount      float64   `json:"taxable_amount"`
+	Amount             float64   `json:"amount"`
+	Provider           string    `json:"provider"`
+	CalculatedAt       time.Time `json:"calculated_at"`
+}
+
+// ToOrderTaxDetailDTOs converts domain OrderTaxDetails to OrderTaxDetailDTOs
+func ToOrderTaxDetailDTOs(details []*domain.OrderTaxDetail) []*OrderTaxDetailDTO {
+	dtos := make([]*OrderTaxDetailDTO, len(details))
+	for i, detail := range details {
+		dtos[i] = &OrderTaxDetailDTO{
+			OrderItemID:        detail.OrderItemID,
+			FulfillmentGroupID: detail.FulfillmentGroupID,
+			JurisdictionName:   detail.JurisdictionName,
+			Country:            detail.Country,
+			Region:             detail.Region,
+			TaxName:            detail.TaxName,
+			Rate:               detail.Rate,
+			TaxableAmount:      money.Round(detail.TaxableAmount, detail.CurrencyCode),
+			Amount:             money.Round(detail.Amount, detail.CurrencyCode),
+			Provider:           detail.Provider,
+			CalculatedAt:       detail.CalculatedAt,
+		}
+	}
+	return dtos
+}
+
 // PaginatedResponse represents a paginated response (generic)
 type PaginatedResponse struct {
 	Data       interface{} `json:"data"`
@@ -290,3 +452,1070 @@ func NewPaginatedResponse(data interface{}, page, pageSize int, totalItems int64
 		TotalPages: totalPages,
 	}
 }
+
+// OrderNoteDTO represents an order note data transfer object.
+type OrderNoteDTO struct {
+	ID              int64     `json:"id"`
+	OrderID         int64     `json:"order_id"`
+	Author          string    `json:"author"`
+	Body            string    `json:"body"`
+	CustomerVisible bool      `json:"customer_visible"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AddOrderNoteRequest represents a request to add a note to an order.
+type AddOrderNoteRequest struct {
+	Author          string `json:"author"` // Defaults to the authenticated admin
+	Body            string `json:"body" validate:"required"`
+	CustomerVisible bool   `json:"customer_visible"`
+}
+
+// OrderTimelineEntryDTO represents one event in an order's timeline.
+type OrderTimelineEntryDTO struct {
+	Type        string        `json:"type"` // created, submitted, status, note
+	Timestamp   time.Time     `json:"timestamp"`
+	Description string        `json:"description"`
+	Note        *OrderNoteDTO `json:"note,omitempty"`
+}
+
+// ToOrderNoteDTO converts a domain.OrderNote to an OrderNoteDTO.
+func ToOrderNoteDTO(note *domain.OrderNote) *OrderNoteDTO {
+	return &OrderNoteDTO{
+		ID:              note.ID,
+		OrderID:         note.OrderID,
+		Author:          note.Author,
+		Body:            note.Body,
+		CustomerVisible: note.CustomerVisible,
+		CreatedAt:       note.CreatedAt,
+	}
+}
+
+// InventoryDeallocationDTO represents a queued inventory deallocation.
+type InventoryDeallocationDTO struct {
+	ID            int64      `json:"id"`
+	OrderID       int64      `json:"order_id"`
+	OrderItemID   int64      `json:"order_item_id"`
+	SKUID         int64      `json:"sku_id"`
+	Quantity      int        `json:"quantity"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"` // Only set while pending
+	ResolvedBy    *string    `json:"resolved_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// DeallocationReconciliationDTO lists the deallocations whose stock is still held
+// because releasing it failed.
+type DeallocationReconciliationDTO struct {
+	GeneratedAt   time.Time                   `json:"generated_at"`
+	Retrying      int                         `json:"retrying"`      // Failed at least once, still being retried
+	DeadLettered  int                         `json:"dead_lettered"` // Retries exhausted
+	UnitsHeld     int                         `json:"units_held"`    // Units still reserved across all entries
+	Deallocations []*InventoryDeallocationDTO `json:"deallocations"`
+}
+
+// ResolveDeallocationRequest represents a request to mark a deallocation as reconciled by hand.
+type ResolveDeallocationRequest struct {
+	ResolvedBy string `json:"resolved_by"` // Defaults to the authenticated admin
+}
+
+// ToInventoryDeallocationDTO converts a domain.InventoryDeallocation to an InventoryDeallocationDTO.
+func ToInventoryDeallocationDTO(d *domain.InventoryDeallocation) *InventoryDeallocationDTO {
+	dto := &InventoryDeallocationDTO{
+		ID:          d.ID,
+		OrderID:     d.OrderID,
+		OrderItemID: d.OrderItemID,
+		SKUID:       d.SKUID,
+		Quantity:    d.Quantity,
+		Status:      string(d.Status),
+		Attempts:    d.Attempts,
+		LastError:   d.LastError,
+		ResolvedBy:  d.ResolvedBy,
+		CreatedAt:   d.CreatedAt,
+		CompletedAt: d.CompletedAt,
+	}
+	if d.Status == domain.DeallocationStatusPending {
+		nextAttemptAt := d.NextAttemptAt
+		dto.NextAttemptAt = &nextAttemptAt
+	}
+	return dto
+}
+
+// OrderHoldDTO represents a hold pausing the fulfillment of an order.
+type OrderHoldDTO struct {
+	ID             int64      `json:"id"`
+	OrderID        int64      `json:"order_id"`
+	OrderNumber    string     `json:"order_number"`
+	EmailAddress   string     `json:"email_address,omitempty"`
+	OrderTotal     float64    `json:"order_total"`
+	CurrencyCode   string     `json:"currency_code"`
+	Reason         string     `json:"reason"`
+	Note           string     `json:"note,omitempty"`
+	PreviousStatus string     `json:"previous_status"`
+	PlacedBy       string     `json:"placed_by"`
+	PlacedAt       time.Time  `json:"placed_at"`
+	Open           bool       `json:"open"`
+	Overdue        bool       `json:"overdue"` // Open longer than the hold SLA
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolvedBy     *string    `json:"resolved_by,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	SLANotifiedAt  *time.Time `json:"sla_notified_at,omitempty"`
+}
+
+// OrderHoldQueueDTO lists the orders on hold awaiting release or cancellation, oldest first.
+type OrderHoldQueueDTO struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	SLA         string          `json:"sla,omitempty"`
+	Overdue     int             `json:"overdue"`
+	Holds       []*OrderHoldDTO `json:"holds"`
+}
+
+// PlaceOrderHoldRequest represents a request to put an order on hold.
+type PlaceOrderHoldRequest struct {
+	Reason   string `json:"reason"` // FRAUD_REVIEW, PAYMENT_VERIFICATION or ADDRESS_ISSUE
+	Note     string `json:"note"`
+	PlacedBy string `json:"placed_by"` // Defaults to the authenticated admin
+}
+
+// ResolveOrderHoldRequest represents a request to release or cancel an order on hold.
+type ResolveOrderHoldRequest struct {
+	Note       string `json:"note"`        // Recorded on the hold; also the cancellation reason
+	ResolvedBy string `json:"resolved_by"` // Defaults to the authenticated admin
+}
+
+// ToOrderHoldDTO converts a domain.OrderHold to an OrderHoldDTO; holds open longer than sla are overdue.
+func ToOrderHoldDTO(h *domain.OrderHold, now time.Time, sla time.Duration) *OrderHoldDTO {
+	return &OrderHoldDTO{
+		ID:             h.ID,
+		OrderID:        h.OrderID,
+		OrderNumber:    h.OrderNumber,
+		EmailAddress:   h.EmailAddress,
+		OrderTotal:     h.OrderTotal,
+		CurrencyCode:   h.CurrencyCode,
+		Reason:         string(h.Reason),
+		Note:           h.Note,
+		PreviousStatus: string(h.PreviousStatus),
+		PlacedBy:       h.PlacedBy,
+		PlacedAt:       h.PlacedAt,
+		Open:           h.IsOpen(),
+		Overdue:        h.IsOverdue(now, sla),
+		Resolution:     string(h.Resolution),
+		ResolvedBy:     h.ResolvedBy,
+		ResolutionNote: h.ResolutionNote,
+		ResolvedAt:     h.ResolvedAt,
+		SLANotifiedAt:  h.SLANotifiedAt,
+	}
+}
+
+// GiftWrapOptionDTO represents a gift wrap offered on the storefront.
+type GiftWrapOptionDTO struct {
+	ID          int64   `json:"id"`
+	SKUID       int64   `json:"sku_id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Price       float64 `json:"price"` // Per wrapped unit
+	Active      bool    `json:"active"`
+}
+
+// SaveGiftWrapOptionRequest represents a request to create or update a gift wrap option.
+type SaveGiftWrapOptionRequest struct {
+	SKUID       int64  `json:"sku_id" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	Active      *bool  `json:"active"` // Defaults to true on create and unchanged on update
+}
+
+// PersonalMessageDTO represents a gift message.
+type PersonalMessageDTO struct {
+	ID       int64  `json:"id"`
+	Message  string `json:"message"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Occasion string `json:"occasion,omitempty"`
+}
+
+// PersonalMessageRequest represents the gift message of a cart item.
+type PersonalMessageRequest struct {
+	Message  string `json:"message" validate:"required"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Occasion string `json:"occasion"`
+}
+
+// SetGiftOptionsRequest replaces the gift options of a cart item. Omitting an
+// option removes it.
+type SetGiftOptionsRequest struct {
+	GiftWrapOptionID *int64                  `json:"gift_wrap_option_id"`
+	PersonalMessage  *PersonalMessageRequest `json:"personal_message"`
+}
+
+// OrderItemGiftOptionsDTO represents the gift options of an order item.
+type OrderItemGiftOptionsDTO struct {
+	OrderItemID     int64               `json:"order_item_id"`
+	GiftWrap        *GiftWrapItemDTO    `json:"gift_wrap,omitempty"`
+	PersonalMessage *PersonalMessageDTO `json:"personal_message,omitempty"`
+}
+
+// GiftWrapItemDTO represents the child order item that prices a gift wrap.
+type GiftWrapItemDTO struct {
+	OrderItemID int64   `json:"order_item_id"`
+	SKUID       int64   `json:"sku_id"`
+	Name        string  `json:"name"`
+	Quantity    int     `json:"quantity"`
+	TotalPrice  float64 `json:"total_price"`
+}
+
+// PackingSlipDTO represents the packing slip of an order. Gift wraps are shown
+// on the items they wrap rather than as lines of their own, and prices are left out.
+type PackingSlipDTO struct {
+	OrderID     int64                 `json:"order_id"`
+	OrderNumber string                `json:"order_number"`
+	ShipToName  string                `json:"ship_to_name"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	Lines       []*PackingSlipLineDTO `json:"lines"`
+}
+
+// PackingSlipLineDTO represents one item on a packing slip.
+type PackingSlipLineDTO struct {
+	OrderItemID     int64               `json:"order_item_id"`
+	SKUID           int64               `json:"sku_id"`
+	Name            string              `json:"name"`
+	Quantity        int                 `json:"quantity"`
+	GiftWrap        string              `json:"gift_wrap,omitempty"`
+	PersonalMessage *PersonalMessageDTO `json:"personal_message,omitempty"`
+}
+
+// PackingSlipShipmentDTO identifies the shipment a packing slip is packed with.
+type PackingSlipShipmentDTO struct {
+	ShipmentID     int64    `json:"shipment_id"`
+	Carrier        string   `json:"carrier"`
+	ShippingMethod string   `json:"shipping_method"`
+	TrackingNumber string   `json:"tracking_number,omitempty"`
+	AddressLines   []string `json:"address_lines"`
+}
+
+// OrderDocumentDTO represents a rendered order document such as an invoice.
+type OrderDocumentDTO struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// ToPersonalMessageDTO converts a domain.PersonalMessage to a PersonalMessageDTO.
+func ToPersonalMessageDTO(message *domain.PersonalMessage) *PersonalMessageDTO {
+	return &PersonalMessageDTO{
+		ID:       message.ID,
+		Message:  message.Message,
+		From:     message.MessageFrom,
+		To:       message.MessageTo,
+		Occasion: message.Occasion,
+	}
+}
+
+// CreateAssistedOrderRequest represents an order an agent places for a phone or mail customer.
+// Either CustomerID or Guest is set.
+type CreateAssistedOrderRequest struct {
+	Channel          string                      `json:"channel" validate:"required,oneof=PHONE MAIL"`
+	CustomerID       *int64                      `json:"customer_id,omitempty"`
+	Guest            *AssistedOrderGuestRequest  `json:"guest,omitempty"`
+	CurrencyCode     string                      `json:"currency_code" validate:"required"`
+	LocaleCode       string                      `json:"locale_code"`
+	Items            []AssistedOrderItemRequest  `json:"items" validate:"required,min=1,dive"`
+	Payment          AssistedOrderPaymentRequest `json:"payment"`
+	BypassCartPolicy bool                        `json:"bypass_cart_policy"` // Requires the order override permission
+}
+
+// AssistedOrderGuestRequest represents a customer without an account.
+type AssistedOrderGuestRequest struct {
+	EmailAddress string `json:"email_address" validate:"required,email"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+}
+
+// AssistedOrderItemRequest represents an item of an assisted order.
+type AssistedOrderItemRequest struct {
+	SKUID       int64    `json:"sku_id" validate:"required"`
+	Quantity    int      `json:"quantity" validate:"required,min=1"`
+	TaxCategory string   `json:"tax_category"`
+	Price       *float64 `json:"price,omitempty"` // Overrides the current unit price; requires the order override permission
+}
+
+// AssistedOrderPaymentRequest represents how an assisted order is paid.
+type AssistedOrderPaymentRequest struct {
+	Method            string `json:"method" validate:"required,oneof=CARD PAY_BY_LINK OFFLINE"`
+	PaymentMethodType string `json:"payment_method_type"` // Gateway method for card payments; defaults to CREDIT_CARD
+	PaymentToken      string `json:"payment_token"`       // Card tokenized by the payment terminal
+}
+
+// AssistedOrderDTO represents an order placed by an agent.
+type AssistedOrderDTO struct {
+	Order              *OrderDTO       `json:"order"`
+	Channel            string          `json:"channel"`
+	AgentID            string          `json:"agent_id"`
+	Guest              bool            `json:"guest"`
+	PriceOverridden    bool            `json:"price_overridden"`
+	CartPolicyBypassed bool            `json:"cart_policy_bypassed"`
+	PaymentMethod      string          `json:"payment_method"`
+	PaymentStatus      string          `json:"payment_status"`
+	TransactionID      *string         `json:"transaction_id,omitempty"`
+	PaymentLink        *PaymentLinkDTO `json:"payment_link,omitempty"` // Latest link of orders paid by link
+}
+
+// CreatePaymentLinkRequest represents a payment link issued for an unpaid order.
+type CreatePaymentLinkRequest struct {
+	Reason         string `json:"reason" validate:"required,oneof=ASSISTED_ORDER PAYMENT_RECOVERY QUOTE"`
+	ExpiresInHours int    `json:"expires_in_hours" validate:"min=0"` // Defaults to the configured link lifetime
+	SendEmail      bool   `json:"send_email"`                        // Email the link to the order's email address
+}
+
+// PaymentLinkDTO represents a payment link of an order.
+type PaymentLinkDTO struct {
+	ID            int64      `json:"id"`
+	OrderID       int64      `json:"order_id"`
+	Reason        string     `json:"reason"`
+	Amount        float64    `json:"amount"`
+	CurrencyCode  string     `json:"currency_code"`
+	Status        string     `json:"status"`
+	URL           string     `json:"url,omitempty"` // Shown while the link can be paid
+	ExpiresAt     time.Time  `json:"expires_at"`
+	CreatedBy     string     `json:"created_by,omitempty"`
+	TransactionID *string    `json:"transaction_id,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Emailed       bool       `json:"emailed,omitempty"`
+}
+
+// PayLinkDTO represents what a customer pays through a payment link and how.
+type PayLinkDTO struct {
+	OrderNumber  string          `json:"order_number"`
+	Name         string          `json:"name"`
+	Amount       float64         `json:"amount"`
+	CurrencyCode string          `json:"currency_code"`
+	Items        []*OrderItemDTO `json:"items"`
+	Status       string          `json:"status"`
+	Payable      bool            `json:"payable"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+	Gateway      string          `json:"gateway,omitempty"`    // Gateway the payment form confirms the payment with
+	PublicKey    string          `json:"public_key,omitempty"` // Publishable gateway key for the payment form
+}
+
+// PayByLinkRequest represents the payment a customer makes through a pay link.
+type PayByLinkRequest struct {
+	PaymentMethodType string `json:"payment_method_type"`               // Defaults to CREDIT_CARD
+	PaymentToken      string `json:"payment_token" validate:"required"` // Payment method or approved order ID from the gateway
+}
+
+// ChannelOrderResultDTO reports the outcome of ingesting one order of a sales channel.
+type ChannelOrderResultDTO struct {
+	ExternalOrderID string `json:"external_order_id"`
+	Status          string `json:"status"` // CREATED, DUPLICATE or FAILED
+	OrderID         *int64 `json:"order_id,omitempty"`
+	OrderNumber     string `json:"order_number,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// ChannelOrderBatchDTO reports the outcome of a batch of channel orders.
+type ChannelOrderBatchDTO struct {
+	Channel      string                   `json:"channel"`
+	Results      []*ChannelOrderResultDTO `json:"results"`
+	Created      int                      `json:"created"`
+	Duplicates   int                      `json:"duplicates"`
+	Failed       int                      `json:"failed"`
+	Acknowledged bool                     `json:"acknowledged"` // Results were reported to the channel's acknowledgement URL
+}
+
+// ChannelOrderDTO represents the ingestion record of a channel order.
+type ChannelOrderDTO struct {
+	ID              int64      `json:"id"`
+	Channel         string     `json:"channel"`
+	ExternalOrderID string     `json:"external_order_id"`
+	OrderID         *int64     `json:"order_id,omitempty"`
+	Status          string     `json:"status"`
+	FailureReason   string     `json:"failure_reason,omitempty"`
+	Attempts        int        `json:"attempts"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ChannelSKUMappingDTO represents a channel SKU mapped to an internal SKU.
+type ChannelSKUMappingDTO struct {
+	Channel     string    `json:"channel"`
+	ExternalSKU string    `json:"external_sku"`
+	SKUID       int64     `json:"sku_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SaveChannelSKUMappingRequest represents a request to map a channel SKU to an internal SKU.
+type SaveChannelSKUMappingRequest struct {
+	ExternalSKU string `json:"external_sku" validate:"required"`
+	SKUID       int64  `json:"sku_id" validate:"required"`
+}
+
+// ToChannelOrderDTO converts a domain.ChannelOrder to a ChannelOrderDTO
+func ToChannelOrderDTO(order *domain.ChannelOrder) *ChannelOrderDTO {
+	return &ChannelOrderDTO{
+		ID:              order.ID,
+		Channel:         order.Channel,
+		ExternalOrderID: order.ExternalOrderID,
+		OrderID:         order.OrderID,
+		Status:          string(order.Status),
+		FailureReason:   order.FailureReason,
+		Attempts:        order.Attempts,
+		AcknowledgedAt:  order.AcknowledgedAt,
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+	}
+}
+
+// ToChannelSKUMappingDTO converts a domain.ChannelSKUMapping to a ChannelSKUMappingDTO
+func ToChannelSKUMappingDTO(mapping *domain.ChannelSKUMapping) *ChannelSKUMappingDTO {
+	return &ChannelSKUMappingDTO{
+		Channel:     mapping.Channel,
+		ExternalSKU: mapping.ExternalSKU,
+		SKUID:       mapping.SKUID,
+		CreatedAt:   mapping.CreatedAt,
+		UpdatedAt:   mapping.UpdatedAt,
+	}
+}
+
+// OrderRetentionPolicyDTO represents how long orders in a final status stay live
+type OrderRetentionPolicyDTO struct {
+	Status          string    `json:"status"`
+	RetentionMonths int       `json:"retention_months"` // 0 keeps the orders live
+	UpdatedBy       string    `json:"updated_by,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SetOrderRetentionRequest represents a request to set the retention window of a status
+type SetOrderRetentionRequest struct {
+	RetentionMonths int `json:"retention_months" validate:"min=0,max=240"`
+}
+
+// OrderArchiveRunDTO reports an archival run
+type OrderArchiveRunDTO struct {
+	Archived int64 `json:"archived"`
+}
+
+// ToOrderRetentionPolicyDTO converts a domain.OrderRetentionPolicy to an OrderRetentionPolicyDTO
+func ToOrderRetentionPolicyDTO(policy *domain.OrderRetentionPolicy) *OrderRetentionPolicyDTO {
+	return &OrderRetentionPolicyDTO{
+		Status:          string(policy.Status),
+		RetentionMonths: policy.RetentionMonths,
+		UpdatedBy:       policy.UpdatedBy,
+		UpdatedAt:       policy.UpdatedAt,
+	}
+}
+
+// OfflinePaymentMethodDTO represents a way to pay outside the platform
+type OfflinePaymentMethodDTO struct {
+	ID            int64     `json:"id"`
+	Code          string    `json:"code"`
+	Type          string    `json:"type"`
+	Name          string    `json:"name"`
+	Instructions  string    `json:"instructions,omitempty"`
+	Enabled       bool      `json:"enabled"`
+	Countries     []string  `json:"countries"`
+	MinOrderTotal *float64  `json:"min_order_total,omitempty"`
+	MaxOrderTotal *float64  `json:"max_order_total,omitempty"`
+	SortOrder     int       `json:"sort_order"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SaveOfflinePaymentMethodRequest represents a request to create or update an offline payment method.
+// The code is only read on creation.
+type SaveOfflinePaymentMethodRequest struct {
+	Code          string   `json:"code"`
+	Type          string   `json:"type" validate:"required,oneof=COD BANK_TRANSFER PAY_IN_STORE"`
+	Name          string   `json:"name" validate:"required"`
+	Instructions  string   `json:"instructions"`
+	Enabled       *bool    `json:"enabled"`   // Defaults to true
+	Countries     []string `json:"countries"` // Empty offers the method everywhere
+	MinOrderTotal *float64 `json:"min_order_total"`
+	MaxOrderTotal *float64 `json:"max_order_total"`
+	SortOrder     int      `json:"sort_order"`
+}
+
+// AvailableOfflinePaymentMethodDTO represents an offline payment method a customer can place an order with
+type AvailableOfflinePaymentMethodDTO struct {
+	Code         string `json:"code"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// PlaceOfflinePaymentOrderRequest represents the offline payment method a customer places an order with
+type PlaceOfflinePaymentOrderRequest struct {
+	MethodCode string `json:"method_code" validate:"required"`
+}
+
+// RecordOfflinePaymentRequest represents the receipt of an offline payment
+type RecordOfflinePaymentRequest struct {
+	Amount           float64    `json:"amount" validate:"required,gt=0"` // Must be the full amount due
+	ReceiptReference string     `json:"receipt_reference"`               // e.g. the bank statement entry
+	ReceivedAt       *time.Time `json:"received_at"`                     // Defaults to now
+	Note             string     `json:"note"`
+}
+
+// OfflinePaymentDTO represents the offline payment of an order
+type OfflinePaymentDTO struct {
+	OrderID          int64      `json:"order_id"`
+	OrderNumber      string     `json:"order_number,omitempty"`
+	OrderStatus      string     `json:"order_status,omitempty"`
+	MethodCode       string     `json:"method_code"`
+	Type             string     `json:"type"`
+	Amount           float64    `json:"amount"`
+	CurrencyCode     string     `json:"currency_code"`
+	Reference        string     `json:"reference"`
+	Instructions     string     `json:"instructions,omitempty"`
+	Status           string     `json:"status"`
+	ReceivedAmount   *float64   `json:"received_amount,omitempty"`
+	ReceiptReference string     `json:"receipt_reference,omitempty"`
+	ReceivedBy       string     `json:"received_by,omitempty"`
+	ReceivedAt       *time.Time `json:"received_at,omitempty"`
+	Note             string     `json:"note,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// ToOfflinePaymentMethodDTO converts a domain.OfflinePaymentMethod to an OfflinePaymentMethodDTO
+func ToOfflinePaymentMethodDTO(method *domain.OfflinePaymentMethod) *OfflinePaymentMethodDTO {
+	return &OfflinePaymentMethodDTO{
+		ID:            method.ID,
+		Code:          method.Code,
+		Type:          string(method.Type),
+		Name:          method.Name,
+		Instructions:  method.Instructions,
+		Enabled:       method.Enabled,
+		Countries:     method.Countries,
+		MinOrderTotal: method.MinOrderTotal,
+		MaxOrderTotal: method.MaxOrderTotal,
+		SortOrder:     method.SortOrder,
+		CreatedAt:     method.CreatedAt,
+		UpdatedAt:     method.UpdatedAt,
+	}
+}
+
+// ToOfflinePaymentDTO converts a domain.OfflinePayment to an OfflinePaymentDTO
+func ToOfflinePaymentDTO(payment *domain.OfflinePayment) *OfflinePaymentDTO {
+	return &OfflinePaymentDTO{
+		OrderID:          payment.OrderID,
+		MethodCode:       payment.MethodCode,
+		Type:             string(payment.Type),
+		Amount:           payment.Amount,
+		CurrencyCode:     payment.CurrencyCode,
+		Reference:        payment.Reference,
+		Status:           string(payment.Status),
+		ReceivedAmount:   payment.ReceivedAmount,
+		ReceiptReference: payment.ReceiptReference,
+		ReceivedBy:       payment.ReceivedBy,
+		ReceivedAt:       payment.ReceivedAt,
+		Note:             payment.Note,
+		CreatedAt:        payment.CreatedAt,
+	}
+}
+
+// SplitPaymentRequest represents an order paid with one or more tenders
+type SplitPaymentRequest struct {
+	Tenders []*TenderPaymentRequest `json:"tenders" validate:"required,min=1,dive"`
+}
+
+// TenderPaymentRequest represents one tender of a split payment
+type TenderPaymentRequest struct {
+	Method string  `json:"method" validate:"required"` // GIFT_CARD, CREDIT_CARD, DEBIT_CARD or PAYPAL
+	Token  string  `json:"token" validate:"required"`  // Gift card number or gateway payment method / approved order ID
+	Amount float64 `json:"amount" validate:"min=0"`    // Omitted on the one tender paying the remainder
+}
+
+// RefundOrderRequest represents a refund spread across an order's tenders
+type RefundOrderRequest struct {
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+	Reason string  `json:"reason"`
+}
+
+// OrderTenderDTO represents one tender of an order's payment
+type OrderTenderDTO struct {
+	ID             int64      `json:"id"`
+	Sequence       int        `json:"sequence"`
+	Method         string     `json:"method"`
+	Amount         float64    `json:"amount"`
+	CurrencyCode   string     `json:"currency_code"`
+	Status         string     `json:"status"`
+	TransactionID  string     `json:"transaction_id,omitempty"`
+	CapturedAmount float64    `json:"captured_amount"`
+	RefundedAmount float64    `json:"refunded_amount"`
+	FailureReason  string     `json:"failure_reason,omitempty"`
+	AuthorizedAt   *time.Time `json:"authorized_at,omitempty"`
+	CapturedAt     *time.Time `json:"captured_at,omitempty"`
+	RefundedAt     *time.Time `json:"refunded_at,omitempty"`
+}
+
+// OrderPaymentDTO represents the tenders an order is paid with and their totals
+type OrderPaymentDTO struct {
+	OrderID    int64             `json:"order_id"`
+	Tenders    []*OrderTenderDTO `json:"tenders"`
+	Authorized float64           `json:"authorized"`
+	Captured   float64           `json:"captured"`
+	Refunded   float64           `json:"refunded"`
+	Complete   bool              `json:"complete"` // Every tender the operation touched succeeded
+}
+
+// TenderRefundDTO represents the part of a refund one tender paid back
+type TenderRefundDTO struct {
+	TenderID int64   `json:"tender_id"`
+	Method   string  `json:"method"`
+	Amount   float64 `json:"amount"`
+	Refunded bool    `json:"refunded"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// OrderRefundDTO reports how a refund was spread across an order's tenders
+type OrderRefundDTO struct {
+	OrderID     int64              `json:"order_id"`
+	Requested   float64            `json:"requested"`
+	Refunded    float64            `json:"refunded"`
+	Allocations []*TenderRefundDTO `json:"allocations"`
+	Complete    bool               `json:"complete"`
+}
+
+// ToOrderTenderDTO converts a domain.OrderTender to an OrderTenderDTO
+func ToOrderTenderDTO(tender *domain.OrderTender) *OrderTenderDTO {
+	return &OrderTenderDTO{
+		ID:             tender.ID,
+		Sequence:       tender.Sequence,
+		Method:         tender.Method,
+		Amount:         tender.Amount,
+		CurrencyCode:   tender.CurrencyCode,
+		Status:         string(tender.Status),
+		TransactionID:  tender.TransactionID,
+		CapturedAmount: tender.CapturedAmount,
+		RefundedAmount: tender.RefundedAmount,
+		FailureReason:  tender.FailureReason,
+		AuthorizedAt:   tender.AuthorizedAt,
+		CapturedAt:     tender.CapturedAt,
+		RefundedAt:     tender.RefundedAt,
+	}
+}
+
+// OrderTagDTO represents a tag on an order
+type OrderTagDTO struct {
+	Tag       string    `json:"tag"`
+	Source    string    `json:"source"` // MANUAL or RULE
+	RuleID    *int64    `json:"rule_id,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrderTagCountDTO represents how many orders carry a tag, by order status
+type OrderTagCountDTO struct {
+	Tag      string           `json:"tag"`
+	Orders   int64            `json:"orders"`
+	ByStatus map[string]int64 `json:"by_status"`
+}
+
+// AddOrderTagsRequest represents a request to tag an order
+type AddOrderTagsRequest struct {
+	Tags      []string `json:"tags"`
+	CreatedBy string   `json:"created_by"` // Defaults to the authenticated admin
+}
+
+// OrderQueueRequest selects the orders of a work queue. Tags and statuses are matched
+// case-insensitively; an empty request lists every order.
+type OrderQueueRequest struct {
+	AllTags     []string `json:"all_tags"`     // Orders carrying every one of these tags
+	AnyTags     []string `json:"any_tags"`     // Orders carrying at least one of these tags
+	ExcludeTags []string `json:"exclude_tags"` // Orders carrying none of these tags
+	Statuses    []string `json:"statuses"`
+	NewestFirst bool     `json:"newest_first"` // Oldest first by default
+	Page        int      `json:"page"`
+	PageSize    int      `json:"page_size"`
+}
+
+// OrderQueueItemDTO represents an order of a work queue
+type OrderQueueItemDTO struct {
+	OrderID      int64      `json:"order_id"`
+	OrderNumber  string     `json:"order_number"`
+	Status       string     `json:"status"`
+	CustomerID   int64      `json:"customer_id"`
+	EmailAddress string     `json:"email_address,omitempty"`
+	OrderTotal   float64    `json:"order_total"`
+	CurrencyCode string     `json:"currency_code"`
+	SubmitDate   *time.Time `json:"submit_date,omitempty"`
+	Tags         []string   `json:"tags"`
+}
+
+// OrderTagRuleDTO represents a rule tagging submitted orders
+type OrderTagRuleDTO struct {
+	ID         int64                     `json:"id"`
+	Name       string                    `json:"name"`
+	Tag        string                    `json:"tag"`
+	Conditions domain.OrderTagConditions `json:"conditions"`
+	Active     bool                      `json:"active"`
+	CreatedBy  string                    `json:"created_by,omitempty"`
+	CreatedAt  time.Time                 `json:"created_at"`
+	UpdatedAt  time.Time                 `json:"updated_at"`
+}
+
+// OrderTagRuleRequest represents a request to create or update a tag rule
+type OrderTagRuleRequest struct {
+	Name       string                    `json:"name"`
+	Tag        string                    `json:"tag"`
+	Conditions domain.OrderTagConditions `json:"conditions"`
+	Active     *bool                     `json:"active"` // Defaults to true on creation and unchanged on update
+	CreatedBy  string                    `json:"created_by"`
+}
+
+// ToOrderTagDTO converts a domain.OrderTag to an OrderTagDTO
+func ToOrderTagDTO(tag *domain.OrderTag) *OrderTagDTO {
+	return &OrderTagDTO{
+		Tag:       tag.Tag,
+		Source:    string(tag.Source),
+		RuleID:    tag.RuleID,
+		CreatedBy: tag.CreatedBy,
+		CreatedAt: tag.CreatedAt,
+	}
+}
+
+// ToOrderQueueItemDTO converts a domain.OrderQueueItem to an OrderQueueItemDTO
+func ToOrderQueueItemDTO(item *domain.OrderQueueItem) *OrderQueueItemDTO {
+	return &OrderQueueItemDTO{
+		OrderID:      item.OrderID,
+		OrderNumber:  item.OrderNumber,
+		Status:       string(item.Status),
+		CustomerID:   item.CustomerID,
+		EmailAddress: item.EmailAddress,
+		OrderTotal:   item.OrderTotal,
+		CurrencyCode: item.CurrencyCode,
+		SubmitDate:   item.SubmitDate,
+		Tags:         item.Tags,
+	}
+}
+
+// ToOrderTagRuleDTO converts a domain.OrderTagRule to an OrderTagRuleDTO
+func ToOrderTagRuleDTO(rule *domain.OrderTagRule) *OrderTagRuleDTO {
+	return &OrderTagRuleDTO{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Tag:        rule.Tag,
+		Conditions: rule.Conditions,
+		Active:     rule.Active,
+		CreatedBy:  rule.CreatedBy,
+		CreatedAt:  rule.CreatedAt,
+		UpdatedAt:  rule.UpdatedAt,
+	}
+}
+
+// CartRevalidationDTO is the outcome of revalidating a cart: what changed and the updated cart
+type CartRevalidationDTO struct {
+	OrderID       int64                 `json:"order_id"`
+	Changed       bool                  `json:"changed"`
+	Lines         []*CartLineChangeDTO  `json:"lines"`
+	Offers        []*CartOfferChangeDTO `json:"offers"`
+	PreviousTotal float64               `json:"previous_total"`
+	Total         float64               `json:"total"`
+	Cart          *OrderDTO             `json:"cart"`
+}
+
+// CartLineChangeDTO represents a change made to a cart line
+type CartLineChangeDTO struct {
+	OrderItemID      int64   `json:"order_item_id"`
+	SKUID            int64   `json:"sku_id"`
+	Name             string  `json:"name"`
+	Change           string  `json:"change"`
+	Reason           string  `json:"reason,omitempty"`
+	PreviousPrice    float64 `json:"previous_price"`
+	Price            float64 `json:"price"`
+	PreviousQuantity int     `json:"previous_quantity"`
+	Quantity         int     `json:"quantity"`
+}
+
+// CartOfferChangeDTO represents a change in the discount of an offer on a cart
+type CartOfferChangeDTO struct {
+	OfferID        int64   `json:"offer_id"`
+	Description    string  `json:"description"`
+	Change         string  `json:"change"`
+	PreviousAmount float64 `json:"previous_amount"`
+	Amount         float64 `json:"amount"`
+}
+
+// RevalidateCartRequest is the request to revalidate a cart
+type RevalidateCartRequest struct {
+	CouponCode *string `json:"coupon_code,omitempty"` // Coupon to apply again; codes are not kept with the cart
+}
+
+// ToCartLineChangeDTO converts a domain.CartLineChange to a CartLineChangeDTO
+func ToCartLineChangeDTO(change *domain.CartLineChange) *CartLineChangeDTO {
+	return &CartLineChangeDTO{
+		OrderItemID:      change.OrderItemID,
+		SKUID:            change.SKUID,
+		Name:             change.Name,
+		Change:           string(change.Type),
+		Reason:           change.Reason,
+		PreviousPrice:    change.PreviousPrice,
+		Price:            change.Price,
+		PreviousQuantity: change.PreviousQuantity,
+		Quantity:         change.Quantity,
+	}
+}
+
+// ToCartOfferChangeDTO converts a domain.CartOfferChange to a CartOfferChangeDTO
+func ToCartOfferChangeDTO(change *domain.CartOfferChange) *CartOfferChangeDTO {
+	return &CartOfferChangeDTO{
+		OfferID:        change.OfferID,
+		Description:    change.Description,
+		Change:         string(change.Type),
+		PreviousAmount: change.PreviousAmount,
+		Amount:         change.Amount,
+	}
+}
+
+// OrderAttachmentDTO represents a file attached to an order
+type OrderAttachmentDTO struct {
+	ID                 int64     `json:"id"`
+	OrderID            int64     `json:"order_id"`
+	Kind               string    `json:"kind"`
+	Visibility         string    `json:"visibility"`
+	FileName           string    `json:"file_name"`
+	ContentType        string    `json:"content_type"`
+	SizeBytes          int64     `json:"size_bytes"`
+	Checksum           string    `json:"checksum"` // SHA-256, hex encoded
+	Description        string    `json:"description,omitempty"`
+	UploadedBy         string    `json:"uploaded_by,omitempty"` // Left out on the storefront
+	UploadedByCustomer bool      `json:"uploaded_by_customer"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// UploadOrderAttachmentRequest represents a file to attach to an order
+type UploadOrderAttachmentRequest struct {
+	Kind        string // PURCHASE_ORDER, CUSTOMS, DAMAGE_PHOTO or OTHER; OTHER by default
+	Visibility  string // INTERNAL or CUSTOMER; INTERNAL by default, always CUSTOMER for customers
+	FileName    string
+	ContentType string // Detected from the content when empty
+	Description string
+	Content     []byte
+}
+
+// UpdateOrderAttachmentRequest represents a change of who can see an attachment
+type UpdateOrderAttachmentRequest struct {
+	Visibility string `json:"visibility" validate:"required"`
+}
+
+// OrderAttachmentContentDTO is the content of an attachment being downloaded
+type OrderAttachmentContentDTO struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// ToOrderAttachmentDTO converts a domain.OrderAttachment to an OrderAttachmentDTO
+func ToOrderAttachmentDTO(attachment *domain.OrderAttachment) *OrderAttachmentDTO {
+	return &OrderAttachmentDTO{
+		ID:                 attachment.ID,
+		OrderID:            attachment.OrderID,
+		Kind:               string(attachment.Kind),
+		Visibility:         string(attachment.Visibility),
+		FileName:           attachment.FileName,
+		ContentType:        attachment.ContentType,
+		SizeBytes:          attachment.SizeBytes,
+		Checksum:           attachment.Checksum,
+		Description:        attachment.Description,
+		UploadedBy:         attachment.UploadedBy,
+		UploadedByCustomer: attachment.UploadedByCustomer,
+		CreatedAt:          attachment.CreatedAt,
+		UpdatedAt:          attachment.UpdatedAt,
+	}
+}
+
+// OrderLandedCostDTO represents the duties and import fees estimated for a
+// cross-border order
+type OrderLandedCostDTO struct {
+	OrderID            int64                     `json:"order_id"`
+	CrossBorder        bool                      `json:"cross_border"` // False when the order ships within the ship-from country and owes no duties
+	Incoterm           domain.Incoterm           `json:"incoterm,omitempty"`
+	ShipFromCountry    string                    `json:"ship_from_country"`
+	DestinationCountry string                    `json:"destination_country"`
+	Duty               float64                   `json:"duty"`
+	ImportTax          float64                   `json:"import_tax"`
+	Fees               float64                   `json:"fees"`
+	Total              float64                   `json:"total"`
+	ChargedWithOrder   float64                   `json:"charged_with_order"` // Part of the order total: the whole estimate under DDP, nothing under DAP
+	DeMinimisApplied   bool                      `json:"de_minimis_applied"`
+	CurrencyCode       string                    `json:"currency_code"`
+	Provider           string                    `json:"provider,omitempty"`
+	Lines              []*OrderLandedCostLineDTO `json:"lines,omitempty"`
+	Stale              bool                      `json:"stale"` // The cart changed since the estimate; estimate again before checkout
+	EstimatedAt        *time.Time                `json:"estimated_at,omitempty"`
+}
+
+// OrderLandedCostLineDTO represents the duty and import tax estimated for an order item
+type OrderLandedCostLineDTO struct {
+	OrderItemID   int64   `json:"order_item_id"`
+	HSCode        string  `json:"hs_code,omitempty"`
+	OriginCountry string  `json:"origin_country,omitempty"`
+	CustomsValue  float64 `json:"customs_value"`
+	DutyRate      float64 `json:"duty_rate"`
+	Duty          float64 `json:"duty"`
+	ImportTax     float64 `json:"import_tax"`
+}
+
+// EstimateLandedCostRequest represents the incoterm a customer chose for a cross-border order
+type EstimateLandedCostRequest struct {
+	Incoterm string `json:"incoterm"` // DDP or DAP
+}
+
+// ToOrderLandedCostDTO converts a domain.OrderLandedCost of an order to an OrderLandedCostDTO
+func ToOrderLandedCostDTO(cost *domain.OrderLandedCost, order *domain.Order) *OrderLandedCostDTO {
+	currency := cost.CurrencyCode
+	lines := make([]*OrderLandedCostLineDTO, len(cost.Lines))
+	for i, line := range cost.Lines {
+		lines[i] = &OrderLandedCostLineDTO{
+			OrderItemID:   line.OrderItemID,
+			HSCode:        line.HSCode,
+			OriginCountry: line.OriginCountry,
+			CustomsValue:  money.Round(line.CustomsValue, currency),
+			DutyRate:      line.DutyRate,
+			Duty:          money.Round(line.Duty, currency),
+			ImportTax:     money.Round(line.ImportTax, currency),
+		}
+	}
+	estimatedAt := cost.EstimatedAt
+	return &OrderLandedCostDTO{
+		OrderID:            cost.OrderID,
+		CrossBorder:        true,
+		Incoterm:           cost.Incoterm,
+		ShipFromCountry:    cost.ShipFromCountry,
+		DestinationCountry: cost.DestinationCountry,
+		Duty:               money.Round(cost.Duty, currency),
+		ImportTax:          money.Round(cost.ImportTax, currency),
+		Fees:               money.Round(cost.Fees, currency),
+		Total:              money.Round(cost.Total, currency),
+		ChargedWithOrder:   money.Round(cost.ChargedAmount(), currency),
+		DeMinimisApplied:   cost.DeMinimisApplied,
+		CurrencyCode:       currency,
+		Provider:           cost.Provider,
+		Lines:              lines,
+		Stale:              order.IsCart() && cost.IsStale(order),
+		EstimatedAt:        &estimatedAt,
+	}
+}
+
+// OrderEditDTO represents an edit of the lines of a submitted order
+type OrderEditDTO struct {
+	ID           int64                `json:"id"`
+	OrderID      int64                `json:"order_id"`
+	Status       string               `json:"status"`
+	OpenedBy     string               `json:"opened_by"`
+	CurrencyCode string               `json:"currency_code"`
+	Revision     int                  `json:"revision"`  // Sent back to commit the edit as previewed
+	Previewed    bool                 `json:"previewed"` // The summary is of the current revision and can be committed
+	Lines        []*OrderEditLineDTO  `json:"lines"`
+	Summary      *OrderEditSummaryDTO `json:"summary,omitempty"`
+	ExpiresAt    time.Time            `json:"expires_at"`
+	ClosedBy     string               `json:"closed_by,omitempty"`
+	ClosedAt     *time.Time           `json:"closed_at,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+}
+
+// OrderEditLineDTO represents a line of an edited order
+type OrderEditLineDTO struct {
+	LineID            int     `json:"line_id"`
+	OrderItemID       int64   `json:"order_item_id,omitempty"` // Absent for added lines
+	ParentOrderItemID *int64  `json:"parent_order_item_id,omitempty"`
+	SKUID             int64   `json:"sku_id"`
+	Name              string  `json:"name,omitempty"` // Set on added lines once previewed
+	Change            string  `json:"change"`         // UNCHANGED, ADDED, QUANTITY_CHANGED or REMOVED
+	FreeGift          bool    `json:"free_gift,omitempty"`
+	OriginalQuantity  int     `json:"original_quantity"`
+	Quantity          int     `json:"quantity"`
+	UnitPrice         float64 `json:"unit_price"`         // After item discounts; what the order was sold at for its own lines
+	Discount          float64 `json:"discount,omitempty"` // Taken off an added line by the current offers
+	OriginalTotal     float64 `json:"original_total"`
+	Total             float64 `json:"total"`
+	Tax               float64 `json:"tax"`
+}
+
+// OrderEditSummaryDTO represents the delta an edit makes to an order, to confirm before committing it
+type OrderEditSummaryDTO struct {
+	Lines      []*OrderEditLineDTO    `json:"lines"` // Changed lines only
+	Previous   domain.OrderEditTotals `json:"previous"`
+	Totals     domain.OrderEditTotals `json:"totals"`
+	BalanceDue float64                `json:"balance_due"` // Owed by the customer; negative is refunded to them
+}
+
+// AddOrderEditLineRequest represents a SKU to add to an order by an edit
+type AddOrderEditLineRequest struct {
+	SKUID    int64 `json:"sku_id"`
+	Quantity int   `json:"quantity"`
+}
+
+// UpdateOrderEditLineRequest represents a new quantity of a line of an order edit
+type UpdateOrderEditLineRequest struct {
+	Quantity int `json:"quantity"` // 0 removes the line
+}
+
+// CommitOrderEditRequest confirms the delta of the last preview of an order edit
+type CommitOrderEditRequest struct {
+	Revision int  `json:"revision"` // Revision of the edit that was previewed
+	Confirm  bool `json:"confirm"`
+}
+
+// ToOrderEditDTO converts a domain.OrderEdit to an OrderEditDTO
+func ToOrderEditDTO(edit *domain.OrderEdit) *OrderEditDTO {
+	dto := &OrderEditDTO{
+		ID:           edit.ID,
+		OrderID:      edit.OrderID,
+		Status:       string(edit.Status),
+		OpenedBy:     edit.OpenedBy,
+		CurrencyCode: edit.CurrencyCode,
+		Revision:     edit.Revision,
+		Previewed:    edit.Summary != nil && edit.PreviewedRevision == edit.Revision,
+		Lines:        make([]*OrderEditLineDTO, len(edit.Lines)),
+		ExpiresAt:    edit.ExpiresAt,
+		ClosedBy:     edit.ClosedBy,
+		ClosedAt:     edit.ClosedAt,
+		CreatedAt:    edit.CreatedAt,
+	}
+	for i, line := range edit.Lines {
+		dto.Lines[i] = toOrderEditLineDTO(line, edit.CurrencyCode)
+	}
+	if edit.Summary != nil {
+		dto.Summary = &OrderEditSummaryDTO{
+			Lines:      make([]*OrderEditLineDTO, len(edit.Summary.Lines)),
+			Previous:   edit.Summary.Previous,
+			Totals:     edit.Summary.Totals,
+			BalanceDue: edit.Summary.BalanceDue,
+		}
+		for i, line := range edit.Summary.Lines {
+			dto.Summary.Lines[i] = toOrderEditLineDTO(line, edit.CurrencyCode)
+		}
+	}
+	return dto
+}
+
+func toOrderEditLineDTO(line *domain.OrderEditLine, currency string) *OrderEditLineDTO {
+	discount := 0.0
+	for _, d := range line.Discounts {
+		discount -= d.Amount
+	}
+	return &OrderEditLineDTO{
+		LineID:            line.LineID,
+		OrderItemID:       line.OrderItemID,
+		ParentOrderItemID: line.ParentOrderItemID,
+		SKUID:             line.SKUID,
+		Name:              line.Name,
+		Change:            string(line.Change()),
+		FreeGift:          line.FreeGift,
+		OriginalQuantity:  line.OriginalQuantity,
+		Quantity:          line.Quantity,
+		UnitPrice:         money.Round(line.UnitPrice, currency),
+		Discount:          money.Round(discount, currency),
+		OriginalTotal:     money.Round(line.OriginalTotal, currency),
+		Total:             money.Round(line.Total, currency),
+		Tax:               money.Round(line.Tax, currency),
+	}
+}
@@ -0,0 +1,422 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	catalogApp "github.com/qhato/ecommerce/internal/catalog/application"
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// GiftOptionService defines the application service for gift wraps and personal
+// messages on order items. A gift wrap is priced as a child order item of the
+// item it wraps, for the same quantity.
+type GiftOptionService interface {
+	// ListGiftWrapOptions lists gift wrap options, optionally only the active ones.
+	ListGiftWrapOptions(ctx context.Context, activeOnly bool) ([]*GiftWrapOptionDTO, error)
+
+	// CreateGiftWrapOption offers a SKU as a gift wrap.
+	CreateGiftWrapOption(ctx context.Context, req *SaveGiftWrapOptionRequest) (*GiftWrapOptionDTO, error)
+
+	// UpdateGiftWrapOption updates a gift wrap option.
+	UpdateGiftWrapOption(ctx context.Context, id int64, req *SaveGiftWrapOptionRequest) (*GiftWrapOptionDTO, error)
+
+	// GetGiftOptions returns the gift options of a customer's cart item.
+	GetGiftOptions(ctx context.Context, customerID, orderID, orderItemID int64) (*OrderItemGiftOptionsDTO, error)
+
+	// SetGiftOptions replaces the gift options of a customer's cart item.
+	SetGiftOptions(ctx context.Context, customerID, orderID, orderItemID int64, req *SetGiftOptionsRequest) (*OrderItemGiftOptionsDTO, error)
+
+	// GetPackingSlip returns the packing slip of an order with its gift options.
+	GetPackingSlip(ctx context.Context, orderID int64) (*PackingSlipDTO, error)
+}
+
+type giftOptionService struct {
+	orderRepo     domain.OrderRepository
+	orderItemRepo domain.OrderItemRepository
+	messageRepo   domain.PersonalMessageRepository
+	wrapRepo      domain.GiftWrapOptionRepository
+	orderService  OrderService
+	skuService    catalogApp.SkuService
+}
+
+// NewGiftOptionService creates a new instance of GiftOptionService.
+func NewGiftOptionService(
+	orderRepo domain.OrderRepository,
+	orderItemRepo domain.OrderItemRepository,
+	messageRepo domain.PersonalMessageRepository,
+	wrapRepo domain.GiftWrapOptionRepository,
+	orderService OrderService,
+	skuService catalogApp.SkuService,
+) GiftOptionService {
+	return &giftOptionService{
+		orderRepo:     orderRepo,
+		orderItemRepo: orderItemRepo,
+		messageRepo:   messageRepo,
+		wrapRepo:      wrapRepo,
+		orderService:  orderService,
+		skuService:    skuService,
+	}
+}
+
+func (s *giftOptionService) ListGiftWrapOptions(ctx context.Context, activeOnly bool) ([]*GiftWrapOptionDTO, error) {
+	options, err := s.wrapRepo.FindAll(ctx, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gift wrap options: %w", err)
+	}
+
+	dtos := make([]*GiftWrapOptionDTO, 0, len(options))
+	for _, option := range options {
+		dto, err := s.toGiftWrapOptionDTO(ctx, option)
+		if err != nil {
+			return nil, err
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (s *giftOptionService) CreateGiftWrapOption(ctx context.Context, req *SaveGiftWrapOptionRequest) (*GiftWrapOptionDTO, error) {
+	if err := s.checkWrapSKU(ctx, req.SKUID, 0); err != nil {
+		return nil, err
+	}
+
+	option, err := domain.NewGiftWrapOption(req.SKUID, req.Name, req.Description)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if req.Active != nil {
+		option.Active = *req.Active
+	}
+	if err := s.wrapRepo.Save(ctx, option); err != nil {
+		return nil, fmt.Errorf("failed to create gift wrap option: %w", err)
+	}
+	return s.toGiftWrapOptionDTO(ctx, option)
+}
+
+func (s *giftOptionService) UpdateGiftWrapOption(ctx context.Context, id int64, req *SaveGiftWrapOptionRequest) (*GiftWrapOptionDTO, error) {
+	option, err := s.findGiftWrapOption(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkWrapSKU(ctx, req.SKUID, id); err != nil {
+		return nil, err
+	}
+
+	updated, err := domain.NewGiftWrapOption(req.SKUID, req.Name, req.Description)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	option.SKUID = updated.SKUID
+	option.Name = updated.Name
+	option.Description = updated.Description
+	if req.Active != nil {
+		option.Active = *req.Active
+	}
+	option.UpdatedAt = time.Now()
+
+	if err := s.wrapRepo.Save(ctx, option); err != nil {
+		return nil, fmt.Errorf("failed to update gift wrap option: %w", err)
+	}
+	return s.toGiftWrapOptionDTO(ctx, option)
+}
+
+func (s *giftOptionService) GetGiftOptions(ctx context.Context, customerID, orderID, orderItemID int64) (*OrderItemGiftOptionsDTO, error) {
+	_, item, err := s.findCartItem(ctx, customerID, orderID, orderItemID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toGiftOptionsDTO(ctx, item)
+}
+
+func (s *giftOptionService) SetGiftOptions(ctx context.Context, customerID, orderID, orderItemID int64, req *SetGiftOptionsRequest) (*OrderItemGiftOptionsDTO, error) {
+	order, item, err := s.findCartItem(ctx, customerID, orderID, orderItemID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.OrderStatusPending {
+		return nil, errors.Conflict("gift options can only be changed before checkout")
+	}
+
+	if err := s.setGiftWrap(ctx, item, req.GiftWrapOptionID); err != nil {
+		return nil, err
+	}
+	if err := s.setPersonalMessage(ctx, item, req.PersonalMessage); err != nil {
+		return nil, err
+	}
+
+	item.UpdatedAt = time.Now()
+	if err := s.orderItemRepo.Save(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to save gift options of order item %d: %w", item.ID, err)
+	}
+	return s.toGiftOptionsDTO(ctx, item)
+}
+
+func (s *giftOptionService) GetPackingSlip(ctx context.Context, orderID int64) (*PackingSlipDTO, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	if order == nil {
+		return nil, errors.NotFound("order")
+	}
+	items, err := s.orderItemRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	// Gift wraps annotate the items they wrap instead of being packed on their own
+	wraps := make(map[int64]*domain.OrderItem)
+	for _, item := range items {
+		if item.OrderItemType == domain.OrderItemTypeGiftWrap {
+			wraps[item.ID] = item
+		}
+	}
+
+	slip := &PackingSlipDTO{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		ShipToName:  order.Name,
+		GeneratedAt: time.Now(),
+		Lines:       make([]*PackingSlipLineDTO, 0, len(items)),
+	}
+	for _, item := range items {
+		if item.OrderItemType == domain.OrderItemTypeGiftWrap {
+			continue
+		}
+		line := &PackingSlipLineDTO{
+			OrderItemID: item.ID,
+			SKUID:       item.SKUID,
+			Name:        item.Name,
+			Quantity:    item.Quantity,
+		}
+		if item.GiftWrapItemID != nil {
+			if wrap, ok := wraps[*item.GiftWrapItemID]; ok {
+				line.GiftWrap = s.giftWrapName(ctx, wrap)
+			}
+		}
+		message, err := s.findPersonalMessage(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		if message != nil {
+			line.PersonalMessage = ToPersonalMessageDTO(message)
+		}
+		slip.Lines = append(slip.Lines, line)
+	}
+	return slip, nil
+}
+
+// setGiftWrap replaces the wrap child item of an item, if the requested wrap differs
+func (s *giftOptionService) setGiftWrap(ctx context.Context, item *domain.OrderItem, optionID *int64) error {
+	var option *domain.GiftWrapOption
+	if optionID != nil {
+		var err error
+		if option, err = s.findGiftWrapOption(ctx, *optionID); err != nil {
+			return err
+		}
+		if !option.Active {
+			return errors.ValidationError(fmt.Sprintf("gift wrap option %d is not available", option.ID))
+		}
+	}
+
+	var current *domain.OrderItem
+	if item.GiftWrapItemID != nil {
+		var err error
+		if current, err = s.orderItemRepo.FindByID(ctx, *item.GiftWrapItemID); err != nil {
+			return fmt.Errorf("failed to find gift wrap item: %w", err)
+		}
+	}
+	if current != nil && option != nil && current.SKUID == option.SKUID {
+		return nil
+	}
+
+	if current != nil {
+		if err := s.orderService.RemoveOrderItem(ctx, current.ID); err != nil {
+			return fmt.Errorf("failed to remove gift wrap from order item %d: %w", item.ID, err)
+		}
+	}
+	item.GiftWrapItemID = nil
+	if option == nil {
+		return nil
+	}
+
+	wrap, err := s.orderService.AddItemToOrder(ctx, item.OrderID, &AddItemToOrderCommand{
+		SKUID:             option.SKUID,
+		Quantity:          item.Quantity,
+		TaxCategory:       item.TaxCategory,
+		ParentOrderItemID: &item.ID,
+		OrderItemType:     domain.OrderItemTypeGiftWrap,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add gift wrap to order item %d: %w", item.ID, err)
+	}
+	item.SetGiftWrapItemID(wrap.ID)
+	return nil
+}
+
+// setPersonalMessage creates, updates or removes the personal message of an item
+func (s *giftOptionService) setPersonalMessage(ctx context.Context, item *domain.OrderItem, req *PersonalMessageRequest) error {
+	existing, err := s.findPersonalMessage(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	if req == nil {
+		if existing != nil {
+			if err := s.messageRepo.Delete(ctx, existing.ID); err != nil {
+				return fmt.Errorf("failed to remove personal message: %w", err)
+			}
+		}
+		item.PersonalMessageID = nil
+		return nil
+	}
+
+	if existing != nil {
+		if err := existing.Update(req.Message, req.From, req.To, req.Occasion); err != nil {
+			return errors.ValidationError(err.Error())
+		}
+		if err := s.messageRepo.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update personal message: %w", err)
+		}
+		return nil
+	}
+
+	message, err := domain.NewPersonalMessage(req.Message, req.From, req.To, req.Occasion)
+	if err != nil {
+		return errors.ValidationError(err.Error())
+	}
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return fmt.Errorf("failed to create personal message: %w", err)
+	}
+	item.SetPersonalMessageID(message.ID)
+	return nil
+}
+
+// findCartItem loads an order item, checking that it belongs to the customer's order
+// and can carry gift options
+func (s *giftOptionService) findCartItem(ctx context.Context, customerID, orderID, orderItemID int64) (*domain.Order, *domain.OrderItem, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	if order == nil {
+		return nil, nil, errors.NotFound("order")
+	}
+	if order.CustomerID != customerID {
+		return nil, nil, errors.Forbidden("order belongs to another customer")
+	}
+
+	item, err := s.orderItemRepo.FindByID(ctx, orderItemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find order item: %w", err)
+	}
+	if item == nil || item.OrderID != orderID {
+		return nil, nil, errors.NotFound("order item")
+	}
+	if item.OrderItemType == domain.OrderItemTypeGiftWrap {
+		return nil, nil, errors.ValidationError("gift wraps cannot have gift options")
+	}
+	return order, item, nil
+}
+
+func (s *giftOptionService) findGiftWrapOption(ctx context.Context, id int64) (*domain.GiftWrapOption, error) {
+	option, err := s.wrapRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find gift wrap option: %w", err)
+	}
+	if option == nil {
+		return nil, errors.NotFound("gift wrap option")
+	}
+	return option, nil
+}
+
+func (s *giftOptionService) findPersonalMessage(ctx context.Context, item *domain.OrderItem) (*domain.PersonalMessage, error) {
+	if item.PersonalMessageID == nil {
+		return nil, nil
+	}
+	message, err := s.messageRepo.FindByID(ctx, *item.PersonalMessageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find personal message of order item %d: %w", item.ID, err)
+	}
+	return message, nil
+}
+
+// checkWrapSKU verifies that a SKU exists and is not already offered by another option
+func (s *giftOptionService) checkWrapSKU(ctx context.Context, skuID, optionID int64) error {
+	sku, err := s.skuService.GetSkuByID(ctx, skuID)
+	if err != nil {
+		return fmt.Errorf("failed to get SKU %d: %w", skuID, err)
+	}
+	if sku == nil {
+		return errors.ValidationError(fmt.Sprintf("SKU %d not found", skuID))
+	}
+	existing, err := s.wrapRepo.FindBySKUID(ctx, skuID)
+	if err != nil {
+		return fmt.Errorf("failed to find gift wrap option by SKU: %w", err)
+	}
+	if existing != nil && existing.ID != optionID {
+		return errors.Conflict(fmt.Sprintf("SKU %d is already offered as gift wrap option %d", skuID, existing.ID))
+	}
+	return nil
+}
+
+// giftWrapName names a wrap item by its option, falling back to the SKU name it was sold under
+func (s *giftOptionService) giftWrapName(ctx context.Context, wrap *domain.OrderItem) string {
+	option, err := s.wrapRepo.FindBySKUID(ctx, wrap.SKUID)
+	if err != nil || option == nil {
+		return wrap.Name
+	}
+	return option.Name
+}
+
+func (s *giftOptionService) toGiftOptionsDTO(ctx context.Context, item *domain.OrderItem) (*OrderItemGiftOptionsDTO, error) {
+	dto := &OrderItemGiftOptionsDTO{OrderItemID: item.ID}
+	if item.GiftWrapItemID != nil {
+		wrap, err := s.orderItemRepo.FindByID(ctx, *item.GiftWrapItemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find gift wrap item: %w", err)
+		}
+		if wrap != nil {
+			dto.GiftWrap = &GiftWrapItemDTO{
+				OrderItemID: wrap.ID,
+				SKUID:       wrap.SKUID,
+				Name:        s.giftWrapName(ctx, wrap),
+				Quantity:    wrap.Quantity,
+				TotalPrice:  wrap.TotalPrice,
+			}
+		}
+	}
+	message, err := s.findPersonalMessage(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if message != nil {
+		dto.PersonalMessage = ToPersonalMessageDTO(message)
+	}
+	return dto, nil
+}
+
+func (s *giftOptionService) toGiftWrapOptionDTO(ctx context.Context, option *domain.GiftWrapOption) (*GiftWrapOptionDTO, error) {
+	dto := &GiftWrapOptionDTO{
+		ID:          option.ID,
+		SKUID:       option.SKUID,
+		Name:        option.Name,
+		Description: option.Description,
+		Active:      option.Active,
+	}
+	sku, err := s.skuService.GetSkuByID(ctx, option.SKUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SKU %d of gift wrap option %d: %w", option.SKUID, option.ID, err)
+	}
+	if sku != nil {
+		// Priced the way order items are: the sale price when it undercuts retail
+		dto.Price = sku.RetailPrice
+		if sku.SalePrice > 0 && sku.SalePrice < sku.RetailPrice {
+			dto.Price = sku.SalePrice
+		}
+	}
+	return dto, nil
+}
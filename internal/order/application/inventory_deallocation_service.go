@@ -0,0 +1,293 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	inventoryApp "github.com/qhato/ecommerce/internal/inventory/application"
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// InventoryDeallocationService releases the stock of cancelled orders. Releases
+// are queued with the cancellation and retried with backoff until they succeed;
+// those that keep failing are dead-lettered and reported for reconciliation.
+type InventoryDeallocationService interface {
+	// Enqueue queues the release of the stock reserved by an order's items.
+	// Items already queued are skipped, so cancellations can be retried safely.
+	Enqueue(ctx context.Context, orderID int64, items []*domain.OrderItem) error
+
+	// ProcessOrder attempts the pending deallocations of an order now.
+	// Failures are recorded and retried later rather than returned.
+	ProcessOrder(ctx context.Context, orderID int64)
+
+	// ProcessDue attempts the deallocations whose retry is due and returns how many succeeded.
+	ProcessDue(ctx context.Context) (int, error)
+
+	// StartScheduledProcessing processes due deallocations periodically until ctx is cancelled.
+	StartScheduledProcessing(ctx context.Context, interval time.Duration)
+
+	// GetReconciliationReport lists the deallocations that failed and still hold stock.
+	GetReconciliationReport(ctx context.Context) (*DeallocationReconciliationDTO, error)
+
+	// Retry requeues a dead-lettered deallocation and attempts it immediately.
+	Retry(ctx context.Context, id int64) (*InventoryDeallocationDTO, error)
+
+	// Resolve marks a failed deallocation as reconciled by hand.
+	Resolve(ctx context.Context, id int64, resolvedBy string) (*InventoryDeallocationDTO, error)
+}
+
+// InventoryDeallocationConfig holds the retry policy of inventory deallocations
+type InventoryDeallocationConfig struct {
+	MaxAttempts int           // Attempts before a deallocation is dead-lettered
+	RetryDelay  time.Duration // Delay before the first retry; doubles with each attempt
+	Lease       time.Duration // How long a claimed deallocation is hidden from other workers
+	BatchSize   int           // Deallocations processed per scheduled run
+}
+
+type inventoryDeallocationService struct {
+	repo             domain.InventoryDeallocationRepository
+	orderRepo        domain.OrderRepository
+	inventoryService inventoryApp.InventoryService
+	cfg              InventoryDeallocationConfig
+	log              *logger.Logger
+}
+
+// NewInventoryDeallocationService creates a new instance of InventoryDeallocationService.
+func NewInventoryDeallocationService(
+	repo domain.InventoryDeallocationRepository,
+	orderRepo domain.OrderRepository,
+	inventoryService inventoryApp.InventoryService,
+	cfg InventoryDeallocationConfig,
+	log *logger.Logger,
+) InventoryDeallocationService {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = 30 * time.Second
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 2 * time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &inventoryDeallocationService{
+		repo:             repo,
+		orderRepo:        orderRepo,
+		inventoryService: inventoryService,
+		cfg:              cfg,
+		log:              log,
+	}
+}
+
+func (s *inventoryDeallocationService) Enqueue(ctx context.Context, orderID int64, items []*domain.OrderItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	deallocations := make([]*domain.InventoryDeallocation, len(items))
+	for i, item := range items {
+		// The first attempt is left to the cancelling request; the scheduler
+		// only picks it up if that request does not get to it
+		deallocations[i] = domain.NewInventoryDeallocation(orderID, item, s.cfg.RetryDelay)
+	}
+	if err := s.repo.Enqueue(ctx, deallocations); err != nil {
+		return fmt.Errorf("failed to enqueue inventory deallocations for order %d: %w", orderID, err)
+	}
+	return nil
+}
+
+func (s *inventoryDeallocationService) ProcessOrder(ctx context.Context, orderID int64) {
+	deallocations, err := s.repo.ClaimByOrderID(ctx, orderID, s.cfg.Lease)
+	if err != nil {
+		s.log.WithError(err).WithField("order_id", orderID).Warn("Failed to claim inventory deallocations, leaving them to the scheduler")
+		return
+	}
+	for _, d := range deallocations {
+		s.process(ctx, d)
+	}
+}
+
+func (s *inventoryDeallocationService) ProcessDue(ctx context.Context) (int, error) {
+	deallocations, err := s.repo.ClaimDue(ctx, time.Now(), s.cfg.Lease, s.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due inventory deallocations: %w", err)
+	}
+
+	completed := 0
+	for _, d := range deallocations {
+		if ctx.Err() != nil {
+			break
+		}
+		if s.process(ctx, d) {
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+func (s *inventoryDeallocationService) StartScheduledProcessing(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.ProcessDue(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled inventory deallocation failed")
+				}
+			}
+		}
+	}()
+}
+
+func (s *inventoryDeallocationService) GetReconciliationReport(ctx context.Context) (*DeallocationReconciliationDTO, error) {
+	deallocations, err := s.repo.FindUnresolved(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unresolved inventory deallocations: %w", err)
+	}
+
+	report := &DeallocationReconciliationDTO{
+		GeneratedAt:   time.Now(),
+		Deallocations: make([]*InventoryDeallocationDTO, len(deallocations)),
+	}
+	for i, d := range deallocations {
+		if d.Status == domain.DeallocationStatusDeadLetter {
+			report.DeadLettered++
+		} else {
+			report.Retrying++
+		}
+		report.UnitsHeld += d.Quantity
+		report.Deallocations[i] = ToInventoryDeallocationDTO(d)
+	}
+	return report, nil
+}
+
+func (s *inventoryDeallocationService) Retry(ctx context.Context, id int64) (*InventoryDeallocationDTO, error) {
+	d, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Requeue(); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.repo.Update(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to requeue inventory deallocation: %w", err)
+	}
+
+	s.ProcessOrder(ctx, d.OrderID)
+	return s.findDTO(ctx, id)
+}
+
+func (s *inventoryDeallocationService) Resolve(ctx context.Context, id int64, resolvedBy string) (*InventoryDeallocationDTO, error) {
+	d, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Resolve(resolvedBy); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.repo.Update(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to resolve inventory deallocation: %w", err)
+	}
+
+	s.log.WithFields(logger.Fields{
+		"deallocation_id": d.ID,
+		"order_id":        d.OrderID,
+		"sku_id":          d.SKUID,
+		"resolved_by":     resolvedBy,
+	}).Info("Inventory deallocation resolved manually")
+	return ToInventoryDeallocationDTO(d), nil
+}
+
+// process releases the stock of a claimed deallocation and records the outcome.
+// It reports whether the stock was released.
+func (s *inventoryDeallocationService) process(ctx context.Context, d *domain.InventoryDeallocation) bool {
+	fields := logger.Fields{
+		"deallocation_id": d.ID,
+		"order_id":        d.OrderID,
+		"sku_id":          d.SKUID,
+		"quantity":        d.Quantity,
+	}
+
+	err := s.release(ctx, d)
+	if err == nil {
+		d.Complete()
+	} else {
+		d.Fail(err, s.cfg.MaxAttempts, s.cfg.RetryDelay)
+	}
+	if updateErr := s.repo.Update(ctx, d); updateErr != nil {
+		// The lease expires and the deallocation is claimed again; if the stock
+		// was released it is released twice, so make this loud
+		s.log.WithError(updateErr).WithFields(fields).Error("Failed to record inventory deallocation outcome")
+		return false
+	}
+
+	switch {
+	case err == nil:
+		return true
+	case d.Status == domain.DeallocationStatusDeadLetter:
+		s.log.WithError(err).WithFields(fields).WithField("attempts", d.Attempts).Error("Inventory deallocation dead-lettered, stock needs reconciliation")
+	default:
+		s.log.WithError(err).WithFields(fields).WithField("attempts", d.Attempts).WithField("next_attempt_at", d.NextAttemptAt).Warn("Inventory deallocation failed, will retry")
+	}
+	return false
+}
+
+// release returns the order item's reserved units to stock
+func (s *inventoryDeallocationService) release(ctx context.Context, d *domain.InventoryDeallocation) error {
+	order, err := s.orderRepo.FindByID(ctx, d.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to find order: %w", err)
+	}
+	if order == nil {
+		return fmt.Errorf("order %d not found", d.OrderID)
+	}
+	// Only cancelled orders give their stock back; the cancellation may not be saved yet
+	if order.Status != domain.OrderStatusCancelled {
+		return fmt.Errorf("order %d is %s, not cancelled", d.OrderID, order.Status)
+	}
+
+	level, err := s.inventoryService.GetInventoryLevelBySKUID(ctx, strconv.FormatInt(d.SKUID, 10))
+	if err != nil {
+		return fmt.Errorf("failed to get inventory level: %w", err)
+	}
+	_, err = s.inventoryService.UpdateInventoryQuantities(
+		ctx,
+		level.ID,
+		level.QuantityOnHand,
+		level.QuantityReserved-d.Quantity,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update inventory quantities: %w", err)
+	}
+	return nil
+}
+
+func (s *inventoryDeallocationService) find(ctx context.Context, id int64) (*domain.InventoryDeallocation, error) {
+	d, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inventory deallocation: %w", err)
+	}
+	if d == nil {
+		return nil, errors.NotFound("inventory deallocation")
+	}
+	return d, nil
+}
+
+func (s *inventoryDeallocationService) findDTO(ctx context.Context, id int64) (*InventoryDeallocationDTO, error) {
+	d, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToInventoryDeallocationDTO(d), nil
+}
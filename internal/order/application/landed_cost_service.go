@@ -0,0 +1,226 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	taxApp "github.com/qhato/ecommerce/internal/tax/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// LandedCostService estimates the duties, import tax and fees a cross-border order
+// owes its destination country, so international checkouts can show them. Under
+// DDP the estimate is charged with the order; under DAP it is only shown, as the
+// customer pays on delivery.
+type LandedCostService interface {
+	// EstimateLandedCost estimates the landed cost of a customer's cart for its
+	// shipping destination and records it with the chosen incoterm.
+	EstimateLandedCost(ctx context.Context, customerID, orderID int64, req *EstimateLandedCostRequest) (*OrderLandedCostDTO, error)
+
+	// GetLandedCost returns the landed cost recorded for a customer's order.
+	GetLandedCost(ctx context.Context, customerID, orderID int64) (*OrderLandedCostDTO, error)
+
+	// GetOrderLandedCost returns the landed cost recorded for an order.
+	GetOrderLandedCost(ctx context.Context, orderID int64) (*OrderLandedCostDTO, error)
+}
+
+type landedCostService struct {
+	orderRepo       domain.OrderRepository
+	orderItemRepo   domain.OrderItemRepository
+	landedCostRepo  domain.OrderLandedCostRepository
+	contextRepo     domain.CartPolicyContextRepository
+	estimator       taxApp.LandedCostService
+	shipFromCountry string
+}
+
+// NewLandedCostService creates a new instance of LandedCostService. Orders shipped
+// within shipFromCountry owe no duties.
+func NewLandedCostService(
+	orderRepo domain.OrderRepository,
+	orderItemRepo domain.OrderItemRepository,
+	landedCostRepo domain.OrderLandedCostRepository,
+	contextRepo domain.CartPolicyContextRepository,
+	estimator taxApp.LandedCostService,
+	shipFromCountry string,
+) LandedCostService {
+	return &landedCostService{
+		orderRepo:       orderRepo,
+		orderItemRepo:   orderItemRepo,
+		landedCostRepo:  landedCostRepo,
+		contextRepo:     contextRepo,
+		estimator:       estimator,
+		shipFromCountry: strings.ToUpper(shipFromCountry),
+	}
+}
+
+func (s *landedCostService) EstimateLandedCost(ctx context.Context, customerID, orderID int64, req *EstimateLandedCostRequest) (*OrderLandedCostDTO, error) {
+	incoterm := domain.Incoterm(strings.ToUpper(req.Incoterm))
+	if !incoterm.IsValid() {
+		return nil, errors.ValidationError("incoterm must be DDP or DAP")
+	}
+	order, err := s.findCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsCart() {
+		return nil, errors.Conflict("duties can only be estimated before the order is submitted")
+	}
+
+	country, err := s.contextRepo.FindDestinationCountry(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if country == "" {
+		return nil, errors.ValidationError("choose a shipping address before estimating duties")
+	}
+	country = strings.ToUpper(country)
+	if country == s.shipFromCountry {
+		if err := s.clearLandedCost(ctx, order); err != nil {
+			return nil, err
+		}
+		return s.domesticDTO(order, country), nil
+	}
+
+	items, err := s.orderItemRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order items for order %d: %w", order.ID, err)
+	}
+	estimateReq := &taxApp.LandedCostRequest{
+		OrderID:            order.ID,
+		CurrencyCode:       order.CurrencyCode,
+		ShipFromCountry:    s.shipFromCountry,
+		DestinationCountry: country,
+		ShippingAmount:     order.TotalShipping,
+		Incoterm:           string(incoterm),
+		Lines:              make([]taxApp.LandedCostLine, 0, len(items)),
+	}
+	for _, item := range items {
+		estimateReq.Lines = append(estimateReq.Lines, taxApp.LandedCostLine{
+			ItemID:    item.ID,
+			ProductID: item.ProductID,
+			SKUID:     item.SKUID,
+			Quantity:  item.Quantity,
+			Amount:    item.TotalPrice,
+		})
+	}
+	// Lines are classified from their products in place, so the request keeps their HS codes and origins
+	result, err := s.estimator.EstimateLandedCost(ctx, estimateReq)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := &domain.OrderLandedCost{
+		OrderID:            order.ID,
+		Incoterm:           incoterm,
+		ShipFromCountry:    s.shipFromCountry,
+		DestinationCountry: country,
+		OrderSubtotal:      order.OrderSubtotal,
+		ShippingAmount:     order.TotalShipping,
+		Duty:               result.Duty,
+		ImportTax:          result.ImportTax,
+		Fees:               result.Fees,
+		Total:              result.Total,
+		DeMinimisApplied:   result.DeMinimisApplied,
+		CurrencyCode:       order.CurrencyCode,
+		Provider:           result.Provider,
+		Lines:              make([]domain.OrderLandedCostLine, 0, len(result.Lines)),
+		EstimatedAt:        time.Now(),
+	}
+	origins := make(map[int64]string, len(estimateReq.Lines))
+	values := make(map[int64]float64, len(estimateReq.Lines))
+	for _, line := range estimateReq.Lines {
+		origins[line.ItemID] = line.OriginCountry
+		values[line.ItemID] = line.Amount
+	}
+	for _, line := range result.Lines {
+		cost.Lines = append(cost.Lines, domain.OrderLandedCostLine{
+			OrderItemID:   line.ItemID,
+			HSCode:        line.HSCode,
+			OriginCountry: origins[line.ItemID],
+			CustomsValue:  values[line.ItemID],
+			DutyRate:      line.DutyRate,
+			Duty:          line.Duty,
+			ImportTax:     line.ImportTax,
+		})
+	}
+	if err := s.landedCostRepo.Save(ctx, cost); err != nil {
+		return nil, fmt.Errorf("failed to save landed cost of order %d: %w", order.ID, err)
+	}
+
+	order.SetTotalDuties(cost.ChargedAmount())
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update duties of order %d: %w", order.ID, err)
+	}
+	return ToOrderLandedCostDTO(cost, order), nil
+}
+
+func (s *landedCostService) GetLandedCost(ctx context.Context, customerID, orderID int64) (*OrderLandedCostDTO, error) {
+	order, err := s.findCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toDTO(ctx, order)
+}
+
+func (s *landedCostService) GetOrderLandedCost(ctx context.Context, orderID int64) (*OrderLandedCostDTO, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	if order == nil {
+		return nil, errors.NotFound("order")
+	}
+	return s.toDTO(ctx, order)
+}
+
+func (s *landedCostService) toDTO(ctx context.Context, order *domain.Order) (*OrderLandedCostDTO, error) {
+	cost, err := s.landedCostRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find landed cost of order %d: %w", order.ID, err)
+	}
+	if cost == nil {
+		return nil, errors.NotFound("landed cost estimate")
+	}
+	return ToOrderLandedCostDTO(cost, order), nil
+}
+
+// clearLandedCost drops the estimate and duties of an order that no longer ships abroad
+func (s *landedCostService) clearLandedCost(ctx context.Context, order *domain.Order) error {
+	if err := s.landedCostRepo.DeleteByOrderID(ctx, order.ID); err != nil {
+		return fmt.Errorf("failed to clear landed cost of order %d: %w", order.ID, err)
+	}
+	if order.TotalDuties == 0 {
+		return nil
+	}
+	order.SetTotalDuties(0)
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return fmt.Errorf("failed to update duties of order %d: %w", order.ID, err)
+	}
+	return nil
+}
+
+func (s *landedCostService) domesticDTO(order *domain.Order, country string) *OrderLandedCostDTO {
+	return &OrderLandedCostDTO{
+		OrderID:            order.ID,
+		ShipFromCountry:    s.shipFromCountry,
+		DestinationCountry: country,
+		CurrencyCode:       order.CurrencyCode,
+	}
+}
+
+func (s *landedCostService) findCustomerOrder(ctx context.Context, customerID, orderID int64) (*domain.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	if order == nil {
+		return nil, errors.NotFound("order")
+	}
+	if order.CustomerID != customerID {
+		return nil, errors.Forbidden("order belongs to another customer")
+	}
+	return order, nil
+}
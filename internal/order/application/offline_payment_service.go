@@ -0,0 +1,407 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const awaitingOfflinePaymentsLimit = 500
+
+// OfflinePaymentService defines the application service for cash on delivery,
+// bank transfer and pay-in-store orders. An order placed with an offline
+// method waits in AWAITING_PAYMENT until an admin records receipt of the
+// money, which submits it.
+type OfflinePaymentService interface {
+	// ListMethods lists the offline payment methods, enabled or not.
+	ListMethods(ctx context.Context) ([]*OfflinePaymentMethodDTO, error)
+
+	// CreateMethod adds an offline payment method.
+	CreateMethod(ctx context.Context, req *SaveOfflinePaymentMethodRequest) (*OfflinePaymentMethodDTO, error)
+
+	// UpdateMethod updates an offline payment method; its code is kept.
+	UpdateMethod(ctx context.Context, id int64, req *SaveOfflinePaymentMethodRequest) (*OfflinePaymentMethodDTO, error)
+
+	// DeleteMethod removes an offline payment method. Orders placed with it keep their payment.
+	DeleteMethod(ctx context.Context, id int64) error
+
+	// ListAvailableMethods lists the methods a customer's order can be placed
+	// with, given its shipping country and total.
+	ListAvailableMethods(ctx context.Context, customerID, orderID int64) ([]*AvailableOfflinePaymentMethodDTO, error)
+
+	// PlaceOrder places a customer's order with an offline payment method,
+	// holding it in AWAITING_PAYMENT.
+	PlaceOrder(ctx context.Context, customerID, orderID int64, req *PlaceOfflinePaymentOrderRequest) (*OfflinePaymentDTO, error)
+
+	// GetCustomerPayment returns the offline payment of a customer's order with its instructions.
+	GetCustomerPayment(ctx context.Context, customerID, orderID int64) (*OfflinePaymentDTO, error)
+
+	// GetPayment returns the offline payment of an order.
+	GetPayment(ctx context.Context, orderID int64) (*OfflinePaymentDTO, error)
+
+	// ListAwaitingPayments lists the orders awaiting an offline payment, oldest first.
+	ListAwaitingPayments(ctx context.Context) ([]*OfflinePaymentDTO, error)
+
+	// RecordPayment records receipt of an order's offline payment and submits the order.
+	RecordPayment(ctx context.Context, orderID int64, req *RecordOfflinePaymentRequest, receivedBy string) (*OfflinePaymentDTO, error)
+}
+
+type offlinePaymentService struct {
+	orderService    OrderService
+	methodRepo      domain.OfflinePaymentMethodRepository
+	paymentRepo     domain.OfflinePaymentRepository
+	assistedRepo    domain.AssistedOrderRepository
+	destinationRepo domain.ShippingDestinationRepository
+	logger          *logger.Logger
+}
+
+// NewOfflinePaymentService creates a new instance of OfflinePaymentService.
+func NewOfflinePaymentService(
+	orderService OrderService,
+	methodRepo domain.OfflinePaymentMethodRepository,
+	paymentRepo domain.OfflinePaymentRepository,
+	assistedRepo domain.AssistedOrderRepository,
+	destinationRepo domain.ShippingDestinationRepository,
+	log *logger.Logger,
+) OfflinePaymentService {
+	return &offlinePaymentService{
+		orderService:    orderService,
+		methodRepo:      methodRepo,
+		paymentRepo:     paymentRepo,
+		assistedRepo:    assistedRepo,
+		destinationRepo: destinationRepo,
+		logger:          log,
+	}
+}
+
+func (s *offlinePaymentService) ListMethods(ctx context.Context) ([]*OfflinePaymentMethodDTO, error) {
+	methods, err := s.methodRepo.FindAll(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offline payment methods: %w", err)
+	}
+
+	dtos := make([]*OfflinePaymentMethodDTO, len(methods))
+	for i, method := range methods {
+		dtos[i] = ToOfflinePaymentMethodDTO(method)
+	}
+	return dtos, nil
+}
+
+func (s *offlinePaymentService) CreateMethod(ctx context.Context, req *SaveOfflinePaymentMethodRequest) (*OfflinePaymentMethodDTO, error) {
+	method, err := domain.NewOfflinePaymentMethod(req.Code, domain.OfflinePaymentType(req.Type), req.Name)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	existing, err := s.methodRepo.FindByCode(ctx, method.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check offline payment method code: %w", err)
+	}
+	if existing != nil {
+		return nil, errors.Conflict(fmt.Sprintf("offline payment method %q already exists", method.Code))
+	}
+	if err := s.applyMethodRequest(method, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.methodRepo.Save(ctx, method); err != nil {
+		return nil, fmt.Errorf("failed to create offline payment method: %w", err)
+	}
+	return ToOfflinePaymentMethodDTO(method), nil
+}
+
+func (s *offlinePaymentService) UpdateMethod(ctx context.Context, id int64, req *SaveOfflinePaymentMethodRequest) (*OfflinePaymentMethodDTO, error) {
+	method, err := s.methodRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find offline payment method %d: %w", id, err)
+	}
+	if method == nil {
+		return nil, errors.NotFound(fmt.Sprintf("offline payment method %d", id))
+	}
+	if err := s.applyMethodRequest(method, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.methodRepo.Save(ctx, method); err != nil {
+		return nil, fmt.Errorf("failed to update offline payment method %d: %w", id, err)
+	}
+	return ToOfflinePaymentMethodDTO(method), nil
+}
+
+func (s *offlinePaymentService) DeleteMethod(ctx context.Context, id int64) error {
+	return s.methodRepo.Delete(ctx, id)
+}
+
+func (s *offlinePaymentService) ListAvailableMethods(ctx context.Context, customerID, orderID int64) ([]*AvailableOfflinePaymentMethodDTO, error) {
+	order, err := s.findCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	country, err := s.shippingCountry(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := s.methodRepo.FindAll(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offline payment methods: %w", err)
+	}
+
+	dtos := make([]*AvailableOfflinePaymentMethodDTO, 0, len(methods))
+	for _, method := range methods {
+		if method.Unavailable(country, order.OrderTotal) != "" {
+			continue
+		}
+		dtos = append(dtos, &AvailableOfflinePaymentMethodDTO{
+			Code:         method.Code,
+			Type:         string(method.Type),
+			Name:         method.Name,
+			Instructions: method.Instructions,
+		})
+	}
+	return dtos, nil
+}
+
+func (s *offlinePaymentService) PlaceOrder(ctx context.Context, customerID, orderID int64, req *PlaceOfflinePaymentOrderRequest) (*OfflinePaymentDTO, error) {
+	order, err := s.findCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.IsAwaitingPayment() {
+		return nil, errors.Conflict(fmt.Sprintf("order %d is %s and can no longer be placed", orderID, order.Status))
+	}
+	if req.MethodCode == "" {
+		return nil, errors.ValidationError("method_code is required")
+	}
+
+	method, err := s.methodRepo.FindByCode(ctx, req.MethodCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find offline payment method: %w", err)
+	}
+	if method == nil {
+		return nil, errors.NotFound(fmt.Sprintf("offline payment method %q", req.MethodCode))
+	}
+	country, err := s.shippingCountry(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if reason := method.Unavailable(country, order.OrderTotal); reason != "" {
+		return nil, errors.ValidationError(reason)
+	}
+
+	// The order number is the reference customers quote with their payment
+	payment, err := domain.NewOfflinePayment(order.ID, method, order.OrderTotal, order.CurrencyCode, order.OrderNumber)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.paymentRepo.Save(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to save offline payment of order %d: %w", order.ID, err)
+	}
+	if order.Status != domain.OrderStatusAwaitingPayment {
+		if err := s.orderService.UpdateOrderStatus(ctx, order.ID, domain.OrderStatusAwaitingPayment); err != nil {
+			return nil, fmt.Errorf("failed to hold order %d for offline payment: %w", order.ID, err)
+		}
+	}
+
+	s.logger.WithFields(logger.Fields{
+		"order_id":    order.ID,
+		"method_code": method.Code,
+		"amount":      payment.Amount,
+	}).Info("Order placed with offline payment")
+
+	dto := s.toOfflinePaymentDTO(payment, order)
+	dto.OrderStatus = string(domain.OrderStatusAwaitingPayment)
+	dto.Instructions = method.Instructions
+	return dto, nil
+}
+
+func (s *offlinePaymentService) GetCustomerPayment(ctx context.Context, customerID, orderID int64) (*OfflinePaymentDTO, error) {
+	order, err := s.findCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := s.findPayment(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := s.toOfflinePaymentDTO(payment, order)
+	if payment.Status == domain.OfflinePaymentStatusAwaiting && payment.MethodID != 0 {
+		method, err := s.methodRepo.FindByID(ctx, payment.MethodID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find offline payment method %d: %w", payment.MethodID, err)
+		}
+		if method != nil {
+			dto.Instructions = method.Instructions
+		}
+	}
+	return dto, nil
+}
+
+func (s *offlinePaymentService) GetPayment(ctx context.Context, orderID int64) (*OfflinePaymentDTO, error) {
+	payment, err := s.findPayment(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orderService.HandleGetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	return s.toOfflinePaymentDTO(payment, order), nil
+}
+
+func (s *offlinePaymentService) ListAwaitingPayments(ctx context.Context) ([]*OfflinePaymentDTO, error) {
+	payments, err := s.paymentRepo.FindAwaiting(ctx, awaitingOfflinePaymentsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list awaiting offline payments: %w", err)
+	}
+
+	dtos := make([]*OfflinePaymentDTO, len(payments))
+	for i, payment := range payments {
+		dtos[i] = ToOfflinePaymentDTO(payment)
+		dtos[i].OrderNumber = payment.Reference
+		dtos[i].OrderStatus = string(domain.OrderStatusAwaitingPayment)
+	}
+	return dtos, nil
+}
+
+func (s *offlinePaymentService) RecordPayment(ctx context.Context, orderID int64, req *RecordOfflinePaymentRequest, receivedBy string) (*OfflinePaymentDTO, error) {
+	payment, err := s.findPayment(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orderService.HandleGetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	if order.Status != domain.OrderStatusAwaitingPayment {
+		return nil, errors.Conflict(fmt.Sprintf("order %d is %s and does not await an offline payment", orderID, order.Status))
+	}
+	if toCents(order.OrderTotal) != toCents(payment.Amount) {
+		return nil, errors.Conflict("the order has changed since it was placed; cancel it or place it again")
+	}
+
+	receivedAt := time.Now()
+	if req.ReceivedAt != nil {
+		if req.ReceivedAt.After(receivedAt) {
+			return nil, errors.ValidationError("received_at cannot be in the future")
+		}
+		receivedAt = *req.ReceivedAt
+	}
+	if err := payment.RecordReceipt(req.Amount, req.ReceiptReference, receivedBy, req.Note, receivedAt); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if err := s.submit(ctx, orderID); err != nil {
+		return nil, err
+	}
+	if err := s.paymentRepo.Save(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to save receipt of offline payment of order %d: %w", orderID, err)
+	}
+
+	s.logger.WithFields(logger.Fields{
+		"order_id":    orderID,
+		"amount":      req.Amount,
+		"received_by": receivedBy,
+	}).Info("Offline payment received")
+
+	order, err = s.orderService.HandleGetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	return s.toOfflinePaymentDTO(payment, order), nil
+}
+
+// submit records the payment of assisted orders and submits the order, keeping
+// the cart policy bypass the agent applied
+func (s *offlinePaymentService) submit(ctx context.Context, orderID int64) error {
+	assisted, err := s.assistedRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to find assisted order %d: %w", orderID, err)
+	}
+
+	submit := s.orderService.SubmitOrder
+	if assisted != nil {
+		assisted.MarkPaidOffline()
+		if err := s.assistedRepo.Save(ctx, assisted); err != nil {
+			return fmt.Errorf("failed to save assisted order payment: %w", err)
+		}
+		if assisted.CartPolicyBypassed {
+			submit = s.orderService.SubmitOrderSkippingCartPolicy
+		}
+	}
+	if err := submit(ctx, orderID); err != nil {
+		return fmt.Errorf("failed to submit order %d paid offline: %w", orderID, err)
+	}
+	return nil
+}
+
+func (s *offlinePaymentService) applyMethodRequest(method *domain.OfflinePaymentMethod, req *SaveOfflinePaymentMethodRequest) error {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	err := method.Update(domain.OfflinePaymentType(req.Type), req.Name, req.Instructions, enabled,
+		req.Countries, req.MinOrderTotal, req.MaxOrderTotal, req.SortOrder)
+	if err != nil {
+		return errors.ValidationError(err.Error())
+	}
+	return nil
+}
+
+func (s *offlinePaymentService) findCustomerOrder(ctx context.Context, customerID, orderID int64) (*OrderDTO, error) {
+	order, err := s.orderService.HandleGetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	if order.CustomerID != customerID {
+		return nil, errors.Forbidden("order belongs to another customer")
+	}
+	return order, nil
+}
+
+func (s *offlinePaymentService) findPayment(ctx context.Context, orderID int64) (*domain.OfflinePayment, error) {
+	payment, err := s.paymentRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find offline payment of order %d: %w", orderID, err)
+	}
+	if payment == nil {
+		return nil, errors.NotFound(fmt.Sprintf("offline payment of order %d", orderID))
+	}
+	return payment, nil
+}
+
+// shippingCountry returns the country of the order's primary shipping address,
+// or an empty string before one is set
+func (s *offlinePaymentService) shippingCountry(ctx context.Context, order *OrderDTO) (string, error) {
+	var addressID *int64
+	for _, group := range order.FulfillmentGroups {
+		if group.AddressID == nil {
+			continue
+		}
+		if addressID == nil || group.IsPrimary {
+			addressID = group.AddressID
+		}
+	}
+	if addressID == nil {
+		return "", nil
+	}
+
+	destination, err := s.destinationRepo.FindByAddressID(ctx, *addressID)
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve shipping address %d: %w", *addressID, err)
+	}
+	return destination.Country, nil
+}
+
+func (s *offlinePaymentService) toOfflinePaymentDTO(payment *domain.OfflinePayment, order *OrderDTO) *OfflinePaymentDTO {
+	dto := ToOfflinePaymentDTO(payment)
+	dto.OrderNumber = order.OrderNumber
+	dto.OrderStatus = string(order.Status)
+	return dto
+}
@@ -0,0 +1,187 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// OrderArchiveService moves delivered, fulfilled, cancelled and refunded orders
+// out of the live order tables once they are older than the retention window
+// admins set for their status. Archived orders are still read transparently
+// by ID and order number and in the customer's order history, and can be
+// restored to the live tables when they need to change.
+type OrderArchiveService interface {
+	// ListPolicies lists the retention window of every archivable status, 0
+	// for the statuses that are kept live.
+	ListPolicies(ctx context.Context) ([]*OrderRetentionPolicyDTO, error)
+
+	// SetPolicy sets the retention window of a status; 0 stops archiving it.
+	SetPolicy(ctx context.Context, status string, req *SetOrderRetentionRequest, updatedBy string) (*OrderRetentionPolicyDTO, error)
+
+	// ArchiveExpired moves the orders past the retention window of their status
+	// to the archive and returns how many were moved.
+	ArchiveExpired(ctx context.Context) (int64, error)
+
+	// RestoreOrder moves an archived order back to the live tables.
+	RestoreOrder(ctx context.Context, orderID int64) (*OrderDTO, error)
+
+	// StartScheduledArchival archives expired orders periodically until ctx is cancelled.
+	StartScheduledArchival(ctx context.Context, interval time.Duration)
+}
+
+type orderArchiveService struct {
+	orderRepo   domain.OrderRepository
+	archiveRepo domain.OrderArchiveRepository
+	batchSize   int
+	log         *logger.Logger
+	archiveMu   sync.Mutex
+}
+
+// NewOrderArchiveService creates a new instance of OrderArchiveService
+func NewOrderArchiveService(
+	orderRepo domain.OrderRepository,
+	archiveRepo domain.OrderArchiveRepository,
+	batchSize int,
+	log *logger.Logger,
+) OrderArchiveService {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &orderArchiveService{
+		orderRepo:   orderRepo,
+		archiveRepo: archiveRepo,
+		batchSize:   batchSize,
+		log:         log,
+	}
+}
+
+// archivableStatuses lists the final statuses in the order they are shown to admins
+var archivableStatuses = []domain.OrderStatus{
+	domain.OrderStatusDelivered,
+	domain.OrderStatusFulfilled,
+	domain.OrderStatusCancelled,
+	domain.OrderStatusRefunded,
+}
+
+func (s *orderArchiveService) ListPolicies(ctx context.Context) ([]*OrderRetentionPolicyDTO, error) {
+	policies, err := s.archiveRepo.FindPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byStatus := make(map[domain.OrderStatus]*domain.OrderRetentionPolicy, len(policies))
+	for _, policy := range policies {
+		byStatus[policy.Status] = policy
+	}
+
+	dtos := make([]*OrderRetentionPolicyDTO, 0, len(archivableStatuses))
+	for _, status := range archivableStatuses {
+		if policy, ok := byStatus[status]; ok {
+			dtos = append(dtos, ToOrderRetentionPolicyDTO(policy))
+			continue
+		}
+		dtos = append(dtos, &OrderRetentionPolicyDTO{Status: string(status)})
+	}
+	return dtos, nil
+}
+
+func (s *orderArchiveService) SetPolicy(ctx context.Context, status string, req *SetOrderRetentionRequest, updatedBy string) (*OrderRetentionPolicyDTO, error) {
+	policy, err := domain.NewOrderRetentionPolicy(domain.OrderStatus(strings.ToUpper(strings.TrimSpace(status))), req.RetentionMonths, updatedBy)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.archiveRepo.SavePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"status":           string(policy.Status),
+		"retention_months": policy.RetentionMonths,
+		"updated_by":       updatedBy,
+	}).Info("Order retention policy updated")
+	return ToOrderRetentionPolicyDTO(policy), nil
+}
+
+func (s *orderArchiveService) ArchiveExpired(ctx context.Context) (int64, error) {
+	if !s.archiveMu.TryLock() {
+		return 0, errors.Conflict("order archival already in progress")
+	}
+	defer s.archiveMu.Unlock()
+
+	policies, err := s.archiveRepo.FindPolicies(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var archived int64
+	for _, policy := range policies {
+		if !policy.IsEnabled() {
+			continue
+		}
+		cutoff := policy.Cutoff(now)
+		var moved int64
+		for {
+			batch, err := s.archiveRepo.ArchiveBefore(ctx, policy.Status, cutoff, s.batchSize)
+			if err != nil {
+				return archived + moved, fmt.Errorf("failed to archive %s orders: %w", policy.Status, err)
+			}
+			moved += batch
+			if batch < int64(s.batchSize) || ctx.Err() != nil {
+				break
+			}
+		}
+		archived += moved
+
+		if moved > 0 {
+			s.log.WithFields(logger.Fields{
+				"status":   string(policy.Status),
+				"archived": moved,
+				"cutoff":   cutoff,
+			}).Info("Archived orders past retention")
+		}
+	}
+	return archived, nil
+}
+
+func (s *orderArchiveService) RestoreOrder(ctx context.Context, orderID int64) (*OrderDTO, error) {
+	if err := s.archiveRepo.Restore(ctx, orderID); err != nil {
+		return nil, err
+	}
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order %d: %w", orderID, err)
+	}
+	if order == nil {
+		return nil, errors.NotFound(fmt.Sprintf("order %d", orderID))
+	}
+
+	s.log.WithField("order_id", orderID).Info("Order restored from archive")
+	return ToOrderDTO(order), nil
+}
+
+func (s *orderArchiveService) StartScheduledArchival(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.ArchiveExpired(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled order archival failed")
+				}
+			}
+		}
+	}()
+}
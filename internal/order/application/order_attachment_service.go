@@ -0,0 +1,328 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/objectstore"
+)
+
+// OrderAttachmentService manages files attached to orders. Staff see every attachment
+// of an order; customers only see, download and upload those visible to them, and
+// only on their own orders.
+type OrderAttachmentService interface {
+	// Upload attaches a file to an order on behalf of an admin.
+	Upload(ctx context.Context, orderID int64, req *UploadOrderAttachmentRequest, uploadedBy string) (*OrderAttachmentDTO, error)
+
+	// List lists the attachments of an order, internal and customer-visible.
+	List(ctx context.Context, orderID int64) ([]*OrderAttachmentDTO, error)
+
+	// Download reads the content of an attachment.
+	Download(ctx context.Context, orderID, attachmentID int64) (*OrderAttachmentContentDTO, error)
+
+	// SetVisibility changes who can see an attachment.
+	SetVisibility(ctx context.Context, orderID, attachmentID int64, req *UpdateOrderAttachmentRequest) (*OrderAttachmentDTO, error)
+
+	// Delete removes an attachment and its content.
+	Delete(ctx context.Context, orderID, attachmentID int64) error
+
+	// UploadForCustomer attaches a customer-visible file to an order of the customer.
+	UploadForCustomer(ctx context.Context, customerID, orderID int64, req *UploadOrderAttachmentRequest) (*OrderAttachmentDTO, error)
+
+	// ListForCustomer lists the customer-visible attachments of an order of the customer.
+	ListForCustomer(ctx context.Context, customerID, orderID int64) ([]*OrderAttachmentDTO, error)
+
+	// DownloadForCustomer reads a customer-visible attachment of an order of the customer.
+	DownloadForCustomer(ctx context.Context, customerID, orderID, attachmentID int64) (*OrderAttachmentContentDTO, error)
+}
+
+// OrderAttachmentConfig bounds the files attached to orders
+type OrderAttachmentConfig struct {
+	MaxSizeBytes    int64
+	ContentTypes    []string // Accepted content types; "image/*" accepts any image
+	CustomerUploads int      // Files a customer may attach to one order; 0 only lets staff attach files
+}
+
+type orderAttachmentService struct {
+	orderRepo      domain.OrderRepository
+	attachmentRepo domain.OrderAttachmentRepository
+	store          objectstore.Store
+	cfg            OrderAttachmentConfig
+	log            *logger.Logger
+}
+
+// NewOrderAttachmentService creates a new instance of OrderAttachmentService. store may
+// be nil when no attachment storage is configured, in which case uploads and downloads
+// are unavailable.
+func NewOrderAttachmentService(
+	orderRepo domain.OrderRepository,
+	attachmentRepo domain.OrderAttachmentRepository,
+	store objectstore.Store,
+	cfg OrderAttachmentConfig,
+	log *logger.Logger,
+) OrderAttachmentService {
+	return &orderAttachmentService{
+		orderRepo:      orderRepo,
+		attachmentRepo: attachmentRepo,
+		store:          store,
+		cfg:            cfg,
+		log:            log,
+	}
+}
+
+func (s *orderAttachmentService) Upload(ctx context.Context, orderID int64, req *UploadOrderAttachmentRequest, uploadedBy string) (*OrderAttachmentDTO, error) {
+	if _, err := s.findOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	visibility := domain.AttachmentVisibility(strings.ToUpper(req.Visibility))
+	if visibility == "" {
+		visibility = domain.AttachmentVisibilityInternal
+	}
+	attachment, err := s.upload(ctx, orderID, req, visibility, uploadedBy, false)
+	if err != nil {
+		return nil, err
+	}
+	return ToOrderAttachmentDTO(attachment), nil
+}
+
+func (s *orderAttachmentService) List(ctx context.Context, orderID int64) ([]*OrderAttachmentDTO, error) {
+	if _, err := s.findOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.list(ctx, orderID, false)
+}
+
+func (s *orderAttachmentService) Download(ctx context.Context, orderID, attachmentID int64) (*OrderAttachmentContentDTO, error) {
+	attachment, err := s.attachmentRepo.FindByID(ctx, orderID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.download(ctx, attachment)
+}
+
+func (s *orderAttachmentService) SetVisibility(ctx context.Context, orderID, attachmentID int64, req *UpdateOrderAttachmentRequest) (*OrderAttachmentDTO, error) {
+	attachment, err := s.attachmentRepo.FindByID(ctx, orderID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := attachment.SetVisibility(domain.AttachmentVisibility(strings.ToUpper(req.Visibility))); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.attachmentRepo.UpdateVisibility(ctx, attachment); err != nil {
+		return nil, err
+	}
+	return ToOrderAttachmentDTO(attachment), nil
+}
+
+func (s *orderAttachmentService) Delete(ctx context.Context, orderID, attachmentID int64) error {
+	attachment, err := s.attachmentRepo.FindByID(ctx, orderID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.Delete(ctx, orderID, attachmentID); err != nil {
+		return err
+	}
+	// The attachment is gone either way; a leftover object is only wasted storage
+	if s.store != nil {
+		if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+			s.log.WithError(err).WithField("order_id", orderID).WithField("storage_key", attachment.StorageKey).
+				Warn("Failed to delete order attachment content")
+		}
+	}
+	return nil
+}
+
+func (s *orderAttachmentService) UploadForCustomer(ctx context.Context, customerID, orderID int64, req *UploadOrderAttachmentRequest) (*OrderAttachmentDTO, error) {
+	if _, err := s.findCustomerOrder(ctx, customerID, orderID); err != nil {
+		return nil, err
+	}
+	if s.cfg.CustomerUploads == 0 {
+		return nil, errors.Forbidden("customers cannot attach files to orders")
+	}
+	count, err := s.attachmentRepo.CountCustomerUploads(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.cfg.CustomerUploads {
+		return nil, errors.Conflict(fmt.Sprintf("an order takes at most %d files from the customer", s.cfg.CustomerUploads))
+	}
+
+	attachment, err := s.upload(ctx, orderID, req, domain.AttachmentVisibilityCustomer, fmt.Sprint(customerID), true)
+	if err != nil {
+		return nil, err
+	}
+	return toCustomerAttachmentDTO(attachment), nil
+}
+
+func (s *orderAttachmentService) ListForCustomer(ctx context.Context, customerID, orderID int64) ([]*OrderAttachmentDTO, error) {
+	if _, err := s.findCustomerOrder(ctx, customerID, orderID); err != nil {
+		return nil, err
+	}
+	dtos, err := s.list(ctx, orderID, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, dto := range dtos {
+		dto.UploadedBy = ""
+	}
+	return dtos, nil
+}
+
+func (s *orderAttachmentService) DownloadForCustomer(ctx context.Context, customerID, orderID, attachmentID int64) (*OrderAttachmentContentDTO, error) {
+	if _, err := s.findCustomerOrder(ctx, customerID, orderID); err != nil {
+		return nil, err
+	}
+	attachment, err := s.attachmentRepo.FindByID(ctx, orderID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	// Internal attachments are not acknowledged to exist
+	if !attachment.IsCustomerVisible() {
+		return nil, errors.NotFound("order attachment")
+	}
+	return s.download(ctx, attachment)
+}
+
+// upload stores the content of a new attachment, then records it
+func (s *orderAttachmentService) upload(
+	ctx context.Context,
+	orderID int64,
+	req *UploadOrderAttachmentRequest,
+	visibility domain.AttachmentVisibility,
+	uploadedBy string,
+	byCustomer bool,
+) (*domain.OrderAttachment, error) {
+	if s.store == nil {
+		return nil, errors.ServiceUnavailable("order attachments require attachment storage")
+	}
+	if s.cfg.MaxSizeBytes > 0 && int64(len(req.Content)) > s.cfg.MaxSizeBytes {
+		return nil, errors.ValidationError(fmt.Sprintf("attachment exceeds %d bytes", s.cfg.MaxSizeBytes))
+	}
+	contentType := attachmentContentType(req.ContentType, req.Content)
+	if !s.acceptsContentType(contentType) {
+		return nil, errors.ValidationError(fmt.Sprintf("attachments of type %s are not accepted", contentType))
+	}
+
+	attachment, err := domain.NewOrderAttachment(
+		orderID,
+		domain.AttachmentKind(strings.ToUpper(req.Kind)),
+		visibility,
+		req.FileName,
+		contentType,
+		req.Description,
+		uploadedBy,
+		byCustomer,
+		req.Content,
+	)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if err := s.store.Put(ctx, attachment.StorageKey, req.Content, attachment.ContentType); err != nil {
+		return nil, errors.InternalWrap(err, "failed to store order attachment")
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		if delErr := s.store.Delete(ctx, attachment.StorageKey); delErr != nil {
+			s.log.WithError(delErr).WithField("storage_key", attachment.StorageKey).Warn("Failed to delete unrecorded order attachment content")
+		}
+		return nil, err
+	}
+
+	s.log.WithField("order_id", orderID).WithField("attachment_id", attachment.ID).
+		WithField("kind", string(attachment.Kind)).Info("Order attachment uploaded")
+	return attachment, nil
+}
+
+func (s *orderAttachmentService) download(ctx context.Context, attachment *domain.OrderAttachment) (*OrderAttachmentContentDTO, error) {
+	if s.store == nil {
+		return nil, errors.ServiceUnavailable("order attachments require attachment storage")
+	}
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		s.log.WithField("order_id", attachment.OrderID).WithField("storage_key", attachment.StorageKey).
+			Error("Order attachment content is missing from storage")
+		return nil, errors.NotFound("order attachment content")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to read order attachment")
+	}
+	return &OrderAttachmentContentDTO{
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Content:     content,
+	}, nil
+}
+
+func (s *orderAttachmentService) list(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*OrderAttachmentDTO, error) {
+	attachments, err := s.attachmentRepo.FindByOrderID(ctx, orderID, customerVisibleOnly)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*OrderAttachmentDTO, len(attachments))
+	for i, attachment := range attachments {
+		dtos[i] = ToOrderAttachmentDTO(attachment)
+	}
+	return dtos, nil
+}
+
+// acceptsContentType checks a content type against the accepted ones; none configured accepts any
+func (s *orderAttachmentService) acceptsContentType(contentType string) bool {
+	if len(s.cfg.ContentTypes) == 0 {
+		return true
+	}
+	for _, accepted := range s.cfg.ContentTypes {
+		accepted = strings.ToLower(strings.TrimSpace(accepted))
+		if accepted == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(accepted, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *orderAttachmentService) findOrder(ctx context.Context, orderID int64) (*domain.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order by ID: %w", err)
+	}
+	if order == nil {
+		return nil, errors.NotFound("order")
+	}
+	return order, nil
+}
+
+func (s *orderAttachmentService) findCustomerOrder(ctx context.Context, customerID, orderID int64) (*domain.Order, error) {
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID != customerID {
+		return nil, errors.Forbidden("order belongs to another customer")
+	}
+	return order, nil
+}
+
+// attachmentContentType returns the declared media type of an upload without its
+// parameters, or the one sniffed from its content when none useful was declared
+func attachmentContentType(declared string, content []byte) string {
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil || mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(content))
+	}
+	return strings.ToLower(mediaType)
+}
+
+// toCustomerAttachmentDTO leaves out who uploaded an attachment
+func toCustomerAttachmentDTO(attachment *domain.OrderAttachment) *OrderAttachmentDTO {
+	dto := ToOrderAttachmentDTO(attachment)
+	dto.UploadedBy = ""
+	return dto
+}
@@ -0,0 +1,441 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	taxApp "github.com/qhato/ecommerce/internal/tax/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/pdf"
+)
+
+// Names of the order document templates; see DocumentTemplates
+const (
+	InvoiceTemplate           = "invoice"
+	QuoteTemplate             = "quote"
+	PackingSlipTemplate       = "packing-slip"
+	CommercialInvoiceTemplate = "commercial-invoice"
+)
+
+// DocumentRenderer renders a document template to PDF; pdf.Queue implements it.
+type DocumentRenderer interface {
+	Render(ctx context.Context, name string, data interface{}, opts pdf.RenderOptions) ([]byte, error)
+}
+
+// DocumentSeller is the business issuing invoices and quotes.
+type DocumentSeller struct {
+	Name         string
+	AddressLines []string
+	TaxID        string
+}
+
+// OrderDocument is the data of the invoice and quote templates.
+type OrderDocument struct {
+	Seller       DocumentSeller
+	Number       string // Invoice or quote number
+	OrderID      int64
+	OrderNumber  string
+	CustomerName string
+	EmailAddress string
+	Currency     string
+	IssuedAt     time.Time
+	SubmittedAt  *time.Time // Invoices only
+	ValidUntil   *time.Time // Quotes only
+	Lines        []*OrderDocumentLine
+	Adjustments  []*OrderDocumentAdjustment
+	Subtotal     float64 // After adjustments
+	Shipping     float64
+	Tax          float64
+	TaxBreakdown []*OrderDocumentTax // Invoices only, by jurisdiction
+	Total        float64
+}
+
+// OrderDocumentTax is the tax one jurisdiction levied on an invoiced order.
+type OrderDocumentTax struct {
+	Label  string // Jurisdiction, tax and rate, e.g. "California Sales Tax (7.25%)"
+	Amount float64
+}
+
+// OrderDocumentLine is a priced item on an invoice or quote.
+type OrderDocumentLine struct {
+	SKUID         int64
+	Name          string
+	Quantity      int
+	UnitPrice     float64
+	Tax           float64
+	Total         float64
+	SerialNumbers []string // Invoices only, for items shipped with serials
+
+	orderItemID int64
+}
+
+// SerialNumberLookup returns the serial numbers shipped for each item of an
+// order, by order item ID; the fulfillment serial number service implements it.
+type SerialNumberLookup interface {
+	OrderSerialNumbers(ctx context.Context, orderID int64) (map[int64][]string, error)
+}
+
+// CustomsDeclarer resolves how shipped items are declared to customs from the
+// customs attributes of their SKU and product; the tax landed cost service implements it.
+type CustomsDeclarer interface {
+	DeclareCustoms(ctx context.Context, items []taxApp.CustomsItem) ([]*taxApp.CustomsDeclarationLine, error)
+}
+
+// CommercialInvoiceDocument is the data of the commercial invoice template.
+type CommercialInvoiceDocument struct {
+	*taxApp.CustomsDeclaration
+	Seller       DocumentSeller
+	Number       string
+	CustomerName string
+	IssuedAt     time.Time
+	Shipment     *PackingSlipShipmentDTO // Set when the invoice travels with a shipment
+}
+
+// OrderDocumentAdjustment is an order-level offer adjustment; discounts are negative.
+type OrderDocumentAdjustment struct {
+	Reason string
+	Amount float64
+}
+
+// PackingSlipDocument is the data of the packing slip template.
+type PackingSlipDocument struct {
+	*PackingSlipDTO
+	Seller   DocumentSeller
+	Shipment *PackingSlipShipmentDTO // Set when the slip is packed with a shipment
+}
+
+// OrderDocumentService renders the PDF documents of an order: invoices for
+// submitted orders, quotes for carts, and packing slips and commercial invoices
+// for fulfillment.
+type OrderDocumentService interface {
+	// RenderInvoice renders the invoice of a submitted order.
+	RenderInvoice(ctx context.Context, orderID int64) (*OrderDocumentDTO, error)
+
+	// RenderQuote renders a quote of an order that has not been submitted.
+	RenderQuote(ctx context.Context, orderID int64) (*OrderDocumentDTO, error)
+
+	// RenderPackingSlip renders the packing slip of an order, optionally for one of its shipments.
+	RenderPackingSlip(ctx context.Context, orderID int64, shipment *PackingSlipShipmentDTO) (*OrderDocumentDTO, error)
+
+	// RenderCommercialInvoice renders the commercial invoice customs clear a
+	// cross-border shipment of a submitted order with.
+	RenderCommercialInvoice(ctx context.Context, orderID int64, shipment *PackingSlipShipmentDTO) (*OrderDocumentDTO, error)
+
+	// GetCustomsDeclaration declares the items of a submitted order to customs, for
+	// commercial invoices and carrier label requests. Every item needs an HS code
+	// and origin country.
+	GetCustomsDeclaration(ctx context.Context, orderID int64) (*taxApp.CustomsDeclaration, error)
+}
+
+type orderDocumentService struct {
+	orderRepo         domain.OrderRepository
+	orderItemRepo     domain.OrderItemRepository
+	adjustmentRepo    domain.OrderAdjustmentRepository
+	taxDetailRepo     domain.OrderTaxDetailRepository
+	giftOptionService GiftOptionService
+	serialNumbers     SerialNumberLookup
+	customs           CustomsDeclarer
+	landedCostRepo    domain.OrderLandedCostRepository
+	renderer          DocumentRenderer
+	seller            DocumentSeller
+	quoteValidity     time.Duration
+}
+
+// NewOrderDocumentService creates a new instance of OrderDocumentService.
+func NewOrderDocumentService(
+	orderRepo domain.OrderRepository,
+	orderItemRepo domain.OrderItemRepository,
+	adjustmentRepo domain.OrderAdjustmentRepository,
+	taxDetailRepo domain.OrderTaxDetailRepository,
+	giftOptionService GiftOptionService,
+	serialNumbers SerialNumberLookup,
+	customs CustomsDeclarer,
+	landedCostRepo domain.OrderLandedCostRepository,
+	renderer DocumentRenderer,
+	seller DocumentSeller,
+	quoteValidity time.Duration,
+) OrderDocumentService {
+	return &orderDocumentService{
+		orderRepo:         orderRepo,
+		orderItemRepo:     orderItemRepo,
+		adjustmentRepo:    adjustmentRepo,
+		taxDetailRepo:     taxDetailRepo,
+		giftOptionService: giftOptionService,
+		serialNumbers:     serialNumbers,
+		customs:           customs,
+		landedCostRepo:    landedCostRepo,
+		renderer:          renderer,
+		seller:            seller,
+		quoteValidity:     quoteValidity,
+	}
+}
+
+func (s *orderDocumentService) RenderInvoice(ctx context.Context, orderID int64) (*OrderDocumentDTO, error) {
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.SubmitDate == nil {
+		return nil, errors.Conflict("only submitted orders have an invoice; render a quote instead")
+	}
+
+	doc, err := s.orderDocument(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	doc.Number = order.OrderNumber
+	doc.SubmittedAt = order.SubmitDate
+	taxDetails, err := s.taxDetailRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax details: %w", err)
+	}
+	doc.TaxBreakdown = taxBreakdown(taxDetails)
+	if s.serialNumbers != nil {
+		serials, err := s.serialNumbers.OrderSerialNumbers(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get serial numbers: %w", err)
+		}
+		for _, line := range doc.Lines {
+			line.SerialNumbers = serials[line.orderItemID]
+		}
+	}
+	return s.render(ctx, InvoiceTemplate, "invoice-"+order.OrderNumber, order.LocaleCode, doc)
+}
+
+func (s *orderDocumentService) RenderQuote(ctx context.Context, orderID int64) (*OrderDocumentDTO, error) {
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.SubmitDate != nil {
+		return nil, errors.Conflict("submitted orders are invoiced, not quoted")
+	}
+
+	doc, err := s.orderDocument(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Lines) == 0 {
+		return nil, errors.ValidationError("cannot quote an empty order")
+	}
+	// Quotes of the same cart are told apart by when they were issued
+	doc.Number = fmt.Sprintf("Q%d-%s", order.ID, doc.IssuedAt.Format("20060102150405"))
+	validUntil := doc.IssuedAt.Add(s.quoteValidity)
+	doc.ValidUntil = &validUntil
+	return s.render(ctx, QuoteTemplate, "quote-"+doc.Number, order.LocaleCode, doc)
+}
+
+func (s *orderDocumentService) RenderPackingSlip(ctx context.Context, orderID int64, shipment *PackingSlipShipmentDTO) (*OrderDocumentDTO, error) {
+	slip, err := s.giftOptionService.GetPackingSlip(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "packing-slip-" + slip.OrderNumber
+	if slip.OrderNumber == "" {
+		name = "packing-slip-" + strconv.FormatInt(orderID, 10)
+	}
+	if shipment != nil {
+		name += "-" + strconv.FormatInt(shipment.ShipmentID, 10)
+	}
+	doc := &PackingSlipDocument{PackingSlipDTO: slip, Seller: s.seller, Shipment: shipment}
+	return s.render(ctx, PackingSlipTemplate, name, order.LocaleCode, doc)
+}
+
+func (s *orderDocumentService) RenderCommercialInvoice(ctx context.Context, orderID int64, shipment *PackingSlipShipmentDTO) (*OrderDocumentDTO, error) {
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	declaration, err := s.customsDeclaration(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &CommercialInvoiceDocument{
+		CustomsDeclaration: declaration,
+		Seller:             s.seller,
+		Number:             "CI-" + order.OrderNumber,
+		CustomerName:       order.Name,
+		IssuedAt:           time.Now(),
+		Shipment:           shipment,
+	}
+	name := "commercial-invoice-" + order.OrderNumber
+	if shipment != nil {
+		doc.Number += "-" + strconv.FormatInt(shipment.ShipmentID, 10)
+		name += "-" + strconv.FormatInt(shipment.ShipmentID, 10)
+	}
+	return s.render(ctx, CommercialInvoiceTemplate, name, order.LocaleCode, doc)
+}
+
+func (s *orderDocumentService) GetCustomsDeclaration(ctx context.Context, orderID int64) (*taxApp.CustomsDeclaration, error) {
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.customsDeclaration(ctx, order)
+}
+
+// customsDeclaration declares the items of a submitted order, described by their
+// name when their product has no customs description
+func (s *orderDocumentService) customsDeclaration(ctx context.Context, order *domain.Order) (*taxApp.CustomsDeclaration, error) {
+	if s.customs == nil {
+		return nil, errors.NotImplemented("customs declarations are not configured")
+	}
+	if order.SubmitDate == nil {
+		return nil, errors.Conflict("only submitted orders can be declared to customs")
+	}
+	items, err := s.orderItemRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+	customsItems := make([]taxApp.CustomsItem, 0, len(items))
+	names := make(map[int64]string, len(items))
+	for _, item := range items {
+		customsItems = append(customsItems, taxApp.CustomsItem{
+			ItemID:    item.ID,
+			ProductID: item.ProductID,
+			SKUID:     item.SKUID,
+			Quantity:  item.Quantity,
+			Amount:    item.TotalPrice,
+		})
+		names[item.ID] = item.Name
+	}
+	lines, err := s.customs.DeclareCustoms(ctx, customsItems)
+	if err != nil {
+		return nil, err
+	}
+
+	declaration := &taxApp.CustomsDeclaration{
+		OrderID:         order.ID,
+		OrderNumber:     order.OrderNumber,
+		CurrencyCode:    order.CurrencyCode,
+		ReasonForExport: taxApp.ReasonForExportSale,
+		Lines:           lines,
+	}
+	var unclassified []string
+	for _, line := range lines {
+		if line.HSCode == "" || line.OriginCountry == "" {
+			unclassified = append(unclassified, strconv.FormatInt(line.SKUID, 10))
+		}
+		if line.Description == "" {
+			line.Description = names[line.ItemID]
+		}
+		declaration.TotalValue += line.Value
+	}
+	if len(unclassified) > 0 {
+		return nil, errors.ValidationError("set the HS code and origin country of SKU " + strings.Join(unclassified, ", ") + " before shipping abroad")
+	}
+
+	cost, err := s.landedCostRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get landed cost: %w", err)
+	}
+	if cost != nil {
+		declaration.Incoterm = string(cost.Incoterm)
+	}
+	return declaration, nil
+}
+
+func (s *orderDocumentService) findOrder(ctx context.Context, orderID int64) (*domain.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	if order == nil {
+		return nil, errors.NotFound("order")
+	}
+	return order, nil
+}
+
+// orderDocument collects the priced lines and totals of an order
+func (s *orderDocumentService) orderDocument(ctx context.Context, order *domain.Order) (*OrderDocument, error) {
+	items, err := s.orderItemRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+	adjustments, err := s.adjustmentRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order adjustments: %w", err)
+	}
+
+	doc := &OrderDocument{
+		Seller:       s.seller,
+		OrderID:      order.ID,
+		OrderNumber:  order.OrderNumber,
+		CustomerName: order.Name,
+		EmailAddress: order.EmailAddress,
+		Currency:     order.CurrencyCode,
+		IssuedAt:     time.Now(),
+		Lines:        make([]*OrderDocumentLine, 0, len(items)),
+		Adjustments:  make([]*OrderDocumentAdjustment, 0, len(adjustments)),
+		Subtotal:     order.OrderSubtotal,
+		Shipping:     order.TotalShipping,
+		Tax:          order.TotalTax,
+		Total:        order.OrderTotal,
+	}
+	for _, item := range items {
+		doc.Lines = append(doc.Lines, &OrderDocumentLine{
+			SKUID:       item.SKUID,
+			Name:        item.Name,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.Price,
+			Tax:         item.TaxAmount,
+			Total:       item.TotalPrice,
+			orderItemID: item.ID,
+		})
+	}
+	for _, adjustment := range adjustments {
+		doc.Adjustments = append(doc.Adjustments, &OrderDocumentAdjustment{
+			Reason: adjustment.AdjustmentReason,
+			Amount: adjustment.AdjustmentValue,
+		})
+	}
+	return doc, nil
+}
+
+// taxBreakdown sums the tax details of an order by jurisdiction, tax and rate, in
+// the order they first appear
+func taxBreakdown(details []*domain.OrderTaxDetail) []*OrderDocumentTax {
+	breakdown := make([]*OrderDocumentTax, 0)
+	byLabel := make(map[string]*OrderDocumentTax)
+	for _, detail := range details {
+		name := strings.TrimSpace(detail.JurisdictionName + " " + detail.TaxName)
+		label := fmt.Sprintf("%s (%s%%)", name, strconv.FormatFloat(detail.Rate*100, 'f', -1, 64))
+		tax, ok := byLabel[label]
+		if !ok {
+			tax = &OrderDocumentTax{Label: label}
+			byLabel[label] = tax
+			breakdown = append(breakdown, tax)
+		}
+		tax.Amount += detail.Amount
+	}
+	return breakdown
+}
+
+// render renders a template in the order's locale
+func (s *orderDocumentService) render(ctx context.Context, template, name, locale string, data interface{}) (*OrderDocumentDTO, error) {
+	content, err := s.renderer.Render(ctx, template, data, pdf.RenderOptions{Locale: locale})
+	if err != nil {
+		switch {
+		case errors.Is(err, pdf.ErrQueueFull):
+			return nil, errors.ServiceUnavailable("document rendering is busy, try again shortly")
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, errors.GatewayTimeout("document rendering timed out")
+		}
+		return nil, fmt.Errorf("failed to render %s: %w", template, err)
+	}
+	return &OrderDocumentDTO{
+		FileName:    name + ".pdf",
+		ContentType: "application/pdf",
+		Content:     content,
+	}, nil
+}
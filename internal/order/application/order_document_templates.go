@@ -0,0 +1,181 @@
+package application
+
+// DocumentTemplates are the built-in order document templates by name. A
+// template directory can replace any of them with a file of the same name, e.g.
+// invoice.html; see pkg/pdf for the markup and the money helper. Their text is
+// translated with the t helper from Messages and the configured message catalogs.
+var DocumentTemplates = map[string]string{
+	InvoiceTemplate:           invoiceTemplate,
+	QuoteTemplate:             quoteTemplate,
+	PackingSlipTemplate:       packingSlipTemplate,
+	CommercialInvoiceTemplate: commercialInvoiceTemplate,
+}
+
+// sellerBlock shows the issuing business, with the logo when one is registered
+const sellerBlock = `
+{{ define "seller" }}
+<table widths="*,*">
+  <tr>
+    <td>{{ if hasImage "logo" }}<img src="logo" height="36"/>{{ end }}
+      {{ if .Seller.Name }}<b size="12">{{ .Seller.Name }}</b>{{ end }}
+      {{ range .Seller.AddressLines }}<br/>{{ . }}{{ end }}
+      {{ if .Seller.TaxID }}<br/>{{ t "document.tax_id" "id" .Seller.TaxID }}{{ end }}</td>
+    <td align="right" size="8" color="#666666">{{ .Number }}</td>
+  </tr>
+</table>
+<hr/>
+{{ end }}`
+
+// orderLines lists the priced items, adjustments and totals of an invoice or quote
+const orderLines = `
+{{ define "lines" }}
+<table widths="*,40,80,80" cellpadding="4">
+  <thead>
+    <tr bg="#eeeeee" rule="0.5"><th>{{ t "document.item" }}</th><th align="right">{{ t "document.quantity" }}</th><th align="right">{{ t "document.unit_price" }}</th><th align="right">{{ t "document.total" }}</th></tr>
+  </thead>
+  {{ range .Lines }}
+  <tr rule="0.25"><td>{{ .Name }}<br/><span size="8" color="#666666">{{ t "document.sku" }} {{ .SKUID }}{{ if .SerialNumbers }}<br/>{{ t "document.serial_numbers" }} {{ range $i, $serial := .SerialNumbers }}{{ if $i }}, {{ end }}{{ $serial }}{{ end }}{{ end }}</span></td>
+    <td align="right">{{ .Quantity }}</td>
+    <td align="right">{{ money .UnitPrice $.Currency }}</td>
+    <td align="right">{{ money .Total $.Currency }}</td></tr>
+  {{ end }}
+</table>
+<spacer height="6"/>
+<table widths="*,100">
+  {{ range .Adjustments }}
+  <tr><td align="right">{{ .Reason }}</td><td align="right">{{ money .Amount $.Currency }}</td></tr>
+  {{ end }}
+  <tr><td align="right">{{ t "document.subtotal" }}</td><td align="right">{{ money .Subtotal .Currency }}</td></tr>
+  <tr><td align="right">{{ t "document.shipping" }}</td><td align="right">{{ money .Shipping .Currency }}</td></tr>
+  <tr><td align="right">{{ t "document.tax" }}</td><td align="right">{{ money .Tax .Currency }}</td></tr>
+  {{ range .TaxBreakdown }}
+  <tr><td align="right" size="8" color="#666666">{{ .Label }}</td><td align="right" size="8" color="#666666">{{ money .Amount $.Currency }}</td></tr>
+  {{ end }}
+  <tr><td align="right"><b size="12">{{ t "document.total" }}</b></td><td align="right"><b size="12">{{ money .Total .Currency }}</b></td></tr>
+</table>
+{{ end }}`
+
+const invoiceTemplate = sellerBlock + orderLines + `
+<html>
+<head><title>{{ t "invoice.title" "number" .Number }}</title></head>
+<body size="A4" margin="40" font-size="10">
+<header>{{ template "seller" . }}</header>
+<h1>{{ t "invoice.heading" }}</h1>
+<table widths="*,*">
+  <tr>
+    <td><b>{{ t "invoice.bill_to" }}</b><br/>{{ .CustomerName }}<br/>{{ .EmailAddress }}</td>
+    <td align="right">{{ t "invoice.number" }}: {{ .Number }}<br/>
+      {{ t "invoice.date" }}: {{ .IssuedAt.Format "2006-01-02" }}<br/>
+      {{ t "invoice.order_date" }}: {{ .SubmittedAt.Format "2006-01-02" }}</td>
+  </tr>
+</table>
+<spacer height="12"/>
+{{ template "lines" . }}
+<footer><p align="center" size="8" color="#666666">{{ t "invoice.title" "number" .Number }} - {{ t "document.page" }} <pagenumber/> {{ t "document.page_of" }} <pagecount/></p></footer>
+</body>
+</html>`
+
+const quoteTemplate = sellerBlock + orderLines + `
+<html>
+<head><title>{{ t "quote.title" "number" .Number }}</title></head>
+<body size="A4" margin="40" font-size="10">
+<header>{{ template "seller" . }}</header>
+<h1>{{ t "quote.heading" }}</h1>
+<table widths="*,*">
+  <tr>
+    <td><b>{{ t "quote.prepared_for" }}</b><br/>{{ .CustomerName }}<br/>{{ .EmailAddress }}</td>
+    <td align="right">{{ t "quote.number" }}: {{ .Number }}<br/>
+      {{ t "quote.issued" }}: {{ .IssuedAt.Format "2006-01-02" }}<br/>
+      <b>{{ t "quote.valid_until" }}: {{ .ValidUntil.Format "2006-01-02" }}</b></td>
+  </tr>
+</table>
+<spacer height="12"/>
+{{ template "lines" . }}
+<spacer height="12"/>
+<p size="8" color="#666666">{{ t "quote.disclaimer" }}</p>
+<footer><p align="center" size="8" color="#666666">{{ t "quote.title" "number" .Number }} - {{ t "document.page" }} <pagenumber/> {{ t "document.page_of" }} <pagecount/></p></footer>
+</body>
+</html>`
+
+const packingSlipTemplate = `
+<html>
+<head><title>{{ t "packing_slip.title" "number" .OrderNumber }}</title></head>
+<body size="A4" margin="40" font-size="10">
+<header>
+  <table widths="*,*">
+    <tr><td>{{ if hasImage "logo" }}<img src="logo" height="36"/>{{ end }}
+      {{ if .Seller.Name }}<b size="12">{{ .Seller.Name }}</b>{{ end }}
+      {{ range .Seller.AddressLines }}<br/>{{ . }}{{ end }}</td>
+    <td align="right"><b size="14">{{ t "packing_slip.heading" }}</b><br/>{{ t "packing_slip.order" "number" .OrderNumber }}</td></tr>
+  </table>
+  <hr/>
+</header>
+<table widths="*,*">
+  <tr>
+    <td><b>{{ t "packing_slip.ship_to" }}</b><br/>{{ if .Shipment }}{{ range .Shipment.AddressLines }}{{ . }}<br/>{{ end }}{{ else }}{{ .ShipToName }}{{ end }}</td>
+    <td align="right">{{ t "packing_slip.date" }}: {{ .GeneratedAt.Format "2006-01-02" }}
+      {{ with .Shipment }}<br/>{{ t "packing_slip.shipment" }}: {{ .ShipmentID }}<br/>{{ .Carrier }} {{ .ShippingMethod }}
+      {{ if .TrackingNumber }}<br/>{{ t "packing_slip.tracking" }}: {{ .TrackingNumber }}{{ end }}{{ end }}</td>
+  </tr>
+</table>
+<spacer height="12"/>
+<table widths="*,70,50" cellpadding="5">
+  <thead>
+    <tr bg="#eeeeee" rule="0.5"><th>{{ t "document.item" }}</th><th>{{ t "document.sku" }}</th><th align="right">{{ t "document.quantity" }}</th></tr>
+  </thead>
+  {{ range .Lines }}
+  <tr rule="0.25">
+    <td>{{ .Name }}
+      {{ if .GiftWrap }}<br/><i>{{ t "packing_slip.gift_wrap" }}: {{ .GiftWrap }}</i>{{ end }}
+      {{ with .PersonalMessage }}<br/><i>{{ t "packing_slip.message" }}{{ if .To }} {{ t "packing_slip.message_to" "name" .To }}{{ end }}{{ if .From }} {{ t "packing_slip.message_from" "name" .From }}{{ end }}:</i> {{ .Message }}{{ end }}</td>
+    <td>{{ .SKUID }}</td>
+    <td align="right"><b>{{ .Quantity }}</b></td>
+  </tr>
+  {{ end }}
+</table>
+<footer><p align="center" size="8" color="#666666">{{ t "packing_slip.order" "number" .OrderNumber }} - {{ t "document.page" }} <pagenumber/> {{ t "document.page_of" }} <pagecount/></p></footer>
+</body>
+</html>`
+
+// commercialInvoiceTemplate declares the contents of a cross-border shipment to
+// customs, valued by the declared value rules of its items
+const commercialInvoiceTemplate = sellerBlock + `
+<html>
+<head><title>Commercial invoice {{ .Number }}</title></head>
+<body size="A4" margin="40" font-size="10">
+<header>{{ template "seller" . }}</header>
+<h1>Commercial invoice</h1>
+<table widths="*,*">
+  <tr>
+    <td><b>Ship to</b><br/>{{ if .Shipment }}{{ range .Shipment.AddressLines }}{{ . }}<br/>{{ end }}{{ else }}{{ .CustomerName }}{{ end }}</td>
+    <td align="right">Invoice number: {{ .Number }}<br/>
+      Date: {{ .IssuedAt.Format "2006-01-02" }}<br/>
+      Order: {{ .OrderNumber }}
+      {{ with .Shipment }}<br/>{{ .Carrier }} {{ .ShippingMethod }}{{ if .TrackingNumber }}<br/>Tracking: {{ .TrackingNumber }}{{ end }}{{ end }}
+      <br/>Reason for export: {{ .ReasonForExport }}
+      {{ if .Incoterm }}<br/>Terms of delivery: {{ .Incoterm }}{{ end }}</td>
+  </tr>
+</table>
+<spacer height="12"/>
+<table widths="*,60,40,40,70,70" cellpadding="4">
+  <thead>
+    <tr bg="#eeeeee" rule="0.5"><th>Description</th><th>HS code</th><th>Origin</th><th align="right">Qty</th><th align="right">Unit value</th><th align="right">Value</th></tr>
+  </thead>
+  {{ range .Lines }}
+  <tr rule="0.25"><td>{{ .Description }}<br/><span size="8" color="#666666">SKU {{ .SKUID }}</span></td>
+    <td>{{ .HSCode }}</td>
+    <td>{{ .OriginCountry }}</td>
+    <td align="right">{{ .Quantity }}</td>
+    <td align="right">{{ money .UnitValue $.CurrencyCode }}</td>
+    <td align="right">{{ money .Value $.CurrencyCode }}</td></tr>
+  {{ end }}
+</table>
+<spacer height="6"/>
+<table widths="*,100">
+  <tr><td align="right"><b size="12">Total declared value</b></td><td align="right"><b size="12">{{ money .TotalValue .CurrencyCode }}</b></td></tr>
+</table>
+<spacer height="18"/>
+<p size="8">I declare that the information on this invoice is true and correct and that the contents of this shipment are as stated above.</p>
+<footer><p align="center" size="8" color="#666666">Commercial invoice {{ .Number }} - page <pagenumber/> of <pagecount/></p></footer>
+</body>
+</html>`
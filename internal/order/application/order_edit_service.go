@@ -0,0 +1,325 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	fulfillmentDomain "github.com/qhato/ecommerce/internal/fulfillment/domain"
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// OrderEditService lets customers and staff change the lines of a submitted order
+// before it ships. An edit snapshots the lines with the prices, discounts and tax
+// they were sold with; lines it adds are priced at the current prices, offers and
+// tax. The order is only changed, and stock reserved or released, when the delta
+// of the last preview is confirmed.
+type OrderEditService interface {
+	// OpenEdit opens an edit of an order, or returns the edit already open.
+	OpenEdit(ctx context.Context, editor OrderEditor, orderID int64) (*OrderEditDTO, error)
+
+	// GetEdit returns an edit of an order.
+	GetEdit(ctx context.Context, editor OrderEditor, editID int64) (*OrderEditDTO, error)
+
+	// AddLine stages a SKU to add to the order.
+	AddLine(ctx context.Context, editor OrderEditor, editID int64, req *AddOrderEditLineRequest) (*OrderEditDTO, error)
+
+	// UpdateLine changes the quantity of a line of the edit; 0 removes it.
+	UpdateLine(ctx context.Context, editor OrderEditor, editID int64, lineID int, req *UpdateOrderEditLineRequest) (*OrderEditDTO, error)
+
+	// PreviewEdit prices the added lines and returns the delta the edit makes to the order.
+	PreviewEdit(ctx context.Context, editor OrderEditor, editID int64) (*OrderEditDTO, error)
+
+	// CommitEdit applies the previewed delta to the order once it is confirmed.
+	CommitEdit(ctx context.Context, editor OrderEditor, editID int64, req *CommitOrderEditRequest) (*OrderEditDTO, error)
+
+	// CancelEdit closes an edit without changing the order.
+	CancelEdit(ctx context.Context, editor OrderEditor, editID int64) (*OrderEditDTO, error)
+}
+
+// OrderEditor is who edits an order: a customer editing their own order, or staff
+type OrderEditor struct {
+	CustomerID int64  // 0 for staff
+	Name       string // Recorded on the edit, e.g. the admin's email
+}
+
+// IsStaff reports whether the editor is staff rather than a customer
+func (e OrderEditor) IsStaff() bool {
+	return e.CustomerID == 0
+}
+
+// OrderEditConfig holds the order edit settings
+type OrderEditConfig struct {
+	Window time.Duration // How long after submission customers can edit their orders; 0 leaves it to staff
+	TTL    time.Duration // How long an edit stays open before it expires
+}
+
+type orderEditService struct {
+	orderRepo     domain.OrderRepository
+	orderItemRepo domain.OrderItemRepository
+	editRepo      domain.OrderEditRepository
+	shipmentRepo  fulfillmentDomain.ShipmentRepository
+	orderService  OrderService
+	cfg           OrderEditConfig
+	log           *logger.Logger
+}
+
+// NewOrderEditService creates a new instance of OrderEditService.
+func NewOrderEditService(
+	orderRepo domain.OrderRepository,
+	orderItemRepo domain.OrderItemRepository,
+	editRepo domain.OrderEditRepository,
+	shipmentRepo fulfillmentDomain.ShipmentRepository,
+	orderService OrderService,
+	cfg OrderEditConfig,
+	log *logger.Logger,
+) OrderEditService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 30 * time.Minute
+	}
+	return &orderEditService{
+		orderRepo:     orderRepo,
+		orderItemRepo: orderItemRepo,
+		editRepo:      editRepo,
+		shipmentRepo:  shipmentRepo,
+		orderService:  orderService,
+		cfg:           cfg,
+		log:           log,
+	}
+}
+
+func (s *orderEditService) OpenEdit(ctx context.Context, editor OrderEditor, orderID int64) (*OrderEditDTO, error) {
+	order, err := s.findEditableOrder(ctx, editor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	edit, err := s.editRepo.FindOpenByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open edit of order %d: %w", orderID, err)
+	}
+	if edit != nil {
+		if edit.IsOpen(time.Now()) {
+			return ToOrderEditDTO(edit), nil
+		}
+		edit.Expire()
+		if err := s.editRepo.Save(ctx, edit); err != nil {
+			return nil, fmt.Errorf("failed to expire order edit %d: %w", edit.ID, err)
+		}
+	}
+
+	items, err := s.orderItemRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order items for order %d: %w", orderID, err)
+	}
+	edit, err = domain.NewOrderEdit(order, items, editor.Name, s.cfg.TTL)
+	if err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.editRepo.Save(ctx, edit); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"order_id":  orderID,
+		"edit_id":   edit.ID,
+		"opened_by": editor.Name,
+	}).Info("Order edit opened")
+	return ToOrderEditDTO(edit), nil
+}
+
+func (s *orderEditService) GetEdit(ctx context.Context, editor OrderEditor, editID int64) (*OrderEditDTO, error) {
+	edit, err := s.findEdit(ctx, editor, editID)
+	if err != nil {
+		return nil, err
+	}
+	return ToOrderEditDTO(edit), nil
+}
+
+func (s *orderEditService) AddLine(ctx context.Context, editor OrderEditor, editID int64, req *AddOrderEditLineRequest) (*OrderEditDTO, error) {
+	if req.SKUID == 0 {
+		return nil, errors.ValidationError("sku_id is required")
+	}
+	if req.Quantity <= 0 {
+		return nil, errors.ValidationError("quantity must be greater than zero")
+	}
+	edit, _, err := s.findOpenEdit(ctx, editor, editID)
+	if err != nil {
+		return nil, err
+	}
+
+	edit.AddLine(&domain.OrderEditLine{SKUID: req.SKUID, Quantity: req.Quantity})
+	if err := s.editRepo.Save(ctx, edit); err != nil {
+		return nil, fmt.Errorf("failed to save order edit %d: %w", edit.ID, err)
+	}
+	return ToOrderEditDTO(edit), nil
+}
+
+func (s *orderEditService) UpdateLine(ctx context.Context, editor OrderEditor, editID int64, lineID int, req *UpdateOrderEditLineRequest) (*OrderEditDTO, error) {
+	edit, _, err := s.findOpenEdit(ctx, editor, editID)
+	if err != nil {
+		return nil, err
+	}
+
+	if edit.Line(lineID) == nil {
+		return nil, errors.NotFound(fmt.Sprintf("line %d of order edit %d", lineID, editID))
+	}
+	if err := edit.SetQuantity(lineID, req.Quantity); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.editRepo.Save(ctx, edit); err != nil {
+		return nil, fmt.Errorf("failed to save order edit %d: %w", edit.ID, err)
+	}
+	return ToOrderEditDTO(edit), nil
+}
+
+func (s *orderEditService) PreviewEdit(ctx context.Context, editor OrderEditor, editID int64) (*OrderEditDTO, error) {
+	edit, order, err := s.findOpenEdit(ctx, editor, editID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only added lines are priced again; the lines of the order keep what they were sold for
+	for _, line := range edit.Lines {
+		if line.OrderItemID == 0 {
+			if err := s.orderService.PriceEditLine(ctx, order, line); err != nil {
+				return nil, err
+			}
+		}
+	}
+	summary, err := edit.Summarize()
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	edit.Preview(summary)
+	if err := s.editRepo.Save(ctx, edit); err != nil {
+		return nil, fmt.Errorf("failed to save order edit %d: %w", edit.ID, err)
+	}
+	return ToOrderEditDTO(edit), nil
+}
+
+func (s *orderEditService) CommitEdit(ctx context.Context, editor OrderEditor, editID int64, req *CommitOrderEditRequest) (*OrderEditDTO, error) {
+	if !req.Confirm {
+		return nil, errors.ValidationError("confirm the previewed changes to commit the edit")
+	}
+	edit, order, err := s.findOpenEdit(ctx, editor, editID)
+	if err != nil {
+		return nil, err
+	}
+	if err := edit.Commit(req.Revision, editor.Name); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+
+	// The edit is committed before its changes are applied, so that concurrent
+	// or retried commits cannot apply it twice
+	claimed, err := s.editRepo.Claim(ctx, edit)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.Conflict(fmt.Sprintf("order edit %d was committed or changed since it was previewed", editID))
+	}
+	if err := s.orderService.ApplyOrderEdit(ctx, order, edit); err != nil {
+		if reopenErr := s.editRepo.Reopen(ctx, edit.ID); reopenErr != nil {
+			s.log.WithError(reopenErr).WithField("edit_id", edit.ID).Error("Failed to reopen order edit")
+		}
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"order_id":     order.ID,
+		"edit_id":      edit.ID,
+		"committed_by": editor.Name,
+		"balance_due":  edit.Summary.BalanceDue,
+	}).Info("Order edit committed")
+	return ToOrderEditDTO(edit), nil
+}
+
+func (s *orderEditService) CancelEdit(ctx context.Context, editor OrderEditor, editID int64) (*OrderEditDTO, error) {
+	edit, err := s.findEdit(ctx, editor, editID)
+	if err != nil {
+		return nil, err
+	}
+	if edit.Status != domain.OrderEditOpen {
+		return nil, errors.Conflict(fmt.Sprintf("order edit %d is %s", editID, edit.Status))
+	}
+
+	edit.Cancel(editor.Name)
+	if err := s.editRepo.Save(ctx, edit); err != nil {
+		return nil, fmt.Errorf("failed to save order edit %d: %w", edit.ID, err)
+	}
+	return ToOrderEditDTO(edit), nil
+}
+
+// findEdit returns an edit of an order the editor may see
+func (s *orderEditService) findEdit(ctx context.Context, editor OrderEditor, editID int64) (*domain.OrderEdit, error) {
+	edit, err := s.editRepo.FindByID(ctx, editID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order edit %d: %w", editID, err)
+	}
+	if edit == nil || (!editor.IsStaff() && edit.CustomerID != editor.CustomerID) {
+		return nil, errors.NotFound(fmt.Sprintf("order edit %d", editID))
+	}
+	return edit, nil
+}
+
+// findOpenEdit returns an open edit the editor may change, with its order, which
+// must still be editable
+func (s *orderEditService) findOpenEdit(ctx context.Context, editor OrderEditor, editID int64) (*domain.OrderEdit, *domain.Order, error) {
+	edit, err := s.findEdit(ctx, editor, editID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if edit.Status != domain.OrderEditOpen {
+		return nil, nil, errors.Conflict(fmt.Sprintf("order edit %d is %s", editID, edit.Status))
+	}
+	if !edit.IsOpen(time.Now()) {
+		edit.Expire()
+		if err := s.editRepo.Save(ctx, edit); err != nil {
+			return nil, nil, fmt.Errorf("failed to expire order edit %d: %w", edit.ID, err)
+		}
+		return nil, nil, errors.Conflict(fmt.Sprintf("order edit %d expired; open a new one", editID))
+	}
+	order, err := s.findEditableOrder(ctx, editor, edit.OrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return edit, order, nil
+}
+
+// findEditableOrder returns an order the editor may still edit: customers only their
+// own, within the edit window after submission; staff any order until it ships
+func (s *orderEditService) findEditableOrder(ctx context.Context, editor OrderEditor, orderID int64) (*domain.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order %d: %w", orderID, err)
+	}
+	if order == nil {
+		return nil, errors.NotFound(fmt.Sprintf("order %d", orderID))
+	}
+	if !editor.IsStaff() {
+		if order.CustomerID != editor.CustomerID {
+			return nil, errors.Forbidden("order belongs to another customer")
+		}
+		if s.cfg.Window == 0 || order.SubmitDate == nil || time.Since(*order.SubmitDate) > s.cfg.Window {
+			return nil, errors.Conflict("the order can no longer be edited; contact support to change it")
+		}
+	}
+	if order.SubmitDate == nil || !order.CanBeHeld() {
+		return nil, errors.OrderNotEditable(strconv.FormatInt(orderID, 10), string(order.Status))
+	}
+
+	// Stock already picked or shipped cannot be taken back by an edit
+	shipments, err := s.shipmentRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shipments of order %d: %w", orderID, err)
+	}
+	for _, shipment := range shipments {
+		if shipment.Status != fulfillmentDomain.ShipmentStatusCancelled && shipment.Status != fulfillmentDomain.ShipmentStatusFailed {
+			return nil, errors.Conflict("the order is already being fulfilled and can no longer be edited")
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/export"
+	"github.com/qhato/ecommerce/pkg/money"
+)
+
+// NewOrderExport describes a CSV export of the orders matching filter
+func NewOrderExport(repo domain.OrderExportRepository, filter *domain.OrderExportFilter) export.Export {
+	return export.Export{
+		Kind:     "orders",
+		Filename: "orders-" + time.Now().Format("20060102-150405") + ".csv",
+		Header: []string{
+			"order_id", "order_number", "customer_id", "email", "name", "status", "currency",
+			"subtotal", "tax", "shipping", "total", "submitted_at", "created_at",
+		},
+		Rows: func(ctx context.Context, w export.RowWriter) error {
+			return repo.StreamOrders(ctx, filter, func(row *domain.OrderExportRow) error {
+				submittedAt := ""
+				if row.SubmitDate != nil {
+					submittedAt = row.SubmitDate.Format(time.RFC3339)
+				}
+				return w.Write([]string{
+					strconv.FormatInt(row.ID, 10),
+					row.OrderNumber,
+					strconv.FormatInt(row.CustomerID, 10),
+					row.EmailAddress,
+					row.Name,
+					string(row.Status),
+					row.CurrencyCode,
+					formatExportAmount(row.SubTotal, row.CurrencyCode),
+					formatExportAmount(row.TotalTax, row.CurrencyCode),
+					formatExportAmount(row.TotalShipping, row.CurrencyCode),
+					formatExportAmount(row.Total, row.CurrencyCode),
+					submittedAt,
+					row.CreatedAt.Format(time.RFC3339),
+				})
+			})
+		},
+	}
+}
+
+// formatExportAmount writes an amount with the currency's decimal places and no symbol
+func formatExportAmount(amount float64, currency string) string {
+	return strconv.FormatFloat(money.Round(amount, currency), 'f', int(money.RuleFor(currency).DecimalPlaces), 64)
+}
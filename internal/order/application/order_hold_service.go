@@ -0,0 +1,300 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// OrderHoldService pauses the fulfillment of submitted orders that need review, such as
+// suspected fraud, unverified payments or address issues. Held orders wait in a queue
+// until staff release them, restoring their previous status, or cancel them. Staff are
+// told when a hold stays open longer than its SLA.
+type OrderHoldService interface {
+	// PlaceHold puts a submitted order that has not shipped on hold.
+	PlaceHold(ctx context.Context, orderID int64, req *PlaceOrderHoldRequest) (*OrderHoldDTO, error)
+
+	// ListHolds lists the orders on hold, oldest first; an empty reason lists every reason.
+	ListHolds(ctx context.Context, reason string) (*OrderHoldQueueDTO, error)
+
+	// GetOrderHolds lists every hold of an order, newest first.
+	GetOrderHolds(ctx context.Context, orderID int64) ([]*OrderHoldDTO, error)
+
+	// ReleaseHold closes the open hold of an order and restores the status it was held from.
+	ReleaseHold(ctx context.Context, orderID int64, req *ResolveOrderHoldRequest) (*OrderHoldDTO, error)
+
+	// CancelHeldOrder cancels an order on hold, releasing its stock, and closes its hold.
+	CancelHeldOrder(ctx context.Context, orderID int64, req *ResolveOrderHoldRequest) (*OrderHoldDTO, error)
+
+	// NotifyOverdue tells staff about the holds that exceeded the SLA since the last run
+	// and returns how many were reported.
+	NotifyOverdue(ctx context.Context) (int, error)
+
+	// StartSLAMonitor reports overdue holds periodically until ctx is cancelled.
+	StartSLAMonitor(ctx context.Context, interval time.Duration)
+}
+
+// OrderHoldConfig holds the order hold SLA settings
+type OrderHoldConfig struct {
+	SLA        time.Duration // How long a hold may stay open before staff are told; 0 disables the SLA
+	AlertEmail string        // Where overdue holds are reported; empty only logs them
+}
+
+// HoldNotifier emails staff about overdue holds
+type HoldNotifier interface {
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
+type orderHoldService struct {
+	orderRepo    domain.OrderRepository
+	holdRepo     domain.OrderHoldRepository
+	orderService OrderService
+	notifier     HoldNotifier
+	cfg          OrderHoldConfig
+	log          *logger.Logger
+}
+
+// NewOrderHoldService creates a new instance of OrderHoldService. notifier may be nil,
+// in which case overdue holds are only logged.
+func NewOrderHoldService(
+	orderRepo domain.OrderRepository,
+	holdRepo domain.OrderHoldRepository,
+	orderService OrderService,
+	notifier HoldNotifier,
+	cfg OrderHoldConfig,
+	log *logger.Logger,
+) OrderHoldService {
+	return &orderHoldService{
+		orderRepo:    orderRepo,
+		holdRepo:     holdRepo,
+		orderService: orderService,
+		notifier:     notifier,
+		cfg:          cfg,
+		log:          log,
+	}
+}
+
+func (s *orderHoldService) PlaceHold(ctx context.Context, orderID int64, req *PlaceOrderHoldRequest) (*OrderHoldDTO, error) {
+	if req.PlacedBy == "" {
+		return nil, errors.ValidationError("placed_by is required")
+	}
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order %d: %w", orderID, err)
+	}
+	if order == nil {
+		return nil, errors.NotFound(fmt.Sprintf("order %d", orderID))
+	}
+
+	reason := domain.HoldReason(strings.ToUpper(strings.TrimSpace(req.Reason)))
+	if !reason.IsValid() {
+		return nil, errors.ValidationError("reason must be FRAUD_REVIEW, PAYMENT_VERIFICATION or ADDRESS_ISSUE")
+	}
+	hold, err := domain.NewOrderHold(order, reason, strings.TrimSpace(req.Note), req.PlacedBy)
+	if err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.holdRepo.Place(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"order_id":  orderID,
+		"hold_id":   hold.ID,
+		"reason":    string(reason),
+		"placed_by": req.PlacedBy,
+	}).Info("Order put on hold")
+	return ToOrderHoldDTO(hold, time.Now(), s.cfg.SLA), nil
+}
+
+func (s *orderHoldService) ListHolds(ctx context.Context, reason string) (*OrderHoldQueueDTO, error) {
+	holdReason := domain.HoldReason(strings.ToUpper(strings.TrimSpace(reason)))
+	if holdReason != "" && !holdReason.IsValid() {
+		return nil, errors.ValidationError("unknown hold reason " + reason)
+	}
+	holds, err := s.holdRepo.FindOpen(ctx, holdReason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order holds: %w", err)
+	}
+
+	now := time.Now()
+	queue := &OrderHoldQueueDTO{
+		GeneratedAt: now,
+		Holds:       make([]*OrderHoldDTO, len(holds)),
+	}
+	if s.cfg.SLA > 0 {
+		queue.SLA = s.cfg.SLA.String()
+	}
+	for i, hold := range holds {
+		queue.Holds[i] = ToOrderHoldDTO(hold, now, s.cfg.SLA)
+		if queue.Holds[i].Overdue {
+			queue.Overdue++
+		}
+	}
+	return queue, nil
+}
+
+func (s *orderHoldService) GetOrderHolds(ctx context.Context, orderID int64) ([]*OrderHoldDTO, error) {
+	holds, err := s.holdRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holds of order %d: %w", orderID, err)
+	}
+	now := time.Now()
+	dtos := make([]*OrderHoldDTO, len(holds))
+	for i, hold := range holds {
+		dtos[i] = ToOrderHoldDTO(hold, now, s.cfg.SLA)
+	}
+	return dtos, nil
+}
+
+func (s *orderHoldService) ReleaseHold(ctx context.Context, orderID int64, req *ResolveOrderHoldRequest) (*OrderHoldDTO, error) {
+	hold, err := s.findOpen(ctx, orderID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := hold.Release(req.ResolvedBy, strings.TrimSpace(req.Note)); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.holdRepo.Release(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"order_id":    orderID,
+		"hold_id":     hold.ID,
+		"status":      string(hold.PreviousStatus),
+		"released_by": req.ResolvedBy,
+	}).Info("Order released from hold")
+	return ToOrderHoldDTO(hold, time.Now(), s.cfg.SLA), nil
+}
+
+func (s *orderHoldService) CancelHeldOrder(ctx context.Context, orderID int64, req *ResolveOrderHoldRequest) (*OrderHoldDTO, error) {
+	hold, err := s.findOpen(ctx, orderID, req)
+	if err != nil {
+		return nil, err
+	}
+	note := strings.TrimSpace(req.Note)
+	if err := hold.Cancel(req.ResolvedBy, note); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+
+	// The hold stays open if the order cannot be cancelled, so it remains in the queue
+	reason := "Cancelled on hold review (" + string(hold.Reason) + ")"
+	if note != "" {
+		reason += ": " + note
+	}
+	if err := s.orderService.CancelOrder(ctx, orderID, reason); err != nil {
+		return nil, err
+	}
+	if err := s.holdRepo.Update(ctx, hold); err != nil {
+		return nil, fmt.Errorf("failed to close hold of cancelled order %d: %w", orderID, err)
+	}
+
+	s.log.WithFields(logger.Fields{
+		"order_id":     orderID,
+		"hold_id":      hold.ID,
+		"cancelled_by": req.ResolvedBy,
+	}).Info("Held order cancelled")
+	return ToOrderHoldDTO(hold, time.Now(), s.cfg.SLA), nil
+}
+
+func (s *orderHoldService) NotifyOverdue(ctx context.Context) (int, error) {
+	if s.cfg.SLA <= 0 {
+		return 0, nil
+	}
+	holds, err := s.holdRepo.FindOverdue(ctx, time.Now().Add(-s.cfg.SLA))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find overdue order holds: %w", err)
+	}
+	if len(holds) == 0 {
+		return 0, nil
+	}
+
+	for _, hold := range holds {
+		s.log.WithFields(logger.Fields{
+			"order_id":  hold.OrderID,
+			"hold_id":   hold.ID,
+			"reason":    string(hold.Reason),
+			"placed_at": hold.PlacedAt,
+		}).Warn("Order hold exceeded its SLA")
+	}
+	// Unsent alerts are retried on the next run rather than marked as notified
+	if !s.emailOverdue(ctx, holds) {
+		return 0, nil
+	}
+
+	notified := 0
+	for _, hold := range holds {
+		hold.MarkSLANotified()
+		if err := s.holdRepo.Update(ctx, hold); err != nil {
+			s.log.WithError(err).WithField("hold_id", hold.ID).Error("Failed to record order hold SLA notification")
+			continue
+		}
+		notified++
+	}
+	return notified, nil
+}
+
+func (s *orderHoldService) StartSLAMonitor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || s.cfg.SLA <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.NotifyOverdue(ctx); err != nil {
+					s.log.WithError(err).Warn("Order hold SLA check failed")
+				}
+			}
+		}
+	}()
+}
+
+// emailOverdue sends one alert listing the overdue holds, reporting whether staff were told.
+// Without an alert address the log is the notification.
+func (s *orderHoldService) emailOverdue(ctx context.Context, holds []*domain.OrderHold) bool {
+	if s.notifier == nil || s.cfg.AlertEmail == "" {
+		return true
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "These orders have been on hold for more than %s:\n\n", s.cfg.SLA)
+	for _, hold := range holds {
+		fmt.Fprintf(&body, "- Order %s (%.2f %s): %s since %s by %s\n",
+			hold.OrderNumber, hold.OrderTotal, hold.CurrencyCode,
+			hold.Reason, hold.PlacedAt.Format(time.RFC1123), hold.PlacedBy)
+	}
+	body.WriteString("\nRelease or cancel them from the order hold queue.")
+
+	subject := fmt.Sprintf("%d order hold(s) exceeded the %s SLA", len(holds), s.cfg.SLA)
+	if err := s.notifier.SendEmail(ctx, s.cfg.AlertEmail, subject, body.String()); err != nil {
+		s.log.WithError(err).Warn("Failed to email overdue order holds")
+		return false
+	}
+	return true
+}
+
+// findOpen retrieves the open hold of an order for a release or cancellation
+func (s *orderHoldService) findOpen(ctx context.Context, orderID int64, req *ResolveOrderHoldRequest) (*domain.OrderHold, error) {
+	if req.ResolvedBy == "" {
+		return nil, errors.ValidationError("resolved_by is required")
+	}
+	hold, err := s.holdRepo.FindOpenByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find hold of order %d: %w", orderID, err)
+	}
+	if hold == nil {
+		return nil, errors.NotFound(fmt.Sprintf("open hold of order %d", orderID))
+	}
+	return hold, nil
+}
@@ -0,0 +1,52 @@
+package application
+
+// MessagesLocale is the locale of the built-in Messages
+const MessagesLocale = "en"
+
+// Messages are the built-in English texts of order documents and emails by key.
+// Message catalogs of other locales translate them by the same keys; keys they
+// leave out fall back to these.
+var Messages = map[string]string{
+	"document.item":           "Item",
+	"document.quantity":       "Qty",
+	"document.unit_price":     "Unit price",
+	"document.total":          "Total",
+	"document.subtotal":       "Subtotal",
+	"document.shipping":       "Shipping",
+	"document.tax":            "Tax",
+	"document.tax_id":         "Tax ID: {id}",
+	"document.sku":            "SKU",
+	"document.serial_numbers": "S/N",
+	"document.page":           "page",
+	"document.page_of":        "of",
+
+	"invoice.title":      "Invoice {number}",
+	"invoice.heading":    "Invoice",
+	"invoice.bill_to":    "Bill to",
+	"invoice.number":     "Invoice number",
+	"invoice.date":       "Invoice date",
+	"invoice.order_date": "Order date",
+
+	"quote.title":        "Quote {number}",
+	"quote.heading":      "Quote",
+	"quote.prepared_for": "Prepared for",
+	"quote.number":       "Quote number",
+	"quote.issued":       "Issued",
+	"quote.valid_until":  "Valid until",
+	"quote.disclaimer":   "Prices, availability and tax are confirmed when the order is placed. This quote is not an invoice and cannot be paid.",
+
+	"packing_slip.title":        "Packing slip {number}",
+	"packing_slip.heading":      "Packing slip",
+	"packing_slip.order":        "Order {number}",
+	"packing_slip.ship_to":      "Ship to",
+	"packing_slip.date":         "Date",
+	"packing_slip.shipment":     "Shipment",
+	"packing_slip.tracking":     "Tracking",
+	"packing_slip.gift_wrap":    "Gift wrap",
+	"packing_slip.message":      "Message",
+	"packing_slip.message_to":   "to {name}",
+	"packing_slip.message_from": "from {name}",
+
+	"payment_link.email.subject": "Complete the payment of order {order}",
+	"payment_link.email.body":    "Pay your order of {amount} here: {url}\nThe link expires on {expires}.",
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// Timeline entry types
+const (
+	TimelineEntryCreated   = "created"
+	TimelineEntrySubmitted = "submitted"
+	TimelineEntryStatus    = "status"
+	TimelineEntryNote      = "note"
+)
+
+// OrderNoteService defines the application service for order notes and the order timeline.
+type OrderNoteService interface {
+	// AddNote adds a note to an order.
+	AddNote(ctx context.Context, orderID int64, req *AddOrderNoteRequest) (*OrderNoteDTO, error)
+
+	// ListNotes lists the notes of an order, optionally only the customer-visible ones.
+	ListNotes(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*OrderNoteDTO, error)
+
+	// GetTimeline returns the order lifecycle events and notes in chronological order.
+	GetTimeline(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*OrderTimelineEntryDTO, error)
+}
+
+type orderNoteService struct {
+	orderRepo domain.OrderRepository
+	noteRepo  domain.OrderNoteRepository
+}
+
+// NewOrderNoteService creates a new instance of OrderNoteService.
+func NewOrderNoteService(orderRepo domain.OrderRepository, noteRepo domain.OrderNoteRepository) OrderNoteService {
+	return &orderNoteService{
+		orderRepo: orderRepo,
+		noteRepo:  noteRepo,
+	}
+}
+
+func (s *orderNoteService) AddNote(ctx context.Context, orderID int64, req *AddOrderNoteRequest) (*OrderNoteDTO, error) {
+	if _, err := s.findOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	note, err := domain.NewOrderNote(orderID, req.Author, req.Body, req.CustomerVisible)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to create order note: %w", err)
+	}
+
+	return ToOrderNoteDTO(note), nil
+}
+
+func (s *orderNoteService) ListNotes(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*OrderNoteDTO, error) {
+	notes, err := s.noteRepo.FindByOrderID(ctx, orderID, customerVisibleOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order notes: %w", err)
+	}
+
+	dtos := make([]*OrderNoteDTO, len(notes))
+	for i, note := range notes {
+		dtos[i] = ToOrderNoteDTO(note)
+	}
+	return dtos, nil
+}
+
+func (s *orderNoteService) GetTimeline(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*OrderTimelineEntryDTO, error) {
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.ListNotes(ctx, orderID, customerVisibleOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := []*OrderTimelineEntryDTO{{
+		Type:        TimelineEntryCreated,
+		Timestamp:   order.CreatedAt,
+		Description: "Order created",
+	}}
+	if order.SubmitDate != nil {
+		timeline = append(timeline, &OrderTimelineEntryDTO{
+			Type:        TimelineEntrySubmitted,
+			Timestamp:   *order.SubmitDate,
+			Description: "Order submitted",
+		})
+	}
+	// Only the current status is known, stamped with the last update
+	if order.Status != domain.OrderStatusPending && order.UpdatedAt.After(order.CreatedAt) {
+		timeline = append(timeline, &OrderTimelineEntryDTO{
+			Type:        TimelineEntryStatus,
+			Timestamp:   order.UpdatedAt,
+			Description: fmt.Sprintf("Order status changed to %s", order.Status),
+		})
+	}
+	for _, note := range notes {
+		timeline = append(timeline, &OrderTimelineEntryDTO{
+			Type:        TimelineEntryNote,
+			Timestamp:   note.CreatedAt,
+			Description: fmt.Sprintf("Note added by %s", note.Author),
+			Note:        note,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.Before(timeline[j].Timestamp)
+	})
+	return timeline, nil
+}
+
+func (s *orderNoteService) findOrder(ctx context.Context, orderID int64) (*domain.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order by ID: %w", err)
+	}
+	if order == nil {
+		return nil, errors.NotFound("order")
+	}
+	return order, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
 	catalogApp "github.com/qhato/ecommerce/internal/catalog/application"
 	inventoryApp "github.com/qhato/ecommerce/internal/inventory/application"
@@ -12,6 +13,10 @@ import (
 	offerDomain "github.com/qhato/ecommerce/internal/offer/domain"
 	"github.com/qhato/ecommerce/internal/order/domain"
 	taxApp "github.com/qhato/ecommerce/internal/tax/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/money"
+	"github.com/qhato/ecommerce/pkg/requestctx"
+	"github.com/qhato/ecommerce/pkg/settings"
 )
 
 // OrderService defines the application service for order-related operations.
@@ -37,6 +42,10 @@ type OrderService interface {
 	// SubmitOrder submits an order for processing.
 	SubmitOrder(ctx context.Context, orderID int64) error
 
+	// SubmitOrderSkippingCartPolicy submits an order an agent placed without the
+	// storefront-only cart policy checks.
+	SubmitOrderSkippingCartPolicy(ctx context.Context, orderID int64) error
+
 	// CancelOrder cancels an existing order.
 	CancelOrder(ctx context.Context, orderID int64, reason string) error
 
@@ -54,6 +63,18 @@ type OrderService interface {
 
 	// GetOrderByOrderNumber retrieves an order by its order number.
 	GetOrderByOrderNumber(ctx context.Context, orderNumber string) (*OrderDTO, error)
+
+	// RevalidateCart re-resolves the current prices, offers and availability of every line
+	// of a customer's cart, updates the cart and returns what changed.
+	RevalidateCart(ctx context.Context, customerID, orderID int64, couponCode *string) (*CartRevalidationDTO, error)
+
+	// PriceEditLine prices a line an order edit adds at the SKU's current price, with the
+	// item-level offers that apply automatically and its tax.
+	PriceEditLine(ctx context.Context, order *domain.Order, line *domain.OrderEditLine) error
+
+	// ApplyOrderEdit applies the lines of a previewed order edit to the order, reserving the
+	// stock of added and increased lines and releasing that of removed and reduced ones.
+	ApplyOrderEdit(ctx context.Context, order *domain.Order, edit *domain.OrderEdit) error
 }
 
 // CreateOrderCommand is a command to create a new order.
@@ -65,6 +86,8 @@ type CreateOrderCommand struct {
 	LocaleCode   string
 	IsPreview    bool
 	TaxOverride  bool
+	Channel      domain.OrderChannel // Required: WEB, MOBILE_APP, MARKETPLACE or MOTO
+	Attributes   map[string]string   // Stored with the order, e.g. the sales channel it came from
 }
 
 // AddItemToOrderCommand is a command to add an item to an order.
@@ -76,6 +99,9 @@ type AddItemToOrderCommand struct {
 	GiftWrapItemID *int64
 	ParentOrderItemID *int64
 	PersonalMessageID *int64
+	OrderItemType     string // Defaults to DEFAULT
+	PriceOverride     *float64 // Unit price set by an agent instead of the SKU's current price
+	SkipCartPolicy    bool     // Agents may waive the storefront-only cart policy
 	// Additional fields for OrderItem creation can be added here.
 }
 
@@ -100,9 +126,16 @@ type orderService struct {
 	fulfillmentGroupRepo    domain.FulfillmentGroupRepository
 	offerService            offerApp.OfferService
 	inventoryService        inventoryApp.InventoryService
+	atpService              inventoryApp.ATPService
 	productService          catalogApp.ProductService
 	skuService              catalogApp.SkuService
 	taxService              taxApp.TaxService
+	taxMode                 domain.TaxMode
+	taxDetailRepo           domain.OrderTaxDetailRepository
+	cartValidator           CartValidator
+	deallocations           InventoryDeallocationService
+	flashAllocation         inventoryApp.FlashAllocationService
+	siteSettings            *settings.Settings
 }
 
 // NewOrderService creates a new instance of OrderService.
@@ -115,9 +148,16 @@ func NewOrderService(
 	fulfillmentGroupRepo domain.FulfillmentGroupRepository,
 	offerService offerApp.OfferService,
 	inventoryService inventoryApp.InventoryService,
+	atpService inventoryApp.ATPService,
 	productService catalogApp.ProductService,
 	skuService catalogApp.SkuService,
 	taxService taxApp.TaxService,
+	taxMode domain.TaxMode, // Deferred estimates tax in the cart and calculates it once at submission
+	taxDetailRepo domain.OrderTaxDetailRepository,
+	cartValidator CartValidator, // Optional, nil disables cart policy checks
+	deallocations InventoryDeallocationService,
+	flashAllocation inventoryApp.FlashAllocationService, // Optional, nil reserves every SKU from the inventory levels
+	siteSettings *settings.Settings, // Optional, nil leaves orders without a number unnumbered
 ) OrderService {
 	return &orderService{
 		orderRepo:               orderRepo,
@@ -128,16 +168,45 @@ func NewOrderService(
 		fulfillmentGroupRepo:    fulfillmentGroupRepo,
 		offerService:            offerService,
 		inventoryService:        inventoryService,
+		atpService:              atpService,
 		productService:          productService,
 		skuService:              skuService,
 		taxService:              taxService,
+		taxMode:                 taxMode,
+		taxDetailRepo:           taxDetailRepo,
+		cartValidator:           cartValidator,
+		deallocations:           deallocations,
+		flashAllocation:         flashAllocation,
+		siteSettings:            siteSettings,
 	}
 }
 
 func (s *orderService) CreateOrder(ctx context.Context, cmd *CreateOrderCommand) (*OrderDTO, error) {
+	// Default customer, currency and locale from the storefront request context
+	if rc, ok := requestctx.FromContext(ctx); ok {
+		if cmd.CustomerID == 0 {
+			cmd.CustomerID = rc.CustomerID
+		}
+		if cmd.CurrencyCode == "" {
+			cmd.CurrencyCode = rc.Currency
+		}
+		if cmd.LocaleCode == "" {
+			cmd.LocaleCode = rc.Locale
+		}
+	}
+
+	if cmd.Channel == "" {
+		return nil, errors.ValidationError("order channel is required")
+	}
+	if !cmd.Channel.IsValid() {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown order channel %q", cmd.Channel))
+	}
+
 	order := domain.NewOrder(cmd.CustomerID, cmd.EmailAddress, cmd.Name, cmd.CurrencyCode, cmd.LocaleCode)
+	order.Channel = cmd.Channel
 	order.IsPreview = cmd.IsPreview
 	order.TaxOverride = cmd.TaxOverride
+	order.Attributes = cmd.Attributes
 
 	err := s.orderRepo.Create(ctx, order)
 	if err != nil {
@@ -169,8 +238,14 @@ func (s *orderService) HandleGetOrderByID(ctx context.Context, id int64) (*Order
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch fulfillment groups for order %d: %w", id, err)
 	}
+	taxDetails, err := s.taxDetailRepo.FindByOrderID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tax details for order %d: %w", id, err)
+	}
 
-	return toOrderDTOWithRelations(order, items, orderAdjustments, fulfillmentGroups), nil
+	dto := toOrderDTOWithRelations(order, items, orderAdjustments, fulfillmentGroups)
+	dto.TaxDetails = ToOrderTaxDetailDTOs(taxDetails)
+	return dto, nil
 }
 
 func (s *orderService) UpdateOrderStatus(ctx context.Context, orderID int64, status domain.OrderStatus) error {
@@ -181,6 +256,10 @@ func (s *orderService) UpdateOrderStatus(ctx context.Context, orderID int64, sta
 	if order == nil {
 		return fmt.Errorf("order with ID %d not found for status update", orderID)
 	}
+	// Holds pause fulfillment until they are released or the order is cancelled from the hold queue
+	if order.Status == domain.OrderStatusOnHold || status == domain.OrderStatusOnHold {
+		return errors.Conflict("orders are put on and taken off hold through order holds")
+	}
 
 	order.UpdateStatus(status)
 	err = s.orderRepo.Update(ctx, order)
@@ -208,24 +287,63 @@ func (s *orderService) AddItemToOrder(ctx context.Context, orderID int64, cmd *A
 		return nil, fmt.Errorf("SKU with ID %d has no associated default product", cmd.SKUID)
 	}
 
-	// 3. Allocate inventory
-	skuAvailability, err := s.inventoryService.GetInventoryLevelBySKUID(ctx, strconv.FormatInt(cmd.SKUID, 10)) // Use new method
-	if err != nil || skuAvailability == nil {
-		return nil, fmt.Errorf("failed to get SKU availability for ID %d: %w", cmd.SKUID, err)
+	// Add-ons and bundle components hang off an item of the same order
+	quantityPerParent := 0
+	if cmd.ParentOrderItemID != nil {
+		parent, err := s.orderItemRepo.FindByID(ctx, *cmd.ParentOrderItemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find parent order item %d: %w", *cmd.ParentOrderItemID, err)
+		}
+		if parent == nil || parent.OrderID != orderID {
+			return nil, errors.ValidationError(fmt.Sprintf("parent order item %d not found in order %d", *cmd.ParentOrderItemID, orderID))
+		}
+		if quantityPerParent, err = childQuantityPerParent(cmd.Quantity, parent); err != nil {
+			return nil, err
+		}
 	}
-	if skuAvailability.QuantityOnHand < cmd.Quantity { // Use QuantityOnHand
-		return nil, fmt.Errorf("not enough quantity on hand for SKU %d", cmd.SKUID)
+
+	// Check the cart policy with the new line included before reserving stock
+	if s.cartValidator != nil && !cmd.SkipCartPolicy {
+		if cmd.CategoryID == nil {
+			productDTO, err := s.productService.GetProductByID(ctx, productID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get product details for ID %d: %w", productID, err)
+			}
+			if productDTO != nil {
+				cmd.CategoryID = productDTO.DefaultCategoryID
+			}
+		}
+		newLine := &domain.OrderItem{SKUID: cmd.SKUID, Quantity: cmd.Quantity, CategoryID: cmd.CategoryID}
+		if err := s.validateCart(ctx, orderID, newLine); err != nil {
+			return nil, err
+		}
 	}
 
-	// Update inventory quantities
-	updatedLevel, err := s.inventoryService.UpdateInventoryQuantities(
-		ctx,
-		skuAvailability.ID,
-		skuAvailability.QuantityOnHand-cmd.Quantity,
-		skuAvailability.QuantityReserved+cmd.Quantity,
-	)
+	// 3. Reserve inventory; SKUs in a flash sale take tokens instead of locking their levels
+	flashReserved, err := s.reserveFlash(ctx, cmd.SKUID, cmd.Quantity)
 	if err != nil {
-		return nil, fmt.Errorf("failed to allocate inventory for SKU %d: %w", cmd.SKUID, err)
+		return nil, err
+	}
+	var updatedLevel *inventoryApp.InventoryLevelDTO
+	if !flashReserved {
+		if err := s.checkAvailableToPromise(ctx, cmd.SKUID, cmd.Quantity); err != nil {
+			return nil, err
+		}
+		skuAvailability, err := s.inventoryService.GetInventoryLevelBySKUID(ctx, strconv.FormatInt(cmd.SKUID, 10)) // Use new method
+		if err != nil || skuAvailability == nil {
+			return nil, fmt.Errorf("failed to get SKU availability for ID %d: %w", cmd.SKUID, err)
+		}
+
+		// Reserved stock stays on hand until it ships
+		updatedLevel, err = s.inventoryService.UpdateInventoryQuantities(
+			ctx,
+			skuAvailability.ID,
+			skuAvailability.QuantityOnHand,
+			skuAvailability.QuantityReserved+cmd.Quantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate inventory for SKU %d: %w", cmd.SKUID, err)
+		}
 	}
 
 	// 4. Create OrderItem domain entity
@@ -243,29 +361,37 @@ func (s *orderService) AddItemToOrder(ctx context.Context, orderID int64, cmd *A
 		return nil, fmt.Errorf("failed to create order item domain entity: %w", err)
 	}
 
+	if cmd.PriceOverride != nil {
+		if err := item.OverridePrice(*cmd.PriceOverride); err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+	}
 	item.CategoryID = cmd.CategoryID
 	item.GiftWrapItemID = cmd.GiftWrapItemID
 	item.ParentOrderItemID = cmd.ParentOrderItemID
+	item.QuantityPerParent = quantityPerParent
 	item.PersonalMessageID = cmd.PersonalMessageID
+	if cmd.OrderItemType != "" {
+		item.OrderItemType = cmd.OrderItemType
+	}
 
 	// Calculate initial tax based on TaxService (simplified)
-	taxAmount := 0.0
-	if cmd.TaxCategory != "" {
-		taxAmount, err = s.taxService.CalculateTaxForItem(ctx, orderID, item.TotalPrice, cmd.TaxCategory)
-		if err != nil {
-			return nil, fmt.Errorf("failed to calculate tax for item: %w", err)
-		}
+	if err := s.applyItemTax(ctx, orderID, item); err != nil {
+		return nil, fmt.Errorf("failed to calculate tax for item: %w", err)
 	}
-	item.SetTaxAmount(taxAmount)
 
 	// 5. Save OrderItem
 	err = s.orderItemRepo.Save(ctx, item)
+	if err != nil && flashReserved {
+		s.releaseFlash(ctx, cmd.SKUID, cmd.Quantity)
+		return nil, fmt.Errorf("failed to save order item: %w", err)
+	}
 	if err != nil {
 		// Attempt to deallocate inventory if item save fails
 		_, deallocErr := s.inventoryService.UpdateInventoryQuantities(
 			ctx,
 			updatedLevel.ID, // Use the ID from the updated level
-			updatedLevel.QuantityOnHand+cmd.Quantity,
+			updatedLevel.QuantityOnHand,
 			updatedLevel.QuantityReserved-cmd.Quantity,
 		)
 		if deallocErr != nil {
@@ -275,18 +401,11 @@ func (s *orderService) AddItemToOrder(ctx context.Context, orderID int64, cmd *A
 	}
 
 	// 6. Recalculate order totals
-	// The order totals will be recalculated by ApplyOffersToOrder or a dedicated recalculate method
-	// For now, we update the order's top-level totals after each item add/update/remove
 	order, err := s.orderRepo.FindByID(ctx, orderID) // Re-fetch order to ensure consistency
 	if err != nil {
 		return nil, fmt.Errorf("failed to re-fetch order to recalculate totals: %w", err)
 	}
-	order.OrderSubtotal += item.TotalPrice
-	order.TotalTax += item.TaxAmount
-	order.OrderTotal = order.OrderSubtotal + order.TotalTax + order.TotalShipping // Assuming shipping is calculated elsewhere
-
-	err = s.orderRepo.Update(ctx, order)
-	if err != nil {
+	if err := s.recalculateTotals(ctx, order); err != nil {
 		return nil, fmt.Errorf("failed to update order totals: %w", err)
 	}
 
@@ -301,6 +420,9 @@ func (s *orderService) UpdateOrderItemQuantity(ctx context.Context, orderItemID
 	if item == nil {
 		return nil, fmt.Errorf("order item with ID %d not found", orderItemID)
 	}
+	if item.IsFreeGift() && newQuantity != item.Quantity {
+		return nil, errors.ValidationError("the quantity of a free gift is set by its offer")
+	}
 
 	order, err := s.orderRepo.FindByID(ctx, item.OrderID)
 	if err != nil {
@@ -310,52 +432,34 @@ func (s *orderService) UpdateOrderItemQuantity(ctx context.Context, orderItemID
 		return nil, fmt.Errorf("order with ID %d not found for item update", item.OrderID)
 	}
 
-	oldQuantity := item.Quantity
-	quantityDiff := newQuantity - oldQuantity
-
-	if quantityDiff != 0 {
-		skuAvailability, err := s.inventoryService.GetInventoryLevelBySKUID(ctx, strconv.FormatInt(item.SKUID, 10))
-		if err != nil || skuAvailability == nil {
-			return nil, fmt.Errorf("failed to get SKU availability for ID %d: %w", item.SKUID, err)
+	// A child set directly keeps the new quantity per unit of its parent
+	if item.ParentOrderItemID != nil {
+		parent, err := s.orderItemRepo.FindByID(ctx, *item.ParentOrderItemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find parent order item %d: %w", *item.ParentOrderItemID, err)
 		}
-
-		if quantityDiff > 0 { // Increasing quantity, need to allocate more
-			if skuAvailability.QuantityOnHand < quantityDiff {
-				return nil, fmt.Errorf("not enough quantity on hand for SKU %d to increase by %d", item.SKUID, quantityDiff)
+		if parent != nil {
+			if item.QuantityPerParent, err = childQuantityPerParent(newQuantity, parent); err != nil {
+				return nil, err
 			}
-			_, err = s.inventoryService.UpdateInventoryQuantities(
-				ctx,
-				skuAvailability.ID,
-				skuAvailability.QuantityOnHand-quantityDiff,
-				skuAvailability.QuantityReserved+quantityDiff,
-			)
-		} else { // Decreasing quantity, need to deallocate
-			_, err = s.inventoryService.UpdateInventoryQuantities(
-				ctx,
-				skuAvailability.ID,
-				skuAvailability.QuantityOnHand+quantityDiff, // This should be QuantityOnHand + abs(quantityDiff)
-				skuAvailability.QuantityReserved-quantityDiff, // This should be QuantityReserved - abs(quantityDiff)
-			)
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to adjust inventory for SKU %d: %w", item.SKUID, err)
 		}
 	}
 
+	quantityDiff := newQuantity - item.Quantity
+
+	if err := s.adjustReservation(ctx, item.SKUID, quantityDiff); err != nil {
+		return nil, err
+	}
+
 	err = item.UpdateQuantity(newQuantity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update order item quantity: %w", err)
 	}
 
 	// Recalculate tax for the item
-	taxAmount := 0.0
-	if item.TaxCategory != "" {
-		taxAmount, err = s.taxService.CalculateTaxForItem(ctx, order.ID, item.TotalPrice, item.TaxCategory)
-		if err != nil {
-			return nil, fmt.Errorf("failed to recalculate tax for item: %w", err)
-		}
+	if err := s.applyItemTax(ctx, order.ID, item); err != nil {
+		return nil, fmt.Errorf("failed to recalculate tax for item: %w", err)
 	}
-	item.SetTaxAmount(taxAmount)
 
 	err = s.orderItemRepo.Save(ctx, item)
 	if err != nil {
@@ -363,18 +467,40 @@ func (s *orderService) UpdateOrderItemQuantity(ctx context.Context, orderItemID
 	}
 
 	// Recalculate order totals
-	order.OrderSubtotal += (item.TotalPrice - (item.Price * float64(oldQuantity))) // Adjust subtotal by change
-	order.TotalTax += (item.TaxAmount - (taxAmount * float64(oldQuantity)))         // Adjust total tax
-	order.OrderTotal = order.OrderSubtotal + order.TotalTax + order.TotalShipping
-
-	err = s.orderRepo.Update(ctx, order)
+	err = s.recalculateTotals(ctx, order)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update order totals after item quantity update: %w", err)
 	}
 
+	// Children keep their quantity per unit of the parent, so a gift wrap still covers every unit
+	if quantityDiff != 0 {
+		items, err := s.orderItemRepo.FindByOrderID(ctx, item.OrderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch child items of item %d: %w", item.ID, err)
+		}
+		for _, child := range domain.ChildItemsOf(items, item.ID) {
+			childQuantity := child.QuantityForParent(newQuantity)
+			if childQuantity == child.Quantity {
+				continue
+			}
+			if _, err := s.UpdateOrderItemQuantity(ctx, child.ID, childQuantity); err != nil {
+				return nil, fmt.Errorf("failed to update quantity of child item %d: %w", child.ID, err)
+			}
+		}
+	}
+
 	return ToOrderItemDTO(item), nil
 }
 
+// childQuantityPerParent returns the units of a child item per unit of its
+// parent; a child must cover every unit of the parent alike
+func childQuantityPerParent(quantity int, parent *domain.OrderItem) (int, error) {
+	if parent.Quantity <= 0 || quantity%parent.Quantity != 0 {
+		return 0, errors.ValidationError(fmt.Sprintf("quantity %d of a child item is not a multiple of the quantity %d of its parent item %d", quantity, parent.Quantity, parent.ID))
+	}
+	return quantity / parent.Quantity, nil
+}
+
 func (s *orderService) RemoveOrderItem(ctx context.Context, orderItemID int64) error {
 	item, err := s.orderItemRepo.FindByID(ctx, orderItemID)
 	if err != nil {
@@ -384,6 +510,17 @@ func (s *orderService) RemoveOrderItem(ctx context.Context, orderItemID int64) e
 		return fmt.Errorf("order item with ID %d not found", orderItemID)
 	}
 
+	// Children go with their parent, gift wraps included
+	items, err := s.orderItemRepo.FindByOrderID(ctx, item.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch child items of item %d: %w", orderItemID, err)
+	}
+	for _, child := range domain.ChildItemsOf(items, orderItemID) {
+		if err := s.RemoveOrderItem(ctx, child.ID); err != nil {
+			return fmt.Errorf("failed to remove child item %d of item %d: %w", child.ID, orderItemID, err)
+		}
+	}
+
 	order, err := s.orderRepo.FindByID(ctx, item.OrderID)
 	if err != nil {
 		return fmt.Errorf("failed to find order by ID for item removal: %w", err)
@@ -393,18 +530,20 @@ func (s *orderService) RemoveOrderItem(ctx context.Context, orderItemID int64) e
 	}
 
 	// Deallocate inventory
-	skuAvailability, err := s.inventoryService.GetInventoryLevelBySKUID(ctx, strconv.FormatInt(item.SKUID, 10))
-	if err != nil || skuAvailability == nil {
-		return fmt.Errorf("failed to get SKU availability for ID %d: %w", item.SKUID, err)
-	}
-	_, err = s.inventoryService.UpdateInventoryQuantities(
-		ctx,
-		skuAvailability.ID,
-		skuAvailability.QuantityOnHand+item.Quantity,
-		skuAvailability.QuantityReserved-item.Quantity,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to deallocate inventory for SKU %d: %w", item.SKUID, err)
+	if !s.releaseFlash(ctx, item.SKUID, item.Quantity) {
+		skuAvailability, err := s.inventoryService.GetInventoryLevelBySKUID(ctx, strconv.FormatInt(item.SKUID, 10))
+		if err != nil || skuAvailability == nil {
+			return fmt.Errorf("failed to get SKU availability for ID %d: %w", item.SKUID, err)
+		}
+		_, err = s.inventoryService.UpdateInventoryQuantities(
+			ctx,
+			skuAvailability.ID,
+			skuAvailability.QuantityOnHand,
+			skuAvailability.QuantityReserved-item.Quantity,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to deallocate inventory for SKU %d: %w", item.SKUID, err)
+		}
 	}
 
 	// Delete item and associated entities
@@ -421,12 +560,37 @@ func (s *orderService) RemoveOrderItem(ctx context.Context, orderItemID int64) e
 		return fmt.Errorf("failed to delete order item: %w", err)
 	}
 
-	// Recalculate order totals
-	order.OrderSubtotal -= item.TotalPrice
-	order.TotalTax -= item.TaxAmount
-	order.OrderTotal = order.OrderSubtotal + order.TotalTax + order.TotalShipping
+	// A parent no longer wrapped must not point at the removed gift wrap
+	if item.ParentOrderItemID != nil {
+		for _, parent := range items {
+			if parent.ID == *item.ParentOrderItemID && parent.GiftWrapItemID != nil && *parent.GiftWrapItemID == orderItemID {
+				parent.GiftWrapItemID = nil
+				if err := s.orderItemRepo.Save(ctx, parent); err != nil {
+					return fmt.Errorf("failed to clear gift wrap of item %d: %w", parent.ID, err)
+				}
+			}
+		}
+	}
 
-	err = s.orderRepo.Update(ctx, order)
+	// Free gifts are never sold alone, so they go with the last paid item
+	if !item.IsFreeGift() {
+		var remaining []*domain.OrderItem
+		for _, other := range items {
+			if other.ID != orderItemID && (other.ParentOrderItemID == nil || *other.ParentOrderItemID != orderItemID) {
+				remaining = append(remaining, other)
+			}
+		}
+		if len(domain.PaidItems(remaining)) == 0 {
+			for _, gift := range remaining {
+				if err := s.RemoveOrderItem(ctx, gift.ID); err != nil {
+					return fmt.Errorf("failed to remove free gift item %d: %w", gift.ID, err)
+				}
+			}
+		}
+	}
+
+	// Recalculate order totals
+	err = s.recalculateTotals(ctx, order)
 	if err != nil {
 		return fmt.Errorf("failed to update order totals after item removal: %w", err)
 	}
@@ -435,6 +599,14 @@ func (s *orderService) RemoveOrderItem(ctx context.Context, orderItemID int64) e
 }
 
 func (s *orderService) SubmitOrder(ctx context.Context, orderID int64) error {
+	return s.submitOrder(ctx, orderID, true)
+}
+
+func (s *orderService) SubmitOrderSkippingCartPolicy(ctx context.Context, orderID int64) error {
+	return s.submitOrder(ctx, orderID, false)
+}
+
+func (s *orderService) submitOrder(ctx context.Context, orderID int64, checkCartPolicy bool) error {
 	order, err := s.orderRepo.FindByID(ctx, orderID)
 	if err != nil {
 		return fmt.Errorf("failed to find order by ID for submission: %w", err)
@@ -444,12 +616,40 @@ func (s *orderService) SubmitOrder(ctx context.Context, orderID int64) error {
 	}
 
 	// In a real system, would check if items exist here. Assume application layer handles this.
-	// We also assume tax calculation is final before submission.
+
+	// A free gift is never purchased alone at zero price
+	items, err := s.orderItemRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order items for order %d: %w", orderID, err)
+	}
+	if len(items) > 0 && len(domain.PaidItems(items)) == 0 {
+		return errors.ValidationError("an order of free gifts only cannot be placed")
+	}
+
+	// Re-check the cart policy now that the destination is known
+	if s.cartValidator != nil && checkCartPolicy {
+		if err := s.validateCart(ctx, orderID, nil); err != nil {
+			return err
+		}
+	}
+
+	// The tax of the order is calculated once more before it is placed: estimated tax
+	// is replaced by the authoritative amounts, shipping is taxed and the breakdown
+	// by jurisdiction is recorded. Tax set by an agent is kept as it is.
+	if !order.TaxOverride {
+		if err := s.finalizeTax(ctx, order); err != nil {
+			return err
+		}
+	}
 
 	err = order.Submit()
 	if err != nil {
 		return fmt.Errorf("failed to submit order: %w", err)
 	}
+	// Orders are numbered at submission, with the prefix set by the admin
+	if order.OrderNumber == "" && s.siteSettings != nil {
+		order.OrderNumber = s.siteSettings.String(settings.OrderNumberPrefix) + strconv.FormatInt(order.ID, 10)
+	}
 
 	err = s.orderRepo.Update(ctx, order)
 	if err != nil {
@@ -458,6 +658,207 @@ func (s *orderService) SubmitOrder(ctx context.Context, orderID int64) error {
 	return nil
 }
 
+// applyItemTax sets the tax of an item: calculated by the tax provider, or estimated
+// from the cached rates when tax is deferred to submission
+func (s *orderService) applyItemTax(ctx context.Context, orderID int64, item *domain.OrderItem) error {
+	taxAmount := 0.0
+	if item.TaxCategory != "" {
+		var err error
+		if s.taxMode == domain.TaxModeDeferred {
+			taxAmount, err = s.taxService.EstimateTaxForItem(ctx, item.TotalPrice, item.TaxCategory)
+		} else {
+			taxAmount, err = s.taxService.CalculateTaxForItem(ctx, orderID, item.TotalPrice, item.TaxCategory)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	item.SetTaxAmount(taxAmount)
+	return nil
+}
+
+// shippingTaxCategory is the tax category of the taxable shipping of a fulfillment group
+const shippingTaxCategory = "SHIPPING"
+
+// finalizeTax calculates the tax of every item and taxable shipping of an order in
+// a single provider call, reconciles it with the tax the cart carried and records
+// its breakdown by jurisdiction
+func (s *orderService) finalizeTax(ctx context.Context, order *domain.Order) error {
+	items, err := s.orderItemRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order items for order %d: %w", order.ID, err)
+	}
+	adjustments, err := s.orderAdjustmentRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order adjustments for order %d: %w", order.ID, err)
+	}
+	groups, err := s.fulfillmentGroupRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch fulfillment groups for order %d: %w", order.ID, err)
+	}
+
+	req := &taxApp.OrderTaxRequest{
+		OrderID:      order.ID,
+		CustomerID:   order.CustomerID,
+		CurrencyCode: order.CurrencyCode,
+		Lines:        make([]taxApp.OrderTaxLine, 0, len(items)+len(groups)),
+	}
+	for _, item := range items {
+		req.Lines = append(req.Lines, taxApp.OrderTaxLine{ItemID: item.ID, Amount: item.TotalPrice, TaxCategory: item.TaxCategory})
+	}
+	for _, group := range groups {
+		if group.ShippingPriceTaxable && group.ShippingPrice > 0 {
+			req.Lines = append(req.Lines, taxApp.OrderTaxLine{
+				FulfillmentGroupID: group.ID,
+				Amount:             group.ShippingPrice,
+				TaxCategory:        shippingTaxCategory,
+			})
+		}
+	}
+	result, err := s.taxService.CalculateOrderTax(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to calculate tax for order %d: %w", order.ID, err)
+	}
+
+	order.ReconcileTax(items, adjustments, groups, result.Lines, result.Groups, result.Provider)
+	for _, item := range items {
+		if err := s.orderItemRepo.Save(ctx, item); err != nil {
+			return fmt.Errorf("failed to save tax of order item %d: %w", item.ID, err)
+		}
+	}
+	for _, group := range groups {
+		if err := s.fulfillmentGroupRepo.Save(ctx, group); err != nil {
+			return fmt.Errorf("failed to save tax of fulfillment group %d: %w", group.ID, err)
+		}
+	}
+	if err := s.taxDetailRepo.ReplaceForOrder(ctx, order.ID, toOrderTaxDetails(order, result)); err != nil {
+		return fmt.Errorf("failed to record tax details of order %d: %w", order.ID, err)
+	}
+	return nil
+}
+
+// toOrderTaxDetails converts the jurisdiction breakdown of a tax calculation into
+// the tax details recorded for an order
+func toOrderTaxDetails(order *domain.Order, result *taxApp.OrderTaxResult) []*domain.OrderTaxDetail {
+	now := time.Now()
+	details := make([]*domain.OrderTaxDetail, 0, len(result.Details))
+	for _, line := range result.Details {
+		detail := &domain.OrderTaxDetail{
+			OrderID:          order.ID,
+			JurisdictionName: line.JurisdictionName,
+			Country:          line.Country,
+			Region:           line.Region,
+			TaxName:          line.TaxName,
+			Rate:             line.Rate,
+			TaxableAmount:    line.TaxableAmount,
+			Amount:           line.Amount,
+			CurrencyCode:     order.CurrencyCode,
+			Provider:         result.Provider,
+			CalculatedAt:     now,
+		}
+		if line.FulfillmentGroupID != 0 {
+			groupID := line.FulfillmentGroupID
+			detail.FulfillmentGroupID = &groupID
+		} else {
+			itemID := line.ItemID
+			detail.OrderItemID = &itemID
+		}
+		details = append(details, detail)
+	}
+	return details
+}
+
+// checkAvailableToPromise rejects a quantity the SKU's available-to-promise stock cannot cover.
+func (s *orderService) checkAvailableToPromise(ctx context.Context, skuID int64, quantity int) error {
+	atps, err := s.atpService.GetAvailableToPromise(ctx, []string{strconv.FormatInt(skuID, 10)})
+	if err != nil {
+		return fmt.Errorf("failed to get available-to-promise for SKU %d: %w", skuID, err)
+	}
+	if atp := atps[0]; !atp.AllowBackorder && atp.Available < quantity {
+		return errors.InsufficientStock(strconv.FormatInt(skuID, 10), quantity, atp.Available)
+	}
+	return nil
+}
+
+// reserveFlash takes quantity from the flash allocation of a SKU, reporting whether the SKU
+// is in a flash sale; other SKUs are reserved from their inventory levels.
+func (s *orderService) reserveFlash(ctx context.Context, skuID int64, quantity int) (bool, error) {
+	if s.flashAllocation == nil {
+		return false, nil
+	}
+	return s.flashAllocation.Reserve(ctx, strconv.FormatInt(skuID, 10), quantity)
+}
+
+// releaseFlash gives quantity back to the flash allocation of a SKU, reporting whether the
+// SKU is still in a flash sale; otherwise the stock is released from its inventory levels.
+func (s *orderService) releaseFlash(ctx context.Context, skuID int64, quantity int) bool {
+	if s.flashAllocation == nil {
+		return false
+	}
+	return s.flashAllocation.Release(ctx, strconv.FormatInt(skuID, 10), quantity)
+}
+
+// adjustReservation reserves more of a SKU for a positive difference in quantity, once
+// its available-to-promise stock covers it, and releases the stock of a negative one.
+func (s *orderService) adjustReservation(ctx context.Context, skuID int64, quantityDiff int) error {
+	if quantityDiff == 0 {
+		return nil
+	}
+	flashHandled := false
+	if quantityDiff > 0 {
+		var err error
+		if flashHandled, err = s.reserveFlash(ctx, skuID, quantityDiff); err != nil {
+			return err
+		}
+	} else {
+		flashHandled = s.releaseFlash(ctx, skuID, -quantityDiff)
+	}
+	if flashHandled {
+		return nil
+	}
+
+	if quantityDiff > 0 {
+		if err := s.checkAvailableToPromise(ctx, skuID, quantityDiff); err != nil {
+			return err
+		}
+	}
+	skuAvailability, err := s.inventoryService.GetInventoryLevelBySKUID(ctx, strconv.FormatInt(skuID, 10))
+	if err != nil || skuAvailability == nil {
+		return fmt.Errorf("failed to get SKU availability for ID %d: %w", skuID, err)
+	}
+	_, err = s.inventoryService.UpdateInventoryQuantities(
+		ctx,
+		skuAvailability.ID,
+		skuAvailability.QuantityOnHand,
+		skuAvailability.QuantityReserved+quantityDiff,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to adjust inventory for SKU %d: %w", skuID, err)
+	}
+	return nil
+}
+
+// validateCart evaluates the cart policy for an order's current items plus an optional new line.
+func (s *orderService) validateCart(ctx context.Context, orderID int64, newLine *domain.OrderItem) error {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to find order by ID for cart validation: %w", err)
+	}
+	if order == nil {
+		return fmt.Errorf("order with ID %d not found", orderID)
+	}
+
+	items, err := s.orderItemRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order items for order %d: %w", orderID, err)
+	}
+	if newLine != nil {
+		items = append(items, newLine)
+	}
+
+	return s.cartValidator.Validate(ctx, order, items)
+}
+
 func (s *orderService) CancelOrder(ctx context.Context, orderID int64, reason string) error {
 	order, err := s.orderRepo.FindByID(ctx, orderID)
 	if err != nil {
@@ -471,28 +872,14 @@ func (s *orderService) CancelOrder(ctx context.Context, orderID int64, reason st
 		return fmt.Errorf("order with ID %d is not cancellable in status %s", orderID, order.Status)
 	}
 
-	// Deallocate inventory for all items in the order
+	// Queue the release of the order's reserved inventory before cancelling, so
+	// a cancelled order never loses track of its stock
 	items, err := s.orderItemRepo.FindByOrderID(ctx, orderID)
 	if err != nil {
 		return fmt.Errorf("failed to get order items for deallocation: %w", err)
 	}
-
-	for _, item := range items {
-		skuAvailability, err := s.inventoryService.GetInventoryLevelBySKUID(ctx, strconv.FormatInt(item.SKUID, 10))
-		if err != nil || skuAvailability == nil {
-			fmt.Printf("warning: failed to get SKU availability for SKU %d (order %d): %v\n", item.SKUID, orderID, err)
-			continue
-		}
-		_, deallocErr := s.inventoryService.UpdateInventoryQuantities(
-			ctx,
-			skuAvailability.ID,
-			skuAvailability.QuantityOnHand+item.Quantity,
-			skuAvailability.QuantityReserved-item.Quantity,
-		)
-		if deallocErr != nil {
-			// Log the error but continue with order cancellation to avoid blocking
-			fmt.Printf("warning: failed to deallocate inventory for SKU %d (order %d): %v\n", item.SKUID, orderID, deallocErr)
-		}
+	if err := s.deallocations.Enqueue(ctx, orderID, items); err != nil {
+		return err
 	}
 
 	order.Cancel()
@@ -500,10 +887,17 @@ func (s *orderService) CancelOrder(ctx context.Context, orderID int64, reason st
 	if err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
+
+	// Failed releases are retried in the background and never block the cancellation
+	s.deallocations.ProcessOrder(ctx, orderID)
 	return nil
 }
 
 func (s *orderService) ApplyOffersToOrder(ctx context.Context, orderID int64, customerID int64, couponCode *string) (*OrderDTO, error) {
+	if customerID == 0 {
+		customerID = requestctx.Pricing(ctx).CustomerID
+	}
+
 	// Load the full order graph
 	order, err := s.orderRepo.FindByID(ctx, orderID)
 	if err != nil {
@@ -518,65 +912,39 @@ func (s *orderService) ApplyOffersToOrder(ctx context.Context, orderID int64, cu
 		return nil, fmt.Errorf("failed to fetch order items for order %d: %w", orderID, err)
 	}
 
-	// Clear existing adjustments before reapplying
-	err = s.orderAdjustmentRepo.DeleteByOrderID(ctx, orderID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to clear existing order adjustments: %w", err)
-	}
+	// Reset item prices for recalculation; existing adjustments are replaced when the result is saved
 	for _, item := range items {
-		err = s.orderItemAdjustmentRepo.DeleteByOrderItemID(ctx, item.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to clear existing item adjustments for item %d: %w", item.ID, err)
-		}
-		// Reset item prices for recalculation
 		item.UpdatePrices(item.RetailPrice, item.SalePrice, item.RetailPrice) // Use original retail for base
-		err = s.orderItemRepo.Save(ctx, item)
-		if err != nil {
-			return nil, fmt.Errorf("failed to reset item prices for item %d: %w", item.ID, err)
-		}
 	}
 
-	// 1. Get all active offers
-	activeOffersDTO, err := s.offerService.GetActiveOffers(ctx)
+	// 1. Get the offers the customer can use, the coupon's included
+	applicableOffers, err := s.applicableOffers(ctx, customerID, couponCode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch active offers: %w", err)
-	}
-	var activeOffers []*offerDomain.Offer
-	for _, dto := range activeOffersDTO {
-		activeOffers = append(activeOffers, offerApp.ToOfferDomain(*dto)) // Corrected: use offerApp.ToOfferDomain and dereference dto
+		return nil, err
 	}
 
+	// Free gift offers add or take back their gift lines before the discounts are computed
+	order, items, err = s.syncFreeGifts(ctx, order, items, applicableOffers)
+	if err != nil {
+		return nil, err
+	}
 
-	var applicableOffers []*offerDomain.Offer
+	// 2. Compute every adjustment in memory; items are indexed once so child
+	// items find their parents without scanning the cart for each offer
+	itemsByID := make(map[int64]*domain.OrderItem, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+	var orderAdjustments []*domain.OrderAdjustment
+	var itemAdjustments []*domain.OrderItemAdjustment
 
-	// Add offers by coupon code if provided
-	if couponCode != nil && *couponCode != "" {
-		couponOfferDTO, err := s.offerService.GetOfferByCode(ctx, *couponCode)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find offer by coupon code %s: %w", *couponCode, err)
+	for _, offer := range applicableOffers {
+		if offer.IsFreeGift() {
+			continue // Its gift line is already in the cart at no charge
 		}
-		if couponOfferDTO != nil && !couponOfferDTO.Archived {
-			// Further check customer-specific max uses and audience here if needed
-			applicableOffers = append(applicableOffers, offerApp.ToOfferDomain(*couponOfferDTO)) // Corrected
-		}
-	}
-
-	// Add other automatically applying offers (not requiring a coupon code)
-	for _, offer := range activeOffers {
-		if offer.AutomaticallyAdded {
-			applicableOffers = append(applicableOffers, offer)
-		}
-	}
-
-	// Sort offers by priority (lower number = higher priority)
-	sort.Slice(applicableOffers, func(i, j int) bool {
-		return applicableOffers[i].OfferPriority < applicableOffers[j].OfferPriority
-	})
-
-	for _, offer := range applicableOffers {
-		// Simplified offer application logic. Real logic would be much more complex.
-		if offer.OrderMinTotal > 0 && order.OrderSubtotal < offer.OrderMinTotal {
-			continue // Order does not meet minimum subtotal
+		// Simplified offer application logic. Real logic would be much more complex.
+		if offer.OrderMinTotal > 0 && order.OrderSubtotal < offer.OrderMinTotal {
+			continue // Order does not meet minimum subtotal
 		}
 
 		switch offer.OfferDiscountType {
@@ -590,77 +958,644 @@ func (s *orderService) ApplyOffersToOrder(ctx context.Context, orderID int64, cu
 					adjustmentAmount = offer.OfferValue
 				}
 				if adjustmentAmount > 0 {
-					adj, _ := domain.NewOrderAdjustment(order.ID, offer.ID, offer.OfferDescription, -adjustmentAmount, false)
-					err = s.orderAdjustmentRepo.Save(ctx, adj)
+					adj, err := domain.NewOrderAdjustment(order.ID, offer.ID, offer.OfferDescription, -adjustmentAmount, false)
 					if err != nil {
-						return nil, fmt.Errorf("failed to save order adjustment: %w", err)
+						return nil, fmt.Errorf("failed to create order adjustment for offer %d: %w", offer.ID, err)
 					}
+					orderAdjustments = append(orderAdjustments, adj)
 					// Increment offer uses (needs to be handled by offer service)
 					// s.offerService.IncrementOfferUses(ctx, offer.ID)
 				}
 			} else if offer.AdjustmentType == offerDomain.OfferAdjustmentTypeOrderItem {
 				// Apply item-level discount
 				for _, item := range items {
-					// Placeholder for complex item eligibility checks using QualCritOfferXref and TarCritOfferXref
-					itemApplies := s.checkItemEligibility(ctx, item, offer)
-
-					if itemApplies {
-						itemAdjustmentAmount := 0.0
-						basePrice := item.Price // Use current item price, which might already be discounted by higher priority offers
-						if offer.OfferType == offerDomain.OfferTypePercentageOff {
-							itemAdjustmentAmount = basePrice * offer.OfferValue * float64(item.Quantity)
-						} else if offer.OfferType == offerDomain.OfferTypeAmountOff {
-							itemAdjustmentAmount = offer.OfferValue * float64(item.Quantity)
-						}
-
-							if itemAdjustmentAmount > 0 {
-								itemAdj, _ := domain.NewOrderItemAdjustment(item.ID, offer.ID, offer.OfferDescription, -itemAdjustmentAmount, offer.ApplyToSalePrice) // Removed last arg
-							err = s.orderItemAdjustmentRepo.Save(ctx, itemAdj)
-							if err != nil {
-								return nil, fmt.Errorf("failed to save order item adjustment: %w", err)
-							}
-							
-							item.UpdatePrices(item.RetailPrice, item.SalePrice, item.Price-(itemAdjustmentAmount/float64(item.Quantity)))
-							err = s.orderItemRepo.Save(ctx, item)
-							if err != nil {
-								return nil, fmt.Errorf("failed to update order item prices: %w", err)
-							}
-							// Increment offer uses (needs to be handled by offer service)
-							// s.offerService.IncrementOfferUses(ctx, offer.ID)
-						}
+					if item.IsFreeGift() || !s.itemQualifies(ctx, itemsByID, item, offer) {
+						continue
+					}
+
+					// Use current item price, which might already be discounted by higher priority offers
+					itemAdjustmentAmount := itemDiscount(offer, item.Price, item.Quantity)
+					if itemAdjustmentAmount <= 0 {
+						continue
+					}
+
+					itemAdj, err := domain.NewOrderItemAdjustment(item.ID, offer.ID, offer.OfferDescription, -itemAdjustmentAmount, offer.ApplyToSalePrice)
+					if err != nil {
+						return nil, fmt.Errorf("failed to create order item adjustment for offer %d: %w", offer.ID, err)
 					}
+					itemAdjustments = append(itemAdjustments, itemAdj)
+					item.UpdatePrices(item.RetailPrice, item.SalePrice, item.Price-(itemAdjustmentAmount/float64(item.Quantity)))
+					// Increment offer uses (needs to be handled by offer service)
+					// s.offerService.IncrementOfferUses(ctx, offer.ID)
 				}
 			}
 		// TODO: Implement BOGO logic, Shipping discounts, and more complex rules.
 		}
 	}
 
+	// 3. Persist the adjustments and repriced items in one batch
+	if err := s.orderAdjustmentRepo.ReplaceOfferAdjustments(ctx, orderID, items, orderAdjustments, itemAdjustments); err != nil {
+		return nil, fmt.Errorf("failed to save offer adjustments for order %d: %w", orderID, err)
+	}
+
 	// Recalculate full order totals after all offers applied
-	order.OrderSubtotal = 0.0
-	order.TotalTax = 0.0
 	order.TotalShipping = 0.0 // Assuming this will be calculated by a shipping service
+	order.RecalculateTotals(items, orderAdjustments)
+
+	err = s.orderRepo.Update(ctx, order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update order after applying offers: %w", err)
+	}
+
+	return toOrderDTOWithRelations(order, items, orderAdjustments, nil), nil // Fulfillment groups not updated here
+}
+
+// applicableOffers returns the active offers a customer can use, sorted by priority: the
+// coupon's offer, if a coupon code is given, and the offers that apply automatically
+func (s *orderService) applicableOffers(ctx context.Context, customerID int64, couponCode *string) ([]*offerDomain.Offer, error) {
+	activeOffersDTO, err := s.offerService.GetActiveOffers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch active offers: %w", err)
+	}
+
+	// Offers limited to tagged customers only apply to customers carrying one of the tags,
+	// and offers with customer conditions only to signed-in customers meeting them
+	var customerTags []string
+	var customer *offerDomain.CustomerTargeting
+	if customerID != 0 {
+		customer, err = s.offerService.GetCustomerTargeting(ctx, customerID)
+		if err != nil {
+			return nil, err
+		}
+		customerTags = customer.Tags
+	}
+	now := time.Now()
+
+	var applicableOffers []*offerDomain.Offer
+
+	// Add offers by coupon code if provided
+	if couponCode != nil && *couponCode != "" {
+		couponOfferDTO, err := s.offerService.GetOfferByCode(ctx, *couponCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find offer by coupon code %s: %w", *couponCode, err)
+		}
+		if couponOfferDTO != nil && !couponOfferDTO.Archived {
+			// Further check customer-specific max uses here if needed
+			couponOffer := offerApp.ToOfferDomain(*couponOfferDTO)
+			if !couponOffer.TargetsCustomer(customerTags) {
+				return nil, errors.ValidationError("coupon code " + *couponCode + " is not available to this customer")
+			}
+			if reason := couponOffer.CustomerConditionFailure(customer, now); reason != "" {
+				return nil, errors.ValidationError(fmt.Sprintf("coupon code %s is not available to this customer: %s", *couponCode, reason))
+			}
+			applicableOffers = append(applicableOffers, couponOffer)
+		}
+	}
+
+	// Add other automatically applying offers (not requiring a coupon code)
+	for _, dto := range activeOffersDTO {
+		if !dto.AutomaticallyAdded {
+			continue
+		}
+		offer := offerApp.ToOfferDomain(*dto)
+		if offer.TargetsCustomer(customerTags) && offer.CustomerConditionFailure(customer, now) == "" {
+			applicableOffers = append(applicableOffers, offer)
+		}
+	}
+
+	// Sort offers by priority (lower number = higher priority)
+	sort.SliceStable(applicableOffers, func(i, j int) bool {
+		return applicableOffers[i].OfferPriority < applicableOffers[j].OfferPriority
+	})
+
 
+	return applicableOffers, nil
+}
+
+// itemDiscount returns what an item-level offer takes off a quantity of an item at a unit price
+func itemDiscount(offer *offerDomain.Offer, unitPrice float64, quantity int) float64 {
+	switch offer.OfferType {
+	case offerDomain.OfferTypePercentageOff:
+		return unitPrice * offer.OfferValue * float64(quantity)
+	case offerDomain.OfferTypeAmountOff:
+		return offer.OfferValue * float64(quantity)
+	}
+	return 0
+}
+
+// syncFreeGifts adds the gift of every free gift offer the cart qualifies for and removes
+// the gift lines of offers it no longer qualifies for. A gift SKU is given once, by the
+// offer of highest priority, and is left out while it is out of stock. The order and its
+// items are reloaded, with their prices reset, when lines were added or removed.
+func (s *orderService) syncFreeGifts(ctx context.Context, order *domain.Order, items []*domain.OrderItem, offers []*offerDomain.Offer) (*domain.Order, []*domain.OrderItem, error) {
+	paidItems := domain.PaidItems(items)
+	paidSubtotal := domain.PaidSubtotal(items)
+
+	var gifts []*offerDomain.Offer
+	giftedSKUs := make(map[int64]*offerDomain.Offer)
+	for _, offer := range offers {
+		if !offer.IsFreeGift() || giftedSKUs[*offer.GiftSKUID] != nil {
+			continue
+		}
+		if offer.OrderMinTotal > 0 && paidSubtotal < offer.OrderMinTotal {
+			continue
+		}
+		qualifies := false
+		for _, item := range paidItems {
+			if item.ParentOrderItemID == nil && s.checkItemEligibility(ctx, item, offer) {
+				qualifies = true
+				break
+			}
+		}
+		if qualifies {
+			gifts = append(gifts, offer)
+			giftedSKUs[*offer.GiftSKUID] = offer
+		}
+	}
+
+	changed := false
+	kept := make(map[int64]bool)
 	for _, item := range items {
-		order.OrderSubtotal += item.TotalPrice
-		order.TotalTax += item.TaxAmount
+		if !item.IsFreeGift() {
+			continue
+		}
+		if offer := giftedSKUs[item.SKUID]; offer != nil && !kept[item.SKUID] && item.Quantity == giftQuantity(offer) {
+			kept[item.SKUID] = true
+			continue
+		}
+		if err := s.RemoveOrderItem(ctx, item.ID); err != nil {
+			return nil, nil, fmt.Errorf("failed to remove free gift item %d: %w", item.ID, err)
+		}
+		changed = true
+	}
+
+	noCharge := 0.0
+	for _, offer := range gifts {
+		if kept[*offer.GiftSKUID] {
+			continue
+		}
+		_, err := s.AddItemToOrder(ctx, order.ID, &AddItemToOrderCommand{
+			SKUID:          *offer.GiftSKUID,
+			Quantity:       giftQuantity(offer),
+			OrderItemType:  domain.OrderItemTypeFreeGift,
+			PriceOverride:  &noCharge,
+			SkipCartPolicy: true,
+		})
+		if errors.IsInsufficientStock(err) {
+			continue // Given once the gift is back in stock and the offers are applied again
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add free gift of offer %d: %w", offer.ID, err)
+		}
+		changed = true
 	}
-	// Sum order adjustments
-	orderAdjustments, err := s.orderAdjustmentRepo.FindByOrderID(ctx, orderID)
+	if !changed {
+		return order, items, nil
+	}
+
+	orderID := order.ID
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to re-fetch order %d after updating free gifts: %w", orderID, err)
+	}
+	items, err = s.orderItemRepo.FindByOrderID(ctx, orderID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch order adjustments for total recalculation: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch order items for order %d: %w", orderID, err)
 	}
-	for _, adj := range orderAdjustments {
-		order.OrderSubtotal += adj.AdjustmentValue // Adjust subtotal based on order-level discounts
+	for _, item := range items {
+		item.UpdatePrices(item.RetailPrice, item.SalePrice, item.RetailPrice)
 	}
-	
-	order.OrderTotal = order.OrderSubtotal + order.TotalTax + order.TotalShipping
+	return order, items, nil
+}
 
-	err = s.orderRepo.Update(ctx, order)
+// giftQuantity is the quantity of its gift SKU a free gift offer adds
+func giftQuantity(offer *offerDomain.Offer) int {
+	return max(offer.GiftQuantity, 1)
+}
+
+// RevalidateCart re-resolves the current price and availability of every line of a cart, then
+// its offers. Lines of SKUs no longer sold are removed, lines holding stock that was oversold
+// give it up, and lines are repriced; the changes are returned with the updated cart.
+func (s *orderService) RevalidateCart(ctx context.Context, customerID, orderID int64, couponCode *string) (*CartRevalidationDTO, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update order after applying offers: %w", err)
+		return nil, fmt.Errorf("failed to find order by ID for revalidation: %w", err)
 	}
+	if order == nil {
+		return nil, errors.NotFound("cart")
+	}
+	if order.CustomerID != customerID {
+		return nil, errors.Forbidden("cart belongs to another customer")
+	}
+	if !order.IsCart() {
+		return nil, errors.OrderNotEditable(strconv.FormatInt(orderID, 10), string(order.Status))
+	}
+	previousTotal := order.OrderTotal
 
-	return toOrderDTOWithRelations(order, items, orderAdjustments, nil), nil // Fulfillment groups not updated here
+	items, err := s.orderItemRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order items for order %d: %w", orderID, err)
+	}
+	previousOffers, err := s.offerAmounts(ctx, orderID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock is checked once per SKU; lines of an oversold SKU give up the shortfall in turn
+	skuIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		skuIDs = append(skuIDs, strconv.FormatInt(item.SKUID, 10))
+	}
+	shortfalls := make(map[int64]int, len(items))
+	if len(skuIDs) > 0 {
+		atps, err := s.atpService.GetAvailableToPromise(ctx, skuIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get available-to-promise for order %d: %w", orderID, err)
+		}
+		for _, atp := range atps {
+			skuID, _ := strconv.ParseInt(atp.SKUID, 10, 64)
+			shortfalls[skuID] = domain.StockShortfall(atp.OnHand, atp.Reserved, atp.Inbound, atp.AllowBackorder)
+		}
+	}
+
+	var lineChanges []*domain.CartLineChange
+	removed := make(map[int64]bool)
+	for _, item := range items {
+		if removed[item.ID] {
+			continue
+		}
+		change := &domain.CartLineChange{
+			OrderItemID:      item.ID,
+			SKUID:            item.SKUID,
+			Name:             item.Name,
+			PreviousPrice:    domain.EffectiveUnitPrice(item.RetailPrice, item.SalePrice),
+			PreviousQuantity: item.Quantity,
+		}
+		change.Price = change.PreviousPrice
+
+		sku, err := s.skuService.GetSkuByID(ctx, item.SKUID)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get SKU details for ID %d: %w", item.SKUID, err)
+		}
+		reason := ""
+		quantity := item.Quantity
+		if sku == nil || !sku.IsActive {
+			reason = domain.CartChangeReasonUnavailable
+			quantity = 0
+		} else if shortfall := shortfalls[item.SKUID]; shortfall > 0 {
+			reason = domain.CartChangeReasonLowStock
+			quantity = item.Quantity - min(shortfall, item.Quantity)
+			shortfalls[item.SKUID] -= item.Quantity - quantity
+			if quantity == 0 {
+				reason = domain.CartChangeReasonSoldOut
+			}
+		}
+
+		if quantity == 0 {
+			descendants := domain.DescendantItemsOf(items, item.ID)
+			if err := s.RemoveOrderItem(ctx, item.ID); err != nil {
+				return nil, fmt.Errorf("failed to remove unavailable item %d: %w", item.ID, err)
+			}
+			removed[item.ID] = true
+			change.Type, change.Reason, change.Quantity = domain.CartChangeRemoved, reason, 0
+			lineChanges = append(lineChanges, change)
+			for _, child := range descendants {
+				removed[child.ID] = true
+				lineChanges = append(lineChanges, &domain.CartLineChange{
+					OrderItemID:      child.ID,
+					SKUID:            child.SKUID,
+					Name:             child.Name,
+					Type:             domain.CartChangeRemoved,
+					Reason:           domain.CartChangeReasonParentRemoved,
+					PreviousPrice:    domain.EffectiveUnitPrice(child.RetailPrice, child.SalePrice),
+					Price:            domain.EffectiveUnitPrice(child.RetailPrice, child.SalePrice),
+					PreviousQuantity: child.Quantity,
+				})
+			}
+			continue
+		}
+
+		// Prices set by an agent are kept
+		if !item.RetailPriceOverride {
+			price := domain.EffectiveUnitPrice(sku.RetailPrice, sku.SalePrice)
+			if money.Round(price, order.CurrencyCode) != money.Round(change.PreviousPrice, order.CurrencyCode) {
+				item.UpdatePrices(sku.RetailPrice, sku.SalePrice, price)
+				if err := s.applyItemTax(ctx, orderID, item); err != nil {
+					return nil, fmt.Errorf("failed to recalculate tax for item %d: %w", item.ID, err)
+				}
+				if err := s.orderItemRepo.Save(ctx, item); err != nil {
+					return nil, fmt.Errorf("failed to save repriced item %d: %w", item.ID, err)
+				}
+				priceChange := *change
+				priceChange.Type, priceChange.Price, priceChange.Quantity = domain.CartChangePriceChanged, price, item.Quantity
+				lineChanges = append(lineChanges, &priceChange)
+				change.PreviousPrice, change.Price = price, price
+			}
+		}
+
+		if quantity < item.Quantity {
+			if _, err := s.UpdateOrderItemQuantity(ctx, item.ID, quantity); err != nil {
+				return nil, fmt.Errorf("failed to reduce quantity of item %d: %w", item.ID, err)
+			}
+			change.Type, change.Reason, change.Quantity = domain.CartChangeQuantityReduced, reason, quantity
+			lineChanges = append(lineChanges, change)
+		}
+	}
+
+	// Offers are resolved again against the updated lines; carts without offers keep their prices
+	order, err = s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-fetch order %d after revalidation: %w", orderID, err)
+	}
+	if err := s.recalculateTotals(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update order totals after revalidation: %w", err)
+	}
+	if len(previousOffers) > 0 || (couponCode != nil && *couponCode != "") {
+		if _, err := s.ApplyOffersToOrder(ctx, orderID, order.CustomerID, couponCode); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err = s.orderItemRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order items for order %d: %w", orderID, err)
+	}
+	offers, err := s.offerAmounts(ctx, orderID, items)
+	if err != nil {
+		return nil, err
+	}
+	offerChanges := diffOfferAmounts(previousOffers, offers, order.CurrencyCode)
+
+	cart, err := s.HandleGetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	result := &CartRevalidationDTO{
+		OrderID:       orderID,
+		Changed:       len(lineChanges) > 0 || len(offerChanges) > 0,
+		Lines:         make([]*CartLineChangeDTO, 0, len(lineChanges)),
+		Offers:        make([]*CartOfferChangeDTO, 0, len(offerChanges)),
+		PreviousTotal: previousTotal,
+		Total:         cart.OrderTotal,
+		Cart:          cart,
+	}
+	for _, change := range lineChanges {
+		result.Lines = append(result.Lines, ToCartLineChangeDTO(change))
+	}
+	for _, change := range offerChanges {
+		result.Offers = append(result.Offers, ToCartOfferChangeDTO(change))
+	}
+	return result, nil
+}
+
+// offerAmounts totals the discount each offer gives an order, across order and item adjustments
+func (s *orderService) offerAmounts(ctx context.Context, orderID int64, items []*domain.OrderItem) (map[int64]*domain.CartOfferChange, error) {
+	amounts := make(map[int64]*domain.CartOfferChange)
+	add := func(offerID int64, description string, amount float64) {
+		if amounts[offerID] == nil {
+			amounts[offerID] = &domain.CartOfferChange{OfferID: offerID, Description: description}
+		}
+		amounts[offerID].Amount += amount
+	}
+
+	adjustments, err := s.orderAdjustmentRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order adjustments for order %d: %w", orderID, err)
+	}
+	for _, adj := range adjustments {
+		add(adj.OfferID, adj.AdjustmentReason, adj.AdjustmentValue)
+	}
+	for _, item := range items {
+		itemAdjustments, err := s.orderItemAdjustmentRepo.FindByOrderItemID(ctx, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch adjustments of order item %d: %w", item.ID, err)
+		}
+		for _, adj := range itemAdjustments {
+			add(adj.OfferID, adj.AdjustmentReason, adj.AdjustmentValue)
+		}
+	}
+	return amounts, nil
+}
+
+// diffOfferAmounts lists the offers added to, removed from or giving a different discount to a cart
+func diffOfferAmounts(previous, current map[int64]*domain.CartOfferChange, currencyCode string) []*domain.CartOfferChange {
+	var changes []*domain.CartOfferChange
+	for offerID, now := range current {
+		before, ok := previous[offerID]
+		switch {
+		case !ok:
+			changes = append(changes, &domain.CartOfferChange{OfferID: offerID, Description: now.Description, Type: domain.OfferChangeAdded, Amount: now.Amount})
+		case money.Round(before.Amount, currencyCode) != money.Round(now.Amount, currencyCode):
+			changes = append(changes, &domain.CartOfferChange{OfferID: offerID, Description: now.Description, Type: domain.OfferChangeAmount, PreviousAmount: before.Amount, Amount: now.Amount})
+		}
+	}
+	for offerID, before := range previous {
+		if _, ok := current[offerID]; !ok {
+			changes = append(changes, &domain.CartOfferChange{OfferID: offerID, Description: before.Description, Type: domain.OfferChangeRemoved, PreviousAmount: before.Amount})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].OfferID < changes[j].OfferID })
+	return changes
+}
+
+func (s *orderService) PriceEditLine(ctx context.Context, order *domain.Order, line *domain.OrderEditLine) error {
+	sku, err := s.skuService.GetSkuByID(ctx, line.SKUID)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get SKU details for ID %d: %w", line.SKUID, err)
+	}
+	if sku == nil || !sku.IsActive {
+		return errors.ValidationError(fmt.Sprintf("SKU %d is not available", line.SKUID))
+	}
+	if sku.DefaultProductID == nil {
+		return fmt.Errorf("SKU with ID %d has no associated default product", line.SKUID)
+	}
+	taxCategory := ""
+	if sku.Taxable {
+		taxCategory = sku.TaxCode
+	}
+
+	// Priced as a new item of the order would be, without saving it
+	item, err := domain.NewOrderItem(order.ID, sku.ID, *sku.DefaultProductID, sku.Name, line.Quantity, sku.RetailPrice, sku.SalePrice, taxCategory)
+	if err != nil {
+		return errors.ValidationError(err.Error())
+	}
+	offers, err := s.applicableOffers(ctx, order.CustomerID, nil)
+	if err != nil {
+		return err
+	}
+	var discounts []*domain.OrderEditDiscount
+	for _, offer := range offers {
+		if offer.IsFreeGift() || offer.AdjustmentType != offerDomain.OfferAdjustmentTypeOrderItem {
+			continue // Order-level offers were settled when the order was placed
+		}
+		if offer.OrderMinTotal > 0 && order.OrderSubtotal < offer.OrderMinTotal {
+			continue
+		}
+		if !s.checkItemEligibility(ctx, item, offer) {
+			continue
+		}
+		amount := itemDiscount(offer, item.Price, item.Quantity)
+		if amount <= 0 {
+			continue
+		}
+		discounts = append(discounts, &domain.OrderEditDiscount{
+			OfferID:            offer.ID,
+			Description:        offer.OfferDescription,
+			Amount:             -amount,
+			AppliedToSalePrice: offer.ApplyToSalePrice,
+		})
+		item.UpdatePrices(item.RetailPrice, item.SalePrice, item.Price-(amount/float64(item.Quantity)))
+	}
+	if err := s.applyItemTax(ctx, order.ID, item); err != nil {
+		return fmt.Errorf("failed to calculate tax for item: %w", err)
+	}
+
+	line.ProductID = item.ProductID
+	line.Name = item.Name
+	line.TaxCategory = item.TaxCategory
+	line.RetailPrice = item.RetailPrice
+	line.SalePrice = item.SalePrice
+	line.UnitPrice = item.Price
+	line.Total = money.Round(item.TotalPrice, order.CurrencyCode)
+	line.Tax = money.Round(item.TaxAmount, order.CurrencyCode)
+	line.Discounts = discounts
+	line.Priced = true
+	return nil
+}
+
+func (s *orderService) ApplyOrderEdit(ctx context.Context, order *domain.Order, edit *domain.OrderEdit) error {
+	if edit.Summary == nil {
+		return errors.ValidationError("preview the edit before applying it")
+	}
+	items, err := s.orderItemRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order items for order %d: %w", order.ID, err)
+	}
+	itemsByID := make(map[int64]*domain.OrderItem, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+	// The lines were snapshotted when the edit opened; the order must not have changed since
+	for _, line := range edit.Lines {
+		if line.OrderItemID == 0 {
+			continue
+		}
+		if item, ok := itemsByID[line.OrderItemID]; !ok || item.Quantity != line.OriginalQuantity {
+			return errors.Conflict("the order changed since the edit was opened")
+		}
+	}
+
+	// Stock is reserved and released before the order changes, so an edit the stock
+	// cannot cover leaves the order untouched
+	var adjusted []*domain.OrderEditLine
+	for _, line := range edit.Lines {
+		if err := s.adjustReservation(ctx, line.SKUID, line.Quantity-line.OriginalQuantity); err != nil {
+			return s.revertEditReservations(ctx, adjusted, err)
+		}
+		adjusted = append(adjusted, line)
+	}
+	if err := s.applyEditLines(ctx, order, edit, itemsByID); err != nil {
+		return s.revertEditReservations(ctx, adjusted, err)
+	}
+
+	order.ApplyEdit(edit.Summary)
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return fmt.Errorf("failed to update order totals after edit: %w", err)
+	}
+	return nil
+}
+
+// applyEditLines saves the lines of an order edit as the items of the order. Changed
+// quantities keep the unit price and scale the tax and offer adjustments of the item.
+func (s *orderService) applyEditLines(ctx context.Context, order *domain.Order, edit *domain.OrderEdit, itemsByID map[int64]*domain.OrderItem) error {
+	removed := make(map[int64]bool)
+	for _, line := range edit.Lines {
+		if line.Change() == domain.OrderEditRemoved {
+			removed[line.OrderItemID] = true
+		}
+	}
+	// A parent no longer wrapped must not point at the removed gift wrap
+	for id, item := range itemsByID {
+		if !removed[id] && item.GiftWrapItemID != nil && removed[*item.GiftWrapItemID] {
+			item.GiftWrapItemID = nil
+			if err := s.orderItemRepo.Save(ctx, item); err != nil {
+				return fmt.Errorf("failed to clear gift wrap of item %d: %w", item.ID, err)
+			}
+		}
+	}
+	// Add-ons are removed before the items they hang off
+	for i := len(edit.Lines) - 1; i >= 0; i-- {
+		line := edit.Lines[i]
+		if !removed[line.OrderItemID] {
+			continue
+		}
+		if err := s.orderItemAttributeRepo.DeleteByOrderItemID(ctx, line.OrderItemID); err != nil {
+			return fmt.Errorf("failed to delete order item attributes for item %d: %w", line.OrderItemID, err)
+		}
+		if err := s.orderItemAdjustmentRepo.DeleteByOrderItemID(ctx, line.OrderItemID); err != nil {
+			return fmt.Errorf("failed to delete order item adjustments for item %d: %w", line.OrderItemID, err)
+		}
+		if err := s.orderItemRepo.Delete(ctx, line.OrderItemID); err != nil {
+			return fmt.Errorf("failed to delete order item: %w", err)
+		}
+	}
+
+	for _, line := range edit.Lines {
+		switch line.Change() {
+		case domain.OrderEditQuantityChanged:
+			item := itemsByID[line.OrderItemID]
+			adjustments, err := s.orderItemAdjustmentRepo.FindByOrderItemID(ctx, item.ID)
+			if err != nil {
+				return fmt.Errorf("failed to fetch order item adjustments for item %d: %w", item.ID, err)
+			}
+			for _, adj := range adjustments {
+				adj.AdjustmentValue = money.Round(adj.AdjustmentValue*float64(line.Quantity)/float64(item.Quantity), order.CurrencyCode)
+				if err := s.orderItemAdjustmentRepo.Save(ctx, adj); err != nil {
+					return fmt.Errorf("failed to save order item adjustment %d: %w", adj.ID, err)
+				}
+			}
+			if err := item.UpdateQuantity(line.Quantity); err != nil {
+				return fmt.Errorf("failed to update order item quantity: %w", err)
+			}
+			item.SetTaxAmount(line.Tax)
+			if err := s.orderItemRepo.Save(ctx, item); err != nil {
+				return fmt.Errorf("failed to save order item after quantity update: %w", err)
+			}
+
+		case domain.OrderEditAdded:
+			item, err := domain.NewOrderItem(order.ID, line.SKUID, line.ProductID, line.Name, line.Quantity, line.RetailPrice, line.SalePrice, line.TaxCategory)
+			if err != nil {
+				return fmt.Errorf("failed to create order item domain entity: %w", err)
+			}
+			item.UpdatePrices(line.RetailPrice, line.SalePrice, line.UnitPrice)
+			item.SetTaxAmount(line.Tax)
+			if err := s.orderItemRepo.Save(ctx, item); err != nil {
+				return fmt.Errorf("failed to save order item: %w", err)
+			}
+			for _, discount := range line.Discounts {
+				adj, err := domain.NewOrderItemAdjustment(item.ID, discount.OfferID, discount.Description, discount.Amount, discount.AppliedToSalePrice)
+				if err != nil {
+					return fmt.Errorf("failed to create order item adjustment for offer %d: %w", discount.OfferID, err)
+				}
+				if err := s.orderItemAdjustmentRepo.Save(ctx, adj); err != nil {
+					return fmt.Errorf("failed to save order item adjustment for offer %d: %w", discount.OfferID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// revertEditReservations undoes the stock reserved and released for the lines of an
+// order edit that could not be applied, returning the error that stopped it
+func (s *orderService) revertEditReservations(ctx context.Context, lines []*domain.OrderEditLine, cause error) error {
+	for _, line := range lines {
+		if err := s.adjustReservation(ctx, line.SKUID, line.OriginalQuantity-line.Quantity); err != nil {
+			return fmt.Errorf("%w (and failed to restore inventory of SKU %d: %v)", cause, line.SKUID, err)
+		}
+	}
+	return cause
 }
 
 func (s *orderService) CreateFulfillmentGroup(ctx context.Context, orderID int64, cmd *CreateFulfillmentGroupCommand) (*FulfillmentGroupDTO, error) {
@@ -704,6 +1639,37 @@ func (s *orderService) UpdateOrderShippingDetails(ctx context.Context, orderID i
 	return nil
 }
 
+// recalculateTotals recomputes an order's totals from all of its items and adjustments and saves it.
+func (s *orderService) recalculateTotals(ctx context.Context, order *domain.Order) error {
+	items, err := s.orderItemRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order items for order %d: %w", order.ID, err)
+	}
+	adjustments, err := s.orderAdjustmentRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order adjustments for order %d: %w", order.ID, err)
+	}
+
+	order.RecalculateTotals(items, adjustments)
+	return s.orderRepo.Update(ctx, order)
+}
+
+// itemQualifies checks whether an item-level offer applies to an item. Child items
+// only qualify when the offer cascades to children and their parent qualifies.
+func (s *orderService) itemQualifies(ctx context.Context, itemsByID map[int64]*domain.OrderItem, item *domain.OrderItem, offer *offerDomain.Offer) bool {
+	if item.ParentOrderItemID == nil {
+		return s.checkItemEligibility(ctx, item, offer)
+	}
+	if !offer.ApplyToChildItems {
+		return false
+	}
+	parent, ok := itemsByID[*item.ParentOrderItemID]
+	if !ok {
+		return false
+	}
+	return s.itemQualifies(ctx, itemsByID, parent, offer) && s.checkItemEligibility(ctx, item, offer)
+}
+
 // checkItemEligibility is a placeholder for complex item eligibility logic.
 // In a real system, this would evaluate offer.OfferItemQualifierRule
 // and offer.OfferItemTargetRule against the item's properties.
@@ -732,6 +1698,9 @@ func toOrderDTO(order *domain.Order) *OrderDTO {
 		CurrencyCode:            order.CurrencyCode,
 		IsPreview:               order.IsPreview,
 		TaxOverride:             order.TaxOverride,
+		EstimatedTax:            order.EstimatedTax,
+		TaxReconciliation:       order.TaxReconciliation,
+		TaxProvider:             order.TaxProvider,
 		LocaleCode:              order.LocaleCode,
 		SubmitDate:              order.SubmitDate,
 		CreatedAt:               order.CreatedAt,
@@ -747,11 +1716,7 @@ func toOrderDTOWithRelations(
 ) *OrderDTO {
 	orderDTO := toOrderDTO(order)
 
-	itemsDTO := make([]*OrderItemDTO, len(items))
-	for i, item := range items {
-		itemsDTO[i] = ToOrderItemDTO(item)
-	}
-	orderDTO.Items = itemsDTO
+	orderDTO.Items = toOrderItemTreeDTOs(items)
 
 	adjustmentsDTO := make([]*OrderAdjustmentDTO, len(orderAdjustments))
 	for i, adj := range orderAdjustments {
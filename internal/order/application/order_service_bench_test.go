@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	offerApp "github.com/qhato/ecommerce/internal/offer/application"
+	offerDomain "github.com/qhato/ecommerce/internal/offer/domain"
+	"github.com/qhato/ecommerce/internal/order/domain"
+)
+
+// benchOrderRepo serves a single order from memory
+type benchOrderRepo struct {
+	domain.OrderRepository
+	order *domain.Order
+}
+
+func (r *benchOrderRepo) FindByID(ctx context.Context, id int64) (*domain.Order, error) {
+	return r.order, nil
+}
+
+func (r *benchOrderRepo) Update(ctx context.Context, order *domain.Order) error {
+	return nil
+}
+
+// benchOrderItemRepo serves the items of the order from memory
+type benchOrderItemRepo struct {
+	domain.OrderItemRepository
+	items []*domain.OrderItem
+}
+
+func (r *benchOrderItemRepo) FindByOrderID(ctx context.Context, orderID int64) ([]*domain.OrderItem, error) {
+	return r.items, nil
+}
+
+// benchAdjustmentRepo counts the batches offer adjustments are saved in
+type benchAdjustmentRepo struct {
+	domain.OrderAdjustmentRepository
+	batches int
+}
+
+func (r *benchAdjustmentRepo) ReplaceOfferAdjustments(ctx context.Context, orderID int64, items []*domain.OrderItem, orderAdjustments []*domain.OrderAdjustment, itemAdjustments []*domain.OrderItemAdjustment) error {
+	r.batches++
+	return nil
+}
+
+// benchOfferService returns a fixed set of automatically applied offers
+type benchOfferService struct {
+	offerApp.OfferService
+	offers []*offerApp.OfferDTO
+}
+
+func (s *benchOfferService) GetActiveOffers(ctx context.Context) ([]*offerApp.OfferDTO, error) {
+	return s.offers, nil
+}
+
+// BenchmarkApplyOffersToOrder applies 60 active offers to a cart of 120 items, a
+// quarter of them add-ons, with every adjustment saved in one batch per order
+func BenchmarkApplyOffersToOrder(b *testing.B) {
+	const orderID = 1
+	order := &domain.Order{ID: orderID, Status: domain.OrderStatusPending, CurrencyCode: "USD"}
+
+	var items []*domain.OrderItem
+	for i := 1; i <= 120; i++ {
+		item, err := domain.NewOrderItem(orderID, int64(i), int64(i), fmt.Sprintf("Item %d", i), i%3+1, 20+float64(i), 0, "")
+		if err != nil {
+			b.Fatal(err)
+		}
+		item.ID = int64(i)
+		if i%4 == 0 {
+			item.SetParentOrderItemID(int64(i - 1))
+		}
+		items = append(items, item)
+	}
+
+	var offers []*offerApp.OfferDTO
+	for i := 1; i <= 60; i++ {
+		offer := &offerApp.OfferDTO{
+			ID:                 int64(i),
+			Name:               fmt.Sprintf("Offer %d", i),
+			OfferDescription:   fmt.Sprintf("Offer %d", i),
+			AutomaticallyAdded: true,
+			OfferPriority:      i,
+			ApplyToChildItems:  i%2 == 0,
+		}
+		switch i % 3 {
+		case 0:
+			offer.AdjustmentType = offerDomain.OfferAdjustmentTypeOrder
+			offer.OfferDiscountType = offerDomain.OfferDiscountTypeAmountOff
+			offer.OfferType = offerDomain.OfferTypeAmountOff
+			offer.OfferValue = 1
+		case 1:
+			offer.AdjustmentType = offerDomain.OfferAdjustmentTypeOrderItem
+			offer.OfferDiscountType = offerDomain.OfferDiscountTypePercentDiscount
+			offer.OfferType = offerDomain.OfferTypePercentageOff
+			offer.OfferValue = 0.01
+		default:
+			offer.AdjustmentType = offerDomain.OfferAdjustmentTypeOrderItem
+			offer.OfferDiscountType = offerDomain.OfferDiscountTypeAmountOff
+			offer.OfferType = offerDomain.OfferTypeAmountOff
+			offer.OfferValue = 0.05
+		}
+		offers = append(offers, offer)
+	}
+
+	adjustments := &benchAdjustmentRepo{}
+	svc := &orderService{
+		orderRepo:           &benchOrderRepo{order: order},
+		orderItemRepo:       &benchOrderItemRepo{items: items},
+		orderAdjustmentRepo: adjustments,
+		offerService:        &benchOfferService{offers: offers},
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.ApplyOffersToOrder(ctx, orderID, 0, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	if adjustments.batches != b.N {
+		b.Fatalf("expected one adjustment batch per order, got %d for %d orders", adjustments.batches, b.N)
+	}
+}
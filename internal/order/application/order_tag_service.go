@@ -0,0 +1,382 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// ruleTaggingBatchSize bounds how many submitted orders one rule tagging run evaluates
+const ruleTaggingBatchSize = 200
+
+// OrderTagService tags orders so operations can work them in custom queues, such as
+// fragile items, international shipments or VIP customers. Staff tag orders by hand and
+// tag rules tag submitted orders matching their conditions. Queues list the orders
+// carrying a combination of tags in the chosen statuses.
+type OrderTagService interface {
+	// ListTags lists the tags in use with how many orders carry each, by status.
+	ListTags(ctx context.Context) ([]*OrderTagCountDTO, error)
+
+	// GetOrderTags lists the tags of an order.
+	GetOrderTags(ctx context.Context, orderID int64) ([]*OrderTagDTO, error)
+
+	// AddTags tags an order by hand and returns its tags.
+	AddTags(ctx context.Context, orderID int64, req *AddOrderTagsRequest) ([]*OrderTagDTO, error)
+
+	// RemoveTag removes a tag from an order, whichever way it was added.
+	RemoveTag(ctx context.Context, orderID int64, tag string) error
+
+	// ListQueue lists a page of the orders of a work queue.
+	ListQueue(ctx context.Context, req *OrderQueueRequest) (*PaginatedResponse, error)
+
+	// CreateRule creates a tag rule; it tags orders submitted from then on.
+	CreateRule(ctx context.Context, req *OrderTagRuleRequest) (*OrderTagRuleDTO, error)
+
+	// UpdateRule replaces the name, tag, conditions and optionally the active flag of a rule.
+	UpdateRule(ctx context.Context, id int64, req *OrderTagRuleRequest) (*OrderTagRuleDTO, error)
+
+	// DeleteRule removes a tag rule; the tags it added stay on their orders.
+	DeleteRule(ctx context.Context, id int64) error
+
+	// ListRules lists every tag rule by name.
+	ListRules(ctx context.Context) ([]*OrderTagRuleDTO, error)
+
+	// ApplyRules evaluates the active rules against an order again, replacing its rule
+	// tags, and returns its tags. Staff tags are kept.
+	ApplyRules(ctx context.Context, orderID int64) ([]*OrderTagDTO, error)
+
+	// TagSubmittedOrders applies the rules to the submitted orders they have not
+	// evaluated yet and returns how many were evaluated.
+	TagSubmittedOrders(ctx context.Context) (int, error)
+
+	// StartRuleTagger tags newly submitted orders periodically until ctx is cancelled.
+	StartRuleTagger(ctx context.Context, interval time.Duration)
+}
+
+type orderTagService struct {
+	orderRepo domain.OrderRepository
+	tagRepo   domain.OrderTagRepository
+	log       *logger.Logger
+}
+
+// NewOrderTagService creates a new instance of OrderTagService
+func NewOrderTagService(
+	orderRepo domain.OrderRepository,
+	tagRepo domain.OrderTagRepository,
+	log *logger.Logger,
+) OrderTagService {
+	return &orderTagService{
+		orderRepo: orderRepo,
+		tagRepo:   tagRepo,
+		log:       log,
+	}
+}
+
+func (s *orderTagService) ListTags(ctx context.Context) ([]*OrderTagCountDTO, error) {
+	counts, err := s.tagRepo.CountTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count order tags: %w", err)
+	}
+	dtos := make([]*OrderTagCountDTO, len(counts))
+	for i, count := range counts {
+		byStatus := make(map[string]int64, len(count.ByStatus))
+		for status, orders := range count.ByStatus {
+			byStatus[string(status)] = orders
+		}
+		dtos[i] = &OrderTagCountDTO{Tag: count.Tag, Orders: count.Orders, ByStatus: byStatus}
+	}
+	return dtos, nil
+}
+
+func (s *orderTagService) GetOrderTags(ctx context.Context, orderID int64) ([]*OrderTagDTO, error) {
+	tags, err := s.tagRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of order %d: %w", orderID, err)
+	}
+	dtos := make([]*OrderTagDTO, len(tags))
+	for i, tag := range tags {
+		dtos[i] = ToOrderTagDTO(tag)
+	}
+	return dtos, nil
+}
+
+func (s *orderTagService) AddTags(ctx context.Context, orderID int64, req *AddOrderTagsRequest) ([]*OrderTagDTO, error) {
+	if len(req.Tags) == 0 {
+		return nil, errors.ValidationError("tags are required")
+	}
+	names, err := normalizeOrderTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tags := make([]*domain.OrderTag, len(names))
+	for i, name := range names {
+		tags[i] = &domain.OrderTag{
+			OrderID:   orderID,
+			Tag:       name,
+			Source:    domain.OrderTagSourceManual,
+			CreatedBy: req.CreatedBy,
+			CreatedAt: now,
+		}
+	}
+	if err := s.tagRepo.AddTags(ctx, tags); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"order_id":   orderID,
+		"tags":       names,
+		"created_by": req.CreatedBy,
+	}).Info("Order tagged")
+	return s.GetOrderTags(ctx, orderID)
+}
+
+func (s *orderTagService) RemoveTag(ctx context.Context, orderID int64, tag string) error {
+	name, err := domain.NormalizeOrderTag(tag)
+	if err != nil {
+		return errors.ValidationError(err.Error())
+	}
+	removed, err := s.tagRepo.RemoveTag(ctx, orderID, name)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.NotFound(fmt.Sprintf("tag %s of order %d", name, orderID))
+	}
+	return nil
+}
+
+func (s *orderTagService) ListQueue(ctx context.Context, req *OrderQueueRequest) (*PaginatedResponse, error) {
+	filter := &domain.OrderQueueFilter{
+		NewestFirst: req.NewestFirst,
+		Page:        req.Page,
+		PageSize:    req.PageSize,
+	}
+	var err error
+	if filter.AllTags, err = normalizeOrderTags(req.AllTags); err != nil {
+		return nil, err
+	}
+	if filter.AnyTags, err = normalizeOrderTags(req.AnyTags); err != nil {
+		return nil, err
+	}
+	if filter.ExcludeTags, err = normalizeOrderTags(req.ExcludeTags); err != nil {
+		return nil, err
+	}
+	for _, status := range req.Statuses {
+		if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+			filter.Statuses = append(filter.Statuses, domain.OrderStatus(status))
+		}
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	items, total, err := s.tagRepo.FindQueue(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order queue: %w", err)
+	}
+	dtos := make([]*OrderQueueItemDTO, len(items))
+	for i, item := range items {
+		dtos[i] = ToOrderQueueItemDTO(item)
+	}
+	return NewPaginatedResponse(dtos, filter.Page, filter.PageSize, total), nil
+}
+
+func (s *orderTagService) CreateRule(ctx context.Context, req *OrderTagRuleRequest) (*OrderTagRuleDTO, error) {
+	rule, err := domain.NewOrderTagRule(req.Name, req.Tag, req.Conditions, req.CreatedBy)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	if err := s.tagRepo.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	s.log.WithFields(logger.Fields{
+		"rule_id":    rule.ID,
+		"tag":        rule.Tag,
+		"created_by": req.CreatedBy,
+	}).Info("Order tag rule created")
+	return ToOrderTagRuleDTO(rule), nil
+}
+
+func (s *orderTagService) UpdateRule(ctx context.Context, id int64, req *OrderTagRuleRequest) (*OrderTagRuleDTO, error) {
+	rule, err := s.findRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := rule.Update(req.Name, req.Tag, req.Conditions); err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	if err := s.tagRepo.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return ToOrderTagRuleDTO(rule), nil
+}
+
+func (s *orderTagService) DeleteRule(ctx context.Context, id int64) error {
+	return s.tagRepo.DeleteRule(ctx, id)
+}
+
+func (s *orderTagService) ListRules(ctx context.Context) ([]*OrderTagRuleDTO, error) {
+	rules, err := s.tagRepo.FindRules(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order tag rules: %w", err)
+	}
+	dtos := make([]*OrderTagRuleDTO, len(rules))
+	for i, rule := range rules {
+		dtos[i] = ToOrderTagRuleDTO(rule)
+	}
+	return dtos, nil
+}
+
+func (s *orderTagService) ApplyRules(ctx context.Context, orderID int64) ([]*OrderTagDTO, error) {
+	rules, err := s.tagRepo.FindRules(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load order tag rules: %w", err)
+	}
+	found, err := s.applyRules(ctx, orderID, rules)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.NotFound(fmt.Sprintf("order %d", orderID))
+	}
+	return s.GetOrderTags(ctx, orderID)
+}
+
+func (s *orderTagService) TagSubmittedOrders(ctx context.Context) (int, error) {
+	orderIDs, err := s.tagRepo.FindPendingRuleOrders(ctx, ruleTaggingBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find orders pending tag rules: %w", err)
+	}
+	if len(orderIDs) == 0 {
+		return 0, nil
+	}
+	rules, err := s.tagRepo.FindRules(ctx, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load order tag rules: %w", err)
+	}
+
+	// Orders that fail stay pending and are retried on the next run
+	evaluated := 0
+	for _, orderID := range orderIDs {
+		if _, err := s.applyRules(ctx, orderID, rules); err != nil {
+			s.log.WithError(err).WithField("order_id", orderID).Error("Failed to apply order tag rules")
+			continue
+		}
+		evaluated++
+	}
+	return evaluated, nil
+}
+
+func (s *orderTagService) StartRuleTagger(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.TagSubmittedOrders(ctx); err != nil {
+					s.log.WithError(err).Warn("Order tag rule run failed")
+				}
+			}
+		}
+	}()
+}
+
+// applyRules replaces the rule tags of an order with the tags of the rules it matches,
+// reporting whether the order exists
+func (s *orderTagService) applyRules(ctx context.Context, orderID int64, rules []*domain.OrderTagRule) (bool, error) {
+	facts, err := s.tagRepo.FindFacts(ctx, orderID)
+	if err != nil {
+		return false, err
+	}
+	if facts == nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	tags := make([]*domain.OrderTag, 0)
+	matched := make(map[string]bool)
+	for _, rule := range rules {
+		if matched[rule.Tag] || !rule.Matches(facts) {
+			continue
+		}
+		matched[rule.Tag] = true
+		ruleID := rule.ID
+		tags = append(tags, &domain.OrderTag{
+			OrderID:   orderID,
+			Tag:       rule.Tag,
+			Source:    domain.OrderTagSourceRule,
+			RuleID:    &ruleID,
+			CreatedBy: "rule:" + rule.Name,
+			CreatedAt: now,
+		})
+	}
+	if err := s.tagRepo.ReplaceRuleTags(ctx, orderID, tags, now); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+func (s *orderTagService) ensureOrder(ctx context.Context, orderID int64) error {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to find order %d: %w", orderID, err)
+	}
+	if order == nil {
+		return errors.NotFound(fmt.Sprintf("order %d", orderID))
+	}
+	return nil
+}
+
+func (s *orderTagService) findRule(ctx context.Context, id int64) (*domain.OrderTagRule, error) {
+	rule, err := s.tagRepo.FindRuleByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order tag rule %d: %w", id, err)
+	}
+	if rule == nil {
+		return nil, errors.NotFound(fmt.Sprintf("order tag rule %d", id))
+	}
+	return rule, nil
+}
+
+// normalizeOrderTags lowercases and checks tags, dropping duplicates
+func normalizeOrderTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name, err := domain.NormalizeOrderTag(tag)
+		if err != nil {
+			return nil, errors.ValidationError(err.Error())
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
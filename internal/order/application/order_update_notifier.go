@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	fulfillmentDomain "github.com/qhato/ecommerce/internal/fulfillment/domain"
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/notification"
+)
+
+// The shipment fields read by the notifier, checked against the fulfillment event schemas
+func init() {
+	event.RegisterContract("order.update_notifier", fulfillmentDomain.EventShipmentShipped, "order_id", "carrier", "tracking_number")
+	event.RegisterContract("order.update_notifier", fulfillmentDomain.EventShipmentDelivered, "order_id")
+}
+
+// OrderUpdateSender delivers order updates to a customer's storefront inbox
+type OrderUpdateSender interface {
+	SendInApp(ctx context.Context, customerID int64, category, subject, body, link string) error
+}
+
+// OrderUpdateNotifier tells customers about the progress of their orders through
+// the in-app channel of the notification pipeline
+type OrderUpdateNotifier struct {
+	orderRepo domain.OrderRepository
+	sender    OrderUpdateSender
+	log       *logger.Logger
+}
+
+// NewOrderUpdateNotifier creates a new OrderUpdateNotifier
+func NewOrderUpdateNotifier(orderRepo domain.OrderRepository, sender OrderUpdateSender, log *logger.Logger) *OrderUpdateNotifier {
+	return &OrderUpdateNotifier{orderRepo: orderRepo, sender: sender, log: log}
+}
+
+// Subscribe notifies customers when shipments of their orders ship or are delivered
+func (n *OrderUpdateNotifier) Subscribe(bus event.Bus) error {
+	if err := bus.Subscribe(fulfillmentDomain.EventShipmentShipped, n.handleShipmentEvent); err != nil {
+		return err
+	}
+	return bus.Subscribe(fulfillmentDomain.EventShipmentDelivered, n.handleShipmentEvent)
+}
+
+func (n *OrderUpdateNotifier) handleShipmentEvent(ctx context.Context, evt event.Event) error {
+	var orderID int64
+	var update, body string
+	switch e := evt.(type) {
+	case *fulfillmentDomain.ShipmentShippedEvent:
+		orderID = e.OrderID
+		update = "has shipped"
+		if e.TrackingNumber != "" {
+			body = fmt.Sprintf("%s tracking number: %s", e.Carrier, e.TrackingNumber)
+		}
+	case *fulfillmentDomain.ShipmentDeliveredEvent:
+		orderID = e.OrderID
+		update = "was delivered"
+	default:
+		return nil
+	}
+
+	order, err := n.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order == nil || order.CustomerID == 0 {
+		return nil
+	}
+
+	orderRef := order.OrderNumber
+	if orderRef == "" {
+		orderRef = fmt.Sprintf("#%d", order.ID)
+	}
+	subject := fmt.Sprintf("Your order %s %s", orderRef, update)
+	link := fmt.Sprintf("/orders/%d", order.ID)
+	if err := n.sender.SendInApp(ctx, order.CustomerID, notification.CategoryOrderUpdate, subject, body, link); err != nil {
+		// A missed inbox message is not worth redelivering the shipment event
+		n.log.WithError(err).WithField("order_id", order.ID).Warn("Failed to deliver order update")
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	paymentDomain "github.com/qhato/ecommerce/internal/payment/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/i18n"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/money"
+)
+
+// PaymentLinkService defines the application service for secure, expiring links
+// customers pay unpaid orders with: assisted orders, failed payment recovery and quotes.
+type PaymentLinkService interface {
+	// CreatePaymentLink issues a link for the current total of an order awaiting
+	// payment, cancelling the order's earlier links.
+	CreatePaymentLink(ctx context.Context, orderID int64, createdBy string, req *CreatePaymentLinkRequest) (*PaymentLinkDTO, error)
+
+	// ListPaymentLinks returns the payment links of an order, newest first.
+	ListPaymentLinks(ctx context.Context, orderID int64) ([]*PaymentLinkDTO, error)
+
+	// CancelPaymentLink makes an unpaid link of an order unusable.
+	CancelPaymentLink(ctx context.Context, orderID, linkID int64) (*PaymentLinkDTO, error)
+
+	// GetPayLink returns what a customer pays with a link and the gateway to pay with.
+	GetPayLink(ctx context.Context, token string) (*PayLinkDTO, error)
+
+	// PayByLink authorizes the payment of an order through its link and submits the order.
+	PayByLink(ctx context.Context, token string, req *PayByLinkRequest) (*PayLinkDTO, error)
+}
+
+// PaymentLinkConfig holds the payment link settings
+type PaymentLinkConfig struct {
+	URL       string        // Storefront page paying an order; "{token}" is replaced with the link token
+	TTL       time.Duration // How long a link can be used unless set when it is issued
+	PublicKey string        // Publishable gateway key the payment page confirms payments with
+	Messages  *i18n.Catalog // Translates the payment link email into the order's locale; nil sends the built-in English Messages
+}
+
+// PayLinkNotifier emails payment links to customers
+type PayLinkNotifier interface {
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
+type paymentLinkService struct {
+	orderService OrderService
+	linkRepo     domain.PaymentLinkRepository
+	assistedRepo domain.AssistedOrderRepository
+	gateway      paymentDomain.PaymentGateway
+	notifier     PayLinkNotifier
+	cfg          PaymentLinkConfig
+	logger       *logger.Logger
+}
+
+// NewPaymentLinkService creates a new instance of PaymentLinkService. gateway may be nil,
+// in which case links can be issued but not paid; notifier may be nil, in which case
+// links are shared with customers by hand.
+func NewPaymentLinkService(
+	orderService OrderService,
+	linkRepo domain.PaymentLinkRepository,
+	assistedRepo domain.AssistedOrderRepository,
+	gateway paymentDomain.PaymentGateway,
+	notifier PayLinkNotifier,
+	cfg PaymentLinkConfig,
+	log *logger.Logger,
+) PaymentLinkService {
+	if cfg.Messages == nil {
+		cfg.Messages = i18n.NewCatalog(MessagesLocale)
+		cfg.Messages.Add(MessagesLocale, Messages)
+	}
+	return &paymentLinkService{
+		orderService: orderService,
+		linkRepo:     linkRepo,
+		assistedRepo: assistedRepo,
+		gateway:      gateway,
+		notifier:     notifier,
+		cfg:          cfg,
+		logger:       log,
+	}
+}
+
+func (s *paymentLinkService) CreatePaymentLink(ctx context.Context, orderID int64, createdBy string, req *CreatePaymentLinkRequest) (*PaymentLinkDTO, error) {
+	order, err := s.orderService.HandleGetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	if !order.Status.IsAwaitingPayment() {
+		return nil, errors.Conflict(fmt.Sprintf("order %d is %s and no longer awaits payment", orderID, order.Status))
+	}
+
+	ttl := s.cfg.TTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	token, err := newPayLinkToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate payment link token: %w", err)
+	}
+	link, err := domain.NewPaymentLink(order.ID, domain.PaymentLinkReason(req.Reason), token, order.OrderTotal,
+		order.CurrencyCode, time.Now().Add(ttl), createdBy)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	// Only the newest link of an order can be paid, so a resent link replaces the earlier ones
+	if err := s.cancelActiveLinks(ctx, order.ID); err != nil {
+		return nil, err
+	}
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create payment link: %w", err)
+	}
+	if order.Status != domain.OrderStatusPayment {
+		if err := s.orderService.UpdateOrderStatus(ctx, order.ID, domain.OrderStatusPayment); err != nil {
+			return nil, fmt.Errorf("failed to hold order %d for payment: %w", order.ID, err)
+		}
+	}
+
+	dto := s.toPaymentLinkDTO(link)
+	if req.SendEmail {
+		dto.Emailed = s.emailLink(ctx, link, order)
+	}
+	return dto, nil
+}
+
+func (s *paymentLinkService) ListPaymentLinks(ctx context.Context, orderID int64) ([]*PaymentLinkDTO, error) {
+	links, err := s.linkRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment links of order %d: %w", orderID, err)
+	}
+
+	dtos := make([]*PaymentLinkDTO, len(links))
+	for i, link := range links {
+		dtos[i] = s.toPaymentLinkDTO(link)
+	}
+	return dtos, nil
+}
+
+func (s *paymentLinkService) CancelPaymentLink(ctx context.Context, orderID, linkID int64) (*PaymentLinkDTO, error) {
+	link, err := s.linkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payment link %d: %w", linkID, err)
+	}
+	if link == nil || link.OrderID != orderID {
+		return nil, errors.NotFound(fmt.Sprintf("payment link %d", linkID))
+	}
+	if err := link.Cancel(); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.linkRepo.Update(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to cancel payment link %d: %w", linkID, err)
+	}
+	return s.toPaymentLinkDTO(link), nil
+}
+
+func (s *paymentLinkService) GetPayLink(ctx context.Context, token string) (*PayLinkDTO, error) {
+	link, err := s.findByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orderService.HandleGetOrderByID(ctx, link.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", link.OrderID, err)
+	}
+	return s.toPayLinkDTO(link, order), nil
+}
+
+func (s *paymentLinkService) PayByLink(ctx context.Context, token string, req *PayByLinkRequest) (*PayLinkDTO, error) {
+	link, err := s.findByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := link.CanPay(time.Now()); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if req.PaymentToken == "" {
+		return nil, errors.ValidationError("payment_token is required")
+	}
+	if s.gateway == nil {
+		return nil, errors.ServiceUnavailable("payment links cannot be paid: no payment gateway is configured")
+	}
+
+	order, err := s.orderService.HandleGetOrderByID(ctx, link.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", link.OrderID, err)
+	}
+	if !order.Status.IsAwaitingPayment() {
+		return nil, errors.Conflict("the order can no longer be paid")
+	}
+	if toCents(order.OrderTotal) != toCents(link.Amount) {
+		return nil, errors.Conflict("the order has changed since the payment link was issued")
+	}
+
+	// Claim the link before charging so that concurrent requests cannot both pay it
+	claimed, err := s.linkRepo.Claim(ctx, link.ID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim payment link %d: %w", link.ID, err)
+	}
+	if !claimed {
+		return nil, errors.Conflict("a payment of the link is already in progress")
+	}
+
+	response, err := s.gateway.Authorize(ctx, s.paymentRequest(link, order, req))
+	if err != nil {
+		s.release(ctx, link)
+		return nil, errors.PaymentFailed(err.Error())
+	}
+	if response.Status == paymentDomain.PaymentStatusFailed {
+		s.release(ctx, link)
+		reason := "the payment was declined"
+		if response.ErrorMessage != nil {
+			reason = *response.ErrorMessage
+		}
+		return nil, errors.PaymentFailed(reason)
+	}
+
+	if err := s.submit(ctx, link.OrderID, response.TransactionID); err != nil {
+		// Release the held funds; the customer can retry while the link is active
+		if _, voidErr := s.gateway.Void(ctx, response.TransactionID); voidErr != nil {
+			s.logger.WithError(voidErr).WithField("order_id", link.OrderID).Error("Failed to void payment of unsubmitted order")
+		}
+		s.release(ctx, link)
+		return nil, err
+	}
+
+	link.MarkPaid(response.TransactionID)
+	if err := s.linkRepo.Update(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save payment of link %d: %w", link.ID, err)
+	}
+
+	order, err = s.orderService.HandleGetOrderByID(ctx, link.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", link.OrderID, err)
+	}
+	return s.toPayLinkDTO(link, order), nil
+}
+
+// release makes a link the customer could not pay with active again
+func (s *paymentLinkService) release(ctx context.Context, link *domain.PaymentLink) {
+	if err := s.linkRepo.Release(ctx, link.ID); err != nil {
+		s.logger.WithError(err).WithField("payment_link_id", link.ID).Error("Failed to release payment link")
+	}
+}
+
+// paymentRequest builds the gateway request confirming the payment the customer
+// set up on the payment page. The token is the payment method for intent based
+// gateways and the approved order for PayPal.
+func (s *paymentLinkService) paymentRequest(link *domain.PaymentLink, order *OrderDTO, req *PayByLinkRequest) *paymentDomain.PaymentRequest {
+	method := paymentDomain.PaymentMethod(req.PaymentMethodType)
+	if method == "" {
+		method = paymentDomain.PaymentMethodCreditCard
+	}
+	tokenKey := "payment_method"
+	if s.gateway.GetName() == "PayPal" {
+		tokenKey = "paypal_order_id"
+	}
+
+	request := &paymentDomain.PaymentRequest{
+		OrderID:       strconv.FormatInt(order.ID, 10),
+		Amount:        decimal.NewFromFloat(link.Amount),
+		Currency:      link.CurrencyCode,
+		PaymentMethod: method,
+		Description:   "Order " + order.OrderNumber,
+		Metadata: map[string]string{
+			tokenKey:          req.PaymentToken,
+			"payment_link_id": strconv.FormatInt(link.ID, 10),
+		},
+	}
+	if order.CustomerID != 0 {
+		customerID := strconv.FormatInt(order.CustomerID, 10)
+		request.CustomerID = &customerID
+	}
+	return request
+}
+
+// submit records the payment of assisted orders and submits the order, keeping
+// the cart policy bypass the agent applied
+func (s *paymentLinkService) submit(ctx context.Context, orderID int64, transactionID string) error {
+	assisted, err := s.assistedRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to find assisted order %d: %w", orderID, err)
+	}
+
+	submit := s.orderService.SubmitOrder
+	if assisted != nil {
+		assisted.MarkAuthorized(transactionID)
+		if err := s.assistedRepo.Save(ctx, assisted); err != nil {
+			return fmt.Errorf("failed to save assisted order payment: %w", err)
+		}
+		if assisted.CartPolicyBypassed {
+			submit = s.orderService.SubmitOrderSkippingCartPolicy
+		}
+	}
+	if err := submit(ctx, orderID); err != nil {
+		return fmt.Errorf("failed to submit order %d paid by link: %w", orderID, err)
+	}
+	return nil
+}
+
+func (s *paymentLinkService) cancelActiveLinks(ctx context.Context, orderID int64) error {
+	links, err := s.linkRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to list payment links of order %d: %w", orderID, err)
+	}
+	for _, link := range links {
+		if link.Status != domain.PaymentLinkStatusActive {
+			continue
+		}
+		if err := link.Cancel(); err != nil {
+			return errors.Conflict(err.Error())
+		}
+		if err := s.linkRepo.Update(ctx, link); err != nil {
+			return fmt.Errorf("failed to cancel payment link %d: %w", link.ID, err)
+		}
+	}
+	return nil
+}
+
+// emailLink sends the link to the order's email address, reporting whether it was sent
+func (s *paymentLinkService) emailLink(ctx context.Context, link *domain.PaymentLink, order *OrderDTO) bool {
+	if s.notifier == nil || order.EmailAddress == "" {
+		return false
+	}
+
+	messages := s.cfg.Messages
+	locale := messages.Match(order.LocaleCode)
+	subject := messages.Translate(locale, "payment_link.email.subject", "order", order.OrderNumber)
+	body := messages.Translate(locale, "payment_link.email.body",
+		"amount", money.Format(link.Amount, link.CurrencyCode, order.LocaleCode),
+		"url", s.linkURL(link.Token),
+		"expires", link.ExpiresAt.Format(time.RFC1123))
+	if err := s.notifier.SendEmail(ctx, order.EmailAddress, subject, body); err != nil {
+		// The link is still shown to the agent to share with the customer
+		s.logger.WithError(err).WithField("order_id", order.ID).Warn("Failed to email payment link")
+		return false
+	}
+	return true
+}
+
+func (s *paymentLinkService) findByToken(ctx context.Context, token string) (*domain.PaymentLink, error) {
+	link, err := s.linkRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payment link: %w", err)
+	}
+	if link == nil {
+		return nil, errors.NotFound("payment link")
+	}
+	return link, nil
+}
+
+func (s *paymentLinkService) linkURL(token string) string {
+	return strings.ReplaceAll(s.cfg.URL, "{token}", token)
+}
+
+func (s *paymentLinkService) toPaymentLinkDTO(link *domain.PaymentLink) *PaymentLinkDTO {
+	status := link.EffectiveStatus(time.Now())
+	dto := &PaymentLinkDTO{
+		ID:            link.ID,
+		OrderID:       link.OrderID,
+		Reason:        string(link.Reason),
+		Amount:        link.Amount,
+		CurrencyCode:  link.CurrencyCode,
+		Status:        string(status),
+		ExpiresAt:     link.ExpiresAt,
+		CreatedBy:     link.CreatedBy,
+		TransactionID: link.TransactionID,
+		PaidAt:        link.PaidAt,
+		CreatedAt:     link.CreatedAt,
+	}
+	if status == domain.PaymentLinkStatusActive {
+		dto.URL = s.linkURL(link.Token)
+	}
+	return dto
+}
+
+func (s *paymentLinkService) toPayLinkDTO(link *domain.PaymentLink, order *OrderDTO) *PayLinkDTO {
+	dto := &PayLinkDTO{
+		OrderNumber:  order.OrderNumber,
+		Name:         order.Name,
+		Amount:       link.Amount,
+		CurrencyCode: link.CurrencyCode,
+		Items:        order.Items,
+		Status:       string(link.EffectiveStatus(time.Now())),
+		ExpiresAt:    link.ExpiresAt,
+	}
+	dto.Payable = link.CanPay(time.Now()) == nil && order.Status.IsAwaitingPayment()
+	if dto.Payable && s.gateway != nil {
+		dto.Gateway = s.gateway.GetName()
+		dto.PublicKey = s.cfg.PublicKey
+	}
+	return dto
+}
+
+// newPayLinkToken returns an unguessable payment link token
+func newPayLinkToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// toCents rounds an amount to the smallest unit payment links are compared in
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -3,7 +3,6 @@ package queries
 import (
 	"context"
 	"encoding/json" // Added json import
-	"fmt"
 	"time"
 
 	"github.com/qhato/ecommerce/internal/order/application" // Import order application package
@@ -161,12 +160,19 @@ func (h *OrderQueryHandler) InvalidateCache(ctx context.Context, orderID int64)
 	}
 }
 
+var (
+	// orderKeys builds the cache keys of orders
+	orderKeys = cache.NewKeyBuilder("order", "order", 1)
+	// orderNumberKeys builds the cache keys of orders looked up by number
+	orderNumberKeys = cache.NewKeyBuilder("order", "order_number", 1)
+)
+
 // orderCacheKey generates a cache key for an order.
 func orderCacheKey(id int64) string {
-	return fmt.Sprintf("order:%d", id)
+	return orderKeys.Key(id)
 }
 
 // orderCacheKeyByNumber generates a cache key for an order by its order number.
 func orderCacheKeyByNumber(orderNumber string) string {
-	return fmt.Sprintf("order:number:%s", orderNumber)
+	return orderNumberKeys.Key(orderNumber)
 }
\ No newline at end of file
@@ -0,0 +1,405 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	paymentDomain "github.com/qhato/ecommerce/internal/payment/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// SplitPaymentService defines the application service for orders paid with
+// several tenders, e.g. a gift card and a card or two cards. Tenders are
+// authorized one after the other; when one is declined the holds already
+// placed are released and no payment is taken.
+type SplitPaymentService interface {
+	// PayOrder authorizes the tenders a customer splits an order's total
+	// across and submits the order.
+	PayOrder(ctx context.Context, customerID, orderID int64, req *SplitPaymentRequest) (*OrderPaymentDTO, error)
+
+	// GetCustomerPayment returns the tenders of a customer's order.
+	GetCustomerPayment(ctx context.Context, customerID, orderID int64) (*OrderPaymentDTO, error)
+
+	// GetPayment returns the tenders of an order.
+	GetPayment(ctx context.Context, orderID int64) (*OrderPaymentDTO, error)
+
+	// CaptureOrder captures every authorized tender of an order. A tender the
+	// gateway fails to capture stays authorized and can be captured again.
+	CaptureOrder(ctx context.Context, orderID int64) (*OrderPaymentDTO, error)
+
+	// VoidOrder releases the holds of an order's authorized tenders.
+	VoidOrder(ctx context.Context, orderID int64) (*OrderPaymentDTO, error)
+
+	// RefundOrder refunds an amount across an order's captured tenders.
+	RefundOrder(ctx context.Context, orderID int64, req *RefundOrderRequest, refundedBy string) (*OrderRefundDTO, error)
+}
+
+// TenderGateways holds the gateways tenders are processed with. Either may be
+// nil, in which case its tenders are not accepted.
+type TenderGateways struct {
+	Card     paymentDomain.PaymentGateway // Cards and PayPal
+	GiftCard paymentDomain.PaymentGateway // Gift card issuer
+}
+
+type splitPaymentService struct {
+	orderService OrderService
+	tenderRepo   domain.OrderTenderRepository
+	gateways     TenderGateways
+	logger       *logger.Logger
+}
+
+// NewSplitPaymentService creates a new instance of SplitPaymentService.
+func NewSplitPaymentService(
+	orderService OrderService,
+	tenderRepo domain.OrderTenderRepository,
+	gateways TenderGateways,
+	log *logger.Logger,
+) SplitPaymentService {
+	return &splitPaymentService{
+		orderService: orderService,
+		tenderRepo:   tenderRepo,
+		gateways:     gateways,
+		logger:       log,
+	}
+}
+
+func (s *splitPaymentService) PayOrder(ctx context.Context, customerID, orderID int64, req *SplitPaymentRequest) (*OrderPaymentDTO, error) {
+	order, err := s.findCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.IsAwaitingPayment() || order.Status == domain.OrderStatusAwaitingPayment {
+		return nil, errors.Conflict(fmt.Sprintf("order %d is %s and cannot be paid", orderID, order.Status))
+	}
+
+	existing, err := s.tenderRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenders of order %d: %w", orderID, err)
+	}
+	nextSequence := 1
+	for _, tender := range existing {
+		if tender.Status == domain.TenderStatusAuthorized || tender.Status == domain.TenderStatusCaptured {
+			return nil, errors.Conflict(fmt.Sprintf("order %d is already paid", orderID))
+		}
+		nextSequence = tender.Sequence + 1
+	}
+
+	requests := make([]domain.TenderRequest, len(req.Tenders))
+	for i, tender := range req.Tenders {
+		requests[i] = domain.TenderRequest{Method: strings.ToUpper(tender.Method), Token: tender.Token, Amount: tender.Amount}
+	}
+	planned, err := domain.PlanTenders(order.ID, order.OrderTotal, order.CurrencyCode, nextSequence, requests)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	tenders := make([]*domain.OrderTender, len(planned))
+	for i, plan := range planned {
+		if _, err := s.gatewayFor(plan.Tender.Method); err != nil {
+			return nil, err
+		}
+		tenders[i] = plan.Tender
+	}
+	for _, tender := range tenders {
+		if err := s.tenderRepo.Save(ctx, tender); err != nil {
+			return nil, fmt.Errorf("failed to save tender of order %d: %w", orderID, err)
+		}
+	}
+
+	for _, plan := range planned {
+		tender := plan.Tender
+		if reason := s.authorize(ctx, order, tender, plan.Token); reason != "" {
+			tender.Fail(reason)
+			s.saveTender(ctx, tender)
+			s.releaseTenders(ctx, tenders, fmt.Sprintf("tender %d was declined", tender.Sequence))
+			return nil, errors.PaymentFailed(fmt.Sprintf("the %s payment of %.2f %s was declined: %s; no payment was taken",
+				describeTender(tender.Method), tender.Amount, tender.CurrencyCode, reason))
+		}
+		s.saveTender(ctx, tender)
+	}
+
+	if err := s.orderService.SubmitOrder(ctx, orderID); err != nil {
+		s.releaseTenders(ctx, tenders, "the order could not be submitted")
+		return nil, fmt.Errorf("failed to submit order %d paid by split payment: %w", orderID, err)
+	}
+
+	s.logger.WithFields(logger.Fields{
+		"order_id": orderID,
+		"tenders":  len(tenders),
+		"amount":   order.OrderTotal,
+	}).Info("Order paid with split payment")
+	return s.GetPayment(ctx, orderID)
+}
+
+func (s *splitPaymentService) GetCustomerPayment(ctx context.Context, customerID, orderID int64) (*OrderPaymentDTO, error) {
+	if _, err := s.findCustomerOrder(ctx, customerID, orderID); err != nil {
+		return nil, err
+	}
+	return s.GetPayment(ctx, orderID)
+}
+
+func (s *splitPaymentService) GetPayment(ctx context.Context, orderID int64) (*OrderPaymentDTO, error) {
+	tenders, err := s.tenderRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenders of order %d: %w", orderID, err)
+	}
+	return toOrderPaymentDTO(orderID, tenders, true), nil
+}
+
+func (s *splitPaymentService) CaptureOrder(ctx context.Context, orderID int64) (*OrderPaymentDTO, error) {
+	tenders, err := s.activeTenders(ctx, orderID, domain.TenderStatusAuthorized)
+	if err != nil {
+		return nil, err
+	}
+
+	complete := true
+	for _, tender := range tenders {
+		gateway, err := s.gatewayFor(tender.Method)
+		if err != nil {
+			return nil, err
+		}
+		response, err := gateway.Capture(ctx, tender.TransactionID, decimal.NewFromFloat(tender.Amount))
+		if reason := gatewayFailure(response, err); reason != "" {
+			// The hold stays in place so the capture can be retried
+			complete = false
+			tender.FailureReason = "capture failed: " + reason
+			s.logger.WithField("order_id", orderID).WithField("tender_id", tender.ID).Warn("Failed to capture order tender")
+		} else if err := tender.MarkCaptured(); err != nil {
+			return nil, errors.Conflict(err.Error())
+		}
+		s.saveTender(ctx, tender)
+	}
+
+	all, err := s.tenderRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenders of order %d: %w", orderID, err)
+	}
+	return toOrderPaymentDTO(orderID, all, complete), nil
+}
+
+func (s *splitPaymentService) VoidOrder(ctx context.Context, orderID int64) (*OrderPaymentDTO, error) {
+	tenders, err := s.activeTenders(ctx, orderID, domain.TenderStatusAuthorized)
+	if err != nil {
+		return nil, err
+	}
+	complete := s.releaseTenders(ctx, tenders, "voided by an admin")
+
+	all, err := s.tenderRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenders of order %d: %w", orderID, err)
+	}
+	return toOrderPaymentDTO(orderID, all, complete), nil
+}
+
+func (s *splitPaymentService) RefundOrder(ctx context.Context, orderID int64, req *RefundOrderRequest, refundedBy string) (*OrderRefundDTO, error) {
+	tenders, err := s.tenderRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenders of order %d: %w", orderID, err)
+	}
+	allocations, err := domain.AllocateRefund(tenders, req.Amount)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	result := &OrderRefundDTO{OrderID: orderID, Requested: req.Amount, Complete: true}
+	var refunded int64
+	for _, allocation := range allocations {
+		tender := allocation.Tender
+		dto := &TenderRefundDTO{TenderID: tender.ID, Method: tender.Method, Amount: allocation.Amount}
+		result.Allocations = append(result.Allocations, dto)
+		if !result.Complete {
+			// Later tenders are left alone once one fails, so the refund can be retried for the rest
+			dto.Error = "not attempted"
+			continue
+		}
+
+		gateway, err := s.gatewayFor(tender.Method)
+		if err != nil {
+			return nil, err
+		}
+		response, err := gateway.Refund(ctx, tender.TransactionID, decimal.NewFromFloat(allocation.Amount))
+		if reason := gatewayFailure(response, err); reason != "" {
+			result.Complete = false
+			dto.Error = reason
+			continue
+		}
+		if err := tender.RecordRefund(allocation.Amount); err != nil {
+			return nil, errors.Conflict(err.Error())
+		}
+		s.saveTender(ctx, tender)
+		dto.Refunded = true
+		refunded += toCents(allocation.Amount)
+	}
+	result.Refunded = float64(refunded) / 100
+
+	s.logger.WithFields(logger.Fields{
+		"order_id":    orderID,
+		"requested":   req.Amount,
+		"refunded":    result.Refunded,
+		"reason":      req.Reason,
+		"refunded_by": refundedBy,
+	}).Info("Order refunded across tenders")
+	return result, nil
+}
+
+// authorize places the hold for a tender, returning why it was declined or an
+// empty string when it was authorized
+func (s *splitPaymentService) authorize(ctx context.Context, order *OrderDTO, tender *domain.OrderTender, token string) string {
+	gateway, err := s.gatewayFor(tender.Method)
+	if err != nil {
+		return err.Error()
+	}
+
+	method := paymentDomain.PaymentMethod(tender.Method)
+	tokenKey := "payment_method"
+	switch {
+	case tender.Method == domain.TenderMethodGiftCard:
+		tokenKey = "gift_card"
+	case gateway.GetName() == "PayPal":
+		tokenKey = "paypal_order_id"
+	}
+	request := &paymentDomain.PaymentRequest{
+		OrderID:       strconv.FormatInt(order.ID, 10),
+		Amount:        decimal.NewFromFloat(tender.Amount),
+		Currency:      tender.CurrencyCode,
+		PaymentMethod: method,
+		Description:   fmt.Sprintf("Order %s (%d of split payment)", order.OrderNumber, tender.Sequence),
+		Metadata: map[string]string{
+			tokenKey:          token,
+			"tender_sequence": strconv.Itoa(tender.Sequence),
+		},
+	}
+	if order.CustomerID != 0 {
+		customerID := strconv.FormatInt(order.CustomerID, 10)
+		request.CustomerID = &customerID
+	}
+
+	response, err := gateway.Authorize(ctx, request)
+	if reason := gatewayFailure(response, err); reason != "" {
+		return reason
+	}
+	tender.MarkAuthorized(response.TransactionID)
+	return ""
+}
+
+// releaseTenders voids the authorized tenders among tenders, reporting whether
+// every hold was released. Pending tenders are marked voided without a gateway call.
+func (s *splitPaymentService) releaseTenders(ctx context.Context, tenders []*domain.OrderTender, reason string) bool {
+	released := true
+	for _, tender := range tenders {
+		switch tender.Status {
+		case domain.TenderStatusPending:
+			tender.MarkVoided(reason)
+		case domain.TenderStatusAuthorized:
+			gateway, err := s.gatewayFor(tender.Method)
+			if err == nil {
+				var response *paymentDomain.PaymentResponse
+				response, err = gateway.Void(ctx, tender.TransactionID)
+				if failure := gatewayFailure(response, err); failure != "" {
+					err = fmt.Errorf("%s", failure)
+				}
+			}
+			if err != nil {
+				// The hold lapses at the gateway eventually; the tender stays authorized for a retry
+				released = false
+				tender.FailureReason = "void failed: " + err.Error()
+				s.logger.WithError(err).WithField("order_id", tender.OrderID).WithField("tender_id", tender.ID).Error("Failed to void order tender")
+			} else {
+				tender.MarkVoided(reason)
+			}
+		default:
+			continue
+		}
+		s.saveTender(ctx, tender)
+	}
+	return released
+}
+
+func (s *splitPaymentService) activeTenders(ctx context.Context, orderID int64, status domain.TenderStatus) ([]*domain.OrderTender, error) {
+	tenders, err := s.tenderRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenders of order %d: %w", orderID, err)
+	}
+	active := make([]*domain.OrderTender, 0, len(tenders))
+	for _, tender := range tenders {
+		if tender.Status == status {
+			active = append(active, tender)
+		}
+	}
+	if len(active) == 0 {
+		return nil, errors.Conflict(fmt.Sprintf("order %d has no %s tenders", orderID, strings.ToLower(string(status))))
+	}
+	return active, nil
+}
+
+func (s *splitPaymentService) gatewayFor(method string) (paymentDomain.PaymentGateway, error) {
+	gateway := s.gateways.Card
+	if method == domain.TenderMethodGiftCard {
+		gateway = s.gateways.GiftCard
+	}
+	if gateway == nil {
+		return nil, errors.ServiceUnavailable(fmt.Sprintf("%s payments are not accepted: no gateway is configured", describeTender(method)))
+	}
+	return gateway, nil
+}
+
+// saveTender persists a tender after a gateway call; the gateway outcome is
+// already final, so a failed save is logged rather than undoing it
+func (s *splitPaymentService) saveTender(ctx context.Context, tender *domain.OrderTender) {
+	if err := s.tenderRepo.Save(ctx, tender); err != nil {
+		s.logger.WithError(err).WithField("order_id", tender.OrderID).WithField("tender_id", tender.ID).Error("Failed to save order tender")
+	}
+}
+
+func (s *splitPaymentService) findCustomerOrder(ctx context.Context, customerID, orderID int64) (*OrderDTO, error) {
+	order, err := s.orderService.HandleGetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	if order.CustomerID != customerID {
+		return nil, errors.Forbidden("order belongs to another customer")
+	}
+	return order, nil
+}
+
+// gatewayFailure returns why a gateway call failed, or an empty string when it succeeded
+func gatewayFailure(response *paymentDomain.PaymentResponse, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if response == nil || response.Status == paymentDomain.PaymentStatusFailed {
+		if response != nil && response.ErrorMessage != nil {
+			return *response.ErrorMessage
+		}
+		return "the payment was declined"
+	}
+	return ""
+}
+
+func describeTender(method string) string {
+	return strings.ToLower(strings.ReplaceAll(method, "_", " "))
+}
+
+func toOrderPaymentDTO(orderID int64, tenders []*domain.OrderTender, complete bool) *OrderPaymentDTO {
+	dto := &OrderPaymentDTO{OrderID: orderID, Tenders: make([]*OrderTenderDTO, len(tenders)), Complete: complete}
+	var authorized, captured, refunded int64
+	for i, tender := range tenders {
+		dto.Tenders[i] = ToOrderTenderDTO(tender)
+		switch tender.Status {
+		case domain.TenderStatusAuthorized:
+			authorized += toCents(tender.Amount)
+		case domain.TenderStatusCaptured, domain.TenderStatusRefunded:
+			captured += toCents(tender.CapturedAmount)
+			refunded += toCents(tender.RefundedAmount)
+		}
+	}
+	dto.Authorized = float64(authorized) / 100
+	dto.Captured = float64(captured) / 100
+	dto.Refunded = float64(refunded) / 100
+	return dto
+}
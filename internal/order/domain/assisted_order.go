@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AssistedOrderChannel is how a customer placed an order with an agent
+type AssistedOrderChannel string
+
+const (
+	AssistedOrderChannelPhone AssistedOrderChannel = "PHONE"
+	AssistedOrderChannelMail  AssistedOrderChannel = "MAIL"
+)
+
+// AssistedPaymentMethod is how an assisted order is paid
+type AssistedPaymentMethod string
+
+const (
+	AssistedPaymentCard      AssistedPaymentMethod = "CARD"        // Card taken by the agent and tokenized by the payment terminal
+	AssistedPaymentPayByLink AssistedPaymentMethod = "PAY_BY_LINK" // The customer is emailed a link to pay on the storefront
+	AssistedPaymentOffline   AssistedPaymentMethod = "OFFLINE"     // Paid outside the platform, e.g. bank transfer or invoice
+)
+
+// AssistedPaymentStatus represents the payment state of an assisted order
+type AssistedPaymentStatus string
+
+const (
+	AssistedPaymentStatusAwaiting   AssistedPaymentStatus = "AWAITING_PAYMENT" // Payment link sent, not paid yet
+	AssistedPaymentStatusAuthorized AssistedPaymentStatus = "AUTHORIZED"
+	AssistedPaymentStatusOffline    AssistedPaymentStatus = "OFFLINE" // Collected outside the platform
+)
+
+// AssistedOrder records an order an agent placed on behalf of a phone or mail
+// (MOTO) customer: who placed it, the storefront rules they overrode and how
+// the order is paid.
+type AssistedOrder struct {
+	OrderID            int64
+	Channel            AssistedOrderChannel
+	AgentID            string
+	Guest              bool // Placed for a customer without an account
+	PriceOverridden    bool // At least one item is charged at a price set by the agent
+	CartPolicyBypassed bool // Storefront-only cart policy checks were skipped
+	PaymentMethod      AssistedPaymentMethod
+	PaymentStatus      AssistedPaymentStatus
+	TransactionID      *string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// NewAssistedOrder creates the assisted order record of an order
+func NewAssistedOrder(orderID int64, channel AssistedOrderChannel, agentID string, method AssistedPaymentMethod) (*AssistedOrder, error) {
+	if orderID == 0 {
+		return nil, NewDomainError("OrderID cannot be zero for an assisted order")
+	}
+	if channel != AssistedOrderChannelPhone && channel != AssistedOrderChannelMail {
+		return nil, NewDomainError("channel must be PHONE or MAIL")
+	}
+	if agentID == "" {
+		return nil, NewDomainError("an assisted order requires the agent placing it")
+	}
+	if !IsAssistedPaymentMethod(method) {
+		return nil, NewDomainError("payment method must be CARD, PAY_BY_LINK or OFFLINE")
+	}
+
+	now := time.Now()
+	return &AssistedOrder{
+		OrderID:       orderID,
+		Channel:       channel,
+		AgentID:       agentID,
+		PaymentMethod: method,
+		PaymentStatus: AssistedPaymentStatusAwaiting,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// IsAssistedPaymentMethod checks if a payment method can be chosen for an assisted order
+func IsAssistedPaymentMethod(method AssistedPaymentMethod) bool {
+	switch method {
+	case AssistedPaymentCard, AssistedPaymentPayByLink, AssistedPaymentOffline:
+		return true
+	}
+	return false
+}
+
+// MarkAuthorized records the authorized payment of the order
+func (a *AssistedOrder) MarkAuthorized(transactionID string) {
+	a.TransactionID = &transactionID
+	a.PaymentStatus = AssistedPaymentStatusAuthorized
+	a.UpdatedAt = time.Now()
+}
+
+// MarkPaidOffline records that the payment is collected outside the platform
+func (a *AssistedOrder) MarkPaidOffline() {
+	a.PaymentStatus = AssistedPaymentStatusOffline
+	a.UpdatedAt = time.Now()
+}
+
+// AssistedOrderRepository defines the interface for assisted order persistence
+type AssistedOrderRepository interface {
+	// Save creates or updates the assisted order record of an order
+	Save(ctx context.Context, order *AssistedOrder) error
+
+	// FindByOrderID returns the assisted order record of an order, or nil when
+	// the order was placed on the storefront
+	FindByOrderID(ctx context.Context, orderID int64) (*AssistedOrder, error)
+}
+
+// AssistedOrderCustomer is the customer an agent places an order for
+type AssistedOrderCustomer struct {
+	ID           int64
+	EmailAddress string
+	FirstName    string
+	LastName     string
+	Registered   bool
+}
+
+// FullName returns the name an order is placed under
+func (c *AssistedOrderCustomer) FullName() string {
+	if c.LastName == "" {
+		return c.FirstName
+	}
+	if c.FirstName == "" {
+		return c.LastName
+	}
+	return c.FirstName + " " + c.LastName
+}
+
+// AssistedOrderCustomerRepository looks up and creates the customers agents place orders for
+type AssistedOrderCustomerRepository interface {
+	// FindByID returns an active customer, or nil
+	FindByID(ctx context.Context, id int64) (*AssistedOrderCustomer, error)
+
+	// FindByEmail returns the active customer with an email address, or nil
+	FindByEmail(ctx context.Context, email string) (*AssistedOrderCustomer, error)
+
+	// CreateGuest creates an unregistered customer and sets its ID
+	CreateGuest(ctx context.Context, customer *AssistedOrderCustomer) error
+}
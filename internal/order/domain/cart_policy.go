@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CartViolationCode identifies the cart policy rule an order breaks
+type CartViolationCode string
+
+const (
+	// ViolationMaxQuantityPerSKU means a SKU is ordered in a larger quantity than allowed
+	ViolationMaxQuantityPerSKU CartViolationCode = "MAX_QUANTITY_PER_SKU"
+	// ViolationMaxDistinctLines means the order has more distinct SKUs than allowed
+	ViolationMaxDistinctLines CartViolationCode = "MAX_DISTINCT_LINES"
+	// ViolationRestrictedSegment means the customer's segment may not buy from a category
+	ViolationRestrictedSegment CartViolationCode = "RESTRICTED_CATEGORY_SEGMENT"
+	// ViolationRestrictedDestination means a category may not be shipped to the destination country
+	ViolationRestrictedDestination CartViolationCode = "RESTRICTED_CATEGORY_DESTINATION"
+)
+
+// CartPolicyViolation describes one broken cart policy rule
+type CartPolicyViolation struct {
+	Code       CartViolationCode `json:"code"`
+	Message    string            `json:"message"`
+	SKUID      int64             `json:"sku_id,omitempty"`
+	CategoryID int64             `json:"category_id,omitempty"`
+}
+
+// CategoryRestriction limits who may buy products of a category and where they may ship
+type CategoryRestriction struct {
+	CategoryID       int64
+	AllowedSegments  []string // Customer segments allowed to purchase; empty allows everyone
+	BlockedCountries []string // ISO alpha-2 destination countries the category cannot ship to
+}
+
+// CartPolicy holds the line item limits and purchase rules orders are validated against
+type CartPolicy struct {
+	MaxQuantityPerSKU    int // 0 disables the limit
+	MaxDistinctLines     int // 0 disables the limit
+	CategoryRestrictions []CategoryRestriction
+}
+
+// CartPolicyContext carries the customer and destination facts the category rules need
+type CartPolicyContext struct {
+	CustomerSegments   []string
+	DestinationCountry string // Empty while the shipping address is unknown
+}
+
+// Evaluate checks order items against the policy and returns every violation found
+func (p *CartPolicy) Evaluate(items []*OrderItem, pctx CartPolicyContext) []CartPolicyViolation {
+	violations := make([]CartPolicyViolation, 0)
+
+	// Quantities are summed per SKU, the same SKU may appear on several lines
+	quantities := make(map[int64]int)
+	skuOrder := make([]int64, 0)
+	for _, item := range items {
+		if _, seen := quantities[item.SKUID]; !seen {
+			skuOrder = append(skuOrder, item.SKUID)
+		}
+		quantities[item.SKUID] += item.Quantity
+	}
+
+	if p.MaxQuantityPerSKU > 0 {
+		for _, skuID := range skuOrder {
+			if quantities[skuID] > p.MaxQuantityPerSKU {
+				violations = append(violations, CartPolicyViolation{
+					Code:    ViolationMaxQuantityPerSKU,
+					Message: fmt.Sprintf("SKU %d quantity %d exceeds the maximum of %d", skuID, quantities[skuID], p.MaxQuantityPerSKU),
+					SKUID:   skuID,
+				})
+			}
+		}
+	}
+
+	if p.MaxDistinctLines > 0 && len(skuOrder) > p.MaxDistinctLines {
+		violations = append(violations, CartPolicyViolation{
+			Code:    ViolationMaxDistinctLines,
+			Message: fmt.Sprintf("order has %d distinct items, the maximum is %d", len(skuOrder), p.MaxDistinctLines),
+		})
+	}
+
+	for _, item := range items {
+		if item.CategoryID == nil {
+			continue
+		}
+		for _, restriction := range p.CategoryRestrictions {
+			if restriction.CategoryID != *item.CategoryID {
+				continue
+			}
+			if len(restriction.AllowedSegments) > 0 && !containsFold(restriction.AllowedSegments, pctx.CustomerSegments...) {
+				violations = append(violations, CartPolicyViolation{
+					Code:       ViolationRestrictedSegment,
+					Message:    fmt.Sprintf("SKU %d belongs to a category the customer is not allowed to purchase", item.SKUID),
+					SKUID:      item.SKUID,
+					CategoryID: restriction.CategoryID,
+				})
+			}
+			if pctx.DestinationCountry != "" && containsFold(restriction.BlockedCountries, pctx.DestinationCountry) {
+				violations = append(violations, CartPolicyViolation{
+					Code:       ViolationRestrictedDestination,
+					Message:    fmt.Sprintf("SKU %d cannot be shipped to %s", item.SKUID, strings.ToUpper(pctx.DestinationCountry)),
+					SKUID:      item.SKUID,
+					CategoryID: restriction.CategoryID,
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// containsFold reports whether any of the values is in the list, ignoring case
+func containsFold(list []string, values ...string) bool {
+	for _, value := range values {
+		for _, entry := range list {
+			if strings.EqualFold(entry, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CartPolicyContextRepository looks up the facts category restrictions are evaluated on
+type CartPolicyContextRepository interface {
+	// FindCustomerSegments returns the segments (role names and tags) of a customer
+	FindCustomerSegments(ctx context.Context, customerID int64) ([]string, error)
+
+	// FindDestinationCountry returns the ISO alpha-2 country of the order's primary
+	// shipping address, or an empty string when none is set
+	FindDestinationCountry(ctx context.Context, orderID int64) (string, error)
+}
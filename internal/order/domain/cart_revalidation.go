@@ -0,0 +1,82 @@
+package domain
+
+// CartChangeType is how revalidating a cart changed one of its lines
+type CartChangeType string
+
+const (
+	CartChangePriceChanged    CartChangeType = "PRICE_CHANGED"
+	CartChangeQuantityReduced CartChangeType = "QUANTITY_REDUCED"
+	CartChangeRemoved         CartChangeType = "REMOVED"
+)
+
+// Reasons a line was reduced or removed
+const (
+	CartChangeReasonUnavailable   = "unavailable"    // The SKU was deleted, deactivated or is outside its active dates
+	CartChangeReasonSoldOut       = "sold_out"       // No stock is left for the line
+	CartChangeReasonLowStock      = "low_stock"      // Only part of the line's quantity is left
+	CartChangeReasonParentRemoved = "parent_removed" // An add-on or gift wrap of a removed line
+)
+
+// CartLineChange is a change made to a line of a cart when it was revalidated.
+// Prices are unit prices before offers.
+type CartLineChange struct {
+	OrderItemID      int64
+	SKUID            int64
+	Name             string
+	Type             CartChangeType
+	Reason           string
+	PreviousPrice    float64
+	Price            float64
+	PreviousQuantity int
+	Quantity         int
+}
+
+// OfferChangeType is how revalidating a cart changed the discount of an offer
+type OfferChangeType string
+
+const (
+	OfferChangeAdded   OfferChangeType = "ADDED"
+	OfferChangeRemoved OfferChangeType = "REMOVED"
+	OfferChangeAmount  OfferChangeType = "AMOUNT_CHANGED"
+)
+
+// CartOfferChange is a change in the discount an offer gives a cart
+type CartOfferChange struct {
+	OfferID        int64
+	Description    string
+	Type           OfferChangeType
+	PreviousAmount float64 // Negative, as adjustments are stored
+	Amount         float64
+}
+
+// EffectiveUnitPrice is the unit price charged for a SKU before offers: its sale
+// price when lower than its retail price
+func EffectiveUnitPrice(retailPrice, salePrice float64) float64 {
+	if salePrice > 0 && salePrice < retailPrice {
+		return salePrice
+	}
+	return retailPrice
+}
+
+// IsCart reports whether the order has not been submitted yet, so its lines
+// may still change
+func (o *Order) IsCart() bool {
+	if o.SubmitDate != nil {
+		return false
+	}
+	switch o.Status {
+	case OrderStatusPending, OrderStatusCustomerInfo, OrderStatusShipping, OrderStatusPayment, OrderStatusReview:
+		return true
+	}
+	return false
+}
+
+// StockShortfall returns how many units of a SKU are reserved beyond the stock
+// that can be promised for them; carts holding the SKU give up that many when
+// revalidated
+func StockShortfall(onHand, reserved, inbound int, allowBackorder bool) int {
+	if allowBackorder || reserved <= onHand+inbound {
+		return 0
+	}
+	return reserved - onHand - inbound
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ChannelOrderAttribute is the order attribute naming the sales channel an order came from
+const ChannelOrderAttribute = "channel"
+
+// ChannelExternalOrderAttribute is the order attribute holding the channel's own order ID
+const ChannelExternalOrderAttribute = "channel_order_id"
+
+// ChannelOrderStatus represents the ingestion state of an external order
+type ChannelOrderStatus string
+
+const (
+	ChannelOrderStatusPending ChannelOrderStatus = "PENDING" // Claimed for ingestion
+	ChannelOrderStatusCreated ChannelOrderStatus = "CREATED"
+	ChannelOrderStatusFailed  ChannelOrderStatus = "FAILED" // May be ingested again
+)
+
+// ChannelOrder records the ingestion of an order placed on an external sales
+// channel, such as a marketplace: the order it became, or why it failed, and
+// whether the outcome was acknowledged back to the channel.
+type ChannelOrder struct {
+	ID              int64
+	Channel         string
+	ExternalOrderID string
+	OrderID         *int64
+	Status          ChannelOrderStatus
+	FailureReason   string
+	Attempts        int
+	AcknowledgedAt  *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewChannelOrder creates the ingestion record of an external order
+func NewChannelOrder(channel, externalOrderID string) (*ChannelOrder, error) {
+	if channel == "" {
+		return nil, NewDomainError("a channel order requires its channel")
+	}
+	if externalOrderID == "" {
+		return nil, NewDomainError("a channel order requires the channel's order ID")
+	}
+
+	now := time.Now()
+	return &ChannelOrder{
+		Channel:         channel,
+		ExternalOrderID: externalOrderID,
+		Status:          ChannelOrderStatusPending,
+		Attempts:        1,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}, nil
+}
+
+// MarkCreated records the order the external order became
+func (o *ChannelOrder) MarkCreated(orderID int64) {
+	o.OrderID = &orderID
+	o.Status = ChannelOrderStatusCreated
+	o.FailureReason = ""
+	o.AcknowledgedAt = nil
+	o.UpdatedAt = time.Now()
+}
+
+// MarkFailed records why the external order could not be ingested
+func (o *ChannelOrder) MarkFailed(reason string) {
+	o.OrderID = nil
+	o.Status = ChannelOrderStatusFailed
+	o.FailureReason = reason
+	o.AcknowledgedAt = nil
+	o.UpdatedAt = time.Now()
+}
+
+// ExternalOrder is an order as a channel sends it, mapped from the channel's format
+type ExternalOrder struct {
+	ExternalOrderID string
+	EmailAddress    string
+	FirstName       string
+	LastName        string
+	CurrencyCode    string
+	LocaleCode      string
+	Items           []ExternalOrderItem
+}
+
+// ExternalOrderItem is a line of an external order
+type ExternalOrderItem struct {
+	ExternalSKU   string
+	Quantity      int
+	UnitPrice     *float64 // Price the channel sold at; nil charges the SKU's current price
+	ReservationID string   // Channel reservation holding the line's stock, if any
+}
+
+// ChannelSKUMapping maps a channel's SKU to an internal SKU
+type ChannelSKUMapping struct {
+	Channel     string
+	ExternalSKU string
+	SKUID       int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ChannelOrderFilter filters listed channel orders
+type ChannelOrderFilter struct {
+	Channel  string
+	Status   ChannelOrderStatus
+	Page     int
+	PageSize int
+}
+
+// ChannelOrderRepository defines the interface for channel order persistence
+type ChannelOrderRepository interface {
+	// Claim stores a pending record for an external order and sets its ID. An order that
+	// failed before is claimed again; it returns false when the order was already created
+	// or is being ingested.
+	Claim(ctx context.Context, order *ChannelOrder) (bool, error)
+
+	// Save updates the outcome of an ingestion
+	Save(ctx context.Context, order *ChannelOrder) error
+
+	// FindByExternalID returns the record of an external order, or nil
+	FindByExternalID(ctx context.Context, channel, externalOrderID string) (*ChannelOrder, error)
+
+	// FindAll lists channel orders, newest first, with the total count
+	FindAll(ctx context.Context, filter *ChannelOrderFilter) ([]*ChannelOrder, int64, error)
+
+	// MarkAcknowledged records that the outcome of the orders was reported to their channel
+	MarkAcknowledged(ctx context.Context, ids []int64, at time.Time) error
+}
+
+// ChannelSKUMappingRepository defines the interface for channel SKU mapping persistence
+type ChannelSKUMappingRepository interface {
+	// FindSKUIDs returns the internal SKU IDs of a channel's SKUs; unmapped SKUs are missing
+	FindSKUIDs(ctx context.Context, channel string, externalSKUs []string) (map[string]int64, error)
+
+	// FindByChannel lists the mappings of a channel
+	FindByChannel(ctx context.Context, channel string) ([]*ChannelSKUMapping, error)
+
+	// Save creates or updates a mapping
+	Save(ctx context.Context, mapping *ChannelSKUMapping) error
+
+	// Delete removes a mapping; it returns NotFound when there is none
+	Delete(ctx context.Context, channel, externalSKU string) error
+}
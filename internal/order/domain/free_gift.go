@@ -0,0 +1,32 @@
+package domain
+
+// OrderItemTypeFreeGift marks an order item added by a free gift offer. The line is
+// priced at retail and discounted in full by the offer, and removed by the offer
+// once the cart no longer qualifies.
+const OrderItemTypeFreeGift = "FREE_GIFT"
+
+// IsFreeGift checks if the item was added by a free gift offer
+func (oi *OrderItem) IsFreeGift() bool {
+	return oi.OrderItemType == OrderItemTypeFreeGift
+}
+
+// PaidItems returns the items of an order other than free gifts
+func PaidItems(items []*OrderItem) []*OrderItem {
+	var paid []*OrderItem
+	for _, item := range items {
+		if !item.IsFreeGift() {
+			paid = append(paid, item)
+		}
+	}
+	return paid
+}
+
+// PaidSubtotal is the subtotal of the items other than free gifts, which free gift
+// offers are qualified against
+func PaidSubtotal(items []*OrderItem) float64 {
+	subtotal := 0.0
+	for _, item := range PaidItems(items) {
+		subtotal += item.Price * float64(item.Quantity)
+	}
+	return subtotal
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// OrderItemTypeGiftWrap marks the child order item that prices the gift wrap of its parent
+const OrderItemTypeGiftWrap = "GIFT_WRAP"
+
+// maxPersonalMessageLength matches the blc_personal_message column sizes
+const maxPersonalMessageLength = 255
+
+// PersonalMessage is a gift message attached to an order item and printed on the packing slip
+type PersonalMessage struct {
+	ID          int64
+	Message     string
+	MessageFrom string
+	MessageTo   string
+	Occasion    string
+}
+
+// NewPersonalMessage creates a new PersonalMessage
+func NewPersonalMessage(message, from, to, occasion string) (*PersonalMessage, error) {
+	pm := &PersonalMessage{}
+	if err := pm.Update(message, from, to, occasion); err != nil {
+		return nil, err
+	}
+	return pm, nil
+}
+
+// Update replaces the message contents
+func (pm *PersonalMessage) Update(message, from, to, occasion string) error {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return NewDomainError("Message cannot be empty for PersonalMessage")
+	}
+	for _, value := range []string{message, from, to, occasion} {
+		if len(value) > maxPersonalMessageLength {
+			return NewDomainError("PersonalMessage fields cannot be longer than 255 characters")
+		}
+	}
+	pm.Message = message
+	pm.MessageFrom = strings.TrimSpace(from)
+	pm.MessageTo = strings.TrimSpace(to)
+	pm.Occasion = strings.TrimSpace(occasion)
+	return nil
+}
+
+// GiftWrapOption is a gift wrap offered on the storefront. Each option is sold
+// through a catalog SKU, which prices it and tracks its stock.
+type GiftWrapOption struct {
+	ID          int64
+	SKUID       int64
+	Name        string
+	Description string
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewGiftWrapOption creates a new active GiftWrapOption
+func NewGiftWrapOption(skuID int64, name, description string) (*GiftWrapOption, error) {
+	if skuID == 0 {
+		return nil, NewDomainError("SKUID cannot be zero for GiftWrapOption")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, NewDomainError("Name cannot be empty for GiftWrapOption")
+	}
+
+	now := time.Now()
+	return &GiftWrapOption{
+		SKUID:       skuID,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// PersonalMessageRepository defines the interface for personal message persistence
+type PersonalMessageRepository interface {
+	// Create stores a new personal message.
+	Create(ctx context.Context, message *PersonalMessage) error
+
+	// Update saves an existing personal message.
+	Update(ctx context.Context, message *PersonalMessage) error
+
+	// FindByID retrieves a personal message by its unique identifier.
+	FindByID(ctx context.Context, id int64) (*PersonalMessage, error)
+
+	// Delete removes a personal message by its unique identifier.
+	Delete(ctx context.Context, id int64) error
+}
+
+// GiftWrapOptionRepository defines the interface for gift wrap option persistence
+type GiftWrapOptionRepository interface {
+	// Save stores a new gift wrap option or updates an existing one.
+	Save(ctx context.Context, option *GiftWrapOption) error
+
+	// FindByID retrieves a gift wrap option by its unique identifier.
+	FindByID(ctx context.Context, id int64) (*GiftWrapOption, error)
+
+	// FindBySKUID retrieves the gift wrap option sold through a SKU.
+	FindBySKUID(ctx context.Context, skuID int64) (*GiftWrapOption, error)
+
+	// FindAll retrieves gift wrap options ordered by name, optionally only the active ones.
+	FindAll(ctx context.Context, activeOnly bool) ([]*GiftWrapOption, error)
+}
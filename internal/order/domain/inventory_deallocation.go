@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DeallocationStatus represents the state of an inventory deallocation
+type DeallocationStatus string
+
+const (
+	DeallocationStatusPending    DeallocationStatus = "PENDING"
+	DeallocationStatusCompleted  DeallocationStatus = "COMPLETED"
+	DeallocationStatusDeadLetter DeallocationStatus = "DEAD_LETTER" // Retries exhausted; needs reconciliation
+	DeallocationStatusResolved   DeallocationStatus = "RESOLVED"    // Reconciled by staff
+)
+
+// maxDeallocationBackoff caps the delay between deallocation retries
+const maxDeallocationBackoff = time.Hour
+
+// InventoryDeallocation is the compensating release of the stock reserved by an
+// order item once its order is cancelled. Deallocations are queued with the
+// cancellation and retried until they succeed or are dead-lettered.
+type InventoryDeallocation struct {
+	ID            int64
+	OrderID       int64
+	OrderItemID   int64
+	SKUID         int64
+	Quantity      int
+	Status        DeallocationStatus
+	Attempts      int
+	LastError     *string
+	NextAttemptAt time.Time
+	ResolvedBy    *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   *time.Time
+}
+
+// NewInventoryDeallocation creates a pending deallocation of an order item's stock,
+// first attempted after delay
+func NewInventoryDeallocation(orderID int64, item *OrderItem, delay time.Duration) *InventoryDeallocation {
+	now := time.Now()
+	return &InventoryDeallocation{
+		OrderID:       orderID,
+		OrderItemID:   item.ID,
+		SKUID:         item.SKUID,
+		Quantity:      item.Quantity,
+		Status:        DeallocationStatusPending,
+		NextAttemptAt: now.Add(delay),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Complete marks the stock as released
+func (d *InventoryDeallocation) Complete() {
+	now := time.Now()
+	d.Status = DeallocationStatusCompleted
+	d.Attempts++
+	d.LastError = nil
+	d.CompletedAt = &now
+	d.UpdatedAt = now
+}
+
+// Fail records a failed attempt and schedules the next one with exponential backoff.
+// Once maxAttempts is reached the deallocation is dead-lettered.
+func (d *InventoryDeallocation) Fail(err error, maxAttempts int, baseDelay time.Duration) {
+	now := time.Now()
+	message := err.Error()
+	d.Attempts++
+	d.LastError = &message
+	d.UpdatedAt = now
+
+	if d.Attempts >= maxAttempts {
+		d.Status = DeallocationStatusDeadLetter
+		return
+	}
+	backoff := baseDelay << (d.Attempts - 1)
+	if backoff <= 0 || backoff > maxDeallocationBackoff {
+		backoff = maxDeallocationBackoff
+	}
+	d.NextAttemptAt = now.Add(backoff)
+}
+
+// Requeue puts a dead-lettered deallocation back in the queue for immediate retry
+func (d *InventoryDeallocation) Requeue() error {
+	if d.Status != DeallocationStatusDeadLetter {
+		return NewDomainError("Only dead-lettered deallocations can be requeued")
+	}
+	now := time.Now()
+	d.Status = DeallocationStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.UpdatedAt = now
+	return nil
+}
+
+// Resolve records that staff reconciled the stock by hand
+func (d *InventoryDeallocation) Resolve(resolvedBy string) error {
+	if d.Status == DeallocationStatusCompleted || d.Status == DeallocationStatusResolved {
+		return NewDomainError("Deallocation is already settled")
+	}
+	now := time.Now()
+	d.Status = DeallocationStatusResolved
+	d.ResolvedBy = &resolvedBy
+	d.CompletedAt = &now
+	d.UpdatedAt = now
+	return nil
+}
+
+// IsUnresolved reports whether the deallocation has failed and is not settled yet
+func (d *InventoryDeallocation) IsUnresolved() bool {
+	return d.Status == DeallocationStatusDeadLetter || (d.Status == DeallocationStatusPending && d.Attempts > 0)
+}
+
+// InventoryDeallocationRepository defines the interface for the deallocation queue
+type InventoryDeallocationRepository interface {
+	// Enqueue stores deallocations, skipping order items that are already queued.
+	Enqueue(ctx context.Context, deallocations []*InventoryDeallocation) error
+
+	// ClaimDue leases up to limit pending deallocations due by now, so no other
+	// worker picks them up until the lease expires.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*InventoryDeallocation, error)
+
+	// ClaimByOrderID leases the pending deallocations of an order regardless of their schedule.
+	ClaimByOrderID(ctx context.Context, orderID int64, lease time.Duration) ([]*InventoryDeallocation, error)
+
+	// FindByID retrieves a deallocation by its ID.
+	FindByID(ctx context.Context, id int64) (*InventoryDeallocation, error)
+
+	// FindUnresolved retrieves dead-lettered deallocations and pending ones that
+	// have failed at least once, oldest first.
+	FindUnresolved(ctx context.Context) ([]*InventoryDeallocation, error)
+
+	// Update saves the state of a deallocation.
+	Update(ctx context.Context, deallocation *InventoryDeallocation) error
+}
@@ -0,0 +1,242 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// OfflinePaymentType is how an offline payment is collected
+type OfflinePaymentType string
+
+const (
+	OfflinePaymentCashOnDelivery OfflinePaymentType = "COD"           // Collected by the carrier on delivery
+	OfflinePaymentBankTransfer   OfflinePaymentType = "BANK_TRANSFER" // Wired by the customer quoting the payment reference
+	OfflinePaymentPayInStore     OfflinePaymentType = "PAY_IN_STORE"  // Paid at a store, usually on collection
+)
+
+// IsOfflinePaymentType checks if a type of offline payment is supported
+func IsOfflinePaymentType(t OfflinePaymentType) bool {
+	switch t {
+	case OfflinePaymentCashOnDelivery, OfflinePaymentBankTransfer, OfflinePaymentPayInStore:
+		return true
+	}
+	return false
+}
+
+var offlinePaymentCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// OfflinePaymentMethod is a way to pay outside the platform that admins offer
+// at checkout. It is offered for orders shipping to one of its countries (any
+// country when none is set) whose total is within its caps.
+type OfflinePaymentMethod struct {
+	ID            int64
+	Code          string // Chosen by customers at checkout, e.g. cod
+	Type          OfflinePaymentType
+	Name          string
+	Instructions  string // Shown to the customer once the order is placed, e.g. the bank details to wire to
+	Enabled       bool
+	Countries     []string // ISO 3166-1 alpha-2; empty offers the method everywhere
+	MinOrderTotal *float64
+	MaxOrderTotal *float64
+	SortOrder     int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewOfflinePaymentMethod creates an offline payment method
+func NewOfflinePaymentMethod(code string, paymentType OfflinePaymentType, name string) (*OfflinePaymentMethod, error) {
+	now := time.Now()
+	method := &OfflinePaymentMethod{
+		Code:      strings.ToLower(strings.TrimSpace(code)),
+		Enabled:   true,
+		CreatedAt: now,
+	}
+	if !offlinePaymentCodePattern.MatchString(method.Code) {
+		return nil, NewDomainError("code must be up to 64 lowercase letters, digits, dashes or underscores")
+	}
+	if err := method.Update(paymentType, name, method.Instructions, true, nil, nil, nil, 0); err != nil {
+		return nil, err
+	}
+	return method, nil
+}
+
+// Update replaces the settings of the method; its code is kept
+func (m *OfflinePaymentMethod) Update(paymentType OfflinePaymentType, name, instructions string, enabled bool,
+	countries []string, minOrderTotal, maxOrderTotal *float64, sortOrder int) error {
+	if !IsOfflinePaymentType(paymentType) {
+		return NewDomainError("type must be COD, BANK_TRANSFER or PAY_IN_STORE")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return NewDomainError("name is required")
+	}
+	normalized := make([]string, 0, len(countries))
+	seen := make(map[string]bool, len(countries))
+	for _, country := range countries {
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if len(country) != 2 {
+			return NewDomainError(fmt.Sprintf("country %q must be an ISO 3166-1 alpha-2 code", country))
+		}
+		if !seen[country] {
+			seen[country] = true
+			normalized = append(normalized, country)
+		}
+	}
+	if minOrderTotal != nil && *minOrderTotal < 0 {
+		return NewDomainError("min_order_total cannot be negative")
+	}
+	if maxOrderTotal != nil && *maxOrderTotal <= 0 {
+		return NewDomainError("max_order_total must be greater than zero")
+	}
+	if minOrderTotal != nil && maxOrderTotal != nil && *minOrderTotal > *maxOrderTotal {
+		return NewDomainError("min_order_total cannot be above max_order_total")
+	}
+
+	m.Type = paymentType
+	m.Name = name
+	m.Instructions = strings.TrimSpace(instructions)
+	m.Enabled = enabled
+	m.Countries = normalized
+	m.MinOrderTotal = minOrderTotal
+	m.MaxOrderTotal = maxOrderTotal
+	m.SortOrder = sortOrder
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+// Unavailable returns why the method cannot pay an order shipping to country
+// with the total, or an empty string when it can. An unknown country only
+// passes methods offered everywhere.
+func (m *OfflinePaymentMethod) Unavailable(country string, orderTotal float64) string {
+	if !m.Enabled {
+		return "the payment method is disabled"
+	}
+	if len(m.Countries) > 0 {
+		country = strings.ToUpper(country)
+		offered := false
+		for _, c := range m.Countries {
+			if c == country {
+				offered = true
+				break
+			}
+		}
+		if !offered {
+			return "the payment method is not offered for the shipping country"
+		}
+	}
+	if m.MinOrderTotal != nil && roundCents(orderTotal) < roundCents(*m.MinOrderTotal) {
+		return fmt.Sprintf("the order total is below the minimum of %.2f for this payment method", *m.MinOrderTotal)
+	}
+	if m.MaxOrderTotal != nil && roundCents(orderTotal) > roundCents(*m.MaxOrderTotal) {
+		return fmt.Sprintf("the order total is above the maximum of %.2f for this payment method", *m.MaxOrderTotal)
+	}
+	return ""
+}
+
+// OfflinePaymentStatus represents the state of an offline payment
+type OfflinePaymentStatus string
+
+const (
+	OfflinePaymentStatusAwaiting OfflinePaymentStatus = "AWAITING" // The order waits in AWAITING_PAYMENT
+	OfflinePaymentStatusReceived OfflinePaymentStatus = "RECEIVED"
+)
+
+// OfflinePayment is the offline payment an order was placed with. The amount
+// is fixed when the order is placed; receipt of it submits the order.
+type OfflinePayment struct {
+	OrderID          int64
+	MethodID         int64
+	MethodCode       string
+	Type             OfflinePaymentType
+	Amount           float64
+	CurrencyCode     string
+	Reference        string // Quoted by the customer with the payment, e.g. on the bank transfer
+	Status           OfflinePaymentStatus
+	ReceivedAmount   *float64
+	ReceiptReference string // Where the money was seen, e.g. the bank statement entry
+	ReceivedBy       string
+	ReceivedAt       *time.Time
+	Note             string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// NewOfflinePayment creates the offline payment of an order's total
+func NewOfflinePayment(orderID int64, method *OfflinePaymentMethod, amount float64, currencyCode, reference string) (*OfflinePayment, error) {
+	if orderID == 0 {
+		return nil, NewDomainError("OrderID cannot be zero for an offline payment")
+	}
+	if amount <= 0 {
+		return nil, NewDomainError("only orders with an amount to pay can be paid offline")
+	}
+	now := time.Now()
+	return &OfflinePayment{
+		OrderID:      orderID,
+		MethodID:     method.ID,
+		MethodCode:   method.Code,
+		Type:         method.Type,
+		Amount:       amount,
+		CurrencyCode: currencyCode,
+		Reference:    reference,
+		Status:       OfflinePaymentStatusAwaiting,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// RecordReceipt records that the payment was received in full
+func (p *OfflinePayment) RecordReceipt(amount float64, receiptReference, receivedBy, note string, receivedAt time.Time) error {
+	if p.Status == OfflinePaymentStatusReceived {
+		return NewDomainError("the payment has already been received")
+	}
+	if roundCents(amount) != roundCents(p.Amount) {
+		return NewDomainError(fmt.Sprintf("the amount received must be the %.2f %s due", p.Amount, p.CurrencyCode))
+	}
+	p.Status = OfflinePaymentStatusReceived
+	p.ReceivedAmount = &amount
+	p.ReceiptReference = strings.TrimSpace(receiptReference)
+	p.ReceivedBy = receivedBy
+	p.ReceivedAt = &receivedAt
+	p.Note = strings.TrimSpace(note)
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+func roundCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// OfflinePaymentMethodRepository defines the interface for offline payment method persistence
+type OfflinePaymentMethodRepository interface {
+	// Save creates or updates a method and sets its ID
+	Save(ctx context.Context, method *OfflinePaymentMethod) error
+
+	// Delete removes a method
+	Delete(ctx context.Context, id int64) error
+
+	// FindByID returns a method, or nil
+	FindByID(ctx context.Context, id int64) (*OfflinePaymentMethod, error)
+
+	// FindByCode returns the method with a code, or nil
+	FindByCode(ctx context.Context, code string) (*OfflinePaymentMethod, error)
+
+	// FindAll returns the methods in display order, only enabled ones when enabledOnly is set
+	FindAll(ctx context.Context, enabledOnly bool) ([]*OfflinePaymentMethod, error)
+}
+
+// OfflinePaymentRepository defines the interface for the offline payments of orders
+type OfflinePaymentRepository interface {
+	// Save creates or updates the offline payment of an order
+	Save(ctx context.Context, payment *OfflinePayment) error
+
+	// FindByOrderID returns the offline payment of an order, or nil
+	FindByOrderID(ctx context.Context, orderID int64) (*OfflinePayment, error)
+
+	// FindAwaiting returns the payments not received yet of orders still
+	// awaiting payment, oldest first
+	FindAwaiting(ctx context.Context, limit int) ([]*OfflinePayment, error)
+}
@@ -19,8 +19,42 @@ const (
 	OrderStatusCancelled    OrderStatus = "CANCELLED"
 	OrderStatusRefunded     OrderStatus = "REFUNDED"
 	OrderStatusFulfilled    OrderStatus = "FULFILLED"
+	OrderStatusOnHold       OrderStatus = "ON_HOLD" // Fulfillment paused for review; see OrderHold
+
+	// Placed with an offline payment (see OfflinePayment); submitted once it is received
+	OrderStatusAwaitingPayment OrderStatus = "AWAITING_PAYMENT"
+)
+
+// TaxMode selects when the tax of an order is calculated
+type TaxMode string
+
+const (
+	TaxModeImmediate TaxMode = "immediate" // Calculated on every cart change
+	TaxModeDeferred  TaxMode = "deferred"  // Estimated in the cart and calculated once at submission
+)
+
+// OrderChannel is the sales channel an order was placed through
+type OrderChannel string
+
+const (
+	OrderChannelWeb         OrderChannel = "WEB"
+	OrderChannelMobileApp   OrderChannel = "MOBILE_APP"
+	OrderChannelMarketplace OrderChannel = "MARKETPLACE"
+	OrderChannelMOTO        OrderChannel = "MOTO" // Mail or telephone order taken by an agent
 )
 
+// OrderChannels lists the known sales channels in reporting order
+var OrderChannels = []OrderChannel{OrderChannelWeb, OrderChannelMobileApp, OrderChannelMarketplace, OrderChannelMOTO}
+
+// IsValid checks if the channel is known
+func (c OrderChannel) IsValid() bool {
+	switch c {
+	case OrderChannelWeb, OrderChannelMobileApp, OrderChannelMarketplace, OrderChannelMOTO:
+		return true
+	}
+	return false
+}
+
 // Order represents an order entity
 type Order struct {
 	ID            int64
@@ -32,15 +66,27 @@ type Order struct {
 	OrderSubtotal float64 // From blc_order.order_subtotal
 	TotalTax      float64
 	TotalShipping float64
+	TotalDuties   float64 // Duties and import fees charged with a DDP cross-border order; see OrderLandedCost
 	OrderTotal    float64 // From blc_order.order_total
 	CurrencyCode  string
-	IsPreview     bool   // From blc_order.is_preview
-	TaxOverride   bool   // From blc_order.tax_override
-	LocaleCode    string // From blc_order.locale_code
+	IsPreview     bool         // From blc_order.is_preview
+	TaxOverride   bool         // From blc_order.tax_override
+	LocaleCode    string       // From blc_order.locale_code
+	Channel       OrderChannel // Set at creation and never changed
 	SubmitDate    *time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	Items         []OrderItem
+	Attributes    map[string]string // Name -> value, e.g. the sales channel an order came from
+
+	// Set when deferred tax is calculated at submission: the estimate the cart
+	// carried, the difference the calculation made, and who calculated it
+	EstimatedTax      *float64
+	TaxReconciliation *float64
+	TaxProvider       string
+
+	// Read from the archive tables; archived orders must be restored before they change
+	Archived bool
 }
 
 // NewOrder creates a new order
@@ -82,7 +128,50 @@ func (o *Order) recalculateSubtotal() {
 }
 
 func (o *Order) recalculateTotal() {
-	o.OrderTotal = o.OrderSubtotal + o.TotalTax + o.TotalShipping
+	o.OrderTotal = o.OrderSubtotal + o.TotalTax + o.TotalShipping + o.TotalDuties
+}
+
+// RecalculateTotals sets the subtotal and tax from all of the order's items, children
+// included, and the order-level adjustments
+func (o *Order) RecalculateTotals(items []*OrderItem, adjustments []*OrderAdjustment) {
+	o.OrderSubtotal = 0
+	o.TotalTax = 0
+	for _, item := range items {
+		o.OrderSubtotal += item.TotalPrice
+		o.TotalTax += item.TaxAmount
+	}
+	for _, adj := range adjustments {
+		o.OrderSubtotal += adj.AdjustmentValue
+	}
+	o.recalculateTotal()
+	o.UpdatedAt = time.Now()
+}
+
+// ReconcileTax replaces the estimated tax of the items with the calculated amounts
+// (by item ID), adds the tax on the shipping of the fulfillment groups (by group
+// ID) and records the difference on the order
+func (o *Order) ReconcileTax(items []*OrderItem, adjustments []*OrderAdjustment, groups []*FulfillmentGroup, itemTaxes, groupTaxes map[int64]float64, provider string) {
+	estimated := 0.0
+	for _, item := range items {
+		estimated += item.TaxAmount
+		item.SetTaxAmount(itemTaxes[item.ID])
+	}
+	for i := range o.Items {
+		o.Items[i].SetTaxAmount(itemTaxes[o.Items[i].ID])
+	}
+
+	o.RecalculateTotals(items, adjustments)
+	for _, group := range groups {
+		group.TotalFgTax = groupTaxes[group.ID]
+		group.TotalTax = group.TotalFgTax + group.TotalItemTax + group.TotalFeeTax
+		o.TotalTax += group.TotalFgTax
+	}
+	o.recalculateTotal()
+
+	difference := o.TotalTax - estimated
+	o.EstimatedTax = &estimated
+	o.TaxReconciliation = &difference
+	o.TaxProvider = provider
 }
 
 // UpdateStatus updates the order status
@@ -109,7 +198,13 @@ func (o *Order) Cancel() {
 
 // IsCancellable checks if order can be cancelled
 func (o *Order) IsCancellable() bool {
-	return o.Status == OrderStatusPending || o.Status == OrderStatusProcessing
+	return o.Status == OrderStatusPending || o.Status == OrderStatusAwaitingPayment ||
+		o.Status == OrderStatusProcessing || o.Status == OrderStatusOnHold
+}
+
+// CanBeHeld checks if the order has been submitted and has not shipped yet
+func (o *Order) CanBeHeld() bool {
+	return o.Status == OrderStatusSubmitted || o.Status == OrderStatusProcessing || o.Status == OrderStatusConfirmed
 }
 
 // OrderFilter represents filtering and pagination options for orders
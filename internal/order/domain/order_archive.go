@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MaxRetentionMonths caps the retention window of an order status
+const MaxRetentionMonths = 240
+
+// IsArchivable reports whether orders in a status are final, so they may be
+// moved to the archive once past their retention window
+func (s OrderStatus) IsArchivable() bool {
+	switch s {
+	case OrderStatusDelivered, OrderStatusFulfilled, OrderStatusCancelled, OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// OrderRetentionPolicy is how long orders in a final status stay in the live
+// order tables after their last update before they are archived
+type OrderRetentionPolicy struct {
+	Status          OrderStatus
+	RetentionMonths int // 0 keeps the orders live
+	UpdatedBy       string
+	UpdatedAt       time.Time
+}
+
+// NewOrderRetentionPolicy creates the retention policy of an order status
+func NewOrderRetentionPolicy(status OrderStatus, retentionMonths int, updatedBy string) (*OrderRetentionPolicy, error) {
+	if !status.IsArchivable() {
+		return nil, NewDomainError("Only delivered, fulfilled, cancelled and refunded orders can be archived")
+	}
+	if retentionMonths < 0 || retentionMonths > MaxRetentionMonths {
+		return nil, NewDomainError("Retention must be between 0 and 240 months")
+	}
+	return &OrderRetentionPolicy{
+		Status:          status,
+		RetentionMonths: retentionMonths,
+		UpdatedBy:       updatedBy,
+		UpdatedAt:       time.Now(),
+	}, nil
+}
+
+// IsEnabled reports whether the policy archives orders at all
+func (p *OrderRetentionPolicy) IsEnabled() bool {
+	return p.RetentionMonths > 0
+}
+
+// Cutoff returns the time before which orders last updated are past retention
+func (p *OrderRetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, -p.RetentionMonths, 0)
+}
+
+// OrderArchiveRepository defines the interface for moving orders between the
+// live order tables and the archive. Archived orders are still found by
+// OrderRepository.FindByID and FindByOrderNumber, flagged as Archived.
+type OrderArchiveRepository interface {
+	// FindPolicies retrieves the retention policies of every status that has one.
+	FindPolicies(ctx context.Context) ([]*OrderRetentionPolicy, error)
+
+	// SavePolicy creates or replaces the retention policy of a status.
+	SavePolicy(ctx context.Context, policy *OrderRetentionPolicy) error
+
+	// ArchiveBefore moves up to batchSize orders in status last updated before
+	// cutoff, with their items, adjustments and attributes, to the archive and
+	// returns how many were moved.
+	ArchiveBefore(ctx context.Context, status OrderStatus, cutoff time.Time, batchSize int) (int64, error)
+
+	// Restore moves an archived order back to the live tables. It fails with
+	// not found if the order is not archived.
+	Restore(ctx context.Context, orderID int64) error
+}
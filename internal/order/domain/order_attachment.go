@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentKind classifies a file attached to an order
+type AttachmentKind string
+
+const (
+	AttachmentKindPurchaseOrder AttachmentKind = "PURCHASE_ORDER"
+	AttachmentKindCustoms       AttachmentKind = "CUSTOMS"
+	AttachmentKindDamagePhoto   AttachmentKind = "DAMAGE_PHOTO" // Evidence for a return or claim
+	AttachmentKindOther         AttachmentKind = "OTHER"
+)
+
+// IsValid checks if the kind is known
+func (k AttachmentKind) IsValid() bool {
+	switch k {
+	case AttachmentKindPurchaseOrder, AttachmentKindCustoms, AttachmentKindDamagePhoto, AttachmentKindOther:
+		return true
+	}
+	return false
+}
+
+// AttachmentVisibility controls who can see and download an attachment
+type AttachmentVisibility string
+
+const (
+	AttachmentVisibilityInternal AttachmentVisibility = "INTERNAL" // Staff only
+	AttachmentVisibilityCustomer AttachmentVisibility = "CUSTOMER" // Also the customer of the order
+)
+
+// IsValid checks if the visibility is known
+func (v AttachmentVisibility) IsValid() bool {
+	return v == AttachmentVisibilityInternal || v == AttachmentVisibilityCustomer
+}
+
+// maxAttachmentFileNameLength bounds the stored file name; longer names keep their extension
+const maxAttachmentFileNameLength = 200
+
+// OrderAttachment is a file attached to an order, such as a customer purchase order,
+// customs paperwork or photos of damaged items. The file itself is kept in object
+// storage under StorageKey.
+type OrderAttachment struct {
+	ID                 int64
+	OrderID            int64
+	Kind               AttachmentKind
+	Visibility         AttachmentVisibility
+	FileName           string
+	ContentType        string
+	SizeBytes          int64
+	Checksum           string // SHA-256 of the content, hex encoded
+	StorageKey         string
+	Description        string
+	UploadedBy         string // Admin user ID, or customer ID when UploadedByCustomer
+	UploadedByCustomer bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// NewOrderAttachment creates an attachment for content uploaded to an order
+func NewOrderAttachment(
+	orderID int64,
+	kind AttachmentKind,
+	visibility AttachmentVisibility,
+	fileName, contentType, description, uploadedBy string,
+	uploadedByCustomer bool,
+	content []byte,
+) (*OrderAttachment, error) {
+	if orderID == 0 {
+		return nil, NewDomainError("OrderID cannot be zero for OrderAttachment")
+	}
+	if kind == "" {
+		kind = AttachmentKindOther
+	}
+	if !kind.IsValid() {
+		return nil, NewDomainError(fmt.Sprintf("unknown attachment kind %q", kind))
+	}
+	if !visibility.IsValid() {
+		return nil, NewDomainError(fmt.Sprintf("unknown attachment visibility %q", visibility))
+	}
+	if len(content) == 0 {
+		return nil, NewDomainError("attachment is empty")
+	}
+	if contentType == "" {
+		return nil, NewDomainError("ContentType cannot be empty for OrderAttachment")
+	}
+	fileName = cleanAttachmentFileName(fileName)
+	if fileName == "" {
+		return nil, NewDomainError("FileName cannot be empty for OrderAttachment")
+	}
+
+	sum := sha256.Sum256(content)
+	now := time.Now()
+	return &OrderAttachment{
+		OrderID:            orderID,
+		Kind:               kind,
+		Visibility:         visibility,
+		FileName:           fileName,
+		ContentType:        contentType,
+		SizeBytes:          int64(len(content)),
+		Checksum:           hex.EncodeToString(sum[:]),
+		StorageKey:         fmt.Sprintf("orders/%d/%s", orderID, uuid.New().String()),
+		Description:        strings.TrimSpace(description),
+		UploadedBy:         uploadedBy,
+		UploadedByCustomer: uploadedByCustomer,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}, nil
+}
+
+// SetVisibility changes who can see the attachment
+func (a *OrderAttachment) SetVisibility(visibility AttachmentVisibility) error {
+	if !visibility.IsValid() {
+		return NewDomainError(fmt.Sprintf("unknown attachment visibility %q", visibility))
+	}
+	a.Visibility = visibility
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsCustomerVisible checks if the customer of the order can see the attachment
+func (a *OrderAttachment) IsCustomerVisible() bool {
+	return a.Visibility == AttachmentVisibilityCustomer
+}
+
+// cleanAttachmentFileName keeps the base name of an uploaded file without control
+// characters or quotes, which would break the Content-Disposition header of downloads
+func cleanAttachmentFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > maxAttachmentFileNameLength {
+		ext := []rune(path.Ext(name))
+		if len(ext) > 16 {
+			ext = nil
+		}
+		name = string(runes[:maxAttachmentFileNameLength-len(ext)]) + string(ext)
+	}
+	return name
+}
+
+// OrderAttachmentRepository defines the interface for order attachment persistence
+type OrderAttachmentRepository interface {
+	// Create stores a new attachment and sets its ID.
+	Create(ctx context.Context, attachment *OrderAttachment) error
+
+	// FindByID retrieves an attachment of an order.
+	FindByID(ctx context.Context, orderID, id int64) (*OrderAttachment, error)
+
+	// FindByOrderID retrieves the attachments of an order, oldest first.
+	// When customerVisibleOnly is set, internal attachments are excluded.
+	FindByOrderID(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*OrderAttachment, error)
+
+	// CountCustomerUploads counts the attachments the customer uploaded to an order.
+	CountCustomerUploads(ctx context.Context, orderID int64) (int, error)
+
+	// UpdateVisibility stores the visibility of an attachment.
+	UpdateVisibility(ctx context.Context, attachment *OrderAttachment) error
+
+	// Delete removes an attachment.
+	Delete(ctx context.Context, orderID, id int64) error
+}
@@ -0,0 +1,381 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/money"
+)
+
+// OrderEditStatus is the state of an order edit
+type OrderEditStatus string
+
+const (
+	OrderEditOpen      OrderEditStatus = "OPEN"      // Lines are being changed; the order is untouched
+	OrderEditCommitted OrderEditStatus = "COMMITTED" // The changes were confirmed and applied to the order
+	OrderEditCancelled OrderEditStatus = "CANCELLED"
+	OrderEditExpired   OrderEditStatus = "EXPIRED" // Left open past its expiry and replaced by a new edit
+)
+
+// OrderEditChange is how an edit changes a line of the order
+type OrderEditChange string
+
+const (
+	OrderEditUnchanged       OrderEditChange = "UNCHANGED"
+	OrderEditAdded           OrderEditChange = "ADDED"
+	OrderEditQuantityChanged OrderEditChange = "QUANTITY_CHANGED"
+	OrderEditRemoved         OrderEditChange = "REMOVED"
+)
+
+// OrderEdit stages changes to the lines of a submitted order that has not shipped.
+// The lines of the order are snapshotted when the edit opens and keep the prices,
+// discounts and tax they were sold with; only added lines are priced again, at the
+// current prices, offers and tax. The changes are applied, and stock reserved or
+// released, only when the edit is committed after its delta was previewed. An
+// order has at most one open edit.
+type OrderEdit struct {
+	ID                int64
+	OrderID           int64
+	CustomerID        int64
+	Status            OrderEditStatus
+	OpenedBy          string
+	CurrencyCode      string
+	Original          OrderEditTotals // Of the order when the edit opened
+	Lines             []*OrderEditLine
+	Revision          int               // Bumped by every change to the lines
+	PreviewedRevision int               // Revision the summary was previewed at; 0 before the first preview
+	Summary           *OrderEditSummary // Delta of the last preview
+	ExpiresAt         time.Time
+	ClosedBy          string
+	ClosedAt          *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// OrderEditTotals are the totals of an order
+type OrderEditTotals struct {
+	Subtotal float64 `json:"subtotal"`
+	Tax      float64 `json:"tax"`
+	Shipping float64 `json:"shipping"`
+	Duties   float64 `json:"duties"`
+	Total    float64 `json:"total"`
+}
+
+// OrderEditLine is a line of an edited order. Lines of the order keep the unit price
+// they were sold at; added lines carry the price, discounts and tax they were
+// previewed with.
+type OrderEditLine struct {
+	LineID            int                  `json:"line_id"`
+	OrderItemID       int64                `json:"order_item_id,omitempty"` // 0 for added lines
+	ParentOrderItemID *int64               `json:"parent_order_item_id,omitempty"`
+	SKUID             int64                `json:"sku_id"`
+	ProductID         int64                `json:"product_id"`
+	Name              string               `json:"name"`
+	TaxCategory       string               `json:"tax_category,omitempty"`
+	FreeGift          bool                 `json:"free_gift,omitempty"`
+	OriginalQuantity  int                  `json:"original_quantity"` // 0 for added lines
+	Quantity          int                  `json:"quantity"`          // 0 once removed
+	RetailPrice       float64              `json:"retail_price"`
+	SalePrice         float64              `json:"sale_price"`
+	UnitPrice         float64              `json:"unit_price"` // Charged per unit, after item discounts
+	OriginalTotal     float64              `json:"original_total"`
+	OriginalTax       float64              `json:"original_tax"`
+	Total             float64              `json:"total"`
+	Tax               float64              `json:"tax"`
+	Discounts         []*OrderEditDiscount `json:"discounts,omitempty"` // Offers applied to an added line
+	Priced            bool                 `json:"priced,omitempty"`    // An added line was priced by a preview
+}
+
+// OrderEditDiscount is the discount an offer gives an added line
+type OrderEditDiscount struct {
+	OfferID            int64   `json:"offer_id"`
+	Description        string  `json:"description"`
+	Amount             float64 `json:"amount"` // Negative, as adjustments are stored
+	AppliedToSalePrice bool    `json:"applied_to_sale_price,omitempty"`
+}
+
+// Change returns how the edit changes the line
+func (l *OrderEditLine) Change() OrderEditChange {
+	switch {
+	case l.OrderItemID == 0:
+		return OrderEditAdded
+	case l.Quantity == 0:
+		return OrderEditRemoved
+	case l.Quantity != l.OriginalQuantity:
+		return OrderEditQuantityChanged
+	}
+	return OrderEditUnchanged
+}
+
+// OrderEditSummary is the delta an edit makes to an order, shown to whoever
+// edits it before they confirm it
+type OrderEditSummary struct {
+	Lines      []*OrderEditLine `json:"lines"` // Changed lines only
+	Previous   OrderEditTotals  `json:"previous"`
+	Totals     OrderEditTotals  `json:"totals"`
+	BalanceDue float64          `json:"balance_due"` // Owed by the customer; negative is refunded to them
+}
+
+// NewOrderEdit opens an edit of an order, snapshotting its lines and totals
+func NewOrderEdit(order *Order, items []*OrderItem, openedBy string, ttl time.Duration) (*OrderEdit, error) {
+	if order.SubmitDate == nil || !order.CanBeHeld() {
+		return nil, NewDomainError("Only submitted orders that have not shipped can be edited")
+	}
+	now := time.Now()
+	edit := &OrderEdit{
+		OrderID:      order.ID,
+		CustomerID:   order.CustomerID,
+		Status:       OrderEditOpen,
+		OpenedBy:     openedBy,
+		CurrencyCode: order.CurrencyCode,
+		Original: OrderEditTotals{
+			Subtotal: order.OrderSubtotal,
+			Tax:      order.TotalTax,
+			Shipping: order.TotalShipping,
+			Duties:   order.TotalDuties,
+			Total:    order.OrderTotal,
+		},
+		Lines:     make([]*OrderEditLine, 0, len(items)),
+		Revision:  1,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for i, item := range items {
+		edit.Lines = append(edit.Lines, &OrderEditLine{
+			LineID:            i + 1,
+			OrderItemID:       item.ID,
+			ParentOrderItemID: item.ParentOrderItemID,
+			SKUID:             item.SKUID,
+			ProductID:         item.ProductID,
+			Name:              item.Name,
+			TaxCategory:       item.TaxCategory,
+			FreeGift:          item.IsFreeGift(),
+			OriginalQuantity:  item.Quantity,
+			Quantity:          item.Quantity,
+			RetailPrice:       item.RetailPrice,
+			SalePrice:         item.SalePrice,
+			UnitPrice:         item.Price,
+			OriginalTotal:     item.TotalPrice,
+			OriginalTax:       item.TaxAmount,
+			Total:             item.TotalPrice,
+			Tax:               item.TaxAmount,
+		})
+	}
+	return edit, nil
+}
+
+// IsOpen reports whether the edit can still be changed and committed
+func (e *OrderEdit) IsOpen(now time.Time) bool {
+	return e.Status == OrderEditOpen && now.Before(e.ExpiresAt)
+}
+
+// Line returns a line of the edit by its line ID, nil if there is none
+func (e *OrderEdit) Line(lineID int) *OrderEditLine {
+	for _, line := range e.Lines {
+		if line.LineID == lineID {
+			return line
+		}
+	}
+	return nil
+}
+
+// AddLine stages a line of a SKU; a SKU already added gets the quantity added to its line
+func (e *OrderEdit) AddLine(line *OrderEditLine) *OrderEditLine {
+	defer e.changed()
+	for _, existing := range e.Lines {
+		if existing.OrderItemID == 0 && existing.SKUID == line.SKUID {
+			existing.Quantity += line.Quantity
+			existing.Priced = false
+			return existing
+		}
+	}
+	line.LineID = e.nextLineID()
+	line.OrderItemID = 0
+	line.OriginalQuantity = 0
+	line.Priced = false
+	e.Lines = append(e.Lines, line)
+	return line
+}
+
+// SetQuantity changes the quantity of a line; 0 removes it. Add-ons and gift wraps
+// follow their parent line, keeping their quantity per unit of it.
+func (e *OrderEdit) SetQuantity(lineID, quantity int) error {
+	line := e.Line(lineID)
+	if line == nil {
+		return NewDomainError("Order edit has no such line")
+	}
+	if quantity < 0 {
+		return NewDomainError("Quantity cannot be negative")
+	}
+	if line.FreeGift {
+		return NewDomainError("The quantity of a free gift is set by its offer")
+	}
+	if line.ParentOrderItemID != nil && quantity > 0 {
+		return NewDomainError("The quantity of an add-on follows its parent line")
+	}
+	defer e.changed()
+
+	if line.OrderItemID == 0 {
+		if quantity == 0 {
+			e.removeLine(lineID)
+			return nil
+		}
+		line.Quantity = quantity
+		line.Priced = false
+		return nil
+	}
+
+	e.scaleQuantity(line, quantity)
+	return nil
+}
+
+// scaleQuantity sets the quantity of a line of the order and of its descendants,
+// which keep their quantity per unit of their parent
+func (e *OrderEdit) scaleQuantity(line *OrderEditLine, quantity int) {
+	line.Quantity = quantity
+	for _, child := range e.Lines {
+		if child.ParentOrderItemID == nil || *child.ParentOrderItemID != line.OrderItemID {
+			continue
+		}
+		childQuantity := 0
+		if quantity > 0 {
+			childQuantity = max(child.OriginalQuantity*quantity/line.OriginalQuantity, 1)
+		}
+		e.scaleQuantity(child, childQuantity)
+	}
+}
+
+// Summarize computes the delta of the edit. Lines of the order keep their unit
+// price, and their tax is scaled with their quantity; added lines must have been priced.
+func (e *OrderEdit) Summarize() (*OrderEditSummary, error) {
+	summary := &OrderEditSummary{
+		Lines:    make([]*OrderEditLine, 0),
+		Previous: e.Original,
+		Totals:   e.Original,
+	}
+	paid := 0
+	for _, line := range e.Lines {
+		if line.OrderItemID == 0 {
+			if !line.Priced {
+				return nil, NewDomainError("Added lines must be priced before the edit is summarized")
+			}
+		} else if line.Quantity == line.OriginalQuantity {
+			line.Total, line.Tax = line.OriginalTotal, line.OriginalTax
+		} else {
+			line.Total = money.Round(line.UnitPrice*float64(line.Quantity), e.CurrencyCode)
+			line.Tax = money.Round(line.OriginalTax*float64(line.Quantity)/float64(line.OriginalQuantity), e.CurrencyCode)
+		}
+		if line.Quantity > 0 && !line.FreeGift {
+			paid++
+		}
+		if line.Change() == OrderEditUnchanged {
+			continue
+		}
+		summary.Lines = append(summary.Lines, line)
+		summary.Totals.Subtotal += line.Total - line.OriginalTotal
+		summary.Totals.Tax += line.Tax - line.OriginalTax
+	}
+	if paid == 0 {
+		return nil, NewDomainError("An edit cannot remove every paid line; cancel the order instead")
+	}
+
+	summary.Totals.Subtotal = money.Round(summary.Totals.Subtotal, e.CurrencyCode)
+	summary.Totals.Tax = money.Round(summary.Totals.Tax, e.CurrencyCode)
+	summary.Totals.Total = summary.Totals.Subtotal + summary.Totals.Tax + summary.Totals.Shipping + summary.Totals.Duties
+	summary.BalanceDue = money.Round(summary.Totals.Total-summary.Previous.Total, e.CurrencyCode)
+	return summary, nil
+}
+
+// Preview records the summary shown for the current revision of the edit
+func (e *OrderEdit) Preview(summary *OrderEditSummary) {
+	e.Summary = summary
+	e.PreviewedRevision = e.Revision
+	e.UpdatedAt = time.Now()
+}
+
+// Commit closes the edit once its changes were applied; the revision confirmed
+// must be the one last previewed
+func (e *OrderEdit) Commit(revision int, committedBy string) error {
+	if e.PreviewedRevision == 0 || e.PreviewedRevision != e.Revision {
+		return NewDomainError("Preview the edit before committing it")
+	}
+	if revision != e.Revision {
+		return NewDomainError("The edit changed since it was previewed; preview it again")
+	}
+	e.close(OrderEditCommitted, committedBy)
+	return nil
+}
+
+// Cancel closes the edit without changing the order
+func (e *OrderEdit) Cancel(cancelledBy string) {
+	e.close(OrderEditCancelled, cancelledBy)
+}
+
+// Expire closes an edit left open past its expiry
+func (e *OrderEdit) Expire() {
+	e.close(OrderEditExpired, "")
+}
+
+func (e *OrderEdit) close(status OrderEditStatus, by string) {
+	now := time.Now()
+	e.Status = status
+	e.ClosedBy = by
+	e.ClosedAt = &now
+	e.UpdatedAt = now
+}
+
+// changed invalidates the preview of the edit
+func (e *OrderEdit) changed() {
+	e.Revision++
+	e.Summary = nil
+	e.UpdatedAt = time.Now()
+}
+
+func (e *OrderEdit) removeLine(lineID int) {
+	for i, line := range e.Lines {
+		if line.LineID == lineID {
+			e.Lines = append(e.Lines[:i], e.Lines[i+1:]...)
+			return
+		}
+	}
+}
+
+func (e *OrderEdit) nextLineID() int {
+	next := 1
+	for _, line := range e.Lines {
+		if line.LineID >= next {
+			next = line.LineID + 1
+		}
+	}
+	return next
+}
+
+// ApplyEdit adds the delta of a committed edit to the totals of the order, keeping
+// its shipping, duties and the tax on its shipping
+func (o *Order) ApplyEdit(summary *OrderEditSummary) {
+	o.OrderSubtotal += summary.Totals.Subtotal - summary.Previous.Subtotal
+	o.TotalTax += summary.Totals.Tax - summary.Previous.Tax
+	o.recalculateTotal()
+	o.UpdatedAt = time.Now()
+}
+
+// OrderEditRepository defines the interface for order edit persistence
+type OrderEditRepository interface {
+	// Save stores a new edit, setting its ID, or updates an existing one.
+	Save(ctx context.Context, edit *OrderEdit) error
+
+	// Claim stores the commit of an edit if it is still open at the revision it
+	// was previewed at, so that a single commit applies it; it returns false
+	// when the edit was committed, closed or changed in the meantime.
+	Claim(ctx context.Context, edit *OrderEdit) (bool, error)
+
+	// Reopen moves a claimed edit whose changes could not be applied back to OPEN.
+	Reopen(ctx context.Context, id int64) error
+
+	// FindByID retrieves an edit by ID, nil if there is none.
+	FindByID(ctx context.Context, id int64) (*OrderEdit, error)
+
+	// FindOpenByOrderID retrieves the open edit of an order, expired or not; nil if there is none.
+	FindOpenByOrderID(ctx context.Context, orderID int64) (*OrderEdit, error)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// OrderExportFilter selects the orders included in an export
+type OrderExportFilter struct {
+	Status      *OrderStatus
+	CustomerID  *int64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+// OrderExportRow is the flat view of an order written to exports
+type OrderExportRow struct {
+	ID            int64
+	OrderNumber   string
+	CustomerID    int64
+	EmailAddress  string
+	Name          string
+	Status        OrderStatus
+	CurrencyCode  string
+	SubTotal      float64
+	TotalTax      float64
+	TotalShipping float64
+	Total         float64
+	SubmitDate    *time.Time
+	CreatedAt     time.Time
+}
+
+// OrderExportRepository streams orders for exports
+type OrderExportRepository interface {
+	// StreamOrders calls fn for each matching order, oldest first, without loading them all
+	StreamOrders(ctx context.Context, filter *OrderExportFilter, fn func(*OrderExportRow) error) error
+}
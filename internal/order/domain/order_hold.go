@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// HoldReason is why an order is held
+type HoldReason string
+
+const (
+	HoldReasonFraudReview         HoldReason = "FRAUD_REVIEW"
+	HoldReasonPaymentVerification HoldReason = "PAYMENT_VERIFICATION"
+	HoldReasonAddressIssue        HoldReason = "ADDRESS_ISSUE"
+)
+
+// IsValid checks the reason is a known reason code
+func (r HoldReason) IsValid() bool {
+	switch r {
+	case HoldReasonFraudReview, HoldReasonPaymentVerification, HoldReasonAddressIssue:
+		return true
+	}
+	return false
+}
+
+// HoldResolution is how a hold was closed
+type HoldResolution string
+
+const (
+	HoldResolutionReleased  HoldResolution = "RELEASED"  // The order went back to the status it was held from
+	HoldResolutionCancelled HoldResolution = "CANCELLED" // The order was cancelled
+)
+
+// OrderHold pauses the fulfillment of a submitted order while staff review it.
+// The order is ON_HOLD until the hold is released, which restores the status it
+// was held from, or the order is cancelled. An order has at most one open hold.
+type OrderHold struct {
+	ID             int64
+	OrderID        int64
+	Reason         HoldReason
+	Note           string
+	PreviousStatus OrderStatus // Restored when the hold is released
+	PlacedBy       string
+	PlacedAt       time.Time
+	Resolution     HoldResolution // Empty while the hold is open
+	ResolvedBy     *string
+	ResolutionNote string
+	ResolvedAt     *time.Time
+	SLANotifiedAt  *time.Time // When staff were told the hold exceeded its SLA
+
+	// Of the held order, for the hold queue
+	OrderNumber  string
+	EmailAddress string
+	OrderTotal   float64
+	CurrencyCode string
+}
+
+// NewOrderHold creates an open hold of an order
+func NewOrderHold(order *Order, reason HoldReason, note, placedBy string) (*OrderHold, error) {
+	if !reason.IsValid() {
+		return nil, NewDomainError("Unknown hold reason " + string(reason))
+	}
+	if order.Status == OrderStatusOnHold {
+		return nil, NewDomainError("Order is already on hold")
+	}
+	if !order.CanBeHeld() {
+		return nil, NewDomainError("Only submitted orders that have not shipped can be held")
+	}
+	return &OrderHold{
+		OrderID:        order.ID,
+		Reason:         reason,
+		Note:           note,
+		PreviousStatus: order.Status,
+		PlacedBy:       placedBy,
+		PlacedAt:       time.Now(),
+		OrderNumber:    order.OrderNumber,
+		EmailAddress:   order.EmailAddress,
+		OrderTotal:     order.OrderTotal,
+		CurrencyCode:   order.CurrencyCode,
+	}, nil
+}
+
+// IsOpen reports whether the hold still pauses its order
+func (h *OrderHold) IsOpen() bool {
+	return h.Resolution == ""
+}
+
+// Release closes the hold so the order resumes from the status it was held from
+func (h *OrderHold) Release(releasedBy, note string) error {
+	return h.resolve(HoldResolutionReleased, releasedBy, note)
+}
+
+// Cancel closes the hold of an order that is cancelled
+func (h *OrderHold) Cancel(cancelledBy, note string) error {
+	return h.resolve(HoldResolutionCancelled, cancelledBy, note)
+}
+
+func (h *OrderHold) resolve(resolution HoldResolution, resolvedBy, note string) error {
+	if !h.IsOpen() {
+		return NewDomainError("Hold is already " + string(h.Resolution))
+	}
+	now := time.Now()
+	h.Resolution = resolution
+	h.ResolvedBy = &resolvedBy
+	h.ResolutionNote = note
+	h.ResolvedAt = &now
+	return nil
+}
+
+// IsOverdue reports whether the hold has been open longer than sla
+func (h *OrderHold) IsOverdue(now time.Time, sla time.Duration) bool {
+	return h.IsOpen() && sla > 0 && now.Sub(h.PlacedAt) > sla
+}
+
+// MarkSLANotified records that staff were told the hold exceeded its SLA
+func (h *OrderHold) MarkSLANotified() {
+	now := time.Now()
+	h.SLANotifiedAt = &now
+}
+
+// OrderHoldRepository defines the interface for order hold persistence
+type OrderHoldRepository interface {
+	// Place stores a new hold and puts its order on hold in one transaction. It fails with
+	// a conflict if the order's status is no longer the one the hold was placed from.
+	Place(ctx context.Context, hold *OrderHold) error
+
+	// Release saves a released hold and restores its order's previous status in one transaction.
+	Release(ctx context.Context, hold *OrderHold) error
+
+	// Update saves the state of a hold without touching its order.
+	Update(ctx context.Context, hold *OrderHold) error
+
+	// FindOpenByOrderID retrieves the open hold of an order, nil if it has none.
+	FindOpenByOrderID(ctx context.Context, orderID int64) (*OrderHold, error)
+
+	// FindByOrderID retrieves every hold of an order, newest first.
+	FindByOrderID(ctx context.Context, orderID int64) ([]*OrderHold, error)
+
+	// FindOpen retrieves the open holds, oldest first; an empty reason returns every reason.
+	FindOpen(ctx context.Context, reason HoldReason) ([]*OrderHold, error)
+
+	// FindOverdue retrieves open holds placed before placedBefore that staff were not told about yet.
+	FindOverdue(ctx context.Context, placedBefore time.Time) ([]*OrderHold, error)
+}
@@ -28,6 +28,7 @@ type OrderItem struct {
 	GiftWrapItemID    *int64 // From blc_order_item.gift_wrap_item_id
 	ParentOrderItemID *int64 // From blc_order_item.parent_order_item_id
 	PersonalMessageID *int64 // From blc_order_item.personal_message_id
+	QuantityPerParent int    // Units per unit of the parent item; 0 for top-level items
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -58,10 +59,7 @@ func NewOrderItem(
 	}
 
 	now := time.Now()
-	itemPrice := retailPrice
-	if salePrice > 0 && salePrice < retailPrice {
-		itemPrice = salePrice
-	}
+	itemPrice := EffectiveUnitPrice(retailPrice, salePrice)
 
 	return &OrderItem{
 		OrderID:             orderID,
@@ -106,6 +104,22 @@ func (oi *OrderItem) UpdatePrices(retailPrice, salePrice, currentPrice float64)
 	oi.UpdatedAt = time.Now()
 }
 
+// OverridePrice charges a unit price set by an agent instead of the SKU's
+// current retail and sale prices
+func (oi *OrderItem) OverridePrice(price float64) error {
+	if price < 0 {
+		return NewDomainError("Price override cannot be negative")
+	}
+	oi.RetailPrice = price
+	oi.SalePrice = 0
+	oi.RetailPriceOverride = true
+	oi.SalePriceOverride = false
+	oi.Price = price
+	oi.TotalPrice = price * float64(oi.Quantity)
+	oi.UpdatedAt = time.Now()
+	return nil
+}
+
 // SetTaxAmount sets the tax amount for the order item
 func (oi *OrderItem) SetTaxAmount(taxAmount float64) {
 	oi.TaxAmount = taxAmount
@@ -141,3 +155,47 @@ func (oi *OrderItem) SetPersonalMessageID(personalMessageID int64) {
 	oi.PersonalMessageID = &personalMessageID
 	oi.UpdatedAt = time.Now()
 }
+
+// IsChild reports whether the item is an add-on or bundle component of another item
+func (oi *OrderItem) IsChild() bool {
+	return oi.ParentOrderItemID != nil
+}
+
+// QuantityForParent returns the quantity of a child item when its parent is
+// ordered in the given quantity
+func (oi *OrderItem) QuantityForParent(parentQuantity int) int {
+	if oi.QuantityPerParent <= 0 {
+		return oi.Quantity
+	}
+	return oi.QuantityPerParent * parentQuantity
+}
+
+// ChildItemsOf returns the direct children of an item among an order's items
+func ChildItemsOf(items []*OrderItem, parentID int64) []*OrderItem {
+	var children []*OrderItem
+	for _, item := range items {
+		if item.ParentOrderItemID != nil && *item.ParentOrderItemID == parentID {
+			children = append(children, item)
+		}
+	}
+	return children
+}
+
+// DescendantItemsOf returns the children of an item among an order's items, their children and so on
+func DescendantItemsOf(items []*OrderItem, parentID int64) []*OrderItem {
+	var descendants []*OrderItem
+	for _, child := range ChildItemsOf(items, parentID) {
+		descendants = append(descendants, child)
+		descendants = append(descendants, DescendantItemsOf(items, child.ID)...)
+	}
+	return descendants
+}
+
+// TotalWithChildren returns the total price of an item and all of its descendants
+func TotalWithChildren(items []*OrderItem, item *OrderItem) float64 {
+	total := item.TotalPrice
+	for _, child := range ChildItemsOf(items, item.ID) {
+		total += TotalWithChildren(items, child)
+	}
+	return total
+}
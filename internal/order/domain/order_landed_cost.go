@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Incoterm is the trade term a cross-border order ships under, deciding who pays
+// the destination's duties and import fees
+type Incoterm string
+
+const (
+	IncotermDDP Incoterm = "DDP" // Delivered duty paid: duties and fees are charged with the order
+	IncotermDAP Incoterm = "DAP" // Delivered at place: the customer pays duties and fees on delivery
+)
+
+// IsValid checks if the incoterm is one orders can ship under
+func (i Incoterm) IsValid() bool {
+	return i == IncotermDDP || i == IncotermDAP
+}
+
+// OrderLandedCost is the estimate of the duties, import tax and fees a cross-border
+// order owes its destination country, with the incoterm the customer chose
+type OrderLandedCost struct {
+	OrderID            int64
+	Incoterm           Incoterm
+	ShipFromCountry    string
+	DestinationCountry string
+	OrderSubtotal      float64 // Order subtotal the estimate was made for
+	ShippingAmount     float64 // Order shipping the estimate was made for
+	Duty               float64
+	ImportTax          float64
+	Fees               float64
+	Total              float64
+	DeMinimisApplied   bool // Valued below the destination's duty-free threshold
+	CurrencyCode       string
+	Provider           string // Landed cost provider that estimated it
+	Lines              []OrderLandedCostLine
+	EstimatedAt        time.Time
+}
+
+// OrderLandedCostLine is the duty and import tax estimated for an order item
+type OrderLandedCostLine struct {
+	OrderItemID   int64   `json:"order_item_id"`
+	HSCode        string  `json:"hs_code,omitempty"`
+	OriginCountry string  `json:"origin_country,omitempty"`
+	CustomsValue  float64 `json:"customs_value"`
+	DutyRate      float64 `json:"duty_rate"`
+	Duty          float64 `json:"duty"`
+	ImportTax     float64 `json:"import_tax"`
+}
+
+// ChargedAmount returns what the estimate adds to the order total: all of it
+// under DDP, nothing under DAP
+func (c *OrderLandedCost) ChargedAmount() float64 {
+	if c.Incoterm == IncotermDDP {
+		return c.Total
+	}
+	return 0
+}
+
+// IsStale reports whether the order's items or shipping changed since the estimate
+func (c *OrderLandedCost) IsStale(order *Order) bool {
+	const tolerance = 0.005
+	return math.Abs(order.OrderSubtotal-c.OrderSubtotal) > tolerance || math.Abs(order.TotalShipping-c.ShippingAmount) > tolerance
+}
+
+// SetTotalDuties sets the duties and import fees charged with the order
+func (o *Order) SetTotalDuties(amount float64) {
+	o.TotalDuties = amount
+	o.recalculateTotal()
+	o.UpdatedAt = time.Now()
+}
+
+// OrderLandedCostRepository defines the interface for order landed cost persistence
+type OrderLandedCostRepository interface {
+	// Save stores the landed cost estimate of an order, replacing any previous one.
+	Save(ctx context.Context, cost *OrderLandedCost) error
+
+	// FindByOrderID retrieves the landed cost estimate of an order, nil if it has none.
+	FindByOrderID(ctx context.Context, orderID int64) (*OrderLandedCost, error)
+
+	// DeleteByOrderID removes the landed cost estimate of an order.
+	DeleteByOrderID(ctx context.Context, orderID int64) error
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// OrderNote is a note left on an order by staff. Notes are internal unless
+// marked customer-visible, in which case they appear on the storefront order.
+type OrderNote struct {
+	ID              int64
+	OrderID         int64
+	Author          string
+	Body            string
+	CustomerVisible bool
+	CreatedAt       time.Time
+}
+
+// NewOrderNote creates a new OrderNote
+func NewOrderNote(orderID int64, author, body string, customerVisible bool) (*OrderNote, error) {
+	if orderID == 0 {
+		return nil, NewDomainError("OrderID cannot be zero for OrderNote")
+	}
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return nil, NewDomainError("Author cannot be empty for OrderNote")
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, NewDomainError("Body cannot be empty for OrderNote")
+	}
+
+	return &OrderNote{
+		OrderID:         orderID,
+		Author:          author,
+		Body:            body,
+		CustomerVisible: customerVisible,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+// OrderNoteRepository defines the interface for order note persistence
+type OrderNoteRepository interface {
+	// Create stores a new order note.
+	Create(ctx context.Context, note *OrderNote) error
+
+	// FindByOrderID retrieves the notes of an order, oldest first.
+	// When customerVisibleOnly is set, internal notes are excluded.
+	FindByOrderID(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*OrderNote, error)
+}
@@ -0,0 +1,281 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var orderTagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]{0,63}$`)
+
+// NormalizeOrderTag lowercases and checks a tag
+func NormalizeOrderTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !orderTagPattern.MatchString(tag) {
+		return "", fmt.Errorf("invalid tag %q: use up to 64 lowercase letters, digits, '_', '.', ':' or '-'", tag)
+	}
+	return tag, nil
+}
+
+// OrderTagSource is how a tag was put on an order
+type OrderTagSource string
+
+const (
+	OrderTagSourceManual OrderTagSource = "MANUAL" // Added by staff
+	OrderTagSourceRule   OrderTagSource = "RULE"   // Added by an OrderTagRule
+)
+
+// OrderTag labels an order so it shows up in the work queues filtering on the tag.
+// Staff tags are only removed by staff; rule tags follow their rule when the rules
+// are applied to the order again.
+type OrderTag struct {
+	OrderID   int64
+	Tag       string
+	Source    OrderTagSource
+	RuleID    *int64
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// OrderTagCount is the number of orders carrying a tag, by order status
+type OrderTagCount struct {
+	Tag      string
+	Orders   int64
+	ByStatus map[OrderStatus]int64
+}
+
+// OrderTagConditions select the orders a rule tags. Every condition that is set must
+// match; list conditions match when any of their values does.
+type OrderTagConditions struct {
+	MinOrderTotal            float64  `json:"min_order_total,omitempty"`
+	MinItemQuantity          int      `json:"min_item_quantity,omitempty"`
+	CurrencyCodes            []string `json:"currency_codes,omitempty"`
+	ShippingCountries        []string `json:"shipping_countries,omitempty"`         // ISO alpha-2
+	ExcludeShippingCountries []string `json:"exclude_shipping_countries,omitempty"` // e.g. the home country, for "international"
+	CustomerSegments         []string `json:"customer_segments,omitempty"`          // Customer role names or tags
+	SKUIDs                   []int64  `json:"sku_ids,omitempty"`
+	SKUAttributes            []string `json:"sku_attributes,omitempty"` // "name" or "name=value", e.g. "fragile=true"
+}
+
+// IsEmpty reports whether no condition is set, which would tag every order
+func (c OrderTagConditions) IsEmpty() bool {
+	return c.MinOrderTotal <= 0 && c.MinItemQuantity <= 0 && len(c.CurrencyCodes) == 0 &&
+		len(c.ShippingCountries) == 0 && len(c.ExcludeShippingCountries) == 0 &&
+		len(c.CustomerSegments) == 0 && len(c.SKUIDs) == 0 && len(c.SKUAttributes) == 0
+}
+
+// OrderTagFacts are what tag rules are evaluated on
+type OrderTagFacts struct {
+	OrderTotal       float64
+	CurrencyCode     string
+	ItemQuantity     int
+	ShippingCountry  string   // Of the primary shipping address; empty when none is set
+	CustomerSegments []string // Role names and tags of the customer
+	SKUIDs           []int64
+	SKUAttributes    map[string][]string // Values of each attribute name across the SKUs ordered
+}
+
+// OrderTagRule tags submitted orders that match its conditions
+type OrderTagRule struct {
+	ID         int64
+	Name       string
+	Tag        string
+	Conditions OrderTagConditions
+	Active     bool
+	CreatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewOrderTagRule creates an active tag rule
+func NewOrderTagRule(name, tag string, conditions OrderTagConditions, createdBy string) (*OrderTagRule, error) {
+	now := time.Now()
+	rule := &OrderTagRule{Active: true, CreatedBy: createdBy, CreatedAt: now, UpdatedAt: now}
+	if err := rule.Update(name, tag, conditions); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Update replaces the name, tag and conditions of the rule
+func (r *OrderTagRule) Update(name, tag string, conditions OrderTagConditions) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return NewDomainError("Tag rule name is required")
+	}
+	normalized, err := NormalizeOrderTag(tag)
+	if err != nil {
+		return NewDomainError(err.Error())
+	}
+	if conditions.MinOrderTotal < 0 || conditions.MinItemQuantity < 0 {
+		return NewDomainError("Tag rule minimums cannot be negative")
+	}
+	conditions = normalizeTagConditions(conditions)
+	if conditions.IsEmpty() {
+		return NewDomainError("Tag rule needs at least one condition")
+	}
+	r.Name = name
+	r.Tag = normalized
+	r.Conditions = conditions
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Matches reports whether an order with the given facts satisfies every condition of the rule
+func (r *OrderTagRule) Matches(facts *OrderTagFacts) bool {
+	c := r.Conditions
+	if c.MinOrderTotal > 0 && facts.OrderTotal < c.MinOrderTotal {
+		return false
+	}
+	if c.MinItemQuantity > 0 && facts.ItemQuantity < c.MinItemQuantity {
+		return false
+	}
+	if len(c.CurrencyCodes) > 0 && !containsFold(c.CurrencyCodes, facts.CurrencyCode) {
+		return false
+	}
+	if len(c.ShippingCountries) > 0 && !containsFold(c.ShippingCountries, facts.ShippingCountry) {
+		return false
+	}
+	// Orders without a shipping address are not known to leave the excluded countries
+	if len(c.ExcludeShippingCountries) > 0 &&
+		(facts.ShippingCountry == "" || containsFold(c.ExcludeShippingCountries, facts.ShippingCountry)) {
+		return false
+	}
+	if len(c.CustomerSegments) > 0 && !containsFold(c.CustomerSegments, facts.CustomerSegments...) {
+		return false
+	}
+	if len(c.SKUIDs) > 0 && !containsAnySKU(c.SKUIDs, facts.SKUIDs) {
+		return false
+	}
+	if len(c.SKUAttributes) > 0 && !hasAnySKUAttribute(c.SKUAttributes, facts.SKUAttributes) {
+		return false
+	}
+	return true
+}
+
+// OrderQueueFilter selects the orders of a work queue by tags and statuses
+type OrderQueueFilter struct {
+	AllTags     []string // Orders carry every one of these tags
+	AnyTags     []string // Orders carry at least one of these tags
+	ExcludeTags []string // Orders carry none of these tags
+	Statuses    []OrderStatus
+	NewestFirst bool // Queues are worked oldest first by default
+	Page        int
+	PageSize    int
+}
+
+// OrderQueueItem is an order of a work queue with its tags
+type OrderQueueItem struct {
+	OrderID      int64
+	OrderNumber  string
+	Status       OrderStatus
+	CustomerID   int64
+	EmailAddress string
+	OrderTotal   float64
+	CurrencyCode string
+	SubmitDate   *time.Time
+	Tags         []string
+}
+
+// OrderTagRepository defines the interface for order tag and tag rule persistence
+type OrderTagRepository interface {
+	// AddTags puts tags on an order, keeping tags it already carries.
+	AddTags(ctx context.Context, tags []*OrderTag) error
+
+	// RemoveTag removes a tag from an order, reporting whether it carried it.
+	RemoveTag(ctx context.Context, orderID int64, tag string) (bool, error)
+
+	// FindByOrderID retrieves the tags of an order, alphabetically.
+	FindByOrderID(ctx context.Context, orderID int64) ([]*OrderTag, error)
+
+	// CountTags counts the orders carrying each tag, by status, alphabetically.
+	CountTags(ctx context.Context) ([]*OrderTagCount, error)
+
+	// FindQueue retrieves a page of the orders matching the filter with their tags, and
+	// the total number of matching orders.
+	FindQueue(ctx context.Context, filter *OrderQueueFilter) ([]*OrderQueueItem, int64, error)
+
+	// ReplaceRuleTags replaces the rule tags of an order with tags in one transaction,
+	// leaving its staff tags alone, and records when the rules were applied.
+	ReplaceRuleTags(ctx context.Context, orderID int64, tags []*OrderTag, appliedAt time.Time) error
+
+	// FindPendingRuleOrders retrieves up to limit submitted orders the rules have not
+	// evaluated yet, oldest first.
+	FindPendingRuleOrders(ctx context.Context, limit int) ([]int64, error)
+
+	// FindFacts gathers what tag rules are evaluated on for an order, nil if it does not exist.
+	FindFacts(ctx context.Context, orderID int64) (*OrderTagFacts, error)
+
+	// SaveRule creates or updates a tag rule.
+	SaveRule(ctx context.Context, rule *OrderTagRule) error
+
+	// FindRuleByID retrieves a tag rule, nil if it does not exist.
+	FindRuleByID(ctx context.Context, id int64) (*OrderTagRule, error)
+
+	// FindRules retrieves the tag rules by name; activeOnly leaves out disabled rules.
+	FindRules(ctx context.Context, activeOnly bool) ([]*OrderTagRule, error)
+
+	// DeleteRule removes a tag rule; the tags it added stay on their orders.
+	DeleteRule(ctx context.Context, id int64) error
+}
+
+func normalizeTagConditions(c OrderTagConditions) OrderTagConditions {
+	c.CurrencyCodes = upperAll(c.CurrencyCodes)
+	c.ShippingCountries = upperAll(c.ShippingCountries)
+	c.ExcludeShippingCountries = upperAll(c.ExcludeShippingCountries)
+	segments := make([]string, 0, len(c.CustomerSegments))
+	for _, segment := range c.CustomerSegments {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	c.CustomerSegments = segments
+	attributes := make([]string, 0, len(c.SKUAttributes))
+	for _, attribute := range c.SKUAttributes {
+		if attribute = strings.TrimSpace(attribute); attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	c.SKUAttributes = attributes
+	return c
+}
+
+func upperAll(values []string) []string {
+	upper := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.ToUpper(strings.TrimSpace(value)); value != "" {
+			upper = append(upper, value)
+		}
+	}
+	return upper
+}
+
+func containsAnySKU(wanted, skuIDs []int64) bool {
+	for _, skuID := range skuIDs {
+		for _, w := range wanted {
+			if skuID == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasAnySKUAttribute matches "name" against any value of the attribute and "name=value"
+// against that value, both case-insensitively
+func hasAnySKUAttribute(wanted []string, attributes map[string][]string) bool {
+	for _, w := range wanted {
+		name, value, withValue := strings.Cut(w, "=")
+		for attrName, values := range attributes {
+			if !strings.EqualFold(attrName, strings.TrimSpace(name)) {
+				continue
+			}
+			if !withValue || containsFold(values, strings.TrimSpace(value)) {
+				return true
+			}
+		}
+	}
+	return false
+}
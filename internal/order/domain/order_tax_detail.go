@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// OrderTaxDetail is the tax a jurisdiction levied on an item of an order, or on
+// the shipping of one of its fulfillment groups, as calculated at submission
+type OrderTaxDetail struct {
+	ID                 int64
+	OrderID            int64
+	OrderItemID        *int64 // Set for item tax
+	FulfillmentGroupID *int64 // Set for tax on the shipping of a group
+	JurisdictionName   string
+	Country            string
+	Region             string
+	TaxName            string
+	Rate               float64
+	TaxableAmount      float64 // Base the rate was applied to
+	Amount             float64
+	CurrencyCode       string
+	Provider           string // Tax provider that calculated it
+	CalculatedAt       time.Time
+}
+
+// OrderTaxDetailRepository defines the interface for order tax detail persistence
+type OrderTaxDetailRepository interface {
+	// ReplaceForOrder replaces the tax details of an order with those of its latest calculation.
+	ReplaceForOrder(ctx context.Context, orderID int64, details []*OrderTaxDetail) error
+
+	// FindByOrderID retrieves the tax details of an order, items first.
+	FindByOrderID(ctx context.Context, orderID int64) ([]*OrderTaxDetail, error)
+}
@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MaxOrderTenders caps how many tenders one order can be paid with
+const MaxOrderTenders = 5
+
+// TenderMethodGiftCard is the tender method of gift cards. Other tenders use the
+// payment method names of the payment gateway, e.g. CREDIT_CARD or PAYPAL.
+const TenderMethodGiftCard = "GIFT_CARD"
+
+// TenderStatus represents the state of one tender of an order's payment
+type TenderStatus string
+
+const (
+	TenderStatusPending    TenderStatus = "PENDING"    // Not sent to the gateway yet
+	TenderStatusAuthorized TenderStatus = "AUTHORIZED" // Funds held
+	TenderStatusCaptured   TenderStatus = "CAPTURED"   // Funds taken; refunds are tracked in RefundedAmount
+	TenderStatusFailed     TenderStatus = "FAILED"     // Declined; no funds held
+	TenderStatusVoided     TenderStatus = "VOIDED"     // Hold released after another tender failed or the order was not submitted
+	TenderStatusRefunded   TenderStatus = "REFUNDED"   // Captured amount refunded in full
+)
+
+// OrderTender is one of the payments an order is split across, e.g. a gift card
+// covering part of the total and a card paying the rest
+type OrderTender struct {
+	ID             int64
+	OrderID        int64
+	Sequence       int    // Authorization order within the order's tenders
+	Method         string // TenderMethodGiftCard or a gateway payment method
+	Amount         float64
+	CurrencyCode   string
+	Status         TenderStatus
+	TransactionID  string
+	CapturedAmount float64
+	RefundedAmount float64
+	FailureReason  string
+	AuthorizedAt   *time.Time
+	CapturedAt     *time.Time
+	RefundedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TenderRequest is a tender a customer splits a payment with. A zero amount
+// pays whatever the other tenders leave of the total.
+type TenderRequest struct {
+	Method string
+	Token  string
+	Amount float64
+}
+
+// PlannedTender is a pending tender with the token it is authorized with.
+// Tokens are only held while authorizing and never stored.
+type PlannedTender struct {
+	Tender *OrderTender
+	Token  string
+}
+
+// PlanTenders validates how a total is split across tenders and returns the
+// pending tenders to authorize, gift cards first so the cards cover what the
+// gift cards do not. Amounts must add up to the total to the cent.
+func PlanTenders(orderID int64, total float64, currencyCode string, firstSequence int, requests []TenderRequest) ([]*PlannedTender, error) {
+	if len(requests) == 0 {
+		return nil, NewDomainError("at least one tender is required")
+	}
+	if len(requests) > MaxOrderTenders {
+		return nil, NewDomainError(fmt.Sprintf("an order can be paid with at most %d tenders", MaxOrderTenders))
+	}
+
+	remainderIndex := -1
+	var allocated int64
+	for i, req := range requests {
+		if req.Method == "" {
+			return nil, NewDomainError(fmt.Sprintf("tender %d: method is required", i+1))
+		}
+		if req.Token == "" {
+			return nil, NewDomainError(fmt.Sprintf("tender %d: token is required", i+1))
+		}
+		switch {
+		case req.Amount < 0:
+			return nil, NewDomainError(fmt.Sprintf("tender %d: amount cannot be negative", i+1))
+		case req.Amount == 0 && remainderIndex >= 0:
+			return nil, NewDomainError("only one tender can leave its amount out to pay the remainder")
+		case req.Amount == 0:
+			remainderIndex = i
+		default:
+			allocated += roundCents(req.Amount)
+		}
+	}
+
+	totalCents := roundCents(total)
+	amounts := make([]int64, len(requests))
+	for i, req := range requests {
+		amounts[i] = roundCents(req.Amount)
+	}
+	if remainderIndex >= 0 {
+		if allocated >= totalCents {
+			return nil, NewDomainError("the other tenders already cover the order total")
+		}
+		amounts[remainderIndex] = totalCents - allocated
+	} else if allocated != totalCents {
+		return nil, NewDomainError(fmt.Sprintf("the tenders add up to %.2f but the order total is %.2f", float64(allocated)/100, float64(totalCents)/100))
+	}
+
+	order := make([]int, len(requests))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return requests[order[a]].Method == TenderMethodGiftCard && requests[order[b]].Method != TenderMethodGiftCard
+	})
+
+	now := time.Now()
+	planned := make([]*PlannedTender, len(requests))
+	for seq, i := range order {
+		planned[seq] = &PlannedTender{Token: requests[i].Token, Tender: &OrderTender{
+			OrderID:      orderID,
+			Sequence:     firstSequence + seq,
+			Method:       requests[i].Method,
+			Amount:       float64(amounts[i]) / 100,
+			CurrencyCode: currencyCode,
+			Status:       TenderStatusPending,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}}
+	}
+	return planned, nil
+}
+
+// MarkAuthorized records the hold the gateway placed for the tender
+func (t *OrderTender) MarkAuthorized(transactionID string) {
+	now := time.Now()
+	t.Status = TenderStatusAuthorized
+	t.TransactionID = transactionID
+	t.FailureReason = ""
+	t.AuthorizedAt = &now
+	t.UpdatedAt = now
+}
+
+// Fail records that the gateway declined the tender
+func (t *OrderTender) Fail(reason string) {
+	t.Status = TenderStatusFailed
+	t.FailureReason = reason
+	t.UpdatedAt = time.Now()
+}
+
+// MarkVoided records that the tender's hold was released
+func (t *OrderTender) MarkVoided(reason string) {
+	t.Status = TenderStatusVoided
+	t.FailureReason = reason
+	t.UpdatedAt = time.Now()
+}
+
+// MarkCaptured records that the tender's funds were taken
+func (t *OrderTender) MarkCaptured() error {
+	if t.Status != TenderStatusAuthorized {
+		return NewDomainError(fmt.Sprintf("tender %d is %s and cannot be captured", t.Sequence, t.Status))
+	}
+	now := time.Now()
+	t.Status = TenderStatusCaptured
+	t.CapturedAmount = t.Amount
+	t.FailureReason = ""
+	t.CapturedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// Refundable returns how much of the tender can still be refunded
+func (t *OrderTender) Refundable() float64 {
+	if t.Status != TenderStatusCaptured {
+		return 0
+	}
+	return float64(roundCents(t.CapturedAmount)-roundCents(t.RefundedAmount)) / 100
+}
+
+// RecordRefund records a refund of part or all of the captured amount
+func (t *OrderTender) RecordRefund(amount float64) error {
+	if amount <= 0 || roundCents(amount) > roundCents(t.Refundable()) {
+		return NewDomainError(fmt.Sprintf("tender %d can be refunded at most %.2f", t.Sequence, t.Refundable()))
+	}
+	now := time.Now()
+	t.RefundedAmount = float64(roundCents(t.RefundedAmount)+roundCents(amount)) / 100
+	if roundCents(t.RefundedAmount) >= roundCents(t.CapturedAmount) {
+		t.Status = TenderStatusRefunded
+	}
+	t.RefundedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// TenderRefund is the part of a refund one tender pays back
+type TenderRefund struct {
+	Tender *OrderTender
+	Amount float64
+}
+
+// AllocateRefund splits a refund across an order's captured tenders in reverse
+// sequence, so cards are refunded before the gift cards that were charged
+// first and a gift card only gets money back once the cards are refunded in full.
+func AllocateRefund(tenders []*OrderTender, amount float64) ([]TenderRefund, error) {
+	remaining := roundCents(amount)
+	if remaining <= 0 {
+		return nil, NewDomainError("refund amount must be greater than zero")
+	}
+
+	ordered := make([]*OrderTender, len(tenders))
+	copy(ordered, tenders)
+	sort.SliceStable(ordered, func(a, b int) bool { return ordered[a].Sequence > ordered[b].Sequence })
+
+	var refunds []TenderRefund
+	var available int64
+	for _, tender := range ordered {
+		refundable := roundCents(tender.Refundable())
+		available += refundable
+		if refundable == 0 || remaining == 0 {
+			continue
+		}
+		part := refundable
+		if part > remaining {
+			part = remaining
+		}
+		refunds = append(refunds, TenderRefund{Tender: tender, Amount: float64(part) / 100})
+		remaining -= part
+	}
+	if remaining > 0 {
+		return nil, NewDomainError(fmt.Sprintf("only %.2f of the order's payments can be refunded", float64(available)/100))
+	}
+	return refunds, nil
+}
+
+// OrderTenderRepository defines the interface for the tenders of order payments
+type OrderTenderRepository interface {
+	// Save creates or updates a tender and sets its ID
+	Save(ctx context.Context, tender *OrderTender) error
+
+	// FindByOrderID returns the tenders of an order in sequence, including failed and voided attempts
+	FindByOrderID(ctx context.Context, orderID int64) ([]*OrderTender, error)
+}
@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PaymentLinkReason is why an order is paid through a link
+type PaymentLinkReason string
+
+const (
+	PaymentLinkReasonAssistedOrder   PaymentLinkReason = "ASSISTED_ORDER"   // Order placed by an agent for a phone or mail customer
+	PaymentLinkReasonPaymentRecovery PaymentLinkReason = "PAYMENT_RECOVERY" // The checkout payment failed or was abandoned
+	PaymentLinkReasonQuote           PaymentLinkReason = "QUOTE"            // Order prepared as a quote for the customer to accept
+)
+
+// PaymentLinkStatus represents the state of a payment link
+type PaymentLinkStatus string
+
+const (
+	PaymentLinkStatusActive     PaymentLinkStatus = "ACTIVE"
+	PaymentLinkStatusProcessing PaymentLinkStatus = "PROCESSING" // Claimed by a payment in progress
+	PaymentLinkStatusPaid       PaymentLinkStatus = "PAID"
+	PaymentLinkStatusCancelled  PaymentLinkStatus = "CANCELLED"
+	PaymentLinkStatusExpired    PaymentLinkStatus = "EXPIRED" // Never stored: an active link past its expiry
+)
+
+// PaymentLink is a secure, expiring link a customer pays an unpaid order with.
+// The amount is fixed when the link is issued so the customer pays what they
+// were told; a link becomes unusable when the order total changes.
+type PaymentLink struct {
+	ID            int64
+	Token         string
+	OrderID       int64
+	Reason        PaymentLinkReason
+	Amount        float64
+	CurrencyCode  string
+	Status        PaymentLinkStatus
+	ExpiresAt     time.Time
+	CreatedBy     string
+	TransactionID *string
+	PaidAt        *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewPaymentLink creates a payment link for the current total of an order
+func NewPaymentLink(orderID int64, reason PaymentLinkReason, token string, amount float64, currencyCode string, expiresAt time.Time, createdBy string) (*PaymentLink, error) {
+	if orderID == 0 {
+		return nil, NewDomainError("OrderID cannot be zero for a payment link")
+	}
+	if !IsPaymentLinkReason(reason) {
+		return nil, NewDomainError("reason must be ASSISTED_ORDER, PAYMENT_RECOVERY or QUOTE")
+	}
+	if token == "" {
+		return nil, NewDomainError("a payment link requires a token")
+	}
+	if amount <= 0 {
+		return nil, NewDomainError("only orders with an amount to pay can be paid by link")
+	}
+
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return nil, NewDomainError("a payment link must expire in the future")
+	}
+	return &PaymentLink{
+		Token:        token,
+		OrderID:      orderID,
+		Reason:       reason,
+		Amount:       amount,
+		CurrencyCode: currencyCode,
+		Status:       PaymentLinkStatusActive,
+		ExpiresAt:    expiresAt,
+		CreatedBy:    createdBy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// IsPaymentLinkReason checks if a payment link can be issued for a reason
+func IsPaymentLinkReason(reason PaymentLinkReason) bool {
+	switch reason {
+	case PaymentLinkReasonAssistedOrder, PaymentLinkReasonPaymentRecovery, PaymentLinkReasonQuote:
+		return true
+	}
+	return false
+}
+
+// EffectiveStatus returns the status of the link at a point in time
+func (l *PaymentLink) EffectiveStatus(now time.Time) PaymentLinkStatus {
+	if l.Status == PaymentLinkStatusActive && !now.Before(l.ExpiresAt) {
+		return PaymentLinkStatusExpired
+	}
+	return l.Status
+}
+
+// CanPay checks if the link can still be paid
+func (l *PaymentLink) CanPay(now time.Time) error {
+	switch l.EffectiveStatus(now) {
+	case PaymentLinkStatusPaid:
+		return NewDomainError("the order has already been paid")
+	case PaymentLinkStatusProcessing:
+		return NewDomainError("a payment of the link is already in progress")
+	case PaymentLinkStatusCancelled:
+		return NewDomainError("the payment link has been cancelled")
+	case PaymentLinkStatusExpired:
+		return NewDomainError("the payment link has expired")
+	}
+	return nil
+}
+
+// MarkPaid records the authorized payment of the link
+func (l *PaymentLink) MarkPaid(transactionID string) {
+	now := time.Now()
+	l.TransactionID = &transactionID
+	l.Status = PaymentLinkStatusPaid
+	l.PaidAt = &now
+	l.UpdatedAt = now
+}
+
+// Cancel makes an unpaid link unusable
+func (l *PaymentLink) Cancel() error {
+	if l.Status == PaymentLinkStatusPaid {
+		return NewDomainError("a paid payment link cannot be cancelled")
+	}
+	if l.Status == PaymentLinkStatusProcessing {
+		return NewDomainError("a payment link cannot be cancelled while it is being paid")
+	}
+	l.Status = PaymentLinkStatusCancelled
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsAwaitingPayment checks if an order in the status has not been submitted yet and can be paid
+func (s OrderStatus) IsAwaitingPayment() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusCustomerInfo, OrderStatusShipping, OrderStatusPayment, OrderStatusReview,
+		OrderStatusAwaitingPayment:
+		return true
+	}
+	return false
+}
+
+// PaymentLinkRepository defines the interface for payment link persistence
+type PaymentLinkRepository interface {
+	// Create saves a new payment link and sets its ID
+	Create(ctx context.Context, link *PaymentLink) error
+
+	// Update saves the status and payment of a payment link
+	Update(ctx context.Context, link *PaymentLink) error
+
+	// Claim moves an active, unexpired link to PROCESSING so that a single
+	// payment can be in progress; it returns false when the link was not active
+	Claim(ctx context.Context, id int64, now time.Time) (bool, error)
+
+	// Release moves a link claimed by a payment that did not go through back to ACTIVE
+	Release(ctx context.Context, id int64) error
+
+	// FindByID returns a payment link, or nil
+	FindByID(ctx context.Context, id int64) (*PaymentLink, error)
+
+	// FindByToken returns the payment link with a token, or nil
+	FindByToken(ctx context.Context, token string) (*PaymentLink, error)
+
+	// FindByOrderID returns the payment links of an order, newest first
+	FindByOrderID(ctx context.Context, orderID int64) ([]*PaymentLink, error)
+}
@@ -48,6 +48,11 @@ type OrderAdjustmentRepository interface {
 
 	// DeleteByOrderID removes all order adjustments for a given order ID.
 	DeleteByOrderID(ctx context.Context, orderID int64) error
+
+	// ReplaceOfferAdjustments replaces the order and item adjustments of an order and
+	// saves the prices of its repriced items in a single round trip and transaction.
+	// New adjustments get their IDs set.
+	ReplaceOfferAdjustments(ctx context.Context, orderID int64, items []*OrderItem, orderAdjustments []*OrderAdjustment, itemAdjustments []*OrderItemAdjustment) error
 }
 
 // OrderItemAdjustmentRepository defines the interface for order item adjustment persistence
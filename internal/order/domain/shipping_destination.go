@@ -0,0 +1,15 @@
+package domain
+
+import "context"
+
+// ShippingDestination is the country and region a shipping address delivers to
+type ShippingDestination struct {
+	Country string // ISO 3166-1 alpha-2
+	Region  string // ISO 3166-2 subdivision or state/province
+}
+
+// ShippingDestinationRepository resolves the destination of shipping addresses
+type ShippingDestinationRepository interface {
+	// FindByAddressID returns the destination of an address
+	FindByAddressID(ctx context.Context, addressID int64) (*ShippingDestination, error)
+}
@@ -0,0 +1,158 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresAssistedOrderRepository implements the AssistedOrderRepository interface
+type PostgresAssistedOrderRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAssistedOrderRepository creates a new PostgresAssistedOrderRepository
+func NewPostgresAssistedOrderRepository(db *database.DB) *PostgresAssistedOrderRepository {
+	return &PostgresAssistedOrderRepository{db: db}
+}
+
+const assistedOrderColumns = `
+	order_id, channel, agent_id, guest, price_overridden, cart_policy_bypassed, payment_method,
+	payment_status, transaction_id, created_at, updated_at`
+
+// Save creates or updates the assisted order record of an order.
+func (r *PostgresAssistedOrderRepository) Save(ctx context.Context, order *domain.AssistedOrder) error {
+	query := `
+		INSERT INTO order_assisted (` + assistedOrderColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (order_id) DO UPDATE SET
+			price_overridden = EXCLUDED.price_overridden,
+			cart_policy_bypassed = EXCLUDED.cart_policy_bypassed,
+			payment_status = EXCLUDED.payment_status,
+			transaction_id = EXCLUDED.transaction_id,
+			updated_at = EXCLUDED.updated_at`
+
+	err := r.db.Exec(ctx, query,
+		order.OrderID, string(order.Channel), order.AgentID, order.Guest, order.PriceOverridden,
+		order.CartPolicyBypassed, string(order.PaymentMethod), string(order.PaymentStatus),
+		order.TransactionID, order.CreatedAt, order.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save assisted order")
+	}
+	return nil
+}
+
+// FindByOrderID returns the assisted order record of an order, or nil.
+func (r *PostgresAssistedOrderRepository) FindByOrderID(ctx context.Context, orderID int64) (*domain.AssistedOrder, error) {
+	query := `SELECT` + assistedOrderColumns + ` FROM order_assisted WHERE order_id = $1`
+	order, err := scanAssistedOrder(r.db.QueryRow(ctx, query, orderID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find assisted order")
+	}
+	return order, nil
+}
+
+func scanAssistedOrder(row pgx.Row) (*domain.AssistedOrder, error) {
+	order := &domain.AssistedOrder{}
+	var channel, method, status string
+	err := row.Scan(
+		&order.OrderID, &channel, &order.AgentID, &order.Guest, &order.PriceOverridden,
+		&order.CartPolicyBypassed, &method, &status, &order.TransactionID, &order.CreatedAt, &order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	order.Channel = domain.AssistedOrderChannel(channel)
+	order.PaymentMethod = domain.AssistedPaymentMethod(method)
+	order.PaymentStatus = domain.AssistedPaymentStatus(status)
+	return order, nil
+}
+
+// PostgresAssistedOrderCustomerRepository implements the AssistedOrderCustomerRepository interface
+type PostgresAssistedOrderCustomerRepository struct {
+	db *database.DB
+}
+
+// NewPostgresAssistedOrderCustomerRepository creates a new PostgresAssistedOrderCustomerRepository
+func NewPostgresAssistedOrderCustomerRepository(db *database.DB) *PostgresAssistedOrderCustomerRepository {
+	return &PostgresAssistedOrderCustomerRepository{db: db}
+}
+
+const assistedCustomerColumns = `customer_id, email_address, first_name, last_name, is_registered`
+
+// FindByID returns an active customer, or nil.
+func (r *PostgresAssistedOrderCustomerRepository) FindByID(ctx context.Context, id int64) (*domain.AssistedOrderCustomer, error) {
+	query := `SELECT ` + assistedCustomerColumns + `
+		FROM blc_customer
+		WHERE customer_id = $1 AND COALESCE(archived, FALSE) = FALSE AND COALESCE(deactivated, FALSE) = FALSE`
+	customer, err := scanAssistedOrderCustomer(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer")
+	}
+	return customer, nil
+}
+
+// FindByEmail returns the active customer with an email address, or nil.
+// Registered customers win over earlier guest records.
+func (r *PostgresAssistedOrderCustomerRepository) FindByEmail(ctx context.Context, email string) (*domain.AssistedOrderCustomer, error) {
+	query := `SELECT ` + assistedCustomerColumns + `
+		FROM blc_customer
+		WHERE LOWER(email_address) = $1 AND COALESCE(archived, FALSE) = FALSE AND COALESCE(deactivated, FALSE) = FALSE
+		ORDER BY is_registered DESC NULLS LAST, customer_id
+		LIMIT 1`
+	customer, err := scanAssistedOrderCustomer(r.db.QueryRow(ctx, query, strings.ToLower(email)))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer by email")
+	}
+	return customer, nil
+}
+
+// CreateGuest creates an unregistered customer and sets its ID.
+func (r *PostgresAssistedOrderCustomerRepository) CreateGuest(ctx context.Context, customer *domain.AssistedOrderCustomer) error {
+	query := `
+		INSERT INTO blc_customer (
+			archived, deactivated, email_address, first_name, last_name, password_change_required,
+			is_preview, receive_email, is_registered, is_tax_exempt, date_created, date_updated
+		) VALUES (FALSE, FALSE, $1, $2, $3, FALSE, FALSE, FALSE, FALSE, FALSE, $4, $4)
+		RETURNING customer_id`
+
+	err := r.db.QueryRow(ctx, query,
+		customer.EmailAddress, customer.FirstName, customer.LastName, time.Now(),
+	).Scan(&customer.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create guest customer")
+	}
+	customer.Registered = false
+	return nil
+}
+
+func scanAssistedOrderCustomer(row pgx.Row) (*domain.AssistedOrderCustomer, error) {
+	customer := &domain.AssistedOrderCustomer{}
+	var email, firstName, lastName sql.NullString
+	var registered sql.NullBool
+	if err := row.Scan(&customer.ID, &email, &firstName, &lastName, &registered); err != nil {
+		return nil, err
+	}
+	customer.EmailAddress = email.String
+	customer.FirstName = firstName.String
+	customer.LastName = lastName.String
+	customer.Registered = registered.Bool
+	return customer, nil
+}
@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCartPolicyContextRepository implements the CartPolicyContextRepository interface
+type PostgresCartPolicyContextRepository struct {
+	db *database.DB
+}
+
+// NewPostgresCartPolicyContextRepository creates a new PostgresCartPolicyContextRepository
+func NewPostgresCartPolicyContextRepository(db *database.DB) *PostgresCartPolicyContextRepository {
+	return &PostgresCartPolicyContextRepository{db: db}
+}
+
+// FindCustomerSegments returns the role names and tags of a customer
+func (r *PostgresCartPolicyContextRepository) FindCustomerSegments(ctx context.Context, customerID int64) ([]string, error) {
+	query := `
+		SELECT r.role_name
+		FROM blc_customer_role cr
+		JOIN blc_role r ON r.role_id = cr.role_id
+		WHERE cr.customer_id = $1
+		UNION
+		SELECT tag FROM blc_customer_tag WHERE customer_id = $1`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find customer segments")
+	}
+	defer rows.Close()
+
+	segments := make([]string, 0)
+	for rows.Next() {
+		var segment string
+		if err := rows.Scan(&segment); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan customer segment")
+		}
+		segments = append(segments, segment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate customer segments")
+	}
+	return segments, nil
+}
+
+// FindDestinationCountry returns the country of the order's primary shipping address
+func (r *PostgresCartPolicyContextRepository) FindDestinationCountry(ctx context.Context, orderID int64) (string, error) {
+	query := `
+		SELECT a.iso_country_alpha2
+		FROM blc_fulfillment_group fg
+		JOIN blc_address a ON a.address_id = fg.address_id
+		WHERE fg.order_id = $1
+		ORDER BY fg.is_primary DESC NULLS LAST, fg.fulfillment_group_id
+		LIMIT 1`
+
+	var country sql.NullString
+	err := r.db.QueryRow(ctx, query, orderID).Scan(&country)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.InternalWrap(err, "failed to find order destination country")
+	}
+	return country.String, nil
+}
@@ -0,0 +1,269 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// staleChannelOrderClaim is how long a pending ingestion may run before the
+// order can be claimed again, e.g. after a crash mid-ingestion
+const staleChannelOrderClaim = 15 * time.Minute
+
+// PostgresChannelOrderRepository implements the ChannelOrderRepository interface
+type PostgresChannelOrderRepository struct {
+	db *database.DB
+}
+
+// NewPostgresChannelOrderRepository creates a new PostgresChannelOrderRepository
+func NewPostgresChannelOrderRepository(db *database.DB) *PostgresChannelOrderRepository {
+	return &PostgresChannelOrderRepository{db: db}
+}
+
+const channelOrderColumns = `
+	id, channel, external_order_id, order_id, status, failure_reason, attempts, acknowledged_at,
+	date_created, date_updated`
+
+// Claim stores a pending record for an external order and sets its ID. A failed or
+// stale pending record is claimed again with one more attempt.
+func (r *PostgresChannelOrderRepository) Claim(ctx context.Context, order *domain.ChannelOrder) (bool, error) {
+	query := `
+		INSERT INTO order_channel_order (
+			channel, external_order_id, status, attempts, date_created, date_updated
+		) VALUES ($1, $2, $3, 1, $4, $4)
+		ON CONFLICT (channel, external_order_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			failure_reason = '',
+			order_id = NULL,
+			attempts = order_channel_order.attempts + 1,
+			acknowledged_at = NULL,
+			date_updated = EXCLUDED.date_updated
+		WHERE order_channel_order.status = 'FAILED'
+			OR (order_channel_order.status = 'PENDING' AND order_channel_order.date_updated < $5)
+		RETURNING id, attempts, date_created`
+
+	err := r.db.QueryRow(ctx, query,
+		order.Channel, order.ExternalOrderID, string(order.Status), order.UpdatedAt,
+		order.UpdatedAt.Add(-staleChannelOrderClaim),
+	).Scan(&order.ID, &order.Attempts, &order.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.InternalWrap(err, "failed to claim channel order")
+	}
+	return true, nil
+}
+
+// Save updates the outcome of an ingestion
+func (r *PostgresChannelOrderRepository) Save(ctx context.Context, order *domain.ChannelOrder) error {
+	query := `
+		UPDATE order_channel_order SET
+			order_id = $2, status = $3, failure_reason = $4, acknowledged_at = $5, date_updated = $6
+		WHERE id = $1`
+	tag, err := r.db.Pool().Exec(ctx, query,
+		order.ID, order.OrderID, string(order.Status), order.FailureReason, order.AcknowledgedAt, order.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save channel order")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("channel order %d", order.ID))
+	}
+	return nil
+}
+
+// FindByExternalID returns the record of an external order, or nil
+func (r *PostgresChannelOrderRepository) FindByExternalID(ctx context.Context, channel, externalOrderID string) (*domain.ChannelOrder, error) {
+	query := `SELECT` + channelOrderColumns + ` FROM order_channel_order WHERE channel = $1 AND external_order_id = $2`
+	order, err := scanChannelOrder(r.db.QueryRow(ctx, query, channel, externalOrderID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find channel order")
+	}
+	return order, nil
+}
+
+// FindAll lists channel orders, newest first, with the total count
+func (r *PostgresChannelOrderRepository) FindAll(ctx context.Context, filter *domain.ChannelOrderFilter) ([]*domain.ChannelOrder, int64, error) {
+	where := " WHERE 1=1"
+	args := make([]interface{}, 0)
+	if filter != nil {
+		if filter.Channel != "" {
+			args = append(args, filter.Channel)
+			where += fmt.Sprintf(" AND channel = $%d", len(args))
+		}
+		if filter.Status != "" {
+			args = append(args, string(filter.Status))
+			where += fmt.Sprintf(" AND status = $%d", len(args))
+		}
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM order_channel_order"+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count channel orders")
+	}
+
+	query := `SELECT` + channelOrderColumns + ` FROM order_channel_order` + where + ` ORDER BY date_created DESC, id DESC`
+	if filter != nil && filter.PageSize > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, filter.PageSize, (max(filter.Page, 1)-1)*filter.PageSize)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to find channel orders")
+	}
+	defer rows.Close()
+
+	orders := make([]*domain.ChannelOrder, 0)
+	for rows.Next() {
+		order, err := scanChannelOrder(rows)
+		if err != nil {
+			return nil, 0, errors.InternalWrap(err, "failed to scan channel order")
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to iterate channel orders")
+	}
+	return orders, total, nil
+}
+
+// MarkAcknowledged records that the outcome of the orders was reported to their channel
+func (r *PostgresChannelOrderRepository) MarkAcknowledged(ctx context.Context, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := r.db.Exec(ctx, `
+		UPDATE order_channel_order SET acknowledged_at = $2, date_updated = $2
+		WHERE id = ANY($1)`,
+		ids, at,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to mark channel orders acknowledged")
+	}
+	return nil
+}
+
+func scanChannelOrder(row pgx.Row) (*domain.ChannelOrder, error) {
+	order := &domain.ChannelOrder{}
+	var status string
+	err := row.Scan(
+		&order.ID, &order.Channel, &order.ExternalOrderID, &order.OrderID, &status, &order.FailureReason,
+		&order.Attempts, &order.AcknowledgedAt, &order.CreatedAt, &order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	order.Status = domain.ChannelOrderStatus(status)
+	return order, nil
+}
+
+// PostgresChannelSKUMappingRepository implements the ChannelSKUMappingRepository interface
+type PostgresChannelSKUMappingRepository struct {
+	db *database.DB
+}
+
+// NewPostgresChannelSKUMappingRepository creates a new PostgresChannelSKUMappingRepository
+func NewPostgresChannelSKUMappingRepository(db *database.DB) *PostgresChannelSKUMappingRepository {
+	return &PostgresChannelSKUMappingRepository{db: db}
+}
+
+// FindSKUIDs returns the internal SKU IDs of a channel's SKUs; unmapped SKUs are missing
+func (r *PostgresChannelSKUMappingRepository) FindSKUIDs(ctx context.Context, channel string, externalSKUs []string) (map[string]int64, error) {
+	skuIDs := make(map[string]int64, len(externalSKUs))
+	if len(externalSKUs) == 0 {
+		return skuIDs, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT external_sku, sku_id FROM order_channel_sku_mapping
+		WHERE channel = $1 AND external_sku = ANY($2)`,
+		channel, externalSKUs,
+	)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find channel SKU mappings")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var externalSKU string
+		var skuID int64
+		if err := rows.Scan(&externalSKU, &skuID); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan channel SKU mapping")
+		}
+		skuIDs[externalSKU] = skuID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate channel SKU mappings")
+	}
+	return skuIDs, nil
+}
+
+// FindByChannel lists the mappings of a channel ordered by external SKU
+func (r *PostgresChannelSKUMappingRepository) FindByChannel(ctx context.Context, channel string) ([]*domain.ChannelSKUMapping, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT channel, external_sku, sku_id, date_created, date_updated
+		FROM order_channel_sku_mapping
+		WHERE channel = $1
+		ORDER BY external_sku`,
+		channel,
+	)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find channel SKU mappings")
+	}
+	defer rows.Close()
+
+	mappings := make([]*domain.ChannelSKUMapping, 0)
+	for rows.Next() {
+		mapping := &domain.ChannelSKUMapping{}
+		if err := rows.Scan(&mapping.Channel, &mapping.ExternalSKU, &mapping.SKUID, &mapping.CreatedAt, &mapping.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan channel SKU mapping")
+		}
+		mappings = append(mappings, mapping)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate channel SKU mappings")
+	}
+	return mappings, nil
+}
+
+// Save creates or updates a mapping
+func (r *PostgresChannelSKUMappingRepository) Save(ctx context.Context, mapping *domain.ChannelSKUMapping) error {
+	query := `
+		INSERT INTO order_channel_sku_mapping (channel, external_sku, sku_id, date_created, date_updated)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (channel, external_sku) DO UPDATE SET
+			sku_id = EXCLUDED.sku_id,
+			date_updated = EXCLUDED.date_updated
+		RETURNING date_created`
+	err := r.db.QueryRow(ctx, query,
+		mapping.Channel, strings.TrimSpace(mapping.ExternalSKU), mapping.SKUID, mapping.CreatedAt, mapping.UpdatedAt,
+	).Scan(&mapping.CreatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save channel SKU mapping")
+	}
+	return nil
+}
+
+// Delete removes a mapping
+func (r *PostgresChannelSKUMappingRepository) Delete(ctx context.Context, channel, externalSKU string) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM order_channel_sku_mapping WHERE channel = $1 AND external_sku = $2`, channel, externalSKU)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete channel SKU mapping")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("channel SKU mapping")
+	}
+	return nil
+}
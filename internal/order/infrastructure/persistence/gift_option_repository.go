@@ -0,0 +1,174 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresPersonalMessageRepository implements the PersonalMessageRepository interface
+type PostgresPersonalMessageRepository struct {
+	db *database.DB
+}
+
+// NewPostgresPersonalMessageRepository creates a new PostgresPersonalMessageRepository
+func NewPostgresPersonalMessageRepository(db *database.DB) *PostgresPersonalMessageRepository {
+	return &PostgresPersonalMessageRepository{db: db}
+}
+
+// Create stores a new personal message.
+func (r *PostgresPersonalMessageRepository) Create(ctx context.Context, message *domain.PersonalMessage) error {
+	query := `
+		INSERT INTO blc_personal_message (message, message_from, message_to, occasion)
+		VALUES ($1, $2, $3, $4)
+		RETURNING personal_message_id`
+	err := r.db.QueryRow(ctx, query, message.Message, message.MessageFrom, message.MessageTo, message.Occasion).
+		Scan(&message.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create personal message")
+	}
+	return nil
+}
+
+// Update saves an existing personal message.
+func (r *PostgresPersonalMessageRepository) Update(ctx context.Context, message *domain.PersonalMessage) error {
+	query := `
+		UPDATE blc_personal_message
+		SET message = $2, message_from = $3, message_to = $4, occasion = $5
+		WHERE personal_message_id = $1`
+	err := r.db.Exec(ctx, query, message.ID, message.Message, message.MessageFrom, message.MessageTo, message.Occasion)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update personal message")
+	}
+	return nil
+}
+
+// FindByID retrieves a personal message by its unique identifier.
+func (r *PostgresPersonalMessageRepository) FindByID(ctx context.Context, id int64) (*domain.PersonalMessage, error) {
+	query := `
+		SELECT personal_message_id, COALESCE(message, ''), COALESCE(message_from, ''),
+			   COALESCE(message_to, ''), COALESCE(occasion, '')
+		FROM blc_personal_message
+		WHERE personal_message_id = $1`
+	message := &domain.PersonalMessage{}
+	err := r.db.QueryRow(ctx, query, id).
+		Scan(&message.ID, &message.Message, &message.MessageFrom, &message.MessageTo, &message.Occasion)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find personal message")
+	}
+	return message, nil
+}
+
+// Delete removes a personal message by its unique identifier.
+func (r *PostgresPersonalMessageRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.db.Exec(ctx, `DELETE FROM blc_personal_message WHERE personal_message_id = $1`, id); err != nil {
+		return errors.InternalWrap(err, "failed to delete personal message")
+	}
+	return nil
+}
+
+// PostgresGiftWrapOptionRepository implements the GiftWrapOptionRepository interface
+type PostgresGiftWrapOptionRepository struct {
+	db *database.DB
+}
+
+// NewPostgresGiftWrapOptionRepository creates a new PostgresGiftWrapOptionRepository
+func NewPostgresGiftWrapOptionRepository(db *database.DB) *PostgresGiftWrapOptionRepository {
+	return &PostgresGiftWrapOptionRepository{db: db}
+}
+
+const giftWrapOptionColumns = `gift_wrap_option_id, sku_id, name, COALESCE(description, ''), active, created_at, updated_at`
+
+// Save stores a new gift wrap option or updates an existing one.
+func (r *PostgresGiftWrapOptionRepository) Save(ctx context.Context, option *domain.GiftWrapOption) error {
+	if option.ID == 0 {
+		query := `
+			INSERT INTO order_gift_wrap_option (sku_id, name, description, active, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING gift_wrap_option_id`
+		err := r.db.QueryRow(ctx, query,
+			option.SKUID, option.Name, option.Description, option.Active, option.CreatedAt, option.UpdatedAt,
+		).Scan(&option.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create gift wrap option")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE order_gift_wrap_option
+		SET sku_id = $2, name = $3, description = $4, active = $5, updated_at = $6
+		WHERE gift_wrap_option_id = $1`
+	err := r.db.Exec(ctx, query, option.ID, option.SKUID, option.Name, option.Description, option.Active, option.UpdatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update gift wrap option")
+	}
+	return nil
+}
+
+// FindByID retrieves a gift wrap option by its unique identifier.
+func (r *PostgresGiftWrapOptionRepository) FindByID(ctx context.Context, id int64) (*domain.GiftWrapOption, error) {
+	query := `SELECT ` + giftWrapOptionColumns + ` FROM order_gift_wrap_option WHERE gift_wrap_option_id = $1`
+	return r.findOne(ctx, query, id)
+}
+
+// FindBySKUID retrieves the gift wrap option sold through a SKU.
+func (r *PostgresGiftWrapOptionRepository) FindBySKUID(ctx context.Context, skuID int64) (*domain.GiftWrapOption, error) {
+	query := `SELECT ` + giftWrapOptionColumns + ` FROM order_gift_wrap_option WHERE sku_id = $1`
+	return r.findOne(ctx, query, skuID)
+}
+
+// FindAll retrieves gift wrap options ordered by name, optionally only the active ones.
+func (r *PostgresGiftWrapOptionRepository) FindAll(ctx context.Context, activeOnly bool) ([]*domain.GiftWrapOption, error) {
+	query := `SELECT ` + giftWrapOptionColumns + ` FROM order_gift_wrap_option`
+	if activeOnly {
+		query += ` WHERE active = TRUE`
+	}
+	query += ` ORDER BY name, gift_wrap_option_id`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list gift wrap options")
+	}
+	defer rows.Close()
+
+	options := make([]*domain.GiftWrapOption, 0)
+	for rows.Next() {
+		option, err := scanGiftWrapOption(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan gift wrap option")
+		}
+		options = append(options, option)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate gift wrap options")
+	}
+	return options, nil
+}
+
+func (r *PostgresGiftWrapOptionRepository) findOne(ctx context.Context, query string, arg int64) (*domain.GiftWrapOption, error) {
+	option, err := scanGiftWrapOption(r.db.QueryRow(ctx, query, arg))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find gift wrap option")
+	}
+	return option, nil
+}
+
+func scanGiftWrapOption(row pgx.Row) (*domain.GiftWrapOption, error) {
+	option := &domain.GiftWrapOption{}
+	err := row.Scan(&option.ID, &option.SKUID, &option.Name, &option.Description, &option.Active, &option.CreatedAt, &option.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return option, nil
+}
@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresInventoryDeallocationRepository implements the InventoryDeallocationRepository interface
+type PostgresInventoryDeallocationRepository struct {
+	db *database.DB
+}
+
+// NewPostgresInventoryDeallocationRepository creates a new PostgresInventoryDeallocationRepository
+func NewPostgresInventoryDeallocationRepository(db *database.DB) *PostgresInventoryDeallocationRepository {
+	return &PostgresInventoryDeallocationRepository{db: db}
+}
+
+const deallocationColumns = `
+	deallocation_id, order_id, order_item_id, sku_id, quantity, status, attempts, last_error,
+	next_attempt_at, resolved_by, created_at, updated_at, completed_at`
+
+// Enqueue stores deallocations, skipping order items that are already queued.
+func (r *PostgresInventoryDeallocationRepository) Enqueue(ctx context.Context, deallocations []*domain.InventoryDeallocation) error {
+	query := `
+		INSERT INTO order_inventory_deallocation (
+			order_id, order_item_id, sku_id, quantity, status, attempts, next_attempt_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (order_item_id) DO NOTHING
+		RETURNING deallocation_id`
+	for _, d := range deallocations {
+		err := r.db.QueryRow(ctx, query,
+			d.OrderID, d.OrderItemID, d.SKUID, d.Quantity, string(d.Status), d.Attempts,
+			d.NextAttemptAt, d.CreatedAt, d.UpdatedAt,
+		).Scan(&d.ID)
+		if err != nil && err != pgx.ErrNoRows {
+			return errors.InternalWrap(err, "failed to enqueue inventory deallocation")
+		}
+	}
+	return nil
+}
+
+// ClaimDue leases up to limit pending deallocations due by now.
+func (r *PostgresInventoryDeallocationRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.InventoryDeallocation, error) {
+	return r.claim(ctx, "next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT $3", now.Add(lease), now, limit)
+}
+
+// ClaimByOrderID leases the pending deallocations of an order regardless of their schedule.
+func (r *PostgresInventoryDeallocationRepository) ClaimByOrderID(ctx context.Context, orderID int64, lease time.Duration) ([]*domain.InventoryDeallocation, error) {
+	return r.claim(ctx, "order_id = $2", time.Now().Add(lease), orderID)
+}
+
+// claim pushes next_attempt_at of the selected pending rows to the lease end and returns them.
+// Rows locked by another worker are skipped.
+func (r *PostgresInventoryDeallocationRepository) claim(ctx context.Context, condition string, leaseUntil time.Time, args ...interface{}) ([]*domain.InventoryDeallocation, error) {
+	query := fmt.Sprintf(`
+		UPDATE order_inventory_deallocation
+		SET next_attempt_at = $1
+		WHERE deallocation_id IN (
+			SELECT deallocation_id FROM order_inventory_deallocation
+			WHERE status = 'PENDING' AND %s
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s`, condition, deallocationColumns)
+	return r.query(ctx, query, append([]interface{}{leaseUntil}, args...)...)
+}
+
+// FindByID retrieves a deallocation by its ID.
+func (r *PostgresInventoryDeallocationRepository) FindByID(ctx context.Context, id int64) (*domain.InventoryDeallocation, error) {
+	query := `SELECT` + deallocationColumns + ` FROM order_inventory_deallocation WHERE deallocation_id = $1`
+	d, err := scanDeallocation(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find inventory deallocation")
+	}
+	return d, nil
+}
+
+// FindUnresolved retrieves dead-lettered and failing pending deallocations, oldest first.
+func (r *PostgresInventoryDeallocationRepository) FindUnresolved(ctx context.Context) ([]*domain.InventoryDeallocation, error) {
+	query := `SELECT` + deallocationColumns + `
+		FROM order_inventory_deallocation
+		WHERE status = 'DEAD_LETTER' OR (status = 'PENDING' AND attempts > 0)
+		ORDER BY created_at, deallocation_id`
+	return r.query(ctx, query)
+}
+
+// Update saves the state of a deallocation.
+func (r *PostgresInventoryDeallocationRepository) Update(ctx context.Context, d *domain.InventoryDeallocation) error {
+	query := `
+		UPDATE order_inventory_deallocation
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5,
+			resolved_by = $6, updated_at = $7, completed_at = $8
+		WHERE deallocation_id = $1`
+	err := r.db.Exec(ctx, query,
+		d.ID, string(d.Status), d.Attempts, d.LastError, d.NextAttemptAt,
+		d.ResolvedBy, d.UpdatedAt, d.CompletedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update inventory deallocation")
+	}
+	return nil
+}
+
+func (r *PostgresInventoryDeallocationRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.InventoryDeallocation, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query inventory deallocations")
+	}
+	defer rows.Close()
+
+	deallocations := make([]*domain.InventoryDeallocation, 0)
+	for rows.Next() {
+		d, err := scanDeallocation(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan inventory deallocation")
+		}
+		deallocations = append(deallocations, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate inventory deallocations")
+	}
+	return deallocations, nil
+}
+
+func scanDeallocation(row pgx.Row) (*domain.InventoryDeallocation, error) {
+	d := &domain.InventoryDeallocation{}
+	var status string
+	err := row.Scan(
+		&d.ID, &d.OrderID, &d.OrderItemID, &d.SKUID, &d.Quantity, &status, &d.Attempts, &d.LastError,
+		&d.NextAttemptAt, &d.ResolvedBy, &d.CreatedAt, &d.UpdatedAt, &d.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Status = domain.DeallocationStatus(status)
+	return d, nil
+}
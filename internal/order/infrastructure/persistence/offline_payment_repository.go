@@ -0,0 +1,248 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOfflinePaymentMethodRepository implements the OfflinePaymentMethodRepository interface
+type PostgresOfflinePaymentMethodRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOfflinePaymentMethodRepository creates a new PostgresOfflinePaymentMethodRepository
+func NewPostgresOfflinePaymentMethodRepository(db *database.DB) *PostgresOfflinePaymentMethodRepository {
+	return &PostgresOfflinePaymentMethodRepository{db: db}
+}
+
+const offlinePaymentMethodColumns = `
+	id, code, payment_type, name, instructions, enabled, countries,
+	min_order_total, max_order_total, sort_order, created_at, updated_at`
+
+// Save creates or updates a method and sets its ID.
+func (r *PostgresOfflinePaymentMethodRepository) Save(ctx context.Context, method *domain.OfflinePaymentMethod) error {
+	if method.ID == 0 {
+		query := `
+			INSERT INTO offline_payment_method (
+				code, payment_type, name, instructions, enabled, countries,
+				min_order_total, max_order_total, sort_order, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id`
+		err := r.db.QueryRow(ctx, query,
+			method.Code, string(method.Type), method.Name, method.Instructions, method.Enabled, method.Countries,
+			method.MinOrderTotal, method.MaxOrderTotal, method.SortOrder, method.CreatedAt, method.UpdatedAt,
+		).Scan(&method.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create offline payment method")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE offline_payment_method
+		SET payment_type = $2, name = $3, instructions = $4, enabled = $5, countries = $6,
+			min_order_total = $7, max_order_total = $8, sort_order = $9, updated_at = $10
+		WHERE id = $1`
+	tag, err := r.db.Pool().Exec(ctx, query,
+		method.ID, string(method.Type), method.Name, method.Instructions, method.Enabled, method.Countries,
+		method.MinOrderTotal, method.MaxOrderTotal, method.SortOrder, method.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update offline payment method")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("offline payment method %d", method.ID))
+	}
+	return nil
+}
+
+// Delete removes a method.
+func (r *PostgresOfflinePaymentMethodRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM offline_payment_method WHERE id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete offline payment method")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("offline payment method %d", id))
+	}
+	return nil
+}
+
+// FindByID returns a method, or nil.
+func (r *PostgresOfflinePaymentMethodRepository) FindByID(ctx context.Context, id int64) (*domain.OfflinePaymentMethod, error) {
+	query := `SELECT` + offlinePaymentMethodColumns + ` FROM offline_payment_method WHERE id = $1`
+	method, err := scanOfflinePaymentMethod(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find offline payment method")
+	}
+	return method, nil
+}
+
+// FindByCode returns the method with a code, or nil.
+func (r *PostgresOfflinePaymentMethodRepository) FindByCode(ctx context.Context, code string) (*domain.OfflinePaymentMethod, error) {
+	query := `SELECT` + offlinePaymentMethodColumns + ` FROM offline_payment_method WHERE code = $1`
+	method, err := scanOfflinePaymentMethod(r.db.QueryRow(ctx, query, code))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find offline payment method by code")
+	}
+	return method, nil
+}
+
+// FindAll returns the methods in display order.
+func (r *PostgresOfflinePaymentMethodRepository) FindAll(ctx context.Context, enabledOnly bool) ([]*domain.OfflinePaymentMethod, error) {
+	query := `SELECT` + offlinePaymentMethodColumns + ` FROM offline_payment_method`
+	if enabledOnly {
+		query += ` WHERE enabled = TRUE`
+	}
+	query += ` ORDER BY sort_order, name, id`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list offline payment methods")
+	}
+	defer rows.Close()
+
+	methods := make([]*domain.OfflinePaymentMethod, 0)
+	for rows.Next() {
+		method, err := scanOfflinePaymentMethod(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan offline payment method")
+		}
+		methods = append(methods, method)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate offline payment methods")
+	}
+	return methods, nil
+}
+
+func scanOfflinePaymentMethod(row pgx.Row) (*domain.OfflinePaymentMethod, error) {
+	method := &domain.OfflinePaymentMethod{}
+	var paymentType string
+	err := row.Scan(
+		&method.ID, &method.Code, &paymentType, &method.Name, &method.Instructions, &method.Enabled, &method.Countries,
+		&method.MinOrderTotal, &method.MaxOrderTotal, &method.SortOrder, &method.CreatedAt, &method.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	method.Type = domain.OfflinePaymentType(paymentType)
+	return method, nil
+}
+
+// PostgresOfflinePaymentRepository implements the OfflinePaymentRepository interface
+type PostgresOfflinePaymentRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOfflinePaymentRepository creates a new PostgresOfflinePaymentRepository
+func NewPostgresOfflinePaymentRepository(db *database.DB) *PostgresOfflinePaymentRepository {
+	return &PostgresOfflinePaymentRepository{db: db}
+}
+
+const offlinePaymentColumns = `
+	p.order_id, p.method_id, p.method_code, p.payment_type, p.amount, p.currency_code, p.reference, p.status,
+	p.received_amount, p.receipt_reference, p.received_by, p.received_at, p.note, p.created_at, p.updated_at`
+
+// Save creates or updates the offline payment of an order. An order placed
+// again with another method replaces its earlier payment.
+func (r *PostgresOfflinePaymentRepository) Save(ctx context.Context, payment *domain.OfflinePayment) error {
+	query := `
+		INSERT INTO order_offline_payment (
+			order_id, method_id, method_code, payment_type, amount, currency_code, reference, status,
+			received_amount, receipt_reference, received_by, received_at, note, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (order_id) DO UPDATE SET
+			method_id = EXCLUDED.method_id, method_code = EXCLUDED.method_code, payment_type = EXCLUDED.payment_type,
+			amount = EXCLUDED.amount, currency_code = EXCLUDED.currency_code, reference = EXCLUDED.reference,
+			status = EXCLUDED.status, received_amount = EXCLUDED.received_amount,
+			receipt_reference = EXCLUDED.receipt_reference, received_by = EXCLUDED.received_by,
+			received_at = EXCLUDED.received_at, note = EXCLUDED.note, updated_at = EXCLUDED.updated_at`
+	var methodID *int64
+	if payment.MethodID != 0 {
+		methodID = &payment.MethodID
+	}
+	err := r.db.Exec(ctx, query,
+		payment.OrderID, methodID, payment.MethodCode, string(payment.Type), payment.Amount, payment.CurrencyCode,
+		payment.Reference, string(payment.Status), payment.ReceivedAmount, payment.ReceiptReference, payment.ReceivedBy,
+		payment.ReceivedAt, payment.Note, payment.CreatedAt, payment.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save offline payment")
+	}
+	return nil
+}
+
+// FindByOrderID returns the offline payment of an order, or nil.
+func (r *PostgresOfflinePaymentRepository) FindByOrderID(ctx context.Context, orderID int64) (*domain.OfflinePayment, error) {
+	query := `SELECT` + offlinePaymentColumns + ` FROM order_offline_payment p WHERE p.order_id = $1`
+	payment, err := scanOfflinePayment(r.db.QueryRow(ctx, query, orderID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find offline payment")
+	}
+	return payment, nil
+}
+
+// FindAwaiting returns the payments not received yet of orders still awaiting payment, oldest first.
+func (r *PostgresOfflinePaymentRepository) FindAwaiting(ctx context.Context, limit int) ([]*domain.OfflinePayment, error) {
+	query := `SELECT` + offlinePaymentColumns + `
+		FROM order_offline_payment p
+		JOIN blc_order o ON o.order_id = p.order_id
+		WHERE p.status = 'AWAITING' AND o.order_status = $1
+		ORDER BY p.created_at, p.order_id
+		LIMIT $2`
+	rows, err := r.db.Query(ctx, query, string(domain.OrderStatusAwaitingPayment), limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list awaiting offline payments")
+	}
+	defer rows.Close()
+
+	payments := make([]*domain.OfflinePayment, 0)
+	for rows.Next() {
+		payment, err := scanOfflinePayment(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan offline payment")
+		}
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate offline payments")
+	}
+	return payments, nil
+}
+
+func scanOfflinePayment(row pgx.Row) (*domain.OfflinePayment, error) {
+	payment := &domain.OfflinePayment{}
+	var paymentType, status string
+	var methodID sql.NullInt64
+	var currencyCode sql.NullString
+	err := row.Scan(
+		&payment.OrderID, &methodID, &payment.MethodCode, &paymentType, &payment.Amount, &currencyCode,
+		&payment.Reference, &status, &payment.ReceivedAmount, &payment.ReceiptReference, &payment.ReceivedBy,
+		&payment.ReceivedAt, &payment.Note, &payment.CreatedAt, &payment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	payment.MethodID = methodID.Int64
+	payment.Type = domain.OfflinePaymentType(paymentType)
+	payment.Status = domain.OfflinePaymentStatus(status)
+	payment.CurrencyCode = currencyCode.String
+	return payment, nil
+}
@@ -3,8 +3,11 @@ package persistence
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5"
+
 	"github.com/qhato/ecommerce/internal/order/domain"
 	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
 )
 
 // PostgresOrderAdjustmentRepository implements the OrderAdjustmentRepository interface
@@ -45,4 +48,56 @@ func (r *PostgresOrderAdjustmentRepository) Delete(ctx context.Context, id int64
 func (r *PostgresOrderAdjustmentRepository) DeleteByOrderID(ctx context.Context, orderID int64) error {
 	// TODO: Implement actual persistence logic
 	return nil
-}
\ No newline at end of file
+}
+
+// ReplaceOfferAdjustments replaces the offer adjustments of an order and saves
+// its repriced items. All statements are sent as one batch inside a transaction,
+// so large carts cost a single round trip instead of several per item.
+func (r *PostgresOrderAdjustmentRepository) ReplaceOfferAdjustments(
+	ctx context.Context,
+	orderID int64,
+	items []*domain.OrderItem,
+	orderAdjustments []*domain.OrderAdjustment,
+	itemAdjustments []*domain.OrderItemAdjustment,
+) error {
+	batch := &pgx.Batch{}
+	batch.Queue(`DELETE FROM blc_order_adjustment WHERE order_id = $1`, orderID)
+	batch.Queue(`
+		DELETE FROM blc_order_item_adjustment
+		WHERE order_item_id IN (SELECT order_item_id FROM blc_order_item WHERE order_id = $1)`, orderID)
+
+	for _, item := range items {
+		batch.Queue(`
+			UPDATE blc_order_item
+			SET retail_price = $2, sale_price = $3, price = $4, updated_at = $5
+			WHERE order_item_id = $1`,
+			item.ID, item.RetailPrice, item.SalePrice, item.Price, item.UpdatedAt)
+	}
+	for _, adjustment := range orderAdjustments {
+		batch.Queue(`
+			INSERT INTO blc_order_adjustment (order_id, offer_id, adjustment_reason, adjustment_value, is_future_credit, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING order_adjustment_id`,
+			orderID, adjustment.OfferID, adjustment.AdjustmentReason, adjustment.AdjustmentValue, adjustment.IsFutureCredit, adjustment.CreatedAt,
+		).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&adjustment.ID)
+		})
+	}
+	for _, adjustment := range itemAdjustments {
+		batch.Queue(`
+			INSERT INTO blc_order_item_adjustment (order_item_id, offer_id, adjustment_reason, adjustment_value, applied_to_sale_price, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING order_item_adjustment_id`,
+			adjustment.OrderItemID, adjustment.OfferID, adjustment.AdjustmentReason, adjustment.AdjustmentValue, adjustment.AppliedToSalePrice, adjustment.CreatedAt,
+		).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&adjustment.ID)
+		})
+	}
+
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return errors.InternalWrap(err, "failed to save offer adjustments")
+		}
+		return nil
+	})
+}
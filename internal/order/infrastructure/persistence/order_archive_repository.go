@@ -0,0 +1,160 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderArchiveRepository implements the OrderArchiveRepository interface
+type PostgresOrderArchiveRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderArchiveRepository creates a new PostgresOrderArchiveRepository
+func NewPostgresOrderArchiveRepository(db *database.DB) *PostgresOrderArchiveRepository {
+	return &PostgresOrderArchiveRepository{db: db}
+}
+
+// archivedOrderTable is a live order table moved to its _archive copy along
+// with the order, keyed either by the order or by its items
+type archivedOrderTable struct {
+	name   string
+	byItem bool
+}
+
+// The tables keyed by order item come first, while the items are still in
+// the table they are looked up in
+var archivedOrderTableSet = []archivedOrderTable{
+	{name: "blc_order_item_add_attr", byItem: true},
+	{name: "blc_order_item_adjustment", byItem: true},
+	{name: "blc_order_item"},
+	{name: "blc_order_adjustment"},
+	{name: "blc_order_attribute"},
+	{name: "blc_order"},
+}
+
+// FindPolicies retrieves the retention policies of every status that has one
+func (r *PostgresOrderArchiveRepository) FindPolicies(ctx context.Context) ([]*domain.OrderRetentionPolicy, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT order_status, retention_months, updated_by, updated_at
+		FROM order_retention_policy
+		ORDER BY order_status`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order retention policies")
+	}
+	defer rows.Close()
+
+	policies := make([]*domain.OrderRetentionPolicy, 0)
+	for rows.Next() {
+		policy := &domain.OrderRetentionPolicy{}
+		if err := rows.Scan(&policy.Status, &policy.RetentionMonths, &policy.UpdatedBy, &policy.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order retention policy")
+		}
+		policies = append(policies, policy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order retention policies")
+	}
+	return policies, nil
+}
+
+// SavePolicy creates or replaces the retention policy of a status
+func (r *PostgresOrderArchiveRepository) SavePolicy(ctx context.Context, policy *domain.OrderRetentionPolicy) error {
+	err := r.db.Exec(ctx, `
+		INSERT INTO order_retention_policy (order_status, retention_months, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (order_status) DO UPDATE
+		SET retention_months = EXCLUDED.retention_months,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`,
+		policy.Status, policy.RetentionMonths, policy.UpdatedBy, policy.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save order retention policy")
+	}
+	return nil
+}
+
+// ArchiveBefore moves up to batchSize orders in status last updated before
+// cutoff to the archive tables in one transaction. Orders locked by a
+// concurrent change are skipped until the next run.
+func (r *PostgresOrderArchiveRepository) ArchiveBefore(ctx context.Context, status domain.OrderStatus, cutoff time.Time, batchSize int) (int64, error) {
+	var archived int64
+	err := r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT order_id FROM blc_order
+			WHERE order_status = $1 AND COALESCE(date_updated, submit_date, date_created, created_at) < $2
+			ORDER BY order_id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED`,
+			status, cutoff, batchSize,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to select orders to archive")
+		}
+		orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+		if err != nil {
+			return errors.InternalWrap(err, "failed to scan orders to archive")
+		}
+		if len(orderIDs) == 0 {
+			return nil
+		}
+
+		archived, err = moveOrders(ctx, tx, orderIDs, "", "_archive")
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return archived, nil
+}
+
+// Restore moves an archived order back to the live tables
+func (r *PostgresOrderArchiveRepository) Restore(ctx context.Context, orderID int64) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		restored, err := moveOrders(ctx, tx, []int64{orderID}, "_archive", "")
+		if err != nil {
+			return err
+		}
+		if restored == 0 {
+			return errors.NotFound(fmt.Sprintf("archived order %d", orderID))
+		}
+		return nil
+	})
+}
+
+// moveOrders moves orders with everything stored under them from the tables
+// with the from suffix to those with the to suffix, returning how many orders
+// were moved. Live and archive tables share their column order, which lets
+// the rows be copied as they are.
+func moveOrders(ctx context.Context, tx pgx.Tx, orderIDs []int64, from, to string) (int64, error) {
+	var moved int64
+	for _, table := range archivedOrderTableSet {
+		where := "order_id = ANY($1)"
+		if table.byItem {
+			where = "order_item_id IN (SELECT order_item_id FROM blc_order_item" + from + " WHERE order_id = ANY($1))"
+		}
+		query := `
+			WITH moved AS (
+				DELETE FROM ` + table.name + from + `
+				WHERE ` + where + `
+				RETURNING *
+			)
+			INSERT INTO ` + table.name + to + ` SELECT * FROM moved`
+
+		tag, err := tx.Exec(ctx, query, orderIDs)
+		if err != nil {
+			return 0, errors.InternalWrap(err, fmt.Sprintf("failed to move %s rows", table.name))
+		}
+		// blc_order is moved last, so this ends as the number of orders
+		moved = tag.RowsAffected()
+	}
+	return moved, nil
+}
@@ -0,0 +1,143 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderAttachmentRepository implements the OrderAttachmentRepository interface
+type PostgresOrderAttachmentRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderAttachmentRepository creates a new PostgresOrderAttachmentRepository
+func NewPostgresOrderAttachmentRepository(db *database.DB) *PostgresOrderAttachmentRepository {
+	return &PostgresOrderAttachmentRepository{db: db}
+}
+
+const orderAttachmentColumns = `
+	order_attachment_id, order_id, kind, visibility, file_name, content_type, size_bytes, checksum,
+	storage_key, description, uploaded_by, uploaded_by_customer, created_at, updated_at`
+
+// Create stores a new attachment and sets its ID.
+func (r *PostgresOrderAttachmentRepository) Create(ctx context.Context, attachment *domain.OrderAttachment) error {
+	query := `
+		INSERT INTO order_attachment (
+			order_id, kind, visibility, file_name, content_type, size_bytes, checksum,
+			storage_key, description, uploaded_by, uploaded_by_customer, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING order_attachment_id`
+	err := r.db.QueryRow(ctx, query,
+		attachment.OrderID, string(attachment.Kind), string(attachment.Visibility), attachment.FileName,
+		attachment.ContentType, attachment.SizeBytes, attachment.Checksum, attachment.StorageKey,
+		attachment.Description, attachment.UploadedBy, attachment.UploadedByCustomer,
+		attachment.CreatedAt, attachment.UpdatedAt,
+	).Scan(&attachment.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create order attachment")
+	}
+	return nil
+}
+
+// FindByID retrieves an attachment of an order.
+func (r *PostgresOrderAttachmentRepository) FindByID(ctx context.Context, orderID, id int64) (*domain.OrderAttachment, error) {
+	query := `SELECT ` + orderAttachmentColumns + ` FROM order_attachment WHERE order_id = $1 AND order_attachment_id = $2`
+	attachment, err := scanOrderAttachment(r.db.QueryRow(ctx, query, orderID, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("order attachment")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order attachment")
+	}
+	return attachment, nil
+}
+
+// FindByOrderID retrieves the attachments of an order, oldest first.
+func (r *PostgresOrderAttachmentRepository) FindByOrderID(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*domain.OrderAttachment, error) {
+	query := `SELECT ` + orderAttachmentColumns + ` FROM order_attachment WHERE order_id = $1`
+	if customerVisibleOnly {
+		query += ` AND visibility = 'CUSTOMER'`
+	}
+	query += ` ORDER BY created_at, order_attachment_id`
+
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list order attachments")
+	}
+	defer rows.Close()
+
+	attachments := make([]*domain.OrderAttachment, 0)
+	for rows.Next() {
+		attachment, err := scanOrderAttachment(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order attachment")
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order attachments")
+	}
+	return attachments, nil
+}
+
+// CountCustomerUploads counts the attachments the customer uploaded to an order.
+func (r *PostgresOrderAttachmentRepository) CountCustomerUploads(ctx context.Context, orderID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM order_attachment WHERE order_id = $1 AND uploaded_by_customer`,
+		orderID,
+	).Scan(&count)
+	if err != nil {
+		return 0, errors.InternalWrap(err, "failed to count customer uploads")
+	}
+	return count, nil
+}
+
+// UpdateVisibility stores the visibility of an attachment.
+func (r *PostgresOrderAttachmentRepository) UpdateVisibility(ctx context.Context, attachment *domain.OrderAttachment) error {
+	tag, err := r.db.Pool().Exec(ctx, `
+		UPDATE order_attachment SET visibility = $3, updated_at = $4
+		WHERE order_id = $1 AND order_attachment_id = $2`,
+		attachment.OrderID, attachment.ID, string(attachment.Visibility), attachment.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update order attachment")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("order attachment")
+	}
+	return nil
+}
+
+// Delete removes an attachment.
+func (r *PostgresOrderAttachmentRepository) Delete(ctx context.Context, orderID, id int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM order_attachment WHERE order_id = $1 AND order_attachment_id = $2`, orderID, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete order attachment")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("order attachment")
+	}
+	return nil
+}
+
+func scanOrderAttachment(row pgx.Row) (*domain.OrderAttachment, error) {
+	attachment := &domain.OrderAttachment{}
+	var kind, visibility string
+	err := row.Scan(
+		&attachment.ID, &attachment.OrderID, &kind, &visibility, &attachment.FileName, &attachment.ContentType,
+		&attachment.SizeBytes, &attachment.Checksum, &attachment.StorageKey, &attachment.Description,
+		&attachment.UploadedBy, &attachment.UploadedByCustomer, &attachment.CreatedAt, &attachment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	attachment.Kind = domain.AttachmentKind(kind)
+	attachment.Visibility = domain.AttachmentVisibility(visibility)
+	return attachment, nil
+}
@@ -0,0 +1,154 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderEditRepository implements the OrderEditRepository interface
+type PostgresOrderEditRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderEditRepository creates a new PostgresOrderEditRepository
+func NewPostgresOrderEditRepository(db *database.DB) *PostgresOrderEditRepository {
+	return &PostgresOrderEditRepository{db: db}
+}
+
+const orderEditColumns = `
+	edit_id, order_id, customer_id, status, opened_by, currency_code, original_totals, lines,
+	revision, previewed_revision, summary, expires_at, closed_by, closed_at, created_at, updated_at`
+
+// Save stores a new edit, setting its ID, or updates an existing one. An order
+// already having an open edit is a conflict.
+func (r *PostgresOrderEditRepository) Save(ctx context.Context, edit *domain.OrderEdit) error {
+	original, err := json.Marshal(edit.Original)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode order edit totals")
+	}
+	lines, err := json.Marshal(edit.Lines)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode order edit lines")
+	}
+	var summary []byte
+	if edit.Summary != nil {
+		if summary, err = json.Marshal(edit.Summary); err != nil {
+			return errors.InternalWrap(err, "failed to encode order edit summary")
+		}
+	}
+
+	if edit.ID == 0 {
+		err = r.db.QueryRow(ctx, `
+			INSERT INTO order_edit (
+				order_id, customer_id, status, opened_by, currency_code, original_totals, lines,
+				revision, previewed_revision, summary, expires_at, closed_by, closed_at, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			ON CONFLICT (order_id) WHERE status = 'OPEN' DO NOTHING
+			RETURNING edit_id`,
+			edit.OrderID, edit.CustomerID, string(edit.Status), edit.OpenedBy, edit.CurrencyCode, original, lines,
+			edit.Revision, edit.PreviewedRevision, summary, edit.ExpiresAt, edit.ClosedBy, edit.ClosedAt, edit.CreatedAt, edit.UpdatedAt,
+		).Scan(&edit.ID)
+		if err == pgx.ErrNoRows {
+			return errors.Conflict(fmt.Sprintf("order %d already has an open edit", edit.OrderID))
+		}
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create order edit")
+		}
+		return nil
+	}
+
+	err = r.db.Exec(ctx, `
+		UPDATE order_edit
+		SET status = $2, lines = $3, revision = $4, previewed_revision = $5, summary = $6,
+			expires_at = $7, closed_by = $8, closed_at = $9, updated_at = $10
+		WHERE edit_id = $1`,
+		edit.ID, string(edit.Status), lines, edit.Revision, edit.PreviewedRevision, summary,
+		edit.ExpiresAt, edit.ClosedBy, edit.ClosedAt, edit.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update order edit")
+	}
+	return nil
+}
+
+// Claim stores the commit of an edit if it is still open at the revision it was
+// previewed at; it returns false when it is not.
+func (r *PostgresOrderEditRepository) Claim(ctx context.Context, edit *domain.OrderEdit) (bool, error) {
+	tag, err := r.db.Pool().Exec(ctx, `
+		UPDATE order_edit
+		SET status = $3, closed_by = $4, closed_at = $5, updated_at = $6
+		WHERE edit_id = $1 AND status = 'OPEN' AND previewed_revision = $2 AND revision = $2`,
+		edit.ID, edit.PreviewedRevision, string(edit.Status), edit.ClosedBy, edit.ClosedAt, edit.UpdatedAt,
+	)
+	if err != nil {
+		return false, errors.InternalWrap(err, "failed to claim order edit")
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Reopen moves a claimed edit whose changes could not be applied back to OPEN
+func (r *PostgresOrderEditRepository) Reopen(ctx context.Context, id int64) error {
+	err := r.db.Exec(ctx, `
+		UPDATE order_edit
+		SET status = 'OPEN', closed_by = '', closed_at = NULL, updated_at = $2
+		WHERE edit_id = $1 AND status = 'COMMITTED'`, id, time.Now())
+	if err != nil {
+		return errors.InternalWrap(err, "failed to reopen order edit")
+	}
+	return nil
+}
+
+// FindByID retrieves an edit by ID, nil if there is none
+func (r *PostgresOrderEditRepository) FindByID(ctx context.Context, id int64) (*domain.OrderEdit, error) {
+	query := `SELECT` + orderEditColumns + `
+		FROM order_edit
+		WHERE edit_id = $1`
+	return r.find(ctx, query, id)
+}
+
+// FindOpenByOrderID retrieves the open edit of an order, expired or not; nil if there is none
+func (r *PostgresOrderEditRepository) FindOpenByOrderID(ctx context.Context, orderID int64) (*domain.OrderEdit, error) {
+	query := `SELECT` + orderEditColumns + `
+		FROM order_edit
+		WHERE order_id = $1 AND status = 'OPEN'`
+	return r.find(ctx, query, orderID)
+}
+
+func (r *PostgresOrderEditRepository) find(ctx context.Context, query string, arg int64) (*domain.OrderEdit, error) {
+	edit := &domain.OrderEdit{}
+	var status string
+	var original, lines, summary []byte
+	err := r.db.QueryRow(ctx, query, arg).Scan(
+		&edit.ID, &edit.OrderID, &edit.CustomerID, &status, &edit.OpenedBy, &edit.CurrencyCode, &original, &lines,
+		&edit.Revision, &edit.PreviewedRevision, &summary, &edit.ExpiresAt, &edit.ClosedBy, &edit.ClosedAt, &edit.CreatedAt, &edit.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order edit")
+	}
+
+	edit.Status = domain.OrderEditStatus(status)
+	if err := json.Unmarshal(original, &edit.Original); err != nil {
+		return nil, errors.InternalWrap(fmt.Errorf("invalid totals of order edit %d: %w", edit.ID, err), "failed to find order edit")
+	}
+	if err := json.Unmarshal(lines, &edit.Lines); err != nil {
+		return nil, errors.InternalWrap(fmt.Errorf("invalid lines of order edit %d: %w", edit.ID, err), "failed to find order edit")
+	}
+	if summary != nil {
+		edit.Summary = &domain.OrderEditSummary{}
+		if err := json.Unmarshal(summary, edit.Summary); err != nil {
+			return nil, errors.InternalWrap(fmt.Errorf("invalid summary of order edit %d: %w", edit.ID, err), "failed to find order edit")
+		}
+	}
+	return edit, nil
+}
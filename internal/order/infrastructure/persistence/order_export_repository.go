@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderExportRepository implements the OrderExportRepository interface
+type PostgresOrderExportRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderExportRepository creates a new PostgresOrderExportRepository
+func NewPostgresOrderExportRepository(db *database.DB) *PostgresOrderExportRepository {
+	return &PostgresOrderExportRepository{db: db}
+}
+
+// StreamOrders calls fn for each matching order as rows arrive from the database
+func (r *PostgresOrderExportRepository) StreamOrders(ctx context.Context, filter *domain.OrderExportFilter, fn func(*domain.OrderExportRow) error) error {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	if filter.Status != nil {
+		args = append(args, string(*filter.Status))
+		conditions = append(conditions, fmt.Sprintf("order_status = $%d", len(args)))
+	}
+	if filter.CustomerID != nil {
+		args = append(args, *filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("date_created >= $%d", len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("date_created < $%d", len(args)))
+	}
+
+	query := `
+		SELECT order_id, COALESCE(order_number, ''), customer_id, COALESCE(email_address, ''), COALESCE(name, ''),
+			   order_status, COALESCE(currency_code, ''), COALESCE(order_subtotal, 0), COALESCE(total_tax, 0),
+			   COALESCE(total_shipping, 0), COALESCE(order_total, 0), submit_date, date_created
+		FROM blc_order`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY order_id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to export orders")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &domain.OrderExportRow{}
+		var status string
+		if err := rows.Scan(
+			&row.ID, &row.OrderNumber, &row.CustomerID, &row.EmailAddress, &row.Name,
+			&status, &row.CurrencyCode, &row.SubTotal, &row.TotalTax,
+			&row.TotalShipping, &row.Total, &row.SubmitDate, &row.CreatedAt,
+		); err != nil {
+			return errors.InternalWrap(err, "failed to scan exported order")
+		}
+		row.Status = domain.OrderStatus(status)
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.InternalWrap(err, "failed to iterate exported orders")
+	}
+	return nil
+}
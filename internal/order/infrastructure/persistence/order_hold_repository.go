@@ -0,0 +1,184 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderHoldRepository implements the OrderHoldRepository interface
+type PostgresOrderHoldRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderHoldRepository creates a new PostgresOrderHoldRepository
+func NewPostgresOrderHoldRepository(db *database.DB) *PostgresOrderHoldRepository {
+	return &PostgresOrderHoldRepository{db: db}
+}
+
+const orderHoldColumns = `
+	h.hold_id, h.order_id, h.reason, h.note, h.previous_status, h.placed_by, h.placed_at,
+	COALESCE(h.resolution, ''), h.resolved_by, h.resolution_note, h.resolved_at, h.sla_notified_at,
+	COALESCE(o.order_number, ''), COALESCE(o.email_address, ''), COALESCE(o.order_total, 0), COALESCE(o.currency_code, '')`
+
+const orderHoldFrom = `
+	FROM order_hold h
+	JOIN blc_order o ON o.order_id = h.order_id`
+
+// Place stores a new hold and puts its order on hold in one transaction.
+func (r *PostgresOrderHoldRepository) Place(ctx context.Context, hold *domain.OrderHold) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Only the status the hold was placed from can be held, so two holds never race
+		tag, err := tx.Exec(ctx, `
+			UPDATE blc_order SET order_status = $3, date_updated = $4
+			WHERE order_id = $1 AND order_status = $2`,
+			hold.OrderID, string(hold.PreviousStatus), string(domain.OrderStatusOnHold), hold.PlacedAt,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to put order on hold")
+		}
+		if tag.RowsAffected() == 0 {
+			return errors.Conflict(fmt.Sprintf("order %d changed status while being held", hold.OrderID))
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO order_hold (order_id, reason, note, previous_status, placed_by, placed_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING hold_id`,
+			hold.OrderID, string(hold.Reason), hold.Note, string(hold.PreviousStatus), hold.PlacedBy, hold.PlacedAt,
+		).Scan(&hold.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create order hold")
+		}
+		return nil
+	})
+}
+
+// Release saves a released hold and restores its order's previous status in one transaction.
+func (r *PostgresOrderHoldRepository) Release(ctx context.Context, hold *domain.OrderHold) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE blc_order SET order_status = $3, date_updated = $4
+			WHERE order_id = $1 AND order_status = $2`,
+			hold.OrderID, string(domain.OrderStatusOnHold), string(hold.PreviousStatus), hold.ResolvedAt,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to release order")
+		}
+		if tag.RowsAffected() == 0 {
+			return errors.Conflict(fmt.Sprintf("order %d is no longer on hold", hold.OrderID))
+		}
+		return r.update(ctx, tx, hold)
+	})
+}
+
+// Update saves the state of a hold without touching its order.
+func (r *PostgresOrderHoldRepository) Update(ctx context.Context, hold *domain.OrderHold) error {
+	return r.update(ctx, r.db.Pool(), hold)
+}
+
+// holdExecer is satisfied by both the pool and transactions
+type holdExecer interface {
+	Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error)
+}
+
+func (r *PostgresOrderHoldRepository) update(ctx context.Context, db holdExecer, hold *domain.OrderHold) error {
+	var resolution *string
+	if !hold.IsOpen() {
+		value := string(hold.Resolution)
+		resolution = &value
+	}
+	_, err := db.Exec(ctx, `
+		UPDATE order_hold
+		SET resolution = $2, resolved_by = $3, resolution_note = $4, resolved_at = $5, sla_notified_at = $6
+		WHERE hold_id = $1`,
+		hold.ID, resolution, hold.ResolvedBy, hold.ResolutionNote, hold.ResolvedAt, hold.SLANotifiedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update order hold")
+	}
+	return nil
+}
+
+// FindOpenByOrderID retrieves the open hold of an order, nil if it has none.
+func (r *PostgresOrderHoldRepository) FindOpenByOrderID(ctx context.Context, orderID int64) (*domain.OrderHold, error) {
+	query := `SELECT` + orderHoldColumns + orderHoldFrom + `
+		WHERE h.order_id = $1 AND h.resolution IS NULL`
+	hold, err := scanOrderHold(r.db.QueryRow(ctx, query, orderID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order hold")
+	}
+	return hold, nil
+}
+
+// FindByOrderID retrieves every hold of an order, newest first.
+func (r *PostgresOrderHoldRepository) FindByOrderID(ctx context.Context, orderID int64) ([]*domain.OrderHold, error) {
+	query := `SELECT` + orderHoldColumns + orderHoldFrom + `
+		WHERE h.order_id = $1
+		ORDER BY h.placed_at DESC, h.hold_id DESC`
+	return r.query(ctx, query, orderID)
+}
+
+// FindOpen retrieves the open holds, oldest first; an empty reason returns every reason.
+func (r *PostgresOrderHoldRepository) FindOpen(ctx context.Context, reason domain.HoldReason) ([]*domain.OrderHold, error) {
+	query := `SELECT` + orderHoldColumns + orderHoldFrom + `
+		WHERE h.resolution IS NULL AND ($1 = '' OR h.reason = $1)
+		ORDER BY h.placed_at, h.hold_id`
+	return r.query(ctx, query, string(reason))
+}
+
+// FindOverdue retrieves open holds placed before placedBefore that staff were not told about yet.
+func (r *PostgresOrderHoldRepository) FindOverdue(ctx context.Context, placedBefore time.Time) ([]*domain.OrderHold, error) {
+	query := `SELECT` + orderHoldColumns + orderHoldFrom + `
+		WHERE h.resolution IS NULL AND h.sla_notified_at IS NULL AND h.placed_at < $1
+		ORDER BY h.placed_at, h.hold_id`
+	return r.query(ctx, query, placedBefore)
+}
+
+func (r *PostgresOrderHoldRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.OrderHold, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query order holds")
+	}
+	defer rows.Close()
+
+	holds := make([]*domain.OrderHold, 0)
+	for rows.Next() {
+		hold, err := scanOrderHold(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order hold")
+		}
+		holds = append(holds, hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order holds")
+	}
+	return holds, nil
+}
+
+func scanOrderHold(row pgx.Row) (*domain.OrderHold, error) {
+	hold := &domain.OrderHold{}
+	var reason, previousStatus, resolution string
+	err := row.Scan(
+		&hold.ID, &hold.OrderID, &reason, &hold.Note, &previousStatus, &hold.PlacedBy, &hold.PlacedAt,
+		&resolution, &hold.ResolvedBy, &hold.ResolutionNote, &hold.ResolvedAt, &hold.SLANotifiedAt,
+		&hold.OrderNumber, &hold.EmailAddress, &hold.OrderTotal, &hold.CurrencyCode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	hold.Reason = domain.HoldReason(reason)
+	hold.PreviousStatus = domain.OrderStatus(previousStatus)
+	hold.Resolution = domain.HoldResolution(resolution)
+	return hold, nil
+}
@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderLandedCostRepository implements the OrderLandedCostRepository interface
+type PostgresOrderLandedCostRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderLandedCostRepository creates a new PostgresOrderLandedCostRepository
+func NewPostgresOrderLandedCostRepository(db *database.DB) *PostgresOrderLandedCostRepository {
+	return &PostgresOrderLandedCostRepository{db: db}
+}
+
+// Save stores the landed cost estimate of an order, replacing any previous one
+func (r *PostgresOrderLandedCostRepository) Save(ctx context.Context, cost *domain.OrderLandedCost) error {
+	lines, err := json.Marshal(cost.Lines)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode order landed cost lines")
+	}
+
+	query := `
+		INSERT INTO order_landed_cost (
+			order_id, incoterm, ship_from_country, destination_country, order_subtotal, shipping_amount,
+			duty, import_tax, fees, total, de_minimis_applied, currency_code, provider, lines, estimated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (order_id) DO UPDATE
+		SET incoterm = EXCLUDED.incoterm, ship_from_country = EXCLUDED.ship_from_country,
+			destination_country = EXCLUDED.destination_country, order_subtotal = EXCLUDED.order_subtotal,
+			shipping_amount = EXCLUDED.shipping_amount, duty = EXCLUDED.duty, import_tax = EXCLUDED.import_tax,
+			fees = EXCLUDED.fees, total = EXCLUDED.total, de_minimis_applied = EXCLUDED.de_minimis_applied,
+			currency_code = EXCLUDED.currency_code, provider = EXCLUDED.provider, lines = EXCLUDED.lines,
+			estimated_at = EXCLUDED.estimated_at`
+
+	err = r.db.Exec(ctx, query,
+		cost.OrderID, string(cost.Incoterm), cost.ShipFromCountry, cost.DestinationCountry, cost.OrderSubtotal, cost.ShippingAmount,
+		cost.Duty, cost.ImportTax, cost.Fees, cost.Total, cost.DeMinimisApplied, cost.CurrencyCode, cost.Provider, lines, cost.EstimatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save order landed cost")
+	}
+	return nil
+}
+
+// FindByOrderID retrieves the landed cost estimate of an order, nil if it has none
+func (r *PostgresOrderLandedCostRepository) FindByOrderID(ctx context.Context, orderID int64) (*domain.OrderLandedCost, error) {
+	query := `
+		SELECT order_id, incoterm, ship_from_country, destination_country, order_subtotal::float8, shipping_amount::float8,
+			duty::float8, import_tax::float8, fees::float8, total::float8, de_minimis_applied, currency_code,
+			provider, lines, estimated_at
+		FROM order_landed_cost
+		WHERE order_id = $1`
+
+	cost := &domain.OrderLandedCost{}
+	var incoterm string
+	var lines []byte
+	err := r.db.QueryRow(ctx, query, orderID).Scan(
+		&cost.OrderID, &incoterm, &cost.ShipFromCountry, &cost.DestinationCountry, &cost.OrderSubtotal, &cost.ShippingAmount,
+		&cost.Duty, &cost.ImportTax, &cost.Fees, &cost.Total, &cost.DeMinimisApplied, &cost.CurrencyCode,
+		&cost.Provider, &lines, &cost.EstimatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order landed cost")
+	}
+
+	cost.Incoterm = domain.Incoterm(incoterm)
+	if err := json.Unmarshal(lines, &cost.Lines); err != nil {
+		return nil, errors.InternalWrap(fmt.Errorf("invalid lines of order %d landed cost: %w", orderID, err), "failed to find order landed cost")
+	}
+	return cost, nil
+}
+
+// DeleteByOrderID removes the landed cost estimate of an order
+func (r *PostgresOrderLandedCostRepository) DeleteByOrderID(ctx context.Context, orderID int64) error {
+	if err := r.db.Exec(ctx, `DELETE FROM order_landed_cost WHERE order_id = $1`, orderID); err != nil {
+		return errors.InternalWrap(err, "failed to delete order landed cost")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderNoteRepository implements the OrderNoteRepository interface
+type PostgresOrderNoteRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderNoteRepository creates a new PostgresOrderNoteRepository
+func NewPostgresOrderNoteRepository(db *database.DB) *PostgresOrderNoteRepository {
+	return &PostgresOrderNoteRepository{db: db}
+}
+
+// Create stores a new order note.
+func (r *PostgresOrderNoteRepository) Create(ctx context.Context, note *domain.OrderNote) error {
+	query := `
+		INSERT INTO order_note (order_id, author, body, customer_visible, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING order_note_id`
+	err := r.db.QueryRow(ctx, query, note.OrderID, note.Author, note.Body, note.CustomerVisible, note.CreatedAt).
+		Scan(&note.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create order note")
+	}
+	return nil
+}
+
+// FindByOrderID retrieves the notes of an order, oldest first.
+func (r *PostgresOrderNoteRepository) FindByOrderID(ctx context.Context, orderID int64, customerVisibleOnly bool) ([]*domain.OrderNote, error) {
+	query := `
+		SELECT order_note_id, order_id, author, body, customer_visible, created_at
+		FROM order_note
+		WHERE order_id = $1`
+	if customerVisibleOnly {
+		query += ` AND customer_visible = TRUE`
+	}
+	query += ` ORDER BY created_at, order_note_id`
+
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list order notes")
+	}
+	defer rows.Close()
+
+	notes := make([]*domain.OrderNote, 0)
+	for rows.Next() {
+		note := &domain.OrderNote{}
+		if err := rows.Scan(&note.ID, &note.OrderID, &note.Author, &note.Body, &note.CustomerVisible, &note.CreatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order note")
+		}
+		notes = append(notes, note)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order notes")
+	}
+	return notes, nil
+}
@@ -35,8 +35,8 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, order *domain.Orde
 		INSERT INTO blc_order (
 			order_number, customer_id, email_address, name, order_status,
 			order_subtotal, total_tax, total_shipping, order_total, currency_code,
-			submit_date, date_created, date_updated
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			submit_date, date_created, date_updated, channel
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING order_id
 	`
 
@@ -54,6 +54,7 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, order *domain.Orde
 		order.SubmitDate,
 		order.CreatedAt,
 		order.UpdatedAt,
+		order.Channel,
 	).Scan(&order.ID)
 
 	if err != nil {
@@ -91,6 +92,18 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, order *domain.Orde
 		}
 	}
 
+	// Insert order attributes
+	for name, value := range order.Attributes {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO blc_order_attribute (order_attribute_id, name, value, order_id)
+			VALUES (nextval('blc_order_attribute_seq'), $1, $2, $3)`,
+			name, value, order.ID,
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to insert order attribute")
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return errors.InternalWrap(err, "failed to commit transaction")
 	}
@@ -111,7 +124,8 @@ func (r *PostgresOrderRepository) Update(ctx context.Context, order *domain.Orde
 		UPDATE blc_order
 		SET order_number = $1, customer_id = $2, email_address = $3, name = $4,
 			order_status = $5, order_subtotal = $6, total_tax = $7, total_shipping = $8,
-			order_total = $9, currency_code = $10, submit_date = $11, date_updated = $12
+			order_total = $9, currency_code = $10, submit_date = $11, date_updated = $12,
+			estimated_tax = $14, tax_reconciliation = $15, tax_provider = NULLIF($16, ''), total_duties = $17
 		WHERE order_id = $13
 	`
 
@@ -129,6 +143,10 @@ func (r *PostgresOrderRepository) Update(ctx context.Context, order *domain.Orde
 		order.SubmitDate,
 		order.UpdatedAt,
 		order.ID,
+		order.EstimatedTax,
+		order.TaxReconciliation,
+		order.TaxProvider,
+		order.TotalDuties,
 	)
 
 	if err != nil {
@@ -136,6 +154,13 @@ func (r *PostgresOrderRepository) Update(ctx context.Context, order *domain.Orde
 	}
 
 	if tag.RowsAffected() == 0 {
+		var archived bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM blc_order_archive WHERE order_id = $1)", order.ID).Scan(&archived); err != nil {
+			return errors.InternalWrap(err, "failed to check archived order")
+		}
+		if archived {
+			return errors.Conflict(fmt.Sprintf("order %d is archived; restore it before changing it", order.ID))
+		}
 		return errors.NotFound(fmt.Sprintf("order %d", order.ID))
 	}
 
@@ -184,71 +209,50 @@ func (r *PostgresOrderRepository) Update(ctx context.Context, order *domain.Orde
 	return nil
 }
 
-// FindByID finds an order by ID
+// FindByID finds an order by ID, falling back to the archive
 func (r *PostgresOrderRepository) FindByID(ctx context.Context, id int64) (*domain.Order, error) {
-	query := `
-		SELECT order_id, order_number, customer_id, email_address, name, order_status,
-			   order_subtotal, total_tax, total_shipping, order_total, currency_code,
-			   submit_date, date_created, date_updated
-		FROM blc_order
-		WHERE order_id = $1
-	`
-
-	order := &domain.Order{}
-	var submitDate sql.NullTime
-
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&order.ID,
-		&order.OrderNumber,
-		&order.CustomerID,
-		&order.EmailAddress,
-		&order.Name,
-		&order.Status,
-		&order.OrderSubtotal,
-		&order.TotalTax,
-		&order.TotalShipping,
-		&order.OrderTotal,
-		&order.CurrencyCode,
-		&submitDate,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
-
-	if err == pgx.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, errors.InternalWrap(err, "failed to find order by ID")
-	}
-
-	if submitDate.Valid {
-		order.SubmitDate = &submitDate.Time
+	order, err := r.findOrder(ctx, liveOrderTables, "order_id = $1", id)
+	if err != nil || order != nil {
+		return order, err
 	}
+	return r.findOrder(ctx, archivedOrderTables, "order_id = $1", id)
+}
 
-	// Load order items
-	items, err := r.findOrderItems(ctx, order.ID)
-	if err != nil {
-		return nil, err
+// FindByOrderNumber finds an order by order number, falling back to the archive
+func (r *PostgresOrderRepository) FindByOrderNumber(ctx context.Context, orderNumber string) (*domain.Order, error) {
+	order, err := r.findOrder(ctx, liveOrderTables, "order_number = $1", orderNumber)
+	if err != nil || order != nil {
+		return order, err
 	}
-	order.Items = items
+	return r.findOrder(ctx, archivedOrderTables, "order_number = $1", orderNumber)
+}
 
-	return order, nil
+// orderTables names the tables an order is read from: the live ones or their archive copies
+type orderTables struct {
+	order, item, attribute string
+	archived               bool
 }
 
-// FindByOrderNumber finds an order by order number
-func (r *PostgresOrderRepository) FindByOrderNumber(ctx context.Context, orderNumber string) (*domain.Order, error) {
+var (
+	liveOrderTables     = orderTables{order: "blc_order", item: "blc_order_item", attribute: "blc_order_attribute"}
+	archivedOrderTables = orderTables{order: "blc_order_archive", item: "blc_order_item_archive", attribute: "blc_order_attribute_archive", archived: true}
+)
+
+// findOrder finds the order matching where with its items and attributes, nil if there is none
+func (r *PostgresOrderRepository) findOrder(ctx context.Context, tables orderTables, where string, arg interface{}) (*domain.Order, error) {
 	query := `
 		SELECT order_id, order_number, customer_id, email_address, name, order_status,
 			   order_subtotal, total_tax, total_shipping, order_total, currency_code,
-			   submit_date, date_created, date_updated
-		FROM blc_order
-		WHERE order_number = $1
-	`
+			   submit_date, date_created, date_updated,
+			   estimated_tax, tax_reconciliation, tax_provider, channel, total_duties
+		FROM ` + tables.order + `
+		WHERE ` + where
 
-	order := &domain.Order{}
+	order := &domain.Order{Archived: tables.archived}
 	var submitDate sql.NullTime
+	var taxProvider sql.NullString
 
-	err := r.db.QueryRow(ctx, query, orderNumber).Scan(
+	err := r.db.QueryRow(ctx, query, arg).Scan(
 		&order.ID,
 		&order.OrderNumber,
 		&order.CustomerID,
@@ -263,37 +267,61 @@ func (r *PostgresOrderRepository) FindByOrderNumber(ctx context.Context, orderNu
 		&submitDate,
 		&order.CreatedAt,
 		&order.UpdatedAt,
+		&order.EstimatedTax,
+		&order.TaxReconciliation,
+		&taxProvider,
+		&order.Channel,
+		&order.TotalDuties,
 	)
 
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
-		return nil, errors.InternalWrap(err, "failed to find order by order number")
+		return nil, errors.InternalWrap(err, "failed to find order")
 	}
 
 	if submitDate.Valid {
 		order.SubmitDate = &submitDate.Time
 	}
+	order.TaxProvider = taxProvider.String
 
 	// Load order items
-	items, err := r.findOrderItems(ctx, order.ID)
+	items, err := r.findOrderItems(ctx, tables, order.ID)
 	if err != nil {
 		return nil, err
 	}
 	order.Items = items
 
+	attributes, err := r.findOrderAttributes(ctx, tables, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	order.Attributes = attributes
+
 	return order, nil
 }
 
+// customerOrdersSource is the live orders together with the archived ones
+const customerOrdersSource = `
+	SELECT order_id, order_number, customer_id, email_address, name, order_status,
+		   order_subtotal, total_tax, total_shipping, order_total, currency_code,
+		   submit_date, date_created, date_updated, channel, FALSE AS archived
+	FROM blc_order
+	UNION ALL
+	SELECT order_id, order_number, customer_id, email_address, name, order_status,
+		   order_subtotal, total_tax, total_shipping, order_total, currency_code,
+		   submit_date, date_created, date_updated, channel, TRUE AS archived
+	FROM blc_order_archive`
+
 // FindByCustomerID finds orders by customer ID
 func (r *PostgresOrderRepository) FindByCustomerID(ctx context.Context, customerID int64, filter *domain.OrderFilter) ([]*domain.Order, int64, error) {
-	// Build query
+	// Build query; the order history includes archived orders
 	query := `
 		SELECT order_id, order_number, customer_id, email_address, name, order_status,
 			   order_subtotal, total_tax, total_shipping, order_total, currency_code,
-			   submit_date, date_created, date_updated
-		FROM blc_order
+			   submit_date, date_created, date_updated, channel, archived
+		FROM (` + customerOrdersSource + `) o
 		WHERE customer_id = $1
 	`
 
@@ -308,7 +336,7 @@ func (r *PostgresOrderRepository) FindByCustomerID(ctx context.Context, customer
 	}
 
 	// Count total
-	countQuery := "SELECT COUNT(*) FROM blc_order WHERE customer_id = $1"
+	countQuery := "SELECT COUNT(*) FROM (" + customerOrdersSource + ") o WHERE customer_id = $1"
 	countArgs := []interface{}{customerID}
 	if filter != nil && filter.Status != nil && *filter.Status != "" {
 		countQuery += " AND order_status = $2"
@@ -365,6 +393,8 @@ func (r *PostgresOrderRepository) FindByCustomerID(ctx context.Context, customer
 			&submitDate,
 			&order.CreatedAt,
 			&order.UpdatedAt,
+			&order.Channel,
+			&order.Archived,
 		)
 		if err != nil {
 			return nil, 0, errors.InternalWrap(err, "failed to scan order")
@@ -375,7 +405,11 @@ func (r *PostgresOrderRepository) FindByCustomerID(ctx context.Context, customer
 		}
 
 		// Load order items
-		items, err := r.findOrderItems(ctx, order.ID)
+		tables := liveOrderTables
+		if order.Archived {
+			tables = archivedOrderTables
+		}
+		items, err := r.findOrderItems(ctx, tables, order.ID)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -397,7 +431,7 @@ func (r *PostgresOrderRepository) FindAll(ctx context.Context, filter *domain.Or
 	query := `
 		SELECT order_id, order_number, customer_id, email_address, name, order_status,
 			   order_subtotal, total_tax, total_shipping, order_total, currency_code,
-			   submit_date, date_created, date_updated
+			   submit_date, date_created, date_updated, channel
 		FROM blc_order
 		WHERE 1=1
 	`
@@ -485,6 +519,7 @@ func (r *PostgresOrderRepository) FindAll(ctx context.Context, filter *domain.Or
 			&submitDate,
 			&order.CreatedAt,
 			&order.UpdatedAt,
+			&order.Channel,
 		)
 		if err != nil {
 			return nil, 0, errors.InternalWrap(err, "failed to scan order")
@@ -495,7 +530,7 @@ func (r *PostgresOrderRepository) FindAll(ctx context.Context, filter *domain.Or
 		}
 
 		// Load order items
-		items, err := r.findOrderItems(ctx, order.ID)
+		items, err := r.findOrderItems(ctx, liveOrderTables, order.ID)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -512,11 +547,11 @@ func (r *PostgresOrderRepository) FindAll(ctx context.Context, filter *domain.Or
 }
 
 // findOrderItems finds all items for an order
-func (r *PostgresOrderRepository) findOrderItems(ctx context.Context, orderID int64) ([]domain.OrderItem, error) {
+func (r *PostgresOrderRepository) findOrderItems(ctx context.Context, tables orderTables, orderID int64) ([]domain.OrderItem, error) {
 	query := `
 		SELECT order_item_id, order_id, sku_id, name, quantity, price, total_price,
 			   tax_amount, shipping_amount
-		FROM blc_order_item
+		FROM ` + tables.item + `
 		WHERE order_id = $1
 		ORDER BY order_item_id
 	`
@@ -552,4 +587,27 @@ func (r *PostgresOrderRepository) findOrderItems(ctx context.Context, orderID in
 	}
 
 	return items, nil
-}
\ No newline at end of file
+}
+
+// findOrderAttributes finds the attributes of an order by name
+func (r *PostgresOrderRepository) findOrderAttributes(ctx context.Context, tables orderTables, orderID int64) (map[string]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT name, value FROM `+tables.attribute+` WHERE order_id = $1`, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order attributes")
+	}
+	defer rows.Close()
+
+	attributes := make(map[string]string)
+	for rows.Next() {
+		var name string
+		var value sql.NullString
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order attribute")
+		}
+		attributes[name] = value.String
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order attributes")
+	}
+	return attributes, nil
+}
@@ -0,0 +1,417 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderTagRepository implements the OrderTagRepository interface
+type PostgresOrderTagRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderTagRepository creates a new PostgresOrderTagRepository
+func NewPostgresOrderTagRepository(db *database.DB) *PostgresOrderTagRepository {
+	return &PostgresOrderTagRepository{db: db}
+}
+
+const orderTagRuleColumns = `rule_id, name, tag, conditions, active, COALESCE(created_by, ''), created_at, updated_at`
+
+// AddTags puts tags on an order, keeping tags it already carries.
+func (r *PostgresOrderTagRepository) AddTags(ctx context.Context, tags []*domain.OrderTag) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return insertOrderTags(ctx, tx, tags)
+	})
+}
+
+// RemoveTag removes a tag from an order, reporting whether it carried it.
+func (r *PostgresOrderTagRepository) RemoveTag(ctx context.Context, orderID int64, tag string) (bool, error) {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM order_tag WHERE order_id = $1 AND tag = $2`, orderID, tag)
+	if err != nil {
+		return false, errors.InternalWrap(err, "failed to remove order tag")
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// FindByOrderID retrieves the tags of an order, alphabetically.
+func (r *PostgresOrderTagRepository) FindByOrderID(ctx context.Context, orderID int64) ([]*domain.OrderTag, error) {
+	query := `
+		SELECT order_id, tag, source, rule_id, COALESCE(created_by, ''), created_at
+		FROM order_tag
+		WHERE order_id = $1
+		ORDER BY tag`
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order tags")
+	}
+	defer rows.Close()
+
+	tags := make([]*domain.OrderTag, 0)
+	for rows.Next() {
+		tag := &domain.OrderTag{}
+		var source string
+		var ruleID sql.NullInt64
+		if err := rows.Scan(&tag.OrderID, &tag.Tag, &source, &ruleID, &tag.CreatedBy, &tag.CreatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order tag")
+		}
+		tag.Source = domain.OrderTagSource(source)
+		if ruleID.Valid {
+			tag.RuleID = &ruleID.Int64
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order tags")
+	}
+	return tags, nil
+}
+
+// CountTags counts the orders carrying each tag, by status, alphabetically.
+func (r *PostgresOrderTagRepository) CountTags(ctx context.Context) ([]*domain.OrderTagCount, error) {
+	query := `
+		SELECT t.tag, COALESCE(o.order_status, ''), COUNT(*)
+		FROM order_tag t
+		JOIN blc_order o ON o.order_id = t.order_id
+		GROUP BY t.tag, o.order_status
+		ORDER BY t.tag, o.order_status`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to count order tags")
+	}
+	defer rows.Close()
+
+	counts := make([]*domain.OrderTagCount, 0)
+	var current *domain.OrderTagCount
+	for rows.Next() {
+		var tag, status string
+		var orders int64
+		if err := rows.Scan(&tag, &status, &orders); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order tag count")
+		}
+		if current == nil || current.Tag != tag {
+			current = &domain.OrderTagCount{Tag: tag, ByStatus: make(map[domain.OrderStatus]int64)}
+			counts = append(counts, current)
+		}
+		current.Orders += orders
+		current.ByStatus[domain.OrderStatus(status)] += orders
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order tag counts")
+	}
+	return counts, nil
+}
+
+// FindQueue retrieves a page of the orders matching the filter with their tags, and
+// the total number of matching orders.
+func (r *PostgresOrderTagRepository) FindQueue(ctx context.Context, filter *domain.OrderQueueFilter) ([]*domain.OrderQueueItem, int64, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	if len(filter.AllTags) > 0 {
+		args = append(args, filter.AllTags)
+		conditions = append(conditions, fmt.Sprintf(
+			"(SELECT COUNT(*) FROM order_tag t WHERE t.order_id = o.order_id AND t.tag = ANY($%d)) = %d",
+			len(args), len(filter.AllTags)))
+	}
+	if len(filter.AnyTags) > 0 {
+		args = append(args, filter.AnyTags)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM order_tag t WHERE t.order_id = o.order_id AND t.tag = ANY($%d))", len(args)))
+	}
+	if len(filter.ExcludeTags) > 0 {
+		args = append(args, filter.ExcludeTags)
+		conditions = append(conditions, fmt.Sprintf(
+			"NOT EXISTS (SELECT 1 FROM order_tag t WHERE t.order_id = o.order_id AND t.tag = ANY($%d))", len(args)))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		args = append(args, statuses)
+		conditions = append(conditions, fmt.Sprintf("o.order_status = ANY($%d)", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM blc_order o`+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count order queue")
+	}
+
+	direction := "ASC"
+	if filter.NewestFirst {
+		direction = "DESC"
+	}
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query := `
+		SELECT o.order_id, COALESCE(o.order_number, ''), COALESCE(o.order_status, ''), o.customer_id,
+			COALESCE(o.email_address, ''), COALESCE(o.order_total, 0), COALESCE(o.currency_code, ''), o.submit_date,
+			COALESCE((SELECT array_agg(t.tag ORDER BY t.tag) FROM order_tag t WHERE t.order_id = o.order_id), '{}')
+		FROM blc_order o` + where + fmt.Sprintf(`
+		ORDER BY o.submit_date %s NULLS LAST, o.order_id %s
+		LIMIT $%d OFFSET $%d`, direction, direction, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to find order queue")
+	}
+	defer rows.Close()
+
+	items := make([]*domain.OrderQueueItem, 0)
+	for rows.Next() {
+		item := &domain.OrderQueueItem{}
+		var status string
+		var submitDate sql.NullTime
+		if err := rows.Scan(
+			&item.OrderID, &item.OrderNumber, &status, &item.CustomerID,
+			&item.EmailAddress, &item.OrderTotal, &item.CurrencyCode, &submitDate, &item.Tags,
+		); err != nil {
+			return nil, 0, errors.InternalWrap(err, "failed to scan order queue item")
+		}
+		item.Status = domain.OrderStatus(status)
+		if submitDate.Valid {
+			item.SubmitDate = &submitDate.Time
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to iterate order queue")
+	}
+	return items, total, nil
+}
+
+// ReplaceRuleTags replaces the rule tags of an order with tags in one transaction,
+// leaving its staff tags alone, and records when the rules were applied.
+func (r *PostgresOrderTagRepository) ReplaceRuleTags(ctx context.Context, orderID int64, tags []*domain.OrderTag, appliedAt time.Time) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM order_tag WHERE order_id = $1 AND source = $2`,
+			orderID, string(domain.OrderTagSourceRule)); err != nil {
+			return errors.InternalWrap(err, "failed to clear order rule tags")
+		}
+		if err := insertOrderTags(ctx, tx, tags); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE blc_order SET tag_rules_applied_at = $2 WHERE order_id = $1`,
+			orderID, appliedAt); err != nil {
+			return errors.InternalWrap(err, "failed to record order tag rule run")
+		}
+		return nil
+	})
+}
+
+// FindPendingRuleOrders retrieves up to limit submitted orders the rules have not
+// evaluated yet, oldest first.
+func (r *PostgresOrderTagRepository) FindPendingRuleOrders(ctx context.Context, limit int) ([]int64, error) {
+	query := `
+		SELECT order_id FROM blc_order
+		WHERE submit_date IS NOT NULL AND tag_rules_applied_at IS NULL
+		ORDER BY submit_date, order_id
+		LIMIT $1`
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find orders pending tag rules")
+	}
+	defer rows.Close()
+
+	orderIDs := make([]int64, 0)
+	for rows.Next() {
+		var orderID int64
+		if err := rows.Scan(&orderID); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order pending tag rules")
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate orders pending tag rules")
+	}
+	return orderIDs, nil
+}
+
+// FindFacts gathers what tag rules are evaluated on for an order, nil if it does not exist.
+func (r *PostgresOrderTagRepository) FindFacts(ctx context.Context, orderID int64) (*domain.OrderTagFacts, error) {
+	facts := &domain.OrderTagFacts{SKUAttributes: make(map[string][]string)}
+	var customerID int64
+	var country sql.NullString
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(o.order_total, 0), COALESCE(o.currency_code, ''), o.customer_id,
+			COALESCE((SELECT SUM(oi.quantity) FROM blc_order_item oi WHERE oi.order_id = o.order_id), 0),
+			(SELECT a.iso_country_alpha2
+			 FROM blc_fulfillment_group fg
+			 JOIN blc_address a ON a.address_id = fg.address_id
+			 WHERE fg.order_id = o.order_id
+			 ORDER BY fg.is_primary DESC NULLS LAST, fg.fulfillment_group_id
+			 LIMIT 1)
+		FROM blc_order o
+		WHERE o.order_id = $1`, orderID,
+	).Scan(&facts.OrderTotal, &facts.CurrencyCode, &customerID, &facts.ItemQuantity, &country)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order tag facts")
+	}
+	facts.ShippingCountry = country.String
+
+	facts.CustomerSegments, err = NewPostgresCartPolicyContextRepository(r.db).FindCustomerSegments(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT oi.sku_id, sa.name, sa.value
+		FROM blc_order_item oi
+		LEFT JOIN blc_sku_attribute sa ON sa.sku_id = oi.sku_id
+		WHERE oi.order_id = $1 AND oi.sku_id IS NOT NULL
+		ORDER BY oi.sku_id`, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find ordered SKU attributes")
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]bool)
+	for rows.Next() {
+		var skuID int64
+		var name, value sql.NullString
+		if err := rows.Scan(&skuID, &name, &value); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan ordered SKU attribute")
+		}
+		if !seen[skuID] {
+			seen[skuID] = true
+			facts.SKUIDs = append(facts.SKUIDs, skuID)
+		}
+		if name.Valid {
+			facts.SKUAttributes[name.String] = append(facts.SKUAttributes[name.String], value.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate ordered SKU attributes")
+	}
+	return facts, nil
+}
+
+// SaveRule creates or updates a tag rule.
+func (r *PostgresOrderTagRepository) SaveRule(ctx context.Context, rule *domain.OrderTagRule) error {
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to encode order tag rule conditions")
+	}
+
+	if rule.ID == 0 {
+		err := r.db.QueryRow(ctx, `
+			INSERT INTO order_tag_rule (name, tag, conditions, active, created_by, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+			RETURNING rule_id`,
+			rule.Name, rule.Tag, conditions, rule.Active, rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt,
+		).Scan(&rule.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create order tag rule")
+		}
+		return nil
+	}
+
+	result, err := r.db.Pool().Exec(ctx, `
+		UPDATE order_tag_rule SET name = $1, tag = $2, conditions = $3, active = $4, updated_at = $5
+		WHERE rule_id = $6`,
+		rule.Name, rule.Tag, conditions, rule.Active, rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update order tag rule")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("order tag rule not found")
+	}
+	return nil
+}
+
+// FindRuleByID retrieves a tag rule, nil if it does not exist.
+func (r *PostgresOrderTagRepository) FindRuleByID(ctx context.Context, id int64) (*domain.OrderTagRule, error) {
+	query := `SELECT ` + orderTagRuleColumns + ` FROM order_tag_rule WHERE rule_id = $1`
+	rule, err := scanOrderTagRule(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order tag rule")
+	}
+	return rule, nil
+}
+
+// FindRules retrieves the tag rules by name; activeOnly leaves out disabled rules.
+func (r *PostgresOrderTagRepository) FindRules(ctx context.Context, activeOnly bool) ([]*domain.OrderTagRule, error) {
+	query := `SELECT ` + orderTagRuleColumns + ` FROM order_tag_rule WHERE (NOT $1 OR active) ORDER BY name, rule_id`
+	rows, err := r.db.Query(ctx, query, activeOnly)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order tag rules")
+	}
+	defer rows.Close()
+
+	rules := make([]*domain.OrderTagRule, 0)
+	for rows.Next() {
+		rule, err := scanOrderTagRule(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order tag rule")
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order tag rules")
+	}
+	return rules, nil
+}
+
+// DeleteRule removes a tag rule; the tags it added stay on their orders.
+func (r *PostgresOrderTagRepository) DeleteRule(ctx context.Context, id int64) error {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM order_tag_rule WHERE rule_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete order tag rule")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("order tag rule not found")
+	}
+	return nil
+}
+
+// insertOrderTags adds tags their order does not carry yet. A staff tag takes over a rule
+// tag of the same name, so re-applying the rules no longer removes it.
+func insertOrderTags(ctx context.Context, tx pgx.Tx, tags []*domain.OrderTag) error {
+	for _, tag := range tags {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO order_tag (order_id, tag, source, rule_id, created_by, created_at)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+			ON CONFLICT (order_id, tag) DO UPDATE SET
+				source = EXCLUDED.source, rule_id = NULL, created_by = EXCLUDED.created_by, created_at = EXCLUDED.created_at
+			WHERE EXCLUDED.source = $7 AND order_tag.source <> $7`,
+			tag.OrderID, tag.Tag, string(tag.Source), tag.RuleID, tag.CreatedBy, tag.CreatedAt,
+			string(domain.OrderTagSourceManual),
+		)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to add order tag")
+		}
+	}
+	return nil
+}
+
+func scanOrderTagRule(row pgx.Row) (*domain.OrderTagRule, error) {
+	rule := &domain.OrderTagRule{}
+	var conditions []byte
+	err := row.Scan(&rule.ID, &rule.Name, &rule.Tag, &conditions, &rule.Active, &rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
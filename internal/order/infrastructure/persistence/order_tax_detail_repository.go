@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderTaxDetailRepository implements the OrderTaxDetailRepository interface
+type PostgresOrderTaxDetailRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderTaxDetailRepository creates a new PostgresOrderTaxDetailRepository
+func NewPostgresOrderTaxDetailRepository(db *database.DB) *PostgresOrderTaxDetailRepository {
+	return &PostgresOrderTaxDetailRepository{db: db}
+}
+
+// ReplaceForOrder replaces the tax details of an order with those of its latest calculation
+func (r *PostgresOrderTaxDetailRepository) ReplaceForOrder(ctx context.Context, orderID int64, details []*domain.OrderTaxDetail) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM order_tax_detail WHERE order_id = $1`, orderID); err != nil {
+			return errors.InternalWrap(err, "failed to clear order tax details")
+		}
+		for _, detail := range details {
+			err := tx.QueryRow(ctx, `
+				INSERT INTO order_tax_detail (
+					order_id, order_item_id, fulfillment_group_id, jurisdiction_name, tax_country, tax_region,
+					tax_name, rate, taxable_amount, amount, currency_code, provider, calculated_at
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+				RETURNING order_tax_detail_id`,
+				orderID, detail.OrderItemID, detail.FulfillmentGroupID, detail.JurisdictionName, detail.Country, detail.Region,
+				detail.TaxName, detail.Rate, detail.TaxableAmount, detail.Amount, detail.CurrencyCode, detail.Provider, detail.CalculatedAt,
+			).Scan(&detail.ID)
+			if err != nil {
+				return errors.InternalWrap(err, "failed to save order tax detail")
+			}
+			detail.OrderID = orderID
+		}
+		return nil
+	})
+}
+
+// FindByOrderID retrieves the tax details of an order, items first
+func (r *PostgresOrderTaxDetailRepository) FindByOrderID(ctx context.Context, orderID int64) ([]*domain.OrderTaxDetail, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT order_tax_detail_id, order_id, order_item_id, fulfillment_group_id, jurisdiction_name,
+			tax_country, tax_region, tax_name, rate::float8, taxable_amount::float8, amount::float8,
+			COALESCE(currency_code, ''), provider, calculated_at
+		FROM order_tax_detail
+		WHERE order_id = $1
+		ORDER BY order_item_id NULLS LAST, fulfillment_group_id, order_tax_detail_id`, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find order tax details")
+	}
+	defer rows.Close()
+
+	details := make([]*domain.OrderTaxDetail, 0)
+	for rows.Next() {
+		detail := &domain.OrderTaxDetail{}
+		err := rows.Scan(
+			&detail.ID, &detail.OrderID, &detail.OrderItemID, &detail.FulfillmentGroupID, &detail.JurisdictionName,
+			&detail.Country, &detail.Region, &detail.TaxName, &detail.Rate, &detail.TaxableAmount, &detail.Amount,
+			&detail.CurrencyCode, &detail.Provider, &detail.CalculatedAt,
+		)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order tax detail")
+		}
+		details = append(details, detail)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order tax details")
+	}
+	return details, nil
+}
@@ -0,0 +1,108 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresOrderTenderRepository implements the OrderTenderRepository interface
+type PostgresOrderTenderRepository struct {
+	db *database.DB
+}
+
+// NewPostgresOrderTenderRepository creates a new PostgresOrderTenderRepository
+func NewPostgresOrderTenderRepository(db *database.DB) *PostgresOrderTenderRepository {
+	return &PostgresOrderTenderRepository{db: db}
+}
+
+// Save creates or updates a tender and sets its ID.
+func (r *PostgresOrderTenderRepository) Save(ctx context.Context, tender *domain.OrderTender) error {
+	if tender.ID == 0 {
+		query := `
+			INSERT INTO order_payment_tender (
+				order_id, sequence, method, amount, currency_code, status, transaction_id,
+				captured_amount, refunded_amount, failure_reason, authorized_at, captured_at,
+				refunded_at, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			RETURNING id`
+		err := r.db.QueryRow(ctx, query,
+			tender.OrderID, tender.Sequence, tender.Method, tender.Amount, tender.CurrencyCode, string(tender.Status),
+			tender.TransactionID, tender.CapturedAmount, tender.RefundedAmount, tender.FailureReason,
+			tender.AuthorizedAt, tender.CapturedAt, tender.RefundedAt, tender.CreatedAt, tender.UpdatedAt,
+		).Scan(&tender.ID)
+		if err != nil {
+			return errors.InternalWrap(err, "failed to create order tender")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE order_payment_tender
+		SET status = $2, transaction_id = $3, captured_amount = $4, refunded_amount = $5,
+			failure_reason = $6, authorized_at = $7, captured_at = $8, refunded_at = $9, updated_at = $10
+		WHERE id = $1`
+	tag, err := r.db.Pool().Exec(ctx, query,
+		tender.ID, string(tender.Status), tender.TransactionID, tender.CapturedAmount, tender.RefundedAmount,
+		tender.FailureReason, tender.AuthorizedAt, tender.CapturedAt, tender.RefundedAt, tender.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update order tender")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("order tender %d", tender.ID))
+	}
+	return nil
+}
+
+// FindByOrderID returns the tenders of an order in sequence.
+func (r *PostgresOrderTenderRepository) FindByOrderID(ctx context.Context, orderID int64) ([]*domain.OrderTender, error) {
+	query := `
+		SELECT id, order_id, sequence, method, amount, currency_code, status, transaction_id,
+			captured_amount, refunded_amount, failure_reason, authorized_at, captured_at,
+			refunded_at, created_at, updated_at
+		FROM order_payment_tender
+		WHERE order_id = $1
+		ORDER BY sequence`
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list order tenders")
+	}
+	defer rows.Close()
+
+	tenders := make([]*domain.OrderTender, 0)
+	for rows.Next() {
+		tender, err := scanOrderTender(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan order tender")
+		}
+		tenders = append(tenders, tender)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate order tenders")
+	}
+	return tenders, nil
+}
+
+func scanOrderTender(row pgx.Row) (*domain.OrderTender, error) {
+	tender := &domain.OrderTender{}
+	var status string
+	var currencyCode sql.NullString
+	err := row.Scan(
+		&tender.ID, &tender.OrderID, &tender.Sequence, &tender.Method, &tender.Amount, &currencyCode, &status,
+		&tender.TransactionID, &tender.CapturedAmount, &tender.RefundedAmount, &tender.FailureReason,
+		&tender.AuthorizedAt, &tender.CapturedAt, &tender.RefundedAt, &tender.CreatedAt, &tender.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tender.Status = domain.TenderStatus(status)
+	tender.CurrencyCode = currencyCode.String
+	return tender, nil
+}
@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresPaymentLinkRepository implements the PaymentLinkRepository interface
+type PostgresPaymentLinkRepository struct {
+	db *database.DB
+}
+
+// NewPostgresPaymentLinkRepository creates a new PostgresPaymentLinkRepository
+func NewPostgresPaymentLinkRepository(db *database.DB) *PostgresPaymentLinkRepository {
+	return &PostgresPaymentLinkRepository{db: db}
+}
+
+const paymentLinkColumns = `
+	id, token, order_id, reason, amount, currency_code, status, expires_at,
+	created_by, transaction_id, paid_at, created_at, updated_at`
+
+// Create saves a new payment link and sets its ID.
+func (r *PostgresPaymentLinkRepository) Create(ctx context.Context, link *domain.PaymentLink) error {
+	query := `
+		INSERT INTO order_payment_link (
+			token, order_id, reason, amount, currency_code, status, expires_at,
+			created_by, transaction_id, paid_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id`
+	err := r.db.QueryRow(ctx, query,
+		link.Token, link.OrderID, string(link.Reason), link.Amount, link.CurrencyCode, string(link.Status),
+		link.ExpiresAt, link.CreatedBy, link.TransactionID, link.PaidAt, link.CreatedAt, link.UpdatedAt,
+	).Scan(&link.ID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create payment link")
+	}
+	return nil
+}
+
+// Update saves the status and payment of a payment link.
+func (r *PostgresPaymentLinkRepository) Update(ctx context.Context, link *domain.PaymentLink) error {
+	query := `
+		UPDATE order_payment_link
+		SET status = $2, transaction_id = $3, paid_at = $4, updated_at = $5
+		WHERE id = $1`
+	err := r.db.Exec(ctx, query, link.ID, string(link.Status), link.TransactionID, link.PaidAt, link.UpdatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update payment link")
+	}
+	return nil
+}
+
+// Claim moves an active, unexpired link to PROCESSING so that a single payment
+// can be in progress; it returns false when the link was not active.
+func (r *PostgresPaymentLinkRepository) Claim(ctx context.Context, id int64, now time.Time) (bool, error) {
+	query := `
+		UPDATE order_payment_link
+		SET status = 'PROCESSING', updated_at = $2
+		WHERE id = $1 AND status = 'ACTIVE' AND expires_at > $2`
+	tag, err := r.db.Pool().Exec(ctx, query, id, now)
+	if err != nil {
+		return false, errors.InternalWrap(err, "failed to claim payment link")
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Release moves a link claimed by a payment that did not go through back to ACTIVE.
+func (r *PostgresPaymentLinkRepository) Release(ctx context.Context, id int64) error {
+	query := `
+		UPDATE order_payment_link
+		SET status = 'ACTIVE', updated_at = $2
+		WHERE id = $1 AND status = 'PROCESSING'`
+	if err := r.db.Exec(ctx, query, id, time.Now()); err != nil {
+		return errors.InternalWrap(err, "failed to release payment link")
+	}
+	return nil
+}
+
+// FindByID returns a payment link, or nil.
+func (r *PostgresPaymentLinkRepository) FindByID(ctx context.Context, id int64) (*domain.PaymentLink, error) {
+	query := `SELECT` + paymentLinkColumns + ` FROM order_payment_link WHERE id = $1`
+	link, err := scanPaymentLink(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find payment link")
+	}
+	return link, nil
+}
+
+// FindByToken returns the payment link with a token, or nil.
+func (r *PostgresPaymentLinkRepository) FindByToken(ctx context.Context, token string) (*domain.PaymentLink, error) {
+	query := `SELECT` + paymentLinkColumns + ` FROM order_payment_link WHERE token = $1`
+	link, err := scanPaymentLink(r.db.QueryRow(ctx, query, token))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find payment link by token")
+	}
+	return link, nil
+}
+
+// FindByOrderID returns the payment links of an order, newest first.
+func (r *PostgresPaymentLinkRepository) FindByOrderID(ctx context.Context, orderID int64) ([]*domain.PaymentLink, error) {
+	query := `SELECT` + paymentLinkColumns + ` FROM order_payment_link WHERE order_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to list payment links")
+	}
+	defer rows.Close()
+
+	links := make([]*domain.PaymentLink, 0)
+	for rows.Next() {
+		link, err := scanPaymentLink(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan payment link")
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate payment links")
+	}
+	return links, nil
+}
+
+func scanPaymentLink(row pgx.Row) (*domain.PaymentLink, error) {
+	link := &domain.PaymentLink{}
+	var reason, status string
+	var currencyCode, createdBy sql.NullString
+	err := row.Scan(
+		&link.ID, &link.Token, &link.OrderID, &reason, &link.Amount, &currencyCode, &status, &link.ExpiresAt,
+		&createdBy, &link.TransactionID, &link.PaidAt, &link.CreatedAt, &link.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	link.Reason = domain.PaymentLinkReason(reason)
+	link.Status = domain.PaymentLinkStatus(status)
+	link.CurrencyCode = currencyCode.String
+	link.CreatedBy = createdBy.String
+	return link, nil
+}
@@ -0,0 +1,40 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresShippingDestinationRepository implements the ShippingDestinationRepository interface
+type PostgresShippingDestinationRepository struct {
+	db *database.DB
+}
+
+// NewPostgresShippingDestinationRepository creates a new PostgresShippingDestinationRepository
+func NewPostgresShippingDestinationRepository(db *database.DB) *PostgresShippingDestinationRepository {
+	return &PostgresShippingDestinationRepository{db: db}
+}
+
+// FindByAddressID returns the country and region of an address
+func (r *PostgresShippingDestinationRepository) FindByAddressID(ctx context.Context, addressID int64) (*domain.ShippingDestination, error) {
+	query := `
+		SELECT iso_country_alpha2, COALESCE(iso_country_sub, state_prov_region)
+		FROM blc_address
+		WHERE address_id = $1`
+
+	var country, region sql.NullString
+	err := r.db.QueryRow(ctx, query, addressID).Scan(&country, &region)
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("address")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find shipping destination")
+	}
+	return &domain.ShippingDestination{Country: country.String, Region: region.String}, nil
+}
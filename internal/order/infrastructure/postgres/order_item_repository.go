@@ -62,16 +62,16 @@ func (r *OrderItemRepository) Save(ctx context.Context, item *domain.OrderItem)
 				tax_amount, tax_category, shipping_amount, discounts_allowed, has_validation_errors, 
 				item_taxable_flag, order_item_type, retail_price_override, sale_price_override, 
 				category_id, gift_wrap_item_id, parent_order_item_id, personal_message_id, 
-				created_at, updated_at
+				quantity_per_parent, created_at, updated_at
 			) VALUES (
-				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25
 			) RETURNING order_item_id`
 		err = tx.QueryRowContext(ctx, query,
 			item.OrderID, item.SKUID, item.ProductID, name, item.Quantity, item.RetailPrice, item.SalePrice, item.Price, item.TotalPrice,
 			item.TaxAmount, taxCategory, item.ShippingAmount, discountsAllowed, hasValidationErrors,
 			itemTaxableFlag, orderItemType, retailPriceOverride, salePriceOverride,
 			categoryID, giftWrapItemID, parentOrderItemID, personalMessageID,
-			item.CreatedAt, item.UpdatedAt,
+			item.QuantityPerParent, item.CreatedAt, item.UpdatedAt,
 		).Scan(&item.ID)
 		if err != nil {
 			return fmt.Errorf("failed to insert order item: %w", err)
@@ -85,14 +85,14 @@ func (r *OrderItemRepository) Save(ctx context.Context, item *domain.OrderItem)
 				discounts_allowed = $13, has_validation_errors = $14, item_taxable_flag = $15, 
 				order_item_type = $16, retail_price_override = $17, sale_price_override = $18, 
 				category_id = $19, gift_wrap_item_id = $20, parent_order_item_id = $21, 
-				personal_message_id = $22, updated_at = $23
-			WHERE order_item_id = $24`
+				personal_message_id = $22, quantity_per_parent = $23, updated_at = $24
+			WHERE order_item_id = $25`
 		_, err = tx.ExecContext(ctx, query,
 			item.OrderID, item.SKUID, item.ProductID, name, item.Quantity, item.RetailPrice, item.SalePrice, item.Price, item.TotalPrice,
 			item.TaxAmount, taxCategory, item.ShippingAmount, discountsAllowed, hasValidationErrors,
 			itemTaxableFlag, orderItemType, retailPriceOverride, salePriceOverride,
 			categoryID, giftWrapItemID, parentOrderItemID, personalMessageID,
-			item.UpdatedAt, item.ID,
+			item.QuantityPerParent, item.UpdatedAt, item.ID,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to update order item: %w", err)
@@ -110,7 +110,7 @@ func (r *OrderItemRepository) FindByID(ctx context.Context, id int64) (*domain.O
 			tax_amount, tax_category, shipping_amount, discounts_allowed, has_validation_errors, 
 			item_taxable_flag, order_item_type, retail_price_override, sale_price_override, 
 			category_id, gift_wrap_item_id, parent_order_item_id, personal_message_id, 
-			created_at, updated_at
+			quantity_per_parent, created_at, updated_at
 		FROM blc_order_item WHERE order_item_id = $1`
 
 	var item domain.OrderItem
@@ -133,7 +133,7 @@ func (r *OrderItemRepository) FindByID(ctx context.Context, id int64) (*domain.O
 		&item.TaxAmount, &taxCategory, &item.ShippingAmount, &discountsAllowed, &hasValidationErrors,
 		&itemTaxableFlag, &orderItemType, &retailPriceOverride, &salePriceOverride,
 		&categoryID, &giftWrapItemID, &parentOrderItemID, &personalMessageID,
-		&item.CreatedAt, &item.UpdatedAt,
+		&item.QuantityPerParent, &item.CreatedAt, &item.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -190,7 +190,7 @@ func (r *OrderItemRepository) FindByOrderID(ctx context.Context, orderID int64)
 			tax_amount, tax_category, shipping_amount, discounts_allowed, has_validation_errors, 
 			item_taxable_flag, order_item_type, retail_price_override, sale_price_override, 
 			category_id, gift_wrap_item_id, parent_order_item_id, personal_message_id, 
-			created_at, updated_at
+			quantity_per_parent, created_at, updated_at
 		FROM blc_order_item WHERE order_id = $1`
 
 	rows, err := r.db.QueryContext(ctx, query, orderID)
@@ -220,7 +220,7 @@ func (r *OrderItemRepository) FindByOrderID(ctx context.Context, orderID int64)
 			&item.TaxAmount, &taxCategory, &item.ShippingAmount, &discountsAllowed, &hasValidationErrors,
 			&itemTaxableFlag, &orderItemType, &retailPriceOverride, &salePriceOverride,
 			&categoryID, &giftWrapItemID, &parentOrderItemID, &personalMessageID,
-			&item.CreatedAt, &item.UpdatedAt,
+			&item.QuantityPerParent, &item.CreatedAt, &item.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan order item row: %w", err)
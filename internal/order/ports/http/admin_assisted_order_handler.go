@@ -0,0 +1,105 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+	"github.com/qhato/ecommerce/pkg/validator"
+)
+
+// AdminAssistedOrderHandler handles orders agents place on behalf of phone and mail customers
+type AdminAssistedOrderHandler struct {
+	assistedOrderService application.AssistedOrderService
+	overrideRole         string
+	authMiddleware       func(http.Handler) http.Handler
+	validator            *validator.Validator
+	log                  *logger.Logger
+}
+
+// NewAdminAssistedOrderHandler creates a new AdminAssistedOrderHandler. Agents need
+// overrideRole to override prices or bypass the storefront cart policy.
+func NewAdminAssistedOrderHandler(
+	assistedOrderService application.AssistedOrderService,
+	overrideRole string,
+	authMiddleware func(http.Handler) http.Handler,
+	validator *validator.Validator,
+	log *logger.Logger,
+) *AdminAssistedOrderHandler {
+	return &AdminAssistedOrderHandler{
+		assistedOrderService: assistedOrderService,
+		overrideRole:         overrideRole,
+		authMiddleware:       authMiddleware,
+		validator:            validator,
+		log:                  log,
+	}
+}
+
+// RegisterRoutes registers assisted order routes
+func (h *AdminAssistedOrderHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/admin/assisted-orders", h.CreateAssistedOrder)
+		r.Get("/admin/assisted-orders/{orderId}", h.GetAssistedOrder)
+	})
+}
+
+// CreateAssistedOrder creates, pays or sends a pay link for an order placed by phone or mail
+func (h *AdminAssistedOrderHandler) CreateAssistedOrder(w http.ResponseWriter, r *http.Request) {
+	var req application.CreateAssistedOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	agent := application.AssistedOrderAgent{
+		ID:          middleware.GetUserID(r.Context()),
+		CanOverride: h.canOverride(r),
+	}
+	order, err := h.assistedOrderService.CreateOrder(r.Context(), agent, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("agent_id", agent.ID).Error("failed to create assisted order")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, order)
+}
+
+// GetAssistedOrder retrieves how an order was placed by an agent
+func (h *AdminAssistedOrderHandler) GetAssistedOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "orderId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	order, err := h.assistedOrderService.GetAssistedOrder(r.Context(), orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to get assisted order")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, order)
+}
+
+// canOverride checks if the agent holds the order override role
+func (h *AdminAssistedOrderHandler) canOverride(r *http.Request) bool {
+	for _, role := range middleware.GetUserRoles(r.Context()) {
+		if role == h.overrideRole {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/validator"
+)
+
+// AdminChannelOrderHandler shows the orders ingested from external sales channels
+// and manages how channel SKUs map to internal SKUs
+type AdminChannelOrderHandler struct {
+	ingestionService application.ChannelOrderIngestionService
+	authMiddleware   func(http.Handler) http.Handler
+	validator        *validator.Validator
+	log              *logger.Logger
+}
+
+// NewAdminChannelOrderHandler creates a new AdminChannelOrderHandler
+func NewAdminChannelOrderHandler(
+	ingestionService application.ChannelOrderIngestionService,
+	authMiddleware func(http.Handler) http.Handler,
+	validator *validator.Validator,
+	log *logger.Logger,
+) *AdminChannelOrderHandler {
+	return &AdminChannelOrderHandler{
+		ingestionService: ingestionService,
+		authMiddleware:   authMiddleware,
+		validator:        validator,
+		log:              log,
+	}
+}
+
+// RegisterRoutes registers channel order admin routes
+func (h *AdminChannelOrderHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/channel-orders", h.ListChannelOrders)
+		r.Get("/admin/channel-sku-mappings/{channel}", h.ListSKUMappings)
+		r.Put("/admin/channel-sku-mappings/{channel}", h.SaveSKUMapping)
+		r.Delete("/admin/channel-sku-mappings/{channel}/{externalSku}", h.DeleteSKUMapping)
+	})
+}
+
+// ListChannelOrders lists ingested channel orders, optionally filtered by ?channel= and ?status=
+func (h *AdminChannelOrderHandler) ListChannelOrders(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	orders, total, err := h.ingestionService.ListChannelOrders(r.Context(), &domain.ChannelOrderFilter{
+		Channel:  r.URL.Query().Get("channel"),
+		Status:   domain.ChannelOrderStatus(r.URL.Query().Get("status")),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, application.NewPaginatedResponse(orders, page, pageSize, total))
+}
+
+// ListSKUMappings lists the SKU mappings of a channel
+func (h *AdminChannelOrderHandler) ListSKUMappings(w http.ResponseWriter, r *http.Request) {
+	mappings, err := h.ingestionService.ListSKUMappings(r.Context(), chi.URLParam(r, "channel"))
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, mappings)
+}
+
+// SaveSKUMapping maps a channel SKU to an internal SKU, replacing its current mapping
+func (h *AdminChannelOrderHandler) SaveSKUMapping(w http.ResponseWriter, r *http.Request) {
+	var req application.SaveChannelSKUMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	channel := chi.URLParam(r, "channel")
+	mapping, err := h.ingestionService.SaveSKUMapping(r.Context(), channel, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("channel", channel).Error("failed to save channel SKU mapping")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, mapping)
+}
+
+// DeleteSKUMapping removes the mapping of a channel SKU
+func (h *AdminChannelOrderHandler) DeleteSKUMapping(w http.ResponseWriter, r *http.Request) {
+	// Channel SKUs may contain reserved characters and arrive escaped
+	externalSKU, err := url.PathUnescape(chi.URLParam(r, "externalSku"))
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid external SKU"))
+		return
+	}
+
+	if err := h.ingestionService.DeleteSKUMapping(r.Context(), chi.URLParam(r, "channel"), externalSKU); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
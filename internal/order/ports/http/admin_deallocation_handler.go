@@ -0,0 +1,108 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminDeallocationHandler handles reconciliation of inventory that cancelled orders failed to release
+type AdminDeallocationHandler struct {
+	deallocationService application.InventoryDeallocationService
+	authMiddleware      func(http.Handler) http.Handler
+	log                 *logger.Logger
+}
+
+// NewAdminDeallocationHandler creates a new AdminDeallocationHandler
+func NewAdminDeallocationHandler(
+	deallocationService application.InventoryDeallocationService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminDeallocationHandler {
+	return &AdminDeallocationHandler{
+		deallocationService: deallocationService,
+		authMiddleware:      authMiddleware,
+		log:                 log,
+	}
+}
+
+// RegisterRoutes registers inventory deallocation routes
+func (h *AdminDeallocationHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/inventory-deallocations/reconciliation", h.GetReconciliationReport)
+		r.Post("/admin/inventory-deallocations/{id}/retry", h.RetryDeallocation)
+		r.Post("/admin/inventory-deallocations/{id}/resolve", h.ResolveDeallocation)
+	})
+}
+
+// GetReconciliationReport lists deallocations that failed and still hold stock
+func (h *AdminDeallocationHandler) GetReconciliationReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.deallocationService.GetReconciliationReport(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to build deallocation reconciliation report")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, report)
+}
+
+// RetryDeallocation requeues a dead-lettered deallocation and attempts it immediately
+func (h *AdminDeallocationHandler) RetryDeallocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid deallocation ID").WithInternal(err))
+		return
+	}
+
+	deallocation, err := h.deallocationService.Retry(r.Context(), id)
+	if err != nil {
+		h.log.WithError(err).WithField("deallocation_id", id).Error("failed to retry inventory deallocation")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, deallocation)
+}
+
+// ResolveDeallocation marks a failed deallocation as reconciled by hand
+func (h *AdminDeallocationHandler) ResolveDeallocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid deallocation ID").WithInternal(err))
+		return
+	}
+
+	var req application.ResolveDeallocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	// The authenticated admin resolves the deallocation when one is known
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		req.ResolvedBy = email
+	}
+	if req.ResolvedBy == "" {
+		httpPkg.RespondError(w, errors.ValidationError("resolved_by is required"))
+		return
+	}
+
+	deallocation, err := h.deallocationService.Resolve(r.Context(), id, req.ResolvedBy)
+	if err != nil {
+		h.log.WithError(err).WithField("deallocation_id", id).Error("failed to resolve inventory deallocation")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, deallocation)
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminGiftOptionHandler handles gift wrap options and packing slips
+type AdminGiftOptionHandler struct {
+	giftOptionService application.GiftOptionService
+	authMiddleware    func(http.Handler) http.Handler
+	log               *logger.Logger
+}
+
+// NewAdminGiftOptionHandler creates a new AdminGiftOptionHandler
+func NewAdminGiftOptionHandler(
+	giftOptionService application.GiftOptionService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminGiftOptionHandler {
+	return &AdminGiftOptionHandler{
+		giftOptionService: giftOptionService,
+		authMiddleware:    authMiddleware,
+		log:               log,
+	}
+}
+
+// RegisterRoutes registers gift option routes
+func (h *AdminGiftOptionHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/gift-wrap-options", h.ListGiftWrapOptions)
+		r.Post("/admin/gift-wrap-options", h.CreateGiftWrapOption)
+		r.Put("/admin/gift-wrap-options/{id}", h.UpdateGiftWrapOption)
+		r.Get("/admin/orders/{id}/packing-slip", h.GetPackingSlip)
+	})
+}
+
+// ListGiftWrapOptions lists all gift wrap options, including inactive ones
+func (h *AdminGiftOptionHandler) ListGiftWrapOptions(w http.ResponseWriter, r *http.Request) {
+	options, err := h.giftOptionService.ListGiftWrapOptions(r.Context(), false)
+	if err != nil {
+		h.log.WithError(err).Error("failed to list gift wrap options")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, options)
+}
+
+// CreateGiftWrapOption offers a SKU as a gift wrap
+func (h *AdminGiftOptionHandler) CreateGiftWrapOption(w http.ResponseWriter, r *http.Request) {
+	var req application.SaveGiftWrapOptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	option, err := h.giftOptionService.CreateGiftWrapOption(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).WithField("sku_id", req.SKUID).Error("failed to create gift wrap option")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, option)
+}
+
+// UpdateGiftWrapOption updates a gift wrap option
+func (h *AdminGiftOptionHandler) UpdateGiftWrapOption(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid gift wrap option ID").WithInternal(err))
+		return
+	}
+
+	var req application.SaveGiftWrapOptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	option, err := h.giftOptionService.UpdateGiftWrapOption(r.Context(), id, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("gift_wrap_option_id", id).Error("failed to update gift wrap option")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, option)
+}
+
+// GetPackingSlip returns the packing slip of an order, with gift wraps and messages
+func (h *AdminGiftOptionHandler) GetPackingSlip(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	slip, err := h.giftOptionService.GetPackingSlip(r.Context(), id)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", id).Error("failed to build packing slip")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, slip)
+}
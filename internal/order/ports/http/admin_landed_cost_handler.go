@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminLandedCostHandler handles the duties and import fees estimated for cross-border orders
+type AdminLandedCostHandler struct {
+	landedCostService application.LandedCostService
+	authMiddleware    func(http.Handler) http.Handler
+	log               *logger.Logger
+}
+
+// NewAdminLandedCostHandler creates a new AdminLandedCostHandler
+func NewAdminLandedCostHandler(
+	landedCostService application.LandedCostService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminLandedCostHandler {
+	return &AdminLandedCostHandler{
+		landedCostService: landedCostService,
+		authMiddleware:    authMiddleware,
+		log:               log,
+	}
+}
+
+// RegisterRoutes registers landed cost routes
+func (h *AdminLandedCostHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/orders/{id}/landed-cost", h.GetOrderLandedCost)
+	})
+}
+
+// GetOrderLandedCost returns the duties and import fees estimated for an order and its incoterm
+func (h *AdminLandedCostHandler) GetOrderLandedCost(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	cost, err := h.landedCostService.GetOrderLandedCost(r.Context(), id)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", id).Error("failed to get order landed cost")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, cost)
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+	"github.com/qhato/ecommerce/pkg/validator"
+)
+
+// AdminOfflinePaymentHandler handles offline payment methods and the receipt of offline payments
+type AdminOfflinePaymentHandler struct {
+	offlinePaymentService application.OfflinePaymentService
+	authMiddleware        func(http.Handler) http.Handler
+	validator             *validator.Validator
+	log                   *logger.Logger
+}
+
+// NewAdminOfflinePaymentHandler creates a new AdminOfflinePaymentHandler
+func NewAdminOfflinePaymentHandler(
+	offlinePaymentService application.OfflinePaymentService,
+	authMiddleware func(http.Handler) http.Handler,
+	validator *validator.Validator,
+	log *logger.Logger,
+) *AdminOfflinePaymentHandler {
+	return &AdminOfflinePaymentHandler{
+		offlinePaymentService: offlinePaymentService,
+		authMiddleware:        authMiddleware,
+		validator:             validator,
+		log:                   log,
+	}
+}
+
+// RegisterRoutes registers offline payment routes
+func (h *AdminOfflinePaymentHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/offline-payment-methods", func(r chi.Router) {
+			r.Get("/", h.ListMethods)
+			r.Post("/", h.CreateMethod)
+			r.Put("/{methodId}", h.UpdateMethod)
+			r.Delete("/{methodId}", h.DeleteMethod)
+		})
+		r.Get("/admin/offline-payments", h.ListAwaitingPayments)
+		r.Get("/admin/orders/{id}/offline-payment", h.GetPayment)
+		r.Post("/admin/orders/{id}/offline-payment/receipt", h.RecordPayment)
+	})
+}
+
+// ListMethods lists the offline payment methods
+func (h *AdminOfflinePaymentHandler) ListMethods(w http.ResponseWriter, r *http.Request) {
+	methods, err := h.offlinePaymentService.ListMethods(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list offline payment methods")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, methods)
+}
+
+// CreateMethod adds an offline payment method
+func (h *AdminOfflinePaymentHandler) CreateMethod(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeMethodRequest(w, r)
+	if !ok {
+		return
+	}
+
+	method, err := h.offlinePaymentService.CreateMethod(r.Context(), req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to create offline payment method")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, method)
+}
+
+// UpdateMethod updates an offline payment method
+func (h *AdminOfflinePaymentHandler) UpdateMethod(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "methodId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid offline payment method ID").WithInternal(err))
+		return
+	}
+	req, ok := h.decodeMethodRequest(w, r)
+	if !ok {
+		return
+	}
+
+	method, err := h.offlinePaymentService.UpdateMethod(r.Context(), id, req)
+	if err != nil {
+		h.log.WithError(err).WithField("method_id", id).Error("failed to update offline payment method")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, method)
+}
+
+// DeleteMethod removes an offline payment method
+func (h *AdminOfflinePaymentHandler) DeleteMethod(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "methodId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid offline payment method ID").WithInternal(err))
+		return
+	}
+
+	if err := h.offlinePaymentService.DeleteMethod(r.Context(), id); err != nil {
+		h.log.WithError(err).WithField("method_id", id).Error("failed to delete offline payment method")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAwaitingPayments lists the orders awaiting an offline payment, oldest first
+func (h *AdminOfflinePaymentHandler) ListAwaitingPayments(w http.ResponseWriter, r *http.Request) {
+	payments, err := h.offlinePaymentService.ListAwaitingPayments(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list awaiting offline payments")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payments)
+}
+
+// GetPayment retrieves the offline payment of an order
+func (h *AdminOfflinePaymentHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	payment, err := h.offlinePaymentService.GetPayment(r.Context(), orderID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
+
+// RecordPayment records receipt of an order's offline payment, submitting the order
+func (h *AdminOfflinePaymentHandler) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	var req application.RecordOfflinePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	payment, err := h.offlinePaymentService.RecordPayment(r.Context(), orderID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to record offline payment")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
+
+func (h *AdminOfflinePaymentHandler) decodeMethodRequest(w http.ResponseWriter, r *http.Request) (*application.SaveOfflinePaymentMethodRequest, bool) {
+	var req application.SaveOfflinePaymentMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return nil, false
+	}
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, err)
+		return nil, false
+	}
+	return &req, true
+}
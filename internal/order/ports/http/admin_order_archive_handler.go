@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminOrderArchiveHandler handles the retention windows of completed orders,
+// their archival and the restoration of archived orders
+type AdminOrderArchiveHandler struct {
+	archiveService application.OrderArchiveService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminOrderArchiveHandler creates a new AdminOrderArchiveHandler
+func NewAdminOrderArchiveHandler(
+	archiveService application.OrderArchiveService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOrderArchiveHandler {
+	return &AdminOrderArchiveHandler{
+		archiveService: archiveService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers order archive routes
+func (h *AdminOrderArchiveHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/order-retention", h.ListPolicies)
+		r.Put("/admin/order-retention/{status}", h.SetPolicy)
+		r.Post("/admin/orders/archive", h.ArchiveExpired)
+		r.Post("/admin/orders/{id}/restore", h.RestoreOrder)
+	})
+}
+
+// ListPolicies lists the retention window of every archivable status
+func (h *AdminOrderArchiveHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.archiveService.ListPolicies(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list order retention policies")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, policies)
+}
+
+// SetPolicy sets the retention window of a status
+func (h *AdminOrderArchiveHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	var req application.SetOrderRetentionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	policy, err := h.archiveService.SetPolicy(r.Context(), chi.URLParam(r, "status"), &req, middleware.GetUserEmail(r.Context()))
+	if err != nil {
+		h.log.WithError(err).Error("failed to set order retention policy")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, policy)
+}
+
+// ArchiveExpired archives the orders past retention now instead of waiting for the scheduled run
+func (h *AdminOrderArchiveHandler) ArchiveExpired(w http.ResponseWriter, r *http.Request) {
+	archived, err := h.archiveService.ArchiveExpired(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to archive orders")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, &application.OrderArchiveRunDTO{Archived: archived})
+}
+
+// RestoreOrder moves an archived order back to the live tables
+func (h *AdminOrderArchiveHandler) RestoreOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	order, err := h.archiveService.RestoreOrder(r.Context(), orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to restore archived order")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, order)
+}
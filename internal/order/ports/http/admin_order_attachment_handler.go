@@ -0,0 +1,219 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// attachmentFormOverhead is allowed on top of the largest attachment for the rest of a multipart form
+const attachmentFormOverhead = 1 << 20
+
+// AdminOrderAttachmentHandler handles files attached to orders by staff
+type AdminOrderAttachmentHandler struct {
+	attachmentService application.OrderAttachmentService
+	maxUploadBytes    int64
+	authMiddleware    func(http.Handler) http.Handler
+	readMiddleware    func(http.Handler) http.Handler
+	writeMiddleware   func(http.Handler) http.Handler
+	log               *logger.Logger
+}
+
+// NewAdminOrderAttachmentHandler creates a new AdminOrderAttachmentHandler.
+// readMiddleware guards listing and downloading attachments, writeMiddleware
+// uploading, changing and deleting them; maxUploadBytes bounds uploaded files.
+func NewAdminOrderAttachmentHandler(
+	attachmentService application.OrderAttachmentService,
+	maxUploadBytes int64,
+	authMiddleware func(http.Handler) http.Handler,
+	readMiddleware func(http.Handler) http.Handler,
+	writeMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOrderAttachmentHandler {
+	return &AdminOrderAttachmentHandler{
+		attachmentService: attachmentService,
+		maxUploadBytes:    maxUploadBytes,
+		authMiddleware:    authMiddleware,
+		readMiddleware:    readMiddleware,
+		writeMiddleware:   writeMiddleware,
+		log:               log,
+	}
+}
+
+// RegisterRoutes registers order attachment routes
+func (h *AdminOrderAttachmentHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Group(func(r chi.Router) {
+			r.Use(h.readMiddleware)
+			r.Get("/admin/orders/{id}/attachments", h.ListAttachments)
+			r.Get("/admin/orders/{id}/attachments/{attachmentId}/download", h.DownloadAttachment)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(h.writeMiddleware)
+			r.Post("/admin/orders/{id}/attachments", h.UploadAttachment)
+			r.Patch("/admin/orders/{id}/attachments/{attachmentId}", h.UpdateAttachment)
+			r.Delete("/admin/orders/{id}/attachments/{attachmentId}", h.DeleteAttachment)
+		})
+	})
+}
+
+// ListAttachments lists all attachments of an order, internal and customer-visible
+func (h *AdminOrderAttachmentHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	attachments, err := h.attachmentService.List(r.Context(), orderID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, attachments)
+}
+
+// UploadAttachment attaches the "file" field of a multipart form to an order. Form
+// fields: kind (PURCHASE_ORDER, CUSTOMS, DAMAGE_PHOTO or OTHER), visibility
+// (INTERNAL or CUSTOMER, INTERNAL by default) and description.
+func (h *AdminOrderAttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+	req, err := readAttachmentUpload(w, r, h.maxUploadBytes)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	req.Visibility = r.FormValue("visibility")
+
+	attachment, err := h.attachmentService.Upload(r.Context(), orderID, req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to upload order attachment")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, attachment)
+}
+
+// DownloadAttachment serves the content of an attachment
+func (h *AdminOrderAttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	orderID, attachmentID, err := parseAttachmentPath(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	content, err := h.attachmentService.Download(r.Context(), orderID, attachmentID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).WithField("attachment_id", attachmentID).Error("failed to download order attachment")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	RespondAttachment(w, content)
+}
+
+// UpdateAttachment changes who can see an attachment
+func (h *AdminOrderAttachmentHandler) UpdateAttachment(w http.ResponseWriter, r *http.Request) {
+	orderID, attachmentID, err := parseAttachmentPath(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	var req application.UpdateOrderAttachmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	attachment, err := h.attachmentService.SetVisibility(r.Context(), orderID, attachmentID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).WithField("attachment_id", attachmentID).Error("failed to update order attachment")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, attachment)
+}
+
+// DeleteAttachment removes an attachment and its content
+func (h *AdminOrderAttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	orderID, attachmentID, err := parseAttachmentPath(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	if err := h.attachmentService.Delete(r.Context(), orderID, attachmentID); err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).WithField("attachment_id", attachmentID).Error("failed to delete order attachment")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RespondAttachment writes the content of an order attachment as a download. The
+// content is never rendered inline, so uploaded HTML or SVG cannot run in the site.
+func RespondAttachment(w http.ResponseWriter, content *application.OrderAttachmentContentDTO) {
+	w.Header().Set("Content-Type", content.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", content.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content.Content)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content.Content)
+}
+
+// readAttachmentUpload reads the file and the kind and description fields of an attachment upload
+func readAttachmentUpload(w http.ResponseWriter, r *http.Request, maxUploadBytes int64) (*application.UploadOrderAttachmentRequest, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes+attachmentFormOverhead)
+
+	upload, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, errors.BadRequest("attachment upload requires a file field").WithInternal(err)
+	}
+	defer upload.Close()
+
+	content, err := io.ReadAll(io.LimitReader(upload, maxUploadBytes+1))
+	if err != nil {
+		return nil, errors.BadRequest("failed to read attachment").WithInternal(err)
+	}
+	if int64(len(content)) > maxUploadBytes {
+		return nil, errors.ValidationError(fmt.Sprintf("attachment exceeds %d bytes", maxUploadBytes))
+	}
+
+	return &application.UploadOrderAttachmentRequest{
+		Kind:        r.FormValue("kind"),
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Description: r.FormValue("description"),
+		Content:     content,
+	}, nil
+}
+
+// parseAttachmentPath reads the order and attachment IDs of the path
+func parseAttachmentPath(r *http.Request) (orderID, attachmentID int64, err error) {
+	if orderID, err = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64); err != nil {
+		return 0, 0, errors.BadRequest("invalid order ID").WithInternal(err)
+	}
+	if attachmentID, err = strconv.ParseInt(chi.URLParam(r, "attachmentId"), 10, 64); err != nil {
+		return 0, 0, errors.BadRequest("invalid attachment ID").WithInternal(err)
+	}
+	return orderID, attachmentID, nil
+}
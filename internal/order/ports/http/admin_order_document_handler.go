@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminOrderDocumentHandler serves the PDF invoices, quotes and packing slips of orders
+type AdminOrderDocumentHandler struct {
+	documentService application.OrderDocumentService
+	authMiddleware  func(http.Handler) http.Handler
+	log             *logger.Logger
+}
+
+// NewAdminOrderDocumentHandler creates a new AdminOrderDocumentHandler
+func NewAdminOrderDocumentHandler(
+	documentService application.OrderDocumentService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOrderDocumentHandler {
+	return &AdminOrderDocumentHandler{
+		documentService: documentService,
+		authMiddleware:  authMiddleware,
+		log:             log,
+	}
+}
+
+// RegisterRoutes registers order document routes
+func (h *AdminOrderDocumentHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/orders/{id}/invoice.pdf", h.GetInvoice)
+		r.Get("/admin/orders/{id}/quote.pdf", h.GetQuote)
+		r.Get("/admin/orders/{id}/packing-slip.pdf", h.GetPackingSlip)
+	})
+}
+
+// GetInvoice renders the invoice of a submitted order
+func (h *AdminOrderDocumentHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "invoice", h.documentService.RenderInvoice)
+}
+
+// GetQuote renders a quote of a cart or unsubmitted order
+func (h *AdminOrderDocumentHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "quote", h.documentService.RenderQuote)
+}
+
+// GetPackingSlip renders the packing slip of an order
+func (h *AdminOrderDocumentHandler) GetPackingSlip(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "packing slip", func(ctx context.Context, orderID int64) (*application.OrderDocumentDTO, error) {
+		return h.documentService.RenderPackingSlip(ctx, orderID, nil)
+	})
+}
+
+func (h *AdminOrderDocumentHandler) serve(
+	w http.ResponseWriter,
+	r *http.Request,
+	kind string,
+	render func(ctx context.Context, orderID int64) (*application.OrderDocumentDTO, error),
+) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	document, err := render(r.Context(), id)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", id).Errorf("failed to render %s", kind)
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	RespondDocument(w, document)
+}
+
+// RespondDocument writes a rendered order document as a download
+func RespondDocument(w http.ResponseWriter, document *application.OrderDocumentDTO) {
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(document.Content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(document.Content)
+}
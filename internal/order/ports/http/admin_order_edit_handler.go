@@ -0,0 +1,213 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminOrderEditHandler handles staff edits of submitted orders that have not shipped
+type AdminOrderEditHandler struct {
+	editService    application.OrderEditService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminOrderEditHandler creates a new AdminOrderEditHandler
+func NewAdminOrderEditHandler(
+	editService application.OrderEditService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOrderEditHandler {
+	return &AdminOrderEditHandler{
+		editService:    editService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers order edit routes
+func (h *AdminOrderEditHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/admin/orders/{id}/edits", h.OpenEdit)
+		r.Get("/admin/order-edits/{editId}", h.GetEdit)
+		r.Post("/admin/order-edits/{editId}/lines", h.AddLine)
+		r.Put("/admin/order-edits/{editId}/lines/{lineId}", h.UpdateLine)
+		r.Post("/admin/order-edits/{editId}/preview", h.PreviewEdit)
+		r.Post("/admin/order-edits/{editId}/commit", h.CommitEdit)
+		r.Post("/admin/order-edits/{editId}/cancel", h.CancelEdit)
+	})
+}
+
+// OpenEdit opens an edit of an order, or returns the one already open
+func (h *AdminOrderEditHandler) OpenEdit(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	edit, err := h.editService.OpenEdit(r.Context(), adminOrderEditor(r), orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to open order edit")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, edit)
+}
+
+// GetEdit returns an order edit with its lines and last previewed summary
+func (h *AdminOrderEditHandler) GetEdit(w http.ResponseWriter, r *http.Request) {
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	edit, err := h.editService.GetEdit(r.Context(), adminOrderEditor(r), editID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// AddLine stages a SKU to add to the order, e.g. {"sku_id": 12, "quantity": 1}
+func (h *AdminOrderEditHandler) AddLine(w http.ResponseWriter, r *http.Request) {
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.AddOrderEditLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	edit, err := h.editService.AddLine(r.Context(), adminOrderEditor(r), editID, &req)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// UpdateLine changes the quantity of a line, e.g. {"quantity": 0} to remove it
+func (h *AdminOrderEditHandler) UpdateLine(w http.ResponseWriter, r *http.Request) {
+	editID, lineID, ok := parseOrderEditLineID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.UpdateOrderEditLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	edit, err := h.editService.UpdateLine(r.Context(), adminOrderEditor(r), editID, lineID, &req)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// PreviewEdit prices the added lines and returns the delta to confirm
+func (h *AdminOrderEditHandler) PreviewEdit(w http.ResponseWriter, r *http.Request) {
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	edit, err := h.editService.PreviewEdit(r.Context(), adminOrderEditor(r), editID)
+	if err != nil {
+		h.log.WithError(err).WithField("edit_id", editID).Error("failed to preview order edit")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// CommitEdit applies the previewed delta, e.g. {"revision": 3, "confirm": true}
+func (h *AdminOrderEditHandler) CommitEdit(w http.ResponseWriter, r *http.Request) {
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.CommitOrderEditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	edit, err := h.editService.CommitEdit(r.Context(), adminOrderEditor(r), editID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("edit_id", editID).Error("failed to commit order edit")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// CancelEdit closes an edit without changing the order
+func (h *AdminOrderEditHandler) CancelEdit(w http.ResponseWriter, r *http.Request) {
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	edit, err := h.editService.CancelEdit(r.Context(), adminOrderEditor(r), editID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// adminOrderEditor is the authenticated admin, who may edit any order
+func adminOrderEditor(r *http.Request) application.OrderEditor {
+	name := middleware.GetUserEmail(r.Context())
+	if name == "" {
+		name = "admin"
+	}
+	return application.OrderEditor{Name: name}
+}
+
+func parseOrderEditID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	editID, err := strconv.ParseInt(chi.URLParam(r, "editId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order edit ID").WithInternal(err))
+		return 0, false
+	}
+	return editID, true
+}
+
+func parseOrderEditLineID(w http.ResponseWriter, r *http.Request) (int64, int, bool) {
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	lineID, err := strconv.Atoi(chi.URLParam(r, "lineId"))
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order edit line ID").WithInternal(err))
+		return 0, 0, false
+	}
+	return editID, lineID, true
+}
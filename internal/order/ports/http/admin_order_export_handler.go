@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/pkg/export"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminOrderExportHandler handles order list exports
+type AdminOrderExportHandler struct {
+	exportRepo     domain.OrderExportRepository
+	jobs           *export.JobManager
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminOrderExportHandler creates a new AdminOrderExportHandler
+func NewAdminOrderExportHandler(
+	exportRepo domain.OrderExportRepository,
+	jobs *export.JobManager,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOrderExportHandler {
+	return &AdminOrderExportHandler{
+		exportRepo:     exportRepo,
+		jobs:           jobs,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers order export routes; exports require the admin role
+func (h *AdminOrderExportHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Use(middleware.RequireRole("admin"))
+		r.Get("/admin/exports/orders", h.ExportOrders)
+	})
+}
+
+// ExportOrders streams the filtered order list as CSV, or queues it with ?async=true.
+// Filters: status, customer_id, created_from, created_to
+func (h *AdminOrderExportHandler) ExportOrders(w http.ResponseWriter, r *http.Request) {
+	from, to, err := export.ParseCreatedRange(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	filter := &domain.OrderExportFilter{CreatedFrom: from, CreatedTo: to}
+	if status := r.URL.Query().Get("status"); status != "" {
+		orderStatus := domain.OrderStatus(status)
+		filter.Status = &orderStatus
+	}
+	if customerID := r.URL.Query().Get("customer_id"); customerID != "" {
+		id, err := strconv.ParseInt(customerID, 10, 64)
+		if err != nil {
+			httpPkg.RespondError(w, httpPkg.NewValidationError("invalid customer_id"))
+			return
+		}
+		filter.CustomerID = &id
+	}
+
+	export.Serve(w, r, h.jobs, application.NewOrderExport(h.exportRepo, filter), h.log)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/qhato/ecommerce/internal/order/domain"
 	httpPkg "github.com/qhato/ecommerce/pkg/http"
 	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
 	"github.com/qhato/ecommerce/pkg/validator"
 	"github.com/qhato/ecommerce/pkg/errors" // Import pkg/errors
 )
@@ -20,6 +21,7 @@ import (
 type AdminOrderHandler struct {
 	commandHandler *commands.OrderCommandHandler
 	queryHandler   *queries.OrderQueryHandler
+	noteService    application.OrderNoteService
 	validator      *validator.Validator
 	log            *logger.Logger
 }
@@ -28,12 +30,14 @@ type AdminOrderHandler struct {
 func NewAdminOrderHandler(
 	commandHandler *commands.OrderCommandHandler,
 	queryHandler *queries.OrderQueryHandler,
+	noteService application.OrderNoteService,
 	validator *validator.Validator,
 	log *logger.Logger,
 ) *AdminOrderHandler {
 	return &AdminOrderHandler{
 		commandHandler: commandHandler,
 		queryHandler:   queryHandler,
+		noteService:    noteService,
 		validator:      validator,
 		log:            log,
 	}
@@ -49,6 +53,9 @@ func (h *AdminOrderHandler) RegisterRoutes(r chi.Router) {
 		r.Post("/{id}/submit", h.SubmitOrder)
 		r.Post("/{id}/cancel", h.CancelOrder)
 		r.Post("/{id}/items", h.AddOrderItem)
+		r.Get("/{id}/notes", h.ListOrderNotes)
+		r.Post("/{id}/notes", h.AddOrderNote)
+		r.Get("/{id}/timeline", h.GetOrderTimeline)
 		r.Get("/number/{orderNumber}", h.GetOrderByNumber)
 	})
 }
@@ -71,6 +78,7 @@ func (h *AdminOrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request)
 		EmailAddress: req.EmailAddress,
 		Name: req.Name,
 		CurrencyCode: req.CurrencyCode,
+		Channel: domain.OrderChannel(req.Channel),
 		// Other fields as needed
 	}
 
@@ -228,7 +236,9 @@ func (h *AdminOrderHandler) SubmitOrder(w http.ResponseWriter, r *http.Request)
 
 	err = h.commandHandler.HandleSubmitOrder(r.Context(), id)
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if errors.IsCartPolicyViolation(err) {
+			errors.HandleHTTPError(w, err)
+		} else if errors.IsNotFound(err) {
 			httpPkg.RespondError(w, errors.NotFound(err.Error()))
 		} else {
 			httpPkg.RespondError(w, errors.Internal("failed to submit order").WithInternal(err))
@@ -293,6 +303,10 @@ func (h *AdminOrderHandler) AddOrderItem(w http.ResponseWriter, r *http.Request)
 		&req, // Pass the command struct
 	)
 	if err != nil {
+		if errors.IsCartPolicyViolation(err) {
+			errors.HandleHTTPError(w, err)
+			return
+		}
 		httpPkg.RespondError(w, errors.Internal("failed to add item to order").WithInternal(err))
 		return
 	}
@@ -302,3 +316,78 @@ func (h *AdminOrderHandler) AddOrderItem(w http.ResponseWriter, r *http.Request)
 
 	httpPkg.RespondJSON(w, http.StatusOK, item)
 }
+
+// AddOrderNote adds an internal or customer-visible note to an order
+func (h *AdminOrderHandler) AddOrderNote(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	var req application.AddOrderNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, errors.ValidationError("validation failed").WithInternal(err))
+		return
+	}
+
+	// The authenticated admin is the author when one is known
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		req.Author = email
+	}
+
+	note, err := h.noteService.AddNote(r.Context(), id, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", id).Error("failed to add order note")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, note)
+}
+
+// ListOrderNotes lists all notes of an order, internal and customer-visible
+func (h *AdminOrderHandler) ListOrderNotes(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	notes, err := h.noteService.ListNotes(r.Context(), id, false)
+	if err != nil {
+		httpPkg.RespondError(w, errors.Internal("failed to list order notes").WithInternal(err))
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, notes)
+}
+
+// GetOrderTimeline retrieves the full timeline of an order, including internal notes
+func (h *AdminOrderHandler) GetOrderTimeline(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	timeline, err := h.noteService.GetTimeline(r.Context(), id, false)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			httpPkg.RespondError(w, errors.NotFound("order"))
+		} else {
+			httpPkg.RespondError(w, errors.Internal("failed to get order timeline").WithInternal(err))
+		}
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, timeline)
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminOrderHoldHandler handles the queue of orders held for fraud, payment or address review
+type AdminOrderHoldHandler struct {
+	holdService    application.OrderHoldService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminOrderHoldHandler creates a new AdminOrderHoldHandler
+func NewAdminOrderHoldHandler(
+	holdService application.OrderHoldService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOrderHoldHandler {
+	return &AdminOrderHoldHandler{
+		holdService:    holdService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers order hold routes
+func (h *AdminOrderHoldHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/order-holds", h.ListHolds)
+		r.Get("/admin/orders/{id}/holds", h.GetOrderHolds)
+		r.Post("/admin/orders/{id}/hold", h.PlaceHold)
+		r.Post("/admin/orders/{id}/hold/release", h.ReleaseHold)
+		r.Post("/admin/orders/{id}/hold/cancel", h.CancelHeldOrder)
+	})
+}
+
+// ListHolds lists the orders on hold, oldest first, optionally by ?reason=
+func (h *AdminOrderHoldHandler) ListHolds(w http.ResponseWriter, r *http.Request) {
+	queue, err := h.holdService.ListHolds(r.Context(), r.URL.Query().Get("reason"))
+	if err != nil {
+		h.log.WithError(err).Error("failed to list order holds")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, queue)
+}
+
+// GetOrderHolds lists every hold of an order, newest first
+func (h *AdminOrderHoldHandler) GetOrderHolds(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := parseHoldOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	holds, err := h.holdService.GetOrderHolds(r.Context(), orderID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, holds)
+}
+
+// PlaceHold puts an order on hold
+func (h *AdminOrderHoldHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := parseHoldOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.PlaceOrderHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	// The authenticated admin places the hold when one is known
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		req.PlacedBy = email
+	}
+
+	hold, err := h.holdService.PlaceHold(r.Context(), orderID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to put order on hold")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, hold)
+}
+
+// ReleaseHold releases an order from hold
+func (h *AdminOrderHoldHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	orderID, req, ok := decodeResolveHold(w, r)
+	if !ok {
+		return
+	}
+
+	hold, err := h.holdService.ReleaseHold(r.Context(), orderID, req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to release order from hold")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, hold)
+}
+
+// CancelHeldOrder cancels an order on hold
+func (h *AdminOrderHoldHandler) CancelHeldOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, req, ok := decodeResolveHold(w, r)
+	if !ok {
+		return
+	}
+
+	hold, err := h.holdService.CancelHeldOrder(r.Context(), orderID, req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to cancel held order")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, hold)
+}
+
+// decodeResolveHold reads the order ID and the optional release or cancellation body
+func decodeResolveHold(w http.ResponseWriter, r *http.Request) (int64, *application.ResolveOrderHoldRequest, bool) {
+	orderID, ok := parseHoldOrderID(w, r)
+	if !ok {
+		return 0, nil, false
+	}
+
+	var req application.ResolveOrderHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return 0, nil, false
+	}
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		req.ResolvedBy = email
+	}
+	return orderID, &req, true
+}
+
+func parseHoldOrderID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return 0, false
+	}
+	return orderID, true
+}
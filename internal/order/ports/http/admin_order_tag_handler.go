@@ -0,0 +1,263 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// AdminOrderTagHandler handles order tags, tag rules and the ops work queues built on them
+type AdminOrderTagHandler struct {
+	tagService     application.OrderTagService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminOrderTagHandler creates a new AdminOrderTagHandler
+func NewAdminOrderTagHandler(
+	tagService application.OrderTagService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminOrderTagHandler {
+	return &AdminOrderTagHandler{
+		tagService:     tagService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers order tag routes
+func (h *AdminOrderTagHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/order-tags", h.ListTags)
+		r.Get("/admin/order-queue", h.ListQueue)
+		r.Get("/admin/orders/{id}/tags", h.GetOrderTags)
+		r.Post("/admin/orders/{id}/tags", h.AddTags)
+		r.Delete("/admin/orders/{id}/tags/{tag}", h.RemoveTag)
+		r.Post("/admin/orders/{id}/tags/apply-rules", h.ApplyRules)
+		r.Get("/admin/order-tag-rules", h.ListRules)
+		r.Post("/admin/order-tag-rules", h.CreateRule)
+		r.Put("/admin/order-tag-rules/{ruleId}", h.UpdateRule)
+		r.Delete("/admin/order-tag-rules/{ruleId}", h.DeleteRule)
+	})
+}
+
+// ListTags lists the tags in use with their order counts by status
+func (h *AdminOrderTagHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.tagService.ListTags(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list order tags")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, tags)
+}
+
+// ListQueue lists the orders of a work queue. Filters: all_tags, any_tags and exclude_tags
+// (comma-separated), status (comma-separated), sort=newest, page, page_size. Queues can
+// be kept as saved views of the order_queue list.
+func (h *AdminOrderTagHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &application.OrderQueueRequest{
+		AllTags:     splitQueueParam(query.Get("all_tags")),
+		AnyTags:     splitQueueParam(query.Get("any_tags")),
+		ExcludeTags: splitQueueParam(query.Get("exclude_tags")),
+		Statuses:    splitQueueParam(query.Get("status")),
+		NewestFirst: query.Get("sort") == "newest",
+		Page:        httpPkg.GetQueryParamInt(r, "page", 1),
+		PageSize:    httpPkg.GetQueryParamInt(r, "page_size", 20),
+	}
+
+	queue, err := h.tagService.ListQueue(r.Context(), req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to list order queue")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, queue)
+}
+
+// GetOrderTags lists the tags of an order
+func (h *AdminOrderTagHandler) GetOrderTags(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := parseTagOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	tags, err := h.tagService.GetOrderTags(r.Context(), orderID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, tags)
+}
+
+// AddTags tags an order
+func (h *AdminOrderTagHandler) AddTags(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := parseTagOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.AddOrderTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		req.CreatedBy = email
+	}
+
+	tags, err := h.tagService.AddTags(r.Context(), orderID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to tag order")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, tags)
+}
+
+// RemoveTag removes a tag from an order
+func (h *AdminOrderTagHandler) RemoveTag(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := parseTagOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.RemoveTag(r.Context(), orderID, chi.URLParam(r, "tag")); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ApplyRules evaluates the tag rules against an order again
+func (h *AdminOrderTagHandler) ApplyRules(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := parseTagOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	tags, err := h.tagService.ApplyRules(r.Context(), orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to apply order tag rules")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, tags)
+}
+
+// ListRules lists the tag rules
+func (h *AdminOrderTagHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.tagService.ListRules(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list order tag rules")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, rules)
+}
+
+// CreateRule creates a tag rule
+func (h *AdminOrderTagHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var req application.OrderTagRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if email := middleware.GetUserEmail(r.Context()); email != "" {
+		req.CreatedBy = email
+	}
+
+	rule, err := h.tagService.CreateRule(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to create order tag rule")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, rule)
+}
+
+// UpdateRule updates a tag rule
+func (h *AdminOrderTagHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, ok := parseTagRuleID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.OrderTagRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	rule, err := h.tagService.UpdateRule(r.Context(), ruleID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("rule_id", ruleID).Error("failed to update order tag rule")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, rule)
+}
+
+// DeleteRule deletes a tag rule
+func (h *AdminOrderTagHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, ok := parseTagRuleID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.DeleteRule(r.Context(), ruleID); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// splitQueueParam splits a comma-separated queue filter, dropping empty entries
+func splitQueueParam(value string) []string {
+	values := make([]string, 0)
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func parseTagOrderID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return 0, false
+	}
+	return orderID, true
+}
+
+func parseTagRuleID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	ruleID, err := strconv.ParseInt(chi.URLParam(r, "ruleId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid tag rule ID").WithInternal(err))
+		return 0, false
+	}
+	return ruleID, true
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+	"github.com/qhato/ecommerce/pkg/validator"
+)
+
+// AdminPaymentLinkHandler handles the payment links of unpaid orders
+type AdminPaymentLinkHandler struct {
+	paymentLinkService application.PaymentLinkService
+	authMiddleware     func(http.Handler) http.Handler
+	validator          *validator.Validator
+	log                *logger.Logger
+}
+
+// NewAdminPaymentLinkHandler creates a new AdminPaymentLinkHandler
+func NewAdminPaymentLinkHandler(
+	paymentLinkService application.PaymentLinkService,
+	authMiddleware func(http.Handler) http.Handler,
+	validator *validator.Validator,
+	log *logger.Logger,
+) *AdminPaymentLinkHandler {
+	return &AdminPaymentLinkHandler{
+		paymentLinkService: paymentLinkService,
+		authMiddleware:     authMiddleware,
+		validator:          validator,
+		log:                log,
+	}
+}
+
+// RegisterRoutes registers payment link routes
+func (h *AdminPaymentLinkHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/admin/orders/{id}/payment-links", h.CreatePaymentLink)
+		r.Get("/admin/orders/{id}/payment-links", h.ListPaymentLinks)
+		r.Post("/admin/orders/{id}/payment-links/{linkId}/cancel", h.CancelPaymentLink)
+	})
+}
+
+// CreatePaymentLink issues a payment link for an order awaiting payment
+func (h *AdminPaymentLinkHandler) CreatePaymentLink(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	var req application.CreatePaymentLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	link, err := h.paymentLinkService.CreatePaymentLink(r.Context(), orderID, middleware.GetUserID(r.Context()), &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to create payment link")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, link)
+}
+
+// ListPaymentLinks lists the payment links of an order, newest first
+func (h *AdminPaymentLinkHandler) ListPaymentLinks(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	links, err := h.paymentLinkService.ListPaymentLinks(r.Context(), orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to list payment links")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, links)
+}
+
+// CancelPaymentLink makes an unpaid payment link unusable
+func (h *AdminPaymentLinkHandler) CancelPaymentLink(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+	linkID, err := strconv.ParseInt(chi.URLParam(r, "linkId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid payment link ID").WithInternal(err))
+		return
+	}
+
+	link, err := h.paymentLinkService.CancelPaymentLink(r.Context(), orderID, linkID)
+	if err != nil {
+		h.log.WithError(err).WithField("payment_link_id", linkID).Error("failed to cancel payment link")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, link)
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+	"github.com/qhato/ecommerce/pkg/validator"
+)
+
+// AdminSplitPaymentHandler handles capturing, voiding and refunding the tenders of orders
+type AdminSplitPaymentHandler struct {
+	splitPaymentService application.SplitPaymentService
+	authMiddleware      func(http.Handler) http.Handler
+	validator           *validator.Validator
+	log                 *logger.Logger
+}
+
+// NewAdminSplitPaymentHandler creates a new AdminSplitPaymentHandler
+func NewAdminSplitPaymentHandler(
+	splitPaymentService application.SplitPaymentService,
+	authMiddleware func(http.Handler) http.Handler,
+	validator *validator.Validator,
+	log *logger.Logger,
+) *AdminSplitPaymentHandler {
+	return &AdminSplitPaymentHandler{
+		splitPaymentService: splitPaymentService,
+		authMiddleware:      authMiddleware,
+		validator:           validator,
+		log:                 log,
+	}
+}
+
+// RegisterRoutes registers order tender routes
+func (h *AdminSplitPaymentHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/orders/{id}/tenders", h.GetPayment)
+		r.Post("/admin/orders/{id}/tenders/capture", h.CaptureOrder)
+		r.Post("/admin/orders/{id}/tenders/void", h.VoidOrder)
+		r.Post("/admin/orders/{id}/tenders/refund", h.RefundOrder)
+	})
+}
+
+// GetPayment lists the tenders of an order, including failed and voided attempts
+func (h *AdminSplitPaymentHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	payment, err := h.splitPaymentService.GetPayment(r.Context(), orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to list order tenders")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
+
+// CaptureOrder captures the authorized tenders of an order; complete is false
+// when a tender failed to capture and stays authorized
+func (h *AdminSplitPaymentHandler) CaptureOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	payment, err := h.splitPaymentService.CaptureOrder(r.Context(), orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to capture order tenders")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
+
+// VoidOrder releases the holds of an order's authorized tenders
+func (h *AdminSplitPaymentHandler) VoidOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	payment, err := h.splitPaymentService.VoidOrder(r.Context(), orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to void order tenders")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
+
+// RefundOrder refunds an amount across the captured tenders of an order
+func (h *AdminSplitPaymentHandler) RefundOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	var req application.RefundOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+	if err := h.validator.Validate(req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	refund, err := h.splitPaymentService.RefundOrder(r.Context(), orderID, &req, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to refund order tenders")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, refund)
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// maxChannelOrderBatchBytes bounds the size of an order batch sent by a channel
+const maxChannelOrderBatchBytes = 5 << 20
+
+// IntegrationChannelOrderHandler accepts the orders of external sales channels.
+// Integrations authenticate with their channel API key as a bearer token and send
+// orders in their channel's order format.
+type IntegrationChannelOrderHandler struct {
+	ingestionService application.ChannelOrderIngestionService
+	authMiddleware   func(http.Handler) http.Handler
+	log              *logger.Logger
+}
+
+// NewIntegrationChannelOrderHandler creates a new IntegrationChannelOrderHandler
+func NewIntegrationChannelOrderHandler(ingestionService application.ChannelOrderIngestionService, authMiddleware func(http.Handler) http.Handler, log *logger.Logger) *IntegrationChannelOrderHandler {
+	return &IntegrationChannelOrderHandler{
+		ingestionService: ingestionService,
+		authMiddleware:   authMiddleware,
+		log:              log,
+	}
+}
+
+// RegisterRoutes registers channel order routes
+func (h *IntegrationChannelOrderHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Post("/integrations/orders", h.IngestOrders)
+	})
+}
+
+// IngestOrders creates the orders of a batch and answers with the result of each;
+// orders sent again are reported as DUPLICATE rather than created twice
+func (h *IntegrationChannelOrderHandler) IngestOrders(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxChannelOrderBatchBytes))
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("order batch could not be read").WithInternal(err))
+		return
+	}
+
+	channel := middleware.GetChannel(r.Context())
+	batch, err := h.ingestionService.IngestOrders(r.Context(), channel, payload)
+	if err != nil {
+		h.log.WithError(err).WithField("channel", channel).Warn("failed to ingest channel orders")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, batch)
+}
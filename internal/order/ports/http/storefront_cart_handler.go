@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// StorefrontCartHandler handles storefront cart maintenance
+type StorefrontCartHandler struct {
+	orderService application.OrderService
+	log          *logger.Logger
+}
+
+// NewStorefrontCartHandler creates a new StorefrontCartHandler
+func NewStorefrontCartHandler(orderService application.OrderService, log *logger.Logger) *StorefrontCartHandler {
+	return &StorefrontCartHandler{
+		orderService: orderService,
+		log:          log,
+	}
+}
+
+// RegisterRoutes registers storefront cart routes
+func (h *StorefrontCartHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/cart/{id}/revalidate", h.RevalidateCart)
+}
+
+// RevalidateCart brings a stale cart up to date with current prices, offers and stock,
+// returning what changed. The body is optional and may name the coupon to apply again.
+func (h *StorefrontCartHandler) RevalidateCart(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		httpPkg.RespondError(w, errors.Unauthorized("authentication required"))
+		return
+	}
+	customerID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.Unauthorized("invalid customer").WithInternal(err))
+		return
+	}
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid cart ID").WithInternal(err))
+		return
+	}
+
+	var req application.RevalidateCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	result, err := h.orderService.RevalidateCart(r.Context(), customerID, orderID, req.CouponCode)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to revalidate cart")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
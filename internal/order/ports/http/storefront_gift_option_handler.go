@@ -0,0 +1,126 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// StorefrontGiftOptionHandler handles gift wraps and personal messages on cart items
+type StorefrontGiftOptionHandler struct {
+	giftOptionService application.GiftOptionService
+	log               *logger.Logger
+}
+
+// NewStorefrontGiftOptionHandler creates a new StorefrontGiftOptionHandler
+func NewStorefrontGiftOptionHandler(giftOptionService application.GiftOptionService, log *logger.Logger) *StorefrontGiftOptionHandler {
+	return &StorefrontGiftOptionHandler{
+		giftOptionService: giftOptionService,
+		log:               log,
+	}
+}
+
+// RegisterRoutes registers storefront gift option routes
+func (h *StorefrontGiftOptionHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/gift-wrap-options", h.ListGiftWrapOptions)
+	r.Get("/orders/{id}/items/{itemId}/gift-options", h.GetGiftOptions)
+	r.Put("/orders/{id}/items/{itemId}/gift-options", h.SetGiftOptions)
+	r.Delete("/orders/{id}/items/{itemId}/gift-options", h.ClearGiftOptions)
+}
+
+// ListGiftWrapOptions lists the gift wraps customers can choose from
+func (h *StorefrontGiftOptionHandler) ListGiftWrapOptions(w http.ResponseWriter, r *http.Request) {
+	options, err := h.giftOptionService.ListGiftWrapOptions(r.Context(), true)
+	if err != nil {
+		h.log.WithError(err).Error("failed to list gift wrap options")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, options)
+}
+
+// GetGiftOptions retrieves the gift options of a cart item
+func (h *StorefrontGiftOptionHandler) GetGiftOptions(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, itemID, err := parseGiftOptionRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	options, err := h.giftOptionService.GetGiftOptions(r.Context(), customerID, orderID, itemID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_item_id", itemID).Error("failed to get gift options")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, options)
+}
+
+// SetGiftOptions replaces the gift wrap and personal message of a cart item.
+// Omitting either one removes it.
+func (h *StorefrontGiftOptionHandler) SetGiftOptions(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, itemID, err := parseGiftOptionRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	var req application.SetGiftOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	options, err := h.giftOptionService.SetGiftOptions(r.Context(), customerID, orderID, itemID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_item_id", itemID).Error("failed to set gift options")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, options)
+}
+
+// ClearGiftOptions removes the gift wrap and personal message of a cart item
+func (h *StorefrontGiftOptionHandler) ClearGiftOptions(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, itemID, err := parseGiftOptionRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	if _, err := h.giftOptionService.SetGiftOptions(r.Context(), customerID, orderID, itemID, &application.SetGiftOptionsRequest{}); err != nil {
+		h.log.WithError(err).WithField("order_item_id", itemID).Error("failed to clear gift options")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseGiftOptionRequest reads the authenticated customer and the order item of the path
+func parseGiftOptionRequest(r *http.Request) (customerID, orderID, itemID int64, err error) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		return 0, 0, 0, errors.Unauthorized("authentication required")
+	}
+	if customerID, err = strconv.ParseInt(userID, 10, 64); err != nil {
+		return 0, 0, 0, errors.Unauthorized("invalid customer").WithInternal(err)
+	}
+	if orderID, err = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64); err != nil {
+		return 0, 0, 0, errors.BadRequest("invalid order ID").WithInternal(err)
+	}
+	if itemID, err = strconv.ParseInt(chi.URLParam(r, "itemId"), 10, 64); err != nil {
+		return 0, 0, 0, errors.BadRequest("invalid order item ID").WithInternal(err)
+	}
+	return customerID, orderID, itemID, nil
+}
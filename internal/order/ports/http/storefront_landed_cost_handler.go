@@ -0,0 +1,92 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// StorefrontLandedCostHandler handles the duties and import fees shown on
+// international checkouts
+type StorefrontLandedCostHandler struct {
+	landedCostService application.LandedCostService
+	log               *logger.Logger
+}
+
+// NewStorefrontLandedCostHandler creates a new StorefrontLandedCostHandler
+func NewStorefrontLandedCostHandler(landedCostService application.LandedCostService, log *logger.Logger) *StorefrontLandedCostHandler {
+	return &StorefrontLandedCostHandler{
+		landedCostService: landedCostService,
+		log:               log,
+	}
+}
+
+// RegisterRoutes registers storefront landed cost routes
+func (h *StorefrontLandedCostHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/orders/{id}/landed-cost", h.GetLandedCost)
+	r.Post("/orders/{id}/landed-cost", h.EstimateLandedCost)
+}
+
+// GetLandedCost returns the duties and import fees last estimated for a cart
+func (h *StorefrontLandedCostHandler) GetLandedCost(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseLandedCostRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	cost, err := h.landedCostService.GetLandedCost(r.Context(), customerID, orderID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, cost)
+}
+
+// EstimateLandedCost estimates the duties and import fees of a cart for its shipping
+// address under the chosen incoterm, e.g. {"incoterm": "DDP"}
+func (h *StorefrontLandedCostHandler) EstimateLandedCost(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseLandedCostRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	var req application.EstimateLandedCostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	cost, err := h.landedCostService.EstimateLandedCost(r.Context(), customerID, orderID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to estimate landed cost")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, cost)
+}
+
+// parseLandedCostRequest reads the authenticated customer and the order of the path
+func parseLandedCostRequest(r *http.Request) (customerID, orderID int64, err error) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		return 0, 0, errors.Unauthorized("authentication required")
+	}
+	if customerID, err = strconv.ParseInt(userID, 10, 64); err != nil {
+		return 0, 0, errors.Unauthorized("invalid customer").WithInternal(err)
+	}
+	if orderID, err = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64); err != nil {
+		return 0, 0, errors.BadRequest("invalid order ID").WithInternal(err)
+	}
+	return customerID, orderID, nil
+}
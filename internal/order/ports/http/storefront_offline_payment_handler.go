@@ -0,0 +1,110 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// StorefrontOfflinePaymentHandler handles placing orders with cash on delivery, bank transfer or pay in store
+type StorefrontOfflinePaymentHandler struct {
+	offlinePaymentService application.OfflinePaymentService
+	log                   *logger.Logger
+}
+
+// NewStorefrontOfflinePaymentHandler creates a new StorefrontOfflinePaymentHandler
+func NewStorefrontOfflinePaymentHandler(offlinePaymentService application.OfflinePaymentService, log *logger.Logger) *StorefrontOfflinePaymentHandler {
+	return &StorefrontOfflinePaymentHandler{
+		offlinePaymentService: offlinePaymentService,
+		log:                   log,
+	}
+}
+
+// RegisterRoutes registers storefront offline payment routes
+func (h *StorefrontOfflinePaymentHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/orders/{id}/offline-payment-methods", h.ListAvailableMethods)
+	r.Post("/orders/{id}/offline-payment", h.PlaceOrder)
+	r.Get("/orders/{id}/offline-payment", h.GetPayment)
+}
+
+// ListAvailableMethods lists the offline payment methods the order can be placed with
+func (h *StorefrontOfflinePaymentHandler) ListAvailableMethods(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseCustomerOrderRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	methods, err := h.offlinePaymentService.ListAvailableMethods(r.Context(), customerID, orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to list offline payment methods")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, methods)
+}
+
+// PlaceOrder places the order with an offline payment method. The order is
+// submitted once the payment is received.
+func (h *StorefrontOfflinePaymentHandler) PlaceOrder(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseCustomerOrderRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	var req application.PlaceOfflinePaymentOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	payment, err := h.offlinePaymentService.PlaceOrder(r.Context(), customerID, orderID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to place order with offline payment")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
+
+// GetPayment retrieves the offline payment of the order with how to pay it
+func (h *StorefrontOfflinePaymentHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseCustomerOrderRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	payment, err := h.offlinePaymentService.GetCustomerPayment(r.Context(), customerID, orderID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
+
+// parseCustomerOrderRequest reads the authenticated customer and the order of the path
+func parseCustomerOrderRequest(r *http.Request) (customerID, orderID int64, err error) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		return 0, 0, errors.Unauthorized("authentication required")
+	}
+	if customerID, err = strconv.ParseInt(userID, 10, 64); err != nil {
+		return 0, 0, errors.Unauthorized("invalid customer").WithInternal(err)
+	}
+	if orderID, err = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64); err != nil {
+		return 0, 0, errors.BadRequest("invalid order ID").WithInternal(err)
+	}
+	return customerID, orderID, nil
+}
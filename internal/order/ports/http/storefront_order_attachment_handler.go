@@ -0,0 +1,101 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// StorefrontOrderAttachmentHandler lets customers see, download and upload the
+// customer-visible attachments of their own orders
+type StorefrontOrderAttachmentHandler struct {
+	attachmentService application.OrderAttachmentService
+	maxUploadBytes    int64
+	log               *logger.Logger
+}
+
+// NewStorefrontOrderAttachmentHandler creates a new StorefrontOrderAttachmentHandler.
+// maxUploadBytes bounds uploaded files.
+func NewStorefrontOrderAttachmentHandler(attachmentService application.OrderAttachmentService, maxUploadBytes int64, log *logger.Logger) *StorefrontOrderAttachmentHandler {
+	return &StorefrontOrderAttachmentHandler{
+		attachmentService: attachmentService,
+		maxUploadBytes:    maxUploadBytes,
+		log:               log,
+	}
+}
+
+// RegisterRoutes registers storefront order attachment routes
+func (h *StorefrontOrderAttachmentHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/orders/{id}/attachments", h.ListAttachments)
+	r.Post("/orders/{id}/attachments", h.UploadAttachment)
+	r.Get("/orders/{id}/attachments/{attachmentId}/download", h.DownloadAttachment)
+}
+
+// ListAttachments lists the customer-visible attachments of an order of the customer
+func (h *StorefrontOrderAttachmentHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseCustomerOrderRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	attachments, err := h.attachmentService.ListForCustomer(r.Context(), customerID, orderID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, attachments)
+}
+
+// UploadAttachment attaches the "file" field of a multipart form to an order of the
+// customer, e.g. a purchase order or photos of damaged items. Form fields: kind
+// (PURCHASE_ORDER, CUSTOMS, DAMAGE_PHOTO or OTHER) and description.
+func (h *StorefrontOrderAttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseCustomerOrderRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	req, err := readAttachmentUpload(w, r, h.maxUploadBytes)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	attachment, err := h.attachmentService.UploadForCustomer(r.Context(), customerID, orderID, req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to upload customer order attachment")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, attachment)
+}
+
+// DownloadAttachment serves a customer-visible attachment of an order of the customer
+func (h *StorefrontOrderAttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseCustomerOrderRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	attachmentID, err := strconv.ParseInt(chi.URLParam(r, "attachmentId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid attachment ID").WithInternal(err))
+		return
+	}
+
+	content, err := h.attachmentService.DownloadForCustomer(r.Context(), customerID, orderID, attachmentID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	RespondAttachment(w, content)
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// StorefrontOrderEditHandler handles customers editing their orders shortly after
+// placing them
+type StorefrontOrderEditHandler struct {
+	editService application.OrderEditService
+	log         *logger.Logger
+}
+
+// NewStorefrontOrderEditHandler creates a new StorefrontOrderEditHandler
+func NewStorefrontOrderEditHandler(editService application.OrderEditService, log *logger.Logger) *StorefrontOrderEditHandler {
+	return &StorefrontOrderEditHandler{
+		editService: editService,
+		log:         log,
+	}
+}
+
+// RegisterRoutes registers storefront order edit routes
+func (h *StorefrontOrderEditHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/orders/{id}/edits", h.OpenEdit)
+	r.Get("/order-edits/{editId}", h.GetEdit)
+	r.Post("/order-edits/{editId}/lines", h.AddLine)
+	r.Put("/order-edits/{editId}/lines/{lineId}", h.UpdateLine)
+	r.Post("/order-edits/{editId}/preview", h.PreviewEdit)
+	r.Post("/order-edits/{editId}/commit", h.CommitEdit)
+	r.Post("/order-edits/{editId}/cancel", h.CancelEdit)
+}
+
+// OpenEdit opens an edit of the customer's order, or returns the one already open
+func (h *StorefrontOrderEditHandler) OpenEdit(w http.ResponseWriter, r *http.Request) {
+	editor, err := storefrontOrderEditor(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	orderID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	edit, err := h.editService.OpenEdit(r.Context(), editor, orderID)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to open order edit")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, edit)
+}
+
+// GetEdit returns an edit of the customer's order
+func (h *StorefrontOrderEditHandler) GetEdit(w http.ResponseWriter, r *http.Request) {
+	editor, err := storefrontOrderEditor(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	edit, err := h.editService.GetEdit(r.Context(), editor, editID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// AddLine stages a SKU to add to the order, e.g. {"sku_id": 12, "quantity": 1}
+func (h *StorefrontOrderEditHandler) AddLine(w http.ResponseWriter, r *http.Request) {
+	editor, err := storefrontOrderEditor(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.AddOrderEditLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	edit, err := h.editService.AddLine(r.Context(), editor, editID, &req)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// UpdateLine changes the quantity of a line, e.g. {"quantity": 0} to remove it
+func (h *StorefrontOrderEditHandler) UpdateLine(w http.ResponseWriter, r *http.Request) {
+	editor, err := storefrontOrderEditor(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	editID, lineID, ok := parseOrderEditLineID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.UpdateOrderEditLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	edit, err := h.editService.UpdateLine(r.Context(), editor, editID, lineID, &req)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// PreviewEdit prices the added lines and returns the delta to confirm
+func (h *StorefrontOrderEditHandler) PreviewEdit(w http.ResponseWriter, r *http.Request) {
+	editor, err := storefrontOrderEditor(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	edit, err := h.editService.PreviewEdit(r.Context(), editor, editID)
+	if err != nil {
+		h.log.WithError(err).WithField("edit_id", editID).Error("failed to preview order edit")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// CommitEdit applies the previewed delta, e.g. {"revision": 3, "confirm": true}
+func (h *StorefrontOrderEditHandler) CommitEdit(w http.ResponseWriter, r *http.Request) {
+	editor, err := storefrontOrderEditor(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.CommitOrderEditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	edit, err := h.editService.CommitEdit(r.Context(), editor, editID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("edit_id", editID).Error("failed to commit order edit")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// CancelEdit closes an edit without changing the order
+func (h *StorefrontOrderEditHandler) CancelEdit(w http.ResponseWriter, r *http.Request) {
+	editor, err := storefrontOrderEditor(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+	editID, ok := parseOrderEditID(w, r)
+	if !ok {
+		return
+	}
+
+	edit, err := h.editService.CancelEdit(r.Context(), editor, editID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, edit)
+}
+
+// storefrontOrderEditor is the authenticated customer, who may only edit their own orders
+func storefrontOrderEditor(r *http.Request) (application.OrderEditor, error) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		return application.OrderEditor{}, errors.Unauthorized("authentication required")
+	}
+	customerID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil || customerID == 0 {
+		return application.OrderEditor{}, errors.Unauthorized("invalid customer").WithInternal(err)
+	}
+	name := middleware.GetUserEmail(r.Context())
+	if name == "" {
+		name = "customer " + userID
+	}
+	return application.OrderEditor{CustomerID: customerID, Name: name}, nil
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	offerApp "github.com/qhato/ecommerce/internal/offer/application"
+	"github.com/qhato/ecommerce/internal/order/application"
 	"github.com/qhato/ecommerce/internal/order/application/queries"
 	"github.com/qhato/ecommerce/internal/order/domain"
 	httpPkg "github.com/qhato/ecommerce/pkg/http"
@@ -14,18 +16,24 @@ import (
 
 // StorefrontOrderHandler handles storefront order HTTP requests
 type StorefrontOrderHandler struct {
-	queryHandler *queries.OrderQueryHandler
-	log          *logger.Logger
+	queryHandler     *queries.OrderQueryHandler
+	noteService      application.OrderNoteService
+	promotionService offerApp.PromotionMessageService
+	log              *logger.Logger
 }
 
 // NewStorefrontOrderHandler creates a new StorefrontOrderHandler
 func NewStorefrontOrderHandler(
 	queryHandler *queries.OrderQueryHandler,
+	noteService application.OrderNoteService,
+	promotionService offerApp.PromotionMessageService,
 	log *logger.Logger,
 ) *StorefrontOrderHandler {
 	return &StorefrontOrderHandler{
-		queryHandler: queryHandler,
-		log:          log,
+		queryHandler:     queryHandler,
+		noteService:      noteService,
+		promotionService: promotionService,
+		log:              log,
 	}
 }
 
@@ -33,6 +41,8 @@ func NewStorefrontOrderHandler(
 func (h *StorefrontOrderHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/orders", func(r chi.Router) {
 		r.Get("/{id}", h.GetOrder)
+		r.Get("/{id}/timeline", h.GetOrderTimeline)
+		r.Get("/{id}/promotion-messages", h.GetPromotionMessages)
 		r.Get("/number/{orderNumber}", h.GetOrderByNumber)
 		r.Get("/customer/{customerId}", h.ListCustomerOrders)
 	})
@@ -58,6 +68,12 @@ func (h *StorefrontOrderHandler) GetOrder(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if err := h.attachCustomerNotes(r, order); err != nil {
+		httpPkg.RespondError(w, errors.Internal("failed to get order notes").WithInternal(err))
+		return
+	}
+	h.attachPromotionMessages(r, order)
+
 	httpPkg.RespondJSON(w, http.StatusOK, order)
 }
 
@@ -80,9 +96,90 @@ func (h *StorefrontOrderHandler) GetOrderByNumber(w http.ResponseWriter, r *http
 		return
 	}
 
+	if err := h.attachCustomerNotes(r, order); err != nil {
+		httpPkg.RespondError(w, errors.Internal("failed to get order notes").WithInternal(err))
+		return
+	}
+	h.attachPromotionMessages(r, order)
+
 	httpPkg.RespondJSON(w, http.StatusOK, order)
 }
 
+// GetOrderTimeline retrieves the customer-visible timeline of an order
+func (h *StorefrontOrderHandler) GetOrderTimeline(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	timeline, err := h.noteService.GetTimeline(r.Context(), id, true)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			httpPkg.RespondError(w, errors.NotFound("order"))
+		} else {
+			httpPkg.RespondError(w, errors.Internal("failed to get order timeline").WithInternal(err))
+		}
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, timeline)
+}
+
+// GetPromotionMessages lists the offers a cart is close to qualifying for, with the spend still needed
+func (h *StorefrontOrderHandler) GetPromotionMessages(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid order ID").WithInternal(err))
+		return
+	}
+
+	order, err := h.queryHandler.HandleGetOrderByID(r.Context(), &queries.GetOrderByIDQuery{ID: id})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			httpPkg.RespondError(w, errors.NotFound(err.Error()))
+		} else {
+			httpPkg.RespondError(w, errors.Internal("failed to get order").WithInternal(err))
+		}
+		return
+	}
+
+	messages, err := h.promotionService.GetQualificationGaps(r.Context(), application.ToCartSnapshot(order))
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", id).Error("failed to get promotion messages")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, messages)
+}
+
+// attachPromotionMessages adds upsell messages to carts. Messages are a nicety, so a
+// failure is logged and the cart returned without them.
+func (h *StorefrontOrderHandler) attachPromotionMessages(r *http.Request, order *application.OrderDTO) {
+	if order.Status != domain.OrderStatusPending {
+		return
+	}
+	messages, err := h.promotionService.GetQualificationGaps(r.Context(), application.ToCartSnapshot(order))
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", order.ID).Warn("failed to get promotion messages")
+		return
+	}
+	order.PromotionMessages = messages
+}
+
+// attachCustomerNotes adds the customer-visible notes to an order
+func (h *StorefrontOrderHandler) attachCustomerNotes(r *http.Request, order *application.OrderDTO) error {
+	notes, err := h.noteService.ListNotes(r.Context(), order.ID, true)
+	if err != nil {
+		return err
+	}
+	order.Notes = notes
+	return nil
+}
+
 // ListCustomerOrders lists orders for a specific customer
 func (h *StorefrontOrderHandler) ListCustomerOrders(w http.ResponseWriter, r *http.Request) {
 	customerIDStr := chi.URLParam(r, "customerId")
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// StorefrontPayLinkHandler lets customers pay unpaid orders through their payment links
+type StorefrontPayLinkHandler struct {
+	paymentLinkService application.PaymentLinkService
+	log                *logger.Logger
+}
+
+// NewStorefrontPayLinkHandler creates a new StorefrontPayLinkHandler
+func NewStorefrontPayLinkHandler(paymentLinkService application.PaymentLinkService, log *logger.Logger) *StorefrontPayLinkHandler {
+	return &StorefrontPayLinkHandler{
+		paymentLinkService: paymentLinkService,
+		log:                log,
+	}
+}
+
+// RegisterRoutes registers payment link routes
+func (h *StorefrontPayLinkHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/orders/pay-links/{token}", h.GetPayLink)
+	r.Post("/orders/pay-links/{token}", h.PayByLink)
+}
+
+// GetPayLink shows the landing page data of a payment link: the order, the
+// amount and the gateway the payment form confirms the payment with
+func (h *StorefrontPayLinkHandler) GetPayLink(w http.ResponseWriter, r *http.Request) {
+	payLink, err := h.paymentLinkService.GetPayLink(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payLink)
+}
+
+// PayByLink pays the order of a payment link and submits it
+func (h *StorefrontPayLinkHandler) PayByLink(w http.ResponseWriter, r *http.Request) {
+	var req application.PayByLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	payLink, err := h.paymentLinkService.PayByLink(r.Context(), chi.URLParam(r, "token"), &req)
+	if err != nil {
+		h.log.WithError(err).Error("failed to pay order by payment link")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payLink)
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/order/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// StorefrontSplitPaymentHandler handles paying orders with one or more tenders
+type StorefrontSplitPaymentHandler struct {
+	splitPaymentService application.SplitPaymentService
+	log                 *logger.Logger
+}
+
+// NewStorefrontSplitPaymentHandler creates a new StorefrontSplitPaymentHandler
+func NewStorefrontSplitPaymentHandler(splitPaymentService application.SplitPaymentService, log *logger.Logger) *StorefrontSplitPaymentHandler {
+	return &StorefrontSplitPaymentHandler{
+		splitPaymentService: splitPaymentService,
+		log:                 log,
+	}
+}
+
+// RegisterRoutes registers storefront split payment routes
+func (h *StorefrontSplitPaymentHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/orders/{id}/payment", h.PayOrder)
+	r.Get("/orders/{id}/payment", h.GetPayment)
+}
+
+// PayOrder pays the order with the tenders it is split across and submits it.
+// When a tender is declined no payment is taken.
+func (h *StorefrontSplitPaymentHandler) PayOrder(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseCustomerOrderRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	var req application.SplitPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	payment, err := h.splitPaymentService.PayOrder(r.Context(), customerID, orderID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("order_id", orderID).Error("failed to pay order")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
+
+// GetPayment retrieves the tenders the order was paid with
+func (h *StorefrontSplitPaymentHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	customerID, orderID, err := parseCustomerOrderRequest(r)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	payment, err := h.splitPaymentService.GetCustomerPayment(r.Context(), customerID, orderID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, payment)
+}
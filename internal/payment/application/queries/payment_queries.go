@@ -28,12 +28,19 @@ func NewPaymentQueryHandler(repo domain.PaymentRepository, cache cache.Cache, lo
 	}
 }
 
+var (
+	// paymentKeys builds the cache keys of payments
+	paymentKeys = cache.NewKeyBuilder("payment", "payment", 1)
+	// paymentTransactionKeys builds the cache keys of payments looked up by transaction ID
+	paymentTransactionKeys = cache.NewKeyBuilder("payment", "transaction", 1)
+)
+
 // GetByID retrieves a payment by ID
 func (h *PaymentQueryHandler) GetByID(ctx context.Context, id int64) (*domain.Payment, error) {
 	h.log.WithField("id", id).Debug("Fetching payment by ID")
 
 	// Try cache first
-	cacheKey := fmt.Sprintf("payment:id:%d", id)
+	cacheKey := paymentKeys.Key(id)
 	if cached, err := h.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
 		var payment domain.Payment
 		if err := json.Unmarshal(cached, &payment); err == nil {
@@ -65,7 +72,7 @@ func (h *PaymentQueryHandler) GetByTransactionID(ctx context.Context, transactio
 	h.log.WithField("transactionID", transactionID).Debug("Fetching payment by transaction ID")
 
 	// Try cache first
-	cacheKey := fmt.Sprintf("payment:txn:%s", transactionID)
+	cacheKey := paymentTransactionKeys.Key(transactionID)
 	if cached, err := h.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
 		var payment domain.Payment
 		if err := json.Unmarshal(cached, &payment); err == nil {
@@ -133,11 +140,11 @@ func (h *PaymentQueryHandler) List(ctx context.Context, filter *domain.PaymentFi
 
 // InvalidateCache invalidates the cache for a payment
 func (h *PaymentQueryHandler) InvalidateCache(ctx context.Context, paymentID int64, transactionID string) {
-	cacheKey1 := fmt.Sprintf("payment:id:%d", paymentID)
+	cacheKey1 := paymentKeys.Key(paymentID)
 	_ = h.cache.Delete(ctx, cacheKey1)
 
 	if transactionID != "" {
-		cacheKey2 := fmt.Sprintf("payment:txn:%s", transactionID)
+		cacheKey2 := paymentTransactionKeys.Key(transactionID)
 		_ = h.cache.Delete(ctx, cacheKey2)
 	}
 }
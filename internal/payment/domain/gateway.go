@@ -112,120 +112,6 @@ type GatewayConfig struct {
 	Config      map[string]string // Additional configuration
 }
 
-// StripeGateway implements PaymentGateway for Stripe
-type StripeGateway struct {
-	config *GatewayConfig
-}
-
-// NewStripeGateway creates a new Stripe gateway
-func NewStripeGateway(config *GatewayConfig) *StripeGateway {
-	return &StripeGateway{config: config}
-}
-
-func (g *StripeGateway) GetName() string {
-	return "Stripe"
-}
-
-func (g *StripeGateway) Authorize(ctx context.Context, request *PaymentRequest) (*PaymentResponse, error) {
-	// TODO: Implement Stripe API integration
-	return &PaymentResponse{
-		TransactionID: "stripe_auth_123",
-		Status:        PaymentStatusAuthorized,
-		Amount:        request.Amount,
-		Currency:      request.Currency,
-		ProcessedAt:   time.Now(),
-	}, nil
-}
-
-func (g *StripeGateway) Capture(ctx context.Context, transactionID string, amount decimal.Decimal) (*PaymentResponse, error) {
-	// TODO: Implement Stripe capture
-	return &PaymentResponse{
-		TransactionID: transactionID,
-		Status:        PaymentStatusCaptured,
-		Amount:        amount,
-		ProcessedAt:   time.Now(),
-	}, nil
-}
-
-func (g *StripeGateway) Sale(ctx context.Context, request *PaymentRequest) (*PaymentResponse, error) {
-	// TODO: Implement Stripe sale (charge)
-	return &PaymentResponse{
-		TransactionID: "stripe_sale_123",
-		Status:        PaymentStatusCompleted,
-		Amount:        request.Amount,
-		Currency:      request.Currency,
-		ProcessedAt:   time.Now(),
-	}, nil
-}
-
-func (g *StripeGateway) Refund(ctx context.Context, transactionID string, amount decimal.Decimal) (*PaymentResponse, error) {
-	// TODO: Implement Stripe refund
-	return &PaymentResponse{
-		TransactionID: transactionID,
-		Status:        PaymentStatusRefunded,
-		Amount:        amount,
-		ProcessedAt:   time.Now(),
-	}, nil
-}
-
-func (g *StripeGateway) Void(ctx context.Context, transactionID string) (*PaymentResponse, error) {
-	// TODO: Implement Stripe void
-	return &PaymentResponse{
-		TransactionID: transactionID,
-		Status:        PaymentStatusCancelled,
-		ProcessedAt:   time.Now(),
-	}, nil
-}
-
-func (g *StripeGateway) GetTransaction(ctx context.Context, transactionID string) (*PaymentResponse, error) {
-	// TODO: Implement Stripe transaction retrieval
-	return nil, nil
-}
-
-// PayPalGateway implements PaymentGateway for PayPal
-type PayPalGateway struct {
-	config *GatewayConfig
-}
-
-// NewPayPalGateway creates a new PayPal gateway
-func NewPayPalGateway(config *GatewayConfig) *PayPalGateway {
-	return &PayPalGateway{config: config}
-}
-
-func (g *PayPalGateway) GetName() string {
-	return "PayPal"
-}
-
-func (g *PayPalGateway) Authorize(ctx context.Context, request *PaymentRequest) (*PaymentResponse, error) {
-	// TODO: Implement PayPal authorization
-	return nil, nil
-}
-
-func (g *PayPalGateway) Capture(ctx context.Context, transactionID string, amount decimal.Decimal) (*PaymentResponse, error) {
-	// TODO: Implement PayPal capture
-	return nil, nil
-}
-
-func (g *PayPalGateway) Sale(ctx context.Context, request *PaymentRequest) (*PaymentResponse, error) {
-	// TODO: Implement PayPal sale
-	return nil, nil
-}
-
-func (g *PayPalGateway) Refund(ctx context.Context, transactionID string, amount decimal.Decimal) (*PaymentResponse, error) {
-	// TODO: Implement PayPal refund
-	return nil, nil
-}
-
-func (g *PayPalGateway) Void(ctx context.Context, transactionID string) (*PaymentResponse, error) {
-	// TODO: Implement PayPal void
-	return nil, nil
-}
-
-func (g *PayPalGateway) GetTransaction(ctx context.Context, transactionID string) (*PaymentResponse, error) {
-	// TODO: Implement PayPal transaction retrieval
-	return nil, nil
-}
-
 // PaymentGatewayService manages multiple payment gateways
 type PaymentGatewayService struct {
 	gateways map[string]PaymentGateway
@@ -0,0 +1,281 @@
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/qhato/ecommerce/pkg/httpclient"
+	"github.com/qhato/ecommerce/pkg/money"
+)
+
+// PayPal API endpoints by gateway environment
+const (
+	PayPalSandboxBaseURL    = "https://api-m.sandbox.paypal.com"
+	PayPalProductionBaseURL = "https://api-m.paypal.com"
+)
+
+// PayPalGateway implements PaymentGateway for PayPal using the Orders v2 API.
+// The buyer approves the order on PayPal first; requests carry the approved
+// order ID in Metadata["paypal_order_id"].
+type PayPalGateway struct {
+	config *GatewayConfig
+	client *httpclient.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewPayPalGateway creates a new PayPal gateway
+func NewPayPalGateway(config *GatewayConfig, client *httpclient.Client) *PayPalGateway {
+	return &PayPalGateway{config: config, client: client}
+}
+
+func (g *PayPalGateway) GetName() string {
+	return "PayPal"
+}
+
+func (g *PayPalGateway) Authorize(ctx context.Context, request *PaymentRequest) (*PaymentResponse, error) {
+	orderID := request.Metadata["paypal_order_id"]
+	if orderID == "" {
+		return nil, NewDomainError("PayPal requires an approved order ID")
+	}
+
+	var order paypalOrder
+	if err := g.call(ctx, http.MethodPost, "/v2/checkout/orders/"+url.PathEscape(orderID)+"/authorize", struct{}{}, &order); err != nil {
+		return nil, err
+	}
+	authorization := order.firstPayment(func(p *paypalPurchaseUnitPayments) []paypalPayment { return p.Authorizations })
+	if authorization == nil {
+		return nil, NewDomainError("PayPal returned no authorization")
+	}
+	return authorization.toPaymentResponse(), nil
+}
+
+func (g *PayPalGateway) Capture(ctx context.Context, transactionID string, amount decimal.Decimal) (*PaymentResponse, error) {
+	authorization, err := g.getPayment(ctx, "/v2/payments/authorizations/"+url.PathEscape(transactionID))
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{"amount": paypalAmountOf(amount, authorization.Amount.CurrencyCode)}
+	var capture paypalPayment
+	if err := g.call(ctx, http.MethodPost, "/v2/payments/authorizations/"+url.PathEscape(transactionID)+"/capture", body, &capture); err != nil {
+		return nil, err
+	}
+	response := capture.toPaymentResponse()
+	if response.Status == PaymentStatusCompleted {
+		response.Status = PaymentStatusCaptured
+	}
+	return response, nil
+}
+
+func (g *PayPalGateway) Sale(ctx context.Context, request *PaymentRequest) (*PaymentResponse, error) {
+	orderID := request.Metadata["paypal_order_id"]
+	if orderID == "" {
+		return nil, NewDomainError("PayPal requires an approved order ID")
+	}
+
+	var order paypalOrder
+	if err := g.call(ctx, http.MethodPost, "/v2/checkout/orders/"+url.PathEscape(orderID)+"/capture", struct{}{}, &order); err != nil {
+		return nil, err
+	}
+	capture := order.firstPayment(func(p *paypalPurchaseUnitPayments) []paypalPayment { return p.Captures })
+	if capture == nil {
+		return nil, NewDomainError("PayPal returned no capture")
+	}
+	return capture.toPaymentResponse(), nil
+}
+
+func (g *PayPalGateway) Refund(ctx context.Context, transactionID string, amount decimal.Decimal) (*PaymentResponse, error) {
+	capture, err := g.getPayment(ctx, "/v2/payments/captures/"+url.PathEscape(transactionID))
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{"amount": paypalAmountOf(amount, capture.Amount.CurrencyCode)}
+	var refund paypalPayment
+	if err := g.call(ctx, http.MethodPost, "/v2/payments/captures/"+url.PathEscape(transactionID)+"/refund", body, &refund); err != nil {
+		return nil, err
+	}
+	response := refund.toPaymentResponse()
+	if response.Status == PaymentStatusCompleted {
+		response.Status = PaymentStatusRefunded
+	}
+	return response, nil
+}
+
+func (g *PayPalGateway) Void(ctx context.Context, transactionID string) (*PaymentResponse, error) {
+	if err := g.call(ctx, http.MethodPost, "/v2/payments/authorizations/"+url.PathEscape(transactionID)+"/void", nil, nil); err != nil {
+		return nil, err
+	}
+	return &PaymentResponse{
+		TransactionID: transactionID,
+		Status:        PaymentStatusCancelled,
+		ProcessedAt:   time.Now(),
+	}, nil
+}
+
+func (g *PayPalGateway) GetTransaction(ctx context.Context, transactionID string) (*PaymentResponse, error) {
+	capture, err := g.getPayment(ctx, "/v2/payments/captures/"+url.PathEscape(transactionID))
+	if err != nil {
+		return nil, err
+	}
+	return capture.toPaymentResponse(), nil
+}
+
+func (g *PayPalGateway) getPayment(ctx context.Context, path string) (*paypalPayment, error) {
+	var payment paypalPayment
+	if err := g.call(ctx, http.MethodGet, path, nil, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// call sends an authenticated JSON request; POSTs carry a PayPal-Request-Id so
+// retries are idempotent
+func (g *PayPalGateway) call(ctx context.Context, method, path string, in, out interface{}) error {
+	token, err := g.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("Content-Type", "application/json")
+	header.Set("PayPal-Request-Id", uuid.New().String())
+
+	var body []byte
+	if in != nil {
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("failed to encode PayPal request: %w", err)
+		}
+	}
+	resp, err := g.client.Do(ctx, &httpclient.Request{Method: method, Path: path, Header: header, Body: body, Idempotent: true})
+	if err != nil {
+		return err
+	}
+	if out != nil && len(resp.Body) > 0 {
+		return resp.DecodeJSON(out)
+	}
+	return nil
+}
+
+// token returns a cached OAuth access token, requesting a new one shortly before expiry
+func (g *PayPalGateway) token(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.accessToken != "" && time.Now().Before(g.expiresAt) {
+		return g.accessToken, nil
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte(g.config.APIKey + ":" + g.config.APISecret))
+	header := http.Header{}
+	header.Set("Authorization", "Basic "+credentials)
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := g.client.Do(ctx, &httpclient.Request{
+		Method:     http.MethodPost,
+		Path:       "/v1/oauth2/token",
+		Header:     header,
+		Body:       []byte("grant_type=client_credentials"),
+		Idempotent: true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := resp.DecodeJSON(&token); err != nil {
+		return "", err
+	}
+	g.accessToken = token.AccessToken
+	g.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return g.accessToken, nil
+}
+
+type paypalAmount struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+type paypalPayment struct {
+	ID     string       `json:"id"`
+	Status string       `json:"status"`
+	Amount paypalAmount `json:"amount"`
+}
+
+type paypalPurchaseUnitPayments struct {
+	Authorizations []paypalPayment `json:"authorizations"`
+	Captures       []paypalPayment `json:"captures"`
+}
+
+type paypalOrder struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	PurchaseUnits []struct {
+		Payments paypalPurchaseUnitPayments `json:"payments"`
+	} `json:"purchase_units"`
+}
+
+func (o *paypalOrder) firstPayment(pick func(*paypalPurchaseUnitPayments) []paypalPayment) *paypalPayment {
+	for i := range o.PurchaseUnits {
+		if payments := pick(&o.PurchaseUnits[i].Payments); len(payments) > 0 {
+			return &payments[0]
+		}
+	}
+	return nil
+}
+
+func (p *paypalPayment) toPaymentResponse() *PaymentResponse {
+	amount, _ := decimal.NewFromString(p.Amount.Value)
+	return &PaymentResponse{
+		TransactionID: p.ID,
+		Status:        paypalStatus(p.Status),
+		Amount:        amount,
+		Currency:      p.Amount.CurrencyCode,
+		ProcessedAt:   time.Now(),
+	}
+}
+
+func paypalStatus(status string) PaymentStatus {
+	switch strings.ToUpper(status) {
+	case "CREATED":
+		return PaymentStatusAuthorized
+	case "CAPTURED":
+		return PaymentStatusCaptured
+	case "COMPLETED":
+		return PaymentStatusCompleted
+	case "PENDING":
+		return PaymentStatusProcessing
+	case "VOIDED":
+		return PaymentStatusCancelled
+	case "DECLINED", "FAILED", "DENIED":
+		return PaymentStatusFailed
+	case "REFUNDED", "PARTIALLY_REFUNDED":
+		return PaymentStatusRefunded
+	default:
+		return PaymentStatusPending
+	}
+}
+
+func paypalAmountOf(amount decimal.Decimal, currency string) paypalAmount {
+	return paypalAmount{
+		CurrencyCode: strings.ToUpper(currency),
+		Value:        amount.StringFixed(money.RuleFor(currency).DecimalPlaces),
+	}
+}
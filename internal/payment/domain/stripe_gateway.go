@@ -0,0 +1,271 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/qhato/ecommerce/pkg/httpclient"
+	"github.com/qhato/ecommerce/pkg/money"
+)
+
+// StripeBaseURL is the Stripe API endpoint; test and live mode are selected by the API key
+const StripeBaseURL = "https://api.stripe.com"
+
+// StripeGateway implements PaymentGateway for Stripe using payment intents.
+// Card data never reaches the gateway: requests carry a Stripe payment method
+// token in Metadata["payment_method"].
+type StripeGateway struct {
+	config *GatewayConfig
+	client *httpclient.Client
+}
+
+// NewStripeGateway creates a new Stripe gateway
+func NewStripeGateway(config *GatewayConfig, client *httpclient.Client) *StripeGateway {
+	return &StripeGateway{config: config, client: client}
+}
+
+func (g *StripeGateway) GetName() string {
+	return "Stripe"
+}
+
+func (g *StripeGateway) Authorize(ctx context.Context, request *PaymentRequest) (*PaymentResponse, error) {
+	return g.createPaymentIntent(ctx, request, "manual")
+}
+
+func (g *StripeGateway) Capture(ctx context.Context, transactionID string, amount decimal.Decimal) (*PaymentResponse, error) {
+	intent, err := g.getPaymentIntent(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("amount_to_capture", strconv.FormatInt(toMinorUnits(amount, intent.Currency), 10))
+	response, err := g.post(ctx, "/v1/payment_intents/"+url.PathEscape(transactionID)+"/capture", form)
+	if err == nil && response.Status == PaymentStatusCompleted {
+		response.Status = PaymentStatusCaptured
+	}
+	return response, err
+}
+
+func (g *StripeGateway) Sale(ctx context.Context, request *PaymentRequest) (*PaymentResponse, error) {
+	return g.createPaymentIntent(ctx, request, "automatic")
+}
+
+func (g *StripeGateway) Refund(ctx context.Context, transactionID string, amount decimal.Decimal) (*PaymentResponse, error) {
+	intent, err := g.getPaymentIntent(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("payment_intent", transactionID)
+	form.Set("amount", strconv.FormatInt(toMinorUnits(amount, intent.Currency), 10))
+	return g.post(ctx, "/v1/refunds", form)
+}
+
+func (g *StripeGateway) Void(ctx context.Context, transactionID string) (*PaymentResponse, error) {
+	return g.post(ctx, "/v1/payment_intents/"+url.PathEscape(transactionID)+"/cancel", url.Values{})
+}
+
+func (g *StripeGateway) GetTransaction(ctx context.Context, transactionID string) (*PaymentResponse, error) {
+	intent, err := g.getPaymentIntent(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return intent.toPaymentResponse(), nil
+}
+
+func (g *StripeGateway) createPaymentIntent(ctx context.Context, request *PaymentRequest, captureMethod string) (*PaymentResponse, error) {
+	paymentMethod := request.Metadata["payment_method"]
+	if paymentMethod == "" {
+		return nil, NewDomainError("Stripe requires a payment method token")
+	}
+
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(toMinorUnits(request.Amount, request.Currency), 10))
+	form.Set("currency", strings.ToLower(request.Currency))
+	form.Set("payment_method", paymentMethod)
+	form.Set("capture_method", captureMethod)
+	form.Set("confirm", "true")
+	form.Set("metadata[order_id]", request.OrderID)
+	if request.Description != "" {
+		form.Set("description", request.Description)
+	}
+	if request.CustomerID != nil {
+		form.Set("metadata[customer_id]", *request.CustomerID)
+	}
+	return g.post(ctx, "/v1/payment_intents", form)
+}
+
+func (g *StripeGateway) getPaymentIntent(ctx context.Context, id string) (*stripeObject, error) {
+	resp, err := g.client.Do(ctx, &httpclient.Request{
+		Method: http.MethodGet,
+		Path:   "/v1/payment_intents/" + url.PathEscape(id),
+		Header: g.headers(),
+	})
+	if err != nil {
+		return nil, g.wrapError(err)
+	}
+	var intent stripeObject
+	if err := resp.DecodeJSON(&intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// post sends a form request with an idempotency key so retries never charge twice.
+// Card declines come back as a FAILED response rather than an error.
+func (g *StripeGateway) post(ctx context.Context, path string, form url.Values) (*PaymentResponse, error) {
+	header := g.headers()
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Idempotency-Key", uuid.New().String())
+
+	resp, err := g.client.Do(ctx, &httpclient.Request{
+		Method:     http.MethodPost,
+		Path:       path,
+		Header:     header,
+		Body:       []byte(form.Encode()),
+		Idempotent: true,
+	})
+	var statusErr *httpclient.StatusError
+	if errors.As(err, &statusErr) && statusErr.Response.StatusCode == http.StatusPaymentRequired {
+		return declinedResponse(statusErr.Response), nil
+	}
+	if err != nil {
+		return nil, g.wrapError(err)
+	}
+
+	var object stripeObject
+	if err := resp.DecodeJSON(&object); err != nil {
+		return nil, err
+	}
+	payment := object.toPaymentResponse()
+	payment.GatewayResponse = string(resp.Body)
+	return payment, nil
+}
+
+func (g *StripeGateway) headers() http.Header {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+g.config.APISecret)
+	return header
+}
+
+func (g *StripeGateway) wrapError(err error) error {
+	var statusErr *httpclient.StatusError
+	if errors.As(err, &statusErr) {
+		if apiErr := parseStripeError(statusErr.Response.Body); apiErr != nil && apiErr.Message != "" {
+			return NewDomainError("Stripe: " + apiErr.Message)
+		}
+	}
+	return err
+}
+
+// stripeObject holds the fields shared by payment intents and refunds
+type stripeObject struct {
+	ID               string       `json:"id"`
+	Object           string       `json:"object"`
+	Status           string       `json:"status"`
+	Amount           int64        `json:"amount"`
+	AmountReceived   int64        `json:"amount_received"`
+	Currency         string       `json:"currency"`
+	LatestCharge     string       `json:"latest_charge"`
+	LastPaymentError *stripeError `json:"last_payment_error"`
+}
+
+type stripeError struct {
+	Type        string `json:"type"`
+	Code        string `json:"code"`
+	DeclineCode string `json:"decline_code"`
+	Message     string `json:"message"`
+}
+
+func (o *stripeObject) toPaymentResponse() *PaymentResponse {
+	currency := strings.ToUpper(o.Currency)
+	response := &PaymentResponse{
+		TransactionID: o.ID,
+		Status:        stripeStatus(o.Object, o.Status),
+		Amount:        fromMinorUnits(o.Amount, currency),
+		Currency:      currency,
+		ProcessedAt:   time.Now(),
+		Metadata:      map[string]string{},
+	}
+	if o.Object == "refund" {
+		response.Metadata["refund_id"] = o.ID
+	}
+	if o.LatestCharge != "" {
+		response.Metadata["charge_id"] = o.LatestCharge
+	}
+	if o.LastPaymentError != nil {
+		response.ErrorCode = &o.LastPaymentError.Code
+		response.ErrorMessage = &o.LastPaymentError.Message
+	}
+	return response
+}
+
+func stripeStatus(object, status string) PaymentStatus {
+	if object == "refund" {
+		if status == "failed" || status == "canceled" {
+			return PaymentStatusFailed
+		}
+		return PaymentStatusRefunded
+	}
+	switch status {
+	case "requires_capture":
+		return PaymentStatusAuthorized
+	case "succeeded":
+		return PaymentStatusCompleted
+	case "processing":
+		return PaymentStatusProcessing
+	case "canceled":
+		return PaymentStatusCancelled
+	case "requires_payment_method":
+		return PaymentStatusFailed
+	default:
+		return PaymentStatusPending
+	}
+}
+
+func parseStripeError(body []byte) *stripeError {
+	var envelope struct {
+		Error *stripeError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil
+	}
+	return envelope.Error
+}
+
+func declinedResponse(resp *httpclient.Response) *PaymentResponse {
+	response := &PaymentResponse{
+		Status:          PaymentStatusFailed,
+		GatewayResponse: string(resp.Body),
+		ProcessedAt:     time.Now(),
+	}
+	if apiErr := parseStripeError(resp.Body); apiErr != nil {
+		code := apiErr.Code
+		if apiErr.DeclineCode != "" {
+			code = apiErr.DeclineCode
+		}
+		response.ErrorCode = &code
+		response.ErrorMessage = &apiErr.Message
+	}
+	return response
+}
+
+// toMinorUnits converts an amount to the smallest currency unit (cents for USD)
+func toMinorUnits(amount decimal.Decimal, currency string) int64 {
+	places := money.RuleFor(currency).DecimalPlaces
+	return amount.Shift(places).Round(0).IntPart()
+}
+
+// fromMinorUnits converts an amount in the smallest currency unit back to a decimal
+func fromMinorUnits(amount int64, currency string) decimal.Decimal {
+	return decimal.New(amount, -money.RuleFor(currency).DecimalPlaces)
+}
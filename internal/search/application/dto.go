@@ -0,0 +1,106 @@
+package application
+
+import (
+	"time"
+
+	"github.com/qhato/ecommerce/internal/search/domain"
+)
+
+// SearchConfigDTO represents the full search configuration
+type SearchConfigDTO struct {
+	SynonymGroups []*SynonymGroupDTO `json:"synonym_groups"`
+	Stopwords     []string           `json:"stopwords"`
+	FieldBoosts   map[string]float64 `json:"field_boosts"`
+	PinnedQueries []*PinnedQueryDTO  `json:"pinned_queries"`
+}
+
+// SynonymGroupDTO represents a synonym group
+type SynonymGroupDTO struct {
+	ID        int64     `json:"id"`
+	Terms     []string  `json:"terms"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PinnedQueryDTO represents products pinned to a query
+type PinnedQueryDTO struct {
+	ID         int64     `json:"id"`
+	Query      string    `json:"query"`
+	ProductIDs []int64   `json:"product_ids"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// AnalyzedQueryDTO shows how a query is interpreted at search time
+type AnalyzedQueryDTO struct {
+	Query            string             `json:"query"`
+	TermGroups       [][]string         `json:"term_groups"`
+	FieldBoosts      map[string]float64 `json:"field_boosts"`
+	PinnedProductIDs []int64            `json:"pinned_product_ids"`
+}
+
+// SynonymGroupRequest is the payload to create or update a synonym group
+type SynonymGroupRequest struct {
+	Terms []string `json:"terms" validate:"required,min=2"`
+}
+
+// StopwordsRequest is the payload to replace the stopword list
+type StopwordsRequest struct {
+	Stopwords []string `json:"stopwords"`
+}
+
+// FieldBoostRequest is the payload to set a field boost
+type FieldBoostRequest struct {
+	Field string  `json:"field" validate:"required"`
+	Boost float64 `json:"boost" validate:"gte=0"`
+}
+
+// PinnedQueryRequest is the payload to pin products to a query
+type PinnedQueryRequest struct {
+	Query      string  `json:"query" validate:"required"`
+	ProductIDs []int64 `json:"product_ids" validate:"required,min=1"`
+}
+
+// ToSearchConfigDTO converts a domain configuration to a DTO
+func ToSearchConfigDTO(config *domain.SearchConfiguration) *SearchConfigDTO {
+	dto := &SearchConfigDTO{
+		SynonymGroups: make([]*SynonymGroupDTO, len(config.SynonymGroups)),
+		Stopwords:     config.Stopwords,
+		FieldBoosts:   config.FieldBoosts,
+		PinnedQueries: make([]*PinnedQueryDTO, len(config.PinnedQueries)),
+	}
+	for i, g := range config.SynonymGroups {
+		dto.SynonymGroups[i] = ToSynonymGroupDTO(g)
+	}
+	for i, p := range config.PinnedQueries {
+		dto.PinnedQueries[i] = ToPinnedQueryDTO(p)
+	}
+	return dto
+}
+
+// ToSynonymGroupDTO converts a domain synonym group to a DTO
+func ToSynonymGroupDTO(group *domain.SynonymGroup) *SynonymGroupDTO {
+	return &SynonymGroupDTO{
+		ID:        group.ID,
+		Terms:     group.Terms,
+		UpdatedAt: group.UpdatedAt,
+	}
+}
+
+// ToPinnedQueryDTO converts a domain pinned query to a DTO
+func ToPinnedQueryDTO(pinned *domain.PinnedQuery) *PinnedQueryDTO {
+	return &PinnedQueryDTO{
+		ID:         pinned.ID,
+		Query:      pinned.Query,
+		ProductIDs: pinned.ProductIDs,
+		UpdatedAt:  pinned.UpdatedAt,
+	}
+}
+
+// ToAnalyzedQueryDTO converts an analyzed query to a DTO
+func ToAnalyzedQueryDTO(analyzed *domain.AnalyzedQuery) *AnalyzedQueryDTO {
+	return &AnalyzedQueryDTO{
+		Query:            analyzed.Original,
+		TermGroups:       analyzed.TermGroups,
+		FieldBoosts:      analyzed.FieldBoosts,
+		PinnedProductIDs: analyzed.PinnedProductIDs,
+	}
+}
@@ -0,0 +1,206 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	catalogDomain "github.com/qhato/ecommerce/internal/catalog/domain"
+	"github.com/qhato/ecommerce/internal/search/domain"
+	"github.com/qhato/ecommerce/pkg/cache"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const searchConfigCacheTTL = 10 * time.Minute
+
+var searchConfigCacheKey = cache.NewKeyBuilder("search", "config", 1).Key()
+
+// SearchConfigService defines the application service for query-time search configuration.
+type SearchConfigService interface {
+	// GetConfiguration returns the current search configuration.
+	GetConfiguration(ctx context.Context) (*SearchConfigDTO, error)
+
+	// SaveSynonymGroup creates a synonym group, or updates it when id is non-zero.
+	SaveSynonymGroup(ctx context.Context, id int64, req *SynonymGroupRequest) (*SynonymGroupDTO, error)
+
+	// DeleteSynonymGroup removes a synonym group.
+	DeleteSynonymGroup(ctx context.Context, id int64) error
+
+	// SetStopwords replaces the stopword list.
+	SetStopwords(ctx context.Context, req *StopwordsRequest) error
+
+	// SetFieldBoost sets the relevance boost of a searchable field.
+	SetFieldBoost(ctx context.Context, req *FieldBoostRequest) error
+
+	// PinProducts pins products to the top of the results of a query.
+	PinProducts(ctx context.Context, req *PinnedQueryRequest) (*PinnedQueryDTO, error)
+
+	// DeletePinnedQuery removes a pinned query.
+	DeletePinnedQuery(ctx context.Context, id int64) error
+
+	// PreviewQuery shows how a query is interpreted with the current configuration.
+	PreviewQuery(ctx context.Context, query string) (*AnalyzedQueryDTO, error)
+
+	// AnalyzeSearchQuery converts a raw query into catalog search criteria.
+	AnalyzeSearchQuery(ctx context.Context, query string) (*catalogDomain.SearchCriteria, error)
+}
+
+type searchConfigService struct {
+	repo     domain.SearchConfigRepository
+	cache    cache.Cache
+	eventBus event.Bus
+	log      *logger.Logger
+}
+
+// NewSearchConfigService creates a new instance of SearchConfigService.
+func NewSearchConfigService(repo domain.SearchConfigRepository, cache cache.Cache, eventBus event.Bus, log *logger.Logger) SearchConfigService {
+	return &searchConfigService{
+		repo:     repo,
+		cache:    cache,
+		eventBus: eventBus,
+		log:      log,
+	}
+}
+
+func (s *searchConfigService) GetConfiguration(ctx context.Context) (*SearchConfigDTO, error) {
+	config, err := s.loadConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ToSearchConfigDTO(config), nil
+}
+
+func (s *searchConfigService) SaveSynonymGroup(ctx context.Context, id int64, req *SynonymGroupRequest) (*SynonymGroupDTO, error) {
+	group, err := domain.NewSynonymGroup(req.Terms)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	group.ID = id
+
+	if err := s.repo.SaveSynonymGroup(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to save synonym group: %w", err)
+	}
+	s.configChanged(ctx, "synonyms")
+	return ToSynonymGroupDTO(group), nil
+}
+
+func (s *searchConfigService) DeleteSynonymGroup(ctx context.Context, id int64) error {
+	if err := s.repo.DeleteSynonymGroup(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete synonym group: %w", err)
+	}
+	s.configChanged(ctx, "synonyms")
+	return nil
+}
+
+func (s *searchConfigService) SetStopwords(ctx context.Context, req *StopwordsRequest) error {
+	stopwords := make([]string, 0, len(req.Stopwords))
+	seen := make(map[string]bool, len(req.Stopwords))
+	for _, word := range req.Stopwords {
+		word = domain.NormalizeQuery(word)
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		stopwords = append(stopwords, word)
+	}
+
+	if err := s.repo.ReplaceStopwords(ctx, stopwords); err != nil {
+		return fmt.Errorf("failed to save stopwords: %w", err)
+	}
+	s.configChanged(ctx, "stopwords")
+	return nil
+}
+
+func (s *searchConfigService) SetFieldBoost(ctx context.Context, req *FieldBoostRequest) error {
+	if !domain.IsSearchableField(req.Field) {
+		return errors.ValidationError(fmt.Sprintf("field %q is not searchable", req.Field))
+	}
+	if req.Boost < 0 {
+		return errors.ValidationError("boost cannot be negative")
+	}
+
+	if err := s.repo.SaveFieldBoost(ctx, req.Field, req.Boost); err != nil {
+		return fmt.Errorf("failed to save field boost: %w", err)
+	}
+	s.configChanged(ctx, "boosts")
+	return nil
+}
+
+func (s *searchConfigService) PinProducts(ctx context.Context, req *PinnedQueryRequest) (*PinnedQueryDTO, error) {
+	pinned, err := domain.NewPinnedQuery(req.Query, req.ProductIDs)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if err := s.repo.SavePinnedQuery(ctx, pinned); err != nil {
+		return nil, fmt.Errorf("failed to save pinned query: %w", err)
+	}
+	s.configChanged(ctx, "pins")
+	return ToPinnedQueryDTO(pinned), nil
+}
+
+func (s *searchConfigService) DeletePinnedQuery(ctx context.Context, id int64) error {
+	if err := s.repo.DeletePinnedQuery(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete pinned query: %w", err)
+	}
+	s.configChanged(ctx, "pins")
+	return nil
+}
+
+func (s *searchConfigService) PreviewQuery(ctx context.Context, query string) (*AnalyzedQueryDTO, error) {
+	config, err := s.loadConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ToAnalyzedQueryDTO(config.Analyze(query)), nil
+}
+
+func (s *searchConfigService) AnalyzeSearchQuery(ctx context.Context, query string) (*catalogDomain.SearchCriteria, error) {
+	config, err := s.loadConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzed := config.Analyze(query)
+	return &catalogDomain.SearchCriteria{
+		TermGroups:       analyzed.TermGroups,
+		FieldBoosts:      analyzed.FieldBoosts,
+		PinnedProductIDs: analyzed.PinnedProductIDs,
+	}, nil
+}
+
+// loadConfiguration reads the configuration through the cache
+func (s *searchConfigService) loadConfiguration(ctx context.Context) (*domain.SearchConfiguration, error) {
+	if cached, err := s.cache.Get(ctx, searchConfigCacheKey); err == nil && len(cached) > 0 {
+		var config domain.SearchConfiguration
+		if err := json.Unmarshal(cached, &config); err == nil {
+			return &config, nil
+		}
+	}
+
+	config, err := s.repo.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load search configuration: %w", err)
+	}
+
+	if data, err := json.Marshal(config); err == nil {
+		if err := s.cache.Set(ctx, searchConfigCacheKey, data, searchConfigCacheTTL); err != nil {
+			s.log.WithError(err).Warn("failed to cache search configuration")
+		}
+	}
+	return config, nil
+}
+
+// configChanged invalidates the cached configuration and notifies subscribers
+func (s *searchConfigService) configChanged(ctx context.Context, section string) {
+	if err := s.cache.Delete(ctx, searchConfigCacheKey); err != nil {
+		s.log.WithError(err).Warn("failed to invalidate search configuration cache")
+	}
+	if err := s.eventBus.Publish(ctx, domain.NewSearchConfigChangedEvent(section)); err != nil {
+		s.log.WithError(err).Error("failed to publish search config changed event")
+	}
+	s.log.WithField("section", section).Info("search configuration changed")
+}
@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/event"
+)
+
+// Searchable fields that accept a boost
+const (
+	FieldModel           = "model"
+	FieldManufacture     = "manufacture"
+	FieldMetaTitle       = "meta_title"
+	FieldMetaDescription = "meta_desc"
+)
+
+// SearchableFields lists the fields a boost can be configured for
+var SearchableFields = []string{FieldModel, FieldManufacture, FieldMetaTitle, FieldMetaDescription}
+
+// EventSearchConfigChanged is published whenever the search configuration changes
+const EventSearchConfigChanged = "search.config.changed"
+
+func init() {
+	event.RegisterType(EventSearchConfigChanged, func() event.Event { return &SearchConfigChangedEvent{} })
+}
+
+// SynonymGroup is a set of terms treated as equivalent at query time
+type SynonymGroup struct {
+	ID        int64
+	Terms     []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewSynonymGroup creates a new synonym group
+func NewSynonymGroup(terms []string) (*SynonymGroup, error) {
+	normalized := normalizeTerms(terms)
+	if len(normalized) < 2 {
+		return nil, NewDomainError("a synonym group needs at least two distinct terms")
+	}
+	now := time.Now()
+	return &SynonymGroup{
+		Terms:     normalized,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// PinnedQuery promotes specific products to the top of results for an exact query
+type PinnedQuery struct {
+	ID         int64
+	Query      string
+	ProductIDs []int64 // In display order
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewPinnedQuery creates a new pinned query
+func NewPinnedQuery(query string, productIDs []int64) (*PinnedQuery, error) {
+	normalized := NormalizeQuery(query)
+	if normalized == "" {
+		return nil, NewDomainError("pinned query cannot be empty")
+	}
+	if len(productIDs) == 0 {
+		return nil, NewDomainError("pinned query needs at least one product")
+	}
+	now := time.Now()
+	return &PinnedQuery{
+		Query:      normalized,
+		ProductIDs: productIDs,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// SearchConfiguration holds all admin-managed query-time search settings
+type SearchConfiguration struct {
+	SynonymGroups []*SynonymGroup
+	Stopwords     []string
+	FieldBoosts   map[string]float64
+	PinnedQueries []*PinnedQuery
+}
+
+// AnalyzedQuery is a query after stopword removal and synonym expansion.
+// Each entry in TermGroups must match; any alternative within a group may match.
+type AnalyzedQuery struct {
+	Original         string
+	TermGroups       [][]string
+	FieldBoosts      map[string]float64
+	PinnedProductIDs []int64
+}
+
+// Analyze applies stopwords, synonyms, boosts and pins to a raw query
+func (c *SearchConfiguration) Analyze(query string) *AnalyzedQuery {
+	normalized := NormalizeQuery(query)
+	analyzed := &AnalyzedQuery{
+		Original:    query,
+		TermGroups:  make([][]string, 0),
+		FieldBoosts: c.boosts(),
+	}
+
+	stopwords := make(map[string]bool, len(c.Stopwords))
+	for _, word := range c.Stopwords {
+		stopwords[word] = true
+	}
+
+	synonyms := make(map[string][]string)
+	for _, group := range c.SynonymGroups {
+		for _, term := range group.Terms {
+			synonyms[term] = append(synonyms[term], group.Terms...)
+		}
+	}
+
+	for _, term := range strings.Fields(normalized) {
+		if stopwords[term] {
+			continue
+		}
+		alternatives := []string{term}
+		for _, synonym := range synonyms[term] {
+			if !contains(alternatives, synonym) {
+				alternatives = append(alternatives, synonym)
+			}
+		}
+		analyzed.TermGroups = append(analyzed.TermGroups, alternatives)
+	}
+
+	// A query made only of stopwords still searches for the raw text
+	if len(analyzed.TermGroups) == 0 && normalized != "" {
+		analyzed.TermGroups = append(analyzed.TermGroups, []string{normalized})
+	}
+
+	for _, pinned := range c.PinnedQueries {
+		if pinned.Query == normalized {
+			analyzed.PinnedProductIDs = pinned.ProductIDs
+			break
+		}
+	}
+
+	return analyzed
+}
+
+// boosts returns the configured boosts with a default of 1 for unset fields
+func (c *SearchConfiguration) boosts() map[string]float64 {
+	boosts := make(map[string]float64, len(SearchableFields))
+	for _, field := range SearchableFields {
+		boosts[field] = 1
+	}
+	for field, boost := range c.FieldBoosts {
+		boosts[field] = boost
+	}
+	return boosts
+}
+
+// IsSearchableField checks if a field accepts a boost
+func IsSearchableField(field string) bool {
+	return contains(SearchableFields, field)
+}
+
+// NormalizeQuery lowercases and collapses whitespace
+func NormalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+func normalizeTerms(terms []string) []string {
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = NormalizeQuery(term)
+		if term != "" && !contains(normalized, term) {
+			normalized = append(normalized, term)
+		}
+	}
+	return normalized
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchConfigChangedEvent is published when synonyms, stopwords, boosts or pins change
+type SearchConfigChangedEvent struct {
+	event.BaseEvent
+	Section string `json:"section"`
+}
+
+// NewSearchConfigChangedEvent creates a new SearchConfigChangedEvent
+func NewSearchConfigChangedEvent(section string) *SearchConfigChangedEvent {
+	return &SearchConfigChangedEvent{
+		BaseEvent: event.NewBaseEvent(EventSearchConfigChanged, section, nil),
+		Section:   section,
+	}
+}
+
+// SearchConfigRepository defines the interface for search configuration persistence
+type SearchConfigRepository interface {
+	// Load retrieves the full search configuration
+	Load(ctx context.Context) (*SearchConfiguration, error)
+
+	// SaveSynonymGroup creates or updates a synonym group
+	SaveSynonymGroup(ctx context.Context, group *SynonymGroup) error
+
+	// DeleteSynonymGroup removes a synonym group
+	DeleteSynonymGroup(ctx context.Context, id int64) error
+
+	// ReplaceStopwords replaces the full stopword list
+	ReplaceStopwords(ctx context.Context, stopwords []string) error
+
+	// SaveFieldBoost sets the boost of a searchable field
+	SaveFieldBoost(ctx context.Context, field string, boost float64) error
+
+	// SavePinnedQuery creates or updates a pinned query
+	SavePinnedQuery(ctx context.Context, pinned *PinnedQuery) error
+
+	// DeletePinnedQuery removes a pinned query
+	DeletePinnedQuery(ctx context.Context, id int64) error
+}
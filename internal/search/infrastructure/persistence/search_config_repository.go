@@ -0,0 +1,204 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/search/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresSearchConfigRepository implements the SearchConfigRepository interface
+type PostgresSearchConfigRepository struct {
+	db *database.DB
+}
+
+// NewPostgresSearchConfigRepository creates a new PostgresSearchConfigRepository
+func NewPostgresSearchConfigRepository(db *database.DB) *PostgresSearchConfigRepository {
+	return &PostgresSearchConfigRepository{db: db}
+}
+
+// Load retrieves the full search configuration
+func (r *PostgresSearchConfigRepository) Load(ctx context.Context) (*domain.SearchConfiguration, error) {
+	config := &domain.SearchConfiguration{
+		SynonymGroups: make([]*domain.SynonymGroup, 0),
+		Stopwords:     make([]string, 0),
+		FieldBoosts:   make(map[string]float64),
+		PinnedQueries: make([]*domain.PinnedQuery, 0),
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT synonym_group_id, terms, created_at, updated_at
+		FROM search_synonym_group
+		ORDER BY synonym_group_id`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to load synonym groups")
+	}
+	for rows.Next() {
+		group := &domain.SynonymGroup{}
+		if err := rows.Scan(&group.ID, &group.Terms, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, errors.InternalWrap(err, "failed to scan synonym group")
+		}
+		config.SynonymGroups = append(config.SynonymGroups, group)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate synonym groups")
+	}
+
+	rows, err = r.db.Query(ctx, `SELECT word FROM search_stopword ORDER BY word`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to load stopwords")
+	}
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			rows.Close()
+			return nil, errors.InternalWrap(err, "failed to scan stopword")
+		}
+		config.Stopwords = append(config.Stopwords, word)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate stopwords")
+	}
+
+	rows, err = r.db.Query(ctx, `SELECT field_name, boost::float8 FROM search_field_boost`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to load field boosts")
+	}
+	for rows.Next() {
+		var field string
+		var boost float64
+		if err := rows.Scan(&field, &boost); err != nil {
+			rows.Close()
+			return nil, errors.InternalWrap(err, "failed to scan field boost")
+		}
+		config.FieldBoosts[field] = boost
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate field boosts")
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT pinned_query_id, query, product_ids, created_at, updated_at
+		FROM search_pinned_query
+		ORDER BY query`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to load pinned queries")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		pinned := &domain.PinnedQuery{}
+		if err := rows.Scan(&pinned.ID, &pinned.Query, &pinned.ProductIDs, &pinned.CreatedAt, &pinned.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan pinned query")
+		}
+		config.PinnedQueries = append(config.PinnedQueries, pinned)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate pinned queries")
+	}
+
+	return config, nil
+}
+
+// SaveSynonymGroup creates or updates a synonym group
+func (r *PostgresSearchConfigRepository) SaveSynonymGroup(ctx context.Context, group *domain.SynonymGroup) error {
+	if group.ID == 0 {
+		query := `
+			INSERT INTO search_synonym_group (terms, created_at, updated_at)
+			VALUES ($1, $2, $3)
+			RETURNING synonym_group_id`
+		if err := r.db.QueryRow(ctx, query, group.Terms, group.CreatedAt, group.UpdatedAt).Scan(&group.ID); err != nil {
+			return errors.InternalWrap(err, "failed to create synonym group")
+		}
+		return nil
+	}
+
+	query := `
+		UPDATE search_synonym_group
+		SET terms = $2, updated_at = $3
+		WHERE synonym_group_id = $1
+		RETURNING created_at`
+	err := r.db.QueryRow(ctx, query, group.ID, group.Terms, group.UpdatedAt).Scan(&group.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return errors.NotFound("synonym group")
+	}
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update synonym group")
+	}
+	return nil
+}
+
+// DeleteSynonymGroup removes a synonym group
+func (r *PostgresSearchConfigRepository) DeleteSynonymGroup(ctx context.Context, id int64) error {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM search_synonym_group WHERE synonym_group_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete synonym group")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("synonym group")
+	}
+	return nil
+}
+
+// ReplaceStopwords replaces the full stopword list
+func (r *PostgresSearchConfigRepository) ReplaceStopwords(ctx context.Context, stopwords []string) error {
+	return r.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM search_stopword`); err != nil {
+			return errors.InternalWrap(err, "failed to clear stopwords")
+		}
+		if len(stopwords) == 0 {
+			return nil
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO search_stopword (word) SELECT unnest($1::text[])`, stopwords); err != nil {
+			return errors.InternalWrap(err, "failed to insert stopwords")
+		}
+		return nil
+	})
+}
+
+// SaveFieldBoost sets the boost of a searchable field
+func (r *PostgresSearchConfigRepository) SaveFieldBoost(ctx context.Context, field string, boost float64) error {
+	query := `
+		INSERT INTO search_field_boost (field_name, boost)
+		VALUES ($1, $2)
+		ON CONFLICT (field_name) DO UPDATE SET boost = EXCLUDED.boost`
+	if err := r.db.Exec(ctx, query, field, boost); err != nil {
+		return errors.InternalWrap(err, "failed to save field boost")
+	}
+	return nil
+}
+
+// SavePinnedQuery creates or updates the pinned products of a query
+func (r *PostgresSearchConfigRepository) SavePinnedQuery(ctx context.Context, pinned *domain.PinnedQuery) error {
+	query := `
+		INSERT INTO search_pinned_query (query, product_ids, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (query) DO UPDATE SET
+			product_ids = EXCLUDED.product_ids,
+			updated_at = EXCLUDED.updated_at
+		RETURNING pinned_query_id, created_at`
+	err := r.db.QueryRow(ctx, query, pinned.Query, pinned.ProductIDs, pinned.CreatedAt, pinned.UpdatedAt).
+		Scan(&pinned.ID, &pinned.CreatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save pinned query")
+	}
+	return nil
+}
+
+// DeletePinnedQuery removes a pinned query
+func (r *PostgresSearchConfigRepository) DeletePinnedQuery(ctx context.Context, id int64) error {
+	result, err := r.db.Pool().Exec(ctx, `DELETE FROM search_pinned_query WHERE pinned_query_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete pinned query")
+	}
+	if result.RowsAffected() == 0 {
+		return errors.NotFound("pinned query")
+	}
+	return nil
+}
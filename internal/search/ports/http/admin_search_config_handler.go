@@ -0,0 +1,198 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/search/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminSearchConfigHandler handles admin search configuration requests
+type AdminSearchConfigHandler struct {
+	configService application.SearchConfigService
+	logger        *logger.Logger
+}
+
+// NewAdminSearchConfigHandler creates a new admin search configuration handler
+func NewAdminSearchConfigHandler(configService application.SearchConfigService, logger *logger.Logger) *AdminSearchConfigHandler {
+	return &AdminSearchConfigHandler{
+		configService: configService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes registers admin search configuration routes
+func (h *AdminSearchConfigHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/search/config", func(r chi.Router) {
+		r.Get("/", h.GetConfiguration)
+		r.Get("/preview", h.PreviewQuery)
+		r.Post("/synonyms", h.CreateSynonymGroup)
+		r.Put("/synonyms/{id}", h.UpdateSynonymGroup)
+		r.Delete("/synonyms/{id}", h.DeleteSynonymGroup)
+		r.Put("/stopwords", h.SetStopwords)
+		r.Put("/boosts", h.SetFieldBoost)
+		r.Post("/pins", h.PinProducts)
+		r.Delete("/pins/{id}", h.DeletePinnedQuery)
+	})
+}
+
+// GetConfiguration returns synonyms, stopwords, boosts and pinned queries
+func (h *AdminSearchConfigHandler) GetConfiguration(w http.ResponseWriter, r *http.Request) {
+	config, err := h.configService.GetConfiguration(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to get search configuration")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, config)
+}
+
+// PreviewQuery shows how a query is analyzed with the current configuration
+func (h *AdminSearchConfigHandler) PreviewQuery(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("query parameter 'q' is required"))
+		return
+	}
+
+	analyzed, err := h.configService.PreviewQuery(r.Context(), query)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to preview search query")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, analyzed)
+}
+
+// CreateSynonymGroup creates a synonym group
+func (h *AdminSearchConfigHandler) CreateSynonymGroup(w http.ResponseWriter, r *http.Request) {
+	var req application.SynonymGroupRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	group, err := h.configService.SaveSynonymGroup(r.Context(), 0, &req)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to create synonym group")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, group)
+}
+
+// UpdateSynonymGroup replaces the terms of a synonym group
+func (h *AdminSearchConfigHandler) UpdateSynonymGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid synonym group ID"))
+		return
+	}
+
+	var req application.SynonymGroupRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	group, err := h.configService.SaveSynonymGroup(r.Context(), id, &req)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to update synonym group")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, group)
+}
+
+// DeleteSynonymGroup removes a synonym group
+func (h *AdminSearchConfigHandler) DeleteSynonymGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid synonym group ID"))
+		return
+	}
+
+	if err := h.configService.DeleteSynonymGroup(r.Context(), id); err != nil {
+		h.logger.WithError(err).Error("failed to delete synonym group")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetStopwords replaces the stopword list
+func (h *AdminSearchConfigHandler) SetStopwords(w http.ResponseWriter, r *http.Request) {
+	var req application.StopwordsRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	if err := h.configService.SetStopwords(r.Context(), &req); err != nil {
+		h.logger.WithError(err).Error("failed to set stopwords")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetFieldBoost sets the relevance boost of a searchable field
+func (h *AdminSearchConfigHandler) SetFieldBoost(w http.ResponseWriter, r *http.Request) {
+	var req application.FieldBoostRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	if err := h.configService.SetFieldBoost(r.Context(), &req); err != nil {
+		h.logger.WithError(err).Error("failed to set field boost")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PinProducts pins products to the top of a query's results
+func (h *AdminSearchConfigHandler) PinProducts(w http.ResponseWriter, r *http.Request) {
+	var req application.PinnedQueryRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pinned, err := h.configService.PinProducts(r.Context(), &req)
+	if err != nil {
+		h.logger.WithError(err).Error("failed to pin products")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, pinned)
+}
+
+// DeletePinnedQuery removes a pinned query
+func (h *AdminSearchConfigHandler) DeletePinnedQuery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid pinned query ID"))
+		return
+	}
+
+	if err := h.configService.DeletePinnedQuery(r.Context(), id); err != nil {
+		h.logger.WithError(err).Error("failed to delete pinned query")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
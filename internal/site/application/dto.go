@@ -0,0 +1,46 @@
+package application
+
+import (
+	"time"
+
+	"github.com/qhato/ecommerce/internal/site/domain"
+)
+
+// SiteDomainDTO represents a custom domain of a site
+type SiteDomainDTO struct {
+	ID                   int64      `json:"id"`
+	SiteID               string     `json:"site_id"`
+	Hostname             string     `json:"hostname"`
+	Status               string     `json:"status"`
+	VerificationRecord   string     `json:"verification_record"` // DNS TXT record name
+	VerificationValue    string     `json:"verification_value"`  // Expected TXT record value
+	VerifiedAt           *time.Time `json:"verified_at,omitempty"`
+	LastCheckedAt        *time.Time `json:"last_checked_at,omitempty"`
+	LastCheckError       string     `json:"last_check_error,omitempty"`
+	CertificateExpiresAt *time.Time `json:"certificate_expires_at,omitempty"` // Set once a certificate was issued
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// AddSiteDomainRequest is the payload to register a custom domain
+type AddSiteDomainRequest struct {
+	SiteID   string `json:"site_id" validate:"required"`
+	Hostname string `json:"hostname" validate:"required"`
+}
+
+// ToSiteDomainDTO converts a domain to its DTO
+func ToSiteDomainDTO(d *domain.SiteDomain) *SiteDomainDTO {
+	return &SiteDomainDTO{
+		ID:                 d.ID,
+		SiteID:             d.SiteID,
+		Hostname:           d.Hostname,
+		Status:             string(d.Status),
+		VerificationRecord: d.VerificationRecord(),
+		VerificationValue:  d.VerificationToken,
+		VerifiedAt:         d.VerifiedAt,
+		LastCheckedAt:      d.LastCheckedAt,
+		LastCheckError:     d.LastCheckError,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
+	}
+}
@@ -0,0 +1,297 @@
+package application
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/qhato/ecommerce/internal/site/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// TXTResolver looks up DNS TXT records; net.DefaultResolver implements it.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// SiteDomainService registers custom domains of storefront sites, verifies
+// them by DNS and tells the storefront which hosts it serves and may request
+// certificates for.
+type SiteDomainService interface {
+	// AddDomain registers a pending domain of a site.
+	AddDomain(ctx context.Context, req *AddSiteDomainRequest) (*SiteDomainDTO, error)
+
+	// VerifyDomain checks the verification TXT record of a domain.
+	VerifyDomain(ctx context.Context, id int64) (*SiteDomainDTO, error)
+
+	// RemoveDomain removes a domain and its cached certificates.
+	RemoveDomain(ctx context.Context, id int64) error
+
+	// GetDomain retrieves a domain.
+	GetDomain(ctx context.Context, id int64) (*SiteDomainDTO, error)
+
+	// ListDomains lists the domains of a site, or of every site when siteID is empty.
+	ListDomains(ctx context.Context, siteID string) ([]*SiteDomainDTO, error)
+
+	// HostPolicy is the autocert host policy: only verified domains get certificates.
+	HostPolicy(ctx context.Context, host string) error
+
+	// SiteForHost returns the site served on a host, "" when the host is not a verified domain.
+	SiteForHost(host string) string
+
+	// Refresh reloads the verified domains SiteForHost knows.
+	Refresh(ctx context.Context) error
+
+	// StartScheduledRefresh refreshes the verified domains periodically until ctx is cancelled.
+	StartScheduledRefresh(ctx context.Context, interval time.Duration)
+}
+
+type siteDomainService struct {
+	repo         domain.SiteDomainRepository
+	certificates domain.CertificateCache
+	resolver     TXTResolver
+	sites        map[string]bool // Sites domains can be added to; empty accepts any
+	log          *logger.Logger
+
+	mu    sync.RWMutex
+	hosts map[string]string // Verified hostname -> site
+}
+
+// NewSiteDomainService creates a new instance of SiteDomainService. sites
+// lists the sites domains can be added to; certificates may be nil when
+// certificates are not managed.
+func NewSiteDomainService(
+	repo domain.SiteDomainRepository,
+	certificates domain.CertificateCache,
+	resolver TXTResolver,
+	sites []string,
+	log *logger.Logger,
+) SiteDomainService {
+	known := make(map[string]bool, len(sites))
+	for _, site := range sites {
+		known[site] = true
+	}
+	return &siteDomainService{
+		repo:         repo,
+		certificates: certificates,
+		resolver:     resolver,
+		sites:        known,
+		log:          log,
+		hosts:        make(map[string]string),
+	}
+}
+
+func (s *siteDomainService) AddDomain(ctx context.Context, req *AddSiteDomainRequest) (*SiteDomainDTO, error) {
+	if req.SiteID == "" {
+		return nil, errors.ValidationError("site ID is required")
+	}
+	if len(s.sites) > 0 && !s.sites[req.SiteID] {
+		return nil, errors.ValidationError(fmt.Sprintf("unknown site %s", req.SiteID))
+	}
+	hostname, err := domain.NormalizeHostname(req.Hostname)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	d, err := domain.NewSiteDomain(req.SiteID, hostname)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, d); err != nil {
+		return nil, err
+	}
+	s.log.WithFields(logger.Fields{"site_id": d.SiteID, "hostname": d.Hostname}).Info("Site domain added")
+	return ToSiteDomainDTO(d), nil
+}
+
+func (s *siteDomainService) VerifyDomain(ctx context.Context, id int64) (*SiteDomainDTO, error) {
+	d, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason := s.checkRecord(ctx, d); reason != "" {
+		d.MarkCheckFailed(reason)
+	} else {
+		d.MarkVerified()
+	}
+	if err := s.repo.Update(ctx, d); err != nil {
+		return nil, err
+	}
+
+	if d.LastCheckError != "" {
+		s.log.WithFields(logger.Fields{"hostname": d.Hostname, "reason": d.LastCheckError}).Info("Site domain verification failed")
+		return s.toDTO(ctx, d), nil
+	}
+	s.mu.Lock()
+	s.hosts[d.Hostname] = d.SiteID
+	s.mu.Unlock()
+	s.log.WithFields(logger.Fields{"site_id": d.SiteID, "hostname": d.Hostname}).Info("Site domain verified")
+	return s.toDTO(ctx, d), nil
+}
+
+// checkRecord looks for the token in the verification TXT record, returning
+// why verification failed or "" when it passed
+func (s *siteDomainService) checkRecord(ctx context.Context, d *domain.SiteDomain) string {
+	records, err := s.resolver.LookupTXT(ctx, d.VerificationRecord())
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return fmt.Sprintf("TXT record %s not found", d.VerificationRecord())
+		}
+		return fmt.Sprintf("failed to look up TXT record %s: %v", d.VerificationRecord(), err)
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == d.VerificationToken {
+			return ""
+		}
+	}
+	return fmt.Sprintf("TXT record %s does not contain the verification value", d.VerificationRecord())
+}
+
+func (s *siteDomainService) RemoveDomain(ctx context.Context, id int64) error {
+	d, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.hosts, d.Hostname)
+	s.mu.Unlock()
+	if s.certificates != nil {
+		// autocert stores ECDSA certificates under the hostname and RSA ones with a +rsa suffix
+		for _, key := range []string{d.Hostname, d.Hostname + "+rsa"} {
+			if err := s.certificates.Delete(ctx, key); err != nil {
+				s.log.WithError(err).WithField("hostname", d.Hostname).Warn("failed to delete cached certificate")
+			}
+		}
+	}
+	s.log.WithFields(logger.Fields{"site_id": d.SiteID, "hostname": d.Hostname}).Info("Site domain removed")
+	return nil
+}
+
+func (s *siteDomainService) GetDomain(ctx context.Context, id int64) (*SiteDomainDTO, error) {
+	d, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toDTO(ctx, d), nil
+}
+
+func (s *siteDomainService) ListDomains(ctx context.Context, siteID string) ([]*SiteDomainDTO, error) {
+	domains, err := s.repo.FindAll(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*SiteDomainDTO, 0, len(domains))
+	for _, d := range domains {
+		dtos = append(dtos, s.toDTO(ctx, d))
+	}
+	return dtos, nil
+}
+
+// HostPolicy reads the domain from the repository rather than the refreshed
+// hosts, so a domain verified on the admin is served on its first handshake
+func (s *siteDomainService) HostPolicy(ctx context.Context, host string) error {
+	d, err := s.repo.FindByHostname(ctx, normalizeHost(host))
+	if err != nil {
+		return err
+	}
+	if d == nil || !d.IsVerified() {
+		return fmt.Errorf("host %q is not a verified site domain", host)
+	}
+	return nil
+}
+
+func (s *siteDomainService) SiteForHost(host string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.hosts[normalizeHost(host)]
+}
+
+func (s *siteDomainService) Refresh(ctx context.Context) error {
+	domains, err := s.repo.FindVerified(ctx)
+	if err != nil {
+		return err
+	}
+	hosts := make(map[string]string, len(domains))
+	for _, d := range domains {
+		hosts[d.Hostname] = d.SiteID
+	}
+
+	s.mu.Lock()
+	s.hosts = hosts
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *siteDomainService) StartScheduledRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Refresh(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled site domain refresh failed")
+				}
+			}
+		}
+	}()
+}
+
+// toDTO converts a domain, with the expiry of its cached certificate
+func (s *siteDomainService) toDTO(ctx context.Context, d *domain.SiteDomain) *SiteDomainDTO {
+	dto := ToSiteDomainDTO(d)
+	if s.certificates == nil || !d.IsVerified() {
+		return dto
+	}
+	data, err := s.certificates.Get(ctx, d.Hostname)
+	if err != nil {
+		if err != autocert.ErrCacheMiss {
+			s.log.WithError(err).WithField("hostname", d.Hostname).Warn("failed to read cached certificate")
+		}
+		return dto
+	}
+	dto.CertificateExpiresAt = certificateExpiry(data)
+	return dto
+}
+
+// certificateExpiry returns when the leaf of a cached PEM key and chain expires
+func certificateExpiry(data []byte) *time.Time {
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil
+		}
+		return &cert.NotAfter
+	}
+	return nil
+}
+
+// normalizeHost drops the port and trailing dot of a Host header or SNI name
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// VerificationRecordPrefix is prepended to a hostname to name the DNS TXT
+// record proving its owner wants it served by the storefront
+const VerificationRecordPrefix = "_ecommerce-verify"
+
+// DomainStatus is where a custom domain is in its registration
+type DomainStatus string
+
+const (
+	DomainStatusPending  DomainStatus = "PENDING"  // Waiting for the verification record
+	DomainStatusVerified DomainStatus = "VERIFIED" // Served, with a certificate issued on the first TLS handshake
+)
+
+// SiteDomain is a custom domain serving a storefront site. Certificates are
+// only requested for verified domains, so nobody can make the storefront
+// request certificates for hostnames they do not control.
+type SiteDomain struct {
+	ID                int64
+	SiteID            string
+	Hostname          string // Lower case, ASCII (punycode) and without a trailing dot
+	Status            DomainStatus
+	VerificationToken string
+	VerifiedAt        *time.Time
+	LastCheckedAt     *time.Time
+	LastCheckError    string // Why the last verification failed
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewSiteDomain creates a pending domain of a site with a fresh verification token
+func NewSiteDomain(siteID, hostname string) (*SiteDomain, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	now := time.Now()
+	return &SiteDomain{
+		SiteID:            siteID,
+		Hostname:          hostname,
+		Status:            DomainStatusPending,
+		VerificationToken: hex.EncodeToString(token),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+// VerificationRecord returns the name of the TXT record holding the token
+func (d *SiteDomain) VerificationRecord() string {
+	return VerificationRecordPrefix + "." + d.Hostname
+}
+
+// IsVerified checks if the domain is served
+func (d *SiteDomain) IsVerified() bool {
+	return d.Status == DomainStatusVerified
+}
+
+// MarkVerified records a successful verification
+func (d *SiteDomain) MarkVerified() {
+	now := time.Now()
+	d.Status = DomainStatusVerified
+	d.VerifiedAt = &now
+	d.LastCheckedAt = &now
+	d.LastCheckError = ""
+	d.UpdatedAt = now
+}
+
+// MarkCheckFailed records a failed verification; verified domains stay verified
+func (d *SiteDomain) MarkCheckFailed(reason string) {
+	now := time.Now()
+	d.LastCheckedAt = &now
+	d.LastCheckError = reason
+	d.UpdatedAt = now
+}
+
+// NormalizeHostname lower-cases a hostname and drops a trailing dot, and
+// checks it is a fully qualified DNS name rather than an IP address or a
+// wildcard. Internationalized names must be given in punycode.
+func NormalizeHostname(hostname string) (string, error) {
+	hostname = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+	if hostname == "" {
+		return "", fmt.Errorf("hostname is required")
+	}
+	if net.ParseIP(hostname) != nil {
+		return "", fmt.Errorf("%s is an IP address, not a hostname", hostname)
+	}
+	if len(hostname) > 253 {
+		return "", fmt.Errorf("hostname is longer than 253 characters")
+	}
+	labels := strings.Split(hostname, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("%s is not a fully qualified hostname", hostname)
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 {
+			return "", fmt.Errorf("%s has an empty or too long label", hostname)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return "", fmt.Errorf("%s has a label starting or ending with a hyphen", hostname)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+				return "", fmt.Errorf("%s contains %q, which is not allowed in a hostname", hostname, c)
+			}
+		}
+	}
+	return hostname, nil
+}
+
+// SiteDomainRepository defines the interface for custom domain persistence
+type SiteDomainRepository interface {
+	// Create stores a new domain; a hostname registered already is a conflict
+	Create(ctx context.Context, domain *SiteDomain) error
+
+	// Update saves the verification state of a domain
+	Update(ctx context.Context, domain *SiteDomain) error
+
+	// Delete removes a domain
+	Delete(ctx context.Context, id int64) error
+
+	// FindByID retrieves a domain by ID
+	FindByID(ctx context.Context, id int64) (*SiteDomain, error)
+
+	// FindByHostname retrieves a domain by hostname, nil if none is registered
+	FindByHostname(ctx context.Context, hostname string) (*SiteDomain, error)
+
+	// FindAll lists the domains of a site, or of every site when siteID is empty
+	FindAll(ctx context.Context, siteID string) ([]*SiteDomain, error)
+
+	// FindVerified lists the verified domains of every site
+	FindVerified(ctx context.Context) ([]*SiteDomain, error)
+}
+
+// CertificateCache stores ACME account keys and issued certificates by key,
+// so every storefront instance serves the same certificates. Its methods
+// match autocert.Cache.
+type CertificateCache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+	Delete(ctx context.Context, key string) error
+}
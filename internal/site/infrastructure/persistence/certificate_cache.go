@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresCertificateCache implements autocert.Cache, and so domain.CertificateCache,
+// in the database so every storefront instance shares the ACME account and the
+// certificates issued for custom domains
+type PostgresCertificateCache struct {
+	db *database.DB
+}
+
+// NewPostgresCertificateCache creates a new PostgresCertificateCache
+func NewPostgresCertificateCache(db *database.DB) *PostgresCertificateCache {
+	return &PostgresCertificateCache{db: db}
+}
+
+// Get returns the data stored under key, or autocert.ErrCacheMiss.
+func (c *PostgresCertificateCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := c.db.QueryRow(ctx, `SELECT data FROM site_certificate_cache WHERE cache_key = $1`, key).Scan(&data)
+	if err == pgx.ErrNoRows {
+		return nil, autocert.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to read certificate cache")
+	}
+	return data, nil
+}
+
+// Put stores data under key, replacing what was there.
+func (c *PostgresCertificateCache) Put(ctx context.Context, key string, data []byte) error {
+	err := c.db.Exec(ctx, `
+		INSERT INTO site_certificate_cache (cache_key, data, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (cache_key) DO UPDATE SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at`,
+		key, data, time.Now(),
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to write certificate cache")
+	}
+	return nil
+}
+
+// Delete removes key; a missing key is not an error.
+func (c *PostgresCertificateCache) Delete(ctx context.Context, key string) error {
+	if err := c.db.Exec(ctx, `DELETE FROM site_certificate_cache WHERE cache_key = $1`, key); err != nil {
+		return errors.InternalWrap(err, "failed to delete from certificate cache")
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/site/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresSiteDomainRepository implements the SiteDomainRepository interface
+type PostgresSiteDomainRepository struct {
+	db *database.DB
+}
+
+// NewPostgresSiteDomainRepository creates a new PostgresSiteDomainRepository
+func NewPostgresSiteDomainRepository(db *database.DB) *PostgresSiteDomainRepository {
+	return &PostgresSiteDomainRepository{db: db}
+}
+
+const siteDomainColumns = `
+	domain_id, site_id, hostname, status, verification_token, verified_at,
+	last_checked_at, last_check_error, created_at, updated_at`
+
+// Create stores a new domain; a hostname registered already is a conflict.
+func (r *PostgresSiteDomainRepository) Create(ctx context.Context, d *domain.SiteDomain) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO site_domain (site_id, hostname, status, verification_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (hostname) DO NOTHING
+		RETURNING domain_id`,
+		d.SiteID, d.Hostname, string(d.Status), d.VerificationToken, d.CreatedAt, d.UpdatedAt,
+	).Scan(&d.ID)
+	if err == pgx.ErrNoRows {
+		return errors.Conflict(fmt.Sprintf("domain %s is already registered", d.Hostname))
+	}
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create site domain")
+	}
+	return nil
+}
+
+// Update saves the verification state of a domain.
+func (r *PostgresSiteDomainRepository) Update(ctx context.Context, d *domain.SiteDomain) error {
+	err := r.db.Exec(ctx, `
+		UPDATE site_domain
+		SET status = $2, verified_at = $3, last_checked_at = $4, last_check_error = $5, updated_at = $6
+		WHERE domain_id = $1`,
+		d.ID, string(d.Status), d.VerifiedAt, d.LastCheckedAt, d.LastCheckError, d.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update site domain")
+	}
+	return nil
+}
+
+// Delete removes a domain.
+func (r *PostgresSiteDomainRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.db.Exec(ctx, `DELETE FROM site_domain WHERE domain_id = $1`, id); err != nil {
+		return errors.InternalWrap(err, "failed to delete site domain")
+	}
+	return nil
+}
+
+// FindByID retrieves a domain by ID.
+func (r *PostgresSiteDomainRepository) FindByID(ctx context.Context, id int64) (*domain.SiteDomain, error) {
+	d, err := scanSiteDomain(r.db.QueryRow(ctx, `SELECT`+siteDomainColumns+` FROM site_domain WHERE domain_id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("site domain")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find site domain")
+	}
+	return d, nil
+}
+
+// FindByHostname retrieves a domain by hostname, nil if none is registered.
+func (r *PostgresSiteDomainRepository) FindByHostname(ctx context.Context, hostname string) (*domain.SiteDomain, error) {
+	d, err := scanSiteDomain(r.db.QueryRow(ctx, `SELECT`+siteDomainColumns+` FROM site_domain WHERE hostname = $1`, hostname))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find site domain")
+	}
+	return d, nil
+}
+
+// FindAll lists the domains of a site, or of every site when siteID is empty.
+func (r *PostgresSiteDomainRepository) FindAll(ctx context.Context, siteID string) ([]*domain.SiteDomain, error) {
+	query := `SELECT` + siteDomainColumns + ` FROM site_domain
+		WHERE $1 = '' OR site_id = $1
+		ORDER BY site_id, hostname`
+	return r.query(ctx, query, siteID)
+}
+
+// FindVerified lists the verified domains of every site.
+func (r *PostgresSiteDomainRepository) FindVerified(ctx context.Context) ([]*domain.SiteDomain, error) {
+	query := `SELECT` + siteDomainColumns + ` FROM site_domain
+		WHERE status = $1
+		ORDER BY hostname`
+	return r.query(ctx, query, string(domain.DomainStatusVerified))
+}
+
+func (r *PostgresSiteDomainRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.SiteDomain, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query site domains")
+	}
+	defer rows.Close()
+
+	domains := make([]*domain.SiteDomain, 0)
+	for rows.Next() {
+		d, err := scanSiteDomain(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan site domain")
+		}
+		domains = append(domains, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate site domains")
+	}
+	return domains, nil
+}
+
+func scanSiteDomain(row pgx.Row) (*domain.SiteDomain, error) {
+	d := &domain.SiteDomain{}
+	var status string
+	err := row.Scan(
+		&d.ID, &d.SiteID, &d.Hostname, &status, &d.VerificationToken, &d.VerifiedAt,
+		&d.LastCheckedAt, &d.LastCheckError, &d.CreatedAt, &d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Status = domain.DomainStatus(status)
+	return d, nil
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/site/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminSiteDomainHandler handles the custom domains of storefront sites. A
+// domain is served, and gets a certificate, once its DNS verification passes.
+type AdminSiteDomainHandler struct {
+	domainService  application.SiteDomainService
+	authMiddleware func(http.Handler) http.Handler
+	logger         *logger.Logger
+}
+
+// NewAdminSiteDomainHandler creates a new admin site domain handler
+func NewAdminSiteDomainHandler(domainService application.SiteDomainService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminSiteDomainHandler {
+	return &AdminSiteDomainHandler{
+		domainService:  domainService,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers site domain routes
+func (h *AdminSiteDomainHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/site-domains", h.ListDomains)
+		r.Post("/admin/site-domains", h.AddDomain)
+		r.Get("/admin/site-domains/{id}", h.GetDomain)
+		r.Post("/admin/site-domains/{id}/verify", h.VerifyDomain)
+		r.Delete("/admin/site-domains/{id}", h.RemoveDomain)
+	})
+}
+
+// ListDomains lists the domains of the site_id query parameter, or of every site
+func (h *AdminSiteDomainHandler) ListDomains(w http.ResponseWriter, r *http.Request) {
+	domains, err := h.domainService.ListDomains(r.Context(), r.URL.Query().Get("site_id"))
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list site domains")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, domains)
+}
+
+// AddDomain registers a domain; the response gives the TXT record to publish
+func (h *AdminSiteDomainHandler) AddDomain(w http.ResponseWriter, r *http.Request) {
+	var req application.AddSiteDomainRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	domain, err := h.domainService.AddDomain(r.Context(), &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("hostname", req.Hostname).Error("failed to add site domain")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusCreated, domain)
+}
+
+// GetDomain retrieves a domain with its verification and certificate state
+func (h *AdminSiteDomainHandler) GetDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid domain ID"))
+		return
+	}
+
+	domain, err := h.domainService.GetDomain(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("domain_id", id).Error("failed to get site domain")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, domain)
+}
+
+// VerifyDomain checks the verification record of a domain; a failed check is
+// reported in last_check_error rather than as an error
+func (h *AdminSiteDomainHandler) VerifyDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid domain ID"))
+		return
+	}
+
+	domain, err := h.domainService.VerifyDomain(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("domain_id", id).Error("failed to verify site domain")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, domain)
+}
+
+// RemoveDomain stops serving a domain and drops its certificates
+func (h *AdminSiteDomainHandler) RemoveDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid domain ID"))
+		return
+	}
+
+	if err := h.domainService.RemoveDomain(r.Context(), id); err != nil {
+		h.logger.WithError(err).WithField("domain_id", id).Error("failed to remove site domain")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/qhato/ecommerce/internal/tax/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/httpclient"
+)
+
+// LandedCostLine is an order item to be cleared through customs
+type LandedCostLine struct {
+	ItemID        int64   `json:"item_id"`
+	ProductID     int64   `json:"product_id"`
+	SKUID         int64   `json:"sku_id,omitempty"`
+	HSCode        string  `json:"hs_code,omitempty"`
+	OriginCountry string  `json:"origin_country,omitempty"`
+	Description   string  `json:"description,omitempty"`
+	Quantity      int     `json:"quantity"`
+	Amount        float64 `json:"amount"` // Customs value: what the customer pays, unless the item's declared value rule says otherwise
+}
+
+// LandedCostRequest holds the lines of a cross-border order and where it ships
+// from and to
+type LandedCostRequest struct {
+	OrderID            int64            `json:"order_id"`
+	CurrencyCode       string           `json:"currency_code"`
+	ShipFromCountry    string           `json:"ship_from_country"`
+	DestinationCountry string           `json:"destination_country"`
+	ShippingAmount     float64          `json:"shipping_amount"`
+	Incoterm           string           `json:"incoterm"` // DDP or DAP
+	Lines              []LandedCostLine `json:"lines"`
+}
+
+// LandedCostLineResult is the duty and import tax estimated for a line
+type LandedCostLineResult struct {
+	ItemID    int64   `json:"item_id"`
+	HSCode    string  `json:"hs_code,omitempty"`
+	DutyRate  float64 `json:"duty_rate"`
+	Duty      float64 `json:"duty"`
+	ImportTax float64 `json:"import_tax"`
+}
+
+// LandedCostResult is the duty, import tax and fees estimated for an order
+type LandedCostResult struct {
+	Provider         string                 `json:"provider"`
+	Lines            []LandedCostLineResult `json:"lines"`
+	Duty             float64                `json:"duty"`
+	ImportTax        float64                `json:"import_tax"` // Includes the import tax on shipping
+	Fees             float64                `json:"fees"`
+	Total            float64                `json:"total"`
+	DeMinimisApplied bool                   `json:"de_minimis_applied"` // The order is valued below the destination's duty-free threshold
+}
+
+// LandedCostProvider estimates the duties and import fees a destination country
+// levies on an order
+type LandedCostProvider interface {
+	// Name identifies the provider on estimated orders
+	Name() string
+
+	// EstimateLandedCost estimates the duties and fees of every line of an order
+	EstimateLandedCost(ctx context.Context, req *LandedCostRequest) (*LandedCostResult, error)
+}
+
+// tableLandedCostProvider estimates from the duty tables and import rules
+type tableLandedCostProvider struct {
+	repo domain.LandedCostRepository
+}
+
+// NewTableLandedCostProvider creates a provider estimating from the configured
+// duty tables and import rules of the destination country
+func NewTableLandedCostProvider(repo domain.LandedCostRepository) LandedCostProvider {
+	return &tableLandedCostProvider{repo: repo}
+}
+
+func (p *tableLandedCostProvider) Name() string {
+	return internalTaxProviderName
+}
+
+func (p *tableLandedCostProvider) EstimateLandedCost(ctx context.Context, req *LandedCostRequest) (*LandedCostResult, error) {
+	rule, err := p.repo.FindImportRule(ctx, req.DestinationCountry)
+	if err != nil {
+		return nil, err
+	}
+	if rule != nil && rule.CurrencyCode != req.CurrencyCode {
+		return nil, errors.ValidationError(fmt.Sprintf(
+			"import rule of %s is in %s and cannot be applied to an order in %s", rule.Country, rule.CurrencyCode, req.CurrencyCode))
+	}
+	rates, err := p.repo.FindDutyRates(ctx, req.DestinationCountry)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LandedCostResult{
+		Provider: p.Name(),
+		Lines:    make([]LandedCostLineResult, 0, len(req.Lines)),
+	}
+	goodsValue := 0.0
+	for _, line := range req.Lines {
+		goodsValue += line.Amount
+	}
+	result.DeMinimisApplied = rule != nil && rule.DeMinimis > 0 && goodsValue < rule.DeMinimis
+
+	for _, line := range req.Lines {
+		lineResult := LandedCostLineResult{ItemID: line.ItemID, HSCode: line.HSCode}
+		if !result.DeMinimisApplied {
+			if rate := domain.MatchDutyRate(rates, line.HSCode, line.OriginCountry); rate != nil {
+				lineResult.DutyRate = rate.Rate
+				lineResult.Duty = line.Amount * rate.Rate
+			}
+			if rule != nil {
+				lineResult.ImportTax = (line.Amount + lineResult.Duty) * rule.ImportTaxRate
+			}
+		}
+		result.Duty += lineResult.Duty
+		result.ImportTax += lineResult.ImportTax
+		result.Lines = append(result.Lines, lineResult)
+	}
+	if rule != nil && !result.DeMinimisApplied {
+		result.ImportTax += req.ShippingAmount * rule.ImportTaxRate
+		if result.Duty+result.ImportTax > 0 {
+			result.Fees = rule.ClearanceFee
+		}
+	}
+	result.Total = result.Duty + result.ImportTax + result.Fees
+	return result, nil
+}
+
+// httpLandedCostProvider posts orders to an external landed cost service
+type httpLandedCostProvider struct {
+	client *httpclient.Client
+	path   string
+}
+
+// NewHTTPLandedCostProvider creates a provider posting orders to an external
+// landed cost service. The service answers a LandedCostRequest with a LandedCostResult.
+func NewHTTPLandedCostProvider(client *httpclient.Client, path string) LandedCostProvider {
+	return &httpLandedCostProvider{client: client, path: path}
+}
+
+func (p *httpLandedCostProvider) Name() string {
+	return "external"
+}
+
+func (p *httpLandedCostProvider) EstimateLandedCost(ctx context.Context, req *LandedCostRequest) (*LandedCostResult, error) {
+	var result LandedCostResult
+	if err := p.client.DoJSON(ctx, http.MethodPost, p.path, req, &result); err != nil {
+		return nil, fmt.Errorf("external landed cost estimation failed for order %d: %w", req.OrderID, err)
+	}
+	if result.Total == 0 {
+		result.Total = result.Duty + result.ImportTax + result.Fees
+	}
+	result.Provider = p.Name()
+	return &result, nil
+}
@@ -0,0 +1,551 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/tax/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// LandedCostService manages the customs classification of products and the duty
+// tables and import rules of destination countries, and estimates the duties and
+// import fees of cross-border orders
+type LandedCostService interface {
+	// GetProductCustoms retrieves the customs classification of a product.
+	GetProductCustoms(ctx context.Context, productID int64) (*ProductCustomsDTO, error)
+
+	// SetProductCustoms sets the HS code, origin country, customs description and
+	// declared value rule of a product.
+	SetProductCustoms(ctx context.Context, productID int64, req *SetProductCustomsRequest) (*ProductCustomsDTO, error)
+
+	// DeleteProductCustoms removes the customs classification of a product.
+	DeleteProductCustoms(ctx context.Context, productID int64) error
+
+	// GetSKUCustoms retrieves the customs overrides of a SKU.
+	GetSKUCustoms(ctx context.Context, skuID int64) (*SKUCustomsDTO, error)
+
+	// SetSKUCustoms sets the customs attributes a SKU declares instead of its product's.
+	SetSKUCustoms(ctx context.Context, skuID int64, req *SetSKUCustomsRequest) (*SKUCustomsDTO, error)
+
+	// DeleteSKUCustoms removes the customs overrides of a SKU.
+	DeleteSKUCustoms(ctx context.Context, skuID int64) error
+
+	// BulkSetCustoms sets the customs attributes of many products and SKUs at once.
+	// Every item is validated first; nothing is saved if any is invalid.
+	BulkSetCustoms(ctx context.Context, req *BulkSetCustomsRequest) (*BulkSetCustomsResult, error)
+
+	// DeclareCustoms resolves how shipped items are declared to customs, from the
+	// overrides of their SKU and the classification of their product.
+	DeclareCustoms(ctx context.Context, items []CustomsItem) ([]*CustomsDeclarationLine, error)
+
+	// ListDutyRates lists the duty rates of a destination country.
+	ListDutyRates(ctx context.Context, country string) ([]*DutyRateDTO, error)
+
+	// SaveDutyRate sets the duty rate of a destination country for an HS code prefix and origin.
+	SaveDutyRate(ctx context.Context, req *SaveDutyRateRequest) (*DutyRateDTO, error)
+
+	// DeleteDutyRate removes a duty rate.
+	DeleteDutyRate(ctx context.Context, id int64) error
+
+	// ListImportRules lists the import rules of all countries.
+	ListImportRules(ctx context.Context) ([]*ImportRuleDTO, error)
+
+	// SetImportRule sets the de minimis threshold, import tax and fees of a country.
+	SetImportRule(ctx context.Context, country string, req *SetImportRuleRequest) (*ImportRuleDTO, error)
+
+	// EstimateLandedCost estimates the duties and import fees of an order. Lines
+	// without an HS code are classified from their SKU and product, and valued by
+	// their declared value rule.
+	EstimateLandedCost(ctx context.Context, req *LandedCostRequest) (*LandedCostResult, error)
+}
+
+// ProductCustomsDTO represents the customs classification of a product
+type ProductCustomsDTO struct {
+	ProductID          int64     `json:"product_id"`
+	HSCode             string    `json:"hs_code"`
+	OriginCountry      string    `json:"origin_country,omitempty"`
+	Description        string    `json:"description,omitempty"`
+	DeclaredValueBasis string    `json:"declared_value_basis"`
+	DeclaredUnitValue  float64   `json:"declared_unit_value,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// SetProductCustomsRequest sets the customs classification of a product. The
+// declared value basis is TRANSACTION (the price paid, the default), MINIMUM (the
+// price paid but at least the declared unit value) or FIXED (the declared unit value).
+type SetProductCustomsRequest struct {
+	HSCode             string  `json:"hs_code"`
+	OriginCountry      string  `json:"origin_country"`
+	Description        string  `json:"description"`
+	DeclaredValueBasis string  `json:"declared_value_basis"`
+	DeclaredUnitValue  float64 `json:"declared_unit_value"`
+}
+
+// SKUCustomsDTO represents the customs overrides of a SKU; empty attributes are
+// inherited from its product
+type SKUCustomsDTO struct {
+	SKUID              int64     `json:"sku_id"`
+	HSCode             string    `json:"hs_code,omitempty"`
+	OriginCountry      string    `json:"origin_country,omitempty"`
+	Description        string    `json:"description,omitempty"`
+	DeclaredValueBasis string    `json:"declared_value_basis,omitempty"`
+	DeclaredUnitValue  float64   `json:"declared_unit_value,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// SetSKUCustomsRequest sets the customs overrides of a SKU; empty attributes are
+// inherited from its product
+type SetSKUCustomsRequest struct {
+	HSCode             string  `json:"hs_code"`
+	OriginCountry      string  `json:"origin_country"`
+	Description        string  `json:"description"`
+	DeclaredValueBasis string  `json:"declared_value_basis"`
+	DeclaredUnitValue  float64 `json:"declared_unit_value"`
+}
+
+// BulkSetCustomsRequest sets the customs attributes of many products and SKUs
+type BulkSetCustomsRequest struct {
+	Items []*BulkCustomsItem `json:"items"`
+}
+
+// BulkCustomsItem sets the customs attributes of a product or, with a SKU ID, the
+// overrides of a SKU
+type BulkCustomsItem struct {
+	ProductID          int64   `json:"product_id,omitempty"`
+	SKUID              int64   `json:"sku_id,omitempty"`
+	HSCode             string  `json:"hs_code"`
+	OriginCountry      string  `json:"origin_country"`
+	Description        string  `json:"description"`
+	DeclaredValueBasis string  `json:"declared_value_basis"`
+	DeclaredUnitValue  float64 `json:"declared_unit_value"`
+}
+
+// BulkSetCustomsResult counts the products and SKUs a bulk edit saved
+type BulkSetCustomsResult struct {
+	Products int `json:"products"`
+	SKUs     int `json:"skus"`
+}
+
+// CustomsItem is a shipped item to be declared to customs
+type CustomsItem struct {
+	ItemID    int64
+	ProductID int64
+	SKUID     int64
+	Quantity  int
+	Amount    float64 // What the customer paid for the item
+}
+
+// CustomsDeclaration declares the contents of a cross-border shipment to customs,
+// on its commercial invoice and label request
+type CustomsDeclaration struct {
+	OrderID         int64                     `json:"order_id"`
+	OrderNumber     string                    `json:"order_number,omitempty"`
+	CurrencyCode    string                    `json:"currency_code"`
+	Incoterm        string                    `json:"incoterm,omitempty"` // DDP or DAP, when the customer chose one
+	ReasonForExport string                    `json:"reason_for_export"`
+	TotalValue      float64                   `json:"total_value"`
+	Lines           []*CustomsDeclarationLine `json:"lines"`
+}
+
+// ReasonForExportSale is the reason for export of goods shipped to customers who bought them
+const ReasonForExportSale = "SALE"
+
+// CustomsDeclarationLine is how a shipped item is declared to customs; the HS code
+// is empty when neither its SKU nor its product is classified
+type CustomsDeclarationLine struct {
+	ItemID        int64   `json:"item_id"`
+	SKUID         int64   `json:"sku_id,omitempty"`
+	HSCode        string  `json:"hs_code,omitempty"`
+	OriginCountry string  `json:"origin_country,omitempty"`
+	Description   string  `json:"description,omitempty"`
+	Quantity      int     `json:"quantity"`
+	UnitValue     float64 `json:"unit_value"`
+	Value         float64 `json:"value"`
+}
+
+// DutyRateDTO represents a duty rate
+type DutyRateDTO struct {
+	ID                 int64     `json:"id"`
+	DestinationCountry string    `json:"destination_country"`
+	HSCodePrefix       string    `json:"hs_code_prefix"`
+	OriginCountry      string    `json:"origin_country,omitempty"`
+	Rate               float64   `json:"rate"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// SaveDutyRateRequest sets a duty rate; an empty HS code prefix sets the
+// country's default rate and an empty origin applies to goods of any origin
+type SaveDutyRateRequest struct {
+	DestinationCountry string  `json:"destination_country"`
+	HSCodePrefix       string  `json:"hs_code_prefix"`
+	OriginCountry      string  `json:"origin_country"`
+	Rate               float64 `json:"rate"`
+}
+
+// ImportRuleDTO represents the import rule of a country
+type ImportRuleDTO struct {
+	Country       string    `json:"country"`
+	CurrencyCode  string    `json:"currency_code"`
+	DeMinimis     float64   `json:"de_minimis"`
+	ImportTaxRate float64   `json:"import_tax_rate"`
+	ClearanceFee  float64   `json:"clearance_fee"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SetImportRuleRequest sets the import rule of a country
+type SetImportRuleRequest struct {
+	CurrencyCode  string  `json:"currency_code"`
+	DeMinimis     float64 `json:"de_minimis"`
+	ImportTaxRate float64 `json:"import_tax_rate"`
+	ClearanceFee  float64 `json:"clearance_fee"`
+}
+
+type landedCostService struct {
+	repo     domain.LandedCostRepository
+	provider LandedCostProvider
+	log      *logger.Logger
+}
+
+// NewLandedCostService creates a new instance of LandedCostService. A nil provider
+// estimates from the duty tables and import rules.
+func NewLandedCostService(repo domain.LandedCostRepository, provider LandedCostProvider, log *logger.Logger) LandedCostService {
+	if provider == nil {
+		provider = NewTableLandedCostProvider(repo)
+	}
+	return &landedCostService{
+		repo:     repo,
+		provider: provider,
+		log:      log,
+	}
+}
+
+func (s *landedCostService) GetProductCustoms(ctx context.Context, productID int64) (*ProductCustomsDTO, error) {
+	customs, err := s.repo.FindProductCustoms(ctx, []int64{productID})
+	if err != nil {
+		return nil, err
+	}
+	c, ok := customs[productID]
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("customs classification of product %d", productID))
+	}
+	return toProductCustomsDTO(c), nil
+}
+
+func (s *landedCostService) SetProductCustoms(ctx context.Context, productID int64, req *SetProductCustomsRequest) (*ProductCustomsDTO, error) {
+	customs, err := domain.NewProductCustoms(productID, customsAttributes(req.HSCode, req.OriginCountry, req.Description, req.DeclaredValueBasis, req.DeclaredUnitValue))
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.SaveProductCustoms(ctx, customs); err != nil {
+		return nil, err
+	}
+	return toProductCustomsDTO(customs), nil
+}
+
+func (s *landedCostService) DeleteProductCustoms(ctx context.Context, productID int64) error {
+	return s.repo.DeleteProductCustoms(ctx, productID)
+}
+
+func (s *landedCostService) GetSKUCustoms(ctx context.Context, skuID int64) (*SKUCustomsDTO, error) {
+	customs, err := s.repo.FindSKUCustoms(ctx, []int64{skuID})
+	if err != nil {
+		return nil, err
+	}
+	c, ok := customs[skuID]
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("customs overrides of SKU %d", skuID))
+	}
+	return toSKUCustomsDTO(c), nil
+}
+
+func (s *landedCostService) SetSKUCustoms(ctx context.Context, skuID int64, req *SetSKUCustomsRequest) (*SKUCustomsDTO, error) {
+	customs, err := domain.NewSKUCustoms(skuID, customsAttributes(req.HSCode, req.OriginCountry, req.Description, req.DeclaredValueBasis, req.DeclaredUnitValue))
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.SaveSKUCustoms(ctx, customs); err != nil {
+		return nil, err
+	}
+	return toSKUCustomsDTO(customs), nil
+}
+
+func (s *landedCostService) DeleteSKUCustoms(ctx context.Context, skuID int64) error {
+	return s.repo.DeleteSKUCustoms(ctx, skuID)
+}
+
+// maxBulkCustomsItems bounds a bulk edit, which is saved in one transaction
+const maxBulkCustomsItems = 1000
+
+func (s *landedCostService) BulkSetCustoms(ctx context.Context, req *BulkSetCustomsRequest) (*BulkSetCustomsResult, error) {
+	if len(req.Items) == 0 {
+		return nil, errors.ValidationError("no customs attributes to set")
+	}
+	if len(req.Items) > maxBulkCustomsItems {
+		return nil, errors.ValidationError(fmt.Sprintf("at most %d items can be set at once", maxBulkCustomsItems))
+	}
+
+	products := make([]*domain.ProductCustoms, 0, len(req.Items))
+	skus := make([]*domain.SKUCustoms, 0)
+	seenProducts := make(map[int64]bool, len(req.Items))
+	seenSKUs := make(map[int64]bool)
+	var invalid []string
+	for i, item := range req.Items {
+		if item == nil {
+			invalid = append(invalid, fmt.Sprintf("item %d is empty", i))
+			continue
+		}
+		attrs := customsAttributes(item.HSCode, item.OriginCountry, item.Description, item.DeclaredValueBasis, item.DeclaredUnitValue)
+		switch {
+		case item.SKUID != 0:
+			if seenSKUs[item.SKUID] {
+				invalid = append(invalid, fmt.Sprintf("item %d: SKU %d is set more than once", i, item.SKUID))
+				continue
+			}
+			seenSKUs[item.SKUID] = true
+			customs, err := domain.NewSKUCustoms(item.SKUID, attrs)
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("item %d: %s", i, err.Error()))
+				continue
+			}
+			skus = append(skus, customs)
+		case item.ProductID != 0:
+			if seenProducts[item.ProductID] {
+				invalid = append(invalid, fmt.Sprintf("item %d: product %d is set more than once", i, item.ProductID))
+				continue
+			}
+			seenProducts[item.ProductID] = true
+			customs, err := domain.NewProductCustoms(item.ProductID, attrs)
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("item %d: %s", i, err.Error()))
+				continue
+			}
+			products = append(products, customs)
+		default:
+			invalid = append(invalid, fmt.Sprintf("item %d: product_id or sku_id is required", i))
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, errors.ValidationError(fmt.Sprintf("%d of %d items are invalid, nothing was saved: %s",
+			len(invalid), len(req.Items), strings.Join(invalid, "; ")))
+	}
+
+	if err := s.repo.SaveCustoms(ctx, products, skus); err != nil {
+		return nil, err
+	}
+	s.log.WithField("products", len(products)).WithField("skus", len(skus)).Info("customs attributes bulk updated")
+	return &BulkSetCustomsResult{Products: len(products), SKUs: len(skus)}, nil
+}
+
+func (s *landedCostService) DeclareCustoms(ctx context.Context, items []CustomsItem) ([]*CustomsDeclarationLine, error) {
+	productIDs := make([]int64, 0, len(items))
+	skuIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+		skuIDs = append(skuIDs, item.SKUID)
+	}
+	customs, err := s.findCustoms(ctx, productIDs, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*CustomsDeclarationLine, 0, len(items))
+	for _, item := range items {
+		attrs := customs.attributes(item.ProductID, item.SKUID)
+		line := &CustomsDeclarationLine{
+			ItemID:        item.ItemID,
+			SKUID:         item.SKUID,
+			HSCode:        attrs.HSCode,
+			OriginCountry: attrs.OriginCountry,
+			Description:   attrs.Description,
+			Quantity:      item.Quantity,
+			Value:         attrs.DeclaredValue.Declare(item.Amount, item.Quantity),
+		}
+		if item.Quantity > 0 {
+			line.UnitValue = line.Value / float64(item.Quantity)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func (s *landedCostService) ListDutyRates(ctx context.Context, country string) ([]*DutyRateDTO, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if len(country) != 2 {
+		return nil, errors.ValidationError("country must be an ISO alpha-2 code")
+	}
+	rates, err := s.repo.FindDutyRates(ctx, country)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*DutyRateDTO, 0, len(rates))
+	for _, rate := range rates {
+		dtos = append(dtos, toDutyRateDTO(rate))
+	}
+	return dtos, nil
+}
+
+func (s *landedCostService) SaveDutyRate(ctx context.Context, req *SaveDutyRateRequest) (*DutyRateDTO, error) {
+	rate, err := domain.NewDutyRate(req.DestinationCountry, req.HSCodePrefix, req.OriginCountry, req.Rate)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.SaveDutyRate(ctx, rate); err != nil {
+		return nil, err
+	}
+	return toDutyRateDTO(rate), nil
+}
+
+func (s *landedCostService) DeleteDutyRate(ctx context.Context, id int64) error {
+	return s.repo.DeleteDutyRate(ctx, id)
+}
+
+func (s *landedCostService) ListImportRules(ctx context.Context) ([]*ImportRuleDTO, error) {
+	rules, err := s.repo.FindImportRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*ImportRuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		dtos = append(dtos, toImportRuleDTO(rule))
+	}
+	return dtos, nil
+}
+
+func (s *landedCostService) SetImportRule(ctx context.Context, country string, req *SetImportRuleRequest) (*ImportRuleDTO, error) {
+	rule, err := domain.NewImportRule(country, req.CurrencyCode, req.DeMinimis, req.ImportTaxRate, req.ClearanceFee)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	if err := s.repo.SaveImportRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return toImportRuleDTO(rule), nil
+}
+
+func (s *landedCostService) EstimateLandedCost(ctx context.Context, req *LandedCostRequest) (*LandedCostResult, error) {
+	productIDs := make([]int64, 0, len(req.Lines))
+	skuIDs := make([]int64, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		productIDs = append(productIDs, line.ProductID)
+		skuIDs = append(skuIDs, line.SKUID)
+	}
+	customs, err := s.findCustoms(ctx, productIDs, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range req.Lines {
+		line := &req.Lines[i]
+		attrs := customs.attributes(line.ProductID, line.SKUID)
+		if line.HSCode == "" {
+			line.HSCode = attrs.HSCode
+		}
+		if line.OriginCountry == "" {
+			line.OriginCountry = attrs.OriginCountry
+		}
+		if line.Description == "" {
+			line.Description = attrs.Description
+		}
+		line.Amount = attrs.DeclaredValue.Declare(line.Amount, line.Quantity)
+	}
+
+	result, err := s.provider.EstimateLandedCost(ctx, req)
+	if err != nil {
+		s.log.WithError(err).WithField("order_id", req.OrderID).Warn("landed cost estimation failed")
+		return nil, err
+	}
+	return result, nil
+}
+
+// customsLookup holds the customs attributes of the SKUs and products of some items
+type customsLookup struct {
+	products map[int64]*domain.ProductCustoms
+	skus     map[int64]*domain.SKUCustoms
+}
+
+// attributes returns the customs attributes of an item: its SKU's overrides, then its product's
+func (l *customsLookup) attributes(productID, skuID int64) domain.CustomsAttributes {
+	var attrs domain.CustomsAttributes
+	if c, ok := l.skus[skuID]; ok {
+		attrs = c.CustomsAttributes
+	}
+	if c, ok := l.products[productID]; ok {
+		attrs = attrs.Inherit(c.CustomsAttributes)
+	}
+	return attrs
+}
+
+func (s *landedCostService) findCustoms(ctx context.Context, productIDs, skuIDs []int64) (*customsLookup, error) {
+	products, err := s.repo.FindProductCustoms(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	skus, err := s.repo.FindSKUCustoms(ctx, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &customsLookup{products: products, skus: skus}, nil
+}
+
+func customsAttributes(hsCode, originCountry, description, declaredValueBasis string, declaredUnitValue float64) domain.CustomsAttributes {
+	return domain.CustomsAttributes{
+		HSCode:        hsCode,
+		OriginCountry: originCountry,
+		Description:   description,
+		DeclaredValue: domain.DeclaredValueRule{
+			Basis:     domain.DeclaredValueBasis(declaredValueBasis),
+			UnitValue: declaredUnitValue,
+		},
+	}
+}
+
+func toProductCustomsDTO(customs *domain.ProductCustoms) *ProductCustomsDTO {
+	return &ProductCustomsDTO{
+		ProductID:          customs.ProductID,
+		HSCode:             customs.HSCode,
+		OriginCountry:      customs.OriginCountry,
+		Description:        customs.Description,
+		DeclaredValueBasis: string(customs.DeclaredValue.Basis),
+		DeclaredUnitValue:  customs.DeclaredValue.UnitValue,
+		UpdatedAt:          customs.UpdatedAt,
+	}
+}
+
+func toSKUCustomsDTO(customs *domain.SKUCustoms) *SKUCustomsDTO {
+	return &SKUCustomsDTO{
+		SKUID:              customs.SKUID,
+		HSCode:             customs.HSCode,
+		OriginCountry:      customs.OriginCountry,
+		Description:        customs.Description,
+		DeclaredValueBasis: string(customs.DeclaredValue.Basis),
+		DeclaredUnitValue:  customs.DeclaredValue.UnitValue,
+		UpdatedAt:          customs.UpdatedAt,
+	}
+}
+
+func toDutyRateDTO(rate *domain.DutyRate) *DutyRateDTO {
+	return &DutyRateDTO{
+		ID:                 rate.ID,
+		DestinationCountry: rate.DestinationCountry,
+		HSCodePrefix:       rate.HSCodePrefix,
+		OriginCountry:      rate.OriginCountry,
+		Rate:               rate.Rate,
+		CreatedAt:          rate.CreatedAt,
+		UpdatedAt:          rate.UpdatedAt,
+	}
+}
+
+func toImportRuleDTO(rule *domain.ImportRule) *ImportRuleDTO {
+	return &ImportRuleDTO{
+		Country:       rule.Country,
+		CurrencyCode:  rule.CurrencyCode,
+		DeMinimis:     rule.DeMinimis,
+		ImportTaxRate: rule.ImportTaxRate,
+		ClearanceFee:  rule.ClearanceFee,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+}
@@ -0,0 +1,173 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/qhato/ecommerce/pkg/httpclient"
+)
+
+// Placeholder jurisdiction until tax is derived from the order's shipping address
+const (
+	defaultTaxCountry = "US"
+	defaultTaxRegion  = "CA"
+	defaultTaxType    = "SALES_TAX"
+)
+
+// internalTaxProviderName marks tax calculated from the configured tax details
+const internalTaxProviderName = "internal"
+
+// OrderTaxLine is an order item to be taxed, or the taxable shipping of a
+// fulfillment group when FulfillmentGroupID is set
+type OrderTaxLine struct {
+	ItemID             int64   `json:"item_id"`
+	FulfillmentGroupID int64   `json:"fulfillment_group_id,omitempty"`
+	Amount             float64 `json:"amount"`
+	TaxCategory        string  `json:"tax_category"`
+}
+
+// OrderTaxRequest holds an order's taxable lines and destination
+type OrderTaxRequest struct {
+	OrderID      int64          `json:"order_id"`
+	CustomerID   int64          `json:"customer_id"`
+	CurrencyCode string         `json:"currency_code"`
+	Country      string         `json:"country"`
+	Region       string         `json:"region"`
+	Lines        []OrderTaxLine `json:"lines"`
+}
+
+// OrderTaxResult is the tax calculated for each line of an order, with its
+// breakdown by jurisdiction
+type OrderTaxResult struct {
+	Provider string            `json:"provider"`
+	Lines    map[int64]float64 `json:"lines"`            // Item ID -> tax amount
+	Groups   map[int64]float64 `json:"groups,omitempty"` // Fulfillment group ID -> tax on its shipping
+	Details  []OrderTaxDetail  `json:"details,omitempty"`
+	TotalTax float64           `json:"total_tax"`
+}
+
+// OrderTaxDetail is the tax one jurisdiction levies on a line
+type OrderTaxDetail struct {
+	ItemID             int64   `json:"item_id,omitempty"`
+	FulfillmentGroupID int64   `json:"fulfillment_group_id,omitempty"`
+	JurisdictionName   string  `json:"jurisdiction_name"`
+	Country            string  `json:"country"`
+	Region             string  `json:"region"`
+	TaxName            string  `json:"tax_name"`
+	Rate               float64 `json:"rate"`
+	TaxableAmount      float64 `json:"taxable_amount"`
+	Amount             float64 `json:"amount"`
+}
+
+// TaxProvider calculates the authoritative tax of an order with an external tax engine
+type TaxProvider interface {
+	// Name identifies the provider on calculated orders
+	Name() string
+
+	// CalculateOrderTax calculates the tax of every line of an order
+	CalculateOrderTax(ctx context.Context, req *OrderTaxRequest) (*OrderTaxResult, error)
+}
+
+// httpTaxProvider posts orders to an external tax engine
+type httpTaxProvider struct {
+	client *httpclient.Client
+	path   string
+}
+
+// NewHTTPTaxProvider creates a provider posting orders to an external tax engine.
+// The engine answers an OrderTaxRequest with an OrderTaxResult.
+func NewHTTPTaxProvider(client *httpclient.Client, path string) TaxProvider {
+	return &httpTaxProvider{client: client, path: path}
+}
+
+func (p *httpTaxProvider) Name() string {
+	return "external"
+}
+
+func (p *httpTaxProvider) CalculateOrderTax(ctx context.Context, req *OrderTaxRequest) (*OrderTaxResult, error) {
+	var result OrderTaxResult
+	if err := p.client.DoJSON(ctx, http.MethodPost, p.path, req, &result); err != nil {
+		return nil, fmt.Errorf("external tax calculation failed for order %d: %w", req.OrderID, err)
+	}
+	if result.Lines == nil {
+		result.Lines = make(map[int64]float64)
+	}
+	if result.Groups == nil {
+		result.Groups = make(map[int64]float64)
+	}
+	if len(result.Details) == 0 {
+		result.Details = summarizeTaxDetails(req, &result)
+	}
+	result.Provider = p.Name()
+	return &result, nil
+}
+
+// summarizeTaxDetails records the tax of each taxed line as a single detail for
+// the destination of the order, for engines that return no jurisdiction breakdown
+func summarizeTaxDetails(req *OrderTaxRequest, result *OrderTaxResult) []OrderTaxDetail {
+	details := make([]OrderTaxDetail, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		tax := result.Lines[line.ItemID]
+		if line.FulfillmentGroupID != 0 {
+			tax = result.Groups[line.FulfillmentGroupID]
+		}
+		if tax == 0 {
+			continue
+		}
+		rate := 0.0
+		if line.Amount != 0 {
+			rate = tax / line.Amount
+		}
+		details = append(details, OrderTaxDetail{
+			ItemID:             line.ItemID,
+			FulfillmentGroupID: line.FulfillmentGroupID,
+			JurisdictionName:   req.Country + " " + req.Region,
+			Country:            req.Country,
+			Region:             req.Region,
+			TaxName:            defaultTaxType,
+			Rate:               rate,
+			TaxableAmount:      line.Amount,
+			Amount:             tax,
+		})
+	}
+	return details
+}
+
+// applyTaxRates taxes every line with a tax category at each of the rates applicable
+// in the jurisdiction, recording what each one levies
+func applyTaxRates(provider, country, region string, lines []OrderTaxLine, rates []*TaxDetailDTO) *OrderTaxResult {
+	result := &OrderTaxResult{
+		Provider: provider,
+		Lines:    make(map[int64]float64, len(lines)),
+		Groups:   make(map[int64]float64),
+		Details:  make([]OrderTaxDetail, 0, len(lines)*len(rates)),
+	}
+	for _, line := range lines {
+		tax := 0.0
+		if line.TaxCategory != "" {
+			for _, rate := range rates {
+				amount := line.Amount * rate.Rate
+				tax += amount
+				result.Details = append(result.Details, OrderTaxDetail{
+					ItemID:             line.ItemID,
+					FulfillmentGroupID: line.FulfillmentGroupID,
+					JurisdictionName:   rate.JurisdictionName,
+					Country:            country,
+					Region:             region,
+					TaxName:            rate.TaxName,
+					Rate:               rate.Rate,
+					TaxableAmount:      line.Amount,
+					Amount:             amount,
+				})
+			}
+		}
+		if line.FulfillmentGroupID != 0 {
+			result.Groups[line.FulfillmentGroupID] += tax
+		} else {
+			result.Lines[line.ItemID] = tax
+		}
+		result.TotalTax += tax
+	}
+	return result
+}
@@ -6,8 +6,15 @@ import (
 	"time"
 
 	"github.com/qhato/ecommerce/internal/tax/domain"
+	"github.com/qhato/ecommerce/pkg/cache"
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/requestctx"
 )
 
+// TaxCacheNamespace holds the cached jurisdiction rate lookups
+const TaxCacheNamespace = "tax"
+
 // TaxService defines the application service for tax-related operations.
 type TaxService interface {
 	// CreateTaxDetail creates a new tax detail record.
@@ -27,6 +34,13 @@ type TaxService interface {
 
 	// CalculateTaxForItem calculates the tax amount for a given item price, category, and order details.
 	CalculateTaxForItem(ctx context.Context, orderID int64, itemTotalPrice float64, itemTaxCategory string) (float64, error)
+
+	// EstimateTaxForItem estimates the tax of an item from the cached jurisdiction rates,
+	// without calling the tax provider.
+	EstimateTaxForItem(ctx context.Context, itemTotalPrice float64, itemTaxCategory string) (float64, error)
+
+	// CalculateOrderTax calculates the authoritative tax of every line of an order.
+	CalculateOrderTax(ctx context.Context, req *OrderTaxRequest) (*OrderTaxResult, error)
 }
 
 // TaxDetailDTO represents a tax detail data transfer object.
@@ -74,12 +88,21 @@ type UpdateTaxDetailCommand struct {
 
 type taxService struct {
 	taxDetailRepo domain.TaxDetailRepository
+	queries       *cache.QueryCache
+	provider      TaxProvider // nil calculates from the configured tax details
+	eventBus      event.Bus
+	log           *logger.Logger
 }
 
-// NewTaxService creates a new instance of TaxService.
-func NewTaxService(taxDetailRepo domain.TaxDetailRepository) TaxService {
+// NewTaxService creates a new instance of TaxService. A nil provider calculates
+// tax from the configured tax details.
+func NewTaxService(taxDetailRepo domain.TaxDetailRepository, queries *cache.QueryCache, provider TaxProvider, eventBus event.Bus, log *logger.Logger) TaxService {
 	return &taxService{
 		taxDetailRepo: taxDetailRepo,
+		queries:       queries,
+		provider:      provider,
+		eventBus:      eventBus,
+		log:           log,
 	}
 }
 
@@ -106,6 +129,7 @@ func (s *taxService) CreateTaxDetail(ctx context.Context, cmd *CreateTaxDetailCo
 	if err != nil {
 		return nil, fmt.Errorf("failed to save tax detail: %w", err)
 	}
+	s.taxDetailChanged(ctx, taxDetail.ID)
 
 	return toTaxDetailDTO(taxDetail), nil
 }
@@ -173,6 +197,7 @@ func (s *taxService) UpdateTaxDetail(ctx context.Context, cmd *UpdateTaxDetailCo
 	if err != nil {
 		return nil, fmt.Errorf("failed to update tax detail: %w", err)
 	}
+	s.taxDetailChanged(ctx, taxDetail.ID)
 
 	return toTaxDetailDTO(taxDetail), nil
 }
@@ -182,10 +207,19 @@ func (s *taxService) DeleteTaxDetail(ctx context.Context, id int64) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete tax detail: %w", err)
 	}
+	s.taxDetailChanged(ctx, id)
 	return nil
 }
 
+// FindApplicableTaxDetails retrieves applicable tax details through the query cache.
 func (s *taxService) FindApplicableTaxDetails(ctx context.Context, taxCountry, taxRegion, taxType string) ([]*TaxDetailDTO, error) {
+	key := "details:" + taxCountry + ":" + taxRegion + ":" + taxType
+	return cache.GetJSON(ctx, s.queries, TaxCacheNamespace, key, func(ctx context.Context) ([]*TaxDetailDTO, error) {
+		return s.loadApplicableTaxDetails(ctx, taxCountry, taxRegion, taxType)
+	})
+}
+
+func (s *taxService) loadApplicableTaxDetails(ctx context.Context, taxCountry, taxRegion, taxType string) ([]*TaxDetailDTO, error) {
 	details, err := s.taxDetailRepo.FindApplicableTaxDetails(ctx, taxCountry, taxRegion, taxType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find applicable tax details: %w", err)
@@ -198,31 +232,73 @@ func (s *taxService) FindApplicableTaxDetails(ctx context.Context, taxCountry, t
 	return detailsDTO, nil
 }
 
-// CalculateTaxForItem calculates the tax amount for a given item price, category, and order details.
-// This is a simplified example; a real tax calculation could involve complex rules, multiple tax details,
-// and external tax providers.
+// CalculateTaxForItem calculates the tax amount for a given item price, category, and order details
+// with the tax provider.
 func (s *taxService) CalculateTaxForItem(ctx context.Context, orderID int64, itemTotalPrice float64, itemTaxCategory string) (float64, error) {
-	// For now, let's assume a simplified scenario where we fetch a default tax detail
-	// based on some hardcoded criteria or a simple lookup.
-	// In a real system, you'd likely derive country/region from the order's shipping address
-	// and use the itemTaxCategory.
+	result, err := s.CalculateOrderTax(ctx, &OrderTaxRequest{
+		OrderID: orderID,
+		Lines:   []OrderTaxLine{{Amount: itemTotalPrice, TaxCategory: itemTaxCategory}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalTax, nil
+}
 
-	// Placeholder values for demonstration
-	defaultTaxCountry := "US"
-	defaultTaxRegion := "CA"
-	defaultTaxType := "SALES_TAX"
+// EstimateTaxForItem estimates the tax of an item from the cached jurisdiction rates.
+// Before an address is known, the jurisdiction is where the visitor was located.
+func (s *taxService) EstimateTaxForItem(ctx context.Context, itemTotalPrice float64, itemTaxCategory string) (float64, error) {
+	country, region := estimateJurisdiction(ctx)
+	rates, err := s.jurisdictionRates(ctx, country, region)
+	if err != nil {
+		return 0, err
+	}
+	lines := []OrderTaxLine{{Amount: itemTotalPrice, TaxCategory: itemTaxCategory}}
+	return applyTaxRates(internalTaxProviderName, country, region, lines, rates).TotalTax, nil
+}
 
-	applicableDetails, err := s.FindApplicableTaxDetails(ctx, defaultTaxCountry, defaultTaxRegion, defaultTaxType)
+// CalculateOrderTax calculates the tax of an order with the tax provider, or from the
+// configured tax details when there is none. The cached rates are current since tax
+// detail changes invalidate them.
+func (s *taxService) CalculateOrderTax(ctx context.Context, req *OrderTaxRequest) (*OrderTaxResult, error) {
+	// In a real system, the jurisdiction would come from the order's shipping address
+	if req.Country == "" {
+		req.Country, req.Region = estimateJurisdiction(ctx)
+	}
+
+	if s.provider != nil {
+		return s.provider.CalculateOrderTax(ctx, req)
+	}
+	rates, err := s.jurisdictionRates(ctx, req.Country, req.Region)
 	if err != nil {
-		return 0, fmt.Errorf("failed to find applicable tax details for item calculation: %w", err)
+		return nil, err
+	}
+	return applyTaxRates(internalTaxProviderName, req.Country, req.Region, req.Lines, rates), nil
+}
+
+// estimateJurisdiction returns the country and region the storefront located the
+// visitor in, or the default jurisdiction when the visitor was not located
+func estimateJurisdiction(ctx context.Context) (country, region string) {
+	if country := requestctx.Country(ctx); country != "" {
+		return country, requestctx.Region(ctx)
 	}
+	return defaultTaxCountry, defaultTaxRegion
+}
 
-	totalTaxRate := 0.0
-	for _, detail := range applicableDetails {
-		totalTaxRate += detail.Rate
+// jurisdictionRates returns the cached tax details applicable in a jurisdiction
+func (s *taxService) jurisdictionRates(ctx context.Context, taxCountry, taxRegion string) ([]*TaxDetailDTO, error) {
+	applicableDetails, err := s.FindApplicableTaxDetails(ctx, taxCountry, taxRegion, defaultTaxType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find applicable tax details for item calculation: %w", err)
 	}
+	return applicableDetails, nil
+}
 
-	return itemTotalPrice * totalTaxRate, nil
+// taxDetailChanged publishes a tax detail change; the query cache is invalidated by its subscription
+func (s *taxService) taxDetailChanged(ctx context.Context, taxDetailID int64) {
+	if err := s.eventBus.Publish(ctx, domain.NewTaxDetailChangedEvent(taxDetailID)); err != nil {
+		s.log.WithError(err).WithField("tax_detail_id", taxDetailID).Error("failed to publish tax detail changed event")
+	}
 }
 
 func toTaxDetailDTO(taxDetail *domain.TaxDetail) *TaxDetailDTO {
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCustomsDescriptionLength is the longest customs description carriers accept
+// on a commercial invoice line
+const MaxCustomsDescriptionLength = 100
+
+// DeclaredValueBasis is how the value declared to customs for an item is derived
+// from the price it was sold for
+type DeclaredValueBasis string
+
+const (
+	DeclaredValueTransaction DeclaredValueBasis = "TRANSACTION" // The price paid
+	DeclaredValueMinimum     DeclaredValueBasis = "MINIMUM"     // The price paid, but at least a unit value, e.g. for free gifts
+	DeclaredValueFixed       DeclaredValueBasis = "FIXED"       // A unit value whatever the price, e.g. for warranty replacements
+)
+
+// IsValid checks if the basis is one declared values can be derived from
+func (b DeclaredValueBasis) IsValid() bool {
+	return b == DeclaredValueTransaction || b == DeclaredValueMinimum || b == DeclaredValueFixed
+}
+
+// DeclaredValueRule derives the value declared to customs for an item
+type DeclaredValueRule struct {
+	Basis     DeclaredValueBasis // Empty inherits the product's rule on a SKU, and is the price paid otherwise
+	UnitValue float64            // Per unit, for MINIMUM and FIXED
+}
+
+// Declare returns the customs value of a quantity of an item sold for amount
+func (r DeclaredValueRule) Declare(amount float64, quantity int) float64 {
+	switch r.Basis {
+	case DeclaredValueMinimum:
+		return math.Max(amount, r.UnitValue*float64(quantity))
+	case DeclaredValueFixed:
+		return r.UnitValue * float64(quantity)
+	}
+	return amount
+}
+
+// CustomsAttributes are what a shipment's customs declaration says about an item
+type CustomsAttributes struct {
+	HSCode        string // Harmonized System code, 6 to 10 digits without separators
+	OriginCountry string // ISO alpha-2 country of manufacture
+	Description   string // Plain description of the goods, e.g. "Men's cotton T-shirt"
+	DeclaredValue DeclaredValueRule
+}
+
+// Normalize validates the attributes, dropping the dots and spaces of the HS code,
+// e.g. "6109.10.00"; empty attributes are left empty
+func (a CustomsAttributes) Normalize() (CustomsAttributes, error) {
+	a.HSCode = NormalizeHSCode(a.HSCode)
+	if a.HSCode != "" && (len(a.HSCode) < 6 || len(a.HSCode) > 10 || !isDigits(a.HSCode)) {
+		return a, NewDomainError("HS code must have 6 to 10 digits")
+	}
+	a.OriginCountry = strings.ToUpper(strings.TrimSpace(a.OriginCountry))
+	if a.OriginCountry != "" && len(a.OriginCountry) != 2 {
+		return a, NewDomainError("origin country must be an ISO alpha-2 code")
+	}
+	a.Description = strings.Join(strings.Fields(a.Description), " ")
+	if utf8.RuneCountInString(a.Description) > MaxCustomsDescriptionLength {
+		return a, NewDomainError(fmt.Sprintf("customs description cannot be longer than %d characters", MaxCustomsDescriptionLength))
+	}
+
+	rule := &a.DeclaredValue
+	rule.Basis = DeclaredValueBasis(strings.ToUpper(strings.TrimSpace(string(rule.Basis))))
+	switch {
+	case rule.Basis != "" && !rule.Basis.IsValid():
+		return a, NewDomainError("declared value basis must be TRANSACTION, MINIMUM or FIXED")
+	case rule.UnitValue < 0:
+		return a, NewDomainError("declared unit value cannot be negative")
+	case (rule.Basis == DeclaredValueMinimum || rule.Basis == DeclaredValueFixed) && rule.UnitValue == 0:
+		return a, NewDomainError("declared value basis " + string(rule.Basis) + " needs a unit value")
+	case rule.Basis == "" || rule.Basis == DeclaredValueTransaction:
+		rule.UnitValue = 0
+	}
+	return a, nil
+}
+
+// IsEmpty reports whether no attribute is set
+func (a CustomsAttributes) IsEmpty() bool {
+	return a.HSCode == "" && a.OriginCountry == "" && a.Description == "" && a.DeclaredValue.Basis == ""
+}
+
+// Inherit fills the attributes left empty from fallback ones, e.g. a SKU's from its product
+func (a CustomsAttributes) Inherit(fallback CustomsAttributes) CustomsAttributes {
+	if a.HSCode == "" {
+		a.HSCode = fallback.HSCode
+	}
+	if a.OriginCountry == "" {
+		a.OriginCountry = fallback.OriginCountry
+	}
+	if a.Description == "" {
+		a.Description = fallback.Description
+	}
+	if a.DeclaredValue.Basis == "" {
+		a.DeclaredValue = fallback.DeclaredValue
+	}
+	return a
+}
+
+// ProductCustoms is the customs classification of a product, used to estimate the
+// duties of shipping it abroad and to declare it to customs
+type ProductCustoms struct {
+	ProductID int64
+	CustomsAttributes
+	UpdatedAt time.Time
+}
+
+// NewProductCustoms creates the customs classification of a product; it needs an
+// HS code, and declares the price paid unless told otherwise
+func NewProductCustoms(productID int64, attrs CustomsAttributes) (*ProductCustoms, error) {
+	if productID == 0 {
+		return nil, NewDomainError("ProductID cannot be zero for ProductCustoms")
+	}
+	attrs, err := attrs.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if attrs.HSCode == "" {
+		return nil, NewDomainError("HS code must have 6 to 10 digits")
+	}
+	if attrs.DeclaredValue.Basis == "" {
+		attrs.DeclaredValue.Basis = DeclaredValueTransaction
+	}
+	return &ProductCustoms{
+		ProductID:         productID,
+		CustomsAttributes: attrs,
+		UpdatedAt:         time.Now(),
+	}, nil
+}
+
+// SKUCustoms overrides the customs attributes of a SKU whose variant differs from
+// its product, e.g. one made in another country; empty attributes are inherited
+type SKUCustoms struct {
+	SKUID int64
+	CustomsAttributes
+	UpdatedAt time.Time
+}
+
+// NewSKUCustoms creates the customs overrides of a SKU
+func NewSKUCustoms(skuID int64, attrs CustomsAttributes) (*SKUCustoms, error) {
+	if skuID == 0 {
+		return nil, NewDomainError("SKUID cannot be zero for SKUCustoms")
+	}
+	attrs, err := attrs.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if attrs.IsEmpty() {
+		return nil, NewDomainError("SKU customs must override at least one attribute")
+	}
+	return &SKUCustoms{
+		SKUID:             skuID,
+		CustomsAttributes: attrs,
+		UpdatedAt:         time.Now(),
+	}, nil
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"strconv"
+
+	"github.com/qhato/ecommerce/pkg/event"
+)
+
+// EventTaxDetailChanged is published whenever a tax detail (jurisdiction rate) changes
+const EventTaxDetailChanged = "tax.detail.changed"
+
+func init() {
+	event.RegisterType(EventTaxDetailChanged, func() event.Event { return &TaxDetailChangedEvent{} })
+}
+
+// TaxDetailChangedEvent signals that cached tax rates are stale
+type TaxDetailChangedEvent struct {
+	event.BaseEvent
+	TaxDetailID int64 `json:"tax_detail_id"`
+}
+
+// NewTaxDetailChangedEvent creates a new TaxDetailChangedEvent
+func NewTaxDetailChangedEvent(taxDetailID int64) *TaxDetailChangedEvent {
+	return &TaxDetailChangedEvent{
+		BaseEvent:   event.NewBaseEvent(EventTaxDetailChanged, strconv.FormatInt(taxDetailID, 10), nil),
+		TaxDetailID: taxDetailID,
+	}
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// DutyRate is the duty a destination country levies on goods whose HS code starts
+// with a prefix. The rate with the longest matching prefix applies; a rate for
+// the goods' origin country takes precedence over one for any origin.
+type DutyRate struct {
+	ID                 int64
+	DestinationCountry string  // ISO alpha-2
+	HSCodePrefix       string  // 2 to 10 digits; empty is the country's default rate
+	OriginCountry      string  // Empty applies to goods of any origin, e.g. a trade agreement rate otherwise
+	Rate               float64 // Fraction of the customs value, e.g. 0.12
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// NewDutyRate creates a new DutyRate
+func NewDutyRate(destinationCountry, hsCodePrefix, originCountry string, rate float64) (*DutyRate, error) {
+	destinationCountry = strings.ToUpper(strings.TrimSpace(destinationCountry))
+	if len(destinationCountry) != 2 {
+		return nil, NewDomainError("destination country must be an ISO alpha-2 code")
+	}
+	hsCodePrefix = NormalizeHSCode(hsCodePrefix)
+	if hsCodePrefix != "" && (len(hsCodePrefix) < 2 || len(hsCodePrefix) > 10 || !isDigits(hsCodePrefix)) {
+		return nil, NewDomainError("HS code prefix must have 2 to 10 digits")
+	}
+	originCountry = strings.ToUpper(strings.TrimSpace(originCountry))
+	if originCountry != "" && len(originCountry) != 2 {
+		return nil, NewDomainError("origin country must be an ISO alpha-2 code")
+	}
+	if rate < 0 {
+		return nil, NewDomainError("duty rate cannot be negative")
+	}
+	now := time.Now()
+	return &DutyRate{
+		DestinationCountry: destinationCountry,
+		HSCodePrefix:       hsCodePrefix,
+		OriginCountry:      originCountry,
+		Rate:               rate,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}, nil
+}
+
+// MatchDutyRate returns the rate of a country's rates that applies to goods of an
+// HS code and origin, nil when none does
+func MatchDutyRate(rates []*DutyRate, hsCode, originCountry string) *DutyRate {
+	var match *DutyRate
+	for _, rate := range rates {
+		if !strings.HasPrefix(hsCode, rate.HSCodePrefix) {
+			continue
+		}
+		if rate.OriginCountry != "" && rate.OriginCountry != originCountry {
+			continue
+		}
+		if match == nil || len(rate.HSCodePrefix) > len(match.HSCodePrefix) ||
+			len(rate.HSCodePrefix) == len(match.HSCodePrefix) && match.OriginCountry == "" && rate.OriginCountry != "" {
+			match = rate
+		}
+	}
+	return match
+}
+
+// ImportRule holds the import tax and fees a destination country levies on top
+// of duties
+type ImportRule struct {
+	Country       string  // ISO alpha-2
+	CurrencyCode  string  // Currency of DeMinimis and ClearanceFee
+	DeMinimis     float64 // Shipments valued below it owe no duty or import tax; 0 disables it
+	ImportTaxRate float64 // VAT or GST levied on the customs value, shipping and duty
+	ClearanceFee  float64 // Charged once per shipment that owes duty or import tax
+	UpdatedAt     time.Time
+}
+
+// NewImportRule creates the import rule of a country
+func NewImportRule(country, currencyCode string, deMinimis, importTaxRate, clearanceFee float64) (*ImportRule, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if len(country) != 2 {
+		return nil, NewDomainError("country must be an ISO alpha-2 code")
+	}
+	currencyCode = strings.ToUpper(strings.TrimSpace(currencyCode))
+	if len(currencyCode) != 3 {
+		return nil, NewDomainError("currency code must be an ISO 4217 code")
+	}
+	if deMinimis < 0 || importTaxRate < 0 || clearanceFee < 0 {
+		return nil, NewDomainError("de minimis, import tax rate and clearance fee cannot be negative")
+	}
+	return &ImportRule{
+		Country:       country,
+		CurrencyCode:  currencyCode,
+		DeMinimis:     deMinimis,
+		ImportTaxRate: importTaxRate,
+		ClearanceFee:  clearanceFee,
+		UpdatedAt:     time.Now(),
+	}, nil
+}
+
+// NormalizeHSCode drops the dots and spaces HS codes are often written with
+func NormalizeHSCode(hsCode string) string {
+	return strings.NewReplacer(".", "", " ", "").Replace(strings.TrimSpace(hsCode))
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// LandedCostRepository defines the interface for customs classification, duty
+// table and import rule persistence
+type LandedCostRepository interface {
+	// SaveProductCustoms stores the customs classification of a product, replacing any previous one.
+	SaveProductCustoms(ctx context.Context, customs *ProductCustoms) error
+
+	// FindProductCustoms retrieves the customs classifications of products by product ID;
+	// unclassified products are left out.
+	FindProductCustoms(ctx context.Context, productIDs []int64) (map[int64]*ProductCustoms, error)
+
+	// DeleteProductCustoms removes the customs classification of a product.
+	DeleteProductCustoms(ctx context.Context, productID int64) error
+
+	// SaveSKUCustoms stores the customs overrides of a SKU, replacing any previous ones.
+	SaveSKUCustoms(ctx context.Context, customs *SKUCustoms) error
+
+	// FindSKUCustoms retrieves the customs overrides of SKUs by SKU ID; SKUs without
+	// overrides are left out.
+	FindSKUCustoms(ctx context.Context, skuIDs []int64) (map[int64]*SKUCustoms, error)
+
+	// DeleteSKUCustoms removes the customs overrides of a SKU.
+	DeleteSKUCustoms(ctx context.Context, skuID int64) error
+
+	// SaveCustoms stores the customs attributes of several products and SKUs at
+	// once; none are stored if any fails.
+	SaveCustoms(ctx context.Context, products []*ProductCustoms, skus []*SKUCustoms) error
+
+	// SaveDutyRate stores a duty rate, replacing the rate of the same country, HS code prefix and origin.
+	SaveDutyRate(ctx context.Context, rate *DutyRate) error
+
+	// DeleteDutyRate removes a duty rate.
+	DeleteDutyRate(ctx context.Context, id int64) error
+
+	// FindDutyRates retrieves the duty rates of a destination country.
+	FindDutyRates(ctx context.Context, destinationCountry string) ([]*DutyRate, error)
+
+	// SaveImportRule stores the import rule of a country, replacing any previous one.
+	SaveImportRule(ctx context.Context, rule *ImportRule) error
+
+	// FindImportRule retrieves the import rule of a country, nil if it has none.
+	FindImportRule(ctx context.Context, country string) (*ImportRule, error)
+
+	// FindImportRules retrieves the import rules of all countries.
+	FindImportRules(ctx context.Context) ([]*ImportRule, error)
+}
@@ -0,0 +1,298 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/tax/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresLandedCostRepository implements the LandedCostRepository interface
+type PostgresLandedCostRepository struct {
+	db *database.DB
+}
+
+// NewPostgresLandedCostRepository creates a new PostgresLandedCostRepository
+func NewPostgresLandedCostRepository(db *database.DB) *PostgresLandedCostRepository {
+	return &PostgresLandedCostRepository{db: db}
+}
+
+// SaveProductCustoms stores the customs classification of a product, replacing any previous one
+func (r *PostgresLandedCostRepository) SaveProductCustoms(ctx context.Context, customs *domain.ProductCustoms) error {
+	if err := r.db.Exec(ctx, saveProductCustomsQuery, productCustomsArgs(customs)...); err != nil {
+		return errors.InternalWrap(err, "failed to save product customs")
+	}
+	return nil
+}
+
+// FindProductCustoms retrieves the customs classifications of products by product ID
+func (r *PostgresLandedCostRepository) FindProductCustoms(ctx context.Context, productIDs []int64) (map[int64]*domain.ProductCustoms, error) {
+	customs := make(map[int64]*domain.ProductCustoms, len(productIDs))
+	if len(productIDs) == 0 {
+		return customs, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT product_id, hs_code, origin_country, description, declared_value_basis,
+			declared_unit_value::float8, updated_at
+		FROM product_customs
+		WHERE product_id = ANY($1)`, productIDs)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find product customs")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := &domain.ProductCustoms{}
+		var basis string
+		if err := rows.Scan(&c.ProductID, &c.HSCode, &c.OriginCountry, &c.Description, &basis,
+			&c.DeclaredValue.UnitValue, &c.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan product customs")
+		}
+		c.DeclaredValue.Basis = domain.DeclaredValueBasis(basis)
+		customs[c.ProductID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate product customs")
+	}
+	return customs, nil
+}
+
+// DeleteProductCustoms removes the customs classification of a product
+func (r *PostgresLandedCostRepository) DeleteProductCustoms(ctx context.Context, productID int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM product_customs WHERE product_id = $1`, productID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete product customs")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("customs classification of product %d", productID))
+	}
+	return nil
+}
+
+// SaveSKUCustoms stores the customs overrides of a SKU, replacing any previous ones
+func (r *PostgresLandedCostRepository) SaveSKUCustoms(ctx context.Context, customs *domain.SKUCustoms) error {
+	if err := r.db.Exec(ctx, saveSKUCustomsQuery, skuCustomsArgs(customs)...); err != nil {
+		return errors.InternalWrap(err, "failed to save SKU customs")
+	}
+	return nil
+}
+
+// FindSKUCustoms retrieves the customs overrides of SKUs by SKU ID
+func (r *PostgresLandedCostRepository) FindSKUCustoms(ctx context.Context, skuIDs []int64) (map[int64]*domain.SKUCustoms, error) {
+	customs := make(map[int64]*domain.SKUCustoms, len(skuIDs))
+	if len(skuIDs) == 0 {
+		return customs, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT sku_id, hs_code, origin_country, description, declared_value_basis,
+			declared_unit_value::float8, updated_at
+		FROM sku_customs
+		WHERE sku_id = ANY($1)`, skuIDs)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find SKU customs")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := &domain.SKUCustoms{}
+		var basis string
+		if err := rows.Scan(&c.SKUID, &c.HSCode, &c.OriginCountry, &c.Description, &basis,
+			&c.DeclaredValue.UnitValue, &c.UpdatedAt); err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan SKU customs")
+		}
+		c.DeclaredValue.Basis = domain.DeclaredValueBasis(basis)
+		customs[c.SKUID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate SKU customs")
+	}
+	return customs, nil
+}
+
+// DeleteSKUCustoms removes the customs overrides of a SKU
+func (r *PostgresLandedCostRepository) DeleteSKUCustoms(ctx context.Context, skuID int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM sku_customs WHERE sku_id = $1`, skuID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete SKU customs")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("customs overrides of SKU %d", skuID))
+	}
+	return nil
+}
+
+// SaveCustoms stores the customs attributes of several products and SKUs in one transaction
+func (r *PostgresLandedCostRepository) SaveCustoms(ctx context.Context, products []*domain.ProductCustoms, skus []*domain.SKUCustoms) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to begin transaction")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, customs := range products {
+		if _, err := tx.Exec(ctx, saveProductCustomsQuery, productCustomsArgs(customs)...); err != nil {
+			return errors.InternalWrap(err, fmt.Sprintf("failed to save customs of product %d", customs.ProductID))
+		}
+	}
+	for _, customs := range skus {
+		if _, err := tx.Exec(ctx, saveSKUCustomsQuery, skuCustomsArgs(customs)...); err != nil {
+			return errors.InternalWrap(err, fmt.Sprintf("failed to save customs of SKU %d", customs.SKUID))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.InternalWrap(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+const saveProductCustomsQuery = `
+	INSERT INTO product_customs (product_id, hs_code, origin_country, description, declared_value_basis, declared_unit_value, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (product_id) DO UPDATE
+	SET hs_code = EXCLUDED.hs_code, origin_country = EXCLUDED.origin_country, description = EXCLUDED.description,
+		declared_value_basis = EXCLUDED.declared_value_basis, declared_unit_value = EXCLUDED.declared_unit_value,
+		updated_at = EXCLUDED.updated_at`
+
+const saveSKUCustomsQuery = `
+	INSERT INTO sku_customs (sku_id, hs_code, origin_country, description, declared_value_basis, declared_unit_value, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (sku_id) DO UPDATE
+	SET hs_code = EXCLUDED.hs_code, origin_country = EXCLUDED.origin_country, description = EXCLUDED.description,
+		declared_value_basis = EXCLUDED.declared_value_basis, declared_unit_value = EXCLUDED.declared_unit_value,
+		updated_at = EXCLUDED.updated_at`
+
+func productCustomsArgs(c *domain.ProductCustoms) []interface{} {
+	return []interface{}{c.ProductID, c.HSCode, c.OriginCountry, c.Description, string(c.DeclaredValue.Basis), c.DeclaredValue.UnitValue, c.UpdatedAt}
+}
+
+func skuCustomsArgs(c *domain.SKUCustoms) []interface{} {
+	return []interface{}{c.SKUID, c.HSCode, c.OriginCountry, c.Description, string(c.DeclaredValue.Basis), c.DeclaredValue.UnitValue, c.UpdatedAt}
+}
+
+// SaveDutyRate stores a duty rate, replacing the rate of the same country, HS code prefix and origin
+func (r *PostgresLandedCostRepository) SaveDutyRate(ctx context.Context, rate *domain.DutyRate) error {
+	query := `
+		INSERT INTO duty_rate (destination_country, hs_code_prefix, origin_country, rate, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (destination_country, hs_code_prefix, origin_country) DO UPDATE
+		SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at
+		RETURNING duty_rate_id, created_at`
+
+	err := r.db.QueryRow(ctx, query,
+		rate.DestinationCountry, rate.HSCodePrefix, rate.OriginCountry, rate.Rate, rate.CreatedAt, rate.UpdatedAt,
+	).Scan(&rate.ID, &rate.CreatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save duty rate")
+	}
+	return nil
+}
+
+// DeleteDutyRate removes a duty rate
+func (r *PostgresLandedCostRepository) DeleteDutyRate(ctx context.Context, id int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM duty_rate WHERE duty_rate_id = $1`, id)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete duty rate")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound(fmt.Sprintf("duty rate %d", id))
+	}
+	return nil
+}
+
+// FindDutyRates retrieves the duty rates of a destination country, by HS code prefix
+func (r *PostgresLandedCostRepository) FindDutyRates(ctx context.Context, destinationCountry string) ([]*domain.DutyRate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT duty_rate_id, destination_country, hs_code_prefix, origin_country, rate::float8, created_at, updated_at
+		FROM duty_rate
+		WHERE destination_country = $1
+		ORDER BY hs_code_prefix, origin_country`, destinationCountry)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find duty rates")
+	}
+	defer rows.Close()
+
+	rates := make([]*domain.DutyRate, 0)
+	for rows.Next() {
+		rate := &domain.DutyRate{}
+		err := rows.Scan(&rate.ID, &rate.DestinationCountry, &rate.HSCodePrefix, &rate.OriginCountry, &rate.Rate, &rate.CreatedAt, &rate.UpdatedAt)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan duty rate")
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate duty rates")
+	}
+	return rates, nil
+}
+
+// SaveImportRule stores the import rule of a country, replacing any previous one
+func (r *PostgresLandedCostRepository) SaveImportRule(ctx context.Context, rule *domain.ImportRule) error {
+	query := `
+		INSERT INTO import_rule (country, currency_code, de_minimis, import_tax_rate, clearance_fee, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (country) DO UPDATE
+		SET currency_code = EXCLUDED.currency_code, de_minimis = EXCLUDED.de_minimis,
+			import_tax_rate = EXCLUDED.import_tax_rate, clearance_fee = EXCLUDED.clearance_fee,
+			updated_at = EXCLUDED.updated_at`
+
+	err := r.db.Exec(ctx, query,
+		rule.Country, rule.CurrencyCode, rule.DeMinimis, rule.ImportTaxRate, rule.ClearanceFee, rule.UpdatedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save import rule")
+	}
+	return nil
+}
+
+const importRuleColumns = `country, currency_code, de_minimis::float8, import_tax_rate::float8, clearance_fee::float8, updated_at`
+
+// FindImportRule retrieves the import rule of a country, nil if it has none
+func (r *PostgresLandedCostRepository) FindImportRule(ctx context.Context, country string) (*domain.ImportRule, error) {
+	rule, err := scanImportRule(r.db.QueryRow(ctx, `SELECT `+importRuleColumns+` FROM import_rule WHERE country = $1`, country))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find import rule")
+	}
+	return rule, nil
+}
+
+// FindImportRules retrieves the import rules of all countries, by country
+func (r *PostgresLandedCostRepository) FindImportRules(ctx context.Context) ([]*domain.ImportRule, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+importRuleColumns+` FROM import_rule ORDER BY country`)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find import rules")
+	}
+	defer rows.Close()
+
+	rules := make([]*domain.ImportRule, 0)
+	for rows.Next() {
+		rule, err := scanImportRule(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan import rule")
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate import rules")
+	}
+	return rules, nil
+}
+
+func scanImportRule(row pgx.Row) (*domain.ImportRule, error) {
+	rule := &domain.ImportRule{}
+	err := row.Scan(&rule.Country, &rule.CurrencyCode, &rule.DeMinimis, &rule.ImportTaxRate, &rule.ClearanceFee, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
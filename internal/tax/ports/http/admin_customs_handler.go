@@ -0,0 +1,262 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/tax/application"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminCustomsHandler handles the customs classification of products and the
+// duty tables and import rules duties of cross-border orders are estimated from
+type AdminCustomsHandler struct {
+	landedCostService application.LandedCostService
+	authMiddleware    func(http.Handler) http.Handler
+	log               *logger.Logger
+}
+
+// NewAdminCustomsHandler creates a new AdminCustomsHandler
+func NewAdminCustomsHandler(
+	landedCostService application.LandedCostService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminCustomsHandler {
+	return &AdminCustomsHandler{
+		landedCostService: landedCostService,
+		authMiddleware:    authMiddleware,
+		log:               log,
+	}
+}
+
+// RegisterRoutes registers customs routes
+func (h *AdminCustomsHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/products/{id}/customs", h.GetProductCustoms)
+		r.Put("/admin/products/{id}/customs", h.SetProductCustoms)
+		r.Delete("/admin/products/{id}/customs", h.DeleteProductCustoms)
+		r.Get("/admin/duty-rates", h.ListDutyRates)
+		r.Put("/admin/duty-rates", h.SaveDutyRate)
+		r.Delete("/admin/duty-rates/{id}", h.DeleteDutyRate)
+		r.Get("/admin/import-rules", h.ListImportRules)
+		r.Put("/admin/import-rules/{country}", h.SetImportRule)
+	})
+}
+
+// GetProductCustoms retrieves the customs classification of a product
+func (h *AdminCustomsHandler) GetProductCustoms(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	customs, err := h.landedCostService.GetProductCustoms(r.Context(), productID)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, customs)
+}
+
+// SetProductCustoms sets the customs classification of a product, e.g. {"hs_code": "6109.10",
+// "origin_country": "PT", "description": "Men's cotton T-shirt", "declared_value_basis": "TRANSACTION"}
+func (h *AdminCustomsHandler) SetProductCustoms(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	var req application.SetProductCustomsRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	customs, err := h.landedCostService.SetProductCustoms(r.Context(), productID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("product_id", productID).Error("failed to set product customs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, customs)
+}
+
+// DeleteProductCustoms removes the customs classification of a product
+func (h *AdminCustomsHandler) DeleteProductCustoms(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid product ID"))
+		return
+	}
+
+	if err := h.landedCostService.DeleteProductCustoms(r.Context(), productID); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSKUCustoms retrieves the customs overrides of a SKU
+func (h *AdminCustomsHandler) GetSKUCustoms(w http.ResponseWriter, r *http.Request) {
+	skuID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid SKU ID"))
+		return
+	}
+
+	customs, err := h.landedCostService.GetSKUCustoms(r.Context(), skuID)
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, customs)
+}
+
+// SetSKUCustoms sets the customs attributes a SKU declares instead of its product's,
+// e.g. {"origin_country": "VN"}
+func (h *AdminCustomsHandler) SetSKUCustoms(w http.ResponseWriter, r *http.Request) {
+	skuID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid SKU ID"))
+		return
+	}
+
+	var req application.SetSKUCustomsRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	customs, err := h.landedCostService.SetSKUCustoms(r.Context(), skuID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("sku_id", skuID).Error("failed to set SKU customs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, customs)
+}
+
+// DeleteSKUCustoms removes the customs overrides of a SKU
+func (h *AdminCustomsHandler) DeleteSKUCustoms(w http.ResponseWriter, r *http.Request) {
+	skuID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid SKU ID"))
+		return
+	}
+
+	if err := h.landedCostService.DeleteSKUCustoms(r.Context(), skuID); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BulkSetCustoms sets the customs attributes of many products and SKUs at once,
+// e.g. {"items": [{"product_id": 1, "hs_code": "610910"}, {"sku_id": 7, "origin_country": "VN"}]}.
+// Nothing is saved if any item is invalid; the errors name the items by index.
+func (h *AdminCustomsHandler) BulkSetCustoms(w http.ResponseWriter, r *http.Request) {
+	var req application.BulkSetCustomsRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	result, err := h.landedCostService.BulkSetCustoms(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).WithField("items", len(req.Items)).Error("failed to bulk set customs")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, result)
+}
+
+// ListDutyRates lists the duty rates of a destination country (?country=GB)
+func (h *AdminCustomsHandler) ListDutyRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.landedCostService.ListDutyRates(r.Context(), r.URL.Query().Get("country"))
+	if err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, rates)
+}
+
+// SaveDutyRate sets a duty rate, e.g. {"destination_country": "GB", "hs_code_prefix": "6109", "rate": 0.12}
+func (h *AdminCustomsHandler) SaveDutyRate(w http.ResponseWriter, r *http.Request) {
+	var req application.SaveDutyRateRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	rate, err := h.landedCostService.SaveDutyRate(r.Context(), &req)
+	if err != nil {
+		h.log.WithError(err).WithField("destination_country", req.DestinationCountry).Error("failed to save duty rate")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, rate)
+}
+
+// DeleteDutyRate removes a duty rate
+func (h *AdminCustomsHandler) DeleteDutyRate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		pkghttp.RespondError(w, pkghttp.NewValidationError("invalid duty rate ID"))
+		return
+	}
+
+	if err := h.landedCostService.DeleteDutyRate(r.Context(), id); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListImportRules lists the import rules of all countries
+func (h *AdminCustomsHandler) ListImportRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.landedCostService.ListImportRules(r.Context())
+	if err != nil {
+		h.log.WithError(err).Error("failed to list import rules")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, rules)
+}
+
+// SetImportRule sets the import rule of a country,
+// e.g. {"currency_code": "GBP", "de_minimis": 135, "import_tax_rate": 0.2, "clearance_fee": 8}
+func (h *AdminCustomsHandler) SetImportRule(w http.ResponseWriter, r *http.Request) {
+	country := strings.ToUpper(chi.URLParam(r, "country"))
+
+	var req application.SetImportRuleRequest
+	if err := pkghttp.DecodeJSON(r, &req); err != nil {
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	rule, err := h.landedCostService.SetImportRule(r.Context(), country, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("country", country).Error("failed to set import rule")
+		pkghttp.RespondError(w, err)
+		return
+	}
+
+	pkghttp.RespondJSON(w, http.StatusOK, rule)
+}
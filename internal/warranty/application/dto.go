@@ -0,0 +1,120 @@
+package application
+
+import (
+	"time"
+
+	"github.com/qhato/ecommerce/internal/warranty/domain"
+)
+
+// WarrantyTermsDTO represents the warranty of a product
+type WarrantyTermsDTO struct {
+	ProductID      int64     `json:"product_id"`
+	DurationMonths int       `json:"duration_months"`
+	Description    string    `json:"description,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// SaveWarrantyTermsRequest is the payload to set the warranty of a product
+type SaveWarrantyTermsRequest struct {
+	DurationMonths int    `json:"duration_months" validate:"required,min=1"`
+	Description    string `json:"description"`
+}
+
+// RegisterProductRequest is the payload of a customer registering a purchased unit
+type RegisterProductRequest struct {
+	OrderItemID  int64  `json:"order_item_id" validate:"required"`
+	SerialNumber string `json:"serial_number"` // Required for items shipped with serials
+}
+
+// RegistrationDTO represents a registered product and the state of its warranty
+type RegistrationDTO struct {
+	ID                int64      `json:"id"`
+	CustomerID        int64      `json:"customer_id"`
+	OrderID           int64      `json:"order_id"`
+	OrderItemID       int64      `json:"order_item_id"`
+	ProductID         int64      `json:"product_id"`
+	SKUID             int64      `json:"sku_id"`
+	ProductName       string     `json:"product_name"`
+	SerialNumber      string     `json:"serial_number,omitempty"`
+	PurchasedAt       time.Time  `json:"purchased_at"`
+	WarrantyMonths    int        `json:"warranty_months"`
+	WarrantyExpiresAt *time.Time `json:"warranty_expires_at,omitempty"`
+	WarrantyStatus    string     `json:"warranty_status"`
+	DaysRemaining     int        `json:"days_remaining"`
+	RegisteredAt      time.Time  `json:"registered_at"`
+}
+
+// RegistrableItemDTO is an item of a customer's order history with units
+// left to register
+type RegistrableItemDTO struct {
+	OrderID       int64             `json:"order_id"`
+	OrderNumber   string            `json:"order_number"`
+	OrderItemID   int64             `json:"order_item_id"`
+	ProductID     int64             `json:"product_id"`
+	SKUID         int64             `json:"sku_id"`
+	ProductName   string            `json:"product_name"`
+	PurchasedAt   time.Time         `json:"purchased_at"`
+	Quantity      int               `json:"quantity"`
+	Registered    int               `json:"registered"`
+	SerialNumbers []string          `json:"serial_numbers,omitempty"` // Shipped serials not registered yet
+	Warranty      *WarrantyTermsDTO `json:"warranty,omitempty"`
+}
+
+// RegistrationSearchQuery represents an admin search of registrations
+type RegistrationSearchQuery struct {
+	Page         int
+	PageSize     int
+	CustomerID   int64
+	OrderID      int64
+	ProductID    int64
+	SerialNumber string
+}
+
+// RegistrationSearchResultDTO represents a page of registrations
+type RegistrationSearchResultDTO struct {
+	Data       []*RegistrationDTO `json:"data"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	TotalItems int64              `json:"total_items"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// ToWarrantyTermsDTO converts warranty terms to their DTO
+func ToWarrantyTermsDTO(t *domain.WarrantyTerms) *WarrantyTermsDTO {
+	return &WarrantyTermsDTO{
+		ProductID:      t.ProductID,
+		DurationMonths: t.DurationMonths,
+		Description:    t.Description,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
+
+// ToRegistrationDTO converts a registration to its DTO, with the state of
+// its warranty at a time
+func ToRegistrationDTO(r *domain.ProductRegistration, at time.Time) *RegistrationDTO {
+	return &RegistrationDTO{
+		ID:                r.ID,
+		CustomerID:        r.CustomerID,
+		OrderID:           r.OrderID,
+		OrderItemID:       r.OrderItemID,
+		ProductID:         r.ProductID,
+		SKUID:             r.SKUID,
+		ProductName:       r.ProductName,
+		SerialNumber:      r.SerialNumber,
+		PurchasedAt:       r.PurchasedAt,
+		WarrantyMonths:    r.WarrantyMonths,
+		WarrantyExpiresAt: r.WarrantyExpiresAt,
+		WarrantyStatus:    string(r.Status(at)),
+		DaysRemaining:     r.DaysRemaining(at),
+		RegisteredAt:      r.RegisteredAt,
+	}
+}
+
+// ToRegistrationDTOs converts registrations to their DTOs
+func ToRegistrationDTOs(registrations []*domain.ProductRegistration, at time.Time) []*RegistrationDTO {
+	dtos := make([]*RegistrationDTO, len(registrations))
+	for i, r := range registrations {
+		dtos[i] = ToRegistrationDTO(r, at)
+	}
+	return dtos
+}
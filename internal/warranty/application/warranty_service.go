@@ -0,0 +1,347 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fulfillmentDomain "github.com/qhato/ecommerce/internal/fulfillment/domain"
+	orderDomain "github.com/qhato/ecommerce/internal/order/domain"
+	"github.com/qhato/ecommerce/internal/warranty/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// SerialNumberLookup returns the serial numbers shipped for each item of an
+// order, by order item ID; the fulfillment serial number service implements it.
+type SerialNumberLookup interface {
+	OrderSerialNumbers(ctx context.Context, orderID int64) (map[int64][]string, error)
+}
+
+// WarrantyService manages the warranty terms of products and the products
+// customers register from their order history, and answers support lookups
+// of registrations.
+type WarrantyService interface {
+	// GetTerms retrieves the warranty terms of a product.
+	GetTerms(ctx context.Context, productID int64) (*WarrantyTermsDTO, error)
+
+	// ListTerms lists the warranty terms of every product.
+	ListTerms(ctx context.Context) ([]*WarrantyTermsDTO, error)
+
+	// SaveTerms sets the warranty terms of a product. Products registered
+	// already keep the warranty they were registered with.
+	SaveTerms(ctx context.Context, productID int64, req *SaveWarrantyTermsRequest) (*WarrantyTermsDTO, error)
+
+	// DeleteTerms removes the warranty terms of a product.
+	DeleteTerms(ctx context.Context, productID int64) error
+
+	// RegistrableItems lists the items of a customer's orders with units left to register.
+	RegistrableItems(ctx context.Context, customerID int64) ([]*RegistrableItemDTO, error)
+
+	// RegisterProduct registers a unit of an item of one of the customer's orders.
+	RegisterProduct(ctx context.Context, customerID int64, req *RegisterProductRequest) (*RegistrationDTO, error)
+
+	// ListRegistrations lists the registrations of a customer.
+	ListRegistrations(ctx context.Context, customerID int64) ([]*RegistrationDTO, error)
+
+	// GetCustomerRegistration retrieves a registration of a customer.
+	GetCustomerRegistration(ctx context.Context, customerID, id int64) (*RegistrationDTO, error)
+
+	// GetRegistration retrieves any registration.
+	GetRegistration(ctx context.Context, id int64) (*RegistrationDTO, error)
+
+	// SearchRegistrations searches registrations by customer, order, product or serial number.
+	SearchRegistrations(ctx context.Context, query *RegistrationSearchQuery) (*RegistrationSearchResultDTO, error)
+}
+
+type warrantyService struct {
+	termsRepo        domain.WarrantyTermsRepository
+	registrationRepo domain.ProductRegistrationRepository
+	orderRepo        orderDomain.OrderRepository
+	orderItemRepo    orderDomain.OrderItemRepository
+	serialNumbers    SerialNumberLookup
+}
+
+// NewWarrantyService creates a new instance of WarrantyService. serialNumbers
+// may be nil when serials are not tracked.
+func NewWarrantyService(
+	termsRepo domain.WarrantyTermsRepository,
+	registrationRepo domain.ProductRegistrationRepository,
+	orderRepo orderDomain.OrderRepository,
+	orderItemRepo orderDomain.OrderItemRepository,
+	serialNumbers SerialNumberLookup,
+) WarrantyService {
+	return &warrantyService{
+		termsRepo:        termsRepo,
+		registrationRepo: registrationRepo,
+		orderRepo:        orderRepo,
+		orderItemRepo:    orderItemRepo,
+		serialNumbers:    serialNumbers,
+	}
+}
+
+func (s *warrantyService) GetTerms(ctx context.Context, productID int64) (*WarrantyTermsDTO, error) {
+	terms, err := s.termsRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if terms == nil {
+		return nil, errors.NotFound("warranty terms")
+	}
+	return ToWarrantyTermsDTO(terms), nil
+}
+
+func (s *warrantyService) ListTerms(ctx context.Context) ([]*WarrantyTermsDTO, error) {
+	terms, err := s.termsRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*WarrantyTermsDTO, len(terms))
+	for i, t := range terms {
+		dtos[i] = ToWarrantyTermsDTO(t)
+	}
+	return dtos, nil
+}
+
+func (s *warrantyService) SaveTerms(ctx context.Context, productID int64, req *SaveWarrantyTermsRequest) (*WarrantyTermsDTO, error) {
+	terms, err := s.termsRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if terms == nil {
+		terms, err = domain.NewWarrantyTerms(productID, req.DurationMonths, req.Description)
+	} else {
+		err = terms.Update(req.DurationMonths, req.Description)
+	}
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+
+	if err := s.termsRepo.Save(ctx, terms); err != nil {
+		return nil, err
+	}
+	return ToWarrantyTermsDTO(terms), nil
+}
+
+func (s *warrantyService) DeleteTerms(ctx context.Context, productID int64) error {
+	return s.termsRepo.Delete(ctx, productID)
+}
+
+func (s *warrantyService) RegistrableItems(ctx context.Context, customerID int64) ([]*RegistrableItemDTO, error) {
+	orders, _, err := s.orderRepo.FindByCustomerID(ctx, customerID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders: %w", err)
+	}
+
+	items := make([]*RegistrableItemDTO, 0)
+	for _, order := range orders {
+		if !isRegistrable(order) {
+			continue
+		}
+		orderItems, err := s.orderItemRepo.FindByOrderID(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order items: %w", err)
+		}
+		if len(orderItems) == 0 {
+			continue
+		}
+
+		itemIDs := make([]int64, 0, len(orderItems))
+		productIDs := make([]int64, 0, len(orderItems))
+		for _, item := range orderItems {
+			itemIDs = append(itemIDs, item.ID)
+			productIDs = append(productIDs, item.ProductID)
+		}
+		registrations, err := s.registrationRepo.FindByOrderItemIDs(ctx, itemIDs)
+		if err != nil {
+			return nil, err
+		}
+		registered := make(map[int64]int)
+		registeredSerials := make(map[string]bool)
+		for _, registration := range registrations {
+			registered[registration.OrderItemID]++
+			registeredSerials[registration.SerialNumber] = true
+		}
+		terms, err := s.termsRepo.FindByProductIDs(ctx, productIDs)
+		if err != nil {
+			return nil, err
+		}
+		shipped, err := s.shippedSerials(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range orderItems {
+			if registered[item.ID] >= item.Quantity {
+				continue
+			}
+			dto := &RegistrableItemDTO{
+				OrderID:     order.ID,
+				OrderNumber: order.OrderNumber,
+				OrderItemID: item.ID,
+				ProductID:   item.ProductID,
+				SKUID:       item.SKUID,
+				ProductName: item.Name,
+				PurchasedAt: *order.SubmitDate,
+				Quantity:    item.Quantity,
+				Registered:  registered[item.ID],
+			}
+			for _, serial := range shipped[item.ID] {
+				if !registeredSerials[serial] {
+					dto.SerialNumbers = append(dto.SerialNumbers, serial)
+				}
+			}
+			if t, ok := terms[item.ProductID]; ok {
+				dto.Warranty = ToWarrantyTermsDTO(t)
+			}
+			items = append(items, dto)
+		}
+	}
+	return items, nil
+}
+
+func (s *warrantyService) RegisterProduct(ctx context.Context, customerID int64, req *RegisterProductRequest) (*RegistrationDTO, error) {
+	if req.OrderItemID <= 0 {
+		return nil, errors.ValidationError("order_item_id is required")
+	}
+	item, err := s.orderItemRepo.FindByID(ctx, req.OrderItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order item: %w", err)
+	}
+	if item == nil {
+		return nil, errors.NotFound("order item")
+	}
+	order, err := s.orderRepo.FindByID(ctx, item.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	// Items of other customers' orders are reported as missing
+	if order == nil || order.CustomerID != customerID {
+		return nil, errors.NotFound("order item")
+	}
+	if !isRegistrable(order) {
+		return nil, errors.Conflict("only items of placed orders can be registered")
+	}
+
+	registrations, err := s.registrationRepo.FindByOrderItemIDs(ctx, []int64{item.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(registrations) >= item.Quantity {
+		return nil, errors.Conflict("every unit of this item is registered already")
+	}
+
+	serialNumber := fulfillmentDomain.NormalizeSerialNumber(req.SerialNumber)
+	shipped, err := s.shippedSerials(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Units shipped with serials are registered by one of them
+	if serials := shipped[item.ID]; len(serials) > 0 && !contains(serials, serialNumber) {
+		if serialNumber == "" {
+			return nil, errors.ValidationError("serial number is required for this item")
+		}
+		return nil, errors.ValidationError(fmt.Sprintf("serial number %s was not shipped with this item", serialNumber))
+	}
+
+	terms, err := s.termsRepo.FindByProductID(ctx, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	registration := domain.NewProductRegistration(
+		customerID, order.ID, item.ID, item.ProductID, item.SKUID, item.Name, serialNumber, *order.SubmitDate, terms,
+	)
+	if err := s.registrationRepo.Create(ctx, registration); err != nil {
+		return nil, err
+	}
+	return ToRegistrationDTO(registration, time.Now()), nil
+}
+
+func (s *warrantyService) ListRegistrations(ctx context.Context, customerID int64) ([]*RegistrationDTO, error) {
+	registrations, err := s.registrationRepo.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return ToRegistrationDTOs(registrations, time.Now()), nil
+}
+
+func (s *warrantyService) GetCustomerRegistration(ctx context.Context, customerID, id int64) (*RegistrationDTO, error) {
+	registration, err := s.registrationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if registration.CustomerID != customerID {
+		return nil, errors.NotFound("product registration")
+	}
+	return ToRegistrationDTO(registration, time.Now()), nil
+}
+
+func (s *warrantyService) GetRegistration(ctx context.Context, id int64) (*RegistrationDTO, error) {
+	registration, err := s.registrationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToRegistrationDTO(registration, time.Now()), nil
+}
+
+func (s *warrantyService) SearchRegistrations(ctx context.Context, query *RegistrationSearchQuery) (*RegistrationSearchResultDTO, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 || query.PageSize > 100 {
+		query.PageSize = 20
+	}
+
+	registrations, total, err := s.registrationRepo.Search(ctx, &domain.RegistrationFilter{
+		Page:         query.Page,
+		PageSize:     query.PageSize,
+		CustomerID:   query.CustomerID,
+		OrderID:      query.OrderID,
+		ProductID:    query.ProductID,
+		SerialNumber: fulfillmentDomain.NormalizeSerialNumber(query.SerialNumber),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize > 0 {
+		totalPages++
+	}
+	return &RegistrationSearchResultDTO{
+		Data:       ToRegistrationDTOs(registrations, time.Now()),
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// shippedSerials returns the serials shipped for each item of an order
+func (s *warrantyService) shippedSerials(ctx context.Context, orderID int64) (map[int64][]string, error) {
+	if s.serialNumbers == nil {
+		return nil, nil
+	}
+	serials, err := s.serialNumbers.OrderSerialNumbers(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serial numbers: %w", err)
+	}
+	return serials, nil
+}
+
+// isRegistrable checks if the items of an order can be registered: the order
+// was placed and was not cancelled or refunded
+func isRegistrable(order *orderDomain.Order) bool {
+	if order.SubmitDate == nil {
+		return false
+	}
+	return order.Status != orderDomain.OrderStatusCancelled && order.Status != orderDomain.OrderStatusRefunded
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
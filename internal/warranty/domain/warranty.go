@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxWarrantyMonths bounds the warranty a product can carry
+const MaxWarrantyMonths = 120
+
+// WarrantyTerms is the manufacturer or store warranty of a product, counted
+// from the purchase date
+type WarrantyTerms struct {
+	ID             int64
+	ProductID      int64
+	DurationMonths int
+	Description    string // What the warranty covers, shown to customers
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewWarrantyTerms creates the warranty terms of a product
+func NewWarrantyTerms(productID int64, durationMonths int, description string) (*WarrantyTerms, error) {
+	now := time.Now()
+	terms := &WarrantyTerms{ProductID: productID, CreatedAt: now}
+	if err := terms.Update(durationMonths, description); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+// Update replaces the duration and description of the terms
+func (t *WarrantyTerms) Update(durationMonths int, description string) error {
+	if durationMonths < 1 || durationMonths > MaxWarrantyMonths {
+		return fmt.Errorf("warranty duration must be between 1 and %d months", MaxWarrantyMonths)
+	}
+	t.DurationMonths = durationMonths
+	t.Description = strings.TrimSpace(description)
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// ExpiresAt returns when a warranty starting at start runs out
+func (t *WarrantyTerms) ExpiresAt(start time.Time) time.Time {
+	return start.AddDate(0, t.DurationMonths, 0)
+}
+
+// WarrantyStatus is the state of the warranty of a registered product
+type WarrantyStatus string
+
+const (
+	WarrantyStatusActive     WarrantyStatus = "ACTIVE"
+	WarrantyStatusExpired    WarrantyStatus = "EXPIRED"
+	WarrantyStatusNoWarranty WarrantyStatus = "NO_WARRANTY" // The product had no warranty terms when registered
+)
+
+// ProductRegistration is a purchased unit a customer registered, linked to
+// the order item it was bought with. The warranty terms in force at
+// registration are kept with it, so later changes to the terms of the product
+// do not shorten or extend it.
+type ProductRegistration struct {
+	ID                int64
+	CustomerID        int64
+	OrderID           int64
+	OrderItemID       int64
+	ProductID         int64
+	SKUID             int64
+	ProductName       string
+	SerialNumber      string // Normalized; empty for items shipped without serials
+	PurchasedAt       time.Time
+	WarrantyMonths    int        // 0 without warranty
+	WarrantyExpiresAt *time.Time // Nil without warranty
+	RegisteredAt      time.Time
+}
+
+// NewProductRegistration registers a purchased unit; terms is nil when the
+// product has no warranty
+func NewProductRegistration(customerID, orderID, orderItemID, productID, skuID int64, productName, serialNumber string, purchasedAt time.Time, terms *WarrantyTerms) *ProductRegistration {
+	registration := &ProductRegistration{
+		CustomerID:   customerID,
+		OrderID:      orderID,
+		OrderItemID:  orderItemID,
+		ProductID:    productID,
+		SKUID:        skuID,
+		ProductName:  productName,
+		SerialNumber: serialNumber,
+		PurchasedAt:  purchasedAt,
+		RegisteredAt: time.Now(),
+	}
+	if terms != nil {
+		expiresAt := terms.ExpiresAt(purchasedAt)
+		registration.WarrantyMonths = terms.DurationMonths
+		registration.WarrantyExpiresAt = &expiresAt
+	}
+	return registration
+}
+
+// Status returns the state of the warranty at a time
+func (r *ProductRegistration) Status(at time.Time) WarrantyStatus {
+	switch {
+	case r.WarrantyExpiresAt == nil:
+		return WarrantyStatusNoWarranty
+	case at.Before(*r.WarrantyExpiresAt):
+		return WarrantyStatusActive
+	default:
+		return WarrantyStatusExpired
+	}
+}
+
+// DaysRemaining returns the whole days of warranty left at a time, 0 once
+// expired or without warranty
+func (r *ProductRegistration) DaysRemaining(at time.Time) int {
+	if r.Status(at) != WarrantyStatusActive {
+		return 0
+	}
+	return int(r.WarrantyExpiresAt.Sub(at).Hours() / 24)
+}
+
+// WarrantyTermsRepository defines the interface for warranty terms persistence
+type WarrantyTermsRepository interface {
+	// Save creates or replaces the terms of a product
+	Save(ctx context.Context, terms *WarrantyTerms) error
+
+	// Delete removes the terms of a product
+	Delete(ctx context.Context, productID int64) error
+
+	// FindByProductID retrieves the terms of a product, nil if it has none
+	FindByProductID(ctx context.Context, productID int64) (*WarrantyTerms, error)
+
+	// FindByProductIDs retrieves the terms of several products, by product ID
+	FindByProductIDs(ctx context.Context, productIDs []int64) (map[int64]*WarrantyTerms, error)
+
+	// FindAll lists the terms of every product
+	FindAll(ctx context.Context) ([]*WarrantyTerms, error)
+}
+
+// ProductRegistrationRepository defines the interface for product registration persistence
+type ProductRegistrationRepository interface {
+	// Create stores a new registration; a serial number registered for the
+	// same order item already is a conflict
+	Create(ctx context.Context, registration *ProductRegistration) error
+
+	// FindByID retrieves a registration by ID
+	FindByID(ctx context.Context, id int64) (*ProductRegistration, error)
+
+	// FindByCustomerID lists the registrations of a customer, newest first
+	FindByCustomerID(ctx context.Context, customerID int64) ([]*ProductRegistration, error)
+
+	// FindByOrderItemIDs lists the registrations of order items
+	FindByOrderItemIDs(ctx context.Context, orderItemIDs []int64) ([]*ProductRegistration, error)
+
+	// Search finds registrations for the admin
+	Search(ctx context.Context, filter *RegistrationFilter) ([]*ProductRegistration, int64, error)
+}
+
+// RegistrationFilter represents filtering options for product registrations
+type RegistrationFilter struct {
+	Page         int
+	PageSize     int
+	CustomerID   int64
+	OrderID      int64
+	ProductID    int64
+	SerialNumber string
+}
@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/warranty/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresProductRegistrationRepository implements the ProductRegistrationRepository interface
+type PostgresProductRegistrationRepository struct {
+	db *database.DB
+}
+
+// NewPostgresProductRegistrationRepository creates a new PostgresProductRegistrationRepository
+func NewPostgresProductRegistrationRepository(db *database.DB) *PostgresProductRegistrationRepository {
+	return &PostgresProductRegistrationRepository{db: db}
+}
+
+const productRegistrationColumns = `
+	registration_id, customer_id, order_id, order_item_id, product_id, sku_id, product_name,
+	serial_number, purchased_at, warranty_months, warranty_expires_at, registered_at`
+
+// Create stores a new registration; a serial number registered for the same
+// order item already is a conflict.
+func (r *PostgresProductRegistrationRepository) Create(ctx context.Context, reg *domain.ProductRegistration) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO product_registration (
+			customer_id, order_id, order_item_id, product_id, sku_id, product_name,
+			serial_number, purchased_at, warranty_months, warranty_expires_at, registered_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (order_item_id, serial_number) WHERE serial_number <> '' DO NOTHING
+		RETURNING registration_id`,
+		reg.CustomerID, reg.OrderID, reg.OrderItemID, reg.ProductID, reg.SKUID, reg.ProductName,
+		reg.SerialNumber, reg.PurchasedAt, reg.WarrantyMonths, reg.WarrantyExpiresAt, reg.RegisteredAt,
+	).Scan(&reg.ID)
+	if err == pgx.ErrNoRows {
+		return errors.Conflict(fmt.Sprintf("serial number %s is already registered", reg.SerialNumber))
+	}
+	if err != nil {
+		return errors.InternalWrap(err, "failed to create product registration")
+	}
+	return nil
+}
+
+// FindByID retrieves a registration by ID.
+func (r *PostgresProductRegistrationRepository) FindByID(ctx context.Context, id int64) (*domain.ProductRegistration, error) {
+	reg, err := scanProductRegistration(r.db.QueryRow(ctx, `SELECT`+productRegistrationColumns+` FROM product_registration WHERE registration_id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, errors.NotFound("product registration")
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find product registration")
+	}
+	return reg, nil
+}
+
+// FindByCustomerID lists the registrations of a customer, newest first.
+func (r *PostgresProductRegistrationRepository) FindByCustomerID(ctx context.Context, customerID int64) ([]*domain.ProductRegistration, error) {
+	query := `SELECT` + productRegistrationColumns + ` FROM product_registration
+		WHERE customer_id = $1
+		ORDER BY registered_at DESC, registration_id DESC`
+	return r.query(ctx, query, customerID)
+}
+
+// FindByOrderItemIDs lists the registrations of order items.
+func (r *PostgresProductRegistrationRepository) FindByOrderItemIDs(ctx context.Context, orderItemIDs []int64) ([]*domain.ProductRegistration, error) {
+	query := `SELECT` + productRegistrationColumns + ` FROM product_registration
+		WHERE order_item_id = ANY($1)
+		ORDER BY registration_id`
+	return r.query(ctx, query, orderItemIDs)
+}
+
+// Search finds registrations by customer, order, product or serial number.
+func (r *PostgresProductRegistrationRepository) Search(ctx context.Context, filter *domain.RegistrationFilter) ([]*domain.ProductRegistration, int64, error) {
+	where := " WHERE 1=1"
+	args := make([]interface{}, 0)
+	argIndex := 1
+
+	if filter.CustomerID > 0 {
+		where += fmt.Sprintf(" AND customer_id = $%d", argIndex)
+		args = append(args, filter.CustomerID)
+		argIndex++
+	}
+	if filter.OrderID > 0 {
+		where += fmt.Sprintf(" AND order_id = $%d", argIndex)
+		args = append(args, filter.OrderID)
+		argIndex++
+	}
+	if filter.ProductID > 0 {
+		where += fmt.Sprintf(" AND product_id = $%d", argIndex)
+		args = append(args, filter.ProductID)
+		argIndex++
+	}
+	if filter.SerialNumber != "" {
+		where += fmt.Sprintf(" AND serial_number = $%d", argIndex)
+		args = append(args, filter.SerialNumber)
+		argIndex++
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM product_registration"+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.InternalWrap(err, "failed to count product registrations")
+	}
+
+	query := `SELECT` + productRegistrationColumns + ` FROM product_registration` + where +
+		" ORDER BY registered_at DESC, registration_id DESC"
+	if filter.PageSize > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	}
+
+	registrations, err := r.query(ctx, query, args...)
+	return registrations, total, err
+}
+
+func (r *PostgresProductRegistrationRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ProductRegistration, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query product registrations")
+	}
+	defer rows.Close()
+
+	registrations := make([]*domain.ProductRegistration, 0)
+	for rows.Next() {
+		reg, err := scanProductRegistration(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan product registration")
+		}
+		registrations = append(registrations, reg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate product registrations")
+	}
+	return registrations, nil
+}
+
+func scanProductRegistration(row pgx.Row) (*domain.ProductRegistration, error) {
+	reg := &domain.ProductRegistration{}
+	err := row.Scan(
+		&reg.ID, &reg.CustomerID, &reg.OrderID, &reg.OrderItemID, &reg.ProductID, &reg.SKUID, &reg.ProductName,
+		&reg.SerialNumber, &reg.PurchasedAt, &reg.WarrantyMonths, &reg.WarrantyExpiresAt, &reg.RegisteredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
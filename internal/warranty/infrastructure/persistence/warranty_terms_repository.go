@@ -0,0 +1,112 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/warranty/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresWarrantyTermsRepository implements the WarrantyTermsRepository interface
+type PostgresWarrantyTermsRepository struct {
+	db *database.DB
+}
+
+// NewPostgresWarrantyTermsRepository creates a new PostgresWarrantyTermsRepository
+func NewPostgresWarrantyTermsRepository(db *database.DB) *PostgresWarrantyTermsRepository {
+	return &PostgresWarrantyTermsRepository{db: db}
+}
+
+const warrantyTermsColumns = `
+	warranty_terms_id, product_id, duration_months, description, created_at, updated_at`
+
+// Save creates or replaces the terms of a product.
+func (r *PostgresWarrantyTermsRepository) Save(ctx context.Context, t *domain.WarrantyTerms) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO warranty_terms (product_id, duration_months, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (product_id) DO UPDATE
+		SET duration_months = EXCLUDED.duration_months, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+		RETURNING warranty_terms_id, created_at`,
+		t.ProductID, t.DurationMonths, t.Description, t.CreatedAt, t.UpdatedAt,
+	).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to save warranty terms")
+	}
+	return nil
+}
+
+// Delete removes the terms of a product.
+func (r *PostgresWarrantyTermsRepository) Delete(ctx context.Context, productID int64) error {
+	tag, err := r.db.Pool().Exec(ctx, `DELETE FROM warranty_terms WHERE product_id = $1`, productID)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to delete warranty terms")
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NotFound("warranty terms")
+	}
+	return nil
+}
+
+// FindByProductID retrieves the terms of a product, nil if it has none.
+func (r *PostgresWarrantyTermsRepository) FindByProductID(ctx context.Context, productID int64) (*domain.WarrantyTerms, error) {
+	t, err := scanWarrantyTerms(r.db.QueryRow(ctx, `SELECT`+warrantyTermsColumns+` FROM warranty_terms WHERE product_id = $1`, productID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find warranty terms")
+	}
+	return t, nil
+}
+
+// FindByProductIDs retrieves the terms of several products, by product ID.
+func (r *PostgresWarrantyTermsRepository) FindByProductIDs(ctx context.Context, productIDs []int64) (map[int64]*domain.WarrantyTerms, error) {
+	terms, err := r.query(ctx, `SELECT`+warrantyTermsColumns+` FROM warranty_terms WHERE product_id = ANY($1)`, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	byProduct := make(map[int64]*domain.WarrantyTerms, len(terms))
+	for _, t := range terms {
+		byProduct[t.ProductID] = t
+	}
+	return byProduct, nil
+}
+
+// FindAll lists the terms of every product.
+func (r *PostgresWarrantyTermsRepository) FindAll(ctx context.Context) ([]*domain.WarrantyTerms, error) {
+	return r.query(ctx, `SELECT`+warrantyTermsColumns+` FROM warranty_terms ORDER BY product_id`)
+}
+
+func (r *PostgresWarrantyTermsRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.WarrantyTerms, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query warranty terms")
+	}
+	defer rows.Close()
+
+	terms := make([]*domain.WarrantyTerms, 0)
+	for rows.Next() {
+		t, err := scanWarrantyTerms(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan warranty terms")
+		}
+		terms = append(terms, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate warranty terms")
+	}
+	return terms, nil
+}
+
+func scanWarrantyTerms(row pgx.Row) (*domain.WarrantyTerms, error) {
+	t := &domain.WarrantyTerms{}
+	err := row.Scan(&t.ID, &t.ProductID, &t.DurationMonths, &t.Description, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/warranty/application"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminWarrantyHandler handles the warranty terms of products and the lookup
+// of product registrations during support interactions
+type AdminWarrantyHandler struct {
+	warrantyService application.WarrantyService
+	authMiddleware  func(http.Handler) http.Handler
+	logger          *logger.Logger
+}
+
+// NewAdminWarrantyHandler creates a new admin warranty handler
+func NewAdminWarrantyHandler(warrantyService application.WarrantyService, authMiddleware func(http.Handler) http.Handler, logger *logger.Logger) *AdminWarrantyHandler {
+	return &AdminWarrantyHandler{
+		warrantyService: warrantyService,
+		authMiddleware:  authMiddleware,
+		logger:          logger,
+	}
+}
+
+// RegisterRoutes registers warranty routes
+func (h *AdminWarrantyHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Route("/admin/warranty", func(r chi.Router) {
+			r.Get("/terms", h.ListTerms)
+			r.Get("/terms/{productId}", h.GetTerms)
+			r.Put("/terms/{productId}", h.SaveTerms)
+			r.Delete("/terms/{productId}", h.DeleteTerms)
+			r.Get("/registrations", h.SearchRegistrations)
+			r.Get("/registrations/{id}", h.GetRegistration)
+		})
+	})
+}
+
+// ListTerms lists the warranty terms of every product
+func (h *AdminWarrantyHandler) ListTerms(w http.ResponseWriter, r *http.Request) {
+	terms, err := h.warrantyService.ListTerms(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("failed to list warranty terms")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, terms)
+}
+
+// GetTerms retrieves the warranty terms of a product
+func (h *AdminWarrantyHandler) GetTerms(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "productId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, httpPkg.NewValidationError("invalid product ID"))
+		return
+	}
+
+	terms, err := h.warrantyService.GetTerms(r.Context(), productID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, terms)
+}
+
+// SaveTerms sets the warranty terms of a product
+func (h *AdminWarrantyHandler) SaveTerms(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "productId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, httpPkg.NewValidationError("invalid product ID"))
+		return
+	}
+
+	var req application.SaveWarrantyTermsRequest
+	if err := httpPkg.DecodeJSON(r, &req); err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	terms, err := h.warrantyService.SaveTerms(r.Context(), productID, &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("product_id", productID).Error("failed to save warranty terms")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, terms)
+}
+
+// DeleteTerms removes the warranty terms of a product
+func (h *AdminWarrantyHandler) DeleteTerms(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "productId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, httpPkg.NewValidationError("invalid product ID"))
+		return
+	}
+
+	if err := h.warrantyService.DeleteTerms(r.Context(), productID); err != nil {
+		h.logger.WithError(err).WithField("product_id", productID).Error("failed to delete warranty terms")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SearchRegistrations looks up product registrations by customer_id,
+// order_id, product_id or serial
+func (h *AdminWarrantyHandler) SearchRegistrations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+	customerID, _ := strconv.ParseInt(query.Get("customer_id"), 10, 64)
+	orderID, _ := strconv.ParseInt(query.Get("order_id"), 10, 64)
+	productID, _ := strconv.ParseInt(query.Get("product_id"), 10, 64)
+
+	result, err := h.warrantyService.SearchRegistrations(r.Context(), &application.RegistrationSearchQuery{
+		Page:         page,
+		PageSize:     pageSize,
+		CustomerID:   customerID,
+		OrderID:      orderID,
+		ProductID:    productID,
+		SerialNumber: query.Get("serial"),
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("failed to search product registrations")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, result)
+}
+
+// GetRegistration retrieves a product registration
+func (h *AdminWarrantyHandler) GetRegistration(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, httpPkg.NewValidationError("invalid registration ID"))
+		return
+	}
+
+	registration, err := h.warrantyService.GetRegistration(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, registration)
+}
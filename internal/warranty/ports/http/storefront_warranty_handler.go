@@ -0,0 +1,146 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/warranty/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/requestctx"
+)
+
+// StorefrontWarrantyHandler handles the warranty terms of products and the
+// signed-in customer's product registrations
+type StorefrontWarrantyHandler struct {
+	warrantyService application.WarrantyService
+	log             *logger.Logger
+}
+
+// NewStorefrontWarrantyHandler creates a new StorefrontWarrantyHandler
+func NewStorefrontWarrantyHandler(warrantyService application.WarrantyService, log *logger.Logger) *StorefrontWarrantyHandler {
+	return &StorefrontWarrantyHandler{
+		warrantyService: warrantyService,
+		log:             log,
+	}
+}
+
+// RegisterRoutes registers warranty routes
+func (h *StorefrontWarrantyHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/warranty/products/{productId}", h.GetTerms)
+	r.Route("/account/registrations", func(r chi.Router) {
+		r.Get("/", h.ListRegistrations)
+		r.Post("/", h.RegisterProduct)
+		r.Get("/eligible", h.ListRegistrableItems)
+		r.Get("/{id}", h.GetRegistration)
+	})
+}
+
+// GetTerms retrieves the warranty terms of a product
+func (h *StorefrontWarrantyHandler) GetTerms(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "productId"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid product ID").WithInternal(err))
+		return
+	}
+
+	terms, err := h.warrantyService.GetTerms(r.Context(), productID)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, terms)
+}
+
+// ListRegistrations lists the signed-in customer's registered products
+func (h *StorefrontWarrantyHandler) ListRegistrations(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := signedInCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	registrations, err := h.warrantyService.ListRegistrations(r.Context(), customerID)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to list product registrations")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, registrations)
+}
+
+// ListRegistrableItems lists the items of the signed-in customer's orders that can be registered
+func (h *StorefrontWarrantyHandler) ListRegistrableItems(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := signedInCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.warrantyService.RegistrableItems(r.Context(), customerID)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to list registrable items")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, items)
+}
+
+// RegisterProduct registers a unit of an item the signed-in customer bought
+func (h *StorefrontWarrantyHandler) RegisterProduct(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := signedInCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.RegisterProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid request body").WithInternal(err))
+		return
+	}
+
+	registration, err := h.warrantyService.RegisterProduct(r.Context(), customerID, &req)
+	if err != nil {
+		h.log.WithError(err).WithField("customer_id", customerID).Error("failed to register product")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusCreated, registration)
+}
+
+// GetRegistration retrieves a registration of the signed-in customer
+func (h *StorefrontWarrantyHandler) GetRegistration(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := signedInCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid registration ID").WithInternal(err))
+		return
+	}
+
+	registration, err := h.warrantyService.GetCustomerRegistration(r.Context(), customerID, id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, registration)
+}
+
+// signedInCustomer returns the signed-in customer, answering 401 for anonymous shoppers
+func signedInCustomer(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	customerID := requestctx.CustomerID(r.Context())
+	if customerID == 0 {
+		httpPkg.RespondError(w, errors.Unauthorized("sign in to register products"))
+		return 0, false
+	}
+	return customerID, true
+}
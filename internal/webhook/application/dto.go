@@ -0,0 +1,57 @@
+package application
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/webhook/domain"
+)
+
+// ReceiveWebhookResultDTO acknowledges a webhook request
+type ReceiveWebhookResultDTO struct {
+	ID         int64  `json:"id,omitempty"`
+	DeliveryID string `json:"delivery_id"`
+	Duplicate  bool   `json:"duplicate"` // The delivery was already received and is not processed again
+}
+
+// InboundWebhookDTO represents a received webhook
+type InboundWebhookDTO struct {
+	ID            int64           `json:"id"`
+	Provider      string          `json:"provider"`
+	DeliveryID    string          `json:"delivery_id"`
+	EventType     string          `json:"event_type"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     *string         `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	ReceivedAt    time.Time       `json:"received_at"`
+	ProcessedAt   *time.Time      `json:"processed_at,omitempty"`
+	Payload       json.RawMessage `json:"payload,omitempty"` // Non-JSON payloads are sent as a string
+}
+
+// ToInboundWebhookDTO converts a webhook to InboundWebhookDTO, with its payload if withPayload
+func ToInboundWebhookDTO(w *domain.InboundWebhook, withPayload bool) *InboundWebhookDTO {
+	dto := &InboundWebhookDTO{
+		ID:          w.ID,
+		Provider:    w.Provider,
+		DeliveryID:  w.DeliveryID,
+		EventType:   w.EventType,
+		Status:      string(w.Status),
+		Attempts:    w.Attempts,
+		LastError:   w.LastError,
+		ReceivedAt:  w.ReceivedAt,
+		ProcessedAt: w.ProcessedAt,
+	}
+	if w.Status == domain.InboundWebhookStatusPending {
+		next := w.NextAttemptAt
+		dto.NextAttemptAt = &next
+	}
+	if withPayload {
+		if json.Valid(w.Payload) {
+			dto.Payload = json.RawMessage(w.Payload)
+		} else {
+			dto.Payload, _ = json.Marshal(string(w.Payload))
+		}
+	}
+	return dto
+}
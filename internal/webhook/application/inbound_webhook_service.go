@@ -0,0 +1,289 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/internal/webhook/domain"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/webhook"
+)
+
+const (
+	defaultInboundWebhooksLimit = 50
+	maxInboundWebhooksLimit     = 500
+)
+
+// AnyEvent registers a handler for every event of a provider without a handler of its own
+const AnyEvent = "*"
+
+// Handler processes a received webhook. Returning an error retries it later.
+type Handler func(ctx context.Context, webhook *domain.InboundWebhook) error
+
+// InboundWebhookService receives the webhooks of payment, carrier and other
+// providers. Each request is verified with its provider's signature scheme,
+// rejected when it replays a delivery already received, and stored before it is
+// acknowledged; stored webhooks are processed in the background by the handlers
+// registered for their provider and event, with retries and a dead letter.
+type InboundWebhookService interface {
+	// RegisterHandler sets the handler of a provider's event, or of all its
+	// events without a handler of their own with AnyEvent.
+	RegisterHandler(provider, eventType string, handler Handler)
+
+	// Receive verifies and stores a webhook request of a provider.
+	Receive(ctx context.Context, provider string, header http.Header, body []byte) (*ReceiveWebhookResultDTO, error)
+
+	// ProcessDue processes the webhooks whose attempt is due and returns how many succeeded.
+	ProcessDue(ctx context.Context) (int, error)
+
+	// StartWorker processes due webhooks periodically until ctx is cancelled.
+	StartWorker(ctx context.Context, interval time.Duration)
+
+	// ListWebhooks lists received webhooks, latest first.
+	ListWebhooks(ctx context.Context, provider, status string, limit, offset int) ([]*InboundWebhookDTO, error)
+
+	// GetWebhook retrieves a received webhook with its payload.
+	GetWebhook(ctx context.Context, id int64) (*InboundWebhookDTO, error)
+
+	// Reprocess requeues a processed, ignored or dead-lettered webhook and processes it immediately.
+	Reprocess(ctx context.Context, id int64) (*InboundWebhookDTO, error)
+}
+
+// InboundWebhookConfig holds the retry policy of received webhooks
+type InboundWebhookConfig struct {
+	MaxAttempts int           // Attempts before a webhook is dead-lettered
+	RetryDelay  time.Duration // Delay before the first retry; doubles with each attempt
+	Lease       time.Duration // How long a claimed webhook is hidden from other workers
+	BatchSize   int           // Webhooks processed per run
+}
+
+type inboundWebhookService struct {
+	repo      domain.InboundWebhookRepository
+	verifiers map[string]webhook.Verifier // Provider -> signature verifier
+	cfg       InboundWebhookConfig
+	log       *logger.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]Handler // provider/event -> handler
+}
+
+// NewInboundWebhookService creates a new instance of InboundWebhookService.
+// Webhooks are only accepted from the providers with a verifier.
+func NewInboundWebhookService(
+	repo domain.InboundWebhookRepository,
+	verifiers map[string]webhook.Verifier,
+	cfg InboundWebhookConfig,
+	log *logger.Logger,
+) InboundWebhookService {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = 30 * time.Second
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 2 * time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	providers := make(map[string]webhook.Verifier, len(verifiers))
+	for provider, verifier := range verifiers {
+		providers[strings.ToLower(provider)] = verifier
+	}
+	return &inboundWebhookService{
+		repo:      repo,
+		verifiers: providers,
+		cfg:       cfg,
+		log:       log,
+		handlers:  make(map[string]Handler),
+	}
+}
+
+func (s *inboundWebhookService) RegisterHandler(provider, eventType string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[handlerKey(provider, eventType)] = handler
+}
+
+func (s *inboundWebhookService) Receive(ctx context.Context, provider string, header http.Header, body []byte) (*ReceiveWebhookResultDTO, error) {
+	provider = strings.ToLower(provider)
+	verifier, ok := s.verifiers[provider]
+	if !ok {
+		return nil, errors.NotFound("webhook provider")
+	}
+	delivery, err := verifier.Verify(header, body, time.Now())
+	if err != nil {
+		s.log.WithError(err).WithField("provider", provider).Warn("Rejected inbound webhook")
+		return nil, err
+	}
+
+	w, err := domain.NewInboundWebhook(provider, delivery.ID, delivery.Event, body)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	created, err := s.repo.Create(ctx, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store %s webhook %s: %w", provider, delivery.ID, err)
+	}
+	if !created {
+		// Replays are acknowledged so the provider stops retrying, but not processed again
+		s.log.WithFields(logger.Fields{"provider": provider, "delivery_id": delivery.ID}).Info("Inbound webhook replayed")
+		return &ReceiveWebhookResultDTO{DeliveryID: delivery.ID, Duplicate: true}, nil
+	}
+	return &ReceiveWebhookResultDTO{ID: w.ID, DeliveryID: delivery.ID}, nil
+}
+
+func (s *inboundWebhookService) ProcessDue(ctx context.Context) (int, error) {
+	webhooks, err := s.repo.ClaimDue(ctx, time.Now(), s.cfg.Lease, s.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due inbound webhooks: %w", err)
+	}
+
+	processed := 0
+	for _, w := range webhooks {
+		if ctx.Err() != nil {
+			break
+		}
+		if s.process(ctx, w) {
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+func (s *inboundWebhookService) StartWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.ProcessDue(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled inbound webhook processing failed")
+				}
+			}
+		}
+	}()
+}
+
+func (s *inboundWebhookService) ListWebhooks(ctx context.Context, provider, status string, limit, offset int) ([]*InboundWebhookDTO, error) {
+	if limit <= 0 || limit > maxInboundWebhooksLimit {
+		limit = defaultInboundWebhooksLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	webhooks, err := s.repo.FindAll(ctx, &domain.InboundWebhookFilter{
+		Provider: strings.ToLower(provider),
+		Status:   domain.InboundWebhookStatus(strings.ToUpper(status)),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inbound webhooks: %w", err)
+	}
+	dtos := make([]*InboundWebhookDTO, len(webhooks))
+	for i, w := range webhooks {
+		dtos[i] = ToInboundWebhookDTO(w, false)
+	}
+	return dtos, nil
+}
+
+func (s *inboundWebhookService) GetWebhook(ctx context.Context, id int64) (*InboundWebhookDTO, error) {
+	w, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToInboundWebhookDTO(w, true), nil
+}
+
+func (s *inboundWebhookService) Reprocess(ctx context.Context, id int64) (*InboundWebhookDTO, error) {
+	w, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Requeue(); err != nil {
+		return nil, errors.Conflict(err.Error())
+	}
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, fmt.Errorf("failed to requeue inbound webhook: %w", err)
+	}
+
+	claimed, err := s.repo.ClaimByID(ctx, id, s.cfg.Lease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim inbound webhook: %w", err)
+	}
+	// A worker that claimed it first processes it instead
+	if claimed != nil {
+		s.process(ctx, claimed)
+		w = claimed
+	}
+	s.log.WithFields(logger.Fields{"inbound_webhook_id": id, "status": w.Status}).Info("Inbound webhook reprocessed")
+	return ToInboundWebhookDTO(w, true), nil
+}
+
+// process runs the handler of a claimed webhook and records the outcome; it
+// reports whether the webhook was handled
+func (s *inboundWebhookService) process(ctx context.Context, w *domain.InboundWebhook) bool {
+	handler := s.handler(w.Provider, w.EventType)
+	if handler == nil {
+		w.Ignore()
+	} else if err := handler(ctx, w); err != nil {
+		w.Fail(err, s.cfg.MaxAttempts, s.cfg.RetryDelay)
+		fields := logger.Fields{
+			"inbound_webhook_id": w.ID,
+			"provider":           w.Provider,
+			"event_type":         w.EventType,
+			"attempts":           w.Attempts,
+		}
+		if w.Status == domain.InboundWebhookStatusDeadLetter {
+			s.log.WithError(err).WithFields(fields).Error("Inbound webhook dead-lettered")
+		} else {
+			s.log.WithError(err).WithFields(fields).Warn("Inbound webhook failed, will retry")
+		}
+	} else {
+		w.Complete()
+	}
+
+	if err := s.repo.Update(ctx, w); err != nil {
+		s.log.WithError(err).WithField("inbound_webhook_id", w.ID).Error("Failed to record inbound webhook outcome")
+		return false
+	}
+	return w.Status == domain.InboundWebhookStatusProcessed
+}
+
+// handler returns the handler of a provider's event, falling back to that of all its events
+func (s *inboundWebhookService) handler(provider, eventType string) Handler {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if handler, ok := s.handlers[handlerKey(provider, eventType)]; ok {
+		return handler
+	}
+	return s.handlers[handlerKey(provider, AnyEvent)]
+}
+
+func (s *inboundWebhookService) find(ctx context.Context, id int64) (*domain.InboundWebhook, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find inbound webhook %d: %w", id, err)
+	}
+	if w == nil {
+		return nil, errors.NotFound("inbound webhook")
+	}
+	return w, nil
+}
+
+func handlerKey(provider, eventType string) string {
+	return strings.ToLower(provider) + "/" + eventType
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// InboundWebhookStatus represents the processing state of a received webhook
+type InboundWebhookStatus string
+
+const (
+	InboundWebhookStatusPending    InboundWebhookStatus = "PENDING"
+	InboundWebhookStatusProcessed  InboundWebhookStatus = "PROCESSED"
+	InboundWebhookStatusIgnored    InboundWebhookStatus = "IGNORED"     // No handler for its provider and event
+	InboundWebhookStatusDeadLetter InboundWebhookStatus = "DEAD_LETTER" // Retries exhausted; needs reprocessing
+)
+
+// maxInboundWebhookBackoff caps the delay between processing retries
+const maxInboundWebhookBackoff = time.Hour
+
+// InboundWebhook is a webhook received from a payment, carrier or other provider.
+// Its payload is stored once its signature is verified and before it is processed,
+// so webhooks that fail are retried and can be reprocessed.
+type InboundWebhook struct {
+	ID            int64
+	Provider      string
+	DeliveryID    string // Unique per provider; a delivery received again is a replay
+	EventType     string
+	Payload       []byte
+	Status        InboundWebhookStatus
+	Attempts      int
+	LastError     *string
+	NextAttemptAt time.Time
+	ReceivedAt    time.Time
+	UpdatedAt     time.Time
+	ProcessedAt   *time.Time
+}
+
+// NewInboundWebhook creates a pending webhook due for processing now
+func NewInboundWebhook(provider, deliveryID, eventType string, payload []byte) (*InboundWebhook, error) {
+	if provider == "" {
+		return nil, NewDomainError("Webhook provider is required")
+	}
+	if deliveryID == "" {
+		return nil, NewDomainError("Webhook delivery ID is required")
+	}
+	now := time.Now()
+	return &InboundWebhook{
+		Provider:      provider,
+		DeliveryID:    deliveryID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        InboundWebhookStatusPending,
+		NextAttemptAt: now,
+		ReceivedAt:    now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Complete marks the webhook as processed
+func (w *InboundWebhook) Complete() {
+	now := time.Now()
+	w.Status = InboundWebhookStatusProcessed
+	w.Attempts++
+	w.LastError = nil
+	w.ProcessedAt = &now
+	w.UpdatedAt = now
+}
+
+// Ignore marks a webhook nothing handles as settled
+func (w *InboundWebhook) Ignore() {
+	now := time.Now()
+	w.Status = InboundWebhookStatusIgnored
+	w.ProcessedAt = &now
+	w.UpdatedAt = now
+}
+
+// Fail records a failed attempt and schedules the next one with exponential backoff.
+// Once maxAttempts is reached the webhook is dead-lettered.
+func (w *InboundWebhook) Fail(err error, maxAttempts int, baseDelay time.Duration) {
+	now := time.Now()
+	message := err.Error()
+	w.Attempts++
+	w.LastError = &message
+	w.UpdatedAt = now
+
+	if w.Attempts >= maxAttempts {
+		w.Status = InboundWebhookStatusDeadLetter
+		return
+	}
+	backoff := baseDelay << (w.Attempts - 1)
+	if backoff <= 0 || backoff > maxInboundWebhookBackoff {
+		backoff = maxInboundWebhookBackoff
+	}
+	w.NextAttemptAt = now.Add(backoff)
+}
+
+// Requeue puts a webhook back in the queue for immediate processing, e.g. after
+// the handler that failed it was fixed or one was added for its event
+func (w *InboundWebhook) Requeue() error {
+	if w.Status == InboundWebhookStatusPending && w.Attempts == 0 {
+		return NewDomainError("Webhook has not been processed yet")
+	}
+	now := time.Now()
+	w.Status = InboundWebhookStatusPending
+	w.Attempts = 0
+	w.NextAttemptAt = now
+	w.ProcessedAt = nil
+	w.UpdatedAt = now
+	return nil
+}
+
+// InboundWebhookFilter restricts the listing of received webhooks
+type InboundWebhookFilter struct {
+	Provider string               // Empty for all providers
+	Status   InboundWebhookStatus // Empty for all statuses
+	Limit    int
+	Offset   int
+}
+
+// InboundWebhookRepository defines the interface for received webhook persistence
+type InboundWebhookRepository interface {
+	// Create stores a received webhook and reports false, leaving it unsaved, when
+	// its provider already sent the delivery.
+	Create(ctx context.Context, webhook *InboundWebhook) (bool, error)
+
+	// ClaimDue leases up to limit pending webhooks due by now, so no other worker
+	// picks them up until the lease expires.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*InboundWebhook, error)
+
+	// ClaimByID leases a pending webhook regardless of its schedule; nil when it is not pending or already leased.
+	ClaimByID(ctx context.Context, id int64, lease time.Duration) (*InboundWebhook, error)
+
+	// FindByID retrieves a webhook by its ID.
+	FindByID(ctx context.Context, id int64) (*InboundWebhook, error)
+
+	// FindAll retrieves the webhooks matching a filter, latest first.
+	FindAll(ctx context.Context, filter *InboundWebhookFilter) ([]*InboundWebhook, error)
+
+	// Update saves the state of a webhook.
+	Update(ctx context.Context, webhook *InboundWebhook) error
+}
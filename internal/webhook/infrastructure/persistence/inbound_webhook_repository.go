@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qhato/ecommerce/internal/webhook/domain"
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// PostgresInboundWebhookRepository implements the InboundWebhookRepository interface
+type PostgresInboundWebhookRepository struct {
+	db *database.DB
+}
+
+// NewPostgresInboundWebhookRepository creates a new PostgresInboundWebhookRepository
+func NewPostgresInboundWebhookRepository(db *database.DB) *PostgresInboundWebhookRepository {
+	return &PostgresInboundWebhookRepository{db: db}
+}
+
+const inboundWebhookColumns = `
+	inbound_webhook_id, provider, delivery_id, event_type, payload, status, attempts, last_error,
+	next_attempt_at, received_at, updated_at, processed_at`
+
+// Create stores a received webhook, reporting false when its provider already sent the delivery.
+func (r *PostgresInboundWebhookRepository) Create(ctx context.Context, w *domain.InboundWebhook) (bool, error) {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO inbound_webhook (
+			provider, delivery_id, event_type, payload, status, attempts, next_attempt_at, received_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (provider, delivery_id) DO NOTHING
+		RETURNING inbound_webhook_id`,
+		w.Provider, w.DeliveryID, w.EventType, w.Payload, string(w.Status), w.Attempts,
+		w.NextAttemptAt, w.ReceivedAt, w.UpdatedAt,
+	).Scan(&w.ID)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.InternalWrap(err, "failed to store inbound webhook")
+	}
+	return true, nil
+}
+
+// ClaimDue leases up to limit pending webhooks due by now, oldest first.
+// Rows locked by another worker are skipped.
+func (r *PostgresInboundWebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.InboundWebhook, error) {
+	return r.query(ctx, `
+		UPDATE inbound_webhook
+		SET next_attempt_at = $1
+		WHERE inbound_webhook_id IN (
+			SELECT inbound_webhook_id FROM inbound_webhook
+			WHERE status = 'PENDING' AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING`+inboundWebhookColumns, now.Add(lease), now, limit)
+}
+
+// ClaimByID leases a pending webhook regardless of its schedule.
+func (r *PostgresInboundWebhookRepository) ClaimByID(ctx context.Context, id int64, lease time.Duration) (*domain.InboundWebhook, error) {
+	webhooks, err := r.query(ctx, `
+		UPDATE inbound_webhook
+		SET next_attempt_at = $1
+		WHERE inbound_webhook_id IN (
+			SELECT inbound_webhook_id FROM inbound_webhook
+			WHERE status = 'PENDING' AND inbound_webhook_id = $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING`+inboundWebhookColumns, time.Now().Add(lease), id)
+	if err != nil || len(webhooks) == 0 {
+		return nil, err
+	}
+	return webhooks[0], nil
+}
+
+// FindByID retrieves a webhook by its ID.
+func (r *PostgresInboundWebhookRepository) FindByID(ctx context.Context, id int64) (*domain.InboundWebhook, error) {
+	w, err := scanInboundWebhook(r.db.QueryRow(ctx, `SELECT`+inboundWebhookColumns+`
+		FROM inbound_webhook WHERE inbound_webhook_id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to find inbound webhook")
+	}
+	return w, nil
+}
+
+// FindAll retrieves the webhooks matching a filter, latest first.
+func (r *PostgresInboundWebhookRepository) FindAll(ctx context.Context, filter *domain.InboundWebhookFilter) ([]*domain.InboundWebhook, error) {
+	return r.query(ctx, `SELECT`+inboundWebhookColumns+`
+		FROM inbound_webhook
+		WHERE ($1 = '' OR provider = $1) AND ($2 = '' OR status = $2)
+		ORDER BY received_at DESC, inbound_webhook_id DESC
+		LIMIT $3 OFFSET $4`,
+		filter.Provider, string(filter.Status), filter.Limit, filter.Offset)
+}
+
+// Update saves the state of a webhook.
+func (r *PostgresInboundWebhookRepository) Update(ctx context.Context, w *domain.InboundWebhook) error {
+	err := r.db.Exec(ctx, `
+		UPDATE inbound_webhook
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, updated_at = $6, processed_at = $7
+		WHERE inbound_webhook_id = $1`,
+		w.ID, string(w.Status), w.Attempts, w.LastError, w.NextAttemptAt, w.UpdatedAt, w.ProcessedAt,
+	)
+	if err != nil {
+		return errors.InternalWrap(err, "failed to update inbound webhook")
+	}
+	return nil
+}
+
+func (r *PostgresInboundWebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.InboundWebhook, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.InternalWrap(err, "failed to query inbound webhooks")
+	}
+	defer rows.Close()
+
+	webhooks := make([]*domain.InboundWebhook, 0)
+	for rows.Next() {
+		w, err := scanInboundWebhook(rows)
+		if err != nil {
+			return nil, errors.InternalWrap(err, "failed to scan inbound webhook")
+		}
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.InternalWrap(err, "failed to iterate inbound webhooks")
+	}
+	return webhooks, nil
+}
+
+func scanInboundWebhook(row pgx.Row) (*domain.InboundWebhook, error) {
+	w := &domain.InboundWebhook{}
+	var status string
+	err := row.Scan(
+		&w.ID, &w.Provider, &w.DeliveryID, &w.EventType, &w.Payload, &status, &w.Attempts, &w.LastError,
+		&w.NextAttemptAt, &w.ReceivedAt, &w.UpdatedAt, &w.ProcessedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	w.Status = domain.InboundWebhookStatus(status)
+	return w, nil
+}
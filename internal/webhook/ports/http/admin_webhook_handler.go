@@ -0,0 +1,101 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/webhook/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// AdminWebhookHandler handles the inspection and reprocessing of received webhooks
+type AdminWebhookHandler struct {
+	webhookService application.InboundWebhookService
+	authMiddleware func(http.Handler) http.Handler
+	log            *logger.Logger
+}
+
+// NewAdminWebhookHandler creates a new AdminWebhookHandler
+func NewAdminWebhookHandler(
+	webhookService application.InboundWebhookService,
+	authMiddleware func(http.Handler) http.Handler,
+	log *logger.Logger,
+) *AdminWebhookHandler {
+	return &AdminWebhookHandler{
+		webhookService: webhookService,
+		authMiddleware: authMiddleware,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers received webhook routes
+func (h *AdminWebhookHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(h.authMiddleware)
+		r.Get("/admin/webhooks", h.ListWebhooks)
+		r.Get("/admin/webhooks/{id}", h.GetWebhook)
+		r.Post("/admin/webhooks/{id}/reprocess", h.ReprocessWebhook)
+	})
+}
+
+// ListWebhooks lists received webhooks, optionally of one provider and status
+func (h *AdminWebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	webhooks, err := h.webhookService.ListWebhooks(r.Context(), query.Get("provider"), query.Get("status"), limit, offset)
+	if err != nil {
+		h.log.WithError(err).Error("failed to list inbound webhooks")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, webhooks)
+}
+
+// GetWebhook retrieves a received webhook with its payload
+func (h *AdminWebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(r.Context(), id)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, webhook)
+}
+
+// ReprocessWebhook requeues a received webhook and processes it immediately
+func (h *AdminWebhookHandler) ReprocessWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.Reprocess(r.Context(), id)
+	if err != nil {
+		h.log.WithError(err).WithField("inbound_webhook_id", id).Error("failed to reprocess inbound webhook")
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	httpPkg.RespondJSON(w, http.StatusOK, webhook)
+}
+
+// parseWebhookID reads the webhook ID of the path, responding with an error when it is invalid
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("invalid webhook ID").WithInternal(err))
+		return 0, false
+	}
+	return id, true
+}
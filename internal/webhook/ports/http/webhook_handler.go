@@ -0,0 +1,57 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/internal/webhook/application"
+	"github.com/qhato/ecommerce/pkg/errors"
+	httpPkg "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// maxWebhookBytes bounds the size of a webhook payload
+const maxWebhookBytes = 1 << 20
+
+// WebhookHandler accepts the webhooks of payment, carrier and other providers.
+// Requests are authenticated by their provider's signature rather than a session.
+type WebhookHandler struct {
+	webhookService application.InboundWebhookService
+	log            *logger.Logger
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(webhookService application.InboundWebhookService, log *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		log:            log,
+	}
+}
+
+// RegisterRoutes registers webhook routes
+func (h *WebhookHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/webhooks/{provider}", h.ReceiveWebhook)
+}
+
+// ReceiveWebhook stores a verified webhook for processing and acknowledges it;
+// deliveries received again are acknowledged without being stored twice
+func (h *WebhookHandler) ReceiveWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		httpPkg.RespondError(w, errors.BadRequest("webhook body could not be read").WithInternal(err))
+		return
+	}
+
+	result, err := h.webhookService.Receive(r.Context(), chi.URLParam(r, "provider"), r.Header, body)
+	if err != nil {
+		httpPkg.RespondError(w, err)
+		return
+	}
+
+	if result.Duplicate {
+		httpPkg.RespondJSON(w, http.StatusOK, result)
+		return
+	}
+	httpPkg.RespondJSON(w, http.StatusAccepted, result)
+}
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS legacy_import_run (
+    import_run_id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    batch_size INT NOT NULL,
+    last_legacy_id BIGINT NOT NULL DEFAULT 0,
+    processed BIGINT NOT NULL DEFAULT 0,
+    skipped BIGINT NOT NULL DEFAULT 0,
+    failed BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_legacy_import_run_entity_type ON legacy_import_run (entity_type, import_run_id DESC);
+
+CREATE TABLE IF NOT EXISTS legacy_id_map (
+    entity_type VARCHAR(50) NOT NULL,
+    legacy_id BIGINT NOT NULL,
+    new_id BIGINT NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    import_run_id BIGINT NOT NULL,
+    imported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entity_type, legacy_id),
+    CONSTRAINT fk_legacy_id_map_import_run_id FOREIGN KEY (import_run_id) REFERENCES legacy_import_run(import_run_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_legacy_id_map_new_id ON legacy_id_map (entity_type, new_id);
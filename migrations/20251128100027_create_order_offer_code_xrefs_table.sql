@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS blc_order_offer_code_xref (
+    order_id BIGINT NOT NULL,
+    offer_code_id BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, offer_code_id),
+    CONSTRAINT fk_blc_order_offer_code_xref_order_id FOREIGN KEY (order_id) REFERENCES blc_order(order_id),
+    CONSTRAINT fk_blc_order_offer_code_xref_offer_code_id FOREIGN KEY (offer_code_id) REFERENCES blc_offer_code(offer_code_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blc_order_offer_code_xref_offer_code_id ON blc_order_offer_code_xref (offer_code_id);
+CREATE INDEX IF NOT EXISTS idx_blc_order_submit_date ON blc_order (submit_date);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS analytics_customer_ltv (
+    customer_id BIGINT PRIMARY KEY,
+    cohort_month TIMESTAMP NOT NULL,
+    first_order_date TIMESTAMP NOT NULL,
+    last_order_date TIMESTAMP NOT NULL,
+    order_count BIGINT NOT NULL,
+    total_revenue NUMERIC(19, 5) NOT NULL,
+    average_order_value NUMERIC(19, 5) NOT NULL,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_customer_ltv_cohort_month ON analytics_customer_ltv (cohort_month);
+CREATE INDEX IF NOT EXISTS idx_analytics_customer_ltv_total_revenue ON analytics_customer_ltv (total_revenue DESC);
+
+CREATE TABLE IF NOT EXISTS analytics_cohort_period (
+    cohort_month TIMESTAMP NOT NULL,
+    months_since_first INT NOT NULL,
+    cohort_size BIGINT NOT NULL,
+    active_customers BIGINT NOT NULL,
+    revenue NUMERIC(19, 5) NOT NULL,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (cohort_month, months_since_first)
+);
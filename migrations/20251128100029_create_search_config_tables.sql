@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS search_synonym_group (
+    synonym_group_id BIGSERIAL PRIMARY KEY,
+    terms TEXT[] NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS search_stopword (
+    word VARCHAR(100) PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS search_field_boost (
+    field_name VARCHAR(50) PRIMARY KEY,
+    boost NUMERIC(6, 2) NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS search_pinned_query (
+    pinned_query_id BIGSERIAL PRIMARY KEY,
+    query VARCHAR(255) NOT NULL UNIQUE,
+    product_ids BIGINT[] NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
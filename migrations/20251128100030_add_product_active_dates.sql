@@ -0,0 +1,5 @@
+ALTER TABLE blc_product ADD COLUMN IF NOT EXISTS active_start_date TIMESTAMP NULL;
+ALTER TABLE blc_product ADD COLUMN IF NOT EXISTS active_end_date TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_blc_product_active_start_date ON blc_product (active_start_date);
+CREATE INDEX IF NOT EXISTS idx_blc_product_active_end_date ON blc_product (active_end_date);
@@ -0,0 +1,10 @@
+-- Read model joined by catalog listings to apply the out-of-stock policy without per-item lookups
+CREATE TABLE IF NOT EXISTS catalog_product_availability (
+    product_id BIGINT PRIMARY KEY,
+    in_stock BOOLEAN NOT NULL DEFAULT TRUE,
+    qty_available INT NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_catalog_product_availability_product_id FOREIGN KEY (product_id) REFERENCES blc_product(product_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_product_availability_in_stock ON catalog_product_availability (in_stock);
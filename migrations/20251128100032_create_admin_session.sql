@@ -0,0 +1,14 @@
+-- Server-side admin login sessions; session_id is the ID of the issued JWT so it can be revoked
+CREATE TABLE IF NOT EXISTS admin_session (
+    session_id VARCHAR(64) PRIMARY KEY,
+    admin_user_id BIGINT NOT NULL,
+    device VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_activity_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_admin_session_admin_user_id FOREIGN KEY (admin_user_id) REFERENCES blc_admin_user(admin_user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_session_admin_user_id ON admin_session (admin_user_id, created_at DESC);
@@ -0,0 +1,24 @@
+-- Staff notes on orders and customers; customer_visible notes are shown on the storefront
+CREATE TABLE IF NOT EXISTS order_note (
+    order_note_id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    customer_visible BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_order_note_order_id FOREIGN KEY (order_id) REFERENCES blc_order(order_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_note_order_id ON order_note (order_id, created_at);
+
+CREATE TABLE IF NOT EXISTS customer_note (
+    customer_note_id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    customer_visible BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_customer_note_customer_id FOREIGN KEY (customer_id) REFERENCES blc_customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_note_customer_id ON customer_note (customer_id, created_at DESC);
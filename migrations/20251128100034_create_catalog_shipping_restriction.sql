@@ -0,0 +1,19 @@
+-- Shipping restrictions by destination and age, attached to a product or a single SKU
+CREATE TABLE IF NOT EXISTS catalog_shipping_restriction (
+    restriction_id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NULL,
+    sku_id BIGINT NULL,
+    blocked_countries TEXT[] NOT NULL DEFAULT '{}',
+    blocked_regions TEXT[] NOT NULL DEFAULT '{}',
+    age_verification_required BOOLEAN NOT NULL DEFAULT FALSE,
+    minimum_age INTEGER NOT NULL DEFAULT 0,
+    reason VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_catalog_shipping_restriction_target CHECK ((product_id IS NULL) <> (sku_id IS NULL)),
+    CONSTRAINT fk_catalog_shipping_restriction_product_id FOREIGN KEY (product_id) REFERENCES blc_product(product_id) ON DELETE CASCADE,
+    CONSTRAINT fk_catalog_shipping_restriction_sku_id FOREIGN KEY (sku_id) REFERENCES blc_sku(sku_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_shipping_restriction_product_id ON catalog_shipping_restriction (product_id);
+CREATE INDEX IF NOT EXISTS idx_catalog_shipping_restriction_sku_id ON catalog_shipping_restriction (sku_id);
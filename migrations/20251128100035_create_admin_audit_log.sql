@@ -0,0 +1,37 @@
+-- Admin audit trail and security events (logins, failed logins, session revocations)
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    audit_id VARCHAR(36) PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    actor_id VARCHAR(64),
+    actor_name VARCHAR(255),
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL DEFAULT '',
+    action VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(64),
+    user_agent VARCHAR(512),
+    changes JSONB,
+    metadata JSONB
+);
+
+-- Cursor pagination walks (occurred_at, audit_id) newest first
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_occurred ON admin_audit_log (occurred_at DESC, audit_id DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_actor ON admin_audit_log (actor_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_entity ON admin_audit_log (entity_type, entity_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_action ON admin_audit_log (action, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_ip ON admin_audit_log (ip_address, occurred_at DESC);
+
+-- Cold storage for rows past the retention period; kept unindexed apart from the key
+CREATE TABLE IF NOT EXISTS admin_audit_log_archive (
+    audit_id VARCHAR(36) PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    actor_id VARCHAR(64),
+    actor_name VARCHAR(255),
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL DEFAULT '',
+    action VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(64),
+    user_agent VARCHAR(512),
+    changes JSONB,
+    metadata JSONB,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,23 @@
+-- Compensating stock releases queued when orders are cancelled; retried until
+-- completed or dead-lettered for reconciliation
+CREATE TABLE IF NOT EXISTS order_inventory_deallocation (
+    deallocation_id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    order_item_id BIGINT NOT NULL,
+    sku_id BIGINT NOT NULL,
+    quantity INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_by VARCHAR(255) NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT uq_order_inventory_deallocation_item UNIQUE (order_item_id),
+    CONSTRAINT fk_order_inventory_deallocation_order_id FOREIGN KEY (order_id) REFERENCES blc_order(order_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_inventory_deallocation_due ON order_inventory_deallocation (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_order_inventory_deallocation_order_id ON order_inventory_deallocation (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_inventory_deallocation_unresolved ON order_inventory_deallocation (created_at) WHERE status = 'DEAD_LETTER' OR (status = 'PENDING' AND attempts > 0);
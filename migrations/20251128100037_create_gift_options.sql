@@ -0,0 +1,23 @@
+-- Gift messages referenced by blc_order_item.personal_message_id and blc_fulfillment_group.personal_message_id
+CREATE TABLE IF NOT EXISTS blc_personal_message (
+    personal_message_id BIGSERIAL PRIMARY KEY,
+    message VARCHAR(255) NULL,
+    message_from VARCHAR(255) NULL,
+    message_to VARCHAR(255) NULL,
+    occasion VARCHAR(255) NULL
+);
+
+-- Gift wraps offered on the storefront, each sold through a catalog SKU
+CREATE TABLE IF NOT EXISTS order_gift_wrap_option (
+    gift_wrap_option_id BIGSERIAL PRIMARY KEY,
+    sku_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_order_gift_wrap_option_sku_id UNIQUE (sku_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blc_order_item_gift_wrap_item_id ON blc_order_item (gift_wrap_item_id);
+CREATE INDEX IF NOT EXISTS idx_blc_order_item_personal_message_id ON blc_order_item (personal_message_id);
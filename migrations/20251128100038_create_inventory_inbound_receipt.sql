@@ -0,0 +1,17 @@
+-- Stock expected to arrive at a warehouse; expected receipts count towards
+-- the available-to-promise quantity of their SKU
+CREATE TABLE IF NOT EXISTS inventory_inbound_receipt (
+    id VARCHAR(36) PRIMARY KEY,
+    sku_id VARCHAR(255) NOT NULL,
+    warehouse_id VARCHAR(255) NULL,
+    quantity INTEGER NOT NULL,
+    expected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reference VARCHAR(255) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'EXPECTED',
+    received_at TIMESTAMP WITH TIME ZONE NULL,
+    date_created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    date_updated TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_inventory_inbound_receipt_quantity CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_inbound_receipt_expected ON inventory_inbound_receipt (sku_id, expected_at) WHERE status = 'EXPECTED';
@@ -0,0 +1,12 @@
+-- Listing rules of a category: pinned products first, in order, then the
+-- remaining products by the sort rule
+CREATE TABLE IF NOT EXISTS catalog_category_merchandising (
+    category_id BIGINT PRIMARY KEY,
+    pinned_product_ids BIGINT[] NOT NULL DEFAULT '{}',
+    sort_rule VARCHAR(30) NOT NULL DEFAULT '',
+    best_seller_days INTEGER NOT NULL DEFAULT 30,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_catalog_category_merchandising_best_seller_days CHECK (best_seller_days > 0)
+);
+
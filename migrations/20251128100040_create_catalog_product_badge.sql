@@ -0,0 +1,18 @@
+-- Manual product badges, optionally scheduled for a campaign. Computed badges
+-- (NEW, SALE, BESTSELLER) are evaluated at read time and not stored.
+CREATE TABLE IF NOT EXISTS catalog_product_badge (
+    badge_id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    campaign VARCHAR(100) NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_catalog_product_badge_product_id FOREIGN KEY (product_id) REFERENCES blc_product(product_id) ON DELETE CASCADE,
+    CONSTRAINT chk_catalog_product_badge_window CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_product_badge_product_id ON catalog_product_badge (product_id);
+CREATE INDEX IF NOT EXISTS idx_catalog_product_badge_code ON catalog_product_badge (code);
@@ -0,0 +1,21 @@
+-- Orders placed by agents on behalf of phone and mail customers (MOTO), with
+-- the agent, the overrides they applied and how the order is paid
+CREATE TABLE IF NOT EXISTS order_assisted (
+    order_id BIGINT PRIMARY KEY,
+    channel VARCHAR(20) NOT NULL,
+    agent_id VARCHAR(255) NOT NULL,
+    guest BOOLEAN NOT NULL DEFAULT FALSE,
+    price_overridden BOOLEAN NOT NULL DEFAULT FALSE,
+    cart_policy_bypassed BOOLEAN NOT NULL DEFAULT FALSE,
+    payment_method VARCHAR(20) NOT NULL,
+    payment_status VARCHAR(20) NOT NULL,
+    transaction_id VARCHAR(255) NULL,
+    pay_link_token VARCHAR(64) NULL,
+    pay_link_expires_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_order_assisted_pay_link_token UNIQUE (pay_link_token),
+    CONSTRAINT fk_order_assisted_order_id FOREIGN KEY (order_id) REFERENCES blc_order(order_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_assisted_agent_id ON order_assisted (agent_id);
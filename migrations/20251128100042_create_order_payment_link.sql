@@ -0,0 +1,26 @@
+-- Secure, expiring links customers pay unpaid orders with (assisted orders,
+-- failed payment recovery and quotes)
+CREATE TABLE IF NOT EXISTS order_payment_link (
+    id BIGSERIAL PRIMARY KEY,
+    token VARCHAR(64) NOT NULL,
+    order_id BIGINT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    amount NUMERIC(19, 5) NOT NULL,
+    currency_code VARCHAR(3) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by VARCHAR(255) NULL,
+    transaction_id VARCHAR(255) NULL,
+    paid_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_order_payment_link_token UNIQUE (token),
+    CONSTRAINT fk_order_payment_link_order_id FOREIGN KEY (order_id) REFERENCES blc_order(order_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_payment_link_order_id ON order_payment_link (order_id, created_at DESC);
+
+-- Assisted orders paid by link now use order_payment_link
+ALTER TABLE order_assisted DROP CONSTRAINT IF EXISTS uq_order_assisted_pay_link_token;
+ALTER TABLE order_assisted DROP COLUMN IF EXISTS pay_link_token;
+ALTER TABLE order_assisted DROP COLUMN IF EXISTS pay_link_expires_at;
@@ -0,0 +1,18 @@
+-- Locale and currency storefront visitors selected per site: by customer once
+-- signed in, by session for anonymous visitors
+CREATE TABLE IF NOT EXISTS storefront_visitor_preference (
+    id BIGSERIAL PRIMARY KEY,
+    site_id VARCHAR(100) NOT NULL,
+    customer_id BIGINT NULL,
+    session_id VARCHAR(255) NULL,
+    locale VARCHAR(20) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_storefront_visitor_preference_owner CHECK (customer_id IS NOT NULL OR session_id IS NOT NULL),
+    CONSTRAINT fk_storefront_visitor_preference_customer_id FOREIGN KEY (customer_id) REFERENCES blc_customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_storefront_visitor_preference_customer
+    ON storefront_visitor_preference (site_id, customer_id) WHERE customer_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_storefront_visitor_preference_session
+    ON storefront_visitor_preference (site_id, session_id) WHERE customer_id IS NULL;
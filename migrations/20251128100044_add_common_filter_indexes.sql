@@ -0,0 +1,15 @@
+-- Indexes for the filters repositories use most that were still missing
+
+-- SKUs of a product (listings, availability, best sellers)
+CREATE INDEX IF NOT EXISTS idx_blc_sku_default_product_id ON blc_sku (default_product_id);
+
+-- Product lookup by URL, which only ever targets unarchived products
+CREATE INDEX IF NOT EXISTS idx_blc_product_url ON blc_product (url) WHERE archived = 'N';
+
+-- Order history of a customer, optionally by status, newest first
+CREATE INDEX IF NOT EXISTS idx_blc_order_customer_id_date_created ON blc_order (customer_id, date_created DESC);
+CREATE INDEX IF NOT EXISTS idx_blc_order_customer_id_order_status ON blc_order (customer_id, order_status);
+
+-- Admin order listing, unfiltered or by status, newest first
+CREATE INDEX IF NOT EXISTS idx_blc_order_date_created ON blc_order (date_created DESC);
+CREATE INDEX IF NOT EXISTS idx_blc_order_order_status_date_created ON blc_order (order_status, date_created DESC);
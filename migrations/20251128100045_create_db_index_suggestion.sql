@@ -0,0 +1,15 @@
+-- Sequential scans on watched tables found by EXPLAIN in development, shared
+-- by every process so the admin diagnostics report covers all of them
+CREATE TABLE IF NOT EXISTS db_index_suggestion (
+    id BIGSERIAL PRIMARY KEY,
+    table_name VARCHAR(255) NOT NULL,
+    columns VARCHAR(1000) NOT NULL DEFAULT '',
+    statement TEXT NOT NULL DEFAULT '',
+    filter TEXT NOT NULL DEFAULT '',
+    estimated_rows DOUBLE PRECISION NOT NULL DEFAULT 0,
+    queries TEXT[] NOT NULL DEFAULT '{}',
+    occurrences BIGINT NOT NULL DEFAULT 0,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_db_index_suggestion_table_columns UNIQUE (table_name, columns)
+);
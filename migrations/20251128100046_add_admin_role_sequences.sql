@@ -0,0 +1,15 @@
+-- Role templates and role cloning create admin roles and permissions from the
+-- API, so their IDs are drawn from sequences past the legacy rows
+CREATE SEQUENCE IF NOT EXISTS blc_admin_role_seq;
+SELECT setval('blc_admin_role_seq', COALESCE((SELECT MAX(admin_role_id) FROM blc_admin_role), 0) + 1, false);
+ALTER TABLE blc_admin_role ALTER COLUMN admin_role_id SET DEFAULT nextval('blc_admin_role_seq');
+
+CREATE SEQUENCE IF NOT EXISTS blc_admin_permission_seq;
+SELECT setval('blc_admin_permission_seq', COALESCE((SELECT MAX(admin_permission_id) FROM blc_admin_permission), 0) + 1, false);
+ALTER TABLE blc_admin_permission ALTER COLUMN admin_permission_id SET DEFAULT nextval('blc_admin_permission_seq');
+
+-- Permissions are granted by name
+CREATE UNIQUE INDEX IF NOT EXISTS uq_blc_admin_permission_name ON blc_admin_permission (name);
+
+CREATE INDEX IF NOT EXISTS idx_blc_admin_user_role_xref_admin_user_id ON blc_admin_user_role_xref (admin_user_id);
+CREATE INDEX IF NOT EXISTS idx_blc_admin_user_permission_xref_admin_user_id ON blc_admin_user_permission_xref (admin_user_id);
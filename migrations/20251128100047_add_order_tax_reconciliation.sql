@@ -0,0 +1,5 @@
+-- Orders whose tax was estimated in the cart record the estimate and the
+-- difference made by the calculation at submission
+ALTER TABLE blc_order ADD COLUMN IF NOT EXISTS estimated_tax NUMERIC(19, 5) NULL;
+ALTER TABLE blc_order ADD COLUMN IF NOT EXISTS tax_reconciliation NUMERIC(19, 5) NULL;
+ALTER TABLE blc_order ADD COLUMN IF NOT EXISTS tax_provider VARCHAR(64) NULL;
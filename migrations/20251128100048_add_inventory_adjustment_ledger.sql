@@ -0,0 +1,19 @@
+-- Ledger of changes to quantities on hand, e.g. from bulk inventory imports
+CREATE TABLE IF NOT EXISTS blc_inventory_adjustment (
+    id VARCHAR(36) PRIMARY KEY,
+    inventory_id VARCHAR(36) NOT NULL,
+    sku_id VARCHAR(255) NOT NULL,
+    warehouse_id VARCHAR(255) NULL,
+    qty_before INTEGER NOT NULL,
+    qty_after INTEGER NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL DEFAULT '',
+    date_created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustment_sku ON blc_inventory_adjustment (sku_id, date_created DESC);
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustment_reference ON blc_inventory_adjustment (reference);
+
+-- Exports of one warehouse's levels
+CREATE INDEX IF NOT EXISTS idx_inventory_level_warehouse_sku ON blc_inventory_level (warehouse_id, sku_id);
@@ -0,0 +1,26 @@
+-- Customer attributes are managed from the admin API: IDs come from a sequence
+-- past the legacy rows, each name appears once per customer, and values are typed
+CREATE SEQUENCE IF NOT EXISTS blc_customer_attribute_seq;
+SELECT setval('blc_customer_attribute_seq', COALESCE((SELECT MAX(customer_attr_id) FROM blc_customer_attribute), 0) + 1, false);
+ALTER TABLE blc_customer_attribute ALTER COLUMN customer_attr_id SET DEFAULT nextval('blc_customer_attribute_seq');
+ALTER TABLE blc_customer_attribute ADD COLUMN IF NOT EXISTS value_type VARCHAR(16) NOT NULL DEFAULT 'STRING';
+
+DELETE FROM blc_customer_attribute a
+USING blc_customer_attribute b
+WHERE a.customer_id = b.customer_id AND a.name = b.name AND a.customer_attr_id < b.customer_attr_id;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_blc_customer_attribute_customer_name ON blc_customer_attribute (customer_id, name);
+CREATE INDEX IF NOT EXISTS idx_blc_customer_attribute_name_value ON blc_customer_attribute (name, value);
+
+-- Tags group customers for CRM integrations, offer targeting and segment rules
+CREATE TABLE IF NOT EXISTS blc_customer_tag (
+    customer_id BIGINT NOT NULL REFERENCES blc_customer(customer_id) ON DELETE CASCADE,
+    tag VARCHAR(64) NOT NULL,
+    date_created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (customer_id, tag)
+);
+
+-- Filtering customers by tag
+CREATE INDEX IF NOT EXISTS idx_blc_customer_tag_tag ON blc_customer_tag (tag, customer_id);
+
+-- Offers can be limited to customers carrying one of a set of tags
+ALTER TABLE blc_offer ADD COLUMN IF NOT EXISTS customer_tags TEXT[] NOT NULL DEFAULT '{}';
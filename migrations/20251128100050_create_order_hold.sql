@@ -0,0 +1,22 @@
+-- Holds pausing the fulfillment of submitted orders under review; the order is
+-- ON_HOLD until its open hold is released or the order is cancelled
+CREATE TABLE IF NOT EXISTS order_hold (
+    hold_id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    previous_status VARCHAR(255) NOT NULL,
+    placed_by VARCHAR(255) NOT NULL,
+    placed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolution VARCHAR(20) NULL,
+    resolved_by VARCHAR(255) NULL,
+    resolution_note TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMP WITH TIME ZONE NULL,
+    sla_notified_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT fk_order_hold_order_id FOREIGN KEY (order_id) REFERENCES blc_order(order_id) ON DELETE CASCADE
+);
+
+-- An order has at most one open hold
+CREATE UNIQUE INDEX IF NOT EXISTS uq_order_hold_open ON order_hold (order_id) WHERE resolution IS NULL;
+CREATE INDEX IF NOT EXISTS idx_order_hold_queue ON order_hold (placed_at) WHERE resolution IS NULL;
+CREATE INDEX IF NOT EXISTS idx_order_hold_order_id ON order_hold (order_id, placed_at DESC);
@@ -0,0 +1,11 @@
+-- Storefront address books create addresses: IDs come from sequences past the legacy rows
+CREATE SEQUENCE IF NOT EXISTS blc_address_seq;
+SELECT setval('blc_address_seq', COALESCE((SELECT MAX(address_id) FROM blc_address), 0) + 1, false);
+ALTER TABLE blc_address ALTER COLUMN address_id SET DEFAULT nextval('blc_address_seq');
+
+CREATE SEQUENCE IF NOT EXISTS blc_customer_address_seq;
+SELECT setval('blc_customer_address_seq', COALESCE((SELECT MAX(customer_address_id) FROM blc_customer_address), 0) + 1, false);
+ALTER TABLE blc_customer_address ALTER COLUMN customer_address_id SET DEFAULT nextval('blc_customer_address_seq');
+
+-- Listing a customer's address book
+CREATE INDEX IF NOT EXISTS idx_blc_customer_address_customer ON blc_customer_address (customer_id);
@@ -0,0 +1,22 @@
+-- Progress and schema of the datasets exported to the data warehouse
+CREATE TABLE IF NOT EXISTS analytics_export_watermark (
+    dataset VARCHAR(64) PRIMARY KEY,
+    last_timestamp TIMESTAMP NULL,
+    last_key VARCHAR(255) NOT NULL DEFAULT '',
+    schema_version INT NOT NULL DEFAULT 1,
+    schema_major INT NOT NULL DEFAULT 1,
+    schema_columns JSONB NOT NULL DEFAULT '[]',
+    rows_exported BIGINT NOT NULL DEFAULT 0,
+    files_exported BIGINT NOT NULL DEFAULT 0,
+    last_run_at TIMESTAMP WITH TIME ZONE NULL,
+    last_success_at TIMESTAMP WITH TIME ZONE NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Incremental extraction reads each table in watermark order
+CREATE INDEX IF NOT EXISTS idx_blc_order_export_watermark
+    ON blc_order ((COALESCE(date_updated, date_created, created_at AT TIME ZONE 'UTC')), order_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustment_export_watermark ON blc_inventory_adjustment (date_created, id);
+CREATE INDEX IF NOT EXISTS idx_blc_order_adjustment_created_at ON blc_order_adjustment (created_at);
+CREATE INDEX IF NOT EXISTS idx_blc_order_item_adjustment_created_at ON blc_order_item_adjustment (created_at);
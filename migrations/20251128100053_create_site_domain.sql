@@ -0,0 +1,24 @@
+-- Custom domains serving storefront sites; certificates are only requested
+-- for VERIFIED domains, whose owner published the verification token in DNS
+CREATE TABLE IF NOT EXISTS site_domain (
+    domain_id BIGSERIAL PRIMARY KEY,
+    site_id VARCHAR(64) NOT NULL,
+    hostname VARCHAR(253) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE NULL,
+    last_checked_at TIMESTAMP WITH TIME ZONE NULL,
+    last_check_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_site_domain_hostname UNIQUE (hostname)
+);
+
+CREATE INDEX IF NOT EXISTS idx_site_domain_site_id ON site_domain (site_id);
+
+-- ACME account keys and issued certificates shared by storefront instances
+CREATE TABLE IF NOT EXISTS site_certificate_cache (
+    cache_key VARCHAR(255) PRIMARY KEY,
+    data BYTEA NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,9 @@
+-- Runtime switches such as storefront maintenance mode and subsystem kill
+-- switches; flags without a row keep their built-in default
+CREATE TABLE IF NOT EXISTS feature_flag (
+    flag_key VARCHAR(64) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    updated_by VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,20 @@
+-- Stock held for orders placed on external sales channels (marketplaces).
+-- Active reservations are counted in qty_reserved of their inventory level.
+CREATE TABLE IF NOT EXISTS inventory_channel_reservation (
+    id VARCHAR(36) PRIMARY KEY,
+    channel VARCHAR(64) NOT NULL,
+    reference VARCHAR(255) NOT NULL,
+    sku_id VARCHAR(255) NOT NULL,
+    inventory_id VARCHAR(36) NOT NULL,
+    quantity INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    released_at TIMESTAMP WITH TIME ZONE NULL,
+    date_created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    date_updated TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_inventory_channel_reservation_reference UNIQUE (channel, reference),
+    CONSTRAINT chk_inventory_channel_reservation_quantity CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_channel_reservation_sku ON inventory_channel_reservation (channel, sku_id) WHERE status = 'ACTIVE';
+CREATE INDEX IF NOT EXISTS idx_inventory_channel_reservation_expiry ON inventory_channel_reservation (expires_at) WHERE status = 'ACTIVE';
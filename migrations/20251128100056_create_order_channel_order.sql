@@ -0,0 +1,31 @@
+-- Orders ingested from external sales channels (marketplaces): one row per
+-- channel order, so a resent order is never created twice
+CREATE TABLE IF NOT EXISTS order_channel_order (
+    id BIGSERIAL PRIMARY KEY,
+    channel VARCHAR(64) NOT NULL,
+    external_order_id VARCHAR(255) NOT NULL,
+    order_id BIGINT NULL REFERENCES blc_order(order_id),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    acknowledged_at TIMESTAMP WITH TIME ZONE NULL,
+    date_created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    date_updated TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_order_channel_order_external UNIQUE (channel, external_order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_channel_order_created ON order_channel_order (date_created DESC);
+
+-- Channel SKUs mapped to internal SKUs
+CREATE TABLE IF NOT EXISTS order_channel_sku_mapping (
+    channel VARCHAR(64) NOT NULL,
+    external_sku VARCHAR(255) NOT NULL,
+    sku_id BIGINT NOT NULL,
+    date_created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    date_updated TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (channel, external_sku)
+);
+
+-- Orders record their channel as an attribute: IDs come from a sequence past the legacy rows
+CREATE SEQUENCE IF NOT EXISTS blc_order_attribute_seq;
+SELECT setval('blc_order_attribute_seq', COALESCE((SELECT MAX(order_attribute_id) FROM blc_order_attribute), 0) + 1, false);
@@ -0,0 +1,25 @@
+-- Products are published through a review: new products start as drafts and
+-- only published products are served on the storefront. Existing products are
+-- already live, so they are backfilled as published.
+ALTER TABLE blc_product ADD COLUMN IF NOT EXISTS publish_status VARCHAR(20) NOT NULL DEFAULT 'PUBLISHED';
+ALTER TABLE blc_product ALTER COLUMN publish_status SET DEFAULT 'DRAFT';
+ALTER TABLE blc_product ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE blc_product ADD COLUMN IF NOT EXISTS published_by VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_blc_product_publish_status ON blc_product (publish_status);
+
+-- Edits of published products awaiting approval; the live row keeps serving the
+-- published version until a reviewer approves the draft
+CREATE TABLE IF NOT EXISTS catalog_product_draft (
+    product_id BIGINT PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    data JSONB NOT NULL,
+    edited_by VARCHAR(255) NOT NULL DEFAULT '',
+    reviewed_by VARCHAR(255) NOT NULL DEFAULT '',
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_catalog_product_draft_product_id FOREIGN KEY (product_id) REFERENCES blc_product(product_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_product_draft_status ON catalog_product_draft (status, updated_at DESC);
@@ -0,0 +1,23 @@
+-- Saved filters and column selections of the admin order, product and customer
+-- lists, owned by an admin user and optionally shared with admin roles
+CREATE TABLE IF NOT EXISTS admin_saved_view (
+    saved_view_id BIGSERIAL PRIMARY KEY,
+    admin_user_id BIGINT NOT NULL,
+    list_type VARCHAR(32) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    columns JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_admin_saved_view_name UNIQUE (admin_user_id, list_type, name)
+);
+
+CREATE TABLE IF NOT EXISTS admin_saved_view_role (
+    saved_view_id BIGINT NOT NULL,
+    admin_role_id BIGINT NOT NULL,
+    PRIMARY KEY (saved_view_id, admin_role_id),
+    CONSTRAINT fk_admin_saved_view_role_view FOREIGN KEY (saved_view_id) REFERENCES admin_saved_view(saved_view_id) ON DELETE CASCADE,
+    CONSTRAINT fk_admin_saved_view_role_role FOREIGN KEY (admin_role_id) REFERENCES blc_admin_role(admin_role_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_saved_view_role_role ON admin_saved_view_role (admin_role_id);
@@ -0,0 +1,27 @@
+-- Start and end dates of offers and catalog items were naive timestamps,
+-- compared with NOW() in the session time zone of whichever server ran the
+-- query. They are stored as instants from now on; existing values were written
+-- as UTC wall-clock times, so they are read as UTC.
+ALTER TABLE blc_offer
+    ALTER COLUMN start_date TYPE TIMESTAMP WITH TIME ZONE USING start_date AT TIME ZONE 'UTC',
+    ALTER COLUMN end_date TYPE TIMESTAMP WITH TIME ZONE USING end_date AT TIME ZONE 'UTC';
+
+ALTER TABLE blc_offer_code
+    ALTER COLUMN start_date TYPE TIMESTAMP WITH TIME ZONE USING start_date AT TIME ZONE 'UTC',
+    ALTER COLUMN end_date TYPE TIMESTAMP WITH TIME ZONE USING end_date AT TIME ZONE 'UTC';
+
+ALTER TABLE blc_offer_price_data
+    ALTER COLUMN start_date TYPE TIMESTAMP WITH TIME ZONE USING start_date AT TIME ZONE 'UTC',
+    ALTER COLUMN end_date TYPE TIMESTAMP WITH TIME ZONE USING end_date AT TIME ZONE 'UTC';
+
+ALTER TABLE blc_category
+    ALTER COLUMN active_start_date TYPE TIMESTAMP WITH TIME ZONE USING active_start_date AT TIME ZONE 'UTC',
+    ALTER COLUMN active_end_date TYPE TIMESTAMP WITH TIME ZONE USING active_end_date AT TIME ZONE 'UTC';
+
+ALTER TABLE blc_sku
+    ALTER COLUMN active_start_date TYPE TIMESTAMP WITH TIME ZONE USING active_start_date AT TIME ZONE 'UTC',
+    ALTER COLUMN active_end_date TYPE TIMESTAMP WITH TIME ZONE USING active_end_date AT TIME ZONE 'UTC';
+
+ALTER TABLE blc_product
+    ALTER COLUMN active_start_date TYPE TIMESTAMP WITH TIME ZONE USING active_start_date AT TIME ZONE 'UTC',
+    ALTER COLUMN active_end_date TYPE TIMESTAMP WITH TIME ZONE USING active_end_date AT TIME ZONE 'UTC';
@@ -0,0 +1,19 @@
+-- Serial numbers of high-value order items, recorded per unit by the
+-- warehouse when a shipment is packed. Serial numbers are stored normalized
+-- (trimmed, uppercase).
+CREATE TABLE IF NOT EXISTS fulfillment_shipment_serial (
+    shipment_serial_id BIGSERIAL PRIMARY KEY,
+    shipment_id BIGINT NOT NULL,
+    order_id BIGINT NOT NULL,
+    order_item_id BIGINT NOT NULL,
+    sku_id BIGINT NOT NULL,
+    serial_number VARCHAR(255) NOT NULL,
+    recorded_by VARCHAR(255) NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_fulfillment_shipment_serial UNIQUE (shipment_id, serial_number),
+    CONSTRAINT fk_fulfillment_shipment_serial_shipment FOREIGN KEY (shipment_id) REFERENCES blc_fulfillment_group(fulfillment_group_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_fulfillment_shipment_serial_number ON fulfillment_shipment_serial (serial_number text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_fulfillment_shipment_serial_order ON fulfillment_shipment_serial (order_id);
+CREATE INDEX IF NOT EXISTS idx_fulfillment_shipment_serial_sku ON fulfillment_shipment_serial (sku_id);
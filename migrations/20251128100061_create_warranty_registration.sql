@@ -0,0 +1,33 @@
+-- Warranty terms per product, counted from the purchase date
+CREATE TABLE IF NOT EXISTS warranty_terms (
+    warranty_terms_id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    duration_months INT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_warranty_terms_product UNIQUE (product_id)
+);
+
+-- Purchased units registered by customers, linked to the order item they were
+-- bought with. The warranty in force at registration is kept with them.
+CREATE TABLE IF NOT EXISTS product_registration (
+    registration_id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT NOT NULL,
+    order_id BIGINT NOT NULL,
+    order_item_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    sku_id BIGINT NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    serial_number VARCHAR(255) NOT NULL DEFAULT '',
+    purchased_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    warranty_months INT NOT NULL DEFAULT 0,
+    warranty_expires_at TIMESTAMP WITH TIME ZONE NULL,
+    registered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_registration_serial ON product_registration (order_item_id, serial_number) WHERE serial_number <> '';
+CREATE INDEX IF NOT EXISTS idx_product_registration_customer ON product_registration (customer_id);
+CREATE INDEX IF NOT EXISTS idx_product_registration_order_item ON product_registration (order_item_id);
+CREATE INDEX IF NOT EXISTS idx_product_registration_order ON product_registration (order_id);
+CREATE INDEX IF NOT EXISTS idx_product_registration_serial_number ON product_registration (serial_number) WHERE serial_number <> '';
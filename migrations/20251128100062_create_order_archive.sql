@@ -0,0 +1,41 @@
+-- Completed orders past the retention window of their status are moved out of
+-- the live order tables into archive copies, and read back from there when
+-- they are not found live. The archive tables mirror the live ones column for
+-- column: a column added to a live table must be added to its archive too.
+CREATE TABLE IF NOT EXISTS blc_order_archive (LIKE blc_order);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_blc_order_archive_order_id ON blc_order_archive (order_id);
+CREATE INDEX IF NOT EXISTS idx_blc_order_archive_customer_id ON blc_order_archive (customer_id);
+CREATE INDEX IF NOT EXISTS idx_blc_order_archive_order_number ON blc_order_archive (order_number);
+
+CREATE TABLE IF NOT EXISTS blc_order_item_archive (LIKE blc_order_item);
+CREATE INDEX IF NOT EXISTS idx_blc_order_item_archive_order_id ON blc_order_item_archive (order_id);
+
+CREATE TABLE IF NOT EXISTS blc_order_item_add_attr_archive (LIKE blc_order_item_add_attr);
+CREATE INDEX IF NOT EXISTS idx_blc_order_item_add_attr_archive_order_item_id ON blc_order_item_add_attr_archive (order_item_id);
+
+CREATE TABLE IF NOT EXISTS blc_order_item_adjustment_archive (LIKE blc_order_item_adjustment);
+CREATE INDEX IF NOT EXISTS idx_blc_order_item_adjustment_archive_order_item_id ON blc_order_item_adjustment_archive (order_item_id);
+
+CREATE TABLE IF NOT EXISTS blc_order_adjustment_archive (LIKE blc_order_adjustment);
+CREATE INDEX IF NOT EXISTS idx_blc_order_adjustment_archive_order_id ON blc_order_adjustment_archive (order_id);
+
+CREATE TABLE IF NOT EXISTS blc_order_attribute_archive (LIKE blc_order_attribute);
+CREATE INDEX IF NOT EXISTS idx_blc_order_attribute_archive_order_id ON blc_order_attribute_archive (order_id);
+
+-- Holds, notes, payment links and the like keep pointing at an order once it
+-- is archived, so they can no longer reference the live table
+ALTER TABLE blc_order_offer_code_xref DROP CONSTRAINT IF EXISTS fk_blc_order_offer_code_xref_order_id;
+ALTER TABLE order_hold DROP CONSTRAINT IF EXISTS fk_order_hold_order_id;
+ALTER TABLE order_note DROP CONSTRAINT IF EXISTS fk_order_note_order_id;
+ALTER TABLE order_inventory_deallocation DROP CONSTRAINT IF EXISTS fk_order_inventory_deallocation_order_id;
+ALTER TABLE order_payment_link DROP CONSTRAINT IF EXISTS fk_order_payment_link_order_id;
+ALTER TABLE order_assisted DROP CONSTRAINT IF EXISTS fk_order_assisted_order_id;
+ALTER TABLE order_channel_order DROP CONSTRAINT IF EXISTS order_channel_order_order_id_fkey;
+
+-- Retention window per order status; statuses without a policy are never archived
+CREATE TABLE IF NOT EXISTS order_retention_policy (
+    order_status VARCHAR(255) PRIMARY KEY,
+    retention_months INT NOT NULL,
+    updated_by VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
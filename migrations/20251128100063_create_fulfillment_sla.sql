@@ -0,0 +1,32 @@
+-- Business days each shipping method has to ship; the row with an empty
+-- method applies to every other method
+CREATE TABLE IF NOT EXISTS fulfillment_sla_policy (
+    shipping_method VARCHAR(255) PRIMARY KEY,
+    ship_within_days INT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Dates the warehouse does not ship on, on top of its non-working weekdays
+CREATE TABLE IF NOT EXISTS fulfillment_holiday (
+    holiday_date DATE PRIMARY KEY,
+    name VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+-- Ship-by deadline and SLA status of each fulfillment group, from the
+-- submission of its order until it ships
+CREATE TABLE IF NOT EXISTS fulfillment_sla (
+    fulfillment_group_id BIGINT PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ship_within_days INT NOT NULL,
+    ship_by TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    shipped_at TIMESTAMP WITH TIME ZONE NULL,
+    alerted_status VARCHAR(20) NOT NULL DEFAULT '',
+    alerted_at TIMESTAMP WITH TIME ZONE NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_fulfillment_sla_group FOREIGN KEY (fulfillment_group_id) REFERENCES blc_fulfillment_group(fulfillment_group_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_fulfillment_sla_status_ship_by ON fulfillment_sla (status, ship_by);
+CREATE INDEX IF NOT EXISTS idx_fulfillment_sla_shipped_at ON fulfillment_sla (shipped_at) WHERE shipped_at IS NOT NULL;
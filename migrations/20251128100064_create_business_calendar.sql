@@ -0,0 +1,68 @@
+-- Named sets of holidays (e.g. a country's public holidays) shared by the
+-- business calendars of sites and warehouses
+CREATE TABLE IF NOT EXISTS holiday_set (
+    holiday_set_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holiday_set_name ON holiday_set (LOWER(name));
+
+CREATE TABLE IF NOT EXISTS holiday (
+    holiday_set_id BIGINT NOT NULL,
+    holiday_date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (holiday_set_id, holiday_date),
+    CONSTRAINT fk_holiday_set FOREIGN KEY (holiday_set_id) REFERENCES holiday_set(holiday_set_id) ON DELETE CASCADE
+);
+
+-- Working days of the default calendar, a site or a warehouse. NULL columns
+-- inherit from the site, then the default calendar, then the configuration.
+CREATE TABLE IF NOT EXISTS business_calendar (
+    calendar_id BIGSERIAL PRIMARY KEY,
+    scope_type VARCHAR(20) NOT NULL,
+    scope_id VARCHAR(255) NOT NULL DEFAULT '',
+    time_zone VARCHAR(64) NULL,
+    workdays SMALLINT[] NULL,
+    cutoff_hour SMALLINT NULL,
+    updated_by VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_business_calendar_scope UNIQUE (scope_type, scope_id)
+);
+
+-- Holiday sets a calendar observes; a calendar without any inherits them
+CREATE TABLE IF NOT EXISTS business_calendar_holiday_set (
+    calendar_id BIGINT NOT NULL,
+    holiday_set_id BIGINT NOT NULL,
+    PRIMARY KEY (calendar_id, holiday_set_id),
+    CONSTRAINT fk_business_calendar_holiday_set_calendar FOREIGN KEY (calendar_id) REFERENCES business_calendar(calendar_id) ON DELETE CASCADE,
+    CONSTRAINT fk_business_calendar_holiday_set_set FOREIGN KEY (holiday_set_id) REFERENCES holiday_set(holiday_set_id) ON DELETE CASCADE
+);
+
+-- Fulfillment holidays move to a holiday set observed by the default calendar
+DO $$
+DECLARE
+    set_id BIGINT;
+    default_calendar_id BIGINT;
+BEGIN
+    IF EXISTS (SELECT 1 FROM fulfillment_holiday) THEN
+        INSERT INTO holiday_set (name, description)
+        VALUES ('Fulfillment holidays', 'Dates the warehouse did not ship on before business calendars')
+        RETURNING holiday_set_id INTO set_id;
+
+        INSERT INTO holiday (holiday_set_id, holiday_date, name)
+        SELECT set_id, holiday_date, name FROM fulfillment_holiday;
+
+        INSERT INTO business_calendar (scope_type, scope_id)
+        VALUES ('DEFAULT', '')
+        ON CONFLICT (scope_type, scope_id) DO UPDATE SET updated_at = NOW()
+        RETURNING calendar_id INTO default_calendar_id;
+
+        INSERT INTO business_calendar_holiday_set (calendar_id, holiday_set_id)
+        VALUES (default_calendar_id, set_id);
+    END IF;
+END $$;
+
+DROP TABLE IF EXISTS fulfillment_holiday;
@@ -0,0 +1,36 @@
+-- Pairs of customer accounts that look like the same person, queued for admin
+-- review. The lower customer ID is always first so a pair is stored once.
+CREATE TABLE IF NOT EXISTS customer_duplicate (
+    duplicate_id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT NOT NULL,
+    other_customer_id BIGINT NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    score INT NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_by VARCHAR(255) NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT uq_customer_duplicate_pair UNIQUE (customer_id, other_customer_id),
+    CONSTRAINT chk_customer_duplicate_order CHECK (customer_id < other_customer_id),
+    CONSTRAINT chk_customer_duplicate_status CHECK (status IN ('PENDING', 'DISMISSED', 'MERGED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_duplicate_status ON customer_duplicate (status, score DESC, duplicate_id);
+CREATE INDEX IF NOT EXISTS idx_customer_duplicate_other ON customer_duplicate (other_customer_id);
+
+-- Accounts merged into another one, with the rows moved from each table
+CREATE TABLE IF NOT EXISTS customer_merge (
+    merge_id BIGSERIAL PRIMARY KEY,
+    surviving_customer_id BIGINT NOT NULL,
+    merged_customer_id BIGINT NOT NULL,
+    merged_email VARCHAR(255) NOT NULL DEFAULT '',
+    moved JSONB NOT NULL DEFAULT '{}',
+    merged_by VARCHAR(255) NOT NULL,
+    merged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_customer_merge_merged UNIQUE (merged_customer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_merge_surviving ON customer_merge (surviving_customer_id);
+
+-- Detection matches accounts by their normalized email and by name
+CREATE INDEX IF NOT EXISTS idx_blc_customer_lower_name ON blc_customer (LOWER(TRIM(last_name)), LOWER(TRIM(first_name)));
@@ -0,0 +1,42 @@
+-- Ways to pay outside the platform offered at checkout: cash on delivery, bank
+-- transfer and payment in store, each limited to countries and order totals
+CREATE TABLE IF NOT EXISTS offline_payment_method (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL,
+    payment_type VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    instructions TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    countries TEXT[] NOT NULL DEFAULT '{}',
+    min_order_total NUMERIC(19, 5) NULL,
+    max_order_total NUMERIC(19, 5) NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_offline_payment_method_code UNIQUE (code)
+);
+
+-- The offline payment an order was placed with; the order waits in
+-- AWAITING_PAYMENT until an admin records its receipt. Kept for archived
+-- orders, so it does not reference the live order table.
+CREATE TABLE IF NOT EXISTS order_offline_payment (
+    order_id BIGINT PRIMARY KEY,
+    method_id BIGINT NULL,
+    method_code VARCHAR(64) NOT NULL,
+    payment_type VARCHAR(20) NOT NULL,
+    amount NUMERIC(19, 5) NOT NULL,
+    currency_code VARCHAR(3) NULL,
+    reference VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'AWAITING',
+    received_amount NUMERIC(19, 5) NULL,
+    receipt_reference VARCHAR(255) NOT NULL DEFAULT '',
+    received_by VARCHAR(255) NOT NULL DEFAULT '',
+    received_at TIMESTAMP WITH TIME ZONE NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_order_offline_payment_method_id FOREIGN KEY (method_id) REFERENCES offline_payment_method(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_offline_payment_awaiting ON order_offline_payment (created_at) WHERE status = 'AWAITING';
+CREATE INDEX IF NOT EXISTS idx_order_offline_payment_reference ON order_offline_payment (reference);
@@ -0,0 +1,24 @@
+-- The tenders an order's payment is split across, e.g. a gift card and a card.
+-- Failed and voided attempts are kept; kept for archived orders too, so it
+-- does not reference the live order table.
+CREATE TABLE IF NOT EXISTS order_payment_tender (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    sequence INT NOT NULL,
+    method VARCHAR(32) NOT NULL,
+    amount NUMERIC(19, 5) NOT NULL,
+    currency_code VARCHAR(3) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    transaction_id VARCHAR(255) NOT NULL DEFAULT '',
+    captured_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    refunded_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    failure_reason TEXT NOT NULL DEFAULT '',
+    authorized_at TIMESTAMP WITH TIME ZONE NULL,
+    captured_at TIMESTAMP WITH TIME ZONE NULL,
+    refunded_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_order_payment_tender_sequence UNIQUE (order_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_payment_tender_transaction_id ON order_payment_tender (transaction_id);
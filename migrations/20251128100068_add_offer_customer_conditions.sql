@@ -0,0 +1,7 @@
+-- Offers can be limited by who the customer is: segment membership (role names
+-- or tags), first order only, a minimum lifetime spend and a minimum account age
+ALTER TABLE blc_offer ADD COLUMN IF NOT EXISTS customer_segments TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE blc_offer ADD COLUMN IF NOT EXISTS first_order_only BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE blc_offer ADD COLUMN IF NOT EXISTS min_lifetime_spend NUMERIC(19, 5);
+ALTER TABLE blc_offer ADD COLUMN IF NOT EXISTS min_account_age_days INTEGER;
+
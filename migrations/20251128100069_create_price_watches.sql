@@ -0,0 +1,37 @@
+-- Customers watch SKUs for a price drop below a watched price. One watch per
+-- customer and SKU; it is alerted once, then converted when the customer buys
+-- the SKU within the attribution window.
+CREATE TABLE IF NOT EXISTS catalog_price_watch (
+    price_watch_id BIGSERIAL PRIMARY KEY,
+    sku_id BIGINT NOT NULL REFERENCES blc_sku(sku_id) ON DELETE CASCADE,
+    customer_id BIGINT NOT NULL REFERENCES blc_customer(customer_id) ON DELETE CASCADE,
+    watched_price NUMERIC(19, 5) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'ACTIVE',
+    notified_price NUMERIC(19, 5) NULL,
+    notified_at TIMESTAMP WITH TIME ZONE NULL,
+    converted_order_id BIGINT NULL,
+    converted_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_catalog_price_watch_customer_sku UNIQUE (customer_id, sku_id)
+);
+
+-- Finding the watches a price drop triggers
+CREATE INDEX IF NOT EXISTS idx_catalog_price_watch_active ON catalog_price_watch (sku_id, watched_price) WHERE status = 'ACTIVE';
+-- Attributing purchases to alerts
+CREATE INDEX IF NOT EXISTS idx_catalog_price_watch_notified ON catalog_price_watch (notified_at) WHERE status = 'NOTIFIED';
+
+-- SKU prices applied at a future time, e.g. for a sale
+CREATE TABLE IF NOT EXISTS catalog_scheduled_price_change (
+    scheduled_price_change_id BIGSERIAL PRIMARY KEY,
+    sku_id BIGINT NOT NULL REFERENCES blc_sku(sku_id) ON DELETE CASCADE,
+    retail_price NUMERIC(19, 5) NOT NULL,
+    sale_price NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE NULL,
+    created_by VARCHAR(255) NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_scheduled_price_change_sku ON catalog_scheduled_price_change (sku_id, effective_at);
+CREATE INDEX IF NOT EXISTS idx_catalog_scheduled_price_change_due ON catalog_scheduled_price_change (effective_at) WHERE applied_at IS NULL;
@@ -0,0 +1,32 @@
+-- Offer codes created in bulk, either generated from a pattern or imported from
+-- a list produced elsewhere. Generation runs in the background and records its
+-- progress here.
+CREATE TABLE IF NOT EXISTS offer_code_batch (
+    batch_id BIGSERIAL PRIMARY KEY,
+    offer_id BIGINT NOT NULL REFERENCES blc_offer(offer_id),
+    source VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    pattern VARCHAR(255) NULL,
+    prefix VARCHAR(64) NULL,
+    charset VARCHAR(255) NULL,
+    quantity INT NOT NULL,
+    created_count INT NOT NULL DEFAULT 0,
+    max_uses INT NULL,
+    start_date TIMESTAMP WITH TIME ZONE NULL,
+    end_date TIMESTAMP WITH TIME ZONE NULL,
+    error TEXT NULL,
+    created_by VARCHAR(255) NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_code_batch_offer ON offer_code_batch (offer_id, created_at);
+
+ALTER TABLE blc_offer_code ADD COLUMN IF NOT EXISTS batch_id BIGINT NULL REFERENCES offer_code_batch(batch_id);
+
+CREATE INDEX IF NOT EXISTS idx_blc_offer_code_batch ON blc_offer_code (batch_id, offer_code_id) WHERE batch_id IS NOT NULL;
+
+-- Codes are looked up by their text alone, so they must be unique across offers.
+-- Bulk inserts skip codes already taken instead of failing the whole chunk.
+DROP INDEX IF EXISTS idx_blc_offer_code_code;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_blc_offer_code_code ON blc_offer_code (offer_code);
@@ -0,0 +1,35 @@
+-- Tags group orders into ops work queues ("fragile", "international", "vip").
+-- Staff tag orders by hand; rules tag submitted orders matching their conditions.
+CREATE TABLE IF NOT EXISTS order_tag_rule (
+    rule_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    tag VARCHAR(64) NOT NULL,
+    conditions JSONB NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(255) NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Like notes, tags keep pointing at an order once it is archived, so order_id
+-- does not reference the live table; the primary key indexes it
+CREATE TABLE IF NOT EXISTS order_tag (
+    order_id BIGINT NOT NULL,
+    tag VARCHAR(64) NOT NULL,
+    source VARCHAR(16) NOT NULL DEFAULT 'MANUAL',
+    rule_id BIGINT NULL REFERENCES order_tag_rule(rule_id) ON DELETE SET NULL,
+    created_by VARCHAR(255) NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, tag)
+);
+
+-- Listing the orders of a queue by tag
+CREATE INDEX IF NOT EXISTS idx_order_tag_tag ON order_tag (tag, order_id);
+
+-- When the tag rules last evaluated a submitted order; NULL until they run
+ALTER TABLE blc_order ADD COLUMN IF NOT EXISTS tag_rules_applied_at TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE blc_order_archive ADD COLUMN IF NOT EXISTS tag_rules_applied_at TIMESTAMP WITH TIME ZONE NULL;
+
+-- Finding submitted orders the rules have not evaluated yet
+CREATE INDEX IF NOT EXISTS idx_blc_order_tag_rules_pending ON blc_order (submit_date)
+    WHERE submit_date IS NOT NULL AND tag_rules_applied_at IS NULL;
@@ -0,0 +1,27 @@
+-- SKUs in flash-sale allocation mode. While active, reservations take tokens from
+-- an atomic counter in Redis instead of updating blc_inventory_level per request;
+-- the reserved quantities are applied to the levels in periodic reconciliations.
+CREATE TABLE IF NOT EXISTS inventory_flash_allocation (
+    sku_id VARCHAR(255) PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'ACTIVE',
+    seeded_quantity INTEGER NOT NULL,
+    reserved_quantity INTEGER NOT NULL DEFAULT 0,
+    started_by VARCHAR(255) NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    stopped_at TIMESTAMP WITH TIME ZONE NULL,
+    last_reconciled_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Ledger of reconciliations: the reservations applied to the inventory levels and
+-- the difference found between the counter and the stock available in the database
+CREATE TABLE IF NOT EXISTS inventory_flash_reconciliation (
+    id BIGSERIAL PRIMARY KEY,
+    sku_id VARCHAR(255) NOT NULL,
+    reserved_delta INTEGER NOT NULL,
+    tokens_before INTEGER NOT NULL,
+    available_after INTEGER NOT NULL,
+    drift INTEGER NOT NULL,
+    date_created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_flash_reconciliation_sku ON inventory_flash_reconciliation (sku_id, date_created DESC);
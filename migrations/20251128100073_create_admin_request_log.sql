@@ -0,0 +1,28 @@
+-- Admin API request log for support forensics; headers, query strings and bodies are redacted before they are stored
+CREATE TABLE IF NOT EXISTS admin_request_log (
+    request_id VARCHAR(36) PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(1024) NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    status INTEGER NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    actor_id VARCHAR(64) NOT NULL DEFAULT '',
+    actor_email VARCHAR(255) NOT NULL DEFAULT '',
+    session_id VARCHAR(64) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    correlation_id VARCHAR(64) NOT NULL DEFAULT '',
+    request_headers JSONB,
+    request_body TEXT NOT NULL DEFAULT '',
+    response_body TEXT NOT NULL DEFAULT '',
+    request_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    response_truncated BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Cursor pagination walks (occurred_at, request_id) newest first
+CREATE INDEX IF NOT EXISTS idx_admin_request_log_occurred ON admin_request_log (occurred_at DESC, request_id DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_request_log_actor ON admin_request_log (actor_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_request_log_session ON admin_request_log (session_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_request_log_path ON admin_request_log (path text_pattern_ops, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_request_log_status ON admin_request_log (status, occurred_at DESC);
@@ -0,0 +1,43 @@
+-- Customer imports from CSV or JSON files, run in the background. The cursor
+-- counts the records committed so an interrupted import resumes after them,
+-- and a running import holds a lease so another instance picks it up once the
+-- worker running it stops renewing it.
+CREATE TABLE IF NOT EXISTS customer_import_job (
+    job_id BIGSERIAL PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL,
+    file_path VARCHAR(1024) NOT NULL,
+    format VARCHAR(8) NOT NULL,
+    duplicate_strategy VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+    cursor_position BIGINT NOT NULL DEFAULT 0,
+    imported BIGINT NOT NULL DEFAULT 0,
+    updated BIGINT NOT NULL DEFAULT 0,
+    skipped BIGINT NOT NULL DEFAULT 0,
+    failed BIGINT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    lease_until TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_customer_import_job_format CHECK (format IN ('CSV', 'JSON')),
+    CONSTRAINT chk_customer_import_job_strategy CHECK (duplicate_strategy IN ('SKIP', 'UPDATE', 'REJECT')),
+    CONSTRAINT chk_customer_import_job_status CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_import_job_runnable ON customer_import_job (created_at)
+    WHERE status IN ('PENDING', 'RUNNING');
+
+-- Records of an import that could not be imported, downloadable as its error report
+CREATE TABLE IF NOT EXISTS customer_import_error (
+    error_id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES customer_import_job (job_id) ON DELETE CASCADE,
+    record_number BIGINT NOT NULL,
+    email_address VARCHAR(255) NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_import_error_job ON customer_import_error (job_id, record_number);
@@ -0,0 +1,12 @@
+-- Progress of the anonymization command run on production snapshots restored
+-- into non-production environments. last_key is the key of the last row
+-- rewritten, so an interrupted run resumes without anonymizing a row twice.
+-- Stays empty in production.
+CREATE TABLE IF NOT EXISTS anonymization_progress (
+    table_name VARCHAR(128) PRIMARY KEY,
+    last_key BIGINT NOT NULL DEFAULT 0,
+    rows_anonymized BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,36 @@
+-- Time-boxed sale events ("daily deals") putting a set of SKUs on sale between
+-- two dates. The scheduler applies the deal prices when an event starts and
+-- restores the prices recorded here when it ends.
+CREATE TABLE IF NOT EXISTS catalog_sale_event (
+    sale_event_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'SCHEDULED',
+    offer_id BIGINT NULL,
+    created_by VARCHAR(255) NULL,
+    started_at TIMESTAMP WITH TIME ZONE NULL,
+    ended_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_catalog_sale_event_window CHECK (ends_at > starts_at)
+);
+
+-- Finding the events to start and end, and the ones shown on the storefront
+CREATE INDEX IF NOT EXISTS idx_catalog_sale_event_starts ON catalog_sale_event (starts_at) WHERE status = 'SCHEDULED';
+CREATE INDEX IF NOT EXISTS idx_catalog_sale_event_ends ON catalog_sale_event (ends_at) WHERE status = 'ACTIVE';
+
+-- SKUs of a sale event with their deal price; retail_price and previous_sale_price
+-- are the SKU prices when the event started
+CREATE TABLE IF NOT EXISTS catalog_sale_event_item (
+    sale_event_id BIGINT NOT NULL REFERENCES catalog_sale_event(sale_event_id) ON DELETE CASCADE,
+    sku_id BIGINT NOT NULL REFERENCES blc_sku(sku_id) ON DELETE CASCADE,
+    sale_price NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    retail_price NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    previous_sale_price NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    applied BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (sale_event_id, sku_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_sale_event_item_sku ON catalog_sale_event_item (sku_id);
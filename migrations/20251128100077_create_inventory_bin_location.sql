@@ -0,0 +1,28 @@
+-- Storage bins of warehouses, addressed by zone, aisle, bay and level so pick
+-- lists can be ordered along a walking path. capacity is in units; 0 is unlimited.
+CREATE TABLE IF NOT EXISTS inventory_bin_location (
+    bin_id BIGSERIAL PRIMARY KEY,
+    warehouse_id VARCHAR(255) NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    zone VARCHAR(64) NOT NULL DEFAULT '',
+    aisle INTEGER NOT NULL DEFAULT 0,
+    bay INTEGER NOT NULL DEFAULT 0,
+    level INTEGER NOT NULL DEFAULT 0,
+    capacity INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_inventory_bin_location_code UNIQUE (warehouse_id, code)
+);
+
+-- SKUs stored in bins; a SKU has at most one primary bin per warehouse
+CREATE TABLE IF NOT EXISTS inventory_bin_assignment (
+    bin_id BIGINT NOT NULL REFERENCES inventory_bin_location(bin_id) ON DELETE CASCADE,
+    sku_id VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (bin_id, sku_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_bin_assignment_sku ON inventory_bin_assignment (sku_id);
@@ -0,0 +1,22 @@
+-- Files attached to orders: customer purchase orders, customs documents, photos
+-- of damaged items. The content lives in object storage under storage_key.
+-- Like notes, attachments keep pointing at an order once it is archived, so
+-- order_id does not reference the live table.
+CREATE TABLE IF NOT EXISTS order_attachment (
+    order_attachment_id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    visibility VARCHAR(16) NOT NULL DEFAULT 'INTERNAL',
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    storage_key VARCHAR(512) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    uploaded_by VARCHAR(255) NOT NULL DEFAULT '',
+    uploaded_by_customer BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_attachment_order_id ON order_attachment (order_id, created_at);
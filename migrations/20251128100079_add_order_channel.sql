@@ -0,0 +1,11 @@
+-- The sales channel an order was placed through: WEB, MOBILE_APP, MARKETPLACE
+-- or MOTO (mail or telephone order). Orders placed before channels were
+-- recorded count as web orders.
+ALTER TABLE blc_order ADD COLUMN IF NOT EXISTS channel VARCHAR(32) NOT NULL DEFAULT 'WEB';
+
+-- Archived orders are copied column for column, so the archive gets the
+-- column too
+ALTER TABLE blc_order_archive ADD COLUMN IF NOT EXISTS channel VARCHAR(32) NOT NULL DEFAULT 'WEB';
+
+-- Channel reports group submitted orders and carts by channel and date
+CREATE INDEX IF NOT EXISTS idx_blc_order_channel_created ON blc_order (channel, date_created);
@@ -0,0 +1,19 @@
+-- Storefront inbox of each customer: order updates, promotions and
+-- back-in-stock alerts delivered by the notification pipeline's in-app channel
+CREATE TABLE IF NOT EXISTS customer_notification (
+    customer_notification_id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT NOT NULL,
+    category VARCHAR(32) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    link VARCHAR(1024) NOT NULL DEFAULT '',
+    read_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_customer_notification_customer_id FOREIGN KEY (customer_id) REFERENCES blc_customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_notification_customer_id ON customer_notification (customer_id, created_at DESC);
+
+-- Unread badge counts
+CREATE INDEX IF NOT EXISTS idx_customer_notification_unread ON customer_notification (customer_id)
+    WHERE read_at IS NULL;
@@ -0,0 +1,25 @@
+-- Fulfillment options offered at checkout: carrier service, price bands and the
+-- weights and destinations each one is available to. Referenced by
+-- blc_fulfillment_group.fulfillment_option_id.
+CREATE TABLE IF NOT EXISTS blc_fulfillment_option (
+    fulfillment_option_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    fulfillment_type VARCHAR(32) NOT NULL,
+    carrier VARCHAR(64) NOT NULL DEFAULT '',
+    service_code VARCHAR(64) NOT NULL DEFAULT '',
+    transit_days_min INT NOT NULL DEFAULT 0,
+    transit_days_max INT NOT NULL DEFAULT 0,
+    price_basis VARCHAR(16) NOT NULL DEFAULT 'FLAT',
+    -- [{"from": 0, "price": 5.99}, {"from": 10, "price": 12.99}], ascending by from
+    price_bands JSONB NOT NULL DEFAULT '[]',
+    min_weight_kg NUMERIC(19, 5) NULL,
+    max_weight_kg NUMERIC(19, 5) NULL,
+    countries TEXT[] NOT NULL DEFAULT '{}',
+    regions TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_blc_fulfillment_option_active ON blc_fulfillment_option (active);
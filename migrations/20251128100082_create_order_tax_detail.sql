@@ -0,0 +1,26 @@
+-- Tax levied on each order item and on the shipping of each fulfillment group,
+-- broken down by jurisdiction as calculated when the order was submitted. The
+-- source for invoices and tax reporting. Rows keep pointing at an order once it
+-- is archived, so they do not reference the live table.
+CREATE TABLE IF NOT EXISTS order_tax_detail (
+    order_tax_detail_id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    order_item_id BIGINT NULL,
+    fulfillment_group_id BIGINT NULL,
+    jurisdiction_name VARCHAR(255) NOT NULL DEFAULT '',
+    tax_country VARCHAR(255) NOT NULL DEFAULT '',
+    tax_region VARCHAR(255) NOT NULL DEFAULT '',
+    tax_name VARCHAR(255) NOT NULL DEFAULT '',
+    rate NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    taxable_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    currency_code VARCHAR(255) NULL,
+    provider VARCHAR(64) NOT NULL DEFAULT '',
+    calculated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_order_tax_detail_line CHECK (order_item_id IS NOT NULL OR fulfillment_group_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_tax_detail_order_id ON order_tax_detail (order_id);
+
+-- Tax reports by calculation date
+CREATE INDEX IF NOT EXISTS idx_order_tax_detail_calculated_at ON order_tax_detail (calculated_at);
@@ -0,0 +1,21 @@
+-- Webhooks received from payment, carrier and other providers, stored once their
+-- signature is verified and processed in the background
+CREATE TABLE IF NOT EXISTS inbound_webhook (
+    inbound_webhook_id BIGSERIAL PRIMARY KEY,
+    provider VARCHAR(100) NOT NULL,
+    delivery_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(255) NOT NULL DEFAULT '',
+    payload BYTEA NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT uq_inbound_webhook_delivery UNIQUE (provider, delivery_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbound_webhook_due ON inbound_webhook (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_inbound_webhook_received_at ON inbound_webhook (received_at);
+CREATE INDEX IF NOT EXISTS idx_inbound_webhook_status ON inbound_webhook (status, received_at);
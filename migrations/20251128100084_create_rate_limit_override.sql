@@ -0,0 +1,15 @@
+-- Temporary raises of the storefront rate limit of a customer, partner API key
+-- or IP in a bucket, set by the admin and read by every storefront instance
+CREATE TABLE IF NOT EXISTS rate_limit_override (
+    rate_limit_override_id BIGSERIAL PRIMARY KEY,
+    subject VARCHAR(255) NOT NULL,
+    bucket VARCHAR(64) NOT NULL,
+    requests INTEGER NOT NULL CHECK (requests > 0),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_rate_limit_override_subject_bucket UNIQUE (subject, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_override_expires_at ON rate_limit_override (expires_at);
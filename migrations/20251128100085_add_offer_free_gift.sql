@@ -0,0 +1,4 @@
+-- FREE_GIFT offers add a SKU to qualifying carts at no charge and remove it
+-- when the cart stops qualifying
+ALTER TABLE blc_offer ADD COLUMN IF NOT EXISTS gift_sku_id BIGINT;
+ALTER TABLE blc_offer ADD COLUMN IF NOT EXISTS gift_quantity INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,29 @@
+-- Inbox of tasks raised by workflows needing an admin decision, such as return
+-- approvals, fraud reviews, catalog publishing and stocktake variances. Tasks
+-- are assigned to an admin user or to the members of an admin role.
+CREATE TABLE IF NOT EXISTS admin_task (
+    admin_task_id BIGSERIAL PRIMARY KEY,
+    task_type VARCHAR(64) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    entity_type VARCHAR(64) NOT NULL DEFAULT '',
+    entity_id VARCHAR(255) NOT NULL DEFAULT '',
+    data JSONB NOT NULL DEFAULT '{}',
+    assigned_role_id BIGINT,
+    assigned_user_id BIGINT,
+    status VARCHAR(16) NOT NULL DEFAULT 'OPEN',
+    claimed_by BIGINT,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    due_at TIMESTAMP WITH TIME ZONE,
+    outcome VARCHAR(64) NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    completed_by BIGINT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_task_assignee ON admin_task (status, assigned_user_id, assigned_role_id);
+CREATE INDEX IF NOT EXISTS idx_admin_task_entity ON admin_task (task_type, entity_type, entity_id) WHERE status IN ('OPEN', 'CLAIMED');
+CREATE INDEX IF NOT EXISTS idx_admin_task_due ON admin_task (due_at) WHERE status IN ('OPEN', 'CLAIMED');
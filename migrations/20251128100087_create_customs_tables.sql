@@ -0,0 +1,32 @@
+-- Customs classification of products and the duty tables and import rules of
+-- destination countries, used to estimate the duties and import fees of
+-- cross-border orders when no external landed cost service is configured
+CREATE TABLE IF NOT EXISTS product_customs (
+    product_id BIGINT PRIMARY KEY,
+    hs_code VARCHAR(10) NOT NULL,
+    origin_country VARCHAR(2) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_product_customs_product_id FOREIGN KEY (product_id) REFERENCES blc_product(product_id) ON DELETE CASCADE
+);
+
+-- The rate with the longest matching HS code prefix applies; an empty prefix is
+-- the country's default rate and an empty origin applies to goods of any origin
+CREATE TABLE IF NOT EXISTS duty_rate (
+    duty_rate_id BIGSERIAL PRIMARY KEY,
+    destination_country VARCHAR(2) NOT NULL,
+    hs_code_prefix VARCHAR(10) NOT NULL DEFAULT '',
+    origin_country VARCHAR(2) NOT NULL DEFAULT '',
+    rate NUMERIC(19, 5) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_duty_rate UNIQUE (destination_country, hs_code_prefix, origin_country)
+);
+
+CREATE TABLE IF NOT EXISTS import_rule (
+    country VARCHAR(2) PRIMARY KEY,
+    currency_code VARCHAR(3) NOT NULL,
+    de_minimis NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    import_tax_rate NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    clearance_fee NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,27 @@
+-- Duties, import tax and fees estimated for a cross-border order, with the
+-- incoterm the customer chose. Under DDP they are charged with the order and
+-- recorded in blc_order.total_duties; under DAP the customer pays them on
+-- delivery. Rows keep pointing at an order once it is archived, so they do not
+-- reference the live table.
+CREATE TABLE IF NOT EXISTS order_landed_cost (
+    order_id BIGINT PRIMARY KEY,
+    incoterm VARCHAR(3) NOT NULL,
+    ship_from_country VARCHAR(2) NOT NULL DEFAULT '',
+    destination_country VARCHAR(2) NOT NULL,
+    order_subtotal NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    shipping_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    duty NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    import_tax NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    fees NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    total NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    de_minimis_applied BOOLEAN NOT NULL DEFAULT FALSE,
+    currency_code VARCHAR(3) NOT NULL DEFAULT '',
+    provider VARCHAR(64) NOT NULL DEFAULT '',
+    lines JSONB NOT NULL DEFAULT '[]',
+    estimated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE blc_order ADD COLUMN IF NOT EXISTS total_duties NUMERIC(19, 5) NOT NULL DEFAULT 0;
+
+-- Archived orders are copied column for column, so the archive gets the column too
+ALTER TABLE blc_order_archive ADD COLUMN IF NOT EXISTS total_duties NUMERIC(19, 5) NOT NULL DEFAULT 0;
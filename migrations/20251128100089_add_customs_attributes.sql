@@ -0,0 +1,18 @@
+-- Customs descriptions and declared value rules of products, printed on the
+-- commercial invoices of cross-border shipments and sent with carrier label requests
+ALTER TABLE product_customs ADD COLUMN IF NOT EXISTS description VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE product_customs ADD COLUMN IF NOT EXISTS declared_value_basis VARCHAR(20) NOT NULL DEFAULT 'TRANSACTION';
+ALTER TABLE product_customs ADD COLUMN IF NOT EXISTS declared_unit_value NUMERIC(19, 5) NOT NULL DEFAULT 0;
+
+-- Overrides for SKUs whose variant differs from its product, e.g. one made in
+-- another country; empty columns inherit the product's attributes
+CREATE TABLE IF NOT EXISTS sku_customs (
+    sku_id BIGINT PRIMARY KEY,
+    hs_code VARCHAR(10) NOT NULL DEFAULT '',
+    origin_country VARCHAR(2) NOT NULL DEFAULT '',
+    description VARCHAR(100) NOT NULL DEFAULT '',
+    declared_value_basis VARCHAR(20) NOT NULL DEFAULT '',
+    declared_unit_value NUMERIC(19, 5) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_sku_customs_sku_id FOREIGN KEY (sku_id) REFERENCES blc_sku(sku_id) ON DELETE CASCADE
+);
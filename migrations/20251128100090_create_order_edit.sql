@@ -0,0 +1,27 @@
+-- Edits of the lines of submitted orders that have not shipped. The lines of the
+-- order are snapshotted with the prices they were sold at when the edit opens;
+-- the order changes only once the previewed delta is confirmed and committed.
+-- Like notes, edits keep pointing at an order once it is archived, so order_id
+-- does not reference the live table.
+CREATE TABLE IF NOT EXISTS order_edit (
+    edit_id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    customer_id BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL,
+    opened_by VARCHAR(255) NOT NULL,
+    currency_code VARCHAR(3) NOT NULL DEFAULT '',
+    original_totals JSONB NOT NULL,
+    lines JSONB NOT NULL DEFAULT '[]',
+    revision INTEGER NOT NULL DEFAULT 1,
+    previewed_revision INTEGER NOT NULL DEFAULT 0,
+    summary JSONB NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    closed_by VARCHAR(255) NOT NULL DEFAULT '',
+    closed_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- An order has at most one open edit
+CREATE UNIQUE INDEX IF NOT EXISTS uq_order_edit_open ON order_edit (order_id) WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS idx_order_edit_order_id ON order_edit (order_id, created_at DESC);
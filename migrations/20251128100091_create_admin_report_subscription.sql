@@ -0,0 +1,24 @@
+-- Reports emailed to recipients on a daily or weekly schedule, owned by the admin
+-- user who subscribed
+CREATE TABLE IF NOT EXISTS admin_report_subscription (
+    subscription_id BIGSERIAL PRIMARY KEY,
+    admin_user_id BIGINT NOT NULL,
+    report VARCHAR(64) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    frequency VARCHAR(16) NOT NULL,
+    weekday SMALLINT NOT NULL DEFAULT 0,
+    hour SMALLINT NOT NULL DEFAULT 0,
+    format VARCHAR(8) NOT NULL,
+    recipients JSONB NOT NULL DEFAULT '[]',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_job_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_admin_report_subscription_frequency CHECK (frequency IN ('daily', 'weekly')),
+    CONSTRAINT chk_admin_report_subscription_format CHECK (format IN ('csv', 'pdf'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_report_subscription_owner ON admin_report_subscription (admin_user_id);
+CREATE INDEX IF NOT EXISTS idx_admin_report_subscription_due ON admin_report_subscription (next_run_at) WHERE active;
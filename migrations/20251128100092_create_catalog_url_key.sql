@@ -0,0 +1,40 @@
+-- URL keys of live products and categories must be unique. Duplicates left by
+-- free-text keys keep the key on the oldest row; the others get their ID appended.
+UPDATE blc_product p
+SET url_key = p.url_key || '-' || p.product_id
+FROM (
+    SELECT product_id, ROW_NUMBER() OVER (PARTITION BY url_key ORDER BY product_id) AS rn
+    FROM blc_product
+    WHERE archived = 'N' AND url_key <> ''
+) d
+WHERE d.product_id = p.product_id AND d.rn > 1;
+
+UPDATE blc_category c
+SET url_key = c.url_key || '-' || c.category_id
+FROM (
+    SELECT category_id, ROW_NUMBER() OVER (PARTITION BY url_key ORDER BY category_id) AS rn
+    FROM blc_category
+    WHERE archived = 'N' AND url_key <> ''
+) d
+WHERE d.category_id = c.category_id AND d.rn > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_blc_product_url_key ON blc_product (url_key) WHERE archived = 'N' AND url_key <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS uq_blc_category_url_key ON blc_category (url_key) WHERE archived = 'N' AND url_key <> '';
+
+-- URL keys of products and categories on a site and/or locale, replacing their
+-- default url_key there. An empty site_id or locale matches every site or locale.
+CREATE TABLE IF NOT EXISTS catalog_url_key (
+    entity_type VARCHAR(16) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    site_id VARCHAR(64) NOT NULL DEFAULT '',
+    locale VARCHAR(16) NOT NULL DEFAULT '',
+    url_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entity_type, entity_id, site_id, locale),
+    CONSTRAINT chk_catalog_url_key_entity CHECK (entity_type IN ('product', 'category')),
+    CONSTRAINT chk_catalog_url_key_scope CHECK (site_id <> '' OR locale <> '')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_catalog_url_key ON catalog_url_key (entity_type, site_id, locale, url_key);
+CREATE INDEX IF NOT EXISTS idx_catalog_url_key_lookup ON catalog_url_key (entity_type, url_key);
@@ -0,0 +1,8 @@
+-- Site settings changed by the admin, such as the store name and the order
+-- number prefix; settings without a row keep their default
+CREATE TABLE IF NOT EXISTS site_setting (
+    setting_key VARCHAR(64) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
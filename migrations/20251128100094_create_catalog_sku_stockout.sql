@@ -0,0 +1,9 @@
+-- SKUs the inventory sync made unavailable when their available-to-promise ran
+-- out; the sync only makes available again the SKUs listed here
+CREATE TABLE IF NOT EXISTS catalog_sku_stockout (
+    sku_id BIGINT PRIMARY KEY,
+    available_to_promise INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_sku_stockout_created ON catalog_sku_stockout (created_at);
@@ -0,0 +1,32 @@
+-- Ledger entries record which stock they change: units on hand, damaged units
+-- or returned units held in quarantine
+ALTER TABLE blc_inventory_adjustment ADD COLUMN IF NOT EXISTS stock VARCHAR(16) NOT NULL DEFAULT 'ON_HAND';
+
+-- Returns received at a warehouse; each line carries the disposition staff
+-- chose for its units, quarantined lines being resolved later
+CREATE TABLE IF NOT EXISTS inventory_return_receipt (
+    id VARCHAR(36) PRIMARY KEY,
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    order_id BIGINT NULL,
+    warehouse_id VARCHAR(255) NULL,
+    received_by VARCHAR(255) NOT NULL DEFAULT '',
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS inventory_return_line (
+    id VARCHAR(36) PRIMARY KEY,
+    receipt_id VARCHAR(36) NOT NULL REFERENCES inventory_return_receipt (id) ON DELETE CASCADE,
+    sku_id VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    disposition VARCHAR(16) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    quarantined BOOLEAN NOT NULL DEFAULT FALSE,
+    resolved_by VARCHAR(255) NOT NULL DEFAULT '',
+    resolved_at TIMESTAMP WITH TIME ZONE NULL,
+    date_created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_inventory_return_line_quantity CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_return_receipt_received ON inventory_return_receipt (received_at);
+CREATE INDEX IF NOT EXISTS idx_inventory_return_line_receipt ON inventory_return_line (receipt_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_return_line_quarantine ON inventory_return_line (sku_id) WHERE disposition = 'QUARANTINE';
@@ -0,0 +1,11 @@
+-- Add-ons and bundle components keep their quantity per unit of the parent
+-- item, so they scale with it without rounding
+ALTER TABLE blc_order_item ADD COLUMN IF NOT EXISTS quantity_per_parent INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE blc_order_item_archive ADD COLUMN IF NOT EXISTS quantity_per_parent INTEGER NOT NULL DEFAULT 0;
+
+UPDATE blc_order_item child
+SET quantity_per_parent = GREATEST(child.quantity / parent.quantity, 1)
+FROM blc_order_item parent
+WHERE child.parent_order_item_id = parent.order_item_id
+  AND child.quantity_per_parent = 0
+  AND parent.quantity > 0;
@@ -17,8 +17,20 @@ const (
 	AuditActionRead   AuditAction = "READ"
 	AuditActionLogin  AuditAction = "LOGIN"
 	AuditActionLogout AuditAction = "LOGOUT"
+	AuditActionMerge  AuditAction = "MERGE"
+
+	AuditActionLoginFailed    AuditAction = "LOGIN_FAILED"
+	AuditActionSessionRevoked AuditAction = "SESSION_REVOKED"
 )
 
+// SecurityActions are the actions reported as security events
+var SecurityActions = []AuditAction{
+	AuditActionLogin,
+	AuditActionLogout,
+	AuditActionLoginFailed,
+	AuditActionSessionRevoked,
+}
+
 // AuditEntry represents an audit log entry
 type AuditEntry struct {
 	ID         string
@@ -46,6 +58,8 @@ type AuditFilter struct {
 	EntityID   *string
 	UserID     *string
 	Action     *AuditAction
+	Actions    []AuditAction // Matches any of the actions
+	IPAddress  *string
 	StartTime  *time.Time
 	EndTime    *time.Time
 	Limit      int
@@ -92,6 +106,12 @@ func (l *DefaultAuditLogger) Query(ctx context.Context, filter *AuditFilter) ([]
 		if filter.Action != nil && *filter.Action != entry.Action {
 			continue
 		}
+		if len(filter.Actions) > 0 && !containsAction(filter.Actions, entry.Action) {
+			continue
+		}
+		if filter.IPAddress != nil && (entry.IPAddress == nil || *filter.IPAddress != *entry.IPAddress) {
+			continue
+		}
 		if filter.StartTime != nil && entry.Timestamp.Before(*filter.StartTime) {
 			continue
 		}
@@ -116,6 +136,15 @@ func (l *DefaultAuditLogger) Query(ctx context.Context, filter *AuditFilter) ([]
 	return result[start:end], nil
 }
 
+func containsAction(actions []AuditAction, action AuditAction) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
 // AuditService provides high-level audit logging functionality
 type AuditService struct {
 	logger AuditLogger
@@ -201,6 +230,35 @@ func (s *AuditService) LogCustomAction(
 	return s.logger.Log(ctx, entry)
 }
 
+// LogSecurityEvent logs a sign-in related action with the client it came from
+func (s *AuditService) LogSecurityEvent(
+	ctx context.Context,
+	action AuditAction,
+	userID, username *string,
+	ipAddress, userAgent string,
+	metadata map[string]interface{},
+) error {
+	entry := &AuditEntry{
+		EntityType: "AdminSession",
+		Action:     action,
+		UserID:     userID,
+		Username:   username,
+		Metadata:   metadata,
+		Timestamp:  time.Now(),
+	}
+	if ipAddress != "" {
+		entry.IPAddress = &ipAddress
+	}
+	if userAgent != "" {
+		entry.UserAgent = &userAgent
+	}
+	if sessionID, ok := metadata["session_id"].(string); ok {
+		entry.EntityID = sessionID
+	}
+
+	return s.logger.Log(ctx, entry)
+}
+
 // GetAuditTrail retrieves audit trail for an entity
 func (s *AuditService) GetAuditTrail(
 	ctx context.Context,
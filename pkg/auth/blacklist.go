@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/cache"
+)
+
+// blacklistKeys builds the cache keys of revoked token IDs
+var blacklistKeys = cache.NewKeyBuilder("auth", "blacklist", 1)
+
+// TokenBlacklist records revoked token IDs until the tokens would have expired
+type TokenBlacklist struct {
+	cache cache.Cache
+}
+
+// NewTokenBlacklist creates a new token blacklist backed by the given cache
+func NewTokenBlacklist(cache cache.Cache) *TokenBlacklist {
+	return &TokenBlacklist{cache: cache}
+}
+
+// Revoke blacklists a token ID until its expiration time
+func (b *TokenBlacklist) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		// Already expired, nothing to blacklist
+		return nil
+	}
+	if err := b.cache.Set(ctx, blacklistKeys.Key(tokenID), []byte("1"), ttl); err != nil {
+		return fmt.Errorf("failed to blacklist token: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked checks whether a token ID has been blacklisted
+func (b *TokenBlacklist) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	revoked, err := b.cache.Exists(ctx, blacklistKeys.Key(tokenID))
+	if err != nil {
+		return false, fmt.Errorf("failed to check token blacklist: %w", err)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// ChannelKeys authenticates the integrations of external sales channels, such
+// as marketplaces, by their API key. It also authenticates the partners whose
+// storefront API quotas are keyed by their API key.
+type ChannelKeys struct {
+	keys map[[sha256.Size]byte]string // API key digest -> channel code
+}
+
+// NewChannelKeys creates channel keys from channel code -> API key
+func NewChannelKeys(keys map[string]string) *ChannelKeys {
+	k := &ChannelKeys{keys: make(map[[sha256.Size]byte]string, len(keys))}
+	for channel, key := range keys {
+		if key != "" {
+			k.keys[sha256.Sum256([]byte(key))] = channel
+		}
+	}
+	return k
+}
+
+// Authenticate returns the code of the channel holding an API key, "" when no channel does
+func (k *ChannelKeys) Authenticate(apiKey string) string {
+	if apiKey == "" {
+		return ""
+	}
+	// Compare digests in constant time so response timing does not leak key prefixes
+	digest := sha256.Sum256([]byte(apiKey))
+	channel := ""
+	for known, code := range k.keys {
+		if subtle.ConstantTimeCompare(known[:], digest[:]) == 1 {
+			channel = code
+		}
+	}
+	return channel
+}
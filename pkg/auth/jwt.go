@@ -32,15 +32,23 @@ func NewJWTService(secret string, expiration time.Duration) *JWTService {
 
 // GenerateToken generates a new JWT token
 func (s *JWTService) GenerateToken(userID, email string, roles []string) (string, error) {
+	tokenString, _, err := s.GenerateSessionToken(uuid.New().String(), userID, email, roles)
+	return tokenString, err
+}
+
+// GenerateSessionToken generates a JWT token whose ID is the given server-side
+// session ID, so the session can be revoked before the token expires
+func (s *JWTService) GenerateSessionToken(sessionID, userID, email string, roles []string) (string, time.Time, error) {
 	now := time.Now()
+	expiresAt := now.Add(s.expiration)
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
 		Roles:  roles,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ID:        uuid.New().String(),
+			ID:        sessionID,
 			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.expiration)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
@@ -48,10 +56,10 @@ func (s *JWTService) GenerateToken(userID, email string, roles []string) (string
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(s.secret)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign token: %w", err)
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
 	}
 
-	return tokenString, nil
+	return tokenString, expiresAt, nil
 }
 
 // ValidateToken validates a JWT token and returns the claims
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Password hashes imported from other platforms are stored as
+// legacy$<algorithm>$<salt position>$<encoding>$<base64 salt>$<digest> until
+// the customer next signs in, when they are replaced by a bcrypt hash.
+const legacyHashPrefix = "legacy$"
+
+// Legacy hash algorithms
+const (
+	LegacyAlgorithmSHA1   = "sha1"
+	LegacyAlgorithmSHA256 = "sha256"
+	LegacyAlgorithmSHA512 = "sha512"
+)
+
+// Where the salt goes relative to the password before hashing
+const (
+	SaltNone   = "none"
+	SaltPrefix = "prefix" // salt + password
+	SaltSuffix = "suffix" // password + salt
+	SaltBraces = "braces" // password + "{" + salt + "}", as Spring Security and Broadleaf hash
+)
+
+// Legacy digest encodings
+const (
+	EncodingHex    = "hex"
+	EncodingBase64 = "base64"
+)
+
+// LegacyHash is a salted SHA password hash exported from another platform
+type LegacyHash struct {
+	Algorithm    string
+	Salt         string
+	SaltPosition string // Defaults to SaltNone without a salt and SaltSuffix with one
+	Encoding     string // Defaults to EncodingHex
+	Digest       string
+}
+
+// EncodeLegacyHash validates a legacy hash and encodes it for storage in
+// place of a bcrypt hash
+func EncodeLegacyHash(h LegacyHash) (string, error) {
+	if h.SaltPosition == "" {
+		h.SaltPosition = SaltNone
+		if h.Salt != "" {
+			h.SaltPosition = SaltSuffix
+		}
+	}
+	if h.Encoding == "" {
+		h.Encoding = EncodingHex
+	}
+
+	newHash, ok := legacyAlgorithms[h.Algorithm]
+	if !ok {
+		return "", fmt.Errorf("unsupported password algorithm %q", h.Algorithm)
+	}
+	switch h.SaltPosition {
+	case SaltNone:
+		if h.Salt != "" {
+			return "", fmt.Errorf("salt given with salt position none")
+		}
+	case SaltPrefix, SaltSuffix, SaltBraces:
+	default:
+		return "", fmt.Errorf("unsupported salt position %q", h.SaltPosition)
+	}
+	digest, err := decodeDigest(h.Encoding, h.Digest)
+	if err != nil {
+		return "", err
+	}
+	if len(digest) != newHash().Size() {
+		return "", fmt.Errorf("%s digest must be %d bytes, got %d", h.Algorithm, newHash().Size(), len(digest))
+	}
+
+	return legacyHashPrefix + strings.Join([]string{
+		h.Algorithm,
+		h.SaltPosition,
+		h.Encoding,
+		base64.RawURLEncoding.EncodeToString([]byte(h.Salt)),
+		h.Digest,
+	}, "$"), nil
+}
+
+// IsLegacyHash reports whether a stored hash was imported from another platform
+func IsLegacyHash(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, legacyHashPrefix)
+}
+
+var legacyAlgorithms = map[string]func() hash.Hash{
+	LegacyAlgorithmSHA1:   sha1.New,
+	LegacyAlgorithmSHA256: sha256.New,
+	LegacyAlgorithmSHA512: sha512.New,
+}
+
+// verifyLegacyPassword checks a password against a stored legacy hash in constant time
+func verifyLegacyPassword(hashedPassword, password string) error {
+	parts := strings.Split(strings.TrimPrefix(hashedPassword, legacyHashPrefix), "$")
+	if len(parts) != 5 {
+		return fmt.Errorf("malformed legacy password hash")
+	}
+	algorithm, position, encoding := parts[0], parts[1], parts[2]
+
+	newHash, ok := legacyAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported password algorithm %q", algorithm)
+	}
+	salt, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return fmt.Errorf("malformed legacy password salt: %w", err)
+	}
+	expected, err := decodeDigest(encoding, parts[4])
+	if err != nil {
+		return err
+	}
+
+	var input string
+	switch position {
+	case SaltNone:
+		input = password
+	case SaltPrefix:
+		input = string(salt) + password
+	case SaltSuffix:
+		input = password + string(salt)
+	case SaltBraces:
+		input = password + "{" + string(salt) + "}"
+	default:
+		return fmt.Errorf("unsupported salt position %q", position)
+	}
+
+	h := newHash()
+	h.Write([]byte(input))
+	if subtle.ConstantTimeCompare(h.Sum(nil), expected) != 1 {
+		return fmt.Errorf("password does not match")
+	}
+	return nil
+}
+
+func decodeDigest(encoding, digest string) ([]byte, error) {
+	switch encoding {
+	case EncodingHex:
+		b, err := hex.DecodeString(strings.ToLower(digest))
+		if err != nil {
+			return nil, fmt.Errorf("password digest is not valid hex")
+		}
+		return b, nil
+	case EncodingBase64:
+		b, err := base64.StdEncoding.DecodeString(digest)
+		if err != nil {
+			return nil, fmt.Errorf("password digest is not valid base64")
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported digest encoding %q", encoding)
+	}
+}
@@ -30,11 +30,32 @@ func (s *PasswordService) HashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
-// VerifyPassword verifies if a password matches the hash
+// VerifyPassword verifies if a password matches the hash, which may be a
+// legacy hash imported from another platform
 func (s *PasswordService) VerifyPassword(hashedPassword, password string) error {
+	if IsLegacyHash(hashedPassword) {
+		return verifyLegacyPassword(hashedPassword, password)
+	}
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
 
+// NeedsRehash reports whether a verified password should be hashed again:
+// legacy hashes and bcrypt hashes of a lower cost than the service's
+func (s *PasswordService) NeedsRehash(hashedPassword string) bool {
+	if IsLegacyHash(hashedPassword) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err == nil && cost < s.cost
+}
+
+// IsBcryptHash reports whether a hash, e.g. one exported from another
+// platform, is a bcrypt hash this service can verify
+func IsBcryptHash(hashedPassword string) bool {
+	_, err := bcrypt.Cost([]byte(hashedPassword))
+	return err == nil
+}
+
 // IsPasswordValid checks if a password is valid (not hashed)
 func (s *PasswordService) IsPasswordValid(password string) error {
 	if len(password) < 8 {
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// keySegment matches the context and entity names of a key
+var keySegment = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// KeyBuilder builds the keys of one kind of cached entry as
+// context:entity:id:vN, where context is the bounded context owning the entry.
+// Bumping the version when the cached value changes shape leaves entries
+// written by older releases to expire unread.
+type KeyBuilder struct {
+	context string
+	entity  string
+	version int
+}
+
+// NewKeyBuilder creates a key builder. It panics on a malformed context or
+// entity name, as builders are declared at package level.
+func NewKeyBuilder(context, entity string, version int) KeyBuilder {
+	if !keySegment.MatchString(context) || !keySegment.MatchString(entity) {
+		panic(fmt.Sprintf("cache: invalid key builder %q:%q", context, entity))
+	}
+	if version < 1 {
+		panic(fmt.Sprintf("cache: invalid key version %d for %s:%s", version, context, entity))
+	}
+	return KeyBuilder{context: context, entity: entity, version: version}
+}
+
+// Key returns the key of the entry identified by ids; an entry without an
+// identifier, such as a singleton, passes none
+func (b KeyBuilder) Key(ids ...interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(b.context)
+	sb.WriteByte(':')
+	sb.WriteString(b.entity)
+	for _, id := range ids {
+		sb.WriteByte(':')
+		fmt.Fprint(&sb, id)
+	}
+	fmt.Fprintf(&sb, ":v%d", b.version)
+	return sb.String()
+}
+
+// KeyPrefix returns the prefix that keeps the entries of one deployment apart
+// from others sharing a Redis server, as app:environment
+func KeyPrefix(app, environment string) string {
+	return app + ":" + environment
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	gocache "github.com/patrickmn/go-cache"
@@ -63,6 +64,18 @@ func (mc *MemoryCache) Clear(ctx context.Context) error {
 	return nil
 }
 
+// ClearPrefix removes the keys starting with prefix
+func (mc *MemoryCache) ClearPrefix(ctx context.Context, prefix string) (int, error) {
+	removed := 0
+	for key := range mc.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			mc.cache.Delete(key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
 // Close closes the memory cache (no-op for memory cache)
 func (mc *MemoryCache) Close() error {
 	return nil
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cache namespaces. Entries derived from the shared database, such as
+// catalog and customer data, live in the shared namespace so an invalidation
+// made by either binary reaches the other; entries only one binary reads live
+// in that binary's namespace.
+const (
+	NamespaceShared     = "shared"
+	NamespaceAdmin      = "admin"
+	NamespaceStorefront = "storefront"
+)
+
+// ErrInvalidNamespace is returned when flushing a namespace or context that is not known
+var ErrInvalidNamespace = errors.New("invalid cache namespace")
+
+// PrefixClearer is implemented by caches that can remove the keys starting
+// with a prefix without clearing everything
+type PrefixClearer interface {
+	// ClearPrefix removes the keys starting with prefix and returns how many were removed
+	ClearPrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// NamespacedCache confines a cache to one namespace by prefixing its keys
+type NamespacedCache struct {
+	cache     Cache
+	namespace string
+}
+
+// WithNamespace returns a view of c confined to namespace. Closing the view
+// does not close c.
+func WithNamespace(c Cache, namespace string) *NamespacedCache {
+	return &NamespacedCache{cache: c, namespace: namespace}
+}
+
+func (n *NamespacedCache) key(key string) string {
+	return n.namespace + ":" + key
+}
+
+// Get retrieves a value from the namespace
+func (n *NamespacedCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return n.cache.Get(ctx, n.key(key))
+}
+
+// Set stores a value in the namespace with TTL
+func (n *NamespacedCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return n.cache.Set(ctx, n.key(key), value, ttl)
+}
+
+// Delete removes a value from the namespace
+func (n *NamespacedCache) Delete(ctx context.Context, key string) error {
+	return n.cache.Delete(ctx, n.key(key))
+}
+
+// Exists checks if a key exists in the namespace
+func (n *NamespacedCache) Exists(ctx context.Context, key string) (bool, error) {
+	return n.cache.Exists(ctx, n.key(key))
+}
+
+// Expire sets a TTL on an existing key of the namespace
+func (n *NamespacedCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return n.cache.Expire(ctx, n.key(key), ttl)
+}
+
+// Clear removes the keys of the namespace only
+func (n *NamespacedCache) Clear(ctx context.Context) error {
+	clearer, ok := n.cache.(PrefixClearer)
+	if !ok {
+		return fmt.Errorf("cache does not support clearing namespace %s", n.namespace)
+	}
+	_, err := clearer.ClearPrefix(ctx, n.key(""))
+	return err
+}
+
+// Close is a no-op; the underlying cache is closed by its owner
+func (n *NamespacedCache) Close() error {
+	return nil
+}
+
+// Health checks the underlying cache
+func (n *NamespacedCache) Health(ctx context.Context) error {
+	return n.cache.Health(ctx)
+}
+
+// Flusher removes the entries of a namespace, or of one bounded context
+// within it, on request. Only the namespaces it was created with can be
+// flushed, so keys outside the cache, such as flash-sale counters, are never
+// touched.
+type Flusher struct {
+	clearer    PrefixClearer
+	namespaces map[string]bool
+}
+
+// NewFlusher creates a flusher of the given namespaces of c. It returns nil
+// when c cannot clear by prefix.
+func NewFlusher(c Cache, namespaces ...string) *Flusher {
+	clearer, ok := c.(PrefixClearer)
+	if !ok {
+		return nil
+	}
+	f := &Flusher{clearer: clearer, namespaces: make(map[string]bool, len(namespaces))}
+	for _, namespace := range namespaces {
+		f.namespaces[namespace] = true
+	}
+	return f
+}
+
+// Namespaces returns the namespaces that can be flushed, sorted
+func (f *Flusher) Namespaces() []string {
+	namespaces := make([]string, 0, len(f.namespaces))
+	for namespace := range f.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// Flush removes the entries of namespace, limited to one bounded context when
+// boundedContext is not empty, and returns how many were removed
+func (f *Flusher) Flush(ctx context.Context, namespace, boundedContext string) (int, error) {
+	if !f.namespaces[namespace] {
+		return 0, fmt.Errorf("%w %q, expected one of %s", ErrInvalidNamespace, namespace, strings.Join(f.Namespaces(), ", "))
+	}
+	prefix := namespace + ":"
+	if boundedContext != "" {
+		if !keySegment.MatchString(boundedContext) {
+			return 0, fmt.Errorf("%w: invalid context %q", ErrInvalidNamespace, boundedContext)
+		}
+		prefix += boundedContext + ":"
+	}
+	return f.clearer.ClearPrefix(ctx, prefix)
+}
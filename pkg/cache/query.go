@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/event"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// queryMetrics is published at /debug/vars as "query_cache"
+var queryMetrics = expvar.NewMap("query_cache")
+
+// generationTTL keeps namespace generations well beyond any entry's lifetime
+const generationTTL = 24 * time.Hour
+
+// QueryCacheConfig holds the expiry settings of a query cache
+type QueryCacheConfig struct {
+	TTL            time.Duration // Maximum age of a cached result
+	RefreshAhead   time.Duration // Results younger than TTL by less than this are refreshed in the background
+	RefreshTimeout time.Duration // Timeout of a background refresh
+}
+
+// QueryCache caches query results by namespace. Results close to expiry are
+// served while a single background load refreshes them, so hot keys never
+// expire under traffic. Invalidating a namespace bumps its generation, which
+// orphans every key in it at once, on every instance sharing the cache.
+type QueryCache struct {
+	cache  Cache
+	cfg    QueryCacheConfig
+	logger *logger.Logger
+
+	mu       sync.Mutex
+	inflight map[string]*queryCall
+}
+
+type queryCall struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+type queryEntry struct {
+	Data      json.RawMessage `json:"data"`
+	RefreshAt time.Time       `json:"refresh_at"`
+}
+
+// NewQueryCache creates a query cache on top of a cache store
+func NewQueryCache(cache Cache, cfg QueryCacheConfig, log *logger.Logger) *QueryCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 5 * time.Minute
+	}
+	if cfg.RefreshAhead < 0 || cfg.RefreshAhead >= cfg.TTL {
+		cfg.RefreshAhead = cfg.TTL / 5
+	}
+	if cfg.RefreshTimeout <= 0 {
+		cfg.RefreshTimeout = 10 * time.Second
+	}
+	return &QueryCache{
+		cache:    cache,
+		cfg:      cfg,
+		logger:   log,
+		inflight: make(map[string]*queryCall),
+	}
+}
+
+// Get returns the cached result of a query, calling load on a miss. The result
+// of load must be valid JSON.
+func (q *QueryCache) Get(ctx context.Context, namespace, key string, load func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	cacheKey := q.key(ctx, namespace, key)
+
+	if cached, err := q.cache.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
+		var entry queryEntry
+		if err := json.Unmarshal(cached, &entry); err == nil {
+			queryMetrics.Add(namespace+".hits", 1)
+			if time.Now().After(entry.RefreshAt) {
+				q.refresh(cacheKey, namespace, load)
+			}
+			return entry.Data, nil
+		}
+	}
+
+	queryMetrics.Add(namespace+".misses", 1)
+	return q.load(ctx, cacheKey, namespace, load)
+}
+
+// Invalidate drops every cached result of a namespace
+func (q *QueryCache) Invalidate(ctx context.Context, namespace string) error {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 10)
+	queryMetrics.Add(namespace+".invalidations", 1)
+	return q.cache.Set(ctx, q.generationKey(namespace), []byte(generation), generationTTL)
+}
+
+// InvalidateOn invalidates a namespace whenever one of the given events is published
+func (q *QueryCache) InvalidateOn(bus event.Bus, namespace string, eventTypes ...string) error {
+	handler := func(ctx context.Context, evt event.Event) error {
+		if err := q.Invalidate(ctx, namespace); err != nil {
+			q.logger.WithError(err).WithField("namespace", namespace).Warn("failed to invalidate query cache")
+			return err
+		}
+		return nil
+	}
+	for _, eventType := range eventTypes {
+		if err := bus.Subscribe(eventType, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load runs a query once per key and process, sharing the result with concurrent callers
+func (q *QueryCache) load(ctx context.Context, cacheKey, namespace string, load func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	q.mu.Lock()
+	if call, ok := q.inflight[cacheKey]; ok {
+		q.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.data, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &queryCall{done: make(chan struct{})}
+	q.inflight[cacheKey] = call
+	q.mu.Unlock()
+
+	call.data, call.err = load(ctx)
+	if call.err == nil {
+		q.store(ctx, cacheKey, call.data)
+	} else {
+		queryMetrics.Add(namespace+".load_errors", 1)
+	}
+
+	q.mu.Lock()
+	delete(q.inflight, cacheKey)
+	q.mu.Unlock()
+	close(call.done)
+
+	return call.data, call.err
+}
+
+// refresh reloads a result in the background unless a load is already running
+func (q *QueryCache) refresh(cacheKey, namespace string, load func(ctx context.Context) ([]byte, error)) {
+	q.mu.Lock()
+	_, running := q.inflight[cacheKey]
+	q.mu.Unlock()
+	if running {
+		return
+	}
+
+	queryMetrics.Add(namespace+".refreshes", 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), q.cfg.RefreshTimeout)
+		defer cancel()
+		if _, err := q.load(ctx, cacheKey, namespace, load); err != nil {
+			q.logger.WithError(err).WithField("key", cacheKey).Warn("background query cache refresh failed")
+		}
+	}()
+}
+
+func (q *QueryCache) store(ctx context.Context, cacheKey string, data []byte) {
+	entry, err := json.Marshal(queryEntry{
+		Data:      data,
+		RefreshAt: time.Now().Add(q.cfg.TTL - q.cfg.RefreshAhead),
+	})
+	if err != nil {
+		q.logger.WithError(err).WithField("key", cacheKey).Warn("failed to encode query cache entry")
+		return
+	}
+	if err := q.cache.Set(ctx, cacheKey, entry, q.cfg.TTL); err != nil {
+		q.logger.WithError(err).WithField("key", cacheKey).Warn("failed to cache query result")
+	}
+}
+
+// key builds the cache key of a query within the namespace's current generation
+func (q *QueryCache) key(ctx context.Context, namespace, key string) string {
+	generation := "0"
+	if value, err := q.cache.Get(ctx, q.generationKey(namespace)); err == nil && len(value) > 0 {
+		generation = string(value)
+	}
+	return "query:" + namespace + ":" + generation + ":" + key
+}
+
+func (q *QueryCache) generationKey(namespace string) string {
+	return "query:" + namespace + ":generation"
+}
+
+// GetJSON returns the cached result of a query, encoding it as JSON
+func GetJSON[T any](ctx context.Context, q *QueryCache, namespace, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	data, err := q.Get(ctx, namespace, key, func(ctx context.Context) ([]byte, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	})
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
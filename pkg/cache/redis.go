@@ -3,12 +3,16 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/qhato/ecommerce/pkg/logger"
 	"github.com/redis/go-redis/v9"
 )
 
+// clearBatchSize is the number of keys scanned and unlinked at a time when clearing a prefix
+const clearBatchSize = 500
+
 // RedisCache implements Cache interface using Redis
 type RedisCache struct {
 	client *redis.Client
@@ -27,29 +31,35 @@ type RedisConfig struct {
 
 // NewRedisCache creates a new Redis cache
 func NewRedisCache(cfg RedisConfig) (*RedisCache, error) {
-	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
-
-	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: cfg.Password,
-		DB:       cfg.Database,
-		PoolSize: cfg.PoolSize,
-	})
+	rc := NewLazyRedisCache(cfg)
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := client.Ping(ctx).Err(); err != nil {
+	if err := rc.client.Ping(ctx).Err(); err != nil {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
 	logger.Info("Redis cache connected successfully")
 
+	return rc, nil
+}
+
+// NewLazyRedisCache creates a Redis cache without checking the connection, for
+// use behind a ResilientCache so startup does not fail while Redis is down
+func NewLazyRedisCache(cfg RedisConfig) *RedisCache {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.Database,
+		PoolSize: cfg.PoolSize,
+	})
+
 	return &RedisCache{
 		client: client,
 		prefix: cfg.Prefix,
-	}, nil
+	}
 }
 
 // prefixKey adds prefix to key
@@ -126,6 +136,52 @@ func (rc *RedisCache) Clear(ctx context.Context) error {
 	return nil
 }
 
+// ClearPrefix removes the keys starting with prefix, scanning and unlinking
+// them in batches so Redis is not blocked
+func (rc *RedisCache) ClearPrefix(ctx context.Context, prefix string) (int, error) {
+	pattern := rc.prefixKey(escapePattern(prefix)) + "*"
+
+	removed := 0
+	batch := make([]string, 0, clearBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := rc.client.Unlink(ctx, batch...).Result()
+		if err != nil {
+			return fmt.Errorf("redis unlink error: %w", err)
+		}
+		removed += int(n)
+		batch = batch[:0]
+		return nil
+	}
+
+	iter := rc.client.Scan(ctx, 0, pattern, clearBatchSize).Iterator()
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == clearBatchSize {
+			if err := flush(); err != nil {
+				return removed, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return removed, fmt.Errorf("redis scan error: %w", err)
+	}
+	if err := flush(); err != nil {
+		return removed, err
+	}
+
+	return removed, nil
+}
+
+// escapePattern escapes the glob characters of a SCAN MATCH pattern
+func escapePattern(s string) string {
+	return patternEscaper.Replace(s)
+}
+
+var patternEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
 // Close closes the Redis connection
 func (rc *RedisCache) Close() error {
 	if rc.client != nil {
@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/circuitbreaker"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// metrics is published at /debug/vars as "cache_resilience"
+var metrics = expvar.NewMap("cache_resilience")
+
+// maxPendingDeletes bounds the deletes remembered while the primary is down
+const maxPendingDeletes = 10000
+
+// ResilienceConfig holds the circuit breaker settings of a resilient cache
+type ResilienceConfig struct {
+	Name             string        // Used in logs and metrics
+	FailureThreshold int           // Consecutive backend failures that trip the breaker
+	Cooldown         time.Duration // Time before a request may probe the backend again
+	ProbeInterval    time.Duration // Health check interval while the breaker is not closed
+	FallbackTTL      time.Duration // Default TTL of the in-memory fallback
+}
+
+// ResilientCache serves from a primary cache (Redis) and falls back to an
+// in-memory cache when the primary fails, so requests degrade instead of erroring.
+// Deletes made during an outage are replayed on the primary once it recovers so
+// invalidations are not lost.
+type ResilientCache struct {
+	primary  Cache
+	fallback *MemoryCache
+	breaker  *circuitbreaker.Breaker
+	cfg      ResilienceConfig
+	logger   *logger.Logger
+
+	mu             sync.Mutex
+	pendingDeletes map[string]struct{}
+}
+
+// NewResilientCache wraps a primary cache with a circuit breaker and in-memory fallback
+func NewResilientCache(primary Cache, cfg ResilienceConfig, log *logger.Logger) *ResilientCache {
+	if cfg.Name == "" {
+		cfg.Name = "cache"
+	}
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = 5 * time.Second
+	}
+	if cfg.FallbackTTL <= 0 {
+		cfg.FallbackTTL = time.Minute
+	}
+
+	c := &ResilientCache{
+		primary:        primary,
+		fallback:       NewMemoryCache(cfg.FallbackTTL, cfg.FallbackTTL),
+		cfg:            cfg,
+		logger:         log.WithField("cache", cfg.Name),
+		pendingDeletes: make(map[string]struct{}),
+	}
+	c.breaker = circuitbreaker.New(circuitbreaker.Config{
+		Name:             cfg.Name,
+		FailureThreshold: cfg.FailureThreshold,
+		Cooldown:         cfg.Cooldown,
+		OnStateChange: func(name string, from, to circuitbreaker.State) {
+			metrics.Add(cfg.Name+".transitions."+string(to), 1)
+			c.logger.WithField("from", from).WithField("to", to).Warn("cache circuit breaker state changed")
+		},
+	})
+	return c
+}
+
+// Get retrieves a value, from the fallback while the primary is unavailable
+func (c *ResilientCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := c.do(func() error {
+		var err error
+		value, err = c.primary.Get(ctx, key)
+		return err
+	})
+	if err == nil || IsCacheMiss(err) {
+		return value, err
+	}
+	return c.fallback.Get(ctx, key)
+}
+
+// Set stores a value in the primary, or in the fallback while it is unavailable
+func (c *ResilientCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.do(func() error { return c.primary.Set(ctx, key, value, ttl) }); err != nil {
+		return c.fallback.Set(ctx, key, value, c.fallbackTTL(ttl))
+	}
+	return nil
+}
+
+// Delete removes a value from both caches, remembering it for the primary when it is unavailable
+func (c *ResilientCache) Delete(ctx context.Context, key string) error {
+	_ = c.fallback.Delete(ctx, key)
+	if err := c.do(func() error { return c.primary.Delete(ctx, key) }); err != nil {
+		c.rememberDelete(key)
+	}
+	return nil
+}
+
+// Exists checks if a key exists
+func (c *ResilientCache) Exists(ctx context.Context, key string) (bool, error) {
+	var exists bool
+	err := c.do(func() error {
+		var err error
+		exists, err = c.primary.Exists(ctx, key)
+		return err
+	})
+	if err == nil {
+		return exists, nil
+	}
+	return c.fallback.Exists(ctx, key)
+}
+
+// Expire sets a TTL on an existing key
+func (c *ResilientCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if err := c.do(func() error { return c.primary.Expire(ctx, key, ttl) }); err != nil {
+		return c.fallback.Expire(ctx, key, c.fallbackTTL(ttl))
+	}
+	return nil
+}
+
+// Clear removes all keys from both caches
+func (c *ResilientCache) Clear(ctx context.Context) error {
+	_ = c.fallback.Clear(ctx)
+	return c.primary.Clear(ctx)
+}
+
+// ClearPrefix removes the keys starting with prefix from both caches and
+// returns how many the primary removed
+func (c *ResilientCache) ClearPrefix(ctx context.Context, prefix string) (int, error) {
+	_, _ = c.fallback.ClearPrefix(ctx, prefix)
+	clearer, ok := c.primary.(PrefixClearer)
+	if !ok {
+		return 0, fmt.Errorf("cache %s does not support clearing by prefix", c.cfg.Name)
+	}
+	var removed int
+	err := c.do(func() error {
+		var err error
+		removed, err = clearer.ClearPrefix(ctx, prefix)
+		return err
+	})
+	return removed, err
+}
+
+// Close closes the primary cache
+func (c *ResilientCache) Close() error {
+	return c.primary.Close()
+}
+
+// Health reports the primary's health; the cache keeps serving from the fallback either way
+func (c *ResilientCache) Health(ctx context.Context) error {
+	return c.primary.Health(ctx)
+}
+
+// State returns the circuit breaker state
+func (c *ResilientCache) State() circuitbreaker.State {
+	return c.breaker.State()
+}
+
+// StartRecoveryProbe checks the primary's health while the breaker is not
+// closed, closing it as soon as the primary answers. It stops when ctx is cancelled.
+func (c *ResilientCache) StartRecoveryProbe(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.cfg.ProbeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if c.breaker.State() == circuitbreaker.StateClosed {
+					continue
+				}
+				if err := c.primary.Health(ctx); err != nil {
+					metrics.Add(c.cfg.Name+".probe_failures", 1)
+					continue
+				}
+				c.breaker.Success()
+				c.replayDeletes(ctx)
+			}
+		}
+	}()
+}
+
+// do runs an operation on the primary through the breaker. Cache misses are
+// healthy answers; any other error counts as a backend failure.
+func (c *ResilientCache) do(op func() error) error {
+	if !c.breaker.Allow() {
+		metrics.Add(c.cfg.Name+".fallback_ops", 1)
+		return circuitbreaker.ErrOpen
+	}
+
+	err := op()
+	if err != nil && !IsCacheMiss(err) {
+		c.breaker.Failure()
+		metrics.Add(c.cfg.Name+".primary_errors", 1)
+		metrics.Add(c.cfg.Name+".fallback_ops", 1)
+		c.logger.WithError(err).Debug("cache backend failed, using in-memory fallback")
+		return err
+	}
+
+	recovered := c.breaker.State() != circuitbreaker.StateClosed
+	c.breaker.Success()
+	if recovered {
+		c.replayDeletes(context.Background())
+	}
+	return err
+}
+
+func (c *ResilientCache) rememberDelete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pendingDeletes) < maxPendingDeletes {
+		c.pendingDeletes[key] = struct{}{}
+	}
+}
+
+// replayDeletes applies deletes missed during an outage so stale entries do not
+// outlive their invalidation
+func (c *ResilientCache) replayDeletes(ctx context.Context) {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.pendingDeletes))
+	for key := range c.pendingDeletes {
+		keys = append(keys, key)
+	}
+	c.pendingDeletes = make(map[string]struct{})
+	c.mu.Unlock()
+
+	for _, key := range keys {
+		if err := c.primary.Delete(ctx, key); err != nil {
+			c.rememberDelete(key)
+		}
+	}
+	if len(keys) > 0 {
+		c.logger.WithField("keys", len(keys)).Info("replayed cache deletes after recovery")
+	}
+}
+
+func (c *ResilientCache) fallbackTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.cfg.FallbackTTL {
+		return c.cfg.FallbackTTL
+	}
+	return ttl
+}
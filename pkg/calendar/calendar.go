@@ -0,0 +1,175 @@
+// Package calendar counts business days: the working days of a site or
+// warehouse in its time zone, less its holidays. Ship-by deadlines, delivery
+// estimates and offers scheduled in business days are computed with it.
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the layout of holiday dates
+const DateLayout = "2006-01-02"
+
+// Calendar holds the days a site or warehouse works on, in its time zone
+type Calendar struct {
+	Location   *time.Location
+	Workdays   map[time.Weekday]bool
+	Holidays   map[string]string // YYYY-MM-DD -> name
+	CutoffHour int               // Work started at or after this hour counts from the next business day; 0 has no cutoff
+}
+
+// New creates a calendar working on workdays, without holidays. Without any
+// workday every day is a business day.
+func New(loc *time.Location, workdays []time.Weekday, cutoffHour int) *Calendar {
+	c := &Calendar{
+		Location:   loc,
+		Workdays:   make(map[time.Weekday]bool, len(workdays)),
+		Holidays:   make(map[string]string),
+		CutoffHour: cutoffHour,
+	}
+	for _, day := range workdays {
+		c.Workdays[day] = true
+	}
+	return c
+}
+
+// AddHoliday marks the date of day (as written, whatever its zone) as a holiday
+func (c *Calendar) AddHoliday(day time.Time, name string) {
+	if c.Holidays == nil {
+		c.Holidays = make(map[string]string)
+	}
+	c.Holidays[day.Format(DateLayout)] = name
+}
+
+// Holiday returns the name of the holiday on the date of t, if it is one
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	name, ok := c.Holidays[t.In(c.location()).Format(DateLayout)]
+	return name, ok
+}
+
+// IsBusinessDay reports whether the date of t is worked on
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	t = t.In(c.location())
+	if len(c.Workdays) > 0 && !c.Workdays[t.Weekday()] {
+		return false
+	}
+	_, holiday := c.Holidays[t.Format(DateLayout)]
+	return !holiday
+}
+
+// StartDay returns the midnight of the business day work started at start
+// counts from: its own date when that is a business day and start is before
+// the cutoff, otherwise the next business day.
+func (c *Calendar) StartDay(start time.Time) time.Time {
+	loc := c.location()
+	start = start.In(loc)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	if !c.IsBusinessDay(day) || (c.CutoffHour > 0 && start.Hour() >= c.CutoffHour) {
+		day = c.NextBusinessDay(day)
+	}
+	return day
+}
+
+// AddBusinessDays returns the midnight of the days-th business day after the
+// date of day; with 0 days it is the date of day itself.
+func (c *Calendar) AddBusinessDays(day time.Time, days int) time.Time {
+	loc := c.location()
+	day = day.In(loc)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	for i := 0; i < days; i++ {
+		day = c.NextBusinessDay(day)
+	}
+	return day
+}
+
+// Deadline returns when work started at start and due within days business
+// days must be done: the end of the days-th business day after its start
+// day. With 0 days it is due by the end of the start day.
+func (c *Calendar) Deadline(start time.Time, days int) time.Time {
+	return c.AddBusinessDays(c.StartDay(start), days).AddDate(0, 0, 1)
+}
+
+// Opening returns start when its date is a business day, otherwise the
+// midnight of the next business day. Unlike StartDay it ignores the cutoff.
+func (c *Calendar) Opening(start time.Time) time.Time {
+	if c.IsBusinessDay(start) {
+		return start
+	}
+	return c.NextBusinessDay(start)
+}
+
+// NextBusinessDay returns the midnight of the first business day after the
+// date of day. A calendar with every day a holiday stops after a year rather
+// than looping forever.
+func (c *Calendar) NextBusinessDay(day time.Time) time.Time {
+	loc := c.location()
+	day = day.In(loc)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	for i := 0; i < 366; i++ {
+		day = day.AddDate(0, 0, 1)
+		if c.IsBusinessDay(day) {
+			return day
+		}
+	}
+	return day
+}
+
+func (c *Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// Resolver returns the calendar of a warehouse or site. Empty IDs skip that
+// level; with both empty it is the default calendar. Calendars may be shared
+// between callers, which must not change them.
+type Resolver interface {
+	Calendar(ctx context.Context, siteID, warehouseID string) (*Calendar, error)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"SUN": time.Sunday, "MON": time.Monday, "TUE": time.Tuesday, "WED": time.Wednesday,
+	"THU": time.Thursday, "FRI": time.Friday, "SAT": time.Saturday,
+}
+
+// ParseWeekday reads a day as MON, TUE, ... or its full English name
+func ParseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if len(name) > 3 {
+		for _, day := range weekdayNames {
+			if strings.ToUpper(day.String()) == name {
+				return day, nil
+			}
+		}
+	}
+	if day, ok := weekdayNames[name]; ok {
+		return day, nil
+	}
+	return 0, fmt.Errorf("invalid weekday %q (must be MON, TUE, WED, THU, FRI, SAT or SUN)", name)
+}
+
+// ParseWeekdays reads a list of days, dropping repeats
+func ParseWeekdays(names []string) ([]time.Weekday, error) {
+	days := make([]time.Weekday, 0, len(names))
+	seen := make(map[time.Weekday]bool, len(names))
+	for _, name := range names {
+		day, err := ParseWeekday(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+// WeekdayName returns the short name of a day, e.g. MON
+func WeekdayName(day time.Weekday) string {
+	return strings.ToUpper(day.String()[:3])
+}
@@ -0,0 +1,263 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxEventDays caps the dates a single multi-day event expands to
+const maxEventDays = 31
+
+// Holiday is a date read from an iCalendar feed
+type Holiday struct {
+	Date time.Time // Midnight UTC of the date
+	Name string
+}
+
+// ICalResult holds the holidays read from an iCalendar feed and the events
+// that could not be read as holidays
+type ICalResult struct {
+	Holidays []Holiday
+	Skipped  []string // Summaries of events skipped, with the reason
+}
+
+// icalEvent holds the properties of a VEVENT that matter to holidays
+type icalEvent struct {
+	summary  string
+	start    string
+	startAll bool // DTSTART is a whole day (a DATE without a time)
+	end      string
+	rrule    string
+	status   string
+}
+
+// ParseICal reads the all-day and timed events of an iCalendar (RFC 5545)
+// feed as holidays on their dates. Multi-day all-day events cover each of
+// their dates. Yearly recurring events are expanded from from to until;
+// other recurrences are skipped, since holiday feeds list moving holidays
+// one date at a time.
+func ParseICal(r io.Reader, from, until time.Time) (*ICalResult, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ICalResult{Holidays: make([]Holiday, 0)}
+	var event *icalEvent
+	for _, line := range lines {
+		name, value := splitICalLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event = &icalEvent{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event != nil {
+				holidays, err := event.holidays(from, until)
+				if err != nil {
+					result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", event.summary, err))
+				} else {
+					result.Holidays = append(result.Holidays, holidays...)
+				}
+			}
+			event = nil
+		case event == nil:
+		case name == "SUMMARY":
+			event.summary = unescapeICalText(value)
+		case name == "DTSTART":
+			event.start = value
+			event.startAll = !strings.Contains(value, "T")
+		case name == "DTEND":
+			event.end = value
+		case name == "RRULE":
+			event.rrule = value
+		case name == "STATUS":
+			event.status = strings.ToUpper(value)
+		}
+	}
+	return result, nil
+}
+
+// holidays returns the dates the event covers
+func (e *icalEvent) holidays(from, until time.Time) ([]Holiday, error) {
+	if e.status == "CANCELLED" {
+		return nil, fmt.Errorf("cancelled")
+	}
+	start, err := parseICalDate(e.start)
+	if err != nil {
+		return nil, err
+	}
+	days := 1
+	if e.startAll && e.end != "" {
+		end, err := parseICalDate(e.end)
+		if err != nil {
+			return nil, err
+		}
+		// DTEND of an all-day event is exclusive
+		days = int(end.Sub(start).Hours() / 24)
+		if days < 1 {
+			days = 1
+		}
+		if days > maxEventDays {
+			return nil, fmt.Errorf("spans more than %d days", maxEventDays)
+		}
+	}
+
+	name := strings.TrimSpace(e.summary)
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	starts := []time.Time{start}
+	if e.rrule != "" {
+		if starts, err = expandYearly(start, e.rrule, from, until); err != nil {
+			return nil, err
+		}
+	}
+
+	holidays := make([]Holiday, 0, len(starts)*days)
+	for _, s := range starts {
+		for i := 0; i < days; i++ {
+			holidays = append(holidays, Holiday{Date: s.AddDate(0, 0, i), Name: name})
+		}
+	}
+	return holidays, nil
+}
+
+// expandYearly lists the dates a FREQ=YEARLY rule repeats start on within
+// from and until, honoring INTERVAL, COUNT and UNTIL
+func expandYearly(start time.Time, rrule string, from, until time.Time) ([]time.Time, error) {
+	interval, count := 1, 0
+	end := until
+	for _, part := range strings.Split(rrule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			if !strings.EqualFold(value, "YEARLY") {
+				return nil, fmt.Errorf("recurs %s, only yearly recurrences are imported", strings.ToLower(value))
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence interval %q", value)
+			}
+			interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence count %q", value)
+			}
+			count = n
+		case "UNTIL":
+			u, err := parseICalDate(value)
+			if err != nil {
+				return nil, err
+			}
+			if u.Before(end) {
+				end = u
+			}
+		case "BYMONTH", "BYMONTHDAY", "WKST":
+			// Repeat the date of DTSTART, which these rules only restate
+		default:
+			return nil, fmt.Errorf("recurrence rule %s is not supported", strings.ToUpper(key))
+		}
+	}
+
+	dates := make([]time.Time, 0)
+	for i := 0; ; i++ {
+		if count > 0 && i >= count {
+			break
+		}
+		date := start.AddDate(i*interval, 0, 0)
+		if date.After(end) {
+			break
+		}
+		// Feb 29 only recurs on leap years
+		if date.Day() != start.Day() {
+			continue
+		}
+		if !date.Before(from) {
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
+
+// parseICalDate reads the date of a DATE (YYYYMMDD) or DATE-TIME value as
+// written, in the zone it was written in
+func parseICalDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return date, nil
+}
+
+// unfoldICal reads the content lines of a feed, joining the folded ones
+func unfoldICal(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lines := make([]string, 0)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read iCalendar feed: %w", err)
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar feed: it must start with BEGIN:VCALENDAR")
+	}
+	return lines, nil
+}
+
+// splitICalLine splits a content line into its upper-cased name, without
+// parameters, and its value. Colons in quoted parameter values do not end the name.
+func splitICalLine(line string) (name, value string) {
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ':' && !quoted:
+			name, _, _ = strings.Cut(line[:i], ";")
+			return strings.ToUpper(name), line[i+1:]
+		}
+	}
+	return strings.ToUpper(line), ""
+}
+
+// unescapeICalText reads the escaped commas, semicolons, backslashes and newlines of a TEXT value
+func unescapeICalText(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune(' ')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
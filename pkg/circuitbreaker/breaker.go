@@ -0,0 +1,140 @@
+// Package circuitbreaker stops calling a failing dependency after repeated
+// failures and probes it again after a cooldown.
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned when the breaker rejects a call
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit breaker
+type State string
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = "closed"
+	// StateOpen rejects calls until the cooldown elapses
+	StateOpen State = "open"
+	// StateHalfOpen lets a single probe call through
+	StateHalfOpen State = "half_open"
+)
+
+// Config holds circuit breaker settings
+type Config struct {
+	Name             string
+	FailureThreshold int           // Consecutive failures that open the breaker
+	Cooldown         time.Duration // Time spent open before a probe is allowed
+	// OnStateChange is called after every transition, e.g. to log or record
+	// metrics; it runs under the breaker lock and must not call the breaker
+	OnStateChange func(name string, from, to State)
+}
+
+// Breaker is a consecutive-failure circuit breaker, safe for concurrent use
+type Breaker struct {
+	cfg      Config
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// New creates a closed circuit breaker
+func New(cfg Config) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	return &Breaker{cfg: cfg, state: StateClosed}
+}
+
+// Name returns the breaker name
+func (b *Breaker) Name() string {
+	return b.cfg.Name
+}
+
+// State returns the current state, moving an expired open breaker to half-open
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh()
+	return b.state
+}
+
+// Allow reports whether a call may proceed. Every allowed call must be followed
+// by Success or Failure.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh()
+
+	switch b.state {
+	case StateOpen:
+		return false
+	case StateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// Success records a successful call and closes the breaker
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+	b.transition(StateClosed)
+}
+
+// Failure records a failed call, opening the breaker when the threshold is
+// reached or when a half-open probe fails
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.probing = false
+		b.openedAt = time.Now()
+		b.transition(StateOpen)
+	}
+}
+
+// Execute runs fn when the breaker allows it and records the outcome
+func (b *Breaker) Execute(fn func() error) error {
+	if !b.Allow() {
+		return ErrOpen
+	}
+	if err := fn(); err != nil {
+		b.Failure()
+		return err
+	}
+	b.Success()
+	return nil
+}
+
+// refresh moves an open breaker to half-open once the cooldown elapsed
+func (b *Breaker) refresh() {
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cfg.Cooldown {
+		b.transition(StateHalfOpen)
+	}
+}
+
+func (b *Breaker) transition(to State) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(b.cfg.Name, from, to)
+	}
+}
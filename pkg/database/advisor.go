@@ -0,0 +1,364 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+const (
+	// advisorQueueSize bounds the queries waiting to be explained; more are retried on a later run
+	advisorQueueSize = 256
+	// advisorFlushInterval is how often scan counts are written to db_index_suggestion
+	advisorFlushInterval = 30 * time.Second
+	// explainTimeout bounds a single EXPLAIN
+	explainTimeout = 5 * time.Second
+)
+
+// filterColumn matches the columns compared in a plan filter such as
+// "((customer_id = $1) AND ((order_status)::text = 'SUBMITTED'::text))"
+var filterColumn = regexp.MustCompile(`(?:^|[\s(])([a-z_][a-z0-9_]*)\)?(?:::[a-z_]+(?: [a-z_]+)*)?\s*(?:=|<>|<=|>=|<|>|~~|IS )`)
+
+// IndexSuggestion is a sequential scan seen on a watched table, grouped by the
+// columns it filtered on
+type IndexSuggestion struct {
+	Table         string    `json:"table"`
+	Columns       []string  `json:"columns"`             // Empty for scans without a filter, e.g. an unbounded listing
+	Statement     string    `json:"statement,omitempty"` // Suggested index; empty when there is nothing to index
+	Filter        string    `json:"filter,omitempty"`    // Last filter seen, as printed by EXPLAIN
+	EstimatedRows float64   `json:"estimated_rows"`
+	Queries       []string  `json:"queries"` // Repository methods, e.g. "order.PostgresOrderRepository.FindAll"
+	Occurrences   int64     `json:"occurrences"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+}
+
+// IndexAdvisor explains every distinct repository query once and records the
+// sequential scans it plans on watched tables. EXPLAIN runs with sequential
+// scans disabled, so a scan still planned means no usable index exists rather
+// than the development tables being small. Suggestions are shared through the
+// db_index_suggestion table so every process feeds the same report.
+//
+// It is a development aid: plans are taken from the queries as they run,
+// outside the request path.
+type IndexAdvisor struct {
+	pool   *pgxpool.Pool
+	tables map[string]bool
+
+	pending   chan *queryTrace
+	stop      chan struct{}
+	explained sync.Map // query fingerprint -> []string of suggestion keys, nil until explained
+
+	mu     sync.Mutex
+	staged map[string]*IndexSuggestion // Occurrences since the last flush
+}
+
+func newIndexAdvisor(tables []string) *IndexAdvisor {
+	watched := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		watched[strings.ToLower(table)] = true
+	}
+	return &IndexAdvisor{
+		tables:  watched,
+		pending: make(chan *queryTrace, advisorQueueSize),
+		stop:    make(chan struct{}),
+		staged:  make(map[string]*IndexSuggestion),
+	}
+}
+
+// start runs the worker explaining queued queries and flushing counts
+func (a *IndexAdvisor) start(pool *pgxpool.Pool) {
+	a.pool = pool
+	go func() {
+		ticker := time.NewTicker(advisorFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case trace := <-a.pending:
+				a.explain(trace)
+			case <-ticker.C:
+				a.flush()
+			case <-a.stop:
+				a.flush()
+				return
+			}
+		}
+	}()
+}
+
+// close stops the worker after a last flush
+func (a *IndexAdvisor) close() {
+	close(a.stop)
+}
+
+// observe queues a repository query for EXPLAIN the first time it is seen and
+// counts the scans of the ones already explained
+func (a *IndexAdvisor) observe(trace *queryTrace) {
+	if trace.caller.Method == "" || !explainable(trace.sql) {
+		return
+	}
+	fingerprint := trace.caller.Repository + "." + trace.caller.Method + "\x00" + trace.sql
+
+	if keys, loaded := a.explained.LoadOrStore(fingerprint, []string(nil)); loaded {
+		if keys := keys.([]string); len(keys) > 0 {
+			a.count(keys)
+		}
+		return
+	}
+	select {
+	case a.pending <- trace:
+	default:
+		a.explained.Delete(fingerprint) // Queue full, explain it on a later run
+	}
+}
+
+func (a *IndexAdvisor) count(keys []string) {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, key := range keys {
+		if staged, ok := a.staged[key]; ok {
+			staged.Occurrences++
+			staged.LastSeen = now
+		}
+	}
+}
+
+// explain plans the query with its original arguments and stages a suggestion
+// per sequential scan on a watched table
+func (a *IndexAdvisor) explain(trace *queryTrace) {
+	fingerprint := trace.caller.Repository + "." + trace.caller.Method + "\x00" + trace.sql
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	plan, err := a.plan(ctx, trace)
+	if err != nil {
+		// Queries relying on their transaction's state cannot be planned alone
+		logger.WithError(err).WithFields(logger.Fields{
+			"repository": trace.caller.Repository,
+			"method":     trace.caller.Method,
+		}).Debug("failed to explain query")
+		return
+	}
+
+	query := trace.caller.Repository + "." + trace.caller.Method
+	now := time.Now()
+	var keys []string
+	a.mu.Lock()
+	for _, scan := range plan.seqScans(a.tables) {
+		columns := scan.filterColumns()
+		key := scan.Relation + "(" + strings.Join(columns, ",") + ")"
+		staged, ok := a.staged[key]
+		if !ok {
+			staged = &IndexSuggestion{
+				Table:     scan.Relation,
+				Columns:   columns,
+				Statement: indexStatement(scan.Relation, columns),
+				FirstSeen: now,
+			}
+			a.staged[key] = staged
+		}
+		staged.Filter = scan.Filter
+		if scan.PlanRows > staged.EstimatedRows {
+			staged.EstimatedRows = scan.PlanRows
+		}
+		if !containsString(staged.Queries, query) {
+			staged.Queries = append(staged.Queries, query)
+		}
+		staged.Occurrences++
+		staged.LastSeen = now
+		keys = append(keys, key)
+	}
+	a.mu.Unlock()
+
+	a.explained.Store(fingerprint, keys)
+}
+
+func (a *IndexAdvisor) plan(ctx context.Context, trace *queryTrace) (*planNode, error) {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SET LOCAL enable_seqscan = off"); err != nil {
+		return nil, err
+	}
+	var raw []byte
+	if err := tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+trace.sql, trace.args...).Scan(&raw); err != nil {
+		return nil, err
+	}
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return nil, err
+	}
+	if len(plans) == 0 {
+		return &planNode{}, nil
+	}
+	return &plans[0].Plan, nil
+}
+
+// flush adds the staged scans to db_index_suggestion
+func (a *IndexAdvisor) flush() {
+	a.mu.Lock()
+	staged := a.staged
+	a.staged = make(map[string]*IndexSuggestion, len(staged))
+	for key, suggestion := range staged {
+		// Keep the metadata so later executions keep counting
+		a.staged[key] = &IndexSuggestion{
+			Table:         suggestion.Table,
+			Columns:       suggestion.Columns,
+			Statement:     suggestion.Statement,
+			Filter:        suggestion.Filter,
+			EstimatedRows: suggestion.EstimatedRows,
+			Queries:       suggestion.Queries,
+			FirstSeen:     suggestion.LastSeen,
+		}
+	}
+	a.mu.Unlock()
+
+	query := `
+		INSERT INTO db_index_suggestion (table_name, columns, statement, filter, estimated_rows, queries, occurrences, first_seen, last_seen)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (table_name, columns) DO UPDATE SET
+			filter = EXCLUDED.filter,
+			estimated_rows = GREATEST(db_index_suggestion.estimated_rows, EXCLUDED.estimated_rows),
+			queries = ARRAY(SELECT DISTINCT unnest(db_index_suggestion.queries || EXCLUDED.queries) ORDER BY 1),
+			occurrences = db_index_suggestion.occurrences + EXCLUDED.occurrences,
+			last_seen = EXCLUDED.last_seen`
+
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+	batch := &pgx.Batch{}
+	for _, s := range staged {
+		if s.Occurrences == 0 {
+			continue
+		}
+		batch.Queue(query, s.Table, strings.Join(s.Columns, ","), s.Statement, s.Filter, s.EstimatedRows, s.Queries, s.Occurrences, s.FirstSeen, s.LastSeen)
+	}
+	if batch.Len() == 0 {
+		return
+	}
+	if err := a.pool.SendBatch(ctx, batch).Close(); err != nil {
+		logger.WithError(err).Warn("failed to save index suggestions")
+	}
+}
+
+// Suggestions returns the recorded scans, most frequent first
+func (a *IndexAdvisor) Suggestions(ctx context.Context) ([]*IndexSuggestion, error) {
+	a.flush()
+
+	rows, err := a.pool.Query(ctx, `
+		SELECT table_name, columns, statement, filter, estimated_rows, queries, occurrences, first_seen, last_seen
+		FROM db_index_suggestion
+		ORDER BY occurrences DESC, table_name, columns`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := make([]*IndexSuggestion, 0)
+	for rows.Next() {
+		s := &IndexSuggestion{}
+		var columns string
+		if err := rows.Scan(&s.Table, &columns, &s.Statement, &s.Filter, &s.EstimatedRows, &s.Queries, &s.Occurrences, &s.FirstSeen, &s.LastSeen); err != nil {
+			return nil, err
+		}
+		s.Columns = make([]string, 0)
+		if columns != "" {
+			s.Columns = strings.Split(columns, ",")
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, rows.Err()
+}
+
+// Reset forgets every suggestion so queries are explained again, e.g. after
+// adding the suggested indexes
+func (a *IndexAdvisor) Reset(ctx context.Context) error {
+	a.mu.Lock()
+	a.staged = make(map[string]*IndexSuggestion)
+	a.mu.Unlock()
+	a.explained.Range(func(key, _ any) bool {
+		a.explained.Delete(key)
+		return true
+	})
+
+	_, err := a.pool.Exec(ctx, `DELETE FROM db_index_suggestion`)
+	return err
+}
+
+// planNode is a node of an EXPLAIN (FORMAT JSON) plan
+type planNode struct {
+	NodeType string     `json:"Node Type"`
+	Relation string     `json:"Relation Name"`
+	PlanRows float64    `json:"Plan Rows"`
+	Filter   string     `json:"Filter"`
+	Plans    []planNode `json:"Plans"`
+}
+
+// seqScans returns the sequential scans on the tables, depth first
+func (n *planNode) seqScans(tables map[string]bool) []*planNode {
+	var scans []*planNode
+	if n.NodeType == "Seq Scan" && tables[n.Relation] {
+		scans = append(scans, n)
+	}
+	for i := range n.Plans {
+		scans = append(scans, n.Plans[i].seqScans(tables)...)
+	}
+	return scans
+}
+
+// filterColumns returns the sorted, distinct columns compared in the filter
+func (n *planNode) filterColumns() []string {
+	seen := make(map[string]bool)
+	columns := make([]string, 0)
+	for _, match := range filterColumn.FindAllStringSubmatch(n.Filter, -1) {
+		if column := match[1]; !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// explainable reports whether a statement reads rows a filter could narrow
+func explainable(sql string) bool {
+	words := strings.Fields(sql)
+	if len(words) == 0 {
+		return false
+	}
+	switch strings.ToUpper(words[0]) {
+	case "SELECT", "WITH", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
+
+func indexStatement(table string, columns []string) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	return "CREATE INDEX IF NOT EXISTS idx_" + table + "_" + strings.Join(columns, "_") +
+		" ON " + table + " (" + strings.Join(columns, ", ") + ");"
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolationState is the SQLSTATE of a statement that breaks a unique constraint
+const uniqueViolationState = "23505"
+
+// IsUniqueViolation reports whether err was caused by a row breaking the unique
+// constraint or index named constraint; an empty name matches any of them
+func IsUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolationState {
+		return false
+	}
+	return constraint == "" || pgErr.ConstraintName == constraint
+}
@@ -6,13 +6,16 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/qhato/ecommerce/pkg/logger"
 )
 
 // DB wraps the pgxpool.Pool
 type DB struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	advisor *IndexAdvisor
 }
 
 // Config holds database configuration
@@ -27,6 +30,21 @@ type Config struct {
 	MaxIdleConns   int
 	MaxLifetime    time.Duration
 	MaxIdleTime    time.Duration
+
+	// QueryTimeout is the deadline of each query, on top of that of its request,
+	// so a runaway query does not hold a connection for the whole request; 0
+	// disables it. It is overridden per context with WithQueryTimeout.
+	QueryTimeout time.Duration
+
+	// SlowQueryThreshold logs queries running at least this long; 0 disables it.
+	// Query latencies are recorded per repository either way.
+	SlowQueryThreshold time.Duration
+
+	// ExplainQueries runs EXPLAIN once on each distinct repository query and
+	// records sequential scans on ExplainTables as index suggestions. Meant
+	// for development databases.
+	ExplainQueries bool
+	ExplainTables  []string
 }
 
 // New creates a new database connection pool
@@ -52,6 +70,24 @@ func New(ctx context.Context, cfg Config) (*DB, error) {
 	// Set connection timeout
 	poolConfig.ConnConfig.ConnectTimeout = 10 * time.Second
 
+	// Queries cancelled by their context are cancelled on the server as well, so
+	// they stop running and the connection returns to the pool
+	poolConfig.ConnConfig.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{
+			Conn:               pgConn,
+			CancelRequestDelay: cancelRequestDelay,
+			DeadlineDelay:      cancelDeadlineDelay,
+		}
+	}
+
+	// Instrument queries and batches and bound them by the query timeout. pgx
+	// traces batches with the same tracer as it implements pgx.BatchTracer.
+	tracer := newQueryTracer(cfg.SlowQueryThreshold, cfg.QueryTimeout)
+	if cfg.ExplainQueries {
+		tracer.advisor = newIndexAdvisor(cfg.ExplainTables)
+	}
+	poolConfig.ConnConfig.Tracer = tracer
+
 	// Create pool
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -64,13 +100,21 @@ func New(ctx context.Context, cfg Config) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if tracer.advisor != nil {
+		tracer.advisor.start(pool)
+		logger.WithField("tables", cfg.ExplainTables).Info("Query EXPLAIN index advisory enabled")
+	}
+
 	logger.Info("Database connection pool created successfully")
 
-	return &DB{pool: pool}, nil
+	return &DB{pool: pool, advisor: tracer.advisor}, nil
 }
 
 // Close closes the database connection pool
 func (db *DB) Close() {
+	if db.advisor != nil {
+		db.advisor.close()
+	}
 	if db.pool != nil {
 		db.pool.Close()
 		logger.Info("Database connection pool closed")
@@ -82,34 +126,66 @@ func (db *DB) Pool() *pgxpool.Pool {
 	return db.pool
 }
 
+// PoolUsage returns the share of the pool's connections in use, 1 when every
+// connection is taken and further queries wait for one
+func (db *DB) PoolUsage() float64 {
+	stat := db.pool.Stat()
+	if stat.MaxConns() <= 0 {
+		return 0
+	}
+	return float64(stat.AcquiredConns()) / float64(stat.MaxConns())
+}
+
+// IndexAdvisor returns the query index advisor, or nil when ExplainQueries is off
+func (db *DB) IndexAdvisor() *IndexAdvisor {
+	return db.advisor
+}
+
 // Ping tests the database connection
 func (db *DB) Ping(ctx context.Context) error {
 	return db.pool.Ping(ctx)
 }
 
-// Begin starts a new transaction
+// Begin starts a new transaction, or a savepoint within the transaction carried by ctx
 func (db *DB) Begin(ctx context.Context) (pgx.Tx, error) {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.Begin(ctx)
+	}
 	return db.pool.Begin(ctx)
 }
 
-// BeginTx starts a new transaction with options
+// BeginTx starts a new transaction with options. Within the transaction carried by
+// ctx it starts a savepoint, and the options are those of that transaction.
 func (db *DB) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.Begin(ctx)
+	}
 	return db.pool.BeginTx(ctx, txOptions)
 }
 
 // Exec executes a query without returning any rows
 func (db *DB) Exec(ctx context.Context, query string, args ...interface{}) error {
+	if tx := contextTx(ctx); tx != nil {
+		_, err := tx.Exec(ctx, query, args...)
+		return err
+	}
 	_, err := db.pool.Exec(ctx, query, args...)
 	return err
 }
 
 // Query executes a query that returns rows
 func (db *DB) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.Query(ctx, query, args...)
+	}
 	return db.pool.Query(ctx, query, args...)
 }
 
 // QueryRow executes a query that returns at most one row
 func (db *DB) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.QueryRow(ctx, query, args...)
+	}
 	return db.pool.QueryRow(ctx, query, args...)
 }
 
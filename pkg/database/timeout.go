@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// cancelRequestDelay lets a query that finishes right at its deadline return
+// before the server is asked to cancel it
+const cancelRequestDelay = 100 * time.Millisecond
+
+// cancelDeadlineDelay is how long a connection waits for the server to answer a
+// cancel request before it is closed
+const cancelDeadlineDelay = 5 * time.Second
+
+type queryTimeoutKey struct{}
+
+// WithQueryTimeout overrides the query timeout of the pool for the queries run
+// with ctx, e.g. for exports and scheduled jobs that legitimately run long
+// queries; 0 runs them without a deadline of their own. A deadline already on
+// ctx still applies.
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, timeout)
+}
+
+// queryTimeout returns the timeout of the queries run with ctx
+func queryTimeout(ctx context.Context, defaultTimeout time.Duration) time.Duration {
+	if timeout, ok := ctx.Value(queryTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return defaultTimeout
+}
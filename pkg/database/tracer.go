@@ -0,0 +1,313 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/tracing"
+)
+
+// queryLatency is published at /debug/vars as "db_query_latency", one
+// histogram per repository (e.g. "catalog.PostgresProductRepository")
+var queryLatency = expvar.NewMap("db_query_latency")
+
+// latencyBuckets are the upper bounds of the histogram buckets in milliseconds
+var latencyBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// maxLoggedQueryLength bounds the SQL logged for a slow query
+const maxLoggedQueryLength = 2000
+
+// queryTracer times every query and batch run on the pool. It records the
+// latency per repository and logs queries slower than the threshold with their
+// bound parameters redacted to their types. It also sets the query timeout on
+// the context each query runs with; a batch is bounded as a whole, from
+// SendBatch until its results are closed.
+type queryTracer struct {
+	slowThreshold time.Duration // 0 disables slow query logging
+	timeout       time.Duration // Default query timeout; 0 disables it
+	advisor       *IndexAdvisor // nil unless queries are explained
+
+	mu         sync.Mutex
+	histograms map[string]*latencyHistogram
+	callers    sync.Map // program counter -> resolved caller
+}
+
+var _ pgx.BatchTracer = (*queryTracer)(nil)
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	start  time.Time
+	caller queryCaller
+	sql    string
+	args   []any
+	cancel context.CancelFunc // Releases the query timeout; nil without one
+
+	batchSize int   // Number of queued queries; 0 for a single query
+	rows      int64 // Rows affected by the queries of a batch so far
+	ended     bool  // A batch that fails early is ended twice by pgx
+}
+
+// queryCaller identifies the repository method that ran a query
+type queryCaller struct {
+	Repository string // e.g. "catalog.PostgresProductRepository"
+	Method     string // e.g. "FindByCategoryID"
+}
+
+func newQueryTracer(slowThreshold, timeout time.Duration) *queryTracer {
+	return &queryTracer{
+		slowThreshold: slowThreshold,
+		timeout:       timeout,
+		histograms:    make(map[string]*latencyHistogram),
+	}
+}
+
+// TraceQueryStart implements pgx.QueryTracer. The returned context is the one
+// the query runs with, so the query timeout is set on it here.
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	trace := &queryTrace{
+		start:  time.Now(),
+		caller: t.resolveCaller(),
+		sql:    data.SQL,
+		args:   data.Args,
+	}
+	if timeout := queryTimeout(ctx, t.timeout); timeout > 0 {
+		ctx, trace.cancel = context.WithTimeout(ctx, timeout)
+	}
+	return context.WithValue(ctx, queryTraceKey{}, trace)
+}
+
+// TraceQueryEnd implements pgx.QueryTracer. For queries returning rows it runs
+// when the rows are closed, so the latency includes reading the results.
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+	if t.advisor != nil && data.Err == nil {
+		t.advisor.observe(trace)
+	}
+	t.end(ctx, trace, data.Err, data.CommandTag.RowsAffected())
+}
+
+// TraceBatchStart implements pgx.BatchTracer. The returned context is the one
+// every query of the batch is sent and read with, so the query timeout set on
+// it bounds the batch as a whole.
+func (t *queryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	trace := &queryTrace{
+		start:     time.Now(),
+		caller:    t.resolveCaller(),
+		batchSize: data.Batch.Len(),
+	}
+	if trace.batchSize > 0 {
+		// Batches queue the same statement over and over, the first one is
+		// enough to recognize it in the logs
+		trace.sql = data.Batch.QueuedQueries[0].SQL
+		trace.args = data.Batch.QueuedQueries[0].Arguments
+	}
+	if timeout := queryTimeout(ctx, t.timeout); timeout > 0 {
+		ctx, trace.cancel = context.WithTimeout(ctx, timeout)
+	}
+	return context.WithValue(ctx, queryTraceKey{}, trace)
+}
+
+// TraceBatchQuery implements pgx.BatchTracer
+func (t *queryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	if trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace); ok {
+		trace.rows += data.CommandTag.RowsAffected()
+	}
+}
+
+// TraceBatchEnd implements pgx.BatchTracer. It runs when the batch results are
+// closed, or from SendBatch when the batch could not be sent.
+func (t *queryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok || trace.ended {
+		return
+	}
+	trace.ended = true
+	t.end(ctx, trace, data.Err, trace.rows)
+}
+
+// end releases the query timeout of a query or batch, records its latency and
+// logs it when it is slow
+func (t *queryTracer) end(ctx context.Context, trace *queryTrace, err error, rows int64) {
+	if trace.cancel != nil {
+		defer trace.cancel()
+	}
+	elapsed := time.Since(trace.start)
+	t.histogram(trace.caller.Repository).observe(elapsed, err != nil)
+
+	if t.slowThreshold <= 0 || elapsed < t.slowThreshold {
+		return
+	}
+
+	fields := logger.Fields{
+		"repository":  trace.caller.Repository,
+		"method":      trace.caller.Method,
+		"duration_ms": elapsed.Milliseconds(),
+		"query":       compactSQL(trace.sql),
+		"args":        redactArgs(trace.args),
+		"rows":        rows,
+	}
+	if trace.batchSize > 0 {
+		fields["batch_size"] = trace.batchSize
+	}
+	if span, ok := tracing.FromContext(ctx); ok {
+		fields["trace_id"] = span.TraceID
+		fields["span_id"] = span.SpanID
+	}
+	log := logger.Get().WithContext(ctx).WithFields(fields)
+	if err != nil {
+		log = log.WithError(err)
+	}
+	log.Warn("slow database query")
+}
+
+func (t *queryTracer) histogram(repository string) *latencyHistogram {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, ok := t.histograms[repository]
+	if !ok {
+		h = &latencyHistogram{counts: make([]int64, len(latencyBuckets)+1)}
+		t.histograms[repository] = h
+		queryLatency.Set(repository, h)
+	}
+	return h
+}
+
+// resolveCaller finds the first repository frame (an infrastructure package
+// under internal/) on the stack of the query
+func (t *queryTracer) resolveCaller() queryCaller {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if cached, ok := t.callers.Load(frame.PC); ok {
+			return cached.(queryCaller)
+		}
+		if caller, ok := parseRepositoryFrame(frame.Function); ok {
+			t.callers.Store(frame.PC, caller)
+			return caller
+		}
+		if !more {
+			return queryCaller{Repository: "other"}
+		}
+	}
+}
+
+// parseRepositoryFrame reads a function name such as
+// "github.com/qhato/ecommerce/internal/catalog/infrastructure/persistence.(*PostgresProductRepository).FindByCategoryID"
+func parseRepositoryFrame(function string) (queryCaller, bool) {
+	i := strings.Index(function, "/internal/")
+	if i < 0 || !strings.Contains(function, "/infrastructure/") {
+		return queryCaller{}, false
+	}
+	boundedContext, _, _ := strings.Cut(function[i+len("/internal/"):], "/")
+
+	name := function[strings.LastIndex(function, "/")+1:]
+	_, name, _ = strings.Cut(name, ".") // Drop the package
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+	parts := strings.Split(name, ".")
+	for len(parts) > 1 && strings.HasPrefix(parts[len(parts)-1], "func") {
+		parts = parts[:len(parts)-1] // Closures report their enclosing method
+	}
+
+	caller := queryCaller{Repository: boundedContext + "." + parts[0]}
+	if len(parts) > 1 {
+		caller.Method = parts[1]
+	}
+	return caller, true
+}
+
+// compactSQL collapses whitespace and bounds the length of a query for logging
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxLoggedQueryLength {
+		sql = sql[:maxLoggedQueryLength] + "..."
+	}
+	return sql
+}
+
+// redactArgs replaces bound parameters with their types so values such as
+// emails or tokens never reach the logs
+func redactArgs(args []any) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			redacted[i] = fmt.Sprintf("$%d=<nil>", i+1)
+			continue
+		}
+		redacted[i] = fmt.Sprintf("$%d=<%T>", i+1, arg)
+	}
+	return redacted
+}
+
+// latencyHistogram counts query latencies into fixed buckets. It implements
+// expvar.Var.
+type latencyHistogram struct {
+	mu     sync.Mutex
+	counts []int64 // One per bucket, the last counts latencies above every bucket
+	count  int64
+	errors int64
+	sumMs  float64
+	maxMs  float64
+}
+
+func (h *latencyHistogram) observe(elapsed time.Duration, failed bool) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	bucket := len(latencyBuckets)
+	for i, bound := range latencyBuckets {
+		if ms <= bound {
+			bucket = i
+			break
+		}
+	}
+	h.counts[bucket]++
+	h.count++
+	h.sumMs += ms
+	if ms > h.maxMs {
+		h.maxMs = ms
+	}
+	if failed {
+		h.errors++
+	}
+}
+
+// String renders the histogram as JSON with cumulative bucket counts
+func (h *latencyHistogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[string]int64, len(h.counts))
+	var cumulative int64
+	for i, count := range h.counts {
+		cumulative += count
+		key := "+Inf"
+		if i < len(latencyBuckets) {
+			key = fmt.Sprintf("le_%gms", latencyBuckets[i])
+		}
+		buckets[key] = cumulative
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"count":   h.count,
+		"errors":  h.errors,
+		"sum_ms":  h.sumMs,
+		"max_ms":  h.maxMs,
+		"buckets": buckets,
+	})
+	return string(data)
+}
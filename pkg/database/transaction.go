@@ -84,6 +84,25 @@ func (db *DB) WithTransactionOptions(ctx context.Context, txOptions pgx.TxOption
 	return nil
 }
 
+type contextTxKey struct{}
+
+// WithContextTransaction executes a function within a transaction carried by the
+// context it is given. Queries and transactions run through db with that context
+// join it, the latter as savepoints, so repositories of different packages can
+// write in one transaction. Queries run on Pool() do not join it, and the context
+// must not be shared between goroutines.
+func (db *DB) WithContextTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, contextTxKey{}, tx))
+	})
+}
+
+// contextTx returns the transaction carried by ctx, nil if there is none
+func contextTx(ctx context.Context) pgx.Tx {
+	tx, _ := ctx.Value(contextTxKey{}).(pgx.Tx)
+	return tx
+}
+
 // Transactional is a helper to run multiple operations in a transaction
 type Transactional struct {
 	db *DB
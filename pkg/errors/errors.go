@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -26,6 +27,7 @@ const (
 	ErrCodeNotImplemented ErrorCode = "NOT_IMPLEMENTED"
 	ErrCodeServiceUnavail ErrorCode = "SERVICE_UNAVAILABLE"
 	ErrCodeGatewayTimeout ErrorCode = "GATEWAY_TIMEOUT"
+	ErrCodeTimeout        ErrorCode = "TIMEOUT" // An operation, e.g. a database query, was cancelled by its deadline
 
 	// Business logic errors
 	ErrCodeInsufficientStock ErrorCode = "INSUFFICIENT_STOCK"
@@ -33,6 +35,10 @@ const (
 	ErrCodePaymentFailed     ErrorCode = "PAYMENT_FAILED"
 	ErrCodeProductInactive   ErrorCode = "PRODUCT_INACTIVE"
 	ErrCodeOrderNotEditable  ErrorCode = "ORDER_NOT_EDITABLE"
+	ErrCodeCartPolicy        ErrorCode = "CART_POLICY_VIOLATION"
+	ErrCodeShippingRestrict  ErrorCode = "SHIPPING_RESTRICTED"
+	ErrCodeAddressInvalid    ErrorCode = "ADDRESS_INVALID"
+	ErrCodeURLKeyTaken       ErrorCode = "URL_KEY_TAKEN"
 )
 
 // AppError represents an application error with additional context
@@ -147,8 +153,12 @@ func Internal(message string) *AppError {
 	return New(ErrCodeInternal, message, http.StatusInternalServerError)
 }
 
-// InternalWrap wraps an error as internal server error
+// InternalWrap wraps an error as internal server error. Errors of operations
+// that timed out or were cancelled are wrapped as a Timeout instead.
 func InternalWrap(err error, message string) *AppError {
+	if isTimeout(err) {
+		return Timeout(err)
+	}
 	return Wrap(err, ErrCodeInternal, message, http.StatusInternalServerError)
 }
 
@@ -162,6 +172,25 @@ func ServiceUnavailable(message string) *AppError {
 	return New(ErrCodeServiceUnavail, message, http.StatusServiceUnavailable)
 }
 
+// GatewayTimeout creates a gateway timeout error (504)
+func GatewayTimeout(message string) *AppError {
+	return New(ErrCodeGatewayTimeout, message, http.StatusGatewayTimeout)
+}
+
+// Timeout creates the error of an operation cancelled by its deadline (504), or
+// because its request or the server went away (503)
+func Timeout(err error) *AppError {
+	if errors.Is(err, context.Canceled) {
+		return Wrap(err, ErrCodeTimeout, "operation cancelled", http.StatusServiceUnavailable)
+	}
+	return Wrap(err, ErrCodeTimeout, "operation timed out", http.StatusGatewayTimeout)
+}
+
+// TooManyRequests creates a rate limit error (429)
+func TooManyRequests(message string) *AppError {
+	return New(ErrCodeTooManyRequests, message, http.StatusTooManyRequests)
+}
+
 // Business logic error constructors
 
 // InsufficientStock creates an insufficient stock error
@@ -212,6 +241,74 @@ func OrderNotEditable(orderID string, status string) *AppError {
 		WithDetail("status", status)
 }
 
+// CartPolicyViolation creates a cart policy violation error; violations lists
+// each broken rule with its own code
+func CartPolicyViolation(violations interface{}) *AppError {
+	return New(
+		ErrCodeCartPolicy,
+		"Order violates cart policy",
+		http.StatusUnprocessableEntity,
+	).WithDetail("violations", violations)
+}
+
+// IsCartPolicyViolation checks if the error is a cart policy violation error
+func IsCartPolicyViolation(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code == ErrCodeCartPolicy
+	}
+	return false
+}
+
+// IsInsufficientStock checks if the error is an insufficient stock error
+func IsInsufficientStock(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code == ErrCodeInsufficientStock
+	}
+	return false
+}
+
+// ShippingRestricted creates an error for items that cannot be shipped to the
+// selected destination; restrictions lists each blocking rule with its reason
+func ShippingRestricted(restrictions interface{}) *AppError {
+	return New(
+		ErrCodeShippingRestrict,
+		"Order contains items that cannot be shipped to the selected address",
+		http.StatusUnprocessableEntity,
+	).WithDetail("restrictions", restrictions)
+}
+
+// AddressInvalid creates an error for an address that cannot be used as entered;
+// validation carries the issues found and any suggested corrections
+func AddressInvalid(validation interface{}) *AppError {
+	return New(
+		ErrCodeAddressInvalid,
+		"Address could not be verified",
+		http.StatusUnprocessableEntity,
+	).WithDetail("validation", validation)
+}
+
+// URLKeyTaken creates an error for a URL key already used by another product or
+// category where it would be served; suggestion is the first free alternative
+func URLKeyTaken(urlKey, suggestion string) *AppError {
+	return New(
+		ErrCodeURLKeyTaken,
+		fmt.Sprintf("URL key %q is already in use", urlKey),
+		http.StatusConflict,
+	).WithDetail("url_key", urlKey).
+		WithDetail("suggestion", suggestion)
+}
+
+// IsURLKeyTaken checks if the error is a URL key taken error
+func IsURLKeyTaken(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code == ErrCodeURLKeyTaken
+	}
+	return false
+}
+
 // IsConflict checks if the error is a conflict error
 func IsConflict(err error) bool {
 	var appErr *AppError
@@ -238,3 +335,25 @@ func IsUnauthorized(err error) bool {
 	}
 	return false
 }
+
+// IsTimeout checks if the error is, or was caused by, an operation that timed out or was cancelled
+func IsTimeout(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) && appErr.Code == ErrCodeTimeout {
+		return true
+	}
+	return isTimeout(err)
+}
+
+// queryCanceledState is the SQLSTATE of a statement cancelled by statement_timeout or a cancel request
+const queryCanceledState = "57014"
+
+// isTimeout reports whether err was caused by a context deadline or cancellation,
+// or by a database statement cancelled on the server
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	var sqlErr interface{ SQLState() string }
+	return errors.As(err, &sqlErr) && sqlErr.SQLState() == queryCanceledState
+}
@@ -25,8 +25,13 @@ func HandleHTTPError(w http.ResponseWriter, err error) {
 	var statusCode int
 	var errorResponse ErrorResponse
 
+	// Timed out operations are reported as such even when nothing wrapped them
+	if !As(err, &appErr) && isTimeout(err) {
+		appErr = Timeout(err)
+	}
+
 	// Check if it's an AppError
-	if As(err, &appErr) {
+	if appErr != nil {
 		statusCode = appErr.StatusCode
 		errorResponse = ErrorResponse{
 			Error: ErrorDetail{
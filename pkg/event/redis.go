@@ -0,0 +1,398 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// metrics is published at /debug/vars as "event_bus", keyed by stream
+var metrics = expvar.NewMap("event_bus")
+
+// RedisStreamConfig holds the settings of a Redis Streams event bus
+type RedisStreamConfig struct {
+	Host     string
+	Port     int
+	Password string
+	Database int
+	PoolSize int
+
+	StreamPrefix    string        // Each event type is published to <prefix>:<type>
+	Group           string        // Consumer group; instances of a service share one
+	Consumer        string        // Name of this instance in the group; defaults to host and pid
+	MaxLen          int64         // Approximate entries kept per stream; 0 keeps every entry
+	BatchSize       int64         // Entries read or claimed at once
+	BlockTimeout    time.Duration // How long a read waits for new entries
+	ClaimIdle       time.Duration // Pending entries idle this long are claimed from crashed consumers
+	MaxDeliveries   int64         // Deliveries of an entry before it is dropped as poison
+	MetricsInterval time.Duration // How often stream lag is sampled; 0 disables sampling
+}
+
+// RedisStreamBus implements Bus on Redis Streams, for deployments running
+// several instances without a message broker. Events are appended to a stream
+// per event type and read by a consumer group, so every group receives each
+// event once whichever instance published it. Entries are acknowledged once
+// every handler succeeds; entries left pending by a failed handler or a
+// crashed instance are claimed again after ClaimIdle.
+//
+// Events are serialized as JSON and rebuilt from the types registered with
+// RegisterType. Unlike the memory bus, Publish returns once the event is
+// stored, before handlers run.
+type RedisStreamBus struct {
+	client *redis.Client
+	cfg    RedisStreamConfig
+	logger *logger.Logger
+
+	mu          sync.RWMutex
+	subscribers map[string][]Handler
+	consumers   map[string]context.CancelFunc // By event type
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRedisStreamBus creates a Redis Streams event bus. It does not check the
+// connection: consumers retry until Redis is reachable.
+func NewRedisStreamBus(cfg RedisStreamConfig, log *logger.Logger) *RedisStreamBus {
+	if cfg.StreamPrefix == "" {
+		cfg.StreamPrefix = "events"
+	}
+	if cfg.Consumer == "" {
+		host, _ := os.Hostname()
+		cfg.Consumer = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.BlockTimeout <= 0 {
+		cfg.BlockTimeout = 5 * time.Second
+	}
+	if cfg.ClaimIdle <= 0 {
+		cfg.ClaimIdle = time.Minute
+	}
+	if cfg.MaxDeliveries <= 0 {
+		cfg.MaxDeliveries = 10
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &RedisStreamBus{
+		client: redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			Password: cfg.Password,
+			DB:       cfg.Database,
+			PoolSize: cfg.PoolSize,
+		}),
+		cfg:         cfg,
+		logger:      log.WithField("event_bus", "redis").WithField("group", cfg.Group),
+		subscribers: make(map[string][]Handler),
+		consumers:   make(map[string]context.CancelFunc),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	if cfg.MetricsInterval > 0 {
+		b.wg.Add(1)
+		go b.sampleLag()
+	}
+	return b
+}
+
+// Publish appends an event to the stream of its type
+func (b *RedisStreamBus) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", event.EventType(), err)
+	}
+
+	stream := b.stream(event.EventType())
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{"type": event.EventType(), "event": data},
+	}
+	if b.cfg.MaxLen > 0 {
+		args.MaxLen = b.cfg.MaxLen
+		args.Approx = true
+	}
+	if err := b.client.XAdd(ctx, args).Err(); err != nil {
+		metrics.Add(stream+".publish_errors", 1)
+		return fmt.Errorf("failed to publish event %s: %w", event.EventType(), err)
+	}
+	metrics.Add(stream+".published", 1)
+	return nil
+}
+
+// Subscribe subscribes a handler to an event type, starting the consumer of
+// its stream with the first handler
+func (b *RedisStreamBus) Subscribe(eventType string, handler Handler) error {
+	if handler == nil {
+		return fmt.Errorf("handler cannot be nil")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers[eventType] = append(b.subscribers[eventType], handler)
+	if _, running := b.consumers[eventType]; !running {
+		ctx, cancel := context.WithCancel(b.ctx)
+		b.consumers[eventType] = cancel
+		b.wg.Add(1)
+		go b.consume(ctx, eventType)
+	}
+
+	b.logger.WithField("event_type", eventType).Debug("Handler subscribed to event")
+	return nil
+}
+
+// Unsubscribe removes the handlers of an event type and stops consuming its stream
+func (b *RedisStreamBus) Unsubscribe(eventType string, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, eventType)
+	if cancel, ok := b.consumers[eventType]; ok {
+		cancel()
+		delete(b.consumers, eventType)
+	}
+
+	b.logger.WithField("event_type", eventType).Debug("Handler unsubscribed from event")
+	return nil
+}
+
+// Close stops the consumers, waiting for the entries being handled, and closes the client
+func (b *RedisStreamBus) Close() error {
+	b.cancel()
+	b.wg.Wait()
+
+	b.mu.Lock()
+	b.subscribers = make(map[string][]Handler)
+	b.consumers = make(map[string]context.CancelFunc)
+	b.mu.Unlock()
+
+	b.logger.Info("Redis event bus closed")
+	return b.client.Close()
+}
+
+func (b *RedisStreamBus) stream(eventType string) string {
+	return b.cfg.StreamPrefix + ":" + eventType
+}
+
+// consume reads the stream of an event type as this instance of the group
+// until ctx is cancelled, claiming the entries other consumers left pending
+// every ClaimIdle
+func (b *RedisStreamBus) consume(ctx context.Context, eventType string) {
+	defer b.wg.Done()
+
+	stream := b.stream(eventType)
+	log := b.logger.WithField("stream", stream)
+	groupReady := false
+	var lastClaim time.Time
+
+	for ctx.Err() == nil {
+		if !groupReady {
+			if err := b.ensureGroup(ctx, stream); err != nil {
+				log.WithError(err).Warn("Failed to create consumer group, retrying")
+				b.wait(ctx, b.cfg.BlockTimeout)
+				continue
+			}
+			groupReady = true
+		}
+
+		if time.Since(lastClaim) >= b.cfg.ClaimIdle {
+			b.claimPending(ctx, eventType, stream)
+			lastClaim = time.Now()
+		}
+
+		streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    b.cfg.Group,
+			Consumer: b.cfg.Consumer,
+			Streams:  []string{stream, ">"},
+			Count:    b.cfg.BatchSize,
+			Block:    b.cfg.BlockTimeout,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				groupReady = false // The stream was deleted
+			}
+			log.WithError(err).Warn("Failed to read event stream")
+			b.wait(ctx, b.cfg.BlockTimeout)
+			continue
+		}
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				b.handle(ctx, eventType, stream, msg)
+			}
+		}
+	}
+}
+
+// ensureGroup creates the consumer group of a stream, starting from new entries
+func (b *RedisStreamBus) ensureGroup(ctx context.Context, stream string) error {
+	err := b.client.XGroupCreateMkStream(ctx, stream, b.cfg.Group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// claimPending takes over the entries of the group pending longer than
+// ClaimIdle, either left by a consumer that crashed or failed by a handler,
+// and handles them again. Entries delivered MaxDeliveries times are dropped.
+func (b *RedisStreamBus) claimPending(ctx context.Context, eventType, stream string) {
+	log := b.logger.WithField("stream", stream)
+	for ctx.Err() == nil {
+		pending, err := b.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: stream,
+			Group:  b.cfg.Group,
+			Idle:   b.cfg.ClaimIdle,
+			Start:  "-",
+			End:    "+",
+			Count:  b.cfg.BatchSize,
+		}).Result()
+		if err != nil {
+			log.WithError(err).Warn("Failed to list pending events")
+			return
+		}
+		if len(pending) == 0 {
+			return
+		}
+
+		ids := make([]string, 0, len(pending))
+		for _, p := range pending {
+			if p.RetryCount >= b.cfg.MaxDeliveries {
+				log.WithField("entry_id", p.ID).WithField("deliveries", p.RetryCount).Error("Dropping event that failed too many times")
+				b.ack(ctx, stream, p.ID)
+				metrics.Add(stream+".dropped", 1)
+				continue
+			}
+			ids = append(ids, p.ID)
+		}
+		if len(ids) > 0 {
+			msgs, err := b.client.XClaim(ctx, &redis.XClaimArgs{
+				Stream:   stream,
+				Group:    b.cfg.Group,
+				Consumer: b.cfg.Consumer,
+				MinIdle:  b.cfg.ClaimIdle,
+				Messages: ids,
+			}).Result()
+			if err != nil {
+				log.WithError(err).Warn("Failed to claim pending events")
+				return
+			}
+			metrics.Add(stream+".claimed", int64(len(msgs)))
+			for _, msg := range msgs {
+				b.handle(ctx, eventType, stream, msg)
+			}
+		}
+		if int64(len(pending)) < b.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// handle runs the handlers of an entry and acknowledges it once they all
+// succeed. A failed entry stays pending to be claimed again.
+func (b *RedisStreamBus) handle(ctx context.Context, eventType, stream string, msg redis.XMessage) {
+	log := b.logger.WithField("stream", stream).WithField("entry_id", msg.ID)
+
+	data, _ := msg.Values["event"].(string)
+	evt, err := decodeEvent(eventType, []byte(data))
+	if err != nil {
+		log.WithError(err).Error("Dropping event that cannot be decoded")
+		b.ack(ctx, stream, msg.ID)
+		metrics.Add(stream+".dropped", 1)
+		return
+	}
+
+	b.mu.RLock()
+	handlers := b.subscribers[eventType]
+	b.mu.RUnlock()
+
+	failed := 0
+	for _, handler := range handlers {
+		if err := handler(ctx, evt); err != nil {
+			failed++
+			log.WithError(err).Error("Event handler failed")
+		}
+	}
+	if failed > 0 {
+		metrics.Add(stream+".failed", 1)
+		return
+	}
+
+	b.ack(ctx, stream, msg.ID)
+	metrics.Add(stream+".handled", 1)
+}
+
+func (b *RedisStreamBus) ack(ctx context.Context, stream, id string) {
+	if err := b.client.XAck(ctx, stream, b.cfg.Group, id).Err(); err != nil {
+		b.logger.WithError(err).WithField("stream", stream).WithField("entry_id", id).Warn("Failed to acknowledge event")
+	}
+}
+
+// sampleLag publishes the lag and pending count of the group on every
+// consumed stream until the bus is closed
+func (b *RedisStreamBus) sampleLag() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.cfg.MetricsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+			b.mu.RLock()
+			eventTypes := make([]string, 0, len(b.consumers))
+			for eventType := range b.consumers {
+				eventTypes = append(eventTypes, eventType)
+			}
+			b.mu.RUnlock()
+
+			for _, eventType := range eventTypes {
+				stream := b.stream(eventType)
+				groups, err := b.client.XInfoGroups(b.ctx, stream).Result()
+				if err != nil {
+					continue
+				}
+				for _, group := range groups {
+					if group.Name != b.cfg.Group {
+						continue
+					}
+					setGauge(stream+".lag", group.Lag)
+					setGauge(stream+".pending", group.Pending)
+				}
+			}
+		}
+	}
+}
+
+// wait sleeps for d or until ctx is cancelled
+func (b *RedisStreamBus) wait(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+func setGauge(key string, value int64) {
+	gauge := new(expvar.Int)
+	gauge.Set(value)
+	metrics.Set(key, gauge)
+}
@@ -0,0 +1,50 @@
+package event
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+var (
+	typesMu sync.RWMutex
+	types   = make(map[string]func() Event)
+)
+
+// RegisterType registers how an event type is rebuilt when it is read back
+// from a bus that serializes events, such as the Redis Streams bus. newEvent
+// returns a pointer to an empty event of the concrete type subscribers expect.
+// Unregistered types are delivered as a *BaseEvent.
+func RegisterType(eventType string, newEvent func() Event) {
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	types[eventType] = newEvent
+}
+
+// RegisteredTypes returns the event types registered with RegisterType, each
+// with an empty event of its concrete type
+func RegisteredTypes() map[string]Event {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+
+	registered := make(map[string]Event, len(types))
+	for eventType, newEvent := range types {
+		registered[eventType] = newEvent()
+	}
+	return registered
+}
+
+// decodeEvent rebuilds a serialized event as its registered type
+func decodeEvent(eventType string, data []byte) (Event, error) {
+	typesMu.RLock()
+	newEvent, ok := types[eventType]
+	typesMu.RUnlock()
+
+	var evt Event = &BaseEvent{}
+	if ok {
+		evt = newEvent()
+	}
+	if err := json.Unmarshal(data, evt); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
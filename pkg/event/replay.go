@@ -0,0 +1,230 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// replayField marks an entry appended by Replay with the ID of the entry it
+// copies, so a later replay over the same range does not copy it again
+const replayField = "replay_of"
+
+// ReplayFilter selects the stored events to publish again
+type ReplayFilter struct {
+	EventTypes   []string  // Event types, or prefixes ending in * such as catalog.*; empty replays every type
+	AggregateIDs []string  // Only events of these aggregates; empty replays every aggregate
+	From         time.Time // Stored at or after; zero starts at the oldest entry
+	To           time.Time // Stored at or before; zero ends at the latest entry
+	Rate         float64   // Events published per second; 0 publishes as fast as possible
+	DryRun       bool      // Report the matching events without publishing them
+}
+
+// ReplayedEvent is an event matched by a replay
+type ReplayedEvent struct {
+	EntryID     string    `json:"entry_id"` // Stream entry the event was read from
+	EventType   string    `json:"event_type"`
+	EventID     string    `json:"event_id"`
+	AggregateID string    `json:"aggregate_id"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// ReplayReport counts the events of a replay by type
+type ReplayReport struct {
+	DryRun    bool           `json:"dry_run"`
+	Streams   int            `json:"streams"`
+	Scanned   int            `json:"scanned"`
+	Replayed  int            `json:"replayed"`
+	ByType    map[string]int `json:"by_type"`
+	Completed bool           `json:"completed"` // False when the replay was interrupted
+}
+
+// Replay appends the stored events matching filter to their streams again, in
+// the order they were stored within each stream, so every consumer group
+// receives them as new events and can rebuild the state derived from them.
+// Only the entries still kept by the streams' MaxLen can be replayed. Handlers
+// must tolerate receiving an event twice. onEvent, when set, is called for
+// each matching event before it is published.
+func (b *RedisStreamBus) Replay(ctx context.Context, filter ReplayFilter, onEvent func(ReplayedEvent)) (*ReplayReport, error) {
+	report := &ReplayReport{DryRun: filter.DryRun, ByType: make(map[string]int)}
+
+	streams, err := b.replayStreams(ctx, filter.EventTypes)
+	if err != nil {
+		return report, err
+	}
+	report.Streams = len(streams)
+
+	aggregates := make(map[string]bool, len(filter.AggregateIDs))
+	for _, id := range filter.AggregateIDs {
+		aggregates[id] = true
+	}
+
+	var ticker *time.Ticker
+	if filter.Rate > 0 && !filter.DryRun {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / filter.Rate))
+		defer ticker.Stop()
+	}
+
+	start, end := "-", "+"
+	if !filter.From.IsZero() {
+		start = strconv.FormatInt(filter.From.UnixMilli(), 10)
+	}
+	if !filter.To.IsZero() {
+		end = strconv.FormatInt(filter.To.UnixMilli(), 10)
+	}
+
+	for _, stream := range streams {
+		eventType := strings.TrimPrefix(stream, b.cfg.StreamPrefix+":")
+		last, err := b.replayEnd(ctx, stream, end)
+		if err != nil {
+			return report, err
+		}
+		from := start
+		for last != "" {
+			msgs, err := b.client.XRangeN(ctx, stream, from, last, b.cfg.BatchSize).Result()
+			if err != nil {
+				if ctx.Err() != nil {
+					return report, nil
+				}
+				return report, fmt.Errorf("failed to read stream %s: %w", stream, err)
+			}
+			for _, msg := range msgs {
+				report.Scanned++
+				if _, replayed := msg.Values[replayField]; replayed {
+					continue
+				}
+				data, _ := msg.Values["event"].(string)
+				evt, err := decodeReplayedEvent(msg.ID, eventType, data)
+				if err != nil {
+					b.logger.WithError(err).WithField("stream", stream).WithField("entry_id", msg.ID).Warn("Skipping event that cannot be decoded")
+					continue
+				}
+				if len(aggregates) > 0 && !aggregates[evt.AggregateID] {
+					continue
+				}
+
+				if onEvent != nil {
+					onEvent(*evt)
+				}
+				if !filter.DryRun {
+					if ticker != nil {
+						select {
+						case <-ctx.Done():
+							return report, nil
+						case <-ticker.C:
+						}
+					}
+					// Copies are not trimmed so they cannot drop entries not yet replayed;
+					// the next event published to the stream trims it back to MaxLen
+					if err := b.client.XAdd(ctx, &redis.XAddArgs{
+						Stream: stream,
+						Values: map[string]interface{}{"type": eventType, "event": data, replayField: msg.ID},
+					}).Err(); err != nil {
+						if ctx.Err() != nil {
+							return report, nil
+						}
+						return report, fmt.Errorf("failed to replay event %s: %w", msg.ID, err)
+					}
+					metrics.Add(stream+".replayed", 1)
+				}
+				report.Replayed++
+				report.ByType[eventType]++
+			}
+			if int64(len(msgs)) < b.cfg.BatchSize {
+				break
+			}
+			from = "(" + msgs[len(msgs)-1].ID
+		}
+	}
+
+	report.Completed = ctx.Err() == nil
+	return report, nil
+}
+
+// replayEnd pins an open range to the latest entry of a stream, so the entries
+// appended by the replay are not read back; it is empty for an empty stream
+func (b *RedisStreamBus) replayEnd(ctx context.Context, stream, end string) (string, error) {
+	if end != "+" {
+		return end, nil
+	}
+	msgs, err := b.client.XRevRangeN(ctx, stream, "+", "-", 1).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to read stream %s: %w", stream, err)
+	}
+	if len(msgs) == 0 {
+		return "", nil
+	}
+	return msgs[0].ID, nil
+}
+
+// replayStreams lists the streams of the event types matching patterns
+func (b *RedisStreamBus) replayStreams(ctx context.Context, patterns []string) ([]string, error) {
+	var streams []string
+	iter := b.client.Scan(ctx, 0, b.cfg.StreamPrefix+":*", 100).Iterator()
+	for iter.Next(ctx) {
+		stream := iter.Val()
+		if matchesEventType(patterns, strings.TrimPrefix(stream, b.cfg.StreamPrefix+":")) {
+			streams = append(streams, stream)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list event streams: %w", err)
+	}
+
+	// Streams share the key space with the cache; only keep actual streams
+	kept := streams[:0]
+	for _, stream := range streams {
+		kind, err := b.client.Type(ctx, stream).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", stream, err)
+		}
+		if kind == "stream" {
+			kept = append(kept, stream)
+		}
+	}
+	sort.Strings(kept)
+	return kept, nil
+}
+
+// matchesEventType reports whether an event type matches any pattern; a
+// pattern ending in * matches the types it prefixes
+func matchesEventType(patterns []string, eventType string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(eventType, prefix) {
+				return true
+			}
+		} else if pattern == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeReplayedEvent reads the envelope of a stored event
+func decodeReplayedEvent(entryID, eventType, data string) (*ReplayedEvent, error) {
+	var envelope struct {
+		ID         string    `json:"id"`
+		Aggregate  string    `json:"aggregate_id"`
+		OccurredOn time.Time `json:"occurred_at"`
+	}
+	if err := json.Unmarshal([]byte(data), &envelope); err != nil {
+		return nil, err
+	}
+	return &ReplayedEvent{
+		EntryID:     entryID,
+		EventType:   eventType,
+		EventID:     envelope.ID,
+		AggregateID: envelope.Aggregate,
+		OccurredAt:  envelope.OccurredOn,
+	}, nil
+}
@@ -0,0 +1,479 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// FieldType is the JSON type of an event payload field
+type FieldType string
+
+const (
+	FieldString  FieldType = "string"
+	FieldInteger FieldType = "integer"
+	FieldNumber  FieldType = "number"
+	FieldBoolean FieldType = "boolean"
+	FieldTime    FieldType = "time" // RFC 3339 string
+	FieldObject  FieldType = "object"
+	FieldArray   FieldType = "array"
+	FieldAny     FieldType = "any"
+)
+
+// Field describes a payload field of an event schema
+type Field struct {
+	Type     FieldType `json:"type"`
+	Required bool      `json:"required,omitempty"` // Always present and not null
+}
+
+// Schema is one version of the payload contract of an event type. Schemas are
+// kept in the repository, one file per version, and a new version may only add
+// fields: consumers of the previous version must be able to read every event of
+// the new one.
+type Schema struct {
+	Type    string           `json:"type"`
+	Version int              `json:"version"`
+	Fields  map[string]Field `json:"fields"`
+}
+
+// Filename returns the name of the file holding the schema, e.g.
+// catalog.product.created.v2.json
+func (s *Schema) Filename() string {
+	return fmt.Sprintf("%s.v%d.json", s.Type, s.Version)
+}
+
+// envelopeFields are the fields of BaseEvent, common to every event and not part of schemas
+var envelopeFields = map[string]bool{"type": true, "id": true, "aggregate_id": true, "occurred_at": true, "payload": true}
+
+// CheckCompatible returns the changes of next that break consumers of prev:
+// removed fields, changed types and required fields made optional
+func CheckCompatible(prev, next *Schema) []string {
+	var breaks []string
+	for _, name := range sortedFields(prev.Fields) {
+		field := prev.Fields[name]
+		nextField, ok := next.Fields[name]
+		switch {
+		case !ok:
+			breaks = append(breaks, fmt.Sprintf("removes field %s", name))
+		case nextField.Type != field.Type:
+			breaks = append(breaks, fmt.Sprintf("changes field %s from %s to %s", name, field.Type, nextField.Type))
+		case field.Required && !nextField.Required:
+			breaks = append(breaks, fmt.Sprintf("makes required field %s optional", name))
+		}
+	}
+	return breaks
+}
+
+// SchemaOf describes the payload of an event from its Go type: the JSON fields
+// of the struct outside the BaseEvent envelope. Pointers, maps, slices,
+// interfaces and omitempty fields may be missing or null and are optional.
+func SchemaOf(eventType string, evt Event) *Schema {
+	schema := &Schema{Type: eventType, Fields: make(map[string]Field)}
+	t := reflect.TypeOf(evt)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		collectFields(t, schema.Fields)
+	}
+	return schema
+}
+
+func collectFields(t reflect.Type, fields map[string]Field) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, omitEmpty, ok := jsonField(sf)
+		if !ok {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, fields)
+				continue
+			}
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if envelopeFields[name] {
+			continue
+		}
+		fieldType, nullable := fieldTypeOf(sf.Type)
+		fields[name] = Field{Type: fieldType, Required: !omitEmpty && !nullable}
+	}
+}
+
+// jsonField returns the JSON name of a struct field, empty when it is not
+// renamed, and whether it is marshaled at all
+func jsonField(sf reflect.StructField) (string, bool, bool) {
+	if !sf.IsExported() && !sf.Anonymous {
+		return "", false, false
+	}
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(","+opts+",", ",omitempty,"), true
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// fieldTypeOf maps a Go type to its JSON type and whether it may be null
+func fieldTypeOf(t reflect.Type) (FieldType, bool) {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+	if t == timeType {
+		return FieldTime, nullable
+	}
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return FieldAny, nullable
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return FieldString, nullable
+	case reflect.Bool:
+		return FieldBoolean, nullable
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FieldInteger, nullable
+	case reflect.Float32, reflect.Float64:
+		return FieldNumber, nullable
+	case reflect.Struct:
+		return FieldObject, nullable
+	case reflect.Map:
+		return FieldObject, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return FieldString, true // Base64
+		}
+		return FieldArray, true
+	case reflect.Array:
+		return FieldArray, nullable
+	default:
+		return FieldAny, true
+	}
+}
+
+// Contract declares the fields of an event type a consumer reads, so a schema
+// change removing them is caught before it reaches the consumer
+type Contract struct {
+	Consumer  string // e.g. order.update_notifier
+	EventType string
+	Fields    []string
+}
+
+var (
+	contractsMu sync.RWMutex
+	contracts   []Contract
+)
+
+// RegisterContract declares that a consumer relies on fields of an event type.
+// Consumers register their contracts next to their subscriptions.
+func RegisterContract(consumer, eventType string, fields ...string) {
+	contractsMu.Lock()
+	defer contractsMu.Unlock()
+	contracts = append(contracts, Contract{Consumer: consumer, EventType: eventType, Fields: fields})
+}
+
+// RegisteredContracts returns the contracts declared with RegisterContract
+func RegisteredContracts() []Contract {
+	contractsMu.RLock()
+	defer contractsMu.RUnlock()
+	return append([]Contract(nil), contracts...)
+}
+
+// SchemaRegistry holds every version of the event schemas kept in the repository
+type SchemaRegistry struct {
+	versions map[string][]*Schema // By event type, ordered by version
+}
+
+// LoadSchemas reads the schemas of fsys, one JSON file per event type and
+// version named <type>.v<version>.json
+func LoadSchemas(fsys fs.FS) (*SchemaRegistry, error) {
+	r := &SchemaRegistry{versions: make(map[string][]*Schema)}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) != ".json" {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		schema := &Schema{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(schema); err != nil {
+			return fmt.Errorf("invalid event schema %s: %w", name, err)
+		}
+		if schema.Type == "" || schema.Version < 1 || path.Base(name) != schema.Filename() {
+			return fmt.Errorf("event schema %s must be named after its type and version, e.g. %s", name, schema.Filename())
+		}
+		r.versions[schema.Type] = append(r.versions[schema.Type], schema)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, versions := range r.versions {
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	}
+	return r, nil
+}
+
+// Latest returns the current schema of an event type
+func (r *SchemaRegistry) Latest(eventType string) (*Schema, bool) {
+	versions := r.versions[eventType]
+	if len(versions) == 0 {
+		return nil, false
+	}
+	return versions[len(versions)-1], true
+}
+
+// Types returns the event types with a schema
+func (r *SchemaRegistry) Types() []string {
+	eventTypes := make([]string, 0, len(r.versions))
+	for eventType := range r.versions {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
+
+// Check runs the compatibility suite and returns the problems found:
+//   - the versions of each schema are numbered from 1 and each only adds fields
+//   - every event type registered with RegisterType has a schema, and its Go
+//     type still matches the latest version
+//   - every field a consumer declared in a contract is in the latest schema
+func (r *SchemaRegistry) Check() []string {
+	var problems []string
+	for _, eventType := range r.Types() {
+		versions := r.versions[eventType]
+		for i, schema := range versions {
+			if schema.Version != i+1 {
+				problems = append(problems, fmt.Sprintf("%s: expected version %d, found %d", eventType, i+1, schema.Version))
+				break
+			}
+			if i == 0 {
+				continue
+			}
+			for _, b := range CheckCompatible(versions[i-1], schema) {
+				problems = append(problems, fmt.Sprintf("%s: version %d %s", eventType, schema.Version, b))
+			}
+		}
+	}
+
+	registered := RegisteredTypes()
+	eventTypes := make([]string, 0, len(registered))
+	for eventType := range registered {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	for _, eventType := range eventTypes {
+		latest, ok := r.Latest(eventType)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: no schema", eventType))
+			continue
+		}
+		current := SchemaOf(eventType, registered[eventType])
+		if diff := diffFields(latest.Fields, current.Fields); diff != "" {
+			problems = append(problems, fmt.Sprintf("%s: Go type no longer matches schema version %d (%s)", eventType, latest.Version, diff))
+		}
+	}
+
+	for _, contract := range RegisteredContracts() {
+		latest, ok := r.Latest(contract.EventType)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: consumed by %s but has no schema", contract.EventType, contract.Consumer))
+			continue
+		}
+		for _, field := range contract.Fields {
+			if _, ok := latest.Fields[field]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: field %s read by %s is not in schema version %d", contract.EventType, field, contract.Consumer, latest.Version))
+			}
+		}
+	}
+	return problems
+}
+
+// Validate checks the payload of an event against the latest schema of its
+// type. Events of types without a schema are not checked.
+func (r *SchemaRegistry) Validate(evt Event) error {
+	schema, ok := r.Latest(evt.EventType())
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", evt.EventType(), err)
+	}
+	var payload map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return fmt.Errorf("event %s is not a JSON object: %w", evt.EventType(), err)
+	}
+
+	var problems []string
+	for _, name := range sortedFields(schema.Fields) {
+		field := schema.Fields[name]
+		value, present := payload[name]
+		if value == nil {
+			if field.Required {
+				problems = append(problems, fmt.Sprintf("missing required field %s", name))
+			}
+			continue
+		}
+		if !present || matchesType(value, field.Type) {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("field %s is not of type %s", name, field.Type))
+	}
+	for name := range payload {
+		if _, ok := schema.Fields[name]; !ok && !envelopeFields[name] {
+			problems = append(problems, fmt.Sprintf("field %s is not in the schema", name))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("event %s does not match schema version %d: %s", evt.EventType(), schema.Version, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// matchesType reports whether a decoded JSON value is of a field type
+func matchesType(value interface{}, fieldType FieldType) bool {
+	switch fieldType {
+	case FieldString:
+		_, ok := value.(string)
+		return ok
+	case FieldTime:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	case FieldInteger:
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case FieldNumber:
+		_, ok := value.(json.Number)
+		return ok
+	case FieldBoolean:
+		_, ok := value.(bool)
+		return ok
+	case FieldObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case FieldArray:
+		_, ok := value.([]interface{})
+		return ok
+	default:
+		return true
+	}
+}
+
+// diffFields describes how the fields of a Go type differ from a schema
+func diffFields(schema, current map[string]Field) string {
+	var diffs []string
+	for _, name := range sortedFields(schema) {
+		field, ok := current[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, "field "+name+" removed")
+		case field != schema[name]:
+			diffs = append(diffs, fmt.Sprintf("field %s is %s", name, describeField(field)))
+		}
+	}
+	for _, name := range sortedFields(current) {
+		if _, ok := schema[name]; !ok {
+			diffs = append(diffs, "field "+name+" added")
+		}
+	}
+	return strings.Join(diffs, ", ")
+}
+
+func describeField(field Field) string {
+	if field.Required {
+		return "a required " + string(field.Type)
+	}
+	return "an optional " + string(field.Type)
+}
+
+func sortedFields(fields map[string]Field) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SchemaMode sets what publishing an event that does not match its schema does
+type SchemaMode string
+
+const (
+	SchemaModeOff     SchemaMode = "off"     // Events are not checked
+	SchemaModeWarn    SchemaMode = "warn"    // Mismatches are logged and the event is published
+	SchemaModeEnforce SchemaMode = "enforce" // Mismatches fail Publish
+)
+
+// IsValid reports whether the mode is known
+func (m SchemaMode) IsValid() bool {
+	return m == SchemaModeOff || m == SchemaModeWarn || m == SchemaModeEnforce
+}
+
+// schemaBus checks events against their schema before publishing them
+type schemaBus struct {
+	Bus
+	registry *SchemaRegistry
+	mode     SchemaMode
+	logger   *logger.Logger
+}
+
+// WithSchemas wraps a bus so published events are checked against the latest
+// schema of their type, catching a producer that drifted from the contract its
+// consumers rely on. SchemaModeOff returns bus unchanged.
+func WithSchemas(bus Bus, registry *SchemaRegistry, mode SchemaMode, log *logger.Logger) Bus {
+	if mode == SchemaModeOff || registry == nil {
+		return bus
+	}
+	return &schemaBus{Bus: bus, registry: registry, mode: mode, logger: log.WithField("event_bus", "schemas")}
+}
+
+// Publish checks the event against its schema, then publishes it
+func (b *schemaBus) Publish(ctx context.Context, event Event) error {
+	if err := b.registry.Validate(event); err != nil {
+		metrics.Add(event.EventType()+".schema_mismatches", 1)
+		if b.mode == SchemaModeEnforce {
+			return err
+		}
+		b.logger.WithError(err).WithField("event_id", event.EventID()).Warn("event does not match its schema")
+	}
+	return b.Bus.Publish(ctx, event)
+}
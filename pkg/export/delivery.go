@@ -0,0 +1,151 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qhato/ecommerce/pkg/notification"
+	"github.com/qhato/ecommerce/pkg/pdf"
+)
+
+// Format is the file format an export is delivered in
+type Format string
+
+const (
+	FormatCSV Format = "csv"
+	FormatPDF Format = "pdf"
+)
+
+// IsValid reports whether exports can be delivered in the format
+func (f Format) IsValid() bool {
+	return f == FormatCSV || f == FormatPDF
+}
+
+// Mailer emails files. The notifier of a job manager must also be a Mailer for
+// exports to be delivered.
+type Mailer interface {
+	SendEmailWithAttachments(ctx context.Context, to, subject, body string, attachments []notification.Attachment) error
+}
+
+// Delivery emails the file of an export job to its recipients once it is written
+type Delivery struct {
+	To      []string
+	Subject string
+	Body    string
+	Format  Format // CSV files are attached as written; PDF files lay the rows out as a table
+	Title   string // Heading of PDF files, the subject when empty
+}
+
+// StartDelivery queues an export whose file is emailed as an attachment. The job
+// fails when the email cannot be sent.
+func (m *JobManager) StartDelivery(exp Export, requestedBy string, delivery Delivery) *Job {
+	job := &Job{
+		ID:          uuid.New().String(),
+		Kind:        exp.Kind,
+		Filename:    exp.Filename,
+		Status:      JobStatusPending,
+		RequestedBy: requestedBy,
+		CreatedAt:   time.Now(),
+	}
+	job.path = filepath.Join(m.cfg.Dir, job.ID+".csv")
+
+	m.mu.Lock()
+	m.jobs[job.ID] = job
+	m.mu.Unlock()
+
+	go m.run(job, exp, "", &delivery)
+	return m.snapshot(job)
+}
+
+// deliver emails the written file of a job to each recipient
+func (m *JobManager) deliver(ctx context.Context, job *Job, exp Export, delivery Delivery) error {
+	mailer, ok := m.notifier.(Mailer)
+	if !ok {
+		return fmt.Errorf("no mailer configured")
+	}
+
+	attachment, err := m.attachment(job, exp, delivery)
+	if err != nil {
+		return err
+	}
+	for _, to := range delivery.To {
+		if err := mailer.SendEmailWithAttachments(ctx, to, delivery.Subject, delivery.Body, []notification.Attachment{attachment}); err != nil {
+			return fmt.Errorf("failed to email %s: %w", to, err)
+		}
+	}
+	return nil
+}
+
+func (m *JobManager) attachment(job *Job, exp Export, delivery Delivery) (notification.Attachment, error) {
+	content, err := os.ReadFile(job.path)
+	if err != nil {
+		return notification.Attachment{}, err
+	}
+	if delivery.Format != FormatPDF {
+		return notification.Attachment{Filename: exp.Filename, ContentType: "text/csv", Content: content}, nil
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return notification.Attachment{}, err
+	}
+	title := delivery.Title
+	if title == "" {
+		title = delivery.Subject
+	}
+	doc := tableDocument{Title: title, GeneratedAt: time.Now().UTC().Format(time.RFC1123)}
+	if len(records) > 0 {
+		doc.Header, doc.Rows = records[0], records[1:]
+	}
+
+	engine, err := tableEngine()
+	if err != nil {
+		return notification.Attachment{}, err
+	}
+	rendered, err := engine.Render(tableTemplateName, doc, pdf.RenderOptions{})
+	if err != nil {
+		return notification.Attachment{}, err
+	}
+	filename := strings.TrimSuffix(exp.Filename, filepath.Ext(exp.Filename)) + ".pdf"
+	return notification.Attachment{Filename: filename, ContentType: "application/pdf", Content: rendered}, nil
+}
+
+// tableDocument is the data of the PDF table template
+type tableDocument struct {
+	Title       string
+	GeneratedAt string
+	Header      []string
+	Rows        [][]string
+}
+
+const tableTemplateName = "export_table"
+
+// tableTemplate lays an export out as one table, in landscape to fit wide reports
+const tableTemplate = `<html><head><title>{{ .Title }}</title></head>
+<body size="A4" orientation="landscape" margin="30" font-size="8">
+  <h2>{{ .Title }}</h2>
+  <p size="8" color="#666666">Generated {{ .GeneratedAt }}</p>
+  <table border="0.5">
+    <tr>{{ range .Header }}<th>{{ . }}</th>{{ end }}</tr>
+    {{ range .Rows }}<tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>{{ end }}
+  </table>
+  <footer><p align="center" size="7">Page <pagenumber/> of <pagecount/></p></footer>
+</body></html>`
+
+// tableEngine is shared by all deliveries; templates are parsed once
+var tableEngine = sync.OnceValues(func() (*pdf.Engine, error) {
+	engine := pdf.NewEngine()
+	if err := engine.AddTemplate(tableTemplateName, tableTemplate); err != nil {
+		return nil, err
+	}
+	return engine, nil
+})
@@ -0,0 +1,42 @@
+// Package export produces CSV exports, either streamed straight to the client
+// or written to a file by a background job when they are too large for a request.
+// Background jobs can also email the file, as CSV or PDF, for scheduled reports.
+package export
+
+import (
+	"context"
+	"time"
+)
+
+// RowWriter receives export rows one at a time
+type RowWriter interface {
+	Write(row []string) error
+}
+
+// Export describes a CSV export. Rows must be written as they are read so an
+// export never holds the full result in memory.
+type Export struct {
+	Kind     string // e.g. "customers", used in job listings and notifications
+	Filename string
+	Header   []string
+	Rows     func(ctx context.Context, w RowWriter) error
+}
+
+// Report is a report admins can subscribe to, built as an export covering a
+// period. Params are the query parameters of the report's admin endpoint.
+type Report struct {
+	Name   string // e.g. "sales_summary"
+	Title  string
+	Params []string // Parameters the report accepts
+	Build  func(from, to time.Time, params map[string]string) (Export, error)
+}
+
+// AcceptsParam reports whether the report takes the parameter
+func (r Report) AcceptsParam(name string) bool {
+	for _, param := range r.Params {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
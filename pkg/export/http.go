@@ -0,0 +1,79 @@
+package export
+
+import (
+	"net/http"
+	"time"
+
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/middleware"
+)
+
+// Serve streams an export to the client, or queues it as a background job when
+// the request has ?async=true. Background jobs notify the requester by email
+// unless ?notify=false.
+func Serve(w http.ResponseWriter, r *http.Request, jobs *JobManager, exp Export, log *logger.Logger) {
+	ctx := r.Context()
+
+	if r.URL.Query().Get("async") == "true" {
+		notifyEmail := ""
+		if r.URL.Query().Get("notify") != "false" {
+			notifyEmail = middleware.GetUserEmail(ctx)
+		}
+		job := jobs.Start(exp, middleware.GetUserID(ctx), notifyEmail)
+		pkghttp.RespondJSON(w, http.StatusAccepted, job)
+		return
+	}
+
+	// Large exports outlive the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.WithError(err).Debug("could not lift write deadline for export")
+	}
+
+	cw, err := pkghttp.NewCSVWriter(w, exp.Filename, exp.Header)
+	if err != nil {
+		log.WithError(err).WithField("kind", exp.Kind).Error("failed to start export")
+		return
+	}
+	// Headers are already sent, so a failure can only cut the download short
+	if err := exp.Rows(ctx, cw); err != nil {
+		log.WithError(err).WithField("kind", exp.Kind).Error("export stream failed")
+		return
+	}
+	if err := cw.Flush(); err != nil {
+		log.WithError(err).WithField("kind", exp.Kind).Error("failed to flush export")
+	}
+}
+
+// ParseCreatedRange reads the created_from and created_to query parameters as
+// RFC 3339 timestamps or YYYY-MM-DD dates. The returned range is half-open, so
+// a created_to date includes that whole day.
+func ParseCreatedRange(r *http.Request) (from, to *time.Time, err error) {
+	values := r.URL.Query()
+	if value := values.Get("created_from"); value != "" {
+		t, _, err := parseTime(value)
+		if err != nil {
+			return nil, nil, pkghttp.NewValidationError("invalid created_from, expected RFC 3339 timestamp or YYYY-MM-DD")
+		}
+		from = &t
+	}
+	if value := values.Get("created_to"); value != "" {
+		t, dateOnly, err := parseTime(value)
+		if err != nil {
+			return nil, nil, pkghttp.NewValidationError("invalid created_to, expected RFC 3339 timestamp or YYYY-MM-DD")
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = &t
+	}
+	return from, to, nil
+}
+
+func parseTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	return t, true, err
+}
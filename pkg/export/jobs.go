@@ -0,0 +1,294 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// JobStatus represents the state of an export job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// Job is a background export
+type Job struct {
+	ID          string     `json:"id"`
+	Kind        string     `json:"kind"`
+	Filename    string     `json:"filename"`
+	Status      JobStatus  `json:"status"`
+	RequestedBy string     `json:"requested_by"`
+	Rows        int64      `json:"rows"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+
+	path string
+}
+
+// Notifier tells the requester that an export is ready
+type Notifier interface {
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
+// JobConfig holds background export settings
+type JobConfig struct {
+	Dir           string        // Directory export files are written to
+	Retention     time.Duration // How long finished files can be downloaded
+	MaxConcurrent int           // Exports running at the same time; further jobs wait
+}
+
+// JobManager runs exports in the background and keeps their files until they expire
+type JobManager struct {
+	cfg      JobConfig
+	notifier Notifier
+	logger   *logger.Logger
+	slots    chan struct{}
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+}
+
+// NewJobManager creates a job manager. notifier may be nil.
+func NewJobManager(cfg JobConfig, notifier Notifier, log *logger.Logger) (*JobManager, error) {
+	if cfg.Dir == "" {
+		cfg.Dir = filepath.Join(os.TempDir(), "exports")
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = 2
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return &JobManager{
+		cfg:      cfg,
+		notifier: notifier,
+		logger:   log,
+		slots:    make(chan struct{}, cfg.MaxConcurrent),
+		jobs:     make(map[string]*Job),
+	}, nil
+}
+
+// Start queues an export. notifyEmail, when set, is emailed once the file is ready.
+func (m *JobManager) Start(exp Export, requestedBy, notifyEmail string) *Job {
+	job := &Job{
+		ID:          uuid.New().String(),
+		Kind:        exp.Kind,
+		Filename:    exp.Filename,
+		Status:      JobStatusPending,
+		RequestedBy: requestedBy,
+		CreatedAt:   time.Now(),
+	}
+	job.path = filepath.Join(m.cfg.Dir, job.ID+".csv")
+
+	m.mu.Lock()
+	m.jobs[job.ID] = job
+	m.mu.Unlock()
+
+	go m.run(job, exp, notifyEmail, nil)
+	return m.snapshot(job)
+}
+
+// Get returns a job visible to the requester
+func (m *JobManager) Get(id, requestedBy string) (*Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, errors.NotFound("export job")
+	}
+	if job.RequestedBy != requestedBy {
+		return nil, errors.Forbidden("export job belongs to another user")
+	}
+	return m.snapshotLocked(job), nil
+}
+
+// List returns the requester's jobs, newest first
+func (m *JobManager) List(requestedBy string) []*Job {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jobs := make([]*Job, 0)
+	for _, job := range m.jobs {
+		if job.RequestedBy == requestedBy {
+			jobs = append(jobs, m.snapshotLocked(job))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs
+}
+
+// Open opens the file of a completed job visible to the requester
+func (m *JobManager) Open(id, requestedBy string) (*os.File, *Job, error) {
+	job, err := m.Get(id, requestedBy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != JobStatusCompleted {
+		return nil, nil, errors.Conflict(fmt.Sprintf("export job is %s", job.Status))
+	}
+	f, err := os.Open(job.path)
+	if err != nil {
+		return nil, nil, errors.InternalWrap(err, "failed to open export file")
+	}
+	return f, job, nil
+}
+
+// StartCleanup removes expired jobs and their files periodically until ctx is cancelled
+func (m *JobManager) StartCleanup(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.removeExpired(time.Now())
+			}
+		}
+	}()
+}
+
+func (m *JobManager) run(job *Job, exp Export, notifyEmail string, delivery *Delivery) {
+	m.slots <- struct{}{}
+	defer func() { <-m.slots }()
+
+	m.setStatus(job, JobStatusRunning, 0, nil)
+	// Exports stream their whole result from one query
+	ctx := database.WithQueryTimeout(context.Background(), 0)
+
+	rows, err := m.writeFile(ctx, job.path, exp)
+	if err != nil {
+		_ = os.Remove(job.path)
+		m.setStatus(job, JobStatusFailed, rows, err)
+		m.logger.WithError(err).WithField("job_id", job.ID).WithField("kind", job.Kind).Error("Export job failed")
+		return
+	}
+
+	if delivery != nil {
+		if err := m.deliver(ctx, job, exp, *delivery); err != nil {
+			m.setStatus(job, JobStatusFailed, rows, fmt.Errorf("delivery failed: %w", err))
+			m.logger.WithError(err).WithField("job_id", job.ID).WithField("kind", job.Kind).Error("Export delivery failed")
+			return
+		}
+	}
+
+	m.setStatus(job, JobStatusCompleted, rows, nil)
+	m.logger.WithField("job_id", job.ID).WithField("kind", job.Kind).WithField("rows", rows).Info("Export job completed")
+
+	if notifyEmail != "" && m.notifier != nil {
+		subject := fmt.Sprintf("Your %s export is ready", job.Kind)
+		body := fmt.Sprintf("Export %s (%d rows) can be downloaded from /admin/exports/jobs/%s/download until %s.",
+			job.ID, rows, job.ID, time.Now().Add(m.cfg.Retention).Format(time.RFC1123))
+		if err := m.notifier.SendEmail(ctx, notifyEmail, subject, body); err != nil {
+			m.logger.WithError(err).WithField("job_id", job.ID).Warn("Failed to notify export requester")
+		}
+	}
+}
+
+// writeFile writes an export to a temporary file and moves it into place once complete
+func (m *JobManager) writeFile(ctx context.Context, path string, exp Export) (int64, error) {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp)
+
+	w := &countingWriter{csv: csv.NewWriter(f)}
+	if err := w.csv.Write(exp.Header); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := exp.Rows(ctx, w); err != nil {
+		f.Close()
+		return w.rows, err
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		f.Close()
+		return w.rows, err
+	}
+	if err := f.Close(); err != nil {
+		return w.rows, err
+	}
+	return w.rows, os.Rename(tmp, path)
+}
+
+func (m *JobManager) setStatus(job *Job, status JobStatus, rows int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job.Status = status
+	job.Rows = rows
+	if err != nil {
+		job.Error = err.Error()
+	}
+	if status == JobStatusCompleted || status == JobStatusFailed {
+		now := time.Now()
+		expiresAt := now.Add(m.cfg.Retention)
+		job.CompletedAt = &now
+		job.ExpiresAt = &expiresAt
+	}
+}
+
+func (m *JobManager) removeExpired(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, job := range m.jobs {
+		if job.ExpiresAt != nil && now.After(*job.ExpiresAt) {
+			if err := os.Remove(job.path); err != nil && !os.IsNotExist(err) {
+				m.logger.WithError(err).WithField("job_id", id).Warn("Failed to remove expired export file")
+			}
+			delete(m.jobs, id)
+		}
+	}
+}
+
+func (m *JobManager) snapshot(job *Job) *Job {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.snapshotLocked(job)
+}
+
+func (m *JobManager) snapshotLocked(job *Job) *Job {
+	copied := *job
+	return &copied
+}
+
+// countingWriter writes rows to a CSV file and counts them
+type countingWriter struct {
+	csv  *csv.Writer
+	rows int64
+}
+
+func (w *countingWriter) Write(row []string) error {
+	if err := w.csv.Write(row); err != nil {
+		return err
+	}
+	w.rows++
+	return nil
+}
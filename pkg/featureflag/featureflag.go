@@ -0,0 +1,144 @@
+// Package featureflag holds runtime switches shared by the admin and storefront
+// servers: maintenance mode and kill switches turning off a subsystem such as
+// checkout or search. Flags are stored by the admin and each server reads a
+// snapshot refreshed periodically, so checking a flag never hits the store.
+package featureflag
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/snapshot"
+)
+
+// Known flags. Maintenance is off by default; kill switches are on, and
+// turning one off disables its subsystem.
+const (
+	Maintenance = "maintenance" // The storefront answers 503 with the maintenance page
+	Checkout    = "checkout"
+	Search      = "search"
+	Reviews     = "reviews"
+)
+
+// Definition describes a known flag
+type Definition struct {
+	Description string
+	Default     bool
+}
+
+// Definitions are the known flags by key
+var Definitions = map[string]Definition{
+	Maintenance: {Description: "Storefront maintenance mode; the admin stays up", Default: false},
+	Checkout:    {Description: "Checkout and payment of orders on the storefront", Default: true},
+	Search:      {Description: "Storefront product search", Default: true},
+	Reviews:     {Description: "Storefront product reviews", Default: true},
+}
+
+// IsKnown checks whether a key is a known flag
+func IsKnown(key string) bool {
+	_, ok := Definitions[key]
+	return ok
+}
+
+// Flag is the state of a flag
+type Flag struct {
+	Key       string    `json:"key"`
+	Enabled   bool      `json:"enabled"`
+	Note      string    `json:"note,omitempty"` // Why it was last switched
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Store persists flags that were switched; flags never switched keep their default
+type Store interface {
+	List(ctx context.Context) ([]*Flag, error)
+	Save(ctx context.Context, flag *Flag) error
+}
+
+// Flags is a snapshot of the stored flags over their defaults. It is safe for
+// concurrent use.
+type Flags struct {
+	store    Store
+	snapshot *snapshot.Snapshot[*Flag]
+}
+
+// New creates flags holding the defaults until the first Refresh
+func New(store Store, log *logger.Logger) *Flags {
+	f := &Flags{store: store}
+	f.snapshot = snapshot.New(snapshot.Config[*Flag]{
+		Name:    "feature flag",
+		Load:    f.load,
+		Changed: func(previous, current *Flag) bool { return previous.Enabled != current.Enabled },
+		Report: func(key string, flag *Flag) {
+			log.WithFields(logger.Fields{"flag": key, "enabled": flag.Enabled}).Info("Feature flag switched")
+		},
+	}, defaults(), log)
+	return f
+}
+
+// Enabled reports whether a flag is on; unknown flags are off
+func (f *Flags) Enabled(key string) bool {
+	flag, ok := f.snapshot.Get(key)
+	return ok && flag.Enabled
+}
+
+// List returns every known flag, ordered by key
+func (f *Flags) List() []*Flag {
+	values := f.snapshot.Values()
+	flags := make([]*Flag, 0, len(values))
+	for _, flag := range values {
+		copied := *flag
+		flags = append(flags, &copied)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	return flags
+}
+
+// Set switches a flag and stores it; the snapshot changes right away here and
+// on the other servers at their next refresh
+func (f *Flags) Set(ctx context.Context, key string, enabled bool, note, updatedBy string) (*Flag, error) {
+	flag := &Flag{Key: key, Enabled: enabled, Note: note, UpdatedBy: updatedBy, UpdatedAt: time.Now()}
+	if err := f.store.Save(ctx, flag); err != nil {
+		return nil, err
+	}
+
+	f.snapshot.Set(key, flag)
+	copied := *flag
+	return &copied, nil
+}
+
+// Refresh reloads the stored flags
+func (f *Flags) Refresh(ctx context.Context) error {
+	return f.snapshot.Refresh(ctx)
+}
+
+// StartScheduledRefresh refreshes the flags periodically until ctx is cancelled
+func (f *Flags) StartScheduledRefresh(ctx context.Context, interval time.Duration) {
+	f.snapshot.StartScheduledRefresh(ctx, interval)
+}
+
+// load reads the stored flags over the defaults. Stored flags that are no
+// longer known are ignored.
+func (f *Flags) load(ctx context.Context) (map[string]*Flag, error) {
+	stored, err := f.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	flags := defaults()
+	for _, flag := range stored {
+		if IsKnown(flag.Key) {
+			flags[flag.Key] = flag
+		}
+	}
+	return flags, nil
+}
+
+func defaults() map[string]*Flag {
+	flags := make(map[string]*Flag, len(Definitions))
+	for key, definition := range Definitions {
+		flags[key] = &Flag{Key: key, Enabled: definition.Default}
+	}
+	return flags
+}
@@ -0,0 +1,195 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// MaxMind DB data section types
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEndMarker = 13
+	typeBoolean   = 14
+	typeFloat     = 15
+)
+
+// maxDecodeDepth bounds nesting so a corrupt database cannot exhaust the stack
+const maxDecodeDepth = 64
+
+// decoder decodes values of a MaxMind DB data section. Maps decode to
+// map[string]interface{}, arrays to []interface{}, unsigned integers to uint64,
+// int32 to int64 and floating point numbers to float64.
+type decoder struct {
+	buffer []byte
+}
+
+// decode decodes the value at offset, returning it with the offset of the next value
+func (d decoder) decode(offset int) (interface{}, int, error) {
+	return d.decodeAt(offset, 0)
+}
+
+func (d decoder) decodeAt(offset, depth int) (interface{}, int, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, fmt.Errorf("data nested too deeply")
+	}
+	if offset < 0 || offset >= len(d.buffer) {
+		return nil, 0, fmt.Errorf("offset %d is outside the data section", offset)
+	}
+
+	control := d.buffer[offset]
+	offset++
+	dataType := int(control >> 5)
+
+	if dataType == typePointer {
+		pointer, next, err := d.pointer(control, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decodeAt(pointer, depth+1)
+		return value, next, err
+	}
+
+	if dataType == typeExtended {
+		if offset >= len(d.buffer) {
+			return nil, 0, fmt.Errorf("truncated extended type")
+		}
+		dataType = 7 + int(d.buffer[offset])
+		offset++
+	}
+
+	size, offset, err := d.size(control, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch dataType {
+	case typeMap:
+		values := make(map[string]interface{}, size)
+		for i := 0; i < size; i++ {
+			var key, value interface{}
+			if key, offset, err = d.decodeAt(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("map key is not a string")
+			}
+			if value, offset, err = d.decodeAt(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			values[name] = value
+		}
+		return values, offset, nil
+	case typeArray:
+		values := make([]interface{}, 0, size)
+		for i := 0; i < size; i++ {
+			var value interface{}
+			if value, offset, err = d.decodeAt(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, value)
+		}
+		return values, offset, nil
+	case typeBoolean:
+		return size != 0, offset, nil
+	case typeContainer, typeEndMarker:
+		return nil, offset, nil
+	}
+
+	if offset+size > len(d.buffer) {
+		return nil, 0, fmt.Errorf("value exceeds the data section")
+	}
+	raw := d.buffer[offset : offset+size]
+	next := offset + size
+
+	switch dataType {
+	case typeString:
+		return string(raw), next, nil
+	case typeBytes:
+		return append([]byte(nil), raw...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(raw)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(raw))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		return unsigned(raw), next, nil
+	case typeUint128:
+		if size > 8 {
+			raw = raw[size-8:] // Larger values than any lookup needs keep their low 64 bits
+		}
+		return unsigned(raw), next, nil
+	case typeInt32:
+		return int64(int32(uint32(unsigned(raw)))), next, nil
+	}
+	return nil, 0, fmt.Errorf("unknown data type %d", dataType)
+}
+
+// pointer reads a pointer into the data section
+func (d decoder) pointer(control byte, offset int) (int, int, error) {
+	pointerSize := int((control>>3)&0x3) + 1
+	if offset+pointerSize > len(d.buffer) {
+		return 0, 0, fmt.Errorf("truncated pointer")
+	}
+	b := d.buffer[offset : offset+pointerSize]
+	next := offset + pointerSize
+	value := uint(control & 0x7)
+
+	switch pointerSize {
+	case 1:
+		return int(value<<8 | uint(b[0])), next, nil
+	case 2:
+		return int(value<<16|uint(b[0])<<8|uint(b[1])) + 2048, next, nil
+	case 3:
+		return int(value<<24|uint(b[0])<<16|uint(b[1])<<8|uint(b[2])) + 526336, next, nil
+	default:
+		return int(binary.BigEndian.Uint32(b)), next, nil
+	}
+}
+
+// size reads the payload size of a value
+func (d decoder) size(control byte, offset int) (int, int, error) {
+	size := int(control & 0x1f)
+	if size < 29 {
+		return size, offset, nil
+	}
+	extra := size - 28
+	if offset+extra > len(d.buffer) {
+		return 0, 0, fmt.Errorf("truncated size")
+	}
+	b := d.buffer[offset : offset+extra]
+	switch size {
+	case 29:
+		return 29 + int(b[0]), offset + 1, nil
+	case 30:
+		return 285 + (int(b[0])<<8 | int(b[1])), offset + 2, nil
+	default:
+		return 65821 + (int(b[0])<<16 | int(b[1])<<8 | int(b[2])), offset + 3, nil
+	}
+}
+
+func unsigned(raw []byte) uint64 {
+	var value uint64
+	for _, b := range raw {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
@@ -0,0 +1,195 @@
+// Package geoip resolves the country and region of IP addresses from a MaxMind DB
+// file, such as the GeoLite2 or GeoIP2 Country and City databases. The database is
+// read into memory once; lookups are safe for concurrent use.
+package geoip
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+)
+
+// metadataMarker precedes the metadata at the end of a MaxMind DB file
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the size of the zeroed gap between the search tree and the data section
+const dataSectionSeparator = 16
+
+// Location is where an IP address is, as ISO codes; fields are "" when unknown
+type Location struct {
+	Country string // ISO 3166-1 alpha-2, e.g. "US"
+	Region  string // ISO 3166-2 subdivision without the country, e.g. "CA"; City databases only
+}
+
+// Reader looks up IP addresses in a MaxMind DB
+type Reader struct {
+	buffer       []byte
+	data         decoder
+	nodeCount    uint
+	recordSize   uint
+	ipVersion    uint
+	databaseType string
+	ipv4Start    uint // Node where IPv4 lookups start in an IPv6 tree
+}
+
+// Open reads a MaxMind DB file
+func Open(path string) (*Reader, error) {
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GeoIP database: %w", err)
+	}
+	return FromBytes(buffer)
+}
+
+// FromBytes creates a Reader from the contents of a MaxMind DB file
+func FromBytes(buffer []byte) (*Reader, error) {
+	markerAt := bytes.LastIndex(buffer, metadataMarker)
+	if markerAt < 0 {
+		return nil, fmt.Errorf("invalid GeoIP database: metadata not found")
+	}
+	metadataStart := markerAt + len(metadataMarker)
+	metadata, _, err := decoder{buffer: buffer[metadataStart:]}.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GeoIP database metadata: %w", err)
+	}
+	fields, ok := metadata.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid GeoIP database metadata")
+	}
+
+	r := &Reader{
+		buffer:       buffer,
+		nodeCount:    metadataUint(fields, "node_count"),
+		recordSize:   metadataUint(fields, "record_size"),
+		ipVersion:    metadataUint(fields, "ip_version"),
+		databaseType: metadataString(fields, "database_type"),
+	}
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("unsupported GeoIP record size %d", r.recordSize)
+	}
+	treeSize := int(r.nodeCount * r.recordSize / 4)
+	if treeSize+dataSectionSeparator > markerAt {
+		return nil, fmt.Errorf("invalid GeoIP database: search tree exceeds the file")
+	}
+	r.data = decoder{buffer: buffer[treeSize+dataSectionSeparator : markerAt]}
+
+	if r.ipVersion == 6 {
+		for i := 0; i < 96 && r.ipv4Start < r.nodeCount; i++ {
+			r.ipv4Start = r.readNode(r.ipv4Start, 0)
+		}
+	}
+	return r, nil
+}
+
+// DatabaseType returns the type of the database, e.g. "GeoLite2-Country"
+func (r *Reader) DatabaseType() string {
+	return r.databaseType
+}
+
+// Lookup finds the location of an IP address. Addresses missing from the
+// database resolve to an empty Location.
+func (r *Reader) Lookup(ip net.IP) (Location, error) {
+	record, err := r.lookupRecord(ip)
+	if err != nil || record == nil {
+		return Location{}, err
+	}
+
+	var location Location
+	fields, _ := record.(map[string]interface{})
+	location.Country = isoCode(fields["country"])
+	if location.Country == "" {
+		location.Country = isoCode(fields["registered_country"])
+	}
+	if subdivisions, ok := fields["subdivisions"].([]interface{}); ok && len(subdivisions) > 0 {
+		location.Region = isoCode(subdivisions[0])
+	}
+	return location, nil
+}
+
+// Locate returns the country and region of an IP address, or "" when they are unknown
+func (r *Reader) Locate(ip net.IP) (country, region string) {
+	location, err := r.Lookup(ip)
+	if err != nil {
+		return "", ""
+	}
+	return location.Country, location.Region
+}
+
+// lookupRecord walks the search tree along the bits of an IP address and
+// decodes the data record it ends at, or returns nil when there is none
+func (r *Reader) lookupRecord(ip net.IP) (interface{}, error) {
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address")
+	}
+	node := uint(0)
+	bits := ip.To4()
+	if bits != nil {
+		if r.ipVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else {
+		if r.ipVersion == 4 {
+			return nil, nil // IPv6 addresses are not in an IPv4 database
+		}
+		bits = ip.To16()
+	}
+
+	for i := 0; i < len(bits)*8 && node < r.nodeCount; i++ {
+		bit := uint(bits[i>>3]>>(7-uint(i&7))) & 1
+		node = r.readNode(node, bit)
+	}
+	if node == r.nodeCount {
+		return nil, nil
+	}
+	if node < r.nodeCount {
+		return nil, fmt.Errorf("invalid GeoIP database: search tree is too deep")
+	}
+
+	offset := int(node-r.nodeCount) - dataSectionSeparator
+	record, _, err := r.data.decode(offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GeoIP record: %w", err)
+	}
+	return record, nil
+}
+
+// readNode reads the left (bit 0) or right (bit 1) record of a search tree node
+func (r *Reader) readNode(node, bit uint) uint {
+	b := r.buffer[node*r.recordSize/4:]
+	switch r.recordSize {
+	case 24:
+		o := bit * 3
+		return uint(b[o])<<16 | uint(b[o+1])<<8 | uint(b[o+2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		o := bit * 4
+		return uint(b[o])<<24 | uint(b[o+1])<<16 | uint(b[o+2])<<8 | uint(b[o+3])
+	}
+}
+
+// isoCode reads the iso_code of a country or subdivision record
+func isoCode(value interface{}) string {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	code, _ := fields["iso_code"].(string)
+	return code
+}
+
+func metadataUint(fields map[string]interface{}, key string) uint {
+	if value, ok := fields[key].(uint64); ok {
+		return uint(value)
+	}
+	return 0
+}
+
+func metadataString(fields map[string]interface{}, key string) string {
+	value, _ := fields[key].(string)
+	return value
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+)
+
+// CSVWriter streams CSV rows to an HTTP response, flushing after every row batch
+type CSVWriter struct {
+	w       http.ResponseWriter
+	csv     *csv.Writer
+	pending int
+}
+
+// csvFlushEvery is the number of rows buffered before flushing to the client
+const csvFlushEvery = 500
+
+// NewCSVWriter sets download headers and writes the header row
+func NewCSVWriter(w http.ResponseWriter, filename string, header []string) (*CSVWriter, error) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	cw := &CSVWriter{w: w, csv: csv.NewWriter(w)}
+	if err := cw.csv.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+// Write writes a single row
+func (cw *CSVWriter) Write(row []string) error {
+	if err := cw.csv.Write(row); err != nil {
+		return err
+	}
+	cw.pending++
+	if cw.pending >= csvFlushEvery {
+		return cw.Flush()
+	}
+	return nil
+}
+
+// Flush sends buffered rows to the client
+func (cw *CSVWriter) Flush() error {
+	cw.pending = 0
+	cw.csv.Flush()
+	if flusher, ok := cw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return cw.csv.Error()
+}
+
+// WantsCSV checks whether the client asked for CSV via ?format=csv or the Accept header
+func WantsCSV(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "csv" {
+		return true
+	}
+	return r.Header.Get("Accept") == "text/csv"
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// HALMediaType is the media type of JSON responses carrying hypermedia links
+const HALMediaType = "application/hal+json"
+
+// WantsLinks checks whether the client asked for hypermedia links via
+// ?links=true or by accepting application/hal+json
+func WantsLinks(r *http.Request) bool {
+	if r.URL.Query().Get("links") == "true" {
+		return true
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted)); err == nil && mediaType == HALMediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// RespondHAL responds with JSON data carrying hypermedia links
+func RespondHAL(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", HALMediaType)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		logger.WithError(err).Error("Failed to encode JSON response")
+	}
+}
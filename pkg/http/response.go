@@ -1,9 +1,11 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
+	"github.com/qhato/ecommerce/pkg/errors"
 	"github.com/qhato/ecommerce/pkg/logger"
 )
 
@@ -87,8 +89,20 @@ func RespondError(w http.ResponseWriter, err error) {
 
 	// Determine status code based on error type
 	statusCode := http.StatusInternalServerError
-	if appErr, ok := err.(interface{ StatusCode() int }); ok {
-		statusCode = appErr.StatusCode()
+	var appErr *errors.AppError
+	if errors.Is(err, context.DeadlineExceeded) {
+		// The request ran out of time; the wrapped error would leak internals
+		statusCode = http.StatusGatewayTimeout
+		err = errors.GatewayTimeout("request timed out")
+	} else if errors.IsTimeout(err) {
+		// A query was cancelled by its deadline or with its request
+		timeout := errors.Timeout(err)
+		statusCode = timeout.StatusCode
+		err = errors.New(timeout.Code, timeout.Message, timeout.StatusCode)
+	} else if statusErr, ok := err.(interface{ StatusCode() int }); ok {
+		statusCode = statusErr.StatusCode()
+	} else if errors.As(err, &appErr) {
+		statusCode = appErr.StatusCode
 	}
 
 	w.WriteHeader(statusCode)
@@ -0,0 +1,276 @@
+// Package httpclient is the HTTP client used by adapters that call external
+// providers (payment, tax, shipping, email). It adds timeouts, retries with
+// jittered backoff, a circuit breaker per provider, redacted request logging
+// and trace context propagation.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/circuitbreaker"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/tracing"
+)
+
+// Config holds the settings of a provider client
+type Config struct {
+	Name             string // Provider name used in logs and breaker state
+	BaseURL          string
+	Timeout          time.Duration // Per attempt
+	MaxRetries       int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	BreakerThreshold int           // Consecutive failed calls that open the breaker
+	BreakerCooldown  time.Duration // Time the breaker stays open before probing
+	DefaultHeaders   map[string]string
+	RedactHeaders    []string // Added to the default sensitive headers
+	RedactFields     []string // Added to the default sensitive body and query fields
+	LogBodies        bool     // Log redacted request and response bodies at debug level
+}
+
+// DefaultConfig returns the default settings for a provider
+func DefaultConfig(name, baseURL string) Config {
+	return Config{
+		Name:             name,
+		BaseURL:          baseURL,
+		Timeout:          10 * time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   200 * time.Millisecond,
+		RetryMaxDelay:    2 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// Request is an outbound request
+type Request struct {
+	Method string
+	Path   string // Joined to the base URL unless absolute
+	Header http.Header
+	Body   []byte
+	// Idempotent allows retrying non-idempotent methods, e.g. a POST carrying an
+	// Idempotency-Key header
+	Idempotent bool
+}
+
+// Response is a fully read response
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// DecodeJSON unmarshals the response body
+func (r *Response) DecodeJSON(v interface{}) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// StatusError is returned for non-2xx responses
+type StatusError struct {
+	Provider string
+	Response *Response
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s responded with status %d", e.Provider, e.Response.StatusCode)
+}
+
+// Client calls a single external provider
+type Client struct {
+	cfg      Config
+	http     *http.Client
+	breaker  *circuitbreaker.Breaker
+	redactor *redactor
+	logger   *logger.Logger
+}
+
+// New creates a provider client
+func New(cfg Config, log *logger.Logger) *Client {
+	defaults := DefaultConfig(cfg.Name, cfg.BaseURL)
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = defaults.RetryBaseDelay
+	}
+	if cfg.RetryMaxDelay <= 0 {
+		cfg.RetryMaxDelay = defaults.RetryMaxDelay
+	}
+
+	log = log.WithField("provider", cfg.Name)
+	breaker := circuitbreaker.New(circuitbreaker.Config{
+		Name:             cfg.Name,
+		FailureThreshold: cfg.BreakerThreshold,
+		Cooldown:         cfg.BreakerCooldown,
+		OnStateChange: func(name string, from, to circuitbreaker.State) {
+			log.WithField("from", from).WithField("to", to).Warn("provider circuit breaker state changed")
+		},
+	})
+
+	return &Client{
+		cfg:      cfg,
+		http:     &http.Client{Timeout: cfg.Timeout},
+		breaker:  breaker,
+		redactor: newRedactor(cfg.RedactHeaders, cfg.RedactFields),
+		logger:   log,
+	}
+}
+
+// BreakerState returns the state of the provider's circuit breaker
+func (c *Client) BreakerState() circuitbreaker.State {
+	return c.breaker.State()
+}
+
+// Do sends a request, retrying transient failures. Non-2xx responses are
+// returned as *StatusError together with the response.
+func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
+	if !c.breaker.Allow() {
+		return nil, fmt.Errorf("%s: %w", c.cfg.Name, circuitbreaker.ErrOpen)
+	}
+
+	// All attempts of a call belong to the same trace
+	span, ok := tracing.FromContext(ctx)
+	if !ok {
+		span = tracing.NewRoot()
+	}
+
+	retryable := req.Idempotent || isIdempotentMethod(req.Method)
+	var resp *Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		resp, err = c.attempt(ctx, req, span, attempt)
+		if attempt >= c.cfg.MaxRetries || !retryable || !shouldRetry(resp, err) || ctx.Err() != nil {
+			break
+		}
+
+		delay := backoff(attempt, c.cfg.RetryBaseDelay, c.cfg.RetryMaxDelay, resp)
+		c.logger.WithField("attempt", attempt+1).WithField("delay_ms", delay.Milliseconds()).Debug("retrying provider request")
+		select {
+		case <-ctx.Done():
+			c.breaker.Failure()
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	// Client errors (4xx) mean the provider is healthy
+	if err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		c.breaker.Failure()
+	} else {
+		c.breaker.Success()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, &StatusError{Provider: c.cfg.Name, Response: resp}
+	}
+	return resp, nil
+}
+
+// DoJSON sends in as a JSON body (when not nil) and decodes the response into out (when not nil)
+func (c *Client) DoJSON(ctx context.Context, method, path string, in, out interface{}) error {
+	req := &Request{Method: method, Path: path, Header: http.Header{}}
+	if in != nil {
+		body, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		req.Body = body
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	if out != nil && len(resp.Body) > 0 {
+		return resp.DecodeJSON(out)
+	}
+	return nil
+}
+
+func (c *Client) attempt(ctx context.Context, req *Request, span tracing.SpanContext, attempt int) (*Response, error) {
+	url := req.Path
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = strings.TrimRight(c.cfg.BaseURL, "/") + "/" + strings.TrimLeft(req.Path, "/")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, url, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", c.cfg.Name, err)
+	}
+	for name, value := range c.cfg.DefaultHeaders {
+		httpReq.Header.Set(name, value)
+	}
+	for name, values := range req.Header {
+		httpReq.Header[name] = values
+	}
+	c.propagate(ctx, httpReq, span)
+
+	log := c.logger.WithFields(logger.Fields{
+		"method":  req.Method,
+		"url":     c.redactor.url(url),
+		"attempt": attempt + 1,
+	})
+	if c.cfg.LogBodies {
+		log.WithField("headers", c.redactor.headers(httpReq.Header)).
+			WithField("body", c.redactor.body(httpReq.Header.Get("Content-Type"), req.Body)).
+			Debug("provider request")
+	}
+
+	start := time.Now()
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		log.WithError(err).WithField("duration_ms", time.Since(start).Milliseconds()).Warn("provider request failed")
+		return nil, fmt.Errorf("%s request failed: %w", c.cfg.Name, err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", c.cfg.Name, err)
+	}
+	resp := &Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: body}
+
+	log = log.WithField("status", resp.StatusCode).WithField("duration_ms", time.Since(start).Milliseconds())
+	if c.cfg.LogBodies {
+		log = log.WithField("body", c.redactor.body(httpResp.Header.Get("Content-Type"), body))
+	}
+	if resp.StatusCode >= 500 {
+		log.Warn("provider request completed with server error")
+	} else {
+		log.Debug("provider request completed")
+	}
+	return resp, nil
+}
+
+// propagate forwards the trace context and correlation ID of the inbound request
+func (c *Client) propagate(ctx context.Context, req *http.Request, span tracing.SpanContext) {
+	tracing.Inject(req.Header, span.Child())
+	if correlationID, ok := ctx.Value("correlation_id").(string); ok && correlationID != "" {
+		req.Header.Set("X-Correlation-ID", correlationID)
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
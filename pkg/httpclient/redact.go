@@ -0,0 +1,115 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	defaultSensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+	defaultSensitiveFields  = []string{
+		"password", "secret", "token", "access_token", "refresh_token", "api_key", "client_secret",
+		"card_number", "number", "cvv", "cvc", "account_number", "routing_number",
+	}
+)
+
+// redactor masks credentials and payment data before they are logged
+type redactor struct {
+	sensitiveHeaders map[string]bool
+	sensitiveFields  map[string]bool
+}
+
+func newRedactor(headers, fields []string) *redactor {
+	r := &redactor{sensitiveHeaders: make(map[string]bool), sensitiveFields: make(map[string]bool)}
+	for _, name := range append(defaultSensitiveHeaders, headers...) {
+		r.sensitiveHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+	for _, name := range append(defaultSensitiveFields, fields...) {
+		r.sensitiveFields[strings.ToLower(name)] = true
+	}
+	return r
+}
+
+// headers returns a copy of the headers with sensitive values masked
+func (r *redactor) headers(header http.Header) map[string]string {
+	masked := make(map[string]string, len(header))
+	for name, values := range header {
+		if r.sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			masked[name] = redacted
+			continue
+		}
+		masked[name] = strings.Join(values, ", ")
+	}
+	return masked
+}
+
+// url masks sensitive query parameters
+func (r *redactor) url(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.RawQuery == "" {
+		return rawURL
+	}
+	parsed.RawQuery = r.form(parsed.Query()).Encode()
+	return parsed.String()
+}
+
+// body masks sensitive fields of JSON and form-encoded bodies; other content
+// types are omitted
+func (r *redactor) body(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	switch {
+	case strings.Contains(contentType, "json"):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return "[unparseable JSON body]"
+		}
+		masked, _ := json.Marshal(r.json(value))
+		return string(masked)
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "[unparseable form body]"
+		}
+		return r.form(values).Encode()
+	default:
+		return "[omitted " + contentType + " body]"
+	}
+}
+
+func (r *redactor) json(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if r.sensitiveFields[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = r.json(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.json(item)
+		}
+	}
+	return value
+}
+
+// form masks sensitive form fields, including nested keys such as card[number]
+func (r *redactor) form(values url.Values) url.Values {
+	for key := range values {
+		name := strings.ToLower(key)
+		if i := strings.LastIndex(name, "["); i >= 0 {
+			name = strings.TrimSuffix(name[i+1:], "]")
+		}
+		if r.sensitiveFields[name] {
+			values[key] = []string{redacted}
+		}
+	}
+	return values
+}
@@ -0,0 +1,39 @@
+package httpclient
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// shouldRetry reports whether an attempt failed transiently
+func shouldRetry(resp *Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the next attempt: the provider's Retry-After
+// when given, otherwise exponential backoff with full jitter
+func backoff(attempt int, base, max time.Duration, resp *Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			if delay := time.Duration(seconds) * time.Second; delay < max {
+				return delay
+			}
+			return max
+		}
+	}
+
+	ceiling := base << attempt
+	if ceiling <= 0 || ceiling > max {
+		ceiling = max
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
@@ -0,0 +1,225 @@
+// Package i18n translates the text of documents and emails into the customer's
+// language. Messages are kept by key in per-locale catalogs and may hold named
+// placeholders, e.g. "Invoice {number}". A message that depends on a count has a
+// text per CLDR plural category under its key, e.g. "lines.one" and
+// "lines.other", and the count fills {count}.
+//
+// A message missing from a locale is looked up in the locale's language ("fr"
+// for "fr-CA"), then in the default locale and its language; a key found
+// nowhere renders as itself, so missing translations show up in the output.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+
+	"github.com/qhato/ecommerce/pkg/requestctx"
+)
+
+// pluralCategories names the CLDR plural categories as message keys end with them
+var pluralCategories = map[plural.Form]string{
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+	plural.Other: "other",
+}
+
+// Catalog holds the messages of each locale. It is safe for concurrent use;
+// a nil catalog translates every key to itself.
+type Catalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	locales       []string                     // As added, for Locales
+	messages      map[string]map[string]string // Normalized locale -> key -> text
+}
+
+// NewCatalog creates a catalog without messages falling back to defaultLocale
+func NewCatalog(defaultLocale string) *Catalog {
+	return &Catalog{
+		defaultLocale: defaultLocale,
+		messages:      make(map[string]map[string]string),
+	}
+}
+
+// Add adds messages to a locale, replacing those with the same keys
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := normalize(locale)
+	texts, ok := c.messages[key]
+	if !ok {
+		texts = make(map[string]string, len(messages))
+		c.messages[key] = texts
+		c.locales = append(c.locales, locale)
+	}
+	for k, text := range messages {
+		texts[k] = text
+	}
+}
+
+// Locales returns the locales with messages, in the order they were added
+func (c *Catalog) Locales() []string {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.locales...)
+}
+
+// DefaultLocale returns the locale messages fall back to
+func (c *Catalog) DefaultLocale() string {
+	if c == nil {
+		return ""
+	}
+	return c.defaultLocale
+}
+
+// Match returns the first candidate locale with messages of its own or of its
+// language, or the default locale when none has any
+func (c *Catalog) Match(candidates ...string) string {
+	if c == nil {
+		return ""
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		key := normalize(candidate)
+		if _, ok := c.messages[key]; ok {
+			return candidate
+		}
+		if _, ok := c.messages[baseLanguage(key)]; ok {
+			return candidate
+		}
+	}
+	return c.defaultLocale
+}
+
+// Negotiate returns the locale to render a request in: the storefront locale of
+// the request, else the first fallback with messages, e.g. the locale an order was
+// placed in, else the default locale
+func (c *Catalog) Negotiate(ctx context.Context, fallbacks ...string) string {
+	return c.Match(append([]string{requestctx.Locale(ctx)}, fallbacks...)...)
+}
+
+// Translate returns the message of a key in a locale with its placeholders filled
+// from name/value pairs, e.g. Translate("es-ES", "invoice.title", "number", "A-1")
+func (c *Catalog) Translate(locale, key string, args ...interface{}) string {
+	if c == nil {
+		return key
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, loc := range c.chain(locale) {
+		if text, ok := c.messages[loc][key]; ok {
+			return fill(text, args)
+		}
+	}
+	return key
+}
+
+// Plural returns the message of a key for a count in a locale: the text of the
+// count's plural category in that locale, else its "other" text, else the key's
+// own text. The count fills {count} and args fill the other placeholders.
+func (c *Catalog) Plural(locale, key string, count int, args ...interface{}) string {
+	if c == nil {
+		return key
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	args = append([]interface{}{"count", count}, args...)
+	for _, loc := range c.chain(locale) {
+		texts := c.messages[loc]
+		if texts == nil {
+			continue
+		}
+		if text, ok := texts[key+"."+pluralCategory(loc, count)]; ok {
+			return fill(text, args)
+		}
+		if text, ok := texts[key+".other"]; ok {
+			return fill(text, args)
+		}
+		if text, ok := texts[key]; ok {
+			return fill(text, args)
+		}
+	}
+	return key
+}
+
+// chain returns the normalized locales a message of a locale is looked up in
+func (c *Catalog) chain(locale string) []string {
+	chain := make([]string, 0, 4)
+	for _, loc := range []string{locale, c.defaultLocale} {
+		if loc == "" {
+			continue
+		}
+		key := normalize(loc)
+		for _, candidate := range []string{key, baseLanguage(key)} {
+			if !contains(chain, candidate) {
+				chain = append(chain, candidate)
+			}
+		}
+	}
+	return chain
+}
+
+// pluralCategory returns the CLDR plural category of a count in a locale
+func pluralCategory(locale string, count int) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		tag = language.English
+	}
+	n := count
+	if n < 0 {
+		n = -n
+	}
+	return pluralCategories[plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0)]
+}
+
+// fill replaces the {name} placeholders of a text with the values of name/value pairs
+func fill(text string, args []interface{}) string {
+	if len(args) < 2 || !strings.Contains(text, "{") {
+		return text
+	}
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// normalize returns the lookup key of a locale: lower case with hyphens, "pt-br" for "pt_BR"
+func normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// baseLanguage returns the language of a normalized locale, "fr" for "fr-ca"
+func baseLanguage(locale string) string {
+	if i := strings.Index(locale, "-"); i > 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// LoadDir adds the message files of a directory, one per locale named after it,
+// e.g. "es-ES.toml" or "fr.json". Tables nest keys, so the TOML
+//
+//	[invoice]
+//	title = "Factura {number}"
+//	[invoice.lines]
+//	one = "{count} artículo"
+//	other = "{count} artículos"
+//
+// holds the keys invoice.title, invoice.lines.one and invoice.lines.other.
+func (c *Catalog) LoadDir(dir string) error {
+	var paths []string
+	for _, pattern := range []string{"*.toml", "*.json"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read messages: %w", err)
+		}
+		name := filepath.Base(path)
+		ext := filepath.Ext(name)
+		if err := c.Load(strings.TrimSuffix(name, ext), ext, data); err != nil {
+			return fmt.Errorf("failed to load messages %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Load adds the messages of a locale from a TOML or JSON document, by its
+// extension ".toml" or ".json"
+func (c *Catalog) Load(locale, ext string, data []byte) error {
+	tree := make(map[string]interface{})
+	switch strings.ToLower(ext) {
+	case ".toml":
+		if err := toml.Unmarshal(data, &tree); err != nil {
+			return err
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported message file type %q", ext)
+	}
+
+	messages := make(map[string]string)
+	if err := flatten("", tree, messages); err != nil {
+		return err
+	}
+	c.Add(locale, messages)
+	return nil
+}
+
+// flatten adds the texts of a tree of tables to messages under their dotted keys
+func flatten(prefix string, tree map[string]interface{}, messages map[string]string) error {
+	for name, value := range tree {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		switch v := value.(type) {
+		case string:
+			messages[key] = v
+		case map[string]interface{}:
+			if err := flatten(key, v, messages); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %s is not text", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package i18n
+
+import "text/template"
+
+// TemplateFuncs returns template helpers translating into a locale:
+//
+//	{{ t "invoice.title" "number" .Number }} -> "Factura A-1001"
+//	{{ tn "invoice.lines" (len .Lines) }}    -> "3 artículos"
+func TemplateFuncs(c *Catalog, locale string) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...interface{}) string {
+			return c.Translate(locale, key, args...)
+		},
+		"tn": func(key string, count int, args ...interface{}) string {
+			return c.Plural(locale, key, count, args...)
+		},
+	}
+}
@@ -0,0 +1,226 @@
+// Package loadshed rejects low-priority requests before a server and its
+// database saturate, so checkout and payments keep their capacity under a
+// crawler storm or a browsing spike. Saturation is the highest of three
+// signals: requests in flight against the most the server should run at once,
+// the share of database connections in use, and the recent average latency of
+// each route group against its target. Low-priority requests are rejected from
+// one threshold, normal ones from a higher one, and critical ones never.
+package loadshed
+
+import (
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metrics is published at /debug/vars as "load_shedding", keyed by route group
+var metrics = expvar.NewMap("load_shedding")
+
+const (
+	// DefaultGroup holds the requests of no route group
+	DefaultGroup = "default"
+
+	// latencyWeight is the weight of the latest request in the average latency of its group
+	latencyWeight = 0.1
+
+	// latencyStale ignores the average latency of a group without recent requests,
+	// so shedding stops once the traffic that slowed the group is gone
+	latencyStale = 10 * time.Second
+)
+
+// Priority sets how long the requests of a route group are served as saturation rises
+type Priority int
+
+const (
+	PriorityLow      Priority = iota // Rejected first, e.g. crawlers and anonymous browsing
+	PriorityNormal                   // Rejected close to full saturation
+	PriorityCritical                 // Never rejected, e.g. checkout and payments
+)
+
+// ParsePriority parses critical, normal or low
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(s) {
+	case "low":
+		return PriorityLow, nil
+	case "normal", "":
+		return PriorityNormal, nil
+	case "critical":
+		return PriorityCritical, nil
+	default:
+		return PriorityNormal, fmt.Errorf("unknown priority %q, expected critical, normal or low", s)
+	}
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// Config holds the saturation limits of a shedder
+type Config struct {
+	Enabled         bool                     // Off, requests are only measured
+	MaxInFlight     int                      // Requests in flight at full saturation; 0 ignores them
+	LowThreshold    float64                  // Saturation from which low-priority requests are rejected
+	NormalThreshold float64                  // Saturation from which normal requests are rejected
+	RetryAfter      time.Duration            // Suggested to rejected clients
+	LatencyTargets  map[string]time.Duration // Average latency of a group at full saturation, by group
+	PoolUsage       func() float64           // Share of database connections in use; nil ignores the database
+}
+
+// Shedder tracks the requests in flight and the latency of each route group,
+// and decides which requests are served
+type Shedder struct {
+	cfg Config
+
+	mu       sync.Mutex
+	inFlight int64
+	groups   map[string]*groupStats
+}
+
+// groupStats holds the measurements of a route group
+type groupStats struct {
+	inFlight  int64
+	latencyMs float64 // Exponentially weighted average
+	updatedAt time.Time
+}
+
+// New creates a shedder
+func New(cfg Config) *Shedder {
+	if cfg.LowThreshold <= 0 {
+		cfg.LowThreshold = 0.8
+	}
+	if cfg.NormalThreshold <= 0 {
+		cfg.NormalThreshold = 0.95
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = 5 * time.Second
+	}
+	return &Shedder{cfg: cfg, groups: make(map[string]*groupStats)}
+}
+
+// Admit decides whether a request of a group is served. Admitted requests are
+// counted in flight until release is called once they complete.
+func (s *Shedder) Admit(group string, priority Priority) (release func(), admitted bool) {
+	s.mu.Lock()
+	saturation := s.saturationLocked(time.Now())
+	if s.cfg.Enabled && saturation >= s.threshold(priority) {
+		s.mu.Unlock()
+		metrics.Add(group+".shed", 1)
+		return nil, false
+	}
+	stats := s.group(group)
+	stats.inFlight++
+	s.inFlight++
+	s.setInFlightLocked(group, stats)
+	s.mu.Unlock()
+
+	start := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.complete(group, time.Since(start)) })
+	}, true
+}
+
+// InFlight returns the requests being served
+func (s *Shedder) InFlight() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inFlight
+}
+
+// Saturation returns the current saturation, 1 being full
+func (s *Shedder) Saturation() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saturationLocked(time.Now())
+}
+
+// RetryAfter returns how long rejected clients should wait before retrying
+func (s *Shedder) RetryAfter() time.Duration {
+	return s.cfg.RetryAfter
+}
+
+func (s *Shedder) threshold(priority Priority) float64 {
+	switch priority {
+	case PriorityLow:
+		return s.cfg.LowThreshold
+	case PriorityNormal:
+		return s.cfg.NormalThreshold
+	default:
+		return 1e9 // Never reached
+	}
+}
+
+// complete records the latency of a request and takes it out of flight
+func (s *Shedder) complete(group string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.group(group)
+	stats.inFlight--
+	s.inFlight--
+	ms := float64(latency) / float64(time.Millisecond)
+	if stats.updatedAt.IsZero() || time.Since(stats.updatedAt) > latencyStale {
+		stats.latencyMs = ms
+	} else {
+		stats.latencyMs += latencyWeight * (ms - stats.latencyMs)
+	}
+	stats.updatedAt = time.Now()
+
+	s.setInFlightLocked(group, stats)
+	metrics.Add(group+".served", 1)
+	setFloat(group+".latency_ms", stats.latencyMs)
+}
+
+func (s *Shedder) saturationLocked(now time.Time) float64 {
+	var saturation float64
+	if s.cfg.MaxInFlight > 0 {
+		saturation = float64(s.inFlight) / float64(s.cfg.MaxInFlight)
+	}
+	if s.cfg.PoolUsage != nil {
+		saturation = max(saturation, s.cfg.PoolUsage())
+	}
+	for name, target := range s.cfg.LatencyTargets {
+		stats, ok := s.groups[name]
+		if !ok || target <= 0 || now.Sub(stats.updatedAt) > latencyStale {
+			continue
+		}
+		saturation = max(saturation, stats.latencyMs/(float64(target)/float64(time.Millisecond)))
+	}
+	setFloat("saturation", saturation)
+	return saturation
+}
+
+func (s *Shedder) group(name string) *groupStats {
+	stats, ok := s.groups[name]
+	if !ok {
+		stats = &groupStats{}
+		s.groups[name] = stats
+	}
+	return stats
+}
+
+func (s *Shedder) setInFlightLocked(group string, stats *groupStats) {
+	setInt(group+".in_flight", stats.inFlight)
+	setInt("in_flight", s.inFlight)
+}
+
+func setInt(key string, value int64) {
+	gauge := new(expvar.Int)
+	gauge.Set(value)
+	metrics.Set(key, gauge)
+}
+
+func setFloat(key string, value float64) {
+	gauge := new(expvar.Float)
+	gauge.Set(value)
+	metrics.Set(key, gauge)
+}
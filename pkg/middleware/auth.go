@@ -19,46 +19,87 @@ const (
 	UserEmailKey contextKey = "user_email"
 	// UserRolesKey is the context key for user roles
 	UserRolesKey contextKey = "user_roles"
+	// SessionIDKey is the context key for the server-side session ID
+	SessionIDKey contextKey = "session_id"
+	// ChannelKey is the context key for the sales channel of an integration
+	ChannelKey contextKey = "channel"
 )
 
+// SessionValidator checks the server-side session behind an authenticated token
+type SessionValidator interface {
+	// ValidateSession returns an error when the session is revoked, expired or idle
+	ValidateSession(ctx context.Context, claims *auth.Claims) error
+}
+
 // JWTAuth creates a middleware that validates JWT tokens
 func JWTAuth(jwtService *auth.JWTService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Extract token from Authorization header
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				errors.HandleHTTPError(w, errors.Unauthorized("Missing authorization header"))
-				return
-			}
-
-			// Check Bearer prefix
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				errors.HandleHTTPError(w, errors.Unauthorized("Invalid authorization header format"))
+			claims, err := authenticate(r, jwtService)
+			if err != nil {
+				errors.HandleHTTPError(w, err)
 				return
 			}
 
-			tokenString := parts[1]
+			// Continue with enriched context
+			ctx := withClaims(r.Context(), claims)
+			setRequestActor(ctx, claims.UserID, claims.Email, "")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
 
-			// Validate token
-			claims, err := jwtService.ValidateToken(tokenString)
+// SessionJWTAuth is like JWTAuth but also rejects tokens whose server-side
+// session has been revoked or has expired
+func SessionJWTAuth(jwtService *auth.JWTService, sessions SessionValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := authenticate(r, jwtService)
 			if err != nil {
-				errors.HandleHTTPError(w, errors.Unauthorized("Invalid or expired token"))
+				errors.HandleHTTPError(w, err)
 				return
 			}
 
-			// Add claims to context
-			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-			ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
-			ctx = context.WithValue(ctx, UserRolesKey, claims.Roles)
+			if err := sessions.ValidateSession(r.Context(), claims); err != nil {
+				errors.HandleHTTPError(w, errors.Unauthorized("Session is no longer valid"))
+				return
+			}
 
-			// Continue with enriched context
+			ctx := context.WithValue(withClaims(r.Context(), claims), SessionIDKey, claims.ID)
+			setRequestActor(ctx, claims.UserID, claims.Email, claims.ID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// authenticate extracts the bearer token from the Authorization header and
+// validates it
+func authenticate(r *http.Request, jwtService *auth.JWTService) (*auth.Claims, error) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return nil, errors.Unauthorized("Missing authorization header")
+	}
+
+	// Check Bearer prefix
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, errors.Unauthorized("Invalid authorization header format")
+	}
+
+	claims, err := jwtService.ValidateToken(parts[1])
+	if err != nil {
+		return nil, errors.Unauthorized("Invalid or expired token")
+	}
+	return claims, nil
+}
+
+// withClaims adds the identity of validated claims to the context
+func withClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
+	return context.WithValue(ctx, UserRolesKey, claims.Roles)
+}
+
 // RequireRole creates a middleware that checks if user has required role
 func RequireRole(requiredRole string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -89,38 +130,73 @@ func RequireRole(requiredRole string) func(http.Handler) http.Handler {
 	}
 }
 
-// OptionalJWTAuth is like JWTAuth but doesn't fail if no token is provided
-func OptionalJWTAuth(jwtService *auth.JWTService) func(http.Handler) http.Handler {
+// PermissionChecker resolves the permissions of authenticated users
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, userID string, permission string) (bool, error)
+}
+
+// RequirePermission creates a middleware that checks if the authenticated user
+// holds a permission. It must run after the authentication middleware.
+func RequirePermission(checker PermissionChecker, permission string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				next.ServeHTTP(w, r)
+			userID := GetUserID(r.Context())
+			if userID == "" {
+				errors.HandleHTTPError(w, errors.Unauthorized("User not authenticated"))
 				return
 			}
 
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				next.ServeHTTP(w, r)
+			granted, err := checker.HasPermission(r.Context(), userID, permission)
+			if err != nil {
+				errors.HandleHTTPError(w, errors.InternalWrap(err, "failed to check permissions"))
+				return
+			}
+			if !granted {
+				errors.HandleHTTPError(w, errors.Forbidden("Missing permission "+permission))
 				return
 			}
 
-			tokenString := parts[1]
-			claims, err := jwtService.ValidateToken(tokenString)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// OptionalJWTAuth is like JWTAuth but doesn't fail if no token is provided
+func OptionalJWTAuth(jwtService *auth.JWTService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := authenticate(r, jwtService)
 			if err != nil {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-			ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
-			ctx = context.WithValue(ctx, UserRolesKey, claims.Roles)
-
+			ctx := withClaims(r.Context(), claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// ChannelAuth creates a middleware that authenticates the integration of an
+// external sales channel by the API key it sends as a bearer token
+func ChannelAuth(keys *auth.ChannelKeys) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				errors.HandleHTTPError(w, errors.Unauthorized("Missing channel API key"))
+				return
+			}
+			channel := keys.Authenticate(apiKey)
+			if channel == "" {
+				errors.HandleHTTPError(w, errors.Unauthorized("Invalid channel API key"))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ChannelKey, channel)))
+		})
+	}
+}
+
 // GetUserID extracts user ID from context
 func GetUserID(ctx context.Context) string {
 	userID, _ := ctx.Value(UserIDKey).(string)
@@ -138,3 +214,15 @@ func GetUserRoles(ctx context.Context) []string {
 	roles, _ := ctx.Value(UserRolesKey).([]string)
 	return roles
 }
+
+// GetSessionID extracts the server-side session ID from context
+func GetSessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value(SessionIDKey).(string)
+	return sessionID
+}
+
+// GetChannel extracts the sales channel of an authenticated integration from context
+func GetChannel(ctx context.Context) string {
+	channel, _ := ctx.Value(ChannelKey).(string)
+	return channel
+}
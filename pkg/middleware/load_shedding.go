@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/loadshed"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// LoadSheddingGroup is a route group measured and shed together
+type LoadSheddingGroup struct {
+	Name         string
+	Priority     loadshed.Priority
+	AnonymousLow bool // Anonymous GET and HEAD requests are low priority, e.g. browsing
+	Routes       []LoadSheddingRoute
+}
+
+// LoadSheddingRoute matches the requests of a group
+type LoadSheddingRoute struct {
+	Method     string // Empty matches any method
+	PathPrefix string // e.g. "/orders"
+}
+
+// LoadSheddingConfig holds the route groups of the load shedding middleware
+type LoadSheddingConfig struct {
+	Groups        []LoadSheddingGroup // The longest matching prefix wins
+	CrawlerAgents []string            // User-Agent substrings of crawlers, which are low priority
+	BypassPaths   []string            // Served whatever the saturation, e.g. health checks
+}
+
+// LoadShedding measures the requests in flight and the latency of each route
+// group, and rejects requests with 503 and Retry-After once the server is too
+// saturated for their priority. Crawlers outside critical groups are low
+// priority, as are anonymous reads in groups marked so.
+func LoadShedding(shedder *loadshed.Shedder, cfg LoadSheddingConfig) func(http.Handler) http.Handler {
+	crawlerAgents := make([]string, len(cfg.CrawlerAgents))
+	for i, agent := range cfg.CrawlerAgents {
+		crawlerAgents[i] = strings.ToLower(agent)
+	}
+	bypass := make(map[string]bool, len(cfg.BypassPaths))
+	for _, p := range cfg.BypassPaths {
+		bypass[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if bypass[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			group, priority := cfg.classify(r, crawlerAgents)
+			release, admitted := shedder.Admit(group, priority)
+			if !admitted {
+				logger.WithFields(logger.Fields{
+					"group":    group,
+					"priority": priority.String(),
+					"path":     r.URL.Path,
+				}).Debug("Request shed")
+				w.Header().Set("Retry-After", strconv.Itoa(int(shedder.RetryAfter().Seconds())))
+				w.Header().Set("Cache-Control", "no-store")
+				errors.HandleHTTPError(w, errors.ServiceUnavailable("the store is busy, please retry shortly").
+					WithDetail("priority", priority.String()))
+				return
+			}
+			defer release()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// classify returns the group of a request and its priority
+func (cfg LoadSheddingConfig) classify(r *http.Request, crawlerAgents []string) (string, loadshed.Priority) {
+	var group *LoadSheddingGroup
+	matched := -1
+	for i := range cfg.Groups {
+		for _, route := range cfg.Groups[i].Routes {
+			if route.Method != "" && !strings.EqualFold(route.Method, r.Method) {
+				continue
+			}
+			if strings.HasPrefix(r.URL.Path, route.PathPrefix) && len(route.PathPrefix) > matched {
+				matched = len(route.PathPrefix)
+				group = &cfg.Groups[i]
+			}
+		}
+	}
+	if group == nil {
+		group = &LoadSheddingGroup{Name: loadshed.DefaultGroup, Priority: loadshed.PriorityNormal}
+	}
+
+	if group.Priority == loadshed.PriorityCritical {
+		return group.Name, group.Priority
+	}
+	userAgent := strings.ToLower(r.UserAgent())
+	for _, agent := range crawlerAgents {
+		if agent != "" && strings.Contains(userAgent, agent) {
+			return group.Name, loadshed.PriorityLow
+		}
+	}
+	if group.AnonymousLow && (r.Method == http.MethodGet || r.Method == http.MethodHead) && GetUserID(r.Context()) == "" {
+		return group.Name, loadshed.PriorityLow
+	}
+	return group.Name, group.Priority
+}
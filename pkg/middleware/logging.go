@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/tracing"
 )
 
 // responseWriter wraps http.ResponseWriter to capture status code
@@ -34,6 +35,18 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Flush implements http.Flusher so streaming handlers keep working behind the logger
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying connection
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // RequestLogger logs HTTP requests
 func RequestLogger() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -46,8 +59,10 @@ func RequestLogger() func(http.Handler) http.Handler {
 				correlationID = uuid.New().String()
 			}
 
-			// Add correlation ID to context
+			// Add correlation ID and trace context to context so outbound calls propagate them
+			span := tracing.Extract(r)
 			ctx := context.WithValue(r.Context(), "correlation_id", correlationID)
+			ctx = tracing.WithSpan(ctx, span)
 			r = r.WithContext(ctx)
 
 			// Add correlation ID to response header
@@ -59,6 +74,7 @@ func RequestLogger() func(http.Handler) http.Handler {
 			// Log request
 			logger.WithFields(logger.Fields{
 				"correlation_id": correlationID,
+				"trace_id":       span.TraceID,
 				"method":         r.Method,
 				"path":           r.URL.Path,
 				"query":          r.URL.RawQuery,
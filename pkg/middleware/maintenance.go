@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/featureflag"
+	"github.com/qhato/ecommerce/pkg/settings"
+)
+
+// MaintenanceBypassHeader lets staff through maintenance mode when it holds the bypass token
+const MaintenanceBypassHeader = "X-Maintenance-Bypass"
+
+// FlagChecker reports whether a runtime flag is on; *featureflag.Flags implements it
+type FlagChecker interface {
+	Enabled(key string) bool
+}
+
+// MaintenanceConfig holds the maintenance mode settings of a server
+type MaintenanceConfig struct {
+	Flags       FlagChecker
+	Page        []byte             // HTML answered to browsers; nil uses MaintenancePage
+	StoreName   string             // Shown on the built-in page
+	Settings    *settings.Settings // Store name and support email of the built-in page, read on each request; nil uses StoreName
+	RetryAfter  time.Duration      // Retry-After of the 503 responses; 0 omits it
+	BypassPaths []string           // Path prefixes served anyway, e.g. health checks
+	BypassToken string             // MaintenanceBypassHeader value served anyway; empty disables it
+}
+
+// Maintenance answers every request with 503 while the maintenance flag is
+// on: browsers get the maintenance page and API clients a structured error.
+// The flag is read on each request, so switching it takes effect at the next
+// flag refresh without a restart.
+func Maintenance(cfg MaintenanceConfig) func(http.Handler) http.Handler {
+	page := cfg.Page
+	if page == nil && cfg.Settings == nil {
+		page = MaintenancePage(cfg.StoreName, "")
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.Flags.Enabled(featureflag.Maintenance) || cfg.bypass(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if cfg.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(cfg.RetryAfter.Seconds())))
+			}
+			w.Header().Set("Cache-Control", "no-store")
+			if strings.Contains(r.Header.Get("Accept"), "text/html") {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				if page == nil {
+					w.Write(MaintenancePage(cfg.Settings.String(settings.StoreName), cfg.Settings.String(settings.SupportEmail)))
+					return
+				}
+				w.Write(page)
+				return
+			}
+			errors.HandleHTTPError(w, errors.ServiceUnavailable("the store is down for maintenance").
+				WithDetail("reason", featureflag.Maintenance))
+		})
+	}
+}
+
+// bypass checks whether a request is served during maintenance
+func (cfg MaintenanceConfig) bypass(r *http.Request) bool {
+	if cfg.BypassToken != "" && r.Header.Get(MaintenanceBypassHeader) == cfg.BypassToken {
+		return true
+	}
+	for _, prefix := range cfg.BypassPaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenancePage renders the built-in maintenance page of a store, with its
+// support address when there is one
+func MaintenancePage(storeName, supportEmail string) []byte {
+	name := html.EscapeString(storeName)
+	contact := ""
+	if supportEmail != "" {
+		email := html.EscapeString(supportEmail)
+		contact = fmt.Sprintf("\n<p>Questions about an order? Write to <a href=\"mailto:%s\">%s</a>.</p>", email, email)
+	}
+	return []byte(fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>%s - Down for maintenance</title>
+<style>
+body{margin:0;min-height:100vh;display:flex;align-items:center;justify-content:center;font-family:system-ui,sans-serif;background:#f6f6f4;color:#222}
+main{max-width:32rem;padding:2rem;text-align:center}
+h1{font-size:1.75rem;margin:0 0 .5rem}
+p{line-height:1.5;color:#555}
+</style>
+</head>
+<body>
+<main>
+<h1>%s</h1>
+<p>We are making some improvements and will be back shortly. Thank you for your patience.</p>%s
+</main>
+</body>
+</html>
+`, name, name, contact))
+}
+
+// KillSwitchRoute turns off the routes under a path prefix while a flag is off
+type KillSwitchRoute struct {
+	Flag       string // e.g. featureflag.Checkout
+	Method     string // Empty matches any method
+	PathPrefix string // e.g. "/orders/pay-links/"
+}
+
+// KillSwitches answers 503 on the routes of subsystems whose flag is off,
+// leaving the rest of the storefront up
+func KillSwitches(flags FlagChecker, routes []KillSwitchRoute) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, route := range routes {
+				if route.Method != "" && !strings.EqualFold(route.Method, r.Method) {
+					continue
+				}
+				if strings.HasPrefix(r.URL.Path, route.PathPrefix) && !flags.Enabled(route.Flag) {
+					errors.HandleHTTPError(w, errors.ServiceUnavailable(fmt.Sprintf("%s is temporarily unavailable", route.Flag)).
+						WithDetail("feature", route.Flag))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qhato/ecommerce/pkg/requestlog"
+)
+
+// requestActorKey holds the actor of a logged request, filled in by the auth middleware
+const requestActorKey contextKey = "request_log_actor"
+
+// RequestLogConfig configures which requests are logged and how much of them is kept
+type RequestLogConfig struct {
+	ReadSampleRate  float64  // Share of successful GET and HEAD requests logged, 0 to 1
+	WriteSampleRate float64  // Share of other successful requests logged, 0 to 1
+	CaptureBodies   bool     // Keep redacted request and response bodies
+	MaxBodyBytes    int      // Bodies are cut at this size
+	ExcludePaths    []string // Path prefixes never logged
+	RedactFields    []string // Field names redacted on top of the built-in list
+}
+
+// requestActor is who made a logged request. Route groups authenticate below
+// the request log middleware, so it shares this holder through the context
+// and the auth middleware fills it in from the verified token.
+type requestActor struct {
+	userID    string
+	email     string
+	sessionID string
+}
+
+// RequestLog records requests with their responses to a recorder for support
+// forensics. Failed requests are always logged; successful ones are sampled.
+// Credentials, card data and personal data are redacted from headers, query
+// strings and bodies, and bodies that cannot be redacted are not kept. The
+// actor is only ever taken from the verified session, so a request cannot
+// attribute itself to someone else through its headers.
+func RequestLog(recorder *requestlog.Recorder, cfg RequestLogConfig) func(http.Handler) http.Handler {
+	redactor := requestlog.NewRedactor(cfg.RedactFields)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if excludedFromRequestLog(cfg.ExcludePaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			actor := &requestActor{}
+			r = r.WithContext(context.WithValue(r.Context(), requestActorKey, actor))
+
+			var requestBody []byte
+			var requestTruncated bool
+			if cfg.CaptureBodies && r.Body != nil && r.Body != http.NoBody {
+				requestBody, requestTruncated = peekBody(r, cfg.MaxBodyBytes)
+			}
+
+			capture := &bodyCaptureWriter{responseWriter: newResponseWriter(w), capture: cfg.CaptureBodies, limit: cfg.MaxBodyBytes}
+			next.ServeHTTP(capture, r)
+
+			if !sampleRequest(r.Method, capture.statusCode, cfg) {
+				return
+			}
+
+			entry := &requestlog.Entry{
+				ID:                uuid.New().String(),
+				OccurredAt:        start,
+				Method:            r.Method,
+				Path:              r.URL.Path,
+				Query:             redactor.Query(r.URL.RawQuery),
+				Status:            capture.statusCode,
+				DurationMs:        time.Since(start).Milliseconds(),
+				ActorID:           actor.userID,
+				ActorEmail:        actor.email,
+				SessionID:         actor.sessionID,
+				IPAddress:         clientIP(r),
+				UserAgent:         r.UserAgent(),
+				CorrelationID:     GetCorrelationID(r.Context()),
+				RequestHeaders:    redactor.Headers(r.Header),
+				RequestTruncated:  requestTruncated,
+				ResponseTruncated: capture.truncated,
+			}
+			if cfg.CaptureBodies {
+				entry.RequestBody = redactor.Body(r.Header.Get("Content-Type"), requestBody)
+				entry.ResponseBody = redactor.Body(capture.Header().Get("Content-Type"), capture.body.Bytes())
+			}
+			recorder.Record(entry)
+		})
+	}
+}
+
+// setRequestActor records the authenticated user on the request log entry, if the request is logged
+func setRequestActor(ctx context.Context, userID, email, sessionID string) {
+	if actor, ok := ctx.Value(requestActorKey).(*requestActor); ok {
+		actor.userID = userID
+		actor.email = email
+		actor.sessionID = sessionID
+	}
+}
+
+// peekBody reads up to limit bytes of the request body and puts them back in
+// front of the rest, so the handler still reads the whole body
+func peekBody(r *http.Request, limit int) ([]byte, bool) {
+	head, err := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), r.Body), Closer: r.Body}
+	if err != nil {
+		return nil, false
+	}
+	if len(head) > limit {
+		return head[:limit], true
+	}
+	return head, false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// sampleRequest decides whether a completed request is logged
+func sampleRequest(method string, status int, cfg RequestLogConfig) bool {
+	if status >= http.StatusBadRequest {
+		return true
+	}
+	rate := cfg.WriteSampleRate
+	if method == http.MethodGet || method == http.MethodHead {
+		rate = cfg.ReadSampleRate
+	}
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
+
+func excludedFromRequestLog(prefixes []string, path string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyCaptureWriter keeps the first bytes of the response body
+type bodyCaptureWriter struct {
+	*responseWriter
+	capture   bool
+	limit     int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	if w.capture && !w.truncated {
+		room := w.limit - w.body.Len()
+		if len(b) > room {
+			w.body.Write(b[:room])
+			w.truncated = true
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.responseWriter.Write(b)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// SavedViewParam is the list query parameter selecting a saved view
+const SavedViewParam = "savedViewId"
+
+// Response headers describing the saved view applied to a list
+const (
+	SavedViewIDHeader      = "X-Saved-View-Id"
+	SavedViewColumnsHeader = "X-Saved-View-Columns"
+)
+
+// SavedViewResolver loads the filters and columns of a saved view of a list
+// that an authenticated user can use
+type SavedViewResolver interface {
+	ResolveSavedView(ctx context.Context, userID string, viewID int64, list string) (map[string]string, []string, error)
+}
+
+// SavedViews applies saved views to list endpoints. A GET on one of the given
+// paths (mapped to its list name) carrying SavedViewParam is authenticated with
+// authMiddleware, and the filters of the view are merged into its query: the
+// parameters sent with the request win over the saved ones. The columns of the
+// view are returned in SavedViewColumnsHeader, comma separated. Requests
+// without a saved view are passed through untouched.
+func SavedViews(resolver SavedViewResolver, lists map[string]string, authMiddleware func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		apply := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			viewID, err := strconv.ParseInt(r.URL.Query().Get(SavedViewParam), 10, 64)
+			if err != nil {
+				errors.HandleHTTPError(w, errors.ValidationError("invalid "+SavedViewParam))
+				return
+			}
+
+			list := lists[strings.TrimSuffix(r.URL.Path, "/")]
+			filters, columns, err := resolver.ResolveSavedView(r.Context(), GetUserID(r.Context()), viewID, list)
+			if err != nil {
+				errors.HandleHTTPError(w, err)
+				return
+			}
+
+			query := r.URL.Query()
+			for key, value := range filters {
+				if !query.Has(key) {
+					query.Set(key, value)
+				}
+			}
+			r.URL.RawQuery = query.Encode()
+
+			w.Header().Set(SavedViewIDHeader, strconv.FormatInt(viewID, 10))
+			if len(columns) > 0 {
+				w.Header().Set(SavedViewColumnsHeader, strings.Join(columns, ","))
+			}
+			next.ServeHTTP(w, r)
+		})
+		resolve := authMiddleware(apply)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || !r.URL.Query().Has(SavedViewParam) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, ok := lists[strings.TrimSuffix(r.URL.Path, "/")]; !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			resolve.ServeHTTP(w, r)
+		})
+	}
+}
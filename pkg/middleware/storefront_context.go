@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qhato/ecommerce/pkg/requestctx"
+)
+
+// Cookies remembering the locale and currency an anonymous visitor chose
+const (
+	LocaleCookieName   = "locale"
+	CurrencyCookieName = "currency"
+)
+
+// StorefrontContextConfig holds the storefront context resolution configuration
+type StorefrontContextConfig struct {
+	DefaultSite       string
+	Localizations     requestctx.Localizations // Locales and currencies offered per site
+	SessionCookieName string
+	Preferences       VisitorPreferences // Optional; stored visitor selections
+	GeoIP             GeoLocator         // Optional; locates anonymous visitors by IP address
+	Hosts             SiteHosts          // Optional; custom domains serving a site
+}
+
+// SiteHosts resolves the site served on a custom domain, returning "" for
+// hosts that are not one
+type SiteHosts interface {
+	SiteForHost(host string) string
+}
+
+// GeoLocator resolves the country and region of an IP address, returning ""
+// for what is unknown
+type GeoLocator interface {
+	Locate(ip net.IP) (country, region string)
+}
+
+// VisitorPreferences looks up the locale and currency a customer or session
+// selected on a site, returning "" for what was not selected
+type VisitorPreferences interface {
+	Preference(ctx context.Context, siteID string, customerID int64, sessionID string) (locale, currency string)
+}
+
+// StorefrontContext creates a middleware that resolves site, locale, currency,
+// customer and session once per request and stores them in the request context.
+// The site comes from the X-Site-ID header, then the custom domain requested,
+// then the default.
+// Anonymous visitors are located by GeoIP, when configured: their country and
+// region default the locale and currency and let carts estimate tax before an
+// address is entered. It must run after OptionalJWTAuth so the authenticated
+// customer is available.
+func StorefrontContext(cfg StorefrontContextConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rc := &requestctx.RequestContext{SiteID: cfg.DefaultSite}
+			if cfg.Hosts != nil {
+				if site := cfg.Hosts.SiteForHost(r.Host); site != "" {
+					rc.SiteID = site
+				}
+			}
+			if site := r.Header.Get("X-Site-ID"); site != "" {
+				rc.SiteID = site
+			}
+			if customerID, err := strconv.ParseInt(GetUserID(r.Context()), 10, 64); err == nil {
+				rc.CustomerID = customerID
+			}
+			if cfg.SessionCookieName != "" {
+				if cookie, err := r.Cookie(cfg.SessionCookieName); err == nil {
+					rc.SessionID = cookie.Value
+				}
+			}
+
+			if cfg.GeoIP != nil && rc.IsAnonymous() {
+				if ip := net.ParseIP(clientIP(r)); ip != nil {
+					rc.Country, rc.Region = cfg.GeoIP.Locate(ip)
+				}
+			}
+
+			var preferredLocale, preferredCurrency string
+			if cfg.Preferences != nil && (rc.CustomerID != 0 || rc.SessionID != "") {
+				preferredLocale, preferredCurrency = cfg.Preferences.Preference(r.Context(), rc.SiteID, rc.CustomerID, rc.SessionID)
+			}
+			localization := cfg.Localizations.ForSite(rc.SiteID)
+			rc.Locale = resolveLocale(r, localization, preferredLocale, rc.Country)
+			rc.Currency = resolveCurrency(r, localization, preferredCurrency, rc.Locale, rc.Country)
+
+			w.Header().Set("Content-Language", rc.Locale)
+			next.ServeHTTP(w, r.WithContext(requestctx.WithRequestContext(r.Context(), rc)))
+		})
+	}
+}
+
+// resolveLocale picks the first offered locale from the query, header, cookie,
+// stored preference, Accept-Language and located country, in that order. A bare
+// Accept-Language language is first tried in the located country ("en" in GB
+// tries "en-GB").
+func resolveLocale(r *http.Request, localization requestctx.Localization, preferred, country string) string {
+	candidates := []string{r.URL.Query().Get("locale"), r.Header.Get("X-Locale")}
+	if cookie, err := r.Cookie(LocaleCookieName); err == nil {
+		candidates = append(candidates, cookie.Value)
+	}
+	candidates = append(candidates, preferred)
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		language := strings.TrimSpace(strings.Split(part, ";")[0])
+		if country != "" && language != "" && language != "*" && !strings.ContainsAny(language, "-_") {
+			candidates = append(candidates, language+"-"+country)
+		}
+		candidates = append(candidates, language)
+	}
+	candidates = append(candidates, localization.CountryLocale(country))
+
+	for _, candidate := range candidates {
+		if locale, ok := localization.MatchLocale(candidate); ok {
+			return locale
+		}
+	}
+	return localization.DefaultLocale
+}
+
+// resolveCurrency picks the first offered currency from the query, header,
+// cookie and stored preference, then the located country's currency, then the
+// locale's currency, then the default
+func resolveCurrency(r *http.Request, localization requestctx.Localization, preferred, locale, country string) string {
+	candidates := []string{r.URL.Query().Get("currency"), r.Header.Get("X-Currency")}
+	if cookie, err := r.Cookie(CurrencyCookieName); err == nil {
+		candidates = append(candidates, cookie.Value)
+	}
+	candidates = append(candidates, preferred, localization.CountryCurrency(country), localization.LocaleCurrency(locale))
+
+	for _, candidate := range candidates {
+		if currency, ok := localization.MatchCurrency(candidate); ok {
+			return currency
+		}
+	}
+	return localization.DefaultCurrency
+}
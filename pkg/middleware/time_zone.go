@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/qhato/ecommerce/pkg/requestctx"
+	"github.com/qhato/ecommerce/pkg/schedule"
+)
+
+// SiteTimeZone creates a middleware that stores the time zone of the request's
+// site in its context, where schedule.Location reads it: dates entered without
+// an offset are resolved in it. The site is the one resolved by
+// StorefrontContext, then the X-Site-ID header (admin requests), then the default.
+func SiteTimeZone(zones schedule.Zones, defaultSite string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			site := requestctx.SiteID(r.Context())
+			if site == "" {
+				site = r.Header.Get("X-Site-ID")
+			}
+			if site == "" {
+				site = defaultSite
+			}
+			next.ServeHTTP(w, r.WithContext(schedule.WithLocation(r.Context(), zones.ForSite(site))))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/database"
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// RouteTimeout overrides the request timeout of the routes under a path prefix
+type RouteTimeout struct {
+	Method     string        // Empty matches any method
+	PathPrefix string        // e.g. "/admin/catalog/snapshot/import"
+	Timeout    time.Duration // 0 disables the timeout, and that of its queries, e.g. for streamed exports
+}
+
+// TimeoutConfig holds the request timeouts of a server
+type TimeoutConfig struct {
+	Default time.Duration  // 0 disables the timeout of unmatched routes
+	Routes  []RouteTimeout // The longest matching prefix wins
+}
+
+// timeoutWriteGrace lets the 504 response be written after a timed out request
+const timeoutWriteGrace = 5 * time.Second
+
+// Timeout bounds each request by the timeout of its route. The deadline is
+// set on the request context so repository calls are cancelled, and a
+// request still running at the deadline gets a structured 504 response
+// instead of hanging. A handler that already started its response (e.g. a
+// stream) is left to finish with its context cancelled. Routes with a
+// timeout of their own may spend all of it on one query, so it replaces the
+// database query timeout as well.
+func Timeout(cfg TimeoutConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout, matched := cfg.timeoutFor(r)
+			if matched {
+				r = r.WithContext(database.WithQueryTimeout(r.Context(), timeout))
+			}
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Routes may run longer than the server-wide read and write timeouts
+			controller := http.NewResponseController(w)
+			_ = controller.SetReadDeadline(time.Now().Add(timeout))
+			_ = controller.SetWriteDeadline(time.Now().Add(timeout + timeoutWriteGrace))
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{w: w, header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if rec := recover(); rec != nil {
+						panicked <- rec
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case rec := <-panicked:
+				panic(rec) // Let the recovery middleware handle it
+			case <-done:
+				return
+			case <-ctx.Done():
+				if !tw.timeOut() {
+					// The response already started; wait for the handler to wind down
+					select {
+					case <-done:
+					case rec := <-panicked:
+						panic(rec)
+					}
+					return
+				}
+				logger.Get().WithContext(r.Context()).WithFields(logger.Fields{
+					"method":     r.Method,
+					"path":       r.URL.Path,
+					"timeout_ms": timeout.Milliseconds(),
+				}).Warn("Request timed out")
+				errors.HandleHTTPError(w, errors.GatewayTimeout("request timed out").
+					WithDetail("timeout_ms", timeout.Milliseconds()))
+			}
+		})
+	}
+}
+
+// timeoutFor returns the timeout of the longest matching route, or the default
+// when no route matches
+func (cfg TimeoutConfig) timeoutFor(r *http.Request) (time.Duration, bool) {
+	timeout := cfg.Default
+	matched := -1
+	for _, route := range cfg.Routes {
+		if route.Method != "" && !strings.EqualFold(route.Method, r.Method) {
+			continue
+		}
+		if strings.HasPrefix(r.URL.Path, route.PathPrefix) && len(route.PathPrefix) > matched {
+			matched = len(route.PathPrefix)
+			timeout = route.Timeout
+		}
+	}
+	return timeout, matched >= 0
+}
+
+// timeoutWriter passes a handler's response through until the request times
+// out; after that every write fails with http.ErrHandlerTimeout
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeaderLocked(code)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeaderLocked(http.StatusOK)
+	return tw.w.Write(b)
+}
+
+// Flush implements http.Flusher so streaming handlers keep working
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeaderLocked(http.StatusOK)
+	if flusher, ok := tw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(code int) {
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	dst := tw.w.Header()
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+	tw.w.WriteHeader(code)
+}
+
+// timeOut marks the response as timed out unless it already started,
+// reporting whether the timeout response may be written
+func (tw *timeoutWriter) timeOut() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.wroteHeader {
+		return false
+	}
+	tw.timedOut = true
+	return true
+}
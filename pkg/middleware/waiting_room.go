@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/qhato/ecommerce/pkg/errors"
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/waitingroom"
+)
+
+// WaitingRoomRoute puts the routes matching a path pattern behind the waiting room
+type WaitingRoomRoute struct {
+	Method string // Empty matches any method
+	Path   string // path.Match pattern, e.g. "/orders/*/payment"
+}
+
+// WaitingRoom admits requests to checkout routes through a waiting room.
+// Admitted visitors get their session in the waiting room token header and
+// reuse it on later checkout requests; the others get 503 with their queue
+// ticket, position and estimated wait, and poll the status endpoint until
+// admitted. A failing waiting room never rejects requests.
+func WaitingRoom(room *waitingroom.Room, routes []WaitingRoomRoute) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !matchWaitingRoomRoute(routes, r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			status, err := room.Admit(r.Context(), waitingroom.Token(r))
+			if err != nil {
+				logger.WithError(err).Warn("Waiting room check failed, allowing request")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(waitingroom.TokenHeader, status.Token)
+			if status.Admitted {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(status.PollAfter().Seconds())))
+			w.Header().Set("Cache-Control", "no-store")
+			errors.HandleHTTPError(w, errors.ServiceUnavailable("checkout is busy, you are in the queue").
+				WithDetail("waiting_room", status))
+		})
+	}
+}
+
+// matchWaitingRoomRoute checks whether a request goes through the waiting room
+func matchWaitingRoomRoute(routes []WaitingRoomRoute, r *http.Request) bool {
+	for _, route := range routes {
+		if route.Method != "" && !strings.EqualFold(route.Method, r.Method) {
+			continue
+		}
+		if matched, _ := path.Match(route.Path, r.URL.Path); matched {
+			return true
+		}
+	}
+	return false
+}
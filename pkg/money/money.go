@@ -0,0 +1,99 @@
+package money
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Money is an amount in a currency
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// New creates Money from a float amount
+func New(amount float64, currency string) Money {
+	return Money{Amount: decimal.NewFromFloat(amount), Currency: strings.ToUpper(currency)}
+}
+
+// FromDecimal creates Money from a decimal amount
+func FromDecimal(amount decimal.Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// Round returns the amount rounded with the currency's rounding rule
+func (m Money) Round() Money {
+	return Money{Amount: RuleFor(m.Currency).round(m.Amount), Currency: m.Currency}
+}
+
+// Float64 returns the rounded amount as a float
+func (m Money) Float64() float64 {
+	return m.Round().Amount.InexactFloat64()
+}
+
+// Format renders the rounded amount with the locale's separators and symbol placement
+func (m Money) Format(locale string) string {
+	rule := RuleFor(m.Currency)
+	format := FormatFor(locale)
+	rounded := rule.round(m.Amount)
+
+	digits := rounded.Abs().StringFixed(rule.DecimalPlaces)
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		integer, fraction = digits[:i], digits[i+1:]
+	}
+
+	var b strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(format.GroupSeparator)
+		}
+		b.WriteRune(digit)
+	}
+	number := b.String()
+	if fraction != "" {
+		number += format.DecimalSeparator + fraction
+	}
+
+	space := ""
+	if format.SymbolSpacing {
+		space = " "
+	}
+	sign := ""
+	if rounded.IsNegative() {
+		sign = "-"
+	}
+	if format.SymbolPosition == SymbolAfter {
+		return sign + number + space + rule.Symbol
+	}
+	return sign + rule.Symbol + space + number
+}
+
+// String formats the amount in the default locale
+func (m Money) String() string {
+	return m.Format(DefaultLocale())
+}
+
+// MarshalJSON serializes the rounded amount as a number with its currency
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   json.Number `json:"amount"`
+		Currency string      `json:"currency"`
+	}{
+		Amount:   json.Number(m.Round().Amount.StringFixed(RuleFor(m.Currency).DecimalPlaces)),
+		Currency: m.Currency,
+	})
+}
+
+// Round rounds a float amount with the currency's rounding rule. DTO converters
+// use it so every serialized amount has the currency's precision.
+func Round(amount float64, currency string) float64 {
+	return New(amount, currency).Float64()
+}
+
+// Format renders a float amount in a currency for a locale
+func Format(amount float64, currency, locale string) string {
+	return New(amount, currency).Format(locale)
+}
@@ -0,0 +1,188 @@
+// Package money rounds and formats monetary amounts with per-currency rounding
+// rules and per-locale display rules, so every API response and template presents
+// totals the same way.
+package money
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode controls how amounts are rounded to a currency's precision
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the nearest even digit (banker's rounding)
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundUp always rounds away from zero
+	RoundUp RoundingMode = "up"
+	// RoundDown always rounds toward zero
+	RoundDown RoundingMode = "down"
+)
+
+// SymbolPosition controls where the currency symbol is displayed
+type SymbolPosition string
+
+const (
+	// SymbolBefore displays the symbol before the amount ("$1.00")
+	SymbolBefore SymbolPosition = "before"
+	// SymbolAfter displays the symbol after the amount ("1,00 €")
+	SymbolAfter SymbolPosition = "after"
+)
+
+// CurrencyRule holds the rounding rules and symbol of a currency
+type CurrencyRule struct {
+	Code              string // ISO 4217
+	Symbol            string
+	DecimalPlaces     int32
+	RoundingIncrement decimal.Decimal // e.g. 0.05 for cash rounding; zero rounds to DecimalPlaces
+	RoundingMode      RoundingMode
+}
+
+// LocaleFormat holds how amounts are displayed in a locale
+type LocaleFormat struct {
+	DecimalSeparator string
+	GroupSeparator   string
+	SymbolPosition   SymbolPosition
+	SymbolSpacing    bool // Separate the symbol from the amount with a space
+}
+
+var (
+	mu            sync.RWMutex
+	currencyRules = defaultCurrencyRules()
+	localeFormats = defaultLocaleFormats()
+	defaultLocale = "en-US"
+)
+
+func defaultCurrencyRules() map[string]CurrencyRule {
+	return map[string]CurrencyRule{
+		"USD": {Code: "USD", Symbol: "$", DecimalPlaces: 2, RoundingMode: RoundHalfUp},
+		"EUR": {Code: "EUR", Symbol: "€", DecimalPlaces: 2, RoundingMode: RoundHalfUp},
+		"GBP": {Code: "GBP", Symbol: "£", DecimalPlaces: 2, RoundingMode: RoundHalfUp},
+		"MXN": {Code: "MXN", Symbol: "$", DecimalPlaces: 2, RoundingMode: RoundHalfUp},
+		"CAD": {Code: "CAD", Symbol: "$", DecimalPlaces: 2, RoundingMode: RoundHalfUp},
+		"JPY": {Code: "JPY", Symbol: "¥", DecimalPlaces: 0, RoundingMode: RoundHalfUp},
+		"CHF": {Code: "CHF", Symbol: "CHF", DecimalPlaces: 2, RoundingIncrement: decimal.RequireFromString("0.05"), RoundingMode: RoundHalfUp},
+	}
+}
+
+func defaultLocaleFormats() map[string]LocaleFormat {
+	return map[string]LocaleFormat{
+		"en-US": {DecimalSeparator: ".", GroupSeparator: ",", SymbolPosition: SymbolBefore},
+		"en-GB": {DecimalSeparator: ".", GroupSeparator: ",", SymbolPosition: SymbolBefore},
+		"es-MX": {DecimalSeparator: ".", GroupSeparator: ",", SymbolPosition: SymbolBefore},
+		"es-ES": {DecimalSeparator: ",", GroupSeparator: ".", SymbolPosition: SymbolAfter, SymbolSpacing: true},
+		"de-DE": {DecimalSeparator: ",", GroupSeparator: ".", SymbolPosition: SymbolAfter, SymbolSpacing: true},
+		"fr-FR": {DecimalSeparator: ",", GroupSeparator: " ", SymbolPosition: SymbolAfter, SymbolSpacing: true},
+		"de-CH": {DecimalSeparator: ".", GroupSeparator: "'", SymbolPosition: SymbolBefore, SymbolSpacing: true},
+	}
+}
+
+// Configure adds or replaces currency rules and locale formats. Currencies and
+// locales that are not configured keep their built-in rules.
+func Configure(currencies []CurrencyRule, locales map[string]LocaleFormat, fallbackLocale string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, rule := range currencies {
+		rule.Code = strings.ToUpper(rule.Code)
+		if rule.RoundingMode == "" {
+			rule.RoundingMode = RoundHalfUp
+		}
+		currencyRules[rule.Code] = rule
+	}
+	for locale, format := range locales {
+		localeFormats[canonicalLocale(locale)] = format
+	}
+	if fallbackLocale != "" {
+		defaultLocale = fallbackLocale
+	}
+}
+
+// RuleFor returns the rounding rule of a currency; unknown currencies round
+// half up to two decimal places and display their code
+func RuleFor(currency string) CurrencyRule {
+	code := strings.ToUpper(currency)
+	mu.RLock()
+	rule, ok := currencyRules[code]
+	mu.RUnlock()
+	if ok {
+		return rule
+	}
+	return CurrencyRule{Code: code, Symbol: code, DecimalPlaces: 2, RoundingMode: RoundHalfUp}
+}
+
+// FormatFor returns the display format of a locale. A bare language ("es")
+// matches its main locale ("es-ES") or else the first configured locale of that
+// language; anything else falls back to the default locale.
+func FormatFor(locale string) LocaleFormat {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	key := canonicalLocale(locale)
+	if format, ok := localeFormats[key]; ok {
+		return format
+	}
+	language := strings.SplitN(key, "-", 2)[0]
+	if format, ok := localeFormats[canonicalLocale(language+"-"+language)]; ok {
+		return format
+	}
+	names := make([]string, 0, len(localeFormats))
+	for name := range localeFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.HasPrefix(name, language+"-") {
+			return localeFormats[name]
+		}
+	}
+	if format, ok := localeFormats[canonicalLocale(defaultLocale)]; ok {
+		return format
+	}
+	return LocaleFormat{DecimalSeparator: ".", GroupSeparator: ",", SymbolPosition: SymbolBefore}
+}
+
+// DefaultLocale returns the locale used when none is given
+func DefaultLocale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultLocale
+}
+
+// canonicalLocale normalizes "es_es" and "ES-es" to "es-ES" (config loaders may
+// lowercase map keys)
+func canonicalLocale(locale string) string {
+	parts := strings.SplitN(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-", 2)
+	if len(parts) == 1 {
+		return strings.ToLower(parts[0])
+	}
+	return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1])
+}
+
+// round applies the rounding rule to an amount
+func (r CurrencyRule) round(amount decimal.Decimal) decimal.Decimal {
+	if r.RoundingIncrement.IsPositive() {
+		steps := roundWithMode(amount.Div(r.RoundingIncrement), 0, r.RoundingMode)
+		return steps.Mul(r.RoundingIncrement).Round(r.DecimalPlaces)
+	}
+	return roundWithMode(amount, r.DecimalPlaces, r.RoundingMode)
+}
+
+func roundWithMode(amount decimal.Decimal, places int32, mode RoundingMode) decimal.Decimal {
+	switch mode {
+	case RoundHalfEven:
+		return amount.RoundBank(places)
+	case RoundUp:
+		return amount.RoundUp(places)
+	case RoundDown:
+		return amount.RoundDown(places)
+	default:
+		return amount.Round(places)
+	}
+}
@@ -0,0 +1,19 @@
+package money
+
+import "text/template"
+
+// TemplateFuncs returns template helpers bound to a locale:
+//
+//	{{ money .Total .Currency }}      -> "$1,234.50"
+//	{{ roundMoney .Total .Currency }} -> 1234.5
+func TemplateFuncs(locale string) template.FuncMap {
+	if locale == "" {
+		locale = DefaultLocale()
+	}
+	return template.FuncMap{
+		"money": func(amount float64, currency string) string {
+			return Format(amount, currency, locale)
+		},
+		"roundMoney": Round,
+	}
+}
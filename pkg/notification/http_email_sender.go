@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/qhato/ecommerce/pkg/httpclient"
+	"github.com/qhato/ecommerce/pkg/settings"
+)
+
+// HTTPEmailSender sends email through a transactional email provider's JSON API
+type HTTPEmailSender struct {
+	client *httpclient.Client
+	path   string
+	from   string
+
+	settings *settings.Settings // Optional sender and reply-to addresses set by the admin
+}
+
+// NewHTTPEmailSender creates an email sender posting to path on the provider client
+func NewHTTPEmailSender(client *httpclient.Client, path, from string) *HTTPEmailSender {
+	return &HTTPEmailSender{client: client, path: path, from: from}
+}
+
+// SetSettings sends emails from the sender address of the site settings, with
+// the support address as their reply-to
+func (s *HTTPEmailSender) SetSettings(siteSettings *settings.Settings) {
+	s.settings = siteSettings
+}
+
+// GetType returns the notification type handled by the sender
+func (s *HTTPEmailSender) GetType() NotificationType {
+	return NotificationTypeEmail
+}
+
+// Send posts the email to the provider
+func (s *HTTPEmailSender) Send(ctx context.Context, notification *Notification) error {
+	from, replyTo := s.from, ""
+	if s.settings != nil {
+		if address := s.settings.String(settings.EmailFrom); address != "" {
+			from = address
+		}
+		replyTo = s.settings.String(settings.SupportEmail)
+	}
+	payload := map[string]interface{}{
+		"from":    from,
+		"to":      notification.Recipient,
+		"subject": notification.Subject,
+		"text":    notification.Body,
+	}
+	if replyTo != "" {
+		payload["reply_to"] = replyTo
+	}
+	if notification.TemplateID != nil {
+		payload["template_id"] = *notification.TemplateID
+		payload["template_data"] = notification.TemplateData
+	}
+	if len(notification.Attachments) > 0 {
+		attachments := make([]map[string]string, 0, len(notification.Attachments))
+		for _, a := range notification.Attachments {
+			attachments = append(attachments, map[string]string{
+				"filename":     a.Filename,
+				"content_type": a.ContentType,
+				"content":      base64.StdEncoding.EncodeToString(a.Content),
+			})
+		}
+		payload["attachments"] = attachments
+	}
+
+	if err := s.client.DoJSON(ctx, http.MethodPost, s.path, payload, nil); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package notification
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -13,6 +14,14 @@ const (
 	NotificationTypeEmail NotificationType = "EMAIL"
 	NotificationTypeSMS   NotificationType = "SMS"
 	NotificationTypePush  NotificationType = "PUSH"
+	NotificationTypeInApp NotificationType = "IN_APP" // Kept in the customer's inbox on the storefront
+)
+
+// Categories of customer notifications, for channels that sort or filter them
+const (
+	CategoryOrderUpdate = "ORDER_UPDATE"
+	CategoryPromotion   = "PROMOTION"
+	CategoryBackInStock = "BACK_IN_STOCK"
 )
 
 // NotificationStatus represents the status of a notification
@@ -34,6 +43,9 @@ type Notification struct {
 	Body         string
 	TemplateID   *string
 	TemplateData map[string]interface{}
+	Category     string       // E.g. CategoryOrderUpdate; used by channels that sort messages
+	Link         string       // Storefront path the message leads to, for channels that can link
+	Attachments  []Attachment // Files sent with an email
 	Status       NotificationStatus
 	Error        *string
 	SentAt       *time.Time
@@ -42,6 +54,13 @@ type Notification struct {
 	UpdatedAt    time.Time
 }
 
+// Attachment is a file sent with an email
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
 // NotificationSender defines the interface for sending notifications
 type NotificationSender interface {
 	Send(ctx context.Context, notification *Notification) error
@@ -102,6 +121,20 @@ func (s *NotificationService) SendEmail(ctx context.Context, to, subject, body s
 	return s.Send(ctx, notification)
 }
 
+// SendEmailWithAttachments sends an email notification with files attached
+func (s *NotificationService) SendEmailWithAttachments(ctx context.Context, to, subject, body string, attachments []Attachment) error {
+	notification := &Notification{
+		Type:        NotificationTypeEmail,
+		Recipient:   to,
+		Subject:     subject,
+		Body:        body,
+		Attachments: attachments,
+		CreatedAt:   time.Now(),
+	}
+
+	return s.Send(ctx, notification)
+}
+
 // SendSMS sends an SMS notification
 func (s *NotificationService) SendSMS(ctx context.Context, to, body string) error {
 	notification := &Notification{
@@ -114,6 +147,21 @@ func (s *NotificationService) SendSMS(ctx context.Context, to, body string) erro
 	return s.Send(ctx, notification)
 }
 
+// SendInApp stores a notification in a customer's storefront inbox
+func (s *NotificationService) SendInApp(ctx context.Context, customerID int64, category, subject, body, link string) error {
+	notification := &Notification{
+		Type:      NotificationTypeInApp,
+		Recipient: strconv.FormatInt(customerID, 10),
+		Subject:   subject,
+		Body:      body,
+		Category:  category,
+		Link:      link,
+		CreatedAt: time.Now(),
+	}
+
+	return s.Send(ctx, notification)
+}
+
 // SendFromTemplate sends a notification using a template
 func (s *NotificationService) SendFromTemplate(
 	ctx context.Context,
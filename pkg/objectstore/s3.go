@@ -0,0 +1,240 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/httpclient"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	signingService   = "s3"
+	amzDateFormat    = "20060102T150405Z"
+)
+
+// s3Store writes and reads objects with the S3 REST API, signing requests with AWS Signature Version 4
+type s3Store struct {
+	scheme  string
+	bucket  string
+	prefix  string
+	cfg     Config
+	client  *httpclient.Client
+	baseURL *url.URL // Bucket URL
+	now     func() time.Time
+}
+
+func newS3Store(scheme, bucket, prefix string, cfg Config, client *httpclient.Client) *s3Store {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+		if scheme == "gs" {
+			cfg.Region = "auto"
+		}
+	}
+
+	base := &url.URL{Scheme: "https"}
+	switch {
+	case cfg.Endpoint != "":
+		if endpoint, err := url.Parse(cfg.Endpoint); err == nil && endpoint.Host != "" {
+			base.Scheme = endpoint.Scheme
+			base.Host = endpoint.Host
+		} else {
+			base.Host = strings.TrimRight(cfg.Endpoint, "/")
+		}
+		cfg.PathStyle = true
+	case scheme == "gs":
+		base.Host = "storage.googleapis.com"
+		cfg.PathStyle = true
+	default:
+		base.Host = "s3." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.PathStyle {
+		base.Path = "/" + bucket
+	} else {
+		base.Host = bucket + "." + base.Host
+	}
+
+	return &s3Store{
+		scheme:  scheme,
+		bucket:  bucket,
+		prefix:  prefix,
+		cfg:     cfg,
+		client:  client,
+		baseURL: base,
+		now:     time.Now,
+	}
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	objectKey := s.objectKey(key)
+	escapedPath := uriEscapePath(s.baseURL.Path + "/" + objectKey)
+	target := s.baseURL.Scheme + "://" + s.baseURL.Host + escapedPath
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	s.sign(http.MethodPut, s.baseURL.Host, escapedPath, header, body)
+
+	if _, err := s.client.Do(ctx, &httpclient.Request{
+		Method: http.MethodPut,
+		Path:   target,
+		Header: header,
+		Body:   body,
+	}); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", s.Location(key), err)
+	}
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	escapedPath := uriEscapePath(s.baseURL.Path + "/" + s.objectKey(key))
+	header := http.Header{}
+	s.sign(http.MethodGet, s.baseURL.Host, escapedPath, header, nil)
+
+	resp, err := s.client.Do(ctx, &httpclient.Request{
+		Method: http.MethodGet,
+		Path:   s.baseURL.Scheme + "://" + s.baseURL.Host + escapedPath,
+		Header: header,
+	})
+	var statusErr *httpclient.StatusError
+	if errors.As(err, &statusErr) && statusErr.Response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("failed to download %s: %w", s.Location(key), ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", s.Location(key), err)
+	}
+	return resp.Body, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	escapedPath := uriEscapePath(s.baseURL.Path + "/" + s.objectKey(key))
+	header := http.Header{}
+	s.sign(http.MethodDelete, s.baseURL.Host, escapedPath, header, nil)
+
+	// S3 answers 204 for missing keys too
+	_, err := s.client.Do(ctx, &httpclient.Request{
+		Method: http.MethodDelete,
+		Path:   s.baseURL.Scheme + "://" + s.baseURL.Host + escapedPath,
+		Header: header,
+	})
+	var statusErr *httpclient.StatusError
+	if err != nil && !(errors.As(err, &statusErr) && statusErr.Response.StatusCode == http.StatusNotFound) {
+		return fmt.Errorf("failed to delete %s: %w", s.Location(key), err)
+	}
+	return nil
+}
+
+func (s *s3Store) Location(key string) string {
+	return s.scheme + "://" + s.bucket + "/" + s.objectKey(key)
+}
+
+func (s *s3Store) objectKey(key string) string {
+	key = strings.TrimLeft(key, "/")
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+// sign adds the Signature Version 4 headers for a request with an empty query string.
+// Content-Type is signed only when set, as requests without a body send none.
+func (s *s3Store) sign(method, host, escapedPath string, header http.Header, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	header.Set("X-Amz-Date", amzDate)
+	header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.cfg.SessionToken != "" {
+		header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	// Headers are listed in sorted order of their lower-case names
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"host":                 host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := header.Get("Content-Type"); contentType != "" {
+		signed = append([]string{"content-type"}, signed...)
+		values["content-type"] = contentType
+	}
+	if s.cfg.SessionToken != "" {
+		signed = append(signed, "x-amz-security-token")
+		values["x-amz-security-token"] = s.cfg.SessionToken
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signed {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(values[name]) + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		escapedPath,
+		"", // Query string
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/" + signingService + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, signingService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// uriEscapePath escapes each segment of a path as Signature Version 4 requires,
+// leaving only unreserved characters and slashes as they are
+func uriEscapePath(path string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '.' || c == '_' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&0xF])
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,125 @@
+// Package objectstore stores files in object storage: Amazon S3 and S3-compatible
+// services, Google Cloud Storage through its S3-compatible XML API, or a local
+// directory during development.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/qhato/ecommerce/pkg/httpclient"
+)
+
+// ErrNotFound is returned when reading an object that does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Store writes and reads objects
+type Store interface {
+	// Put writes an object, replacing any object with the same key.
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+
+	// Get reads an object; fails with ErrNotFound when there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Delete removes an object; removing a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// Location returns where an object is stored, e.g. s3://bucket/prefix/key.
+	Location(key string) string
+}
+
+// Config holds the settings of a store
+type Config struct {
+	URL             string // s3://bucket/prefix, gs://bucket/prefix or file:///path
+	Region          string // Defaults to us-east-1 for S3 and auto for GCS
+	Endpoint        string // Custom endpoint of an S3-compatible service, e.g. http://localhost:9000
+	PathStyle       bool   // Address buckets in the path instead of the host name; implied by Endpoint
+	AccessKeyID     string // HMAC key for GCS
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// New creates the store a URL points at; client sends the requests of remote stores
+func New(cfg Config, client *httpclient.Client) (Store, error) {
+	parsed, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object store URL: %w", err)
+	}
+	prefix := strings.Trim(parsed.Path, "/")
+
+	switch parsed.Scheme {
+	case "file":
+		if parsed.Path == "" {
+			return nil, fmt.Errorf("invalid object store URL %q: missing directory", cfg.URL)
+		}
+		return &localStore{dir: filepath.FromSlash(parsed.Path)}, nil
+	case "s3", "gs":
+		if parsed.Host == "" {
+			return nil, fmt.Errorf("invalid object store URL %q: missing bucket", cfg.URL)
+		}
+		if client == nil {
+			return nil, fmt.Errorf("object store %q needs an HTTP client", cfg.URL)
+		}
+		if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, fmt.Errorf("object store %q needs access credentials", cfg.URL)
+		}
+		return newS3Store(parsed.Scheme, parsed.Host, prefix, cfg, client), nil
+	}
+	return nil, fmt.Errorf("unsupported object store URL %q", cfg.URL)
+}
+
+// localStore writes objects as files under a directory
+type localStore struct {
+	dir string
+}
+
+func (s *localStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	path := s.Location(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+	// Write through a temporary file so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *localStore) Get(ctx context.Context, key string) ([]byte, error) {
+	body, err := os.ReadFile(s.Location(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", key, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return body, nil
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.Location(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *localStore) Location(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
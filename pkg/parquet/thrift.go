@@ -0,0 +1,112 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol field types
+const (
+	thriftBooleanTrue  = 1
+	thriftBooleanFalse = 2
+	thriftI32          = 5
+	thriftI64          = 6
+	thriftBinary       = 8
+	thriftList         = 9
+	thriftStruct       = 12
+)
+
+// thriftWriter encodes the Parquet footer and page headers with the Thrift
+// compact protocol. Only the types the writer needs are supported.
+type thriftWriter struct {
+	buf         bytes.Buffer
+	lastFieldID []int16 // Last field ID written, per open struct
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastFieldID: []int16{0}}
+}
+
+func (t *thriftWriter) bytes() []byte {
+	return t.buf.Bytes()
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	last := &t.lastFieldID[len(t.lastFieldID)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) i32Field(id int16, value int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(zigzag(int64(value)))
+}
+
+func (t *thriftWriter) i64Field(id int16, value int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(zigzag(value))
+}
+
+func (t *thriftWriter) boolField(id int16, value bool) {
+	if value {
+		t.fieldHeader(id, thriftBooleanTrue)
+	} else {
+		t.fieldHeader(id, thriftBooleanFalse)
+	}
+}
+
+func (t *thriftWriter) stringField(id int16, value string) {
+	t.fieldHeader(id, thriftBinary)
+	t.binary(value)
+}
+
+// beginStructField opens a nested struct; close it with endStruct
+func (t *thriftWriter) beginStructField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginStruct()
+}
+
+// beginStruct opens a struct written as a list element; close it with endStruct
+func (t *thriftWriter) beginStruct() {
+	t.lastFieldID = append(t.lastFieldID, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0) // Stop field
+	t.lastFieldID = t.lastFieldID[:len(t.lastFieldID)-1]
+}
+
+// listField writes the header of a list field; the elements follow
+func (t *thriftWriter) listField(id int16, elementType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		t.buf.WriteByte(0xF0 | elementType)
+		t.varint(uint64(size))
+	}
+}
+
+func (t *thriftWriter) i32Element(value int32) {
+	t.varint(zigzag(int64(value)))
+}
+
+func (t *thriftWriter) binary(value string) {
+	t.varint(uint64(len(value)))
+	t.buf.WriteString(value)
+}
+
+func (t *thriftWriter) varint(value uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], value)
+	t.buf.Write(b[:n])
+}
+
+func zigzag(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
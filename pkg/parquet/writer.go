@@ -0,0 +1,469 @@
+// Package parquet writes Apache Parquet files for analytics tools. It covers the
+// flat, nullable tables the data warehouse exports need: every column is an
+// OPTIONAL primitive, values are PLAIN encoded and pages are GZIP compressed,
+// which every Parquet reader supports.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// magic starts and ends every Parquet file
+const magic = "PAR1"
+
+// DefaultRowGroupSize is the number of rows buffered before a row group is written
+const DefaultRowGroupSize = 50000
+
+// Type is the type of a column
+type Type int
+
+const (
+	Int64     Type = iota // int, int32 or int64 values
+	Double                // float32 or float64 values
+	String                // string or []byte values, UTF-8
+	Boolean               // bool values
+	Timestamp             // time.Time values, stored as microseconds since the Unix epoch in UTC
+)
+
+// String returns the name of the type
+func (t Type) String() string {
+	switch t {
+	case Int64:
+		return "int64"
+	case Double:
+		return "double"
+	case String:
+		return "string"
+	case Boolean:
+		return "boolean"
+	case Timestamp:
+		return "timestamp"
+	}
+	return fmt.Sprintf("type(%d)", int(t))
+}
+
+// Column describes a column of a file
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Parquet format enumerations
+const (
+	physicalBoolean   = 0
+	physicalInt64     = 2
+	physicalDouble    = 5
+	physicalByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+
+	repetitionOptional = 1
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecGzip = 2
+
+	pageTypeData = 0
+)
+
+// Writer writes rows to a Parquet file. Rows are buffered and written as a row
+// group every RowGroupSize rows; Close writes the last row group and the footer.
+type Writer struct {
+	RowGroupSize int
+
+	out       io.Writer
+	columns   []Column
+	buffered  [][]interface{} // Buffered values, per column
+	rows      int
+	offset    int64
+	rowGroups []rowGroup
+	metadata  [][2]string
+	createdBy string
+	closed    bool
+}
+
+type rowGroup struct {
+	numRows   int64
+	totalSize int64
+	chunks    []columnChunk
+}
+
+type columnChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// NewWriter creates a Writer of a file with the given columns
+func NewWriter(out io.Writer, columns []Column) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet: no columns")
+	}
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if column.Name == "" {
+			return nil, fmt.Errorf("parquet: column without a name")
+		}
+		if seen[column.Name] {
+			return nil, fmt.Errorf("parquet: duplicate column %q", column.Name)
+		}
+		if column.Type < Int64 || column.Type > Timestamp {
+			return nil, fmt.Errorf("parquet: column %q has unknown %s", column.Name, column.Type)
+		}
+		seen[column.Name] = true
+	}
+
+	w := &Writer{
+		RowGroupSize: DefaultRowGroupSize,
+		out:          out,
+		columns:      columns,
+		buffered:     make([][]interface{}, len(columns)),
+		createdBy:    "qhato-ecommerce",
+	}
+	if err := w.write([]byte(magic)); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// SetMetadata adds a key-value pair to the file footer
+func (w *Writer) SetMetadata(key, value string) {
+	w.metadata = append(w.metadata, [2]string{key, value})
+}
+
+// Write adds a row; values are in column order and nil is null
+func (w *Writer) Write(row []interface{}) error {
+	if w.closed {
+		return fmt.Errorf("parquet: writer is closed")
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, want %d", len(row), len(w.columns))
+	}
+	for i, value := range row {
+		normalized, err := normalize(w.columns[i], value)
+		if err != nil {
+			return err
+		}
+		w.buffered[i] = append(w.buffered[i], normalized)
+	}
+	w.rows++
+
+	if w.RowGroupSize > 0 && w.rows >= w.RowGroupSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Rows returns the number of rows written so far
+func (w *Writer) Rows() int64 {
+	total := int64(w.rows)
+	for _, group := range w.rowGroups {
+		total += group.numRows
+	}
+	return total
+}
+
+// Flush writes the buffered rows as a row group
+func (w *Writer) Flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+
+	group := rowGroup{numRows: int64(w.rows)}
+	for i, column := range w.columns {
+		chunk, err := w.writeColumnChunk(column, w.buffered[i])
+		if err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.totalSize += chunk.uncompressedSize
+		w.buffered[i] = w.buffered[i][:0]
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.rows = 0
+	return nil
+}
+
+// Close writes the remaining rows and the footer. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	footer := w.fileMetadata()
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	if err := w.write(length[:]); err != nil {
+		return err
+	}
+	return w.write([]byte(magic))
+}
+
+// writeColumnChunk writes the values of a column as a single data page
+func (w *Writer) writeColumnChunk(column Column, values []interface{}) (columnChunk, error) {
+	var page bytes.Buffer
+	writeDefinitionLevels(&page, values)
+	writeValues(&page, column.Type, values)
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(page.Bytes()); err != nil {
+		return columnChunk{}, fmt.Errorf("parquet: failed to compress column %q: %w", column.Name, err)
+	}
+	if err := zw.Close(); err != nil {
+		return columnChunk{}, fmt.Errorf("parquet: failed to compress column %q: %w", column.Name, err)
+	}
+
+	header := newThriftWriter()
+	header.i32Field(1, pageTypeData)
+	header.i32Field(2, int32(page.Len()))
+	header.i32Field(3, int32(compressed.Len()))
+	header.beginStructField(5)
+	header.i32Field(1, int32(len(values)))
+	header.i32Field(2, encodingPlain)
+	header.i32Field(3, encodingRLE)
+	header.i32Field(4, encodingRLE)
+	header.endStruct()
+	header.endStruct()
+
+	chunk := columnChunk{
+		offset:           w.offset,
+		numValues:        int64(len(values)),
+		uncompressedSize: int64(len(header.bytes()) + page.Len()),
+		compressedSize:   int64(len(header.bytes()) + compressed.Len()),
+	}
+	if err := w.write(header.bytes()); err != nil {
+		return columnChunk{}, err
+	}
+	if err := w.write(compressed.Bytes()); err != nil {
+		return columnChunk{}, err
+	}
+	return chunk, nil
+}
+
+// fileMetadata encodes the footer
+func (w *Writer) fileMetadata() []byte {
+	t := newThriftWriter()
+	t.i32Field(1, 1) // Format version
+
+	t.listField(2, thriftStruct, len(w.columns)+1)
+	t.beginStruct()
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(w.columns)))
+	t.endStruct()
+	for _, column := range w.columns {
+		physical, converted := physicalType(column.Type)
+		t.beginStruct()
+		t.i32Field(1, physical)
+		t.i32Field(3, repetitionOptional)
+		t.stringField(4, column.Name)
+		if converted >= 0 {
+			t.i32Field(6, converted)
+		}
+		t.endStruct()
+	}
+
+	t.i64Field(3, w.Rows())
+
+	t.listField(4, thriftStruct, len(w.rowGroups))
+	for _, group := range w.rowGroups {
+		t.beginStruct()
+		t.listField(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			physical, _ := physicalType(w.columns[i].Type)
+			t.beginStruct()
+			t.i64Field(2, chunk.offset)
+			t.beginStructField(3)
+			t.i32Field(1, physical)
+			t.listField(2, thriftI32, 2)
+			t.i32Element(encodingPlain)
+			t.i32Element(encodingRLE)
+			t.listField(3, thriftBinary, 1)
+			t.binary(w.columns[i].Name)
+			t.i32Field(4, codecGzip)
+			t.i64Field(5, chunk.numValues)
+			t.i64Field(6, chunk.uncompressedSize)
+			t.i64Field(7, chunk.compressedSize)
+			t.i64Field(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64Field(2, group.totalSize)
+		t.i64Field(3, group.numRows)
+		t.endStruct()
+	}
+
+	if len(w.metadata) > 0 {
+		t.listField(5, thriftStruct, len(w.metadata))
+		for _, kv := range w.metadata {
+			t.beginStruct()
+			t.stringField(1, kv[0])
+			t.stringField(2, kv[1])
+			t.endStruct()
+		}
+	}
+	t.stringField(6, w.createdBy)
+	t.endStruct()
+
+	return t.bytes()
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.out.Write(b)
+	w.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("parquet: write failed: %w", err)
+	}
+	return nil
+}
+
+// physicalType returns the physical and converted type of a column type; the
+// converted type is -1 when there is none
+func physicalType(t Type) (int32, int32) {
+	switch t {
+	case Double:
+		return physicalDouble, -1
+	case String:
+		return physicalByteArray, convertedUTF8
+	case Boolean:
+		return physicalBoolean, -1
+	case Timestamp:
+		return physicalInt64, convertedTimestampMicros
+	default:
+		return physicalInt64, -1
+	}
+}
+
+// normalize converts a value to the Go type stored for its column
+func normalize(column Column, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch column.Type {
+	case Int64:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case int:
+			return int64(v), nil
+		case int32:
+			return int64(v), nil
+		case int16:
+			return int64(v), nil
+		}
+	case Double:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case float32:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		}
+	case String:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		}
+	case Boolean:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case Timestamp:
+		if v, ok := value.(time.Time); ok {
+			return v.UnixMicro(), nil
+		}
+	}
+	return nil, fmt.Errorf("parquet: column %q (%s) cannot hold %T", column.Name, column.Type, value)
+}
+
+// writeDefinitionLevels writes whether each value is present, as the RLE hybrid
+// encoding with a bit width of 1 preceded by its length
+func writeDefinitionLevels(page *bytes.Buffer, values []interface{}) {
+	var levels bytes.Buffer
+	var varint [binary.MaxVarintLen64]byte
+	for i := 0; i < len(values); {
+		present := values[i] != nil
+		run := 1
+		for i+run < len(values) && (values[i+run] != nil) == present {
+			run++
+		}
+		n := binary.PutUvarint(varint[:], uint64(run)<<1)
+		levels.Write(varint[:n])
+		if present {
+			levels.WriteByte(1)
+		} else {
+			levels.WriteByte(0)
+		}
+		i += run
+	}
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(levels.Len()))
+	page.Write(length[:])
+	page.Write(levels.Bytes())
+}
+
+// writeValues writes the non-null values with the PLAIN encoding
+func writeValues(page *bytes.Buffer, t Type, values []interface{}) {
+	var b [8]byte
+	if t == Boolean {
+		var bits byte
+		count := 0
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+			if value.(bool) {
+				bits |= 1 << uint(count%8)
+			}
+			count++
+			if count%8 == 0 {
+				page.WriteByte(bits)
+				bits = 0
+			}
+		}
+		if count%8 != 0 {
+			page.WriteByte(bits)
+		}
+		return
+	}
+
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case int64:
+			binary.LittleEndian.PutUint64(b[:], uint64(v))
+			page.Write(b[:])
+		case float64:
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+			page.Write(b[:])
+		case string:
+			binary.LittleEndian.PutUint32(b[:4], uint32(len(v)))
+			page.Write(b[:4])
+			page.WriteString(v)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package pdf renders documents such as invoices, packing slips and quotes to
+// PDF. Documents are html/template templates producing a small HTML subset,
+// laid out by a native flow engine:
+//
+//	<html><head><title>Invoice {{ .Number }}</title></head>
+//	<body size="A4" margin="40" font-size="10">
+//	  <header><img src="logo" width="120"/></header>
+//	  <h1>Invoice {{ .Number }}</h1>
+//	  <table widths="*,60,80" border="0.5">
+//	    <tr><th>Item</th><th align="right">Qty</th><th align="right">Total</th></tr>
+//	    {{ range .Lines }}<tr><td>{{ .Name }}</td><td align="right">{{ .Quantity }}</td>
+//	    <td align="right">{{ money .Total $.Currency }}</td></tr>{{ end }}
+//	  </table>
+//	  <footer><p align="center" size="8">Page <pagenumber/> of <pagecount/></p></footer>
+//	</body></html>
+//
+// Templates can use the money and roundMoney helpers of pkg/money and the t and
+// tn helpers of pkg/i18n, bound to the locale of the render, and hasImage to check
+// whether an image is registered.
+//
+// Block elements are h1-h3, p, div, table (tr, th, td), ul/ol (li), hr, img,
+// spacer and pagebreak; inline elements are b, strong, i, em, span and br.
+// Elements take align, size, color and font attributes. Text uses the standard
+// Helvetica fonts unless TrueType fonts are registered, which are embedded.
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/qhato/ecommerce/pkg/i18n"
+	"github.com/qhato/ecommerce/pkg/money"
+)
+
+// DefaultFamily is the font family used unless a document chooses another
+const DefaultFamily = "helvetica"
+
+// ErrUnknownTemplate is returned when rendering a template that was not added
+var ErrUnknownTemplate = errors.New("unknown document template")
+
+// RenderOptions holds the settings of a render
+type RenderOptions struct {
+	Locale string // Formats amounts with the money helper and translates with t; defaults to the money package and message catalog locales
+}
+
+// Engine holds document templates, fonts and images and renders documents.
+// It is safe for concurrent use.
+type Engine struct {
+	mu        sync.RWMutex
+	templates *template.Template
+	families  map[string]*fontFamily
+	images    map[string]*Image
+	messages  *i18n.Catalog // Translates t and tn; nil renders their keys
+}
+
+// NewEngine creates an engine with the Helvetica family and no templates
+func NewEngine() *Engine {
+	e := &Engine{
+		families: map[string]*fontFamily{
+			DefaultFamily: {Helvetica, HelveticaBold, HelveticaOblique, HelveticaBoldOblique},
+		},
+		images: make(map[string]*Image),
+	}
+	e.templates = template.New("").Funcs(e.funcs(""))
+	return e
+}
+
+// funcs returns the template helpers of a render
+func (e *Engine) funcs(locale string) template.FuncMap {
+	funcs := template.FuncMap(money.TemplateFuncs(locale))
+	for name, fn := range i18n.TemplateFuncs(e.messages, locale) {
+		funcs[name] = fn
+	}
+	funcs["hasImage"] = func(name string) bool {
+		_, ok := e.images[name]
+		return ok
+	}
+	return funcs
+}
+
+// AddTemplate adds a template, replacing one with the same name
+func (e *Engine) AddTemplate(name, text string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := e.templates.New(name).Parse(text); err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return nil
+}
+
+// LoadTemplates adds the *.html files of a directory, named after the file
+// without its extension, so "invoice.html" replaces the "invoice" template
+func (e *Engine) LoadTemplates(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		if err := e.AddTemplate(strings.TrimSuffix(filepath.Base(path), ".html"), string(text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasTemplate reports whether a template was added
+func (e *Engine) HasTemplate(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.templates.Lookup(name) != nil
+}
+
+// SetMessages sets the message catalog templates translate with
+func (e *Engine) SetMessages(messages *i18n.Catalog) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.messages = messages
+}
+
+// RegisterFont adds a font to a family; documents choose it with font="family".
+// Styles missing from a family fall back to its regular font.
+func (e *Engine) RegisterFont(family string, style FontStyle, font Font) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	family = strings.ToLower(family)
+	fonts, ok := e.families[family]
+	if !ok {
+		fonts = &fontFamily{}
+		e.families[family] = fonts
+	}
+	fonts[style] = font
+}
+
+// RegisterFontFile reads a TrueType font file and adds it to a family
+func (e *Engine) RegisterFontFile(family string, style FontStyle, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read font: %w", err)
+	}
+	font, err := ParseTrueType(data)
+	if err != nil {
+		return fmt.Errorf("failed to load font %s: %w", path, err)
+	}
+	e.RegisterFont(family, style, font)
+	return nil
+}
+
+// RegisterImage adds an image documents place with <img src="name">
+func (e *Engine) RegisterImage(name string, img *Image) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.images[name] = img
+}
+
+// Render executes a template with data and lays the result out as a PDF
+func (e *Engine) Render(name string, data interface{}, opts RenderOptions) ([]byte, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.templates.Lookup(name) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	// Clone so the locale's helpers do not leak into concurrent renders
+	templates, err := e.templates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	templates.Funcs(e.funcs(opts.Locale))
+
+	var markup bytes.Buffer
+	if err := templates.ExecuteTemplate(&markup, name, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", name, err)
+	}
+	root, err := parseMarkup(&markup)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := layoutDocument(root, e.families, e.images)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lay out %s: %w", name, err)
+	}
+	return doc.bytes()
+}
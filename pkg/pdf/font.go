@@ -0,0 +1,188 @@
+package pdf
+
+import "strings"
+
+// FontStyle is a style of a font family
+type FontStyle int
+
+const (
+	Regular FontStyle = iota
+	Bold
+	Italic
+	BoldItalic
+)
+
+// Font measures and encodes text. Text is encoded with WinAnsiEncoding, which
+// covers Western European languages; other characters are printed as "?".
+type Font interface {
+	// Name returns the PostScript name of the font.
+	Name() string
+
+	// width returns the advance width of a WinAnsi character in 1/1000 of the font size.
+	width(code byte) float64
+}
+
+// fontFamily holds the fonts of a family by style
+type fontFamily [4]Font
+
+// style returns the font of a style, falling back to the closest one registered
+func (f *fontFamily) style(style FontStyle) Font {
+	if font := f[style]; font != nil {
+		return font
+	}
+	if style == BoldItalic && f[Bold] != nil {
+		return f[Bold]
+	}
+	if f[Regular] != nil {
+		return f[Regular]
+	}
+	for _, font := range f {
+		if font != nil {
+			return font
+		}
+	}
+	return Helvetica
+}
+
+// TextWidth returns the width of text set in a font at a size, in points
+func TextWidth(font Font, text string, size float64) float64 {
+	var width float64
+	for _, code := range encodeWinAnsi(text) {
+		width += font.width(code)
+	}
+	return width * size / 1000
+}
+
+// standardFont is one of the standard Type 1 fonts every PDF reader provides
+type standardFont struct {
+	name   string
+	widths [256]uint16
+}
+
+func (f *standardFont) Name() string {
+	return f.name
+}
+
+func (f *standardFont) width(code byte) float64 {
+	return float64(f.widths[code])
+}
+
+// Advance widths of the printable ASCII characters (32-126) in the Helvetica fonts
+var (
+	helveticaASCII = []uint16{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldASCII = []uint16{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// Advance widths of the WinAnsi symbols above ASCII in the Helvetica fonts;
+// accented letters take the width of their base letter
+var helveticaSymbols = map[byte]uint16{
+	0x80: 556, 0x82: 222, 0x83: 556, 0x84: 333, 0x85: 1000, 0x86: 556, 0x87: 556, 0x88: 333,
+	0x89: 1000, 0x8B: 333, 0x8C: 1000, 0x91: 222, 0x92: 222, 0x93: 333, 0x94: 333, 0x95: 350,
+	0x96: 556, 0x97: 1000, 0x98: 333, 0x99: 1000, 0x9B: 333, 0x9C: 944,
+	0xA0: 278, 0xA1: 333, 0xA2: 556, 0xA3: 556, 0xA4: 556, 0xA5: 556, 0xA6: 260, 0xA7: 556,
+	0xA8: 333, 0xA9: 737, 0xAA: 370, 0xAB: 556, 0xAC: 584, 0xAD: 333, 0xAE: 737, 0xAF: 333,
+	0xB0: 400, 0xB1: 584, 0xB2: 333, 0xB3: 333, 0xB4: 333, 0xB5: 556, 0xB6: 537, 0xB7: 278,
+	0xB8: 333, 0xB9: 333, 0xBA: 365, 0xBB: 556, 0xBC: 834, 0xBD: 834, 0xBE: 834, 0xBF: 611,
+	0xC6: 1000, 0xD7: 584, 0xDE: 667, 0xDF: 611, 0xE6: 889, 0xF0: 556, 0xF7: 584, 0xFE: 556,
+}
+
+// newStandardFont builds the width table of a standard font
+func newStandardFont(name string, ascii []uint16) *standardFont {
+	f := &standardFont{name: name}
+	for i := range f.widths {
+		f.widths[i] = 556
+	}
+	for i, w := range ascii {
+		f.widths[32+i] = w
+	}
+	for code, w := range helveticaSymbols {
+		f.widths[code] = w
+	}
+	for code := 0x80; code <= 0xFF; code++ {
+		if base := accentBase(rune(winAnsiRunes[code])); base != 0 {
+			f.widths[code] = f.widths[base]
+		}
+	}
+	return f
+}
+
+// Standard Helvetica fonts, used when no font is registered
+var (
+	Helvetica            Font = newStandardFont("Helvetica", helveticaASCII)
+	HelveticaBold        Font = newStandardFont("Helvetica-Bold", helveticaBoldASCII)
+	HelveticaOblique     Font = newStandardFont("Helvetica-Oblique", helveticaASCII)
+	HelveticaBoldOblique Font = newStandardFont("Helvetica-BoldOblique", helveticaBoldASCII)
+)
+
+// accentBase returns the ASCII base letter of an accented Latin-1 letter, or 0
+func accentBase(r rune) byte {
+	const (
+		accented = "ÀÁÂÃÄÅÇÈÉÊËÌÍÎÏÐÑÒÓÔÕÖØÙÚÛÜÝàáâãäåçèéêëìíîïñòóôõöøùúûüýÿŠšŽžŸ"
+		bases    = "AAAAAACEEEEIIIIDNOOOOOOUUUUYaaaaaaceeeeiiiinoooooouuuuyySsZzY"
+	)
+	if i := strings.IndexRune(accented, r); i >= 0 {
+		return bases[len([]rune(accented[:i]))]
+	}
+	return 0
+}
+
+// winAnsiRunes maps WinAnsi codes to Unicode; codes 0xA0-0xFF match Latin-1
+var winAnsiRunes = func() [256]rune {
+	var runes [256]rune
+	for i := range runes {
+		runes[i] = rune(i)
+	}
+	high := []rune{
+		'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+		0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+	}
+	for i, r := range high {
+		runes[0x80+i] = r
+	}
+	return runes
+}()
+
+// winAnsiCodes maps Unicode to WinAnsi codes
+var winAnsiCodes = func() map[rune]byte {
+	codes := make(map[rune]byte, 256)
+	for code, r := range winAnsiRunes {
+		if r != 0 && (code < 0x80 || code >= 0xA0 || r != rune(code)) {
+			codes[r] = byte(code)
+		}
+	}
+	return codes
+}()
+
+// encodeWinAnsi encodes text as WinAnsi; unsupported characters become "?"
+func encodeWinAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r < 0x7F:
+			encoded = append(encoded, byte(r))
+		case r == '\t' || r == '\n' || r == '\r':
+			encoded = append(encoded, ' ')
+		default:
+			if code, ok := winAnsiCodes[r]; ok && code >= 0x20 {
+				encoded = append(encoded, code)
+			} else {
+				encoded = append(encoded, '?')
+			}
+		}
+	}
+	return encoded
+}
@@ -0,0 +1,80 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // JPEG and PNG images are supported
+	_ "image/png"
+)
+
+// Image is a raster image, such as a logo, placed with <img src="name">
+type Image struct {
+	width      int
+	height     int
+	colorSpace string
+	filter     string
+	decode     string
+	data       []byte
+}
+
+// NewImage reads a JPEG or PNG image. JPEGs are embedded as they are; other images
+// are decoded and embedded losslessly, with transparency blended onto white.
+func NewImage(data []byte) (*Image, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	if format == "jpeg" {
+		img := &Image{width: config.Width, height: config.Height, filter: "DCTDecode", data: data}
+		switch config.ColorModel {
+		case color.GrayModel:
+			img.colorSpace = "DeviceGray"
+		case color.CMYKModel:
+			img.colorSpace = "DeviceCMYK"
+			img.decode = "[1 0 1 0 1 0 1 0]" // Adobe CMYK JPEGs are stored inverted
+		default:
+			img.colorSpace = "DeviceRGB"
+		}
+		return img, nil
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	bounds := decoded.Bounds()
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := decoded.At(x, y).RGBA()
+			// Colors are premultiplied by alpha; add white for the transparent part
+			white := 0xFFFF - a
+			pixels = append(pixels, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+	}
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(pixels); err != nil {
+		return nil, fmt.Errorf("failed to compress image: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress image: %w", err)
+	}
+	return &Image{
+		width:      bounds.Dx(),
+		height:     bounds.Dy(),
+		colorSpace: "DeviceRGB",
+		filter:     "FlateDecode",
+		data:       compressed.Bytes(),
+	}, nil
+}
+
+// Size returns the size of the image in pixels
+func (i *Image) Size() (width, height int) {
+	return i.width, i.height
+}
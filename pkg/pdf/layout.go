@@ -0,0 +1,688 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lineHeight is the height of a line of text relative to its font size
+const lineHeight = 1.25
+
+// Page sizes in points, portrait
+var pageSizes = map[string][2]float64{
+	"a4":     {595.28, 841.89},
+	"a5":     {419.53, 595.28},
+	"letter": {612, 792},
+	"legal":  {612, 1008},
+}
+
+// Font size and space after of headings
+var headingStyles = map[string][2]float64{
+	"h1": {20, 10},
+	"h2": {16, 8},
+	"h3": {13, 6},
+}
+
+// blockTags are the elements laid out as blocks; everything else flows inline
+var blockTags = map[string]bool{
+	"head": true, "title": true, "header": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "p": true, "div": true,
+	"table": true, "ul": true, "ol": true, "hr": true, "img": true,
+	"spacer": true, "pagebreak": true,
+}
+
+// style is the inherited text style
+type style struct {
+	family    *fontFamily
+	fontStyle FontStyle
+	size      float64
+	color     Color
+	align     string
+}
+
+func (s style) font() Font {
+	return s.family.style(s.fontStyle)
+}
+
+// layout flows a document onto pages, top to bottom
+type layout struct {
+	doc      *document
+	families map[string]*fontFamily
+	images   map[string]*Image
+
+	page        *page
+	y           float64
+	top, bottom float64 // Body area of each page
+	fixed       bool    // No page breaks: headers, footers, table cells and measuring
+	pageNumber  int
+	pageCount   int
+	err         error
+}
+
+// layoutDocument lays out a parsed template. The body sets the page:
+//
+//	<body size="A4" orientation="portrait" margin="40" font="helvetica" font-size="10">
+//
+// and its <header> and <footer> repeat on every page.
+func layoutDocument(root *node, families map[string]*fontFamily, images map[string]*Image) (*document, error) {
+	body := root.find("body")
+	if body == nil {
+		body = root
+	}
+
+	size, ok := pageSizes[strings.ToLower(body.attrs["size"])]
+	if !ok {
+		size = pageSizes["a4"]
+	}
+	width, height := size[0], size[1]
+	if strings.EqualFold(body.attrs["orientation"], "landscape") {
+		width, height = height, width
+	}
+	margin := body.number("margin", 40)
+
+	doc := newDocument(width, height)
+	if title := root.find("title"); title != nil {
+		doc.title = strings.TrimSpace(title.textContent())
+	}
+
+	l := &layout{doc: doc, families: families, images: images}
+	s := style{family: families[DefaultFamily], size: body.number("font-size", 10), align: "left"}
+	if family, ok := families[strings.ToLower(body.attrs["font"])]; ok {
+		s.family = family
+	}
+	if color, ok := parseColor(body.attrs["color"]); ok {
+		s.color = color
+	}
+
+	var header, footer *node
+	for _, child := range body.children {
+		switch child.tag {
+		case "header":
+			header = child
+		case "footer":
+			footer = child
+		}
+	}
+
+	contentWidth := width - 2*margin
+	var headerHeight, footerHeight float64
+	if header != nil {
+		headerHeight = l.measure(func() { l.blocks(header, l.inherit(header, s), margin, contentWidth) })
+	}
+	if footer != nil {
+		footerHeight = l.measure(func() { l.blocks(footer, l.inherit(footer, s), margin, contentWidth) })
+	}
+	l.top = margin
+	if headerHeight > 0 {
+		l.top += headerHeight + s.size
+	}
+	l.bottom = height - margin
+	if footerHeight > 0 {
+		l.bottom -= footerHeight + s.size
+	}
+	if l.bottom-l.top < 72 {
+		return nil, fmt.Errorf("header and footer leave no room for the document body")
+	}
+
+	l.newPage()
+	l.blocks(body, s, margin, contentWidth)
+
+	// Headers and footers go last, once the page count is known
+	l.fixed = true
+	l.pageCount = len(doc.pages)
+	for i, p := range doc.pages {
+		l.page, l.pageNumber = p, i+1
+		if header != nil {
+			l.y = margin
+			l.blocks(header, l.inherit(header, s), margin, contentWidth)
+		}
+		if footer != nil {
+			l.y = height - margin - footerHeight
+			l.blocks(footer, l.inherit(footer, s), margin, contentWidth)
+		}
+	}
+	if l.err != nil {
+		return nil, l.err
+	}
+	return doc, nil
+}
+
+func (l *layout) fail(err error) {
+	if l.err == nil {
+		l.err = err
+	}
+}
+
+func (l *layout) newPage() {
+	l.page = l.doc.addPage()
+	l.pageNumber = len(l.doc.pages)
+	l.y = l.top
+}
+
+// ensure starts a new page unless height fits in what is left of this one
+func (l *layout) ensure(height float64) {
+	if !l.fixed && l.y+height > l.bottom && l.y > l.top {
+		l.newPage()
+	}
+}
+
+// measure returns the height fn lays out, without drawing it
+func (l *layout) measure(fn func()) float64 {
+	current, y, fixed := l.page, l.y, l.fixed
+	l.page, l.fixed = &page{doc: l.doc}, true
+	fn()
+	height := l.y - y
+	l.page, l.y, l.fixed = current, y, fixed
+	return height
+}
+
+// inherit applies the style attributes of an element: font, size, color and align
+func (l *layout) inherit(n *node, s style) style {
+	if family, ok := l.families[strings.ToLower(n.attrs["font"])]; ok {
+		s.family = family
+	}
+	if size := n.number("size", 0); size > 0 {
+		s.size = size
+	}
+	if color, ok := parseColor(n.attrs["color"]); ok {
+		s.color = color
+	}
+	if align := n.attrs["align"]; align != "" {
+		s.align = strings.ToLower(align)
+	}
+	return s
+}
+
+// blocks lays out the children of a block element, gathering inline content into paragraphs
+func (l *layout) blocks(n *node, s style, x, width float64) {
+	var inline []*node
+	flush := func() {
+		if len(inline) > 0 {
+			l.paragraph(inline, s, x, width)
+			inline = nil
+		}
+	}
+	for _, child := range n.children {
+		if blockTags[child.tag] {
+			flush()
+			l.block(child, s, x, width)
+			continue
+		}
+		inline = append(inline, child)
+	}
+	flush()
+}
+
+func (l *layout) block(n *node, s style, x, width float64) {
+	switch n.tag {
+	case "h1", "h2", "h3":
+		heading := headingStyles[n.tag]
+		s.fontStyle, s.size = Bold, heading[0]
+		l.blocks(n, l.inherit(n, s), x, width)
+		l.y += heading[1]
+	case "p":
+		s = l.inherit(n, s)
+		l.blocks(n, s, x, width)
+		l.y += s.size * 0.6
+	case "div":
+		l.blocks(n, l.inherit(n, s), x, width)
+	case "hr":
+		thickness := n.number("thickness", 0.5)
+		color, ok := parseColor(n.attrs["color"])
+		if !ok {
+			color = Color{R: 0.6, G: 0.6, B: 0.6}
+		}
+		l.ensure(thickness + 8)
+		l.y += 4
+		l.page.line(x, l.y, x+width, l.y, thickness, color)
+		l.y += thickness + 4
+	case "spacer":
+		l.y += n.number("height", s.size)
+	case "pagebreak":
+		if !l.fixed {
+			l.newPage()
+		}
+	case "img":
+		l.image(n, s, x, width)
+	case "ul", "ol":
+		l.list(n, s, x, width)
+	case "table":
+		l.table(n, s, x, width)
+		l.y += 10
+	}
+	// head, title, header and footer are laid out by layoutDocument
+}
+
+// piece is a word, a breakable space or a line break in a paragraph
+type piece struct {
+	text      string
+	font      Font
+	size      float64
+	color     Color
+	width     float64
+	space     bool
+	lineBreak bool
+}
+
+// pieces splits inline content into pieces
+func (l *layout) pieces(nodes []*node, s style, out []piece) []piece {
+	for _, n := range nodes {
+		switch n.tag {
+		case "":
+			out = appendText(out, n.text, s)
+		case "br":
+			out = append(out, piece{size: s.size, lineBreak: true})
+		case "b", "strong":
+			s := s
+			s.fontStyle |= Bold
+			out = l.pieces(n.children, l.inherit(n, s), out)
+		case "i", "em":
+			s := s
+			s.fontStyle |= Italic
+			out = l.pieces(n.children, l.inherit(n, s), out)
+		case "pagenumber":
+			out = appendText(out, strconv.Itoa(l.pageNumber), s)
+		case "pagecount":
+			if l.pageCount > 0 {
+				out = appendText(out, strconv.Itoa(l.pageCount), s)
+			}
+		default:
+			out = l.pieces(n.children, l.inherit(n, s), out)
+		}
+	}
+	return out
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// appendText splits text into words and single spaces; runs of whitespace
+// collapse as in HTML, while non-breaking spaces are kept
+func appendText(out []piece, text string, s style) []piece {
+	font := s.font()
+	for text != "" {
+		end := strings.IndexFunc(text, isSpace)
+		if end == 0 {
+			end = strings.IndexFunc(text, func(r rune) bool { return !isSpace(r) })
+			if end < 0 {
+				end = len(text)
+			}
+			if len(out) == 0 || !out[len(out)-1].space {
+				out = append(out, piece{text: " ", font: font, size: s.size, color: s.color, width: TextWidth(font, " ", s.size), space: true})
+			}
+			text = text[end:]
+			continue
+		}
+		if end < 0 {
+			end = len(text)
+		}
+		out = append(out, piece{text: text[:end], font: font, size: s.size, color: s.color, width: TextWidth(font, text[:end], s.size)})
+		text = text[end:]
+	}
+	return out
+}
+
+// paragraph fills lines with inline content, breaking at spaces
+func (l *layout) paragraph(nodes []*node, s style, x, width float64) {
+	pieces := l.pieces(nodes, s, nil)
+	var line []piece
+	var lineWidth float64
+	for i := 0; i < len(pieces); {
+		p := pieces[i]
+		switch {
+		case p.lineBreak:
+			l.line(line, s, x, width)
+			line, lineWidth = nil, 0
+			i++
+		case p.space:
+			if len(line) > 0 {
+				line = append(line, p)
+				lineWidth += p.width
+			}
+			i++
+		default:
+			// A word may span several pieces, e.g. "<b>Total</b>:"
+			j, wordWidth := i, 0.0
+			for ; j < len(pieces) && !pieces[j].space && !pieces[j].lineBreak; j++ {
+				wordWidth += pieces[j].width
+			}
+			if len(line) > 0 && lineWidth+wordWidth > width {
+				l.line(line, s, x, width)
+				line, lineWidth = nil, 0
+			}
+			line = append(line, pieces[i:j]...)
+			lineWidth += wordWidth
+			i = j
+		}
+	}
+	if len(line) > 0 {
+		l.line(line, s, x, width)
+	}
+}
+
+// baseline returns the baseline of a line of text set at a size, from the top of the line
+func baseline(size float64) float64 {
+	return size * (lineHeight - 0.3)
+}
+
+// line draws a line of pieces; an empty line is a blank line of the style's size
+func (l *layout) line(pieces []piece, s style, x, width float64) {
+	for len(pieces) > 0 && pieces[len(pieces)-1].space {
+		pieces = pieces[:len(pieces)-1]
+	}
+	size := s.size
+	var lineWidth float64
+	for i, p := range pieces {
+		if i == 0 || p.size > size {
+			size = p.size
+		}
+		lineWidth += p.width
+	}
+	l.ensure(size * lineHeight)
+
+	switch s.align {
+	case "center":
+		x += (width - lineWidth) / 2
+	case "right":
+		x += width - lineWidth
+	}
+	top := l.y + baseline(size)
+	// Pieces of the same style are drawn as one string
+	for i := 0; i < len(pieces); {
+		j, text, runWidth := i, "", 0.0
+		for ; j < len(pieces) && pieces[j].font == pieces[i].font && pieces[j].size == pieces[i].size && pieces[j].color == pieces[i].color; j++ {
+			text += pieces[j].text
+			runWidth += pieces[j].width
+		}
+		l.page.text(x, top, pieces[i].font, pieces[i].size, pieces[i].color, text)
+		x += runWidth
+		i = j
+	}
+	l.y += size * lineHeight
+}
+
+// image places a registered image: <img src="logo" width="120">. With one
+// dimension given the other keeps the aspect ratio; with none, pixels are 0.75pt.
+func (l *layout) image(n *node, s style, x, width float64) {
+	img, ok := l.images[n.attrs["src"]]
+	if !ok {
+		l.fail(fmt.Errorf("unknown image %q", n.attrs["src"]))
+		return
+	}
+	pixelWidth, pixelHeight := img.Size()
+	if pixelWidth == 0 || pixelHeight == 0 {
+		return
+	}
+	aspect := float64(pixelHeight) / float64(pixelWidth)
+	w, h := n.number("width", 0), n.number("height", 0)
+	switch {
+	case w <= 0 && h <= 0:
+		w, h = float64(pixelWidth)*0.75, float64(pixelHeight)*0.75
+	case w <= 0:
+		w = h / aspect
+	case h <= 0:
+		h = w * aspect
+	}
+	if w > width {
+		w, h = width, width*aspect
+	}
+	l.ensure(h)
+
+	switch l.inherit(n, s).align {
+	case "center":
+		x += (width - w) / 2
+	case "right":
+		x += width - w
+	}
+	l.page.image(img, x, l.y, w, h)
+	l.y += h
+}
+
+// list lays out <ul> with bullets or <ol> with numbers
+func (l *layout) list(n *node, s style, x, width float64) {
+	s = l.inherit(n, s)
+	indent := s.size * 1.5
+	number := 0
+	for _, item := range n.children {
+		if item.tag != "li" {
+			continue
+		}
+		number++
+		marker := "•"
+		if n.tag == "ol" {
+			marker = strconv.Itoa(number) + "."
+		}
+		l.ensure(s.size * lineHeight)
+		l.page.text(x, l.y+baseline(s.size), s.font(), s.size, s.color, marker)
+		l.blocks(item, l.inherit(item, s), x+indent, width-indent)
+	}
+	l.y += s.size * 0.6
+}
+
+// tableRow is a row of a table with the column each cell starts at
+type tableRow struct {
+	node    *node
+	cells   []tableCell
+	header  bool
+	allTh   bool
+	columns int
+}
+
+type tableCell struct {
+	node   *node
+	column int
+	span   int
+}
+
+// collectRows gathers the rows of a table, looking into <thead>, <tbody> and <tfoot>
+func collectRows(n *node, header bool, rows []tableRow) []tableRow {
+	for _, child := range n.children {
+		switch child.tag {
+		case "thead":
+			rows = collectRows(child, true, rows)
+		case "tbody", "tfoot":
+			rows = collectRows(child, false, rows)
+		case "tr":
+			row := tableRow{node: child, header: header, allTh: true}
+			for _, cell := range child.children {
+				if cell.tag != "td" && cell.tag != "th" {
+					continue
+				}
+				span := int(cell.number("colspan", 1))
+				if span < 1 {
+					span = 1
+				}
+				row.cells = append(row.cells, tableCell{node: cell, column: row.columns, span: span})
+				row.columns += span
+				row.allTh = row.allTh && cell.tag == "th"
+			}
+			row.allTh = row.allTh && len(row.cells) > 0
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// columnWidths resolves widths="40%,120,*": percentages of the table width,
+// points, or a share of what is left
+func columnWidths(spec string, columns int, width float64) []float64 {
+	parts := strings.Split(spec, ",")
+	widths := make([]float64, columns)
+	var used float64
+	flexible := 0
+	for i := range widths {
+		part := ""
+		if i < len(parts) {
+			part = strings.TrimSpace(parts[i])
+		}
+		if strings.HasSuffix(part, "%") {
+			if v, err := strconv.ParseFloat(strings.TrimSuffix(part, "%"), 64); err == nil && v > 0 {
+				widths[i] = width * v / 100
+				used += widths[i]
+				continue
+			}
+		} else if v, err := strconv.ParseFloat(strings.TrimSuffix(part, "pt"), 64); err == nil && v > 0 {
+			widths[i] = v
+			used += v
+			continue
+		}
+		widths[i] = -1
+		flexible++
+	}
+	if flexible > 0 {
+		share := (width - used) / float64(flexible)
+		if share < 0 {
+			share = 0
+		}
+		for i := range widths {
+			if widths[i] < 0 {
+				widths[i] = share
+			}
+		}
+	}
+	return widths
+}
+
+// tableLayout holds the resolved settings of a table
+type tableLayout struct {
+	x           float64
+	widths      []float64
+	padding     float64
+	border      float64
+	borderColor Color
+	style       style
+}
+
+// span returns the position and width of cells spanning columns
+func (t *tableLayout) span(column, count int) (x, width float64) {
+	x = t.x
+	for i := 0; i < column && i < len(t.widths); i++ {
+		x += t.widths[i]
+	}
+	for i := column; i < column+count && i < len(t.widths); i++ {
+		width += t.widths[i]
+	}
+	return x, width
+}
+
+// table lays out a table. Attributes: widths, border (line width), border-color
+// and cellpadding; rows and cells take bg and the text style attributes. Header
+// rows, from <thead> or leading rows of <th> cells, repeat on every page.
+func (l *layout) table(n *node, s style, x, width float64) {
+	s = l.inherit(n, s)
+	rows := collectRows(n, false, nil)
+	columns := 0
+	hasHead := false
+	for _, row := range rows {
+		if row.columns > columns {
+			columns = row.columns
+		}
+		hasHead = hasHead || row.header
+	}
+	if columns == 0 {
+		return
+	}
+	if !hasHead {
+		for i := 0; i < len(rows) && rows[i].allTh; i++ {
+			rows[i].header = true
+		}
+	}
+
+	t := &tableLayout{
+		x:       x,
+		widths:  columnWidths(n.attrs["widths"], columns, width),
+		padding: n.number("cellpadding", 4),
+		border:  n.number("border", 0),
+		style:   s,
+	}
+	if color, ok := parseColor(n.attrs["border-color"]); ok {
+		t.borderColor = color
+	}
+
+	var headers []tableRow
+	for _, row := range rows {
+		if row.header {
+			headers = append(headers, row)
+		}
+		height := l.rowHeight(t, row)
+		if !l.fixed && l.y+height > l.bottom && l.y > l.top {
+			l.newPage()
+			if !row.header {
+				for _, header := range headers {
+					l.row(t, header, l.rowHeight(t, header))
+				}
+			}
+		}
+		l.row(t, row, height)
+	}
+}
+
+// cellStyle returns the style of a cell: <th> cells are bold
+func (l *layout) cellStyle(t *tableLayout, row tableRow, cell tableCell) style {
+	s := l.inherit(row.node, t.style)
+	if cell.node.tag == "th" {
+		s.fontStyle |= Bold
+	}
+	return l.inherit(cell.node, s)
+}
+
+// cell lays out the content of a cell from the current position
+func (l *layout) cell(t *tableLayout, row tableRow, cell tableCell) {
+	x, width := t.span(cell.column, cell.span)
+	l.y += t.padding
+	l.blocks(cell.node, l.cellStyle(t, row, cell), x+t.padding, width-2*t.padding)
+	l.y += t.padding
+}
+
+// rowHeight returns the height of the tallest cell of a row
+func (l *layout) rowHeight(t *tableLayout, row tableRow) float64 {
+	var height float64
+	for _, cell := range row.cells {
+		if h := l.measure(func() { l.cell(t, row, cell) }); h > height {
+			height = h
+		}
+	}
+	return height
+}
+
+// row draws a row: backgrounds, then content, then borders
+func (l *layout) row(t *tableLayout, row tableRow, height float64) {
+	top := l.y
+	rowBackground, hasRowBackground := parseColor(row.node.attrs["bg"])
+	for _, cell := range row.cells {
+		background, ok := parseColor(cell.node.attrs["bg"])
+		if !ok {
+			background, ok = rowBackground, hasRowBackground
+		}
+		if ok {
+			x, width := t.span(cell.column, cell.span)
+			l.page.rect(x, top, width, height, background)
+		}
+	}
+
+	fixed := l.fixed
+	l.fixed = true
+	for _, cell := range row.cells {
+		l.y = top
+		l.cell(t, row, cell)
+	}
+	l.fixed = fixed
+	l.y = top + height
+
+	_, tableWidth := t.span(0, len(t.widths))
+	if t.border > 0 {
+		l.page.line(t.x, top, t.x+tableWidth, top, t.border, t.borderColor)
+		l.page.line(t.x, l.y, t.x+tableWidth, l.y, t.border, t.borderColor)
+		l.page.line(t.x, top, t.x, l.y, t.border, t.borderColor)
+		for _, cell := range row.cells {
+			x, width := t.span(cell.column, cell.span)
+			l.page.line(x+width, top, x+width, l.y, t.border, t.borderColor)
+		}
+	}
+	// rule="0.5" draws a line under a single row, e.g. below the header
+	if rule := row.node.number("rule", 0); rule > 0 {
+		l.page.line(t.x, l.y, t.x+tableWidth, l.y, rule, t.borderColor)
+	}
+}
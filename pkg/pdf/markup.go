@@ -0,0 +1,114 @@
+package pdf
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// node is an element or, when tag is empty, a run of text of a document template
+type node struct {
+	tag      string
+	attrs    map[string]string
+	text     string
+	children []*node
+}
+
+// parseMarkup reads the HTML subset documents are written in. Parsing is
+// lenient: void elements such as <br> and <hr> need no closing tag.
+func parseMarkup(r io.Reader) (*node, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	root := &node{tag: "#document"}
+	stack := []*node{root}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid document markup: %w", err)
+		}
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{tag: strings.ToLower(t.Name.Local), attrs: make(map[string]string, len(t.Attr))}
+			for _, attr := range t.Attr {
+				n.attrs[strings.ToLower(attr.Name.Local)] = attr.Value
+			}
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			tag := strings.ToLower(t.Name.Local)
+			// Close up to the matching element, tolerating unclosed children
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].tag == tag {
+					stack = stack[:i]
+					break
+				}
+			}
+		case xml.CharData:
+			parent.children = append(parent.children, &node{text: string(t)})
+		}
+	}
+	return root, nil
+}
+
+// find returns the first element with a tag, searching depth first
+func (n *node) find(tag string) *node {
+	for _, child := range n.children {
+		if child.tag == tag {
+			return child
+		}
+		if found := child.find(tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// textContent returns the text of a node and its descendants
+func (n *node) textContent() string {
+	if n.tag == "" {
+		return n.text
+	}
+	var b strings.Builder
+	for _, child := range n.children {
+		b.WriteString(child.textContent())
+	}
+	return b.String()
+}
+
+// number reads a numeric attribute, returning def when it is missing or invalid
+func (n *node) number(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(n.attrs[name]), "pt"), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// parseColor reads a #rgb or #rrggbb color
+func parseColor(s string) (Color, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return Color{}, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}, false
+	}
+	return Color{
+		R: float64(v>>16&0xFF) / 255,
+		G: float64(v>>8&0xFF) / 255,
+		B: float64(v&0xFF) / 255,
+	}, true
+}
@@ -0,0 +1,147 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// ErrQueueFull is returned when the rendering queue has no room for a job
+var ErrQueueFull = errors.New("pdf rendering queue is full")
+
+// ErrQueueClosed is returned when rendering after the queue was closed
+var ErrQueueClosed = errors.New("pdf rendering queue is closed")
+
+// QueueConfig holds the settings of a rendering queue
+type QueueConfig struct {
+	Workers int           // Documents rendered at once
+	Size    int           // Jobs waiting for a worker before Render fails with ErrQueueFull
+	Timeout time.Duration // Longest a caller waits, queued and rendering; zero waits for the caller's context
+}
+
+// Queue bounds the rendering work of an engine: a fixed pool of workers takes
+// jobs from a bounded queue, so bursts of document requests cannot exhaust CPU
+// and memory
+type Queue struct {
+	engine  *Engine
+	jobs    chan *renderJob
+	timeout time.Duration
+	logger  *logger.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type renderJob struct {
+	ctx    context.Context
+	name   string
+	data   interface{}
+	opts   RenderOptions
+	result chan renderResult
+}
+
+type renderResult struct {
+	pdf []byte
+	err error
+}
+
+// NewQueue creates a queue and starts its workers
+func NewQueue(engine *Engine, cfg QueueConfig, log *logger.Logger) *Queue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 2
+	}
+	if cfg.Size < 0 {
+		cfg.Size = 0
+	}
+	q := &Queue{
+		engine:  engine,
+		jobs:    make(chan *renderJob, cfg.Size),
+		timeout: cfg.Timeout,
+		logger:  log,
+	}
+	q.wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+// Render queues a document and waits for it. It fails fast with ErrQueueFull
+// when the queue is full, and gives up when ctx is done or the timeout passes.
+func (q *Queue) Render(ctx context.Context, name string, data interface{}, opts RenderOptions) ([]byte, error) {
+	if q.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.timeout)
+		defer cancel()
+	}
+
+	job := &renderJob{ctx: ctx, name: name, data: data, opts: opts, result: make(chan renderResult, 1)}
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return nil, ErrQueueClosed
+	}
+	select {
+	case q.jobs <- job:
+		q.mu.RUnlock()
+	default:
+		q.mu.RUnlock()
+		return nil, ErrQueueFull
+	}
+
+	select {
+	case result := <-job.result:
+		return result.pdf, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops accepting jobs and waits for the queued ones to finish
+func (q *Queue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+	q.wg.Wait()
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		if err := job.ctx.Err(); err != nil {
+			job.result <- renderResult{err: err} // The caller gave up while the job was queued
+			continue
+		}
+		job.result <- q.render(job)
+	}
+}
+
+// render renders a job, turning a panic in a template or the layout into an error
+func (q *Queue) render(job *renderJob) (result renderResult) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result = renderResult{err: fmt.Errorf("failed to render %s: %v", job.name, r)}
+		}
+		if result.err != nil {
+			q.logger.WithError(result.err).WithField("template", job.name).Error("failed to render document")
+			return
+		}
+		q.logger.WithFields(logger.Fields{
+			"template": job.name,
+			"bytes":    len(result.pdf),
+			"duration": time.Since(start).String(),
+		}).Debug("rendered document")
+	}()
+
+	pdf, err := q.engine.Render(job.name, job.data, job.opts)
+	return renderResult{pdf: pdf, err: err}
+}
@@ -0,0 +1,245 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// TrueTypeFont is a TrueType font embedded in the documents that use it
+type TrueTypeFont struct {
+	name        string
+	data        []byte
+	widths      [256]float64 // Per WinAnsi code, in 1/1000 em
+	bbox        [4]float64
+	ascent      float64
+	descent     float64
+	capHeight   float64
+	italicAngle float64
+	bold        bool
+}
+
+// ParseTrueType reads a TrueType (.ttf) font. OpenType fonts with CFF outlines
+// (.otf) are not supported.
+func ParseTrueType(data []byte) (*TrueTypeFont, error) {
+	tables, err := readTableDirectory(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("invalid TrueType font: missing %s table", tag)
+		}
+	}
+	if _, ok := tables["glyf"]; !ok {
+		return nil, fmt.Errorf("unsupported font: only TrueType outlines can be embedded")
+	}
+
+	head := tables["head"]
+	if len(head) < 54 {
+		return nil, fmt.Errorf("invalid TrueType font: short head table")
+	}
+	unitsPerEm := float64(binary.BigEndian.Uint16(head[18:]))
+	if unitsPerEm == 0 {
+		return nil, fmt.Errorf("invalid TrueType font: zero units per em")
+	}
+	scale := func(v int16) float64 { return float64(v) * 1000 / unitsPerEm }
+
+	f := &TrueTypeFont{data: data}
+	f.bbox = [4]float64{
+		scale(int16(binary.BigEndian.Uint16(head[36:]))),
+		scale(int16(binary.BigEndian.Uint16(head[38:]))),
+		scale(int16(binary.BigEndian.Uint16(head[40:]))),
+		scale(int16(binary.BigEndian.Uint16(head[42:]))),
+	}
+	f.bold = binary.BigEndian.Uint16(head[44:])&1 != 0
+
+	hhea := tables["hhea"]
+	if len(hhea) < 36 {
+		return nil, fmt.Errorf("invalid TrueType font: short hhea table")
+	}
+	f.ascent = scale(int16(binary.BigEndian.Uint16(hhea[4:])))
+	f.descent = scale(int16(binary.BigEndian.Uint16(hhea[6:])))
+	numberOfHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	f.capHeight = f.ascent
+
+	if os2 := tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		f.capHeight = scale(int16(binary.BigEndian.Uint16(os2[88:])))
+	}
+	if post := tables["post"]; len(post) >= 8 {
+		f.italicAngle = float64(int32(binary.BigEndian.Uint32(post[4:]))) / 65536
+	}
+
+	glyphs, err := readCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	hmtx := tables["hmtx"]
+	if numberOfHMetrics == 0 || len(hmtx) < numberOfHMetrics*4 {
+		return nil, fmt.Errorf("invalid TrueType font: short hmtx table")
+	}
+	advance := func(glyph int) float64 {
+		if glyph >= numberOfHMetrics {
+			glyph = numberOfHMetrics - 1 // Monospaced tails repeat the last advance
+		}
+		return float64(binary.BigEndian.Uint16(hmtx[glyph*4:])) * 1000 / unitsPerEm
+	}
+	for code := 0; code < 256; code++ {
+		f.widths[code] = advance(glyphs[winAnsiRunes[code]]) // Missing characters use glyph 0
+	}
+
+	f.name = fontName(tables["name"])
+	if f.name == "" {
+		f.name = "EmbeddedFont"
+	}
+	return f, nil
+}
+
+// Name returns the PostScript name of the font
+func (f *TrueTypeFont) Name() string {
+	return f.name
+}
+
+func (f *TrueTypeFont) width(code byte) float64 {
+	return f.widths[code]
+}
+
+// readTableDirectory returns the tables of a font by tag
+func readTableDirectory(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("invalid TrueType font: too short")
+	}
+	switch version := binary.BigEndian.Uint32(data); version {
+	case 0x00010000, 0x74727565: // 1.0 or "true"
+	case 0x4F54544F: // "OTTO"
+		return nil, fmt.Errorf("unsupported font: only TrueType outlines can be embedded")
+	default:
+		return nil, fmt.Errorf("invalid TrueType font: unknown version %#x", version)
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+numTables*16 {
+		return nil, fmt.Errorf("invalid TrueType font: truncated table directory")
+	}
+	tables := make(map[string][]byte, numTables)
+	for i := 0; i < numTables; i++ {
+		entry := data[12+i*16:]
+		offset := int(binary.BigEndian.Uint32(entry[8:]))
+		length := int(binary.BigEndian.Uint32(entry[12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("invalid TrueType font: table %q exceeds the file", entry[:4])
+		}
+		tables[string(entry[:4])] = data[offset : offset+length]
+	}
+	return tables, nil
+}
+
+// readCmap maps the characters of the Unicode BMP subtable to glyph indexes
+func readCmap(cmap []byte) (map[rune]int, error) {
+	if len(cmap) < 4 {
+		return nil, fmt.Errorf("invalid TrueType font: short cmap table")
+	}
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	var subtable []byte
+	for i := 0; i < numTables && 4+i*8+8 <= len(cmap); i++ {
+		record := cmap[4+i*8:]
+		platform := binary.BigEndian.Uint16(record)
+		encoding := binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if offset+4 > len(cmap) {
+			continue
+		}
+		// Windows Unicode BMP (3,1), or Unicode (0,x); symbol fonts only have (3,0)
+		if (platform == 3 && (encoding == 1 || encoding == 0)) || platform == 0 {
+			if binary.BigEndian.Uint16(cmap[offset:]) == 4 {
+				subtable = cmap[offset:]
+				if platform == 3 && encoding == 1 {
+					break
+				}
+			}
+		}
+	}
+	if subtable == nil {
+		return nil, fmt.Errorf("unsupported font: no Unicode character map")
+	}
+
+	glyphs := make(map[rune]int)
+	if len(subtable) < 14 {
+		return nil, fmt.Errorf("invalid TrueType font: short character map")
+	}
+	segments := int(binary.BigEndian.Uint16(subtable[6:])) / 2
+	if len(subtable) < 16+segments*8 {
+		return nil, fmt.Errorf("invalid TrueType font: truncated character map")
+	}
+	endCodes := subtable[14:]
+	startCodes := subtable[16+segments*2:]
+	deltas := subtable[16+segments*4:]
+	rangeOffsets := subtable[16+segments*6:]
+
+	for s := 0; s < segments; s++ {
+		end := int(binary.BigEndian.Uint16(endCodes[s*2:]))
+		start := int(binary.BigEndian.Uint16(startCodes[s*2:]))
+		delta := int(binary.BigEndian.Uint16(deltas[s*2:]))
+		rangeOffset := int(binary.BigEndian.Uint16(rangeOffsets[s*2:]))
+		// Only characters WinAnsi can encode are needed
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			if _, ok := winAnsiCodes[rune(c)]; !ok {
+				continue
+			}
+			glyph := 0
+			if rangeOffset == 0 {
+				glyph = (c + delta) & 0xFFFF
+			} else {
+				at := 16 + segments*6 + s*2 + rangeOffset + (c-start)*2
+				if at+2 <= len(subtable) {
+					if glyph = int(binary.BigEndian.Uint16(subtable[at:])); glyph != 0 {
+						glyph = (glyph + delta) & 0xFFFF
+					}
+				}
+			}
+			glyphs[rune(c)] = glyph
+		}
+	}
+	return glyphs, nil
+}
+
+// fontName reads the PostScript name of a font, keeping the characters PDF names allow
+func fontName(name []byte) string {
+	if len(name) < 6 {
+		return ""
+	}
+	count := int(binary.BigEndian.Uint16(name[2:]))
+	storage := int(binary.BigEndian.Uint16(name[4:]))
+	for i := 0; i < count && 6+i*12+12 <= len(name); i++ {
+		record := name[6+i*12:]
+		platform := binary.BigEndian.Uint16(record)
+		nameID := binary.BigEndian.Uint16(record[6:])
+		length := int(binary.BigEndian.Uint16(record[8:]))
+		offset := storage + int(binary.BigEndian.Uint16(record[10:]))
+		if nameID != 6 || offset+length > len(name) {
+			continue
+		}
+		raw := name[offset : offset+length]
+		var b strings.Builder
+		if platform == 3 || platform == 0 {
+			for j := 0; j+1 < len(raw); j += 2 {
+				b.WriteRune(rune(binary.BigEndian.Uint16(raw[j:])))
+			}
+		} else {
+			b.Write(raw)
+		}
+		return sanitizeName(b.String())
+	}
+	return ""
+}
+
+// sanitizeName keeps the letters, digits and hyphens of a name
+func sanitizeName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,283 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Color is an RGB color with components from 0 to 1
+type Color struct {
+	R, G, B float64
+}
+
+// Black is the default text color
+var Black = Color{}
+
+// document is a laid out document: pages of drawing operators and the fonts and
+// images they use
+type document struct {
+	width, height float64
+	title         string
+	pages         []*page
+	fonts         []Font
+	fontNames     map[Font]string
+	images        []*Image
+	imageNames    map[*Image]string
+}
+
+func newDocument(width, height float64) *document {
+	return &document{
+		width:      width,
+		height:     height,
+		fontNames:  make(map[Font]string),
+		imageNames: make(map[*Image]string),
+	}
+}
+
+// page holds the content stream of a page; coordinates given to its methods
+// have their origin at the top left corner
+type page struct {
+	doc     *document
+	content bytes.Buffer
+}
+
+func (d *document) addPage() *page {
+	p := &page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+func (d *document) fontResource(font Font) string {
+	name, ok := d.fontNames[font]
+	if !ok {
+		d.fonts = append(d.fonts, font)
+		name = "F" + strconv.Itoa(len(d.fonts))
+		d.fontNames[font] = name
+	}
+	return name
+}
+
+func (d *document) imageResource(img *Image) string {
+	name, ok := d.imageNames[img]
+	if !ok {
+		d.images = append(d.images, img)
+		name = "Im" + strconv.Itoa(len(d.images))
+		d.imageNames[img] = name
+	}
+	return name
+}
+
+// text draws text with its baseline at y
+func (p *page) text(x, y float64, font Font, size float64, color Color, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s rg %s %s Td %s Tj ET\n",
+		p.doc.fontResource(font), num(size), color.operands(), num(x), num(p.doc.height-y), literal(encodeWinAnsi(text)))
+}
+
+// line strokes a line
+func (p *page) line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s w %s RG %s %s m %s %s l S\n",
+		num(width), color.operands(), num(x1), num(p.doc.height-y1), num(x2), num(p.doc.height-y2))
+}
+
+// rect fills a rectangle whose top left corner is at x, y
+func (p *page) rect(x, y, width, height float64, color Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n",
+		color.operands(), num(x), num(p.doc.height-y-height), num(width), num(height))
+}
+
+// image draws an image whose top left corner is at x, y
+func (p *page) image(img *Image, x, y, width, height float64) {
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /%s Do Q\n",
+		num(width), num(height), num(x), num(p.doc.height-y-height), p.doc.imageResource(img))
+}
+
+func (c Color) operands() string {
+	return num(c.R) + " " + num(c.G) + " " + num(c.B)
+}
+
+// num formats a number for a content stream
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 3, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// literal encodes bytes as a PDF literal string
+func literal(b []byte) string {
+	var s strings.Builder
+	s.WriteByte('(')
+	for _, c := range b {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			s.WriteByte('\\')
+			s.WriteByte(c)
+		case c < 0x20 || c > 0x7E:
+			fmt.Fprintf(&s, "\\%03o", c)
+		default:
+			s.WriteByte(c)
+		}
+	}
+	s.WriteByte(')')
+	return s.String()
+}
+
+// textString encodes text for the document information dictionary as UTF-16
+func textString(text string) string {
+	var s strings.Builder
+	s.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&s, "%04X", unit)
+	}
+	s.WriteByte('>')
+	return s.String()
+}
+
+// objectWriter serializes numbered objects and the cross-reference table
+type objectWriter struct {
+	buf     bytes.Buffer
+	offsets []int // Byte offset of each object, indexed by object number - 1
+}
+
+// reserve allocates an object number
+func (w *objectWriter) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+// object writes an object under a reserved number
+func (w *objectWriter) object(n int, body string) {
+	w.offsets[n-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+// stream writes a stream object; extra holds additional dictionary entries
+func (w *objectWriter) stream(n int, extra string, data []byte, compress bool) error {
+	if compress {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(data); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		data = compressed.Bytes()
+		extra += " /Filter /FlateDecode"
+	}
+	w.offsets[n-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d%s >>\nstream\n", n, len(data), extra)
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+// bytes returns the document with the cross-reference table and trailer
+func (d *document) bytes() ([]byte, error) {
+	w := &objectWriter{}
+	w.buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	catalog := w.reserve()
+	pagesRoot := w.reserve()
+	info := w.reserve()
+
+	// Fonts and images first, so pages can refer to them
+	var resources strings.Builder
+	resources.WriteString("<< /Font <<")
+	for _, font := range d.fonts {
+		n, err := w.writeFont(font)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&resources, " /%s %d 0 R", d.fontNames[font], n)
+	}
+	resources.WriteString(" >>")
+	if len(d.images) > 0 {
+		resources.WriteString(" /XObject <<")
+		for _, img := range d.images {
+			n := w.reserve()
+			extra := fmt.Sprintf(" /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s",
+				img.width, img.height, img.colorSpace, img.filter)
+			if img.decode != "" {
+				extra += " /Decode " + img.decode
+			}
+			if err := w.stream(n, extra, img.data, false); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&resources, " /%s %d 0 R", d.imageNames[img], n)
+		}
+		resources.WriteString(" >>")
+	}
+	resources.WriteString(" /ProcSet [/PDF /Text /ImageB /ImageC] >>")
+
+	kids := make([]string, 0, len(d.pages))
+	for _, p := range d.pages {
+		content := w.reserve()
+		if err := w.stream(content, "", p.content.Bytes(), true); err != nil {
+			return nil, err
+		}
+		n := w.reserve()
+		w.object(n, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pagesRoot, num(d.width), num(d.height), resources.String(), content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", n))
+	}
+
+	w.object(pagesRoot, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	w.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesRoot))
+	infoDict := fmt.Sprintf("<< /Producer (qhato-ecommerce) /CreationDate (D:%s)", time.Now().UTC().Format("20060102150405Z"))
+	if d.title != "" {
+		infoDict += " /Title " + textString(d.title)
+	}
+	w.object(info, infoDict+" >>")
+
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, catalog, info, xref)
+	return w.buf.Bytes(), nil
+}
+
+// writeFont writes a font dictionary, embedding TrueType fonts, and returns its object number
+func (w *objectWriter) writeFont(font Font) (int, error) {
+	n := w.reserve()
+	ttf, ok := font.(*TrueTypeFont)
+	if !ok {
+		w.object(n, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.Name()))
+		return n, nil
+	}
+
+	descriptor := w.reserve()
+	file := w.reserve()
+	widths := make([]string, 0, 224)
+	for code := 32; code < 256; code++ {
+		widths = append(widths, num(ttf.widths[code]))
+	}
+	w.object(n, fmt.Sprintf("<< /Type /Font /Subtype /TrueType /BaseFont /%s /FirstChar 32 /LastChar 255 /Widths [%s] /Encoding /WinAnsiEncoding /FontDescriptor %d 0 R >>",
+		ttf.name, strings.Join(widths, " "), descriptor))
+
+	flags := 32 // Nonsymbolic
+	if ttf.italicAngle != 0 {
+		flags |= 64
+	}
+	stemV := 80
+	if ttf.bold {
+		stemV = 120
+	}
+	w.object(descriptor, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [%s %s %s %s] /ItalicAngle %s /Ascent %s /Descent %s /CapHeight %s /StemV %d /FontFile2 %d 0 R >>",
+		ttf.name, flags, num(ttf.bbox[0]), num(ttf.bbox[1]), num(ttf.bbox[2]), num(ttf.bbox[3]),
+		num(ttf.italicAngle), num(ttf.ascent), num(ttf.descent), num(ttf.capHeight), stemV, file))
+	if err := w.stream(file, fmt.Sprintf(" /Length1 %d", len(ttf.data)), ttf.data, true); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryWindow struct {
+	start time.Time
+	count int64
+}
+
+// MemoryLimiter keeps counters in process memory; limits apply per instance
+type MemoryLimiter struct {
+	mu       sync.Mutex
+	counters map[string]*memoryWindow
+	sweptAt  time.Time
+}
+
+// NewMemoryLimiter creates an in-memory limiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{counters: make(map[string]*memoryWindow)}
+}
+
+// Allow increments the key's counter for the current window
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	now := time.Now()
+	start := windowStart(now, window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop expired windows at most once per window so the map does not grow unbounded
+	if now.Sub(l.sweptAt) >= window {
+		for k, w := range l.counters {
+			if w.start.Add(window).Before(now) {
+				delete(l.counters, k)
+			}
+		}
+		l.sweptAt = now
+	}
+
+	counter, ok := l.counters[key]
+	if !ok || !counter.start.Equal(start) {
+		counter = &memoryWindow{start: start}
+		l.counters[key] = counter
+	}
+	counter.count++
+
+	return newResult(counter.count, limit, start.Add(window)), nil
+}
+
+// Count returns the key's counter for the current window without incrementing it
+func (l *MemoryLimiter) Count(ctx context.Context, key string, window time.Duration) (int64, error) {
+	start := windowStart(time.Now(), window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	counter, ok := l.counters[key]
+	if !ok || !counter.start.Equal(start) {
+		return 0, nil
+	}
+	return counter.count, nil
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"strings"
+	"time"
+)
+
+// Subject prefixes of the clients limits are counted for
+const (
+	subjectIP       = "ip:"
+	subjectCustomer = "customer:"
+	subjectPartner  = "key:"
+)
+
+// IPSubject is the subject of anonymous requests from an IP
+func IPSubject(ip string) string { return subjectIP + ip }
+
+// CustomerSubject is the subject of a customer's authenticated requests
+func CustomerSubject(customerID string) string { return subjectCustomer + customerID }
+
+// PartnerSubject is the subject of the requests made with a partner's API key
+func PartnerSubject(partner string) string { return subjectPartner + partner }
+
+// SubjectPartner returns the partner of a partner subject
+func SubjectPartner(subject string) (string, bool) {
+	return strings.CutPrefix(subject, subjectPartner)
+}
+
+// ValidSubject reports whether a subject names an IP, customer or partner
+func ValidSubject(subject string) bool {
+	for _, prefix := range []string{subjectIP, subjectCustomer, subjectPartner} {
+		if id, ok := strings.CutPrefix(subject, prefix); ok {
+			return id != ""
+		}
+	}
+	return false
+}
+
+// Policy holds the limits of each kind of subject, outside buckets and in
+// each bucket
+type Policy struct {
+	Requests         int // Per IP per window
+	CustomerRequests int // Per customer and partner; 0 uses Requests
+	Window           time.Duration
+	Buckets          []Bucket
+	Partners         map[string]PartnerQuota // By partner
+}
+
+// Bucket holds the limits of expensive routes, such as search or checkout,
+// counted apart from the rest
+type Bucket struct {
+	Name             string
+	Requests         int // Per IP per window
+	CustomerRequests int // Per customer and partner; 0 uses Requests
+	Window           time.Duration
+}
+
+// PartnerQuota holds the limits of a partner integration
+type PartnerQuota struct {
+	Requests int            // Per window outside buckets; 0 uses the customer limit
+	Buckets  map[string]int // Per bucket; buckets missing use their customer limit
+}
+
+// Limit returns the limit and window of a subject in a bucket, false when
+// there is no such bucket
+func (p *Policy) Limit(subject, bucket string) (int, time.Duration, bool) {
+	requests, customerRequests, window := p.Requests, p.CustomerRequests, p.Window
+	if bucket != DefaultBucket {
+		b := p.bucket(bucket)
+		if b == nil {
+			return 0, 0, false
+		}
+		requests, customerRequests, window = b.Requests, b.CustomerRequests, b.Window
+	}
+	if strings.HasPrefix(subject, subjectIP) {
+		return requests, window, true
+	}
+
+	limit := requests
+	if customerRequests > 0 {
+		limit = customerRequests
+	}
+	if partner, ok := strings.CutPrefix(subject, subjectPartner); ok {
+		quota := p.Partners[partner]
+		if bucket == DefaultBucket && quota.Requests > 0 {
+			limit = quota.Requests
+		} else if quota.Buckets[bucket] > 0 {
+			limit = quota.Buckets[bucket]
+		}
+	}
+	return limit, window, true
+}
+
+// BucketNames returns the name of every bucket, the default one first
+func (p *Policy) BucketNames() []string {
+	names := []string{DefaultBucket}
+	for _, b := range p.Buckets {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+func (p *Policy) bucket(name string) *Bucket {
+	for i := range p.Buckets {
+		if p.Buckets[i].Name == name {
+			return &p.Buckets[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/snapshot"
+)
+
+// DefaultBucket counts the requests of routes without a bucket of their own
+const DefaultBucket = "default"
+
+// Override temporarily raises the limit of a subject in a bucket, such as a
+// partner running a catalog sync. Subjects are "customer:<id>", "key:<partner>"
+// or "ip:<address>".
+type Override struct {
+	ID        int64     `json:"id"`
+	Subject   string    `json:"subject"`
+	Bucket    string    `json:"bucket"`
+	Requests  int       `json:"requests"` // Requests allowed per window while the override lasts
+	ExpiresAt time.Time `json:"expires_at"`
+	Note      string    `json:"note,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Active reports whether the override still applies at now
+func (o *Override) Active(now time.Time) bool {
+	return now.Before(o.ExpiresAt)
+}
+
+// OverrideStore persists overrides; a subject has one override per bucket
+type OverrideStore interface {
+	// ListActive retrieves the overrides not expired at now
+	ListActive(ctx context.Context, now time.Time) ([]*Override, error)
+	// Save stores an override, replacing that of its subject and bucket
+	Save(ctx context.Context, override *Override) error
+	// Delete removes an override, reporting whether it existed
+	Delete(ctx context.Context, id int64) (bool, error)
+}
+
+// Inspector reads the counters of a limiter without counting a request
+type Inspector interface {
+	// Count returns the requests counted for key in the current window
+	Count(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// Quotas is a snapshot of the active overrides, stored by the admin and read
+// by each storefront instance, refreshed periodically so checking a limit
+// never hits the store. It is safe for concurrent use.
+type Quotas struct {
+	store     OverrideStore
+	overrides *snapshot.Snapshot[*Override] // subject/bucket -> override
+}
+
+// NewQuotas creates quotas without overrides until the first Refresh
+func NewQuotas(store OverrideStore, log *logger.Logger) *Quotas {
+	q := &Quotas{store: store}
+	q.overrides = snapshot.New(snapshot.Config[*Override]{
+		Name: "rate limit override",
+		Load: q.load,
+		Changed: func(previous, current *Override) bool {
+			return previous.Requests != current.Requests || !previous.ExpiresAt.Equal(current.ExpiresAt)
+		},
+		Report: func(_ string, override *Override) {
+			log.WithFields(logger.Fields{
+				"subject":    override.Subject,
+				"bucket":     override.Bucket,
+				"requests":   override.Requests,
+				"expires_at": override.ExpiresAt,
+			}).Info("Rate limit override applied")
+		},
+	}, nil, log)
+	return q
+}
+
+// Limit returns the limit of a subject in a bucket: that of its active
+// override, or limit without one
+func (q *Quotas) Limit(subject, bucket string, limit int) int {
+	override, ok := q.overrides.Get(overrideKey(subject, bucket))
+	if ok && override.Active(time.Now()) {
+		return override.Requests
+	}
+	return limit
+}
+
+// List returns the active overrides, ordered by subject and bucket
+func (q *Quotas) List() []*Override {
+	now := time.Now()
+	values := q.overrides.Values()
+	overrides := make([]*Override, 0, len(values))
+	for _, override := range values {
+		if override.Active(now) {
+			copied := *override
+			overrides = append(overrides, &copied)
+		}
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		if overrides[i].Subject != overrides[j].Subject {
+			return overrides[i].Subject < overrides[j].Subject
+		}
+		return overrides[i].Bucket < overrides[j].Bucket
+	})
+	return overrides
+}
+
+// Set stores an override; the snapshot changes right away here and on the
+// other servers at their next refresh
+func (q *Quotas) Set(ctx context.Context, override *Override) (*Override, error) {
+	if err := q.store.Save(ctx, override); err != nil {
+		return nil, err
+	}
+
+	q.overrides.Set(overrideKey(override.Subject, override.Bucket), override)
+	copied := *override
+	return &copied, nil
+}
+
+// Remove deletes an override, reporting whether it existed
+func (q *Quotas) Remove(ctx context.Context, id int64) (bool, error) {
+	deleted, err := q.store.Delete(ctx, id)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+
+	q.overrides.DeleteFunc(func(_ string, override *Override) bool { return override.ID == id })
+	return true, nil
+}
+
+// Refresh reloads the active overrides
+func (q *Quotas) Refresh(ctx context.Context) error {
+	return q.overrides.Refresh(ctx)
+}
+
+// StartScheduledRefresh refreshes the overrides periodically until ctx is cancelled
+func (q *Quotas) StartScheduledRefresh(ctx context.Context, interval time.Duration) {
+	q.overrides.StartScheduledRefresh(ctx, interval)
+}
+
+func (q *Quotas) load(ctx context.Context) (map[string]*Override, error) {
+	stored, err := q.store.ListActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[string]*Override, len(stored))
+	for _, override := range stored {
+		overrides[overrideKey(override.Subject, override.Bucket)] = override
+	}
+	return overrides, nil
+}
+
+// CounterKey is the limiter key counting the requests of a subject in a bucket
+func CounterKey(subject, bucket string) string {
+	if bucket == "" || bucket == DefaultBucket {
+		return subject
+	}
+	return bucket + ":" + subject
+}
+
+func overrideKey(subject, bucket string) string {
+	return subject + "/" + bucket
+}
@@ -0,0 +1,40 @@
+// Package ratelimit counts requests per key in fixed windows, backed by Redis
+// with an in-memory fallback when Redis is unavailable.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// Limiter checks and counts requests against a limit per window
+type Limiter interface {
+	// Allow counts a request for key and reports whether it is within limit
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error)
+}
+
+// windowStart returns the start of the fixed window containing now
+func windowStart(now time.Time, window time.Duration) time.Time {
+	return now.Truncate(window)
+}
+
+func newResult(count int64, limit int, resetAt time.Time) *Result {
+	remaining := limit - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &Result{
+		Allowed:   count <= int64(limit),
+		Limit:     limit,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}
+}
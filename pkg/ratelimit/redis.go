@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLimiter shares counters across instances through Redis
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLimiter creates a Redis-backed limiter
+func NewRedisLimiter(client *redis.Client, prefix string) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: prefix}
+}
+
+// Allow increments the key's counter for the current window
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	start := windowStart(time.Now(), window)
+	redisKey := l.prefix + ":ratelimit:" + key + ":" + strconv.FormatInt(start.Unix(), 10)
+
+	pipe := l.client.TxPipeline()
+	incr := pipe.Incr(ctx, redisKey)
+	pipe.ExpireNX(ctx, redisKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("redis rate limit error: %w", err)
+	}
+
+	return newResult(incr.Val(), limit, start.Add(window)), nil
+}
+
+// Count returns the key's counter for the current window without incrementing it
+func (l *RedisLimiter) Count(ctx context.Context, key string, window time.Duration) (int64, error) {
+	start := windowStart(time.Now(), window)
+	redisKey := l.prefix + ":ratelimit:" + key + ":" + strconv.FormatInt(start.Unix(), 10)
+
+	count, err := l.client.Get(ctx, redisKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("redis rate limit error: %w", err)
+	}
+	return count, nil
+}
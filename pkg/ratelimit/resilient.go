@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/circuitbreaker"
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// metrics is published at /debug/vars as "ratelimit_resilience"
+var metrics = expvar.NewMap("ratelimit_resilience")
+
+// ResilientLimiter counts through a primary limiter (Redis) and falls back to
+// per-instance in-memory counting while the primary is failing, so requests
+// are still limited but never rejected because of a backend outage.
+type ResilientLimiter struct {
+	primary  Limiter
+	fallback *MemoryLimiter
+	breaker  *circuitbreaker.Breaker
+	logger   *logger.Logger
+}
+
+// NewResilientLimiter wraps a primary limiter with a circuit breaker and in-memory fallback
+func NewResilientLimiter(primary Limiter, failureThreshold int, cooldown time.Duration, log *logger.Logger) *ResilientLimiter {
+	log = log.WithField("limiter", "ratelimit")
+	return &ResilientLimiter{
+		primary:  primary,
+		fallback: NewMemoryLimiter(),
+		breaker: circuitbreaker.New(circuitbreaker.Config{
+			Name:             "ratelimit",
+			FailureThreshold: failureThreshold,
+			Cooldown:         cooldown,
+			OnStateChange: func(name string, from, to circuitbreaker.State) {
+				metrics.Add("transitions."+string(to), 1)
+				log.WithField("from", from).WithField("to", to).Warn("rate limiter circuit breaker state changed")
+			},
+		}),
+		logger: log,
+	}
+}
+
+// Allow checks the primary limiter, or the in-memory fallback while it is unavailable.
+// The breaker probes the primary again after the cooldown.
+func (l *ResilientLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	if l.breaker.Allow() {
+		result, err := l.primary.Allow(ctx, key, limit, window)
+		if err == nil {
+			l.breaker.Success()
+			return result, nil
+		}
+		l.breaker.Failure()
+		metrics.Add("primary_errors", 1)
+		l.logger.WithError(err).Debug("rate limit backend failed, using in-memory fallback")
+	}
+
+	metrics.Add("fallback_checks", 1)
+	return l.fallback.Allow(ctx, key, limit, window)
+}
+
+// State returns the circuit breaker state
+func (l *ResilientLimiter) State() circuitbreaker.State {
+	return l.breaker.State()
+}
@@ -0,0 +1,104 @@
+package requestctx
+
+import "strings"
+
+// Localization is the locales and currencies a site offers its visitors
+type Localization struct {
+	DefaultLocale     string
+	DefaultCurrency   string
+	Locales           []string
+	Currencies        []string
+	LocaleCurrencies  map[string]string // Locale -> default currency
+	CountryCurrencies map[string]string // Country -> default currency for located visitors
+}
+
+// Localizations holds the localization of each site; sites without their own
+// use the default
+type Localizations struct {
+	Default Localization
+	Sites   map[string]Localization
+}
+
+// ForSite returns the localization of a site. Site IDs are matched
+// case-insensitively (config loaders may lowercase map keys).
+func (l Localizations) ForSite(siteID string) Localization {
+	for id, site := range l.Sites {
+		if strings.EqualFold(id, siteID) {
+			return site
+		}
+	}
+	return l.Default
+}
+
+// MatchLocale returns the offered locale matching a code. A bare language
+// ("es") matches the first offered locale of that language.
+func (l Localization) MatchLocale(code string) (string, bool) {
+	key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+	if key == "" {
+		return "", false
+	}
+	for _, locale := range l.Locales {
+		if strings.ToLower(locale) == key {
+			return locale, true
+		}
+	}
+	if !strings.Contains(key, "-") {
+		for _, locale := range l.Locales {
+			if strings.HasPrefix(strings.ToLower(locale), key+"-") {
+				return locale, true
+			}
+		}
+	}
+	return "", false
+}
+
+// MatchCurrency returns the offered currency matching a code
+func (l Localization) MatchCurrency(code string) (string, bool) {
+	key := strings.ToUpper(strings.TrimSpace(code))
+	if key == "" {
+		return "", false
+	}
+	for _, currency := range l.Currencies {
+		if strings.ToUpper(currency) == key {
+			return strings.ToUpper(currency), true
+		}
+	}
+	return "", false
+}
+
+// LocaleCurrency returns the default currency of a locale, or ""
+func (l Localization) LocaleCurrency(locale string) string {
+	for code, currency := range l.LocaleCurrencies {
+		if strings.EqualFold(code, locale) {
+			return currency
+		}
+	}
+	return ""
+}
+
+// CountryLocale returns the first offered locale of a country ("fr-CA" for CA), or ""
+func (l Localization) CountryLocale(country string) string {
+	if country == "" {
+		return ""
+	}
+	for _, locale := range l.Locales {
+		if i := strings.LastIndex(locale, "-"); i >= 0 && strings.EqualFold(locale[i+1:], country) {
+			return locale
+		}
+	}
+	return ""
+}
+
+// CountryCurrency returns the default currency of a country: the configured one,
+// else the currency of the country's locale, or ""
+func (l Localization) CountryCurrency(country string) string {
+	if country == "" {
+		return ""
+	}
+	for code, currency := range l.CountryCurrencies {
+		if strings.EqualFold(code, country) {
+			return currency
+		}
+	}
+	return l.LocaleCurrency(l.CountryLocale(country))
+}
@@ -0,0 +1,115 @@
+// Package requestctx carries the storefront context (site, locale, currency,
+// customer and session) resolved once per request by middleware.StorefrontContext.
+package requestctx
+
+import "context"
+
+// RequestContext is the storefront context resolved for a request
+type RequestContext struct {
+	SiteID     string
+	Locale     string // BCP 47 tag, e.g. "en-US"
+	Currency   string // ISO 4217 code, e.g. "USD"
+	CustomerID int64  // 0 for anonymous shoppers
+	SessionID  string
+	Country    string // ISO 3166-1 alpha-2 located by GeoIP for anonymous shoppers; "" when unknown
+	Region     string // ISO 3166-2 subdivision without the country, e.g. "CA"; "" when unknown
+}
+
+// PricingContext is the subset of the request context that affects prices
+type PricingContext struct {
+	SiteID     string
+	Currency   string
+	CustomerID int64
+}
+
+// IsAnonymous checks if the request has no authenticated customer
+func (c *RequestContext) IsAnonymous() bool {
+	return c.CustomerID == 0
+}
+
+// Pricing returns the pricing context of the request
+func (c *RequestContext) Pricing() PricingContext {
+	return PricingContext{
+		SiteID:     c.SiteID,
+		Currency:   c.Currency,
+		CustomerID: c.CustomerID,
+	}
+}
+
+type contextKey struct{}
+
+// WithRequestContext returns a copy of ctx carrying rc
+func WithRequestContext(ctx context.Context, rc *RequestContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, rc)
+}
+
+// FromContext returns the request context, or false when none was resolved
+// (e.g. admin requests, background jobs)
+func FromContext(ctx context.Context) (*RequestContext, bool) {
+	rc, ok := ctx.Value(contextKey{}).(*RequestContext)
+	return rc, ok && rc != nil
+}
+
+// SiteID returns the resolved site, or "" when none was resolved
+func SiteID(ctx context.Context) string {
+	if rc, ok := FromContext(ctx); ok {
+		return rc.SiteID
+	}
+	return ""
+}
+
+// Locale returns the resolved locale, or "" when none was resolved
+func Locale(ctx context.Context) string {
+	if rc, ok := FromContext(ctx); ok {
+		return rc.Locale
+	}
+	return ""
+}
+
+// Currency returns the resolved currency, or "" when none was resolved
+func Currency(ctx context.Context) string {
+	if rc, ok := FromContext(ctx); ok {
+		return rc.Currency
+	}
+	return ""
+}
+
+// CustomerID returns the authenticated customer, or 0 for anonymous requests
+func CustomerID(ctx context.Context) int64 {
+	if rc, ok := FromContext(ctx); ok {
+		return rc.CustomerID
+	}
+	return 0
+}
+
+// SessionID returns the storefront session, or "" when there is none
+func SessionID(ctx context.Context) string {
+	if rc, ok := FromContext(ctx); ok {
+		return rc.SessionID
+	}
+	return ""
+}
+
+// Country returns the visitor's located country, or "" when it is unknown
+func Country(ctx context.Context) string {
+	if rc, ok := FromContext(ctx); ok {
+		return rc.Country
+	}
+	return ""
+}
+
+// Region returns the visitor's located region, or "" when it is unknown
+func Region(ctx context.Context) string {
+	if rc, ok := FromContext(ctx); ok {
+		return rc.Region
+	}
+	return ""
+}
+
+// Pricing returns the pricing context, or a zero value when none was resolved
+func Pricing(ctx context.Context) PricingContext {
+	if rc, ok := FromContext(ctx); ok {
+		return rc.Pricing()
+	}
+	return PricingContext{}
+}
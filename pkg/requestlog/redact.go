@@ -0,0 +1,218 @@
+package requestlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces every secret or personal value
+const Redacted = "[REDACTED]"
+
+// Placeholders stored instead of bodies that cannot be redacted safely
+const (
+	omittedBody    = "[omitted: unsupported content type]"
+	unparsableBody = "[omitted: body could not be parsed for redaction]"
+)
+
+// sensitiveFields are matched against normalized field names (lowercase,
+// without separators), exactly or as a suffix: "token" covers access_token
+// and X-Refresh-Token, "password" covers newPassword.
+var sensitiveFields = []string{
+	// Credentials and secrets
+	"password", "passwd", "passphrase", "secret", "token", "apikey", "accesskey", "privatekey",
+	"authorization", "cookie", "otp", "mfacode", "signature",
+	// Card data
+	"cardnumber", "cvv", "cvv2", "cvc", "securitycode", "expirydate", "cardexpiry",
+	// Bank and tax identifiers
+	"iban", "accountnumber", "routingnumber", "ssn", "taxid",
+	// Personal data
+	"email", "phone", "phonenumber", "mobile", "dateofbirth", "birthdate", "dob",
+	"addressline1", "addressline2", "address1", "address2",
+}
+
+// Redactor strips secrets and personal data from request parts
+type Redactor struct {
+	fields []string
+}
+
+// NewRedactor creates a redactor for the built-in sensitive fields plus extra field names
+func NewRedactor(extra []string) *Redactor {
+	fields := append([]string{}, sensitiveFields...)
+	for _, field := range extra {
+		if normalized := normalizeField(field); normalized != "" {
+			fields = append(fields, normalized)
+		}
+	}
+	return &Redactor{fields: fields}
+}
+
+// IsSensitive reports whether a field, header or parameter name holds a secret or personal data
+func (r *Redactor) IsSensitive(name string) bool {
+	normalized := normalizeField(name)
+	if normalized == "" {
+		return false
+	}
+	for _, field := range r.fields {
+		if strings.HasSuffix(normalized, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Headers returns the request headers with sensitive values redacted. Repeated headers are joined.
+func (r *Redactor) Headers(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for name, values := range header {
+		if r.IsSensitive(name) {
+			redacted[name] = Redacted
+			continue
+		}
+		redacted[name] = r.value(strings.Join(values, ", "))
+	}
+	return redacted
+}
+
+// Query returns a raw query string with sensitive parameters redacted
+func (r *Redactor) Query(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return Redacted
+	}
+	r.values(values)
+	return values.Encode()
+}
+
+// Body returns a redacted copy of a body. JSON and form bodies are redacted
+// field by field; other content types, and bodies that do not parse (such as
+// truncated ones), are replaced by a placeholder since they cannot be checked.
+func (r *Redactor) Body(contentType string, body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return r.json(body)
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return unparsableBody
+		}
+		r.values(values)
+		return values.Encode()
+	default:
+		return omittedBody
+	}
+}
+
+func (r *Redactor) json(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return unparsableBody
+	}
+	redacted, err := json.Marshal(r.walk(doc))
+	if err != nil {
+		return unparsableBody
+	}
+	return string(redacted)
+}
+
+// walk redacts sensitive keys and card numbers anywhere in a decoded JSON document
+func (r *Redactor) walk(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if r.IsSensitive(key) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = r.walk(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.walk(item)
+		}
+		return v
+	case string:
+		return r.value(v)
+	case json.Number:
+		if isCardNumber(v.String()) {
+			return Redacted
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (r *Redactor) values(values url.Values) {
+	for key, list := range values {
+		for i, value := range list {
+			if r.IsSensitive(key) {
+				list[i] = Redacted
+			} else {
+				list[i] = r.value(value)
+			}
+		}
+	}
+}
+
+// value redacts free-standing card numbers that appear under innocuous names
+func (r *Redactor) value(value string) string {
+	if isCardNumber(value) {
+		return Redacted
+	}
+	return value
+}
+
+// isCardNumber reports whether a value is 13 to 19 digits, optionally
+// separated by spaces or dashes, passing the Luhn check
+func isCardNumber(value string) bool {
+	digits := make([]int, 0, 19)
+	for _, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, int(c-'0'))
+		case c == ' ' || c == '-':
+		default:
+			return false
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func normalizeField(name string) string {
+	return strings.Map(func(c rune) rune {
+		switch c {
+		case '_', '-', '.', ' ':
+			return -1
+		}
+		return c
+	}, strings.ToLower(name))
+}
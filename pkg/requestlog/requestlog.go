@@ -0,0 +1,111 @@
+// Package requestlog records admin API requests and responses for support
+// forensics. Entries are redacted before they leave the request: secrets and
+// personal data in headers, query strings and bodies never reach storage.
+// Entries are written asynchronously so a slow or failing store never delays
+// the request being logged.
+package requestlog
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// drainTimeout bounds how long buffered entries are flushed once the recorder stops
+const drainTimeout = 5 * time.Second
+
+// Entry is a logged request with its response. Headers, query and bodies are already redacted.
+type Entry struct {
+	ID                string
+	OccurredAt        time.Time
+	Method            string
+	Path              string
+	Query             string
+	Status            int
+	DurationMs        int64
+	ActorID           string // Taken from the verified session, never from request headers
+	ActorEmail        string
+	SessionID         string
+	IPAddress         string
+	UserAgent         string
+	CorrelationID     string
+	RequestHeaders    map[string]string
+	RequestBody       string
+	ResponseBody      string
+	RequestTruncated  bool // The body exceeded the capture limit and was cut
+	ResponseTruncated bool
+}
+
+// Store persists logged requests
+type Store interface {
+	SaveRequestLog(ctx context.Context, entry *Entry) error
+}
+
+// Recorder writes entries to a Store in the background. When its buffer is
+// full, entries are dropped rather than blocking requests.
+type Recorder struct {
+	store   Store
+	entries chan *Entry
+	log     *logger.Logger
+	dropped atomic.Int64
+}
+
+// NewRecorder creates a recorder buffering up to bufferSize entries
+func NewRecorder(store Store, bufferSize int, log *logger.Logger) *Recorder {
+	if bufferSize <= 0 {
+		bufferSize = 1000
+	}
+	return &Recorder{store: store, entries: make(chan *Entry, bufferSize), log: log}
+}
+
+// Record queues an entry for storage without blocking
+func (r *Recorder) Record(entry *Entry) {
+	select {
+	case r.entries <- entry:
+	default:
+		if dropped := r.dropped.Add(1); dropped == 1 || dropped%1000 == 0 {
+			r.log.WithField("dropped", dropped).Warn("Request log buffer full, dropping entries")
+		}
+	}
+}
+
+// Dropped returns how many entries were dropped because the buffer was full
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Start writes queued entries until ctx is cancelled, then flushes what is left
+func (r *Recorder) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case entry := <-r.entries:
+				r.save(ctx, entry)
+			case <-ctx.Done():
+				r.drain()
+				return
+			}
+		}
+	}()
+}
+
+func (r *Recorder) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	for {
+		select {
+		case entry := <-r.entries:
+			r.save(ctx, entry)
+		default:
+			return
+		}
+	}
+}
+
+func (r *Recorder) save(ctx context.Context, entry *Entry) {
+	if err := r.store.SaveRequestLog(ctx, entry); err != nil {
+		r.log.WithError(err).WithField("path", entry.Path).Warn("Failed to save request log entry")
+	}
+}
@@ -0,0 +1,205 @@
+// Package schedule resolves the start and end dates of offers, catalog items
+// and content in the time zone of a site. Schedules are stored as UTC
+// instants; dates entered without an offset are wall-clock times of the site.
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts accepted for dates entered without an offset
+const (
+	dateLayout        = "2006-01-02"
+	localMinuteLayout = "2006-01-02T15:04"
+	localLayout       = "2006-01-02T15:04:05"
+)
+
+// Zones holds the time zone of each site; sites without their own use the default
+type Zones struct {
+	Default *time.Location
+	Sites   map[string]*time.Location
+}
+
+// LoadZones loads the default time zone and the zones of sites by IANA name
+// (e.g. "America/New_York"). Empty names are UTC for the default and the
+// default for sites.
+func LoadZones(defaultZone string, sites map[string]string) (Zones, error) {
+	zones := Zones{Default: time.UTC, Sites: make(map[string]*time.Location, len(sites))}
+	if defaultZone != "" {
+		loc, err := time.LoadLocation(defaultZone)
+		if err != nil {
+			return Zones{}, fmt.Errorf("invalid time zone %q: %w", defaultZone, err)
+		}
+		zones.Default = loc
+	}
+	for site, name := range sites {
+		if name == "" {
+			continue
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return Zones{}, fmt.Errorf("invalid time zone %q of site %q: %w", name, site, err)
+		}
+		zones.Sites[site] = loc
+	}
+	return zones, nil
+}
+
+// ForSite returns the time zone of a site. Site IDs are matched
+// case-insensitively (config loaders may lowercase map keys).
+func (z Zones) ForSite(siteID string) *time.Location {
+	for id, loc := range z.Sites {
+		if strings.EqualFold(id, siteID) {
+			return loc
+		}
+	}
+	if z.Default == nil {
+		return time.UTC
+	}
+	return z.Default
+}
+
+type contextKey struct{}
+
+// WithLocation returns a copy of ctx carrying the time zone schedules are read in
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, contextKey{}, loc)
+}
+
+// Location returns the time zone of the request's site, or UTC when none was set
+func Location(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(contextKey{}).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Time is a schedule date as entered: an instant when it carries an offset
+// (RFC3339), otherwise a wall-clock time or a whole day resolved in the time
+// zone of the site.
+type Time struct {
+	t        time.Time
+	local    bool
+	dateOnly bool
+}
+
+// Parse reads a schedule date in RFC3339, YYYY-MM-DDTHH:MM[:SS] or YYYY-MM-DD
+func Parse(raw string) (Time, error) {
+	raw = strings.TrimSpace(raw)
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return Time{t: t}, nil
+	}
+	if t, err := time.Parse(dateLayout, raw); err == nil {
+		return Time{t: t, local: true, dateOnly: true}, nil
+	}
+	for _, layout := range []string{localLayout, localMinuteLayout} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return Time{t: t, local: true}, nil
+		}
+	}
+	return Time{}, fmt.Errorf("invalid date %q, expected RFC3339, YYYY-MM-DDTHH:MM:SS or YYYY-MM-DD", raw)
+}
+
+// UnmarshalJSON reads a schedule date from a JSON string
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("schedule date must be a string: %w", err)
+	}
+	parsed, err := Parse(raw)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalJSON writes the date as it was entered
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// String formats the date as it was entered
+func (t Time) String() string {
+	switch {
+	case t.dateOnly:
+		return t.t.Format(dateLayout)
+	case t.local:
+		return t.t.Format(localLayout)
+	default:
+		return t.t.Format(time.RFC3339)
+	}
+}
+
+// Start resolves the date as the start of a window: a whole day starts at its
+// midnight in loc
+func (t Time) Start(loc *time.Location) time.Time {
+	if !t.local {
+		return t.t.UTC()
+	}
+	return wallClock(t.t, loc).UTC()
+}
+
+// End resolves the date as the inclusive end of a window: a whole day ends at
+// the last microsecond before the next midnight in loc, however long DST makes
+// the day
+func (t Time) End(loc *time.Location) time.Time {
+	if !t.dateOnly {
+		return t.Start(loc)
+	}
+	next := time.Date(t.t.Year(), t.t.Month(), t.t.Day()+1, 0, 0, 0, 0, loc)
+	return next.Add(-time.Microsecond).UTC()
+}
+
+// StartOf resolves an optional date as the start of a window
+func StartOf(t *Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	start := t.Start(loc)
+	return &start
+}
+
+// EndOf resolves an optional date as the inclusive end of a window
+func EndOf(t *Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	end := t.End(loc)
+	return &end
+}
+
+// Contains checks if the window from start to the inclusive end contains at;
+// nil bounds are open
+func Contains(start, end *time.Time, at time.Time) bool {
+	if start != nil && at.Before(*start) {
+		return false
+	}
+	if end != nil && at.After(*end) {
+		return false
+	}
+	return true
+}
+
+// wallClock places the wall-clock time of t in loc. A time skipped by a DST
+// transition is read with the offset in force before it, so a window entered
+// at 02:30 on a spring-forward night opens at 03:30 rather than an hour early.
+// time.Date may resolve a skipped time on either side of the transition
+// depending on the zone, so the offset is taken an hour before it.
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	placed := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	if placed.Hour() == t.Hour() && placed.Minute() == t.Minute() {
+		return placed
+	}
+	_, offset := placed.Add(-time.Hour).Zone()
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Add(-time.Duration(offset) * time.Second).In(loc)
+}
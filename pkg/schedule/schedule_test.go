@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load time zone %s: %v", name, err)
+	}
+	return loc
+}
+
+func mustParse(t *testing.T, raw string) Time {
+	t.Helper()
+	parsed, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", raw, err)
+	}
+	return parsed
+}
+
+func TestStartAcrossDSTTransitions(t *testing.T) {
+	tests := []struct {
+		name string
+		zone string
+		raw  string
+		want string
+	}{
+		{"berlin skipped time opens after the gap", "Europe/Berlin", "2026-03-29T02:30", "2026-03-29T01:30:00Z"},
+		{"new york skipped time opens after the gap", "America/New_York", "2026-03-08T02:30", "2026-03-08T07:30:00Z"},
+		{"sydney skipped time opens after the gap", "Australia/Sydney", "2026-10-04T02:30", "2026-10-03T16:30:00Z"},
+		{"berlin before the gap", "Europe/Berlin", "2026-03-29T01:59", "2026-03-29T00:59:00Z"},
+		{"berlin after the gap", "Europe/Berlin", "2026-03-29T03:00", "2026-03-29T01:00:00Z"},
+		{"berlin spring-forward day", "Europe/Berlin", "2026-03-29", "2026-03-28T23:00:00Z"},
+		{"berlin fall-back day", "Europe/Berlin", "2026-10-25", "2026-10-24T22:00:00Z"},
+		{"offset is kept", "Europe/Berlin", "2026-03-29T02:30:00+01:00", "2026-03-29T01:30:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustParse(t, tt.raw).Start(mustLoad(t, tt.zone))
+			if got.Format(time.RFC3339) != tt.want {
+				t.Errorf("Start(%q) in %s = %s, want %s", tt.raw, tt.zone, got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestStartOfRepeatedTime(t *testing.T) {
+	// 02:30 occurs twice on a fall-back night; either occurrence reads 02:30 on the site's clock
+	loc := mustLoad(t, "Europe/Berlin")
+	got := mustParse(t, "2026-10-25T02:30").Start(loc)
+	if wall := got.In(loc).Format("2006-01-02T15:04"); wall != "2026-10-25T02:30" {
+		t.Errorf("Start of a repeated time = %s, want 2026-10-25T02:30 on the site's clock", wall)
+	}
+}
+
+func TestEndOfDSTDays(t *testing.T) {
+	tests := []struct {
+		name  string
+		zone  string
+		raw   string
+		want  string
+		hours float64
+	}{
+		{"spring-forward day is 23 hours", "Europe/Berlin", "2026-03-29", "2026-03-29T21:59:59.999999Z", 23},
+		{"fall-back day is 25 hours", "Europe/Berlin", "2026-10-25", "2026-10-25T22:59:59.999999Z", 25},
+		{"new york spring-forward day", "America/New_York", "2026-03-08", "2026-03-09T03:59:59.999999Z", 23},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := mustLoad(t, tt.zone)
+			date := mustParse(t, tt.raw)
+			end := date.End(loc)
+			if end.Format(time.RFC3339Nano) != tt.want {
+				t.Errorf("End(%q) in %s = %s, want %s", tt.raw, tt.zone, end.Format(time.RFC3339Nano), tt.want)
+			}
+			if hours := end.Add(time.Microsecond).Sub(date.Start(loc)).Hours(); hours != tt.hours {
+				t.Errorf("day %q in %s lasts %v hours, want %v", tt.raw, tt.zone, hours, tt.hours)
+			}
+		})
+	}
+}
+
+func TestContainsAcrossSpringForward(t *testing.T) {
+	loc := mustLoad(t, "Europe/Berlin")
+	start := StartOf(&[]Time{mustParse(t, "2026-03-29T02:30")}[0], loc)
+	end := EndOf(&[]Time{mustParse(t, "2026-03-29")}[0], loc)
+
+	before := time.Date(2026, 3, 29, 1, 59, 0, 0, loc) // 01:59 CET, before the window opens
+	opening := time.Date(2026, 3, 29, 3, 30, 0, 0, loc)
+	if Contains(start, end, before) {
+		t.Errorf("window opening at 02:30 contains %s", before)
+	}
+	if !Contains(start, end, opening) {
+		t.Errorf("window opening at 02:30 does not contain %s", opening)
+	}
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// DrainConfig holds the shutdown timings of a server
+type DrainConfig struct {
+	// Delay keeps serving with readiness failing, so load balancers stop
+	// routing new requests before the listener closes
+	Delay time.Duration
+	// Timeout bounds the wait for in-flight requests once the listener closes
+	Timeout time.Duration
+	// InFlight reports the requests still being served, logged every second
+	// while draining; nil logs no progress
+	InFlight func() int64
+}
+
+// drainProgressInterval is how often the requests left are logged while draining
+const drainProgressInterval = time.Second
+
+// Drainer tracks whether a server is accepting new traffic and shuts it down
+// without dropping in-flight requests
+type Drainer struct {
+	server   *http.Server
+	cfg      DrainConfig
+	logger   *logger.Logger
+	draining atomic.Bool
+}
+
+// NewDrainer creates a drainer for a server
+func NewDrainer(server *http.Server, cfg DrainConfig, log *logger.Logger) *Drainer {
+	return &Drainer{
+		server: server,
+		cfg:    cfg,
+		logger: log,
+	}
+}
+
+// ReadinessHandler reports 503 once draining starts so load balancers take the
+// instance out of rotation
+func (d *Drainer) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if d.draining.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"draining"}`))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ready"}`))
+}
+
+// Draining checks if the server stopped accepting new traffic
+func (d *Drainer) Draining() bool {
+	return d.draining.Load()
+}
+
+// Drain fails readiness, waits for the drain delay, then closes the listener
+// and waits up to the drain timeout for in-flight requests to complete.
+// Keep-alive connections are closed after their current request so clients
+// reconnect to another instance.
+func (d *Drainer) Drain() error {
+	d.draining.Store(true)
+	d.server.SetKeepAlivesEnabled(false)
+
+	if d.cfg.Delay > 0 {
+		d.logger.WithField("delay", d.cfg.Delay.String()).Info("Draining: readiness failing, waiting for load balancers")
+		time.Sleep(d.cfg.Delay)
+	}
+
+	ctx := context.Background()
+	if d.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.cfg.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	if d.cfg.InFlight != nil {
+		d.logger.WithField("in_flight", d.cfg.InFlight()).Info("Draining: listener closing")
+		stop := make(chan struct{})
+		defer close(stop)
+		go d.logProgress(start, stop)
+	}
+	if err := d.server.Shutdown(ctx); err != nil {
+		log := d.logger.WithError(err).WithField("timeout", d.cfg.Timeout.String())
+		if d.cfg.InFlight != nil {
+			log = log.WithField("in_flight", d.cfg.InFlight())
+		}
+		log.Error("Drain timed out, closing remaining connections")
+		d.server.Close()
+		return err
+	}
+	d.logger.WithField("duration_ms", time.Since(start).Milliseconds()).Info("In-flight requests drained")
+	return nil
+}
+
+// logProgress logs the requests still in flight until stop is closed
+func (d *Drainer) logProgress(start time.Time, stop <-chan struct{}) {
+	ticker := time.NewTicker(drainProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.logger.WithFields(logger.Fields{
+				"in_flight":  d.cfg.InFlight(),
+				"elapsed_ms": time.Since(start).Milliseconds(),
+			}).Info("Draining: waiting for in-flight requests")
+		}
+	}
+}
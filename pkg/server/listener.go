@@ -0,0 +1,101 @@
+// Package server starts HTTP servers for zero-downtime deploys: listeners are
+// inherited from systemd socket activation or a handoff file descriptor, or
+// bound with SO_REUSEPORT so a new release can bind next to the old one, and
+// shutdown drains in-flight requests after readiness turns off.
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd (SD_LISTEN_FDS_START)
+const listenFDsStart = 3
+
+// ListenerConfig selects where the listener of a server comes from
+type ListenerConfig struct {
+	Address     string // host:port bound when no listener is inherited
+	ReusePort   bool   // Bind with SO_REUSEPORT so old and new releases can share the port
+	InheritFD   int    // Handoff file descriptor passed by a parent process; 0 disables it
+	SystemdName string // Socket name in LISTEN_FDNAMES to use; empty takes the first socket
+}
+
+// Listen returns the listener of a server and where it came from: systemd
+// socket activation first, then the handoff file descriptor, then a new socket
+func Listen(ctx context.Context, cfg ListenerConfig) (net.Listener, string, error) {
+	if listener, ok, err := systemdListener(cfg.SystemdName); err != nil {
+		return nil, "", err
+	} else if ok {
+		return listener, "systemd", nil
+	}
+
+	if cfg.InheritFD > 0 {
+		listener, err := fileListener(uintptr(cfg.InheritFD), "inherited")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to inherit listener from fd %d: %w", cfg.InheritFD, err)
+		}
+		return listener, "inherited", nil
+	}
+
+	lc := net.ListenConfig{}
+	if cfg.ReusePort {
+		lc.Control = reusePortControl
+	}
+	listener, err := lc.Listen(ctx, "tcp", cfg.Address)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to listen on %s: %w", cfg.Address, err)
+	}
+	return listener, "bound", nil
+}
+
+// systemdListener returns a socket passed by systemd socket activation.
+// The LISTEN_* variables are cleared so child processes do not reuse them.
+func systemdListener(name string) (net.Listener, bool, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, false, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return nil, false, nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	index := 0
+	if name != "" {
+		index = -1
+		for i, fdName := range names {
+			if fdName == name && i < count {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, false, fmt.Errorf("systemd did not pass a socket named %q", name)
+		}
+	}
+
+	listener, err := fileListener(uintptr(listenFDsStart+index), "systemd")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to use systemd socket: %w", err)
+	}
+	return listener, true, nil
+}
+
+// fileListener wraps an inherited socket file descriptor
+func fileListener(fd uintptr, name string) (net.Listener, error) {
+	file := os.NewFile(fd, name)
+	if file == nil {
+		return nil, fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	defer file.Close() // net.FileListener duplicates the descriptor
+
+	return net.FileListener(file)
+}
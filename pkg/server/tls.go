@@ -0,0 +1,56 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLSOptions selects the certificates of a server
+type TLSOptions struct {
+	CertFile string // Certificate of the platform's own hostnames; optional with a manager
+	KeyFile  string
+	Manager  *autocert.Manager // Issues certificates for the hosts its policy allows; nil serves CertFile only
+}
+
+// NewTLSConfig returns the TLS settings of a server choosing its certificate
+// by SNI: the static certificate for the names it covers and for clients
+// sending no name, an ACME certificate for the hosts the manager allows, and
+// the static certificate again for any other host
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	var static *tls.Certificate
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		static = &cert
+	}
+	if opts.Manager == nil {
+		if static == nil {
+			return nil, fmt.Errorf("a TLS certificate or an ACME manager is required")
+		}
+		return &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{*static}}, nil
+	}
+
+	cfg := opts.Manager.TLSConfig()
+	cfg.MinVersion = tls.VersionTLS12
+	managed := cfg.GetCertificate
+	cfg.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		// TLS-ALPN-01 challenges are always answered by the manager
+		if len(hello.SupportedProtos) == 1 && hello.SupportedProtos[0] == acme.ALPNProto {
+			return managed(hello)
+		}
+		if static != nil && (hello.ServerName == "" || static.Leaf != nil && static.Leaf.VerifyHostname(hello.ServerName) == nil) {
+			return static, nil
+		}
+		cert, err := managed(hello)
+		if err != nil && static != nil {
+			return static, nil
+		}
+		return cert, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,323 @@
+// Package settings holds the site settings the admin changes at runtime, such
+// as the store name, the order number prefix or the thresholds of promotion
+// messages, in place of constants and config.yaml values. Each setting has a
+// typed key, so services read it as a string, number, flag or duration. Values
+// are stored by the admin and each server reads a snapshot refreshed
+// periodically, so reading a setting never hits the store. Settings never
+// changed keep their default: built in, or taken from config.yaml at startup.
+package settings
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+	"github.com/qhato/ecommerce/pkg/snapshot"
+)
+
+// Type is the type of the value of a setting
+type Type string
+
+const (
+	TypeString   Type = "string"
+	TypeEmail    Type = "email" // An address, or empty
+	TypeInt      Type = "int"
+	TypeFloat    Type = "float"
+	TypeBool     Type = "bool"
+	TypeDuration Type = "duration" // e.g. 90s, 15m or 2h
+)
+
+// Typed keys. A key is read through the getter of its type, e.g. Int for an IntKey.
+type (
+	StringKey   string
+	IntKey      string
+	FloatKey    string
+	BoolKey     string
+	DurationKey string
+)
+
+// Known settings
+const (
+	StoreName              StringKey = "store.name"
+	SupportEmail           StringKey = "store.support_email"
+	EmailFrom              StringKey = "notification.email_from"
+	OrderNumberPrefix      StringKey = "order.number_prefix"
+	PromotionMessageMaxGap FloatKey  = "order.promotion_message_max_gap"
+	PromotionMessageLimit  IntKey    = "order.promotion_message_limit"
+)
+
+// Definition describes a known setting
+type Definition struct {
+	Type        Type
+	Default     string
+	Description string
+	Min         *float64 // Lowest value of numbers and durations (in seconds), if any
+	MaxLength   int      // Longest value of strings; 0 is unbounded
+}
+
+var zero = 0.0
+
+// Definitions are the known settings by key
+var Definitions = map[string]Definition{
+	string(StoreName):              {Type: TypeString, Default: "Store", Description: "Store name shown on storefront pages and emails", MaxLength: 120},
+	string(SupportEmail):           {Type: TypeEmail, Description: "Customer support address, shown to customers and used as the reply-to of emails"},
+	string(EmailFrom):              {Type: TypeEmail, Default: "no-reply@localhost", Description: "Sender address of transactional emails"},
+	string(OrderNumberPrefix):      {Type: TypeString, Description: "Prefix of the order numbers given to orders at submission, e.g. WEB-", MaxLength: 16},
+	string(PromotionMessageMaxGap): {Type: TypeFloat, Default: "0", Description: "Largest spend gap advertised by cart promotion messages; 0 advertises any gap", Min: &zero},
+	string(PromotionMessageLimit):  {Type: TypeInt, Default: "0", Description: "Offers advertised per cart by promotion messages; 0 advertises all of them", Min: &zero},
+}
+
+// IsKnown checks whether a key is a known setting
+func IsKnown(key string) bool {
+	_, ok := Definitions[key]
+	return ok
+}
+
+// Normalize checks a value against the definition of its key and returns it
+// in canonical form, e.g. a duration as 15m0s
+func Normalize(key, value string) (string, error) {
+	definition, ok := Definitions[key]
+	if !ok {
+		return "", fmt.Errorf("unknown setting %s", key)
+	}
+	value = strings.TrimSpace(value)
+
+	var number float64
+	switch definition.Type {
+	case TypeString:
+		if definition.MaxLength > 0 && len([]rune(value)) > definition.MaxLength {
+			return "", fmt.Errorf("%s must be at most %d characters", key, definition.MaxLength)
+		}
+		return value, nil
+	case TypeEmail:
+		if value == "" {
+			return "", nil
+		}
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return "", fmt.Errorf("%s must be an email address", key)
+		}
+		return value, nil
+	case TypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%s must be true or false", key)
+		}
+		return strconv.FormatBool(b), nil
+	case TypeInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s must be a whole number", key)
+		}
+		number, value = float64(n), strconv.FormatInt(n, 10)
+	case TypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s must be a number", key)
+		}
+		number, value = f, strconv.FormatFloat(f, 'f', -1, 64)
+	case TypeDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return "", fmt.Errorf("%s must be a duration such as 90s, 15m or 2h", key)
+		}
+		number, value = d.Seconds(), d.String()
+	default:
+		return "", fmt.Errorf("setting %s has an unknown type %s", key, definition.Type)
+	}
+	if definition.Min != nil && number < *definition.Min {
+		return "", fmt.Errorf("%s must be at least %v", key, *definition.Min)
+	}
+	return value, nil
+}
+
+// Setting is the value of a setting
+type Setting struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Store persists settings that were changed; settings never changed keep their default
+type Store interface {
+	List(ctx context.Context) ([]*Setting, error)
+	Save(ctx context.Context, setting *Setting) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Settings is a snapshot of the stored settings over their defaults. It is
+// safe for concurrent use.
+type Settings struct {
+	store    Store
+	defaults map[string]string
+	log      *logger.Logger
+	snapshot *snapshot.Snapshot[*Setting]
+}
+
+// New creates settings holding the defaults until the first Refresh. defaults
+// replaces the built-in default of some keys, e.g. with values of config.yaml.
+func New(store Store, defaults map[string]string, log *logger.Logger) (*Settings, error) {
+	values := make(map[string]string, len(Definitions))
+	for key, definition := range Definitions {
+		values[key] = definition.Default
+	}
+	for key, value := range defaults {
+		normalized, err := Normalize(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default: %w", err)
+		}
+		values[key] = normalized
+	}
+
+	s := &Settings{store: store, defaults: values, log: log}
+	s.snapshot = snapshot.New(snapshot.Config[*Setting]{
+		Name:    "site settings",
+		Load:    s.load,
+		Changed: func(previous, current *Setting) bool { return previous.Value != current.Value },
+		Report: func(key string, setting *Setting) {
+			log.WithFields(logger.Fields{"setting": key, "value": setting.Value}).Info("Site setting changed")
+		},
+	}, s.defaultSettings(), log)
+	return s, nil
+}
+
+// String returns the value of a string or email setting
+func (s *Settings) String(key StringKey) string {
+	return s.value(string(key))
+}
+
+// Int returns the value of an integer setting
+func (s *Settings) Int(key IntKey) int {
+	n, _ := strconv.Atoi(s.value(string(key)))
+	return n
+}
+
+// Float returns the value of a number setting
+func (s *Settings) Float(key FloatKey) float64 {
+	f, _ := strconv.ParseFloat(s.value(string(key)), 64)
+	return f
+}
+
+// Bool returns the value of a true or false setting
+func (s *Settings) Bool(key BoolKey) bool {
+	b, _ := strconv.ParseBool(s.value(string(key)))
+	return b
+}
+
+// Duration returns the value of a duration setting
+func (s *Settings) Duration(key DurationKey) time.Duration {
+	d, _ := time.ParseDuration(s.value(string(key)))
+	return d
+}
+
+// Default returns the default of a setting
+func (s *Settings) Default(key string) string {
+	return s.defaults[key]
+}
+
+// Get returns a setting; unknown keys are not found
+func (s *Settings) Get(key string) (*Setting, bool) {
+	setting, ok := s.snapshot.Get(key)
+	if !ok {
+		return nil, false
+	}
+	copied := *setting
+	return &copied, true
+}
+
+// List returns every known setting, ordered by key
+func (s *Settings) List() []*Setting {
+	values := s.snapshot.Values()
+	settings := make([]*Setting, 0, len(values))
+	for _, setting := range values {
+		copied := *setting
+		settings = append(settings, &copied)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// Set checks and stores the value of a setting; the snapshot changes right
+// away here and on the other servers at their next refresh
+func (s *Settings) Set(ctx context.Context, key, value, updatedBy string) (*Setting, error) {
+	normalized, err := Normalize(key, value)
+	if err != nil {
+		return nil, err
+	}
+	setting := &Setting{Key: key, Value: normalized, UpdatedBy: updatedBy, UpdatedAt: time.Now()}
+	if err := s.store.Save(ctx, setting); err != nil {
+		return nil, err
+	}
+
+	s.snapshot.Set(key, setting)
+	copied := *setting
+	return &copied, nil
+}
+
+// Reset removes the stored value of a setting so its default applies again
+func (s *Settings) Reset(ctx context.Context, key string) (*Setting, error) {
+	if !IsKnown(key) {
+		return nil, fmt.Errorf("unknown setting %s", key)
+	}
+	if err := s.store.Delete(ctx, key); err != nil {
+		return nil, err
+	}
+
+	setting := &Setting{Key: key, Value: s.defaults[key]}
+	s.snapshot.Set(key, setting)
+	copied := *setting
+	return &copied, nil
+}
+
+// Refresh reloads the stored settings
+func (s *Settings) Refresh(ctx context.Context) error {
+	return s.snapshot.Refresh(ctx)
+}
+
+// StartScheduledRefresh refreshes the settings periodically until ctx is cancelled
+func (s *Settings) StartScheduledRefresh(ctx context.Context, interval time.Duration) {
+	s.snapshot.StartScheduledRefresh(ctx, interval)
+}
+
+// load reads the stored settings over the defaults. Stored settings that are
+// no longer known, or whose value no longer fits their definition, are ignored.
+func (s *Settings) load(ctx context.Context) (map[string]*Setting, error) {
+	stored, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settings := s.defaultSettings()
+	for _, setting := range stored {
+		normalized, err := Normalize(setting.Key, setting.Value)
+		if err != nil {
+			if IsKnown(setting.Key) {
+				s.log.WithError(err).WithField("setting", setting.Key).Warn("Ignoring invalid stored setting")
+			}
+			continue
+		}
+		setting.Value = normalized
+		settings[setting.Key] = setting
+	}
+	return settings, nil
+}
+
+func (s *Settings) value(key string) string {
+	if setting, ok := s.snapshot.Get(key); ok {
+		return setting.Value
+	}
+	return ""
+}
+
+func (s *Settings) defaultSettings() map[string]*Setting {
+	settings := make(map[string]*Setting, len(s.defaults))
+	for key, value := range s.defaults {
+		settings[key] = &Setting{Key: key, Value: value}
+	}
+	return settings
+}
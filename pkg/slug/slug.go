@@ -0,0 +1,96 @@
+// Package slug turns names into URL keys: lower case ASCII letters and digits in
+// words separated by single hyphens, e.g. "Crème Brûlée Set" becomes
+// "creme-brulee-set". Accented Latin letters lose their marks and Greek and
+// Cyrillic letters are transliterated, so localized names still give readable keys.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength bounds the length of a slug, leaving room for a de-duplication
+// suffix within the 255 characters of a url_key column
+const MaxLength = 200
+
+// transliterations spells out letters that do not decompose into an ASCII base
+// letter and combining marks
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th", 'ł': "l", 'ı': "i", 'ŋ': "ng",
+	'&': " and ", '@': " at ", '+': " plus ",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye", 'ж': "zh",
+	'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh",
+	'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i", 'κ': "k",
+	'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t",
+	'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Make returns the slug of s, or "" when s has no letters or digits that can be
+// spelled in ASCII
+func Make(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	write := func(part string) {
+		for _, r := range part {
+			if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+				if pendingHyphen && b.Len() > 0 {
+					b.WriteByte('-')
+				}
+				pendingHyphen = false
+				b.WriteRune(r)
+			} else {
+				pendingHyphen = true
+			}
+		}
+	}
+
+	for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+		spelled, ok := transliterations[r]
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks left by decomposing accented letters
+		case ok:
+			write(spelled)
+		case r < unicode.MaxASCII:
+			write(string(r))
+		default:
+			pendingHyphen = true
+		}
+	}
+
+	return truncate(b.String(), MaxLength)
+}
+
+// IsValid reports whether s is already a slug, i.e. Make(s) == s and s is not empty
+func IsValid(s string) bool {
+	return s != "" && Make(s) == s
+}
+
+// WithSuffix returns the n-th alternative of a slug taken by something else,
+// e.g. "blue-shirt-2", keeping it within MaxLength
+func WithSuffix(s string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	return truncate(s, MaxLength-len(suffix)) + suffix
+}
+
+// truncate shortens a slug to at most max characters, cutting at a word boundary
+// when there is one
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		return s[:i]
+	}
+	return s
+}
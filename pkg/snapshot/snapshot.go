@@ -0,0 +1,124 @@
+// Package snapshot keeps an in-memory copy of values the admin stores at
+// runtime, such as feature flags, site settings or rate limit overrides, so
+// reading them never hits the store. Each server reloads its copy
+// periodically; a change made through a server shows there right away and on
+// the other servers at their next refresh.
+package snapshot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/logger"
+)
+
+// Config describes what a snapshot holds and how it is loaded
+type Config[V any] struct {
+	Name    string                                          // What the snapshot holds, for logs, e.g. "feature flag"
+	Load    func(ctx context.Context) (map[string]V, error) // Every value by key, defaults included
+	Changed func(previous, current V) bool                  // Whether a value changed enough to be reported
+	Report  func(key string, value V)                       // Called for the values a refresh added or changed
+}
+
+// Snapshot is a copy of stored values by key. It is safe for concurrent use;
+// values are shared, so callers hand out copies of mutable ones.
+type Snapshot[V any] struct {
+	config Config[V]
+	log    *logger.Logger
+
+	mu     sync.RWMutex
+	values map[string]V
+}
+
+// New creates a snapshot holding initial until the first Refresh
+func New[V any](config Config[V], initial map[string]V, log *logger.Logger) *Snapshot[V] {
+	if initial == nil {
+		initial = make(map[string]V)
+	}
+	return &Snapshot[V]{config: config, log: log, values: initial}
+}
+
+// Get returns the value of a key
+func (s *Snapshot[V]) Get(key string) (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Values returns every value, in no particular order
+func (s *Snapshot[V]) Values() []V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	values := make([]V, 0, len(s.values))
+	for _, value := range s.values {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Set replaces the value of a key once it was stored
+func (s *Snapshot[V]) Set(key string, value V) {
+	s.mu.Lock()
+	s.values[key] = value
+	s.mu.Unlock()
+}
+
+// DeleteFunc removes the values for which del returns true once they were
+// removed from the store
+func (s *Snapshot[V]) DeleteFunc(del func(key string, value V) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, value := range s.values {
+		if del(key, value) {
+			delete(s.values, key)
+		}
+	}
+}
+
+// Refresh reloads the values and reports those added or changed
+func (s *Snapshot[V]) Refresh(ctx context.Context) error {
+	values, err := s.config.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	changed := make([]string, 0)
+	for key, value := range values {
+		if previous, ok := s.values[key]; !ok || s.config.Changed(previous, value) {
+			changed = append(changed, key)
+		}
+	}
+	s.values = values
+	s.mu.Unlock()
+
+	if s.config.Report != nil {
+		for _, key := range changed {
+			s.config.Report(key, values[key])
+		}
+	}
+	return nil
+}
+
+// StartScheduledRefresh refreshes the values periodically until ctx is cancelled
+func (s *Snapshot[V]) StartScheduledRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Refresh(ctx); err != nil {
+					s.log.WithError(err).Warn("Scheduled " + s.config.Name + " refresh failed")
+				}
+			}
+		}
+	}()
+}
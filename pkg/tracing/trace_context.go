@@ -0,0 +1,97 @@
+// Package tracing propagates W3C Trace Context (traceparent/tracestate) so
+// inbound requests and outbound calls join the same trace in OpenTelemetry
+// compatible collectors.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Header names defined by the W3C Trace Context specification
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+type contextKey struct{}
+
+// SpanContext identifies the current span of a trace
+type SpanContext struct {
+	TraceID    string // 32 hex characters
+	SpanID     string // 16 hex characters
+	Flags      string // 2 hex characters, "01" when sampled
+	TraceState string
+}
+
+// NewRoot starts a new sampled trace
+func NewRoot() SpanContext {
+	return SpanContext{TraceID: randomHex(16), SpanID: randomHex(8), Flags: "01"}
+}
+
+// Child returns a new span in the same trace
+func (s SpanContext) Child() SpanContext {
+	return SpanContext{TraceID: s.TraceID, SpanID: randomHex(8), Flags: s.Flags, TraceState: s.TraceState}
+}
+
+// Traceparent renders the span as a traceparent header value
+func (s SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%s", s.TraceID, s.SpanID, s.Flags)
+}
+
+// Parse reads a traceparent header value
+func Parse(traceparent, tracestate string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		!isHex(parts[1], 32) || !isHex(parts[2], 16) || !isHex(parts[3], 2) ||
+		parts[1] == strings.Repeat("0", 32) || parts[2] == strings.Repeat("0", 16) {
+		return SpanContext{}, false
+	}
+	return SpanContext{TraceID: parts[1], SpanID: parts[2], Flags: parts[3], TraceState: tracestate}, true
+}
+
+// Extract reads the span context of an inbound request, starting a new trace
+// when the request carries none
+func Extract(r *http.Request) SpanContext {
+	if span, ok := Parse(r.Header.Get(TraceparentHeader), r.Header.Get(TracestateHeader)); ok {
+		return span.Child()
+	}
+	return NewRoot()
+}
+
+// Inject writes the span context to outbound request headers
+func Inject(header http.Header, span SpanContext) {
+	header.Set(TraceparentHeader, span.Traceparent())
+	if span.TraceState != "" {
+		header.Set(TracestateHeader, span.TraceState)
+	}
+}
+
+// WithSpan stores the span context in a context
+func WithSpan(ctx context.Context, span SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, span)
+}
+
+// FromContext returns the span context stored in a context
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	span, ok := ctx.Value(contextKey{}).(SpanContext)
+	return span, ok
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil && strings.ToLower(s) == s
+}
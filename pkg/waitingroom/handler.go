@@ -0,0 +1,65 @@
+package waitingroom
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qhato/ecommerce/pkg/errors"
+	pkghttp "github.com/qhato/ecommerce/pkg/http"
+)
+
+// TokenHeader carries the ticket or session of a visitor on checkout requests
+const TokenHeader = "X-Waiting-Room-Token"
+
+// Token returns the waiting room token of a request, from TokenHeader or the token query parameter
+func Token(r *http.Request) string {
+	if token := r.Header.Get(TokenHeader); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("token")
+}
+
+// RegisterRoutes registers the queue status routes polled by queued visitors
+func (r *Room) RegisterRoutes(router chi.Router) {
+	router.Get("/waiting-room", r.OverviewHandler)
+	router.Get("/waiting-room/status", r.StatusHandler)
+}
+
+// OverviewHandler reports whether checkout is queuing and the estimated wait of a visitor joining now
+func (r *Room) OverviewHandler(w http.ResponseWriter, req *http.Request) {
+	overview, err := r.Overview(req.Context())
+	if err != nil {
+		pkghttp.RespondError(w, errors.ServiceUnavailable("waiting room unavailable").WithInternal(err))
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	pkghttp.RespondJSON(w, http.StatusOK, overview)
+}
+
+// StatusHandler reports the position and estimated wait of a ticket, or the
+// session it was admitted with once its turn came
+func (r *Room) StatusHandler(w http.ResponseWriter, req *http.Request) {
+	token := Token(req)
+	if token == "" {
+		pkghttp.RespondError(w, errors.BadRequest("waiting room token is required"))
+		return
+	}
+
+	status, err := r.Status(req.Context(), token)
+	if err != nil {
+		if errors.GetStatusCode(err) == http.StatusBadRequest {
+			pkghttp.RespondError(w, err)
+			return
+		}
+		pkghttp.RespondError(w, errors.ServiceUnavailable("waiting room unavailable").WithInternal(err))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set(TokenHeader, status.Token)
+	if !status.Admitted {
+		w.Header().Set("Retry-After", strconv.Itoa(int(status.PollAfter().Seconds())))
+	}
+	pkghttp.RespondJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,79 @@
+package waitingroom
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the waiting room in process memory; limits apply per instance
+type MemoryStore struct {
+	mu         sync.Mutex
+	sessions   map[string]time.Time // Session ID -> expiry
+	head       int64
+	tail       int64
+	admittedAt time.Time
+}
+
+// NewMemoryStore creates an in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]time.Time)}
+}
+
+// Enter starts a session when the room is not queuing, otherwise issues the next queue position
+func (s *MemoryStore) Enter(ctx context.Context, sessionID string, maxActive int, expiresAt time.Time) (bool, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active := s.expire(time.Now())
+	if s.tail <= s.head && active < maxActive {
+		s.sessions[sessionID] = expiresAt
+		return true, 0, nil
+	}
+	s.tail++
+	return false, s.tail, nil
+}
+
+// Advance admits queued positions for the time elapsed since the last admission
+func (s *MemoryStore) Advance(ctx context.Context, now time.Time, admitPerMinute, maxActive int) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active := s.expire(now)
+	if s.admittedAt.IsZero() {
+		s.admittedAt = now
+	}
+	allowance := int64(now.Sub(s.admittedAt) * time.Duration(admitPerMinute) / time.Minute)
+	admitted := min(allowance, int64(maxActive-active), s.tail-s.head)
+	if admitted < 0 {
+		admitted = 0
+	}
+	s.head += admitted
+	// Time not spent admitting is not banked, so a free room does not admit a burst later
+	if admitted < allowance {
+		s.admittedAt = now
+	} else {
+		s.admittedAt = s.admittedAt.Add(time.Duration(admitted) * time.Minute / time.Duration(admitPerMinute))
+	}
+
+	return &State{Active: active, Head: s.head, Tail: s.tail}, nil
+}
+
+// Activate starts the session of an admitted ticket
+func (s *MemoryStore) Activate(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[sessionID] = expiresAt
+	return nil
+}
+
+// expire drops expired sessions and returns how many are left
+func (s *MemoryStore) expire(now time.Time) int {
+	for id, expiresAt := range s.sessions {
+		if !now.Before(expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	return len(s.sessions)
+}
@@ -0,0 +1,90 @@
+package waitingroom
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// The sessions key is a sorted set of session IDs scored by expiry; the state
+// key holds the admitted (head) and issued (tail) queue positions and the time
+// of the last admission. Both share a hash tag so scripts run on one cluster slot.
+var (
+	enterScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+local active = redis.call('ZCARD', KEYS[1])
+local head = tonumber(redis.call('HGET', KEYS[2], 'head') or '0')
+local tail = tonumber(redis.call('HGET', KEYS[2], 'tail') or '0')
+if tail <= head and active < tonumber(ARGV[2]) then
+	redis.call('ZADD', KEYS[1], ARGV[4], ARGV[3])
+	return {1, 0}
+end
+return {0, redis.call('HINCRBY', KEYS[2], 'tail', 1)}`)
+
+	advanceScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+local active = redis.call('ZCARD', KEYS[1])
+local head = tonumber(redis.call('HGET', KEYS[2], 'head') or '0')
+local tail = tonumber(redis.call('HGET', KEYS[2], 'tail') or '0')
+local last = tonumber(redis.call('HGET', KEYS[2], 'admitted_at') or now)
+local allowance = math.floor((now - last) * rate / 60000)
+local admitted = math.max(math.min(allowance, tonumber(ARGV[3]) - active, tail - head), 0)
+head = head + admitted
+if admitted < allowance then
+	last = now
+else
+	last = last + math.floor(admitted * 60000 / rate)
+end
+redis.call('HSET', KEYS[2], 'head', head, 'admitted_at', last)
+return {active, head, tail}`)
+)
+
+// RedisStore shares the waiting room across instances through Redis
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore creates a Redis-backed store
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Enter starts a session when the room is not queuing, otherwise issues the next queue position
+func (s *RedisStore) Enter(ctx context.Context, sessionID string, maxActive int, expiresAt time.Time) (bool, int64, error) {
+	result, err := enterScript.Run(ctx, s.client, s.keys(),
+		time.Now().UnixMilli(), maxActive, sessionID, expiresAt.UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("redis waiting room error: %w", err)
+	}
+	return result[0] == 1, result[1], nil
+}
+
+// Advance admits queued positions for the time elapsed since the last admission
+func (s *RedisStore) Advance(ctx context.Context, now time.Time, admitPerMinute, maxActive int) (*State, error) {
+	result, err := advanceScript.Run(ctx, s.client, s.keys(),
+		now.UnixMilli(), admitPerMinute, maxActive,
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("redis waiting room error: %w", err)
+	}
+	return &State{Active: int(result[0]), Head: result[1], Tail: result[2]}, nil
+}
+
+// Activate starts the session of an admitted ticket
+func (s *RedisStore) Activate(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	err := s.client.ZAdd(ctx, s.keys()[0], redis.Z{Score: float64(expiresAt.UnixMilli()), Member: sessionID}).Err()
+	if err != nil {
+		return fmt.Errorf("redis waiting room error: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) keys() []string {
+	return []string{s.prefix + ":{waitingroom}:sessions", s.prefix + ":{waitingroom}:state"}
+}
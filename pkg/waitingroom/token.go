@@ -0,0 +1,64 @@
+package waitingroom
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+const (
+	kindTicket  = "ticket"
+	kindSession = "session"
+)
+
+// ticket is the signed content of a token: a queue position while queued, a
+// checkout session once admitted
+type ticket struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Position  int64     `json:"pos,omitempty"`
+	IssuedAt  time.Time `json:"iat"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// sign encodes a ticket as payload.signature, both base64url
+func (r *Room) sign(t *ticket) string {
+	payload, _ := json.Marshal(t)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(r.mac(encoded))
+}
+
+// verify decodes a token, rejecting tampered and expired ones
+func (r *Room) verify(token string, now time.Time) (*ticket, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errors.BadRequest("invalid waiting room token")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, r.mac(encoded)) {
+		return nil, errors.BadRequest("invalid waiting room token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.BadRequest("invalid waiting room token")
+	}
+	var t ticket
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return nil, errors.BadRequest("invalid waiting room token")
+	}
+	if (t.Kind != kindTicket && t.Kind != kindSession) || !now.Before(t.ExpiresAt) {
+		return nil, errors.BadRequest("waiting room token expired")
+	}
+	return &t, nil
+}
+
+func (r *Room) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, r.cfg.Secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
@@ -0,0 +1,208 @@
+// Package waitingroom queues visitors in front of checkout during high-demand
+// drops. While fewer than MaxActive checkout sessions are active and nobody is
+// waiting, visitors are admitted straight away; beyond that they get a signed
+// ticket with their queue position and are admitted in order at a controlled
+// rate as sessions free up. State is shared between instances through Redis,
+// with an in-memory store for single-instance setups.
+package waitingroom
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxPollInterval bounds how long a queued visitor is told to wait before checking again
+const maxPollInterval = 30 * time.Second
+
+// Config holds the limits of a waiting room
+type Config struct {
+	MaxActive      int           // Checkout sessions active at once before visitors are queued
+	AdmitPerMinute int           // Queued visitors admitted per minute, as sessions free up
+	SessionTTL     time.Duration // How long an admitted visitor may check out
+	TicketTTL      time.Duration // How long a queue ticket stays valid
+	Secret         []byte        // Signs tickets and sessions
+}
+
+// State is the shared state of a waiting room. Queue positions up to Head are admitted.
+type State struct {
+	Active int   // Checkout sessions not yet expired
+	Head   int64 // Last queue position admitted
+	Tail   int64 // Last queue position issued
+}
+
+// Waiting returns how many queued visitors are not admitted yet
+func (s *State) Waiting() int64 {
+	if s.Tail < s.Head {
+		return 0
+	}
+	return s.Tail - s.Head
+}
+
+// Store holds the shared state of a waiting room. Each operation is atomic
+// across every instance sharing the store.
+type Store interface {
+	// Enter starts a session expiring at expiresAt when nobody is waiting and
+	// fewer than maxActive sessions are active; otherwise it issues the next
+	// queue position. position is 0 when the session was started.
+	Enter(ctx context.Context, sessionID string, maxActive int, expiresAt time.Time) (admitted bool, position int64, err error)
+
+	// Advance admits queued positions at admitPerMinute for the time elapsed
+	// since the last admission, without exceeding maxActive sessions, and
+	// returns the resulting state
+	Advance(ctx context.Context, now time.Time, admitPerMinute, maxActive int) (*State, error)
+
+	// Activate starts the session of an admitted ticket, expiring at expiresAt
+	Activate(ctx context.Context, sessionID string, expiresAt time.Time) error
+}
+
+// Status is where a visitor stands in the waiting room
+type Status struct {
+	Admitted   bool      `json:"admitted"`
+	Token      string    `json:"token"`              // Session once admitted, ticket while queued
+	Position   int64     `json:"position,omitempty"` // Queue position of the ticket
+	Ahead      int64     `json:"ahead"`              // Visitors admitted before this one
+	ETASeconds int       `json:"eta_seconds"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// PollAfter is how long a queued visitor should wait before checking again
+func (s *Status) PollAfter() time.Duration {
+	wait := time.Duration(s.ETASeconds) * time.Second
+	if wait < time.Second {
+		return time.Second
+	}
+	if wait > maxPollInterval {
+		return maxPollInterval
+	}
+	return wait
+}
+
+// Overview is the state of a waiting room as shown to visitors before they join
+type Overview struct {
+	Queuing        bool  `json:"queuing"` // Whether new visitors are queued
+	Active         int   `json:"active"`
+	MaxActive      int   `json:"max_active"`
+	Waiting        int64 `json:"waiting"`
+	AdmitPerMinute int   `json:"admit_per_minute"`
+	ETASeconds     int   `json:"eta_seconds"` // Estimated wait of a visitor joining now
+}
+
+// Room admits visitors to checkout through a Store
+type Room struct {
+	store Store
+	cfg   Config
+	now   func() time.Time
+}
+
+// New creates a waiting room
+func New(store Store, cfg Config) *Room {
+	return &Room{store: store, cfg: cfg, now: time.Now}
+}
+
+// Admit decides whether the holder of a token may check out now. A valid
+// session passes; a ticket whose position was reached starts a session; a
+// visitor without a valid token joins the room, admitted straight away while
+// it is not queuing.
+func (r *Room) Admit(ctx context.Context, token string) (*Status, error) {
+	now := r.now()
+	if t, err := r.verify(token, now); err == nil {
+		if t.Kind == kindSession {
+			return &Status{Admitted: true, Token: token, ExpiresAt: t.ExpiresAt}, nil
+		}
+		return r.check(ctx, t, now)
+	}
+
+	sessionID := uuid.NewString()
+	expiresAt := now.Add(r.cfg.SessionTTL)
+	admitted, position, err := r.store.Enter(ctx, sessionID, r.cfg.MaxActive, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if admitted {
+		return r.session(sessionID, expiresAt), nil
+	}
+
+	t := &ticket{ID: sessionID, Kind: kindTicket, Position: position, IssuedAt: now, ExpiresAt: now.Add(r.cfg.TicketTTL)}
+	state, err := r.store.Advance(ctx, now, r.cfg.AdmitPerMinute, r.cfg.MaxActive)
+	if err != nil {
+		return nil, err
+	}
+	return r.queued(t, state), nil
+}
+
+// Status reports where the holder of a token stands without joining the room;
+// a ticket whose position was reached starts its session
+func (r *Room) Status(ctx context.Context, token string) (*Status, error) {
+	now := r.now()
+	t, err := r.verify(token, now)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind == kindSession {
+		return &Status{Admitted: true, Token: token, ExpiresAt: t.ExpiresAt}, nil
+	}
+	return r.check(ctx, t, now)
+}
+
+// Overview returns the state of the room
+func (r *Room) Overview(ctx context.Context) (*Overview, error) {
+	state, err := r.store.Advance(ctx, r.now(), r.cfg.AdmitPerMinute, r.cfg.MaxActive)
+	if err != nil {
+		return nil, err
+	}
+	waiting := state.Waiting()
+	return &Overview{
+		Queuing:        waiting > 0 || state.Active >= r.cfg.MaxActive,
+		Active:         state.Active,
+		MaxActive:      r.cfg.MaxActive,
+		Waiting:        waiting,
+		AdmitPerMinute: r.cfg.AdmitPerMinute,
+		ETASeconds:     r.eta(waiting + 1),
+	}, nil
+}
+
+// check admits a ticket once the queue reached its position
+func (r *Room) check(ctx context.Context, t *ticket, now time.Time) (*Status, error) {
+	state, err := r.store.Advance(ctx, now, r.cfg.AdmitPerMinute, r.cfg.MaxActive)
+	if err != nil {
+		return nil, err
+	}
+	if t.Position > state.Head {
+		return r.queued(t, state), nil
+	}
+
+	expiresAt := now.Add(r.cfg.SessionTTL)
+	if err := r.store.Activate(ctx, t.ID, expiresAt); err != nil {
+		return nil, err
+	}
+	return r.session(t.ID, expiresAt), nil
+}
+
+func (r *Room) session(sessionID string, expiresAt time.Time) *Status {
+	t := &ticket{ID: sessionID, Kind: kindSession, IssuedAt: r.now(), ExpiresAt: expiresAt}
+	return &Status{Admitted: true, Token: r.sign(t), ExpiresAt: expiresAt}
+}
+
+func (r *Room) queued(t *ticket, state *State) *Status {
+	ahead := t.Position - state.Head - 1
+	if ahead < 0 {
+		ahead = 0
+	}
+	return &Status{
+		Token:      r.sign(t),
+		Position:   t.Position,
+		Ahead:      ahead,
+		ETASeconds: r.eta(ahead + 1),
+		ExpiresAt:  t.ExpiresAt,
+	}
+}
+
+// eta estimates how long until the next visitors are admitted at the admission rate
+func (r *Room) eta(visitors int64) int {
+	if visitors <= 0 {
+		return 0
+	}
+	return int((visitors*60 + int64(r.cfg.AdmitPerMinute) - 1) / int64(r.cfg.AdmitPerMinute))
+}
@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qhato/ecommerce/pkg/errors"
+)
+
+// Headers of the webhooks the platform sends and of providers signing the same way
+const (
+	IDHeader        = "X-Webhook-ID"        // Delivery ID, identical on retries so receivers can deduplicate
+	EventHeader     = "X-Webhook-Event"     // Event type of the payload
+	TimestampHeader = "X-Webhook-Timestamp" // Unix time the delivery was signed at
+	SignatureHeader = "X-Webhook-Signature" // sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+)
+
+// DefaultTolerance is how old a signed timestamp may be before a delivery is rejected as a replay
+const DefaultTolerance = 5 * time.Minute
+
+// Delivery is what a verified webhook request tells about itself
+type Delivery struct {
+	ID        string // Unique per delivery; used to reject replays
+	Event     string
+	Timestamp time.Time
+}
+
+// Verifier checks the signature of a provider's webhook requests
+type Verifier interface {
+	// Verify authenticates the body of a request and returns its delivery, or an
+	// unauthorized error when the signature or timestamp is invalid.
+	Verify(header http.Header, body []byte, now time.Time) (*Delivery, error)
+}
+
+// Sign returns the signature of a body signed at timestamp
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return "sha256=" + hex.EncodeToString(computeHMAC(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+// SetHeaders sets the delivery and signature headers of an outgoing webhook. The
+// headers are set once per delivery so retries of the request carry the same ID
+// and timestamp and stay valid within the receiver's tolerance; the signature is
+// left out when secret is empty.
+func SetHeaders(header http.Header, secret, id, event string, timestamp time.Time, body []byte) {
+	header.Set(IDHeader, id)
+	header.Set(EventHeader, event)
+	header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	if secret != "" {
+		header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	}
+}
+
+// HMACVerifier verifies requests signed with SetHeaders
+type HMACVerifier struct {
+	Secret    string
+	Tolerance time.Duration // 0 uses DefaultTolerance
+}
+
+// Verify checks the X-Webhook-Signature of a request against its body and timestamp
+func (v *HMACVerifier) Verify(header http.Header, body []byte, now time.Time) (*Delivery, error) {
+	timestamp, err := parseTimestamp(header.Get(TimestampHeader), v.Tolerance, now)
+	if err != nil {
+		return nil, err
+	}
+	signature := strings.TrimPrefix(header.Get(SignatureHeader), "sha256=")
+	if !validSignature(v.Secret, header.Get(TimestampHeader), body, signature) {
+		return nil, errors.Unauthorized("invalid webhook signature")
+	}
+	id := header.Get(IDHeader)
+	if id == "" {
+		return nil, errors.BadRequest("webhook delivery ID is required")
+	}
+	return &Delivery{ID: id, Event: header.Get(EventHeader), Timestamp: timestamp}, nil
+}
+
+// StripeVerifier verifies requests signed like Stripe webhooks: a Stripe-Signature
+// header of t=<unix>,v1=<hex HMAC-SHA256 of "<t>.<body>">, with the event ID and
+// type in the JSON body
+type StripeVerifier struct {
+	Secret    string
+	Tolerance time.Duration // 0 uses DefaultTolerance
+}
+
+// Verify checks the Stripe-Signature of a request; any of its v1 signatures may match
+func (v *StripeVerifier) Verify(header http.Header, body []byte, now time.Time) (*Delivery, error) {
+	var t string
+	var signatures []string
+	for _, part := range strings.Split(header.Get("Stripe-Signature"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	timestamp, err := parseTimestamp(t, v.Tolerance, now)
+	if err != nil {
+		return nil, err
+	}
+	valid := false
+	for _, signature := range signatures {
+		if validSignature(v.Secret, t, body, signature) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, errors.Unauthorized("invalid webhook signature")
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil || event.ID == "" {
+		return nil, errors.BadRequest("webhook event ID is required")
+	}
+	return &Delivery{ID: event.ID, Event: event.Type, Timestamp: timestamp}, nil
+}
+
+// NewVerifier creates the verifier of a signature scheme: hmac (the default) or stripe
+func NewVerifier(scheme, secret string, tolerance time.Duration) (Verifier, error) {
+	switch strings.ToLower(scheme) {
+	case "", "hmac":
+		return &HMACVerifier{Secret: secret, Tolerance: tolerance}, nil
+	case "stripe":
+		return &StripeVerifier{Secret: secret, Tolerance: tolerance}, nil
+	default:
+		return nil, fmt.Errorf("unknown webhook signature scheme %q", scheme)
+	}
+}
+
+// parseTimestamp reads a unix timestamp, rejecting those further than tolerance from now
+func parseTimestamp(value string, tolerance time.Duration, now time.Time) (time.Time, error) {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Unauthorized("webhook timestamp is missing or invalid")
+	}
+	timestamp := time.Unix(seconds, 0)
+	if age := now.Sub(timestamp); age > tolerance || age < -tolerance {
+		return time.Time{}, errors.Unauthorized("webhook timestamp is outside the tolerance")
+	}
+	return timestamp, nil
+}
+
+// validSignature compares a hex signature with that of "<timestamp>.<body>" in constant time
+func validSignature(secret, timestamp string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || secret == "" {
+		return false
+	}
+	return hmac.Equal(expected, computeHMAC(secret, timestamp, body))
+}
+
+func computeHMAC(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,14 @@
+// Package schemas holds the payload schemas of the events published on the
+// event bus, one file per event type and version, e.g.
+// events/shipment.shipped.v1.json. A new version may only add fields; run
+// `make check-events` to check the schemas against the event types and the
+// fields their consumers read, and `make generate-events` to add the version
+// of an event type that changed.
+package schemas
+
+import "embed"
+
+// Events holds the event schema files under events/
+//
+//go:embed events/*.json
+var Events embed.FS